@@ -0,0 +1,105 @@
+package accounting
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+	"totesbackend/config"
+)
+
+const (
+	ACCOUNTING_PROVIDER_SIIGO = "siigo"
+	ACCOUNTING_PROVIDER_LOG   = "log"
+)
+
+// Customer identifica al cliente en el sistema contable. Debe existir allí con el mismo número
+// de identificación.
+type Customer struct {
+	Identification string
+	Name           string
+	LastName       string
+	Email          string
+	IsBusiness     bool
+}
+
+type Line struct {
+	// Code es el código del producto en el sistema contable: el ID del ítem en este sistema
+	Code        string
+	Description string
+	Quantity    int
+	UnitPrice   float64
+}
+
+type Invoice struct {
+	ID       int
+	Date     time.Time
+	DueDate  *time.Time
+	Customer Customer
+	Lines    []Line
+	Subtotal float64
+	Total    float64
+	// moneda de los precios y los valores, y su tasa a la moneda base
+	Currency     string
+	ExchangeRate float64
+}
+
+// Payment es el pago de una factura a crédito que ya se envió al sistema contable.
+type Payment struct {
+	InvoiceID int
+	// ID y número que el sistema contable le dio a la factura
+	InvoiceExternalID     string
+	InvoiceExternalNumber string
+	InvoiceDate           time.Time
+	Customer              Customer
+	Date                  time.Time
+	Amount                float64
+}
+
+// Result es lo que el sistema contable devolvió al crear el documento.
+type Result struct {
+	ExternalID     string
+	ExternalNumber string
+}
+
+// Client envía documentos a un sistema contable. Con un error el documento se reintenta más tarde.
+type Client interface {
+	Provider() string
+	PushInvoice(ctx context.Context, invoice Invoice) (Result, error)
+	PushPayment(ctx context.Context, payment Payment) (Result, error)
+}
+
+// NewClient crea el cliente del proveedor configurado en ACCOUNTING_PROVIDER. Devuelve nil si la
+// integración está desactivada.
+func NewClient(cfg config.AccountingConfig) (Client, error) {
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case ACCOUNTING_PROVIDER_SIIGO:
+		return NewSiigoClient(cfg.Siigo), nil
+	case ACCOUNTING_PROVIDER_LOG:
+		return LogClient{}, nil
+	default:
+		return nil, fmt.Errorf("unknown accounting provider %q", cfg.Provider)
+	}
+}
+
+// LogClient no envía nada: escribe el documento en el log y lo da por sincronizado. Sirve para
+// probar el flujo sin una cuenta del sistema contable.
+type LogClient struct{}
+
+func (LogClient) Provider() string {
+	return ACCOUNTING_PROVIDER_LOG
+}
+
+func (LogClient) PushInvoice(ctx context.Context, invoice Invoice) (Result, error) {
+	log.Printf("accounting: invoice %d for %s, total %.2f %s (not sent: ACCOUNTING_PROVIDER=log)", invoice.ID, invoice.Customer.Identification,
+		invoice.Total, invoice.Currency)
+	return Result{ExternalID: "log-invoice-" + strconv.Itoa(invoice.ID)}, nil
+}
+
+func (LogClient) PushPayment(ctx context.Context, payment Payment) (Result, error) {
+	log.Printf("accounting: payment of invoice %d, amount %.2f (not sent: ACCOUNTING_PROVIDER=log)", payment.InvoiceID, payment.Amount)
+	return Result{ExternalID: "log-payment-" + strconv.Itoa(payment.InvoiceID)}, nil
+}
@@ -0,0 +1,198 @@
+package accounting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"totesbackend/config"
+)
+
+// SiigoClient crea facturas de venta y recibos de caja con la API de Siigo Nube. Los productos y
+// los clientes deben existir en Siigo: los productos con el ID del ítem como código y los clientes
+// con su número de documento.
+type SiigoClient struct {
+	Config config.SiigoConfig
+	Client *http.Client
+
+	mu           sync.Mutex
+	token        string
+	tokenExpires time.Time
+}
+
+func NewSiigoClient(cfg config.SiigoConfig) *SiigoClient {
+	return &SiigoClient{Config: cfg, Client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+func (c *SiigoClient) Provider() string {
+	return ACCOUNTING_PROVIDER_SIIGO
+}
+
+type siigoRef struct {
+	ID int `json:"id"`
+}
+
+type siigoCustomerRef struct {
+	Identification string `json:"identification"`
+	BranchOffice   int    `json:"branch_office"`
+}
+
+type siigoInvoiceItem struct {
+	Code        string  `json:"code"`
+	Description string  `json:"description,omitempty"`
+	Quantity    int     `json:"quantity"`
+	Price       float64 `json:"price"`
+}
+
+type siigoPayment struct {
+	ID      int     `json:"id"`
+	Value   float64 `json:"value"`
+	DueDate string  `json:"due_date,omitempty"`
+}
+
+type siigoDocumentResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func (c *SiigoClient) PushInvoice(ctx context.Context, invoice Invoice) (Result, error) {
+	dueDate := invoice.Date
+	if invoice.DueDate != nil {
+		dueDate = *invoice.DueDate
+	}
+	items := make([]siigoInvoiceItem, len(invoice.Lines))
+	for i, line := range invoice.Lines {
+		items[i] = siigoInvoiceItem{Code: line.Code, Description: line.Description, Quantity: line.Quantity, Price: line.UnitPrice}
+	}
+
+	request := map[string]interface{}{
+		"document":     siigoRef{ID: c.Config.InvoiceDocumentID},
+		"date":         invoice.Date.Format("2006-01-02"),
+		"customer":     siigoCustomerRef{Identification: invoice.Customer.Identification},
+		"seller":       c.Config.SellerID,
+		"observations": "Factura " + strconv.Itoa(invoice.ID),
+		"items":        items,
+		"payments":     []siigoPayment{{ID: c.Config.PaymentMethodID, Value: invoice.Total, DueDate: dueDate.Format("2006-01-02")}},
+	}
+	// sin currency Siigo toma los valores en pesos
+	if invoice.Currency != config.BASE_CURRENCY {
+		request["currency"] = map[string]interface{}{"code": invoice.Currency, "exchange_rate": invoice.ExchangeRate}
+	}
+	return c.create(ctx, "/v1/invoices", request)
+}
+
+// PushPayment registra el pago como recibo de caja que abona a la factura. Siigo identifica la
+// factura por su prefijo y consecutivo, que vienen en su número (p. ej. "FV-1-25").
+func (c *SiigoClient) PushPayment(ctx context.Context, payment Payment) (Result, error) {
+	separator := strings.LastIndex(payment.InvoiceExternalNumber, "-")
+	if separator < 0 {
+		return Result{}, fmt.Errorf("unexpected Siigo invoice number %q", payment.InvoiceExternalNumber)
+	}
+	consecutive, err := strconv.Atoi(payment.InvoiceExternalNumber[separator+1:])
+	if err != nil {
+		return Result{}, fmt.Errorf("unexpected Siigo invoice number %q", payment.InvoiceExternalNumber)
+	}
+
+	request := map[string]interface{}{
+		"document": siigoRef{ID: c.Config.VoucherDocumentID},
+		"date":     payment.Date.Format("2006-01-02"),
+		"type":     "DebtPayment",
+		"customer": siigoCustomerRef{Identification: payment.Customer.Identification},
+		"items": []map[string]interface{}{{
+			"due": map[string]interface{}{
+				"prefix":      payment.InvoiceExternalNumber[:separator],
+				"consecutive": consecutive,
+				"quote":       1,
+				"date":        payment.InvoiceDate.Format("2006-01-02"),
+			},
+			"value": payment.Amount,
+		}},
+		"payment":      siigoPayment{ID: c.Config.PaymentMethodID, Value: payment.Amount},
+		"observations": "Pago de la factura " + strconv.Itoa(payment.InvoiceID),
+	}
+	return c.create(ctx, "/v1/vouchers", request)
+}
+
+func (c *SiigoClient) create(ctx context.Context, path string, request interface{}) (Result, error) {
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var response siigoDocumentResponse
+	status, err := c.post(ctx, path, token, request, &response)
+	if status == http.StatusUnauthorized {
+		// el token venció antes de lo esperado: el próximo intento pide otro
+		c.mu.Lock()
+		c.token = ""
+		c.mu.Unlock()
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{ExternalID: response.ID, ExternalNumber: response.Name}, nil
+}
+
+// accessToken devuelve el token de la API, pidiendo uno nuevo cuando está por vencer.
+func (c *SiigoClient) accessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && time.Now().Before(c.tokenExpires) {
+		return c.token, nil
+	}
+
+	var response struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	credentials := map[string]string{"username": c.Config.Username, "access_key": c.Config.AccessKey}
+	if _, err := c.post(ctx, "/auth", "", credentials, &response); err != nil {
+		return "", fmt.Errorf("siigo authentication failed: %w", err)
+	}
+	c.token = response.AccessToken
+	// se renueva un minuto antes de que venza
+	c.tokenExpires = time.Now().Add(time.Duration(response.ExpiresIn)*time.Second - time.Minute)
+	return c.token, nil
+}
+
+func (c *SiigoClient) post(ctx context.Context, path, token string, request, response interface{}) (int, error) {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Config.BaseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Partner-Id", c.Config.PartnerID)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if len(body) > 300 {
+			body = body[:300]
+		}
+		return resp.StatusCode, fmt.Errorf("siigo responded %s: %s", resp.Status, body)
+	}
+	return resp.StatusCode, json.Unmarshal(body, response)
+}
@@ -0,0 +1,141 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+	"totesbackend/config"
+	"totesbackend/database"
+	"totesbackend/repositories"
+	"totesbackend/services"
+)
+
+// RunCommand ejecuta un comando de administración en lugar de levantar el servidor:
+//
+//	totesbackend migrate status
+//	totesbackend migrate up [--allow-destructive]
+//	totesbackend seed
+//	totesbackend export [--output archivo.zip]
+func RunCommand(args []string) error {
+	switch args[0] {
+	case "migrate":
+		return runMigrateCommand(args[1:])
+	case "seed":
+		return runSeedCommand()
+	case "export":
+		return runExportCommand(args[1:])
+	default:
+		return fmt.Errorf("unknown command %q (available: migrate, seed, export)", args[0])
+	}
+}
+
+// runSeedCommand aplica las migraciones pendientes y carga los datos base. El usuario
+// administrador se toma de SEED_ADMIN_EMAIL y SEED_ADMIN_PASSWORD.
+func runSeedCommand() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if err := database.StartPostgres(cfg.Database); err != nil {
+		return err
+	}
+	defer database.ClosePostgres()
+
+	if err := database.MigrateDB(cfg.Database.AllowDestructiveMigrations); err != nil {
+		return err
+	}
+	if err := database.Seed(cfg.Seed.AdminEmail, cfg.Seed.AdminPassword); err != nil {
+		return err
+	}
+	fmt.Println("seed completed")
+	return nil
+}
+
+// runExportCommand escribe la misma exportación que POST /exports directamente en un archivo, sin
+// pasar por la cola ni por el directorio de exportaciones.
+func runExportCommand(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	output := flags.String("output", "totes-export-"+time.Now().Format("20060102-150405")+".zip", "file to write the export to")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if err := database.StartPostgres(cfg.Database); err != nil {
+		return err
+	}
+	defer database.ClosePostgres()
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	service := services.NewDataExportService(repositories.NewDataExportRepository(database.GetDB()), nil, cfg.Export)
+	if err := service.Write(context.Background(), file); err != nil {
+		file.Close()
+		os.Remove(*output)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("export written to %s\n", *output)
+	return nil
+}
+
+func runMigrateCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate status | migrate up [--allow-destructive]")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if err := database.StartPostgres(cfg.Database); err != nil {
+		return err
+	}
+	defer database.ClosePostgres()
+
+	switch args[0] {
+	case "status":
+		return printMigrationStatus()
+	case "up":
+		flags := flag.NewFlagSet("migrate up", flag.ContinueOnError)
+		allowDestructive := flags.Bool("allow-destructive", cfg.Database.AllowDestructiveMigrations, "apply destructive migrations too")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if err := database.MigrateDB(*allowDestructive); err != nil {
+			return err
+		}
+		return printMigrationStatus()
+	default:
+		return fmt.Errorf("unknown migrate subcommand %q (available: status, up)", args[0])
+	}
+}
+
+func printMigrationStatus() error {
+	status, err := database.GetMigrationStatus(database.GetDB())
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tDESTRUCTIVE\tAPPLIED AT")
+	for _, migration := range status {
+		appliedAt := "pending"
+		if migration.AppliedAt != nil {
+			appliedAt = migration.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%t\t%s\n", migration.Version, migration.Name, migration.Destructive, appliedAt)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"totesbackend/config"
+	"totesbackend/repositories"
+	"totesbackend/services"
+)
+
+// Trabajos programados del servidor. Las expresiones cron usan la hora local del servidor.
+const (
+	JOB_SECURITY_EVENT_RETENTION = "security_event_retention"
+	JOB_USER_LOG_RETENTION       = "user_log_retention"
+	JOB_LOW_STOCK_CHECK          = "low_stock_check"
+	JOB_NOTIFICATION_RETENTION   = "notification_retention"
+	JOB_PAYMENT_REMINDERS        = "payment_reminders"
+	JOB_APPOINTMENT_REMINDERS    = "appointment_reminders"
+	JOB_ARCHIVE                  = "archive"
+	JOB_DATA_EXPORT_RETENTION    = "data_export_retention"
+	JOB_CUSTOMER_GEOCODING       = "customer_geocoding"
+	JOB_REFRESH_TOKEN_RETENTION  = "refresh_token_retention"
+)
+
+func registerScheduledJobs(scheduler *services.SchedulerService, securityEventService *services.SecurityEventService, userLogService *services.UserLogService,
+	inboxService *services.InboxService, paymentReminderService *services.PaymentReminderService, archiveService *services.ArchiveService,
+	dataExportService *services.DataExportService) error {
+	type job struct {
+		name string
+		spec string
+		run  services.JobFunc
+	}
+	jobs := []job{
+		{JOB_SECURITY_EVENT_RETENTION, "30 3 * * *", func(ctx context.Context) (string, error) {
+			// los eventos de seguridad solo se purgan una vez vencido su periodo de retención
+			deleted, err := securityEventService.PurgeExpiredSecurityEvents(ctx, config.SECURITY_EVENT_RETENTION_DAYS)
+			return fmt.Sprintf("%d security events deleted", deleted), err
+		}},
+		{JOB_USER_LOG_RETENTION, "0 3 * * *", func(ctx context.Context) (string, error) {
+			deleted, err := userLogService.PurgeExpiredUserLogs(ctx, config.USER_LOG_RETENTION_DAYS)
+			return fmt.Sprintf("%d log entries deleted", deleted), err
+		}},
+		{JOB_LOW_STOCK_CHECK, "0 * * * *", func(ctx context.Context) (string, error) {
+			items, queued, err := lowStockAlertService.SendLowStockAlerts(ctx, time.Now())
+			return fmt.Sprintf("%d items reached their reorder level, %d alerts queued", items, queued), err
+		}},
+		{JOB_NOTIFICATION_RETENTION, "45 3 * * *", func(ctx context.Context) (string, error) {
+			deleted, err := inboxService.PurgeReadNotifications(ctx, config.INBOX_READ_RETENTION_DAYS)
+			return fmt.Sprintf("%d read notifications deleted", deleted), err
+		}},
+		{JOB_PAYMENT_REMINDERS, "0 9 * * *", func(ctx context.Context) (string, error) {
+			queued, err := paymentReminderService.SendPaymentReminders(ctx, time.Now())
+			return fmt.Sprintf("%d payment reminders queued", queued), err
+		}},
+		{JOB_APPOINTMENT_REMINDERS, "*/10 * * * *", func(ctx context.Context) (string, error) {
+			queued, err := appointmentReminderService.SendAppointmentReminders(ctx, time.Now())
+			return fmt.Sprintf("%d appointment reminders queued", queued), err
+		}},
+		{JOB_ARCHIVE, "15 2 * * *", func(ctx context.Context) (string, error) {
+			archived, err := archiveService.ArchiveOldDocuments(ctx, time.Now())
+			return fmt.Sprintf("%d invoices and %d appointments archived", archived.Invoices, archived.Appointments), err
+		}},
+		{JOB_DATA_EXPORT_RETENTION, "30 4 * * *", func(ctx context.Context) (string, error) {
+			purged, err := dataExportService.PurgeExpiredExports(ctx, config.DATA_EXPORT_RETENTION_DAYS)
+			return fmt.Sprintf("%d export files deleted", purged), err
+		}},
+		{JOB_REFRESH_TOKEN_RETENTION, "50 3 * * *", func(ctx context.Context) (string, error) {
+			deleted, err := tokenService.PurgeExpiredTokens(ctx)
+			return fmt.Sprintf("%d expired refresh tokens deleted", deleted), err
+		}},
+	}
+	if geocoder != nil {
+		customerService := services.NewCustomerService(repositories.NewCustomerRepository(db), repositories.NewGormTransactor(db))
+		customerService.Geocoder = geocoder
+		jobs = append(jobs, job{JOB_CUSTOMER_GEOCODING, "20 * * * *", func(ctx context.Context) (string, error) {
+			geocoded, err := customerService.GeocodePendingCustomers(ctx)
+			return fmt.Sprintf("%d addresses located, %d not found", geocoded.Located, geocoded.NotFound), err
+		}})
+	}
+
+	for _, job := range jobs {
+		if err := scheduler.Register(job.name, job.spec, job.run); err != nil {
+			return err
+		}
+	}
+	return nil
+}
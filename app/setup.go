@@ -1,14 +1,26 @@
 package app
 
 import (
-	"time"
+	"context"
+	"log"
+	"net/http"
+	"os/signal"
+	"slices"
+	"syscall"
+	"totesbackend/accounting"
+	"totesbackend/captcha"
 	"totesbackend/config"
 	"totesbackend/controllers"
 	"totesbackend/controllers/utilities"
 	"totesbackend/database"
+	"totesbackend/ecommerce"
+	"totesbackend/geocoding"
+	"totesbackend/notifications"
+	"totesbackend/payments"
 	"totesbackend/repositories"
 	routes "totesbackend/router"
 	"totesbackend/services"
+	"totesbackend/storage"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -20,38 +32,67 @@ import (
 )
 
 var db *gorm.DB
+
+// nil sin réplicas configuradas; los repositorios usan entonces la primaria
+var replicaDB *gorm.DB
 var router *gin.Engine
 var authUtil *utilities.AuthorizationUtil
+var tokenService *services.TokenService
 var logUtil *utilities.LogUtil
+var auditUtil *utilities.AuditUtil
+var webhookService *services.WebhookService
+var eventStreamService *services.EventStreamService
+var eventBus *services.EventBus
+var schedulerService *services.SchedulerService
+var emailService *services.EmailService
+var textMessageService *services.TextMessageService
+var linkSigner *services.LinkSigner
+var notificationPreferenceService *services.NotificationPreferenceService
+var inboxService *services.InboxService
+var emailTemplateService *services.EmailTemplateService
+var paymentReminderService *services.PaymentReminderService
+var appointmentReminderService *services.AppointmentReminderService
+var lowStockAlertService *services.LowStockAlertService
+var archiveService *services.ArchiveService
+var supplierService *services.SupplierService
+var businessHoursService *services.BusinessHoursService
+var dataExportService *services.DataExportService
+var accountingService *services.AccountingService
+var geocoder geocoding.Geocoder
+var captchaVerifier captcha.Verifier
+var ecommerceService *services.EcommerceService
+var paymentGateway payments.Gateway
+var fileStorage storage.Storage
 
 // @schemes   https
 
 // SetupAndRunApp initializes and configures the entire application server,
 // including environment variables, database connection, middleware, route handlers,
 // CORS policies, and Swagger documentation.
-// It runs the HTTPS server on port 443 using TLS certificates.
+// It runs the HTTPS server on SERVER_PORT (443 by default) using TLS certificates until SIGINT or SIGTERM,
+// then drains in-flight requests, flushes pending logs and closes the database.
 // If any initialization step fails, it returns an error.
 //
 // The function also performs the following steps:
-// - Loads environment variables
+// - Loads and validates the configuration from the environment
 // - Starts and defers closure of the PostgreSQL connection
-// - Applies database migrations
+// - Applies pending versioned migrations (refusing to start if a destructive one is pending)
 // - Initializes repositories, services, and utilities
 // - Registers all API route groups (users, roles, auth, billing, etc.)
 // - Enables CORS with specific allowed origins
 // - Mounts the Swagger UI at /swagger/index.html
-// - Starts the HTTPS server
+// - Starts the HTTPS server and shuts it down gracefully on SIGINT/SIGTERM
 
 func SetupAndRunApp() error {
 
-	// load env
-	err := config.LoadENV()
+	// load env and validate the configuration before touching anything else
+	cfg, err := config.Load()
 	if err != nil {
 		return err
 	}
 
 	// start database
-	err = database.StartPostgres()
+	err = database.StartPostgres(cfg.Database)
 	if err != nil {
 		return err
 	}
@@ -60,24 +101,176 @@ func SetupAndRunApp() error {
 	defer database.ClosePostgres()
 
 	db = database.GetDB()
+	replicaDB = database.GetReplicaDB()
 	userRepo := repositories.NewUserRepository(db)
 	authUtil = utilities.NewAuthorizationUtil(services.NewAuthorizationService(repositories.NewAuthorizationRepository(db), userRepo))
-	logUtil = utilities.NewLogUtil(services.NewUserLogService(repositories.NewUserLogRepository(db)))
+	userLogRepo := repositories.NewUserLogRepository(db)
+	userLogRepo.Replica = replicaDB
+	userLogService := services.NewUserLogService(userLogRepo)
+	logSink, err := services.NewLogSink(cfg.Log.SinkKind, cfg.Log.SinkNetwork, cfg.Log.SinkAddress, cfg.Log.SinkToken)
+	if err != nil {
+		return err
+	}
+	userLogService.Sink = logSink
+	userLogService.Writer = services.NewUserLogWriter(userLogRepo, config.LOG_QUEUE_SIZE, config.LOG_BATCH_SIZE, config.LOG_FLUSH_INTERVAL)
+	userLogService.Writer.Sink = logSink
+	// los logs pendientes se escriben antes de cerrar la base de datos; Close cierra también el sink
+	defer userLogService.Writer.Close()
+	logUtil = utilities.NewLogUtil(userLogService)
+	logUtil.FailOpen = cfg.Log.FailurePolicy == config.LOG_FAILURE_POLICY_OPEN
+	securityEventService := services.NewSecurityEventService(repositories.NewSecurityEventRepository(db))
+	logUtil.Security = securityEventService
+	authUtil.Security = securityEventService
+	tokenService = services.NewTokenService(repositories.NewRefreshTokenRepository(db), userRepo, cfg.Auth)
+	tokenService.Security = securityEventService
+	auditService := services.NewAuditService(repositories.NewAuditRepository(db))
+	auditUtil = utilities.NewAuditUtil(auditService)
 	router = gin.Default()
-	database.MigrateDB() // recordar descomentar para inicializar la base de datos
+	// aplica las migraciones pendientes; con una destructiva pendiente el servidor no arranca
+	if err := database.MigrateDB(cfg.Database.AllowDestructiveMigrations); err != nil {
+		return err
+	}
+	if cfg.Sandbox.Enabled {
+		log.Printf("SANDBOX_MODE is on: serving the %s schema with demo data", cfg.Sandbox.Schema)
+		if err := database.SeedSandbox(cfg.Sandbox.AdminEmail, cfg.Sandbox.AdminPassword); err != nil {
+			return err
+		}
+	}
+
+	// las entregas en curso terminan antes de cerrar la base de datos
+	webhookService = services.NewWebhookService(repositories.NewWebhookRepository(db))
+	defer webhookService.Close()
+	eventStreamService = services.NewEventStreamService()
+
+	mailer, err := notifications.NewMailer(cfg.Email, cfg.SMTP)
+	if err != nil {
+		return err
+	}
+	emailService = services.NewEmailService(repositories.NewEmailMessageRepository(db), mailer)
+	defer emailService.Close()
+	textSender, err := notifications.NewTextSender(cfg.Text)
+	if err != nil {
+		return err
+	}
+	textMessageService = services.NewTextMessageService(repositories.NewTextMessageRepository(db), repositories.NewCustomerRepository(db),
+		textSender, cfg.Text.DefaultCountryCode)
+	defer textMessageService.Close()
+	linkSigner = services.NewLinkSigner(cfg.Notifications)
+	accountingClient, err := accounting.NewClient(cfg.Accounting)
+	if err != nil {
+		return err
+	}
+	if accountingClient != nil {
+		// los documentos en envío terminan antes de cerrar la base de datos
+		accountingService = services.NewAccountingService(repositories.NewAccountingSyncRepository(db), repositories.NewInvoiceRepository(db), accountingClient)
+		accountingService.Currencies = repositories.NewCurrencyRepository(db)
+		defer accountingService.Close()
+	}
+	// los servicios publican sus eventos de dominio en el bus; los interesados se suscriben aquí o
+	// al configurar su router
+	eventBus = services.NewEventBus()
+	webhookService.HandleEvents(eventBus)
+	eventStreamService.Bus = eventBus
+	eventStreamService.HandleEvents(eventBus)
+	if accountingService != nil {
+		accountingService.HandleEvents(eventBus)
+	}
+	auditService.HandleEvents(eventBus)
+	if geocoder, err = geocoding.NewGeocoder(cfg.Geocoding); err != nil {
+		return err
+	}
+	if captchaVerifier, err = captcha.NewVerifier(cfg.Captcha); err != nil {
+		return err
+	}
+	storePlatform, err := ecommerce.NewPlatform(cfg.Ecommerce)
+	if err != nil {
+		return err
+	}
+	if storePlatform != nil {
+		ecommerceService = services.NewEcommerceService(repositories.NewEcommerceRepository(db), repositories.NewCustomerRepository(db),
+			storePlatform, cfg.Ecommerce.IdentifierTypeID)
+		defer ecommerceService.Close()
+	}
+	if paymentGateway, err = payments.NewGateway(cfg.Payments); err != nil {
+		return err
+	}
+	if fileStorage, err = storage.NewStorage(cfg.Storage); err != nil {
+		return err
+	}
+	notificationPreferenceService = services.NewNotificationPreferenceService(repositories.NewNotificationPreferenceRepository(db), linkSigner)
+	emailService.Preferences = notificationPreferenceService
+	textMessageService.Preferences = notificationPreferenceService
+	emailTemplateService = services.NewEmailTemplateService(repositories.NewEmailTemplateRepository(db))
+	emailTemplateService.Email = emailService
+	emailService.Templates = emailTemplateService
+	// recibe los mismos eventos que /events y los guarda en la bandeja de cada usuario
+	inboxService = services.NewInboxService(repositories.NewNotificationRepository(db), repositories.NewAuthorizationRepository(db),
+		notificationPreferenceService, eventStreamService)
+	defer inboxService.Close()
+	paymentReminderService = services.NewPaymentReminderService(repositories.NewInvoiceReminderRepository(db), emailService,
+		cfg.Notifications.PaymentReminderDays)
+	appointmentReminderService = services.NewAppointmentReminderService(repositories.NewAppointmentReminderRepository(db), emailService,
+		cfg.Notifications.AppointmentReminderHours)
+	appointmentReminderService.Links = linkSigner
+	appointmentReminderService.Texts = textMessageService
+	lowStockAlertService = services.NewLowStockAlertService(repositories.NewLowStockAlertRepository(db), repositories.NewAuthorizationRepository(db),
+		userRepo, emailService)
+	archiveService = services.NewArchiveService(repositories.NewArchiveRepository(db), cfg.Archive)
+	supplierRepo := repositories.NewSupplierRepository(db)
+	supplierRepo.Replica = replicaDB
+	supplierService = services.NewSupplierService(supplierRepo)
+	businessHoursService = services.NewBusinessHoursService(repositories.NewBusinessHoursRepository(db))
+	// la exportación en curso termina antes de cerrar la base de datos
+	dataExportService = services.NewDataExportService(repositories.NewDataExportRepository(db), linkSigner, cfg.Export)
+	dataExportService.Start()
+	defer dataExportService.Close()
+
+	// se detiene antes que los webhooks y la base de datos; los trabajos en curso se cancelan
+	schedulerService = services.NewSchedulerService(repositories.NewScheduledJobRepository(db))
+	if err := registerScheduledJobs(schedulerService, securityEventService, userLogService, inboxService, paymentReminderService, archiveService,
+		dataExportService); err != nil {
+		return err
+	}
+	if err := schedulerService.Start(); err != nil {
+		return err
+	}
+	defer schedulerService.Close()
 
-	// Configurar CORS
+	// cada petición recibe un X-Request-ID antes de cualquier log
+	router.Use(utilities.RequestID())
+
+	// orígenes, métodos y encabezados permitidos según CORS_*; el X-Request-ID siempre se acepta y se expone
 	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:3000", "http://127.0.0.1:5503", "http://127.0.0.1:5500", "http://127.0.0.1:5501"}, // Especifica los orígenes permitidos
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Username"},
-		AllowCredentials: true,
-		MaxAge:           12 * time.Hour,
+		AllowOrigins:     cfg.CORS.AllowedOrigins,
+		AllowWildcard:    true,
+		AllowMethods:     cfg.CORS.AllowedMethods,
+		AllowHeaders:     append(slices.Clone(cfg.CORS.AllowedHeaders), utilities.REQUEST_ID_HEADER),
+		ExposeHeaders:    []string{utilities.REQUEST_ID_HEADER, "Retry-After"},
+		AllowCredentials: cfg.CORS.AllowCredentials,
+		MaxAge:           cfg.CORS.MaxAge,
 	}))
 
+	// va antes del logger para que este registre los cuerpos sin comprimir
+	router.Use(utilities.Compress(cfg.Compression))
+
+	// un log por petición con método, ruta, estado y latencia
+	router.Use(logUtil.RequestLogger())
+
+	// todos los errores se devuelven con el formato de dtos.ErrorResponse
+	utilities.UseJSONFieldNames()
+	router.Use(utilities.ErrorHandler())
+	// identifica al usuario por su token de acceso; las rutas públicas aceptan peticiones sin él
+	router.Use(utilities.Authenticate(tokenService))
+	// límite por usuario o IP, con límites más estrictos en rutas como /login
+	router.Use(utilities.GlobalRateLimit(cfg.RateLimit))
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(utilities.NoRouteHandler)
+	router.NoMethod(utilities.NoMethodHandler)
+
 	setUpUserRouter()
 	setUpItemTypeRouter()
 	setUpItemRouter()
+	setUpPublicCatalogRouter()
 	setUpPermissionRouter()
 	setUpRoleRouter()
 	setUpUserTypeRouter()
@@ -99,13 +292,85 @@ func SetupAndRunApp() error {
 	setUpInvoice()
 	setUpExternalSaleRouter()
 	setUpSalesReportRouter()
+	setUpDashboardRouter()
+	setUpInventoryReportRouter()
+	setUpAppointmentReportRouter()
+	setUpDailyCloseRouter()
+	setUpUserLogRouter()
+	setUpAuditRouter()
+	setUpSecurityEventRouter()
+	setUpHealthRouter()
+	setUpWebhookRouter()
+	setUpEventStreamRouter()
+	setUpMigrationRouter()
+	setUpSchedulerRouter()
+	setUpNotificationPreferenceRouter()
+	setUpNotificationRouter()
+	setUpEmailTemplateRouter()
+	setUpNotificationDeliveryRouter()
+	setUpArchiveRouter()
+	setUpDataExportRouter()
+	setUpStockRouter()
+	setUpRestockOrderRouter()
+	setUpSupplierRouter()
+	setUpCreditNoteRouter()
+	setUpInvoiceReturnRouter()
+	setUpBusinessHoursRouter()
+	setUpQuotationRouter()
+	setUpCurrencyRouter()
+	setUpPriceListRouter()
+	setUpItemImageRouter(cfg.Storage)
+	if cfg.Sandbox.Enabled {
+		setUpSandboxRouter(cfg.Sandbox)
+	}
+	if accountingService != nil {
+		setUpAccountingRouter()
+	}
+	if ecommerceService != nil {
+		setUpEcommerceRouter()
+	}
+	setUpMetaRouter()
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	err = router.RunTLS(":443", "certs/cert.pem", "certs/key.pem")
-	if err != nil {
-		panic(err)
+	return runServer(cfg.Server)
+}
+
+// runServer atiende peticiones hasta recibir SIGINT o SIGTERM. Al recibir la señal deja de
+// aceptar conexiones y espera a que terminen las peticiones en curso; al volver, los defer de
+// SetupAndRunApp vacían el buffer de logs y cierran la base de datos.
+func runServer(cfg config.ServerConfig) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	server := &http.Server{
+		Addr:              cfg.Address(),
+		Handler:           router,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+	// las conexiones de /events no terminan solas; se cierran al iniciar el apagado
+	server.RegisterOnShutdown(eventStreamService.Close)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServeTLS(cfg.CertFile, cfg.KeyFile)
+	}()
+
+	select {
+	case err := <-serverErr:
+		return err
+	case <-ctx.Done():
 	}
 
+	log.Println("shutdown signal received, draining in-flight requests")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	log.Println("server stopped")
 	return nil
 }
 
@@ -126,7 +391,7 @@ func setUpEmployeeRouter() {
 func setUpRoleRouter() {
 	roleRepo := repositories.NewRoleRepository(db)
 	roleService := services.NewRoleService(roleRepo)
-	roleController := controllers.NewRoleController(roleService, authUtil, logUtil)
+	roleController := controllers.NewRoleController(roleService, authUtil, logUtil, auditUtil)
 	routes.RegisterRoleRoutes(router, roleController)
 }
 
@@ -140,17 +405,29 @@ func setUpItemTypeRouter() {
 func setUpUserTypeRouter() {
 	userTypeRepo := repositories.NewUserTypeRepository(db)
 	userTypeService := services.NewUserTypeService(userTypeRepo)
-	userTypeController := controllers.NewUserTypeController(userTypeService, authUtil, logUtil)
+	userTypeController := controllers.NewUserTypeController(userTypeService, authUtil, logUtil, auditUtil)
 	routes.RegisterUserTypeRoutes(router, userTypeController)
 }
 
 func setUpItemRouter() {
 	itemRepo := repositories.NewItemRepository(db)
-	itemService := services.NewItemService(itemRepo)
-	itemController := controllers.NewItemController(itemService, authUtil, logUtil)
+	itemRepo.Replica = replicaDB
+	itemService := services.NewItemService(itemRepo, repositories.NewHistoricalItemPriceRepository(db), repositories.NewCurrencyRepository(db),
+		repositories.NewGormTransactor(db))
+	itemService.Bus = eventBus
+	itemService.Events = eventStreamService
+	itemController := controllers.NewItemController(itemService, authUtil, logUtil, auditUtil)
 	routes.RegisterItemRoutes(router, itemController)
 }
 
+func setUpPublicCatalogRouter() {
+	itemRepo := repositories.NewItemRepository(db)
+	itemRepo.Replica = replicaDB
+	publicCatalogService := services.NewPublicCatalogService(itemRepo)
+	publicCatalogController := controllers.NewPublicCatalogController(publicCatalogService, logUtil)
+	routes.RegisterPublicCatalogRoutes(router, publicCatalogController)
+}
+
 func setUpUserStateTypeRouter() {
 	userStateTypeRepo := repositories.NewUserStateTypeRepository(db)
 	userStateTypeService := services.NewUserStateTypeService(userStateTypeRepo)
@@ -168,13 +445,15 @@ func setUpIdentifierTypeRouter() {
 func setUpUserRouter() {
 	userRepo := repositories.NewUserRepository(db)
 	userService := services.NewUserService(userRepo)
-	userController := controllers.NewUserController(userService, authUtil, logUtil)
+	userController := controllers.NewUserController(userService, authUtil, logUtil, auditUtil)
+	userController.Tokens = tokenService
 	routes.RegisterUserRoutes(router, userController)
 }
 
 func setUpAdditionalExpenseRouter() {
 	addRepo := repositories.NewAdditionalExpenseRepository(db)
 	addService := services.NewAdditionalExpenseService(addRepo)
+	addService.Suppliers = supplierService
 	addController := controllers.NewAdditionalExpenseController(addService, authUtil, logUtil)
 	routes.RegisterAdditionalExpenseRoutes(router, addController)
 }
@@ -189,6 +468,7 @@ func setUpHistoricalItemPriceRouter() {
 func setUpCommentRouter() {
 	commentRepo := repositories.NewCommentRepository(db)
 	commentService := services.NewCommentService(commentRepo)
+	commentService.Events = eventStreamService
 	commentController := controllers.NewCommentController(commentService, authUtil, logUtil)
 	routes.RegisterCommentRoutes(router, commentController)
 }
@@ -204,14 +484,29 @@ func setUpAuthRouter() {
 func setUpAppointmentRouter() {
 	appointmentRepo := repositories.NewAppointmentRepository(db)
 	appointmentService := services.NewAppointmentService(appointmentRepo)
+	appointmentService.Customers = repositories.NewCustomerRepository(db)
+	appointmentService.Employees = repositories.NewEmployeeRepository(db)
+	appointmentService.Tx = repositories.NewGormTransactor(db)
+	appointmentService.Bus = eventBus
+	appointmentService.Email = emailService
+	appointmentService.Texts = textMessageService
+	appointmentService.Links = linkSigner
+	appointmentService.Hours = businessHoursService
+	appointmentService.Captcha = captchaVerifier
+	appointmentService.ConfirmationEmails = config.Get().Notifications.AppointmentConfirmation
+	appointmentService.HandleEvents(eventBus)
 	appointmentController := controllers.NewAppointmentController(appointmentService, authUtil, logUtil)
+	appointmentController.Reminders = appointmentReminderService
 	routes.RegisterAppointmentRoutes(router, appointmentController)
 }
 
 func setUpCustomerRouter() {
 	customerRepo := repositories.NewCustomerRepository(db)
-	customerService := services.NewCustomerService(customerRepo)
-	customerController := controllers.NewCustomerController(customerService, authUtil, logUtil)
+	customerRepo.Replica = replicaDB
+	customerService := services.NewCustomerService(customerRepo, repositories.NewGormTransactor(db))
+	customerService.Geocoder = geocoder
+	customerService.RejectUnknownAddresses = config.Get().Geocoding.RejectUnknown
+	customerController := controllers.NewCustomerController(customerService, authUtil, logUtil, auditUtil)
 	routes.RegisterCustomerRoutes(router, customerController)
 
 }
@@ -225,14 +520,26 @@ func setUpOrderStateTypeRouter() {
 
 func setUpPurchaseOrderRouter() {
 	purchaseOrderRepo := repositories.NewPurchaseOrderRepository(db)
+	purchaseOrderRepo.Replica = replicaDB
 	itemRepo := repositories.NewItemRepository(db)
+	itemRepo.Replica = replicaDB
 	billingRepo := repositories.NewItemRepository(db)
+	billingRepo.Replica = replicaDB
 	discountRepo := repositories.NewDiscountTypeRepository(db)
 	taxRepo := repositories.NewTaxTypeRepository(db)
 	invoiceRepo := repositories.NewInvoiceRepository(db)
+	invoiceRepo.Replica = replicaDB
 
-	billingService := services.NewBillingService(billingRepo, discountRepo, taxRepo)
+	billingService := services.NewBillingService(billingRepo, discountRepo, taxRepo, repositories.NewCurrencyRepository(db),
+		repositories.NewPriceListRepository(db))
 	purchaseOrderService := services.NewPurchaseOrderService(purchaseOrderRepo, itemRepo, billingService, invoiceRepo)
+	purchaseOrderService.Bus = eventBus
+	purchaseOrderService.Events = eventStreamService
+	purchaseOrderService.Accounting = accountingService
+	if ecommerceService != nil {
+		// los pedidos web se importan como órdenes de compra
+		ecommerceService.Orders = purchaseOrderService
+	}
 	purchaseOrderController := controllers.NewPurchaseOrderController(purchaseOrderService, authUtil, logUtil)
 
 	routes.RegisterPurchaseOrderRoutes(router, purchaseOrderController)
@@ -248,7 +555,8 @@ func setUpDiscountTypeRouter() {
 func setUpUserCredentialValidationRouter() {
 	userRepository := repositories.NewUserRepository(db)
 	userCredentialValidationService := services.NewUserCredentialValidationService(userRepository)
-	userCredentialValidationController := controllers.NewUserCredentialValidationController(userCredentialValidationService, authUtil, logUtil)
+	userCredentialValidationController := controllers.NewUserCredentialValidationController(userCredentialValidationService, tokenService, authUtil, logUtil)
+	userCredentialValidationController.Resets = services.NewPasswordResetService(userRepository, tokenService, emailService, linkSigner)
 	routes.RegisterUserCredentialValidationRoutes(router, userCredentialValidationController)
 }
 
@@ -261,10 +569,12 @@ func setUpTaxTypeRouter() {
 
 func setUpBillingRouter() {
 	billingRepo := repositories.NewItemRepository(db)
+	billingRepo.Replica = replicaDB
 	discountRepo := repositories.NewDiscountTypeRepository(db)
 	taxRepo := repositories.NewTaxTypeRepository(db)
 
-	billingService := services.NewBillingService(billingRepo, discountRepo, taxRepo)
+	billingService := services.NewBillingService(billingRepo, discountRepo, taxRepo, repositories.NewCurrencyRepository(db),
+		repositories.NewPriceListRepository(db))
 	billingController := controllers.NewBillingController(billingService, authUtil)
 
 	routes.RegisterBillingRoutes(router, billingController)
@@ -272,21 +582,43 @@ func setUpBillingRouter() {
 
 func setUpInvoice() {
 	invoiceRepo := repositories.NewInvoiceRepository(db)
+	invoiceRepo.Replica = replicaDB
 	itemRepo := repositories.NewItemRepository(db)
+	itemRepo.Replica = replicaDB
 	billingRepo := repositories.NewItemRepository(db)
+	billingRepo.Replica = replicaDB
 	discountRepo := repositories.NewDiscountTypeRepository(db)
 	taxRepo := repositories.NewTaxTypeRepository(db)
 
-	billingService := services.NewBillingService(billingRepo, discountRepo, taxRepo)
+	billingService := services.NewBillingService(billingRepo, discountRepo, taxRepo, repositories.NewCurrencyRepository(db),
+		repositories.NewPriceListRepository(db))
 	invoiceService := services.NewInvoiceService(invoiceRepo, itemRepo, billingService)
+	invoiceService.Bus = eventBus
+	invoiceService.Events = eventStreamService
+	invoiceService.Accounting = accountingService
+	invoiceService.Email = emailService
+	invoiceService.ReceiptEmails = config.Get().Notifications.InvoiceReceipt
+	invoiceService.HandleEvents(eventBus)
 	invoiceController := controllers.NewInvoiceController(invoiceService, authUtil, logUtil)
+	invoiceController.Reminders = paymentReminderService
 
 	routes.RegisterInvoice(router, invoiceController)
+	if paymentGateway != nil {
+		// los pagos de la pasarela se registran como los demás pagos de la factura
+		setUpPaymentGatewayRouter(invoiceService)
+	}
+}
+
+func setUpPaymentGatewayRouter(invoiceService *services.InvoiceService) {
+	paymentGatewayService := services.NewPaymentGatewayService(repositories.NewGatewayPaymentRepository(db), invoiceService, paymentGateway)
+	paymentGatewayController := controllers.NewPaymentGatewayController(paymentGatewayService, logUtil)
+	routes.RegisterPaymentGatewayRoutes(router, paymentGatewayController)
 }
 
 func setUpExternalSaleRouter() {
 	externalSaleRepo := repositories.NewExternalSaleRepository(db)
 	customerRepo := repositories.NewCustomerRepository(db)
+	customerRepo.Replica = replicaDB
 	externalSaleService := services.NewExternalSaleService(externalSaleRepo, customerRepo)
 	externalSaleController := controllers.NewExternalSaleController(externalSaleService, authUtil, logUtil)
 	routes.RegisterExternalSaleRoutes(router, externalSaleController)
@@ -294,7 +626,220 @@ func setUpExternalSaleRouter() {
 
 func setUpSalesReportRouter() {
 	invoiceRepo := repositories.NewInvoiceRepository(db)
+	invoiceRepo.Replica = replicaDB
 	salesReportService := services.NewSalesReportService(invoiceRepo)
 	salesReportController := controllers.NewSalesReportController(salesReportService, authUtil, logUtil)
 	routes.RegisterSalesReportRoutes(router, salesReportController)
 }
+
+func setUpDashboardRouter() {
+	dashboardRepo := repositories.NewDashboardRepository(db)
+	dashboardRepo.Replica = replicaDB
+	dashboardService := services.NewDashboardService(dashboardRepo)
+	dashboardController := controllers.NewDashboardController(dashboardService, authUtil, logUtil)
+	routes.RegisterDashboardRoutes(router, dashboardController)
+}
+
+func setUpInventoryReportRouter() {
+	inventoryReportRepo := repositories.NewInventoryReportRepository(db)
+	inventoryReportRepo.Replica = replicaDB
+	inventoryReportService := services.NewInventoryReportService(inventoryReportRepo)
+	inventoryReportController := controllers.NewInventoryReportController(inventoryReportService, authUtil, logUtil)
+	routes.RegisterInventoryReportRoutes(router, inventoryReportController)
+}
+
+func setUpAppointmentReportRouter() {
+	appointmentReportRepo := repositories.NewAppointmentReportRepository(db)
+	appointmentReportRepo.Replica = replicaDB
+	appointmentReportService := services.NewAppointmentReportService(appointmentReportRepo)
+	appointmentReportController := controllers.NewAppointmentReportController(appointmentReportService, authUtil, logUtil)
+	routes.RegisterAppointmentReportRoutes(router, appointmentReportController)
+}
+
+func setUpDailyCloseRouter() {
+	dailyCloseRepo := repositories.NewDailyCloseRepository(db)
+	invoiceRepo := repositories.NewInvoiceRepository(db)
+	invoiceRepo.Replica = replicaDB
+	dailyCloseService := services.NewDailyCloseService(dailyCloseRepo, invoiceRepo)
+	dailyCloseController := controllers.NewDailyCloseController(dailyCloseService, authUtil, logUtil)
+	routes.RegisterDailyCloseRoutes(router, dailyCloseController)
+}
+
+func setUpUserLogRouter() {
+	userLogController := controllers.NewUserLogController(logUtil.LogService, authUtil, logUtil)
+	routes.RegisterUserLogRoutes(router, userLogController)
+}
+
+func setUpAuditRouter() {
+	auditController := controllers.NewAuditController(auditUtil.AuditService, authUtil, logUtil)
+	routes.RegisterAuditRoutes(router, auditController)
+}
+
+func setUpSecurityEventRouter() {
+	securityEventController := controllers.NewSecurityEventController(logUtil.Security, authUtil, logUtil)
+	routes.RegisterSecurityEventRoutes(router, securityEventController)
+}
+
+func setUpHealthRouter() {
+	healthController := controllers.NewHealthController(services.NewHealthService(db))
+	routes.RegisterHealthRoutes(router, healthController)
+}
+
+func setUpWebhookRouter() {
+	webhookController := controllers.NewWebhookController(webhookService, authUtil, logUtil)
+	routes.RegisterWebhookRoutes(router, webhookController)
+}
+
+func setUpEventStreamRouter() {
+	eventStreamController := controllers.NewEventStreamController(eventStreamService, authUtil, logUtil)
+	routes.RegisterEventStreamRoutes(router, eventStreamController)
+}
+
+func setUpMigrationRouter() {
+	migrationController := controllers.NewMigrationController(services.NewMigrationService(db), authUtil, logUtil)
+	routes.RegisterMigrationRoutes(router, migrationController)
+}
+
+func setUpSchedulerRouter() {
+	schedulerController := controllers.NewSchedulerController(schedulerService, authUtil, logUtil)
+	routes.RegisterSchedulerRoutes(router, schedulerController)
+}
+
+func setUpNotificationPreferenceRouter() {
+	customerRepo := repositories.NewCustomerRepository(db)
+	customerService := services.NewCustomerService(customerRepo, repositories.NewGormTransactor(db))
+	userService := services.NewUserService(repositories.NewUserRepository(db))
+	notificationPreferenceController := controllers.NewNotificationPreferenceController(notificationPreferenceService,
+		userService, customerService, authUtil, logUtil)
+	routes.RegisterNotificationPreferenceRoutes(router, notificationPreferenceController)
+}
+
+func setUpNotificationRouter() {
+	userService := services.NewUserService(repositories.NewUserRepository(db))
+	notificationController := controllers.NewNotificationController(inboxService, userService, authUtil, logUtil)
+	routes.RegisterNotificationRoutes(router, notificationController)
+}
+
+func setUpEmailTemplateRouter() {
+	emailTemplateController := controllers.NewEmailTemplateController(emailTemplateService, authUtil, logUtil)
+	routes.RegisterEmailTemplateRoutes(router, emailTemplateController)
+}
+
+func setUpNotificationDeliveryRouter() {
+	deliveryService := services.NewNotificationDeliveryService(repositories.NewNotificationDeliveryRepository(db), emailService)
+	deliveryService.Texts = textMessageService
+	deliveryController := controllers.NewNotificationDeliveryController(deliveryService, authUtil, logUtil)
+	routes.RegisterNotificationDeliveryRoutes(router, deliveryController)
+}
+
+func setUpStockRouter() {
+	stockRepo := repositories.NewStockMovementRepository(db)
+	stockRepo.Replica = replicaDB
+	stockController := controllers.NewStockController(services.NewStockService(stockRepo), authUtil, logUtil)
+	routes.RegisterStockRoutes(router, stockController)
+}
+
+func setUpRestockOrderRouter() {
+	restockOrderRepo := repositories.NewRestockOrderRepository(db)
+	restockOrderRepo.Replica = replicaDB
+	restockOrderService := services.NewRestockOrderService(restockOrderRepo, repositories.NewItemRepository(db))
+	restockOrderService.Bus = eventBus
+	restockOrderService.Suppliers = supplierService
+	restockOrderController := controllers.NewRestockOrderController(restockOrderService, authUtil, logUtil)
+	routes.RegisterRestockOrderRoutes(router, restockOrderController)
+}
+
+func setUpSupplierRouter() {
+	supplierController := controllers.NewSupplierController(supplierService, authUtil, logUtil)
+	routes.RegisterSupplierRoutes(router, supplierController)
+}
+
+func setUpCreditNoteRouter() {
+	creditNoteRepo := repositories.NewCreditNoteRepository(db)
+	creditNoteRepo.Replica = replicaDB
+	creditNoteService := services.NewCreditNoteService(creditNoteRepo)
+	creditNoteService.Bus = eventBus
+	creditNoteController := controllers.NewCreditNoteController(creditNoteService, authUtil, logUtil)
+	routes.RegisterCreditNoteRoutes(router, creditNoteController)
+}
+
+func setUpInvoiceReturnRouter() {
+	invoiceReturnService := services.NewInvoiceReturnService(repositories.NewInvoiceReturnRepository(db))
+	invoiceReturnService.Bus = eventBus
+	invoiceReturnController := controllers.NewInvoiceReturnController(invoiceReturnService, authUtil, logUtil)
+	routes.RegisterInvoiceReturnRoutes(router, invoiceReturnController)
+}
+
+func setUpBusinessHoursRouter() {
+	businessHoursController := controllers.NewBusinessHoursController(businessHoursService, authUtil, logUtil)
+	routes.RegisterBusinessHoursRoutes(router, businessHoursController)
+}
+
+func setUpQuotationRouter() {
+	quotationRepo := repositories.NewQuotationRepository(db)
+	quotationRepo.Replica = replicaDB
+	itemRepo := repositories.NewItemRepository(db)
+	itemRepo.Replica = replicaDB
+	billingService := services.NewBillingService(itemRepo, repositories.NewDiscountTypeRepository(db), repositories.NewTaxTypeRepository(db),
+		repositories.NewCurrencyRepository(db), repositories.NewPriceListRepository(db))
+	invoiceService := services.NewInvoiceService(repositories.NewInvoiceRepository(db), itemRepo, billingService)
+	invoiceService.Bus = eventBus
+	invoiceService.Events = eventStreamService
+	invoiceService.Accounting = accountingService
+	quotationService := services.NewQuotationService(quotationRepo, invoiceService)
+	quotationController := controllers.NewQuotationController(quotationService, authUtil, logUtil)
+	routes.RegisterQuotationRoutes(router, quotationController)
+}
+
+func setUpCurrencyRouter() {
+	currencyService := services.NewCurrencyService(repositories.NewCurrencyRepository(db))
+	currencyController := controllers.NewCurrencyController(currencyService, authUtil, logUtil)
+	routes.RegisterCurrencyRoutes(router, currencyController)
+}
+
+func setUpPriceListRouter() {
+	priceListService := services.NewPriceListService(repositories.NewPriceListRepository(db), repositories.NewItemRepository(db),
+		repositories.NewCustomerRepository(db))
+	priceListController := controllers.NewPriceListController(priceListService, authUtil, logUtil)
+	routes.RegisterPriceListRoutes(router, priceListController)
+}
+
+func setUpItemImageRouter(cfg config.StorageConfig) {
+	if cfg.Provider == storage.STORAGE_PROVIDER_LOCAL {
+		router.Static(config.STORAGE_LOCAL_PATH, cfg.Dir)
+	}
+	itemImageService := services.NewItemImageService(repositories.NewItemImageRepository(db), repositories.NewItemRepository(db),
+		fileStorage, cfg.MaxImageBytes)
+	itemImageController := controllers.NewItemImageController(itemImageService, authUtil, logUtil)
+	routes.RegisterItemImageRoutes(router, itemImageController)
+}
+
+func setUpArchiveRouter() {
+	archiveController := controllers.NewArchiveController(archiveService, authUtil, logUtil)
+	routes.RegisterArchiveRoutes(router, archiveController)
+}
+
+func setUpDataExportRouter() {
+	dataExportController := controllers.NewDataExportController(dataExportService, authUtil, logUtil)
+	routes.RegisterDataExportRoutes(router, dataExportController)
+}
+
+func setUpSandboxRouter(cfg config.SandboxConfig) {
+	sandboxController := controllers.NewSandboxController(services.NewSandboxService(db, cfg), authUtil, logUtil)
+	routes.RegisterSandboxRoutes(router, sandboxController)
+}
+
+func setUpAccountingRouter() {
+	accountingController := controllers.NewAccountingController(accountingService, authUtil, logUtil)
+	routes.RegisterAccountingRoutes(router, accountingController)
+}
+
+func setUpEcommerceRouter() {
+	ecommerceController := controllers.NewEcommerceController(ecommerceService, authUtil, logUtil)
+	routes.RegisterEcommerceRoutes(router, ecommerceController)
+}
+
+func setUpMetaRouter() {
+	metaController := controllers.NewMetaController(router, authUtil, logUtil)
+	routes.RegisterMetaRoutes(router, metaController)
+}
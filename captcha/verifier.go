@@ -0,0 +1,122 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"totesbackend/config"
+)
+
+const (
+	CAPTCHA_PROVIDER_RECAPTCHA = "recaptcha"
+	CAPTCHA_PROVIDER_HCAPTCHA  = "hcaptcha"
+	CAPTCHA_PROVIDER_TURNSTILE = "turnstile"
+)
+
+// ErrCaptchaRejected indica que el proveedor no aceptó el token (vacío, vencido, ya usado o con
+// puntaje bajo). Cualquier otro error es del proveedor y no del cliente.
+var ErrCaptchaRejected = errors.New("captcha rejected")
+
+type Verifier interface {
+	Provider() string
+	Verify(ctx context.Context, token, remoteIP string) error
+}
+
+// NewVerifier crea el verificador del proveedor configurado en CAPTCHA_PROVIDER. Devuelve nil si el
+// captcha está desactivado.
+func NewVerifier(cfg config.CaptchaConfig) (Verifier, error) {
+	var endpoint string
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case CAPTCHA_PROVIDER_RECAPTCHA:
+		endpoint = "https://www.google.com/recaptcha/api/siteverify"
+	case CAPTCHA_PROVIDER_HCAPTCHA:
+		endpoint = "https://api.hcaptcha.com/siteverify"
+	case CAPTCHA_PROVIDER_TURNSTILE:
+		endpoint = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+	default:
+		return nil, fmt.Errorf("unknown captcha provider %q", cfg.Provider)
+	}
+	return &SiteVerifier{
+		Config:   cfg,
+		Endpoint: endpoint,
+		Client:   &http.Client{Timeout: config.CAPTCHA_REQUEST_TIMEOUT},
+	}, nil
+}
+
+// SiteVerifier verifica tokens con el endpoint siteverify, que reCAPTCHA, hCaptcha y Turnstile
+// implementan igual.
+type SiteVerifier struct {
+	Config   config.CaptchaConfig
+	Endpoint string
+	Client   *http.Client
+}
+
+func (v *SiteVerifier) Provider() string {
+	return v.Config.Provider
+}
+
+func (v *SiteVerifier) Verify(ctx context.Context, token, remoteIP string) error {
+	if strings.TrimSpace(token) == "" {
+		return ErrCaptchaRejected
+	}
+	form := url.Values{}
+	form.Set("secret", v.Config.Secret)
+	form.Set("response", token)
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.Endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := v.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if len(body) > 300 {
+			body = body[:300]
+		}
+		return fmt.Errorf("captcha provider responded %s: %s", resp.Status, body)
+	}
+
+	var response struct {
+		Success bool `json:"success"`
+		// solo reCAPTCHA v3
+		Score      *float64 `json:"score"`
+		ErrorCodes []string `json:"error-codes"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return err
+	}
+	for _, code := range response.ErrorCodes {
+		// errores de configuración del servidor, no del token
+		if code == "missing-input-secret" || code == "invalid-input-secret" || code == "sitekey-secret-mismatch" {
+			return fmt.Errorf("captcha provider rejected the secret: %s", code)
+		}
+	}
+	if !response.Success {
+		return ErrCaptchaRejected
+	}
+	if response.Score != nil && *response.Score < config.CAPTCHA_RECAPTCHA_MIN_SCORE {
+		return ErrCaptchaRejected
+	}
+	return nil
+}
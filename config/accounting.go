@@ -0,0 +1,18 @@
+package config
+
+import "time"
+
+const (
+	// A document is marked as failed after this many attempts; POST /accounting/syncs/{id}/resync
+	// queues it again
+	ACCOUNTING_MAX_ATTEMPTS = 6
+	// Delay before the first retry; it doubles on every failed attempt
+	ACCOUNTING_RETRY_BASE_DELAY = time.Minute
+	ACCOUNTING_RETRY_MAX_DELAY  = 2 * time.Hour
+	// How often pending documents are checked
+	ACCOUNTING_POLL_INTERVAL = 30 * time.Second
+	// Documents pushed per polling round
+	ACCOUNTING_BATCH_SIZE = 20
+	// A claimed document is not picked up by another instance for this long
+	ACCOUNTING_SYNC_LEASE = 5 * time.Minute
+)
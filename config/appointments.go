@@ -0,0 +1,31 @@
+package config
+
+import "time"
+
+// Appointment states, seeded with these IDs in appointment_state_types
+const (
+	APPOINTMENT_STATE_PENDING   = 1
+	APPOINTMENT_STATE_CONFIRMED = 2
+	APPOINTMENT_STATE_COMPLETED = 3
+	APPOINTMENT_STATE_CANCELLED = 4
+	APPOINTMENT_STATE_NO_SHOW   = 5
+)
+
+const (
+	// Appointments processed per round when linking historical appointments to customers
+	APPOINTMENT_LINK_BATCH_SIZE = 100
+	// Appointments without an assigned employee that can be booked at the same date and time
+	APPOINTMENT_SLOT_CAPACITY = 3
+	// Appointments an employee can be assigned at the same date and time
+	APPOINTMENT_EMPLOYEE_SLOT_CAPACITY = 1
+	// Default business hours, for the weekdays not configured in business_hours: one-hour slots
+	// starting from the opening hour up to the last slot's hour
+	APPOINTMENT_OPENING_HOUR   = 9
+	APPOINTMENT_LAST_SLOT_HOUR = 17
+	// How far ahead the public booking endpoint accepts appointments
+	PUBLIC_BOOKING_MAX_DAYS_AHEAD = 60
+	// Requests per client IP and window to the public booking endpoints
+	PUBLIC_BOOKING_RATE_LIMIT      = 10
+	PUBLIC_SLOTS_RATE_LIMIT        = 60
+	PUBLIC_APPOINTMENT_RATE_WINDOW = time.Minute
+)
@@ -0,0 +1,6 @@
+package config
+
+const (
+	// Documentos que el archivado revisa por vuelta
+	ARCHIVE_BATCH_SIZE = 200
+)
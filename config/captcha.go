@@ -0,0 +1,9 @@
+package config
+
+import "time"
+
+const (
+	CAPTCHA_REQUEST_TIMEOUT = 10 * time.Second
+	// reCAPTCHA v3 scores each request from 0.0 (bot) to 1.0; lower scores are rejected
+	CAPTCHA_RECAPTCHA_MIN_SCORE = 0.5
+)
@@ -0,0 +1,897 @@
+package config
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config agrupa todos los ajustes que se leen del entorno. Se carga y valida una sola vez al
+// arrancar (Load); el resto del código la consulta con Get en lugar de leer variables sueltas.
+type Config struct {
+	Database      DatabaseConfig
+	Server        ServerConfig
+	Auth          AuthConfig
+	Log           LogConfig
+	Email         EmailConfig
+	SMTP          SMTPConfig
+	Text          TextConfig
+	Notifications NotificationConfig
+	RateLimit     RateLimitConfig
+	CORS          CORSConfig
+	Compression   CompressionConfig
+	Features      FeatureFlags
+	Archive       ArchiveConfig
+	Export        ExportConfig
+	Sandbox       SandboxConfig
+	Accounting    AccountingConfig
+	Geocoding     GeocodingConfig
+	Captcha       CaptchaConfig
+	Ecommerce     EcommerceConfig
+	Payments      PaymentGatewayConfig
+	Storage       StorageConfig
+	Seed          SeedConfig
+}
+
+type DatabaseConfig struct {
+	// POSTGRES_URI
+	DSN string
+	// POSTGRES_REPLICA_URIS: réplicas de solo lectura separadas por comas (opcional)
+	ReplicaDSNs []string
+	Pool        DBPoolConfig
+	// DB_QUERY_TIMEOUT: tiempo máximo de cada consulta de un repositorio
+	QueryTimeout time.Duration
+	// DB_ALLOW_DESTRUCTIVE_MIGRATIONS: sin él el servidor no arranca con una migración destructiva pendiente
+	AllowDestructiveMigrations bool
+	// esquema en el que se trabaja en lugar de public; solo lo fija SANDBOX_MODE
+	Schema string
+}
+
+type DBPoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+type ServerConfig struct {
+	// SERVER_PORT
+	Port     int
+	CertFile string
+	KeyFile  string
+	// SERVER_READ_HEADER_TIMEOUT, SERVER_READ_TIMEOUT, SERVER_WRITE_TIMEOUT y SERVER_IDLE_TIMEOUT
+	// limitan cada conexión; el stream de eventos y las descargas de reportes no tienen WriteTimeout
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// SERVER_SHUTDOWN_TIMEOUT: cuánto esperan las peticiones en curso al recibir SIGINT/SIGTERM
+	ShutdownTimeout time.Duration
+}
+
+// Address es la dirección en la que escucha el servidor HTTPS.
+func (s ServerConfig) Address() string {
+	return ":" + strconv.Itoa(s.Port)
+}
+
+// AuthConfig firma los tokens de acceso (JWT HS256) y fija cuánto duran las sesiones.
+type AuthConfig struct {
+	// JWT_SECRET: al menos 32 caracteres; cambiarlo invalida los tokens de acceso emitidos
+	JWTSecret string
+	// JWT_ACCESS_TOKEN_TTL: vigencia del token de acceso
+	AccessTokenTTL time.Duration
+	// JWT_REFRESH_TOKEN_TTL: vigencia del token de refresco; cada refresco emite uno nuevo
+	RefreshTokenTTL time.Duration
+}
+
+type LogConfig struct {
+	// LOG_FAILURE_POLICY: fail-open o fail-closed
+	FailurePolicy string
+	// LOG_SINK: stdout, syslog o http; vacío lo desactiva
+	SinkKind    string
+	SinkNetwork string
+	SinkAddress string
+	SinkToken   string
+}
+
+type EmailConfig struct {
+	// EMAIL_PROVIDER: smtp, sendgrid o log. Por defecto smtp si hay SMTP_HOST, si no log
+	Provider string
+	// EMAIL_FROM: remitente, p. ej. "Totes <no-reply@totes.com>"
+	From           string
+	SendGridAPIKey string
+}
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+}
+
+// TextConfig es el proveedor de SMS y WhatsApp.
+type TextConfig struct {
+	// TEXT_PROVIDER: twilio o log. Por defecto twilio si hay TWILIO_ACCOUNT_SID, si no log
+	Provider         string
+	TwilioAccountSID string
+	TwilioAuthToken  string
+	// TWILIO_FROM: número que envía los SMS, en formato E.164 (+573001234567)
+	From string
+	// TWILIO_WHATSAPP_FROM: número habilitado para WhatsApp; sin él no se envían mensajes por WhatsApp
+	WhatsAppFrom string
+	// TEXT_DEFAULT_COUNTRY_CODE: indicativo que se antepone a los teléfonos guardados sin "+"
+	DefaultCountryCode string
+}
+
+type NotificationConfig struct {
+	// PUBLIC_BASE_URL: dirección pública del API, usada en los enlaces de los correos
+	PublicURL string
+	// NOTIFICATION_SIGNING_KEY: firma los enlaces de los correos (darse de baja, cancelar una cita)
+	SigningKey string
+	// APPOINTMENT_CONFIRMATION_EMAIL: enviar la confirmación al agendar una cita
+	AppointmentConfirmation bool
+	// INVOICE_EMAIL: enviar la factura al cliente al emitirla
+	InvoiceReceipt bool
+	// PAYMENT_REMINDER_DAYS: días respecto del vencimiento en que se recuerda el pago de una
+	// factura, negativos antes y positivos después; "off" desactiva los recordatorios
+	PaymentReminderDays []int
+	// APPOINTMENT_REMINDER_HOURS: horas antes de la cita en que se le recuerda al cliente; "off"
+	// desactiva los recordatorios
+	AppointmentReminderHours []int
+}
+
+type RateLimitConfig struct {
+	// RATE_LIMIT_REQUESTS peticiones por cliente en cada RATE_LIMIT_WINDOW
+	Requests int
+	Window   time.Duration
+	// RATE_LIMIT_ROUTES: límites más estrictos por ruta, "METHOD /path=n" separados por comas, que
+	// se suman a los de Routes; cada ruta cuenta aparte y además consume del límite global
+	Routes map[string]int
+}
+
+// CORSConfig controla qué frontends de otros dominios pueden llamar a la API desde el navegador.
+type CORSConfig struct {
+	// CORS_ALLOWED_ORIGINS: orígenes separados por comas; "*" admite cualquiera y
+	// "https://*.example.com" cualquier subdominio
+	AllowedOrigins []string
+	// CORS_ALLOWED_METHODS y CORS_ALLOWED_HEADERS, separados por comas
+	AllowedMethods []string
+	AllowedHeaders []string
+	// CORS_ALLOW_CREDENTIALS: permite enviar cookies y el encabezado Authorization
+	AllowCredentials bool
+	// CORS_MAX_AGE: cuánto guarda el navegador la respuesta al preflight
+	MaxAge time.Duration
+}
+
+// CompressionConfig controla qué respuestas se comprimen con gzip.
+type CompressionConfig struct {
+	// COMPRESSION_MIN_SIZE: cuerpos más chicos se envían sin comprimir
+	MinSize int
+	// COMPRESSION_CONTENT_TYPES: tipos de contenido comprimibles separados por comas
+	ContentTypes []string
+}
+
+// FeatureFlags son las funcionalidades activadas con FEATURE_FLAGS (lista separada por comas).
+type FeatureFlags map[string]bool
+
+func (f FeatureFlags) Enabled(name string) bool {
+	return f[name]
+}
+
+// ArchiveConfig fija la antigüedad con la que las facturas y citas pasan a las tablas de archivo.
+type ArchiveConfig struct {
+	// ARCHIVE_INVOICES_AFTER_DAYS
+	InvoiceDays int
+	// ARCHIVE_APPOINTMENTS_AFTER_DAYS
+	AppointmentDays int
+}
+
+type ExportConfig struct {
+	// EXPORT_DIR: directorio donde se guardan los zip de las exportaciones. Con varias instancias
+	// debe ser compartido, porque la descarga puede llegar a una instancia distinta de la que lo generó
+	Dir string
+}
+
+// SandboxConfig activa el modo demo: el servidor trabaja sobre un esquema aparte con datos de
+// ejemplo que se pueden reiniciar, sin tocar los datos reales de la misma base.
+type SandboxConfig struct {
+	// SANDBOX_MODE
+	Enabled bool
+	// SANDBOX_SCHEMA
+	Schema string
+	// SANDBOX_ADMIN_EMAIL y SANDBOX_ADMIN_PASSWORD: administrador que se crea en cada reinicio
+	AdminEmail    string
+	AdminPassword string
+}
+
+type AccountingConfig struct {
+	// ACCOUNTING_PROVIDER: siigo o log; vacío desactiva la integración contable
+	Provider string
+	Siigo    SiigoConfig
+}
+
+// SiigoConfig son las credenciales de la API de Siigo Nube y los IDs de los catálogos de la cuenta
+// (tipo de comprobante, vendedor, forma de pago) con que se crean los documentos.
+type SiigoConfig struct {
+	// SIIGO_BASE_URL
+	BaseURL string
+	// SIIGO_USERNAME, SIIGO_ACCESS_KEY y SIIGO_PARTNER_ID
+	Username  string
+	AccessKey string
+	PartnerID string
+	// SIIGO_INVOICE_DOCUMENT_ID: tipo de comprobante de las facturas de venta
+	InvoiceDocumentID int
+	// SIIGO_VOUCHER_DOCUMENT_ID: tipo de comprobante de los recibos de caja (pagos)
+	VoucherDocumentID int
+	// SIIGO_SELLER_ID
+	SellerID int
+	// SIIGO_PAYMENT_METHOD_ID
+	PaymentMethodID int
+}
+
+type GeocodingConfig struct {
+	// GEOCODING_PROVIDER: google o nominatim; vacío desactiva la geocodificación
+	Provider string
+	// GEOCODING_API_KEY: obligatoria con google
+	APIKey string
+	// GEOCODING_BASE_URL: vacío usa la API pública del proveedor
+	BaseURL string
+	// GEOCODING_COUNTRY: código ISO 3166-1 del país al que se limitan las búsquedas
+	Country string
+	// GEOCODING_REJECT_UNKNOWN: rechaza al crear o editar un cliente las direcciones que el
+	// proveedor no encuentra, en lugar de guardarlas sin ubicación
+	RejectUnknown bool
+}
+
+type CaptchaConfig struct {
+	// CAPTCHA_PROVIDER: recaptcha, hcaptcha o turnstile; vacío no pide captcha en las reservas públicas
+	Provider string
+	// CAPTCHA_SECRET: clave secreta con la que se verifican los tokens
+	Secret string
+}
+
+type EcommerceConfig struct {
+	// ECOMMERCE_PROVIDER: shopify o woocommerce; vacío desactiva la sincronización con la tienda
+	Provider string
+	// ECOMMERCE_STORE_URL: https://<tienda>.myshopify.com o la URL del sitio de WordPress
+	StoreURL string
+	// ECOMMERCE_ACCESS_TOKEN: token de la Admin API (Shopify)
+	AccessToken string
+	// ECOMMERCE_CONSUMER_KEY y ECOMMERCE_CONSUMER_SECRET (WooCommerce)
+	ConsumerKey    string
+	ConsumerSecret string
+	// ECOMMERCE_LOCATION_ID: bodega de Shopify cuyas existencias se actualizan
+	LocationID string
+	// ECOMMERCE_WEBHOOK_SECRET: con él se verifica la firma de los webhooks de pedidos
+	WebhookSecret string
+	// ECOMMERCE_IDENTIFIER_TYPE_ID: tipo de documento de los clientes creados desde pedidos web
+	IdentifierTypeID int
+}
+
+type PaymentGatewayConfig struct {
+	// PAYMENT_GATEWAY: stripe o payu; vacío desactiva POST /webhooks/payments
+	Provider string
+	// PAYMENT_GATEWAY_WEBHOOK_SECRET: signing secret del endpoint de webhooks (Stripe)
+	WebhookSecret string
+	// PAYU_API_KEY y PAYU_MERCHANT_ID: con ellos se verifica la firma de las confirmaciones (PayU)
+	APIKey     string
+	MerchantID string
+}
+
+// StorageConfig indica dónde se guardan los archivos subidos, como las imágenes de los items.
+type StorageConfig struct {
+	// STORAGE_PROVIDER: local (por defecto) o s3
+	Provider string
+	// STORAGE_DIR: directorio de los archivos con local; el servidor los publica en /uploads
+	Dir string
+	// STORAGE_PUBLIC_URL: URL base de los archivos. Con local por defecto es PUBLIC_BASE_URL/uploads;
+	// con s3 vacío usa la URL del objeto en el bucket, que debe admitir lectura pública
+	PublicURL string
+	// S3_ENDPOINT, S3_REGION y S3_BUCKET: cualquier servicio compatible con S3 (AWS, MinIO, R2...);
+	// los objetos se direccionan como S3_ENDPOINT/S3_BUCKET/llave
+	Endpoint string
+	Region   string
+	Bucket   string
+	// S3_ACCESS_KEY_ID y S3_SECRET_ACCESS_KEY
+	AccessKeyID     string
+	SecretAccessKey string
+	// ITEM_IMAGE_MAX_BYTES: tamaño máximo de cada imagen de un item
+	MaxImageBytes int
+}
+
+type SeedConfig struct {
+	AdminEmail    string
+	AdminPassword string
+}
+
+// ConfigError lista todos los ajustes ausentes o inválidos encontrados al cargar la configuración.
+type ConfigError struct {
+	Problems []string
+}
+
+func (e *ConfigError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+var sandboxSchemaPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+var (
+	e164Pattern        = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+	countryCodePattern = regexp.MustCompile(`^[1-9][0-9]{0,2}$`)
+)
+
+var current = defaultConfig()
+
+// Get devuelve la configuración cargada por Load, o los valores por defecto si aún no se cargó.
+func Get() *Config {
+	return current
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		Database: DatabaseConfig{
+			Pool: DBPoolConfig{
+				MaxOpenConns:    25,
+				MaxIdleConns:    10,
+				ConnMaxLifetime: 30 * time.Minute,
+				ConnMaxIdleTime: 5 * time.Minute,
+			},
+			QueryTimeout: 10 * time.Second,
+		},
+		Server: ServerConfig{
+			Port:              443,
+			CertFile:          "certs/cert.pem",
+			KeyFile:           "certs/key.pem",
+			ReadHeaderTimeout: 10 * time.Second,
+			ReadTimeout:       30 * time.Second,
+			WriteTimeout:      60 * time.Second,
+			IdleTimeout:       2 * time.Minute,
+			ShutdownTimeout:   30 * time.Second,
+		},
+		Auth: AuthConfig{
+			AccessTokenTTL:  15 * time.Minute,
+			RefreshTokenTTL: 30 * 24 * time.Hour,
+		},
+		Log: LogConfig{
+			FailurePolicy: LOG_FAILURE_POLICY_OPEN,
+		},
+		SMTP: SMTPConfig{
+			Port: 587,
+		},
+		Text: TextConfig{
+			DefaultCountryCode: "57",
+		},
+		Notifications: NotificationConfig{
+			PublicURL:                "https://localhost",
+			AppointmentConfirmation:  true,
+			InvoiceReceipt:           true,
+			PaymentReminderDays:      []int{-3, 1, 7},
+			AppointmentReminderHours: []int{1, 24},
+		},
+		RateLimit: RateLimitConfig{
+			Requests: 100,
+			Window:   time.Minute,
+			Routes: map[string]int{
+				"POST /login":                      5,
+				"POST /user-credential-validation": 5,
+				"POST /comments":                   10,
+			},
+		},
+		CORS: CORSConfig{
+			AllowedOrigins:   []string{"http://localhost:3000", "http://127.0.0.1:5503", "http://127.0.0.1:5500", "http://127.0.0.1:5501"},
+			AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+			AllowedHeaders:   []string{"Origin", "Content-Type", "Authorization"},
+			AllowCredentials: true,
+			MaxAge:           12 * time.Hour,
+		},
+		Compression: CompressionConfig{
+			MinSize:      1024,
+			ContentTypes: []string{"application/json", "text/csv", "text/plain", "text/html", "text/css", "application/javascript"},
+		},
+		Features: FeatureFlags{},
+		Archive: ArchiveConfig{
+			InvoiceDays:     730,
+			AppointmentDays: 365,
+		},
+		Export: ExportConfig{
+			Dir: "exports",
+		},
+		Accounting: AccountingConfig{
+			Siigo: SiigoConfig{
+				BaseURL: "https://api.siigo.com",
+			},
+		},
+		Geocoding: GeocodingConfig{
+			Country: "co",
+		},
+		Ecommerce: EcommerceConfig{
+			IdentifierTypeID: 1,
+		},
+		Storage: StorageConfig{
+			Provider:      "local",
+			Dir:           "uploads",
+			Region:        "us-east-1",
+			MaxImageBytes: 5 << 20,
+		},
+		Sandbox: SandboxConfig{
+			Schema:        "sandbox",
+			AdminEmail:    "demo@example.com",
+			AdminPassword: "totes-demo",
+		},
+	}
+}
+
+// Load loads the .env file (see LoadENV), reads every setting from the environment and validates
+// them. All problems are reported together in a *ConfigError so they can be fixed in one go. On
+// success the configuration becomes the one returned by Get.
+func Load() (*Config, error) {
+	if err := LoadENV(); err != nil {
+		return nil, err
+	}
+
+	cfg := defaultConfig()
+	env := &envReader{}
+
+	cfg.Database.DSN = env.required("POSTGRES_URI")
+	for _, dsn := range strings.Split(os.Getenv("POSTGRES_REPLICA_URIS"), ",") {
+		if dsn = strings.TrimSpace(dsn); dsn != "" {
+			cfg.Database.ReplicaDSNs = append(cfg.Database.ReplicaDSNs, dsn)
+		}
+	}
+	cfg.Database.Pool.MaxOpenConns = env.positiveInt("DB_MAX_OPEN_CONNS", cfg.Database.Pool.MaxOpenConns)
+	cfg.Database.Pool.MaxIdleConns = env.positiveInt("DB_MAX_IDLE_CONNS", cfg.Database.Pool.MaxIdleConns)
+	cfg.Database.Pool.ConnMaxLifetime = env.duration("DB_CONN_MAX_LIFETIME", cfg.Database.Pool.ConnMaxLifetime)
+	cfg.Database.Pool.ConnMaxIdleTime = env.duration("DB_CONN_MAX_IDLE_TIME", cfg.Database.Pool.ConnMaxIdleTime)
+	if cfg.Database.Pool.MaxIdleConns > cfg.Database.Pool.MaxOpenConns {
+		cfg.Database.Pool.MaxIdleConns = cfg.Database.Pool.MaxOpenConns
+	}
+	cfg.Database.QueryTimeout = env.duration("DB_QUERY_TIMEOUT", cfg.Database.QueryTimeout)
+	cfg.Database.AllowDestructiveMigrations = env.boolean("DB_ALLOW_DESTRUCTIVE_MIGRATIONS", false)
+
+	cfg.Sandbox.Enabled = env.boolean("SANDBOX_MODE", false)
+	cfg.Sandbox.Schema = env.optional("SANDBOX_SCHEMA", cfg.Sandbox.Schema)
+	cfg.Sandbox.AdminEmail = env.optional("SANDBOX_ADMIN_EMAIL", cfg.Sandbox.AdminEmail)
+	cfg.Sandbox.AdminPassword = env.optional("SANDBOX_ADMIN_PASSWORD", cfg.Sandbox.AdminPassword)
+	if cfg.Sandbox.Enabled {
+		if !sandboxSchemaPattern.MatchString(cfg.Sandbox.Schema) || cfg.Sandbox.Schema == "public" {
+			env.problem("SANDBOX_SCHEMA must be a lowercase schema name other than public, got %q", cfg.Sandbox.Schema)
+		}
+		if len(cfg.Sandbox.AdminPassword) < 8 {
+			env.problem("SANDBOX_ADMIN_PASSWORD must be at least 8 characters")
+		}
+		cfg.Database.Schema = cfg.Sandbox.Schema
+		// las réplicas y los datos reales no se usan en modo demo
+		cfg.Database.ReplicaDSNs = nil
+	}
+
+	cfg.Server.Port = env.port("SERVER_PORT", cfg.Server.Port)
+	cfg.Server.CertFile = env.optional("SERVER_CERT_FILE", cfg.Server.CertFile)
+	cfg.Server.KeyFile = env.optional("SERVER_KEY_FILE", cfg.Server.KeyFile)
+	cfg.Server.ReadHeaderTimeout = env.duration("SERVER_READ_HEADER_TIMEOUT", cfg.Server.ReadHeaderTimeout)
+	cfg.Server.ReadTimeout = env.duration("SERVER_READ_TIMEOUT", cfg.Server.ReadTimeout)
+	cfg.Server.WriteTimeout = env.duration("SERVER_WRITE_TIMEOUT", cfg.Server.WriteTimeout)
+	cfg.Server.IdleTimeout = env.duration("SERVER_IDLE_TIMEOUT", cfg.Server.IdleTimeout)
+	cfg.Server.ShutdownTimeout = env.duration("SERVER_SHUTDOWN_TIMEOUT", cfg.Server.ShutdownTimeout)
+
+	cfg.Auth.JWTSecret = env.required("JWT_SECRET")
+	if cfg.Auth.JWTSecret != "" && len(cfg.Auth.JWTSecret) < 32 {
+		env.problem("JWT_SECRET must be at least 32 characters")
+	}
+	cfg.Auth.AccessTokenTTL = env.duration("JWT_ACCESS_TOKEN_TTL", cfg.Auth.AccessTokenTTL)
+	cfg.Auth.RefreshTokenTTL = env.duration("JWT_REFRESH_TOKEN_TTL", cfg.Auth.RefreshTokenTTL)
+	if cfg.Auth.RefreshTokenTTL < cfg.Auth.AccessTokenTTL {
+		env.problem("JWT_REFRESH_TOKEN_TTL must not be shorter than JWT_ACCESS_TOKEN_TTL")
+	}
+
+	cfg.Log.FailurePolicy = env.oneOf("LOG_FAILURE_POLICY", cfg.Log.FailurePolicy, LOG_FAILURE_POLICY_OPEN, LOG_FAILURE_POLICY_CLOSED)
+	cfg.Log.SinkKind = env.oneOf("LOG_SINK", "", "stdout", "syslog", "http")
+	cfg.Log.SinkNetwork = env.oneOf("LOG_SINK_NETWORK", "", "udp", "tcp")
+	cfg.Log.SinkAddress = env.optional("LOG_SINK_ADDRESS", "")
+	cfg.Log.SinkToken = env.optional("LOG_SINK_TOKEN", "")
+	if (cfg.Log.SinkKind == "syslog" || cfg.Log.SinkKind == "http") && cfg.Log.SinkAddress == "" {
+		env.problem("LOG_SINK_ADDRESS is required when LOG_SINK is %s", cfg.Log.SinkKind)
+	}
+
+	cfg.SMTP.Host = env.optional("SMTP_HOST", "")
+	cfg.SMTP.Port = env.port("SMTP_PORT", cfg.SMTP.Port)
+	cfg.SMTP.Username = env.optional("SMTP_USERNAME", "")
+	cfg.SMTP.Password = env.optional("SMTP_PASSWORD", "")
+
+	defaultProvider := "log"
+	if cfg.SMTP.Host != "" {
+		defaultProvider = "smtp"
+	}
+	cfg.Email.Provider = env.oneOf("EMAIL_PROVIDER", defaultProvider, "smtp", "sendgrid", "log")
+	if cfg.Sandbox.Enabled {
+		// una demo nunca envía correos reales
+		cfg.Email.Provider = "log"
+	}
+	cfg.Email.From = env.optional("EMAIL_FROM", "")
+	cfg.Email.SendGridAPIKey = env.optional("SENDGRID_API_KEY", "")
+	if cfg.Email.Provider != "log" {
+		if cfg.Email.From == "" {
+			env.problem("EMAIL_FROM is required when EMAIL_PROVIDER is %s", cfg.Email.Provider)
+		} else if _, err := mail.ParseAddress(cfg.Email.From); err != nil {
+			env.problem("EMAIL_FROM must be an email address, got %q", cfg.Email.From)
+		}
+	}
+	if cfg.Email.Provider == "smtp" && cfg.SMTP.Host == "" {
+		env.problem("SMTP_HOST is required when EMAIL_PROVIDER is smtp")
+	}
+	if cfg.Email.Provider == "sendgrid" && cfg.Email.SendGridAPIKey == "" {
+		env.problem("SENDGRID_API_KEY is required when EMAIL_PROVIDER is sendgrid")
+	}
+
+	cfg.Text.TwilioAccountSID = env.optional("TWILIO_ACCOUNT_SID", "")
+	cfg.Text.TwilioAuthToken = env.optional("TWILIO_AUTH_TOKEN", "")
+	cfg.Text.From = env.optional("TWILIO_FROM", "")
+	cfg.Text.WhatsAppFrom = env.optional("TWILIO_WHATSAPP_FROM", "")
+	defaultTextProvider := "log"
+	if cfg.Text.TwilioAccountSID != "" {
+		defaultTextProvider = "twilio"
+	}
+	cfg.Text.Provider = env.oneOf("TEXT_PROVIDER", defaultTextProvider, "twilio", "log")
+	if cfg.Sandbox.Enabled {
+		// una demo nunca envía mensajes reales
+		cfg.Text.Provider = "log"
+	}
+	if cfg.Text.Provider == "twilio" {
+		if cfg.Text.TwilioAccountSID == "" || cfg.Text.TwilioAuthToken == "" {
+			env.problem("TWILIO_ACCOUNT_SID and TWILIO_AUTH_TOKEN are required when TEXT_PROVIDER is twilio")
+		}
+		if cfg.Text.From == "" && cfg.Text.WhatsAppFrom == "" {
+			env.problem("TWILIO_FROM or TWILIO_WHATSAPP_FROM is required when TEXT_PROVIDER is twilio")
+		}
+	}
+	for _, number := range []struct{ name, value string }{{"TWILIO_FROM", cfg.Text.From}, {"TWILIO_WHATSAPP_FROM", cfg.Text.WhatsAppFrom}} {
+		if number.value != "" && !e164Pattern.MatchString(number.value) {
+			env.problem("%s must be a phone number in E.164 format (+573001234567), got %q", number.name, number.value)
+		}
+	}
+	cfg.Text.DefaultCountryCode = strings.TrimPrefix(env.optional("TEXT_DEFAULT_COUNTRY_CODE", cfg.Text.DefaultCountryCode), "+")
+	if !countryCodePattern.MatchString(cfg.Text.DefaultCountryCode) {
+		env.problem("TEXT_DEFAULT_COUNTRY_CODE must be 1 to 3 digits, got %q", cfg.Text.DefaultCountryCode)
+	}
+
+	cfg.Notifications.PublicURL = strings.TrimRight(env.optional("PUBLIC_BASE_URL", cfg.Notifications.PublicURL), "/")
+	if parsed, err := url.Parse(cfg.Notifications.PublicURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		env.problem("PUBLIC_BASE_URL must be an absolute http or https URL, got %q", cfg.Notifications.PublicURL)
+	}
+	cfg.Notifications.SigningKey = env.optional("NOTIFICATION_SIGNING_KEY", "")
+	if cfg.Notifications.SigningKey == "" && cfg.Email.Provider != "log" {
+		env.problem("NOTIFICATION_SIGNING_KEY is required when EMAIL_PROVIDER is %s", cfg.Email.Provider)
+	} else if cfg.Notifications.SigningKey != "" && len(cfg.Notifications.SigningKey) < 32 {
+		env.problem("NOTIFICATION_SIGNING_KEY must be at least 32 characters")
+	}
+	cfg.Notifications.AppointmentConfirmation = env.boolean("APPOINTMENT_CONFIRMATION_EMAIL", cfg.Notifications.AppointmentConfirmation)
+	cfg.Notifications.InvoiceReceipt = env.boolean("INVOICE_EMAIL", cfg.Notifications.InvoiceReceipt)
+	cfg.Notifications.PaymentReminderDays = env.dayOffsets("PAYMENT_REMINDER_DAYS", cfg.Notifications.PaymentReminderDays)
+	cfg.Notifications.AppointmentReminderHours = env.hoursBefore("APPOINTMENT_REMINDER_HOURS", cfg.Notifications.AppointmentReminderHours)
+
+	cfg.RateLimit.Requests = env.positiveInt("RATE_LIMIT_REQUESTS", cfg.RateLimit.Requests)
+	cfg.RateLimit.Window = env.duration("RATE_LIMIT_WINDOW", cfg.RateLimit.Window)
+	env.routeLimits("RATE_LIMIT_ROUTES", cfg.RateLimit.Routes)
+
+	cfg.CORS.AllowedOrigins = env.list("CORS_ALLOWED_ORIGINS", cfg.CORS.AllowedOrigins)
+	for _, origin := range cfg.CORS.AllowedOrigins {
+		if origin != "*" && !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+			env.problem("CORS_ALLOWED_ORIGINS must be \"*\" or origins starting with http:// or https://, got %q", origin)
+		}
+	}
+	cfg.CORS.AllowedMethods = env.list("CORS_ALLOWED_METHODS", cfg.CORS.AllowedMethods)
+	for i, method := range cfg.CORS.AllowedMethods {
+		cfg.CORS.AllowedMethods[i] = strings.ToUpper(method)
+	}
+	cfg.CORS.AllowedHeaders = env.list("CORS_ALLOWED_HEADERS", cfg.CORS.AllowedHeaders)
+	cfg.CORS.AllowCredentials = env.boolean("CORS_ALLOW_CREDENTIALS", cfg.CORS.AllowCredentials)
+	cfg.CORS.MaxAge = env.duration("CORS_MAX_AGE", cfg.CORS.MaxAge)
+	// los navegadores rechazan las respuestas con credenciales y Access-Control-Allow-Origin: *
+	if cfg.CORS.AllowCredentials && slices.Contains(cfg.CORS.AllowedOrigins, "*") {
+		env.problem("CORS_ALLOWED_ORIGINS cannot be \"*\" when CORS_ALLOW_CREDENTIALS is true")
+	}
+
+	cfg.Compression.MinSize = env.positiveInt("COMPRESSION_MIN_SIZE", cfg.Compression.MinSize)
+	if contentTypes := os.Getenv("COMPRESSION_CONTENT_TYPES"); contentTypes != "" {
+		cfg.Compression.ContentTypes = nil
+		for _, contentType := range strings.Split(contentTypes, ",") {
+			if contentType = strings.ToLower(strings.TrimSpace(contentType)); contentType != "" {
+				cfg.Compression.ContentTypes = append(cfg.Compression.ContentTypes, contentType)
+			}
+		}
+	}
+
+	for _, flag := range strings.Split(os.Getenv("FEATURE_FLAGS"), ",") {
+		if flag = strings.TrimSpace(flag); flag != "" {
+			cfg.Features[flag] = true
+		}
+	}
+
+	cfg.Archive.InvoiceDays = env.positiveInt("ARCHIVE_INVOICES_AFTER_DAYS", cfg.Archive.InvoiceDays)
+	cfg.Archive.AppointmentDays = env.positiveInt("ARCHIVE_APPOINTMENTS_AFTER_DAYS", cfg.Archive.AppointmentDays)
+	cfg.Export.Dir = env.optional("EXPORT_DIR", cfg.Export.Dir)
+
+	cfg.Accounting.Provider = env.oneOf("ACCOUNTING_PROVIDER", "", "siigo", "log")
+	if cfg.Sandbox.Enabled && cfg.Accounting.Provider == "siigo" {
+		// una demo nunca crea documentos en la contabilidad real
+		cfg.Accounting.Provider = "log"
+	}
+	if cfg.Accounting.Provider == "siigo" {
+		siigo := &cfg.Accounting.Siigo
+		siigo.BaseURL = strings.TrimRight(env.optional("SIIGO_BASE_URL", siigo.BaseURL), "/")
+		siigo.Username = env.required("SIIGO_USERNAME")
+		siigo.AccessKey = env.required("SIIGO_ACCESS_KEY")
+		siigo.PartnerID = env.required("SIIGO_PARTNER_ID")
+		siigo.InvoiceDocumentID = env.requiredPositiveInt("SIIGO_INVOICE_DOCUMENT_ID")
+		siigo.VoucherDocumentID = env.requiredPositiveInt("SIIGO_VOUCHER_DOCUMENT_ID")
+		siigo.SellerID = env.requiredPositiveInt("SIIGO_SELLER_ID")
+		siigo.PaymentMethodID = env.requiredPositiveInt("SIIGO_PAYMENT_METHOD_ID")
+	}
+
+	cfg.Geocoding.Provider = env.oneOf("GEOCODING_PROVIDER", "", "google", "nominatim")
+	if cfg.Geocoding.Provider != "" {
+		geocoding := &cfg.Geocoding
+		if geocoding.Provider == "google" {
+			geocoding.APIKey = env.required("GEOCODING_API_KEY")
+		}
+		geocoding.BaseURL = strings.TrimRight(env.optional("GEOCODING_BASE_URL", ""), "/")
+		geocoding.Country = strings.ToLower(env.optional("GEOCODING_COUNTRY", geocoding.Country))
+		geocoding.RejectUnknown = env.boolean("GEOCODING_REJECT_UNKNOWN", false)
+	}
+
+	cfg.Captcha.Provider = env.oneOf("CAPTCHA_PROVIDER", "", "recaptcha", "hcaptcha", "turnstile")
+	if cfg.Captcha.Provider != "" {
+		cfg.Captcha.Secret = env.required("CAPTCHA_SECRET")
+	}
+
+	cfg.Ecommerce.Provider = env.oneOf("ECOMMERCE_PROVIDER", "", "shopify", "woocommerce")
+	if cfg.Sandbox.Enabled {
+		// una demo nunca cambia la tienda real
+		cfg.Ecommerce.Provider = ""
+	}
+	if cfg.Ecommerce.Provider != "" {
+		ecommerce := &cfg.Ecommerce
+		ecommerce.StoreURL = strings.TrimRight(env.required("ECOMMERCE_STORE_URL"), "/")
+		if ecommerce.Provider == "shopify" {
+			ecommerce.AccessToken = env.required("ECOMMERCE_ACCESS_TOKEN")
+			ecommerce.LocationID = env.required("ECOMMERCE_LOCATION_ID")
+		} else {
+			ecommerce.ConsumerKey = env.required("ECOMMERCE_CONSUMER_KEY")
+			ecommerce.ConsumerSecret = env.required("ECOMMERCE_CONSUMER_SECRET")
+		}
+		ecommerce.WebhookSecret = env.required("ECOMMERCE_WEBHOOK_SECRET")
+		ecommerce.IdentifierTypeID = env.positiveInt("ECOMMERCE_IDENTIFIER_TYPE_ID", ecommerce.IdentifierTypeID)
+	}
+
+	cfg.Payments.Provider = env.oneOf("PAYMENT_GATEWAY", "", "stripe", "payu")
+	if cfg.Sandbox.Enabled {
+		// una demo no registra pagos reales
+		cfg.Payments.Provider = ""
+	}
+	switch cfg.Payments.Provider {
+	case "stripe":
+		cfg.Payments.WebhookSecret = env.required("PAYMENT_GATEWAY_WEBHOOK_SECRET")
+	case "payu":
+		cfg.Payments.APIKey = env.required("PAYU_API_KEY")
+		cfg.Payments.MerchantID = env.required("PAYU_MERCHANT_ID")
+	}
+
+	cfg.Storage.Provider = env.oneOf("STORAGE_PROVIDER", cfg.Storage.Provider, "local", "s3")
+	cfg.Storage.PublicURL = strings.TrimRight(env.optional("STORAGE_PUBLIC_URL", ""), "/")
+	if cfg.Sandbox.Enabled {
+		// una demo no sube archivos al bucket real
+		cfg.Storage.Provider = "local"
+		cfg.Storage.PublicURL = ""
+	}
+	cfg.Storage.Dir = env.optional("STORAGE_DIR", cfg.Storage.Dir)
+	if cfg.Storage.Provider == "s3" {
+		storage := &cfg.Storage
+		storage.Endpoint = strings.TrimRight(env.required("S3_ENDPOINT"), "/")
+		if parsed, err := url.Parse(storage.Endpoint); storage.Endpoint != "" && (err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "") {
+			env.problem("S3_ENDPOINT must be an absolute http or https URL, got %q", storage.Endpoint)
+		}
+		storage.Region = env.optional("S3_REGION", storage.Region)
+		storage.Bucket = env.required("S3_BUCKET")
+		storage.AccessKeyID = env.required("S3_ACCESS_KEY_ID")
+		storage.SecretAccessKey = env.required("S3_SECRET_ACCESS_KEY")
+	}
+	if cfg.Storage.Provider == "local" && cfg.Storage.PublicURL == "" {
+		cfg.Storage.PublicURL = cfg.Notifications.PublicURL + STORAGE_LOCAL_PATH
+	}
+	cfg.Storage.MaxImageBytes = env.positiveInt("ITEM_IMAGE_MAX_BYTES", cfg.Storage.MaxImageBytes)
+
+	cfg.Seed.AdminEmail = env.optional("SEED_ADMIN_EMAIL", "")
+	cfg.Seed.AdminPassword = env.optional("SEED_ADMIN_PASSWORD", "")
+
+	if len(env.problems) > 0 {
+		return nil, &ConfigError{Problems: env.problems}
+	}
+	current = cfg
+	return cfg, nil
+}
+
+// envReader lee variables de entorno acumulando los errores en lugar de detenerse en el primero.
+type envReader struct {
+	problems []string
+}
+
+func (r *envReader) problem(format string, args ...interface{}) {
+	r.problems = append(r.problems, fmt.Sprintf(format, args...))
+}
+
+func (r *envReader) required(name string) string {
+	value := os.Getenv(name)
+	if value == "" {
+		r.problem("%s is required", name)
+	}
+	return value
+}
+
+func (r *envReader) optional(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func (r *envReader) positiveInt(name string, fallback int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		r.problem("%s must be a positive integer, got %q", name, value)
+		return fallback
+	}
+	return n
+}
+
+func (r *envReader) requiredPositiveInt(name string) int {
+	if os.Getenv(name) == "" {
+		r.problem("%s is required", name)
+		return 0
+	}
+	return r.positiveInt(name, 0)
+}
+
+func (r *envReader) port(name string, fallback int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > 65535 {
+		r.problem("%s must be a port between 1 and 65535, got %q", name, value)
+		return fallback
+	}
+	return n
+}
+
+func (r *envReader) duration(name string, fallback time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		r.problem("%s must be a positive duration such as 30m, got %q", name, value)
+		return fallback
+	}
+	return d
+}
+
+func (r *envReader) boolean(name string, fallback bool) bool {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		r.problem("%s must be true or false, got %q", name, value)
+		return fallback
+	}
+	return b
+}
+
+// dayOffsets lee una lista de días separados por comas, como "-3,1,7", sin repetidos y ordenada.
+// "off" devuelve una lista vacía.
+func (r *envReader) dayOffsets(name string, fallback []int) []int {
+	value := strings.TrimSpace(os.Getenv(name))
+	if value == "" {
+		return fallback
+	}
+	if strings.EqualFold(value, "off") {
+		return []int{}
+	}
+
+	var days []int
+	for _, part := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < -365 || n > 365 {
+			r.problem("%s must be a comma separated list of days between -365 and 365, got %q", name, value)
+			return fallback
+		}
+		if !slices.Contains(days, n) {
+			days = append(days, n)
+		}
+	}
+	slices.Sort(days)
+	return days
+}
+
+// hoursBefore lee una lista de horas entre 1 y 720 separadas por comas y la devuelve ordenada.
+func (r *envReader) hoursBefore(name string, fallback []int) []int {
+	value := strings.TrimSpace(os.Getenv(name))
+	if value == "" {
+		return fallback
+	}
+	if strings.EqualFold(value, "off") {
+		return []int{}
+	}
+
+	var hours []int
+	for _, part := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 1 || n > 720 {
+			r.problem("%s must be a comma separated list of hours between 1 and 720, got %q", name, value)
+			return fallback
+		}
+		if !slices.Contains(hours, n) {
+			hours = append(hours, n)
+		}
+	}
+	slices.Sort(hours)
+	return hours
+}
+
+// list lee una lista separada por comas sin elementos vacíos; si no hay ninguno devuelve fallback.
+func (r *envReader) list(name string, fallback []string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(name), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return fallback
+	}
+	return values
+}
+
+// routeLimits lee una lista "METHOD /path=n" separada por comas y agrega cada límite a limits.
+func (r *envReader) routeLimits(name string, limits map[string]int) {
+	value := strings.TrimSpace(os.Getenv(name))
+	if value == "" {
+		return
+	}
+	for _, part := range strings.Split(value, ",") {
+		route, limit, found := strings.Cut(part, "=")
+		fields := strings.Fields(route)
+		n, err := strconv.Atoi(strings.TrimSpace(limit))
+		if !found || len(fields) != 2 || !strings.HasPrefix(fields[1], "/") || err != nil || n < 1 {
+			r.problem("%s must be a comma separated list of \"METHOD /path=requests\", got %q", name, part)
+			continue
+		}
+		limits[strings.ToUpper(fields[0])+" "+fields[1]] = n
+	}
+}
+
+func (r *envReader) oneOf(name, fallback string, allowed ...string) string {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	for _, option := range allowed {
+		if value == option {
+			return value
+		}
+	}
+	r.problem("%s must be one of %s, got %q", name, strings.Join(allowed, ", "), value)
+	return fallback
+}
@@ -0,0 +1,8 @@
+package config
+
+const (
+	// Currency of the accounting, the reports and the fixed-value discounts and taxes. Its exchange rate is always 1
+	BASE_CURRENCY = "COP"
+	// Decimals kept when converting a price into another currency
+	CURRENCY_DECIMALS = 2
+)
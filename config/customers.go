@@ -0,0 +1,11 @@
+package config
+
+const (
+	// Similitud mínima (0 a 1) entre nombres completos para tomar dos clientes con el mismo
+	// teléfono como el mismo
+	CUSTOMER_DUPLICATE_NAME_SIMILARITY = 0.8
+	// Los teléfonos se comparan por sus últimos dígitos, así "+57 300 123 4567" y "3001234567" coinciden
+	CUSTOMER_DUPLICATE_PHONE_DIGITS = 7
+	// Máximo de candidatos que se devuelven en el 409 al crear un cliente
+	CUSTOMER_DUPLICATE_MAX_CANDIDATES = 5
+)
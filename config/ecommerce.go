@@ -0,0 +1,18 @@
+package config
+
+import "time"
+
+const (
+	// How often items whose stock or price changed are pushed to the store
+	ECOMMERCE_SYNC_INTERVAL = time.Minute
+	// How often the store's product list is read again to pick up new or removed SKUs
+	ECOMMERCE_CATALOG_REFRESH = time.Hour
+	// Items pushed per round
+	ECOMMERCE_BATCH_SIZE = 50
+	// A claimed item is not pushed by another instance for this long
+	ECOMMERCE_PUSH_LEASE = 5 * time.Minute
+	// Delay before retrying a failed push; it doubles on every failure
+	ECOMMERCE_RETRY_BASE_DELAY = time.Minute
+	ECOMMERCE_RETRY_MAX_DELAY  = time.Hour
+	ECOMMERCE_REQUEST_TIMEOUT  = 30 * time.Second
+)
@@ -0,0 +1,45 @@
+package config
+
+import "time"
+
+const (
+	// An email is marked as failed after this many attempts
+	EMAIL_MAX_ATTEMPTS = 5
+	// Delay before the first retry; it doubles on every failed attempt
+	EMAIL_RETRY_BASE_DELAY = time.Minute
+	EMAIL_RETRY_MAX_DELAY  = 2 * time.Hour
+	// How often pending emails are checked
+	EMAIL_POLL_INTERVAL = 10 * time.Second
+	// Emails sent per polling round
+	EMAIL_BATCH_SIZE = 20
+	// A claimed email is not picked up by another instance for this long
+	EMAIL_SEND_LEASE = 5 * time.Minute
+)
+
+const (
+	// A payment reminder missed for this many days (server down, job failing) is skipped rather
+	// than sent late
+	PAYMENT_REMINDER_CATCH_UP_DAYS = 3
+)
+
+const (
+	// How long a password reset link is valid
+	PASSWORD_RESET_LINK_TTL = time.Hour
+	PASSWORD_MIN_LENGTH     = 8
+	// Password reset requests per client IP and window
+	PASSWORD_RESET_RATE_LIMIT  = 5
+	PASSWORD_RESET_RATE_WINDOW = 15 * time.Minute
+)
+
+const (
+	// A text message is marked as failed after this many attempts; retries are spaced like emails
+	TEXT_MAX_ATTEMPTS = 3
+	// How often pending text messages are checked
+	TEXT_POLL_INTERVAL = 10 * time.Second
+	// Text messages sent per polling round
+	TEXT_BATCH_SIZE = 20
+	// A claimed text message is not picked up by another instance for this long
+	TEXT_SEND_LEASE = 2 * time.Minute
+	// Timeout of each request to the text provider
+	TEXT_REQUEST_TIMEOUT = 15 * time.Second
+)
@@ -0,0 +1,14 @@
+package config
+
+import "time"
+
+const (
+	// Comentario enviado a cada cliente de /events para que proxies y balanceadores no corten la conexión
+	EVENT_STREAM_HEARTBEAT = 25 * time.Second
+	// Eventos que se guardan por cliente; si un cliente lento lo llena, los nuevos se descartan
+	EVENT_STREAM_BUFFER = 32
+	// Eventos que la bandeja de notificaciones puede tener pendientes de guardar
+	INBOX_EVENT_BUFFER = 1024
+	// Las notificaciones ya leídas se borran pasado este tiempo
+	INBOX_READ_RETENTION_DAYS = 90
+)
@@ -0,0 +1,33 @@
+package config
+
+import "time"
+
+const (
+	// Cada cuánto el worker de exportaciones busca pedidos pendientes
+	DATA_EXPORT_POLL_INTERVAL = 30 * time.Second
+	// Vigencia del enlace de descarga de una exportación
+	DATA_EXPORT_LINK_TTL = 24 * time.Hour
+	// Los archivos de exportación se borran pasado este tiempo
+	DATA_EXPORT_RETENTION_DAYS = 7
+)
+
+// DATA_EXPORT_TABLES son las tablas con datos del negocio que incluye una exportación completa,
+// en un orden en que se pueden volver a cargar sin violar llaves foráneas. Quedan fuera los logs,
+// la auditoría, los eventos de seguridad y las colas internas.
+var DATA_EXPORT_TABLES = []string{
+	"permissions", "roles", "role_permission", "user_types", "user_type_has_role", "user_state_types", "users",
+	"identifier_types", "employees", "customers", "notification_preferences",
+	"currencies", "suppliers", "item_types", "items", "item_images", "additional_expenses", "historical_item_prices", "restock_orders", "restock_order_items",
+	"discount_types", "tax_types", "tax_type_rates", "order_state_types", "price_lists", "price_list_items",
+	"appointments", "appointment_reminders", "comments",
+	"purchase_orders", "purchase_order_items", "purchase_order_discounts", "purchase_order_taxes",
+	"invoices", "invoice_items", "invoice_discounts", "invoice_taxes", "invoice_reminders", "payments",
+	"credit_notes", "credit_note_items", "invoice_returns", "invoice_return_items",
+	"external_sales", "stock_movements", "daily_closes", "daily_close_payments",
+	"archived_invoices", "archived_appointments",
+}
+
+// DATA_EXPORT_EXCLUDED_COLUMNS son columnas que nunca salen en una exportación.
+var DATA_EXPORT_EXCLUDED_COLUMNS = map[string][]string{
+	"users": {"password"},
+}
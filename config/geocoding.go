@@ -0,0 +1,11 @@
+package config
+
+import "time"
+
+const (
+	// Customers geocoded per run of the customer_geocoding job
+	GEOCODING_BACKFILL_BATCH_SIZE = 200
+	// Nominatim's usage policy allows at most one request per second
+	GEOCODING_NOMINATIM_INTERVAL = time.Second
+	GEOCODING_REQUEST_TIMEOUT    = 10 * time.Second
+)
@@ -0,0 +1,25 @@
+package config
+
+const (
+	// Default reorder level of new items: with stock at or below it an item is considered low on stock
+	LOW_STOCK_THRESHOLD = 5
+)
+
+// Reasons of the stock movements; ReferenceID points to the invoice, external sale, purchase order, restock order, credit note or return
+const (
+	// balance of each item when the ledger was introduced (migration 22)
+	STOCK_MOVEMENT_OPENING       = "opening"
+	STOCK_MOVEMENT_ITEM_CREATED  = "item_created"
+	STOCK_MOVEMENT_ADJUSTMENT    = "adjustment"
+	STOCK_MOVEMENT_INVOICE       = "invoice"
+	STOCK_MOVEMENT_EXTERNAL_SALE = "external_sale"
+	// stock reserved when a purchase order goes in transit, and returned when it is sent back
+	STOCK_MOVEMENT_PURCHASE_ORDER        = "purchase_order"
+	STOCK_MOVEMENT_PURCHASE_ORDER_RETURN = "purchase_order_return"
+	// units received from a supplier for a restock order
+	STOCK_MOVEMENT_RESTOCK_ORDER = "restock_order"
+	// units returned to stock when an invoice is cancelled with a credit note
+	STOCK_MOVEMENT_CREDIT_NOTE = "credit_note"
+	// units returned to stock by a partial return of an invoice
+	STOCK_MOVEMENT_INVOICE_RETURN = "invoice_return"
+)
@@ -0,0 +1,30 @@
+package config
+
+import "time"
+
+const (
+	// Maximum number of log entries waiting to be written
+	LOG_QUEUE_SIZE = 10000
+	// Log entries written per insert
+	LOG_BATCH_SIZE = 100
+	// Pending log entries are written at least this often
+	LOG_FLUSH_INTERVAL = time.Second
+	// Attempts to insert a batch of log entries before writing them to stderr; the delay
+	// between attempts doubles each time
+	LOG_WRITE_ATTEMPTS    = 4
+	LOG_WRITE_RETRY_DELAY = 500 * time.Millisecond
+)
+
+const (
+	// A failed log write is reported on stderr and the request goes on
+	LOG_FAILURE_POLICY_OPEN = "fail-open"
+	// A failed log write aborts the request with a 500
+	LOG_FAILURE_POLICY_CLOSED = "fail-closed"
+)
+
+const (
+	// Security events are kept at least this long; only older events can be purged
+	SECURITY_EVENT_RETENTION_DAYS = 730
+	// The user_log_retention job deletes history log entries older than this
+	USER_LOG_RETENTION_DAYS = 365
+)
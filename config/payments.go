@@ -0,0 +1,23 @@
+package config
+
+import "time"
+
+// Payment status of an invoice. Invoices without a due date are paid on issue; credit invoices start
+// unpaid and become partial and then paid as payments are recorded against them
+const (
+	INVOICE_PAYMENT_UNPAID  = "unpaid"
+	INVOICE_PAYMENT_PARTIAL = "partial"
+	INVOICE_PAYMENT_PAID    = "paid"
+)
+
+// Methods a payment can be recorded with; online is the method of the payments confirmed by the
+// payment gateway
+var PAYMENT_METHODS = []string{PAYMENT_METHOD_CASH, "card", "transfer", "check", "online", "other"}
+
+// Method the daily close counts as cash in the drawer
+const PAYMENT_METHOD_CASH = "cash"
+
+const (
+	// A Stripe webhook signed longer ago than this is rejected, so a captured request cannot be replayed
+	PAYMENT_WEBHOOK_TOLERANCE = 5 * time.Minute
+)
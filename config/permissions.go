@@ -26,7 +26,13 @@ const (
 	PERMISSION_USER_HAS_PERMISSION                     = 4008
 	PERMISSION_GET_USER_STATE_TYPE_BY_ID               = 5001
 	PERMISSION_GET_ALL_USER_STATE_TYPES                = 5002
+	PERMISSION_CREATE_USER_STATE_TYPE                  = 5003
+	PERMISSION_UPDATE_USER_STATE_TYPE                  = 5004
+	PERMISSION_DELETE_USER_STATE_TYPE                  = 5005
 	PERMISSION_GET_ALL_LOGS_FROM_USER                  = 6001
+	PERMISSION_SEARCH_LOGS                             = 6002
+	PERMISSION_SEARCH_SECURITY_EVENTS                  = 6003
+	PERMISSION_VERIFY_SECURITY_EVENTS                  = 6004
 	PERMISSION_GET_EMPLOYEE_BY_ID                      = 7001
 	PERMISSION_GET_ALL_EMPLOYEES                       = 7002
 	PERMISSION_SEARCH_EMPLOYEES_BY_NAME                = 7003
@@ -35,14 +41,18 @@ const (
 	PERMISSION_SEARCH_EMPLOYEES_BY_ID                  = 7006
 	PERMISSION_GET_ITEM_TYPES_BY_ID                    = 8001
 	PERMISSION_GET_ITEM_TYPES                          = 8002
+	PERMISSION_CREATE_ITEM_TYPE                        = 8003
+	PERMISSION_UPDATE_ITEM_TYPE                        = 8004
+	PERMISSION_DELETE_ITEM_TYPE                        = 8005
 	PERMISSION_GET_ITEM_BY_ID                          = 9001
 	PERMISSION_GET_ALL_ITEMS                           = 9002
-	PERMISSION_SEARCH_ITEMS_BY_ID                      = 9003
-	PERMISSION_SEARCH_ITEMS_BY_NAME                    = 9004
+	PERMISSION_SEARCH_ITEMS                            = 9004
 	PERMISSION_UPDATE_ITEM_STATE                       = 9005
 	PERMISSION_UPDATE_ITEM                             = 9006
 	PERMISSION_CREATE_ITEM                             = 9007
 	PERMISSION_CHECK_ITEM_STOCK                        = 9008
+	PERMISSION_UPLOAD_ITEM_IMAGE                       = 9009
+	PERMISSION_DELETE_ITEM_IMAGE                       = 9010
 	PERMISSION_GET_ADDITIONAL_EXPENSE_BY_ID            = 10001
 	PERMISSION_GET_ALL_ADDITIONAL_EXPENSE              = 10002
 	PERMISSION_CREATE_ADDITIONAL_EXPENSE               = 10003
@@ -67,17 +77,24 @@ const (
 	PERMISSION_GET_APPOINTMENTS_BY_CUSTOMERID_AND_DATE = 13009
 	PERMISSION_DELETE_APPOINTMENT                      = 13010
 	PERMISSION_GET_APPOINTMENTS_BY_HOUR                = 13011
+	PERMISSION_LINK_APPOINTMENT_CUSTOMERS              = 13012
+	PERMISSION_GET_APPOINTMENT_REMINDERS               = 13013
+	PERMISSION_GET_EMPLOYEE_APPOINTMENTS               = 13014
 	PERMISSION_GET_ALL_CUSTOMERS                       = 14001
 	PERMISSION_GET_CUSTOMER_BY_ID                      = 14002
 	PERMISSION_CREATE_CUSTOMER                         = 14003
 	PERMISSION_UPDATE_CUSTOMER                         = 14004
 	PERMISSION_GET_CUSTOMER_BY_EMAIL                   = 14005
-	PERMISSION_SEARCH_CUSTOMERS_BY_ID                  = 14006
-	PERMISSION_SEARCH_CUSTOMERS_BY_NAME                = 14007
-	PERMISSION_SEARCH_CUSTOMERS_BY_LASTNAME            = 14008
+	PERMISSION_SEARCH_CUSTOMERS                        = 14007
 	PERMISSION_GET_CUSTOMER_BY_CUSTOMERID              = 14009
+	PERMISSION_DELETE_CUSTOMER                         = 14010
+	PERMISSION_RESTORE_CUSTOMER                        = 14011
+	PERMISSION_MERGE_CUSTOMERS                         = 14012
 	PERMISSION_GET_ALL_IDENTIFIER_TYPES                = 15001
 	PERMISSION_GET_IDENTIFIER_TYPE_BY_ID               = 15002
+	PERMISSION_CREATE_IDENTIFIER_TYPE                  = 15003
+	PERMISSION_UPDATE_IDENTIFIER_TYPE                  = 15004
+	PERMISSION_DELETE_IDENTIFIER_TYPE                  = 15005
 	PERMISSION_GET_ORDER_STATE_TYPE_BY_ID              = 16001
 	PERMISSION_GET_ALL_ORDER_STATE_TYPES               = 16002
 	PERMISSION_GET_PURCHASE_ORDER_BY_ID                = 17001
@@ -92,18 +109,112 @@ const (
 	PERMISSION_GET_DISCOUNT_TYPE_BY_ID                 = 18001
 	PERMISSION_GET_ALL_DISCOUNT_TYPES                  = 18002
 	PERMISSION_CREATE_DISCOUNT_TYPE                    = 18003
+	PERMISSION_IMPORT_DISCOUNT_TYPES                   = 18004
+	PERMISSION_UPDATE_DISCOUNT_TYPE                    = 18005
+	PERMISSION_DEACTIVATE_DISCOUNT_TYPE                = 18006
 	PERMISSION_GET_INVOICE_BY_ID                       = 19001
 	PERMISSION_GET_ALL_INVOICES                        = 19002
 	PERMISSION_SEARCH_INVOICE_BY_ID                    = 19003
 	PERMISSION_SEARCH_INVOICE_BY_CUSTOMER_PERSONAL_ID  = 19004
 	PERMISSION_CREATE_INVOICE                          = 19005
+	PERMISSION_UPDATE_INVOICE_PAYMENT                  = 19006
+	PERMISSION_GET_INVOICE_REMINDERS                   = 19007
 	PERMISSION_CALCULATE_SUBTOTAL                      = 20001
 	PERMISSION_CALCULATE_TOTAL                         = 20002
 	PERMISSION_GET_TAX_TYPE_BY_ID                      = 21001
 	PERMISSION_GET_ALL_TAX_TYPES                       = 21002
 	PERMISSION_CREATE_TAX_TYPE                         = 21003
+	PERMISSION_IMPORT_TAX_TYPES                        = 21004
+	PERMISSION_UPDATE_TAX_TYPE                         = 21005
+	PERMISSION_DEACTIVATE_TAX_TYPE                     = 21006
 	PERMISSION_GET_EXTERNAL_SALE_BY_ID                 = 22001
 	PERMISSION_GET_ALL_EXTERNAL_SALES                  = 22002
 	PERMISSION_CREATE_EXTERNAL_SALE                    = 22003
 	PERMISSION_VIEW_SALES_REPORT                       = 23001
+	PERMISSION_VIEW_SALES_SUMMARY_REPORT               = 23002
+	PERMISSION_VIEW_MARGIN_REPORT                      = 23003
+	PERMISSION_VIEW_DISCOUNT_USAGE_REPORT              = 23004
+	PERMISSION_VIEW_TOP_ITEMS_REPORT                   = 23005
+	PERMISSION_VIEW_REVENUE_BY_CUSTOMER_REPORT         = 23006
+	PERMISSION_VIEW_DAILY_CLOSE                        = 23005
+	PERMISSION_CLOSE_DAY                               = 23006
+	PERMISSION_VIEW_DASHBOARD                          = 24001
+	PERMISSION_VIEW_INVENTORY_TURNOVER_REPORT          = 25001
+	PERMISSION_VIEW_AUDIT_TRAIL                        = 26001
+	PERMISSION_GET_WEBHOOKS                            = 27001
+	PERMISSION_CREATE_WEBHOOK                          = 27002
+	PERMISSION_UPDATE_WEBHOOK                          = 27003
+	PERMISSION_DELETE_WEBHOOK                          = 27004
+	PERMISSION_VIEW_WEBHOOK_DELIVERIES                 = 27005
+	PERMISSION_SUBSCRIBE_EVENTS                        = 28001
+	PERMISSION_VIEW_MIGRATIONS                         = 29001
+	PERMISSION_VIEW_SCHEDULED_JOBS                     = 30001
+	PERMISSION_VIEW_CUSTOMER_NOTIFICATION_PREFERENCES  = 31001
+	PERMISSION_EDIT_CUSTOMER_NOTIFICATION_PREFERENCES  = 31002
+	PERMISSION_GET_EMAIL_TEMPLATES                     = 32001
+	PERMISSION_EDIT_EMAIL_TEMPLATE                     = 32002
+	PERMISSION_SEND_TEST_EMAIL                         = 32003
+	PERMISSION_VIEW_NOTIFICATION_DELIVERIES            = 33001
+	PERMISSION_RETRY_NOTIFICATION_DELIVERY             = 33002
+	PERMISSION_VIEW_ROUTES                             = 34001
+	PERMISSION_VIEW_ARCHIVE                            = 35001
+	PERMISSION_CREATE_DATA_EXPORT                      = 36001
+	PERMISSION_VIEW_DATA_EXPORTS                       = 36002
+	PERMISSION_RESET_SANDBOX                           = 37001
+	PERMISSION_VIEW_ACCOUNTING_SYNCS                   = 38001
+	PERMISSION_RESYNC_ACCOUNTING_DOCUMENT              = 38002
+	PERMISSION_VIEW_ECOMMERCE_ORDERS                   = 39001
+	PERMISSION_RETRY_ECOMMERCE_ORDER                   = 39002
+	PERMISSION_VIEW_ECOMMERCE_RECONCILIATION           = 39003
+	PERMISSION_VIEW_STOCK_MOVEMENTS                    = 40001
+	PERMISSION_VIEW_RESTOCK_ORDERS                     = 41001
+	PERMISSION_CREATE_RESTOCK_ORDER                    = 41002
+	PERMISSION_UPDATE_RESTOCK_ORDER                    = 41003
+	PERMISSION_SEND_RESTOCK_ORDER                      = 41004
+	PERMISSION_RECEIVE_RESTOCK_ORDER                   = 41005
+	PERMISSION_CANCEL_RESTOCK_ORDER                    = 41006
+	PERMISSION_GET_SUPPLIERS                           = 42001
+	PERMISSION_CREATE_SUPPLIER                         = 42002
+	PERMISSION_UPDATE_SUPPLIER                         = 42003
+	PERMISSION_DELETE_SUPPLIER                         = 42004
+	PERMISSION_GET_LOW_STOCK_ITEMS                     = 43001
+	PERMISSION_RECEIVE_LOW_STOCK_ALERTS                = 43002
+	PERMISSION_CANCEL_INVOICE                          = 44001
+	PERMISSION_GET_CREDIT_NOTES                        = 44002
+	PERMISSION_EXPORT_CUSTOMERS                        = 45001
+	PERMISSION_EXPORT_ITEMS                            = 45002
+	PERMISSION_EXPORT_INVOICES                         = 45003
+	PERMISSION_GET_AVAILABLE_APPOINTMENT_SLOTS         = 46001
+	PERMISSION_GET_BUSINESS_HOURS                      = 46002
+	PERMISSION_UPDATE_BUSINESS_HOURS                   = 46003
+	PERMISSION_CHANGE_APPOINTMENT_STATE                = 47001
+	PERMISSION_GET_APPOINTMENT_STATE_HISTORY           = 47002
+	PERMISSION_GET_APPOINTMENT_STATE_TYPES             = 47003
+	PERMISSION_CREATE_ROLE                             = 48001
+	PERMISSION_UPDATE_ROLE                             = 48002
+	PERMISSION_DELETE_ROLE                             = 48003
+	PERMISSION_UPDATE_ROLE_PERMISSIONS                 = 48004
+	PERMISSION_CREATE_USER_TYPE                        = 49001
+	PERMISSION_UPDATE_USER_TYPE                        = 49002
+	PERMISSION_DELETE_USER_TYPE                        = 49003
+	PERMISSION_UPDATE_USER_TYPE_ROLES                  = 49004
+	PERMISSION_VIEW_QUOTATIONS                         = 50001
+	PERMISSION_CREATE_QUOTATION                        = 50002
+	PERMISSION_UPDATE_QUOTATION                        = 50003
+	PERMISSION_DELETE_QUOTATION                        = 50004
+	PERMISSION_DECIDE_QUOTATION                        = 50005
+	PERMISSION_CONVERT_QUOTATION                       = 50006
+	PERMISSION_VIEW_APPOINTMENT_REPORT                 = 51001
+	PERMISSION_VIEW_CURRENCIES                         = 52001
+	PERMISSION_CREATE_CURRENCY                         = 52002
+	PERMISSION_UPDATE_CURRENCY                         = 52003
+	PERMISSION_RECORD_INVOICE_PAYMENT                  = 53001
+	PERMISSION_GET_INVOICE_PAYMENTS                    = 53002
+	PERMISSION_CREATE_INVOICE_RETURN                   = 54001
+	PERMISSION_GET_INVOICE_RETURNS                     = 54002
+	PERMISSION_VIEW_PRICE_LISTS                        = 55001
+	PERMISSION_CREATE_PRICE_LIST                       = 55002
+	PERMISSION_UPDATE_PRICE_LIST                       = 55003
+	PERMISSION_DELETE_PRICE_LIST                       = 55004
+	PERMISSION_ASSIGN_PRICE_LIST                       = 55005
 )
@@ -0,0 +1,10 @@
+package config
+
+import "time"
+
+const (
+	// How long a page of the public catalog is served from memory (and cached by browsers and CDNs)
+	PUBLIC_CATALOG_CACHE_TTL = time.Minute
+	// Pages kept in memory; past this, expired pages are dropped and then the whole cache if needed
+	PUBLIC_CATALOG_CACHE_MAX_ENTRIES = 1000
+)
@@ -0,0 +1,7 @@
+package config
+
+const (
+	// Rows returned by the ranking reports (top items, revenue by customer) when no limit is given, and the most allowed
+	REPORT_RANKING_DEFAULT_LIMIT = 10
+	REPORT_RANKING_MAX_LIMIT     = 100
+)
@@ -0,0 +1,244 @@
+package config
+
+// ROUTE_PERMISSIONS indica, por "MÉTODO ruta" (con la sintaxis de gin), los permisos que revisa el
+// handler de cada ruta. Las rutas que no aparecen no exigen permiso. Los lotes (/items/batch,
+// /customers/batch) revisan, de los listados, el de cada tipo de operación que incluyen.
+// Al agregar una ruta o cambiar el permiso de un handler hay que actualizar esta tabla.
+var ROUTE_PERMISSIONS = map[string][]int{
+	"GET /item-types":                                        {PERMISSION_GET_ITEM_TYPES},
+	"GET /item-types/:id":                                    {PERMISSION_GET_ITEM_BY_ID},
+	"POST /item-types":                                       {PERMISSION_CREATE_ITEM_TYPE},
+	"PUT /item-types/:id":                                    {PERMISSION_UPDATE_ITEM_TYPE},
+	"DELETE /item-types/:id":                                 {PERMISSION_DELETE_ITEM_TYPE},
+	"GET /items/search":                                      {PERMISSION_SEARCH_ITEMS},
+	"PATCH /items/:id/state":                                 {PERMISSION_UPDATE_ITEM_STATE},
+	"PUT /items/:id":                                         {PERMISSION_UPDATE_ITEM},
+	"PATCH /items/:id":                                       {PERMISSION_UPDATE_ITEM},
+	"POST /items":                                            {PERMISSION_CREATE_ITEM},
+	"POST /items/batch":                                      {PERMISSION_CREATE_ITEM, PERMISSION_UPDATE_ITEM, PERMISSION_UPDATE_ITEM_STATE},
+	"GET /items/:id/stock":                                   {PERMISSION_CHECK_ITEM_STOCK},
+	"POST /items/:id/images":                                 {PERMISSION_UPLOAD_ITEM_IMAGE},
+	"DELETE /items/:id/images/:imageId":                      {PERMISSION_DELETE_ITEM_IMAGE},
+	"GET /permissions":                                       {PERMISSION_GET_ALL_PERMISSIONS},
+	"GET /permissions/:id":                                   {PERMISSION_GET_PERMISSION_BY_ID},
+	"GET /permissions/searchByID":                            {PERMISSION_SEARCH_PERMISSION_BY_ID},
+	"GET /permissions/searchByName":                          {PERMISSION_SEARCH_PERMISSION_BY_NAME},
+	"GET /roles/:id":                                         {PERMISSION_GET_ROLE_BY_ID},
+	"GET /roles/:id/permission":                              {PERMISSION_GET_ALL_PERMISSIONS_OF_ROLE},
+	"GET /roles/:id/exist":                                   {PERMISSION_EXIST_ROLE},
+	"GET /roles":                                             {PERMISSION_GET_ALL_ROLES},
+	"GET /roles/searchByID":                                  {PERMISSION_SEARCH_ROLE_BY_ID},
+	"GET /roles/searchByName":                                {PERMISSION_SEARCH_ROLE_BY_NAME},
+	"GET /user-types":                                        {PERMISSION_GET_ALL_USER_TYPES},
+	"GET /user-types/:id":                                    {PERMISSION_GET_USER_TYPE_BY_ID},
+	"GET /user-types/:id/exists":                             {PERMISSION_EXIST_USER_TYPE},
+	"GET /user-types/searchByID":                             {PERMISSION_SEARCH_USER_TYPES_BY_ID},
+	"GET /user-types/searchByName":                           {PERMISSION_SEARCH_USER_TYPES_BY_NAME},
+	"GET /user-state-types":                                  {PERMISSION_GET_ALL_USER_STATE_TYPES},
+	"GET /user-state-types/:id":                              {PERMISSION_GET_USER_STATE_TYPE_BY_ID},
+	"POST /user-state-types":                                 {PERMISSION_CREATE_USER_STATE_TYPE},
+	"PUT /user-state-types/:id":                              {PERMISSION_UPDATE_USER_STATE_TYPE},
+	"DELETE /user-state-types/:id":                           {PERMISSION_DELETE_USER_STATE_TYPE},
+	"GET /identifier-types":                                  {PERMISSION_GET_ALL_IDENTIFIER_TYPES},
+	"GET /identifier-types/:id":                              {PERMISSION_GET_IDENTIFIER_TYPE_BY_ID},
+	"POST /identifier-types":                                 {PERMISSION_CREATE_IDENTIFIER_TYPE},
+	"PUT /identifier-types/:id":                              {PERMISSION_UPDATE_IDENTIFIER_TYPE},
+	"DELETE /identifier-types/:id":                           {PERMISSION_DELETE_IDENTIFIER_TYPE},
+	"GET /users":                                             {PERMISSION_GET_ALL_USERS},
+	"GET /users/:id":                                         {PERMISSION_GET_USER_BY_ID},
+	"GET /users/searchByID":                                  {PERMISSION_SEARCH_USER_BY_ID},
+	"GET /users/searchByEmail":                               {PERMISSION_SEARCH_USERS_BY_EMAIL},
+	"PATCH /users/:id/state":                                 {PERMISSION_UPDATE_USER_STATE},
+	"PUT /users/:id":                                         {PERMISSION_UPDATE_USER},
+	"PATCH /users/:id":                                       {PERMISSION_UPDATE_USER},
+	"POST /users":                                            {PERMISSION_CREATE_USER},
+	"GET /employees/:id":                                     {PERMISSION_GET_EMPLOYEE_BY_ID},
+	"GET /employees":                                         {PERMISSION_GET_ALL_EMPLOYEES},
+	"GET /employees/searchByID":                              {PERMISSION_SEARCH_EMPLOYEES_BY_ID},
+	"GET /employees/searchByName":                            {PERMISSION_SEARCH_EMPLOYEES_BY_NAME},
+	"POST /employees":                                        {PERMISSION_CREATE_EMPLOYEE},
+	"PUT /employees/:id":                                     {PERMISSION_UPDATE_EMPLOYEE},
+	"PATCH /employees/:id":                                   {PERMISSION_UPDATE_EMPLOYEE},
+	"GET /additional-expenses":                               {PERMISSION_GET_ALL_ADDITIONAL_EXPENSE},
+	"GET /additional-expenses/:id":                           {PERMISSION_GET_ADDITIONAL_EXPENSE_BY_ID},
+	"POST /additional-expenses":                              {PERMISSION_CREATE_ADDITIONAL_EXPENSE},
+	"PUT /additional-expenses/:id":                           {PERMISSION_UPDATE_ADDITIONAL_EXPENSE},
+	"DELETE /additional-expenses/:id":                        {PERMISSION_DELETE_ADDITIONAL_EXPENSE},
+	"GET /historical-item-prices/:id":                        {PERMISSION_GET_HISTORICAL_ITEM_PRICE},
+	"GET /comments/:id":                                      {PERMISSION_GET_COMMENT_BY_ID},
+	"GET /comments":                                          {PERMISSION_GET_ALL_COMMENTS},
+	"GET /comments/searchByID":                               {PERMISSION_SEARCH_COMMENTS_BY_ID},
+	"GET /comments/searchByName":                             {PERMISSION_SEARCH_COMMENTS_BY_NAME},
+	"GET /comments/searchByEmail":                            {PERMISSION_SEARCH_COMMENTS_BY_EMAIL},
+	"POST /comments":                                         {PERMISSION_CREATE_COMMENT},
+	"PUT /comments/:id":                                      {PERMISSION_UPDATE_COMMENT},
+	"GET /appointments/:id":                                  {PERMISSION_GET_APPOINTMENT_BY_ID},
+	"GET /appointments":                                      {PERMISSION_GET_ALL_APPOINTMENTS},
+	"GET /appointments/searchByID":                           {PERMISSION_SEARCH_APPOINTMENTS_BY_ID},
+	"GET /appointments/searchByCustomerID":                   {PERMISSION_GET_APPOINTMENT_BY_CUSTOMER_ID},
+	"GET /appointments/searchByState":                        {PERMISSION_SEARCH_APPOINTMENT_BY_STATE},
+	"GET /appointments/customer/:customerID":                 {PERMISSION_GET_APPOINTMENT_BY_CUSTOMER_ID},
+	"POST /appointments":                                     {PERMISSION_CREATE_APPOINTMENT},
+	"PUT /appointments/:id":                                  {PERMISSION_UPDATE_APPOINTMENT},
+	"GET /appointments/byCustomerAndDate":                    {PERMISSION_GET_APPOINTMENTS_BY_CUSTOMERID_AND_DATE},
+	"DELETE /appointments/deleteAppointment/:id":             {PERMISSION_DELETE_APPOINTMENT},
+	"GET /appointments/hourly-count":                         {PERMISSION_GET_APPOINTMENTS_BY_HOUR},
+	"POST /appointments/link-customers":                      {PERMISSION_LINK_APPOINTMENT_CUSTOMERS},
+	"GET /appointments/:id/reminders":                        {PERMISSION_GET_APPOINTMENT_REMINDERS},
+	"GET /employees/:id/appointments":                        {PERMISSION_GET_EMPLOYEE_APPOINTMENTS},
+	"GET /customers/:id":                                     {PERMISSION_GET_CUSTOMER_BY_ID},
+	"GET /customers/customerID/:customerID":                  {PERMISSION_GET_CUSTOMER_BY_CUSTOMERID},
+	"GET /customers":                                         {PERMISSION_GET_ALL_CUSTOMERS},
+	"GET /customers/email/:email":                            {PERMISSION_GET_CUSTOMER_BY_EMAIL},
+	"GET /customers/search":                                  {PERMISSION_SEARCH_CUSTOMERS},
+	"POST /customers":                                        {PERMISSION_CREATE_CUSTOMER},
+	"PUT /customers/:id":                                     {PERMISSION_UPDATE_CUSTOMER},
+	"PATCH /customers/:id":                                   {PERMISSION_UPDATE_CUSTOMER},
+	"DELETE /customers/:id":                                  {PERMISSION_DELETE_CUSTOMER},
+	"POST /customers/:id/restore":                            {PERMISSION_RESTORE_CUSTOMER},
+	"GET /customers/:id/dependencies":                        {PERMISSION_GET_CUSTOMER_BY_ID},
+	"POST /customers/batch":                                  {PERMISSION_CREATE_CUSTOMER, PERMISSION_UPDATE_CUSTOMER},
+	"POST /customers/merge":                                  {PERMISSION_MERGE_CUSTOMERS},
+	"GET /order-state-types":                                 {PERMISSION_GET_ALL_ORDER_STATE_TYPES},
+	"GET /order-state-types/:id":                             {PERMISSION_GET_ORDER_STATE_TYPE_BY_ID},
+	"GET /purchase-orders":                                   {PERMISSION_GET_ALL_PURCHASE_ORDERS},
+	"GET /purchase-orders/searchByID":                        {PERMISSION_SEARCH_PURCHASE_ORDERS_BY_ID},
+	"GET /purchase-orders/customers/:customerID":             {PERMISSION_GET_PURCHASE_ORDERS_BY_CUSTOMER_ID},
+	"GET /purchase-orders/seller/:sellerID":                  {PERMISSION_GET_PURCHASE_ORDERS_BY_SELLER_ID},
+	"GET /purchase-orders/state/:stateID":                    {PERMISSION_GET_PURCHASE_ORDERS_BY_STATE_ID},
+	"POST /purchase-orders":                                  {PERMISSION_CREATE_PURCHASE_ORDER},
+	"PATCH /purchase-orders/:id/state":                       {PERMISSION_UPDATE_PURCHASE_ORDER_STATE},
+	"GET /discount-types":                                    {PERMISSION_GET_ALL_DISCOUNT_TYPES},
+	"GET /discount-types/:id":                                {PERMISSION_GET_DISCOUNT_TYPE_BY_ID},
+	"POST /discount-types":                                   {PERMISSION_CREATE_DISCOUNT_TYPE},
+	"POST /discount-types/import":                            {PERMISSION_IMPORT_DISCOUNT_TYPES},
+	"PUT /discount-types/:id":                                {PERMISSION_UPDATE_DISCOUNT_TYPE},
+	"PATCH /discount-types/:id/deactivate":                   {PERMISSION_DEACTIVATE_DISCOUNT_TYPE},
+	"GET /tax-types":                                         {PERMISSION_GET_ALL_TAX_TYPES},
+	"GET /tax-types/:id":                                     {PERMISSION_GET_TAX_TYPE_BY_ID},
+	"POST /tax-types":                                        {PERMISSION_CREATE_TAX_TYPE},
+	"POST /tax-types/import":                                 {PERMISSION_IMPORT_TAX_TYPES},
+	"PUT /tax-types/:id":                                     {PERMISSION_UPDATE_TAX_TYPE},
+	"PATCH /tax-types/:id/deactivate":                        {PERMISSION_DEACTIVATE_TAX_TYPE},
+	"GET /tax-types/:id/rates":                               {PERMISSION_GET_TAX_TYPE_BY_ID},
+	"POST /billing/subtotal":                                 {PERMISSION_CALCULATE_SUBTOTAL},
+	"POST /billing/total":                                    {PERMISSION_CALCULATE_TOTAL},
+	"GET /invoices/:id":                                      {PERMISSION_GET_INVOICE_BY_ID},
+	"GET /invoices":                                          {PERMISSION_GET_ALL_INVOICES},
+	"GET /invoices/searchById":                               {PERMISSION_SEARCH_INVOICE_BY_ID},
+	"GET /invoices/searchByPersonalId":                       {PERMISSION_SEARCH_INVOICE_BY_CUSTOMER_PERSONAL_ID},
+	"POST /invoices":                                         {PERMISSION_CREATE_INVOICE},
+	"PATCH /invoices/:id/payment":                            {PERMISSION_UPDATE_INVOICE_PAYMENT},
+	"GET /invoices/:id/reminders":                            {PERMISSION_GET_INVOICE_REMINDERS},
+	"POST /invoices/:id/payments":                            {PERMISSION_RECORD_INVOICE_PAYMENT},
+	"GET /invoices/:id/payments":                             {PERMISSION_GET_INVOICE_PAYMENTS},
+	"GET /external-sales/:id":                                {PERMISSION_GET_EXTERNAL_SALE_BY_ID},
+	"GET /external-sales":                                    {PERMISSION_GET_ALL_EXTERNAL_SALES},
+	"GET /sales-report/invoices":                             {PERMISSION_VIEW_SALES_REPORT},
+	"GET /reports/sales":                                     {PERMISSION_VIEW_SALES_SUMMARY_REPORT},
+	"GET /reports/margins":                                   {PERMISSION_VIEW_MARGIN_REPORT},
+	"GET /reports/discounts":                                 {PERMISSION_VIEW_DISCOUNT_USAGE_REPORT},
+	"GET /reports/top-items":                                 {PERMISSION_VIEW_TOP_ITEMS_REPORT},
+	"GET /reports/revenue-by-customer":                       {PERMISSION_VIEW_REVENUE_BY_CUSTOMER_REPORT},
+	"GET /dashboard":                                         {PERMISSION_VIEW_DASHBOARD},
+	"GET /reports/inventory-turnover":                        {PERMISSION_VIEW_INVENTORY_TURNOVER_REPORT},
+	"GET /reports/appointments":                              {PERMISSION_VIEW_APPOINTMENT_REPORT},
+	"GET /reports/daily-close":                               {PERMISSION_VIEW_DAILY_CLOSE},
+	"POST /reports/daily-close":                              {PERMISSION_CLOSE_DAY},
+	"GET /logs":                                              {PERMISSION_SEARCH_LOGS},
+	"GET /audit/:entity/:id":                                 {PERMISSION_VIEW_AUDIT_TRAIL},
+	"GET /security-events":                                   {PERMISSION_SEARCH_SECURITY_EVENTS},
+	"GET /security-events/verify":                            {PERMISSION_VERIFY_SECURITY_EVENTS},
+	"GET /webhooks":                                          {PERMISSION_GET_WEBHOOKS},
+	"GET /webhooks/:id":                                      {PERMISSION_GET_WEBHOOKS},
+	"POST /webhooks":                                         {PERMISSION_CREATE_WEBHOOK},
+	"PUT /webhooks/:id":                                      {PERMISSION_UPDATE_WEBHOOK},
+	"DELETE /webhooks/:id":                                   {PERMISSION_DELETE_WEBHOOK},
+	"GET /webhooks/:id/deliveries":                           {PERMISSION_VIEW_WEBHOOK_DELIVERIES},
+	"GET /events":                                            {PERMISSION_SUBSCRIBE_EVENTS},
+	"GET /migrations":                                        {PERMISSION_VIEW_MIGRATIONS},
+	"GET /scheduler/jobs":                                    {PERMISSION_VIEW_SCHEDULED_JOBS},
+	"GET /customers/:id/notification-preferences":            {PERMISSION_VIEW_CUSTOMER_NOTIFICATION_PREFERENCES},
+	"PUT /customers/:id/notification-preferences":            {PERMISSION_EDIT_CUSTOMER_NOTIFICATION_PREFERENCES},
+	"GET /email-templates":                                   {PERMISSION_GET_EMAIL_TEMPLATES},
+	"GET /email-templates/:name":                             {PERMISSION_GET_EMAIL_TEMPLATES},
+	"GET /email-templates/:name/versions":                    {PERMISSION_GET_EMAIL_TEMPLATES},
+	"POST /email-templates/:name/versions":                   {PERMISSION_EDIT_EMAIL_TEMPLATE},
+	"POST /email-templates/:name/versions/:version/activate": {PERMISSION_EDIT_EMAIL_TEMPLATE},
+	"POST /email-templates/:name/preview":                    {PERMISSION_GET_EMAIL_TEMPLATES},
+	"POST /email-templates/:name/test":                       {PERMISSION_SEND_TEST_EMAIL},
+	"GET /notifications/deliveries":                          {PERMISSION_VIEW_NOTIFICATION_DELIVERIES},
+	"POST /notifications/deliveries/:id/retry":               {PERMISSION_RETRY_NOTIFICATION_DELIVERY},
+	"GET /archive/invoices":                                  {PERMISSION_VIEW_ARCHIVE},
+	"GET /archive/invoices/:id":                              {PERMISSION_VIEW_ARCHIVE},
+	"GET /archive/appointments":                              {PERMISSION_VIEW_ARCHIVE},
+	"GET /archive/appointments/:id":                          {PERMISSION_VIEW_ARCHIVE},
+	"POST /exports":                                          {PERMISSION_CREATE_DATA_EXPORT},
+	"GET /exports":                                           {PERMISSION_VIEW_DATA_EXPORTS},
+	"GET /exports/:id":                                       {PERMISSION_VIEW_DATA_EXPORTS},
+	"POST /sandbox/reset":                                    {PERMISSION_RESET_SANDBOX},
+	"GET /accounting/syncs":                                  {PERMISSION_VIEW_ACCOUNTING_SYNCS},
+	"POST /accounting/syncs/:id/resync":                      {PERMISSION_RESYNC_ACCOUNTING_DOCUMENT},
+	"GET /ecommerce/orders":                                  {PERMISSION_VIEW_ECOMMERCE_ORDERS},
+	"POST /ecommerce/orders/:id/retry":                       {PERMISSION_RETRY_ECOMMERCE_ORDER},
+	"GET /ecommerce/reconciliation":                          {PERMISSION_VIEW_ECOMMERCE_RECONCILIATION},
+	"GET /stock-movements":                                   {PERMISSION_VIEW_STOCK_MOVEMENTS},
+	"GET /stock-movements/balance/:itemId":                   {PERMISSION_VIEW_STOCK_MOVEMENTS},
+	"GET /stock-movements/discrepancies":                     {PERMISSION_VIEW_STOCK_MOVEMENTS},
+	"GET /restock-orders":                                    {PERMISSION_VIEW_RESTOCK_ORDERS},
+	"GET /restock-orders/:id":                                {PERMISSION_VIEW_RESTOCK_ORDERS},
+	"POST /restock-orders":                                   {PERMISSION_CREATE_RESTOCK_ORDER},
+	"PUT /restock-orders/:id":                                {PERMISSION_UPDATE_RESTOCK_ORDER},
+	"POST /restock-orders/:id/send":                          {PERMISSION_SEND_RESTOCK_ORDER},
+	"POST /restock-orders/:id/receive":                       {PERMISSION_RECEIVE_RESTOCK_ORDER},
+	"POST /restock-orders/:id/cancel":                        {PERMISSION_CANCEL_RESTOCK_ORDER},
+	"GET /suppliers":                                         {PERMISSION_GET_SUPPLIERS},
+	"GET /suppliers/:id":                                     {PERMISSION_GET_SUPPLIERS},
+	"GET /suppliers/searchById":                              {PERMISSION_GET_SUPPLIERS},
+	"GET /suppliers/searchByName":                            {PERMISSION_GET_SUPPLIERS},
+	"POST /suppliers":                                        {PERMISSION_CREATE_SUPPLIER},
+	"PUT /suppliers/:id":                                     {PERMISSION_UPDATE_SUPPLIER},
+	"DELETE /suppliers/:id":                                  {PERMISSION_DELETE_SUPPLIER},
+	"GET /items/lowStock":                                    {PERMISSION_GET_LOW_STOCK_ITEMS},
+	"POST /invoices/:id/cancel":                              {PERMISSION_CANCEL_INVOICE},
+	"POST /invoices/:id/returns":                             {PERMISSION_CREATE_INVOICE_RETURN},
+	"GET /invoices/:id/returns":                              {PERMISSION_GET_INVOICE_RETURNS},
+	"GET /credit-notes":                                      {PERMISSION_GET_CREDIT_NOTES},
+	"GET /credit-notes/:id":                                  {PERMISSION_GET_CREDIT_NOTES},
+	"GET /customers/export":                                  {PERMISSION_EXPORT_CUSTOMERS},
+	"GET /items/export":                                      {PERMISSION_EXPORT_ITEMS},
+	"GET /invoices/export":                                   {PERMISSION_EXPORT_INVOICES},
+	"GET /appointments/availableSlots":                       {PERMISSION_GET_AVAILABLE_APPOINTMENT_SLOTS},
+	"GET /business-hours":                                    {PERMISSION_GET_BUSINESS_HOURS},
+	"PUT /business-hours":                                    {PERMISSION_UPDATE_BUSINESS_HOURS},
+	"PATCH /appointments/:id/state":                          {PERMISSION_CHANGE_APPOINTMENT_STATE},
+	"GET /appointments/:id/state-history":                    {PERMISSION_GET_APPOINTMENT_STATE_HISTORY},
+	"GET /appointment-state-types":                           {PERMISSION_GET_APPOINTMENT_STATE_TYPES},
+	"POST /roles":                                            {PERMISSION_CREATE_ROLE},
+	"PUT /roles/:id":                                         {PERMISSION_UPDATE_ROLE},
+	"DELETE /roles/:id":                                      {PERMISSION_DELETE_ROLE},
+	"PUT /roles/:id/permissions":                             {PERMISSION_UPDATE_ROLE_PERMISSIONS},
+	"POST /user-types":                                       {PERMISSION_CREATE_USER_TYPE},
+	"PUT /user-types/:id":                                    {PERMISSION_UPDATE_USER_TYPE},
+	"DELETE /user-types/:id":                                 {PERMISSION_DELETE_USER_TYPE},
+	"PUT /user-types/:id/roles":                              {PERMISSION_UPDATE_USER_TYPE_ROLES},
+	"GET /quotations":                                        {PERMISSION_VIEW_QUOTATIONS},
+	"GET /quotations/:id":                                    {PERMISSION_VIEW_QUOTATIONS},
+	"POST /quotations":                                       {PERMISSION_CREATE_QUOTATION},
+	"PUT /quotations/:id":                                    {PERMISSION_UPDATE_QUOTATION},
+	"DELETE /quotations/:id":                                 {PERMISSION_DELETE_QUOTATION},
+	"POST /quotations/:id/accept":                            {PERMISSION_DECIDE_QUOTATION},
+	"POST /quotations/:id/reject":                            {PERMISSION_DECIDE_QUOTATION},
+	"POST /quotations/:id/convert":                           {PERMISSION_CONVERT_QUOTATION},
+	"GET /currencies":                                        {PERMISSION_VIEW_CURRENCIES},
+	"GET /currencies/:code":                                  {PERMISSION_VIEW_CURRENCIES},
+	"POST /currencies":                                       {PERMISSION_CREATE_CURRENCY},
+	"PUT /currencies/:code":                                  {PERMISSION_UPDATE_CURRENCY},
+	"GET /price-lists":                                       {PERMISSION_VIEW_PRICE_LISTS},
+	"GET /price-lists/:id":                                   {PERMISSION_VIEW_PRICE_LISTS},
+	"POST /price-lists":                                      {PERMISSION_CREATE_PRICE_LIST},
+	"PUT /price-lists/:id":                                   {PERMISSION_UPDATE_PRICE_LIST},
+	"DELETE /price-lists/:id":                                {PERMISSION_DELETE_PRICE_LIST},
+	"PUT /customers/:id/price-list":                          {PERMISSION_ASSIGN_PRICE_LIST},
+	"GET /meta/routes":                                       {PERMISSION_VIEW_ROUTES},
+}
@@ -0,0 +1,10 @@
+package config
+
+import "time"
+
+const (
+	// How often each instance checks whether a job is due
+	SCHEDULER_POLL_INTERVAL = 30 * time.Second
+	// A job still running after this long is cancelled
+	SCHEDULER_JOB_TIMEOUT = 30 * time.Minute
+)
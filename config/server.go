@@ -0,0 +1,8 @@
+package config
+
+import "time"
+
+const (
+	// Each dependency checked by /health/ready must answer within this time
+	HEALTH_CHECK_TIMEOUT = 2 * time.Second
+)
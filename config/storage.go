@@ -0,0 +1,19 @@
+package config
+
+import "time"
+
+const (
+	// Ruta en que el servidor publica los archivos de STORAGE_DIR cuando STORAGE_PROVIDER es local
+	STORAGE_LOCAL_PATH      = "/uploads"
+	STORAGE_REQUEST_TIMEOUT = 30 * time.Second
+	ITEM_IMAGE_MAX_PER_ITEM = 10
+)
+
+// Tipos de imagen que se aceptan para los items, con la extensión con que se guardan. El tipo se
+// detecta del contenido del archivo, no del nombre ni del Content-Type que envía el cliente
+var ITEM_IMAGE_CONTENT_TYPES = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
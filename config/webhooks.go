@@ -0,0 +1,16 @@
+package config
+
+import "time"
+
+const (
+	// A delivery is marked as failed after this many attempts
+	WEBHOOK_MAX_ATTEMPTS = 8
+	// Delay before the first retry; it doubles on every failed attempt
+	WEBHOOK_RETRY_BASE_DELAY = 30 * time.Second
+	WEBHOOK_RETRY_MAX_DELAY  = 6 * time.Hour
+	// How often pending deliveries are checked
+	WEBHOOK_POLL_INTERVAL = 5 * time.Second
+	// Deliveries sent per polling round
+	WEBHOOK_BATCH_SIZE = 50
+	WEBHOOK_TIMEOUT    = 10 * time.Second
+)
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type AccountingController struct {
+	Service *services.AccountingService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewAccountingController(service *services.AccountingService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *AccountingController {
+	return &AccountingController{Service: service, Auth: auth, Log: log}
+}
+
+// GetAccountingSyncs godoc
+// @Summary      List accounting sync status
+// @Description  Returns a page of the documents sent (or waiting to be sent) to the accounting system, newest first, with their status (pending, synced or failed), attempts, last error and the ID the accounting system assigned.
+// @Tags         accounting
+// @Produce      json
+// @Param        status        query  string  false  "pending, synced or failed"
+// @Param        documentType  query  string  false  "invoice or payment"
+// @Param        documentId    query  int     false  "Invoice ID"
+// @Param        page          query  int     false  "Page number (default 1)"
+// @Param        pageSize      query  int     false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[models.AccountingSync]  "Page of sync records"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid filter"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving sync records"
+// @Security     ApiKeyAuth
+// @Router       /accounting/syncs [get]
+func (ac *AccountingController) GetAccountingSyncs(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_ACCOUNTING_SYNCS
+	if !ac.Auth.CheckPermission(c, permissionId) {
+		_ = ac.Log.RegisterLog(c, "Access denied for GetAccountingSyncs")
+		return
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Invalid pagination for GetAccountingSyncs: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	filter := dtos.AccountingSyncFilterDTO{
+		Status:        c.Query("status"),
+		DocumentType:  c.Query("documentType"),
+		PaginationDTO: pagination,
+	}
+	if documentStr := c.Query("documentId"); documentStr != "" {
+		documentID, err := strconv.Atoi(documentStr)
+		if err != nil {
+			_ = ac.Log.RegisterLog(c, "Invalid documentId: "+documentStr)
+			utilities.RespondError(c, http.StatusBadRequest, "Invalid 'documentId'")
+			return
+		}
+		filter.DocumentID = &documentID
+	}
+
+	syncs, total, err := ac.Service.GetSyncs(c.Request.Context(), filter)
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Error retrieving accounting syncs: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving sync records")
+		return
+	}
+
+	_ = ac.Log.RegisterLog(c, "Successfully retrieved accounting syncs")
+	c.JSON(http.StatusOK, dtos.NewPageDTO(syncs, pagination, total))
+}
+
+// ResyncAccountingDocument godoc
+// @Summary      Retry a failed accounting sync
+// @Description  Queues again a document that exhausted its automatic retries. Check in the accounting system that it was not created before retrying one that failed on a network error.
+// @Tags         accounting
+// @Produce      json
+// @Param        id   path      int  true  "Sync record ID"
+// @Success      202  {object}  models.AccountingSync  "Document queued again"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid sync record ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Sync record not found"
+// @Failure      409  {object}  dtos.ErrorResponse  "The document has not failed"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error queuing document"
+// @Security     ApiKeyAuth
+// @Router       /accounting/syncs/{id}/resync [post]
+func (ac *AccountingController) ResyncAccountingDocument(c *gin.Context) {
+	permissionId := config.PERMISSION_RESYNC_ACCOUNTING_DOCUMENT
+	if !ac.Auth.CheckPermission(c, permissionId) {
+		_ = ac.Log.RegisterLog(c, "Access denied for ResyncAccountingDocument")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Invalid accounting sync ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid sync record ID")
+		return
+	}
+
+	record, requeued, err := ac.Service.Resync(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = ac.Log.RegisterLog(c, "Accounting sync not found with ID: "+strconv.Itoa(id))
+			utilities.RespondError(c, http.StatusNotFound, "Sync record not found")
+			return
+		}
+		_ = ac.Log.RegisterLog(c, "Error requeuing accounting sync "+strconv.Itoa(id)+": "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error queuing document")
+		return
+	}
+	if !requeued {
+		_ = ac.Log.RegisterLog(c, "Accounting sync "+strconv.Itoa(id)+" not requeued: it has not failed")
+		utilities.RespondError(c, http.StatusConflict, "Only failed documents can be synced again")
+		return
+	}
+
+	_ = ac.Log.RegisterLog(c, "Accounting sync "+strconv.Itoa(id)+" queued again")
+	c.JSON(http.StatusAccepted, record)
+}
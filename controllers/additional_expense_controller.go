@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"totesbackend/config"
@@ -32,34 +33,29 @@ func NewAdditionalExpenseController(service *services.AdditionalExpenseService,
 // @Produce      json
 // @Param        id   path      string                   true  "ID of the additional expense"
 // @Success      200  {object}  models.AdditionalExpense "The additional expense record"
-// @Failure      401  {object}  models.ErrorResponse      "Unauthorized or permission denied"
-// @Failure      404  {object}  models.ErrorResponse      "Additional expense not found"
-// @Failure      500  {object}  models.ErrorResponse      "Internal server error (log registration or DB error)"
+// @Failure      401  {object}  dtos.ErrorResponse      "Unauthorized or permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse      "Additional expense not found"
+// @Failure      500  {object}  dtos.ErrorResponse      "Internal server error (log registration or DB error)"
 // @Router       /additional-expenses/{id} [get]
 func (aec *AdditionalExpenseController) GetAdditionalExpenseByID(c *gin.Context) {
 	idParam := c.Param("id")
 
-	if aec.Log.RegisterLog(c, "Attempting to retrieve AdditionalExpense with ID: "+idParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_GET_ADDITIONAL_EXPENSE_BY_ID
 	if !aec.Auth.CheckPermission(c, permissionId) {
 		_ = aec.Log.RegisterLog(c, "Access denied for GetAdditionalExpenseByID")
 		return
 	}
 
-	additionalExpense, err := aec.Service.GetAdditionalExpenseByID(idParam)
+	additionalExpense, err := aec.Service.GetAdditionalExpenseByID(c.Request.Context(), idParam)
 	if err != nil {
 		_ = aec.Log.RegisterLog(c, "Error retrieving AdditionalExpense with ID "+idParam+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving Additional Expense"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving Additional Expense")
 		return
 	}
 
 	if additionalExpense == nil {
 		_ = aec.Log.RegisterLog(c, "AdditionalExpense with ID "+idParam+" not found")
-		c.JSON(http.StatusNotFound, gin.H{"error": "Additional Expense not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Additional Expense not found")
 		return
 	}
 
@@ -74,34 +70,38 @@ func (aec *AdditionalExpenseController) GetAdditionalExpenseByID(c *gin.Context)
 // @Tags         additional-expenses
 // @Accept       json
 // @Produce      json
-// @Success      200  {array}   models.AdditionalExpense   "A list of all additional expenses"
-// @Failure      401  {object}  models.ErrorResponse       "Unauthorized or permission denied"
-// @Failure      500  {object}  models.ErrorResponse       "Error retrieving additional expenses"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[models.AdditionalExpense]   "A list of all additional expenses"
+// @Failure      401  {object}  dtos.ErrorResponse       "Unauthorized or permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse       "Error retrieving additional expenses"
 // @Security     ApiKeyAuth
 // @Router       /additional-expenses [get]
 func (aec *AdditionalExpenseController) GetAllAdditionalExpenses(c *gin.Context) {
-	if aec.Log.RegisterLog(c, "Attempting to retrieve all AdditionalExpenses") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_GET_ALL_ADDITIONAL_EXPENSE
 	if !aec.Auth.CheckPermission(c, permissionId) {
 		_ = aec.Log.RegisterLog(c, "Access denied for GetAllAdditionalExpenses")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
+		utilities.RespondError(c, http.StatusForbidden, "Permission denied")
 		return
 	}
 
-	additionalExpenses, err := aec.Service.GetAllAdditionalExpenses()
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = aec.Log.RegisterLog(c, "Invalid pagination for GetAllAdditionalExpenses: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	additionalExpenses, total, err := aec.Service.GetAllAdditionalExpenses(c.Request.Context(), pagination)
 	if err != nil {
 		_ = aec.Log.RegisterLog(c, "Error retrieving all AdditionalExpenses: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving additional expenses"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving additional expenses")
 		return
 	}
 
 	_ = aec.Log.RegisterLog(c, "Successfully retrieved all AdditionalExpenses")
 
-	c.JSON(http.StatusOK, additionalExpenses)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(additionalExpenses, pagination, total))
 }
 
 // CreateAdditionalExpense godoc
@@ -112,28 +112,23 @@ func (aec *AdditionalExpenseController) GetAllAdditionalExpenses(c *gin.Context)
 // @Produce      json
 // @Param        expense  body      dtos.UpdateAdditionalExpenseDTO  true  "Additional Expense DTO"
 // @Success      201      {object}  models.AdditionalExpense         "The created additional expense"
-// @Failure      400      {object}  models.ErrorResponse             "Invalid JSON format"
-// @Failure      401      {object}  models.ErrorResponse             "Unauthorized or permission denied"
-// @Failure      500      {object}  models.ErrorResponse             "Error creating additional expense"
+// @Failure      400      {object}  dtos.ErrorResponse             "Invalid JSON format"
+// @Failure      401      {object}  dtos.ErrorResponse             "Unauthorized or permission denied"
+// @Failure      500      {object}  dtos.ErrorResponse             "Error creating additional expense"
 // @Security     ApiKeyAuth
 // @Router       /additional-expenses [post]
 func (aec *AdditionalExpenseController) CreateAdditionalExpense(c *gin.Context) {
-	if aec.Log.RegisterLog(c, "Attempting to create a new AdditionalExpense") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_CREATE_ADDITIONAL_EXPENSE
 	if !aec.Auth.CheckPermission(c, permissionId) {
 		_ = aec.Log.RegisterLog(c, "Access denied for CreateAdditionalExpense")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
+		utilities.RespondError(c, http.StatusForbidden, "Permission denied")
 		return
 	}
 
 	var dto dtos.UpdateAdditionalExpenseDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = aec.Log.RegisterLog(c, "Invalid JSON format for CreateAdditionalExpense: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		utilities.RespondValidationError(c, "Invalid JSON format", err)
 		return
 	}
 
@@ -142,12 +137,17 @@ func (aec *AdditionalExpenseController) CreateAdditionalExpense(c *gin.Context)
 		ItemID:      dto.ItemID,
 		Expense:     dto.Expense,
 		Description: dto.Description,
+		SupplierID:  dto.SupplierID,
 	}
 
-	createdExpense, err := aec.Service.CreateAdditionalExpense(newExpense)
+	createdExpense, err := aec.Service.CreateAdditionalExpense(c.Request.Context(), newExpense)
 	if err != nil {
 		_ = aec.Log.RegisterLog(c, "Error creating AdditionalExpense: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating additional expense"})
+		if errors.Is(err, services.ErrSupplierNotFound) {
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error creating additional expense")
 		return
 	}
 
@@ -164,36 +164,31 @@ func (aec *AdditionalExpenseController) CreateAdditionalExpense(c *gin.Context)
 // @Produce      json
 // @Param        id    path      string                  true  "Additional Expense ID"
 // @Success      200   {object}  models.MessageResponse       "Message indicating successful deletion"
-// @Failure      400   {object}  models.ErrorResponse    "Invalid ID format or request"
-// @Failure      401   {object}  models.ErrorResponse    "Unauthorized or permission denied"
-// @Failure      404   {object}  models.ErrorResponse    "Additional expense not found"
-// @Failure      500   {object}  models.ErrorResponse    "Error deleting additional expense"
+// @Failure      400   {object}  dtos.ErrorResponse    "Invalid ID format or request"
+// @Failure      401   {object}  dtos.ErrorResponse    "Unauthorized or permission denied"
+// @Failure      404   {object}  dtos.ErrorResponse    "Additional expense not found"
+// @Failure      500   {object}  dtos.ErrorResponse    "Error deleting additional expense"
 // @Security     ApiKeyAuth
 // @Router       /additional-expenses/{id} [delete]
 func (aec *AdditionalExpenseController) DeleteAdditionalExpense(c *gin.Context) {
 	id := c.Param("id")
 
-	if aec.Log.RegisterLog(c, "Attempting to delete AdditionalExpense with ID: "+id) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_DELETE_ADDITIONAL_EXPENSE
 	if !aec.Auth.CheckPermission(c, permissionId) {
 		_ = aec.Log.RegisterLog(c, "Access denied for DeleteAdditionalExpense with ID: "+id)
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
+		utilities.RespondError(c, http.StatusForbidden, "Permission denied")
 		return
 	}
 
-	err := aec.Service.DeleteAdditionalExpense(id)
+	err := aec.Service.DeleteAdditionalExpense(c.Request.Context(), id)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			_ = aec.Log.RegisterLog(c, "AdditionalExpense with ID "+id+" not found")
-			c.JSON(http.StatusNotFound, gin.H{"error": "Additional Expense not found"})
+			utilities.RespondError(c, http.StatusNotFound, "Additional Expense not found")
 			return
 		}
 		_ = aec.Log.RegisterLog(c, "Error deleting AdditionalExpense with ID "+id+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting Additional Expense"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error deleting Additional Expense")
 		return
 	}
 
@@ -211,38 +206,33 @@ func (aec *AdditionalExpenseController) DeleteAdditionalExpense(c *gin.Context)
 // @Param        id    path      string                            true  "Additional Expense ID"
 // @Param        body  body      dtos.UpdateAdditionalExpenseDTO   true  "Updated Additional Expense details"
 // @Success      200   {object}  models.AdditionalExpense           "The updated additional expense"
-// @Failure      400   {object}  models.ErrorResponse               "Invalid request or JSON format"
-// @Failure      401   {object}  models.ErrorResponse               "Unauthorized or permission denied"
-// @Failure      404   {object}  models.ErrorResponse               "Additional expense not found"
-// @Failure      500   {object}  models.ErrorResponse               "Internal server error"
+// @Failure      400   {object}  dtos.ErrorResponse               "Invalid request or JSON format"
+// @Failure      401   {object}  dtos.ErrorResponse               "Unauthorized or permission denied"
+// @Failure      404   {object}  dtos.ErrorResponse               "Additional expense not found"
+// @Failure      500   {object}  dtos.ErrorResponse               "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /additional-expenses/{id} [put]
 func (aec *AdditionalExpenseController) UpdateAdditionalExpense(c *gin.Context) {
 	id := c.Param("id")
 
-	if aec.Log.RegisterLog(c, "Attempting to update AdditionalExpense with ID: "+id) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_UPDATE_ADDITIONAL_EXPENSE
 	if !aec.Auth.CheckPermission(c, permissionId) {
 		_ = aec.Log.RegisterLog(c, "Access denied for UpdateAdditionalExpense with ID: "+id)
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
+		utilities.RespondError(c, http.StatusForbidden, "Permission denied")
 		return
 	}
 
 	var dto dtos.UpdateAdditionalExpenseDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = aec.Log.RegisterLog(c, "Invalid JSON format for UpdateAdditionalExpense with ID: "+id)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		utilities.RespondValidationError(c, "Invalid JSON format", err)
 		return
 	}
 
-	expense, err := aec.Service.GetAdditionalExpenseByID(id)
+	expense, err := aec.Service.GetAdditionalExpenseByID(c.Request.Context(), id)
 	if err != nil {
 		_ = aec.Log.RegisterLog(c, "AdditionalExpense with ID "+id+" not found")
-		c.JSON(http.StatusNotFound, gin.H{"error": "AdditionalExpense not found"})
+		utilities.RespondError(c, http.StatusNotFound, "AdditionalExpense not found")
 		return
 	}
 
@@ -250,11 +240,16 @@ func (aec *AdditionalExpenseController) UpdateAdditionalExpense(c *gin.Context)
 	expense.ItemID = dto.ItemID
 	expense.Expense = dto.Expense
 	expense.Description = dto.Description
+	expense.SupplierID = dto.SupplierID
 
-	updatedExpense, err := aec.Service.UpdateAdditionalExpense(expense)
+	updatedExpense, err := aec.Service.UpdateAdditionalExpense(c.Request.Context(), expense)
 	if err != nil {
 		_ = aec.Log.RegisterLog(c, "Error updating AdditionalExpense with ID "+id+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating AdditionalExpense"})
+		if errors.Is(err, services.ErrSupplierNotFound) {
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error updating AdditionalExpense")
 		return
 	}
 
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/services"
 
@@ -16,9 +18,10 @@ import (
 )
 
 type AppointmentController struct {
-	Service *services.AppointmentService
-	Auth    *utilities.AuthorizationUtil
-	Log     *utilities.LogUtil
+	Service   *services.AppointmentService
+	Reminders *services.AppointmentReminderService
+	Auth      *utilities.AuthorizationUtil
+	Log       *utilities.LogUtil
 }
 
 func NewAppointmentController(service *services.AppointmentService, auth *utilities.AuthorizationUtil,
@@ -33,19 +36,14 @@ func NewAppointmentController(service *services.AppointmentService, auth *utilit
 // @Accept       json
 // @Produce      json
 // @Param        id   path      int  true  "Appointment ID"
-// @Success      200  {object}  models.Appointment           "The appointment object"
-// @Failure      400  {object}  models.ErrorResponse         "Invalid appointment ID"
-// @Failure      401  {object}  models.ErrorResponse         "Unauthorized or permission denied"
-// @Failure      404  {object}  models.ErrorResponse         "Appointment not found"
-// @Failure      500  {object}  models.ErrorResponse         "Internal server error"
+// @Success      200  {object}  dtos.ResourceDTO[models.Appointment]  "The appointment object with a link to its customer"
+// @Failure      400  {object}  dtos.ErrorResponse         "Invalid appointment ID"
+// @Failure      401  {object}  dtos.ErrorResponse         "Unauthorized or permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse         "Appointment not found"
+// @Failure      500  {object}  dtos.ErrorResponse         "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /appointments/{id} [get]
 func (ac *AppointmentController) GetAppointmentByID(c *gin.Context) {
-	if ac.Log.RegisterLog(c, "Attempting to get appointment by ID") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_GET_APPOINTMENT_BY_ID
 	if !ac.Auth.CheckPermission(c, permissionId) {
 		_ = ac.Log.RegisterLog(c, "Access denied for GetAppointmentByID")
@@ -55,19 +53,26 @@ func (ac *AppointmentController) GetAppointmentByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Invalid appointment ID: "+c.Param("id"))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid appointment ID"})
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid appointment ID")
 		return
 	}
 
-	appointment, err := ac.Service.GetAppointmentByID(id)
+	appointment, err := ac.Service.GetAppointmentByID(c.Request.Context(), id)
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Appointment not found for ID: "+strconv.Itoa(id))
-		c.JSON(http.StatusNotFound, gin.H{"error": "Appointment not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Appointment not found")
 		return
 	}
 
+	links := dtos.LinksDTO{
+		"self":     utilities.ResourceLink("appointments", appointment.ID),
+		"customer": utilities.ResourceLink("customers", appointment.CustomerID),
+	}
+	if appointment.EmployeeID != nil {
+		links["employee"] = utilities.ResourceLink("employees", *appointment.EmployeeID)
+	}
 	_ = ac.Log.RegisterLog(c, "Appointment retrieved successfully for ID: "+strconv.Itoa(id))
-	c.JSON(http.StatusOK, appointment)
+	c.JSON(http.StatusOK, dtos.NewResourceDTO(appointment, links))
 }
 
 // GetAllAppointments godoc
@@ -76,32 +81,36 @@ func (ac *AppointmentController) GetAppointmentByID(c *gin.Context) {
 // @Tags         appointments
 // @Accept       json
 // @Produce      json
-// @Success      200  {array}   models.Appointment       "List of all appointments"
-// @Failure      401  {object}  models.ErrorResponse     "Unauthorized or permission denied"
-// @Failure      500  {object}  models.ErrorResponse     "Error retrieving appointments or logging"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[models.Appointment]       "List of all appointments"
+// @Failure      401  {object}  dtos.ErrorResponse     "Unauthorized or permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse     "Error retrieving appointments or logging"
 // @Security     ApiKeyAuth
 // @Router       /appointments [get]
 func (ac *AppointmentController) GetAllAppointments(c *gin.Context) {
-	if ac.Log.RegisterLog(c, "Attempting to retrieve all appointments") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_GET_ALL_APPOINTMENTS
 	if !ac.Auth.CheckPermission(c, permissionId) {
 		_ = ac.Log.RegisterLog(c, "Access denied for GetAllAppointments")
 		return
 	}
 
-	appointments, err := ac.Service.GetAllAppointments()
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Invalid pagination for GetAllAppointments: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	appointments, total, err := ac.Service.GetAllAppointments(c.Request.Context(), pagination)
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Error retrieving appointments")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving appointments"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving appointments")
 		return
 	}
 
 	_ = ac.Log.RegisterLog(c, "All appointments retrieved successfully")
-	c.JSON(http.StatusOK, appointments)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(appointments, pagination, total))
 }
 
 // SearchAppointmentsByID godoc
@@ -111,19 +120,15 @@ func (ac *AppointmentController) GetAllAppointments(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        id     query     string  true  "Appointment ID to search"
-// @Success      200    {array}   models.Appointment
-// @Failure      401    {object} models.ErrorResponse  "Unauthorized or permission denied"
-// @Failure      404    {object}  models.ErrorResponse   "No appointments found"
-// @Failure      500    {object}  models.ErrorResponse  "Error retrieving appointments or logging"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200    {object}  dtos.PageDTO[models.Appointment]
+// @Failure      401    {object} dtos.ErrorResponse  "Unauthorized or permission denied"
+// @Failure      404    {object}  dtos.ErrorResponse   "No appointments found"
+// @Failure      500    {object}  dtos.ErrorResponse  "Error retrieving appointments or logging"
 // @Security     ApiKeyAuth
 // @Router       /appointments/searchByID [get]
 func (ac *AppointmentController) SearchAppointmentsByID(c *gin.Context) {
-
-	if ac.Log.RegisterLog(c, "Attempting to search appointments by ID") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_SEARCH_APPOINTMENTS_BY_ID
 	if !ac.Auth.CheckPermission(c, permissionId) {
 		_ = ac.Log.RegisterLog(c, "Access denied for SearchAppointmentsByID")
@@ -133,21 +138,28 @@ func (ac *AppointmentController) SearchAppointmentsByID(c *gin.Context) {
 	query := c.Query("id")
 	fmt.Println("Searching appointments by ID with:", query)
 
-	appointments, err := ac.Service.SearchAppointmentsByID(query)
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Invalid pagination for SearchAppointmentsByID: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	appointments, total, err := ac.Service.SearchAppointmentsByID(c.Request.Context(), query, pagination)
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Error retrieving appointments")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving appointments"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving appointments")
 		return
 	}
 
-	if len(appointments) == 0 {
+	if total == 0 {
 		_ = ac.Log.RegisterLog(c, "No appointments found for given ID")
-		c.JSON(http.StatusNotFound, gin.H{"message": "No appointments found"})
+		utilities.RespondError(c, http.StatusNotFound, "No appointments found")
 		return
 	}
 
 	_ = ac.Log.RegisterLog(c, "Appointments found by ID successfully")
-	c.JSON(http.StatusOK, appointments)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(appointments, pagination, total))
 }
 
 // SearchAppointmentsByCustomerID godoc
@@ -157,19 +169,15 @@ func (ac *AppointmentController) SearchAppointmentsByID(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        id     query     string  true  "Customer ID to search appointments"
-// @Success      200    {array}   models.Appointment   "List of appointments found"
-// @Failure      401    {object} models.ErrorResponse   "Unauthorized or permission denied"
-// @Failure      404    {object}  models.ErrorResponse   "No appointments found for the given customer ID"
-// @Failure      500    {object}  models.ErrorResponse  "Error retrieving appointments or logging"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200    {object}  dtos.PageDTO[models.Appointment]   "List of appointments found"
+// @Failure      401    {object} dtos.ErrorResponse   "Unauthorized or permission denied"
+// @Failure      404    {object}  dtos.ErrorResponse   "No appointments found for the given customer ID"
+// @Failure      500    {object}  dtos.ErrorResponse  "Error retrieving appointments or logging"
 // @Security     ApiKeyAuth
 // @Router       /appointments/searchByCustomerID [get]
 func (ac *AppointmentController) SearchAppointmentsByCustomerID(c *gin.Context) {
-
-	if ac.Log.RegisterLog(c, "Attempting to search appointments by customer ID") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_GET_APPOINTMENT_BY_CUSTOMER_ID
 	if !ac.Auth.CheckPermission(c, permissionId) {
 		_ = ac.Log.RegisterLog(c, "Access denied for SearchAppointmentsByCustomerID")
@@ -179,21 +187,28 @@ func (ac *AppointmentController) SearchAppointmentsByCustomerID(c *gin.Context)
 	query := c.Query("id")
 	fmt.Println("Searching appointments by Customer ID with:", query)
 
-	appointments, err := ac.Service.SearchAppointmentsByCustomerID(query)
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Invalid pagination for SearchAppointmentsByCustomerID: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	appointments, total, err := ac.Service.SearchAppointmentsByCustomerID(c.Request.Context(), query, pagination)
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Error retrieving appointments by customer ID")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving appointments"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving appointments")
 		return
 	}
 
-	if len(appointments) == 0 {
+	if total == 0 {
 		_ = ac.Log.RegisterLog(c, "No appointments found for given customer ID")
-		c.JSON(http.StatusNotFound, gin.H{"message": "No appointments found"})
+		utilities.RespondError(c, http.StatusNotFound, "No appointments found")
 		return
 	}
 
 	_ = ac.Log.RegisterLog(c, "Appointments found by customer ID successfully")
-	c.JSON(http.StatusOK, appointments)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(appointments, pagination, total))
 }
 
 // SearchAppointmentsByState godoc
@@ -202,42 +217,45 @@ func (ac *AppointmentController) SearchAppointmentsByCustomerID(c *gin.Context)
 // @Tags         appointments
 // @Accept       json
 // @Produce      json
-// @Param        state   query     bool    true  "State of the appointment (true for confirmed, false for pending)"
-// @Success      200     {array}   models.Appointment   "List of appointments found based on state"
-// @Failure      400     {object}  models.ErrorResponse   "Invalid state value provided"
-// @Failure      401     {object}  models.ErrorResponse   "Unauthorized or permission denied"
-// @Failure      500     {object}  models.ErrorResponse   "Error retrieving appointments or logging"
+// @Param        state   query     int    true  "Appointment state ID (see /appointment-state-types)"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200     {object}  dtos.PageDTO[models.Appointment]   "List of appointments found based on state"
+// @Failure      400     {object}  dtos.ErrorResponse   "Invalid state value provided"
+// @Failure      401     {object}  dtos.ErrorResponse   "Unauthorized or permission denied"
+// @Failure      500     {object}  dtos.ErrorResponse   "Error retrieving appointments or logging"
 // @Security     ApiKeyAuth
 // @Router       /appointments/searchByState [get]
 func (ac *AppointmentController) SearchAppointmentsByState(c *gin.Context) {
-
-	if ac.Log.RegisterLog(c, "Attempting to search appointments by state") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_SEARCH_APPOINTMENT_BY_STATE
 	if !ac.Auth.CheckPermission(c, permissionId) {
 		_ = ac.Log.RegisterLog(c, "Access denied for SearchAppointmentsByState")
 		return
 	}
 
-	state, err := strconv.ParseBool(c.Query("state"))
+	state, err := strconv.Atoi(c.Query("state"))
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Invalid state value provided for appointment search")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid state value"})
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid state value")
+		return
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Invalid pagination for SearchAppointmentsByState: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	appointments, err := ac.Service.SearchAppointmentsByState(state)
+	appointments, total, err := ac.Service.SearchAppointmentsByState(c.Request.Context(), state, pagination)
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Error retrieving appointments by state")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving appointments"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving appointments")
 		return
 	}
 
 	_ = ac.Log.RegisterLog(c, "Appointments retrieved successfully by state")
-	c.JSON(http.StatusOK, appointments)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(appointments, pagination, total))
 }
 
 // GetAppointmentsByCustomerID godoc
@@ -247,18 +265,14 @@ func (ac *AppointmentController) SearchAppointmentsByState(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        customerID  path      int                          true  "ID of the customer"
-// @Success      200         {array}   models.Appointment           "List of appointments"
-// @Failure      400         {object}  models.ErrorResponse       "Invalid customer ID"
-// @Failure      401         {object} models.ErrorResponse        "Unauthorized or permission denied"
-// @Failure      500         {object}  models.ErrorResponse       "Error retrieving appointments"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200         {object}  dtos.PageDTO[models.Appointment]           "List of appointments"
+// @Failure      400         {object}  dtos.ErrorResponse       "Invalid customer ID"
+// @Failure      401         {object} dtos.ErrorResponse        "Unauthorized or permission denied"
+// @Failure      500         {object}  dtos.ErrorResponse       "Error retrieving appointments"
 // @Router       /appointments/customer/{customerID} [get]
 func (ac *AppointmentController) GetAppointmentsByCustomerID(c *gin.Context) {
-
-	if ac.Log.RegisterLog(c, "Attempting to retrieve appointments by customer ID") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_GET_APPOINTMENT_BY_CUSTOMER_ID
 	if !ac.Auth.CheckPermission(c, permissionId) {
 		_ = ac.Log.RegisterLog(c, "Access denied for GetAppointmentsByCustomerID")
@@ -268,62 +282,68 @@ func (ac *AppointmentController) GetAppointmentsByCustomerID(c *gin.Context) {
 	customerID, err := strconv.Atoi(c.Param("customerID"))
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Invalid customer ID provided")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid customer ID"})
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid customer ID")
 		return
 	}
 
-	appointments, err := ac.Service.GetAppointmentsByCustomerID(customerID)
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Invalid pagination for GetAppointmentsByCustomerID: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	appointments, total, err := ac.Service.GetAppointmentsByCustomerID(c.Request.Context(), customerID, pagination)
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Error retrieving appointments by customer ID")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving appointments"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving appointments")
 		return
 	}
 
 	_ = ac.Log.RegisterLog(c, "Appointments retrieved successfully by customer ID")
-	c.JSON(http.StatusOK, appointments)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(appointments, pagination, total))
 }
 
 // CreateAppointment godoc
 // @Summary      Create a new appointment
-// @Description  Create a new appointment in the system. Requires permission to create appointments.
+// @Description  Create a new appointment in the system. Requires permission to create appointments. An appointment with employeeId only needs that employee to be free at that time; one without it takes one of the slot's shared places.
 // @Tags         appointments
 // @Accept       json
 // @Produce      json
 // @Param        appointment  body      models.Appointment  true  "Appointment data to create"
 // @Success      201          {object}  models.Appointment  "Appointment successfully created"
-// @Failure      400          {object}  models.ErrorResponse   "Invalid JSON format or appointment limit reached"
-// @Failure      403          {object}  models.ErrorResponse   "Forbidden, no permission to create appointments"
-// @Failure      500          {object}  models.ErrorResponse   "Error creating appointment or logging"
+// @Failure      400          {object}  dtos.ErrorResponse   "Invalid JSON format, unknown employee or appointment limit reached"
+// @Failure      409          {object}  dtos.ErrorResponse   "The employee already has an appointment at that time"
+// @Failure      403          {object}  dtos.ErrorResponse   "Forbidden, no permission to create appointments"
+// @Failure      500          {object}  dtos.ErrorResponse   "Error creating appointment or logging"
 // @Security     ApiKeyAuth
 // @Router       /appointments [post]
 func (ac *AppointmentController) CreateAppointment(c *gin.Context) {
-	if ac.Log.RegisterLog(c, "Attempting to create appointment") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_CREATE_APPOINTMENT
 	if !ac.Auth.CheckPermission(c, permissionId) {
 		_ = ac.Log.RegisterLog(c, "Access denied for CreateAppointment")
-		c.JSON(http.StatusForbidden, gin.H{"error": "No tienes permisos para crear citas"})
+		utilities.RespondError(c, http.StatusForbidden, "You do not have permission to create appointments")
 		return
 	}
 
 	var appointment models.Appointment
 	if err := c.ShouldBindJSON(&appointment); err != nil {
 		_ = ac.Log.RegisterLog(c, "Invalid JSON format when creating appointment")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Formato JSON inválido"})
+		utilities.RespondValidationError(c, "Invalid JSON format", err)
 		return
 	}
 
-	createdAppointment, err := ac.Service.CreateAppointment(appointment)
+	createdAppointment, err := ac.Service.CreateAppointment(c.Request.Context(), appointment)
 	if err != nil {
-		if err.Error() == "ya existen 3 citas agendadas para esta fecha y hora" {
+		if errors.Is(err, services.ErrAppointmentSlotFull) {
 			_ = ac.Log.RegisterLog(c, "limite de citas alcanzado :v")
-			c.JSON(http.StatusBadRequest, gin.H{"error": "no se puede crear la cita. Ya hay 3 citas agendadas para esta fecha y hora."})
+			utilities.RespondError(c, http.StatusBadRequest, "Cannot create appointment: there are already "+
+				strconv.Itoa(config.APPOINTMENT_SLOT_CAPACITY)+" appointments without employee scheduled for this date and time")
+		} else if ac.respondEmployeeError(c, err) {
+			_ = ac.Log.RegisterLog(c, "Employee not available for CreateAppointment: "+err.Error())
 		} else {
 			_ = ac.Log.RegisterLog(c, "Error creando cita")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear la cita"})
+			utilities.RespondError(c, http.StatusInternalServerError, "Error creating appointment")
 		}
 		return
 	}
@@ -341,19 +361,14 @@ func (ac *AppointmentController) CreateAppointment(c *gin.Context) {
 // @Param        id          path      int                 true  "Appointment ID to update"
 // @Param        appointment body      models.Appointment   true  "Appointment data to update"
 // @Success      200         {object}  models.Appointment   "Appointment successfully updated"
-// @Failure      400         {object}  models.ErrorResponse   "Invalid appointment ID or JSON format"
-// @Failure      403         {object} models.ErrorResponse   "Forbidden, no permission to update appointments"
-// @Failure      404         {object} models.ErrorResponse   "Appointment not found for update"
-// @Failure      500         {object}  models.ErrorResponse   "Error updating appointment or logging"
+// @Failure      400         {object}  dtos.ErrorResponse   "Invalid appointment ID or JSON format"
+// @Failure      403         {object} dtos.ErrorResponse   "Forbidden, no permission to update appointments"
+// @Failure      404         {object} dtos.ErrorResponse   "Appointment not found for update"
+// @Failure      500         {object}  dtos.ErrorResponse   "Error updating appointment or logging"
+// @Failure      409         {object}  dtos.ErrorResponse   "Version conflict, or the employee already has an appointment at that time"
 // @Security     ApiKeyAuth
 // @Router       /appointments/{id} [put]
 func (ac *AppointmentController) UpdateAppointment(c *gin.Context) {
-
-	if ac.Log.RegisterLog(c, "Attempting to update appointment") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_UPDATE_APPOINTMENT
 
 	if !ac.Auth.CheckPermission(c, permissionId) {
@@ -364,28 +379,43 @@ func (ac *AppointmentController) UpdateAppointment(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Invalid appointment ID format")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid appointment ID"})
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid appointment ID")
 		return
 	}
 
 	var appointment models.Appointment
 	if err := c.ShouldBindJSON(&appointment); err != nil {
 		_ = ac.Log.RegisterLog(c, "Invalid JSON format on update appointment")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		utilities.RespondValidationError(c, "Invalid JSON format", err)
+		return
+	}
+
+	if appointment.Version == 0 {
+		_ = ac.Log.RegisterLog(c, "Missing version on update appointment")
+		utilities.RespondVersionRequired(c)
 		return
 	}
 
 	appointment.ID = id
 
-	err = ac.Service.UpdateAppointment(&appointment)
+	err = ac.Service.UpdateAppointment(c.Request.Context(), &appointment)
 	if err != nil {
+		if errors.Is(err, services.ErrVersionConflict) {
+			_ = ac.Log.RegisterLog(c, "Version conflict on update appointment")
+			utilities.RespondVersionConflict(c, err.Error())
+			return
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			_ = ac.Log.RegisterLog(c, "Appointment not found for update")
-			c.JSON(http.StatusNotFound, gin.H{"error": "Appointment not found"})
+			utilities.RespondError(c, http.StatusNotFound, "Appointment not found")
+			return
+		}
+		if ac.respondEmployeeError(c, err) {
+			_ = ac.Log.RegisterLog(c, "Employee not available for UpdateAppointment: "+err.Error())
 			return
 		}
 		_ = ac.Log.RegisterLog(c, "Error updating appointment")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating appointment"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error updating appointment")
 		return
 	}
 
@@ -393,6 +423,85 @@ func (ac *AppointmentController) UpdateAppointment(c *gin.Context) {
 	c.JSON(http.StatusOK, appointment)
 }
 
+// respondEmployeeError responde los errores del empleado asignado a la cita; devuelve false si err
+// es otro.
+func (ac *AppointmentController) respondEmployeeError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrInvalidAppointmentEmployee):
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrEmployeeUnavailable):
+		utilities.RespondError(c, http.StatusConflict, "The employee already has an appointment at this date and time")
+	default:
+		return false
+	}
+	return true
+}
+
+// GetEmployeeAppointments godoc
+// @Summary      Employee calendar
+// @Description  Lists the appointments assigned to an employee in chronological order, optionally between from and to. Employees can always see their own calendar; anyone else needs the permission.
+// @Tags         appointments
+// @Produce      json
+// @Param        id        path   int     true   "Employee ID"
+// @Param        from      query  string  false  "From (YYYY-MM-DD or RFC3339)"
+// @Param        to        query  string  false  "To, inclusive (YYYY-MM-DD or RFC3339)"
+// @Param        page      query  int     false  "Page number (default 1)"
+// @Param        pageSize  query  int     false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[models.Appointment]  "The employee's appointments"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid employee ID, dates or pagination"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Employee not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving the appointments"
+// @Security     ApiKeyAuth
+// @Router       /employees/{id}/appointments [get]
+func (ac *AppointmentController) GetEmployeeAppointments(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Invalid employee ID for GetEmployeeAppointments")
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid employee ID")
+		return
+	}
+
+	employee, err := ac.Service.GetEmployee(c.Request.Context(), id)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		_ = ac.Log.RegisterLog(c, "Error retrieving employee for GetEmployeeAppointments: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving the employee")
+		return
+	}
+	// la agenda propia no necesita permiso; el 404 solo lo ve quien podría consultar cualquier agenda
+	own := employee != nil && utilities.CurrentUser(c) != "" && strings.EqualFold(employee.User.Email, utilities.CurrentUser(c))
+	if !own && !ac.Auth.CheckPermission(c, config.PERMISSION_GET_EMPLOYEE_APPOINTMENTS) {
+		_ = ac.Log.RegisterLog(c, "Access denied for GetEmployeeAppointments")
+		return
+	}
+	if employee == nil {
+		_ = ac.Log.RegisterLog(c, "Employee not found for GetEmployeeAppointments")
+		utilities.RespondError(c, http.StatusNotFound, "Employee not found")
+		return
+	}
+
+	filter, ok := parseCSVExportFilter(c, ac.Log)
+	if !ok {
+		return
+	}
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Invalid pagination for GetEmployeeAppointments: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	appointments, total, err := ac.Service.GetEmployeeAppointments(c.Request.Context(), id, filter.From, filter.To, pagination)
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Error retrieving appointments of employee "+strconv.Itoa(id)+": "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving appointments")
+		return
+	}
+
+	_ = ac.Log.RegisterLog(c, "Appointments retrieved for employee "+strconv.Itoa(id))
+	c.JSON(http.StatusOK, dtos.NewPageDTO(appointments, pagination, total))
+}
+
 // GetAppointmentByCustomerIDAndDate godoc
 // @Summary      Get appointment by customer ID and date
 // @Description  Retrieve an appointment based on the customer ID and appointment date. Requires permission to get appointments.
@@ -402,19 +511,13 @@ func (ac *AppointmentController) UpdateAppointment(c *gin.Context) {
 // @Param        customerId  query     int    true  "Customer ID"
 // @Param        dateTime    query     string true  "Appointment date and time (format: YYYY-MM-DD HH:MM:SS)"
 // @Success      200         {object}  models.Appointment  "Appointment successfully retrieved"
-// @Failure      400         {object}  models.ErrorResponse   "Invalid customer ID or date format"
-// @Failure      401         {object}  models.ErrorResponse   "Unauthorized or permission denied"
-// @Failure      404         {object} models.ErrorResponse   "Appointment not found for the given customer ID and date"
-// @Failure      500         {object}  models.ErrorResponse  "Error retrieving appointment or logging"
+// @Failure      400         {object}  dtos.ErrorResponse   "Invalid customer ID or date format"
+// @Failure      401         {object}  dtos.ErrorResponse   "Unauthorized or permission denied"
+// @Failure      404         {object} dtos.ErrorResponse   "Appointment not found for the given customer ID and date"
+// @Failure      500         {object}  dtos.ErrorResponse  "Error retrieving appointment or logging"
 // @Security     ApiKeyAuth
 // @Router       /appointments/byCustomerIdAndDate [get]
 func (ac *AppointmentController) GetAppointmentByCustomerIDAndDate(c *gin.Context) {
-
-	if ac.Log.RegisterLog(c, "Attempting to get appointment by customer ID and date") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_GET_APPOINTMENTS_BY_CUSTOMERID_AND_DATE
 
 	if !ac.Auth.CheckPermission(c, permissionId) {
@@ -425,21 +528,21 @@ func (ac *AppointmentController) GetAppointmentByCustomerIDAndDate(c *gin.Contex
 	customerID, err := strconv.Atoi(c.Query("customerId"))
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Invalid customer ID format")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid customer ID"})
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid customer ID")
 		return
 	}
 
 	dateTime, err := time.Parse("2006-01-02 15:04:05", c.Query("dateTime"))
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Invalid date format for GetAppointmentByCustomerIDAndDate")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format, use 'YYYY-MM-DD HH:MM:SS'"})
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid date format, use 'YYYY-MM-DD HH:MM:SS'")
 		return
 	}
 
-	appointment, err := ac.Service.GetAppointmentByCustomerIDAndDate(customerID, dateTime)
+	appointment, err := ac.Service.GetAppointmentByCustomerIDAndDate(c.Request.Context(), customerID, dateTime)
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Appointment not found for given customer ID and date")
-		c.JSON(http.StatusNotFound, gin.H{"error": "Appointment not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Appointment not found")
 		return
 	}
 
@@ -465,40 +568,35 @@ func (ac *AppointmentController) GetAppointmentByCustomerIDAndDate(c *gin.Contex
 // @Produce      json
 // @Param        id  path     int  true  "Appointment ID"
 // @Success      200 {object} models.MessageResponse "Appointment deleted successfully"
-// @Failure      400 {object} models.ErrorResponse  "Invalid appointment ID format"
-// @Failure      401 {object} models.ErrorResponse  "Unauthorized or permission denied"
-// @Failure      404 {object} models.ErrorResponse  "Appointment not found for the given ID"
-// @Failure      500 {object} models.ErrorResponse  "Error deleting the appointment"
+// @Failure      400 {object} dtos.ErrorResponse  "Invalid appointment ID format"
+// @Failure      401 {object} dtos.ErrorResponse  "Unauthorized or permission denied"
+// @Failure      404 {object} dtos.ErrorResponse  "Appointment not found for the given ID"
+// @Failure      500 {object} dtos.ErrorResponse  "Error deleting the appointment"
 // @Security     ApiKeyAuth
 // @Router       /appointments/deleteAppointment/{id} [delete]
 func (ac *AppointmentController) DeleteAppointmentByID(c *gin.Context) {
-	if ac.Log.RegisterLog(c, "Attempting to delete appointment") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_DELETE_APPOINTMENT
 	if !ac.Auth.CheckPermission(c, permissionId) {
 		_ = ac.Log.RegisterLog(c, "Access denied for DeleteAppointmentByID")
-		c.JSON(http.StatusForbidden, gin.H{"error": "No tienes permisos para eliminar citas"})
+		utilities.RespondError(c, http.StatusForbidden, "You do not have permission to delete appointments")
 		return
 	}
 
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Invalid appointment ID: "+c.Param("id"))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid appointment ID"})
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid appointment ID")
 		return
 	}
 
-	err = ac.Service.DeleteAppointmentByID(id)
+	err = ac.Service.DeleteAppointmentByID(c.Request.Context(), id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			_ = ac.Log.RegisterLog(c, "Appointment not found for ID: "+strconv.Itoa(id))
-			c.JSON(http.StatusNotFound, gin.H{"error": "Appointment not found"})
+			utilities.RespondError(c, http.StatusNotFound, "Appointment not found")
 		} else {
 			_ = ac.Log.RegisterLog(c, "Error deleting appointment")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			utilities.RespondError(c, http.StatusInternalServerError, err.Error())
 		}
 		return
 	}
@@ -510,43 +608,392 @@ func (ac *AppointmentController) DeleteAppointmentByID(c *gin.Context) {
 
 // GetAppointmentsByHourRange godoc
 // @Summary      Get appointment count by hourly range for a specific date
-// @Description  Retrieves the number of appointments for each hour within a specified date. Requires permission to view appointments by hour.
+// @Description  Retrieves the number of appointments for each hour of the day's business hours, starting at openingHour. Closed days have no hours. Requires permission to view appointments by hour.
 // @Tags         appointments
 // @Accept       json
 // @Produce      json
 // @Param        date  query     string  true  "Date in YYYY-MM-DD format"
 // @Success      200  {array}  models.Appointment  "List of hourly appointment counts"
-// @Failure      400  {object}  models.ErrorResponse  "Invalid date format or missing 'date' parameter"
-// @Failure      401  {object}  models.ErrorResponse "Unauthorized or permission denied"
-// @Failure      500  {object}  models.ErrorResponse  "Error retrieving appointment counts"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid date format or missing 'date' parameter"
+// @Failure      401  {object}  dtos.ErrorResponse "Unauthorized or permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving appointment counts"
 // @Security     ApiKeyAuth
 // @Router       /appointments/hourly-count [get]
 func (c *AppointmentController) GetAppointmentsByHourRange(ctx *gin.Context) {
-
 	permissionId := config.PERMISSION_GET_APPOINTMENTS_BY_HOUR
 	if !c.Auth.CheckPermission(ctx, permissionId) {
 		_ = c.Log.RegisterLog(ctx, "Access denied for CreateAppointment")
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "No tienes permisos para crear citas"})
+		utilities.RespondError(ctx, http.StatusForbidden, "You do not have permission to create appointments")
 		return
 	}
 
 	dateParam := ctx.Query("date")
 	if dateParam == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Se requiere el parámetro 'date' en formato YYYY-MM-DD"})
+		utilities.RespondError(ctx, http.StatusBadRequest, "Query parameter 'date' is required in YYYY-MM-DD format")
 		return
 	}
 
 	date, err := time.Parse("2006-01-02", dateParam)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Formato de fecha inválido. Usa YYYY-MM-DD"})
+		utilities.RespondError(ctx, http.StatusBadRequest, "Invalid date format. Use YYYY-MM-DD")
+		return
+	}
+
+	counts, hours, err := c.Service.GetHourlyAppointmentCount(ctx.Request.Context(), date)
+	if err != nil {
+		utilities.RespondError(ctx, http.StatusInternalServerError, "Error counting appointments")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"date": dateParam, "open": hours.Open, "openingHour": hours.OpeningHour, "appointmentsPerHour": counts})
+}
+
+// GetAvailableSlots godoc
+// @Summary      Available appointment slots
+// @Description  Lists the one-hour slots of a day within its business hours that have not started yet and still have room, with how many appointments can still be booked in each. Closed days have no slots.
+// @Tags         appointments
+// @Produce      json
+// @Param        date  query  string  true  "Date in YYYY-MM-DD format"
+// @Success      200  {array}   dtos.AppointmentSlotDTO  "Available slots"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid date format or missing 'date' parameter"
+// @Failure      403  {object}  dtos.ErrorResponse  "Access denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving the slots"
+// @Security     ApiKeyAuth
+// @Router       /appointments/availableSlots [get]
+func (ac *AppointmentController) GetAvailableSlots(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_AVAILABLE_APPOINTMENT_SLOTS
+	if !ac.Auth.CheckPermission(c, permissionId) {
+		_ = ac.Log.RegisterLog(c, "Access denied for GetAvailableSlots")
+		return
+	}
+
+	ac.GetAvailableAppointmentSlots(c)
+}
+
+// GetAvailableAppointmentSlots godoc
+// @Summary      Available appointment slots
+// @Description  Public, rate-limited. Lists the one-hour slots of a day within its business hours that have not started yet and still have room, with how many appointments can still be booked in each.
+// @Tags         public
+// @Produce      json
+// @Param        date  query  string  true  "Date in YYYY-MM-DD format"
+// @Success      200  {array}   dtos.AppointmentSlotDTO  "Available slots"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid date format or missing 'date' parameter"
+// @Failure      429  {object}  dtos.ErrorResponse  "Too many requests"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving the slots"
+// @Router       /public/appointments/slots [get]
+func (ac *AppointmentController) GetAvailableAppointmentSlots(c *gin.Context) {
+	dateParam := c.Query("date")
+	if dateParam == "" {
+		utilities.RespondError(c, http.StatusBadRequest, "Query parameter 'date' is required in YYYY-MM-DD format")
+		return
+	}
+	date, err := time.ParseInLocation("2006-01-02", dateParam, time.Local)
+	if err != nil {
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid date format. Use YYYY-MM-DD")
 		return
 	}
 
-	counts, err := c.Service.GetHourlyAppointmentCount(date)
+	slots, err := ac.Service.GetAvailableSlots(c.Request.Context(), date)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al contar las citas: " + err.Error()})
+		_ = ac.Log.RegisterLog(c, "Error retrieving available appointment slots: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving the slots")
+		return
+	}
+
+	c.JSON(http.StatusOK, slots)
+}
+
+// BookAppointment godoc
+// @Summary      Book an appointment
+// @Description  Public, rate-limited. Books an appointment in one of the available slots (on the hour, within business hours, up to 60 days ahead) with the same capacity rule as POST /appointments. The customer is matched by email or created, and a confirmation email with the cancellation link is always sent; the same link is returned as cancelUrl. When CAPTCHA_PROVIDER is configured, captchaToken must carry a valid token from the captcha widget.
+// @Tags         public
+// @Accept       json
+// @Produce      json
+// @Param        booking  body      dtos.PublicAppointmentBookingDTO  true  "Booking"
+// @Success      201  {object}  dtos.PublicAppointmentDTO  "Appointment booked"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid data or the time is not a bookable slot"
+// @Failure      403  {object}  dtos.ErrorResponse  "Missing or invalid captcha token"
+// @Failure      409  {object}  dtos.ErrorResponse  "The slot is full"
+// @Failure      429  {object}  dtos.ErrorResponse  "Too many requests"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error booking the appointment"
+// @Router       /public/appointments [post]
+func (ac *AppointmentController) BookAppointment(c *gin.Context) {
+	var booking dtos.PublicAppointmentBookingDTO
+	if err := c.ShouldBindJSON(&booking); err != nil {
+		utilities.RespondValidationError(c, "Invalid JSON format", err)
+		return
+	}
+
+	appointment, err := ac.Service.BookAppointment(c.Request.Context(), booking, c.ClientIP())
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrCaptchaFailed):
+			utilities.RespondError(c, http.StatusForbidden, "Captcha verification failed, try again")
+		case errors.Is(err, services.ErrAppointmentSlotUnavailable):
+			utilities.RespondError(c, http.StatusBadRequest, "The requested time is not an available slot")
+		case errors.Is(err, services.ErrAppointmentSlotFull):
+			utilities.RespondError(c, http.StatusConflict, "The slot is already full, choose another time")
+		default:
+			_ = ac.Log.RegisterLog(c, "Error booking public appointment: "+err.Error())
+			utilities.RespondError(c, http.StatusInternalServerError, "Error booking the appointment")
+		}
+		return
+	}
+
+	_ = ac.Log.RegisterLog(c, "Appointment booked from the web with ID: "+strconv.Itoa(appointment.ID))
+	c.JSON(http.StatusCreated, appointment)
+}
+
+// LinkAppointmentCustomers godoc
+// @Summary      Link historical appointments to customers
+// @Description  Links every appointment whose customerId matches no customer to the customer with the same email (case-insensitive). When there is none, a customer is created from the appointment data with a provisional document number (APPT-...). Appointments without email are skipped.
+// @Tags         appointments
+// @Produce      json
+// @Success      200  {object}  dtos.AppointmentCustomerLinkDTO  "Linking summary"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error linking appointments"
+// @Security     ApiKeyAuth
+// @Router       /appointments/link-customers [post]
+func (ac *AppointmentController) LinkAppointmentCustomers(c *gin.Context) {
+	permissionId := config.PERMISSION_LINK_APPOINTMENT_CUSTOMERS
+	if !ac.Auth.CheckPermission(c, permissionId) {
+		_ = ac.Log.RegisterLog(c, "Access denied for LinkAppointmentCustomers")
+		return
+	}
+
+	result, err := ac.Service.LinkAppointmentCustomers(c.Request.Context())
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Error linking appointments to customers: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error linking appointments to customers")
+		return
+	}
+
+	_ = ac.Log.RegisterLog(c, "Linked "+strconv.Itoa(result.Linked)+" appointments to customers")
+	c.JSON(http.StatusOK, result)
+}
+
+// GetAppointmentCancellation godoc
+// @Summary      Show the cancellation page of an appointment
+// @Description  Target of the cancellation link in the confirmation email. It needs no authentication: the signed token identifies the appointment. Shows the appointment and a button that confirms the cancellation with a POST, so link scanners cannot cancel it.
+// @Tags         appointments
+// @Produce      html
+// @Param        token  query  string  true  "Signed cancellation token"
+// @Success      200  {string}  string  "Confirmation page"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid or expired link"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving appointment"
+// @Router       /appointments/cancel [get]
+func (ac *AppointmentController) GetAppointmentCancellation(c *gin.Context) {
+	appointment, err := ac.Service.GetAppointmentByCancelToken(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		ac.respondCancellationError(c, err)
+		return
+	}
+
+	when := appointment.DateTime.Format("02/01/2006") + " a las " + appointment.DateTime.Format("15:04")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(cancellationPage(
+		"<p>¿Quieres cancelar tu cita del "+when+"?</p>"+
+			`<form method="post"><button type="submit">Cancelar la cita</button></form>`)))
+}
+
+// CancelAppointmentByToken godoc
+// @Summary      Cancel an appointment from its email link
+// @Description  Cancels the appointment identified by the signed token and frees its time slot. The link stops working once the appointment time has passed, it was rescheduled or it is no longer pending or confirmed.
+// @Tags         appointments
+// @Produce      html
+// @Param        token  query  string  true  "Signed cancellation token"
+// @Success      200  {string}  string  "Cancellation confirmed"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid or expired link"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error cancelling appointment"
+// @Router       /appointments/cancel [post]
+func (ac *AppointmentController) CancelAppointmentByToken(c *gin.Context) {
+	appointment, err := ac.Service.CancelAppointmentByToken(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		ac.respondCancellationError(c, err)
+		return
+	}
+
+	_ = ac.Log.RegisterLog(c, "Appointment cancelled from email link with ID: "+strconv.Itoa(appointment.ID))
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(cancellationPage("<p>Tu cita fue cancelada.</p>")))
+}
+
+func (ac *AppointmentController) respondCancellationError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidLinkToken) {
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid or expired cancellation link")
+		return
+	}
+	utilities.RespondError(c, http.StatusInternalServerError, "Error cancelling appointment")
+}
+
+func cancellationPage(content string) string {
+	return `<!DOCTYPE html><html lang="es"><head><meta charset="UTF-8"><title>Cancelar cita</title></head>` +
+		`<body style="font-family:Arial,Helvetica,sans-serif;padding:24px;">` + content + `</body></html>`
+}
+
+// GetAppointmentReminders godoc
+// @Summary      Get the reminder history of an appointment
+// @Description  Lists every reminder stage reached by the appointment, including the ones not sent because the customer opted out or has no email. A rescheduled appointment is reminded again for its new date.
+// @Tags         appointments
+// @Produce      json
+// @Param        id   path  int  true  "Appointment ID"
+// @Success      200 {array}  models.AppointmentReminder "Reminder history"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid appointment ID"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "Appointment not found"
+// @Failure      500 {object} dtos.ErrorResponse "Error retrieving reminders"
+// @Security     ApiKeyAuth
+// @Router       /appointments/{id}/reminders [get]
+func (ac *AppointmentController) GetAppointmentReminders(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_APPOINTMENT_REMINDERS
+	if !ac.Auth.CheckPermission(c, permissionId) {
+		_ = ac.Log.RegisterLog(c, "Access denied for GetAppointmentReminders")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Invalid appointment ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid appointment ID")
+		return
+	}
+
+	reminders, err := ac.Reminders.GetAppointmentReminders(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = ac.Log.RegisterLog(c, "Appointment not found with ID: "+c.Param("id"))
+			utilities.RespondError(c, http.StatusNotFound, "Appointment not found")
+			return
+		}
+		_ = ac.Log.RegisterLog(c, "Error retrieving appointment reminders: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving reminders")
+		return
+	}
+
+	_ = ac.Log.RegisterLog(c, "Successfully retrieved reminders of appointment with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, reminders)
+}
+
+// GetAppointmentStateTypes godoc
+// @Summary      Get appointment states
+// @Description  Lists the states an appointment can be in: pending, confirmed, completed, cancelled and no_show.
+// @Tags         appointments
+// @Produce      json
+// @Success      200 {array}  models.AppointmentStateType "Appointment states"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      500 {object} dtos.ErrorResponse "Error retrieving appointment states"
+// @Security     ApiKeyAuth
+// @Router       /appointment-state-types [get]
+func (ac *AppointmentController) GetAppointmentStateTypes(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_APPOINTMENT_STATE_TYPES
+	if !ac.Auth.CheckPermission(c, permissionId) {
+		_ = ac.Log.RegisterLog(c, "Access denied for GetAppointmentStateTypes")
+		return
+	}
+
+	states, err := ac.Service.GetAppointmentStateTypes(c.Request.Context())
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Error retrieving appointment states: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving appointment states")
+		return
+	}
+
+	_ = ac.Log.RegisterLog(c, "Successfully retrieved appointment states")
+	c.JSON(http.StatusOK, states)
+}
+
+// ChangeAppointmentState godoc
+// @Summary      Change the state of an appointment
+// @Description  Moves the appointment to another state and records it in its state history. Pending appointments can be confirmed or cancelled; confirmed ones can be completed, cancelled or marked as no_show, the last two only once the appointment time has come. Completed, cancelled and no_show are final. Cancelled appointments free their slot.
+// @Tags         appointments
+// @Accept       json
+// @Produce      json
+// @Param        id     path  int                       true  "Appointment ID"
+// @Param        state  body  dtos.AppointmentStateDTO  true  "New state"
+// @Success      200 {object} models.Appointment "Updated appointment"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid request data"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "Appointment not found"
+// @Failure      409 {object} dtos.ErrorResponse "Transition not allowed"
+// @Failure      500 {object} dtos.ErrorResponse "Error changing the appointment state"
+// @Security     ApiKeyAuth
+// @Router       /appointments/{id}/state [patch]
+func (ac *AppointmentController) ChangeAppointmentState(c *gin.Context) {
+	permissionId := config.PERMISSION_CHANGE_APPOINTMENT_STATE
+	if !ac.Auth.CheckPermission(c, permissionId) {
+		_ = ac.Log.RegisterLog(c, "Access denied for ChangeAppointmentState")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Invalid appointment ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid appointment ID")
+		return
+	}
+
+	var dto dtos.AppointmentStateDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = ac.Log.RegisterLog(c, "Invalid appointment state data: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	appointment, err := ac.Service.ChangeAppointmentState(c.Request.Context(), id, dto.StateID, dto.Reason)
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Error changing state of appointment with ID "+c.Param("id")+": "+err.Error())
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utilities.RespondError(c, http.StatusNotFound, "Appointment not found")
+		case errors.Is(err, services.ErrInvalidAppointmentState):
+			utilities.RespondError(c, http.StatusConflict, err.Error())
+		default:
+			utilities.RespondError(c, http.StatusInternalServerError, "Error changing the appointment state")
+		}
+		return
+	}
+
+	_ = ac.Log.RegisterLog(c, "Appointment with ID "+c.Param("id")+" moved to state "+strconv.Itoa(dto.StateID))
+	c.JSON(http.StatusOK, appointment)
+}
+
+// GetAppointmentStateHistory godoc
+// @Summary      Get the state history of an appointment
+// @Description  Lists every state change of the appointment, oldest first, with who made it and why.
+// @Tags         appointments
+// @Produce      json
+// @Param        id   path  int  true  "Appointment ID"
+// @Success      200 {array}  models.AppointmentStateChange "State history"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid appointment ID"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "Appointment not found"
+// @Failure      500 {object} dtos.ErrorResponse "Error retrieving the state history"
+// @Security     ApiKeyAuth
+// @Router       /appointments/{id}/state-history [get]
+func (ac *AppointmentController) GetAppointmentStateHistory(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_APPOINTMENT_STATE_HISTORY
+	if !ac.Auth.CheckPermission(c, permissionId) {
+		_ = ac.Log.RegisterLog(c, "Access denied for GetAppointmentStateHistory")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Invalid appointment ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid appointment ID")
+		return
+	}
+
+	history, err := ac.Service.GetAppointmentStateHistory(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = ac.Log.RegisterLog(c, "Appointment not found with ID: "+c.Param("id"))
+			utilities.RespondError(c, http.StatusNotFound, "Appointment not found")
+			return
+		}
+		_ = ac.Log.RegisterLog(c, "Error retrieving appointment state history: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving the state history")
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"date": dateParam, "appointmentsPerHour": counts})
+	_ = ac.Log.RegisterLog(c, "Successfully retrieved state history of appointment with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, history)
 }
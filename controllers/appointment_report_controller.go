@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AppointmentReportController struct {
+	Service *services.AppointmentReportService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewAppointmentReportController(service *services.AppointmentReportService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *AppointmentReportController {
+	return &AppointmentReportController{Service: service, Auth: auth, Log: log}
+}
+
+// GetAppointmentReport godoc
+// @Summary      Appointment report
+// @Description  Counts the appointments between two dates per day, week or month and state, with the no-show rate (no-shows over completed plus no-shows).
+// @Description  busiest_hours ranks the hours of the day by non-cancelled appointments, to plan reception staffing.
+// @Tags         reports
+// @Produce      json
+// @Param        from     query  string  true   "Start date (YYYY-MM-DD)"
+// @Param        to       query  string  true   "End date, inclusive (YYYY-MM-DD)"
+// @Param        groupBy  query  string  false  "Grouping: day, week or month (default day)"
+// @Param        format   query  string  false  "Output format: json, csv or xlsx (also negotiated through the Accept header); exports contain the periods"
+// @Success      200  {object}  dtos.AppointmentReportDTO  "Appointment figures"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid dates or grouping"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error building the report"
+// @Security     ApiKeyAuth
+// @Router       /reports/appointments [get]
+func (arc *AppointmentReportController) GetAppointmentReport(c *gin.Context) {
+	fromStr := c.Query("from")
+	toStr := c.Query("to")
+	groupBy := c.DefaultQuery("groupBy", "day")
+
+	permissionId := config.PERMISSION_VIEW_APPOINTMENT_REPORT
+	if !arc.Auth.CheckPermission(c, permissionId) {
+		_ = arc.Log.RegisterLog(c, "Access denied for GetAppointmentReport")
+		return
+	}
+
+	format, err := utilities.ReportFormat(c)
+	if err != nil {
+		_ = arc.Log.RegisterLog(c, "Invalid report format: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	from, err := time.Parse("2006-01-02", fromStr)
+	if err != nil {
+		_ = arc.Log.RegisterLog(c, "Invalid from date: "+fromStr)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid 'from' date format. Use YYYY-MM-DD")
+		return
+	}
+
+	to, err := time.Parse("2006-01-02", toStr)
+	if err != nil {
+		_ = arc.Log.RegisterLog(c, "Invalid to date: "+toStr)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid 'to' date format. Use YYYY-MM-DD")
+		return
+	}
+	to = to.Add(24*time.Hour - time.Nanosecond)
+
+	report, err := arc.Service.GetAppointmentReport(c.Request.Context(), from, to, groupBy)
+	if err != nil {
+		_ = arc.Log.RegisterLog(c, "Error building appointment report: "+err.Error())
+		if errors.Is(err, services.ErrInvalidReportParams) {
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error building appointment report")
+		return
+	}
+
+	if format != utilities.ReportFormatJSON {
+		if err := utilities.WriteReportTable(c, format, appointmentReportTable(report)); err != nil {
+			_ = arc.Log.RegisterLog(c, "Error exporting appointment report: "+err.Error())
+			return
+		}
+		_ = arc.Log.RegisterLog(c, "Successfully exported appointment report between "+fromStr+" and "+toStr)
+		return
+	}
+
+	_ = arc.Log.RegisterLog(c, "Successfully built appointment report between "+fromStr+" and "+toStr)
+	c.JSON(http.StatusOK, report)
+}
+
+func appointmentReportTable(report *dtos.AppointmentReportDTO) utilities.ReportTable {
+	return utilities.ReportTable{
+		Name:   "appointments-" + report.GroupBy,
+		Header: []string{"period", "total", "pending", "confirmed", "completed", "cancelled", "no_show", "no_show_rate"},
+		Rows: func(write func(row []interface{}) error) error {
+			for _, p := range report.Periods {
+				if err := write([]interface{}{p.Period.Format("2006-01-02"), p.Total, p.Pending, p.Confirmed, p.Completed, p.Cancelled, p.NoShow, p.NoShowRate}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,233 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ArchiveController struct {
+	Service *services.ArchiveService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewArchiveController(service *services.ArchiveService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *ArchiveController {
+	return &ArchiveController{Service: service, Auth: auth, Log: log}
+}
+
+// GetArchivedInvoices godoc
+// @Summary      Search archived invoices
+// @Description  Returns a page of invoices moved to the archive, optionally filtered by customer and issue date. Each entry carries the invoice as it was returned by GET /invoices/{id} when it was archived.
+// @Tags         archive
+// @Produce      json
+// @Param        customerId  query  int     false  "Customer ID"
+// @Param        from        query  string  false  "Start date (YYYY-MM-DD or RFC3339)"
+// @Param        to          query  string  false  "End date (YYYY-MM-DD, inclusive, or RFC3339)"
+// @Param        page        query  int     false  "Page number (default 1)"
+// @Param        pageSize    query  int     false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[dtos.ArchivedDocumentDTO]  "Page of archived invoices"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid filter"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error searching archived invoices"
+// @Security     ApiKeyAuth
+// @Router       /archive/invoices [get]
+func (ac *ArchiveController) GetArchivedInvoices(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_ARCHIVE
+	if !ac.Auth.CheckPermission(c, permissionId) {
+		_ = ac.Log.RegisterLog(c, "Access denied for GetArchivedInvoices")
+		return
+	}
+
+	filter, ok := ac.parseArchiveFilter(c)
+	if !ok {
+		return
+	}
+
+	page, err := ac.Service.SearchArchivedInvoices(c.Request.Context(), filter)
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Error searching archived invoices: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error searching archived invoices")
+		return
+	}
+
+	_ = ac.Log.RegisterLog(c, "Successfully searched archived invoices")
+	c.JSON(http.StatusOK, page)
+}
+
+// GetArchivedInvoiceByID godoc
+// @Summary      Get an archived invoice
+// @Description  Returns an invoice moved to the archive, by its original ID.
+// @Tags         archive
+// @Produce      json
+// @Param        id   path      int  true  "Invoice ID"
+// @Success      200  {object}  dtos.ArchivedDocumentDTO  "Archived invoice"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid invoice ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Invoice not found in the archive"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving archived invoice"
+// @Security     ApiKeyAuth
+// @Router       /archive/invoices/{id} [get]
+func (ac *ArchiveController) GetArchivedInvoiceByID(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_ARCHIVE
+	if !ac.Auth.CheckPermission(c, permissionId) {
+		_ = ac.Log.RegisterLog(c, "Access denied for GetArchivedInvoiceByID")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Invalid archived invoice ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid invoice ID")
+		return
+	}
+
+	document, err := ac.Service.GetArchivedInvoice(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = ac.Log.RegisterLog(c, "Archived invoice not found with ID: "+strconv.Itoa(id))
+			utilities.RespondError(c, http.StatusNotFound, "Invoice not found in the archive")
+			return
+		}
+		_ = ac.Log.RegisterLog(c, "Error retrieving archived invoice with ID "+strconv.Itoa(id)+": "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving archived invoice")
+		return
+	}
+
+	_ = ac.Log.RegisterLog(c, "Successfully retrieved archived invoice with ID: "+strconv.Itoa(id))
+	c.JSON(http.StatusOK, document)
+}
+
+// GetArchivedAppointments godoc
+// @Summary      Search archived appointments
+// @Description  Returns a page of appointments moved to the archive, optionally filtered by customer and appointment date. Each entry carries the appointment as it was returned by GET /appointments/{id} when it was archived.
+// @Tags         archive
+// @Produce      json
+// @Param        customerId  query  int     false  "Customer ID"
+// @Param        from        query  string  false  "Start date (YYYY-MM-DD or RFC3339)"
+// @Param        to          query  string  false  "End date (YYYY-MM-DD, inclusive, or RFC3339)"
+// @Param        page        query  int     false  "Page number (default 1)"
+// @Param        pageSize    query  int     false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[dtos.ArchivedDocumentDTO]  "Page of archived appointments"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid filter"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error searching archived appointments"
+// @Security     ApiKeyAuth
+// @Router       /archive/appointments [get]
+func (ac *ArchiveController) GetArchivedAppointments(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_ARCHIVE
+	if !ac.Auth.CheckPermission(c, permissionId) {
+		_ = ac.Log.RegisterLog(c, "Access denied for GetArchivedAppointments")
+		return
+	}
+
+	filter, ok := ac.parseArchiveFilter(c)
+	if !ok {
+		return
+	}
+
+	page, err := ac.Service.SearchArchivedAppointments(c.Request.Context(), filter)
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Error searching archived appointments: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error searching archived appointments")
+		return
+	}
+
+	_ = ac.Log.RegisterLog(c, "Successfully searched archived appointments")
+	c.JSON(http.StatusOK, page)
+}
+
+// GetArchivedAppointmentByID godoc
+// @Summary      Get an archived appointment
+// @Description  Returns an appointment moved to the archive, by its original ID.
+// @Tags         archive
+// @Produce      json
+// @Param        id   path      int  true  "Appointment ID"
+// @Success      200  {object}  dtos.ArchivedDocumentDTO  "Archived appointment"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid appointment ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Appointment not found in the archive"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving archived appointment"
+// @Security     ApiKeyAuth
+// @Router       /archive/appointments/{id} [get]
+func (ac *ArchiveController) GetArchivedAppointmentByID(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_ARCHIVE
+	if !ac.Auth.CheckPermission(c, permissionId) {
+		_ = ac.Log.RegisterLog(c, "Access denied for GetArchivedAppointmentByID")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Invalid archived appointment ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid appointment ID")
+		return
+	}
+
+	document, err := ac.Service.GetArchivedAppointment(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = ac.Log.RegisterLog(c, "Archived appointment not found with ID: "+strconv.Itoa(id))
+			utilities.RespondError(c, http.StatusNotFound, "Appointment not found in the archive")
+			return
+		}
+		_ = ac.Log.RegisterLog(c, "Error retrieving archived appointment with ID "+strconv.Itoa(id)+": "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving archived appointment")
+		return
+	}
+
+	_ = ac.Log.RegisterLog(c, "Successfully retrieved archived appointment with ID: "+strconv.Itoa(id))
+	c.JSON(http.StatusOK, document)
+}
+
+func (ac *ArchiveController) parseArchiveFilter(c *gin.Context) (dtos.ArchiveFilterDTO, bool) {
+	var filter dtos.ArchiveFilterDTO
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Invalid pagination: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return filter, false
+	}
+	filter.PaginationDTO = pagination
+
+	if customerStr := c.Query("customerId"); customerStr != "" {
+		customerID, err := strconv.Atoi(customerStr)
+		if err != nil {
+			_ = ac.Log.RegisterLog(c, "Invalid customerId: "+customerStr)
+			utilities.RespondError(c, http.StatusBadRequest, "Invalid 'customerId'")
+			return filter, false
+		}
+		filter.CustomerID = &customerID
+	}
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, _, err := parseLogDate(fromStr)
+		if err != nil {
+			_ = ac.Log.RegisterLog(c, "Invalid from date: "+fromStr)
+			utilities.RespondError(c, http.StatusBadRequest, "Invalid 'from' date. Use YYYY-MM-DD or RFC3339")
+			return filter, false
+		}
+		filter.From = &from
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		to, dateOnly, err := parseLogDate(toStr)
+		if err != nil {
+			_ = ac.Log.RegisterLog(c, "Invalid to date: "+toStr)
+			utilities.RespondError(c, http.StatusBadRequest, "Invalid 'to' date. Use YYYY-MM-DD or RFC3339")
+			return filter, false
+		}
+		if dateOnly {
+			to = to.Add(24*time.Hour - time.Nanosecond)
+		}
+		filter.To = &to
+	}
+	return filter, true
+}
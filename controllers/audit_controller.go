@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditController struct {
+	Service *services.AuditService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewAuditController(service *services.AuditService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *AuditController {
+	return &AuditController{Service: service, Auth: auth, Log: log}
+}
+
+// GetAuditTrail godoc
+// @Summary      Get the change history of an object
+// @Description  Returns the before/after snapshots recorded for every update or delete of the given object, newest first.
+// @Tags         audit
+// @Produce      json
+// @Param        entity  path  string  true  "Entity: customers, items, invoices, users, roles or user-types"
+// @Param        id      path  string  true  "Object ID"
+// @Success      200  {array}   dtos.GetAuditEntryDTO  "Change history"
+// @Failure      400  {object}  dtos.ErrorResponse  "Unknown entity"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving the change history"
+// @Security     ApiKeyAuth
+// @Router       /audit/{entity}/{id} [get]
+func (ac *AuditController) GetAuditTrail(c *gin.Context) {
+	entity := c.Param("entity")
+	id := c.Param("id")
+
+	permissionId := config.PERMISSION_VIEW_AUDIT_TRAIL
+	if !ac.Auth.CheckPermission(c, permissionId) {
+		_ = ac.Log.RegisterLog(c, "Access denied for GetAuditTrail")
+		return
+	}
+
+	entries, err := ac.Service.GetAuditTrail(c.Request.Context(), entity, id)
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Error retrieving audit trail for "+entity+" "+id+": "+err.Error())
+		if errors.Is(err, services.ErrUnknownAuditEntity) {
+			utilities.RespondError(c, http.StatusBadRequest, "Unknown entity '"+entity+"'")
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving audit trail")
+		return
+	}
+
+	entryDTOs := make([]dtos.GetAuditEntryDTO, 0, len(entries))
+	for _, entry := range entries {
+		entryDTOs = append(entryDTOs, dtos.GetAuditEntryDTO{
+			ID:        entry.ID,
+			Entity:    entry.Entity,
+			EntityID:  entry.EntityID,
+			Action:    entry.Action,
+			UserEmail: entry.UserEmail,
+			Before:    auditRawJSON(entry.Before),
+			After:     auditRawJSON(entry.After),
+			DateTime:  entry.DateTime,
+		})
+	}
+
+	_ = ac.Log.RegisterLog(c, "Successfully retrieved audit trail for "+entity+" "+id)
+	c.JSON(http.StatusOK, entryDTOs)
+}
+
+func auditRawJSON(value string) json.RawMessage {
+	if value == "" {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(value)
+}
@@ -28,8 +28,8 @@ func NewAuthorizationController(service *services.AuthorizationService, log *uti
 // @Param        email       query     string  true  "User's email address"
 // @Param        permission_id  query  string  true  "Permission ID to check"
 // @Success      200        {object}  models.MessageResponse   "Response with the permission status"
-// @Failure      400        {object}  models.ErrorResponse   "Invalid or missing parameters"
-// @Failure      500        {object}  models.ErrorResponse   "Error checking permission"
+// @Failure      400        {object}  dtos.ErrorResponse   "Invalid or missing parameters"
+// @Failure      500        {object}  dtos.ErrorResponse   "Error checking permission"
 // @Router       /auth/check-permission [get]
 func (ac *AuthorizationController) CheckUserPermission(c *gin.Context) {
 	email := c.Query("email")
@@ -37,18 +37,18 @@ func (ac *AuthorizationController) CheckUserPermission(c *gin.Context) {
 	permissionStr, err := strconv.Atoi(permissionID)
 
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Permission ID"})
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid Permission ID")
 		return
 	}
 
 	if email == "" || permissionID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Email and permission_id are required"})
+		utilities.RespondError(c, http.StatusBadRequest, "Email and permission_id are required")
 		return
 	}
 
-	hasPermission, err := ac.Service.UserHasPermission(email, permissionStr)
+	hasPermission, err := ac.Service.UserHasPermission(c.Request.Context(), email, permissionStr)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking permission"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error checking permission")
 		return
 	}
 
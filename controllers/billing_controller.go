@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"totesbackend/config"
@@ -26,15 +27,17 @@ type SubtotalResponse struct {
 
 // CalculateSubtotal godoc
 // @Summary      Calculate subtotal
-// @Description  Calculates the subtotal based on a list of billing items. Requires permission.
+// @Description  Calculates the subtotal based on a list of billing items, converting each item price into the requested currency with the stored exchange rates. With customerId, items on the customer's price list use the list price. Requires permission.
 // @Tags         billing
 // @Accept       json
 // @Produce      json
-// @Param        items  body      []dtos.BillingItemDTO  true  "List of billing items"
+// @Param        items     body      []dtos.BillingItemDTO  true  "List of billing items"
+// @Param        currency  query     string                 false "Currency code of the subtotal (default: the base currency)"
+// @Param        customerId query    int                    false "Customer whose price list applies"
 // @Success      200    {object}  SubtotalResponse       "Calculated subtotal"
-// @Failure      400    {object}  models.ErrorResponse    "Invalid request data"
-// @Failure      401    {object}  models.ErrorResponse    "Unauthorized or permission denied"
-// @Failure      404    {object}  models.ErrorResponse    "Calculation error (e.g., related data not found)"
+// @Failure      400    {object}  dtos.ErrorResponse    "Invalid request data"
+// @Failure      401    {object}  dtos.ErrorResponse    "Unauthorized or permission denied"
+// @Failure      404    {object}  dtos.ErrorResponse    "Calculation error (e.g., related data not found)"
 // @Security     ApiKeyAuth
 // @Router       /billing/subtotal [post]
 func (bc *BillingController) CalculateSubtotal(c *gin.Context) {
@@ -46,13 +49,22 @@ func (bc *BillingController) CalculateSubtotal(c *gin.Context) {
 
 	var itemsDTO []dtos.BillingItemDTO
 	if err := c.ShouldBindJSON(&itemsDTO); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		utilities.RespondValidationError(c, "Invalid request data", err)
 		return
 	}
 
-	subtotal, err := bc.Service.CalculateSubtotal(itemsDTO)
+	customerID := 0
+	if raw := c.Query("customerId"); raw != "" {
+		var err error
+		if customerID, err = strconv.Atoi(raw); err != nil {
+			utilities.RespondError(c, http.StatusBadRequest, "Invalid customerId")
+			return
+		}
+	}
+
+	subtotal, err := bc.Service.CalculateSubtotal(c.Request.Context(), customerID, c.Query("currency"), itemsDTO)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utilities.RespondError(c, http.StatusNotFound, err.Error())
 		return
 	}
 
@@ -65,15 +77,15 @@ type TotalResponse struct {
 
 // CalculateTotal godoc
 // @Summary      Calculate total
-// @Description  Calculates the total amount based on billing items, discounts, and tax types. Requires permission.
+// @Description  Calculates the total amount based on billing items, discounts, and tax types, in the requested currency (default: the base currency). Fixed-value discounts and taxes are in the base currency and are converted. With customerId, items on the customer's price list use the list price. Requires permission.
 // @Tags         billing
 // @Accept       json
 // @Produce      json
 // @Param        body  body  dtos.CalculateTotalRequestDTO  true  "Billing total calculation input"
 // @Success      200   {object}  TotalResponse         "Calculated total"
-// @Failure      400   {object}  models.ErrorResponse       "Invalid request data"
-// @Failure      401   {object}  models.ErrorResponse       "Unauthorized or permission denied"
-// @Failure      404   {object}  models.ErrorResponse       "Calculation error (e.g., related data not found)"
+// @Failure      400   {object}  dtos.ErrorResponse       "Invalid request data, an inactive or expired discount, or an inactive tax"
+// @Failure      401   {object}  dtos.ErrorResponse       "Unauthorized or permission denied"
+// @Failure      404   {object}  dtos.ErrorResponse       "Calculation error (e.g., related data not found)"
 // @Security     ApiKeyAuth
 // @Router       /billing/total [post]
 func (bc *BillingController) CalculateTotal(c *gin.Context) {
@@ -86,7 +98,7 @@ func (bc *BillingController) CalculateTotal(c *gin.Context) {
 
 	// Estructura del request con arrays de enteros
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		utilities.RespondValidationError(c, "Invalid request data", err)
 		return
 	}
 
@@ -100,9 +112,13 @@ func (bc *BillingController) CalculateTotal(c *gin.Context) {
 		taxTypesIdsStr[i] = strconv.Itoa(id)
 	}
 
-	total, err := bc.Service.CalculateTotal(discountTypesIdsStr, taxTypesIdsStr, request.ItemsDTO)
+	total, err := bc.Service.CalculateTotal(c.Request.Context(), request.CustomerID, request.Currency, discountTypesIdsStr, taxTypesIdsStr, request.ItemsDTO)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		if errors.Is(err, services.ErrDiscountNotApplicable) || errors.Is(err, services.ErrTaxTypeInactive) {
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusNotFound, err.Error())
 		return
 	}
 
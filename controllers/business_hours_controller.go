@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BusinessHoursController struct {
+	Service *services.BusinessHoursService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewBusinessHoursController(service *services.BusinessHoursService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *BusinessHoursController {
+	return &BusinessHoursController{Service: service, Auth: auth, Log: log}
+}
+
+// GetBusinessHours godoc
+// @Summary      Get business hours
+// @Description  Returns the business hours of the seven weekdays (0 Sunday to 6 Saturday). Appointments are booked in one-hour slots from openingHour to the slot starting at lastSlotHour; days that were never configured use the default hours.
+// @Tags         appointments
+// @Produce      json
+// @Success      200 {array}  models.BusinessHours "Business hours, Sunday first"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      500 {object} dtos.ErrorResponse "Error retrieving business hours"
+// @Security     ApiKeyAuth
+// @Router       /business-hours [get]
+func (bc *BusinessHoursController) GetBusinessHours(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_BUSINESS_HOURS
+	if !bc.Auth.CheckPermission(c, permissionId) {
+		_ = bc.Log.RegisterLog(c, "Access denied for GetBusinessHours")
+		return
+	}
+
+	hours, err := bc.Service.GetBusinessHours(c.Request.Context())
+	if err != nil {
+		_ = bc.Log.RegisterLog(c, "Error retrieving business hours: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving business hours")
+		return
+	}
+
+	_ = bc.Log.RegisterLog(c, "Successfully retrieved business hours")
+	c.JSON(http.StatusOK, hours)
+}
+
+// UpdateBusinessHours godoc
+// @Summary      Update business hours
+// @Description  Sets the business hours of the weekdays sent; the others keep theirs. Send open false to close a day. Existing appointments outside the new hours are kept.
+// @Tags         appointments
+// @Accept       json
+// @Produce      json
+// @Param        hours  body  []dtos.BusinessHoursDTO  true  "Business hours per weekday"
+// @Success      200 {array}  models.BusinessHours "Business hours of the whole week, Sunday first"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid business hours"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      500 {object} dtos.ErrorResponse "Error updating business hours"
+// @Security     ApiKeyAuth
+// @Router       /business-hours [put]
+func (bc *BusinessHoursController) UpdateBusinessHours(c *gin.Context) {
+	permissionId := config.PERMISSION_UPDATE_BUSINESS_HOURS
+	if !bc.Auth.CheckPermission(c, permissionId) {
+		_ = bc.Log.RegisterLog(c, "Access denied for UpdateBusinessHours")
+		return
+	}
+
+	var days []dtos.BusinessHoursDTO
+	if err := c.ShouldBindJSON(&days); err != nil {
+		_ = bc.Log.RegisterLog(c, "Invalid business hours: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	hours, err := bc.Service.UpdateBusinessHours(c.Request.Context(), days)
+	if err != nil {
+		_ = bc.Log.RegisterLog(c, "Error updating business hours: "+err.Error())
+		if errors.Is(err, services.ErrInvalidBusinessHours) {
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error updating business hours")
+		return
+	}
+
+	_ = bc.Log.RegisterLog(c, "Successfully updated business hours")
+	c.JSON(http.StatusOK, hours)
+}
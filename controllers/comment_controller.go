@@ -32,20 +32,15 @@ func NewCommentController(service *services.CommentService, auth *utilities.Auth
 // @Produce      json
 // @Param        id   path      int  true  "Comment ID"
 // @Success      200  {object}  dtos.GetCommentDTO       "The retrieved comment"
-// @Failure      400  {object}  models.ErrorResponse     "Invalid comment ID"
-// @Failure      401  {object}  models.ErrorResponse     "Unauthorized or permission denied"
-// @Failure      404  {object}  models.ErrorResponse     "Comment not found"
-// @Failure      500  {object}  models.ErrorResponse     "Error retrieving comment or registering log"
+// @Failure      400  {object}  dtos.ErrorResponse     "Invalid comment ID"
+// @Failure      401  {object}  dtos.ErrorResponse     "Unauthorized or permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse     "Comment not found"
+// @Failure      500  {object}  dtos.ErrorResponse     "Error retrieving comment or registering log"
 // @Security     ApiKeyAuth
 // @Router       /comments/{id} [get]
 func (cc *CommentController) GetCommentByID(c *gin.Context) {
 	idParam := c.Param("id")
 
-	if cc.Log.RegisterLog(c, "Attempting to retrieve Comment with ID: "+idParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_GET_COMMENT_BY_ID
 	if !cc.Auth.CheckPermission(c, permissionId) {
 		_ = cc.Log.RegisterLog(c, "Access denied for GetCommentByID")
@@ -55,20 +50,20 @@ func (cc *CommentController) GetCommentByID(c *gin.Context) {
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Invalid comment ID format: "+idParam)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid comment ID")
 		return
 	}
 
-	comment, err := cc.Service.GetCommentByID(id)
+	comment, err := cc.Service.GetCommentByID(c.Request.Context(), id)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Error retrieving Comment with ID "+idParam+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving comment"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving comment")
 		return
 	}
 
 	if comment == nil {
 		_ = cc.Log.RegisterLog(c, "Comment with ID "+idParam+" not found")
-		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Comment not found")
 		return
 	}
 
@@ -83,6 +78,7 @@ func (cc *CommentController) GetCommentByID(c *gin.Context) {
 		ResidenceState: comment.ResidenceState,
 		ResidenceCity:  comment.ResidenceCity,
 		Comment:        comment.Comment,
+		Reviewed:       comment.Reviewed,
 	}
 
 	c.JSON(http.StatusOK, commentDTO)
@@ -94,27 +90,31 @@ func (cc *CommentController) GetCommentByID(c *gin.Context) {
 // @Tags         comments
 // @Accept       json
 // @Produce      json
-// @Success      200  {array}   dtos.GetCommentDTO       "List of all comments"
-// @Failure      401  {object}  models.ErrorResponse     "Unauthorized or permission denied"
-// @Failure      500  {object}  models.ErrorResponse     "Failed to fetch comments or register log"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[dtos.GetCommentDTO]       "List of all comments"
+// @Failure      401  {object}  dtos.ErrorResponse     "Unauthorized or permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse     "Failed to fetch comments or register log"
 // @Security     ApiKeyAuth
 // @Router       /comments [get]
 func (cc *CommentController) GetAllComments(c *gin.Context) {
-	if err := cc.Log.RegisterLog(c, "Attempting to retrieve all comments"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_GET_ALL_COMMENTS
 	if !cc.Auth.CheckPermission(c, permissionId) {
 		_ = cc.Log.RegisterLog(c, "Access denied for GetAllComments")
 		return
 	}
 
-	comments, err := cc.Service.GetAllComments()
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid pagination for GetAllComments: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	comments, total, err := cc.Service.GetAllComments(c.Request.Context(), pagination)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Error retrieving all comments: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Failed to fetch comments")
 		return
 	}
 
@@ -129,12 +129,13 @@ func (cc *CommentController) GetAllComments(c *gin.Context) {
 			ResidenceState: comment.ResidenceState,
 			ResidenceCity:  comment.ResidenceCity,
 			Comment:        comment.Comment,
+			Reviewed:       comment.Reviewed,
 		})
 	}
 
 	_ = cc.Log.RegisterLog(c, "Successfully retrieved all comments")
 
-	c.JSON(http.StatusOK, commentsDTO)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(commentsDTO, pagination, total))
 }
 
 // SearchCommentsByEmail godoc
@@ -144,18 +145,15 @@ func (cc *CommentController) GetAllComments(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        email  query     string                  true  "Email address to search comments by"
-// @Success      200    {array}   dtos.GetCommentDTO      "List of matching comments"
-// @Failure      400    {object}  models.ErrorResponse    "Email parameter is required"
-// @Failure      401    {object}  models.ErrorResponse    "Unauthorized or permission denied"
-// @Failure      500    {object}  models.ErrorResponse    "Failed to search comments or register log"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200    {object}  dtos.PageDTO[dtos.GetCommentDTO]      "List of matching comments"
+// @Failure      400    {object}  dtos.ErrorResponse    "Email parameter is required"
+// @Failure      401    {object}  dtos.ErrorResponse    "Unauthorized or permission denied"
+// @Failure      500    {object}  dtos.ErrorResponse    "Failed to search comments or register log"
 // @Security     ApiKeyAuth
 // @Router       /comments/searchByEmail [get]
 func (cc *CommentController) SearchCommentsByEmail(c *gin.Context) {
-	if err := cc.Log.RegisterLog(c, "Attempting to search comments by email"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_SEARCH_COMMENTS_BY_EMAIL
 	if !cc.Auth.CheckPermission(c, permissionId) {
 		_ = cc.Log.RegisterLog(c, "Access denied for SearchCommentsByEmail")
@@ -165,14 +163,21 @@ func (cc *CommentController) SearchCommentsByEmail(c *gin.Context) {
 	email := c.Query("email")
 	if email == "" {
 		_ = cc.Log.RegisterLog(c, "Missing 'email' query parameter")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Email parameter is required"})
+		utilities.RespondError(c, http.StatusBadRequest, "Email parameter is required")
 		return
 	}
 
-	comments, err := cc.Service.SearchCommentsByEmail(email)
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid pagination for SearchCommentsByEmail: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	comments, total, err := cc.Service.SearchCommentsByEmail(c.Request.Context(), email, pagination)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Error searching comments by email '"+email+"': "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search comments"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Failed to search comments")
 		return
 	}
 
@@ -187,12 +192,13 @@ func (cc *CommentController) SearchCommentsByEmail(c *gin.Context) {
 			ResidenceState: comment.ResidenceState,
 			ResidenceCity:  comment.ResidenceCity,
 			Comment:        comment.Comment,
+			Reviewed:       comment.Reviewed,
 		})
 	}
 
 	_ = cc.Log.RegisterLog(c, "Successfully searched comments by email: "+email)
 
-	c.JSON(http.StatusOK, commentsDTO)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(commentsDTO, pagination, total))
 }
 
 // CreateComment godoc
@@ -203,17 +209,12 @@ func (cc *CommentController) SearchCommentsByEmail(c *gin.Context) {
 // @Produce      json
 // @Param        comment  body      dtos.CreateCommentDTO  true  "Comment data to create"
 // @Success      201      {object}  dtos.GetCommentDTO     "Created comment"
-// @Failure      400      {object}  models.ErrorResponse   "Invalid request data"
-// @Failure      401      {object}  models.ErrorResponse   "Unauthorized or permission denied"
-// @Failure      500      {object}  models.ErrorResponse   "Failed to create comment or register log"
+// @Failure      400      {object}  dtos.ErrorResponse   "Invalid request data"
+// @Failure      401      {object}  dtos.ErrorResponse   "Unauthorized or permission denied"
+// @Failure      500      {object}  dtos.ErrorResponse   "Failed to create comment or register log"
 // @Security     ApiKeyAuth
 // @Router       /comments [post]
 func (cc *CommentController) CreateComment(c *gin.Context) {
-	if err := cc.Log.RegisterLog(c, "Attempting to create a comment"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_CREATE_COMMENT
 	if !cc.Auth.CheckPermission(c, permissionId) {
 		_ = cc.Log.RegisterLog(c, "Access denied for CreateComment")
@@ -223,7 +224,7 @@ func (cc *CommentController) CreateComment(c *gin.Context) {
 	var dto dtos.CreateCommentDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = cc.Log.RegisterLog(c, "Invalid input for CreateComment: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utilities.RespondValidationError(c, "Invalid request body", err)
 		return
 	}
 
@@ -237,10 +238,10 @@ func (cc *CommentController) CreateComment(c *gin.Context) {
 		Comment:        dto.Comment,
 	}
 
-	createdComment, err := cc.Service.CreateComment(comment)
+	createdComment, err := cc.Service.CreateComment(c.Request.Context(), comment)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Error creating comment: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Failed to create comment")
 		return
 	}
 
@@ -253,6 +254,7 @@ func (cc *CommentController) CreateComment(c *gin.Context) {
 		ResidenceState: createdComment.ResidenceState,
 		ResidenceCity:  createdComment.ResidenceCity,
 		Comment:        createdComment.Comment,
+		Reviewed:       createdComment.Reviewed,
 	}
 
 	_ = cc.Log.RegisterLog(c, "Successfully created comment with ID: "+strconv.Itoa(createdComment.ID))
@@ -268,10 +270,10 @@ func (cc *CommentController) CreateComment(c *gin.Context) {
 // @Param        id       path      int                  true  "Comment ID"
 // @Param        comment  body      dtos.UpdateCommentDTO  true  "Updated comment data"
 // @Success      200      {object}  dtos.GetCommentDTO     "Updated comment"
-// @Failure      400      {object}  models.ErrorResponse   "Invalid ID or request data"
-// @Failure      401      {object}  models.ErrorResponse   "Unauthorized or permission denied"
-// @Failure      404      {object}  models.ErrorResponse   "Comment not found"
-// @Failure      500      {object}  models.ErrorResponse   "Internal server error or failed update"
+// @Failure      400      {object}  dtos.ErrorResponse   "Invalid ID or request data"
+// @Failure      401      {object}  dtos.ErrorResponse   "Unauthorized or permission denied"
+// @Failure      404      {object}  dtos.ErrorResponse   "Comment not found"
+// @Failure      500      {object}  dtos.ErrorResponse   "Internal server error or failed update"
 // @Security     ApiKeyAuth
 // @Router       /comments/{id} [put]
 func (cc *CommentController) UpdateComment(c *gin.Context) {
@@ -285,26 +287,26 @@ func (cc *CommentController) UpdateComment(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Invalid comment ID format")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid comment ID")
 		return
 	}
 
 	var dto dtos.UpdateCommentDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = cc.Log.RegisterLog(c, "Failed to bind JSON in UpdateComment: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utilities.RespondValidationError(c, "Invalid request body", err)
 		return
 	}
 
-	comment, err := cc.Service.GetCommentByID(id)
+	comment, err := cc.Service.GetCommentByID(c.Request.Context(), id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			_ = cc.Log.RegisterLog(c, "Comment with ID "+strconv.Itoa(id)+" not found")
-			c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+			utilities.RespondError(c, http.StatusNotFound, "Comment not found")
 			return
 		}
 		_ = cc.Log.RegisterLog(c, "Internal error retrieving comment with ID "+strconv.Itoa(id)+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
@@ -315,11 +317,14 @@ func (cc *CommentController) UpdateComment(c *gin.Context) {
 	comment.ResidenceState = dto.ResidenceState
 	comment.ResidenceCity = dto.ResidenceCity
 	comment.Comment = dto.Comment
+	if dto.Reviewed != nil {
+		comment.Reviewed = *dto.Reviewed
+	}
 
-	err = cc.Service.UpdateComment(comment)
+	err = cc.Service.UpdateComment(c.Request.Context(), comment)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Failed to update comment with ID "+strconv.Itoa(id)+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update comment"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Failed to update comment")
 		return
 	}
 
@@ -334,6 +339,7 @@ func (cc *CommentController) UpdateComment(c *gin.Context) {
 		ResidenceState: comment.ResidenceState,
 		ResidenceCity:  comment.ResidenceCity,
 		Comment:        comment.Comment,
+		Reviewed:       comment.Reviewed,
 	}
 
 	c.JSON(http.StatusOK, updatedCommentDTO)
@@ -346,11 +352,13 @@ func (cc *CommentController) UpdateComment(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        id       query     string               true  "ID to search for comments"
-// @Success      200      {array}   dtos.GetCommentDTO   "List of comments matching the ID"
-// @Failure      400      {object}  models.ErrorResponse "Invalid request parameters"
-// @Failure      401      {object}  models.ErrorResponse "Unauthorized or permission denied"
-// @Failure      404      {object}  models.ErrorResponse "No comments found for the given ID"
-// @Failure      500      {object}  models.ErrorResponse "Internal server error or failure in processing"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200      {object}  dtos.PageDTO[dtos.GetCommentDTO]   "List of comments matching the ID"
+// @Failure      400      {object}  dtos.ErrorResponse "Invalid request parameters"
+// @Failure      401      {object}  dtos.ErrorResponse "Unauthorized or permission denied"
+// @Failure      404      {object}  dtos.ErrorResponse "No comments found for the given ID"
+// @Failure      500      {object}  dtos.ErrorResponse "Internal server error or failure in processing"
 // @Security     ApiKeyAuth
 // @Router       /comments/searchByID [get]
 func (cc *CommentController) SearchCommentsByID(c *gin.Context) {
@@ -363,16 +371,23 @@ func (cc *CommentController) SearchCommentsByID(c *gin.Context) {
 		return
 	}
 
-	comments, err := cc.Service.SearchCommentsByID(query)
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid pagination for SearchCommentsByID: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	comments, total, err := cc.Service.SearchCommentsByID(c.Request.Context(), query, pagination)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Error retrieving comments with ID "+query+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving comments"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving comments")
 		return
 	}
 
-	if len(comments) == 0 {
+	if total == 0 {
 		_ = cc.Log.RegisterLog(c, "No comments found for ID "+query)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No comments found"})
+		utilities.RespondError(c, http.StatusNotFound, "No comments found")
 		return
 	}
 
@@ -387,11 +402,12 @@ func (cc *CommentController) SearchCommentsByID(c *gin.Context) {
 			ResidenceState: comment.ResidenceState,
 			ResidenceCity:  comment.ResidenceCity,
 			Comment:        comment.Comment,
+			Reviewed:       comment.Reviewed,
 		})
 	}
 
 	_ = cc.Log.RegisterLog(c, "Successfully retrieved comments with ID: "+query)
-	c.JSON(http.StatusOK, commentsDTO)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(commentsDTO, pagination, total))
 }
 
 // SearchCommentsByName godoc
@@ -401,11 +417,13 @@ func (cc *CommentController) SearchCommentsByID(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        name     query     string               true  "Name to search for comments"
-// @Success      200      {array}   dtos.GetCommentDTO   "List of comments matching the name"
-// @Failure      400      {object}  models.ErrorResponse "Invalid request parameters"
-// @Failure      401      {object}  models.ErrorResponse "Unauthorized or permission denied"
-// @Failure      404      {object}  models.ErrorResponse "No comments found for the given name"
-// @Failure      500      {object}  models.ErrorResponse "Internal server error or failure in processing"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200      {object}  dtos.PageDTO[dtos.GetCommentDTO]   "List of comments matching the name"
+// @Failure      400      {object}  dtos.ErrorResponse "Invalid request parameters"
+// @Failure      401      {object}  dtos.ErrorResponse "Unauthorized or permission denied"
+// @Failure      404      {object}  dtos.ErrorResponse "No comments found for the given name"
+// @Failure      500      {object}  dtos.ErrorResponse "Internal server error or failure in processing"
 // @Security     ApiKeyAuth
 // @Router       /comments/searchByName [get]
 func (cc *CommentController) SearchCommentsByName(c *gin.Context) {
@@ -418,16 +436,23 @@ func (cc *CommentController) SearchCommentsByName(c *gin.Context) {
 		return
 	}
 
-	comments, err := cc.Service.SearchCommentsByName(query)
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid pagination for SearchCommentsByName: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	comments, total, err := cc.Service.SearchCommentsByName(c.Request.Context(), query, pagination)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Error retrieving comments with name "+query+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving comments"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving comments")
 		return
 	}
 
-	if len(comments) == 0 {
+	if total == 0 {
 		_ = cc.Log.RegisterLog(c, "No comments found for name "+query)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No comments found"})
+		utilities.RespondError(c, http.StatusNotFound, "No comments found")
 		return
 	}
 
@@ -442,9 +467,10 @@ func (cc *CommentController) SearchCommentsByName(c *gin.Context) {
 			ResidenceState: comment.ResidenceState,
 			ResidenceCity:  comment.ResidenceCity,
 			Comment:        comment.Comment,
+			Reviewed:       comment.Reviewed,
 		})
 	}
 
 	_ = cc.Log.RegisterLog(c, "Successfully retrieved comments with name: "+query)
-	c.JSON(http.StatusOK, commentsDTO)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(commentsDTO, pagination, total))
 }
@@ -0,0 +1,176 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type CreditNoteController struct {
+	Service *services.CreditNoteService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewCreditNoteController(service *services.CreditNoteService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *CreditNoteController {
+	return &CreditNoteController{Service: service, Auth: auth, Log: log}
+}
+
+// CancelInvoice godoc
+// @Summary      Cancel an invoice
+// @Description  Cancels the invoice with a credit note that records the reason and returns to stock every invoiced unit not already returned with POST /invoices/{id}/returns, with a credit_note stock movement per item. An invoice can only be cancelled once. Cancelled invoices are left out of sales reports and payment reminders.
+// @Tags         credit-notes
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int                    true  "Invoice ID"
+// @Param        cancel  body      dtos.CancelInvoiceDTO  true  "Reason for the cancellation"
+// @Success      201     {object}  models.CreditNote  "Credit note"
+// @Failure      400     {object}  dtos.ErrorResponse  "Invalid request data"
+// @Failure      403     {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404     {object}  dtos.ErrorResponse  "Invoice not found"
+// @Failure      409     {object}  dtos.ErrorResponse  "The invoice is already cancelled"
+// @Failure      500     {object}  dtos.ErrorResponse  "Error cancelling invoice"
+// @Security     ApiKeyAuth
+// @Router       /invoices/{id}/cancel [post]
+func (cc *CreditNoteController) CancelInvoice(c *gin.Context) {
+	permissionId := config.PERMISSION_CANCEL_INVOICE
+	if !cc.Auth.CheckPermission(c, permissionId) {
+		_ = cc.Log.RegisterLog(c, "Access denied for CancelInvoice")
+		return
+	}
+
+	idStr := c.Param("id")
+	invoiceID, err := strconv.Atoi(idStr)
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid invoice ID: "+idStr)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid invoice ID")
+		return
+	}
+
+	var dto dtos.CancelInvoiceDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid cancellation data: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	note, err := cc.Service.CancelInvoice(c.Request.Context(), invoiceID, dto.Reason)
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Error cancelling invoice with ID "+idStr+": "+err.Error())
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utilities.RespondError(c, http.StatusNotFound, "Invoice not found")
+		case errors.Is(err, services.ErrInvalidCreditNote):
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, services.ErrInvoiceAlreadyCancelled):
+			utilities.RespondError(c, http.StatusConflict, err.Error())
+		default:
+			utilities.RespondError(c, http.StatusInternalServerError, "Error cancelling invoice")
+		}
+		return
+	}
+
+	_ = cc.Log.RegisterLog(c, "Successfully cancelled invoice with ID "+idStr+" with credit note "+strconv.Itoa(note.ID))
+	c.JSON(http.StatusCreated, note)
+}
+
+// GetCreditNotes godoc
+// @Summary      List credit notes
+// @Description  Returns a page of credit notes, newest first.
+// @Tags         credit-notes
+// @Produce      json
+// @Param        invoiceId  query  int  false  "Invoice ID"
+// @Param        page       query  int  false  "Page number (default 1)"
+// @Param        pageSize   query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[models.CreditNote]  "Page of credit notes"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid filter"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving credit notes"
+// @Security     ApiKeyAuth
+// @Router       /credit-notes [get]
+func (cc *CreditNoteController) GetCreditNotes(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_CREDIT_NOTES
+	if !cc.Auth.CheckPermission(c, permissionId) {
+		_ = cc.Log.RegisterLog(c, "Access denied for GetCreditNotes")
+		return
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid pagination: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var invoiceID *int
+	if invoiceStr := c.Query("invoiceId"); invoiceStr != "" {
+		id, err := strconv.Atoi(invoiceStr)
+		if err != nil {
+			_ = cc.Log.RegisterLog(c, "Invalid invoiceId: "+invoiceStr)
+			utilities.RespondError(c, http.StatusBadRequest, "Invalid 'invoiceId'")
+			return
+		}
+		invoiceID = &id
+	}
+
+	notes, total, err := cc.Service.GetCreditNotes(c.Request.Context(), invoiceID, pagination)
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Error retrieving credit notes: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving credit notes")
+		return
+	}
+
+	_ = cc.Log.RegisterLog(c, "Successfully retrieved credit notes")
+	c.JSON(http.StatusOK, dtos.NewPageDTO(notes, pagination, total))
+}
+
+// GetCreditNoteByID godoc
+// @Summary      Get a credit note
+// @Description  Returns a credit note with the units it returned to stock.
+// @Tags         credit-notes
+// @Produce      json
+// @Param        id   path      int  true  "Credit note ID"
+// @Success      200  {object}  models.CreditNote  "Credit note"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid credit note ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Credit note not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving credit note"
+// @Security     ApiKeyAuth
+// @Router       /credit-notes/{id} [get]
+func (cc *CreditNoteController) GetCreditNoteByID(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_CREDIT_NOTES
+	if !cc.Auth.CheckPermission(c, permissionId) {
+		_ = cc.Log.RegisterLog(c, "Access denied for GetCreditNoteByID")
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid credit note ID: "+idStr)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid credit note ID")
+		return
+	}
+
+	note, err := cc.Service.GetCreditNoteByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = cc.Log.RegisterLog(c, "Credit note not found with ID: "+idStr)
+			utilities.RespondError(c, http.StatusNotFound, "Credit note not found")
+			return
+		}
+		_ = cc.Log.RegisterLog(c, "Error retrieving credit note with ID "+idStr+": "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving credit note")
+		return
+	}
+
+	_ = cc.Log.RegisterLog(c, "Successfully retrieved credit note with ID: "+idStr)
+	c.JSON(http.StatusOK, note)
+}
@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type CurrencyController struct {
+	Service *services.CurrencyService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewCurrencyController(service *services.CurrencyService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *CurrencyController {
+	return &CurrencyController{Service: service, Auth: auth, Log: log}
+}
+
+// GetCurrencies godoc
+// @Summary      List currencies
+// @Description  Returns the currencies items can be priced in and invoices issued in, with their exchange rate to the base currency.
+// @Tags         currencies
+// @Produce      json
+// @Success      200 {array}  models.Currency    "Currencies ordered by code"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      500 {object} dtos.ErrorResponse "Error retrieving currencies"
+// @Security     ApiKeyAuth
+// @Router       /currencies [get]
+func (cc *CurrencyController) GetCurrencies(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_CURRENCIES
+	if !cc.Auth.CheckPermission(c, permissionId) {
+		_ = cc.Log.RegisterLog(c, "Access denied for GetCurrencies")
+		return
+	}
+
+	currencies, err := cc.Service.GetCurrencies(c.Request.Context())
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Error retrieving currencies: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving currencies")
+		return
+	}
+
+	_ = cc.Log.RegisterLog(c, "Successfully retrieved currencies")
+	c.JSON(http.StatusOK, currencies)
+}
+
+// GetCurrencyByCode godoc
+// @Summary      Get a currency
+// @Tags         currencies
+// @Produce      json
+// @Param        code path     string true "ISO 4217 currency code"
+// @Success      200 {object} models.Currency    "Currency"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "Currency not found"
+// @Failure      500 {object} dtos.ErrorResponse "Error retrieving currency"
+// @Security     ApiKeyAuth
+// @Router       /currencies/{code} [get]
+func (cc *CurrencyController) GetCurrencyByCode(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_CURRENCIES
+	if !cc.Auth.CheckPermission(c, permissionId) {
+		_ = cc.Log.RegisterLog(c, "Access denied for GetCurrencyByCode")
+		return
+	}
+
+	code := c.Param("code")
+	currency, err := cc.Service.GetCurrencyByCode(c.Request.Context(), code)
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Error retrieving currency "+code+": "+err.Error())
+		cc.respondError(c, err, "Error retrieving currency")
+		return
+	}
+
+	_ = cc.Log.RegisterLog(c, "Successfully retrieved currency "+currency.Code)
+	c.JSON(http.StatusOK, currency)
+}
+
+// CreateCurrency godoc
+// @Summary      Create a currency
+// @Description  Adds a currency with its exchange rate: how much one unit is worth in the base currency (COP).
+// @Tags         currencies
+// @Accept       json
+// @Produce      json
+// @Param        currency body     dtos.CurrencyDTO   true "Currency"
+// @Success      201 {object} models.Currency    "Created currency"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid currency"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      409 {object} dtos.ErrorResponse "A currency with that code already exists"
+// @Failure      500 {object} dtos.ErrorResponse "Error creating currency"
+// @Security     ApiKeyAuth
+// @Router       /currencies [post]
+func (cc *CurrencyController) CreateCurrency(c *gin.Context) {
+	permissionId := config.PERMISSION_CREATE_CURRENCY
+	if !cc.Auth.CheckPermission(c, permissionId) {
+		_ = cc.Log.RegisterLog(c, "Access denied for CreateCurrency")
+		return
+	}
+
+	var dto dtos.CurrencyDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid currency: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	currency, err := cc.Service.CreateCurrency(c.Request.Context(), dto)
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Error creating currency: "+err.Error())
+		cc.respondError(c, err, "Error creating currency")
+		return
+	}
+
+	_ = cc.Log.RegisterLog(c, "Successfully created currency "+currency.Code)
+	c.JSON(http.StatusCreated, currency)
+}
+
+// UpdateCurrency godoc
+// @Summary      Update a currency
+// @Description  Changes the name, symbol and exchange rate of a currency. New invoices and price conversions use the new rate; issued invoices keep the rate they were issued with. The base currency's rate is always 1.
+// @Tags         currencies
+// @Accept       json
+// @Produce      json
+// @Param        code     path     string             true "ISO 4217 currency code"
+// @Param        currency body     dtos.CurrencyDTO   true "Currency"
+// @Success      200 {object} models.Currency    "Updated currency"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid currency"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "Currency not found"
+// @Failure      500 {object} dtos.ErrorResponse "Error updating currency"
+// @Security     ApiKeyAuth
+// @Router       /currencies/{code} [put]
+func (cc *CurrencyController) UpdateCurrency(c *gin.Context) {
+	permissionId := config.PERMISSION_UPDATE_CURRENCY
+	if !cc.Auth.CheckPermission(c, permissionId) {
+		_ = cc.Log.RegisterLog(c, "Access denied for UpdateCurrency")
+		return
+	}
+
+	code := c.Param("code")
+	var dto dtos.CurrencyDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid currency: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	currency, err := cc.Service.UpdateCurrency(c.Request.Context(), code, dto)
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Error updating currency "+code+": "+err.Error())
+		cc.respondError(c, err, "Error updating currency")
+		return
+	}
+
+	_ = cc.Log.RegisterLog(c, "Successfully updated currency "+currency.Code)
+	c.JSON(http.StatusOK, currency)
+}
+
+func (cc *CurrencyController) respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utilities.RespondError(c, http.StatusNotFound, "Currency not found")
+	case errors.Is(err, services.ErrInvalidCurrency):
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrCurrencyTaken):
+		utilities.RespondError(c, http.StatusConflict, err.Error())
+	default:
+		utilities.RespondError(c, http.StatusInternalServerError, message)
+	}
+}
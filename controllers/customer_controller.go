@@ -1,8 +1,12 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
 	"totesbackend/dtos"
@@ -10,17 +14,19 @@ import (
 	"totesbackend/services"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type CustomerController struct {
 	Service *services.CustomerService
 	Auth    *utilities.AuthorizationUtil
 	Log     *utilities.LogUtil
+	Audit   *utilities.AuditUtil
 }
 
 func NewCustomerController(service *services.CustomerService, auth *utilities.AuthorizationUtil,
-	log *utilities.LogUtil) *CustomerController {
-	return &CustomerController{Service: service, Auth: auth, Log: log}
+	log *utilities.LogUtil, audit *utilities.AuditUtil) *CustomerController {
+	return &CustomerController{Service: service, Auth: auth, Log: log, Audit: audit}
 }
 
 // GetAllCustomers godoc
@@ -29,33 +35,44 @@ func NewCustomerController(service *services.CustomerService, auth *utilities.Au
 // @Tags         customers
 // @Accept       json
 // @Produce      json
-// @Success      200      {array}   models.Customer         "List of all customers"
-// @Failure      400      {object}  models.ErrorResponse    "Invalid request parameters"
-// @Failure      401      {object}  models.ErrorResponse    "Unauthorized or permission denied"
-// @Failure      500      {object}  models.ErrorResponse    "Internal server error or failure in retrieving customers"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Param        includeDeleted  query  bool  false  "Include deleted customers (default false)"
+// @Success      200      {object}  dtos.PageDTO[models.Customer]         "List of all customers"
+// @Failure      400      {object}  dtos.ErrorResponse    "Invalid request parameters"
+// @Failure      401      {object}  dtos.ErrorResponse    "Unauthorized or permission denied"
+// @Failure      500      {object}  dtos.ErrorResponse    "Internal server error or failure in retrieving customers"
 // @Security     ApiKeyAuth
 // @Router       /customers [get]
 func (cc *CustomerController) GetAllCustomers(c *gin.Context) {
-	if cc.Log.RegisterLog(c, "Attempting to retrieve all customers") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_GET_ALL_CUSTOMERS
 	if !cc.Auth.CheckPermission(c, permissionId) {
 		_ = cc.Log.RegisterLog(c, "Access denied for GetAllCustomers")
 		return
 	}
 
-	customers, err := cc.Service.GetAllCustomers()
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid pagination for GetAllCustomers: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	includeDeleted, err := parseIncludeDeleted(c)
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid includeDeleted parameter for GetAllCustomers: "+c.Query("includeDeleted"))
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	customers, total, err := cc.Service.GetAllCustomers(c.Request.Context(), includeDeleted, pagination)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Error retrieving customers: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving customers"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving customers")
 		return
 	}
 
 	_ = cc.Log.RegisterLog(c, "Successfully retrieved all customers")
-	c.JSON(http.StatusOK, customers)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(customers, pagination, total))
 }
 
 // GetCustomerByID godoc
@@ -65,19 +82,15 @@ func (cc *CustomerController) GetAllCustomers(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        id       path      int                  true  "Customer ID"
-// @Success      200      {object}  models.Customer      "Customer data"
-// @Failure      400      {object}  models.ErrorResponse "Invalid customer ID"
-// @Failure      401      {object}  models.ErrorResponse "Unauthorized or permission denied"
-// @Failure      404      {object}  models.ErrorResponse "Customer not found"
-// @Failure      500      {object}  models.ErrorResponse "Internal server error or failure in retrieving customer"
+// @Success      200      {object}  dtos.ResourceDTO[models.Customer]  "Customer data with links to its appointments, purchase orders and dependencies"
+// @Failure      400      {object}  dtos.ErrorResponse "Invalid customer ID"
+// @Failure      401      {object}  dtos.ErrorResponse "Unauthorized or permission denied"
+// @Failure      404      {object}  dtos.ErrorResponse "Customer not found"
+// @Failure      500      {object}  dtos.ErrorResponse "Internal server error or failure in retrieving customer"
 // @Security     ApiKeyAuth
 // @Router       /customers/{id} [get]
 func (cc *CustomerController) GetCustomerByID(c *gin.Context) {
 	idParam := c.Param("id")
-	if cc.Log.RegisterLog(c, "Attempting to retrieve customer with ID: "+idParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
 
 	permissionId := config.PERMISSION_GET_CUSTOMER_BY_ID
 	if !cc.Auth.CheckPermission(c, permissionId) {
@@ -88,19 +101,30 @@ func (cc *CustomerController) GetCustomerByID(c *gin.Context) {
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Invalid customer ID provided: "+idParam)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid customer ID"})
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid customer ID")
 		return
 	}
 
-	customer, err := cc.Service.GetCustomerByID(id)
+	customer, err := cc.Service.GetCustomerByID(c.Request.Context(), id)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Customer not found with ID: "+idParam)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Customer not found")
 		return
 	}
 
 	_ = cc.Log.RegisterLog(c, "Successfully retrieved customer with ID: "+idParam)
-	c.JSON(http.StatusOK, customer)
+	c.JSON(http.StatusOK, dtos.NewResourceDTO(customer, customerLinks(customer.ID)))
+}
+
+func customerLinks(id int) dtos.LinksDTO {
+	self := utilities.ResourceLink("customers", id)
+	return dtos.LinksDTO{
+		"self":                     self,
+		"appointments":             "/appointments/customer/" + strconv.Itoa(id),
+		"purchase_orders":          "/purchase-orders/customers/" + strconv.Itoa(id),
+		"dependencies":             self + "/dependencies",
+		"notification_preferences": self + "/notification-preferences",
+	}
 }
 
 // GetCustomerByCustomerID godoc
@@ -111,17 +135,13 @@ func (cc *CustomerController) GetCustomerByID(c *gin.Context) {
 // @Produce      json
 // @Param        customerID   path      string               true  "Customer ID"
 // @Success      200          {object}  models.Customer      "Customer data"
-// @Failure      401          {object}  models.ErrorResponse "Unauthorized or permission denied"
-// @Failure      404          {object}  models.ErrorResponse "Customer not found"
-// @Failure      500          {object}  models.ErrorResponse "Internal server error or failure in retrieving customer"
+// @Failure      401          {object}  dtos.ErrorResponse "Unauthorized or permission denied"
+// @Failure      404          {object}  dtos.ErrorResponse "Customer not found"
+// @Failure      500          {object}  dtos.ErrorResponse "Internal server error or failure in retrieving customer"
 // @Security     ApiKeyAuth
 // @Router       /customers/customerID/{customerID} [get]
 func (cc *CustomerController) GetCustomerByCustomerID(c *gin.Context) {
 	customerID := c.Param("customerID")
-	if cc.Log.RegisterLog(c, "Attempting to retrieve customer with customerID: "+customerID) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
 
 	permissionId := config.PERMISSION_GET_CUSTOMER_BY_CUSTOMERID
 	if !cc.Auth.CheckPermission(c, permissionId) {
@@ -129,10 +149,10 @@ func (cc *CustomerController) GetCustomerByCustomerID(c *gin.Context) {
 		return
 	}
 
-	customer, err := cc.Service.GetCustomerByCustomerID(customerID)
+	customer, err := cc.Service.GetCustomerByCustomerID(c.Request.Context(), customerID)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Customer not found with customerID: "+customerID)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Customer not found")
 		return
 	}
 
@@ -142,23 +162,21 @@ func (cc *CustomerController) GetCustomerByCustomerID(c *gin.Context) {
 
 // CreateCustomer godoc
 // @Summary      Create a new customer
-// @Description  Creates a new customer record in the system. Requires appropriate permission.
+// @Description  Creates a new customer record in the system. Requires appropriate permission. If the customer looks like an existing one (same document ignoring dots and dashes, same email, or a similar full name sharing a phone number) it responds 409 with code DUPLICATE and the candidates in details (dtos.CustomerDuplicatesDTO); when details.canOverride is true, repeat the request with force=true to create it anyway. With geocoding enabled the address is replaced by the normalized one and its coordinates are stored.
 // @Tags         customers
 // @Accept       json
 // @Produce      json
 // @Param        customer  body      dtos.CreateCustomerDTO  true  "New customer data"
+// @Param        force     query     bool                    false "Create the customer even if it looks like a duplicate"
 // @Success      201       {object}  models.Customer         "The created customer"
-// @Failure      400       {object}  models.ErrorResponse    "Invalid input data (JSON format or missing fields)"
-// @Failure      401       {object}  models.ErrorResponse    "Unauthorized or permission denied"
-// @Failure      500       {object}  models.ErrorResponse    "Internal server error or failure in creating customer"
+// @Failure      400       {object}  dtos.ErrorResponse    "Invalid input data (JSON format or missing fields)"
+// @Failure      401       {object}  dtos.ErrorResponse    "Unauthorized or permission denied"
+// @Failure      409       {object}  dtos.ErrorResponse    "Likely duplicate of an existing customer"
+// @Failure      422       {object}  dtos.ErrorResponse    "The address could not be found (only with GEOCODING_REJECT_UNKNOWN)"
+// @Failure      500       {object}  dtos.ErrorResponse    "Internal server error or failure in creating customer"
 // @Security     ApiKeyAuth
 // @Router       /customers [post]
 func (cc *CustomerController) CreateCustomer(c *gin.Context) {
-	if cc.Log.RegisterLog(c, "Attempting to create new customer") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_CREATE_CUSTOMER
 	if !cc.Auth.CheckPermission(c, permissionId) {
 		_ = cc.Log.RegisterLog(c, "Access denied for CreateCustomer")
@@ -168,10 +186,20 @@ func (cc *CustomerController) CreateCustomer(c *gin.Context) {
 	var dto dtos.CreateCustomerDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = cc.Log.RegisterLog(c, "Invalid JSON format in CreateCustomer request")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		utilities.RespondValidationError(c, "Invalid JSON format", err)
 		return
 	}
 
+	force := false
+	if forceStr := c.Query("force"); forceStr != "" {
+		var err error
+		if force, err = strconv.ParseBool(forceStr); err != nil {
+			_ = cc.Log.RegisterLog(c, "Invalid force parameter: "+forceStr)
+			utilities.RespondError(c, http.StatusBadRequest, "Invalid 'force' parameter")
+			return
+		}
+	}
+
 	customer := models.Customer{
 		CustomerName:     dto.CustomerName,
 		CustomerId:       dto.CustomerId,
@@ -182,12 +210,34 @@ func (cc *CustomerController) CreateCustomer(c *gin.Context) {
 		Email:            dto.Email,
 		LastName:         dto.LastName,
 		IdentifierTypeID: dto.IdentifierTypeID,
+		TextChannel:      dto.TextChannel,
 	}
 
-	createdCustomer, err := cc.Service.CreateCustomer(customer)
+	duplicates, err := cc.Service.FindDuplicateCustomers(c.Request.Context(), customer)
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Error checking for duplicate customers: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error creating customer")
+		return
+	}
+	if duplicates != nil && (!force || !duplicates.CanOverride) {
+		_ = cc.Log.RegisterLog(c, fmt.Sprintf("Customer %s not created: %d likely duplicates", customer.CustomerId, len(duplicates.Candidates)))
+		message := "Customer looks like an existing one; use force=true to create it anyway"
+		if !duplicates.CanOverride {
+			message = "Another customer already has this document number or email"
+		}
+		utilities.RespondErrorWithDetails(c, http.StatusConflict, utilities.ErrCodeDuplicate, message, duplicates)
+		return
+	}
+
+	createdCustomer, err := cc.Service.CreateCustomer(c.Request.Context(), customer)
+	if errors.Is(err, services.ErrAddressNotFound) {
+		_ = cc.Log.RegisterLog(c, "Customer "+customer.CustomerId+" not created: address not found")
+		utilities.RespondError(c, http.StatusUnprocessableEntity, "The address could not be found")
+		return
+	}
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Error creating customer: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating customer"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error creating customer")
 		return
 	}
 
@@ -204,18 +254,15 @@ func (cc *CustomerController) CreateCustomer(c *gin.Context) {
 // @Param        id        path      int                    true  "Customer ID"
 // @Param        customer  body      dtos.UpdateCustomerDTO  true  "Updated customer data"
 // @Success      200       {object}  models.Customer         "The updated customer"
-// @Failure      400       {object}  models.ErrorResponse    "Invalid input data (ID format or JSON format)"
-// @Failure      401       {object}  models.ErrorResponse    "Unauthorized or permission denied"
-// @Failure      404       {object}  models.ErrorResponse    "Customer not found"
-// @Failure      500       {object}  models.ErrorResponse    "Internal server error or failure in updating customer"
+// @Failure      400       {object}  dtos.ErrorResponse    "Invalid input data (ID format or JSON format)"
+// @Failure      401       {object}  dtos.ErrorResponse    "Unauthorized or permission denied"
+// @Failure      404       {object}  dtos.ErrorResponse    "Customer not found"
+// @Failure      500       {object}  dtos.ErrorResponse    "Internal server error or failure in updating customer"
+// @Failure      409       {object}  dtos.ErrorResponse    "Version conflict: the record changed since it was read"
+// @Failure      422       {object}  dtos.ErrorResponse    "The address could not be found (only with GEOCODING_REJECT_UNKNOWN)"
 // @Security     ApiKeyAuth
 // @Router       /customers/{id} [put]
 func (cc *CustomerController) UpdateCustomer(c *gin.Context) {
-	if cc.Log.RegisterLog(c, "Attempting to update customer") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_UPDATE_CUSTOMER
 	if !cc.Auth.CheckPermission(c, permissionId) {
 		_ = cc.Log.RegisterLog(c, "Access denied for UpdateCustomer")
@@ -225,17 +272,89 @@ func (cc *CustomerController) UpdateCustomer(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Invalid customer ID format in URL parameter")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid customer ID"})
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid customer ID")
 		return
 	}
 
 	var dto dtos.UpdateCustomerDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = cc.Log.RegisterLog(c, "Invalid JSON format in UpdateCustomer request")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		utilities.RespondValidationError(c, "Invalid JSON format", err)
+		return
+	}
+
+	before, err := cc.Service.GetCustomerByID(c.Request.Context(), id)
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Customer not found with ID: "+strconv.Itoa(id))
+		utilities.RespondError(c, http.StatusNotFound, "Customer not found")
+		return
+	}
+
+	cc.saveCustomer(c, id, before, dto)
+}
+
+// PatchCustomer godoc
+// @Summary      Partially update a customer
+// @Description  Applies a JSON merge patch (RFC 7396) to a customer: only the supplied fields change and null clears a field. The version field is required.
+// @Tags         customers
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int                     true  "Customer ID"
+// @Param        customer  body      dtos.UpdateCustomerDTO  true  "Fields to change"
+// @Success      200       {object}  models.Customer         "The updated customer"
+// @Failure      400       {object}  dtos.ErrorResponse      "Invalid ID or patch document"
+// @Failure      401       {object}  dtos.ErrorResponse      "Unauthorized or permission denied"
+// @Failure      404       {object}  dtos.ErrorResponse      "Customer not found"
+// @Failure      409       {object}  dtos.ErrorResponse      "Version conflict: the record changed since it was read"
+// @Failure      422       {object}  dtos.ErrorResponse      "The address could not be found (only with GEOCODING_REJECT_UNKNOWN)"
+// @Failure      500       {object}  dtos.ErrorResponse      "Internal server error or failure in updating customer"
+// @Security     ApiKeyAuth
+// @Router       /customers/{id} [patch]
+func (cc *CustomerController) PatchCustomer(c *gin.Context) {
+	permissionId := config.PERMISSION_UPDATE_CUSTOMER
+	if !cc.Auth.CheckPermission(c, permissionId) {
+		_ = cc.Log.RegisterLog(c, "Access denied for PatchCustomer")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid customer ID format in URL parameter")
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid customer ID")
+		return
+	}
+
+	before, err := cc.Service.GetCustomerByID(c.Request.Context(), id)
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Customer not found with ID: "+strconv.Itoa(id))
+		utilities.RespondError(c, http.StatusNotFound, "Customer not found")
+		return
+	}
+
+	// la versión no se toma del estado actual: el cliente debe enviar la que leyó
+	current := dtos.UpdateCustomerDTO{
+		CustomerName:     before.CustomerName,
+		CustomerId:       before.CustomerId,
+		IsBusiness:       before.IsBusiness,
+		Address:          before.Address,
+		PhoneNumbers:     before.PhoneNumbers,
+		CustomerState:    before.CustomerState,
+		Email:            before.Email,
+		LastName:         before.LastName,
+		IdentifierTypeID: before.IdentifierTypeID,
+		TextChannel:      before.TextChannel,
+	}
+	var dto dtos.UpdateCustomerDTO
+	if err := utilities.BindMergePatch(c, current, &dto); err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid patch document in PatchCustomer request: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid patch document", err)
 		return
 	}
 
+	cc.saveCustomer(c, id, before, dto)
+}
+
+func (cc *CustomerController) saveCustomer(c *gin.Context, id int, before *models.Customer, dto dtos.UpdateCustomerDTO) {
 	customer := models.Customer{
 		ID:               id,
 		CustomerName:     dto.CustomerName,
@@ -247,15 +366,28 @@ func (cc *CustomerController) UpdateCustomer(c *gin.Context) {
 		Email:            dto.Email,
 		LastName:         dto.LastName,
 		IdentifierTypeID: dto.IdentifierTypeID,
+		TextChannel:      dto.TextChannel,
+		Version:          *dto.Version,
 	}
 
-	err = cc.Service.UpdateCustomer(&customer)
-	if err != nil {
+	if err := cc.Service.UpdateCustomer(c.Request.Context(), &customer, before); err != nil {
 		_ = cc.Log.RegisterLog(c, "Error updating customer with ID "+strconv.Itoa(id)+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating customer"})
+		if errors.Is(err, services.ErrVersionConflict) {
+			utilities.RespondVersionConflict(c, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrAddressNotFound) {
+			utilities.RespondError(c, http.StatusUnprocessableEntity, "The address could not be found")
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error updating customer")
 		return
 	}
 
+	if err := cc.Audit.RecordChange(c, services.AUDIT_ENTITY_CUSTOMER, strconv.Itoa(id), services.AUDIT_ACTION_UPDATE, before, customer); err != nil {
+		_ = cc.Log.RegisterLog(c, "Error recording audit trail for customer with ID "+strconv.Itoa(id)+": "+err.Error())
+	}
+
 	_ = cc.Log.RegisterLog(c, "Customer updated successfully with ID: "+strconv.Itoa(id))
 	c.JSON(http.StatusOK, customer)
 }
@@ -268,17 +400,12 @@ func (cc *CustomerController) UpdateCustomer(c *gin.Context) {
 // @Produce      json
 // @Param        email     path      string               true  "Customer email"
 // @Success      200       {object}  models.Customer      "Customer data"
-// @Failure      401       {object}  models.ErrorResponse "Unauthorized or permission denied"
-// @Failure      404       {object}  models.ErrorResponse "Customer not found"
-// @Failure      500       {object}  models.ErrorResponse "Internal server error or failure in retrieving customer"
+// @Failure      401       {object}  dtos.ErrorResponse "Unauthorized or permission denied"
+// @Failure      404       {object}  dtos.ErrorResponse "Customer not found"
+// @Failure      500       {object}  dtos.ErrorResponse "Internal server error or failure in retrieving customer"
 // @Security     ApiKeyAuth
 // @Router       /customers/email/{email} [get]
 func (cc *CustomerController) GetCustomerByEmail(c *gin.Context) {
-	if cc.Log.RegisterLog(c, "Attempting to retrieve customer by email") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_GET_CUSTOMER_BY_EMAIL
 	if !cc.Auth.CheckPermission(c, permissionId) {
 		_ = cc.Log.RegisterLog(c, "Access denied for GetCustomerByEmail")
@@ -287,10 +414,10 @@ func (cc *CustomerController) GetCustomerByEmail(c *gin.Context) {
 
 	email := c.Param("email")
 
-	customer, err := cc.Service.GetCustomerByEmail(email)
+	customer, err := cc.Service.GetCustomerByEmail(c.Request.Context(), email)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Customer not found with email: "+email)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Customer not found")
 		return
 	}
 
@@ -298,185 +425,496 @@ func (cc *CustomerController) GetCustomerByEmail(c *gin.Context) {
 	c.JSON(http.StatusOK, customer)
 }
 
-// SearchCustomersByID godoc
-// @Summary      Search customers by ID
-// @Description  Searches for customers based on a query ID. Requires appropriate permission.
+// SearchCustomers godoc
+// @Summary      Search customers
+// @Description  Returns the customers matching every filter given; filters left out do not apply. Name and last name match by prefix ignoring case and accents, email by prefix ignoring case, and phone by the digits contained in any of the customer's phone numbers.
 // @Tags         customers
 // @Accept       json
 // @Produce      json
-// @Param        id   query     string                 true  "Customer ID query"
-// @Success      200  {array}   dtos.GetCustomerDTO     "List of customers matching the search"
-// @Failure      400  {object}  models.ErrorResponse    "Invalid query or request format"
-// @Failure      401  {object}  models.ErrorResponse    "Unauthorized or permission denied"
-// @Failure      404  {object}  models.ErrorResponse    "No customers found"
-// @Failure      500  {object}  models.ErrorResponse    "Internal server error or failure in retrieving customers"
+// @Param        id                query  string  false  "Internal ID prefix"
+// @Param        name              query  string  false  "Name prefix"
+// @Param        lastName          query  string  false  "Last name prefix"
+// @Param        email             query  string  false  "Email prefix"
+// @Param        phone             query  string  false  "Phone digits"
+// @Param        identifierTypeId  query  int     false  "Identifier type"
+// @Param        isBusiness        query  bool    false  "Businesses (true) or people (false)"
+// @Param        state             query  bool    false  "Active (true) or inactive (false) customers"
+// @Param        sortBy            query  string  false  "id, name, lastName, email or createdAt (default id)"
+// @Param        order             query  string  false  "asc or desc (default asc)"
+// @Param        page              query  int     false  "Page number (default 1)"
+// @Param        pageSize          query  int     false  "Page size, up to 200 (default 50)"
+// @Param        includeDeleted    query  bool    false  "Include deleted customers (default false)"
+// @Success      200  {object}  dtos.PageDTO[dtos.GetCustomerDTO]     "Customers matching the search"
+// @Failure      400  {object}  dtos.ErrorResponse    "Invalid filter, sorting or pagination"
+// @Failure      401  {object}  dtos.ErrorResponse    "Unauthorized or permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse    "No customers found"
+// @Failure      500  {object}  dtos.ErrorResponse    "Internal server error or failure in retrieving customers"
 // @Security     ApiKeyAuth
-// @Router       /customers/searchByID [get]
-func (cc *CustomerController) SearchCustomersByID(c *gin.Context) {
-	if cc.Log.RegisterLog(c, "Attempting to search customers by ID") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+// @Router       /customers/search [get]
+func (cc *CustomerController) SearchCustomers(c *gin.Context) {
+	permissionId := config.PERMISSION_SEARCH_CUSTOMERS
+	if !cc.Auth.CheckPermission(c, permissionId) {
+		_ = cc.Log.RegisterLog(c, "Access denied for SearchCustomers")
 		return
 	}
 
-	permissionId := config.PERMISSION_SEARCH_CUSTOMERS_BY_ID
-	if !cc.Auth.CheckPermission(c, permissionId) {
-		_ = cc.Log.RegisterLog(c, "Access denied for SearchCustomersByID")
+	search := dtos.CustomerSearchDTO{
+		ID:       strings.TrimSpace(c.Query("id")),
+		Name:     strings.TrimSpace(c.Query("name")),
+		LastName: strings.TrimSpace(c.Query("lastName")),
+		Email:    strings.TrimSpace(c.Query("email")),
+		Phone:    strings.TrimSpace(c.Query("phone")),
+		SortBy:   c.DefaultQuery("sortBy", "id"),
+		Order:    c.DefaultQuery("order", "asc"),
+	}
+	var err error
+	if search.IdentifierTypeID, err = parseOptionalInt(c, "identifierTypeId"); err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid customer search: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if search.IsBusiness, err = parseOptionalBool(c, "isBusiness"); err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid customer search: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if search.State, err = parseOptionalBool(c, "state"); err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid customer search: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if search.PaginationDTO, err = utilities.ParsePagination(c); err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid pagination for SearchCustomers: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if search.IncludeDeleted, err = parseIncludeDeleted(c); err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid includeDeleted parameter for SearchCustomers: "+c.Query("includeDeleted"))
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	query := c.Query("id")
-
-	customers, err := cc.Service.SearchCustomersByID(query)
+	customers, total, err := cc.Service.SearchCustomers(c.Request.Context(), search)
 	if err != nil {
-		_ = cc.Log.RegisterLog(c, "Error retrieving customers by ID query: "+query)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving customers"})
+		_ = cc.Log.RegisterLog(c, "Error searching customers: "+err.Error())
+		if errors.Is(err, services.ErrInvalidCustomerSearch) {
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving customers")
 		return
 	}
 
-	if len(customers) == 0 {
-		_ = cc.Log.RegisterLog(c, "No customers found for ID query: "+query)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No customers found"})
+	if total == 0 {
+		_ = cc.Log.RegisterLog(c, "No customers found for search: "+c.Request.URL.RawQuery)
+		utilities.RespondError(c, http.StatusNotFound, "No customers found")
 		return
 	}
 
-	var customersDTO []dtos.GetCustomerDTO
-	for _, customer := range customers {
-		customersDTO = append(customersDTO, dtos.GetCustomerDTO{
-			ID:               customer.ID,
-			CustomerName:     customer.CustomerName,
-			CustomerId:       customer.CustomerId,
-			IsBusiness:       customer.IsBusiness,
-			Address:          customer.Address,
-			PhoneNumbers:     customer.PhoneNumbers,
-			CustomerState:    customer.CustomerState,
-			Email:            customer.Email,
-			LastName:         customer.LastName,
-			IdentifierTypeID: customer.IdentifierTypeID,
-		})
+	customersDTO := make([]dtos.GetCustomerDTO, len(customers))
+	for i, customer := range customers {
+		customersDTO[i] = customerDTO(customer)
 	}
 
-	_ = cc.Log.RegisterLog(c, "Customers retrieved successfully for ID query: "+query)
-	c.JSON(http.StatusOK, customersDTO)
+	_ = cc.Log.RegisterLog(c, "Customers retrieved successfully for search: "+c.Request.URL.RawQuery)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(customersDTO, search.PaginationDTO, total))
+}
+
+func customerDTO(customer models.Customer) dtos.GetCustomerDTO {
+	return dtos.GetCustomerDTO{
+		ID:               customer.ID,
+		CustomerName:     customer.CustomerName,
+		CustomerId:       customer.CustomerId,
+		IsBusiness:       customer.IsBusiness,
+		Address:          customer.Address,
+		PhoneNumbers:     customer.PhoneNumbers,
+		CustomerState:    customer.CustomerState,
+		Email:            customer.Email,
+		LastName:         customer.LastName,
+		IdentifierTypeID: customer.IdentifierTypeID,
+		Latitude:         customer.Latitude,
+		Longitude:        customer.Longitude,
+		AddressStatus:    customer.AddressStatus,
+		TextChannel:      customer.TextChannel,
+		Version:          customer.Version,
+		DeletedAt:        customerDeletedAt(customer),
+	}
 }
 
-// SearchCustomersByName godoc
-// @Summary      Search customers by name
-// @Description  Searches for customers based on a name query. Requires appropriate permission.
+// BatchCustomers godoc
+// @Summary      Create, update and deactivate customers in bulk
+// @Description  Runs up to 100 operations in a single transaction. If any operation fails, nothing is saved: the failed operations report their error and the rest are marked rolled_back.
+// @Description  "delete" deactivates the customer (customerState = false). Creating requires the create permission; updating and deleting require the update permission.
 // @Tags         customers
 // @Accept       json
 // @Produce      json
-// @Param        name  query     string                 true  "Customer name query"
-// @Success      200   {array}   dtos.GetCustomerDTO     "List of customers matching the search"
-// @Failure      400   {object}  models.ErrorResponse    "Invalid query or request format"
-// @Failure      401   {object}  models.ErrorResponse    "Unauthorized or permission denied"
-// @Failure      404   {object}  models.ErrorResponse    "No customers found"
-// @Failure      500   {object}  models.ErrorResponse    "Internal server error or failure in retrieving customers"
+// @Param        batch  body      dtos.BatchRequestDTO[dtos.CreateCustomerDTO]  true  "Operations to apply"
+// @Success      200    {object}  dtos.BatchResponseDTO  "All operations were applied"
+// @Failure      400    {object}  dtos.ErrorResponse     "Invalid request body"
+// @Failure      403    {object}  dtos.ErrorResponse     "Permission denied"
+// @Failure      422    {object}  dtos.BatchResponseDTO  "At least one operation failed; nothing was applied"
+// @Failure      500    {object}  dtos.ErrorResponse     "Error applying the batch"
+// @Security     ApiKeyAuth
+// @Router       /customers/batch [post]
+func (cc *CustomerController) BatchCustomers(c *gin.Context) {
+	var dto dtos.BatchRequestDTO[dtos.CreateCustomerDTO]
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid JSON format in BatchCustomers request")
+		utilities.RespondValidationError(c, "Invalid JSON format", err)
+		return
+	}
+
+	permissions := map[string]int{
+		services.BATCH_OP_CREATE: config.PERMISSION_CREATE_CUSTOMER,
+		services.BATCH_OP_UPDATE: config.PERMISSION_UPDATE_CUSTOMER,
+		services.BATCH_OP_DELETE: config.PERMISSION_UPDATE_CUSTOMER,
+	}
+	checked := make(map[string]bool)
+	for _, op := range dto.Operations {
+		if checked[op.Op] {
+			continue
+		}
+		if !cc.Auth.CheckPermission(c, permissions[op.Op]) {
+			_ = cc.Log.RegisterLog(c, "Access denied for BatchCustomers ("+op.Op+")")
+			return
+		}
+		checked[op.Op] = true
+	}
+
+	response, err := cc.Service.BatchCustomers(c.Request.Context(), dto.Operations)
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Error applying customer batch: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error applying the batch")
+		return
+	}
+
+	if !response.Committed {
+		_ = cc.Log.RegisterLog(c, "Customer batch rolled back")
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	for _, result := range response.Results {
+		if result.Before == nil {
+			continue
+		}
+		action := services.AUDIT_ACTION_UPDATE
+		if result.Op == services.BATCH_OP_DELETE {
+			action = services.AUDIT_ACTION_DELETE
+		}
+		if err := cc.Audit.RecordChange(c, services.AUDIT_ENTITY_CUSTOMER, strconv.Itoa(result.ID), action, result.Before, result.After); err != nil {
+			_ = cc.Log.RegisterLog(c, "Error recording audit trail for customer with ID "+strconv.Itoa(result.ID)+": "+err.Error())
+		}
+	}
+
+	_ = cc.Log.RegisterLog(c, "Customer batch applied successfully with "+strconv.Itoa(len(response.Results))+" operations")
+	c.JSON(http.StatusOK, response)
+}
+
+// GetCustomerDependencies godoc
+// @Summary      Count the records that depend on a customer
+// @Description  Returns how many invoices, appointments, external sales and purchase orders reference the customer. A customer with any of them cannot be deleted, only archived.
+// @Tags         customers
+// @Produce      json
+// @Param        id   path      int  true  "Customer ID"
+// @Success      200  {object}  dtos.CustomerDependenciesDTO  "Dependent records"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid customer ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Customer not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error counting dependent records"
 // @Security     ApiKeyAuth
-// @Router       /customers/searchByName [get]
-func (cc *CustomerController) SearchCustomersByName(c *gin.Context) {
-	if cc.Log.RegisterLog(c, "Attempting to search customers by name") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+// @Router       /customers/{id}/dependencies [get]
+func (cc *CustomerController) GetCustomerDependencies(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_CUSTOMER_BY_ID
+	if !cc.Auth.CheckPermission(c, permissionId) {
+		_ = cc.Log.RegisterLog(c, "Access denied for GetCustomerDependencies")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid customer ID format in URL parameter")
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid customer ID")
 		return
 	}
 
-	permissionId := config.PERMISSION_SEARCH_CUSTOMERS_BY_NAME
+	dependencies, err := cc.Service.GetCustomerDependencies(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = cc.Log.RegisterLog(c, "Customer not found with ID: "+strconv.Itoa(id))
+			utilities.RespondError(c, http.StatusNotFound, "Customer not found")
+			return
+		}
+		_ = cc.Log.RegisterLog(c, "Error counting dependencies of customer with ID "+strconv.Itoa(id)+": "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error counting dependent records")
+		return
+	}
+
+	_ = cc.Log.RegisterLog(c, "Retrieved dependencies of customer with ID: "+strconv.Itoa(id))
+	c.JSON(http.StatusOK, dependencies)
+}
+
+// DeleteCustomer godoc
+// @Summary      Delete a customer
+// @Description  Soft-deletes a customer: it no longer appears in lookups or searches (unless includeDeleted=true), while its invoices, appointments, external sales and purchase orders are kept and still show it. The response includes the count of those records, and having them no longer blocks the deletion. POST /customers/{id}/restore undoes it. With strategy=archive the customer is only deactivated instead.
+// @Tags         customers
+// @Produce      json
+// @Param        id        path      int     true   "Customer ID"
+// @Param        strategy  query     string  false  "archive: deactivate the customer instead of deleting it"
+// @Success      200  {object}  dtos.CustomerDeletionDTO  "Customer deleted or archived"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid customer ID or strategy"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Customer not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error deleting customer"
+// @Security     ApiKeyAuth
+// @Router       /customers/{id} [delete]
+func (cc *CustomerController) DeleteCustomer(c *gin.Context) {
+	permissionId := config.PERMISSION_DELETE_CUSTOMER
 	if !cc.Auth.CheckPermission(c, permissionId) {
-		_ = cc.Log.RegisterLog(c, "Access denied for SearchCustomersByName")
+		_ = cc.Log.RegisterLog(c, "Access denied for DeleteCustomer")
 		return
 	}
 
-	query := c.Query("name")
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid customer ID format in URL parameter")
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid customer ID")
+		return
+	}
+
+	deletion, err := cc.Service.DeleteCustomer(c.Request.Context(), id, c.Query("strategy"))
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			_ = cc.Log.RegisterLog(c, "Customer not found with ID: "+strconv.Itoa(id))
+			utilities.RespondError(c, http.StatusNotFound, "Customer not found")
+		case errors.Is(err, services.ErrInvalidCustomerDeleteStrategy):
+			_ = cc.Log.RegisterLog(c, "Invalid deletion strategy for customer with ID "+strconv.Itoa(id))
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		default:
+			_ = cc.Log.RegisterLog(c, "Error deleting customer with ID "+strconv.Itoa(id)+": "+err.Error())
+			utilities.RespondError(c, http.StatusInternalServerError, "Error deleting customer")
+		}
+		return
+	}
+
+	if deletion.Action == services.CUSTOMER_DELETION_DELETED {
+		err = cc.Audit.RecordChange(c, services.AUDIT_ENTITY_CUSTOMER, strconv.Itoa(id), services.AUDIT_ACTION_DELETE, deletion.Before, nil)
+	} else {
+		err = cc.Audit.RecordChange(c, services.AUDIT_ENTITY_CUSTOMER, strconv.Itoa(id), services.AUDIT_ACTION_UPDATE, deletion.Before, deletion.After)
+	}
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Error recording audit trail for customer with ID "+strconv.Itoa(id)+": "+err.Error())
+	}
+
+	_ = cc.Log.RegisterLog(c, "Customer "+deletion.Action+" with ID: "+strconv.Itoa(id))
+	c.JSON(http.StatusOK, dtos.CustomerDeletionDTO{Action: deletion.Action, Dependencies: deletion.Dependencies})
+}
+
+// RestoreCustomer godoc
+// @Summary      Restore a deleted customer
+// @Description  Undoes the deletion of a customer, which appears again in lookups and searches. Fails with 409 if another customer was created in the meantime with the same document number or email.
+// @Tags         customers
+// @Produce      json
+// @Param        id   path      int  true  "Customer ID"
+// @Success      200  {object}  models.Customer  "Restored customer"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid customer ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Deleted customer not found"
+// @Failure      409  {object}  dtos.ErrorResponse  "Another customer uses the same document or email"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error restoring customer"
+// @Security     ApiKeyAuth
+// @Router       /customers/{id}/restore [post]
+func (cc *CustomerController) RestoreCustomer(c *gin.Context) {
+	permissionId := config.PERMISSION_RESTORE_CUSTOMER
+	if !cc.Auth.CheckPermission(c, permissionId) {
+		_ = cc.Log.RegisterLog(c, "Access denied for RestoreCustomer")
+		return
+	}
 
-	customers, err := cc.Service.SearchCustomersByName(query)
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		_ = cc.Log.RegisterLog(c, "Error retrieving customers by name query: "+query)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving customers"})
+		_ = cc.Log.RegisterLog(c, "Invalid customer ID format in URL parameter")
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid customer ID")
 		return
 	}
 
-	if len(customers) == 0 {
-		_ = cc.Log.RegisterLog(c, "No customers found for name query: "+query)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No customers found"})
+	customer, err := cc.Service.RestoreCustomer(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			_ = cc.Log.RegisterLog(c, "Deleted customer not found with ID: "+strconv.Itoa(id))
+			utilities.RespondError(c, http.StatusNotFound, "Deleted customer not found")
+		case errors.Is(err, services.ErrCustomerRestoreConflict):
+			_ = cc.Log.RegisterLog(c, "Customer with ID "+strconv.Itoa(id)+" not restored: "+err.Error())
+			utilities.RespondError(c, http.StatusConflict, err.Error())
+		default:
+			_ = cc.Log.RegisterLog(c, "Error restoring customer with ID "+strconv.Itoa(id)+": "+err.Error())
+			utilities.RespondError(c, http.StatusInternalServerError, "Error restoring customer")
+		}
 		return
 	}
 
-	var customersDTO []dtos.GetCustomerDTO
-	for _, customer := range customers {
-		customersDTO = append(customersDTO, dtos.GetCustomerDTO{
-			ID:               customer.ID,
-			CustomerName:     customer.CustomerName,
-			CustomerId:       customer.CustomerId,
-			IsBusiness:       customer.IsBusiness,
-			Address:          customer.Address,
-			PhoneNumbers:     customer.PhoneNumbers,
-			CustomerState:    customer.CustomerState,
-			Email:            customer.Email,
-			LastName:         customer.LastName,
-			IdentifierTypeID: customer.IdentifierTypeID,
-		})
+	if err := cc.Audit.RecordChange(c, services.AUDIT_ENTITY_CUSTOMER, strconv.Itoa(id), services.AUDIT_ACTION_RESTORE, nil, customer); err != nil {
+		_ = cc.Log.RegisterLog(c, "Error recording audit trail for customer with ID "+strconv.Itoa(id)+": "+err.Error())
 	}
 
-	_ = cc.Log.RegisterLog(c, "Customers retrieved successfully for name query: "+query)
-	c.JSON(http.StatusOK, customersDTO)
+	_ = cc.Log.RegisterLog(c, "Customer restored with ID: "+strconv.Itoa(id))
+	c.JSON(http.StatusOK, customer)
 }
 
-// SearchCustomersByLastName godoc
-// @Summary      Search customers by last name
-// @Description  Searches for customers based on a last name query. Requires appropriate permission.
+// MergeCustomers godoc
+// @Summary      Merge duplicate customers
+// @Description  Moves the appointments, invoices and external sales of the duplicate customer to the primary one and deactivates the duplicate, all in one transaction. Neither customer can be deleted.
 // @Tags         customers
 // @Accept       json
 // @Produce      json
-// @Param        lastName  query     string                 true  "Customer last name query"
-// @Success      200       {array}   dtos.GetCustomerDTO     "List of customers matching the search"
-// @Failure      400       {object}  models.ErrorResponse    "Invalid query or request format"
-// @Failure      401       {object}  models.ErrorResponse    "Unauthorized or permission denied"
-// @Failure      404       {object}  models.ErrorResponse    "No customers found"
-// @Failure      500       {object}  models.ErrorResponse    "Internal server error or failure in retrieving customers"
+// @Param        merge  body      dtos.MergeCustomersDTO  true  "Primary and duplicate customer IDs"
+// @Success      200  {object}  dtos.CustomerMergeDTO  "Merged customers and the number of records moved"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid request data or same customer twice"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Customer not found"
+// @Failure      409  {object}  dtos.ErrorResponse  "The duplicate customer was modified concurrently"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error merging customers"
 // @Security     ApiKeyAuth
-// @Router       /customers/searchByLastName [get]
-func (cc *CustomerController) SearchCustomersByLastName(c *gin.Context) {
-	if cc.Log.RegisterLog(c, "Attempting to search customers by last name") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+// @Router       /customers/merge [post]
+func (cc *CustomerController) MergeCustomers(c *gin.Context) {
+	permissionId := config.PERMISSION_MERGE_CUSTOMERS
+	if !cc.Auth.CheckPermission(c, permissionId) {
+		_ = cc.Log.RegisterLog(c, "Access denied for MergeCustomers")
 		return
 	}
 
-	permissionId := config.PERMISSION_SEARCH_CUSTOMERS_BY_LASTNAME
-	if !cc.Auth.CheckPermission(c, permissionId) {
-		_ = cc.Log.RegisterLog(c, "Access denied for SearchCustomersByLastName")
+	var dto dtos.MergeCustomersDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid customer merge: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+	ids := strconv.Itoa(dto.DuplicateID) + " into " + strconv.Itoa(dto.PrimaryID)
+
+	merge, err := cc.Service.MergeCustomers(c.Request.Context(), dto.PrimaryID, dto.DuplicateID)
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Error merging customer "+ids+": "+err.Error())
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utilities.RespondError(c, http.StatusNotFound, "Customer not found")
+		case errors.Is(err, services.ErrInvalidCustomerMerge):
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, services.ErrVersionConflict):
+			utilities.RespondVersionConflict(c, err.Error())
+		default:
+			utilities.RespondError(c, http.StatusInternalServerError, "Error merging customers")
+		}
 		return
 	}
 
-	query := c.Query("lastName")
+	if err := cc.Audit.RecordChange(c, services.AUDIT_ENTITY_CUSTOMER, strconv.Itoa(dto.DuplicateID), services.AUDIT_ACTION_UPDATE, merge.DuplicateBefore, merge.Duplicate); err != nil {
+		_ = cc.Log.RegisterLog(c, "Error recording audit trail for customer with ID "+strconv.Itoa(dto.DuplicateID)+": "+err.Error())
+	}
+
+	_ = cc.Log.RegisterLog(c, "Merged customer "+ids)
+	c.JSON(http.StatusOK, dtos.CustomerMergeDTO{Customer: merge.Primary, Duplicate: merge.Duplicate, Reassigned: merge.Reassigned})
+}
 
-	customers, err := cc.Service.SearchCustomersByLastName(query)
+// parseIncludeDeleted lee el parámetro includeDeleted de listados y búsquedas de clientes.
+func parseIncludeDeleted(c *gin.Context) (bool, error) {
+	value := c.Query("includeDeleted")
+	if value == "" {
+		return false, nil
+	}
+	includeDeleted, err := strconv.ParseBool(value)
 	if err != nil {
-		_ = cc.Log.RegisterLog(c, "Error retrieving customers by last name query: "+query)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving customers"})
+		return false, errors.New("invalid 'includeDeleted' parameter")
+	}
+	return includeDeleted, nil
+}
+
+// parseOptionalBool lee un parámetro booleano opcional; ausente devuelve nil.
+func parseOptionalBool(c *gin.Context, name string) (*bool, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid '%s' parameter", name)
+	}
+	return &b, nil
+}
+
+// parseOptionalInt lee un parámetro entero opcional; ausente devuelve nil.
+func parseOptionalInt(c *gin.Context, name string) (*int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid '%s' parameter", name)
+	}
+	return &n, nil
+}
+
+func customerDeletedAt(customer models.Customer) *time.Time {
+	if !customer.DeletedAt.Valid {
+		return nil
+	}
+	return &customer.DeletedAt.Time
+}
+
+var customerExportColumns = []utilities.ExportColumn[models.Customer]{
+	{Name: "id", Value: func(cu models.Customer) interface{} { return cu.ID }},
+	{Name: "customer_id", Value: func(cu models.Customer) interface{} { return cu.CustomerId }},
+	{Name: "identifier_type_id", Value: func(cu models.Customer) interface{} { return cu.IdentifierTypeID }},
+	{Name: "customer_name", Value: func(cu models.Customer) interface{} { return cu.CustomerName }},
+	{Name: "last_name", Value: func(cu models.Customer) interface{} { return cu.LastName }},
+	{Name: "is_business", Value: func(cu models.Customer) interface{} { return cu.IsBusiness }},
+	{Name: "email", Value: func(cu models.Customer) interface{} { return cu.Email }},
+	{Name: "phone_numbers", Value: func(cu models.Customer) interface{} { return cu.PhoneNumbers }},
+	{Name: "address", Value: func(cu models.Customer) interface{} { return cu.Address }},
+	{Name: "customer_state", Value: func(cu models.Customer) interface{} { return cu.CustomerState }},
+	{Name: "created_at", Value: func(cu models.Customer) interface{} { return cu.CreatedAt }},
+}
+
+// ExportCustomers godoc
+// @Summary      Export customers to CSV
+// @Description  Streams the customers that are not deleted as CSV. "columns" picks and orders the columns (all by default); from/to filter by creation date, so customers created before creation dates were recorded are only exported without them.
+// @Tags         customers
+// @Produce      text/csv
+// @Param        columns  query  string  false  "Comma separated columns: id, customer_id, identifier_type_id, customer_name, last_name, is_business, email, phone_numbers, address, customer_state, created_at"
+// @Param        from     query  string  false  "Created from (YYYY-MM-DD or RFC3339)"
+// @Param        to       query  string  false  "Created until, inclusive (YYYY-MM-DD or RFC3339)"
+// @Success      200  {file}   file  "CSV file"
+// @Failure      400  {object} dtos.ErrorResponse "Unknown column or invalid date"
+// @Failure      403  {object} dtos.ErrorResponse "Access denied"
+// @Security     ApiKeyAuth
+// @Router       /customers/export [get]
+func (cc *CustomerController) ExportCustomers(c *gin.Context) {
+	permissionId := config.PERMISSION_EXPORT_CUSTOMERS
+	if !cc.Auth.CheckPermission(c, permissionId) {
+		_ = cc.Log.RegisterLog(c, "Access denied for ExportCustomers")
 		return
 	}
 
-	if len(customers) == 0 {
-		_ = cc.Log.RegisterLog(c, "No customers found for last name query: "+query)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No customers found"})
+	filter, ok := parseCSVExportFilter(c, cc.Log)
+	if !ok {
 		return
 	}
 
-	var customersDTO []dtos.GetCustomerDTO
-	for _, customer := range customers {
-		customersDTO = append(customersDTO, dtos.GetCustomerDTO{
-			ID:               customer.ID,
-			CustomerName:     customer.CustomerName,
-			CustomerId:       customer.CustomerId,
-			IsBusiness:       customer.IsBusiness,
-			Address:          customer.Address,
-			PhoneNumbers:     customer.PhoneNumbers,
-			CustomerState:    customer.CustomerState,
-			Email:            customer.Email,
-			LastName:         customer.LastName,
-			IdentifierTypeID: customer.IdentifierTypeID,
-		})
+	ctx := c.Request.Context()
+	table, err := utilities.ExportTable(c, "customers", customerExportColumns, func(fn func(models.Customer) error) error {
+		return cc.Service.StreamCustomers(ctx, filter, fn)
+	})
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid export columns: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	_ = cc.Log.RegisterLog(c, "Customers retrieved successfully for last name query: "+query)
-	c.JSON(http.StatusOK, customersDTO)
+	if err := utilities.WriteReportTable(c, utilities.ReportFormatCSV, table); err != nil {
+		_ = cc.Log.RegisterLog(c, "Error exporting customers: "+err.Error())
+		return
+	}
+	_ = cc.Log.RegisterLog(c, "Successfully exported customers")
 }
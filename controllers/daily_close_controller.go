@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DailyCloseController struct {
+	Service *services.DailyCloseService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewDailyCloseController(service *services.DailyCloseService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *DailyCloseController {
+	return &DailyCloseController{Service: service, Auth: auth, Log: log}
+}
+
+// GetDailyClose godoc
+// @Summary      End-of-day (Z) report
+// @Description  Summarizes the invoices of a day, payments by method net of refunds, voids and the cash expected in the drawer.
+// @Description  If the day was already closed the frozen report is returned, otherwise it is computed on the fly.
+// @Tags         reports
+// @Produce      json
+// @Param        date  query  string  false  "Day to summarize (YYYY-MM-DD, default today)"
+// @Success      200  {object}  models.DailyClose  "Daily close report"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid date"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error building the daily close report"
+// @Security     ApiKeyAuth
+// @Router       /reports/daily-close [get]
+func (dcc *DailyCloseController) GetDailyClose(c *gin.Context) {
+	dateStr := c.DefaultQuery("date", time.Now().Format("2006-01-02"))
+
+	permissionId := config.PERMISSION_VIEW_DAILY_CLOSE
+	if !dcc.Auth.CheckPermission(c, permissionId) {
+		_ = dcc.Log.RegisterLog(c, "Access denied for GetDailyClose")
+		return
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", dateStr, time.Local)
+	if err != nil {
+		_ = dcc.Log.RegisterLog(c, "Invalid date: "+dateStr)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid date format. Use YYYY-MM-DD")
+		return
+	}
+
+	dailyClose, err := dcc.Service.GetDailyClose(c.Request.Context(), date)
+	if err != nil {
+		_ = dcc.Log.RegisterLog(c, "Error building daily close for "+dateStr+": "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error building daily close report")
+		return
+	}
+
+	_ = dcc.Log.RegisterLog(c, "Successfully retrieved daily close for "+dateStr)
+	c.JSON(http.StatusOK, dailyClose)
+}
+
+// CloseDay godoc
+// @Summary      Close the day
+// @Description  Computes the end-of-day (Z) report and freezes it. A closed day cannot be closed again.
+// @Tags         reports
+// @Produce      json
+// @Param        date  query  string  false  "Day to close (YYYY-MM-DD, default today)"
+// @Success      201  {object}  models.DailyClose  "Frozen daily close report"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid date"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      409  {object}  dtos.ErrorResponse  "The day is already closed"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error closing the day"
+// @Security     ApiKeyAuth
+// @Router       /reports/daily-close [post]
+func (dcc *DailyCloseController) CloseDay(c *gin.Context) {
+	dateStr := c.DefaultQuery("date", time.Now().Format("2006-01-02"))
+
+	permissionId := config.PERMISSION_CLOSE_DAY
+	if !dcc.Auth.CheckPermission(c, permissionId) {
+		_ = dcc.Log.RegisterLog(c, "Access denied for CloseDay")
+		return
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", dateStr, time.Local)
+	if err != nil {
+		_ = dcc.Log.RegisterLog(c, "Invalid date: "+dateStr)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid date format. Use YYYY-MM-DD")
+		return
+	}
+
+	dailyClose, err := dcc.Service.CloseDay(c.Request.Context(), date, utilities.CurrentUser(c))
+	if err != nil {
+		_ = dcc.Log.RegisterLog(c, "Error closing day "+dateStr+": "+err.Error())
+		if errors.Is(err, services.ErrDayAlreadyClosed) {
+			utilities.RespondError(c, http.StatusConflict, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error closing the day")
+		return
+	}
+
+	_ = dcc.Log.RegisterLog(c, "Successfully closed day "+dateStr)
+	c.JSON(http.StatusCreated, dailyClose)
+}
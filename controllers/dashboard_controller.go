@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DashboardController struct {
+	Service *services.DashboardService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewDashboardController(service *services.DashboardService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *DashboardController {
+	return &DashboardController{Service: service, Auth: auth, Log: log}
+}
+
+// GetDashboard godoc
+// @Summary      Get admin dashboard figures
+// @Description  Returns today's, this week's and this month's sales and appointment counts, the number of low-stock items and the number of pending comments.
+// @Tags         dashboard
+// @Produce      json
+// @Success      200  {object}  dtos.DashboardDTO     "Dashboard aggregates"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error building dashboard"
+// @Security     ApiKeyAuth
+// @Router       /dashboard [get]
+func (dc *DashboardController) GetDashboard(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_DASHBOARD
+	if !dc.Auth.CheckPermission(c, permissionId) {
+		_ = dc.Log.RegisterLog(c, "Access denied for GetDashboard")
+		return
+	}
+
+	dashboard, err := dc.Service.GetDashboard(c.Request.Context(), time.Now())
+	if err != nil {
+		_ = dc.Log.RegisterLog(c, "Error building dashboard: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error building dashboard")
+		return
+	}
+
+	_ = dc.Log.RegisterLog(c, "Successfully retrieved dashboard")
+	c.JSON(http.StatusOK, dashboard)
+}
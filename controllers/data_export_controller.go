@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type DataExportController struct {
+	Service *services.DataExportService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewDataExportController(service *services.DataExportService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *DataExportController {
+	return &DataExportController{Service: service, Auth: auth, Log: log}
+}
+
+// CreateDataExport godoc
+// @Summary      Request a full data export
+// @Description  Queues a backup of all business data. The export is generated in the background as a zip with one JSON file per table and a manifest.json; poll GET /exports/{id} until it is completed to get its download link.
+// @Tags         exports
+// @Produce      json
+// @Success      202  {object}  dtos.DataExportDTO  "Export queued"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error queuing export"
+// @Security     ApiKeyAuth
+// @Router       /exports [post]
+func (dc *DataExportController) CreateDataExport(c *gin.Context) {
+	permissionId := config.PERMISSION_CREATE_DATA_EXPORT
+	if !dc.Auth.CheckPermission(c, permissionId) {
+		_ = dc.Log.RegisterLog(c, "Access denied for CreateDataExport")
+		return
+	}
+
+	export, err := dc.Service.RequestExport(c.Request.Context(), utilities.CurrentUser(c))
+	if err != nil {
+		_ = dc.Log.RegisterLog(c, "Error queuing data export: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error queuing export")
+		return
+	}
+
+	_ = dc.Log.RegisterLog(c, "Data export queued with ID: "+strconv.Itoa(export.ID))
+	c.JSON(http.StatusAccepted, dc.Service.ToDTO(*export))
+}
+
+// GetDataExports godoc
+// @Summary      List data exports
+// @Description  Returns a page of data exports, newest first.
+// @Tags         exports
+// @Produce      json
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[dtos.DataExportDTO]  "Page of exports"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid pagination"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving exports"
+// @Security     ApiKeyAuth
+// @Router       /exports [get]
+func (dc *DataExportController) GetDataExports(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_DATA_EXPORTS
+	if !dc.Auth.CheckPermission(c, permissionId) {
+		_ = dc.Log.RegisterLog(c, "Access denied for GetDataExports")
+		return
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = dc.Log.RegisterLog(c, "Invalid pagination for GetDataExports: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := dc.Service.GetExports(c.Request.Context(), pagination)
+	if err != nil {
+		_ = dc.Log.RegisterLog(c, "Error retrieving data exports: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving exports")
+		return
+	}
+
+	_ = dc.Log.RegisterLog(c, "Successfully retrieved data exports")
+	c.JSON(http.StatusOK, page)
+}
+
+// GetDataExportByID godoc
+// @Summary      Get a data export
+// @Description  Returns the status of a data export. Completed exports include a signed download link that expires after 24 hours; each request returns a fresh one.
+// @Tags         exports
+// @Produce      json
+// @Param        id   path      int  true  "Export ID"
+// @Success      200  {object}  dtos.DataExportDTO  "Export"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid export ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Export not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving export"
+// @Security     ApiKeyAuth
+// @Router       /exports/{id} [get]
+func (dc *DataExportController) GetDataExportByID(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_DATA_EXPORTS
+	if !dc.Auth.CheckPermission(c, permissionId) {
+		_ = dc.Log.RegisterLog(c, "Access denied for GetDataExportByID")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = dc.Log.RegisterLog(c, "Invalid data export ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid export ID")
+		return
+	}
+
+	export, err := dc.Service.GetExport(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = dc.Log.RegisterLog(c, "Data export not found with ID: "+strconv.Itoa(id))
+			utilities.RespondError(c, http.StatusNotFound, "Export not found")
+			return
+		}
+		_ = dc.Log.RegisterLog(c, "Error retrieving data export with ID "+strconv.Itoa(id)+": "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving export")
+		return
+	}
+
+	_ = dc.Log.RegisterLog(c, "Successfully retrieved data export with ID: "+strconv.Itoa(id))
+	c.JSON(http.StatusOK, export)
+}
+
+// DownloadDataExport godoc
+// @Summary      Download a data export
+// @Description  Downloads the zip of a completed export. The signed token in the link is the only credential, so the link can be opened directly from a browser.
+// @Tags         exports
+// @Produce      application/zip
+// @Param        token  query  string  true  "Signed download token"
+// @Success      200  {file}    file  "Export zip"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid or expired link"
+// @Failure      404  {object}  dtos.ErrorResponse  "Export not found or no longer available"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error downloading export"
+// @Router       /exports/download [get]
+func (dc *DataExportController) DownloadDataExport(c *gin.Context) {
+	path, fileName, err := dc.Service.OpenDownload(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidLinkToken):
+			utilities.RespondError(c, http.StatusBadRequest, "Invalid or expired download link")
+		case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, services.ErrDataExportNotReady):
+			utilities.RespondError(c, http.StatusNotFound, "Export not found or no longer available")
+		default:
+			_ = dc.Log.RegisterLog(c, "Error downloading data export: "+err.Error())
+			utilities.RespondError(c, http.StatusInternalServerError, "Error downloading export")
+		}
+		return
+	}
+
+	_ = dc.Log.RegisterLog(c, "Data export downloaded: "+fileName)
+	c.FileAttachment(path, fileName)
+}
@@ -1,14 +1,17 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
-	"totesbackend/models"
+	"totesbackend/dtos"
 	"totesbackend/services"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type DiscountTypeController struct {
@@ -29,30 +32,25 @@ func NewDiscountTypeController(service *services.DiscountTypeService, auth *util
 // @Produce      json
 // @Param        id  path     string              true  "Discount Type ID"
 // @Success      200 {object} models.DiscountType "The requested discount type"
-// @Failure      400 {object} models.ErrorResponse "Invalid ID format"
-// @Failure      401 {object} models.ErrorResponse "Unauthorized or permission denied"
-// @Failure      404 {object} models.ErrorResponse "Discount type not found"
-// @Failure      500 {object} models.ErrorResponse "Internal server error or failure in retrieving discount type"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid ID format"
+// @Failure      401 {object} dtos.ErrorResponse "Unauthorized or permission denied"
+// @Failure      404 {object} dtos.ErrorResponse "Discount type not found"
+// @Failure      500 {object} dtos.ErrorResponse "Internal server error or failure in retrieving discount type"
 // @Security     ApiKeyAuth
 // @Router       /discount-types/{id} [get]
 func (dtc *DiscountTypeController) GetDiscountTypeByID(c *gin.Context) {
 	id := c.Param("id")
 
-	if dtc.Log.RegisterLog(c, "Attempting to retrieve discount type with ID: "+id) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_GET_DISCOUNT_TYPE_BY_ID
 	if !dtc.Auth.CheckPermission(c, permissionId) {
 		_ = dtc.Log.RegisterLog(c, "Access denied for GetDiscountTypeByID")
 		return
 	}
 
-	discountType, err := dtc.Service.GetDiscountTypeByID(id)
+	discountType, err := dtc.Service.GetDiscountTypeByID(c.Request.Context(), id)
 	if err != nil {
 		_ = dtc.Log.RegisterLog(c, "Discount Type with ID "+id+" not found: "+err.Error())
-		c.JSON(http.StatusNotFound, gin.H{"error": "Discount Type not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Discount Type not found")
 		return
 	}
 
@@ -66,73 +64,202 @@ func (dtc *DiscountTypeController) GetDiscountTypeByID(c *gin.Context) {
 // @Tags         discount-types
 // @Accept       json
 // @Produce      json
-// @Success      200 {array} models.DiscountType "List of all discount types"
-// @Failure      401 {object} models.ErrorResponse "Unauthorized or permission denied"
-// @Failure      500 {object} models.ErrorResponse "Internal server error or failure in retrieving discount types"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200 {object} dtos.PageDTO[models.DiscountType] "List of all discount types"
+// @Failure      401 {object} dtos.ErrorResponse "Unauthorized or permission denied"
+// @Failure      500 {object} dtos.ErrorResponse "Internal server error or failure in retrieving discount types"
 // @Security     ApiKeyAuth
 // @Router       /discount-types [get]
 func (dtc *DiscountTypeController) GetAllDiscountTypes(c *gin.Context) {
-	if dtc.Log.RegisterLog(c, "Attempting to retrieve all discount types") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_GET_ALL_DISCOUNT_TYPES
 	if !dtc.Auth.CheckPermission(c, permissionId) {
 		_ = dtc.Log.RegisterLog(c, "Access denied for GetAllDiscountTypes")
 		return
 	}
 
-	discountTypes, err := dtc.Service.GetAllDiscountTypes()
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = dtc.Log.RegisterLog(c, "Invalid pagination for GetAllDiscountTypes: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	discountTypes, total, err := dtc.Service.GetAllDiscountTypes(c.Request.Context(), pagination)
 	if err != nil {
 		_ = dtc.Log.RegisterLog(c, "Error retrieving discount types: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving Discount Types"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving Discount Types")
 		return
 	}
 
 	_ = dtc.Log.RegisterLog(c, "Successfully retrieved all discount types")
-	c.JSON(http.StatusOK, discountTypes)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(discountTypes, pagination, total))
 }
 
 // CreateDiscountType godoc
 // @Summary      Create a new discount type
-// @Description  Allows the creation of a new discount type in the system. Requires appropriate permissions.
+// @Description  Allows the creation of a new discount type in the system. New discount types are active; valid_from and valid_to optionally limit when it can be applied. Requires appropriate permissions.
 // @Tags         discount-types
 // @Accept       json
 // @Produce      json
-// @Param        discountType body models.DiscountType true "Discount type details"
+// @Param        discountType body dtos.DiscountTypeDTO true "Discount type details"
 // @Success      201 {object} models.DiscountType "Successfully created discount type"
-// @Failure      400 {object} models.ErrorResponse "Invalid input data"
-// @Failure      401 {object} models.ErrorResponse "Unauthorized or permission denied"
-// @Failure      500 {object} models.ErrorResponse "Internal server error or failure in creating the discount type"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid input data"
+// @Failure      401 {object} dtos.ErrorResponse "Unauthorized or permission denied"
+// @Failure      500 {object} dtos.ErrorResponse "Internal server error or failure in creating the discount type"
 // @Security     ApiKeyAuth
 // @Router       /discount-types [post]
 func (dtc *DiscountTypeController) CreateDiscountType(c *gin.Context) {
-	if dtc.Log.RegisterLog(c, "Attempting to create a new discount type") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_CREATE_DISCOUNT_TYPE
 	if !dtc.Auth.CheckPermission(c, permissionId) {
 		_ = dtc.Log.RegisterLog(c, "Access denied for CreateDiscountType")
 		return
 	}
 
-	var discount models.DiscountType
-	if err := c.ShouldBindJSON(&discount); err != nil {
+	var dto dtos.DiscountTypeDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = dtc.Log.RegisterLog(c, "Invalid input for discount creation: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		utilities.RespondValidationError(c, "Invalid input", err)
 		return
 	}
 
-	err := dtc.Service.CreateDiscountType(&discount)
+	discount, err := dtc.Service.CreateDiscountType(c.Request.Context(), dto)
 	if err != nil {
 		_ = dtc.Log.RegisterLog(c, "Failed to create discount type: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create discount type"})
+		dtc.respondError(c, err, "Could not create discount type")
 		return
 	}
 
 	_ = dtc.Log.RegisterLog(c, "Successfully created new discount type")
 	c.JSON(http.StatusCreated, discount)
 }
+
+// UpdateDiscountType godoc
+// @Summary      Update a discount type
+// @Description  Changes the name, value and validity window of a discount type, and optionally its active flag. Invoices and quotations already issued keep their totals.
+// @Tags         discount-types
+// @Accept       json
+// @Produce      json
+// @Param        id           path string               true "Discount Type ID"
+// @Param        discountType body dtos.DiscountTypeDTO true "Discount type details"
+// @Success      200 {object} models.DiscountType "Updated discount type"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid input data"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "Discount type not found"
+// @Failure      500 {object} dtos.ErrorResponse "Error updating discount type"
+// @Security     ApiKeyAuth
+// @Router       /discount-types/{id} [put]
+func (dtc *DiscountTypeController) UpdateDiscountType(c *gin.Context) {
+	permissionId := config.PERMISSION_UPDATE_DISCOUNT_TYPE
+	if !dtc.Auth.CheckPermission(c, permissionId) {
+		_ = dtc.Log.RegisterLog(c, "Access denied for UpdateDiscountType")
+		return
+	}
+
+	id := c.Param("id")
+	var dto dtos.DiscountTypeDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = dtc.Log.RegisterLog(c, "Invalid input for discount type update: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid input", err)
+		return
+	}
+
+	discount, err := dtc.Service.UpdateDiscountType(c.Request.Context(), id, dto)
+	if err != nil {
+		_ = dtc.Log.RegisterLog(c, "Error updating discount type with ID "+id+": "+err.Error())
+		dtc.respondError(c, err, "Error updating discount type")
+		return
+	}
+
+	_ = dtc.Log.RegisterLog(c, "Successfully updated discount type with ID: "+id)
+	c.JSON(http.StatusOK, discount)
+}
+
+// DeactivateDiscountType godoc
+// @Summary      Deactivate a discount type
+// @Description  Deactivates a discount type without deleting it: it can no longer be applied to new invoices or quotations, and the invoices that applied it keep it. Reactivate it with PUT /discount-types/{id}.
+// @Tags         discount-types
+// @Produce      json
+// @Param        id  path string true "Discount Type ID"
+// @Success      200 {object} models.DiscountType "Deactivated discount type"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "Discount type not found"
+// @Failure      500 {object} dtos.ErrorResponse "Error deactivating discount type"
+// @Security     ApiKeyAuth
+// @Router       /discount-types/{id}/deactivate [patch]
+func (dtc *DiscountTypeController) DeactivateDiscountType(c *gin.Context) {
+	permissionId := config.PERMISSION_DEACTIVATE_DISCOUNT_TYPE
+	if !dtc.Auth.CheckPermission(c, permissionId) {
+		_ = dtc.Log.RegisterLog(c, "Access denied for DeactivateDiscountType")
+		return
+	}
+
+	id := c.Param("id")
+	discount, err := dtc.Service.DeactivateDiscountType(c.Request.Context(), id)
+	if err != nil {
+		_ = dtc.Log.RegisterLog(c, "Error deactivating discount type with ID "+id+": "+err.Error())
+		dtc.respondError(c, err, "Error deactivating discount type")
+		return
+	}
+
+	_ = dtc.Log.RegisterLog(c, "Successfully deactivated discount type with ID: "+id)
+	c.JSON(http.StatusOK, discount)
+}
+
+// ImportDiscountTypes godoc
+// @Summary      Bulk import discount types
+// @Description  Creates several discount types at once from a JSON array, a text/csv body or a multipart CSV file (field "file").
+// @Description  CSV files must include a header row with the columns name, description, is_percentage and value.
+// @Description  The import is all-or-nothing: if any row is invalid nothing is created.
+// @Tags         discount-types
+// @Accept       json
+// @Accept       text/csv
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        discounts  body      []dtos.ImportCatalogEntryDTO  false  "Discount types to import (JSON)"
+// @Param        file       formData  file                          false  "CSV file with the discount types to import"
+// @Success      201 {object} dtos.ImportCatalogResultDTO "Number of imported discount types"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid input data"
+// @Failure      401 {object} dtos.ErrorResponse "Unauthorized or permission denied"
+// @Failure      500 {object} dtos.ErrorResponse "Internal server error"
+// @Security     ApiKeyAuth
+// @Router       /discount-types/import [post]
+func (dtc *DiscountTypeController) ImportDiscountTypes(c *gin.Context) {
+	permissionId := config.PERMISSION_IMPORT_DISCOUNT_TYPES
+	if !dtc.Auth.CheckPermission(c, permissionId) {
+		_ = dtc.Log.RegisterLog(c, "Access denied for ImportDiscountTypes")
+		return
+	}
+
+	entries, err := utilities.ParseCatalogImport(c)
+	if err != nil {
+		_ = dtc.Log.RegisterLog(c, "Invalid input for discount type import: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid input: "+err.Error(), err)
+		return
+	}
+
+	discountTypes, err := dtc.Service.ImportDiscountTypes(c.Request.Context(), entries)
+	if err != nil {
+		_ = dtc.Log.RegisterLog(c, "Failed to import discount types: "+err.Error())
+		if errors.Is(err, services.ErrInvalidCatalogImport) {
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Failed to import discount types")
+		return
+	}
+
+	_ = dtc.Log.RegisterLog(c, "Successfully imported "+strconv.Itoa(len(discountTypes))+" discount types")
+	c.JSON(http.StatusCreated, dtos.ImportCatalogResultDTO{Imported: len(discountTypes)})
+}
+
+func (dtc *DiscountTypeController) respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utilities.RespondError(c, http.StatusNotFound, "Discount Type not found")
+	case errors.Is(err, services.ErrInvalidDiscountType):
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+	default:
+		utilities.RespondError(c, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/ecommerce"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type EcommerceController struct {
+	Service *services.EcommerceService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewEcommerceController(service *services.EcommerceService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *EcommerceController {
+	return &EcommerceController{Service: service, Auth: auth, Log: log}
+}
+
+// ReceiveOrderWebhook godoc
+// @Summary      Receive a web order
+// @Description  Webhook for the online store's order-created event (Shopify orders/create, WooCommerce order.created). The signature header is the only credential. The order becomes an Issued purchase order; if that fails (unknown SKU, not enough stock) it is recorded as failed and can be retried. A repeated delivery returns the order already recorded.
+// @Tags         ecommerce
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  models.EcommerceOrder  "Order recorded (or event ignored, with no body)"
+// @Failure      401  {object}  dtos.ErrorResponse  "Invalid signature"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error recording the order; the store retries the webhook"
+// @Router       /ecommerce/webhooks/orders [post]
+func (ec *EcommerceController) ReceiveOrderWebhook(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	order, err := ec.Service.ReceiveOrderWebhook(c.Request.Context(), c.Request.Header, body)
+	if err != nil {
+		if errors.Is(err, ecommerce.ErrInvalidWebhookSignature) {
+			_ = ec.Log.RegisterLog(c, "Web order webhook rejected: invalid signature")
+			utilities.RespondError(c, http.StatusUnauthorized, "Invalid webhook signature")
+			return
+		}
+		_ = ec.Log.RegisterLog(c, "Error receiving web order: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error recording the order")
+		return
+	}
+	if order == nil {
+		c.Status(http.StatusOK)
+		return
+	}
+
+	_ = ec.Log.RegisterLog(c, "Web order "+order.ExternalID+" received: "+order.Status)
+	c.JSON(http.StatusOK, order)
+}
+
+// GetEcommerceOrders godoc
+// @Summary      List web orders
+// @Description  Returns a page of the orders received from the online store, newest first, with the purchase order each one became or the error that kept it from being imported.
+// @Tags         ecommerce
+// @Produce      json
+// @Param        status    query  string  false  "received, imported or failed"
+// @Param        page      query  int     false  "Page number (default 1)"
+// @Param        pageSize  query  int     false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[models.EcommerceOrder]  "Page of web orders"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid pagination"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving web orders"
+// @Security     ApiKeyAuth
+// @Router       /ecommerce/orders [get]
+func (ec *EcommerceController) GetEcommerceOrders(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_ECOMMERCE_ORDERS
+	if !ec.Auth.CheckPermission(c, permissionId) {
+		_ = ec.Log.RegisterLog(c, "Access denied for GetEcommerceOrders")
+		return
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = ec.Log.RegisterLog(c, "Invalid pagination for GetEcommerceOrders: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	filter := dtos.EcommerceOrderFilterDTO{Status: c.Query("status"), PaginationDTO: pagination}
+
+	orders, total, err := ec.Service.GetOrders(c.Request.Context(), filter)
+	if err != nil {
+		_ = ec.Log.RegisterLog(c, "Error retrieving web orders: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving web orders")
+		return
+	}
+
+	_ = ec.Log.RegisterLog(c, "Successfully retrieved web orders")
+	c.JSON(http.StatusOK, dtos.NewPageDTO(orders, pagination, total))
+}
+
+// RetryEcommerceOrder godoc
+// @Summary      Retry importing a web order
+// @Description  Imports again a web order that failed, for example after creating the missing item or restocking.
+// @Tags         ecommerce
+// @Produce      json
+// @Param        id   path      int  true  "Web order ID"
+// @Success      200  {object}  models.EcommerceOrder  "Result of the new attempt"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid web order ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Web order not found"
+// @Failure      409  {object}  dtos.ErrorResponse  "The order has not failed"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error importing the order"
+// @Security     ApiKeyAuth
+// @Router       /ecommerce/orders/{id}/retry [post]
+func (ec *EcommerceController) RetryEcommerceOrder(c *gin.Context) {
+	permissionId := config.PERMISSION_RETRY_ECOMMERCE_ORDER
+	if !ec.Auth.CheckPermission(c, permissionId) {
+		_ = ec.Log.RegisterLog(c, "Access denied for RetryEcommerceOrder")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = ec.Log.RegisterLog(c, "Invalid web order ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid web order ID")
+		return
+	}
+
+	order, retried, err := ec.Service.RetryOrder(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = ec.Log.RegisterLog(c, "Web order not found with ID: "+strconv.Itoa(id))
+			utilities.RespondError(c, http.StatusNotFound, "Web order not found")
+			return
+		}
+		_ = ec.Log.RegisterLog(c, "Error retrying web order "+strconv.Itoa(id)+": "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error importing the order")
+		return
+	}
+	if !retried {
+		_ = ec.Log.RegisterLog(c, "Web order "+strconv.Itoa(id)+" not retried: it has not failed")
+		utilities.RespondError(c, http.StatusConflict, "Only failed orders can be imported again")
+		return
+	}
+
+	_ = ec.Log.RegisterLog(c, "Web order "+strconv.Itoa(id)+" retried: "+order.Status)
+	c.JSON(http.StatusOK, order)
+}
+
+// GetEcommerceReconciliation godoc
+// @Summary      Online store reconciliation report
+// @Description  Reads the store's catalog and compares it with the items: products whose price or stock differ, product SKUs that are not item IDs and active items not listed in the store, plus the number of failed pushes and web orders.
+// @Tags         ecommerce
+// @Produce      json
+// @Success      200  {object}  dtos.EcommerceReconciliationDTO  "Reconciliation report"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error building the report"
+// @Failure      502  {object}  dtos.ErrorResponse  "The store could not be reached"
+// @Security     ApiKeyAuth
+// @Router       /ecommerce/reconciliation [get]
+func (ec *EcommerceController) GetEcommerceReconciliation(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_ECOMMERCE_RECONCILIATION
+	if !ec.Auth.CheckPermission(c, permissionId) {
+		_ = ec.Log.RegisterLog(c, "Access denied for GetEcommerceReconciliation")
+		return
+	}
+
+	report, err := ec.Service.Reconcile(c.Request.Context())
+	if err != nil {
+		_ = ec.Log.RegisterLog(c, "Error building the store reconciliation report: "+err.Error())
+		if errors.Is(err, services.ErrEcommerceStoreUnavailable) {
+			utilities.RespondError(c, http.StatusBadGateway, "The online store could not be reached")
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error building the report")
+		return
+	}
+
+	_ = ec.Log.RegisterLog(c, "Successfully built the store reconciliation report")
+	c.JSON(http.StatusOK, report)
+}
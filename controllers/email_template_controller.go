@@ -0,0 +1,278 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type EmailTemplateController struct {
+	Service *services.EmailTemplateService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewEmailTemplateController(service *services.EmailTemplateService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *EmailTemplateController {
+	return &EmailTemplateController{Service: service, Auth: auth, Log: log}
+}
+
+// GetEmailTemplates godoc
+// @Summary      List email templates
+// @Description  Lists every email template with its active version (0 means the built-in template is used).
+// @Tags         email-templates
+// @Produce      json
+// @Success      200  {array}   dtos.EmailTemplateSummaryDTO  "Email templates"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving email templates"
+// @Security     ApiKeyAuth
+// @Router       /email-templates [get]
+func (etc *EmailTemplateController) GetEmailTemplates(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_EMAIL_TEMPLATES
+	if !etc.Auth.CheckPermission(c, permissionId) {
+		_ = etc.Log.RegisterLog(c, "Access denied for GetEmailTemplates")
+		return
+	}
+
+	templates, err := etc.Service.ListTemplates(c.Request.Context())
+	if err != nil {
+		_ = etc.Log.RegisterLog(c, "Error retrieving email templates: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving email templates")
+		return
+	}
+
+	_ = etc.Log.RegisterLog(c, "Successfully retrieved email templates")
+	c.JSON(http.StatusOK, templates)
+}
+
+// GetEmailTemplate godoc
+// @Summary      Get an email template
+// @Description  Returns the subject and HTML body currently in use and the placeholders available to them, e.g. {{.CustomerName}}.
+// @Description  Helpers: {{date .Field}}, {{clock .Field}}, {{money .Field}} and {{company}}.
+// @Tags         email-templates
+// @Produce      json
+// @Param        name  path  string  true  "Template name"
+// @Success      200  {object}  dtos.EmailTemplateDTO  "Email template"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Template not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving email template"
+// @Security     ApiKeyAuth
+// @Router       /email-templates/{name} [get]
+func (etc *EmailTemplateController) GetEmailTemplate(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_EMAIL_TEMPLATES
+	if !etc.Auth.CheckPermission(c, permissionId) {
+		_ = etc.Log.RegisterLog(c, "Access denied for GetEmailTemplate")
+		return
+	}
+
+	template, err := etc.Service.GetTemplate(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		etc.respondTemplateError(c, err, "Error retrieving email template")
+		return
+	}
+
+	_ = etc.Log.RegisterLog(c, "Successfully retrieved email template: "+c.Param("name"))
+	c.JSON(http.StatusOK, template)
+}
+
+// GetEmailTemplateVersions godoc
+// @Summary      List the versions of an email template
+// @Tags         email-templates
+// @Produce      json
+// @Param        name  path  string  true  "Template name"
+// @Success      200  {array}   models.EmailTemplateVersion  "Versions, newest first"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Template not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving template versions"
+// @Security     ApiKeyAuth
+// @Router       /email-templates/{name}/versions [get]
+func (etc *EmailTemplateController) GetEmailTemplateVersions(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_EMAIL_TEMPLATES
+	if !etc.Auth.CheckPermission(c, permissionId) {
+		_ = etc.Log.RegisterLog(c, "Access denied for GetEmailTemplateVersions")
+		return
+	}
+
+	versions, err := etc.Service.GetVersions(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		etc.respondTemplateError(c, err, "Error retrieving template versions")
+		return
+	}
+
+	_ = etc.Log.RegisterLog(c, "Successfully retrieved versions of email template: "+c.Param("name"))
+	c.JSON(http.StatusOK, versions)
+}
+
+// CreateEmailTemplateVersion godoc
+// @Summary      Save a new version of an email template
+// @Description  Validates the subject and body against the template's sample data, stores them as a new version and makes it the active one.
+// @Tags         email-templates
+// @Accept       json
+// @Produce      json
+// @Param        name     path  string  true  "Template name"
+// @Param        version  body  dtos.CreateEmailTemplateVersionDTO  true  "Subject and HTML body"
+// @Success      201  {object}  models.EmailTemplateVersion  "Created version"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid template"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Template not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error saving template version"
+// @Security     ApiKeyAuth
+// @Router       /email-templates/{name}/versions [post]
+func (etc *EmailTemplateController) CreateEmailTemplateVersion(c *gin.Context) {
+	permissionId := config.PERMISSION_EDIT_EMAIL_TEMPLATE
+	if !etc.Auth.CheckPermission(c, permissionId) {
+		_ = etc.Log.RegisterLog(c, "Access denied for CreateEmailTemplateVersion")
+		return
+	}
+
+	var dto dtos.CreateEmailTemplateVersionDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = etc.Log.RegisterLog(c, "Invalid JSON for CreateEmailTemplateVersion: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request body", err)
+		return
+	}
+
+	version, err := etc.Service.CreateVersion(c.Request.Context(), c.Param("name"), dto, utilities.CurrentUser(c))
+	if err != nil {
+		etc.respondTemplateError(c, err, "Error saving template version")
+		return
+	}
+
+	_ = etc.Log.RegisterLog(c, "Saved version "+strconv.Itoa(version.Version)+" of email template: "+c.Param("name"))
+	c.JSON(http.StatusCreated, version)
+}
+
+// ActivateEmailTemplateVersion godoc
+// @Summary      Activate a version of an email template
+// @Description  Makes an earlier version the active one. Version 0 goes back to the built-in template.
+// @Tags         email-templates
+// @Param        name     path  string  true  "Template name"
+// @Param        version  path  int     true  "Version to activate"
+// @Success      204  "Version activated"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid version"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Template or version not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error activating template version"
+// @Security     ApiKeyAuth
+// @Router       /email-templates/{name}/versions/{version}/activate [post]
+func (etc *EmailTemplateController) ActivateEmailTemplateVersion(c *gin.Context) {
+	permissionId := config.PERMISSION_EDIT_EMAIL_TEMPLATE
+	if !etc.Auth.CheckPermission(c, permissionId) {
+		_ = etc.Log.RegisterLog(c, "Access denied for ActivateEmailTemplateVersion")
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 0 {
+		_ = etc.Log.RegisterLog(c, "Invalid email template version: "+c.Param("version"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid version")
+		return
+	}
+
+	if err := etc.Service.ActivateVersion(c.Request.Context(), c.Param("name"), version); err != nil {
+		etc.respondTemplateError(c, err, "Error activating template version")
+		return
+	}
+
+	_ = etc.Log.RegisterLog(c, "Activated version "+c.Param("version")+" of email template: "+c.Param("name"))
+	c.Status(http.StatusNoContent)
+}
+
+// PreviewEmailTemplate godoc
+// @Summary      Preview an email template
+// @Description  Renders the active version, or the draft subject/body sent in the request, with sample data. Fields in data override the sample values.
+// @Tags         email-templates
+// @Accept       json
+// @Produce      json
+// @Param        name     path  string  true  "Template name"
+// @Param        preview  body  dtos.PreviewEmailTemplateDTO  false  "Draft and data"
+// @Success      200  {object}  dtos.EmailTemplatePreviewDTO  "Rendered subject and HTML"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid template"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Template not found"
+// @Security     ApiKeyAuth
+// @Router       /email-templates/{name}/preview [post]
+func (etc *EmailTemplateController) PreviewEmailTemplate(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_EMAIL_TEMPLATES
+	if !etc.Auth.CheckPermission(c, permissionId) {
+		_ = etc.Log.RegisterLog(c, "Access denied for PreviewEmailTemplate")
+		return
+	}
+
+	var dto dtos.PreviewEmailTemplateDTO
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&dto); err != nil {
+			_ = etc.Log.RegisterLog(c, "Invalid JSON for PreviewEmailTemplate: "+err.Error())
+			utilities.RespondValidationError(c, "Invalid request body", err)
+			return
+		}
+	}
+
+	preview, err := etc.Service.Preview(c.Request.Context(), c.Param("name"), dto)
+	if err != nil {
+		etc.respondTemplateError(c, err, "Error rendering email template")
+		return
+	}
+
+	_ = etc.Log.RegisterLog(c, "Previewed email template: "+c.Param("name"))
+	c.JSON(http.StatusOK, preview)
+}
+
+// SendTestEmail godoc
+// @Summary      Send a test email
+// @Description  Queues the active version, or the draft sent in the request, rendered with sample data, to the given address. The subject is prefixed with [Prueba].
+// @Tags         email-templates
+// @Accept       json
+// @Produce      json
+// @Param        name  path  string  true  "Template name"
+// @Param        test  body  dtos.SendTestEmailDTO  true  "Recipient, draft and data"
+// @Success      202  {object}  models.EmailMessage  "Queued email"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid template or recipient"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Template not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error queuing test email"
+// @Security     ApiKeyAuth
+// @Router       /email-templates/{name}/test [post]
+func (etc *EmailTemplateController) SendTestEmail(c *gin.Context) {
+	permissionId := config.PERMISSION_SEND_TEST_EMAIL
+	if !etc.Auth.CheckPermission(c, permissionId) {
+		_ = etc.Log.RegisterLog(c, "Access denied for SendTestEmail")
+		return
+	}
+
+	var dto dtos.SendTestEmailDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = etc.Log.RegisterLog(c, "Invalid JSON for SendTestEmail: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request body", err)
+		return
+	}
+
+	message, err := etc.Service.SendTest(c.Request.Context(), c.Param("name"), dto)
+	if err != nil {
+		etc.respondTemplateError(c, err, "Error queuing test email")
+		return
+	}
+
+	_ = etc.Log.RegisterLog(c, "Queued test email of template "+c.Param("name")+" to "+dto.To)
+	c.JSON(http.StatusAccepted, message)
+}
+
+func (etc *EmailTemplateController) respondTemplateError(c *gin.Context, err error, message string) {
+	_ = etc.Log.RegisterLog(c, message+": "+err.Error())
+	switch {
+	case errors.Is(err, services.ErrUnknownEmailTemplate):
+		utilities.RespondError(c, http.StatusNotFound, "Template not found")
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utilities.RespondError(c, http.StatusNotFound, "Template version not found")
+	case errors.Is(err, services.ErrInvalidEmailTemplate), errors.Is(err, services.ErrInvalidEmailRecipient):
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+	default:
+		utilities.RespondError(c, http.StatusInternalServerError, message)
+	}
+}
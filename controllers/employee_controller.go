@@ -31,32 +31,27 @@ func NewEmployeeController(service *services.EmployeeService, auth *utilities.Au
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "Employee ID"
-// @Success      200 {object} dtos.GetEmployeeDTO "Successfully retrieved employee details"
-// @Failure      400 {object} models.ErrorResponse "Invalid employee ID"
-// @Failure      403 {object} models.ErrorResponse "Permission denied"
-// @Failure      404 {object} models.ErrorResponse "Employee not found"
+// @Success      200 {object} dtos.ResourceDTO[dtos.GetEmployeeDTO] "Successfully retrieved employee details, with a link to the employee's user"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid employee ID"
+// @Failure      403 {object} dtos.ErrorResponse "Permission denied"
+// @Failure      404 {object} dtos.ErrorResponse "Employee not found"
 // @Security     ApiKeyAuth
 // @Router       /employees/{id} [get]
 func (ec *EmployeeController) GetEmployeeByID(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_EMPLOYEE_BY_ID
 
-	if ec.Log.RegisterLog(c, "Attempting to get employee by ID") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	if !ec.Auth.CheckPermission(c, permissionId) {
 		_ = ec.Log.RegisterLog(c, "Access denied for GetEmployeeByID")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		utilities.RespondError(c, http.StatusForbidden, "Access denied")
 		return
 	}
 
 	id := c.Param("id")
 
-	employee, err := ec.Service.GetEmployeeByID(id)
+	employee, err := ec.Service.GetEmployeeByID(c.Request.Context(), id)
 	if err != nil {
 		_ = ec.Log.RegisterLog(c, "Employee not found with ID: "+id)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Employee not found")
 		return
 	}
 
@@ -72,7 +67,10 @@ func (ec *EmployeeController) GetEmployeeByID(c *gin.Context) {
 	}
 
 	_ = ec.Log.RegisterLog(c, "Successfully retrieved employee with ID: "+id)
-	c.JSON(http.StatusOK, employeeDTO)
+	c.JSON(http.StatusOK, dtos.NewResourceDTO(employeeDTO, dtos.LinksDTO{
+		"self": utilities.ResourceLink("employees", employeeDTO.ID),
+		"user": utilities.ResourceLink("users", employeeDTO.UserID),
+	}))
 }
 
 // GetAllEmployees godoc
@@ -81,29 +79,33 @@ func (ec *EmployeeController) GetEmployeeByID(c *gin.Context) {
 // @Tags         employees
 // @Accept       json
 // @Produce      json
-// @Success      200 {array} dtos.GetEmployeeDTO "Successfully retrieved list of employees"
-// @Failure      403 {object} models.ErrorResponse "Permission denied"
-// @Failure      500 {object} models.ErrorResponse "Error retrieving employees"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200 {object} dtos.PageDTO[dtos.GetEmployeeDTO] "Successfully retrieved list of employees"
+// @Failure      403 {object} dtos.ErrorResponse "Permission denied"
+// @Failure      500 {object} dtos.ErrorResponse "Error retrieving employees"
 // @Security     ApiKeyAuth
 // @Router       /employees [get]
 func (ec *EmployeeController) GetAllEmployees(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_ALL_EMPLOYEES
 
-	if ec.Log.RegisterLog(c, "Attempting to get all employees") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if !ec.Auth.CheckPermission(c, permissionId) {
+		_ = ec.Log.RegisterLog(c, "Access denied for GetAllEmployees")
+		utilities.RespondError(c, http.StatusForbidden, "Access denied")
 		return
 	}
 
-	if !ec.Auth.CheckPermission(c, permissionId) {
-		_ = ec.Log.RegisterLog(c, "Access denied for GetAllEmployees")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = ec.Log.RegisterLog(c, "Invalid pagination for GetAllEmployees: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	employees, err := ec.Service.GetAllEmployees()
+	employees, total, err := ec.Service.GetAllEmployees(c.Request.Context(), pagination)
 	if err != nil {
 		_ = ec.Log.RegisterLog(c, "Error retrieving employees: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving employees"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving employees")
 		return
 	}
 
@@ -123,7 +125,7 @@ func (ec *EmployeeController) GetAllEmployees(c *gin.Context) {
 	}
 
 	_ = ec.Log.RegisterLog(c, "Successfully retrieved all employees")
-	c.JSON(http.StatusOK, employeesDTO)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(employeesDTO, pagination, total))
 }
 
 // SearchEmployeesByID godoc
@@ -133,37 +135,41 @@ func (ec *EmployeeController) GetAllEmployees(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        id query string true "Employee ID to search for"
-// @Success      200 {array} dtos.GetEmployeeDTO "Successfully found employees matching ID"
-// @Failure      403 {object} models.ErrorResponse "Permission denied"
-// @Failure      404 {object} models.ErrorResponse "No employees found"
-// @Failure      500 {object} models.ErrorResponse "Error retrieving employees"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200 {object} dtos.PageDTO[dtos.GetEmployeeDTO] "Successfully found employees matching ID"
+// @Failure      403 {object} dtos.ErrorResponse "Permission denied"
+// @Failure      404 {object} dtos.ErrorResponse "No employees found"
+// @Failure      500 {object} dtos.ErrorResponse "Error retrieving employees"
 // @Security     ApiKeyAuth
 // @Router       /employees/searchByID [get]
 func (ec *EmployeeController) SearchEmployeesByID(c *gin.Context) {
 	query := c.Query("id")
 	permissionId := config.PERMISSION_SEARCH_EMPLOYEES_BY_ID
 
-	if ec.Log.RegisterLog(c, "Attempting to search employees by ID: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if !ec.Auth.CheckPermission(c, permissionId) {
+		_ = ec.Log.RegisterLog(c, "Access denied for SearchEmployeesByID")
+		utilities.RespondError(c, http.StatusForbidden, "Access denied")
 		return
 	}
 
-	if !ec.Auth.CheckPermission(c, permissionId) {
-		_ = ec.Log.RegisterLog(c, "Access denied for SearchEmployeesByID")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = ec.Log.RegisterLog(c, "Invalid pagination for SearchEmployeesByID: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	employees, err := ec.Service.SearchEmployeesByID(query)
+	employees, total, err := ec.Service.SearchEmployeesByID(c.Request.Context(), query, pagination)
 	if err != nil {
 		_ = ec.Log.RegisterLog(c, "Error retrieving employees by ID: "+query+" - "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving employees"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving employees")
 		return
 	}
 
-	if len(employees) == 0 {
+	if total == 0 {
 		_ = ec.Log.RegisterLog(c, "No employees found with ID: "+query)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No employees found"})
+		utilities.RespondError(c, http.StatusNotFound, "No employees found")
 		return
 	}
 
@@ -180,7 +186,7 @@ func (ec *EmployeeController) SearchEmployeesByID(c *gin.Context) {
 	}
 
 	_ = ec.Log.RegisterLog(c, "Successfully found employees matching ID: "+query)
-	c.JSON(http.StatusOK, employeesDTO)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(employeesDTO, pagination, total))
 }
 
 // SearchEmployeesByName godoc
@@ -190,11 +196,13 @@ func (ec *EmployeeController) SearchEmployeesByID(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        names query string true "Employee name to search for"
-// @Success      200 {array} dtos.GetEmployeeDTO "Successfully found employees matching name"
-// @Failure      400 {object} models.ErrorResponse "Search query is required"
-// @Failure      403 {object} models.ErrorResponse "Permission denied"
-// @Failure      404 {object} models.ErrorResponse "No employees found"
-// @Failure      500 {object} models.ErrorResponse "Error retrieving employees"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200 {object} dtos.PageDTO[dtos.GetEmployeeDTO] "Successfully found employees matching name"
+// @Failure      400 {object} dtos.ErrorResponse "Search query is required"
+// @Failure      403 {object} dtos.ErrorResponse "Permission denied"
+// @Failure      404 {object} dtos.ErrorResponse "No employees found"
+// @Failure      500 {object} dtos.ErrorResponse "Error retrieving employees"
 // @Security     ApiKeyAuth
 // @Router       /employees/searchByName [get]
 func (ec *EmployeeController) SearchEmployeesByName(c *gin.Context) {
@@ -202,33 +210,35 @@ func (ec *EmployeeController) SearchEmployeesByName(c *gin.Context) {
 
 	query := c.Query("names")
 
-	if ec.Log.RegisterLog(c, "Attempting to search employees by name: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	if !ec.Auth.CheckPermission(c, permissionId) {
 		_ = ec.Log.RegisterLog(c, "Access denied for SearchEmployeesByName")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		utilities.RespondError(c, http.StatusForbidden, "Access denied")
 		return
 	}
 
 	if query == "" {
 		_ = ec.Log.RegisterLog(c, "Empty name query provided in SearchEmployeesByName")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query is required"})
+		utilities.RespondError(c, http.StatusBadRequest, "Search query is required")
 		return
 	}
 
-	employees, err := ec.Service.SearchEmployeesByName(query)
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = ec.Log.RegisterLog(c, "Invalid pagination for SearchEmployeesByName: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	employees, total, err := ec.Service.SearchEmployeesByName(c.Request.Context(), query, pagination)
 	if err != nil {
 		_ = ec.Log.RegisterLog(c, "Error retrieving employees by name: "+query+" - "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving employees"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving employees")
 		return
 	}
 
-	if len(employees) == 0 {
+	if total == 0 {
 		_ = ec.Log.RegisterLog(c, "No employees found with name: "+query)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No employees found"})
+		utilities.RespondError(c, http.StatusNotFound, "No employees found")
 		return
 	}
 
@@ -247,7 +257,7 @@ func (ec *EmployeeController) SearchEmployeesByName(c *gin.Context) {
 	}
 
 	_ = ec.Log.RegisterLog(c, "Successfully found employees with name: "+query)
-	c.JSON(http.StatusOK, employeesDTO)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(employeesDTO, pagination, total))
 }
 
 // CreateEmployee godoc
@@ -258,43 +268,38 @@ func (ec *EmployeeController) SearchEmployeesByName(c *gin.Context) {
 // @Produce      json
 // @Param        employee body dtos.CreateEmployeeDTO true "Employee information"
 // @Success      201 {object} dtos.GetEmployeeDTO "Successfully created employee"
-// @Failure      400 {object} models.ErrorResponse "Invalid JSON format, or missing fields"
-// @Failure      403 {object} models.ErrorResponse "Permission denied"
-// @Failure      409 {object} models.ErrorResponse "Employee with this Personal ID already exists"
-// @Failure      500 {object} models.ErrorResponse "Error creating employee"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid JSON format, or missing fields"
+// @Failure      403 {object} dtos.ErrorResponse "Permission denied"
+// @Failure      409 {object} dtos.ErrorResponse "Employee with this Personal ID already exists"
+// @Failure      500 {object} dtos.ErrorResponse "Error creating employee"
 // @Security     ApiKeyAuth
 // @Router       /employees [post]
 func (ec *EmployeeController) CreateEmployee(c *gin.Context) {
 	permissionId := config.PERMISSION_CREATE_EMPLOYEE
 
-	if ec.Log.RegisterLog(c, "Attempting to create an employee") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	if !ec.Auth.CheckPermission(c, permissionId) {
 		_ = ec.Log.RegisterLog(c, "Permission denied for CreateEmployee")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
+		utilities.RespondError(c, http.StatusForbidden, "Permission denied")
 		return
 	}
 
 	var dto dtos.CreateEmployeeDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = ec.Log.RegisterLog(c, "Invalid JSON format: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format", "details": err.Error()})
+		utilities.RespondValidationError(c, "Invalid JSON format", err)
 		return
 	}
 
-	existingEmployee, _ := ec.Service.GetEmployeeByID(dto.PersonalID)
+	existingEmployee, _ := ec.Service.GetEmployeeByID(c.Request.Context(), dto.PersonalID)
 	if existingEmployee != nil {
 		_ = ec.Log.RegisterLog(c, "Attempt to create duplicate employee with PersonalID: "+dto.PersonalID)
-		c.JSON(http.StatusConflict, gin.H{"error": "An employee with this Personal ID already exists"})
+		utilities.RespondError(c, http.StatusConflict, "An employee with this Personal ID already exists")
 		return
 	}
 
 	if dto.UserID <= 0 || dto.IdentifierTypeID <= 0 {
 		_ = ec.Log.RegisterLog(c, "Invalid UserID or IdentifierTypeID: UserID="+strconv.Itoa(dto.UserID)+", IdentifierTypeID="+strconv.Itoa(dto.IdentifierTypeID))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid User ID or Identifier Type ID"})
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid User ID or Identifier Type ID")
 		return
 	}
 
@@ -308,10 +313,10 @@ func (ec *EmployeeController) CreateEmployee(c *gin.Context) {
 		IdentifierTypeID: dto.IdentifierTypeID,
 	}
 
-	createdEmployee, err := ec.Service.CreateEmployee(employee)
+	createdEmployee, err := ec.Service.CreateEmployee(c.Request.Context(), employee)
 	if err != nil {
 		_ = ec.Log.RegisterLog(c, "Error creating employee: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating employee", "details": err.Error()})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error creating employee")
 		return
 	}
 
@@ -339,23 +344,18 @@ func (ec *EmployeeController) CreateEmployee(c *gin.Context) {
 // @Param        id path string true "Employee ID"
 // @Param        employee body dtos.UpdateEmployeeDTO true "Updated employee information"
 // @Success      200 {object} dtos.GetEmployeeDTO "Successfully updated employee"
-// @Failure      400 {object} models.ErrorResponse "Invalid JSON format"
-// @Failure      403 {object} models.ErrorResponse "Permission denied"
-// @Failure      404 {object} models.ErrorResponse "Employee not found"
-// @Failure      500 {object} models.ErrorResponse "Error updating employee"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid JSON format"
+// @Failure      403 {object} dtos.ErrorResponse "Permission denied"
+// @Failure      404 {object} dtos.ErrorResponse "Employee not found"
+// @Failure      500 {object} dtos.ErrorResponse "Error updating employee"
 // @Security     ApiKeyAuth
 // @Router       /employees/{id} [put]
 func (ec *EmployeeController) UpdateEmployee(c *gin.Context) {
 	permissionId := config.PERMISSION_UPDATE_EMPLOYEE
 
-	if err := ec.Log.RegisterLog(c, "Attempting to update an employee"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	if !ec.Auth.CheckPermission(c, permissionId) {
 		_ = ec.Log.RegisterLog(c, "Permission denied for UpdateEmployee")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
+		utilities.RespondError(c, http.StatusForbidden, "Permission denied")
 		return
 	}
 
@@ -364,22 +364,83 @@ func (ec *EmployeeController) UpdateEmployee(c *gin.Context) {
 	var dto dtos.UpdateEmployeeDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = ec.Log.RegisterLog(c, "Invalid JSON in UpdateEmployee: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utilities.RespondValidationError(c, "Invalid request body", err)
 		return
 	}
 
-	employee, err := ec.Service.GetEmployeeByID(id)
+	employee, err := ec.Service.GetEmployeeByID(c.Request.Context(), id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			_ = ec.Log.RegisterLog(c, "Employee not found in UpdateEmployee: ID = "+id)
-			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+			utilities.RespondError(c, http.StatusNotFound, "Employee not found")
 			return
 		}
 		_ = ec.Log.RegisterLog(c, "Error retrieving employee in UpdateEmployee: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
+	ec.saveEmployee(c, id, employee, dto)
+}
+
+// PatchEmployee godoc
+// @Summary      Partially update an employee
+// @Description  Applies a JSON merge patch (RFC 7396) to an employee: only the supplied fields change and null clears a field.
+// @Tags         employees
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Employee ID"
+// @Param        employee body dtos.UpdateEmployeeDTO true "Fields to change"
+// @Success      200 {object} dtos.GetEmployeeDTO "Successfully updated employee"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid patch document"
+// @Failure      403 {object} dtos.ErrorResponse "Permission denied"
+// @Failure      404 {object} dtos.ErrorResponse "Employee not found"
+// @Failure      500 {object} dtos.ErrorResponse "Error updating employee"
+// @Security     ApiKeyAuth
+// @Router       /employees/{id} [patch]
+func (ec *EmployeeController) PatchEmployee(c *gin.Context) {
+	permissionId := config.PERMISSION_UPDATE_EMPLOYEE
+
+	if !ec.Auth.CheckPermission(c, permissionId) {
+		_ = ec.Log.RegisterLog(c, "Permission denied for PatchEmployee")
+		utilities.RespondError(c, http.StatusForbidden, "Permission denied")
+		return
+	}
+
+	id := c.Param("id")
+
+	employee, err := ec.Service.GetEmployeeByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = ec.Log.RegisterLog(c, "Employee not found in PatchEmployee: ID = "+id)
+			utilities.RespondError(c, http.StatusNotFound, "Employee not found")
+			return
+		}
+		_ = ec.Log.RegisterLog(c, "Error retrieving employee in PatchEmployee: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	current := dtos.UpdateEmployeeDTO{
+		Names:            employee.Names,
+		LastNames:        employee.LastNames,
+		PersonalID:       employee.PersonalID,
+		Address:          employee.Address,
+		PhoneNumbers:     employee.PhoneNumbers,
+		UserID:           employee.UserID,
+		IdentifierTypeID: employee.IdentifierTypeID,
+	}
+	var dto dtos.UpdateEmployeeDTO
+	if err := utilities.BindMergePatch(c, current, &dto); err != nil {
+		_ = ec.Log.RegisterLog(c, "Invalid patch document in PatchEmployee: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid patch document", err)
+		return
+	}
+
+	ec.saveEmployee(c, id, employee, dto)
+}
+
+func (ec *EmployeeController) saveEmployee(c *gin.Context, id string, employee *models.Employee, dto dtos.UpdateEmployeeDTO) {
 	employee.Names = dto.Names
 	employee.LastNames = dto.LastNames
 	employee.PersonalID = dto.PersonalID
@@ -388,10 +449,9 @@ func (ec *EmployeeController) UpdateEmployee(c *gin.Context) {
 	employee.UserID = dto.UserID
 	employee.IdentifierTypeID = dto.IdentifierTypeID
 
-	err = ec.Service.UpdateEmployee(employee)
-	if err != nil {
+	if err := ec.Service.UpdateEmployee(c.Request.Context(), employee); err != nil {
 		_ = ec.Log.RegisterLog(c, "Error updating employee: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
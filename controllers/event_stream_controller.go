@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EventStreamController struct {
+	Service *services.EventStreamService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewEventStreamController(service *services.EventStreamService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *EventStreamController {
+	return &EventStreamController{Service: service, Auth: auth, Log: log}
+}
+
+// StreamEvents godoc
+// @Summary      Stream server-side events
+// @Description  Keeps the connection open and pushes events as Server-Sent Events. Only the event types the user has permission to see are sent:
+// @Description  appointment.created (get all appointments), item.low_stock (get all items), comment.created (get all comments).
+// @Description  Each message has the event type as its name and a dtos.StreamEventDTO as JSON data; a ping comment is sent periodically to keep the connection alive.
+// @Tags         events
+// @Produce      text/event-stream
+// @Param        types  query  string  false  "Comma-separated event types to receive (default: every permitted type)"
+// @Success      200  {object}  dtos.StreamEventDTO  "Event stream"
+// @Failure      400  {object}  dtos.ErrorResponse  "Unknown event type"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Authorization service error"
+// @Security     ApiKeyAuth
+// @Router       /events [get]
+func (ec *EventStreamController) StreamEvents(c *gin.Context) {
+	permissionId := config.PERMISSION_SUBSCRIBE_EVENTS
+	if !ec.Auth.CheckPermission(c, permissionId) {
+		_ = ec.Log.RegisterLog(c, "Access denied for StreamEvents")
+		return
+	}
+
+	var requested []string
+	if types := c.Query("types"); types != "" {
+		for _, eventType := range strings.Split(types, ",") {
+			eventType = strings.TrimSpace(eventType)
+			if _, ok := services.StreamEventPermissions[eventType]; !ok {
+				_ = ec.Log.RegisterLog(c, "Unknown event type for StreamEvents: "+eventType)
+				utilities.RespondError(c, http.StatusBadRequest, "Unknown event type '"+eventType+"'")
+				return
+			}
+			requested = append(requested, eventType)
+		}
+	} else {
+		for eventType := range services.StreamEventPermissions {
+			requested = append(requested, eventType)
+		}
+	}
+
+	// cada tipo de evento exige el mismo permiso que su listado
+	username := utilities.CurrentUser(c)
+	var allowed []string
+	for _, eventType := range requested {
+		hasPermission, err := ec.Auth.Service.UserHasPermission(c.Request.Context(), username, services.StreamEventPermissions[eventType])
+		if err != nil {
+			_ = ec.Log.RegisterLog(c, "Error checking event permissions: "+err.Error())
+			utilities.RespondError(c, http.StatusInternalServerError, "Authorization service error")
+			return
+		}
+		if hasPermission {
+			allowed = append(allowed, eventType)
+		}
+	}
+	if len(allowed) == 0 {
+		_ = ec.Log.RegisterLog(c, "No permitted event types for StreamEvents")
+		utilities.RespondError(c, http.StatusForbidden, "User does not have permission for any of the requested events")
+		return
+	}
+
+	subscription := ec.Service.Subscribe(allowed)
+	defer ec.Service.Unsubscribe(subscription)
+	_ = ec.Log.RegisterLog(c, "Subscribed to event stream: "+strings.Join(allowed, ","))
+
+	utilities.ClearWriteDeadline(c)
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(config.EVENT_STREAM_HEARTBEAT)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return false
+			}
+			_, _ = io.WriteString(w, "id: "+strconv.FormatInt(event.ID, 10)+"\n")
+			c.SSEvent(event.Type, event)
+			return true
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": ping\n\n")
+			return err == nil
+		}
+	})
+}
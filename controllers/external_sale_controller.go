@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"totesbackend/config"
@@ -30,29 +31,24 @@ func NewExternalSaleController(service *services.ExternalSaleService, auth *util
 // @Produce      json
 // @Param        id path string true "External Sale ID"
 // @Success      200 {object} dtos.GetExternalSaleDTO "Successfully retrieved external sale"
-// @Failure      403 {object} models.ErrorResponse "Access denied"
-// @Failure      404 {object} models.ErrorResponse "External sale not found"
-// @Failure      500 {object} models.ErrorResponse "Error retrieving external sale"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "External sale not found"
+// @Failure      500 {object} dtos.ErrorResponse "Error retrieving external sale"
 // @Security     ApiKeyAuth
 // @Router       /external-sales/{id} [get]
 func (esc *ExternalSaleController) GetExternalSaleByID(c *gin.Context) {
 	id := c.Param("id")
 
-	if esc.Log.RegisterLog(c, "Fetching external sale by ID: "+id) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_GET_EXTERNAL_SALE_BY_ID
 	if !esc.Auth.CheckPermission(c, permissionId) {
 		_ = esc.Log.RegisterLog(c, "Access denied for GetExternalSaleByID")
 		return
 	}
 
-	externalSale, err := esc.Service.GetExternalSaleByID(id)
+	externalSale, err := esc.Service.GetExternalSaleByID(c.Request.Context(), id)
 	if err != nil {
 		_ = esc.Log.RegisterLog(c, "External Sale not found with ID: "+id)
-		c.JSON(http.StatusNotFound, gin.H{"error": "External Sale not found"})
+		utilities.RespondError(c, http.StatusNotFound, "External Sale not found")
 		return
 	}
 
@@ -78,27 +74,31 @@ func (esc *ExternalSaleController) GetExternalSaleByID(c *gin.Context) {
 // @Tags         external-sales
 // @Accept       json
 // @Produce      json
-// @Success      200 {array} dtos.GetExternalSaleDTO "Successfully retrieved all external sales"
-// @Failure      403 {object} models.ErrorResponse "Access denied"
-// @Failure      500 {object} models.ErrorResponse "Error retrieving external sales"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200 {object} dtos.PageDTO[dtos.GetExternalSaleDTO] "Successfully retrieved all external sales"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      500 {object} dtos.ErrorResponse "Error retrieving external sales"
 // @Security     ApiKeyAuth
 // @Router       /external-sales [get]
 func (esc *ExternalSaleController) GetAllExternalSales(c *gin.Context) {
-	if esc.Log.RegisterLog(c, "Fetching all external sales") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_GET_ALL_EXTERNAL_SALES
 	if !esc.Auth.CheckPermission(c, permissionId) {
 		_ = esc.Log.RegisterLog(c, "Access denied for GetAllExternalSales")
 		return
 	}
 
-	externalSales, err := esc.Service.GetAllExternalSales()
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = esc.Log.RegisterLog(c, "Invalid pagination for GetAllExternalSales: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	externalSales, total, err := esc.Service.GetAllExternalSales(c.Request.Context(), pagination)
 	if err != nil {
 		_ = esc.Log.RegisterLog(c, "Error retrieving external sales")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving external sales"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving external sales")
 		return
 	}
 
@@ -120,32 +120,27 @@ func (esc *ExternalSaleController) GetAllExternalSales(c *gin.Context) {
 
 	_ = esc.Log.RegisterLog(c, "Successfully retrieved all external sales")
 
-	c.JSON(http.StatusOK, externalSalesDTO)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(externalSalesDTO, pagination, total))
 }
 
 // CreateExternalSale godoc
 // @Summary      Create a new external sale
-// @Description  Creates a new external sale, including the sale details and customer information.
+// @Description  Creates a new external sale, including the sale details and customer information. The sold units are subtracted from the item's stock; if it is not enough nothing is created and the response lists the shortage.
 // @Tags         external-sales
 // @Accept       json
 // @Produce      json
 // @Param        external-sale body dtos.CreateExternalSaleDTO true "External Sale data"
 // @Success      201 {object} dtos.GetExternalSaleDTO "Successfully created external sale"
-// @Failure      400 {object} models.ErrorResponse "Invalid JSON format"
-// @Failure      500 {object} models.ErrorResponse "Error creating external sale"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid JSON format"
+// @Failure      409 {object} dtos.ErrorResponse "Insufficient stock; details.items lists the item with the quantity requested and available"
+// @Failure      500 {object} dtos.ErrorResponse "Error creating external sale"
 // @Security     ApiKeyAuth
 // @Router       /external-sales [post]
 func (esc *ExternalSaleController) CreateExternalSale(c *gin.Context) {
-
-	if esc.Log.RegisterLog(c, "Creating new external sale") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	var dto dtos.CreateExternalSaleDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = esc.Log.RegisterLog(c, "Invalid JSON format for external sale")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		utilities.RespondValidationError(c, "Invalid JSON format", err)
 		return
 	}
 
@@ -168,10 +163,15 @@ func (esc *ExternalSaleController) CreateExternalSale(c *gin.Context) {
 		},
 	}
 
-	externalSaleWithID, err := esc.Service.CreateExternalSale(&externalSale)
+	externalSaleWithID, err := esc.Service.CreateExternalSale(c.Request.Context(), &externalSale)
 	if err != nil {
-		_ = esc.Log.RegisterLog(c, "Error creating external sale: "+dto.ReporterName)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating external sale"})
+		_ = esc.Log.RegisterLog(c, "Error creating external sale: "+dto.ReporterName+" - "+err.Error())
+		var insufficient *services.InsufficientStockError
+		if errors.As(err, &insufficient) {
+			utilities.RespondErrorWithDetails(c, http.StatusConflict, utilities.ErrCodeInsufficientStock, "Insufficient stock", gin.H{"items": insufficient.Shortages})
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error creating external sale")
 		return
 	}
 
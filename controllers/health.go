@@ -2,19 +2,57 @@ package controllers
 
 import (
 	"net/http"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
 
 	"github.com/gin-gonic/gin"
 )
 
-// ControllerHealthCheck godoc
+type HealthController struct {
+	Service *services.HealthService
+}
+
+func NewHealthController(service *services.HealthService) *HealthController {
+	return &HealthController{Service: service}
+}
+
+// HealthCheck godoc
 // @Summary      Health Check
-// @Description  Returns a 200 status if the server is running correctly.
+// @Description  Returns a 200 status if the server is running correctly, along with database connection pool statistics.
 // @Tags         Health
 // @Produce      json
-// @Success      200  {object}  models.MessageResponse
+// @Success      200  {object}  dtos.HealthDTO
+// @Failure      500  {object}  dtos.ErrorResponse  "Database pool unavailable"
 // @Router       /health [get]
-func ControllerHealthCheck(c *gin.Context) {
-	// IndentedJSON serializes the given struct as pretty JSON (indented + endlines) into the response body.
-	// Includes de status onf http and the struct
-	c.IndentedJSON(http.StatusOK, gin.H{"message": "Successful Health Check."})
+func (hc *HealthController) HealthCheck(c *gin.Context) {
+	poolStats, err := hc.Service.GetDBPoolStats()
+	if err != nil {
+		utilities.RespondError(c, http.StatusInternalServerError, "Database pool unavailable")
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, dtos.HealthDTO{
+		Message:  "Successful Health Check.",
+		Database: *poolStats,
+	})
+}
+
+// ReadinessCheck godoc
+// @Summary      Readiness check
+// @Description  Pings the database and every read replica and checks that no migration is pending, each within 2 seconds.
+// @Description  Answers 200 when every dependency is up and 503 otherwise, with the status of each, for orchestration readiness probes.
+// @Tags         Health
+// @Produce      json
+// @Success      200  {object}  dtos.ReadinessDTO  "Every dependency is up"
+// @Failure      503  {object}  dtos.ReadinessDTO  "Some dependency is down"
+// @Router       /health/ready [get]
+func (hc *HealthController) ReadinessCheck(c *gin.Context) {
+	readiness := hc.Service.CheckReadiness(c.Request.Context())
+
+	status := http.StatusOK
+	if readiness.Status != services.HEALTH_STATUS_READY {
+		status = http.StatusServiceUnavailable
+	}
+	c.IndentedJSON(status, readiness)
 }
@@ -1,12 +1,16 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
 	"totesbackend/services"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type IdentifierTypeController struct {
@@ -25,34 +29,38 @@ func NewIdentifierTypeController(service *services.IdentifierTypeService, auth *
 // @Tags         identifier-types
 // @Accept       json
 // @Produce      json
-// @Success      200 {array} models.IdentifierType "Successfully retrieved identifier types"
-// @Failure      500 {object} models.ErrorResponse "Error retrieving identifier types"
-// @Failure      403 {object} models.ErrorResponse "Access denied"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200 {object} dtos.PageDTO[models.IdentifierType] "Successfully retrieved identifier types"
+// @Failure      500 {object} dtos.ErrorResponse "Error retrieving identifier types"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
 // @Security     ApiKeyAuth
 // @Router       /identifier-types [get]
 func (itc *IdentifierTypeController) GetAllIdentifierTypes(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_ALL_IDENTIFIER_TYPES
 
-	if itc.Log.RegisterLog(c, "Attempting to get all identifier types") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if !itc.Auth.CheckPermission(c, permissionId) {
+		_ = itc.Log.RegisterLog(c, "Access denied for GetAllIdentifierTypes")
+		utilities.RespondError(c, http.StatusForbidden, "Access denied")
 		return
 	}
 
-	if !itc.Auth.CheckPermission(c, permissionId) {
-		_ = itc.Log.RegisterLog(c, "Access denied for GetAllIdentifierTypes")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = itc.Log.RegisterLog(c, "Invalid pagination for GetAllIdentifierTypes: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	identifierTypes, err := itc.Service.GetAllIdentifierTypes()
+	identifierTypes, total, err := itc.Service.GetAllIdentifierTypes(c.Request.Context(), pagination)
 	if err != nil {
 		_ = itc.Log.RegisterLog(c, "Error retrieving identifier types: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving Identifier Types"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving Identifier Types")
 		return
 	}
 
 	_ = itc.Log.RegisterLog(c, "Successfully retrieved all identifier types")
-	c.JSON(http.StatusOK, identifierTypes)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(identifierTypes, pagination, total))
 }
 
 // GetIdentifierTypeByID godoc
@@ -63,33 +71,172 @@ func (itc *IdentifierTypeController) GetAllIdentifierTypes(c *gin.Context) {
 // @Produce      json
 // @Param        id  path      string  true  "Identifier Type ID"
 // @Success      200 {object} models.IdentifierType "Successfully retrieved identifier type"
-// @Failure      404 {object} models.ErrorResponse "Identifier Type not found"
-// @Failure      403 {object} models.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "Identifier Type not found"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
 // @Security     ApiKeyAuth
 // @Router       /identifier-types/{id} [get]
 func (itc *IdentifierTypeController) GetIdentifierTypeByID(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_IDENTIFIER_TYPE_BY_ID
 
-	if itc.Log.RegisterLog(c, "Attempting to get identifier type by ID") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	if !itc.Auth.CheckPermission(c, permissionId) {
 		_ = itc.Log.RegisterLog(c, "Access denied for GetIdentifierTypeByID")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		utilities.RespondError(c, http.StatusForbidden, "Access denied")
 		return
 	}
 
 	id := c.Param("id")
 
-	identifierType, err := itc.Service.GetIdentifierTypeByID(id)
+	identifierType, err := itc.Service.GetIdentifierTypeByID(c.Request.Context(), id)
 	if err != nil {
 		_ = itc.Log.RegisterLog(c, "Identifier type not found with ID: "+id)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Identifier Type not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Identifier Type not found")
 		return
 	}
 
 	_ = itc.Log.RegisterLog(c, "Successfully retrieved identifier type with ID: "+id)
 	c.JSON(http.StatusOK, identifierType)
 }
+
+// CreateIdentifierType godoc
+// @Summary      Create an identifier type
+// @Description  Adds a document type (e.g. PPT or a foreign ID) for customers and employees. Names are unique regardless of case.
+// @Tags         identifier-types
+// @Accept       json
+// @Produce      json
+// @Param        identifierType  body      dtos.IdentifierTypeDTO  true  "Identifier type"
+// @Success      201 {object} models.IdentifierType "Created identifier type"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid request data"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      409 {object} dtos.ErrorResponse "Name already in use"
+// @Failure      500 {object} dtos.ErrorResponse "Error creating identifier type"
+// @Security     ApiKeyAuth
+// @Router       /identifier-types [post]
+func (itc *IdentifierTypeController) CreateIdentifierType(c *gin.Context) {
+	permissionId := config.PERMISSION_CREATE_IDENTIFIER_TYPE
+
+	if !itc.Auth.CheckPermission(c, permissionId) {
+		_ = itc.Log.RegisterLog(c, "Access denied for CreateIdentifierType")
+		utilities.RespondError(c, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	var dto dtos.IdentifierTypeDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = itc.Log.RegisterLog(c, "Invalid identifier type data: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	identifierType, err := itc.Service.CreateIdentifierType(c.Request.Context(), dto)
+	if err != nil {
+		_ = itc.Log.RegisterLog(c, "Error creating identifier type: "+err.Error())
+		itc.respondWriteError(c, err, "Error creating Identifier Type")
+		return
+	}
+
+	_ = itc.Log.RegisterLog(c, "Successfully created identifier type with ID: "+strconv.Itoa(identifierType.ID))
+	c.JSON(http.StatusCreated, identifierType)
+}
+
+// UpdateIdentifierType godoc
+// @Summary      Rename an identifier type
+// @Description  Changes the name of an identifier type; customers and employees that use it keep it.
+// @Tags         identifier-types
+// @Accept       json
+// @Produce      json
+// @Param        id              path      int                     true  "Identifier Type ID"
+// @Param        identifierType  body      dtos.IdentifierTypeDTO  true  "Identifier type"
+// @Success      200 {object} models.IdentifierType "Updated identifier type"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid request data"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "Identifier Type not found"
+// @Failure      409 {object} dtos.ErrorResponse "Name already in use"
+// @Failure      500 {object} dtos.ErrorResponse "Error updating identifier type"
+// @Security     ApiKeyAuth
+// @Router       /identifier-types/{id} [put]
+func (itc *IdentifierTypeController) UpdateIdentifierType(c *gin.Context) {
+	permissionId := config.PERMISSION_UPDATE_IDENTIFIER_TYPE
+
+	if !itc.Auth.CheckPermission(c, permissionId) {
+		_ = itc.Log.RegisterLog(c, "Access denied for UpdateIdentifierType")
+		utilities.RespondError(c, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = itc.Log.RegisterLog(c, "Invalid identifier type ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid Identifier Type ID")
+		return
+	}
+
+	var dto dtos.IdentifierTypeDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = itc.Log.RegisterLog(c, "Invalid identifier type data: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	identifierType, err := itc.Service.UpdateIdentifierType(c.Request.Context(), id, dto)
+	if err != nil {
+		_ = itc.Log.RegisterLog(c, "Error updating identifier type with ID "+c.Param("id")+": "+err.Error())
+		itc.respondWriteError(c, err, "Error updating Identifier Type")
+		return
+	}
+
+	_ = itc.Log.RegisterLog(c, "Successfully updated identifier type with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, identifierType)
+}
+
+// DeleteIdentifierType godoc
+// @Summary      Delete an identifier type
+// @Description  Deletes an identifier type that no customer, employee or appointment uses.
+// @Tags         identifier-types
+// @Produce      json
+// @Param        id  path  int  true  "Identifier Type ID"
+// @Success      200 {object} models.MessageResponse "Identifier type deleted"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid Identifier Type ID"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "Identifier Type not found"
+// @Failure      409 {object} dtos.ErrorResponse "Identifier type in use"
+// @Failure      500 {object} dtos.ErrorResponse "Error deleting identifier type"
+// @Security     ApiKeyAuth
+// @Router       /identifier-types/{id} [delete]
+func (itc *IdentifierTypeController) DeleteIdentifierType(c *gin.Context) {
+	permissionId := config.PERMISSION_DELETE_IDENTIFIER_TYPE
+
+	if !itc.Auth.CheckPermission(c, permissionId) {
+		_ = itc.Log.RegisterLog(c, "Access denied for DeleteIdentifierType")
+		utilities.RespondError(c, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = itc.Log.RegisterLog(c, "Invalid identifier type ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid Identifier Type ID")
+		return
+	}
+
+	if err := itc.Service.DeleteIdentifierType(c.Request.Context(), id); err != nil {
+		_ = itc.Log.RegisterLog(c, "Error deleting identifier type with ID "+c.Param("id")+": "+err.Error())
+		itc.respondWriteError(c, err, "Error deleting Identifier Type")
+		return
+	}
+
+	_ = itc.Log.RegisterLog(c, "Successfully deleted identifier type with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, gin.H{"message": "Identifier Type deleted successfully"})
+}
+
+func (itc *IdentifierTypeController) respondWriteError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utilities.RespondError(c, http.StatusNotFound, "Identifier Type not found")
+	case errors.Is(err, services.ErrInvalidIdentifierTypeName):
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrIdentifierTypeNameTaken), errors.Is(err, services.ErrIdentifierTypeInUse):
+		utilities.RespondError(c, http.StatusConflict, err.Error())
+	default:
+		utilities.RespondError(c, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InventoryReportController struct {
+	Service *services.InventoryReportService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewInventoryReportController(service *services.InventoryReportService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *InventoryReportController {
+	return &InventoryReportController{Service: service, Auth: auth, Log: log}
+}
+
+// GetInventoryTurnover godoc
+// @Summary      Inventory turnover report
+// @Description  Computes turnover (units sold / current stock) and days of stock left per item or per category for the given period.
+// @Description  Items with stock but no sales in the period are flagged as dead stock.
+// @Tags         reports
+// @Produce      json
+// @Param        from     query  string  true   "Start date (YYYY-MM-DD)"
+// @Param        to       query  string  true   "End date, inclusive (YYYY-MM-DD)"
+// @Param        groupBy  query  string  false  "Grouping: item or category (default item)"
+// @Param        format   query  string  false  "Output format: json, csv or xlsx (also negotiated through the Accept header)"
+// @Success      200  {object}  dtos.InventoryTurnoverReportDTO  "Turnover figures"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid dates or grouping"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error building the report"
+// @Security     ApiKeyAuth
+// @Router       /reports/inventory-turnover [get]
+func (irc *InventoryReportController) GetInventoryTurnover(c *gin.Context) {
+	fromStr := c.Query("from")
+	toStr := c.Query("to")
+	groupBy := c.DefaultQuery("groupBy", "item")
+
+	permissionId := config.PERMISSION_VIEW_INVENTORY_TURNOVER_REPORT
+	if !irc.Auth.CheckPermission(c, permissionId) {
+		_ = irc.Log.RegisterLog(c, "Access denied for GetInventoryTurnover")
+		return
+	}
+
+	format, err := utilities.ReportFormat(c)
+	if err != nil {
+		_ = irc.Log.RegisterLog(c, "Invalid report format: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	from, err := time.Parse("2006-01-02", fromStr)
+	if err != nil {
+		_ = irc.Log.RegisterLog(c, "Invalid from date: "+fromStr)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid 'from' date format. Use YYYY-MM-DD")
+		return
+	}
+
+	to, err := time.Parse("2006-01-02", toStr)
+	if err != nil {
+		_ = irc.Log.RegisterLog(c, "Invalid to date: "+toStr)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid 'to' date format. Use YYYY-MM-DD")
+		return
+	}
+	to = to.Add(24*time.Hour - time.Nanosecond)
+
+	report, err := irc.Service.GetInventoryTurnover(c.Request.Context(), from, to, groupBy)
+	if err != nil {
+		_ = irc.Log.RegisterLog(c, "Error building inventory turnover report: "+err.Error())
+		if errors.Is(err, services.ErrInvalidReportParams) {
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error building inventory turnover report")
+		return
+	}
+
+	if format != utilities.ReportFormatJSON {
+		if err := utilities.WriteReportTable(c, format, inventoryTurnoverTable(report)); err != nil {
+			_ = irc.Log.RegisterLog(c, "Error exporting inventory turnover report: "+err.Error())
+			return
+		}
+		_ = irc.Log.RegisterLog(c, "Successfully exported inventory turnover report between "+fromStr+" and "+toStr)
+		return
+	}
+
+	_ = irc.Log.RegisterLog(c, "Successfully built inventory turnover report between "+fromStr+" and "+toStr)
+	c.JSON(http.StatusOK, report)
+}
+
+func inventoryTurnoverTable(report *dtos.InventoryTurnoverReportDTO) utilities.ReportTable {
+	return utilities.ReportTable{
+		Name:   "inventory-turnover-" + report.GroupBy,
+		Header: []string{"item_id", "item_name", "category_id", "category_name", "stock", "units_sold", "turnover", "days_of_stock", "dead_stock"},
+		Rows: func(write func(row []interface{}) error) error {
+			for _, r := range report.Rows {
+				if err := write([]interface{}{r.ItemID, r.ItemName, r.CategoryID, r.CategoryName, r.Stock, r.UnitsSold, r.Turnover, r.DaysOfStock, r.DeadStock}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
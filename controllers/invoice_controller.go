@@ -1,8 +1,10 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
 	"totesbackend/dtos"
@@ -10,12 +12,14 @@ import (
 	"totesbackend/services"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type InvoiceController struct {
-	Service *services.InvoiceService
-	Auth    *utilities.AuthorizationUtil
-	Log     *utilities.LogUtil //
+	Service   *services.InvoiceService
+	Reminders *services.PaymentReminderService
+	Auth      *utilities.AuthorizationUtil
+	Log       *utilities.LogUtil //
 }
 
 func NewInvoiceController(
@@ -33,33 +37,37 @@ func NewInvoiceController(
 // @Tags         invoices
 // @Accept       json
 // @Produce      json
-// @Success      200 {array} dtos.GetInvoiceDTO "List of all invoices"
-// @Failure      404 {object} models.ErrorResponse "No invoices found"
-// @Failure      403 {object} models.ErrorResponse "Access denied"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200 {object} dtos.PageDTO[dtos.GetInvoiceDTO] "List of all invoices"
+// @Failure      404 {object} dtos.ErrorResponse "No invoices found"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
 // @Security     ApiKeyAuth
 // @Router       /invoices [get]
 func (ic *InvoiceController) GetAllInvoices(c *gin.Context) {
-	if ic.Log.RegisterLog(c, "Attempting to retrieve all invoices") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_GET_ALL_INVOICES
 	if !ic.Auth.CheckPermission(c, permissionId) {
 		_ = ic.Log.RegisterLog(c, "Access denied for GetAllInvoices")
 		return
 	}
 
-	invoices, err := ic.Service.GetAllInvoices()
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = ic.Log.RegisterLog(c, "Invalid pagination for GetAllInvoices: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	invoices, total, err := ic.Service.GetAllInvoices(c.Request.Context(), pagination)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Error retrieving invoices: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve invoices"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Failed to retrieve invoices")
 		return
 	}
 
-	if len(invoices) == 0 {
+	if total == 0 {
 		_ = ic.Log.RegisterLog(c, "No invoices found")
-		c.JSON(http.StatusNotFound, gin.H{"error": "No invoices found"})
+		utilities.RespondError(c, http.StatusNotFound, "No invoices found")
 		return
 	}
 
@@ -72,14 +80,22 @@ func (ic *InvoiceController) GetAllInvoices(c *gin.Context) {
 			CustomerID:     invoice.CustomerID,
 			Subtotal:       invoice.Subtotal,
 			Total:          invoice.Total,
+			Currency:       invoice.Currency,
+			ExchangeRate:   invoice.ExchangeRate,
+			PaymentStatus:  invoice.PaymentStatus,
+			AmountPaid:     invoice.AmountPaid,
+			Balance:        services.InvoiceBalance(&invoice),
 			Items:          extractInvoiceBillingItems(invoice.Items),
 			Discounts:      extractDiscountIds(invoice.Discounts),
 			Taxes:          extractTaxIds(invoice.Taxes),
+			DueDate:        invoice.DueDate,
+			PaidAt:         invoice.PaidAt,
+			Version:        invoice.Version,
 		})
 	}
 
 	_ = ic.Log.RegisterLog(c, "Successfully retrieved all invoices")
-	c.JSON(http.StatusOK, invoiceDTOs)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(invoiceDTOs, pagination, total))
 }
 
 // GetInvoiceByID godoc
@@ -89,18 +105,14 @@ func (ic *InvoiceController) GetAllInvoices(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        id   path      int  true  "Invoice ID"
-// @Success      200 {object} dtos.GetInvoiceDTO "Invoice details"
-// @Failure      400 {object} models.ErrorResponse "Invalid invoice ID"
-// @Failure      404 {object} models.ErrorResponse "Invoice not found"
-// @Failure      403 {object} models.ErrorResponse "Access denied"
+// @Success      200 {object} dtos.ResourceDTO[dtos.GetInvoiceDTO] "Invoice details with links to its customer, items and reminders"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid invoice ID"
+// @Failure      404 {object} dtos.ErrorResponse "Invoice not found"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
 // @Security     ApiKeyAuth
 // @Router       /invoices/{id} [get]
 func (ic *InvoiceController) GetInvoiceByID(c *gin.Context) {
 	idParam := c.Param("id")
-	if ic.Log.RegisterLog(c, "Attempting to retrieve invoice with ID: "+idParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
 
 	permissionId := config.PERMISSION_GET_INVOICE_BY_ID
 	if !ic.Auth.CheckPermission(c, permissionId) {
@@ -111,14 +123,14 @@ func (ic *InvoiceController) GetInvoiceByID(c *gin.Context) {
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Invalid invoice ID: "+idParam)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid invoice ID")
 		return
 	}
 
-	invoice, err := ic.Service.GetInvoiceByID(strconv.Itoa(id))
+	invoice, err := ic.Service.GetInvoiceByID(c.Request.Context(), strconv.Itoa(id))
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Invoice not found with ID: "+idParam)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Invoice not found")
 		return
 	}
 
@@ -129,13 +141,35 @@ func (ic *InvoiceController) GetInvoiceByID(c *gin.Context) {
 		CustomerID:     invoice.CustomerID,
 		Subtotal:       invoice.Subtotal,
 		Total:          invoice.Total,
+		Currency:       invoice.Currency,
+		ExchangeRate:   invoice.ExchangeRate,
+		PaymentStatus:  invoice.PaymentStatus,
+		AmountPaid:     invoice.AmountPaid,
+		Balance:        services.InvoiceBalance(invoice),
 		Items:          extractInvoiceBillingItems(invoice.Items),
 		Discounts:      extractDiscountIds(invoice.Discounts),
 		Taxes:          extractTaxIds(invoice.Taxes),
+		DueDate:        invoice.DueDate,
+		PaidAt:         invoice.PaidAt,
+		Version:        invoice.Version,
 	}
 
 	_ = ic.Log.RegisterLog(c, "Successfully retrieved invoice with ID: "+idParam)
-	c.JSON(http.StatusOK, invoiceDTO)
+	c.JSON(http.StatusOK, dtos.NewResourceDTO(invoiceDTO, invoiceLinks(invoiceDTO)))
+}
+
+func invoiceLinks(invoice dtos.GetInvoiceDTO) dtos.LinksDTO {
+	itemIDs := make([]int, len(invoice.Items))
+	for i, item := range invoice.Items {
+		itemIDs[i] = item.ID
+	}
+	self := utilities.ResourceLink("invoices", invoice.ID)
+	return dtos.LinksDTO{
+		"self":      self,
+		"customer":  utilities.ResourceLink("customers", invoice.CustomerID),
+		"items":     utilities.ResourceLinks("items", itemIDs),
+		"reminders": self + "/reminders",
+	}
 }
 
 // SearchInvoiceByID godoc
@@ -145,20 +179,17 @@ func (ic *InvoiceController) GetInvoiceByID(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        id   query     string  true  "Invoice ID Query"
-// @Success      200 {array} dtos.GetInvoiceDTO "List of invoices found"
-// @Failure      400 {object} models.ErrorResponse "Query parameter is required"
-// @Failure      404 {object} models.ErrorResponse "No invoices found"
-// @Failure      403 {object} models.ErrorResponse "Access denied"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200 {object} dtos.PageDTO[dtos.GetInvoiceDTO] "List of invoices found"
+// @Failure      400 {object} dtos.ErrorResponse "Query parameter is required"
+// @Failure      404 {object} dtos.ErrorResponse "No invoices found"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
 // @Security     ApiKeyAuth
 // @Router       /invoices/searchById [get]
 func (ic *InvoiceController) SearchInvoiceByID(c *gin.Context) {
 	query := c.Query("id")
 
-	if ic.Log.RegisterLog(c, "Attempting to search invoice(s) by ID query: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_SEARCH_INVOICE_BY_ID
 	if !ic.Auth.CheckPermission(c, permissionId) {
 		_ = ic.Log.RegisterLog(c, "Access denied for SearchInvoiceByID")
@@ -167,14 +198,21 @@ func (ic *InvoiceController) SearchInvoiceByID(c *gin.Context) {
 
 	if query == "" {
 		_ = ic.Log.RegisterLog(c, "Missing query parameter for SearchInvoiceByID")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter is required"})
+		utilities.RespondError(c, http.StatusBadRequest, "Query parameter is required")
+		return
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = ic.Log.RegisterLog(c, "Invalid pagination for SearchInvoiceByID: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	invoices, err := ic.Service.SearchInvoiceByID(query)
+	invoices, total, err := ic.Service.SearchInvoiceByID(c.Request.Context(), query, pagination)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Error searching invoices by ID query "+query+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error searching invoices"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error searching invoices")
 		return
 	}
 
@@ -187,14 +225,22 @@ func (ic *InvoiceController) SearchInvoiceByID(c *gin.Context) {
 			CustomerID:     invoice.CustomerID,
 			Subtotal:       invoice.Subtotal,
 			Total:          invoice.Total,
+			Currency:       invoice.Currency,
+			ExchangeRate:   invoice.ExchangeRate,
+			PaymentStatus:  invoice.PaymentStatus,
+			AmountPaid:     invoice.AmountPaid,
+			Balance:        services.InvoiceBalance(&invoice),
 			Items:          extractInvoiceBillingItems(invoice.Items),
 			Discounts:      extractDiscountIds(invoice.Discounts),
 			Taxes:          extractTaxIds(invoice.Taxes),
+			DueDate:        invoice.DueDate,
+			PaidAt:         invoice.PaidAt,
+			Version:        invoice.Version,
 		})
 	}
 
 	_ = ic.Log.RegisterLog(c, "Successfully retrieved "+strconv.Itoa(len(invoiceDTOs))+" invoice(s) for search ID: "+query)
-	c.JSON(http.StatusOK, invoiceDTOs)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(invoiceDTOs, pagination, total))
 }
 
 // SearchInvoiceByCustomerPersonalId godoc
@@ -204,20 +250,17 @@ func (ic *InvoiceController) SearchInvoiceByID(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        personal_id   query     string  true  "Customer Personal ID Query"
-// @Success      200 {array} dtos.GetInvoiceDTO "List of invoices found"
-// @Failure      400 {object} models.ErrorResponse "Query parameter 'personal_id' is required"
-// @Failure      404 {object} models.ErrorResponse "No invoices found"
-// @Failure      403 {object} models.ErrorResponse "Access denied"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200 {object} dtos.PageDTO[dtos.GetInvoiceDTO] "List of invoices found"
+// @Failure      400 {object} dtos.ErrorResponse "Query parameter 'personal_id' is required"
+// @Failure      404 {object} dtos.ErrorResponse "No invoices found"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
 // @Security     ApiKeyAuth
 // @Router       /invoices/searchByPersonalId [get]
 func (ic *InvoiceController) SearchInvoiceByCustomerPersonalId(c *gin.Context) {
 	query := c.Query("personal_id")
 
-	if ic.Log.RegisterLog(c, "Attempting to search invoice(s) by customer personal ID: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_SEARCH_INVOICE_BY_CUSTOMER_PERSONAL_ID
 	if !ic.Auth.CheckPermission(c, permissionId) {
 		_ = ic.Log.RegisterLog(c, "Access denied for SearchInvoiceByCustomerPersonalId")
@@ -226,14 +269,21 @@ func (ic *InvoiceController) SearchInvoiceByCustomerPersonalId(c *gin.Context) {
 
 	if query == "" {
 		_ = ic.Log.RegisterLog(c, "Missing query parameter 'personal_id' for SearchInvoiceByCustomerPersonalId")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'personal_id' is required"})
+		utilities.RespondError(c, http.StatusBadRequest, "Query parameter 'personal_id' is required")
+		return
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = ic.Log.RegisterLog(c, "Invalid pagination for SearchInvoiceByCustomerPersonalId: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	invoices, err := ic.Service.SearchInvoiceByCustomerPersonalId(query)
+	invoices, total, err := ic.Service.SearchInvoiceByCustomerPersonalId(c.Request.Context(), query, pagination)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Error searching invoices by customer personal ID "+query+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error searching invoices by customer personal ID"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error searching invoices by customer personal ID")
 		return
 	}
 
@@ -246,14 +296,22 @@ func (ic *InvoiceController) SearchInvoiceByCustomerPersonalId(c *gin.Context) {
 			CustomerID:     invoice.CustomerID,
 			Subtotal:       invoice.Subtotal,
 			Total:          invoice.Total,
+			Currency:       invoice.Currency,
+			ExchangeRate:   invoice.ExchangeRate,
+			PaymentStatus:  invoice.PaymentStatus,
+			AmountPaid:     invoice.AmountPaid,
+			Balance:        services.InvoiceBalance(&invoice),
 			Items:          extractInvoiceBillingItems(invoice.Items),
 			Discounts:      extractDiscountIds(invoice.Discounts),
 			Taxes:          extractTaxIds(invoice.Taxes),
+			DueDate:        invoice.DueDate,
+			PaidAt:         invoice.PaidAt,
+			Version:        invoice.Version,
 		})
 	}
 
 	_ = ic.Log.RegisterLog(c, "Successfully retrieved "+strconv.Itoa(len(invoiceDTOs))+" invoice(s) for customer personal ID: "+query)
-	c.JSON(http.StatusOK, invoiceDTOs)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(invoiceDTOs, pagination, total))
 }
 
 // CreateInvoice godoc
@@ -264,17 +322,13 @@ func (ic *InvoiceController) SearchInvoiceByCustomerPersonalId(c *gin.Context) {
 // @Produce      json
 // @Param        invoice_body  body      dtos.CreateInvoiceDTO  true  "Invoice data"
 // @Success      201 {object} dtos.GetInvoiceDTO "Created invoice"
-// @Failure      400 {object} models.ErrorResponse "Invalid request data"
-// @Failure      403 {object} models.ErrorResponse "Access denied"
-// @Failure      500 {object} models.ErrorResponse "Error creating invoice"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid request data, unknown currency, an inactive or expired discount, or an inactive tax"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      409 {object} dtos.ErrorResponse "Insufficient stock; details.items lists each item short with the quantity requested and available"
+// @Failure      500 {object} dtos.ErrorResponse "Error creating invoice"
 // @Security     ApiKeyAuth
 // @Router       /invoices [post]
 func (ic *InvoiceController) CreateInvoice(c *gin.Context) {
-	if ic.Log.RegisterLog(c, "Attempting to create new invoice") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_CREATE_INVOICE
 	if !ic.Auth.CheckPermission(c, permissionId) {
 		_ = ic.Log.RegisterLog(c, "Access denied for CreateInvoice")
@@ -284,14 +338,24 @@ func (ic *InvoiceController) CreateInvoice(c *gin.Context) {
 	var dto dtos.CreateInvoiceDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = ic.Log.RegisterLog(c, "Invalid invoice creation request data: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		utilities.RespondValidationError(c, "Invalid request data", err)
 		return
 	}
 
-	invoice, err := ic.Service.CreateInvoice(&dto)
+	invoice, err := ic.Service.CreateInvoice(c.Request.Context(), &dto)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Error creating invoice: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		var insufficient *services.InsufficientStockError
+		if errors.As(err, &insufficient) {
+			utilities.RespondErrorWithDetails(c, http.StatusConflict, utilities.ErrCodeInsufficientStock, "Insufficient stock", gin.H{"items": insufficient.Shortages})
+			return
+		}
+		if errors.Is(err, services.ErrUnknownCurrency) || errors.Is(err, services.ErrDiscountNotApplicable) ||
+			errors.Is(err, services.ErrTaxTypeInactive) {
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -302,15 +366,249 @@ func (ic *InvoiceController) CreateInvoice(c *gin.Context) {
 		CustomerID:     invoice.CustomerID,
 		Subtotal:       invoice.Subtotal,
 		Total:          invoice.Total,
+		Currency:       invoice.Currency,
+		ExchangeRate:   invoice.ExchangeRate,
+		PaymentStatus:  invoice.PaymentStatus,
+		AmountPaid:     invoice.AmountPaid,
+		Balance:        services.InvoiceBalance(invoice),
 		Items:          extractInvoiceBillingItems(invoice.Items),
 		Discounts:      extractDiscountIds(invoice.Discounts),
 		Taxes:          extractTaxIds(invoice.Taxes),
+		DueDate:        invoice.DueDate,
+		PaidAt:         invoice.PaidAt,
+		Version:        invoice.Version,
 	}
 
 	_ = ic.Log.RegisterLog(c, "Successfully created invoice with ID: "+strconv.Itoa(invoice.ID))
 	c.JSON(http.StatusCreated, invoiceDTO)
 }
 
+// UpdateInvoicePayment godoc
+// @Summary      Mark an invoice as paid or unpaid
+// @Description  Records that the invoice was paid in full (now) or returns it to pending. Pending keeps the payments recorded with POST /invoices/{id}/payments, so the invoice goes back to unpaid or partial. Unpaid invoices with a due date receive payment reminders.
+// @Tags         invoices
+// @Accept       json
+// @Produce      json
+// @Param        id    path  int                     true  "Invoice ID"
+// @Param        body  body  dtos.InvoicePaymentDTO  true  "Payment state"
+// @Success      200 {object} dtos.GetInvoiceDTO "Updated invoice"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid request data"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "Invoice not found"
+// @Failure      500 {object} dtos.ErrorResponse "Error updating invoice"
+// @Failure      409 {object} dtos.ErrorResponse "Version conflict: the record changed since it was read"
+// @Security     ApiKeyAuth
+// @Router       /invoices/{id}/payment [patch]
+func (ic *InvoiceController) UpdateInvoicePayment(c *gin.Context) {
+	permissionId := config.PERMISSION_UPDATE_INVOICE_PAYMENT
+	if !ic.Auth.CheckPermission(c, permissionId) {
+		_ = ic.Log.RegisterLog(c, "Access denied for UpdateInvoicePayment")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = ic.Log.RegisterLog(c, "Invalid invoice ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid invoice ID")
+		return
+	}
+
+	var dto dtos.InvoicePaymentDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = ic.Log.RegisterLog(c, "Invalid invoice payment request data: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	invoice, err := ic.Service.SetInvoicePaid(c.Request.Context(), id, *dto.Version, *dto.Paid)
+	if err != nil {
+		if errors.Is(err, services.ErrVersionConflict) {
+			_ = ic.Log.RegisterLog(c, "Version conflict updating payment of invoice with ID: "+c.Param("id"))
+			utilities.RespondVersionConflict(c, err.Error())
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = ic.Log.RegisterLog(c, "Invoice not found with ID: "+c.Param("id"))
+			utilities.RespondError(c, http.StatusNotFound, "Invoice not found")
+			return
+		}
+		_ = ic.Log.RegisterLog(c, "Error updating invoice payment: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error updating invoice")
+		return
+	}
+
+	invoiceDTO := dtos.GetInvoiceDTO{
+		ID:             invoice.ID,
+		EnterpriseData: invoice.EnterpriseData,
+		DateTime:       invoice.DateTime,
+		CustomerID:     invoice.CustomerID,
+		Subtotal:       invoice.Subtotal,
+		Total:          invoice.Total,
+		Currency:       invoice.Currency,
+		ExchangeRate:   invoice.ExchangeRate,
+		PaymentStatus:  invoice.PaymentStatus,
+		AmountPaid:     invoice.AmountPaid,
+		Balance:        services.InvoiceBalance(invoice),
+		Items:          extractInvoiceBillingItems(invoice.Items),
+		Discounts:      extractDiscountIds(invoice.Discounts),
+		Taxes:          extractTaxIds(invoice.Taxes),
+		DueDate:        invoice.DueDate,
+		PaidAt:         invoice.PaidAt,
+		Version:        invoice.Version,
+	}
+
+	_ = ic.Log.RegisterLog(c, "Updated payment of invoice with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, invoiceDTO)
+}
+
+// GetInvoiceReminders godoc
+// @Summary      Get the payment reminder history of an invoice
+// @Description  Lists every payment reminder stage reached by the invoice, including the ones not sent because the customer opted out or has no email.
+// @Tags         invoices
+// @Produce      json
+// @Param        id   path  int  true  "Invoice ID"
+// @Success      200 {array}  models.InvoiceReminder "Reminder history"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid invoice ID"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "Invoice not found"
+// @Failure      500 {object} dtos.ErrorResponse "Error retrieving reminders"
+// @Security     ApiKeyAuth
+// @Router       /invoices/{id}/reminders [get]
+func (ic *InvoiceController) GetInvoiceReminders(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_INVOICE_REMINDERS
+	if !ic.Auth.CheckPermission(c, permissionId) {
+		_ = ic.Log.RegisterLog(c, "Access denied for GetInvoiceReminders")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = ic.Log.RegisterLog(c, "Invalid invoice ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid invoice ID")
+		return
+	}
+
+	reminders, err := ic.Reminders.GetInvoiceReminders(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = ic.Log.RegisterLog(c, "Invoice not found with ID: "+c.Param("id"))
+			utilities.RespondError(c, http.StatusNotFound, "Invoice not found")
+			return
+		}
+		_ = ic.Log.RegisterLog(c, "Error retrieving invoice reminders: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving reminders")
+		return
+	}
+
+	_ = ic.Log.RegisterLog(c, "Successfully retrieved reminders of invoice with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, reminders)
+}
+
+// RecordInvoicePayment godoc
+// @Summary      Record a payment against an invoice
+// @Description  Records a payment, in the invoice currency, and updates the amount paid and the payment status: partial while there is an outstanding balance and paid when it is settled. A payment cannot exceed the outstanding balance nor be recorded against a cancelled invoice. Methods: cash, card, transfer, check, online, other.
+// @Tags         invoices
+// @Accept       json
+// @Produce      json
+// @Param        id       path  int                    true  "Invoice ID"
+// @Param        payment  body  dtos.CreatePaymentDTO  true  "Payment"
+// @Success      201 {object} dtos.PaymentRecordedDTO "Recorded payment and invoice balance"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid payment"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "Invoice not found"
+// @Failure      409 {object} dtos.ErrorResponse "The invoice is cancelled or the payment exceeds its outstanding balance"
+// @Failure      500 {object} dtos.ErrorResponse "Error recording payment"
+// @Security     ApiKeyAuth
+// @Router       /invoices/{id}/payments [post]
+func (ic *InvoiceController) RecordInvoicePayment(c *gin.Context) {
+	permissionId := config.PERMISSION_RECORD_INVOICE_PAYMENT
+	if !ic.Auth.CheckPermission(c, permissionId) {
+		_ = ic.Log.RegisterLog(c, "Access denied for RecordInvoicePayment")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = ic.Log.RegisterLog(c, "Invalid invoice ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid invoice ID")
+		return
+	}
+
+	var dto dtos.CreatePaymentDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = ic.Log.RegisterLog(c, "Invalid payment data: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	payment, invoice, err := ic.Service.RecordPayment(c.Request.Context(), id, dto)
+	if err != nil {
+		_ = ic.Log.RegisterLog(c, "Error recording payment of invoice with ID "+c.Param("id")+": "+err.Error())
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utilities.RespondError(c, http.StatusNotFound, "Invoice not found")
+		case errors.Is(err, services.ErrInvalidPayment):
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, services.ErrInvoiceCancelled), errors.Is(err, services.ErrPaymentExceedsBalance):
+			utilities.RespondError(c, http.StatusConflict, err.Error())
+		default:
+			utilities.RespondError(c, http.StatusInternalServerError, "Error recording payment")
+		}
+		return
+	}
+
+	_ = ic.Log.RegisterLog(c, "Recorded payment "+strconv.Itoa(payment.ID)+" of invoice with ID: "+c.Param("id"))
+	c.JSON(http.StatusCreated, dtos.PaymentRecordedDTO{
+		Payment:       *payment,
+		AmountPaid:    invoice.AmountPaid,
+		Balance:       services.InvoiceBalance(invoice),
+		PaymentStatus: invoice.PaymentStatus,
+	})
+}
+
+// GetInvoicePayments godoc
+// @Summary      Get the payments of an invoice
+// @Description  Lists the payments recorded against the invoice, oldest first, with the amount paid, the outstanding balance and the payment status.
+// @Tags         invoices
+// @Produce      json
+// @Param        id   path  int  true  "Invoice ID"
+// @Success      200 {object} dtos.InvoicePaymentsDTO "Payments and balance"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid invoice ID"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "Invoice not found"
+// @Failure      500 {object} dtos.ErrorResponse "Error retrieving payments"
+// @Security     ApiKeyAuth
+// @Router       /invoices/{id}/payments [get]
+func (ic *InvoiceController) GetInvoicePayments(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_INVOICE_PAYMENTS
+	if !ic.Auth.CheckPermission(c, permissionId) {
+		_ = ic.Log.RegisterLog(c, "Access denied for GetInvoicePayments")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = ic.Log.RegisterLog(c, "Invalid invoice ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid invoice ID")
+		return
+	}
+
+	payments, err := ic.Service.GetInvoicePayments(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = ic.Log.RegisterLog(c, "Invoice not found with ID: "+c.Param("id"))
+			utilities.RespondError(c, http.StatusNotFound, "Invoice not found")
+			return
+		}
+		_ = ic.Log.RegisterLog(c, "Error retrieving invoice payments: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving payments")
+		return
+	}
+
+	_ = ic.Log.RegisterLog(c, "Successfully retrieved payments of invoice with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, payments)
+}
+
 func extractInvoiceBillingItems(items []models.InvoiceItem) []dtos.BillingItemDTO {
 	var billingItems []dtos.BillingItemDTO
 	for _, item := range items {
@@ -337,3 +635,69 @@ func extractTaxIds(taxes []models.TaxType) []int {
 	}
 	return ids
 }
+
+var invoiceExportColumns = []utilities.ExportColumn[models.Invoice]{
+	{Name: "id", Value: func(i models.Invoice) interface{} { return i.ID }},
+	{Name: "date_time", Value: func(i models.Invoice) interface{} { return i.DateTime }},
+	{Name: "customer_id", Value: func(i models.Invoice) interface{} { return i.CustomerID }},
+	{Name: "customer_document", Value: func(i models.Invoice) interface{} { return i.Customer.CustomerId }},
+	{Name: "customer_name", Value: func(i models.Invoice) interface{} {
+		return strings.TrimSpace(i.Customer.CustomerName + " " + i.Customer.LastName)
+	}},
+	{Name: "units", Value: func(i models.Invoice) interface{} {
+		units := 0
+		for _, item := range i.Items {
+			units += item.Amount
+		}
+		return units
+	}},
+	{Name: "subtotal", Value: func(i models.Invoice) interface{} { return i.Subtotal }},
+	{Name: "total", Value: func(i models.Invoice) interface{} { return i.Total }},
+	{Name: "due_date", Value: func(i models.Invoice) interface{} { return i.DueDate }},
+	{Name: "paid_at", Value: func(i models.Invoice) interface{} { return i.PaidAt }},
+	{Name: "payment_status", Value: func(i models.Invoice) interface{} { return i.PaymentStatus }},
+	{Name: "amount_paid", Value: func(i models.Invoice) interface{} { return i.AmountPaid }},
+	{Name: "cancelled_at", Value: func(i models.Invoice) interface{} { return i.CancelledAt }},
+}
+
+// ExportInvoices godoc
+// @Summary      Export invoices to CSV
+// @Description  Streams the invoices, cancelled ones included, as CSV. "columns" picks and orders the columns (all by default); from/to filter by invoice date.
+// @Tags         invoices
+// @Produce      text/csv
+// @Param        columns  query  string  false  "Comma separated columns: id, date_time, customer_id, customer_document, customer_name, units, subtotal, total, due_date, paid_at, payment_status, amount_paid, cancelled_at"
+// @Param        from     query  string  false  "Issued from (YYYY-MM-DD or RFC3339)"
+// @Param        to       query  string  false  "Issued until, inclusive (YYYY-MM-DD or RFC3339)"
+// @Success      200  {file}   file  "CSV file"
+// @Failure      400  {object} dtos.ErrorResponse "Unknown column or invalid date"
+// @Failure      403  {object} dtos.ErrorResponse "Access denied"
+// @Security     ApiKeyAuth
+// @Router       /invoices/export [get]
+func (ic *InvoiceController) ExportInvoices(c *gin.Context) {
+	permissionId := config.PERMISSION_EXPORT_INVOICES
+	if !ic.Auth.CheckPermission(c, permissionId) {
+		_ = ic.Log.RegisterLog(c, "Access denied for ExportInvoices")
+		return
+	}
+
+	filter, ok := parseCSVExportFilter(c, ic.Log)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	table, err := utilities.ExportTable(c, "invoices", invoiceExportColumns, func(fn func(models.Invoice) error) error {
+		return ic.Service.StreamInvoices(ctx, filter, fn)
+	})
+	if err != nil {
+		_ = ic.Log.RegisterLog(c, "Invalid export columns: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := utilities.WriteReportTable(c, utilities.ReportFormatCSV, table); err != nil {
+		_ = ic.Log.RegisterLog(c, "Error exporting invoices: "+err.Error())
+		return
+	}
+	_ = ic.Log.RegisterLog(c, "Successfully exported invoices")
+}
@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type InvoiceReturnController struct {
+	Service *services.InvoiceReturnService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewInvoiceReturnController(service *services.InvoiceReturnService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *InvoiceReturnController {
+	return &InvoiceReturnController{Service: service, Auth: auth, Log: log}
+}
+
+// CreateInvoiceReturn godoc
+// @Summary      Return items of an invoice
+// @Description  Returns part of what was sold in the invoice: each line is checked against the units sold minus those already returned, the units go back to stock with an invoice_return stock movement per item, and a return document linked to the invoice records the amount to refund and how (refund_method, one of the payment methods) and when (refund_date, now by default) it was paid out. Lines are valued at the item price in effect on the invoice date, in the invoice currency, and the total applies the invoice's discounts and taxes in proportion. Cancelled invoices cannot take returns.
+// @Tags         invoice-returns
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int                          true  "Invoice ID"
+// @Param        return  body      dtos.CreateInvoiceReturnDTO  true  "Items and quantities returned"
+// @Success      201     {object}  models.InvoiceReturn  "Return document"
+// @Failure      400     {object}  dtos.ErrorResponse  "Invalid request data, refund method or refund date"
+// @Failure      403     {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404     {object}  dtos.ErrorResponse  "Invoice not found"
+// @Failure      409     {object}  dtos.ErrorResponse  "The invoice is cancelled, or RETURN_EXCEEDS_SOLD with details.items"
+// @Failure      500     {object}  dtos.ErrorResponse  "Error returning items"
+// @Security     ApiKeyAuth
+// @Router       /invoices/{id}/returns [post]
+func (rc *InvoiceReturnController) CreateInvoiceReturn(c *gin.Context) {
+	permissionId := config.PERMISSION_CREATE_INVOICE_RETURN
+	if !rc.Auth.CheckPermission(c, permissionId) {
+		_ = rc.Log.RegisterLog(c, "Access denied for CreateInvoiceReturn")
+		return
+	}
+
+	idStr := c.Param("id")
+	invoiceID, err := strconv.Atoi(idStr)
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Invalid invoice ID: "+idStr)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid invoice ID")
+		return
+	}
+
+	var dto dtos.CreateInvoiceReturnDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = rc.Log.RegisterLog(c, "Invalid return data: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	invoiceReturn, err := rc.Service.CreateReturn(c.Request.Context(), invoiceID, dto)
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Error returning items of invoice with ID "+idStr+": "+err.Error())
+		var exceeds *services.ReturnExceedsSoldError
+		switch {
+		case errors.As(err, &exceeds):
+			utilities.RespondErrorWithDetails(c, http.StatusConflict, utilities.ErrCodeReturnExceedsSold, "Return exceeds the units sold",
+				gin.H{"items": exceeds.Excesses})
+		case errors.Is(err, services.ErrInvalidReturn):
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utilities.RespondError(c, http.StatusNotFound, "Invoice not found")
+		case errors.Is(err, services.ErrInvoiceCancelled):
+			utilities.RespondError(c, http.StatusConflict, err.Error())
+		default:
+			utilities.RespondError(c, http.StatusInternalServerError, "Error returning items")
+		}
+		return
+	}
+
+	_ = rc.Log.RegisterLog(c, "Successfully returned items of invoice with ID "+idStr+" with return "+strconv.Itoa(invoiceReturn.ID))
+	c.JSON(http.StatusCreated, invoiceReturn)
+}
+
+// GetInvoiceReturns godoc
+// @Summary      List the returns of an invoice
+// @Description  Returns the return documents of the invoice, oldest first.
+// @Tags         invoice-returns
+// @Produce      json
+// @Param        id   path      int  true  "Invoice ID"
+// @Success      200  {array}   models.InvoiceReturn  "Return documents"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid invoice ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving returns"
+// @Security     ApiKeyAuth
+// @Router       /invoices/{id}/returns [get]
+func (rc *InvoiceReturnController) GetInvoiceReturns(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_INVOICE_RETURNS
+	if !rc.Auth.CheckPermission(c, permissionId) {
+		_ = rc.Log.RegisterLog(c, "Access denied for GetInvoiceReturns")
+		return
+	}
+
+	idStr := c.Param("id")
+	invoiceID, err := strconv.Atoi(idStr)
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Invalid invoice ID: "+idStr)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid invoice ID")
+		return
+	}
+
+	returns, err := rc.Service.GetInvoiceReturns(c.Request.Context(), invoiceID)
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Error retrieving returns of invoice with ID "+idStr+": "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving returns")
+		return
+	}
+
+	_ = rc.Log.RegisterLog(c, "Successfully retrieved returns of invoice with ID: "+idStr)
+	c.JSON(http.StatusOK, returns)
+}
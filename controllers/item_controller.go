@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
@@ -19,10 +20,11 @@ type ItemController struct {
 	Service *services.ItemService
 	Auth    *utilities.AuthorizationUtil
 	Log     *utilities.LogUtil
+	Audit   *utilities.AuditUtil
 }
 
-func NewItemController(service *services.ItemService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *ItemController {
-	return &ItemController{Service: service, Auth: auth, Log: log}
+func NewItemController(service *services.ItemService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil, audit *utilities.AuditUtil) *ItemController {
+	return &ItemController{Service: service, Auth: auth, Log: log, Audit: audit}
 }
 
 // CheckItemStock godoc
@@ -34,20 +36,15 @@ func NewItemController(service *services.ItemService, auth *utilities.Authorizat
 // @Param        id       path     string  true  "Item ID"
 // @Param        quantity query    int     true  "Quantity to check"
 // @Success      200 {boolean} true "Indicates whether the stock is sufficient or not"
-// @Failure      400 {object} models.ErrorResponse "Invalid quantity"
-// @Failure      403 {object} models.ErrorResponse "Access denied"
-// @Failure      500 {object} models.ErrorResponse "Error checking stock"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid quantity"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      500 {object} dtos.ErrorResponse "Error checking stock"
 // @Security     ApiKeyAuth
 // @Router       /items/{id}/stock [get]
 func (ic *ItemController) CheckItemStock(c *gin.Context) {
 	idParam := c.Param("id")
 	quantityParam := c.Query("quantity")
 
-	if ic.Log.RegisterLog(c, "Checking stock for item ID: "+idParam+" with quantity: "+quantityParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_CHECK_ITEM_STOCK
 	if !ic.Auth.CheckPermission(c, permissionId) {
 		_ = ic.Log.RegisterLog(c, "Access denied for CheckItemStock")
@@ -57,14 +54,14 @@ func (ic *ItemController) CheckItemStock(c *gin.Context) {
 	quantity, err := strconv.Atoi(quantityParam)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Invalid quantity: "+quantityParam)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid quantity"})
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid quantity")
 		return
 	}
 
-	hasStock, err := ic.Service.HasEnoughStock(idParam, quantity)
+	hasStock, err := ic.Service.HasEnoughStock(c.Request.Context(), idParam, quantity)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Error checking stock for item ID "+idParam+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking stock"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error checking stock")
 		return
 	}
 
@@ -80,23 +77,19 @@ func (ic *ItemController) CheckItemStock(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        id   path     string  true  "Item ID"
-// @Success      200  {object} dtos.GetItemDTO "Item found"
-// @Failure      400  {object} models.ErrorResponse "Invalid item ID format"
-// @Failure      404  {object} models.ErrorResponse "Item not found"
-// @Failure      500  {object} models.ErrorResponse "Error fetching item"
+// @Success      200  {object} dtos.ResourceDTO[dtos.GetItemDTO] "Item found, with links to its type and stock"
+// @Failure      400  {object} dtos.ErrorResponse "Invalid item ID format"
+// @Failure      404  {object} dtos.ErrorResponse "Item not found"
+// @Failure      500  {object} dtos.ErrorResponse "Error fetching item"
 // @Security     ApiKeyAuth
 // @Router       /items/{id} [get]
 func (ic *ItemController) GetItemByID(c *gin.Context) {
 	id := c.Param("id")
 
-	if ic.Log.RegisterLog(c, "Fetching item by ID: "+id) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-	item, err := ic.Service.GetItemByID(id)
+	item, err := ic.Service.GetItemByID(c.Request.Context(), id)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Item not found with ID: "+id)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Item not found")
 		return
 	}
 
@@ -109,17 +102,42 @@ func (ic *ItemController) GetItemByID(c *gin.Context) {
 		ID:                 item.ID,
 		Name:               item.Name,
 		Description:        item.Description,
+		SKU:                item.SKU,
+		Barcode:            item.Barcode,
 		Stock:              item.Stock,
 		SellingPrice:       item.SellingPrice,
 		PurchasePrice:      item.PurchasePrice,
+		Currency:           item.Currency,
 		ItemState:          item.ItemState,
+		ReorderLevel:       item.ReorderLevel,
 		ItemTypeID:         item.ItemTypeID,
 		AdditionalExpenses: additionalExpenseIDs,
+		Images:             itemImageDTOs(item.Images),
+		Version:            item.Version,
 	}
 
 	_ = ic.Log.RegisterLog(c, "Successfully fetched item with ID: "+id)
 
-	c.JSON(http.StatusOK, itemDTO)
+	c.JSON(http.StatusOK, dtos.NewResourceDTO(itemDTO, itemLinks(itemDTO)))
+}
+
+func itemLinks(item dtos.GetItemDTO) dtos.LinksDTO {
+	self := utilities.ResourceLink("items", item.ID)
+	return dtos.LinksDTO{
+		"self":                self,
+		"item_type":           utilities.ResourceLink("item-types", item.ItemTypeID),
+		"stock":               self + "/stock",
+		"additional_expenses": utilities.ResourceLinks("additional-expenses", item.AdditionalExpenses),
+		"images":              self + "/images",
+	}
+}
+
+func itemImageDTOs(images []models.ItemImage) []dtos.ItemImageDTO {
+	result := make([]dtos.ItemImageDTO, len(images))
+	for i, image := range images {
+		result[i] = dtos.ItemImageDTO{ID: image.ID, URL: image.URL}
+	}
+	return result
 }
 
 // GetAllItems godoc
@@ -128,20 +146,24 @@ func (ic *ItemController) GetItemByID(c *gin.Context) {
 // @Tags         items
 // @Accept       json
 // @Produce      json
-// @Success      200  {array}  dtos.GetItemDTO "List of items"
-// @Failure      500  {object} models.ErrorResponse "Error retrieving items"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200  {object} dtos.PageDTO[dtos.GetItemDTO] "List of items"
+// @Failure      500  {object} dtos.ErrorResponse "Error retrieving items"
 // @Security     ApiKeyAuth
 // @Router       /items [get]
 func (ic *ItemController) GetAllItems(c *gin.Context) {
-	if ic.Log.RegisterLog(c, "Fetching all items") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = ic.Log.RegisterLog(c, "Invalid pagination for GetAllItems: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	items, err := ic.Service.GetAllItems()
+	items, total, err := ic.Service.GetAllItems(c.Request.Context(), pagination)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Error retrieving items")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving items"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving items")
 		return
 	}
 
@@ -156,12 +178,18 @@ func (ic *ItemController) GetAllItems(c *gin.Context) {
 			ID:                 item.ID,
 			Name:               item.Name,
 			Description:        item.Description,
+			SKU:                item.SKU,
+			Barcode:            item.Barcode,
 			Stock:              item.Stock,
 			SellingPrice:       item.SellingPrice,
 			PurchasePrice:      item.PurchasePrice,
+			Currency:           item.Currency,
 			ItemState:          item.ItemState,
+			ReorderLevel:       item.ReorderLevel,
 			ItemTypeID:         item.ItemTypeID,
 			AdditionalExpenses: additionalExpenseIDs,
+			Images:             itemImageDTOs(item.Images),
+			Version:            item.Version,
 		}
 
 		itemsDTO = append(itemsDTO, itemDTO)
@@ -169,51 +197,55 @@ func (ic *ItemController) GetAllItems(c *gin.Context) {
 
 	_ = ic.Log.RegisterLog(c, "Successfully retrieved all items")
 
-	c.JSON(http.StatusOK, itemsDTO)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(itemsDTO, pagination, total))
 }
 
-// SearchItemsByID godoc
-// @Summary      Search items by ID
-// @Description  Search for items in the inventory by their ID using a query parameter.
+// SearchItems godoc
+// @Summary      Search items
+// @Description  Finds the items whose ID, SKU or barcode is q, or where every word of q appears in the name or description (ignoring case and accents) or starts the SKU. Exact code matches come first, then names starting with q, names containing it and the rest, each by name similarity.
 // @Tags         items
 // @Accept       json
 // @Produce      json
-// @Param        id  query     string  true  "Item ID to search for"
-// @Success      200  {array}  dtos.GetItemDTO "List of items matching the search criteria"
-// @Failure      400  {object} models.ErrorResponse "Missing or invalid search query"
-// @Failure      404  {object} models.ErrorResponse "No items found"
-// @Failure      500  {object} models.ErrorResponse "Error retrieving items"
+// @Param        q         query  string  true  "Search text"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200  {object} dtos.PageDTO[dtos.GetItemDTO] "List of items matching the search criteria"
+// @Failure      400  {object} dtos.ErrorResponse "Missing or invalid search query"
+// @Failure      404  {object} dtos.ErrorResponse "No items found"
+// @Failure      500  {object} dtos.ErrorResponse "Error retrieving items"
 // @Security     ApiKeyAuth
-// @Router       /items/searchById [get]
-func (ic *ItemController) SearchItemsByID(c *gin.Context) {
-	if ic.Log.RegisterLog(c, "Searching items by ID") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
-	permissionId := config.PERMISSION_SEARCH_ITEMS_BY_ID
+// @Router       /items/search [get]
+func (ic *ItemController) SearchItems(c *gin.Context) {
+	permissionId := config.PERMISSION_SEARCH_ITEMS
 	if !ic.Auth.CheckPermission(c, permissionId) {
-		_ = ic.Log.RegisterLog(c, "Access denied for SearchItemsByID")
+		_ = ic.Log.RegisterLog(c, "Access denied for SearchItems")
 		return
 	}
 
-	query := c.Query("id")
+	query := strings.TrimSpace(c.Query("q"))
 	if query == "" {
 		_ = ic.Log.RegisterLog(c, "Search query is missing")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query is required"})
+		utilities.RespondError(c, http.StatusBadRequest, "Search query is required")
+		return
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = ic.Log.RegisterLog(c, "Invalid pagination for SearchItems: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	items, err := ic.Service.SearchItemsByID(query)
+	items, total, err := ic.Service.SearchItems(c.Request.Context(), query, pagination)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Error retrieving items from database")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving items"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving items")
 		return
 	}
 
-	if len(items) == 0 {
+	if total == 0 {
 		_ = ic.Log.RegisterLog(c, "No items found for query: "+query)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No items found"})
+		utilities.RespondError(c, http.StatusNotFound, "No items found")
 		return
 	}
 
@@ -228,12 +260,18 @@ func (ic *ItemController) SearchItemsByID(c *gin.Context) {
 			ID:                 item.ID,
 			Name:               item.Name,
 			Description:        item.Description,
+			SKU:                item.SKU,
+			Barcode:            item.Barcode,
 			Stock:              item.Stock,
 			SellingPrice:       item.SellingPrice,
 			PurchasePrice:      item.PurchasePrice,
+			Currency:           item.Currency,
 			ItemState:          item.ItemState,
+			ReorderLevel:       item.ReorderLevel,
 			ItemTypeID:         item.ItemTypeID,
 			AdditionalExpenses: additionalExpenseIDs,
+			Images:             itemImageDTOs(item.Images),
+			Version:            item.Version,
 		}
 
 		itemsDTO = append(itemsDTO, itemDTO)
@@ -241,79 +279,72 @@ func (ic *ItemController) SearchItemsByID(c *gin.Context) {
 
 	_ = ic.Log.RegisterLog(c, "Successfully retrieved items for query: "+query)
 
-	c.JSON(http.StatusOK, itemsDTO)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(itemsDTO, pagination, total))
 }
 
-// SearchItemsByName godoc
-// @Summary      Search items by name
-// @Description  Search for items in the inventory by their name using a query parameter.
+// GetLowStockItems godoc
+// @Summary      List items low on stock
+// @Description  Returns the active items whose stock is at or below their reorder level, those missing the most units first. Items with reorder level 0 are never listed.
 // @Tags         items
-// @Accept       json
 // @Produce      json
-// @Param        name  query     string  true  "Item name to search for"
-// @Success      200   {array}   dtos.GetItemDTO "List of items matching the search criteria"
-// @Failure      400   {object}  models.ErrorResponse "Missing or invalid search query"
-// @Failure      404   {object}  models.ErrorResponse "No items found"
-// @Failure      500   {object}  models.ErrorResponse "Error retrieving items"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200  {object} dtos.PageDTO[dtos.GetItemDTO] "Items low on stock"
+// @Failure      400  {object} dtos.ErrorResponse "Invalid pagination"
+// @Failure      403  {object} dtos.ErrorResponse "Access denied"
+// @Failure      500  {object} dtos.ErrorResponse "Error retrieving items"
 // @Security     ApiKeyAuth
-// @Router       /items/searchByName [get]
-func (ic *ItemController) SearchItemsByName(c *gin.Context) {
-	if ic.Log.RegisterLog(c, "Searching items by name") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
-	permissionId := config.PERMISSION_SEARCH_ITEMS_BY_NAME
+// @Router       /items/lowStock [get]
+func (ic *ItemController) GetLowStockItems(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_LOW_STOCK_ITEMS
 	if !ic.Auth.CheckPermission(c, permissionId) {
-		_ = ic.Log.RegisterLog(c, "Access denied for SearchItemsByName")
-		return
-	}
-
-	query := c.Query("name")
-	if query == "" {
-		_ = ic.Log.RegisterLog(c, "Search query is missing")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query is required"})
+		_ = ic.Log.RegisterLog(c, "Access denied for GetLowStockItems")
 		return
 	}
 
-	items, err := ic.Service.SearchItemsByName(query)
+	pagination, err := utilities.ParsePagination(c)
 	if err != nil {
-		_ = ic.Log.RegisterLog(c, "Error retrieving items from database")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving items"})
+		_ = ic.Log.RegisterLog(c, "Invalid pagination for GetLowStockItems: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if len(items) == 0 {
-		_ = ic.Log.RegisterLog(c, "No items found for query: "+query)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No items found"})
+	items, total, err := ic.Service.GetLowStockItems(c.Request.Context(), pagination)
+	if err != nil {
+		_ = ic.Log.RegisterLog(c, "Error retrieving low stock items")
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving items")
 		return
 	}
 
-	var itemsDTO []dtos.GetItemDTO
+	itemsDTO := []dtos.GetItemDTO{}
 	for _, item := range items {
 		additionalExpenseIDs := make([]int, len(item.AdditionalExpenses))
 		for i, expense := range item.AdditionalExpenses {
 			additionalExpenseIDs[i] = expense.ID
 		}
 
-		itemDTO := dtos.GetItemDTO{
+		itemsDTO = append(itemsDTO, dtos.GetItemDTO{
 			ID:                 item.ID,
 			Name:               item.Name,
 			Description:        item.Description,
+			SKU:                item.SKU,
+			Barcode:            item.Barcode,
 			Stock:              item.Stock,
 			SellingPrice:       item.SellingPrice,
 			PurchasePrice:      item.PurchasePrice,
+			Currency:           item.Currency,
 			ItemState:          item.ItemState,
+			ReorderLevel:       item.ReorderLevel,
 			ItemTypeID:         item.ItemTypeID,
 			AdditionalExpenses: additionalExpenseIDs,
-		}
-
-		itemsDTO = append(itemsDTO, itemDTO)
+			Images:             itemImageDTOs(item.Images),
+			Version:            item.Version,
+		})
 	}
 
-	_ = ic.Log.RegisterLog(c, "Successfully retrieved items for query: "+query)
+	_ = ic.Log.RegisterLog(c, "Successfully retrieved low stock items")
 
-	c.JSON(http.StatusOK, itemsDTO)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(itemsDTO, pagination, total))
 }
 
 // UpdateItemState godoc
@@ -325,17 +356,13 @@ func (ic *ItemController) SearchItemsByName(c *gin.Context) {
 // @Param        id        path     string  true  "ID of the item to update"
 // @Param        item_state  body     bool    true  "New state for the item (true for active, false for inactive)"
 // @Success      200      {object}  dtos.GetItemDTO "Updated item information"
-// @Failure      400      {object}  models.ErrorResponse "Invalid request body"
-// @Failure      404      {object}  models.ErrorResponse "Item not found"
-// @Failure      500      {object}  models.ErrorResponse "Error updating item state"
+// @Failure      400      {object}  dtos.ErrorResponse "Invalid request body"
+// @Failure      404      {object}  dtos.ErrorResponse "Item not found"
+// @Failure      500      {object}  dtos.ErrorResponse "Error updating item state"
+// @Failure      409      {object}  dtos.ErrorResponse "Version conflict: the record changed since it was read"
 // @Security     ApiKeyAuth
 // @Router       /items/{id}/state [patch]
 func (ic *ItemController) UpdateItemState(c *gin.Context) {
-	if ic.Log.RegisterLog(c, "Updating item state") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_UPDATE_ITEM_STATE
 	if !ic.Auth.CheckPermission(c, permissionId) {
 		_ = ic.Log.RegisterLog(c, "Access denied for UpdateItemState")
@@ -347,18 +374,33 @@ func (ic *ItemController) UpdateItemState(c *gin.Context) {
 
 	var request struct {
 		ItemState bool `json:"item_state"`
+		Version   *int `json:"version" binding:"required"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
 		_ = ic.Log.RegisterLog(c, "Invalid request body")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		utilities.RespondValidationError(c, "Invalid request body", err)
 		return
 	}
 
-	item, err := ic.Service.UpdateItemState(id, request.ItemState)
+	before, err := ic.Service.GetItemByID(c.Request.Context(), id)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Item not found with ID: "+id)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Item not found")
+		return
+	}
+
+	item, err := ic.Service.UpdateItemState(c.Request.Context(), id, *request.Version, request.ItemState)
+	if err != nil {
+		_ = ic.Log.RegisterLog(c, "Error updating state for item ID "+id+": "+err.Error())
+		switch {
+		case errors.Is(err, services.ErrVersionConflict):
+			utilities.RespondVersionConflict(c, err.Error())
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utilities.RespondError(c, http.StatusNotFound, "Item not found")
+		default:
+			utilities.RespondError(c, http.StatusInternalServerError, "Error updating item state")
+		}
 		return
 	}
 
@@ -371,12 +413,22 @@ func (ic *ItemController) UpdateItemState(c *gin.Context) {
 		ID:                 item.ID,
 		Name:               item.Name,
 		Description:        item.Description,
+		SKU:                item.SKU,
+		Barcode:            item.Barcode,
 		Stock:              item.Stock,
 		SellingPrice:       item.SellingPrice,
 		PurchasePrice:      item.PurchasePrice,
+		Currency:           item.Currency,
 		ItemState:          item.ItemState,
+		ReorderLevel:       item.ReorderLevel,
 		ItemTypeID:         item.ItemTypeID,
 		AdditionalExpenses: additionalExpenseIDs,
+		Images:             itemImageDTOs(item.Images),
+		Version:            item.Version,
+	}
+
+	if err := ic.Audit.RecordChange(c, services.AUDIT_ENTITY_ITEM, id, services.AUDIT_ACTION_UPDATE, before, item); err != nil {
+		_ = ic.Log.RegisterLog(c, "Error recording audit trail for item with ID "+id+": "+err.Error())
 	}
 
 	_ = ic.Log.RegisterLog(c, "Successfully updated state for item ID: "+id)
@@ -393,17 +445,13 @@ func (ic *ItemController) UpdateItemState(c *gin.Context) {
 // @Param        id    path      string              true  "ID of the item to update"
 // @Param        item  body      dtos.UpdateItemDTO  true  "Updated item data"
 // @Success      200   {object}  dtos.GetItemDTO      "Item updated successfully"
-// @Failure      400   {object}  models.ErrorResponse "Invalid JSON format"
-// @Failure      404   {object}  models.ErrorResponse "Item not found"
-// @Failure      500   {object}  models.ErrorResponse "Error updating item"
+// @Failure      400   {object}  dtos.ErrorResponse "Invalid JSON format or unknown currency"
+// @Failure      404   {object}  dtos.ErrorResponse "Item not found"
+// @Failure      500   {object}  dtos.ErrorResponse "Error updating item"
+// @Failure      409   {object}  dtos.ErrorResponse "Version conflict, or another item already has the SKU or barcode"
 // @Security     ApiKeyAuth
 // @Router       /items/{id} [put]
 func (ic *ItemController) UpdateItem(c *gin.Context) {
-	if ic.Log.RegisterLog(c, "Updating item") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_UPDATE_ITEM
 	if !ic.Auth.CheckPermission(c, permissionId) {
 		_ = ic.Log.RegisterLog(c, "Access denied for UpdateItem")
@@ -416,37 +464,137 @@ func (ic *ItemController) UpdateItem(c *gin.Context) {
 	var dto dtos.UpdateItemDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = ic.Log.RegisterLog(c, "Invalid JSON format")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		utilities.RespondValidationError(c, "Invalid JSON format", err)
+		return
+	}
+	if dto.Version == nil {
+		_ = ic.Log.RegisterLog(c, "Missing version when updating item with ID: "+id)
+		utilities.RespondVersionRequired(c)
 		return
 	}
 
 	// Buscar el item en la base de datos
-	item, err := ic.Service.GetItemByID(id)
+	item, err := ic.Service.GetItemByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = ic.Log.RegisterLog(c, "Item not found with ID: "+id)
+			utilities.RespondError(c, http.StatusNotFound, "Item not found")
+			return
+		}
+		_ = ic.Log.RegisterLog(c, "Error retrieving item with ID: "+id)
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving item")
+		return
+	}
+
+	ic.saveItem(c, id, item, dto)
+}
+
+// PatchItem godoc
+// @Summary      Partially update an item
+// @Description  Applies a JSON merge patch (RFC 7396) to an item: only the supplied fields change and null clears a field. The version field is required.
+// @Tags         items
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string              true  "ID of the item to update"
+// @Param        item  body      dtos.UpdateItemDTO  true  "Fields to change"
+// @Success      200   {object}  dtos.GetItemDTO     "Item updated successfully"
+// @Failure      400   {object}  dtos.ErrorResponse  "Invalid patch document or unknown currency"
+// @Failure      404   {object}  dtos.ErrorResponse  "Item not found"
+// @Failure      409   {object}  dtos.ErrorResponse  "Version conflict, or another item already has the SKU or barcode"
+// @Failure      500   {object}  dtos.ErrorResponse  "Error updating item"
+// @Security     ApiKeyAuth
+// @Router       /items/{id} [patch]
+func (ic *ItemController) PatchItem(c *gin.Context) {
+	permissionId := config.PERMISSION_UPDATE_ITEM
+	if !ic.Auth.CheckPermission(c, permissionId) {
+		_ = ic.Log.RegisterLog(c, "Access denied for PatchItem")
+		return
+	}
+
+	id := c.Param("id")
+
+	item, err := ic.Service.GetItemByID(c.Request.Context(), id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			_ = ic.Log.RegisterLog(c, "Item not found with ID: "+id)
-			c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+			utilities.RespondError(c, http.StatusNotFound, "Item not found")
 			return
 		}
 		_ = ic.Log.RegisterLog(c, "Error retrieving item with ID: "+id)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving item"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving item")
+		return
+	}
+
+	// la versión no se toma del estado actual: el cliente debe enviar la que leyó
+	current := dtos.UpdateItemDTO{
+		Name:          item.Name,
+		Description:   item.Description,
+		SKU:           item.SKU,
+		Barcode:       item.Barcode,
+		Stock:         item.Stock,
+		SellingPrice:  item.SellingPrice,
+		PurchasePrice: item.PurchasePrice,
+		Currency:      item.Currency,
+		ItemState:     item.ItemState,
+		ReorderLevel:  &item.ReorderLevel,
+		ItemTypeID:    item.ItemTypeID,
+	}
+	var dto dtos.UpdateItemDTO
+	if err := utilities.BindMergePatch(c, current, &dto); err != nil {
+		_ = ic.Log.RegisterLog(c, "Invalid patch document for item with ID "+id+": "+err.Error())
+		utilities.RespondValidationError(c, "Invalid patch document", err)
+		return
+	}
+	if dto.Version == nil {
+		_ = ic.Log.RegisterLog(c, "Missing version when patching item with ID: "+id)
+		utilities.RespondVersionRequired(c)
 		return
 	}
 
+	ic.saveItem(c, id, item, dto)
+}
+
+func (ic *ItemController) saveItem(c *gin.Context, id string, item *models.Item, dto dtos.UpdateItemDTO) {
+	before := *item
+
 	// Asignar los valores del DTO al modelo
 	item.Name = dto.Name
 	item.Description = dto.Description
+	if dto.SKU != nil {
+		item.SKU = dto.SKU
+	}
+	if dto.Barcode != nil {
+		item.Barcode = dto.Barcode
+	}
 	item.Stock = dto.Stock
 	item.SellingPrice = dto.SellingPrice
 	item.PurchasePrice = dto.PurchasePrice
+	if dto.Currency != "" {
+		item.Currency = dto.Currency
+	}
 	item.ItemState = dto.ItemState
 	item.ItemTypeID = dto.ItemTypeID
+	if dto.ReorderLevel != nil {
+		item.ReorderLevel = *dto.ReorderLevel
+	}
+	item.Version = *dto.Version
 
 	// Llamar al servicio para actualizar el item
-	err = ic.Service.UpdateItem(item)
-	if err != nil {
+	if err := ic.Service.UpdateItem(c.Request.Context(), item); err != nil {
 		_ = ic.Log.RegisterLog(c, "Error updating item with ID: "+id)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating item"})
+		if errors.Is(err, services.ErrVersionConflict) {
+			utilities.RespondVersionConflict(c, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrUnknownCurrency) {
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrItemCodeTaken) {
+			utilities.RespondError(c, http.StatusConflict, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error updating item")
 		return
 	}
 
@@ -459,12 +607,22 @@ func (ic *ItemController) UpdateItem(c *gin.Context) {
 		ID:                 item.ID,
 		Name:               item.Name,
 		Description:        item.Description,
+		SKU:                item.SKU,
+		Barcode:            item.Barcode,
 		Stock:              item.Stock,
 		SellingPrice:       item.SellingPrice,
 		PurchasePrice:      item.PurchasePrice,
+		Currency:           item.Currency,
 		ItemState:          item.ItemState,
+		ReorderLevel:       item.ReorderLevel,
 		ItemTypeID:         item.ItemTypeID,
 		AdditionalExpenses: additionalExpenseIDs,
+		Images:             itemImageDTOs(item.Images),
+		Version:            item.Version,
+	}
+
+	if err := ic.Audit.RecordChange(c, services.AUDIT_ENTITY_ITEM, id, services.AUDIT_ACTION_UPDATE, before, item); err != nil {
+		_ = ic.Log.RegisterLog(c, "Error recording audit trail for item with ID "+id+": "+err.Error())
 	}
 
 	_ = ic.Log.RegisterLog(c, "Successfully updated item with ID: "+id)
@@ -480,16 +638,12 @@ func (ic *ItemController) UpdateItem(c *gin.Context) {
 // @Produce      json
 // @Param        item  body      dtos.UpdateItemDTO  true  "Item to create"
 // @Success      201   {object}  dtos.GetItemDTO      "Item created successfully"
-// @Failure      400   {object}  models.ErrorResponse "Invalid JSON format"
-// @Failure      500   {object}  models.ErrorResponse "Error creating item"
+// @Failure      400   {object}  dtos.ErrorResponse "Invalid JSON format or unknown currency"
+// @Failure      409   {object}  dtos.ErrorResponse "Another item already has the SKU or barcode"
+// @Failure      500   {object}  dtos.ErrorResponse "Error creating item"
 // @Security     ApiKeyAuth
 // @Router       /items [post]
 func (ic *ItemController) CreateItem(c *gin.Context) {
-	if ic.Log.RegisterLog(c, "Creating new item") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_CREATE_ITEM
 	if !ic.Auth.CheckPermission(c, permissionId) {
 		_ = ic.Log.RegisterLog(c, "Access denied for CreateItem")
@@ -499,7 +653,7 @@ func (ic *ItemController) CreateItem(c *gin.Context) {
 	var dto dtos.UpdateItemDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = ic.Log.RegisterLog(c, "Invalid JSON format")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		utilities.RespondValidationError(c, "Invalid JSON format", err)
 		return
 	}
 
@@ -509,18 +663,33 @@ func (ic *ItemController) CreateItem(c *gin.Context) {
 	item := models.Item{
 		Name:          dto.Name,
 		Description:   dto.Description,
+		SKU:           dto.SKU,
+		Barcode:       dto.Barcode,
 		Stock:         dto.Stock,
 		SellingPrice:  dto.SellingPrice,
 		PurchasePrice: dto.PurchasePrice,
+		Currency:      dto.Currency,
 		ItemState:     dto.ItemState,
+		ReorderLevel:  config.LOW_STOCK_THRESHOLD,
 		ItemTypeID:    dto.ItemTypeID,
 	}
+	if dto.ReorderLevel != nil {
+		item.ReorderLevel = *dto.ReorderLevel
+	}
 
 	// Llamar al servicio para crear el item
-	itemWithId, err := ic.Service.CreateItem(&item)
+	itemWithId, err := ic.Service.CreateItem(c.Request.Context(), &item)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Error creating item: "+dto.Name)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating item"})
+		if errors.Is(err, services.ErrUnknownCurrency) {
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrItemCodeTaken) {
+			utilities.RespondError(c, http.StatusConflict, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error creating item")
 		return
 	}
 
@@ -533,15 +702,149 @@ func (ic *ItemController) CreateItem(c *gin.Context) {
 		ID:                 itemWithId.ID,
 		Name:               itemWithId.Name,
 		Description:        itemWithId.Description,
+		SKU:                itemWithId.SKU,
+		Barcode:            itemWithId.Barcode,
 		Stock:              itemWithId.Stock,
 		SellingPrice:       itemWithId.SellingPrice,
 		PurchasePrice:      itemWithId.PurchasePrice,
+		Currency:           itemWithId.Currency,
 		ItemState:          itemWithId.ItemState,
+		ReorderLevel:       itemWithId.ReorderLevel,
 		ItemTypeID:         itemWithId.ItemTypeID,
 		AdditionalExpenses: additionalExpenseIDs,
+		Images:             itemImageDTOs(itemWithId.Images),
+		Version:            itemWithId.Version,
 	}
 
 	_ = ic.Log.RegisterLog(c, "Successfully created item with ID: "+strconv.Itoa(dtoGet.ID))
 
 	c.JSON(http.StatusCreated, dtoGet)
 }
+
+// BatchItems godoc
+// @Summary      Create, update and deactivate items in bulk
+// @Description  Runs up to 100 operations in a single transaction. If any operation fails, nothing is saved: the failed operations report their error and the rest are marked rolled_back.
+// @Description  "delete" deactivates the item, like PATCH /items/{id}/state. Each kind of operation requires the same permission as its single-item endpoint.
+// @Tags         items
+// @Accept       json
+// @Produce      json
+// @Param        batch  body      dtos.BatchRequestDTO[dtos.UpdateItemDTO]  true  "Operations to apply"
+// @Success      200    {object}  dtos.BatchResponseDTO  "All operations were applied"
+// @Failure      400    {object}  dtos.ErrorResponse     "Invalid request body"
+// @Failure      403    {object}  dtos.ErrorResponse     "Permission denied"
+// @Failure      422    {object}  dtos.BatchResponseDTO  "At least one operation failed; nothing was applied"
+// @Failure      500    {object}  dtos.ErrorResponse     "Error applying the batch"
+// @Security     ApiKeyAuth
+// @Router       /items/batch [post]
+func (ic *ItemController) BatchItems(c *gin.Context) {
+	var dto dtos.BatchRequestDTO[dtos.UpdateItemDTO]
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = ic.Log.RegisterLog(c, "Invalid JSON format for BatchItems")
+		utilities.RespondValidationError(c, "Invalid JSON format", err)
+		return
+	}
+
+	permissions := map[string]int{
+		services.BATCH_OP_CREATE: config.PERMISSION_CREATE_ITEM,
+		services.BATCH_OP_UPDATE: config.PERMISSION_UPDATE_ITEM,
+		services.BATCH_OP_DELETE: config.PERMISSION_UPDATE_ITEM_STATE,
+	}
+	checked := make(map[string]bool)
+	for _, op := range dto.Operations {
+		if checked[op.Op] {
+			continue
+		}
+		if !ic.Auth.CheckPermission(c, permissions[op.Op]) {
+			_ = ic.Log.RegisterLog(c, "Access denied for BatchItems ("+op.Op+")")
+			return
+		}
+		checked[op.Op] = true
+	}
+
+	response, err := ic.Service.BatchItems(c.Request.Context(), dto.Operations)
+	if err != nil {
+		_ = ic.Log.RegisterLog(c, "Error applying item batch: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error applying the batch")
+		return
+	}
+
+	if !response.Committed {
+		_ = ic.Log.RegisterLog(c, "Item batch rolled back")
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	for _, result := range response.Results {
+		if result.Before == nil {
+			continue
+		}
+		action := services.AUDIT_ACTION_UPDATE
+		if result.Op == services.BATCH_OP_DELETE {
+			action = services.AUDIT_ACTION_DELETE
+		}
+		if err := ic.Audit.RecordChange(c, services.AUDIT_ENTITY_ITEM, strconv.Itoa(result.ID), action, result.Before, result.After); err != nil {
+			_ = ic.Log.RegisterLog(c, "Error recording audit trail for item with ID "+strconv.Itoa(result.ID)+": "+err.Error())
+		}
+	}
+
+	_ = ic.Log.RegisterLog(c, "Successfully applied item batch with "+strconv.Itoa(len(response.Results))+" operations")
+	c.JSON(http.StatusOK, response)
+}
+
+var itemExportColumns = []utilities.ExportColumn[models.Item]{
+	{Name: "id", Value: func(i models.Item) interface{} { return i.ID }},
+	{Name: "name", Value: func(i models.Item) interface{} { return i.Name }},
+	{Name: "description", Value: func(i models.Item) interface{} { return i.Description }},
+	{Name: "sku", Value: func(i models.Item) interface{} { return i.SKU }},
+	{Name: "barcode", Value: func(i models.Item) interface{} { return i.Barcode }},
+	{Name: "item_type", Value: func(i models.Item) interface{} { return i.ItemType.Name }},
+	{Name: "stock", Value: func(i models.Item) interface{} { return i.Stock }},
+	{Name: "reorder_level", Value: func(i models.Item) interface{} { return i.ReorderLevel }},
+	{Name: "selling_price", Value: func(i models.Item) interface{} { return i.SellingPrice }},
+	{Name: "purchase_price", Value: func(i models.Item) interface{} { return i.PurchasePrice }},
+	{Name: "currency", Value: func(i models.Item) interface{} { return i.Currency }},
+	{Name: "item_state", Value: func(i models.Item) interface{} { return i.ItemState }},
+	{Name: "created_at", Value: func(i models.Item) interface{} { return i.CreatedAt }},
+}
+
+// ExportItems godoc
+// @Summary      Export items to CSV
+// @Description  Streams the items as CSV. "columns" picks and orders the columns (all by default); from/to filter by creation date, so items created before creation dates were recorded are only exported without them.
+// @Tags         items
+// @Produce      text/csv
+// @Param        columns  query  string  false  "Comma separated columns: id, name, description, item_type, stock, reorder_level, selling_price, purchase_price, item_state, created_at"
+// @Param        from     query  string  false  "Created from (YYYY-MM-DD or RFC3339)"
+// @Param        to       query  string  false  "Created until, inclusive (YYYY-MM-DD or RFC3339)"
+// @Success      200  {file}   file  "CSV file"
+// @Failure      400  {object} dtos.ErrorResponse "Unknown column or invalid date"
+// @Failure      403  {object} dtos.ErrorResponse "Access denied"
+// @Security     ApiKeyAuth
+// @Router       /items/export [get]
+func (ic *ItemController) ExportItems(c *gin.Context) {
+	permissionId := config.PERMISSION_EXPORT_ITEMS
+	if !ic.Auth.CheckPermission(c, permissionId) {
+		_ = ic.Log.RegisterLog(c, "Access denied for ExportItems")
+		return
+	}
+
+	filter, ok := parseCSVExportFilter(c, ic.Log)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	table, err := utilities.ExportTable(c, "items", itemExportColumns, func(fn func(models.Item) error) error {
+		return ic.Service.StreamItems(ctx, filter, fn)
+	})
+	if err != nil {
+		_ = ic.Log.RegisterLog(c, "Invalid export columns: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := utilities.WriteReportTable(c, utilities.ReportFormatCSV, table); err != nil {
+		_ = ic.Log.RegisterLog(c, "Error exporting items: "+err.Error())
+		return
+	}
+	_ = ic.Log.RegisterLog(c, "Successfully exported items")
+}
@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ItemImageController struct {
+	Service *services.ItemImageService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewItemImageController(service *services.ItemImageService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *ItemImageController {
+	return &ItemImageController{Service: service, Auth: auth, Log: log}
+}
+
+// UploadItemImage godoc
+// @Summary      Upload an item image
+// @Description  Uploads an image of the item as the "file" field of a multipart form. JPEG, PNG, GIF and WebP are accepted, detected from the file content, up to ITEM_IMAGE_MAX_BYTES (5 MB by default) and 10 images per item. The image URL is listed in the item's images.
+// @Tags         items
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id   path     int  true "Item ID"
+// @Param        file formData file true "Image"
+// @Success      201 {object} dtos.ItemImageDTO  "Uploaded image"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid item ID, missing file or image limit reached"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "Item not found"
+// @Failure      413 {object} dtos.ErrorResponse "Image too large"
+// @Failure      415 {object} dtos.ErrorResponse "Unsupported image type"
+// @Failure      500 {object} dtos.ErrorResponse "Error uploading image"
+// @Security     ApiKeyAuth
+// @Router       /items/{id}/images [post]
+func (iic *ItemImageController) UploadItemImage(c *gin.Context) {
+	permissionId := config.PERMISSION_UPLOAD_ITEM_IMAGE
+	if !iic.Auth.CheckPermission(c, permissionId) {
+		_ = iic.Log.RegisterLog(c, "Access denied for UploadItemImage")
+		return
+	}
+
+	itemID, ok := iic.parseID(c, "id", "item")
+	if !ok {
+		return
+	}
+
+	// el formulario puede traer algo más que el archivo; lo que pase de ese margen no se lee
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(iic.Service.MaxBytes)+64<<10)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			_ = iic.Log.RegisterLog(c, "Item image too large for item "+c.Param("id"))
+			utilities.RespondError(c, http.StatusRequestEntityTooLarge, services.ErrItemImageTooLarge.Error())
+			return
+		}
+		_ = iic.Log.RegisterLog(c, "Missing item image file: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, "missing 'file' field in form data")
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		_ = iic.Log.RegisterLog(c, "Error reading item image: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid image file")
+		return
+	}
+	defer file.Close()
+	body, err := io.ReadAll(io.LimitReader(file, int64(iic.Service.MaxBytes)+1))
+	if err != nil {
+		_ = iic.Log.RegisterLog(c, "Error reading item image: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid image file")
+		return
+	}
+
+	image, err := iic.Service.UploadItemImage(c.Request.Context(), itemID, body)
+	if err != nil {
+		_ = iic.Log.RegisterLog(c, "Error uploading image of item "+c.Param("id")+": "+err.Error())
+		iic.respondError(c, err, "Error uploading image")
+		return
+	}
+
+	_ = iic.Log.RegisterLog(c, "Successfully uploaded image "+strconv.Itoa(image.ID)+" of item "+c.Param("id"))
+	c.JSON(http.StatusCreated, dtos.ItemImageDTO{ID: image.ID, URL: image.URL})
+}
+
+// DeleteItemImage godoc
+// @Summary      Delete an item image
+// @Description  Removes the image from the item and deletes its file from storage.
+// @Tags         items
+// @Produce      json
+// @Param        id      path int true "Item ID"
+// @Param        imageId path int true "Image ID"
+// @Success      204 "Image deleted"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid ID"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "Image not found"
+// @Failure      500 {object} dtos.ErrorResponse "Error deleting image"
+// @Security     ApiKeyAuth
+// @Router       /items/{id}/images/{imageId} [delete]
+func (iic *ItemImageController) DeleteItemImage(c *gin.Context) {
+	permissionId := config.PERMISSION_DELETE_ITEM_IMAGE
+	if !iic.Auth.CheckPermission(c, permissionId) {
+		_ = iic.Log.RegisterLog(c, "Access denied for DeleteItemImage")
+		return
+	}
+
+	itemID, ok := iic.parseID(c, "id", "item")
+	if !ok {
+		return
+	}
+	imageID, ok := iic.parseID(c, "imageId", "image")
+	if !ok {
+		return
+	}
+
+	if err := iic.Service.DeleteItemImage(c.Request.Context(), itemID, imageID); err != nil {
+		_ = iic.Log.RegisterLog(c, "Error deleting image "+c.Param("imageId")+" of item "+c.Param("id")+": "+err.Error())
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utilities.RespondError(c, http.StatusNotFound, "Image not found")
+			return
+		}
+		iic.respondError(c, err, "Error deleting image")
+		return
+	}
+
+	_ = iic.Log.RegisterLog(c, "Successfully deleted image "+c.Param("imageId")+" of item "+c.Param("id"))
+	c.Status(http.StatusNoContent)
+}
+
+func (iic *ItemImageController) parseID(c *gin.Context, param string, name string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(param))
+	if err != nil {
+		_ = iic.Log.RegisterLog(c, "Invalid "+name+" ID: "+c.Param(param))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid "+name+" ID")
+		return 0, false
+	}
+	return id, true
+}
+
+func (iic *ItemImageController) respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utilities.RespondError(c, http.StatusNotFound, "Item not found")
+	case errors.Is(err, services.ErrItemImageTooLarge):
+		utilities.RespondError(c, http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, services.ErrItemImageUnsupported):
+		utilities.RespondError(c, http.StatusUnsupportedMediaType, err.Error())
+	case errors.Is(err, services.ErrItemImageLimit):
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+	default:
+		utilities.RespondError(c, http.StatusInternalServerError, message)
+	}
+}
@@ -1,13 +1,17 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"totesbackend/dtos"
 
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
 	"totesbackend/services"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type ItemTypeController struct {
@@ -27,28 +31,23 @@ func NewItemTypeController(service *services.ItemTypeService, auth *utilities.Au
 // @Produce      json
 // @Param        id   path      string                 true  "Item Type ID"
 // @Success      200  {object}  models.ItemType        "Item Type retrieved successfully"
-// @Failure      404  {object}  models.ErrorResponse   "Item Type not found"
-// @Failure      500  {object}  models.ErrorResponse   "Error registering log"
+// @Failure      404  {object}  dtos.ErrorResponse   "Item Type not found"
+// @Failure      500  {object}  dtos.ErrorResponse   "Error registering log"
 // @Security     ApiKeyAuth
 // @Router       /item-types/{id} [get]
 func (itc *ItemTypeController) GetItemTypeByID(c *gin.Context) {
 	id := c.Param("id")
 
-	if itc.Log.RegisterLog(c, "Attempting to retrieve ItemType with ID: "+id) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_GET_ITEM_BY_ID
 	if !itc.Auth.CheckPermission(c, permissionId) {
 		_ = itc.Log.RegisterLog(c, "Access denied for GetItemTypeByID")
 		return
 	}
 
-	itemType, err := itc.Service.GetItemTypeByID(id)
+	itemType, err := itc.Service.GetItemTypeByID(c.Request.Context(), id)
 	if err != nil {
 		_ = itc.Log.RegisterLog(c, "Error retrieving ItemType with ID "+id+": "+err.Error())
-		c.JSON(http.StatusNotFound, gin.H{"error": "Item Type not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Item Type not found")
 		return
 	}
 
@@ -61,29 +60,171 @@ func (itc *ItemTypeController) GetItemTypeByID(c *gin.Context) {
 // @Description  Retrieves a list of all item types.
 // @Tags         item-types
 // @Produce      json
-// @Success      200  {array}   models.ItemType         "List of item types retrieved successfully"
-// @Failure      500  {object}  models.ErrorResponse    "Error retrieving item types or registering log"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[models.ItemType]         "List of item types retrieved successfully"
+// @Failure      500  {object}  dtos.ErrorResponse    "Error retrieving item types or registering log"
 // @Security     ApiKeyAuth
 // @Router       /item-types [get]
 func (itc *ItemTypeController) GetItemTypes(c *gin.Context) {
-	if itc.Log.RegisterLog(c, "Attempting to retrieve all ItemTypes") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_GET_ITEM_TYPES
 	if !itc.Auth.CheckPermission(c, permissionId) {
 		_ = itc.Log.RegisterLog(c, "Access denied for GetItemTypes")
 		return
 	}
 
-	itemTypes, err := itc.Service.GetAllItemTypes()
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = itc.Log.RegisterLog(c, "Invalid pagination for GetItemTypes: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	itemTypes, total, err := itc.Service.GetAllItemTypes(c.Request.Context(), pagination)
 	if err != nil {
 		_ = itc.Log.RegisterLog(c, "Error retrieving ItemTypes: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving Item Types"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving Item Types")
 		return
 	}
 
 	_ = itc.Log.RegisterLog(c, "Successfully retrieved all ItemTypes")
-	c.JSON(http.StatusOK, itemTypes)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(itemTypes, pagination, total))
+}
+
+// CreateItemType godoc
+// @Summary      Create an item type
+// @Description  Adds an item type to the catalog. Names are unique regardless of case.
+// @Tags         item-types
+// @Accept       json
+// @Produce      json
+// @Param        itemType  body      dtos.ItemTypeDTO    true  "Item type"
+// @Success      201  {object}  models.ItemType     "Item Type created successfully"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid request data"
+// @Failure      403  {object}  dtos.ErrorResponse  "Access denied"
+// @Failure      409  {object}  dtos.ErrorResponse  "Name already in use"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error creating item type"
+// @Security     ApiKeyAuth
+// @Router       /item-types [post]
+func (itc *ItemTypeController) CreateItemType(c *gin.Context) {
+	permissionId := config.PERMISSION_CREATE_ITEM_TYPE
+	if !itc.Auth.CheckPermission(c, permissionId) {
+		_ = itc.Log.RegisterLog(c, "Access denied for CreateItemType")
+		return
+	}
+
+	var dto dtos.ItemTypeDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = itc.Log.RegisterLog(c, "Invalid ItemType data: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	itemType, err := itc.Service.CreateItemType(c.Request.Context(), dto)
+	if err != nil {
+		_ = itc.Log.RegisterLog(c, "Error creating ItemType: "+err.Error())
+		itc.respondWriteError(c, err, "Error creating Item Type")
+		return
+	}
+
+	_ = itc.Log.RegisterLog(c, "Successfully created ItemType with ID: "+strconv.Itoa(itemType.ID))
+	c.JSON(http.StatusCreated, itemType)
+}
+
+// UpdateItemType godoc
+// @Summary      Rename an item type
+// @Description  Changes the name of an item type; items that use it keep it.
+// @Tags         item-types
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int               true  "Item Type ID"
+// @Param        itemType  body      dtos.ItemTypeDTO  true  "Item type"
+// @Success      200  {object}  models.ItemType     "Item Type updated successfully"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid request data"
+// @Failure      403  {object}  dtos.ErrorResponse  "Access denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Item Type not found"
+// @Failure      409  {object}  dtos.ErrorResponse  "Name already in use"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error updating item type"
+// @Security     ApiKeyAuth
+// @Router       /item-types/{id} [put]
+func (itc *ItemTypeController) UpdateItemType(c *gin.Context) {
+	permissionId := config.PERMISSION_UPDATE_ITEM_TYPE
+	if !itc.Auth.CheckPermission(c, permissionId) {
+		_ = itc.Log.RegisterLog(c, "Access denied for UpdateItemType")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = itc.Log.RegisterLog(c, "Invalid ItemType ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid Item Type ID")
+		return
+	}
+
+	var dto dtos.ItemTypeDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = itc.Log.RegisterLog(c, "Invalid ItemType data: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	itemType, err := itc.Service.UpdateItemType(c.Request.Context(), id, dto)
+	if err != nil {
+		_ = itc.Log.RegisterLog(c, "Error updating ItemType with ID "+c.Param("id")+": "+err.Error())
+		itc.respondWriteError(c, err, "Error updating Item Type")
+		return
+	}
+
+	_ = itc.Log.RegisterLog(c, "Successfully updated ItemType with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, itemType)
+}
+
+// DeleteItemType godoc
+// @Summary      Delete an item type
+// @Description  Deletes an item type that no item uses.
+// @Tags         item-types
+// @Produce      json
+// @Param        id  path  int  true  "Item Type ID"
+// @Success      200  {object}  models.MessageResponse  "Item Type deleted successfully"
+// @Failure      400  {object}  dtos.ErrorResponse      "Invalid Item Type ID"
+// @Failure      403  {object}  dtos.ErrorResponse      "Access denied"
+// @Failure      404  {object}  dtos.ErrorResponse      "Item Type not found"
+// @Failure      409  {object}  dtos.ErrorResponse      "Item type in use"
+// @Failure      500  {object}  dtos.ErrorResponse      "Error deleting item type"
+// @Security     ApiKeyAuth
+// @Router       /item-types/{id} [delete]
+func (itc *ItemTypeController) DeleteItemType(c *gin.Context) {
+	permissionId := config.PERMISSION_DELETE_ITEM_TYPE
+	if !itc.Auth.CheckPermission(c, permissionId) {
+		_ = itc.Log.RegisterLog(c, "Access denied for DeleteItemType")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = itc.Log.RegisterLog(c, "Invalid ItemType ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid Item Type ID")
+		return
+	}
+
+	if err := itc.Service.DeleteItemType(c.Request.Context(), id); err != nil {
+		_ = itc.Log.RegisterLog(c, "Error deleting ItemType with ID "+c.Param("id")+": "+err.Error())
+		itc.respondWriteError(c, err, "Error deleting Item Type")
+		return
+	}
+
+	_ = itc.Log.RegisterLog(c, "Successfully deleted ItemType with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, gin.H{"message": "Item Type deleted successfully"})
+}
+
+func (itc *ItemTypeController) respondWriteError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utilities.RespondError(c, http.StatusNotFound, "Item Type not found")
+	case errors.Is(err, services.ErrInvalidItemTypeName):
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrItemTypeNameTaken), errors.Is(err, services.ErrItemTypeInUse):
+		utilities.RespondError(c, http.StatusConflict, err.Error())
+	default:
+		utilities.RespondError(c, http.StatusInternalServerError, message)
+	}
 }
@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"net/http"
+	"sort"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MetaController struct {
+	Router *gin.Engine
+	Auth   *utilities.AuthorizationUtil
+	Log    *utilities.LogUtil
+}
+
+func NewMetaController(router *gin.Engine, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *MetaController {
+	return &MetaController{Router: router, Auth: auth, Log: log}
+}
+
+// GetRoutes godoc
+// @Summary      List routes and their permissions
+// @Description  Lists every registered route with the permission IDs its handler checks, taken from config.ROUTE_PERMISSIONS. Routes without permissions are public; batch routes check, of the listed ones, the permission of each operation type they contain.
+// @Tags         meta
+// @Produce      json
+// @Success      200  {array}   dtos.RouteDTO       "Registered routes"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Security     ApiKeyAuth
+// @Router       /meta/routes [get]
+func (mc *MetaController) GetRoutes(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_ROUTES
+	if !mc.Auth.CheckPermission(c, permissionId) {
+		_ = mc.Log.RegisterLog(c, "Access denied for GetRoutes")
+		return
+	}
+
+	registered := mc.Router.Routes()
+	routes := make([]dtos.RouteDTO, len(registered))
+	for i, route := range registered {
+		permissions := config.ROUTE_PERMISSIONS[route.Method+" "+route.Path]
+		if permissions == nil {
+			permissions = []int{}
+		}
+		routes[i] = dtos.RouteDTO{Method: route.Method, Path: route.Path, PermissionIDs: permissions}
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+
+	_ = mc.Log.RegisterLog(c, "Successfully retrieved registered routes")
+	c.JSON(http.StatusOK, routes)
+}
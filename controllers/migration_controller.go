@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"net/http"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MigrationController struct {
+	Service *services.MigrationService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewMigrationController(service *services.MigrationService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *MigrationController {
+	return &MigrationController{Service: service, Auth: auth, Log: log}
+}
+
+// GetMigrationStatus godoc
+// @Summary      Database migration status
+// @Description  Lists every versioned migration known to this build and whether it has been applied to the database.
+// @Tags         migrations
+// @Produce      json
+// @Success      200  {array}   dtos.MigrationStatusDTO  "Migration status"
+// @Failure      403  {object}  dtos.ErrorResponse       "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse       "Error retrieving migration status"
+// @Security     ApiKeyAuth
+// @Router       /migrations [get]
+func (mc *MigrationController) GetMigrationStatus(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_MIGRATIONS
+	if !mc.Auth.CheckPermission(c, permissionId) {
+		_ = mc.Log.RegisterLog(c, "Access denied for GetMigrationStatus")
+		return
+	}
+
+	status, err := mc.Service.GetMigrationStatus(c.Request.Context())
+	if err != nil {
+		_ = mc.Log.RegisterLog(c, "Error retrieving migration status: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving migration status")
+		return
+	}
+
+	_ = mc.Log.RegisterLog(c, "Successfully retrieved migration status")
+	c.JSON(http.StatusOK, status)
+}
@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// NotificationController expone la bandeja del usuario que hace la petición; no requiere permisos
+// propios porque cada usuario solo ve sus notificaciones.
+type NotificationController struct {
+	Service *services.InboxService
+	Users   *services.UserService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewNotificationController(service *services.InboxService, users *services.UserService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *NotificationController {
+	return &NotificationController{Service: service, Users: users, Auth: auth, Log: log}
+}
+
+// GetNotifications godoc
+// @Summary      List my notifications
+// @Description  Returns the logged-in user's in-app notifications, newest first.
+// @Tags         notifications
+// @Produce      json
+// @Param        unread    query  bool  false  "Only unread notifications"
+// @Param        page      query  int   false  "Page number (default 1)"
+// @Param        pageSize  query  int   false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[dtos.GetNotificationDTO]  "Notifications"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid parameters"
+// @Failure      404  {object}  dtos.ErrorResponse  "User not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving notifications"
+// @Security     ApiKeyAuth
+// @Router       /notifications [get]
+func (nc *NotificationController) GetNotifications(c *gin.Context) {
+	userID, ok := nc.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = nc.Log.RegisterLog(c, "Invalid pagination for GetNotifications: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	unreadOnly := false
+	if unread := c.Query("unread"); unread != "" {
+		if unreadOnly, err = strconv.ParseBool(unread); err != nil {
+			_ = nc.Log.RegisterLog(c, "Invalid unread filter for GetNotifications: "+unread)
+			utilities.RespondError(c, http.StatusBadRequest, "unread must be true or false")
+			return
+		}
+	}
+
+	notifications, total, err := nc.Service.GetNotifications(c.Request.Context(), userID, unreadOnly, pagination)
+	if err != nil {
+		_ = nc.Log.RegisterLog(c, "Error retrieving notifications: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving notifications")
+		return
+	}
+
+	_ = nc.Log.RegisterLog(c, "Successfully retrieved notifications")
+	c.JSON(http.StatusOK, dtos.NewPageDTO(notifications, pagination, total))
+}
+
+// GetUnreadNotificationCount godoc
+// @Summary      Count my unread notifications
+// @Tags         notifications
+// @Produce      json
+// @Success      200  {object}  dtos.UnreadNotificationCountDTO  "Unread notifications"
+// @Failure      404  {object}  dtos.ErrorResponse  "User not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error counting notifications"
+// @Security     ApiKeyAuth
+// @Router       /notifications/unread-count [get]
+func (nc *NotificationController) GetUnreadNotificationCount(c *gin.Context) {
+	userID, ok := nc.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	count, err := nc.Service.CountUnread(c.Request.Context(), userID)
+	if err != nil {
+		_ = nc.Log.RegisterLog(c, "Error counting unread notifications: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error counting notifications")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.UnreadNotificationCountDTO{Unread: count})
+}
+
+// MarkNotificationAsRead godoc
+// @Summary      Mark a notification as read
+// @Tags         notifications
+// @Param        id   path  int  true  "Notification ID"
+// @Success      204  "Notification marked as read"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid notification ID"
+// @Failure      404  {object}  dtos.ErrorResponse  "Notification not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error updating notification"
+// @Security     ApiKeyAuth
+// @Router       /notifications/{id}/read [patch]
+func (nc *NotificationController) MarkNotificationAsRead(c *gin.Context) {
+	userID, ok := nc.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = nc.Log.RegisterLog(c, "Invalid notification ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid notification ID")
+		return
+	}
+
+	if err := nc.Service.MarkAsRead(c.Request.Context(), userID, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = nc.Log.RegisterLog(c, "Notification not found with ID: "+c.Param("id"))
+			utilities.RespondError(c, http.StatusNotFound, "Notification not found")
+			return
+		}
+		_ = nc.Log.RegisterLog(c, "Error marking notification as read: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error updating notification")
+		return
+	}
+
+	_ = nc.Log.RegisterLog(c, "Marked notification as read with ID: "+c.Param("id"))
+	c.Status(http.StatusNoContent)
+}
+
+// MarkAllNotificationsAsRead godoc
+// @Summary      Mark all my notifications as read
+// @Tags         notifications
+// @Produce      json
+// @Success      200  {object}  dtos.MarkedNotificationsDTO  "Number of notifications marked"
+// @Failure      404  {object}  dtos.ErrorResponse  "User not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error updating notifications"
+// @Security     ApiKeyAuth
+// @Router       /notifications/read-all [post]
+func (nc *NotificationController) MarkAllNotificationsAsRead(c *gin.Context) {
+	userID, ok := nc.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	marked, err := nc.Service.MarkAllAsRead(c.Request.Context(), userID)
+	if err != nil {
+		_ = nc.Log.RegisterLog(c, "Error marking notifications as read: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error updating notifications")
+		return
+	}
+
+	_ = nc.Log.RegisterLog(c, "Marked "+strconv.FormatInt(marked, 10)+" notifications as read")
+	c.JSON(http.StatusOK, dtos.MarkedNotificationsDTO{Marked: marked})
+}
+
+func (nc *NotificationController) currentUserID(c *gin.Context) (int, bool) {
+	user, err := nc.Users.GetUserByEmail(c.Request.Context(), utilities.CurrentUser(c))
+	if err != nil {
+		_ = nc.Log.RegisterLog(c, "User not found for notifications")
+		utilities.RespondError(c, http.StatusNotFound, "User not found")
+		return 0, false
+	}
+	return user.ID, true
+}
@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type NotificationDeliveryController struct {
+	Service *services.NotificationDeliveryService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewNotificationDeliveryController(service *services.NotificationDeliveryService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *NotificationDeliveryController {
+	return &NotificationDeliveryController{Service: service, Auth: auth, Log: log}
+}
+
+// GetNotificationDeliveries godoc
+// @Summary      Search the notification delivery log
+// @Description  Returns a page of delivery attempts, newest first. Every attempt to send an email is recorded with its recipient, status and the provider response or error.
+// @Tags         notifications
+// @Produce      json
+// @Param        channel     query  string  false  "Channel: email, sms or whatsapp"
+// @Param        status      query  string  false  "Attempt status: sent or failed"
+// @Param        recipient   query  string  false  "Recipient address (partial match)"
+// @Param        message_id  query  int     false  "Only the attempts of this message"
+// @Param        from        query  string  false  "Start date (YYYY-MM-DD or RFC3339)"
+// @Param        to          query  string  false  "End date (YYYY-MM-DD, inclusive, or RFC3339)"
+// @Param        page        query  int     false  "Page number (default 1)"
+// @Param        pageSize    query  int     false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[models.NotificationDelivery]  "Page of delivery attempts"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid filter"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error searching deliveries"
+// @Security     ApiKeyAuth
+// @Router       /notifications/deliveries [get]
+func (ndc *NotificationDeliveryController) GetNotificationDeliveries(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_NOTIFICATION_DELIVERIES
+	if !ndc.Auth.CheckPermission(c, permissionId) {
+		_ = ndc.Log.RegisterLog(c, "Access denied for GetNotificationDeliveries")
+		return
+	}
+
+	filter := dtos.NotificationDeliveryFilterDTO{
+		Channel:   c.Query("channel"),
+		Status:    c.Query("status"),
+		Recipient: c.Query("recipient"),
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = ndc.Log.RegisterLog(c, "Invalid pagination: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	filter.PaginationDTO = pagination
+
+	if messageID := c.Query("message_id"); messageID != "" {
+		if filter.MessageID, err = strconv.Atoi(messageID); err != nil {
+			_ = ndc.Log.RegisterLog(c, "Invalid message ID: "+messageID)
+			utilities.RespondError(c, http.StatusBadRequest, "Invalid 'message_id'")
+			return
+		}
+	}
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, _, err := parseLogDate(fromStr)
+		if err != nil {
+			_ = ndc.Log.RegisterLog(c, "Invalid from date: "+fromStr)
+			utilities.RespondError(c, http.StatusBadRequest, "Invalid 'from' date. Use YYYY-MM-DD or RFC3339")
+			return
+		}
+		filter.From = &from
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		to, dateOnly, err := parseLogDate(toStr)
+		if err != nil {
+			_ = ndc.Log.RegisterLog(c, "Invalid to date: "+toStr)
+			utilities.RespondError(c, http.StatusBadRequest, "Invalid 'to' date. Use YYYY-MM-DD or RFC3339")
+			return
+		}
+		if dateOnly {
+			to = to.Add(24*time.Hour - time.Nanosecond)
+		}
+		filter.To = &to
+	}
+
+	page, err := ndc.Service.SearchDeliveries(c.Request.Context(), filter)
+	if err != nil {
+		_ = ndc.Log.RegisterLog(c, "Error searching notification deliveries: "+err.Error())
+		if errors.Is(err, services.ErrInvalidDeliveryFilter) {
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error searching deliveries")
+		return
+	}
+
+	_ = ndc.Log.RegisterLog(c, "Successfully searched notification deliveries")
+	c.JSON(http.StatusOK, page)
+}
+
+// RetryNotificationDelivery godoc
+// @Summary      Retry a failed notification
+// @Description  Queues again the message of the given delivery attempt, with its attempt count reset. Only messages that exhausted their automatic retries can be retried.
+// @Tags         notifications
+// @Param        id   path  int  true  "Delivery ID"
+// @Success      202  "Notification queued again"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid delivery ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Delivery not found"
+// @Failure      409  {object}  dtos.ErrorResponse  "The notification has not failed"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrying notification"
+// @Security     ApiKeyAuth
+// @Router       /notifications/deliveries/{id}/retry [post]
+func (ndc *NotificationDeliveryController) RetryNotificationDelivery(c *gin.Context) {
+	permissionId := config.PERMISSION_RETRY_NOTIFICATION_DELIVERY
+	if !ndc.Auth.CheckPermission(c, permissionId) {
+		_ = ndc.Log.RegisterLog(c, "Access denied for RetryNotificationDelivery")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = ndc.Log.RegisterLog(c, "Invalid delivery ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid delivery ID")
+		return
+	}
+
+	delivery, err := ndc.Service.RetryDelivery(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			_ = ndc.Log.RegisterLog(c, "Notification delivery not found with ID: "+c.Param("id"))
+			utilities.RespondError(c, http.StatusNotFound, "Delivery not found")
+		case errors.Is(err, services.ErrNotificationNotRetryable):
+			_ = ndc.Log.RegisterLog(c, "Notification delivery not retryable with ID: "+c.Param("id"))
+			utilities.RespondError(c, http.StatusConflict, err.Error())
+		default:
+			_ = ndc.Log.RegisterLog(c, "Error retrying notification delivery: "+err.Error())
+			utilities.RespondError(c, http.StatusInternalServerError, "Error retrying notification")
+		}
+		return
+	}
+
+	_ = ndc.Log.RegisterLog(c, "Queued again "+delivery.Channel+" message "+strconv.Itoa(delivery.MessageID)+" to "+delivery.Recipient)
+	c.Status(http.StatusAccepted)
+}
@@ -0,0 +1,209 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationPreferenceController struct {
+	Service   *services.NotificationPreferenceService
+	Users     *services.UserService
+	Customers *services.CustomerService
+	Auth      *utilities.AuthorizationUtil
+	Log       *utilities.LogUtil
+}
+
+func NewNotificationPreferenceController(service *services.NotificationPreferenceService, users *services.UserService,
+	customers *services.CustomerService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *NotificationPreferenceController {
+	return &NotificationPreferenceController{Service: service, Users: users, Customers: customers, Auth: auth, Log: log}
+}
+
+// GetMyNotificationPreferences godoc
+// @Summary      Get my notification preferences
+// @Description  Returns, for every notification a staff user can receive, the channel and whether it is enabled. Preferences never changed show their default.
+// @Tags         notification-preferences
+// @Produce      json
+// @Success      200  {array}   dtos.NotificationPreferenceDTO  "Notification preferences"
+// @Failure      404  {object}  dtos.ErrorResponse  "User not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving notification preferences"
+// @Security     ApiKeyAuth
+// @Router       /notification-preferences/me [get]
+func (npc *NotificationPreferenceController) GetMyNotificationPreferences(c *gin.Context) {
+	user, err := npc.Users.GetUserByEmail(c.Request.Context(), utilities.CurrentUser(c))
+	if err != nil {
+		_ = npc.Log.RegisterLog(c, "User not found for GetMyNotificationPreferences")
+		utilities.RespondError(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	preferences, err := npc.Service.GetPreferences(c.Request.Context(), services.NOTIFICATION_RECIPIENT_USER, user.ID)
+	if err != nil {
+		_ = npc.Log.RegisterLog(c, "Error retrieving notification preferences: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving notification preferences")
+		return
+	}
+
+	_ = npc.Log.RegisterLog(c, "Successfully retrieved own notification preferences")
+	c.JSON(http.StatusOK, preferences)
+}
+
+// UpdateMyNotificationPreferences godoc
+// @Summary      Update my notification preferences
+// @Description  Enables or disables notifications per event type and channel. Mandatory notifications cannot be disabled.
+// @Tags         notification-preferences
+// @Accept       json
+// @Produce      json
+// @Param        preferences  body  dtos.UpdateNotificationPreferencesDTO  true  "Preferences to change"
+// @Success      200  {array}   dtos.NotificationPreferenceDTO  "Updated notification preferences"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid preferences"
+// @Failure      404  {object}  dtos.ErrorResponse  "User not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error updating notification preferences"
+// @Security     ApiKeyAuth
+// @Router       /notification-preferences/me [put]
+func (npc *NotificationPreferenceController) UpdateMyNotificationPreferences(c *gin.Context) {
+	user, err := npc.Users.GetUserByEmail(c.Request.Context(), utilities.CurrentUser(c))
+	if err != nil {
+		_ = npc.Log.RegisterLog(c, "User not found for UpdateMyNotificationPreferences")
+		utilities.RespondError(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	npc.updatePreferences(c, services.NOTIFICATION_RECIPIENT_USER, user.ID)
+}
+
+// GetCustomerNotificationPreferences godoc
+// @Summary      Get a customer's notification preferences
+// @Tags         notification-preferences
+// @Produce      json
+// @Param        id   path  int  true  "Customer ID"
+// @Success      200  {array}   dtos.NotificationPreferenceDTO  "Notification preferences"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid customer ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Customer not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving notification preferences"
+// @Security     ApiKeyAuth
+// @Router       /customers/{id}/notification-preferences [get]
+func (npc *NotificationPreferenceController) GetCustomerNotificationPreferences(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_CUSTOMER_NOTIFICATION_PREFERENCES
+	if !npc.Auth.CheckPermission(c, permissionId) {
+		_ = npc.Log.RegisterLog(c, "Access denied for GetCustomerNotificationPreferences")
+		return
+	}
+
+	customerID, ok := npc.customerID(c)
+	if !ok {
+		return
+	}
+
+	preferences, err := npc.Service.GetPreferences(c.Request.Context(), services.NOTIFICATION_RECIPIENT_CUSTOMER, customerID)
+	if err != nil {
+		_ = npc.Log.RegisterLog(c, "Error retrieving notification preferences: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving notification preferences")
+		return
+	}
+
+	_ = npc.Log.RegisterLog(c, "Successfully retrieved notification preferences of customer with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, preferences)
+}
+
+// UpdateCustomerNotificationPreferences godoc
+// @Summary      Update a customer's notification preferences
+// @Tags         notification-preferences
+// @Accept       json
+// @Produce      json
+// @Param        id           path  int  true  "Customer ID"
+// @Param        preferences  body  dtos.UpdateNotificationPreferencesDTO  true  "Preferences to change"
+// @Success      200  {array}   dtos.NotificationPreferenceDTO  "Updated notification preferences"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid preferences"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Customer not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error updating notification preferences"
+// @Security     ApiKeyAuth
+// @Router       /customers/{id}/notification-preferences [put]
+func (npc *NotificationPreferenceController) UpdateCustomerNotificationPreferences(c *gin.Context) {
+	permissionId := config.PERMISSION_EDIT_CUSTOMER_NOTIFICATION_PREFERENCES
+	if !npc.Auth.CheckPermission(c, permissionId) {
+		_ = npc.Log.RegisterLog(c, "Access denied for UpdateCustomerNotificationPreferences")
+		return
+	}
+
+	customerID, ok := npc.customerID(c)
+	if !ok {
+		return
+	}
+
+	npc.updatePreferences(c, services.NOTIFICATION_RECIPIENT_CUSTOMER, customerID)
+}
+
+// Unsubscribe godoc
+// @Summary      Unsubscribe from a notification
+// @Description  Target of the unsubscribe link included in notification emails. It needs no authentication: the signed token identifies the recipient, event and channel. Also accepts the one-click POST sent by mail clients.
+// @Tags         notification-preferences
+// @Produce      html
+// @Param        token  query  string  true  "Signed unsubscribe token"
+// @Success      200  {string}  string  "Confirmation page"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid or missing token"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error saving the preference"
+// @Router       /notification-preferences/unsubscribe [get]
+// @Router       /notification-preferences/unsubscribe [post]
+func (npc *NotificationPreferenceController) Unsubscribe(c *gin.Context) {
+	preference, err := npc.Service.Unsubscribe(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidUnsubscribeToken) {
+			utilities.RespondError(c, http.StatusBadRequest, "Invalid unsubscribe link")
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error saving the preference")
+		return
+	}
+
+	_ = npc.Log.RegisterLog(c, "Unsubscribed "+preference.RecipientType+" "+strconv.Itoa(preference.RecipientID)+
+		" from "+preference.EventType+" by "+preference.Channel)
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(`<!DOCTYPE html><html lang="es"><head><meta charset="UTF-8"><title>Suscripción cancelada</title></head>`+
+		`<body style="font-family:Arial,Helvetica,sans-serif;padding:24px;"><p>Listo, ya no recibirás estos correos.</p></body></html>`))
+}
+
+func (npc *NotificationPreferenceController) updatePreferences(c *gin.Context, recipientType string, recipientID int) {
+	var dto dtos.UpdateNotificationPreferencesDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = npc.Log.RegisterLog(c, "Invalid JSON for notification preferences: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request body", err)
+		return
+	}
+
+	preferences, err := npc.Service.UpdatePreferences(c.Request.Context(), recipientType, recipientID, dto)
+	if err != nil {
+		_ = npc.Log.RegisterLog(c, "Error updating notification preferences: "+err.Error())
+		if errors.Is(err, services.ErrInvalidNotificationPreference) {
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error updating notification preferences")
+		return
+	}
+
+	_ = npc.Log.RegisterLog(c, "Successfully updated notification preferences of "+recipientType+" "+strconv.Itoa(recipientID))
+	c.JSON(http.StatusOK, preferences)
+}
+
+func (npc *NotificationPreferenceController) customerID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = npc.Log.RegisterLog(c, "Invalid customer ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid customer ID")
+		return 0, false
+	}
+	if _, err := npc.Customers.GetCustomerByID(c.Request.Context(), id); err != nil {
+		_ = npc.Log.RegisterLog(c, "Customer not found with ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusNotFound, "Customer not found")
+		return 0, false
+	}
+	return id, true
+}
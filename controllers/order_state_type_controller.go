@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
 	"totesbackend/services"
 
 	"github.com/gin-gonic/gin"
@@ -26,31 +27,26 @@ func NewOrderStateTypeController(service *services.OrderStateTypeService, auth *
 // @Produce      json
 // @Param        id   path      int                         true  "Order State Type ID"
 // @Success      200  {object}  models.OrderStateType       "Order state type retrieved successfully"
-// @Failure      403  {object}  models.ErrorResponse        "Access denied"
-// @Failure      404  {object}  models.ErrorResponse        "Order state type not found"
-// @Failure      500  {object}  models.ErrorResponse        "Internal server error"
+// @Failure      403  {object}  dtos.ErrorResponse        "Access denied"
+// @Failure      404  {object}  dtos.ErrorResponse        "Order state type not found"
+// @Failure      500  {object}  dtos.ErrorResponse        "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /order-state-types/{id} [get]
 func (ostc *OrderStateTypeController) GetOrderStateTypeByID(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_ORDER_STATE_TYPE_BY_ID
 
-	if ostc.Log.RegisterLog(c, "Attempting to get order state type by ID") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	if !ostc.Auth.CheckPermission(c, permissionId) {
 		_ = ostc.Log.RegisterLog(c, "Access denied for GetOrderStateTypeByID")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		utilities.RespondError(c, http.StatusForbidden, "Access denied")
 		return
 	}
 
 	id := c.Param("id")
 
-	orderStateType, err := ostc.Service.GetOrderStateTypeByID(id)
+	orderStateType, err := ostc.Service.GetOrderStateTypeByID(c.Request.Context(), id)
 	if err != nil {
 		_ = ostc.Log.RegisterLog(c, "Order state type not found with ID: "+id)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Order State Type not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Order State Type not found")
 		return
 	}
 
@@ -63,32 +59,36 @@ func (ostc *OrderStateTypeController) GetOrderStateTypeByID(c *gin.Context) {
 // @Description  Retrieves a list of all available order state types.
 // @Tags         order-state-types
 // @Produce      json
-// @Success      200  {array}   models.OrderStateType       "List of order state types"
-// @Failure      403  {object}  models.ErrorResponse        "Access denied"
-// @Failure      500  {object}  models.ErrorResponse        "Internal server error"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[models.OrderStateType]       "List of order state types"
+// @Failure      403  {object}  dtos.ErrorResponse        "Access denied"
+// @Failure      500  {object}  dtos.ErrorResponse        "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /order-state-types [get]
 func (ostc *OrderStateTypeController) GetAllOrderStateTypes(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_ALL_ORDER_STATE_TYPES
 
-	if ostc.Log.RegisterLog(c, "Attempting to get all order state types") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if !ostc.Auth.CheckPermission(c, permissionId) {
+		_ = ostc.Log.RegisterLog(c, "Access denied for GetAllOrderStateTypes")
+		utilities.RespondError(c, http.StatusForbidden, "Access denied")
 		return
 	}
 
-	if !ostc.Auth.CheckPermission(c, permissionId) {
-		_ = ostc.Log.RegisterLog(c, "Access denied for GetAllOrderStateTypes")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = ostc.Log.RegisterLog(c, "Invalid pagination for GetAllOrderStateTypes: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	orderStateTypes, err := ostc.Service.GetAllOrderStateTypes()
+	orderStateTypes, total, err := ostc.Service.GetAllOrderStateTypes(c.Request.Context(), pagination)
 	if err != nil {
 		_ = ostc.Log.RegisterLog(c, "Error retrieving order state types: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving Order State Types"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving Order State Types")
 		return
 	}
 
 	_ = ostc.Log.RegisterLog(c, "Successfully retrieved all order state types")
-	c.JSON(http.StatusOK, orderStateTypes)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(orderStateTypes, pagination, total))
 }
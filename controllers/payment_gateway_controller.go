@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"totesbackend/controllers/utilities"
+	"totesbackend/payments"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PaymentGatewayController struct {
+	Service *services.PaymentGatewayService
+	Log     *utilities.LogUtil
+}
+
+func NewPaymentGatewayController(service *services.PaymentGatewayService, log *utilities.LogUtil) *PaymentGatewayController {
+	return &PaymentGatewayController{Service: service, Log: log}
+}
+
+// ReceivePaymentWebhook godoc
+// @Summary      Receive a payment gateway notification
+// @Description  Webhook for approved payments: Stripe payment_intent.succeeded events, with the invoice ID in the invoice_id metadata, or the PayU confirmation page, with the invoice ID as reference_sale (optionally followed by "-" and a suffix). The gateway signature is the only credential. The payment is recorded against the invoice with method online; if that fails (invalid reference or amount, unknown or cancelled invoice, another currency, more than the outstanding balance) it is kept as failed. A repeated notification returns the payment already recorded, or processes it again if it was not.
+// @Tags         payments
+// @Accept       json
+// @Accept       x-www-form-urlencoded
+// @Produce      json
+// @Success      200  {object}  models.GatewayPayment  "Payment recorded (or event ignored, with no body)"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid notification"
+// @Failure      401  {object}  dtos.ErrorResponse  "Invalid signature"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error recording the payment; the gateway retries the notification"
+// @Router       /webhooks/payments [post]
+func (pc *PaymentGatewayController) ReceivePaymentWebhook(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	payment, err := pc.Service.ReceiveWebhook(c.Request.Context(), c.Request.Header, body)
+	if err != nil {
+		switch {
+		case errors.Is(err, payments.ErrInvalidWebhookSignature):
+			_ = pc.Log.RegisterLog(c, "Payment webhook rejected: invalid signature")
+			utilities.RespondError(c, http.StatusUnauthorized, "Invalid webhook signature")
+		case errors.Is(err, services.ErrInvalidGatewayPayment):
+			_ = pc.Log.RegisterLog(c, "Payment webhook rejected: "+err.Error())
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		default:
+			_ = pc.Log.RegisterLog(c, "Error receiving payment webhook: "+err.Error())
+			utilities.RespondError(c, http.StatusInternalServerError, "Error recording the payment")
+		}
+		return
+	}
+	if payment == nil {
+		c.Status(http.StatusOK)
+		return
+	}
+
+	_ = pc.Log.RegisterLog(c, "Payment "+payment.ExternalID+" received from "+payment.Provider+": "+payment.Status)
+	c.JSON(http.StatusOK, payment)
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
 	"totesbackend/services"
 
 	"github.com/gin-gonic/gin"
@@ -27,10 +28,10 @@ func NewPermissionController(service *services.PermissionService, auth *utilitie
 // @Produce      json
 // @Param        id   path      int                           true  "Permission ID"
 // @Success      200  {object}  models.Permission             "Permission data"
-// @Failure      400  {object}  models.ErrorResponse          "Invalid permission ID"
-// @Failure      403  {object}  models.ErrorResponse          "Access denied"
-// @Failure      404  {object}  models.ErrorResponse          "Permission not found"
-// @Failure      500  {object}  models.ErrorResponse          "Internal server error"
+// @Failure      400  {object}  dtos.ErrorResponse          "Invalid permission ID"
+// @Failure      403  {object}  dtos.ErrorResponse          "Access denied"
+// @Failure      404  {object}  dtos.ErrorResponse          "Permission not found"
+// @Failure      500  {object}  dtos.ErrorResponse          "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /permissions/{id} [get]
 func (pc *PermissionController) GetPermissionByID(c *gin.Context) {
@@ -38,7 +39,7 @@ func (pc *PermissionController) GetPermissionByID(c *gin.Context) {
 
 	if !pc.Auth.CheckPermission(c, permissionId) {
 		if pc.Log.RegisterLog(c, "Access denied for GetPermissionByID") != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+			utilities.RespondError(c, http.StatusInternalServerError, "Error registering log")
 			return
 		}
 		return
@@ -48,30 +49,25 @@ func (pc *PermissionController) GetPermissionByID(c *gin.Context) {
 	var id uint
 	if _, err := fmt.Sscanf(idParam, "%d", &id); err != nil {
 		if pc.Log.RegisterLog(c, "Invalid permission ID: "+idParam) != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+			utilities.RespondError(c, http.StatusInternalServerError, "Error registering log")
 			return
 		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid permission ID"})
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid permission ID")
 		return
 	}
 
-	if pc.Log.RegisterLog(c, "Attempting to retrieve Permission with ID: "+idParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
-	permission, err := pc.Service.GetPermissionByID(id)
+	permission, err := pc.Service.GetPermissionByID(c.Request.Context(), id)
 	if err != nil {
 		if pc.Log.RegisterLog(c, "Permission with ID "+idParam+" not found") != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+			utilities.RespondError(c, http.StatusInternalServerError, "Error registering log")
 			return
 		}
-		c.JSON(http.StatusNotFound, gin.H{"error": "Permission not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Permission not found")
 		return
 	}
 
 	if pc.Log.RegisterLog(c, "Successfully retrieved Permission with ID: "+idParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error registering log")
 		return
 	}
 
@@ -83,9 +79,11 @@ func (pc *PermissionController) GetPermissionByID(c *gin.Context) {
 // @Description  Retrieves a list of all permissions available in the system.
 // @Tags         permissions
 // @Produce      json
-// @Success      200  {array}   models.Permission             "List of permissions"
-// @Failure      403  {object}  models.ErrorResponse          "Access denied"
-// @Failure      500  {object}  models.ErrorResponse          "Internal server error"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[models.Permission]             "List of permissions"
+// @Failure      403  {object}  dtos.ErrorResponse          "Access denied"
+// @Failure      500  {object}  dtos.ErrorResponse          "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /permissions [get]
 func (pc *PermissionController) GetAllPermissions(c *gin.Context) {
@@ -93,33 +91,35 @@ func (pc *PermissionController) GetAllPermissions(c *gin.Context) {
 
 	if !pc.Auth.CheckPermission(c, permissionId) {
 		if pc.Log.RegisterLog(c, "Access denied for GetAllPermissions") != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+			utilities.RespondError(c, http.StatusInternalServerError, "Error registering log")
 			return
 		}
 		return
 	}
 
-	if pc.Log.RegisterLog(c, "Attempting to retrieve all permissions") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = pc.Log.RegisterLog(c, "Invalid pagination for GetAllPermissions: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	permissions, err := pc.Service.GetAllPermissions()
+	permissions, total, err := pc.Service.GetAllPermissions(c.Request.Context(), pagination)
 	if err != nil {
 		if pc.Log.RegisterLog(c, "Error retrieving all permissions: "+err.Error()) != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+			utilities.RespondError(c, http.StatusInternalServerError, "Error registering log")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving permissions"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving permissions")
 		return
 	}
 
 	if pc.Log.RegisterLog(c, "Successfully retrieved all permissions") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error registering log")
 		return
 	}
 
-	c.JSON(http.StatusOK, permissions)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(permissions, pagination, total))
 }
 
 // SearchPermissionsByID godoc
@@ -128,10 +128,12 @@ func (pc *PermissionController) GetAllPermissions(c *gin.Context) {
 // @Tags         permissions
 // @Produce      json
 // @Param        id   query     string  true  "ID to search for (partial or full match)"
-// @Success      200  {array}   models.Permission             "List of matching permissions"
-// @Failure      400  {object}  models.ErrorResponse          "Missing or invalid query parameter"
-// @Failure      403  {object}  models.ErrorResponse          "Access denied"
-// @Failure      500  {object}  models.ErrorResponse          "Internal server error"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[models.Permission]             "List of matching permissions"
+// @Failure      400  {object}  dtos.ErrorResponse          "Missing or invalid query parameter"
+// @Failure      403  {object}  dtos.ErrorResponse          "Access denied"
+// @Failure      500  {object}  dtos.ErrorResponse          "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /permissions/searchByID [get]
 func (pc *PermissionController) SearchPermissionsByID(c *gin.Context) {
@@ -139,7 +141,7 @@ func (pc *PermissionController) SearchPermissionsByID(c *gin.Context) {
 
 	if !pc.Auth.CheckPermission(c, permissionId) {
 		if pc.Log.RegisterLog(c, "Access denied for SearchPermissionsByID") != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+			utilities.RespondError(c, http.StatusInternalServerError, "Error registering log")
 			return
 		}
 		return
@@ -148,34 +150,36 @@ func (pc *PermissionController) SearchPermissionsByID(c *gin.Context) {
 	query := c.Query("id")
 	if query == "" {
 		if pc.Log.RegisterLog(c, "SearchPermissionsByID: missing 'id' query parameter") != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+			utilities.RespondError(c, http.StatusInternalServerError, "Error registering log")
 			return
 		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query is required"})
+		utilities.RespondError(c, http.StatusBadRequest, "Search query is required")
 		return
 	}
 
-	if pc.Log.RegisterLog(c, "Attempting to search permissions by ID: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = pc.Log.RegisterLog(c, "Invalid pagination for SearchPermissionsByID: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	permissions, err := pc.Service.SearchPermissionsByID(query)
+	permissions, total, err := pc.Service.SearchPermissionsByID(c.Request.Context(), query, pagination)
 	if err != nil {
 		if pc.Log.RegisterLog(c, "Error retrieving permissions by ID: "+err.Error()) != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+			utilities.RespondError(c, http.StatusInternalServerError, "Error registering log")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving permissions"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving permissions")
 		return
 	}
 
 	if pc.Log.RegisterLog(c, "Successfully retrieved permissions by ID: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error registering log")
 		return
 	}
 
-	c.JSON(http.StatusOK, permissions)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(permissions, pagination, total))
 }
 
 // SearchPermissionsByName godoc
@@ -184,10 +188,12 @@ func (pc *PermissionController) SearchPermissionsByID(c *gin.Context) {
 // @Tags         permissions
 // @Produce      json
 // @Param        name   query     string  true  "Name to search for (partial or full match)"
-// @Success      200    {array}   models.Permission             "List of matching permissions"
-// @Failure      400    {object}  models.ErrorResponse          "Missing or invalid query parameter"
-// @Failure      403    {object}  models.ErrorResponse          "Access denied"
-// @Failure      500    {object}  models.ErrorResponse          "Internal server error"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200    {object}  dtos.PageDTO[models.Permission]             "List of matching permissions"
+// @Failure      400    {object}  dtos.ErrorResponse          "Missing or invalid query parameter"
+// @Failure      403    {object}  dtos.ErrorResponse          "Access denied"
+// @Failure      500    {object}  dtos.ErrorResponse          "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /permissions/searchByName [get]
 func (pc *PermissionController) SearchPermissionsByName(c *gin.Context) {
@@ -195,7 +201,7 @@ func (pc *PermissionController) SearchPermissionsByName(c *gin.Context) {
 
 	if !pc.Auth.CheckPermission(c, permissionId) {
 		if pc.Log.RegisterLog(c, "Access denied for SearchPermissionsByName") != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+			utilities.RespondError(c, http.StatusInternalServerError, "Error registering log")
 			return
 		}
 		return
@@ -204,32 +210,34 @@ func (pc *PermissionController) SearchPermissionsByName(c *gin.Context) {
 	query := c.Query("name")
 	if query == "" {
 		if pc.Log.RegisterLog(c, "SearchPermissionsByName: missing 'name' query parameter") != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+			utilities.RespondError(c, http.StatusInternalServerError, "Error registering log")
 			return
 		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query is required"})
+		utilities.RespondError(c, http.StatusBadRequest, "Search query is required")
 		return
 	}
 
-	if pc.Log.RegisterLog(c, "Attempting to search permissions by name: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = pc.Log.RegisterLog(c, "Invalid pagination for SearchPermissionsByName: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	permissions, err := pc.Service.SearchPermissionsByName(query)
+	permissions, total, err := pc.Service.SearchPermissionsByName(c.Request.Context(), query, pagination)
 	if err != nil {
 		if pc.Log.RegisterLog(c, "Error retrieving permissions by name: "+err.Error()) != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+			utilities.RespondError(c, http.StatusInternalServerError, "Error registering log")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving permissions"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving permissions")
 		return
 	}
 
 	if pc.Log.RegisterLog(c, "Successfully retrieved permissions by name: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error registering log")
 		return
 	}
 
-	c.JSON(http.StatusOK, permissions)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(permissions, pagination, total))
 }
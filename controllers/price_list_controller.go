@@ -0,0 +1,285 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type PriceListController struct {
+	Service *services.PriceListService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewPriceListController(service *services.PriceListService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *PriceListController {
+	return &PriceListController{Service: service, Auth: auth, Log: log}
+}
+
+// GetPriceLists godoc
+// @Summary      List price lists
+// @Description  Returns the price lists ordered by name, each with its item prices.
+// @Tags         price-lists
+// @Produce      json
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200 {object} dtos.PageDTO[models.PriceList] "Price lists"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid pagination"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      500 {object} dtos.ErrorResponse "Error retrieving price lists"
+// @Security     ApiKeyAuth
+// @Router       /price-lists [get]
+func (pc *PriceListController) GetPriceLists(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_PRICE_LISTS
+	if !pc.Auth.CheckPermission(c, permissionId) {
+		_ = pc.Log.RegisterLog(c, "Access denied for GetPriceLists")
+		return
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = pc.Log.RegisterLog(c, "Invalid pagination for GetPriceLists: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	priceLists, total, err := pc.Service.GetPriceLists(c.Request.Context(), pagination)
+	if err != nil {
+		_ = pc.Log.RegisterLog(c, "Error retrieving price lists: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving price lists")
+		return
+	}
+
+	_ = pc.Log.RegisterLog(c, "Successfully retrieved price lists")
+	c.JSON(http.StatusOK, dtos.NewPageDTO(priceLists, pagination, total))
+}
+
+// GetPriceListByID godoc
+// @Summary      Get a price list
+// @Tags         price-lists
+// @Produce      json
+// @Param        id  path  int  true  "Price list ID"
+// @Success      200 {object} models.PriceList   "Price list with its items"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid price list ID"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "Price list not found"
+// @Failure      500 {object} dtos.ErrorResponse "Error retrieving price list"
+// @Security     ApiKeyAuth
+// @Router       /price-lists/{id} [get]
+func (pc *PriceListController) GetPriceListByID(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_PRICE_LISTS
+	if !pc.Auth.CheckPermission(c, permissionId) {
+		_ = pc.Log.RegisterLog(c, "Access denied for GetPriceListByID")
+		return
+	}
+
+	id, ok := pc.parseID(c, "Invalid price list ID")
+	if !ok {
+		return
+	}
+
+	priceList, err := pc.Service.GetPriceListByID(c.Request.Context(), id)
+	if err != nil {
+		_ = pc.Log.RegisterLog(c, "Error retrieving price list with ID "+c.Param("id")+": "+err.Error())
+		pc.respondError(c, err, "Price list not found", "Error retrieving price list")
+		return
+	}
+
+	_ = pc.Log.RegisterLog(c, "Successfully retrieved price list with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, priceList)
+}
+
+// CreatePriceList godoc
+// @Summary      Create a price list
+// @Description  Creates a list of special item prices, each in the item's currency. Customers assigned to the list buy those items at the list price and everything else at the regular selling price.
+// @Tags         price-lists
+// @Accept       json
+// @Produce      json
+// @Param        priceList body     dtos.PriceListDTO  true "Price list"
+// @Success      201 {object} models.PriceList   "Created price list"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid price list"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      409 {object} dtos.ErrorResponse "A price list with that name already exists"
+// @Failure      500 {object} dtos.ErrorResponse "Error creating price list"
+// @Security     ApiKeyAuth
+// @Router       /price-lists [post]
+func (pc *PriceListController) CreatePriceList(c *gin.Context) {
+	permissionId := config.PERMISSION_CREATE_PRICE_LIST
+	if !pc.Auth.CheckPermission(c, permissionId) {
+		_ = pc.Log.RegisterLog(c, "Access denied for CreatePriceList")
+		return
+	}
+
+	var dto dtos.PriceListDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = pc.Log.RegisterLog(c, "Invalid price list: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	priceList, err := pc.Service.CreatePriceList(c.Request.Context(), dto)
+	if err != nil {
+		_ = pc.Log.RegisterLog(c, "Error creating price list: "+err.Error())
+		pc.respondError(c, err, "Price list not found", "Error creating price list")
+		return
+	}
+
+	_ = pc.Log.RegisterLog(c, "Successfully created price list with ID: "+strconv.Itoa(priceList.ID))
+	c.JSON(http.StatusCreated, priceList)
+}
+
+// UpdatePriceList godoc
+// @Summary      Update a price list
+// @Description  Replaces the name, description and every item price of a price list. Items left out go back to their regular selling price for the list's customers. Issued invoices are not affected.
+// @Tags         price-lists
+// @Accept       json
+// @Produce      json
+// @Param        id        path     int                true "Price list ID"
+// @Param        priceList body     dtos.PriceListDTO  true "Price list"
+// @Success      200 {object} models.PriceList   "Updated price list"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid price list"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "Price list not found"
+// @Failure      409 {object} dtos.ErrorResponse "A price list with that name already exists"
+// @Failure      500 {object} dtos.ErrorResponse "Error updating price list"
+// @Security     ApiKeyAuth
+// @Router       /price-lists/{id} [put]
+func (pc *PriceListController) UpdatePriceList(c *gin.Context) {
+	permissionId := config.PERMISSION_UPDATE_PRICE_LIST
+	if !pc.Auth.CheckPermission(c, permissionId) {
+		_ = pc.Log.RegisterLog(c, "Access denied for UpdatePriceList")
+		return
+	}
+
+	id, ok := pc.parseID(c, "Invalid price list ID")
+	if !ok {
+		return
+	}
+
+	var dto dtos.PriceListDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = pc.Log.RegisterLog(c, "Invalid price list: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	priceList, err := pc.Service.UpdatePriceList(c.Request.Context(), id, dto)
+	if err != nil {
+		_ = pc.Log.RegisterLog(c, "Error updating price list with ID "+c.Param("id")+": "+err.Error())
+		pc.respondError(c, err, "Price list not found", "Error updating price list")
+		return
+	}
+
+	_ = pc.Log.RegisterLog(c, "Successfully updated price list with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, priceList)
+}
+
+// DeletePriceList godoc
+// @Summary      Delete a price list
+// @Description  Deletes a price list. Its customers go back to the regular selling prices.
+// @Tags         price-lists
+// @Produce      json
+// @Param        id  path  int  true  "Price list ID"
+// @Success      200 {object} models.MessageResponse "Price list deleted"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid price list ID"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "Price list not found"
+// @Failure      500 {object} dtos.ErrorResponse "Error deleting price list"
+// @Security     ApiKeyAuth
+// @Router       /price-lists/{id} [delete]
+func (pc *PriceListController) DeletePriceList(c *gin.Context) {
+	permissionId := config.PERMISSION_DELETE_PRICE_LIST
+	if !pc.Auth.CheckPermission(c, permissionId) {
+		_ = pc.Log.RegisterLog(c, "Access denied for DeletePriceList")
+		return
+	}
+
+	id, ok := pc.parseID(c, "Invalid price list ID")
+	if !ok {
+		return
+	}
+
+	if err := pc.Service.DeletePriceList(c.Request.Context(), id); err != nil {
+		_ = pc.Log.RegisterLog(c, "Error deleting price list with ID "+c.Param("id")+": "+err.Error())
+		pc.respondError(c, err, "Price list not found", "Error deleting price list")
+		return
+	}
+
+	_ = pc.Log.RegisterLog(c, "Successfully deleted price list with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, gin.H{"message": "Price list deleted successfully"})
+}
+
+// AssignCustomerPriceList godoc
+// @Summary      Assign a price list to a customer
+// @Description  Sets the price list used to price the customer's invoices, quotations and purchase orders; a null price_list_id removes it and the customer goes back to the regular selling prices.
+// @Tags         price-lists
+// @Accept       json
+// @Produce      json
+// @Param        id    path     int                      true "Customer ID"
+// @Param        body  body     dtos.AssignPriceListDTO  true "Price list to assign"
+// @Success      200 {object} models.Customer    "Updated customer"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid customer ID or unknown price list"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "Customer not found"
+// @Failure      500 {object} dtos.ErrorResponse "Error assigning price list"
+// @Security     ApiKeyAuth
+// @Router       /customers/{id}/price-list [put]
+func (pc *PriceListController) AssignCustomerPriceList(c *gin.Context) {
+	permissionId := config.PERMISSION_ASSIGN_PRICE_LIST
+	if !pc.Auth.CheckPermission(c, permissionId) {
+		_ = pc.Log.RegisterLog(c, "Access denied for AssignCustomerPriceList")
+		return
+	}
+
+	customerID, ok := pc.parseID(c, "Invalid customer ID")
+	if !ok {
+		return
+	}
+
+	var dto dtos.AssignPriceListDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = pc.Log.RegisterLog(c, "Invalid price list assignment: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	customer, err := pc.Service.AssignPriceList(c.Request.Context(), customerID, dto.PriceListID)
+	if err != nil {
+		_ = pc.Log.RegisterLog(c, "Error assigning price list to customer "+c.Param("id")+": "+err.Error())
+		pc.respondError(c, err, "Customer not found", "Error assigning price list")
+		return
+	}
+
+	_ = pc.Log.RegisterLog(c, "Successfully assigned price list to customer with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, customer)
+}
+
+func (pc *PriceListController) parseID(c *gin.Context, message string) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = pc.Log.RegisterLog(c, message+": "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, message)
+		return 0, false
+	}
+	return id, true
+}
+
+func (pc *PriceListController) respondError(c *gin.Context, err error, notFound, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utilities.RespondError(c, http.StatusNotFound, notFound)
+	case errors.Is(err, services.ErrInvalidPriceList), errors.Is(err, services.ErrPriceListNotFound):
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrPriceListTaken):
+		utilities.RespondError(c, http.StatusConflict, err.Error())
+	default:
+		utilities.RespondError(c, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PublicCatalogController struct {
+	Service *services.PublicCatalogService
+	Log     *utilities.LogUtil
+}
+
+func NewPublicCatalogController(service *services.PublicCatalogService, log *utilities.LogUtil) *PublicCatalogController {
+	return &PublicCatalogController{Service: service, Log: log}
+}
+
+// GetPublicCatalog godoc
+// @Summary      Public catalog
+// @Description  Returns a page of the active items for the online storefront: name, description, selling price, category and whether there is stock. It needs no authentication and never exposes purchase prices or stock quantities. Pages are cached for a minute, so changes can take that long to show.
+// @Tags         public
+// @Produce      json
+// @Param        search    query  string  false  "Text contained in the name or description (case and accent insensitive)"
+// @Param        category  query  int     false  "Item type ID"
+// @Param        page      query  int     false  "Page number (default 1)"
+// @Param        pageSize  query  int     false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[dtos.PublicCatalogItemDTO]  "Page of the catalog"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid pagination or category"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving the catalog"
+// @Router       /public/catalog [get]
+func (pc *PublicCatalogController) GetPublicCatalog(c *gin.Context) {
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	filter := dtos.PublicCatalogFilterDTO{Search: strings.TrimSpace(c.Query("search")), PaginationDTO: pagination}
+	if value := c.Query("category"); value != "" {
+		if filter.CategoryID, err = strconv.Atoi(value); err != nil || filter.CategoryID < 1 {
+			utilities.RespondError(c, http.StatusBadRequest, "category must be a positive integer")
+			return
+		}
+	}
+
+	items, total, err := pc.Service.GetCatalog(c.Request.Context(), filter)
+	if err != nil {
+		_ = pc.Log.RegisterLog(c, "Error retrieving the public catalog: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving the catalog")
+		return
+	}
+
+	// sin RegisterLog en el caso exitoso: es tráfico anónimo de la tienda
+	c.Header("Cache-Control", "public, max-age="+strconv.Itoa(int(config.PUBLIC_CATALOG_CACHE_TTL.Seconds())))
+	c.JSON(http.StatusOK, dtos.NewPageDTO(items, pagination, total))
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -29,25 +30,19 @@ func NewPurchaseOrderController(service *services.PurchaseOrderService, auth *ut
 // @Tags         purchase_orders
 // @Produce      json
 // @Param        id   path     string  true  "Purchase Order ID"
-// @Success      200  {object}  dtos.GetPurchaseOrderDTO    "Purchase Order details"
-// @Failure      400  {object}  models.ErrorResponse       "Invalid ID format"
-// @Failure      404  {object}  models.ErrorResponse       "Purchase Order not found"
-// @Failure      500  {object}  models.ErrorResponse       "Internal server error"
+// @Success      200  {object}  dtos.ResourceDTO[dtos.GetPurchaseOrderDTO]  "Purchase Order details with links to its customer, employees and items"
+// @Failure      400  {object}  dtos.ErrorResponse       "Invalid ID format"
+// @Failure      404  {object}  dtos.ErrorResponse       "Purchase Order not found"
+// @Failure      500  {object}  dtos.ErrorResponse       "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /purchase-orders/{id} [get]
 func (poc *PurchaseOrderController) GetPurchaseOrderByID(c *gin.Context) {
-
-	if err := poc.Log.RegisterLog(c, "Attempting to retrieve Purchase Order by ID"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	id := c.Param("id")
 
-	purchaseOrder, err := poc.Service.GetPurchaseOrderByID(id)
+	purchaseOrder, err := poc.Service.GetPurchaseOrderByID(c.Request.Context(), id)
 	if err != nil {
 		_ = poc.Log.RegisterLog(c, "Purchase Order not found with ID: "+id)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Purchase Order not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Purchase Order not found")
 		return
 	}
 
@@ -67,7 +62,30 @@ func (poc *PurchaseOrderController) GetPurchaseOrderByID(c *gin.Context) {
 
 	_ = poc.Log.RegisterLog(c, "Successfully retrieved Purchase Order with ID: "+id)
 
-	c.JSON(http.StatusOK, purchaseOrderDTO)
+	c.JSON(http.StatusOK, dtos.NewResourceDTO(purchaseOrderDTO, purchaseOrderLinks(purchaseOrderDTO)))
+}
+
+func purchaseOrderLinks(order dtos.GetPurchaseOrderDTO) dtos.LinksDTO {
+	itemIDs := make([]int, len(order.Items))
+	for i, item := range order.Items {
+		itemIDs[i] = item.ID
+	}
+	links := dtos.LinksDTO{
+		"self":        utilities.ResourceLink("purchase-orders", order.ID),
+		"order_state": utilities.ResourceLink("order-state-types", order.OrderStateID),
+		"items":       utilities.ResourceLinks("items", itemIDs),
+	}
+	// cliente, vendedor y responsable son opcionales
+	if order.CustomerID != nil {
+		links["customer"] = utilities.ResourceLink("customers", *order.CustomerID)
+	}
+	if order.SellerID != nil {
+		links["seller"] = utilities.ResourceLink("employees", *order.SellerID)
+	}
+	if order.ResponsibleID != nil {
+		links["responsible"] = utilities.ResourceLink("employees", *order.ResponsibleID)
+	}
+	return links
 }
 
 // GetPurchaseOrdersByStateID godoc
@@ -76,39 +94,43 @@ func (poc *PurchaseOrderController) GetPurchaseOrderByID(c *gin.Context) {
 // @Tags         purchase_orders
 // @Produce      json
 // @Param        stateID  path     string  true  "State ID"
-// @Success      200      {array}  dtos.GetPurchaseOrderDTO  "List of Purchase Orders"
-// @Failure      400      {object} models.ErrorResponse     "Invalid State ID format"
-// @Failure      403      {object} models.ErrorResponse     "Permission denied"
-// @Failure      404      {object} models.ErrorResponse     "Purchase Orders not found"
-// @Failure      500      {object} models.ErrorResponse     "Internal server error"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200      {object} dtos.PageDTO[dtos.GetPurchaseOrderDTO]  "List of Purchase Orders"
+// @Failure      400      {object} dtos.ErrorResponse     "Invalid State ID format"
+// @Failure      403      {object} dtos.ErrorResponse     "Permission denied"
+// @Failure      404      {object} dtos.ErrorResponse     "Purchase Orders not found"
+// @Failure      500      {object} dtos.ErrorResponse     "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /purchase-orders/state/{stateID} [get]
 func (poc *PurchaseOrderController) GetPurchaseOrdersByStateID(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_PURCHASE_ORDERS_BY_STATE_ID
 
-	if err := poc.Log.RegisterLog(c, "Attempting to retrieve Purchase Orders by State ID"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	if !poc.Auth.CheckPermission(c, permissionId) {
 		_ = poc.Log.RegisterLog(c, "Permission denied for GetPurchaseOrdersByStateID")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
+		utilities.RespondError(c, http.StatusForbidden, "Permission denied")
 		return
 	}
 
 	stateID := c.Param("stateID")
 
-	purchaseOrders, err := poc.Service.GetPurchaseOrdersByStateID(stateID)
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = poc.Log.RegisterLog(c, "Invalid pagination for GetPurchaseOrdersByStateID: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	purchaseOrders, total, err := poc.Service.GetPurchaseOrdersByStateID(c.Request.Context(), stateID, pagination)
 	if err != nil {
 		_ = poc.Log.RegisterLog(c, "Purchase Orders not found for State ID: "+stateID)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Purchase Orders not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Purchase Orders not found")
 		return
 	}
 
-	if len(purchaseOrders) == 0 {
+	if total == 0 {
 		_ = poc.Log.RegisterLog(c, "No Purchase Orders found for State ID: "+stateID)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No purchase orders found"})
+		utilities.RespondError(c, http.StatusNotFound, "No purchase orders found")
 		return
 	}
 
@@ -131,7 +153,7 @@ func (poc *PurchaseOrderController) GetPurchaseOrdersByStateID(c *gin.Context) {
 
 	_ = poc.Log.RegisterLog(c, "Successfully retrieved Purchase Orders with State ID: "+stateID)
 
-	c.JSON(http.StatusOK, purchaseOrderDTOs)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(purchaseOrderDTOs, pagination, total))
 }
 
 // GetAllPurchaseOrders godoc
@@ -139,30 +161,34 @@ func (poc *PurchaseOrderController) GetPurchaseOrdersByStateID(c *gin.Context) {
 // @Description  Retrieves all purchase orders from the system.
 // @Tags         purchase_orders
 // @Produce      json
-// @Success      200      {array}  dtos.GetPurchaseOrderDTO  "List of Purchase Orders"
-// @Failure      403      {object} models.ErrorResponse     "Permission denied"
-// @Failure      404      {object} models.ErrorResponse     "Purchase Orders not found"
-// @Failure      500      {object} models.ErrorResponse     "Internal server error"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200      {object} dtos.PageDTO[dtos.GetPurchaseOrderDTO]  "List of Purchase Orders"
+// @Failure      403      {object} dtos.ErrorResponse     "Permission denied"
+// @Failure      404      {object} dtos.ErrorResponse     "Purchase Orders not found"
+// @Failure      500      {object} dtos.ErrorResponse     "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /purchase-orders [get]
 func (poc *PurchaseOrderController) GetAllPurchaseOrders(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_ALL_PURCHASE_ORDERS
 
-	if err := poc.Log.RegisterLog(c, "Attempting to retrieve all Purchase Orders"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if !poc.Auth.CheckPermission(c, permissionId) {
+		_ = poc.Log.RegisterLog(c, "Permission denied for GetAllPurchaseOrders")
+		utilities.RespondError(c, http.StatusForbidden, "Permission denied")
 		return
 	}
 
-	if !poc.Auth.CheckPermission(c, permissionId) {
-		_ = poc.Log.RegisterLog(c, "Permission denied for GetAllPurchaseOrders")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = poc.Log.RegisterLog(c, "Invalid pagination for GetAllPurchaseOrders: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	purchaseOrders, err := poc.Service.GetAllPurchaseOrders()
+	purchaseOrders, total, err := poc.Service.GetAllPurchaseOrders(c.Request.Context(), pagination)
 	if err != nil {
 		_ = poc.Log.RegisterLog(c, "Error retrieving all Purchase Orders")
-		c.JSON(http.StatusNotFound, gin.H{"error": "Purchase Orders not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Purchase Orders not found")
 		return
 	}
 
@@ -185,7 +211,7 @@ func (poc *PurchaseOrderController) GetAllPurchaseOrders(c *gin.Context) {
 
 	_ = poc.Log.RegisterLog(c, "Successfully retrieved all Purchase Orders")
 
-	c.JSON(http.StatusOK, purchaseOrderDTOs)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(purchaseOrderDTOs, pagination, total))
 }
 
 // SearchPurchaseOrdersByID godoc
@@ -194,44 +220,48 @@ func (poc *PurchaseOrderController) GetAllPurchaseOrders(c *gin.Context) {
 // @Tags         purchase_orders
 // @Produce      json
 // @Param        id  query     string  true  "Purchase Order ID"
-// @Success      200  {array}  dtos.GetPurchaseOrderDTO  "List of Purchase Orders"
-// @Failure      400  {object} models.ErrorResponse     "Missing 'id' query parameter"
-// @Failure      403  {object} models.ErrorResponse     "Permission denied"
-// @Failure      404  {object} models.ErrorResponse     "Purchase Orders not found"
-// @Failure      500  {object} models.ErrorResponse     "Internal server error"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200  {object} dtos.PageDTO[dtos.GetPurchaseOrderDTO]  "List of Purchase Orders"
+// @Failure      400  {object} dtos.ErrorResponse     "Missing 'id' query parameter"
+// @Failure      403  {object} dtos.ErrorResponse     "Permission denied"
+// @Failure      404  {object} dtos.ErrorResponse     "Purchase Orders not found"
+// @Failure      500  {object} dtos.ErrorResponse     "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /purchase-orders/searchByID [get]
 func (poc *PurchaseOrderController) SearchPurchaseOrdersByID(c *gin.Context) {
 	permissionId := config.PERMISSION_SEARCH_PURCHASE_ORDERS_BY_ID
 
-	if err := poc.Log.RegisterLog(c, "Attempting to search Purchase Orders by ID"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	if !poc.Auth.CheckPermission(c, permissionId) {
 		_ = poc.Log.RegisterLog(c, "Permission denied for SearchPurchaseOrdersByID")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
+		utilities.RespondError(c, http.StatusForbidden, "Permission denied")
 		return
 	}
 
 	id := c.Query("id")
 	if id == "" {
 		_ = poc.Log.RegisterLog(c, "Missing 'id' query parameter in SearchPurchaseOrdersByID")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter is required"})
+		utilities.RespondError(c, http.StatusBadRequest, "Query parameter is required")
 		return
 	}
 
-	purchaseOrders, err := poc.Service.SearchPurchaseOrdersByID(id)
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = poc.Log.RegisterLog(c, "Invalid pagination for SearchPurchaseOrdersByID: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	purchaseOrders, total, err := poc.Service.SearchPurchaseOrdersByID(c.Request.Context(), id, pagination)
 	if err != nil {
 		_ = poc.Log.RegisterLog(c, "Error retrieving Purchase Orders with ID: "+id)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Purchase Orders not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Purchase Orders not found")
 		return
 	}
 
-	if len(purchaseOrders) == 0 {
+	if total == 0 {
 		_ = poc.Log.RegisterLog(c, "No Purchase Orders found with ID: "+id)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No purchase orders found"})
+		utilities.RespondError(c, http.StatusNotFound, "No purchase orders found")
 		return
 	}
 
@@ -253,7 +283,7 @@ func (poc *PurchaseOrderController) SearchPurchaseOrdersByID(c *gin.Context) {
 	}
 
 	_ = poc.Log.RegisterLog(c, "Successfully found Purchase Orders with ID containing: "+id)
-	c.JSON(http.StatusOK, purchaseOrderDTOs)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(purchaseOrderDTOs, pagination, total))
 }
 
 // GetPurchaseOrdersByCustomerID godoc
@@ -262,38 +292,42 @@ func (poc *PurchaseOrderController) SearchPurchaseOrdersByID(c *gin.Context) {
 // @Tags         purchase_orders
 // @Produce      json
 // @Param        customerID  path     string  true  "Customer ID"
-// @Success      200         {array}  dtos.GetPurchaseOrderDTO  "List of Purchase Orders for the specified Customer ID"
-// @Failure      403         {object} models.ErrorResponse     "Permission denied"
-// @Failure      404         {object} models.ErrorResponse     "Purchase Orders not found for the specified Customer ID"
-// @Failure      500         {object} models.ErrorResponse     "Internal server error"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200         {object} dtos.PageDTO[dtos.GetPurchaseOrderDTO]  "List of Purchase Orders for the specified Customer ID"
+// @Failure      403         {object} dtos.ErrorResponse     "Permission denied"
+// @Failure      404         {object} dtos.ErrorResponse     "Purchase Orders not found for the specified Customer ID"
+// @Failure      500         {object} dtos.ErrorResponse     "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /purchase-orders/customers/{customerID} [get]
 func (poc *PurchaseOrderController) GetPurchaseOrdersByCustomerID(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_PURCHASE_ORDERS_BY_CUSTOMER_ID
 
-	if err := poc.Log.RegisterLog(c, "Attempting to retrieve Purchase Orders by Customer ID"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	if !poc.Auth.CheckPermission(c, permissionId) {
 		_ = poc.Log.RegisterLog(c, "Permission denied for GetPurchaseOrdersByCustomerID")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
+		utilities.RespondError(c, http.StatusForbidden, "Permission denied")
 		return
 	}
 
 	customerID := c.Param("customerID")
 
-	purchaseOrders, err := poc.Service.GetPurchaseOrdersByCustomerID(customerID)
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = poc.Log.RegisterLog(c, "Invalid pagination for GetPurchaseOrdersByCustomerID: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	purchaseOrders, total, err := poc.Service.GetPurchaseOrdersByCustomerID(c.Request.Context(), customerID, pagination)
 	if err != nil {
 		_ = poc.Log.RegisterLog(c, "Error retrieving Purchase Orders for Customer ID: "+customerID)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Purchase Orders not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Purchase Orders not found")
 		return
 	}
 
-	if len(purchaseOrders) == 0 {
+	if total == 0 {
 		_ = poc.Log.RegisterLog(c, "No Purchase Orders found for Customer ID: "+customerID)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No purchase orders found"})
+		utilities.RespondError(c, http.StatusNotFound, "No purchase orders found")
 		return
 	}
 
@@ -315,7 +349,7 @@ func (poc *PurchaseOrderController) GetPurchaseOrdersByCustomerID(c *gin.Context
 	}
 
 	_ = poc.Log.RegisterLog(c, "Successfully retrieved Purchase Orders for Customer ID: "+customerID)
-	c.JSON(http.StatusOK, purchaseOrderDTOs)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(purchaseOrderDTOs, pagination, total))
 }
 
 // GetPurchaseOrdersBySellerID godoc
@@ -324,32 +358,36 @@ func (poc *PurchaseOrderController) GetPurchaseOrdersByCustomerID(c *gin.Context
 // @Tags         purchase_orders
 // @Produce      json
 // @Param        sellerID  path     string  true  "Seller ID"
-// @Success      200       {array}  dtos.GetPurchaseOrderDTO  "List of Purchase Orders for the specified Seller ID"
-// @Failure      403       {object} models.ErrorResponse     "Permission denied"
-// @Failure      404       {object} models.ErrorResponse     "Purchase Orders not found for the specified Seller ID"
-// @Failure      500       {object} models.ErrorResponse     "Internal server error"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200       {object} dtos.PageDTO[dtos.GetPurchaseOrderDTO]  "List of Purchase Orders for the specified Seller ID"
+// @Failure      403       {object} dtos.ErrorResponse     "Permission denied"
+// @Failure      404       {object} dtos.ErrorResponse     "Purchase Orders not found for the specified Seller ID"
+// @Failure      500       {object} dtos.ErrorResponse     "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /purchase-orders/seller/{sellerID} [get]
 func (poc *PurchaseOrderController) GetPurchaseOrdersBySellerID(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_PURCHASE_ORDERS_BY_SELLER_ID
 
-	if err := poc.Log.RegisterLog(c, "Attempting to retrieve Purchase Orders by Seller ID"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	if !poc.Auth.CheckPermission(c, permissionId) {
 		_ = poc.Log.RegisterLog(c, "Permission denied for GetPurchaseOrdersBySellerID")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
+		utilities.RespondError(c, http.StatusForbidden, "Permission denied")
 		return
 	}
 
 	sellerID := c.Param("sellerID")
 
-	purchaseOrders, err := poc.Service.GetPurchaseOrdersBySellerID(sellerID)
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = poc.Log.RegisterLog(c, "Invalid pagination for GetPurchaseOrdersBySellerID: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	purchaseOrders, total, err := poc.Service.GetPurchaseOrdersBySellerID(c.Request.Context(), sellerID, pagination)
 	if err != nil {
 		_ = poc.Log.RegisterLog(c, "Error retrieving Purchase Orders for Seller ID: "+sellerID)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Purchase Orders not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Purchase Orders not found")
 		return
 	}
 
@@ -371,34 +409,30 @@ func (poc *PurchaseOrderController) GetPurchaseOrdersBySellerID(c *gin.Context)
 	}
 
 	_ = poc.Log.RegisterLog(c, "Successfully retrieved Purchase Orders for Seller ID: "+sellerID)
-	c.JSON(http.StatusOK, purchaseOrderDTOs)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(purchaseOrderDTOs, pagination, total))
 }
 
 // ChangePurchaseOrderState godoc
 // @Summary      Change the state of a Purchase Order
-// @Description  Update the state of a specific Purchase Order based on its ID.
+// @Description  Update the state of a specific Purchase Order based on its ID. Moving an issued order to in transit subtracts its stock only if every item has enough (409 INSUFFICIENT_STOCK otherwise) and cancelling an order in transit returns it; both happen in the same transaction as the state change.
 // @Tags         purchase_orders
 // @Produce      json
 // @Param        id            path     string  true  "Purchase Order ID"
 // @Param        order_state_id  body     int     true  "Order State ID"
 // @Success      200       {object}  models.MessageResponse  "Updated Purchase Order and associated Invoice"
-// @Failure      400       {object}  models.ErrorResponse     "Invalid request body"
-// @Failure      403       {object}  models.ErrorResponse     "Permission denied"
-// @Failure      404       {object}  models.ErrorResponse     "Purchase Order not found"
-// @Failure      500       {object}  models.ErrorResponse     "Internal server error"
+// @Failure      400       {object}  dtos.ErrorResponse     "Invalid request body"
+// @Failure      403       {object}  dtos.ErrorResponse     "Permission denied"
+// @Failure      404       {object}  dtos.ErrorResponse     "Purchase Order not found"
+// @Failure      409       {object}  dtos.ErrorResponse     "Insufficient stock, or the order state changed meanwhile"
+// @Failure      500       {object}  dtos.ErrorResponse     "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /purchase-orders/{id}/state [patch]
 func (poc *PurchaseOrderController) ChangePurchaseOrderState(c *gin.Context) {
 	permissionId := config.PERMISSION_UPDATE_PURCHASE_ORDER_STATE
 
-	if err := poc.Log.RegisterLog(c, "Attempting to update Purchase Order state"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	if !poc.Auth.CheckPermission(c, permissionId) {
 		_ = poc.Log.RegisterLog(c, "Permission denied for UpdatePurchaseOrderState")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
+		utilities.RespondError(c, http.StatusForbidden, "Permission denied")
 		return
 	}
 
@@ -410,16 +444,25 @@ func (poc *PurchaseOrderController) ChangePurchaseOrderState(c *gin.Context) {
 
 	if err := c.ShouldBindJSON(&request); err != nil {
 		_ = poc.Log.RegisterLog(c, "Error binding JSON for UpdatePurchaseOrderState: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utilities.RespondValidationError(c, "Invalid request body", err)
 		return
 	}
 
 	orderStateIDStr := strconv.Itoa(request.OrderStateID)
 
-	purchaseOrder, invoice, err := poc.Service.ChangePurchaseOrderState(id, orderStateIDStr)
+	purchaseOrder, invoice, err := poc.Service.ChangePurchaseOrderState(c.Request.Context(), id, orderStateIDStr)
 	if err != nil {
 		_ = poc.Log.RegisterLog(c, err.Error())
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		var insufficient *services.InsufficientStockError
+		if errors.As(err, &insufficient) {
+			utilities.RespondErrorWithDetails(c, http.StatusConflict, utilities.ErrCodeInsufficientStock, "Insufficient stock", gin.H{"items": insufficient.Shortages})
+			return
+		}
+		if errors.Is(err, services.ErrPurchaseOrderStateConflict) {
+			utilities.RespondError(c, http.StatusConflict, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusNotFound, err.Error())
 		return
 	}
 
@@ -446,10 +489,16 @@ func (poc *PurchaseOrderController) ChangePurchaseOrderState(c *gin.Context) {
 			DateTime:       invoice.DateTime,
 			CustomerID:     invoice.CustomerID,
 			Total:          invoice.Total,
+			Currency:       invoice.Currency,
+			ExchangeRate:   invoice.ExchangeRate,
+			PaymentStatus:  invoice.PaymentStatus,
+			AmountPaid:     invoice.AmountPaid,
+			Balance:        services.InvoiceBalance(invoice),
 			Subtotal:       invoice.Subtotal,
 			Items:          extractInvoiceBillingItems(invoice.Items),
 			Discounts:      extractDiscountIds(invoice.Discounts),
 			Taxes:          extractTaxIds(invoice.Taxes),
+			Version:        invoice.Version,
 		}
 	}
 
@@ -469,22 +518,17 @@ func (poc *PurchaseOrderController) ChangePurchaseOrderState(c *gin.Context) {
 // @Produce      json
 // @Param        purchase_order  body     dtos.CreatePurchaseOrderDTO  true  "Purchase Order details"
 // @Success      201       {object}  dtos.GetPurchaseOrderDTO     "Created Purchase Order"
-// @Failure      400       {object}  models.ErrorResponse        "Invalid request data"
-// @Failure      403       {object}  models.ErrorResponse        "Permission denied"
-// @Failure      500       {object}  models.ErrorResponse        "Internal server error"
+// @Failure      400       {object}  dtos.ErrorResponse        "Invalid request data"
+// @Failure      403       {object}  dtos.ErrorResponse        "Permission denied"
+// @Failure      500       {object}  dtos.ErrorResponse        "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /purchase-orders [post]
 func (poc *PurchaseOrderController) CreatePurchaseOrder(c *gin.Context) {
 	permissionId := config.PERMISSION_CREATE_PURCHASE_ORDER
 
-	if err := poc.Log.RegisterLog(c, "Attempting to create a new Purchase Order"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	if !poc.Auth.CheckPermission(c, permissionId) {
 		_ = poc.Log.RegisterLog(c, "Permission denied for CreatePurchaseOrder")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
+		utilities.RespondError(c, http.StatusForbidden, "Permission denied")
 		return
 	}
 
@@ -492,14 +536,14 @@ func (poc *PurchaseOrderController) CreatePurchaseOrder(c *gin.Context) {
 
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = poc.Log.RegisterLog(c, "Invalid request data for CreatePurchaseOrder: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		utilities.RespondValidationError(c, "Invalid request data", err)
 		return
 	}
 
-	purchaseOrder, err := poc.Service.CreatePurchaseOrder(&dto)
+	purchaseOrder, err := poc.Service.CreatePurchaseOrder(c.Request.Context(), &dto)
 	if err != nil {
 		_ = poc.Log.RegisterLog(c, "Error creating Purchase Order: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utilities.RespondError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -0,0 +1,408 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type QuotationController struct {
+	Service *services.QuotationService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewQuotationController(service *services.QuotationService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *QuotationController {
+	return &QuotationController{Service: service, Auth: auth, Log: log}
+}
+
+// GetQuotations godoc
+// @Summary      List quotations
+// @Description  Returns a page of quotations, newest first.
+// @Tags         quotations
+// @Produce      json
+// @Param        state       query  string  false  "State (pending, accepted, rejected, converted)"
+// @Param        customerId  query  int     false  "Customer ID"
+// @Param        page        query  int     false  "Page number (default 1)"
+// @Param        pageSize    query  int     false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[models.Quotation]  "Page of quotations"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid filter"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving quotations"
+// @Security     ApiKeyAuth
+// @Router       /quotations [get]
+func (qc *QuotationController) GetQuotations(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_QUOTATIONS
+	if !qc.Auth.CheckPermission(c, permissionId) {
+		_ = qc.Log.RegisterLog(c, "Access denied for GetQuotations")
+		return
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = qc.Log.RegisterLog(c, "Invalid pagination: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	state := c.Query("state")
+	if state != "" && !slices.Contains(services.QuotationStates, state) {
+		_ = qc.Log.RegisterLog(c, "Invalid quotation state: "+state)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid 'state'. Use one of: "+strings.Join(services.QuotationStates, ", "))
+		return
+	}
+
+	var customerID *int
+	if customerStr := c.Query("customerId"); customerStr != "" {
+		id, err := strconv.Atoi(customerStr)
+		if err != nil {
+			_ = qc.Log.RegisterLog(c, "Invalid customerId: "+customerStr)
+			utilities.RespondError(c, http.StatusBadRequest, "Invalid 'customerId'")
+			return
+		}
+		customerID = &id
+	}
+
+	quotations, total, err := qc.Service.GetQuotations(c.Request.Context(), state, customerID, pagination)
+	if err != nil {
+		_ = qc.Log.RegisterLog(c, "Error retrieving quotations: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving quotations")
+		return
+	}
+
+	_ = qc.Log.RegisterLog(c, "Successfully retrieved quotations")
+	c.JSON(http.StatusOK, dtos.NewPageDTO(quotations, pagination, total))
+}
+
+// GetQuotationByID godoc
+// @Summary      Get a quotation
+// @Description  Returns a quotation with its customer, items, discounts and taxes.
+// @Tags         quotations
+// @Produce      json
+// @Param        id   path      int  true  "Quotation ID"
+// @Success      200  {object}  models.Quotation  "Quotation"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid quotation ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Quotation not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving quotation"
+// @Security     ApiKeyAuth
+// @Router       /quotations/{id} [get]
+func (qc *QuotationController) GetQuotationByID(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_QUOTATIONS
+	if !qc.Auth.CheckPermission(c, permissionId) {
+		_ = qc.Log.RegisterLog(c, "Access denied for GetQuotationByID")
+		return
+	}
+
+	id, ok := qc.parseQuotationID(c)
+	if !ok {
+		return
+	}
+
+	quotation, err := qc.Service.GetQuotationByID(c.Request.Context(), id)
+	if err != nil {
+		_ = qc.Log.RegisterLog(c, "Error retrieving quotation with ID "+c.Param("id")+": "+err.Error())
+		qc.respondError(c, err, "Error retrieving quotation")
+		return
+	}
+
+	_ = qc.Log.RegisterLog(c, "Successfully retrieved quotation with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, quotation)
+}
+
+// CreateQuotation godoc
+// @Summary      Create a quotation
+// @Description  Creates a pending quotation for a customer. Subtotal and total are calculated like an invoice's, with the current item prices, and are kept when the quotation is converted. Each item, discount and tax may appear once, and expires_at must be in the future.
+// @Tags         quotations
+// @Accept       json
+// @Produce      json
+// @Param        quotation  body      dtos.QuotationDTO  true  "Quotation"
+// @Success      201        {object}  models.Quotation  "Created quotation"
+// @Failure      400        {object}  dtos.ErrorResponse  "Invalid request data"
+// @Failure      403        {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500        {object}  dtos.ErrorResponse  "Error creating quotation"
+// @Security     ApiKeyAuth
+// @Router       /quotations [post]
+func (qc *QuotationController) CreateQuotation(c *gin.Context) {
+	permissionId := config.PERMISSION_CREATE_QUOTATION
+	if !qc.Auth.CheckPermission(c, permissionId) {
+		_ = qc.Log.RegisterLog(c, "Access denied for CreateQuotation")
+		return
+	}
+
+	var dto dtos.QuotationDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = qc.Log.RegisterLog(c, "Invalid quotation data: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	quotation, err := qc.Service.CreateQuotation(c.Request.Context(), dto)
+	if err != nil {
+		_ = qc.Log.RegisterLog(c, "Error creating quotation: "+err.Error())
+		qc.respondError(c, err, "Error creating quotation")
+		return
+	}
+
+	_ = qc.Log.RegisterLog(c, "Successfully created quotation with ID: "+strconv.Itoa(quotation.ID))
+	c.JSON(http.StatusCreated, quotation)
+}
+
+// UpdateQuotation godoc
+// @Summary      Update a quotation
+// @Description  Replaces a pending quotation and recalculates its values with the current item prices.
+// @Tags         quotations
+// @Accept       json
+// @Produce      json
+// @Param        id         path      int                true  "Quotation ID"
+// @Param        quotation  body      dtos.QuotationDTO  true  "Quotation"
+// @Success      200        {object}  models.Quotation  "Updated quotation"
+// @Failure      400        {object}  dtos.ErrorResponse  "Invalid request data"
+// @Failure      403        {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404        {object}  dtos.ErrorResponse  "Quotation not found"
+// @Failure      409        {object}  dtos.ErrorResponse  "The quotation is no longer pending"
+// @Failure      500        {object}  dtos.ErrorResponse  "Error updating quotation"
+// @Security     ApiKeyAuth
+// @Router       /quotations/{id} [put]
+func (qc *QuotationController) UpdateQuotation(c *gin.Context) {
+	permissionId := config.PERMISSION_UPDATE_QUOTATION
+	if !qc.Auth.CheckPermission(c, permissionId) {
+		_ = qc.Log.RegisterLog(c, "Access denied for UpdateQuotation")
+		return
+	}
+
+	id, ok := qc.parseQuotationID(c)
+	if !ok {
+		return
+	}
+
+	var dto dtos.QuotationDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = qc.Log.RegisterLog(c, "Invalid quotation data: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	quotation, err := qc.Service.UpdateQuotation(c.Request.Context(), id, dto)
+	if err != nil {
+		_ = qc.Log.RegisterLog(c, "Error updating quotation with ID "+c.Param("id")+": "+err.Error())
+		qc.respondError(c, err, "Error updating quotation")
+		return
+	}
+
+	_ = qc.Log.RegisterLog(c, "Successfully updated quotation with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, quotation)
+}
+
+// DeleteQuotation godoc
+// @Summary      Delete a quotation
+// @Description  Deletes a quotation that has not been converted into an invoice.
+// @Tags         quotations
+// @Produce      json
+// @Param        id   path      int  true  "Quotation ID"
+// @Success      200  {object}  models.MessageResponse  "Quotation deleted"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid quotation ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Quotation not found"
+// @Failure      409  {object}  dtos.ErrorResponse  "The quotation was converted"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error deleting quotation"
+// @Security     ApiKeyAuth
+// @Router       /quotations/{id} [delete]
+func (qc *QuotationController) DeleteQuotation(c *gin.Context) {
+	permissionId := config.PERMISSION_DELETE_QUOTATION
+	if !qc.Auth.CheckPermission(c, permissionId) {
+		_ = qc.Log.RegisterLog(c, "Access denied for DeleteQuotation")
+		return
+	}
+
+	id, ok := qc.parseQuotationID(c)
+	if !ok {
+		return
+	}
+
+	if err := qc.Service.DeleteQuotation(c.Request.Context(), id); err != nil {
+		_ = qc.Log.RegisterLog(c, "Error deleting quotation with ID "+c.Param("id")+": "+err.Error())
+		qc.respondError(c, err, "Error deleting quotation")
+		return
+	}
+
+	_ = qc.Log.RegisterLog(c, "Successfully deleted quotation with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, gin.H{"message": "Quotation deleted successfully"})
+}
+
+// AcceptQuotation godoc
+// @Summary      Accept a quotation
+// @Description  Records that the customer accepted a pending quotation. Expired quotations cannot be accepted.
+// @Tags         quotations
+// @Produce      json
+// @Param        id   path      int  true  "Quotation ID"
+// @Success      200  {object}  models.Quotation  "Accepted quotation"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid quotation ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Quotation not found"
+// @Failure      409  {object}  dtos.ErrorResponse  "The quotation is not pending or has expired"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error accepting quotation"
+// @Security     ApiKeyAuth
+// @Router       /quotations/{id}/accept [post]
+func (qc *QuotationController) AcceptQuotation(c *gin.Context) {
+	permissionId := config.PERMISSION_DECIDE_QUOTATION
+	if !qc.Auth.CheckPermission(c, permissionId) {
+		_ = qc.Log.RegisterLog(c, "Access denied for AcceptQuotation")
+		return
+	}
+
+	id, ok := qc.parseQuotationID(c)
+	if !ok {
+		return
+	}
+
+	quotation, err := qc.Service.AcceptQuotation(c.Request.Context(), id)
+	if err != nil {
+		_ = qc.Log.RegisterLog(c, "Error accepting quotation with ID "+c.Param("id")+": "+err.Error())
+		qc.respondError(c, err, "Error accepting quotation")
+		return
+	}
+
+	_ = qc.Log.RegisterLog(c, "Successfully accepted quotation with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, quotation)
+}
+
+// RejectQuotation godoc
+// @Summary      Reject a quotation
+// @Description  Records that the customer rejected a pending or accepted quotation.
+// @Tags         quotations
+// @Produce      json
+// @Param        id   path      int  true  "Quotation ID"
+// @Success      200  {object}  models.Quotation  "Rejected quotation"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid quotation ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Quotation not found"
+// @Failure      409  {object}  dtos.ErrorResponse  "The quotation is already rejected or converted"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error rejecting quotation"
+// @Security     ApiKeyAuth
+// @Router       /quotations/{id}/reject [post]
+func (qc *QuotationController) RejectQuotation(c *gin.Context) {
+	permissionId := config.PERMISSION_DECIDE_QUOTATION
+	if !qc.Auth.CheckPermission(c, permissionId) {
+		_ = qc.Log.RegisterLog(c, "Access denied for RejectQuotation")
+		return
+	}
+
+	id, ok := qc.parseQuotationID(c)
+	if !ok {
+		return
+	}
+
+	quotation, err := qc.Service.RejectQuotation(c.Request.Context(), id)
+	if err != nil {
+		_ = qc.Log.RegisterLog(c, "Error rejecting quotation with ID "+c.Param("id")+": "+err.Error())
+		qc.respondError(c, err, "Error rejecting quotation")
+		return
+	}
+
+	_ = qc.Log.RegisterLog(c, "Successfully rejected quotation with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, quotation)
+}
+
+// ConvertQuotation godoc
+// @Summary      Convert a quotation into an invoice
+// @Description  Creates an invoice from an accepted quotation, with its items, discounts, taxes and quoted values, and marks the quotation as converted. Stock is deducted like when creating an invoice; with due_date the invoice is issued on credit.
+// @Tags         quotations
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                       true   "Quotation ID"
+// @Param        invoice  body      dtos.ConvertQuotationDTO  false  "Invoice data"
+// @Success      201      {object}  dtos.GetInvoiceDTO  "Created invoice"
+// @Failure      400      {object}  dtos.ErrorResponse  "Invalid request data"
+// @Failure      403      {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404      {object}  dtos.ErrorResponse  "Quotation not found"
+// @Failure      409      {object}  dtos.ErrorResponse  "The quotation is not accepted, or insufficient stock; details.items lists each item short"
+// @Failure      500      {object}  dtos.ErrorResponse  "Error converting quotation"
+// @Security     ApiKeyAuth
+// @Router       /quotations/{id}/convert [post]
+func (qc *QuotationController) ConvertQuotation(c *gin.Context) {
+	permissionId := config.PERMISSION_CONVERT_QUOTATION
+	if !qc.Auth.CheckPermission(c, permissionId) {
+		_ = qc.Log.RegisterLog(c, "Access denied for ConvertQuotation")
+		return
+	}
+
+	id, ok := qc.parseQuotationID(c)
+	if !ok {
+		return
+	}
+
+	var dto dtos.ConvertQuotationDTO
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&dto); err != nil {
+			_ = qc.Log.RegisterLog(c, "Invalid quotation conversion data: "+err.Error())
+			utilities.RespondValidationError(c, "Invalid request data", err)
+			return
+		}
+	}
+
+	invoice, err := qc.Service.ConvertQuotation(c.Request.Context(), id, dto)
+	if err != nil {
+		_ = qc.Log.RegisterLog(c, "Error converting quotation with ID "+c.Param("id")+": "+err.Error())
+		qc.respondError(c, err, "Error converting quotation")
+		return
+	}
+
+	invoiceDTO := dtos.GetInvoiceDTO{
+		ID:             invoice.ID,
+		EnterpriseData: invoice.EnterpriseData,
+		DateTime:       invoice.DateTime,
+		CustomerID:     invoice.CustomerID,
+		Subtotal:       invoice.Subtotal,
+		Total:          invoice.Total,
+		Currency:       invoice.Currency,
+		ExchangeRate:   invoice.ExchangeRate,
+		PaymentStatus:  invoice.PaymentStatus,
+		AmountPaid:     invoice.AmountPaid,
+		Balance:        services.InvoiceBalance(invoice),
+		Items:          extractInvoiceBillingItems(invoice.Items),
+		Discounts:      extractDiscountIds(invoice.Discounts),
+		Taxes:          extractTaxIds(invoice.Taxes),
+		DueDate:        invoice.DueDate,
+		PaidAt:         invoice.PaidAt,
+		Version:        invoice.Version,
+	}
+
+	_ = qc.Log.RegisterLog(c, "Successfully converted quotation with ID "+c.Param("id")+" into invoice "+strconv.Itoa(invoice.ID))
+	c.JSON(http.StatusCreated, invoiceDTO)
+}
+
+func (qc *QuotationController) parseQuotationID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = qc.Log.RegisterLog(c, "Invalid quotation ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid quotation ID")
+		return 0, false
+	}
+	return id, true
+}
+
+func (qc *QuotationController) respondError(c *gin.Context, err error, message string) {
+	var insufficient *services.InsufficientStockError
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utilities.RespondError(c, http.StatusNotFound, "Quotation not found")
+	case errors.Is(err, services.ErrInvalidQuotation):
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrQuotationTransition):
+		utilities.RespondError(c, http.StatusConflict, err.Error())
+	case errors.As(err, &insufficient):
+		utilities.RespondErrorWithDetails(c, http.StatusConflict, utilities.ErrCodeInsufficientStock, "Insufficient stock", gin.H{"items": insufficient.Shortages})
+	default:
+		utilities.RespondError(c, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,337 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type RestockOrderController struct {
+	Service *services.RestockOrderService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewRestockOrderController(service *services.RestockOrderService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *RestockOrderController {
+	return &RestockOrderController{Service: service, Auth: auth, Log: log}
+}
+
+// GetRestockOrders godoc
+// @Summary      List restock orders
+// @Description  Returns a page of the orders placed with suppliers to restock inventory, newest first.
+// @Tags         restock-orders
+// @Produce      json
+// @Param        state     query  string  false  "State (draft, sent, received, cancelled)"
+// @Param        page      query  int     false  "Page number (default 1)"
+// @Param        pageSize  query  int     false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[models.RestockOrder]  "Page of restock orders"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid filter"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving restock orders"
+// @Security     ApiKeyAuth
+// @Router       /restock-orders [get]
+func (rc *RestockOrderController) GetRestockOrders(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_RESTOCK_ORDERS
+	if !rc.Auth.CheckPermission(c, permissionId) {
+		_ = rc.Log.RegisterLog(c, "Access denied for GetRestockOrders")
+		return
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Invalid pagination: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	state := c.Query("state")
+	if state != "" && !slices.Contains(services.RestockOrderStates, state) {
+		_ = rc.Log.RegisterLog(c, "Invalid restock order state: "+state)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid 'state'. Use one of: "+strings.Join(services.RestockOrderStates, ", "))
+		return
+	}
+
+	orders, total, err := rc.Service.GetRestockOrders(c.Request.Context(), state, pagination)
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Error retrieving restock orders: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving restock orders")
+		return
+	}
+
+	_ = rc.Log.RegisterLog(c, "Successfully retrieved restock orders")
+	c.JSON(http.StatusOK, dtos.NewPageDTO(orders, pagination, total))
+}
+
+// GetRestockOrderByID godoc
+// @Summary      Get a restock order
+// @Description  Returns a restock order with its lines, including how much of each has been received.
+// @Tags         restock-orders
+// @Produce      json
+// @Param        id   path      int  true  "Restock order ID"
+// @Success      200  {object}  models.RestockOrder  "Restock order"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid restock order ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Restock order not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving restock order"
+// @Security     ApiKeyAuth
+// @Router       /restock-orders/{id} [get]
+func (rc *RestockOrderController) GetRestockOrderByID(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_RESTOCK_ORDERS
+	if !rc.Auth.CheckPermission(c, permissionId) {
+		_ = rc.Log.RegisterLog(c, "Access denied for GetRestockOrderByID")
+		return
+	}
+
+	id, ok := rc.parseRestockOrderID(c)
+	if !ok {
+		return
+	}
+
+	order, err := rc.Service.GetRestockOrderByID(c.Request.Context(), id)
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Error retrieving restock order with ID "+c.Param("id")+": "+err.Error())
+		rc.respondError(c, err, "Error retrieving restock order")
+		return
+	}
+
+	_ = rc.Log.RegisterLog(c, "Successfully retrieved restock order with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, order)
+}
+
+// CreateRestockOrder godoc
+// @Summary      Create a restock order
+// @Description  Creates a draft order for a supplier, given by supplier_id (an active supplier) or, for suppliers not registered, by supplier_name. Each item may appear once; the total is the sum of quantity times unit cost.
+// @Tags         restock-orders
+// @Accept       json
+// @Produce      json
+// @Param        order  body      dtos.RestockOrderDTO  true  "Restock order"
+// @Success      201    {object}  models.RestockOrder  "Created restock order"
+// @Failure      400    {object}  dtos.ErrorResponse  "Invalid request data"
+// @Failure      403    {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500    {object}  dtos.ErrorResponse  "Error creating restock order"
+// @Security     ApiKeyAuth
+// @Router       /restock-orders [post]
+func (rc *RestockOrderController) CreateRestockOrder(c *gin.Context) {
+	permissionId := config.PERMISSION_CREATE_RESTOCK_ORDER
+	if !rc.Auth.CheckPermission(c, permissionId) {
+		_ = rc.Log.RegisterLog(c, "Access denied for CreateRestockOrder")
+		return
+	}
+
+	var dto dtos.RestockOrderDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = rc.Log.RegisterLog(c, "Invalid restock order data: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	order, err := rc.Service.CreateRestockOrder(c.Request.Context(), dto)
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Error creating restock order: "+err.Error())
+		rc.respondError(c, err, "Error creating restock order")
+		return
+	}
+
+	_ = rc.Log.RegisterLog(c, "Successfully created restock order with ID: "+strconv.Itoa(order.ID))
+	c.JSON(http.StatusCreated, order)
+}
+
+// UpdateRestockOrder godoc
+// @Summary      Update a draft restock order
+// @Description  Replaces the supplier, notes and lines of an order that is still a draft.
+// @Tags         restock-orders
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int                   true  "Restock order ID"
+// @Param        order  body      dtos.RestockOrderDTO  true  "Restock order"
+// @Success      200    {object}  models.RestockOrder  "Updated restock order"
+// @Failure      400    {object}  dtos.ErrorResponse  "Invalid request data"
+// @Failure      403    {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404    {object}  dtos.ErrorResponse  "Restock order not found"
+// @Failure      409    {object}  dtos.ErrorResponse  "The order is no longer a draft"
+// @Failure      500    {object}  dtos.ErrorResponse  "Error updating restock order"
+// @Security     ApiKeyAuth
+// @Router       /restock-orders/{id} [put]
+func (rc *RestockOrderController) UpdateRestockOrder(c *gin.Context) {
+	permissionId := config.PERMISSION_UPDATE_RESTOCK_ORDER
+	if !rc.Auth.CheckPermission(c, permissionId) {
+		_ = rc.Log.RegisterLog(c, "Access denied for UpdateRestockOrder")
+		return
+	}
+
+	id, ok := rc.parseRestockOrderID(c)
+	if !ok {
+		return
+	}
+
+	var dto dtos.RestockOrderDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = rc.Log.RegisterLog(c, "Invalid restock order data: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	order, err := rc.Service.UpdateRestockOrder(c.Request.Context(), id, dto)
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Error updating restock order with ID "+c.Param("id")+": "+err.Error())
+		rc.respondError(c, err, "Error updating restock order")
+		return
+	}
+
+	_ = rc.Log.RegisterLog(c, "Successfully updated restock order with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, order)
+}
+
+// SendRestockOrder godoc
+// @Summary      Mark a restock order as sent
+// @Description  Moves a draft order to sent, once it has been placed with the supplier. Sent orders can no longer be edited.
+// @Tags         restock-orders
+// @Produce      json
+// @Param        id   path      int  true  "Restock order ID"
+// @Success      200  {object}  models.RestockOrder  "Sent restock order"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid restock order ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Restock order not found"
+// @Failure      409  {object}  dtos.ErrorResponse  "The order is not a draft"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error sending restock order"
+// @Security     ApiKeyAuth
+// @Router       /restock-orders/{id}/send [post]
+func (rc *RestockOrderController) SendRestockOrder(c *gin.Context) {
+	permissionId := config.PERMISSION_SEND_RESTOCK_ORDER
+	if !rc.Auth.CheckPermission(c, permissionId) {
+		_ = rc.Log.RegisterLog(c, "Access denied for SendRestockOrder")
+		return
+	}
+
+	id, ok := rc.parseRestockOrderID(c)
+	if !ok {
+		return
+	}
+
+	order, err := rc.Service.SendRestockOrder(c.Request.Context(), id)
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Error sending restock order with ID "+c.Param("id")+": "+err.Error())
+		rc.respondError(c, err, "Error sending restock order")
+		return
+	}
+
+	_ = rc.Log.RegisterLog(c, "Successfully sent restock order with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, order)
+}
+
+// ReceiveRestockOrder godoc
+// @Summary      Receive a restock order
+// @Description  Adds the delivered quantities to the lines of a sent order and to the items' stock, recording a stock movement for each. Without items everything pending is received. A line cannot exceed the quantity ordered. When every line is complete the order becomes received.
+// @Tags         restock-orders
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                          true   "Restock order ID"
+// @Param        receipt  body      dtos.ReceiveRestockOrderDTO  false  "Delivered quantities"
+// @Success      200      {object}  models.RestockOrder  "Restock order after the receipt"
+// @Failure      400      {object}  dtos.ErrorResponse  "Invalid quantities"
+// @Failure      403      {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404      {object}  dtos.ErrorResponse  "Restock order not found"
+// @Failure      409      {object}  dtos.ErrorResponse  "The order is not sent, or changed meanwhile"
+// @Failure      500      {object}  dtos.ErrorResponse  "Error receiving restock order"
+// @Security     ApiKeyAuth
+// @Router       /restock-orders/{id}/receive [post]
+func (rc *RestockOrderController) ReceiveRestockOrder(c *gin.Context) {
+	permissionId := config.PERMISSION_RECEIVE_RESTOCK_ORDER
+	if !rc.Auth.CheckPermission(c, permissionId) {
+		_ = rc.Log.RegisterLog(c, "Access denied for ReceiveRestockOrder")
+		return
+	}
+
+	id, ok := rc.parseRestockOrderID(c)
+	if !ok {
+		return
+	}
+
+	var dto dtos.ReceiveRestockOrderDTO
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&dto); err != nil {
+			_ = rc.Log.RegisterLog(c, "Invalid restock receipt data: "+err.Error())
+			utilities.RespondValidationError(c, "Invalid request data", err)
+			return
+		}
+	}
+
+	order, err := rc.Service.ReceiveRestockOrder(c.Request.Context(), id, dto)
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Error receiving restock order with ID "+c.Param("id")+": "+err.Error())
+		rc.respondError(c, err, "Error receiving restock order")
+		return
+	}
+
+	_ = rc.Log.RegisterLog(c, "Successfully received restock order with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, order)
+}
+
+// CancelRestockOrder godoc
+// @Summary      Cancel a restock order
+// @Description  Cancels a draft or sent order. Units already received stay in stock.
+// @Tags         restock-orders
+// @Produce      json
+// @Param        id   path      int  true  "Restock order ID"
+// @Success      200  {object}  models.RestockOrder  "Cancelled restock order"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid restock order ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Restock order not found"
+// @Failure      409  {object}  dtos.ErrorResponse  "The order is already received or cancelled"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error cancelling restock order"
+// @Security     ApiKeyAuth
+// @Router       /restock-orders/{id}/cancel [post]
+func (rc *RestockOrderController) CancelRestockOrder(c *gin.Context) {
+	permissionId := config.PERMISSION_CANCEL_RESTOCK_ORDER
+	if !rc.Auth.CheckPermission(c, permissionId) {
+		_ = rc.Log.RegisterLog(c, "Access denied for CancelRestockOrder")
+		return
+	}
+
+	id, ok := rc.parseRestockOrderID(c)
+	if !ok {
+		return
+	}
+
+	order, err := rc.Service.CancelRestockOrder(c.Request.Context(), id)
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Error cancelling restock order with ID "+c.Param("id")+": "+err.Error())
+		rc.respondError(c, err, "Error cancelling restock order")
+		return
+	}
+
+	_ = rc.Log.RegisterLog(c, "Successfully cancelled restock order with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, order)
+}
+
+func (rc *RestockOrderController) parseRestockOrderID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Invalid restock order ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid restock order ID")
+		return 0, false
+	}
+	return id, true
+}
+
+func (rc *RestockOrderController) respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utilities.RespondError(c, http.StatusNotFound, "Restock order not found")
+	case errors.Is(err, services.ErrInvalidRestockOrder):
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrRestockOrderTransition):
+		utilities.RespondError(c, http.StatusConflict, err.Error())
+	default:
+		utilities.RespondError(c, http.StatusInternalServerError, message)
+	}
+}
@@ -1,24 +1,30 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
 	"totesbackend/dtos"
+	"totesbackend/models"
 	"totesbackend/services"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type RoleController struct {
 	Service *services.RoleService
 	Auth    *utilities.AuthorizationUtil
 	Log     *utilities.LogUtil
+	Audit   *utilities.AuditUtil
 }
 
-func NewRoleController(service *services.RoleService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *RoleController {
-	return &RoleController{Service: service, Auth: auth, Log: log}
+func NewRoleController(service *services.RoleService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil, audit *utilities.AuditUtil) *RoleController {
+	return &RoleController{Service: service, Auth: auth, Log: log, Audit: audit}
 }
 
 // GetRoleByID godoc
@@ -28,10 +34,10 @@ func NewRoleController(service *services.RoleService, auth *utilities.Authorizat
 // @Produce      json
 // @Param        id  path     int  true  "Role ID"
 // @Success      200  {object}  dtos.RoleDTO  "Role details with permissions"
-// @Failure      400  {object}  models.ErrorResponse  "Invalid role ID"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      404  {object}  models.ErrorResponse  "Role not found"
-// @Failure      500  {object}  models.ErrorResponse  "Internal server error"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid role ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Role not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /roles/{id} [get]
 func (rc *RoleController) GetRoleByID(c *gin.Context) {
@@ -44,29 +50,24 @@ func (rc *RoleController) GetRoleByID(c *gin.Context) {
 
 	idParam := c.Param("id")
 
-	if rc.Log.RegisterLog(c, "Attempting to retrieve role with ID: "+idParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	var id uint
 	if _, err := fmt.Sscanf(idParam, "%d", &id); err != nil {
 		_ = rc.Log.RegisterLog(c, "Invalid role ID format: "+idParam)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role ID"})
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid role ID")
 		return
 	}
 
-	role, err := rc.Service.GetRoleByID(id)
+	role, err := rc.Service.GetRoleByID(c.Request.Context(), id)
 	if err != nil {
 		_ = rc.Log.RegisterLog(c, "Role not found with ID: "+idParam)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Role not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Role not found")
 		return
 	}
 
-	permissionIDs, err := rc.Service.GetRolePermissions(id)
+	permissionIDs, err := rc.Service.GetRolePermissions(c.Request.Context(), id)
 	if err != nil {
 		_ = rc.Log.RegisterLog(c, "Error retrieving role permissions for ID: "+idParam)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving role permissions"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving role permissions")
 		return
 	}
 
@@ -90,9 +91,11 @@ func (rc *RoleController) GetRoleByID(c *gin.Context) {
 // @Description  Retrieve a list of all roles, including their associated permissions.
 // @Tags         roles
 // @Produce      json
-// @Success      200  {array}  dtos.RoleDTO  "List of roles with permissions"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500  {object}  models.ErrorResponse  "Error retrieving roles"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200  {object} dtos.PageDTO[dtos.RoleDTO]  "List of roles with permissions"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving roles"
 // @Security     ApiKeyAuth
 // @Router       /roles [get]
 func (rc *RoleController) GetAllRoles(c *gin.Context) {
@@ -103,24 +106,26 @@ func (rc *RoleController) GetAllRoles(c *gin.Context) {
 		return
 	}
 
-	if rc.Log.RegisterLog(c, "Attempting to retrieve all roles") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Invalid pagination for GetAllRoles: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	roles, err := rc.Service.GetAllRoles()
+	roles, total, err := rc.Service.GetAllRoles(c.Request.Context(), pagination)
 	if err != nil {
 		_ = rc.Log.RegisterLog(c, "Error retrieving roles")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving roles"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving roles")
 		return
 	}
 
 	var rolesDTO []dtos.RoleDTO
 	for _, role := range roles {
-		permissionIDs, err := rc.Service.GetRolePermissions(role.ID)
+		permissionIDs, err := rc.Service.GetRolePermissions(c.Request.Context(), role.ID)
 		if err != nil {
 			_ = rc.Log.RegisterLog(c, "Error retrieving permissions for role ID: "+fmt.Sprintf("%d", role.ID))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving role permissions"})
+			utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving role permissions")
 			return
 		}
 
@@ -139,7 +144,7 @@ func (rc *RoleController) GetAllRoles(c *gin.Context) {
 	}
 
 	_ = rc.Log.RegisterLog(c, "Successfully retrieved all roles")
-	c.JSON(http.StatusOK, rolesDTO)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(rolesDTO, pagination, total))
 }
 
 // GetAllPermissionsOfRole godoc
@@ -148,10 +153,12 @@ func (rc *RoleController) GetAllRoles(c *gin.Context) {
 // @Tags         roles
 // @Produce      json
 // @Param        id  path  int  true  "Role ID"
-// @Success      200  {array}  string  "List of permissions for the role"
-// @Failure      400  {object}  models.ErrorResponse  "Invalid role ID"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500  {object}  models.ErrorResponse  "Error retrieving permissions for role"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200  {object} dtos.PageDTO[models.Permission]  "List of permissions for the role"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid role ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving permissions for role"
 // @Security     ApiKeyAuth
 // @Router       /roles/{id}/permission [get]
 func (rc *RoleController) GetAllPermissionsOfRole(c *gin.Context) {
@@ -164,27 +171,29 @@ func (rc *RoleController) GetAllPermissionsOfRole(c *gin.Context) {
 
 	roleIDParam := c.Param("id")
 
-	if rc.Log.RegisterLog(c, "Attempting to retrieve permissions for role ID: "+roleIDParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	var roleID uint
 	if _, err := fmt.Sscanf(roleIDParam, "%d", &roleID); err != nil {
 		_ = rc.Log.RegisterLog(c, "Invalid role ID format: "+roleIDParam)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role ID"})
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid role ID")
+		return
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Invalid pagination for GetAllPermissionsOfRole: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	permissions, err := rc.Service.GetAllPermissionsOfRole(roleID)
+	permissions, total, err := rc.Service.GetAllPermissionsOfRole(c.Request.Context(), roleID, pagination)
 	if err != nil {
 		_ = rc.Log.RegisterLog(c, "Error retrieving permissions for role ID: "+roleIDParam)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving permissions for role"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving permissions for role")
 		return
 	}
 
 	_ = rc.Log.RegisterLog(c, "Successfully retrieved permissions for role ID: "+roleIDParam)
-	c.JSON(http.StatusOK, permissions)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(permissions, pagination, total))
 }
 
 // ExistRole godoc
@@ -194,9 +203,9 @@ func (rc *RoleController) GetAllPermissionsOfRole(c *gin.Context) {
 // @Produce      json
 // @Param        id  path  int  true  "Role ID"
 // @Success      200  {object}  models.MessageResponse  "Returns a boolean indicating if the role exists"
-// @Failure      400  {object}  models.ErrorResponse  "Invalid role ID"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500  {object}  models.ErrorResponse  "Error checking role existence"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid role ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error checking role existence"
 // @Security     ApiKeyAuth
 // @Router       /roles/{id}/exist [get]
 func (rc *RoleController) ExistRole(c *gin.Context) {
@@ -209,22 +218,17 @@ func (rc *RoleController) ExistRole(c *gin.Context) {
 
 	idParam := c.Param("id")
 
-	if rc.Log.RegisterLog(c, "Attempting to check existence of role with ID: "+idParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	var id uint
 	if _, err := fmt.Sscanf(idParam, "%d", &id); err != nil {
 		_ = rc.Log.RegisterLog(c, "Invalid role ID format: "+idParam)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role ID"})
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid role ID")
 		return
 	}
 
-	exists, err := rc.Service.ExistRole(id)
+	exists, err := rc.Service.ExistRole(c.Request.Context(), id)
 	if err != nil {
 		_ = rc.Log.RegisterLog(c, "Error checking existence of role ID: "+idParam)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking role existence"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error checking role existence")
 		return
 	}
 
@@ -238,10 +242,12 @@ func (rc *RoleController) ExistRole(c *gin.Context) {
 // @Tags         roles
 // @Produce      json
 // @Param        id  query  string  true  "Role ID"
-// @Success      200  {array}  models.Role  "Returns the roles matching the search criteria"
-// @Failure      400  {object}  models.ErrorResponse  "Invalid query parameter"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500  {object}  models.ErrorResponse  "Error searching roles by ID"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200  {object} dtos.PageDTO[models.Role]  "Returns the roles matching the search criteria"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid query parameter"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error searching roles by ID"
 // @Security     ApiKeyAuth
 // @Router       /roles/searchByID [get]
 func (rc *RoleController) SearchRolesByID(c *gin.Context) {
@@ -254,20 +260,22 @@ func (rc *RoleController) SearchRolesByID(c *gin.Context) {
 
 	query := c.Query("id")
 
-	if rc.Log.RegisterLog(c, "Attempting to search roles by ID: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Invalid pagination for SearchRolesByID: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	roles, err := rc.Service.SearchRolesByID(query)
+	roles, total, err := rc.Service.SearchRolesByID(c.Request.Context(), query, pagination)
 	if err != nil {
 		_ = rc.Log.RegisterLog(c, "Error searching roles by ID: "+query)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error searching roles by ID"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error searching roles by ID")
 		return
 	}
 
 	_ = rc.Log.RegisterLog(c, "Successfully searched roles by ID: "+query)
-	c.JSON(http.StatusOK, roles)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(roles, pagination, total))
 }
 
 // SearchRolesByName godoc
@@ -276,10 +284,12 @@ func (rc *RoleController) SearchRolesByID(c *gin.Context) {
 // @Tags         roles
 // @Produce      json
 // @Param        name  query  string  true  "Role Name"
-// @Success      200  {array}  models.Role  "Returns the roles matching the search criteria"
-// @Failure      400  {object}  models.ErrorResponse  "Invalid query parameter"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500  {object}  models.ErrorResponse  "Error searching roles by name"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200  {object} dtos.PageDTO[models.Role]  "Returns the roles matching the search criteria"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid query parameter"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error searching roles by name"
 // @Security     ApiKeyAuth
 // @Router       /roles/searchByName [get]
 func (rc *RoleController) SearchRolesByName(c *gin.Context) {
@@ -292,18 +302,265 @@ func (rc *RoleController) SearchRolesByName(c *gin.Context) {
 
 	query := c.Query("name")
 
-	if rc.Log.RegisterLog(c, "Attempting to search roles by name: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Invalid pagination for SearchRolesByName: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	roles, err := rc.Service.SearchRolesByName(query)
+	roles, total, err := rc.Service.SearchRolesByName(c.Request.Context(), query, pagination)
 	if err != nil {
 		_ = rc.Log.RegisterLog(c, "Error searching roles by name: "+query)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error searching roles by name"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error searching roles by name")
 		return
 	}
 
 	_ = rc.Log.RegisterLog(c, "Successfully searched roles by name: "+query)
-	c.JSON(http.StatusOK, roles)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(roles, pagination, total))
+}
+
+// CreateRole godoc
+// @Summary      Create a role
+// @Description  Adds a role with the given permissions (optional). Role names are unique, ignoring case, and every permission ID must exist.
+// @Tags         roles
+// @Accept       json
+// @Produce      json
+// @Param        role  body      dtos.CreateRoleDTO  true  "Role"
+// @Success      201   {object}  dtos.RoleDTO  "Created role"
+// @Failure      400   {object}  dtos.ErrorResponse  "Invalid data or unknown permission IDs"
+// @Failure      403   {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      409   {object}  dtos.ErrorResponse  "Role name already in use"
+// @Failure      500   {object}  dtos.ErrorResponse  "Error creating role"
+// @Security     ApiKeyAuth
+// @Router       /roles [post]
+func (rc *RoleController) CreateRole(c *gin.Context) {
+	permissionId := config.PERMISSION_CREATE_ROLE
+	if !rc.Auth.CheckPermission(c, permissionId) {
+		_ = rc.Log.RegisterLog(c, "Access denied for CreateRole")
+		return
+	}
+
+	var dto dtos.CreateRoleDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = rc.Log.RegisterLog(c, "Invalid role data: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	role, err := rc.Service.CreateRole(c.Request.Context(), dto)
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Error creating role: "+err.Error())
+		rc.respondError(c, err, "Error creating role")
+		return
+	}
+
+	rc.recordRoleChange(c, role.ID, services.AUDIT_ACTION_CREATE, nil, role, "role "+role.Name+" created")
+
+	_ = rc.Log.RegisterLog(c, fmt.Sprintf("Successfully created role with ID: %d", role.ID))
+	c.JSON(http.StatusCreated, newRoleDTO(role))
+}
+
+// UpdateRole godoc
+// @Summary      Update a role
+// @Description  Changes the role's name and description. Its permissions are changed with PUT /roles/{id}/permissions.
+// @Tags         roles
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                 true  "Role ID"
+// @Param        role  body      dtos.UpdateRoleDTO  true  "Role"
+// @Success      200   {object}  dtos.RoleDTO  "Updated role"
+// @Failure      400   {object}  dtos.ErrorResponse  "Invalid data"
+// @Failure      403   {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404   {object}  dtos.ErrorResponse  "Role not found"
+// @Failure      409   {object}  dtos.ErrorResponse  "Role name already in use"
+// @Failure      500   {object}  dtos.ErrorResponse  "Error updating role"
+// @Security     ApiKeyAuth
+// @Router       /roles/{id} [put]
+func (rc *RoleController) UpdateRole(c *gin.Context) {
+	permissionId := config.PERMISSION_UPDATE_ROLE
+	if !rc.Auth.CheckPermission(c, permissionId) {
+		_ = rc.Log.RegisterLog(c, "Access denied for UpdateRole")
+		return
+	}
+
+	id, ok := rc.parseRoleID(c)
+	if !ok {
+		return
+	}
+
+	var dto dtos.UpdateRoleDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = rc.Log.RegisterLog(c, "Invalid role data: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	before, err := rc.Service.GetRoleByID(c.Request.Context(), id)
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Error retrieving role with ID "+c.Param("id")+": "+err.Error())
+		rc.respondError(c, err, "Error updating role")
+		return
+	}
+
+	role, err := rc.Service.UpdateRole(c.Request.Context(), id, dto)
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Error updating role with ID "+c.Param("id")+": "+err.Error())
+		rc.respondError(c, err, "Error updating role")
+		return
+	}
+
+	rc.recordRoleChange(c, id, services.AUDIT_ACTION_UPDATE, before, role, "role "+c.Param("id")+" renamed from "+before.Name+" to "+role.Name)
+
+	_ = rc.Log.RegisterLog(c, "Successfully updated role with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, newRoleDTO(role))
+}
+
+// UpdateRolePermissions godoc
+// @Summary      Set the permissions of a role
+// @Description  Replaces the role's permissions with the given list in one transaction: missing IDs are assigned and the ones left out are removed. If any permission ID does not exist nothing changes. Users get the new permissions on their next request.
+// @Tags         roles
+// @Accept       json
+// @Produce      json
+// @Param        id           path      int                            true  "Role ID"
+// @Param        permissions  body      dtos.UpdateRolePermissionsDTO  true  "Permission IDs"
+// @Success      200          {object}  dtos.RoleDTO  "Role with its new permissions"
+// @Failure      400          {object}  dtos.ErrorResponse  "Invalid data or unknown permission IDs"
+// @Failure      403          {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404          {object}  dtos.ErrorResponse  "Role not found"
+// @Failure      500          {object}  dtos.ErrorResponse  "Error updating role permissions"
+// @Security     ApiKeyAuth
+// @Router       /roles/{id}/permissions [put]
+func (rc *RoleController) UpdateRolePermissions(c *gin.Context) {
+	permissionId := config.PERMISSION_UPDATE_ROLE_PERMISSIONS
+	if !rc.Auth.CheckPermission(c, permissionId) {
+		_ = rc.Log.RegisterLog(c, "Access denied for UpdateRolePermissions")
+		return
+	}
+
+	id, ok := rc.parseRoleID(c)
+	if !ok {
+		return
+	}
+
+	var dto dtos.UpdateRolePermissionsDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = rc.Log.RegisterLog(c, "Invalid role permissions data: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	before, err := rc.Service.GetRoleByID(c.Request.Context(), id)
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Error retrieving role with ID "+c.Param("id")+": "+err.Error())
+		rc.respondError(c, err, "Error updating role permissions")
+		return
+	}
+
+	role, err := rc.Service.UpdateRolePermissions(c.Request.Context(), id, dto.Permissions)
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Error updating permissions of role with ID "+c.Param("id")+": "+err.Error())
+		rc.respondError(c, err, "Error updating role permissions")
+		return
+	}
+
+	rc.recordRoleChange(c, id, services.AUDIT_ACTION_UPDATE, before, role,
+		fmt.Sprintf("permissions of role %s changed from [%s] to [%s]", c.Param("id"), strings.Join(newRoleDTO(before).Permissions, ","), strings.Join(newRoleDTO(role).Permissions, ",")))
+
+	_ = rc.Log.RegisterLog(c, fmt.Sprintf("Successfully set %d permissions on role with ID: %s", len(role.Permissions), c.Param("id")))
+	c.JSON(http.StatusOK, newRoleDTO(role))
+}
+
+// DeleteRole godoc
+// @Summary      Delete a role
+// @Description  Deletes a role that no user type has; remove it from the user types first.
+// @Tags         roles
+// @Produce      json
+// @Param        id  path  int  true  "Role ID"
+// @Success      200  {object}  models.MessageResponse  "Role deleted"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid role ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Role not found"
+// @Failure      409  {object}  dtos.ErrorResponse  "Role in use"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error deleting role"
+// @Security     ApiKeyAuth
+// @Router       /roles/{id} [delete]
+func (rc *RoleController) DeleteRole(c *gin.Context) {
+	permissionId := config.PERMISSION_DELETE_ROLE
+	if !rc.Auth.CheckPermission(c, permissionId) {
+		_ = rc.Log.RegisterLog(c, "Access denied for DeleteRole")
+		return
+	}
+
+	id, ok := rc.parseRoleID(c)
+	if !ok {
+		return
+	}
+
+	before, err := rc.Service.GetRoleByID(c.Request.Context(), id)
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Error retrieving role with ID "+c.Param("id")+": "+err.Error())
+		rc.respondError(c, err, "Error deleting role")
+		return
+	}
+
+	if err := rc.Service.DeleteRole(c.Request.Context(), id); err != nil {
+		_ = rc.Log.RegisterLog(c, "Error deleting role with ID "+c.Param("id")+": "+err.Error())
+		rc.respondError(c, err, "Error deleting role")
+		return
+	}
+
+	rc.recordRoleChange(c, id, services.AUDIT_ACTION_DELETE, before, nil, "role "+before.Name+" deleted")
+
+	_ = rc.Log.RegisterLog(c, "Successfully deleted role with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, gin.H{"message": "Role deleted successfully"})
+}
+
+// recordRoleChange deja el cambio del rol en la auditoría y en el registro de seguridad, a nombre de
+// quien lo hizo.
+func (rc *RoleController) recordRoleChange(c *gin.Context, id uint, action string, before, after interface{}, detail string) {
+	roleID := strconv.FormatUint(uint64(id), 10)
+	if err := rc.Audit.RecordChange(c, services.AUDIT_ENTITY_ROLE, roleID, action, before, after); err != nil {
+		_ = rc.Log.RegisterLog(c, "Error recording audit trail for role with ID "+roleID+": "+err.Error())
+	}
+	_ = rc.Log.RegisterSecurityEvent(c, services.SECURITY_EVENT_ROLE_CHANGE, utilities.CurrentUser(c), detail+" by "+utilities.CurrentUser(c))
+}
+
+func (rc *RoleController) parseRoleID(c *gin.Context) (uint, bool) {
+	idParam := c.Param("id")
+
+	var id uint
+	if _, err := fmt.Sscanf(idParam, "%d", &id); err != nil {
+		_ = rc.Log.RegisterLog(c, "Invalid role ID format: "+idParam)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid role ID")
+		return 0, false
+	}
+	return id, true
+}
+
+func (rc *RoleController) respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utilities.RespondError(c, http.StatusNotFound, "Role not found")
+	case errors.Is(err, services.ErrInvalidRole), errors.Is(err, services.ErrPermissionMissing):
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrRoleNameTaken), errors.Is(err, services.ErrRoleInUse):
+		utilities.RespondError(c, http.StatusConflict, err.Error())
+	default:
+		utilities.RespondError(c, http.StatusInternalServerError, message)
+	}
+}
+
+// newRoleDTO arma el RoleDTO de un rol leído con sus permisos.
+func newRoleDTO(role *models.Role) dtos.RoleDTO {
+	roleDTO := dtos.RoleDTO{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: make([]string, len(role.Permissions)),
+	}
+	for i, permission := range role.Permissions {
+		roleDTO.Permissions[i] = fmt.Sprintf("%d", permission.ID)
+	}
+	return roleDTO
 }
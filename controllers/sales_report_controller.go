@@ -1,7 +1,10 @@
 package controllers
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strconv"
 	"time"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
@@ -29,45 +32,57 @@ func NewSalesReportController(service *services.SalesReportService, auth *utilit
 // @Produce      json
 // @Param        startDate  query  string  true  "Start Date (RFC3339 format)"
 // @Param        endDate    query  string  true  "End Date (RFC3339 format)"
+// @Param        format     query  string  false  "Output format: json, csv or xlsx (also negotiated through the Accept header)"
 // @Success      200  {array}  dtos.SalesReportInvoiceDTO  "List of invoices between the given dates"
-// @Failure      400  {object}  models.ErrorResponse  "Invalid date format"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500  {object}  models.ErrorResponse  "Error fetching invoices"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid date format"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error fetching invoices"
 // @Security     ApiKeyAuth
 // @Router       /sales-report/invoices [get]
 func (src *SalesReportController) GetInvoicesBetweenDates(c *gin.Context) {
 	startDateStr := c.Query("startDate")
 	endDateStr := c.Query("endDate")
 
-	if src.Log.RegisterLog(c, "Request to fetch invoices between "+startDateStr+" and "+endDateStr) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_VIEW_SALES_REPORT
 	if !src.Auth.CheckPermission(c, permissionId) {
 		_ = src.Log.RegisterLog(c, "Access denied for GetInvoicesBetweenDates")
 		return
 	}
 
+	format, err := utilities.ReportFormat(c)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Invalid report format: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	startDate, err := time.Parse(time.RFC3339, startDateStr)
 	if err != nil {
 		_ = src.Log.RegisterLog(c, "Invalid startDate: "+startDateStr)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid startDate format. Use RFC3339 format: yyyy-mm-ddTHH:MM:SSZ"})
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid startDate format. Use RFC3339 format: yyyy-mm-ddTHH:MM:SSZ")
 		return
 	}
 
 	endDate, err := time.Parse(time.RFC3339, endDateStr)
 	if err != nil {
 		_ = src.Log.RegisterLog(c, "Invalid endDate: "+endDateStr)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid endDate format. Use RFC3339 format: yyyy-mm-ddTHH:MM:SSZ"})
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid endDate format. Use RFC3339 format: yyyy-mm-ddTHH:MM:SSZ")
+		return
+	}
+
+	if format != utilities.ReportFormatJSON {
+		if err := utilities.WriteReportTable(c, format, invoicesTable(c.Request.Context(), src.Service, startDate, endDate)); err != nil {
+			_ = src.Log.RegisterLog(c, "Error exporting invoices: "+err.Error())
+			return
+		}
+		_ = src.Log.RegisterLog(c, "Successfully exported invoices between "+startDateStr+" and "+endDateStr)
 		return
 	}
 
-	invoices, err := src.Service.GetInvoicesBetweenDates(startDate, endDate)
+	invoices, err := src.Service.GetInvoicesBetweenDates(c.Request.Context(), startDate, endDate)
 	if err != nil {
 		_ = src.Log.RegisterLog(c, "Error fetching invoices: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching invoices"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error fetching invoices")
 		return
 	}
 
@@ -82,6 +97,391 @@ func (src *SalesReportController) GetInvoicesBetweenDates(c *gin.Context) {
 	c.JSON(http.StatusOK, invoiceDTOs)
 }
 
+// GetSalesSummary godoc
+// @Summary      Sales report by date range
+// @Description  Returns invoice counts, subtotal, tax, discount and total figures between two dates, grouped by day, week or month.
+// @Tags         sales-report
+// @Produce      json
+// @Param        from     query  string  true   "Start date (YYYY-MM-DD)"
+// @Param        to       query  string  true   "End date, inclusive (YYYY-MM-DD)"
+// @Param        groupBy  query  string  false  "Grouping: day, week or month (default day)"
+// @Param        format   query  string  false  "Output format: json, csv or xlsx (also negotiated through the Accept header)"
+// @Success      200  {object}  dtos.SalesSummaryReportDTO  "Sales figures per period"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid dates or grouping"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error building the sales report"
+// @Security     ApiKeyAuth
+// @Router       /reports/sales [get]
+func (src *SalesReportController) GetSalesSummary(c *gin.Context) {
+	fromStr := c.Query("from")
+	toStr := c.Query("to")
+	groupBy := c.DefaultQuery("groupBy", "day")
+
+	permissionId := config.PERMISSION_VIEW_SALES_SUMMARY_REPORT
+	if !src.Auth.CheckPermission(c, permissionId) {
+		_ = src.Log.RegisterLog(c, "Access denied for GetSalesSummary")
+		return
+	}
+
+	format, err := utilities.ReportFormat(c)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Invalid report format: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	from, err := time.Parse("2006-01-02", fromStr)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Invalid from date: "+fromStr)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid 'from' date format. Use YYYY-MM-DD")
+		return
+	}
+
+	to, err := time.Parse("2006-01-02", toStr)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Invalid to date: "+toStr)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid 'to' date format. Use YYYY-MM-DD")
+		return
+	}
+	// La fecha final es inclusiva
+	to = to.Add(24*time.Hour - time.Nanosecond)
+
+	report, err := src.Service.GetSalesSummary(c.Request.Context(), from, to, groupBy)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Error building sales report: "+err.Error())
+		if errors.Is(err, services.ErrInvalidReportParams) {
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error building sales report")
+		return
+	}
+
+	if format != utilities.ReportFormatJSON {
+		if err := utilities.WriteReportTable(c, format, salesSummaryTable(report)); err != nil {
+			_ = src.Log.RegisterLog(c, "Error exporting sales report: "+err.Error())
+			return
+		}
+		_ = src.Log.RegisterLog(c, "Successfully exported sales report between "+fromStr+" and "+toStr)
+		return
+	}
+
+	_ = src.Log.RegisterLog(c, "Successfully built sales report between "+fromStr+" and "+toStr)
+	c.JSON(http.StatusOK, report)
+}
+
+// GetMarginReport godoc
+// @Summary      Profit margin report
+// @Description  Combines the selling price in force at invoice time, the purchase price and the additional expenses of each invoice line
+// @Description  to show revenue, cost and gross margin grouped by item, category or period. An item's additional expenses are spread
+// @Description  over all the units purchased of it (opening stock, stock at creation and restock receipts).
+// @Tags         sales-report
+// @Produce      json
+// @Param        from     query  string  true   "Start date (YYYY-MM-DD)"
+// @Param        to       query  string  true   "End date, inclusive (YYYY-MM-DD)"
+// @Param        groupBy  query  string  false  "Grouping: item, category, day, week or month (default item)"
+// @Param        format   query  string  false  "Output format: json, csv or xlsx (also negotiated through the Accept header)"
+// @Success      200  {object}  dtos.MarginReportDTO  "Margin figures"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid dates or grouping"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error building the margin report"
+// @Security     ApiKeyAuth
+// @Router       /reports/margins [get]
+func (src *SalesReportController) GetMarginReport(c *gin.Context) {
+	fromStr := c.Query("from")
+	toStr := c.Query("to")
+	groupBy := c.DefaultQuery("groupBy", "item")
+
+	permissionId := config.PERMISSION_VIEW_MARGIN_REPORT
+	if !src.Auth.CheckPermission(c, permissionId) {
+		_ = src.Log.RegisterLog(c, "Access denied for GetMarginReport")
+		return
+	}
+
+	format, err := utilities.ReportFormat(c)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Invalid report format: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	from, err := time.Parse("2006-01-02", fromStr)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Invalid from date: "+fromStr)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid 'from' date format. Use YYYY-MM-DD")
+		return
+	}
+
+	to, err := time.Parse("2006-01-02", toStr)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Invalid to date: "+toStr)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid 'to' date format. Use YYYY-MM-DD")
+		return
+	}
+	to = to.Add(24*time.Hour - time.Nanosecond)
+
+	report, err := src.Service.GetMarginReport(c.Request.Context(), from, to, groupBy)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Error building margin report: "+err.Error())
+		if errors.Is(err, services.ErrInvalidReportParams) {
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error building margin report")
+		return
+	}
+
+	if format != utilities.ReportFormatJSON {
+		if err := utilities.WriteReportTable(c, format, marginTable(report)); err != nil {
+			_ = src.Log.RegisterLog(c, "Error exporting margin report: "+err.Error())
+			return
+		}
+		_ = src.Log.RegisterLog(c, "Successfully exported margin report between "+fromStr+" and "+toStr)
+		return
+	}
+
+	_ = src.Log.RegisterLog(c, "Successfully built margin report between "+fromStr+" and "+toStr)
+	c.JSON(http.StatusOK, report)
+}
+
+// GetDiscountUsageReport godoc
+// @Summary      Discount usage report
+// @Description  Shows, for every discount type, how many invoices applied it in the period, the subtotal of those invoices and the amount discounted.
+// @Tags         sales-report
+// @Produce      json
+// @Param        from     query  string  true   "Start date (YYYY-MM-DD)"
+// @Param        to       query  string  true   "End date, inclusive (YYYY-MM-DD)"
+// @Param        format   query  string  false  "Output format: json, csv or xlsx (also negotiated through the Accept header)"
+// @Success      200  {object}  dtos.DiscountUsageReportDTO  "Discount usage figures"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid dates"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error building the discount usage report"
+// @Security     ApiKeyAuth
+// @Router       /reports/discounts [get]
+func (src *SalesReportController) GetDiscountUsageReport(c *gin.Context) {
+	fromStr := c.Query("from")
+	toStr := c.Query("to")
+
+	permissionId := config.PERMISSION_VIEW_DISCOUNT_USAGE_REPORT
+	if !src.Auth.CheckPermission(c, permissionId) {
+		_ = src.Log.RegisterLog(c, "Access denied for GetDiscountUsageReport")
+		return
+	}
+
+	format, err := utilities.ReportFormat(c)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Invalid report format: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	from, err := time.Parse("2006-01-02", fromStr)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Invalid from date: "+fromStr)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid 'from' date format. Use YYYY-MM-DD")
+		return
+	}
+
+	to, err := time.Parse("2006-01-02", toStr)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Invalid to date: "+toStr)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid 'to' date format. Use YYYY-MM-DD")
+		return
+	}
+	to = to.Add(24*time.Hour - time.Nanosecond)
+
+	report, err := src.Service.GetDiscountUsageReport(c.Request.Context(), from, to)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Error building discount usage report: "+err.Error())
+		if errors.Is(err, services.ErrInvalidReportParams) {
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error building discount usage report")
+		return
+	}
+
+	if format != utilities.ReportFormatJSON {
+		if err := utilities.WriteReportTable(c, format, discountUsageTable(report)); err != nil {
+			_ = src.Log.RegisterLog(c, "Error exporting discount usage report: "+err.Error())
+			return
+		}
+		_ = src.Log.RegisterLog(c, "Successfully exported discount usage report between "+fromStr+" and "+toStr)
+		return
+	}
+
+	_ = src.Log.RegisterLog(c, "Successfully built discount usage report between "+fromStr+" and "+toStr)
+	c.JSON(http.StatusOK, report)
+}
+
+// GetTopItemsReport godoc
+// @Summary      Top selling items
+// @Description  Ranks the items sold in the period by units or by revenue, at the price in force at invoice time. Cancelled invoices are left out.
+// @Tags         sales-report
+// @Produce      json
+// @Param        from     query  string  true   "Start date (YYYY-MM-DD)"
+// @Param        to       query  string  true   "End date, inclusive (YYYY-MM-DD)"
+// @Param        orderBy  query  string  false  "Ranking: units or revenue (default units)"
+// @Param        limit    query  int     false  "Number of items, up to 100 (default 10)"
+// @Param        format   query  string  false  "Output format: json, csv or xlsx (also negotiated through the Accept header)"
+// @Success      200  {object}  dtos.TopItemsReportDTO  "Top items"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid dates, ranking or limit"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error building the top items report"
+// @Security     ApiKeyAuth
+// @Router       /reports/top-items [get]
+func (src *SalesReportController) GetTopItemsReport(c *gin.Context) {
+	fromStr := c.Query("from")
+	toStr := c.Query("to")
+	orderBy := c.DefaultQuery("orderBy", "units")
+
+	permissionId := config.PERMISSION_VIEW_TOP_ITEMS_REPORT
+	if !src.Auth.CheckPermission(c, permissionId) {
+		_ = src.Log.RegisterLog(c, "Access denied for GetTopItemsReport")
+		return
+	}
+
+	format, err := utilities.ReportFormat(c)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Invalid report format: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	from, err := time.Parse("2006-01-02", fromStr)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Invalid from date: "+fromStr)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid 'from' date format. Use YYYY-MM-DD")
+		return
+	}
+
+	to, err := time.Parse("2006-01-02", toStr)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Invalid to date: "+toStr)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid 'to' date format. Use YYYY-MM-DD")
+		return
+	}
+	to = to.Add(24*time.Hour - time.Nanosecond)
+
+	limit, ok := src.parseRankingLimit(c)
+	if !ok {
+		return
+	}
+
+	report, err := src.Service.GetTopItemsReport(c.Request.Context(), from, to, orderBy, limit)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Error building top items report: "+err.Error())
+		if errors.Is(err, services.ErrInvalidReportParams) {
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error building top items report")
+		return
+	}
+
+	if format != utilities.ReportFormatJSON {
+		if err := utilities.WriteReportTable(c, format, topItemsTable(report)); err != nil {
+			_ = src.Log.RegisterLog(c, "Error exporting top items report: "+err.Error())
+			return
+		}
+		_ = src.Log.RegisterLog(c, "Successfully exported top items report between "+fromStr+" and "+toStr)
+		return
+	}
+
+	_ = src.Log.RegisterLog(c, "Successfully built top items report between "+fromStr+" and "+toStr)
+	c.JSON(http.StatusOK, report)
+}
+
+// GetRevenueByCustomerReport godoc
+// @Summary      Revenue by customer
+// @Description  Ranks the customers by the total invoiced in the period, with their invoice count, subtotal and last invoice date. Cancelled invoices are left out; deleted customers are included.
+// @Tags         sales-report
+// @Produce      json
+// @Param        from    query  string  true   "Start date (YYYY-MM-DD)"
+// @Param        to      query  string  true   "End date, inclusive (YYYY-MM-DD)"
+// @Param        limit   query  int     false  "Number of customers, up to 100 (default 10)"
+// @Param        format  query  string  false  "Output format: json, csv or xlsx (also negotiated through the Accept header)"
+// @Success      200  {object}  dtos.RevenueByCustomerReportDTO  "Revenue per customer"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid dates or limit"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error building the revenue by customer report"
+// @Security     ApiKeyAuth
+// @Router       /reports/revenue-by-customer [get]
+func (src *SalesReportController) GetRevenueByCustomerReport(c *gin.Context) {
+	fromStr := c.Query("from")
+	toStr := c.Query("to")
+
+	permissionId := config.PERMISSION_VIEW_REVENUE_BY_CUSTOMER_REPORT
+	if !src.Auth.CheckPermission(c, permissionId) {
+		_ = src.Log.RegisterLog(c, "Access denied for GetRevenueByCustomerReport")
+		return
+	}
+
+	format, err := utilities.ReportFormat(c)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Invalid report format: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	from, err := time.Parse("2006-01-02", fromStr)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Invalid from date: "+fromStr)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid 'from' date format. Use YYYY-MM-DD")
+		return
+	}
+
+	to, err := time.Parse("2006-01-02", toStr)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Invalid to date: "+toStr)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid 'to' date format. Use YYYY-MM-DD")
+		return
+	}
+	to = to.Add(24*time.Hour - time.Nanosecond)
+
+	limit, ok := src.parseRankingLimit(c)
+	if !ok {
+		return
+	}
+
+	report, err := src.Service.GetRevenueByCustomerReport(c.Request.Context(), from, to, limit)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Error building revenue by customer report: "+err.Error())
+		if errors.Is(err, services.ErrInvalidReportParams) {
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error building revenue by customer report")
+		return
+	}
+
+	if format != utilities.ReportFormatJSON {
+		if err := utilities.WriteReportTable(c, format, revenueByCustomerTable(report)); err != nil {
+			_ = src.Log.RegisterLog(c, "Error exporting revenue by customer report: "+err.Error())
+			return
+		}
+		_ = src.Log.RegisterLog(c, "Successfully exported revenue by customer report between "+fromStr+" and "+toStr)
+		return
+	}
+
+	_ = src.Log.RegisterLog(c, "Successfully built revenue by customer report between "+fromStr+" and "+toStr)
+	c.JSON(http.StatusOK, report)
+}
+
+func (src *SalesReportController) parseRankingLimit(c *gin.Context) (int, bool) {
+	limitStr := c.Query("limit")
+	if limitStr == "" {
+		return config.REPORT_RANKING_DEFAULT_LIMIT, true
+	}
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Invalid limit: "+limitStr)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid 'limit'")
+		return 0, false
+	}
+	return limit, true
+}
+
 // Función para mapear un Invoice a SalesReportInvoiceDTO
 func mapInvoiceToSalesReportDTO(invoice models.Invoice) dtos.SalesReportInvoiceDTO {
 	// Convertir los items
@@ -99,8 +499,100 @@ func mapInvoiceToSalesReportDTO(invoice models.Invoice) dtos.SalesReportInvoiceD
 		DateTime:  invoice.DateTime,
 		Total:     invoice.Total,
 		Subtotal:  invoice.Subtotal,
+		Currency:  invoice.Currency,
 		Items:     billingItems,
 		Discounts: invoice.Discounts,
 		Taxes:     invoice.Taxes,
 	}
 }
+
+func invoicesTable(ctx context.Context, service *services.SalesReportService, startDate, endDate time.Time) utilities.ReportTable {
+	return utilities.ReportTable{
+		Name:   "invoices",
+		Header: []string{"id", "date_time", "customer_id", "items", "subtotal", "total"},
+		Rows: func(write func(row []interface{}) error) error {
+			return service.StreamInvoicesBetweenDates(ctx, startDate, endDate, func(invoice models.Invoice) error {
+				units := 0
+				for _, item := range invoice.Items {
+					units += item.Amount
+				}
+				return write([]interface{}{invoice.ID, invoice.DateTime, invoice.CustomerID, units, invoice.Subtotal, invoice.Total})
+			})
+		},
+	}
+}
+
+func salesSummaryTable(report *dtos.SalesSummaryReportDTO) utilities.ReportTable {
+	return utilities.ReportTable{
+		Name:   "sales-" + report.GroupBy,
+		Header: []string{"period", "invoice_count", "subtotal", "tax", "discount", "total"},
+		Rows: func(write func(row []interface{}) error) error {
+			for _, p := range report.Periods {
+				if err := write([]interface{}{p.Period.Format("2006-01-02"), p.InvoiceCount, p.Subtotal, p.Tax, p.Discount, p.Total}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func marginTable(report *dtos.MarginReportDTO) utilities.ReportTable {
+	return utilities.ReportTable{
+		Name:   "margins-" + report.GroupBy,
+		Header: []string{"key", "label", "units_sold", "revenue", "cost", "margin", "margin_pct"},
+		Rows: func(write func(row []interface{}) error) error {
+			for _, r := range append(report.Rows, report.Totals) {
+				if err := write([]interface{}{r.Key, r.Label, r.UnitsSold, r.Revenue, r.Cost, r.Margin, r.MarginPct}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func discountUsageTable(report *dtos.DiscountUsageReportDTO) utilities.ReportTable {
+	return utilities.ReportTable{
+		Name:   "discount-usage",
+		Header: []string{"discount_type_id", "discount_type_name", "is_percentage", "value", "invoice_count", "subtotal", "discounted"},
+		Rows: func(write func(row []interface{}) error) error {
+			for _, d := range report.DiscountTypes {
+				if err := write([]interface{}{d.DiscountTypeID, d.DiscountTypeName, d.IsPercentage, d.Value, d.InvoiceCount, d.Subtotal, d.Discounted}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func topItemsTable(report *dtos.TopItemsReportDTO) utilities.ReportTable {
+	return utilities.ReportTable{
+		Name:   "top-items-" + report.OrderBy,
+		Header: []string{"item_id", "item_name", "units_sold", "invoice_count", "revenue"},
+		Rows: func(write func(row []interface{}) error) error {
+			for _, i := range report.Items {
+				if err := write([]interface{}{i.ItemID, i.ItemName, i.UnitsSold, i.InvoiceCount, i.Revenue}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func revenueByCustomerTable(report *dtos.RevenueByCustomerReportDTO) utilities.ReportTable {
+	return utilities.ReportTable{
+		Name:   "revenue-by-customer",
+		Header: []string{"customer_id", "customer_name", "document_number", "invoice_count", "subtotal", "total", "last_invoice_at"},
+		Rows: func(write func(row []interface{}) error) error {
+			for _, r := range report.Customers {
+				if err := write([]interface{}{r.CustomerID, r.CustomerName, r.DocumentNumber, r.InvoiceCount, r.Subtotal, r.Total, r.LastInvoiceAt}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"net/http"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SandboxController struct {
+	Service *services.SandboxService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewSandboxController(service *services.SandboxService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *SandboxController {
+	return &SandboxController{Service: service, Auth: auth, Log: log}
+}
+
+// ResetSandbox godoc
+// @Summary      Reset the sandbox data
+// @Description  Only available when the server runs with SANDBOX_MODE. Empties every table of the sandbox schema and loads the base catalogs, the demo administrator and the demo customers, items and appointments again. Production data in other schemas is never touched.
+// @Tags         sandbox
+// @Produce      json
+// @Success      200  {object}  models.MessageResponse  "Sandbox reset"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error resetting the sandbox"
+// @Security     ApiKeyAuth
+// @Router       /sandbox/reset [post]
+func (sc *SandboxController) ResetSandbox(c *gin.Context) {
+	permissionId := config.PERMISSION_RESET_SANDBOX
+	if !sc.Auth.CheckPermission(c, permissionId) {
+		_ = sc.Log.RegisterLog(c, "Access denied for ResetSandbox")
+		return
+	}
+
+	if err := sc.Service.Reset(c.Request.Context()); err != nil {
+		_ = sc.Log.RegisterLog(c, "Error resetting the sandbox: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error resetting the sandbox")
+		return
+	}
+
+	_ = sc.Log.RegisterLog(c, "Sandbox reset")
+	c.JSON(http.StatusOK, gin.H{"message": "Sandbox reset successfully"})
+}
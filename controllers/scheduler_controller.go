@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"net/http"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SchedulerController struct {
+	Service *services.SchedulerService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewSchedulerController(service *services.SchedulerService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *SchedulerController {
+	return &SchedulerController{Service: service, Auth: auth, Log: log}
+}
+
+// GetScheduledJobs godoc
+// @Summary      List scheduled jobs
+// @Description  Returns every scheduled job with its cron expression, next run and the outcome of its last run.
+// @Tags         scheduler
+// @Produce      json
+// @Success      200  {array}   models.ScheduledJob  "Scheduled jobs"
+// @Failure      403  {object}  dtos.ErrorResponse   "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse   "Error retrieving scheduled jobs"
+// @Security     ApiKeyAuth
+// @Router       /scheduler/jobs [get]
+func (sc *SchedulerController) GetScheduledJobs(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_SCHEDULED_JOBS
+	if !sc.Auth.CheckPermission(c, permissionId) {
+		_ = sc.Log.RegisterLog(c, "Access denied for GetScheduledJobs")
+		return
+	}
+
+	jobs, err := sc.Service.GetJobs(c.Request.Context())
+	if err != nil {
+		_ = sc.Log.RegisterLog(c, "Error retrieving scheduled jobs: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving scheduled jobs")
+		return
+	}
+
+	_ = sc.Log.RegisterLog(c, "Successfully retrieved scheduled jobs")
+	c.JSON(http.StatusOK, jobs)
+}
@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SecurityEventController struct {
+	Service *services.SecurityEventService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewSecurityEventController(service *services.SecurityEventService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *SecurityEventController {
+	return &SecurityEventController{Service: service, Auth: auth, Log: log}
+}
+
+// GetSecurityEvents godoc
+// @Summary      Search the security event log
+// @Description  Returns a page of security events (logins, permission denials, role and password changes), newest first.
+// @Tags         logs
+// @Produce      json
+// @Param        type      query  string  false  "Event type: login_success, login_failure, permission_denied, role_change, password_change or token_reuse"
+// @Param        user      query  string  false  "User email (partial match)"
+// @Param        from      query  string  false  "Start date (YYYY-MM-DD or RFC3339)"
+// @Param        to        query  string  false  "End date (YYYY-MM-DD, inclusive, or RFC3339)"
+// @Param        page      query  int     false  "Page number (default 1)"
+// @Param        pageSize  query  int     false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[models.SecurityEvent]  "Page of security events"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid filter"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error searching security events"
+// @Security     ApiKeyAuth
+// @Router       /security-events [get]
+func (sec *SecurityEventController) GetSecurityEvents(c *gin.Context) {
+	permissionId := config.PERMISSION_SEARCH_SECURITY_EVENTS
+	if !sec.Auth.CheckPermission(c, permissionId) {
+		_ = sec.Log.RegisterLog(c, "Access denied for GetSecurityEvents")
+		return
+	}
+
+	filter := dtos.SecurityEventFilterDTO{
+		EventType: c.Query("type"),
+		User:      c.Query("user"),
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = sec.Log.RegisterLog(c, "Invalid pagination: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	filter.PaginationDTO = pagination
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, _, err := parseLogDate(fromStr)
+		if err != nil {
+			_ = sec.Log.RegisterLog(c, "Invalid from date: "+fromStr)
+			utilities.RespondError(c, http.StatusBadRequest, "Invalid 'from' date. Use YYYY-MM-DD or RFC3339")
+			return
+		}
+		filter.From = &from
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		to, dateOnly, err := parseLogDate(toStr)
+		if err != nil {
+			_ = sec.Log.RegisterLog(c, "Invalid to date: "+toStr)
+			utilities.RespondError(c, http.StatusBadRequest, "Invalid 'to' date. Use YYYY-MM-DD or RFC3339")
+			return
+		}
+		if dateOnly {
+			to = to.Add(24*time.Hour - time.Nanosecond)
+		}
+		filter.To = &to
+	}
+
+	page, err := sec.Service.SearchSecurityEvents(c.Request.Context(), filter)
+	if err != nil {
+		_ = sec.Log.RegisterLog(c, "Error searching security events: "+err.Error())
+		if errors.Is(err, services.ErrInvalidLogFilter) {
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error searching security events")
+		return
+	}
+
+	_ = sec.Log.RegisterLog(c, "Successfully searched security events")
+	c.JSON(http.StatusOK, page)
+}
+
+// VerifySecurityEvents godoc
+// @Summary      Verify the security event chain
+// @Description  Recomputes the hash chain of the security log and reports the first event that was altered or removed, if any.
+// @Tags         logs
+// @Produce      json
+// @Success      200  {object}  dtos.SecurityEventChainDTO  "Verification result"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error verifying security events"
+// @Security     ApiKeyAuth
+// @Router       /security-events/verify [get]
+func (sec *SecurityEventController) VerifySecurityEvents(c *gin.Context) {
+	permissionId := config.PERMISSION_VERIFY_SECURITY_EVENTS
+	if !sec.Auth.CheckPermission(c, permissionId) {
+		_ = sec.Log.RegisterLog(c, "Access denied for VerifySecurityEvents")
+		return
+	}
+
+	result, err := sec.Service.VerifySecurityEventChain(c.Request.Context())
+	if err != nil {
+		_ = sec.Log.RegisterLog(c, "Error verifying security event chain: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error verifying security events")
+		return
+	}
+
+	_ = sec.Log.RegisterLog(c, "Security event chain verified, valid: "+strconv.FormatBool(result.Valid))
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type StockController struct {
+	Service *services.StockService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewStockController(service *services.StockService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *StockController {
+	return &StockController{Service: service, Auth: auth, Log: log}
+}
+
+// GetStockMovements godoc
+// @Summary      List stock movements
+// @Description  Returns a page of the stock ledger, newest first. Every change to an item's stock (invoices, external sales, purchase orders, manual adjustments) is a movement with its delta, the resulting stock, the reference document, the user and the request ID.
+// @Tags         stock
+// @Produce      json
+// @Param        itemId    query  int     false  "Item ID"
+// @Param        reason    query  string  false  "Reason (opening, item_created, adjustment, invoice, external_sale, purchase_order, purchase_order_return)"
+// @Param        from      query  string  false  "Start date (YYYY-MM-DD or RFC3339)"
+// @Param        to        query  string  false  "End date (YYYY-MM-DD, inclusive, or RFC3339)"
+// @Param        page      query  int     false  "Page number (default 1)"
+// @Param        pageSize  query  int     false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[models.StockMovement]  "Page of stock movements"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid filter"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving stock movements"
+// @Security     ApiKeyAuth
+// @Router       /stock-movements [get]
+func (sc *StockController) GetStockMovements(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_STOCK_MOVEMENTS
+	if !sc.Auth.CheckPermission(c, permissionId) {
+		_ = sc.Log.RegisterLog(c, "Access denied for GetStockMovements")
+		return
+	}
+
+	filter, ok := sc.parseStockMovementFilter(c)
+	if !ok {
+		return
+	}
+
+	movements, total, err := sc.Service.GetStockMovements(c.Request.Context(), filter)
+	if err != nil {
+		_ = sc.Log.RegisterLog(c, "Error retrieving stock movements: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving stock movements")
+		return
+	}
+
+	_ = sc.Log.RegisterLog(c, "Successfully retrieved stock movements")
+	c.JSON(http.StatusOK, dtos.NewPageDTO(movements, filter.PaginationDTO, total))
+}
+
+// GetStockBalance godoc
+// @Summary      Get an item's stock from the ledger
+// @Description  Returns the stock of the item computed as the sum of its movements, next to the stock stored in the item.
+// @Tags         stock
+// @Produce      json
+// @Param        itemId  path  int  true  "Item ID"
+// @Success      200  {object}  dtos.StockBalanceDTO  "Ledger and item stock"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid item ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Item not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error computing stock"
+// @Security     ApiKeyAuth
+// @Router       /stock-movements/balance/{itemId} [get]
+func (sc *StockController) GetStockBalance(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_STOCK_MOVEMENTS
+	if !sc.Auth.CheckPermission(c, permissionId) {
+		_ = sc.Log.RegisterLog(c, "Access denied for GetStockBalance")
+		return
+	}
+
+	idStr := c.Param("itemId")
+	itemID, err := strconv.Atoi(idStr)
+	if err != nil {
+		_ = sc.Log.RegisterLog(c, "Invalid item ID: "+idStr)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid item ID")
+		return
+	}
+
+	balance, err := sc.Service.GetStockBalance(c.Request.Context(), itemID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = sc.Log.RegisterLog(c, "Item not found with ID: "+idStr)
+			utilities.RespondError(c, http.StatusNotFound, "Item not found")
+			return
+		}
+		_ = sc.Log.RegisterLog(c, "Error computing stock for item "+idStr+": "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error computing stock")
+		return
+	}
+
+	_ = sc.Log.RegisterLog(c, "Successfully computed stock for item with ID: "+idStr)
+	c.JSON(http.StatusOK, balance)
+}
+
+// GetStockDiscrepancies godoc
+// @Summary      List items whose stock does not match the ledger
+// @Description  Returns the items whose stored stock differs from the sum of their movements, for example because the items table was edited by hand.
+// @Tags         stock
+// @Produce      json
+// @Success      200  {array}   dtos.StockBalanceDTO  "Items with a discrepancy"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error checking stock"
+// @Security     ApiKeyAuth
+// @Router       /stock-movements/discrepancies [get]
+func (sc *StockController) GetStockDiscrepancies(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_STOCK_MOVEMENTS
+	if !sc.Auth.CheckPermission(c, permissionId) {
+		_ = sc.Log.RegisterLog(c, "Access denied for GetStockDiscrepancies")
+		return
+	}
+
+	discrepancies, err := sc.Service.GetStockDiscrepancies(c.Request.Context())
+	if err != nil {
+		_ = sc.Log.RegisterLog(c, "Error checking stock discrepancies: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error checking stock")
+		return
+	}
+	if discrepancies == nil {
+		discrepancies = []dtos.StockBalanceDTO{}
+	}
+
+	_ = sc.Log.RegisterLog(c, "Successfully checked stock discrepancies")
+	c.JSON(http.StatusOK, discrepancies)
+}
+
+func (sc *StockController) parseStockMovementFilter(c *gin.Context) (dtos.StockMovementFilterDTO, bool) {
+	var filter dtos.StockMovementFilterDTO
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = sc.Log.RegisterLog(c, "Invalid pagination: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return filter, false
+	}
+	filter.PaginationDTO = pagination
+	filter.Reason = c.Query("reason")
+
+	if itemStr := c.Query("itemId"); itemStr != "" {
+		itemID, err := strconv.Atoi(itemStr)
+		if err != nil {
+			_ = sc.Log.RegisterLog(c, "Invalid itemId: "+itemStr)
+			utilities.RespondError(c, http.StatusBadRequest, "Invalid 'itemId'")
+			return filter, false
+		}
+		filter.ItemID = &itemID
+	}
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, _, err := parseLogDate(fromStr)
+		if err != nil {
+			_ = sc.Log.RegisterLog(c, "Invalid from date: "+fromStr)
+			utilities.RespondError(c, http.StatusBadRequest, "Invalid 'from' date. Use YYYY-MM-DD or RFC3339")
+			return filter, false
+		}
+		filter.From = &from
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		to, dateOnly, err := parseLogDate(toStr)
+		if err != nil {
+			_ = sc.Log.RegisterLog(c, "Invalid to date: "+toStr)
+			utilities.RespondError(c, http.StatusBadRequest, "Invalid 'to' date. Use YYYY-MM-DD or RFC3339")
+			return filter, false
+		}
+		if dateOnly {
+			to = to.Add(24*time.Hour - time.Nanosecond)
+		}
+		filter.To = &to
+	}
+	return filter, true
+}
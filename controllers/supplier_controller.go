@@ -0,0 +1,312 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type SupplierController struct {
+	Service *services.SupplierService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewSupplierController(service *services.SupplierService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *SupplierController {
+	return &SupplierController{Service: service, Auth: auth, Log: log}
+}
+
+// GetAllSuppliers godoc
+// @Summary      Get all suppliers
+// @Description  Returns a page of the suppliers inventory is bought from, sorted by name.
+// @Tags         suppliers
+// @Produce      json
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200 {object} dtos.PageDTO[models.Supplier] "Page of suppliers"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid pagination"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      500 {object} dtos.ErrorResponse "Error retrieving suppliers"
+// @Security     ApiKeyAuth
+// @Router       /suppliers [get]
+func (sc *SupplierController) GetAllSuppliers(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_SUPPLIERS
+	if !sc.Auth.CheckPermission(c, permissionId) {
+		_ = sc.Log.RegisterLog(c, "Access denied for GetAllSuppliers")
+		return
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = sc.Log.RegisterLog(c, "Invalid pagination for GetAllSuppliers: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	suppliers, total, err := sc.Service.GetAllSuppliers(c.Request.Context(), pagination)
+	if err != nil {
+		_ = sc.Log.RegisterLog(c, "Error retrieving suppliers: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving suppliers")
+		return
+	}
+
+	_ = sc.Log.RegisterLog(c, "Successfully retrieved all suppliers")
+	c.JSON(http.StatusOK, dtos.NewPageDTO(suppliers, pagination, total))
+}
+
+// GetSupplierByID godoc
+// @Summary      Get supplier by ID
+// @Tags         suppliers
+// @Produce      json
+// @Param        id  path  int  true  "Supplier ID"
+// @Success      200 {object} models.Supplier "Supplier"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid supplier ID"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "Supplier not found"
+// @Failure      500 {object} dtos.ErrorResponse "Error retrieving supplier"
+// @Security     ApiKeyAuth
+// @Router       /suppliers/{id} [get]
+func (sc *SupplierController) GetSupplierByID(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_SUPPLIERS
+	if !sc.Auth.CheckPermission(c, permissionId) {
+		_ = sc.Log.RegisterLog(c, "Access denied for GetSupplierByID")
+		return
+	}
+
+	id, ok := sc.parseSupplierID(c)
+	if !ok {
+		return
+	}
+
+	supplier, err := sc.Service.GetSupplierByID(c.Request.Context(), id)
+	if err != nil {
+		_ = sc.Log.RegisterLog(c, "Error retrieving supplier with ID "+c.Param("id")+": "+err.Error())
+		sc.respondError(c, err, "Error retrieving supplier")
+		return
+	}
+
+	_ = sc.Log.RegisterLog(c, "Successfully retrieved supplier with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, supplier)
+}
+
+// SearchSuppliersByID godoc
+// @Summary      Search suppliers by ID
+// @Description  Finds the suppliers whose internal ID or tax ID starts with the query.
+// @Tags         suppliers
+// @Produce      json
+// @Param        id        query  string  true   "Beginning of the ID or tax ID"
+// @Param        page      query  int     false  "Page number (default 1)"
+// @Param        pageSize  query  int     false  "Page size, up to 200 (default 50)"
+// @Success      200 {object} dtos.PageDTO[models.Supplier] "Matching suppliers"
+// @Failure      400 {object} dtos.ErrorResponse "Missing search query"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      500 {object} dtos.ErrorResponse "Error searching suppliers"
+// @Security     ApiKeyAuth
+// @Router       /suppliers/searchById [get]
+func (sc *SupplierController) SearchSuppliersByID(c *gin.Context) {
+	sc.searchSuppliers(c, "SearchSuppliersByID", "id", sc.Service.SearchSuppliersByID)
+}
+
+// SearchSuppliersByName godoc
+// @Summary      Search suppliers by name
+// @Description  Finds the suppliers whose name starts with the query, ignoring case and accents.
+// @Tags         suppliers
+// @Produce      json
+// @Param        name      query  string  true   "Beginning of the name"
+// @Param        page      query  int     false  "Page number (default 1)"
+// @Param        pageSize  query  int     false  "Page size, up to 200 (default 50)"
+// @Success      200 {object} dtos.PageDTO[models.Supplier] "Matching suppliers"
+// @Failure      400 {object} dtos.ErrorResponse "Missing search query"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      500 {object} dtos.ErrorResponse "Error searching suppliers"
+// @Security     ApiKeyAuth
+// @Router       /suppliers/searchByName [get]
+func (sc *SupplierController) SearchSuppliersByName(c *gin.Context) {
+	sc.searchSuppliers(c, "SearchSuppliersByName", "name", sc.Service.SearchSuppliersByName)
+}
+
+// CreateSupplier godoc
+// @Summary      Create a supplier
+// @Description  Adds a supplier. The tax ID is unique; spaces are ignored and letters are stored in upper case.
+// @Tags         suppliers
+// @Accept       json
+// @Produce      json
+// @Param        supplier  body  dtos.SupplierDTO  true  "Supplier"
+// @Success      201 {object} models.Supplier "Created supplier"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid request data"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      409 {object} dtos.ErrorResponse "Tax ID already in use"
+// @Failure      500 {object} dtos.ErrorResponse "Error creating supplier"
+// @Security     ApiKeyAuth
+// @Router       /suppliers [post]
+func (sc *SupplierController) CreateSupplier(c *gin.Context) {
+	permissionId := config.PERMISSION_CREATE_SUPPLIER
+	if !sc.Auth.CheckPermission(c, permissionId) {
+		_ = sc.Log.RegisterLog(c, "Access denied for CreateSupplier")
+		return
+	}
+
+	var dto dtos.SupplierDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = sc.Log.RegisterLog(c, "Invalid supplier data: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	supplier, err := sc.Service.CreateSupplier(c.Request.Context(), dto)
+	if err != nil {
+		_ = sc.Log.RegisterLog(c, "Error creating supplier: "+err.Error())
+		sc.respondError(c, err, "Error creating supplier")
+		return
+	}
+
+	_ = sc.Log.RegisterLog(c, "Successfully created supplier with ID: "+strconv.Itoa(supplier.ID))
+	c.JSON(http.StatusCreated, supplier)
+}
+
+// UpdateSupplier godoc
+// @Summary      Update a supplier
+// @Description  Replaces the supplier's data. Set supplier_state to false to deactivate a supplier that can no longer be deleted.
+// @Tags         suppliers
+// @Accept       json
+// @Produce      json
+// @Param        id        path  int               true  "Supplier ID"
+// @Param        supplier  body  dtos.SupplierDTO  true  "Supplier"
+// @Success      200 {object} models.Supplier "Updated supplier"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid request data"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "Supplier not found"
+// @Failure      409 {object} dtos.ErrorResponse "Tax ID already in use"
+// @Failure      500 {object} dtos.ErrorResponse "Error updating supplier"
+// @Security     ApiKeyAuth
+// @Router       /suppliers/{id} [put]
+func (sc *SupplierController) UpdateSupplier(c *gin.Context) {
+	permissionId := config.PERMISSION_UPDATE_SUPPLIER
+	if !sc.Auth.CheckPermission(c, permissionId) {
+		_ = sc.Log.RegisterLog(c, "Access denied for UpdateSupplier")
+		return
+	}
+
+	id, ok := sc.parseSupplierID(c)
+	if !ok {
+		return
+	}
+
+	var dto dtos.SupplierDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = sc.Log.RegisterLog(c, "Invalid supplier data: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	supplier, err := sc.Service.UpdateSupplier(c.Request.Context(), id, dto)
+	if err != nil {
+		_ = sc.Log.RegisterLog(c, "Error updating supplier with ID "+c.Param("id")+": "+err.Error())
+		sc.respondError(c, err, "Error updating supplier")
+		return
+	}
+
+	_ = sc.Log.RegisterLog(c, "Successfully updated supplier with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, supplier)
+}
+
+// DeleteSupplier godoc
+// @Summary      Delete a supplier
+// @Description  Deletes a supplier with no additional expenses or restock orders; deactivate the others instead.
+// @Tags         suppliers
+// @Produce      json
+// @Param        id  path  int  true  "Supplier ID"
+// @Success      200 {object} models.MessageResponse "Supplier deleted"
+// @Failure      400 {object} dtos.ErrorResponse "Invalid supplier ID"
+// @Failure      403 {object} dtos.ErrorResponse "Access denied"
+// @Failure      404 {object} dtos.ErrorResponse "Supplier not found"
+// @Failure      409 {object} dtos.ErrorResponse "Supplier in use"
+// @Failure      500 {object} dtos.ErrorResponse "Error deleting supplier"
+// @Security     ApiKeyAuth
+// @Router       /suppliers/{id} [delete]
+func (sc *SupplierController) DeleteSupplier(c *gin.Context) {
+	permissionId := config.PERMISSION_DELETE_SUPPLIER
+	if !sc.Auth.CheckPermission(c, permissionId) {
+		_ = sc.Log.RegisterLog(c, "Access denied for DeleteSupplier")
+		return
+	}
+
+	id, ok := sc.parseSupplierID(c)
+	if !ok {
+		return
+	}
+
+	if err := sc.Service.DeleteSupplier(c.Request.Context(), id); err != nil {
+		_ = sc.Log.RegisterLog(c, "Error deleting supplier with ID "+c.Param("id")+": "+err.Error())
+		sc.respondError(c, err, "Error deleting supplier")
+		return
+	}
+
+	_ = sc.Log.RegisterLog(c, "Successfully deleted supplier with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, gin.H{"message": "Supplier deleted successfully"})
+}
+
+func (sc *SupplierController) searchSuppliers(c *gin.Context, handler, param string,
+	search func(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Supplier, int64, error)) {
+	permissionId := config.PERMISSION_GET_SUPPLIERS
+	if !sc.Auth.CheckPermission(c, permissionId) {
+		_ = sc.Log.RegisterLog(c, "Access denied for "+handler)
+		return
+	}
+
+	query := c.Query(param)
+	if query == "" {
+		_ = sc.Log.RegisterLog(c, "Search query is missing for "+handler)
+		utilities.RespondError(c, http.StatusBadRequest, "Search query '"+param+"' is required")
+		return
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = sc.Log.RegisterLog(c, "Invalid pagination for "+handler+": "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	suppliers, total, err := search(c.Request.Context(), query, pagination)
+	if err != nil {
+		_ = sc.Log.RegisterLog(c, "Error searching suppliers: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error searching suppliers")
+		return
+	}
+
+	_ = sc.Log.RegisterLog(c, "Successfully searched suppliers for: "+query)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(suppliers, pagination, total))
+}
+
+func (sc *SupplierController) parseSupplierID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = sc.Log.RegisterLog(c, "Invalid supplier ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid supplier ID")
+		return 0, false
+	}
+	return id, true
+}
+
+func (sc *SupplierController) respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utilities.RespondError(c, http.StatusNotFound, "Supplier not found")
+	case errors.Is(err, services.ErrInvalidSupplier):
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrSupplierTaxIDTaken), errors.Is(err, services.ErrSupplierInUse):
+		utilities.RespondError(c, http.StatusConflict, err.Error())
+	default:
+		utilities.RespondError(c, http.StatusInternalServerError, message)
+	}
+}
@@ -1,14 +1,17 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
-	"totesbackend/models"
+	"totesbackend/dtos"
 	"totesbackend/services"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type TaxTypeController struct {
@@ -29,9 +32,9 @@ func NewTaxTypeController(service *services.TaxTypeService,
 // @Produce      json
 // @Param        id  path  string  true  "Tax Type ID"
 // @Success      200  {object}  models.TaxType  "Details of the tax type"
-// @Failure      400  {object}  models.ErrorResponse  "Invalid ID format"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      404  {object}  models.ErrorResponse  "Tax type not found"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid ID format"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Tax type not found"
 // @Security     ApiKeyAuth
 // @Router       /tax-types/{id} [get]
 func (ttc *TaxTypeController) GetTaxTypeByID(c *gin.Context) {
@@ -42,9 +45,9 @@ func (ttc *TaxTypeController) GetTaxTypeByID(c *gin.Context) {
 	}
 
 	id := c.Param("id")
-	taxType, err := ttc.Service.GetTaxTypeByID(id)
+	taxType, err := ttc.Service.GetTaxTypeByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Tax Type not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Tax Type not found")
 		return
 	}
 
@@ -56,9 +59,11 @@ func (ttc *TaxTypeController) GetTaxTypeByID(c *gin.Context) {
 // @Description  Fetches the list of all available tax types.
 // @Tags         tax-types
 // @Produce      json
-// @Success      200  {array}   models.TaxType  "List of tax types"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500  {object}  models.ErrorResponse  "Error retrieving tax types"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[models.TaxType]  "List of tax types"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving tax types"
 // @Security     ApiKeyAuth
 // @Router       /tax-types [get]
 func (ttc *TaxTypeController) GetAllTaxTypes(c *gin.Context) {
@@ -68,53 +73,213 @@ func (ttc *TaxTypeController) GetAllTaxTypes(c *gin.Context) {
 		return
 	}
 
-	taxTypes, err := ttc.Service.GetAllTaxTypes()
+	pagination, err := utilities.ParsePagination(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving Tax Types"})
+		_ = ttc.Log.RegisterLog(c, "Invalid pagination for GetAllTaxTypes: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
-	c.JSON(http.StatusOK, taxTypes)
+
+	taxTypes, total, err := ttc.Service.GetAllTaxTypes(c.Request.Context(), pagination)
+	if err != nil {
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving Tax Types")
+		return
+	}
+	c.JSON(http.StatusOK, dtos.NewPageDTO(taxTypes, pagination, total))
 }
 
 // CreateTaxType godoc
 // @Summary      Create a new tax type
-// @Description  Creates a new tax type by providing its details (name, percentage, etc).
+// @Description  Creates a new, active tax type by providing its details (name, percentage, etc).
 // @Tags         tax-types
 // @Accept       json
 // @Produce      json
-// @Param        tax  body     models.TaxType  true  "Tax Type Details"
+// @Param        tax  body     dtos.TaxTypeDTO  true  "Tax Type Details"
 // @Success      201  {object}  models.TaxType  "Successfully created tax type"
-// @Failure      400  {object}  models.ErrorResponse  "Invalid input data"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500  {object}  models.ErrorResponse  "Error creating tax type"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid input data"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error creating tax type"
 // @Security     ApiKeyAuth
 // @Router       /tax-types [post]
 func (ttc *TaxTypeController) CreateTaxType(c *gin.Context) {
-	if ttc.Log.RegisterLog(c, "Attempting to create a new tax type") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	permissionId := config.PERMISSION_CREATE_TAX_TYPE
 	if !ttc.Auth.CheckPermission(c, permissionId) {
 		_ = ttc.Log.RegisterLog(c, "Access denied for CreateTaxType")
 		return
 	}
 
-	var tax models.TaxType
-	if err := c.ShouldBindJSON(&tax); err != nil {
+	var dto dtos.TaxTypeDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = ttc.Log.RegisterLog(c, "Invalid input for tax type creation: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos del impuesto"})
+		utilities.RespondValidationError(c, "Invalid tax data", err)
 		return
 	}
 
-	err := ttc.Service.CreateTaxType(&tax)
+	tax, err := ttc.Service.CreateTaxType(c.Request.Context(), dto)
 	if err != nil {
 		_ = ttc.Log.RegisterLog(c, "Failed to create tax type: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo crear el impuesto"})
+		ttc.respondError(c, err, "Error creating tax type")
 		return
 	}
 
 	_ = ttc.Log.RegisterLog(c, "Successfully created new tax type")
 	c.JSON(http.StatusCreated, tax)
 }
+
+// UpdateTaxType godoc
+// @Summary      Update a tax type
+// @Description  Changes the name, rate and optionally the active flag of a tax type. When the rate changes the previous one is kept in the rate history, and invoices issued before the change keep the rate they were issued with.
+// @Tags         tax-types
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string           true  "Tax Type ID"
+// @Param        tax  body      dtos.TaxTypeDTO  true  "Tax Type Details"
+// @Success      200  {object}  models.TaxType  "Updated tax type"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid input data"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Tax type not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error updating tax type"
+// @Security     ApiKeyAuth
+// @Router       /tax-types/{id} [put]
+func (ttc *TaxTypeController) UpdateTaxType(c *gin.Context) {
+	permissionId := config.PERMISSION_UPDATE_TAX_TYPE
+	if !ttc.Auth.CheckPermission(c, permissionId) {
+		_ = ttc.Log.RegisterLog(c, "Access denied for UpdateTaxType")
+		return
+	}
+
+	id := c.Param("id")
+	var dto dtos.TaxTypeDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = ttc.Log.RegisterLog(c, "Invalid input for tax type update: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid tax data", err)
+		return
+	}
+
+	tax, err := ttc.Service.UpdateTaxType(c.Request.Context(), id, dto)
+	if err != nil {
+		_ = ttc.Log.RegisterLog(c, "Error updating tax type with ID "+id+": "+err.Error())
+		ttc.respondError(c, err, "Error updating tax type")
+		return
+	}
+
+	_ = ttc.Log.RegisterLog(c, "Successfully updated tax type with ID: "+id)
+	c.JSON(http.StatusOK, tax)
+}
+
+// DeactivateTaxType godoc
+// @Summary      Deactivate a tax type
+// @Description  Deactivates a tax type without deleting it: it can no longer be applied to new invoices or quotations, and the invoices that applied it keep it. Reactivate it with PUT /tax-types/{id}.
+// @Tags         tax-types
+// @Produce      json
+// @Param        id   path      string  true  "Tax Type ID"
+// @Success      200  {object}  models.TaxType  "Deactivated tax type"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Tax type not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error deactivating tax type"
+// @Security     ApiKeyAuth
+// @Router       /tax-types/{id}/deactivate [patch]
+func (ttc *TaxTypeController) DeactivateTaxType(c *gin.Context) {
+	permissionId := config.PERMISSION_DEACTIVATE_TAX_TYPE
+	if !ttc.Auth.CheckPermission(c, permissionId) {
+		_ = ttc.Log.RegisterLog(c, "Access denied for DeactivateTaxType")
+		return
+	}
+
+	id := c.Param("id")
+	tax, err := ttc.Service.DeactivateTaxType(c.Request.Context(), id)
+	if err != nil {
+		_ = ttc.Log.RegisterLog(c, "Error deactivating tax type with ID "+id+": "+err.Error())
+		ttc.respondError(c, err, "Error deactivating tax type")
+		return
+	}
+
+	_ = ttc.Log.RegisterLog(c, "Successfully deactivated tax type with ID: "+id)
+	c.JSON(http.StatusOK, tax)
+}
+
+// GetTaxTypeRates godoc
+// @Summary      Tax type rate history
+// @Description  Lists the previous rates of a tax type, newest first, each with the moment it stopped applying. Invoices issued before valid_until are read with that rate.
+// @Tags         tax-types
+// @Produce      json
+// @Param        id   path      string  true  "Tax Type ID"
+// @Success      200  {array}   models.TaxTypeRate  "Previous rates"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Tax type not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving tax type rates"
+// @Security     ApiKeyAuth
+// @Router       /tax-types/{id}/rates [get]
+func (ttc *TaxTypeController) GetTaxTypeRates(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_TAX_TYPE_BY_ID
+	if !ttc.Auth.CheckPermission(c, permissionId) {
+		return
+	}
+
+	id := c.Param("id")
+	rates, err := ttc.Service.GetTaxTypeRates(c.Request.Context(), id)
+	if err != nil {
+		ttc.respondError(c, err, "Error retrieving tax type rates")
+		return
+	}
+
+	c.JSON(http.StatusOK, rates)
+}
+
+// ImportTaxTypes godoc
+// @Summary      Bulk import tax types
+// @Description  Creates several tax types at once from a JSON array, a text/csv body or a multipart CSV file (field "file").
+// @Description  CSV files must include a header row with the columns name, description, is_percentage and value.
+// @Description  The import is all-or-nothing: if any row is invalid nothing is created.
+// @Tags         tax-types
+// @Accept       json
+// @Accept       text/csv
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        taxes  body      []dtos.ImportCatalogEntryDTO  false  "Tax types to import (JSON)"
+// @Param        file   formData  file                          false  "CSV file with the tax types to import"
+// @Success      201  {object}  dtos.ImportCatalogResultDTO  "Number of imported tax types"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid input data"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error importing tax types"
+// @Security     ApiKeyAuth
+// @Router       /tax-types/import [post]
+func (ttc *TaxTypeController) ImportTaxTypes(c *gin.Context) {
+	permissionId := config.PERMISSION_IMPORT_TAX_TYPES
+	if !ttc.Auth.CheckPermission(c, permissionId) {
+		_ = ttc.Log.RegisterLog(c, "Access denied for ImportTaxTypes")
+		return
+	}
+
+	entries, err := utilities.ParseCatalogImport(c)
+	if err != nil {
+		_ = ttc.Log.RegisterLog(c, "Invalid input for tax type import: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid input: "+err.Error(), err)
+		return
+	}
+
+	taxTypes, err := ttc.Service.ImportTaxTypes(c.Request.Context(), entries)
+	if err != nil {
+		_ = ttc.Log.RegisterLog(c, "Failed to import tax types: "+err.Error())
+		if errors.Is(err, services.ErrInvalidCatalogImport) {
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Failed to import tax types")
+		return
+	}
+
+	_ = ttc.Log.RegisterLog(c, "Successfully imported "+strconv.Itoa(len(taxTypes))+" tax types")
+	c.JSON(http.StatusCreated, dtos.ImportCatalogResultDTO{Imported: len(taxTypes)})
+}
+
+func (ttc *TaxTypeController) respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utilities.RespondError(c, http.StatusNotFound, "Tax Type not found")
+	case errors.Is(err, services.ErrInvalidTaxType):
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+	default:
+		utilities.RespondError(c, http.StatusInternalServerError, message)
+	}
+}
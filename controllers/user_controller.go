@@ -19,10 +19,13 @@ type UserController struct {
 	Service *services.UserService
 	Auth    *utilities.AuthorizationUtil
 	Log     *utilities.LogUtil
+	Audit   *utilities.AuditUtil
+	// cierra las sesiones del usuario al cambiar su contraseña o su estado
+	Tokens *services.TokenService
 }
 
-func NewUserController(service *services.UserService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *UserController {
-	return &UserController{Service: service, Auth: auth, Log: log}
+func NewUserController(service *services.UserService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil, audit *utilities.AuditUtil) *UserController {
+	return &UserController{Service: service, Auth: auth, Log: log, Audit: audit}
 }
 
 // GetUserByID godoc
@@ -33,38 +36,34 @@ func NewUserController(service *services.UserService, auth *utilities.Authorizat
 // @Produce      json
 // @Param        id  path     string  true  "User ID"
 // @Success      200  {object}  dtos.GetUserDTO  "User details"
-// @Failure      400  {object}  models.ErrorResponse  "Invalid ID format"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      404  {object}  models.ErrorResponse  "User not found"
-// @Failure      500  {object}  models.ErrorResponse  "Error retrieving user"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid ID format"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "User not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving user"
 // @Security     ApiKeyAuth
 // @Router       /users/{id} [get]
 func (uc *UserController) GetUserByID(c *gin.Context) {
 	id := c.Param("id")
 
 	// Log de intento
-	if uc.Log.RegisterLog(c, "Attempting to retrieve user with ID: "+id) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
 
 	permissionId := config.PERMISSION_GET_USER_BY_ID
 	if !uc.Auth.CheckPermission(c, permissionId) {
 		_ = uc.Log.RegisterLog(c, "Access denied for GetUserByID")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		utilities.RespondError(c, http.StatusForbidden, "Access denied")
 		return
 	}
 
-	user, err := uc.Service.GetUserByID(id)
+	user, err := uc.Service.GetUserByID(c.Request.Context(), id)
 	if err != nil {
 		_ = uc.Log.RegisterLog(c, "Error retrieving user with ID "+id+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving user"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving user")
 		return
 	}
 
 	if user == nil {
 		_ = uc.Log.RegisterLog(c, "User with ID "+id+" not found")
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		utilities.RespondError(c, http.StatusNotFound, "User not found")
 		return
 	}
 
@@ -86,31 +85,36 @@ func (uc *UserController) GetUserByID(c *gin.Context) {
 // @Tags         users
 // @Accept       json
 // @Produce      json
-// @Success      200  {array}  dtos.GetUserDTO  "List of users"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      404  {object}  models.ErrorResponse  "Users not found"
-// @Failure      500  {object}  models.ErrorResponse  "Error retrieving users"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200  {object} dtos.PageDTO[dtos.GetUserDTO]  "List of users"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Users not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving users"
 // @Security     ApiKeyAuth
 // @Router       /users [get]
 func (uc *UserController) GetAllUsers(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_ALL_USERS
 
 	// Intento de obtener todos los usuarios
-	if uc.Log.RegisterLog(c, "Attempting to retrieve all users") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
 
 	if !uc.Auth.CheckPermission(c, permissionId) {
 		_ = uc.Log.RegisterLog(c, "Access denied for GetAllUsers")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		utilities.RespondError(c, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = uc.Log.RegisterLog(c, "Invalid pagination for GetAllUsers: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	users, err := uc.Service.GetAllUsers()
+	users, total, err := uc.Service.GetAllUsers(c.Request.Context(), pagination)
 	if err != nil {
 		_ = uc.Log.RegisterLog(c, "Error retrieving all users: "+err.Error())
-		c.JSON(http.StatusNotFound, gin.H{"error": "Users not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Users not found")
 		return
 	}
 
@@ -127,7 +131,7 @@ func (uc *UserController) GetAllUsers(c *gin.Context) {
 	}
 
 	_ = uc.Log.RegisterLog(c, "Successfully retrieved all users")
-	c.JSON(http.StatusOK, usersDTO)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(usersDTO, pagination, total))
 }
 
 // SearchUsersByID godoc
@@ -137,11 +141,13 @@ func (uc *UserController) GetAllUsers(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        id   query     string  true  "User ID to search"
-// @Success      200  {array}  dtos.GetUserDTO  "List of users matching the search criteria"
-// @Failure      400  {object}  models.ErrorResponse  "Query parameter is required"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      404  {object}  models.ErrorResponse  "Users not found"
-// @Failure      500  {object}  models.ErrorResponse  "Error searching users"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200  {object} dtos.PageDTO[dtos.GetUserDTO]  "List of users matching the search criteria"
+// @Failure      400  {object}  dtos.ErrorResponse  "Query parameter is required"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Users not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error searching users"
 // @Security     ApiKeyAuth
 // @Router       /users/searchByID [get]
 func (uc *UserController) SearchUsersByID(c *gin.Context) {
@@ -149,33 +155,36 @@ func (uc *UserController) SearchUsersByID(c *gin.Context) {
 	query := c.Query("id")
 
 	// Intento de búsqueda
-	if uc.Log.RegisterLog(c, "Attempting to search users by ID with query: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
 
 	if !uc.Auth.CheckPermission(c, permissionId) {
 		_ = uc.Log.RegisterLog(c, "Access denied for SearchUsersByID")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		utilities.RespondError(c, http.StatusForbidden, "Access denied")
 		return
 	}
 
 	if query == "" {
 		_ = uc.Log.RegisterLog(c, "Query parameter 'id' is missing for SearchUsersByID")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter is required"})
+		utilities.RespondError(c, http.StatusBadRequest, "Query parameter is required")
 		return
 	}
 
-	users, err := uc.Service.SearchUsersByID(query)
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = uc.Log.RegisterLog(c, "Invalid pagination for SearchUsersByID: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	users, total, err := uc.Service.SearchUsersByID(c.Request.Context(), query, pagination)
 	if err != nil {
 		_ = uc.Log.RegisterLog(c, "Error searching users by ID "+query+": "+err.Error())
-		c.JSON(http.StatusNotFound, gin.H{"error": "Users not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Users not found")
 		return
 	}
 
-	if len(users) == 0 {
+	if total == 0 {
 		_ = uc.Log.RegisterLog(c, "No users found with ID containing: "+query)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No users found"})
+		utilities.RespondError(c, http.StatusNotFound, "No users found")
 		return
 	}
 
@@ -192,7 +201,7 @@ func (uc *UserController) SearchUsersByID(c *gin.Context) {
 	}
 
 	_ = uc.Log.RegisterLog(c, "Successfully retrieved users with query: "+query)
-	c.JSON(http.StatusOK, usersDTO)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(usersDTO, pagination, total))
 }
 
 // SearchUsersByEmail godoc
@@ -202,11 +211,13 @@ func (uc *UserController) SearchUsersByID(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        email   query     string  true  "User email to search"
-// @Success      200     {array}  dtos.GetUserDTO  "List of users matching the search criteria"
-// @Failure      400     {object}  models.ErrorResponse  "Query parameter is required"
-// @Failure      403     {object}  models.ErrorResponse  "Permission denied"
-// @Failure      404     {object}  models.ErrorResponse  "Users not found"
-// @Failure      500     {object}  models.ErrorResponse  "Error searching users"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200     {object} dtos.PageDTO[dtos.GetUserDTO]  "List of users matching the search criteria"
+// @Failure      400     {object}  dtos.ErrorResponse  "Query parameter is required"
+// @Failure      403     {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404     {object}  dtos.ErrorResponse  "Users not found"
+// @Failure      500     {object}  dtos.ErrorResponse  "Error searching users"
 // @Security     ApiKeyAuth
 // @Router       /users/searchByEmail [get]
 func (uc *UserController) SearchUsersByEmail(c *gin.Context) {
@@ -214,33 +225,36 @@ func (uc *UserController) SearchUsersByEmail(c *gin.Context) {
 	query := c.Query("email")
 
 	// Intento de búsqueda
-	if uc.Log.RegisterLog(c, "Attempting to search users by email with query: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
 
 	if !uc.Auth.CheckPermission(c, permissionId) {
 		_ = uc.Log.RegisterLog(c, "Access denied for SearchUsersByEmail")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		utilities.RespondError(c, http.StatusForbidden, "Access denied")
 		return
 	}
 
 	if query == "" {
 		_ = uc.Log.RegisterLog(c, "Query parameter 'email' is missing for SearchUsersByEmail")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter is required"})
+		utilities.RespondError(c, http.StatusBadRequest, "Query parameter is required")
+		return
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = uc.Log.RegisterLog(c, "Invalid pagination for SearchUsersByEmail: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	users, err := uc.Service.SearchUsersByEmail(query)
+	users, total, err := uc.Service.SearchUsersByEmail(c.Request.Context(), query, pagination)
 	if err != nil {
 		_ = uc.Log.RegisterLog(c, "Error searching users by email "+query+": "+err.Error())
-		c.JSON(http.StatusNotFound, gin.H{"error": "Users not found"})
+		utilities.RespondError(c, http.StatusNotFound, "Users not found")
 		return
 	}
 
-	if len(users) == 0 {
+	if total == 0 {
 		_ = uc.Log.RegisterLog(c, "No users found with email containing: "+query)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No users found"})
+		utilities.RespondError(c, http.StatusNotFound, "No users found")
 		return
 	}
 
@@ -257,7 +271,7 @@ func (uc *UserController) SearchUsersByEmail(c *gin.Context) {
 	}
 
 	_ = uc.Log.RegisterLog(c, "Successfully retrieved users with email query: "+query)
-	c.JSON(http.StatusOK, usersDTO)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(usersDTO, pagination, total))
 }
 
 type request struct {
@@ -273,10 +287,10 @@ type request struct {
 // @Param        id      path     string  true  "User ID"
 // @Param        body    body     request  true  "User state to update" // Updated 'object' to 'body' and set 'true'
 // @Success      200     {object}  dtos.GetUserDTO  "Updated user information"
-// @Failure      400     {object}  models.ErrorResponse  "Invalid request body"
-// @Failure      403     {object}  models.ErrorResponse  "Permission denied"
-// @Failure      404     {object}  models.ErrorResponse  "User not found"
-// @Failure      500     {object}  models.ErrorResponse  "Error updating user state"
+// @Failure      400     {object}  dtos.ErrorResponse  "Invalid request body"
+// @Failure      403     {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404     {object}  dtos.ErrorResponse  "User not found"
+// @Failure      500     {object}  dtos.ErrorResponse  "Error updating user state"
 // @Security     ApiKeyAuth
 // @Router       /users/{id}/state [patch]
 func (uc *UserController) UpdateUserState(c *gin.Context) {
@@ -285,30 +299,33 @@ func (uc *UserController) UpdateUserState(c *gin.Context) {
 	id := c.Param("id")
 
 	// Log de intento
-	if uc.Log.RegisterLog(c, "Attempting to update user state for ID: "+id) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
 
 	// Check permission
 	if !uc.Auth.CheckPermission(c, permissionId) {
 		_ = uc.Log.RegisterLog(c, "Access denied for UpdateUserState")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		utilities.RespondError(c, http.StatusForbidden, "Access denied")
 		return
 	}
 
 	// Bind JSON request body to request struct
 	if err := c.ShouldBindJSON(&request); err != nil {
 		_ = uc.Log.RegisterLog(c, "Invalid request body for UpdateUserState: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utilities.RespondValidationError(c, "Invalid request body", err)
+		return
+	}
+
+	before, err := uc.Service.GetUserByID(c.Request.Context(), id)
+	if err != nil {
+		_ = uc.Log.RegisterLog(c, "User not found with ID "+id+" while updating state")
+		utilities.RespondError(c, http.StatusNotFound, "User not found")
 		return
 	}
 
 	// Update user state
-	user, err := uc.Service.UpdateUserState(id, request.UserState)
+	user, err := uc.Service.UpdateUserState(c.Request.Context(), id, request.UserState)
 	if err != nil {
 		_ = uc.Log.RegisterLog(c, "User not found with ID "+id+" while updating state")
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		utilities.RespondError(c, http.StatusNotFound, "User not found")
 		return
 	}
 
@@ -321,6 +338,10 @@ func (uc *UserController) UpdateUserState(c *gin.Context) {
 		UserStateID: user.UserStateTypeID,
 	}
 
+	if err := uc.Audit.RecordChange(c, services.AUDIT_ENTITY_USER, id, services.AUDIT_ACTION_UPDATE, before, user); err != nil {
+		_ = uc.Log.RegisterLog(c, "Error recording audit trail for user with ID "+id+": "+err.Error())
+	}
+
 	// Log success and return response
 	_ = uc.Log.RegisterLog(c, "Successfully updated user state for ID: "+id)
 	c.JSON(http.StatusOK, userDTO)
@@ -335,52 +356,106 @@ func (uc *UserController) UpdateUserState(c *gin.Context) {
 // @Param        id      path     string  true  "User ID"
 // @Param        body    body     dtos.UpdateUserDTO  true  "User details to update"
 // @Success      200     {object}  dtos.GetUserDTO  "Updated user information"
-// @Failure      400     {object}  models.ErrorResponse  "Invalid request body"
-// @Failure      403     {object}  models.ErrorResponse  "Permission denied"
-// @Failure      404     {object}  models.ErrorResponse  "User not found"
-// @Failure      500     {object}  models.ErrorResponse  "Error updating user information"
+// @Failure      400     {object}  dtos.ErrorResponse  "Invalid request body"
+// @Failure      403     {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404     {object}  dtos.ErrorResponse  "User not found"
+// @Failure      500     {object}  dtos.ErrorResponse  "Error updating user information"
 // @Security     ApiKeyAuth
 // @Router       /users/{id} [put]
 func (uc *UserController) UpdateUser(c *gin.Context) {
 	permissionId := config.PERMISSION_UPDATE_USER
 	id := c.Param("id")
 
-	if uc.Log.RegisterLog(c, "Attempting to update user with ID: "+id) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	if !uc.Auth.CheckPermission(c, permissionId) {
 		_ = uc.Log.RegisterLog(c, "Access denied for UpdateUser")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		utilities.RespondError(c, http.StatusForbidden, "Access denied")
 		return
 	}
 
 	var dto dtos.UpdateUserDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = uc.Log.RegisterLog(c, "Invalid request body for UpdateUser: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utilities.RespondValidationError(c, "Invalid request body", err)
+		return
+	}
+
+	user, err := uc.Service.GetUserByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = uc.Log.RegisterLog(c, "User not found with ID: "+id)
+			utilities.RespondError(c, http.StatusNotFound, "User not found")
+			return
+		}
+		_ = uc.Log.RegisterLog(c, "Error retrieving user with ID: "+id)
+		utilities.RespondError(c, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
-	user, err := uc.Service.GetUserByID(id)
+	uc.saveUser(c, id, user, dto)
+}
+
+// PatchUser godoc
+// @Summary      Partially update a user
+// @Description  Applies a JSON merge patch (RFC 7396) to a user: only the supplied fields change and null clears a field.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id      path     string              true  "User ID"
+// @Param        body    body     dtos.UpdateUserDTO  true  "Fields to change"
+// @Success      200     {object}  dtos.GetUserDTO     "Updated user information"
+// @Failure      400     {object}  dtos.ErrorResponse  "Invalid patch document"
+// @Failure      403     {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404     {object}  dtos.ErrorResponse  "User not found"
+// @Failure      500     {object}  dtos.ErrorResponse  "Error updating user information"
+// @Security     ApiKeyAuth
+// @Router       /users/{id} [patch]
+func (uc *UserController) PatchUser(c *gin.Context) {
+	permissionId := config.PERMISSION_UPDATE_USER
+	id := c.Param("id")
+
+	if !uc.Auth.CheckPermission(c, permissionId) {
+		_ = uc.Log.RegisterLog(c, "Access denied for PatchUser")
+		utilities.RespondError(c, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	user, err := uc.Service.GetUserByID(c.Request.Context(), id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			_ = uc.Log.RegisterLog(c, "User not found with ID: "+id)
-			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			utilities.RespondError(c, http.StatusNotFound, "User not found")
 			return
 		}
 		_ = uc.Log.RegisterLog(c, "Error retrieving user with ID: "+id)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	current := dtos.UpdateUserDTO{
+		Email:       user.Email,
+		Password:    user.Password,
+		UserTypeID:  user.UserTypeID,
+		UserStateID: user.UserStateTypeID,
+	}
+	var dto dtos.UpdateUserDTO
+	if err := utilities.BindMergePatch(c, current, &dto); err != nil {
+		_ = uc.Log.RegisterLog(c, "Invalid patch document for PatchUser: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid patch document", err)
 		return
 	}
 
+	uc.saveUser(c, id, user, dto)
+}
+
+func (uc *UserController) saveUser(c *gin.Context, id string, user *models.User, dto dtos.UpdateUserDTO) {
+	before := *user
+
 	user.Email = dto.Email
 	user.Password = dto.Password
 	user.UserTypeID = dto.UserTypeID
 	user.UserStateTypeID = dto.UserStateID
 
-	err = uc.Service.UpdateUser(user)
+	err := uc.Service.UpdateUser(c.Request.Context(), user)
 
 	dtoUser := dtos.GetUserDTO{
 		ID:          user.ID,
@@ -392,10 +467,28 @@ func (uc *UserController) UpdateUser(c *gin.Context) {
 
 	if err != nil {
 		_ = uc.Log.RegisterLog(c, "Failed to update user with ID: "+id)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
+	if err := uc.Audit.RecordChange(c, services.AUDIT_ENTITY_USER, id, services.AUDIT_ACTION_UPDATE, before, user); err != nil {
+		_ = uc.Log.RegisterLog(c, "Error recording audit trail for user with ID "+id+": "+err.Error())
+	}
+
+	if dto.Password != "" && dto.Password != before.Password {
+		_ = uc.Log.RegisterSecurityEvent(c, services.SECURITY_EVENT_PASSWORD_CHANGE, user.Email,
+			"password changed for user "+id+" by "+utilities.CurrentUser(c))
+	}
+	if (dto.Password != "" && dto.Password != before.Password) || user.UserStateTypeID != before.UserStateTypeID {
+		if err := uc.Tokens.RevokeUserTokens(c.Request.Context(), user.ID); err != nil {
+			_ = uc.Log.RegisterLog(c, "Error closing the sessions of user with ID "+id+": "+err.Error())
+		}
+	}
+	if dto.UserTypeID != 0 && dto.UserTypeID != before.UserTypeID {
+		_ = uc.Log.RegisterSecurityEvent(c, services.SECURITY_EVENT_ROLE_CHANGE, user.Email,
+			"user type of user "+id+" changed from "+strconv.Itoa(before.UserTypeID)+" to "+strconv.Itoa(user.UserTypeID)+" by "+utilities.CurrentUser(c))
+	}
+
 	_ = uc.Log.RegisterLog(c, "Successfully updated user with ID: "+id)
 	c.JSON(http.StatusOK, dtoUser)
 }
@@ -408,37 +501,32 @@ func (uc *UserController) UpdateUser(c *gin.Context) {
 // @Produce      json
 // @Param        body    body     dtos.CreateUserDTO  true  "User details to create"
 // @Success      201     {object}  dtos.GetUserDTO  "Created user information"
-// @Failure      400     {object}  models.ErrorResponse  "Invalid request body"
-// @Failure      403     {object}  models.ErrorResponse  "Permission denied"
-// @Failure      409     {object}  models.ErrorResponse  "Email already in use"
-// @Failure      500     {object}  models.ErrorResponse  "Error creating user"
+// @Failure      400     {object}  dtos.ErrorResponse  "Invalid request body"
+// @Failure      403     {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      409     {object}  dtos.ErrorResponse  "Email already in use"
+// @Failure      500     {object}  dtos.ErrorResponse  "Error creating user"
 // @Security     ApiKeyAuth
 // @Router       /users [post]
 func (uc *UserController) CreateUser(c *gin.Context) {
 	permissionId := config.PERMISSION_CREATE_USER
 
-	if uc.Log.RegisterLog(c, "Attempting to create new user") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	if !uc.Auth.CheckPermission(c, permissionId) {
 		_ = uc.Log.RegisterLog(c, "Access denied for CreateUser")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		utilities.RespondError(c, http.StatusForbidden, "Access denied")
 		return
 	}
 
 	var dto dtos.CreateUserDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = uc.Log.RegisterLog(c, "Invalid request body for CreateUser: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utilities.RespondValidationError(c, "Invalid request body", err)
 		return
 	}
 
-	existingUser, _ := uc.Service.GetUserByEmail(dto.Email)
+	existingUser, _ := uc.Service.GetUserByEmail(c.Request.Context(), dto.Email)
 	if existingUser != nil {
 		_ = uc.Log.RegisterLog(c, "Email already in use: "+dto.Email)
-		c.JSON(http.StatusConflict, gin.H{"error": "Email already in use"})
+		utilities.RespondError(c, http.StatusConflict, "Email already in use")
 		return
 	}
 
@@ -449,10 +537,10 @@ func (uc *UserController) CreateUser(c *gin.Context) {
 		UserStateTypeID: dto.UserStateID,
 	}
 
-	createdUser, err := uc.Service.CreateUser(&newUser)
+	createdUser, err := uc.Service.CreateUser(c.Request.Context(), &newUser)
 	if err != nil {
 		_ = uc.Log.RegisterLog(c, "Failed to create user: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Failed to create user")
 		return
 	}
 
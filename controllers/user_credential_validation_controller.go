@@ -1,22 +1,31 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
+	"totesbackend/config"
 	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/models"
 	"totesbackend/services"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 type UserCredentialValidationController struct {
 	Service *services.UserCredentialValidationService
+	Tokens  *services.TokenService
 	Auth    *utilities.AuthorizationUtil
 	Log     *utilities.LogUtil
+	Resets  *services.PasswordResetService
 }
 
-func NewUserCredentialValidationController(service *services.UserCredentialValidationService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *UserCredentialValidationController {
-	return &UserCredentialValidationController{Service: service, Auth: auth, Log: log}
+func NewUserCredentialValidationController(service *services.UserCredentialValidationService, tokens *services.TokenService,
+	auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *UserCredentialValidationController {
+	return &UserCredentialValidationController{Service: service, Tokens: tokens, Auth: auth, Log: log}
 }
 
 // LoginData defines the structure for user login request
@@ -26,50 +35,213 @@ type LoginData struct {
 }
 
 // ValidateUserCredentials godoc
-// @Summary      Validate user credentials
-// @Description  Validates the user's credentials (email and password) for login.
+// @Summary      Log in
+// @Description  Validates the user's credentials (email and password) and opens a session: a short-lived JWT access token, sent as "Authorization: Bearer <token>" on every request, and a refresh token to obtain new ones from POST /auth/refresh.
 // @Tags         authentication
 // @Accept       json
 // @Produce      json
 // @Param        body    body     LoginData  true  "User credentials to validate"
-// @Success      200     {object}   models.MessageResponse "Login successful message"
-// @Failure      400     {object}  models.ErrorResponse  "Invalid request body"
-// @Failure      403     {object}  models.ErrorResponse  "User account is not active"
-// @Failure      401     {object}  models.ErrorResponse  "Invalid email or password"
-// @Failure      500     {object}  models.ErrorResponse  "Error validating credentials"
-// @Security     ApiKeyAuth
+// @Success      200     {object}  dtos.TokenPairDTO  "Access and refresh tokens"
+// @Failure      400     {object}  dtos.ErrorResponse  "Invalid request body"
+// @Failure      403     {object}  dtos.ErrorResponse  "User account is not active"
+// @Failure      401     {object}  dtos.ErrorResponse  "Invalid email or password"
+// @Failure      500     {object}  dtos.ErrorResponse  "Error validating credentials"
 // @Router       /login [post]
 func (ucvc *UserCredentialValidationController) ValidateUserCredentials(c *gin.Context) {
-	if ucvc.Log.RegisterLog(c, "Attempting user login") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	var loginData LoginData
 
 	if err := c.ShouldBindJSON(&loginData); err != nil {
 		_ = ucvc.Log.RegisterLog(c, "Invalid request body for login")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		utilities.RespondValidationError(c, "Invalid request body", err)
 		return
 	}
 
-	err := ucvc.Service.ValidateUserCredentials(loginData.Email, loginData.Password)
+	user, err := ucvc.Service.ValidateUserCredentials(c.Request.Context(), loginData.Email, loginData.Password)
 	if err != nil {
 		if err.Error() == "user is not active" {
 			_ = ucvc.Log.RegisterLog(c, "Login attempt for inactive user: "+loginData.Email)
-			c.JSON(http.StatusForbidden, gin.H{"error": "User account is not active"})
+			_ = ucvc.Log.RegisterSecurityEvent(c, services.SECURITY_EVENT_LOGIN_FAILURE, loginData.Email, "inactive user")
+			utilities.RespondError(c, http.StatusForbidden, "User account is not active")
 			return
 		}
 
 		_ = ucvc.Log.RegisterLog(c, "Login failed for user: "+loginData.Email)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+		_ = ucvc.Log.RegisterSecurityEvent(c, services.SECURITY_EVENT_LOGIN_FAILURE, loginData.Email, "invalid email or password")
+		utilities.RespondError(c, http.StatusUnauthorized, "Invalid email or password")
+		return
+	}
+
+	tokens, err := ucvc.Tokens.IssueTokens(c.Request.Context(), user, c.ClientIP())
+	if err != nil {
+		_ = ucvc.Log.RegisterLog(c, "Error issuing tokens for user "+loginData.Email+": "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error validating credentials")
 		return
 	}
 
 	_ = ucvc.Log.RegisterLog(c, "Login successful for user: "+loginData.Email)
+	_ = ucvc.Log.RegisterSecurityEvent(c, services.SECURITY_EVENT_LOGIN_SUCCESS, loginData.Email, "login successful")
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// RefreshTokens godoc
+// @Summary      Refresh the session
+// @Description  Exchanges a valid refresh token for a new access token and a new refresh token; the one sent stops working. Sending a refresh token that was already used closes every session of the user.
+// @Tags         authentication
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dtos.RefreshTokenRequestDTO  true  "Refresh token"
+// @Success      200   {object}  dtos.TokenPairDTO  "New access and refresh tokens"
+// @Failure      400   {object}  dtos.ErrorResponse  "Invalid request body"
+// @Failure      401   {object}  dtos.ErrorResponse  "Invalid, expired or revoked refresh token"
+// @Failure      500   {object}  dtos.ErrorResponse  "Error refreshing the session"
+// @Router       /auth/refresh [post]
+func (ucvc *UserCredentialValidationController) RefreshTokens(c *gin.Context) {
+	var dto dtos.RefreshTokenRequestDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utilities.RespondValidationError(c, "Invalid request body", err)
+		return
+	}
+
+	tokens, err := ucvc.Tokens.Refresh(c.Request.Context(), dto.RefreshToken, c.ClientIP())
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidToken) {
+			utilities.RespondError(c, http.StatusUnauthorized, "Invalid or expired refresh token")
+			return
+		}
+		_ = ucvc.Log.RegisterLog(c, "Error refreshing session: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error refreshing the session")
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// RevokeToken godoc
+// @Summary      Log out
+// @Description  Revokes the refresh token so the session cannot be renewed. The current access token keeps working until it expires. Unknown or already revoked tokens are accepted too.
+// @Tags         authentication
+// @Accept       json
+// @Param        body  body  dtos.RefreshTokenRequestDTO  true  "Refresh token"
+// @Success      204  "Session closed"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid request body"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error closing the session"
+// @Router       /auth/revoke [post]
+func (ucvc *UserCredentialValidationController) RevokeToken(c *gin.Context) {
+	var dto dtos.RefreshTokenRequestDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utilities.RespondValidationError(c, "Invalid request body", err)
+		return
+	}
+
+	if err := ucvc.Tokens.Revoke(c.Request.Context(), dto.RefreshToken); err != nil {
+		_ = ucvc.Log.RegisterLog(c, "Error revoking refresh token: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error closing the session")
+		return
+	}
+
+	_ = ucvc.Log.RegisterLog(c, "Session closed")
+	c.Status(http.StatusNoContent)
+}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Login successful",
-	})
+// RequestPasswordReset godoc
+// @Summary      Request a password reset
+// @Description  Emails the user a link to choose a new password, valid for one hour and only once. The response is the same whether or not the email belongs to a user, so it cannot be used to find out which accounts exist.
+// @Tags         authentication
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dtos.PasswordResetRequestDTO  true  "User email"
+// @Success      202   {object}  models.MessageResponse  "Request accepted"
+// @Failure      400   {object}  dtos.ErrorResponse  "Invalid request body"
+// @Failure      429   {object}  dtos.ErrorResponse  "Too many requests"
+// @Failure      500   {object}  dtos.ErrorResponse  "Error requesting the password reset"
+// @Router       /auth/password-reset [post]
+func (ucvc *UserCredentialValidationController) RequestPasswordReset(c *gin.Context) {
+	var dto dtos.PasswordResetRequestDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		utilities.RespondValidationError(c, "Invalid request body", err)
+		return
+	}
+
+	if err := ucvc.Resets.RequestPasswordReset(c.Request.Context(), dto.Email); err != nil {
+		_ = ucvc.Log.RegisterLog(c, "Error requesting password reset for "+dto.Email+": "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error requesting the password reset")
+		return
+	}
+
+	_ = ucvc.Log.RegisterLog(c, "Password reset requested for "+dto.Email)
+	c.JSON(http.StatusAccepted, models.MessageResponse{Message: "If the email belongs to an active user, a reset link was sent to it"})
+}
+
+// GetPasswordReset godoc
+// @Summary      Show the password reset page
+// @Description  Target of the link in the password reset email. Shows a form that posts the new password to the same link.
+// @Tags         authentication
+// @Produce      html
+// @Param        token  query  string  true  "Signed reset token"
+// @Success      200  {string}  string  "Password form"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid or expired link"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving user"
+// @Router       /auth/password-reset/confirm [get]
+func (ucvc *UserCredentialValidationController) GetPasswordReset(c *gin.Context) {
+	if _, err := ucvc.Resets.GetUserByResetToken(c.Request.Context(), c.Query("token")); err != nil {
+		ucvc.respondPasswordResetError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(passwordResetPage(
+		"<p>Elige una nueva contraseña (mínimo "+strconv.Itoa(config.PASSWORD_MIN_LENGTH)+" caracteres).</p>"+
+			`<form method="post"><input type="password" name="password" autocomplete="new-password" required> `+
+			`<button type="submit">Guardar</button></form>`)))
+}
+
+// ResetPassword godoc
+// @Summary      Reset the password
+// @Description  Sets the new password of the user identified by the signed token and closes all of their sessions. The link stops working once used. Accepts JSON, answered with 204, or the form of the reset page, answered with an HTML page.
+// @Tags         authentication
+// @Accept       json
+// @Param        token  query  string                 true  "Signed reset token"
+// @Param        body   body   dtos.PasswordResetDTO  true  "New password"
+// @Success      204  "Password changed"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid or expired link, or invalid password"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error resetting the password"
+// @Router       /auth/password-reset/confirm [post]
+func (ucvc *UserCredentialValidationController) ResetPassword(c *gin.Context) {
+	var dto dtos.PasswordResetDTO
+	if err := c.ShouldBind(&dto); err != nil {
+		utilities.RespondValidationError(c, "Invalid request body", err)
+		return
+	}
+
+	user, err := ucvc.Resets.ResetPassword(c.Request.Context(), c.Query("token"), dto.Password)
+	if err != nil {
+		ucvc.respondPasswordResetError(c, err)
+		return
+	}
+
+	_ = ucvc.Log.RegisterSecurityEvent(c, services.SECURITY_EVENT_PASSWORD_CHANGE, user.Email,
+		"password of user "+strconv.Itoa(user.ID)+" reset from the email link")
+	_ = ucvc.Log.RegisterLog(c, "Password reset for user "+user.Email)
+	if c.ContentType() == binding.MIMEPOSTForm {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(passwordResetPage("<p>Tu contraseña fue cambiada. Ya puedes iniciar sesión.</p>")))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (ucvc *UserCredentialValidationController) respondPasswordResetError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidLinkToken):
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid or expired password reset link")
+	case errors.Is(err, services.ErrInvalidPassword):
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+	default:
+		_ = ucvc.Log.RegisterLog(c, "Error resetting password: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error resetting the password")
+	}
+}
 
+func passwordResetPage(content string) string {
+	return `<!DOCTYPE html><html lang="es"><head><meta charset="UTF-8"><title>Restablecer contraseña</title></head>` +
+		`<body style="font-family:Arial,Helvetica,sans-serif;padding:24px;">` + content + `</body></html>`
 }
@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserLogController struct {
+	Service *services.UserLogService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewUserLogController(service *services.UserLogService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *UserLogController {
+	return &UserLogController{Service: service, Auth: auth, Log: log}
+}
+
+// GetUserLogs godoc
+// @Summary      Search the history log
+// @Description  Returns a page of history log entries filtered by user, date range, endpoint and free text.
+// @Tags         logs
+// @Produce      json
+// @Param        user      query  string  false  "User email (partial match)"
+// @Param        from      query  string  false  "Start date (YYYY-MM-DD or RFC3339)"
+// @Param        to        query  string  false  "End date (YYYY-MM-DD, inclusive, or RFC3339)"
+// @Param        endpoint  query  string  false  "Endpoint, e.g. 'POST /items' (partial match)"
+// @Param        q         query  string  false  "Text to search in the log message"
+// @Param        requestId query  string  false  "X-Request-ID of the request that produced the log (exact match)"
+// @Param        page      query  int     false  "Page number (default 1)"
+// @Param        pageSize  query  int     false  "Page size, up to 200 (default 50)"
+// @Param        sortBy    query  string  false  "Sort field: id, date_time, email or endpoint (default date_time)"
+// @Param        order     query  string  false  "Sort order: asc or desc (default desc)"
+// @Success      200  {object}  dtos.PageDTO[models.UserLog]  "Page of log entries"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid filter"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error searching logs"
+// @Security     ApiKeyAuth
+// @Router       /logs [get]
+func (ulc *UserLogController) GetUserLogs(c *gin.Context) {
+	permissionId := config.PERMISSION_SEARCH_LOGS
+	if !ulc.Auth.CheckPermission(c, permissionId) {
+		_ = ulc.Log.RegisterLog(c, "Access denied for GetUserLogs")
+		return
+	}
+
+	filter := dtos.UserLogFilterDTO{
+		User:      c.Query("user"),
+		Endpoint:  c.Query("endpoint"),
+		Text:      c.Query("q"),
+		RequestID: c.Query("requestId"),
+		SortBy:    c.DefaultQuery("sortBy", "date_time"),
+		Order:     c.DefaultQuery("order", "desc"),
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = ulc.Log.RegisterLog(c, "Invalid pagination: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	filter.PaginationDTO = pagination
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, _, err := parseLogDate(fromStr)
+		if err != nil {
+			_ = ulc.Log.RegisterLog(c, "Invalid from date: "+fromStr)
+			utilities.RespondError(c, http.StatusBadRequest, "Invalid 'from' date. Use YYYY-MM-DD or RFC3339")
+			return
+		}
+		filter.From = &from
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		to, dateOnly, err := parseLogDate(toStr)
+		if err != nil {
+			_ = ulc.Log.RegisterLog(c, "Invalid to date: "+toStr)
+			utilities.RespondError(c, http.StatusBadRequest, "Invalid 'to' date. Use YYYY-MM-DD or RFC3339")
+			return
+		}
+		if dateOnly {
+			to = to.Add(24*time.Hour - time.Nanosecond)
+		}
+		filter.To = &to
+	}
+
+	page, err := ulc.Service.SearchUserLogs(c.Request.Context(), filter)
+	if err != nil {
+		_ = ulc.Log.RegisterLog(c, "Error searching history logs: "+err.Error())
+		if errors.Is(err, services.ErrInvalidLogFilter) {
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error searching logs")
+		return
+	}
+
+	_ = ulc.Log.RegisterLog(c, "Successfully searched history logs")
+	c.JSON(http.StatusOK, page)
+}
+
+// parseLogDate acepta fechas YYYY-MM-DD o RFC3339 e indica si se recibió solo la fecha.
+func parseLogDate(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t, false, err
+}
+
+// parseCSVExportFilter lee el rango from/to de las exportaciones CSV; una fecha sin hora en to
+// incluye todo ese día.
+func parseCSVExportFilter(c *gin.Context, log *utilities.LogUtil) (dtos.CSVExportFilterDTO, bool) {
+	var filter dtos.CSVExportFilterDTO
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, _, err := parseLogDate(fromStr)
+		if err != nil {
+			_ = log.RegisterLog(c, "Invalid from date: "+fromStr)
+			utilities.RespondError(c, http.StatusBadRequest, "Invalid 'from' date. Use YYYY-MM-DD or RFC3339")
+			return filter, false
+		}
+		filter.From = &from
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		to, dateOnly, err := parseLogDate(toStr)
+		if err != nil {
+			_ = log.RegisterLog(c, "Invalid to date: "+toStr)
+			utilities.RespondError(c, http.StatusBadRequest, "Invalid 'to' date. Use YYYY-MM-DD or RFC3339")
+			return filter, false
+		}
+		if dateOnly {
+			to = to.Add(24*time.Hour - time.Nanosecond)
+		}
+		filter.To = &to
+	}
+	return filter, true
+}
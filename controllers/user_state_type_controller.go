@@ -1,12 +1,16 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
 	"totesbackend/services"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type UserStateTypeController struct {
@@ -27,9 +31,9 @@ func NewUserStateTypeController(service *services.UserStateTypeService, auth *ut
 // @Produce      json
 // @Param        id      path     string  true  "User State Type ID"
 // @Success      200     {object}  models.UserStateType  "User State Type details"
-// @Failure      403     {object}  models.ErrorResponse  "Permission denied"
-// @Failure      404     {object}  models.ErrorResponse  "User State Type not found"
-// @Failure      500     {object}  models.ErrorResponse  "Internal server error"
+// @Failure      403     {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404     {object}  dtos.ErrorResponse  "User State Type not found"
+// @Failure      500     {object}  dtos.ErrorResponse  "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /user-state-types/{id} [get]
 func (ustc *UserStateTypeController) GetUserStateTypeByID(c *gin.Context) {
@@ -42,14 +46,9 @@ func (ustc *UserStateTypeController) GetUserStateTypeByID(c *gin.Context) {
 
 	id := c.Param("id")
 
-	if ustc.Log.RegisterLog(c, "Attempting to retrieve user state type with ID: "+id) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
-	userStateType, err := ustc.Service.GetUserStateTypeByID(id)
+	userStateType, err := ustc.Service.GetUserStateTypeByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User State Type not found"})
+		utilities.RespondError(c, http.StatusNotFound, "User State Type not found")
 		return
 	}
 
@@ -64,9 +63,11 @@ func (ustc *UserStateTypeController) GetUserStateTypeByID(c *gin.Context) {
 // @Tags         user_state_types
 // @Accept       json
 // @Produce      json
-// @Success      200     {array}   models.UserStateType  "List of User State Types"
-// @Failure      403     {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500     {object}  models.ErrorResponse  "Internal server error"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200     {object}  dtos.PageDTO[models.UserStateType]  "List of User State Types"
+// @Failure      403     {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500     {object}  dtos.ErrorResponse  "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /user-state-types [get]
 func (ustc *UserStateTypeController) GetAllUserStateTypes(c *gin.Context) {
@@ -77,18 +78,162 @@ func (ustc *UserStateTypeController) GetAllUserStateTypes(c *gin.Context) {
 		return
 	}
 
-	if ustc.Log.RegisterLog(c, "Attempting to retrieve all user state types") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = ustc.Log.RegisterLog(c, "Invalid pagination for GetAllUserStateTypes: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	userStateTypes, err := ustc.Service.GetAllUserStateTypes()
+	userStateTypes, total, err := ustc.Service.GetAllUserStateTypes(c.Request.Context(), pagination)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving User State Types"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving User State Types")
 		return
 	}
 
 	_ = ustc.Log.RegisterLog(c, "Successfully retrieved all user state types")
 
-	c.JSON(http.StatusOK, userStateTypes)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(userStateTypes, pagination, total))
+}
+
+// CreateUserStateType godoc
+// @Summary      Create a user state type
+// @Description  Adds a user state such as "Suspended" or "On vacation". allows_login decides whether users in that state can log in. Names are unique regardless of case.
+// @Tags         user_state_types
+// @Accept       json
+// @Produce      json
+// @Param        userStateType  body      dtos.UserStateTypeDTO  true  "User state type"
+// @Success      201  {object}  models.UserStateType  "Created user state type"
+// @Failure      400  {object}  dtos.ErrorResponse    "Invalid request data"
+// @Failure      403  {object}  dtos.ErrorResponse    "Permission denied"
+// @Failure      409  {object}  dtos.ErrorResponse    "Name already in use"
+// @Failure      500  {object}  dtos.ErrorResponse    "Internal server error"
+// @Security     ApiKeyAuth
+// @Router       /user-state-types [post]
+func (ustc *UserStateTypeController) CreateUserStateType(c *gin.Context) {
+	permissionId := config.PERMISSION_CREATE_USER_STATE_TYPE
+
+	if !ustc.Auth.CheckPermission(c, permissionId) {
+		_ = ustc.Log.RegisterLog(c, "Access denied for CreateUserStateType")
+		return
+	}
+
+	var dto dtos.UserStateTypeDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = ustc.Log.RegisterLog(c, "Invalid user state type data: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	userStateType, err := ustc.Service.CreateUserStateType(c.Request.Context(), dto)
+	if err != nil {
+		_ = ustc.Log.RegisterLog(c, "Error creating user state type: "+err.Error())
+		ustc.respondWriteError(c, err, "Error creating User State Type")
+		return
+	}
+
+	_ = ustc.Log.RegisterLog(c, "Successfully created user state type with ID: "+strconv.Itoa(userStateType.ID))
+	c.JSON(http.StatusCreated, userStateType)
+}
+
+// UpdateUserStateType godoc
+// @Summary      Update a user state type
+// @Description  Renames a user state type or changes whether it allows login. Built-in states (Active, Inactive) cannot be changed.
+// @Tags         user_state_types
+// @Accept       json
+// @Produce      json
+// @Param        id             path      int                    true  "User State Type ID"
+// @Param        userStateType  body      dtos.UserStateTypeDTO  true  "User state type"
+// @Success      200  {object}  models.UserStateType  "Updated user state type"
+// @Failure      400  {object}  dtos.ErrorResponse    "Invalid request data"
+// @Failure      403  {object}  dtos.ErrorResponse    "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse    "User State Type not found"
+// @Failure      409  {object}  dtos.ErrorResponse    "Built-in state or name already in use"
+// @Failure      500  {object}  dtos.ErrorResponse    "Internal server error"
+// @Security     ApiKeyAuth
+// @Router       /user-state-types/{id} [put]
+func (ustc *UserStateTypeController) UpdateUserStateType(c *gin.Context) {
+	permissionId := config.PERMISSION_UPDATE_USER_STATE_TYPE
+
+	if !ustc.Auth.CheckPermission(c, permissionId) {
+		_ = ustc.Log.RegisterLog(c, "Access denied for UpdateUserStateType")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = ustc.Log.RegisterLog(c, "Invalid user state type ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid User State Type ID")
+		return
+	}
+
+	var dto dtos.UserStateTypeDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = ustc.Log.RegisterLog(c, "Invalid user state type data: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	userStateType, err := ustc.Service.UpdateUserStateType(c.Request.Context(), id, dto)
+	if err != nil {
+		_ = ustc.Log.RegisterLog(c, "Error updating user state type with ID "+c.Param("id")+": "+err.Error())
+		ustc.respondWriteError(c, err, "Error updating User State Type")
+		return
+	}
+
+	_ = ustc.Log.RegisterLog(c, "Successfully updated user state type with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, userStateType)
+}
+
+// DeleteUserStateType godoc
+// @Summary      Delete a user state type
+// @Description  Deletes a user state type that is not built-in and that no user has.
+// @Tags         user_state_types
+// @Produce      json
+// @Param        id  path  int  true  "User State Type ID"
+// @Success      200  {object}  models.MessageResponse  "User state type deleted"
+// @Failure      400  {object}  dtos.ErrorResponse      "Invalid User State Type ID"
+// @Failure      403  {object}  dtos.ErrorResponse      "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse      "User State Type not found"
+// @Failure      409  {object}  dtos.ErrorResponse      "Built-in state or state in use"
+// @Failure      500  {object}  dtos.ErrorResponse      "Internal server error"
+// @Security     ApiKeyAuth
+// @Router       /user-state-types/{id} [delete]
+func (ustc *UserStateTypeController) DeleteUserStateType(c *gin.Context) {
+	permissionId := config.PERMISSION_DELETE_USER_STATE_TYPE
+
+	if !ustc.Auth.CheckPermission(c, permissionId) {
+		_ = ustc.Log.RegisterLog(c, "Access denied for DeleteUserStateType")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = ustc.Log.RegisterLog(c, "Invalid user state type ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid User State Type ID")
+		return
+	}
+
+	if err := ustc.Service.DeleteUserStateType(c.Request.Context(), id); err != nil {
+		_ = ustc.Log.RegisterLog(c, "Error deleting user state type with ID "+c.Param("id")+": "+err.Error())
+		ustc.respondWriteError(c, err, "Error deleting User State Type")
+		return
+	}
+
+	_ = ustc.Log.RegisterLog(c, "Successfully deleted user state type with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, gin.H{"message": "User State Type deleted successfully"})
+}
+
+func (ustc *UserStateTypeController) respondWriteError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utilities.RespondError(c, http.StatusNotFound, "User State Type not found")
+	case errors.Is(err, services.ErrInvalidUserStateTypeName):
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrUserStateTypeNameTaken), errors.Is(err, services.ErrUserStateTypeBuiltIn),
+		errors.Is(err, services.ErrUserStateTypeInUse):
+		utilities.RespondError(c, http.StatusConflict, err.Error())
+	default:
+		utilities.RespondError(c, http.StatusInternalServerError, message)
+	}
 }
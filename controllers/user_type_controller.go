@@ -1,24 +1,30 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
 	"totesbackend/dtos"
+	"totesbackend/models"
 	"totesbackend/services"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type UserTypeController struct {
 	Service *services.UserTypeService
 	Auth    *utilities.AuthorizationUtil
 	Log     *utilities.LogUtil
+	Audit   *utilities.AuditUtil
 }
 
-func NewUserTypeController(service *services.UserTypeService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *UserTypeController {
-	return &UserTypeController{Service: service, Auth: auth, Log: log}
+func NewUserTypeController(service *services.UserTypeService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil, audit *utilities.AuditUtil) *UserTypeController {
+	return &UserTypeController{Service: service, Auth: auth, Log: log, Audit: audit}
 }
 
 // GetUserTypeByID godoc
@@ -29,10 +35,10 @@ func NewUserTypeController(service *services.UserTypeService, auth *utilities.Au
 // @Produce      json
 // @Param        id      path     int     true  "User Type ID"
 // @Success      200     {object}  dtos.UserTypeDTO  "User type information"
-// @Failure      400     {object}  models.ErrorResponse  "Invalid user type ID format"
-// @Failure      403     {object}  models.ErrorResponse  "Permission denied"
-// @Failure      404     {object}  models.ErrorResponse  "User type not found"
-// @Failure      500     {object}  models.ErrorResponse  "Internal server error"
+// @Failure      400     {object}  dtos.ErrorResponse  "Invalid user type ID format"
+// @Failure      403     {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404     {object}  dtos.ErrorResponse  "User type not found"
+// @Failure      500     {object}  dtos.ErrorResponse  "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /user-types/{id} [get]
 func (utc *UserTypeController) GetUserTypeByID(c *gin.Context) {
@@ -44,29 +50,24 @@ func (utc *UserTypeController) GetUserTypeByID(c *gin.Context) {
 
 	idParam := c.Param("id")
 
-	if utc.Log.RegisterLog(c, "Attempting to retrieve user type with ID: "+idParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	var id uint
 	if _, err := fmt.Sscanf(idParam, "%d", &id); err != nil {
 		_ = utc.Log.RegisterLog(c, "Invalid user type ID format: "+idParam)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user type ID"})
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid user type ID")
 		return
 	}
 
-	userType, err := utc.Service.GetUserTypeByID(id)
+	userType, err := utc.Service.GetUserTypeByID(c.Request.Context(), id)
 	if err != nil {
 		_ = utc.Log.RegisterLog(c, "User type not found with ID: "+idParam)
-		c.JSON(http.StatusNotFound, gin.H{"error": "User type not found"})
+		utilities.RespondError(c, http.StatusNotFound, "User type not found")
 		return
 	}
 
-	roleIDs, err := utc.Service.GetRolesForUserType(id)
+	roleIDs, err := utc.Service.GetRolesForUserType(c.Request.Context(), id)
 	if err != nil {
 		_ = utc.Log.RegisterLog(c, "Error retrieving roles for user type with ID: "+idParam)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving roles for user type"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving roles for user type")
 		return
 	}
 
@@ -91,9 +92,11 @@ func (utc *UserTypeController) GetUserTypeByID(c *gin.Context) {
 // @Tags         user_types
 // @Accept       json
 // @Produce      json
-// @Success      200     {array}   dtos.UserTypeDTO  "List of user types"
-// @Failure      403     {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500     {object}  models.ErrorResponse  "Error retrieving user types"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200     {object}  dtos.PageDTO[dtos.UserTypeDTO]  "List of user types"
+// @Failure      403     {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500     {object}  dtos.ErrorResponse  "Error retrieving user types"
 // @Security     ApiKeyAuth
 // @Router       /user-types [get]
 func (utc *UserTypeController) GetAllUserTypes(c *gin.Context) {
@@ -103,24 +106,26 @@ func (utc *UserTypeController) GetAllUserTypes(c *gin.Context) {
 		return
 	}
 
-	if utc.Log.RegisterLog(c, "Attempting to retrieve all user types") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = utc.Log.RegisterLog(c, "Invalid pagination for GetAllUserTypes: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	userTypes, err := utc.Service.ObtainAllUserTypes()
+	userTypes, total, err := utc.Service.ObtainAllUserTypes(c.Request.Context(), pagination)
 	if err != nil {
 		_ = utc.Log.RegisterLog(c, "Error retrieving all user types")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving user types"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving user types")
 		return
 	}
 
 	var userTypesDTO []dtos.UserTypeDTO
 	for _, userType := range userTypes {
-		roleIDs, err := utc.Service.GetRolesForUserType(userType.ID)
+		roleIDs, err := utc.Service.GetRolesForUserType(c.Request.Context(), userType.ID)
 		if err != nil {
 			_ = utc.Log.RegisterLog(c, fmt.Sprintf("Error retrieving roles for user type ID: %d", userType.ID))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving roles for user type"})
+			utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving roles for user type")
 			return
 		}
 
@@ -139,7 +144,7 @@ func (utc *UserTypeController) GetAllUserTypes(c *gin.Context) {
 	}
 
 	_ = utc.Log.RegisterLog(c, "Successfully retrieved all user types")
-	c.JSON(http.StatusOK, userTypesDTO)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(userTypesDTO, pagination, total))
 }
 
 // ExistsUserType godoc
@@ -150,9 +155,9 @@ func (utc *UserTypeController) GetAllUserTypes(c *gin.Context) {
 // @Produce      json
 // @Param        id      path     string  true  "User Type ID"
 // @Success      200     {object}  models.MessageResponse "Existence status of the user type"
-// @Failure      400     {object}  models.ErrorResponse  "Invalid user type ID"
-// @Failure      403     {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500     {object}  models.ErrorResponse  "Error checking user type existence"
+// @Failure      400     {object}  dtos.ErrorResponse  "Invalid user type ID"
+// @Failure      403     {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500     {object}  dtos.ErrorResponse  "Error checking user type existence"
 // @Security     ApiKeyAuth
 // @Router       /user-types/{id}/exists [get]
 func (utc *UserTypeController) ExistsUserType(c *gin.Context) {
@@ -164,22 +169,17 @@ func (utc *UserTypeController) ExistsUserType(c *gin.Context) {
 
 	idParam := c.Param("id")
 
-	if utc.Log.RegisterLog(c, "Attempting to check existence of user type with ID: "+idParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
-		return
-	}
-
 	var id uint
 	if _, err := fmt.Sscanf(idParam, "%d", &id); err != nil {
 		_ = utc.Log.RegisterLog(c, "Invalid user type ID format: "+idParam)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user type ID"})
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid user type ID")
 		return
 	}
 
-	exists, err := utc.Service.Exists(id)
+	exists, err := utc.Service.Exists(c.Request.Context(), id)
 	if err != nil {
 		_ = utc.Log.RegisterLog(c, "Error checking existence for user type ID: "+idParam)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking user type existence"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error checking user type existence")
 		return
 	}
 
@@ -194,10 +194,12 @@ func (utc *UserTypeController) ExistsUserType(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        id      query    string  true  "User Type ID Query"
-// @Success      200     {array}   dtos.UserTypeDTO  "List of user types matching the ID query"
-// @Failure      400     {object}  models.ErrorResponse  "Invalid query parameter"
-// @Failure      403     {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500     {object}  models.ErrorResponse  "Error searching user types"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200     {object}  dtos.PageDTO[dtos.UserTypeDTO]  "List of user types matching the ID query"
+// @Failure      400     {object}  dtos.ErrorResponse  "Invalid query parameter"
+// @Failure      403     {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500     {object}  dtos.ErrorResponse  "Error searching user types"
 // @Security     ApiKeyAuth
 // @Router       /user-types/searchByID [get]
 func (utc *UserTypeController) SearchUserTypesByID(c *gin.Context) {
@@ -209,21 +211,23 @@ func (utc *UserTypeController) SearchUserTypesByID(c *gin.Context) {
 
 	query := c.Query("id")
 
-	if utc.Log.RegisterLog(c, "Attempting to search user types by ID: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = utc.Log.RegisterLog(c, "Invalid pagination for SearchUserTypesByID: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	userTypes, err := utc.Service.SearchUserTypesByID(query)
+	userTypes, total, err := utc.Service.SearchUserTypesByID(c.Request.Context(), query, pagination)
 	if err != nil {
 		_ = utc.Log.RegisterLog(c, "Error retrieving user types by ID query: "+query)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving user types"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving user types")
 		return
 	}
 
 	var userTypesDTO []dtos.UserTypeDTO
 	for _, userType := range userTypes {
-		roleIDs, _ := utc.Service.GetRolesForUserType(userType.ID)
+		roleIDs, _ := utc.Service.GetRolesForUserType(c.Request.Context(), userType.ID)
 
 		userTypeDTO := dtos.UserTypeDTO{
 			ID:          userType.ID,
@@ -240,7 +244,7 @@ func (utc *UserTypeController) SearchUserTypesByID(c *gin.Context) {
 	}
 
 	_ = utc.Log.RegisterLog(c, "Successfully searched user types by ID query: "+query)
-	c.JSON(http.StatusOK, userTypesDTO)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(userTypesDTO, pagination, total))
 }
 
 // SearchUserTypesByName godoc
@@ -250,10 +254,12 @@ func (utc *UserTypeController) SearchUserTypesByID(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        name    query    string  true  "User Type Name Query"
-// @Success      200     {array}   dtos.UserTypeDTO  "List of user types matching the name query"
-// @Failure      400     {object}  models.ErrorResponse  "Invalid query parameter"
-// @Failure      403     {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500     {object}  models.ErrorResponse  "Error searching user types"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200     {object}  dtos.PageDTO[dtos.UserTypeDTO]  "List of user types matching the name query"
+// @Failure      400     {object}  dtos.ErrorResponse  "Invalid query parameter"
+// @Failure      403     {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500     {object}  dtos.ErrorResponse  "Error searching user types"
 // @Security     ApiKeyAuth
 // @Router       /user-types/searchByName [get]
 func (utc *UserTypeController) SearchUserTypesByName(c *gin.Context) {
@@ -265,21 +271,23 @@ func (utc *UserTypeController) SearchUserTypesByName(c *gin.Context) {
 
 	query := c.Query("name")
 
-	if utc.Log.RegisterLog(c, "Attempting to search user types by name: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = utc.Log.RegisterLog(c, "Invalid pagination for SearchUserTypesByName: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	userTypes, err := utc.Service.SearchUserTypesByName(query)
+	userTypes, total, err := utc.Service.SearchUserTypesByName(c.Request.Context(), query, pagination)
 	if err != nil {
 		_ = utc.Log.RegisterLog(c, "Error retrieving user types by name query: "+query)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving user types"})
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving user types")
 		return
 	}
 
 	var userTypesDTO []dtos.UserTypeDTO
 	for _, userType := range userTypes {
-		roleIDs, _ := utc.Service.GetRolesForUserType(userType.ID)
+		roleIDs, _ := utc.Service.GetRolesForUserType(c.Request.Context(), userType.ID)
 
 		userTypeDTO := dtos.UserTypeDTO{
 			ID:          userType.ID,
@@ -296,5 +304,250 @@ func (utc *UserTypeController) SearchUserTypesByName(c *gin.Context) {
 	}
 
 	_ = utc.Log.RegisterLog(c, "Successfully searched user types by name query: "+query)
-	c.JSON(http.StatusOK, userTypesDTO)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(userTypesDTO, pagination, total))
+}
+
+// CreateUserType godoc
+// @Summary      Create a user type
+// @Description  Adds a user type with the given roles (optional). User type names are unique, ignoring case, and every role ID must exist.
+// @Tags         user_types
+// @Accept       json
+// @Produce      json
+// @Param        userType  body      dtos.CreateUserTypeDTO  true  "User type"
+// @Success      201       {object}  dtos.UserTypeDTO  "Created user type"
+// @Failure      400       {object}  dtos.ErrorResponse  "Invalid data or unknown role IDs"
+// @Failure      403       {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      409       {object}  dtos.ErrorResponse  "User type name already in use"
+// @Failure      500       {object}  dtos.ErrorResponse  "Error creating user type"
+// @Security     ApiKeyAuth
+// @Router       /user-types [post]
+func (utc *UserTypeController) CreateUserType(c *gin.Context) {
+	permissionId := config.PERMISSION_CREATE_USER_TYPE
+	if !utc.Auth.CheckPermission(c, permissionId) {
+		_ = utc.Log.RegisterLog(c, "Access denied for CreateUserType")
+		return
+	}
+
+	var dto dtos.CreateUserTypeDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = utc.Log.RegisterLog(c, "Invalid user type data: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	userType, err := utc.Service.CreateUserType(c.Request.Context(), dto)
+	if err != nil {
+		_ = utc.Log.RegisterLog(c, "Error creating user type: "+err.Error())
+		utc.respondError(c, err, "Error creating user type")
+		return
+	}
+
+	utc.recordUserTypeChange(c, userType.ID, services.AUDIT_ACTION_CREATE, nil, userType, "user type "+userType.Name+" created")
+
+	_ = utc.Log.RegisterLog(c, fmt.Sprintf("Successfully created user type with ID: %d", userType.ID))
+	c.JSON(http.StatusCreated, newUserTypeDTO(userType))
+}
+
+// UpdateUserType godoc
+// @Summary      Update a user type
+// @Description  Changes the user type's name and description. Its roles are changed with PUT /user-types/{id}/roles.
+// @Tags         user_types
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int                     true  "User Type ID"
+// @Param        userType  body      dtos.UpdateUserTypeDTO  true  "User type"
+// @Success      200       {object}  dtos.UserTypeDTO  "Updated user type"
+// @Failure      400       {object}  dtos.ErrorResponse  "Invalid data"
+// @Failure      403       {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404       {object}  dtos.ErrorResponse  "User type not found"
+// @Failure      409       {object}  dtos.ErrorResponse  "User type name already in use"
+// @Failure      500       {object}  dtos.ErrorResponse  "Error updating user type"
+// @Security     ApiKeyAuth
+// @Router       /user-types/{id} [put]
+func (utc *UserTypeController) UpdateUserType(c *gin.Context) {
+	permissionId := config.PERMISSION_UPDATE_USER_TYPE
+	if !utc.Auth.CheckPermission(c, permissionId) {
+		_ = utc.Log.RegisterLog(c, "Access denied for UpdateUserType")
+		return
+	}
+
+	id, ok := utc.parseUserTypeID(c)
+	if !ok {
+		return
+	}
+
+	var dto dtos.UpdateUserTypeDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = utc.Log.RegisterLog(c, "Invalid user type data: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	before, err := utc.Service.GetUserTypeByID(c.Request.Context(), id)
+	if err != nil {
+		_ = utc.Log.RegisterLog(c, "Error retrieving user type with ID "+c.Param("id")+": "+err.Error())
+		utc.respondError(c, err, "Error updating user type")
+		return
+	}
+
+	userType, err := utc.Service.UpdateUserType(c.Request.Context(), id, dto)
+	if err != nil {
+		_ = utc.Log.RegisterLog(c, "Error updating user type with ID "+c.Param("id")+": "+err.Error())
+		utc.respondError(c, err, "Error updating user type")
+		return
+	}
+
+	utc.recordUserTypeChange(c, id, services.AUDIT_ACTION_UPDATE, before, userType, "user type "+c.Param("id")+" renamed from "+before.Name+" to "+userType.Name)
+
+	_ = utc.Log.RegisterLog(c, "Successfully updated user type with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, newUserTypeDTO(userType))
+}
+
+// UpdateUserTypeRoles godoc
+// @Summary      Set the roles of a user type
+// @Description  Replaces the user type's roles with the given list in one transaction: missing IDs are assigned and the ones left out are removed. If any role ID does not exist nothing changes. Users of the type get the new permissions on their next request.
+// @Tags         user_types
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int                          true  "User Type ID"
+// @Param        roles  body      dtos.UpdateUserTypeRolesDTO  true  "Role IDs"
+// @Success      200    {object}  dtos.UserTypeDTO  "User type with its new roles"
+// @Failure      400    {object}  dtos.ErrorResponse  "Invalid data or unknown role IDs"
+// @Failure      403    {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404    {object}  dtos.ErrorResponse  "User type not found"
+// @Failure      500    {object}  dtos.ErrorResponse  "Error updating user type roles"
+// @Security     ApiKeyAuth
+// @Router       /user-types/{id}/roles [put]
+func (utc *UserTypeController) UpdateUserTypeRoles(c *gin.Context) {
+	permissionId := config.PERMISSION_UPDATE_USER_TYPE_ROLES
+	if !utc.Auth.CheckPermission(c, permissionId) {
+		_ = utc.Log.RegisterLog(c, "Access denied for UpdateUserTypeRoles")
+		return
+	}
+
+	id, ok := utc.parseUserTypeID(c)
+	if !ok {
+		return
+	}
+
+	var dto dtos.UpdateUserTypeRolesDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = utc.Log.RegisterLog(c, "Invalid user type roles data: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request data", err)
+		return
+	}
+
+	before, err := utc.Service.GetUserTypeByID(c.Request.Context(), id)
+	if err != nil {
+		_ = utc.Log.RegisterLog(c, "Error retrieving user type with ID "+c.Param("id")+": "+err.Error())
+		utc.respondError(c, err, "Error updating user type roles")
+		return
+	}
+
+	userType, err := utc.Service.UpdateUserTypeRoles(c.Request.Context(), id, dto.Roles)
+	if err != nil {
+		_ = utc.Log.RegisterLog(c, "Error updating roles of user type with ID "+c.Param("id")+": "+err.Error())
+		utc.respondError(c, err, "Error updating user type roles")
+		return
+	}
+
+	utc.recordUserTypeChange(c, id, services.AUDIT_ACTION_UPDATE, before, userType,
+		fmt.Sprintf("roles of user type %s changed from [%s] to [%s]", c.Param("id"), strings.Join(newUserTypeDTO(before).Roles, ","), strings.Join(newUserTypeDTO(userType).Roles, ",")))
+
+	_ = utc.Log.RegisterLog(c, fmt.Sprintf("Successfully set %d roles on user type with ID: %s", len(userType.Roles), c.Param("id")))
+	c.JSON(http.StatusOK, newUserTypeDTO(userType))
+}
+
+// DeleteUserType godoc
+// @Summary      Delete a user type
+// @Description  Deletes a user type that no user has; change the users' type first.
+// @Tags         user_types
+// @Produce      json
+// @Param        id  path  int  true  "User Type ID"
+// @Success      200  {object}  models.MessageResponse  "User type deleted"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid user type ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "User type not found"
+// @Failure      409  {object}  dtos.ErrorResponse  "User type in use"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error deleting user type"
+// @Security     ApiKeyAuth
+// @Router       /user-types/{id} [delete]
+func (utc *UserTypeController) DeleteUserType(c *gin.Context) {
+	permissionId := config.PERMISSION_DELETE_USER_TYPE
+	if !utc.Auth.CheckPermission(c, permissionId) {
+		_ = utc.Log.RegisterLog(c, "Access denied for DeleteUserType")
+		return
+	}
+
+	id, ok := utc.parseUserTypeID(c)
+	if !ok {
+		return
+	}
+
+	before, err := utc.Service.GetUserTypeByID(c.Request.Context(), id)
+	if err != nil {
+		_ = utc.Log.RegisterLog(c, "Error retrieving user type with ID "+c.Param("id")+": "+err.Error())
+		utc.respondError(c, err, "Error deleting user type")
+		return
+	}
+
+	if err := utc.Service.DeleteUserType(c.Request.Context(), id); err != nil {
+		_ = utc.Log.RegisterLog(c, "Error deleting user type with ID "+c.Param("id")+": "+err.Error())
+		utc.respondError(c, err, "Error deleting user type")
+		return
+	}
+
+	utc.recordUserTypeChange(c, id, services.AUDIT_ACTION_DELETE, before, nil, "user type "+before.Name+" deleted")
+
+	_ = utc.Log.RegisterLog(c, "Successfully deleted user type with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, gin.H{"message": "User type deleted successfully"})
+}
+
+// recordUserTypeChange deja el cambio del tipo de usuario en la auditoría y en el registro de
+// seguridad, como los cambios de rol de un usuario.
+func (utc *UserTypeController) recordUserTypeChange(c *gin.Context, id uint, action string, before, after interface{}, detail string) {
+	userTypeID := strconv.FormatUint(uint64(id), 10)
+	if err := utc.Audit.RecordChange(c, services.AUDIT_ENTITY_USER_TYPE, userTypeID, action, before, after); err != nil {
+		_ = utc.Log.RegisterLog(c, "Error recording audit trail for user type with ID "+userTypeID+": "+err.Error())
+	}
+	_ = utc.Log.RegisterSecurityEvent(c, services.SECURITY_EVENT_ROLE_CHANGE, utilities.CurrentUser(c), detail+" by "+utilities.CurrentUser(c))
+}
+
+func (utc *UserTypeController) parseUserTypeID(c *gin.Context) (uint, bool) {
+	idParam := c.Param("id")
+
+	var id uint
+	if _, err := fmt.Sscanf(idParam, "%d", &id); err != nil {
+		_ = utc.Log.RegisterLog(c, "Invalid user type ID format: "+idParam)
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid user type ID")
+		return 0, false
+	}
+	return id, true
+}
+
+func (utc *UserTypeController) respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utilities.RespondError(c, http.StatusNotFound, "User type not found")
+	case errors.Is(err, services.ErrInvalidUserType), errors.Is(err, services.ErrRoleMissing):
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrUserTypeNameTaken), errors.Is(err, services.ErrUserTypeInUse):
+		utilities.RespondError(c, http.StatusConflict, err.Error())
+	default:
+		utilities.RespondError(c, http.StatusInternalServerError, message)
+	}
+}
+
+// newUserTypeDTO arma el UserTypeDTO de un tipo de usuario leído con sus roles.
+func newUserTypeDTO(userType *models.UserType) dtos.UserTypeDTO {
+	userTypeDTO := dtos.UserTypeDTO{
+		ID:          userType.ID,
+		Name:        userType.Name,
+		Description: userType.Description,
+		Roles:       make([]string, len(userType.Roles)),
+	}
+	for i, role := range userType.Roles {
+		userTypeDTO.Roles[i] = fmt.Sprintf("%d", role.ID)
+	}
+	return userTypeDTO
 }
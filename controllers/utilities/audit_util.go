@@ -0,0 +1,20 @@
+package utilities
+
+import (
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditUtil struct {
+	AuditService *services.AuditService
+}
+
+func NewAuditUtil(auditService *services.AuditService) *AuditUtil {
+	return &AuditUtil{AuditService: auditService}
+}
+
+// RecordChange stores the before/after snapshots of a change made by the authenticated user.
+func (a *AuditUtil) RecordChange(c *gin.Context, entity, entityID, action string, before, after interface{}) error {
+	return a.AuditService.RecordChange(c.Request.Context(), entity, entityID, action, CurrentUser(c), before, after)
+}
@@ -0,0 +1,48 @@
+package utilities
+
+import (
+	"net/http"
+	"strings"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// clave del contexto de gin con el correo del usuario autenticado
+const authUserKey = "authUser"
+
+// Authenticate validates the "Authorization: Bearer <access token>" header and stores the user's
+// email for CurrentUser. Requests without the header continue anonymously (public routes); a
+// missing or invalid token on a protected route ends in 401 at CheckPermission, while an invalid
+// token is rejected here right away.
+func Authenticate(tokens *services.TokenService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" {
+			c.Next()
+			return
+		}
+
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok {
+			c.Header("WWW-Authenticate", `Bearer`)
+			RespondError(c, http.StatusUnauthorized, "The Authorization header must be 'Bearer <access token>'")
+			return
+		}
+		claims, err := tokens.ValidateAccessToken(strings.TrimSpace(token))
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			RespondError(c, http.StatusUnauthorized, "Invalid or expired access token")
+			return
+		}
+
+		c.Set(authUserKey, claims.Email)
+		c.Request = c.Request.WithContext(services.WithUser(c.Request.Context(), claims.Email))
+		c.Next()
+	}
+}
+
+// CurrentUser devuelve el correo del usuario del token de acceso, o "" si la petición es anónima.
+func CurrentUser(c *gin.Context) string {
+	return c.GetString(authUserKey)
+}
@@ -1,14 +1,19 @@
 package utilities
 
 import (
+	"log"
 	"net/http"
+	"slices"
+	"strconv"
+	"totesbackend/config"
 	"totesbackend/services"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AuthorizationUtil struct {
-	Service *services.AuthorizationService
+	Service  *services.AuthorizationService
+	Security *services.SecurityEventService
 }
 
 func NewAuthorizationUtil(service *services.AuthorizationService) *AuthorizationUtil {
@@ -16,16 +21,32 @@ func NewAuthorizationUtil(service *services.AuthorizationService) *Authorization
 }
 
 func (u *AuthorizationUtil) CheckPermission(c *gin.Context, permissionID int) bool {
-	username := c.GetHeader("Username")
-	authResult, err := u.Service.UserHasPermission(username, permissionID)
+	// /meta/routes publica config.ROUTE_PERMISSIONS: avisa si la tabla quedó desactualizada
+	if route := c.Request.Method + " " + c.FullPath(); !slices.Contains(config.ROUTE_PERMISSIONS[route], permissionID) {
+		log.Printf("permission %d checked on %s is missing from config.ROUTE_PERMISSIONS", permissionID, route)
+	}
+
+	username := CurrentUser(c)
+	if username == "" {
+		c.Header("WWW-Authenticate", `Bearer`)
+		RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return false
+	}
+	authResult, err := u.Service.UserHasPermission(c.Request.Context(), username, permissionID)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Authorization service error"})
+		RespondError(c, http.StatusInternalServerError, "Authorization service error")
 		return false
 	}
 
 	if !authResult {
-		c.JSON(http.StatusForbidden, gin.H{"error": "User does not have permission"})
+		if u.Security != nil {
+			detail := "permission " + strconv.Itoa(permissionID) + " denied on " + c.Request.Method + " " + c.Request.URL.Path
+			if err := u.Security.RecordSecurityEvent(c.Request.Context(), services.SECURITY_EVENT_PERMISSION_DENIED, username, detail, c.ClientIP()); err != nil {
+				log.Printf("error recording permission denial for %s: %v", username, err)
+			}
+		}
+		RespondError(c, http.StatusForbidden, "User does not have permission")
 		return false
 	}
 
@@ -0,0 +1,95 @@
+package utilities
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"totesbackend/dtos"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ParseCatalogImport reads the entries of a bulk catalog import (tax or discount types).
+// The body can be a JSON array, a raw text/csv body or a multipart form with a "file" field.
+// CSV input must have a header row with the columns name, description, is_percentage and value.
+func ParseCatalogImport(c *gin.Context) ([]dtos.ImportCatalogEntryDTO, error) {
+	switch c.ContentType() {
+	case "text/csv":
+		return parseCatalogCSV(c.Request.Body)
+	case "multipart/form-data":
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return nil, errors.New("missing 'file' field in form data")
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return parseCatalogCSV(file)
+	default:
+		var entries []dtos.ImportCatalogEntryDTO
+		if err := c.ShouldBindJSON(&entries); err != nil {
+			return nil, err
+		}
+		return entries, nil
+	}
+}
+
+func parseCatalogCSV(reader io.Reader) ([]dtos.ImportCatalogEntryDTO, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, errors.New("empty or invalid CSV file")
+	}
+
+	columns := make(map[string]int)
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, required := range []string{"name", "is_percentage", "value"} {
+		if _, ok := columns[required]; !ok {
+			return nil, errors.New("missing required CSV column: " + required)
+		}
+	}
+
+	var entries []dtos.ImportCatalogEntryDTO
+	line := 1
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, errors.New("invalid CSV at line " + strconv.Itoa(line) + ": " + err.Error())
+		}
+
+		isPercentage, err := strconv.ParseBool(record[columns["is_percentage"]])
+		if err != nil {
+			return nil, errors.New("invalid is_percentage value at line " + strconv.Itoa(line))
+		}
+
+		value, err := strconv.ParseFloat(record[columns["value"]], 64)
+		if err != nil {
+			return nil, errors.New("invalid value at line " + strconv.Itoa(line))
+		}
+
+		entry := dtos.ImportCatalogEntryDTO{
+			Name:         record[columns["name"]],
+			IsPercentage: isPercentage,
+			Value:        value,
+		}
+		if index, ok := columns["description"]; ok {
+			entry.Description = record[index]
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,216 @@
+package utilities
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"totesbackend/config"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ENCODING_BROTLI = "br"
+	ENCODING_GZIP   = "gzip"
+)
+
+// encoder es lo que Compress usa de gzip.Writer y brotli.Writer.
+type encoder interface {
+	io.Writer
+	Flush() error
+	Close() error
+	Reset(w io.Writer)
+}
+
+var encoders = map[string]*sync.Pool{
+	ENCODING_GZIP: {New: func() interface{} {
+		gz, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+		return gz
+	}},
+	ENCODING_BROTLI: {New: func() interface{} {
+		return brotli.NewWriterLevel(nil, brotli.DefaultCompression)
+	}},
+}
+
+// Compress compresses responses whose Content-Type is in cfg.ContentTypes and whose body reaches
+// cfg.MinSize bytes with Brotli or gzip, whichever the client prefers in Accept-Encoding (Brotli on a
+// tie). Anything else (small bodies, images, xlsx, the /events stream) is sent as is. Streamed
+// responses are compressed as they are written.
+func Compress(cfg config.CompressionConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if c.Request.Method == http.MethodHead || encoding == "" {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{ResponseWriter: c.Writer, cfg: cfg, encoding: encoding}
+		c.Writer = writer
+		c.Next()
+		writer.finish()
+		c.Writer = writer.ResponseWriter
+	}
+}
+
+// negotiateEncoding elige entre br y gzip según los q de Accept-Encoding; * vale para las que no se
+// nombran y q=0 las rechaza. Devuelve "" si el cliente no acepta ninguna.
+func negotiateEncoding(acceptEncoding string) string {
+	weights := map[string]float64{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		params := strings.Split(part, ";")
+		encoding := strings.ToLower(strings.TrimSpace(params[0]))
+		if encoding != ENCODING_BROTLI && encoding != ENCODING_GZIP && encoding != "*" {
+			continue
+		}
+		weight := 1.0
+		for _, param := range params[1:] {
+			if q, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if value, err := strconv.ParseFloat(q, 64); err == nil {
+					weight = value
+				}
+			}
+		}
+		weights[encoding] = weight
+	}
+
+	best, bestWeight := "", 0.0
+	for _, encoding := range []string{ENCODING_BROTLI, ENCODING_GZIP} {
+		weight, ok := weights[encoding]
+		if !ok {
+			weight = weights["*"]
+		}
+		if weight > bestWeight {
+			best, bestWeight = encoding, weight
+		}
+	}
+	return best
+}
+
+// compressWriter retiene los primeros bytes hasta saber si la respuesta merece comprimirse.
+type compressWriter struct {
+	gin.ResponseWriter
+	cfg      config.CompressionConfig
+	encoding string
+	buf      []byte
+	decided  bool
+	enc      encoder
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		if !w.compressibleType() {
+			w.decide()
+		} else {
+			w.buf = append(w.buf, data...)
+			if len(w.buf) < w.cfg.MinSize {
+				return len(data), nil
+			}
+			w.decide()
+			return len(data), w.flushBuffer()
+		}
+	}
+	if w.enc != nil {
+		return w.enc.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.decided {
+		w.decide()
+		_ = w.flushBuffer()
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Flush envía lo que haya hasta el momento; lo usan las descargas que se generan por partes.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide()
+		_ = w.flushBuffer()
+	}
+	if w.enc != nil {
+		_ = w.enc.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Unwrap deja que http.ResponseController llegue a la conexión, p. ej. para quitar el WriteTimeout.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *compressWriter) Written() bool {
+	return len(w.buf) > 0 || w.ResponseWriter.Written()
+}
+
+func (w *compressWriter) compressibleType() bool {
+	contentType := w.Header().Get("Content-Type")
+	if contentType == "" {
+		return false
+	}
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	for _, allowed := range w.cfg.ContentTypes {
+		if mediaType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// decide activa la compresión si el cuerpo acumulado alcanzó el mínimo; después ya no se puede cambiar.
+func (w *compressWriter) decide() {
+	w.decided = true
+	status := w.Status()
+	if len(w.buf) < w.cfg.MinSize || !w.compressibleType() || w.Header().Get("Content-Encoding") != "" ||
+		status == http.StatusNoContent || status == http.StatusNotModified {
+		return
+	}
+
+	header := w.Header()
+	header.Set("Content-Encoding", w.encoding)
+	header.Add("Vary", "Accept-Encoding")
+	header.Del("Content-Length")
+	// el cuerpo comprimido ya no es idéntico byte a byte al que originó el ETag
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		header.Set("ETag", "W/"+etag)
+	}
+
+	w.enc = encoders[w.encoding].Get().(encoder)
+	w.enc.Reset(w.ResponseWriter)
+}
+
+func (w *compressWriter) flushBuffer() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	buf := w.buf
+	w.buf = nil
+	var err error
+	if w.enc != nil {
+		_, err = w.enc.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+func (w *compressWriter) finish() {
+	if !w.decided {
+		w.decide()
+		_ = w.flushBuffer()
+	}
+	if w.enc != nil {
+		_ = w.enc.Close()
+		encoders[w.encoding].Put(w.enc)
+		w.enc = nil
+	}
+}
@@ -0,0 +1,191 @@
+package utilities
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"totesbackend/dtos"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Códigos de error que reciben los clientes en dtos.ErrorResponse.Code.
+const (
+	ErrCodeBadRequest        = "BAD_REQUEST"
+	ErrCodeValidation        = "VALIDATION_FAILED"
+	ErrCodeUnauthorized      = "UNAUTHORIZED"
+	ErrCodeForbidden         = "FORBIDDEN"
+	ErrCodeNotFound          = "NOT_FOUND"
+	ErrCodeRouteNotFound     = "ROUTE_NOT_FOUND"
+	ErrCodeMethodNotAllowed  = "METHOD_NOT_ALLOWED"
+	ErrCodeConflict          = "CONFLICT"
+	ErrCodeVersionConflict   = "VERSION_CONFLICT"
+	ErrCodeDuplicate         = "DUPLICATE"
+	ErrCodeInsufficientStock = "INSUFFICIENT_STOCK"
+	ErrCodeReturnExceedsSold = "RETURN_EXCEEDS_SOLD"
+	ErrCodeInternal          = "INTERNAL_ERROR"
+)
+
+// APIError is attached to the gin context by the Respond* helpers and rendered by ErrorHandler.
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+	Fields  []dtos.FieldErrorDTO
+	Details any
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// RespondError aborts the request with the given status; the error code is derived from it.
+func RespondError(c *gin.Context, status int, message string) {
+	RespondErrorWithCode(c, status, codeForStatus(status), message)
+}
+
+func RespondErrorWithCode(c *gin.Context, status int, code, message string) {
+	_ = c.Error(&APIError{Status: status, Code: code, Message: message})
+	c.Abort()
+}
+
+// RespondErrorWithDetails is RespondErrorWithCode plus a details payload the client needs to act on
+// the error.
+func RespondErrorWithDetails(c *gin.Context, status int, code, message string, details any) {
+	_ = c.Error(&APIError{Status: status, Code: code, Message: message, Details: details})
+	c.Abort()
+}
+
+// RespondVersionRequired aborts with 400 when an update of a versioned entity does not say which
+// version it was based on.
+func RespondVersionRequired(c *gin.Context) {
+	_ = c.Error(&APIError{
+		Status:  http.StatusBadRequest,
+		Code:    ErrCodeValidation,
+		Message: "Invalid request data",
+		Fields:  []dtos.FieldErrorDTO{{Field: "version", Message: "is required"}},
+	})
+	c.Abort()
+}
+
+// RespondVersionConflict aborts with 409 when the record changed after the client read it.
+func RespondVersionConflict(c *gin.Context, message string) {
+	RespondErrorWithCode(c, http.StatusConflict, ErrCodeVersionConflict, message)
+}
+
+// RespondValidationError aborts with 400 and lists the offending fields when err comes from
+// request binding.
+func RespondValidationError(c *gin.Context, message string, err error) {
+	apiErr := &APIError{Status: http.StatusBadRequest, Code: ErrCodeBadRequest, Message: message}
+
+	var validationErrors validator.ValidationErrors
+	var typeError *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &validationErrors):
+		apiErr.Code = ErrCodeValidation
+		for _, fieldError := range validationErrors {
+			apiErr.Fields = append(apiErr.Fields, dtos.FieldErrorDTO{
+				Field:   fieldError.Field(),
+				Message: validationMessage(fieldError),
+			})
+		}
+	case errors.As(err, &typeError):
+		apiErr.Code = ErrCodeValidation
+		apiErr.Fields = []dtos.FieldErrorDTO{{
+			Field:   typeError.Field,
+			Message: "must be of type " + typeError.Type.String(),
+		}}
+	}
+
+	_ = c.Error(apiErr)
+	c.Abort()
+}
+
+// ErrorHandler renders the first APIError recorded during the request as a dtos.ErrorResponse.
+// Handlers that already wrote a response are left untouched.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.Writer.Written() || len(c.Errors) == 0 {
+			return
+		}
+
+		for _, ginErr := range c.Errors {
+			var apiErr *APIError
+			if errors.As(ginErr.Err, &apiErr) {
+				c.JSON(apiErr.Status, dtos.ErrorResponse{Code: apiErr.Code, Message: apiErr.Message, Fields: apiErr.Fields, Details: apiErr.Details})
+				return
+			}
+		}
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: ErrCodeInternal, Message: "Internal server error"})
+	}
+}
+
+// UseJSONFieldNames makes binding errors report the JSON name of each field instead of the
+// Go struct field name.
+func UseJSONFieldNames() {
+	if validate, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+	}
+}
+
+func NoRouteHandler(c *gin.Context) {
+	RespondErrorWithCode(c, http.StatusNotFound, ErrCodeRouteNotFound, "Route "+c.Request.Method+" "+c.Request.URL.Path+" not found")
+}
+
+func NoMethodHandler(c *gin.Context) {
+	RespondErrorWithCode(c, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed, "Method "+c.Request.Method+" not allowed on "+c.Request.URL.Path)
+}
+
+func codeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return ErrCodeBadRequest
+	case http.StatusUnauthorized:
+		return ErrCodeUnauthorized
+	case http.StatusForbidden:
+		return ErrCodeForbidden
+	case http.StatusNotFound:
+		return ErrCodeNotFound
+	case http.StatusConflict:
+		return ErrCodeConflict
+	case http.StatusMethodNotAllowed:
+		return ErrCodeMethodNotAllowed
+	default:
+		if status >= 500 {
+			return ErrCodeInternal
+		}
+		return strings.ToUpper(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+	}
+}
+
+func validationMessage(fieldError validator.FieldError) string {
+	switch fieldError.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email"
+	case "min":
+		return "must be at least " + fieldError.Param()
+	case "max":
+		return "must be at most " + fieldError.Param()
+	case "oneof":
+		return "must be one of: " + fieldError.Param()
+	default:
+		return "failed '" + fieldError.Tag() + "' validation"
+	}
+}
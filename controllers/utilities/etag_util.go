@@ -0,0 +1,104 @@
+package utilities
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// etagWriter retiene la respuesta del handler para poder calcular su ETag antes de enviarla.
+type etagWriter struct {
+	gin.ResponseWriter
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+}
+
+func (w *etagWriter) WriteHeader(code int) {
+	w.status = code
+	w.wroteHeader = true
+}
+
+func (w *etagWriter) WriteHeaderNow() {}
+
+func (w *etagWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *etagWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+func (w *etagWriter) Status() int {
+	return w.status
+}
+
+func (w *etagWriter) Size() int {
+	return w.body.Len()
+}
+
+func (w *etagWriter) Written() bool {
+	return w.body.Len() > 0
+}
+
+// ETag adds an ETag to successful GET responses and answers 304 Not Modified when it matches
+// the request's If-None-Match header. The handler still runs (permissions included); only the
+// body is saved. Streaming endpoints must not use it, since the whole response is buffered.
+func ETag() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		writer := &etagWriter{ResponseWriter: original, status: http.StatusOK}
+		c.Writer = writer
+		c.Next()
+		c.Writer = original
+
+		if writer.status != http.StatusOK || writer.body.Len() == 0 {
+			// los errores sin cuerpo los escribe ErrorHandler
+			if writer.wroteHeader || writer.body.Len() > 0 {
+				original.WriteHeader(writer.status)
+				original.WriteHeaderNow()
+				_, _ = original.Write(writer.body.Bytes())
+			}
+			return
+		}
+
+		sum := sha256.Sum256(writer.body.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		original.Header().Set("ETag", etag)
+		// el cliente puede guardar la respuesta pero debe revalidarla en cada petición
+		original.Header().Set("Cache-Control", "private, no-cache")
+
+		if etagMatches(c.GetHeader("If-None-Match"), etag) {
+			original.Header().Del("Content-Type")
+			original.WriteHeader(http.StatusNotModified)
+			original.WriteHeaderNow()
+			return
+		}
+
+		original.WriteHeader(http.StatusOK)
+		_, _ = original.Write(writer.body.Bytes())
+	}
+}
+
+// etagMatches aplica la comparación débil de If-None-Match (RFC 9110): se ignora el prefijo W/.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,16 @@
+package utilities
+
+import "strconv"
+
+// ResourceLink arma la ruta de un recurso, p. ej. ResourceLink("customers", 7) es "/customers/7".
+func ResourceLink(collection string, id int) string {
+	return "/" + collection + "/" + strconv.Itoa(id)
+}
+
+func ResourceLinks(collection string, ids []int) []string {
+	links := make([]string, len(ids))
+	for i, id := range ids {
+		links[i] = ResourceLink(collection, id)
+	}
+	return links
+}
@@ -2,6 +2,9 @@ package utilities
 
 import (
 	"errors"
+	"fmt"
+	"os"
+	"time"
 	"totesbackend/services"
 
 	"github.com/gin-gonic/gin"
@@ -9,6 +12,9 @@ import (
 
 type LogUtil struct {
 	LogService *services.UserLogService
+	// Con FailOpen, si no se puede guardar el log se escribe en stderr y la petición continúa
+	FailOpen bool
+	Security *services.SecurityEventService
 }
 
 func NewLogUtil(logService *services.UserLogService) *LogUtil {
@@ -16,15 +22,41 @@ func NewLogUtil(logService *services.UserLogService) *LogUtil {
 }
 
 func (l *LogUtil) RegisterLog(c *gin.Context, logMessage string) error {
-	userEmail := c.GetHeader("Username")
+	userEmail := CurrentUser(c)
 	if userEmail == "" {
-		return errors.New("missing Username header")
+		return errors.New("unauthenticated request")
 	}
 
-	_, err := l.LogService.CreateUserLog(userEmail, logMessage)
+	endpoint := c.FullPath()
+	if endpoint == "" {
+		endpoint = c.Request.URL.Path
+	}
+
+	_, err := l.LogService.CreateUserLog(c.Request.Context(), userEmail, c.Request.Method+" "+endpoint, logMessage)
 	if err != nil {
+		if l.FailOpen {
+			fmt.Fprintf(os.Stderr, "%s [%s] %s %s: %s (request %s, log not stored: %v)\n",
+				time.Now().Format(time.RFC3339), userEmail, c.Request.Method, endpoint, logMessage, c.Writer.Header().Get(REQUEST_ID_HEADER), err)
+			return nil
+		}
 		return err
 	}
 
 	return nil
 }
+
+// RegisterSecurityEvent stores a security-relevant event (login, permission denial, role or
+// password change) in the tamper-evident security log. userEmail is the affected account.
+func (l *LogUtil) RegisterSecurityEvent(c *gin.Context, eventType, userEmail, detail string) error {
+	if l.Security == nil {
+		return nil
+	}
+
+	err := l.Security.RecordSecurityEvent(c.Request.Context(), eventType, userEmail, detail, c.ClientIP())
+	if err != nil && l.FailOpen {
+		fmt.Fprintf(os.Stderr, "%s [%s] security event %s: %s (request %s, event not stored: %v)\n",
+			time.Now().Format(time.RFC3339), userEmail, eventType, detail, c.Writer.Header().Get(REQUEST_ID_HEADER), err)
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,68 @@
+package utilities
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+var ErrInvalidMergePatch = errors.New("the request body must be a JSON object")
+
+// BindMergePatch aplica el cuerpo de la petición como JSON merge patch (RFC 7396) sobre current y
+// deja el resultado validado en target. current es el DTO de actualización armado con el estado
+// actual de la entidad: los campos que no vienen en el parche conservan su valor y un null los
+// deja en su valor cero.
+func BindMergePatch(c *gin.Context, current, target any) error {
+	raw, err := c.GetRawData()
+	if err != nil {
+		return err
+	}
+
+	var patch map[string]any
+	if err := json.Unmarshal(raw, &patch); err != nil || patch == nil {
+		return ErrInvalidMergePatch
+	}
+
+	encoded, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	var document map[string]any
+	if err := json.Unmarshal(encoded, &document); err != nil {
+		return err
+	}
+
+	merged, err := json.Marshal(mergePatch(document, patch))
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		return err
+	}
+	return binding.Validator.ValidateStruct(target)
+}
+
+func mergePatch(target, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = make(map[string]any)
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergePatch(targetObject[key], value)
+	}
+	return targetObject
+}
@@ -0,0 +1,36 @@
+package utilities
+
+import (
+	"errors"
+	"strconv"
+	"totesbackend/dtos"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// ParsePagination lee los parámetros "page" y "pageSize" de la query. Si no vienen se
+// usa la primera página con DefaultPageSize elementos.
+func ParsePagination(c *gin.Context) (dtos.PaginationDTO, error) {
+	pagination := dtos.PaginationDTO{Page: 1, PageSize: DefaultPageSize, URL: c.Request.URL}
+
+	if value := c.Query("page"); value != "" {
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 1 {
+			return pagination, errors.New("page must be a positive integer")
+		}
+		pagination.Page = page
+	}
+	if value := c.Query("pageSize"); value != "" {
+		pageSize, err := strconv.Atoi(value)
+		if err != nil || pageSize < 1 || pageSize > MaxPageSize {
+			return pagination, errors.New("pageSize must be between 1 and " + strconv.Itoa(MaxPageSize))
+		}
+		pagination.PageSize = pageSize
+	}
+	return pagination, nil
+}
@@ -0,0 +1,118 @@
+package utilities
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+	"totesbackend/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// rateLimiter guarda un token bucket por cliente: cada uno empieza lleno con limit tokens y recupera
+// limit tokens por window, así se admiten ráfagas cortas sin pasar del promedio.
+type rateLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{limit: limit, window: window, buckets: make(map[string]*tokenBucket), lastSweep: time.Now()}
+}
+
+// take consume un token de key; si no queda ninguno devuelve false y cuánto falta para el siguiente.
+func (l *rateLimiter) take(key string, now time.Time) (bool, time.Duration) {
+	rate := float64(l.limit) / l.window.Seconds()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// un bucket sin uso durante una ventana ya está lleno y es igual a no tenerlo; se borran de vez en
+	// cuando para que el mapa no crezca sin límite
+	if now.Sub(l.lastSweep) > l.window {
+		for k, bucket := range l.buckets {
+			if now.Sub(bucket.updated) >= l.window {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(l.limit), updated: now}
+		l.buckets[key] = bucket
+	}
+	bucket.tokens = math.Min(float64(l.limit), bucket.tokens+now.Sub(bucket.updated).Seconds()*rate)
+	bucket.updated = now
+	if bucket.tokens < 1 {
+		return false, time.Duration((1 - bucket.tokens) / rate * float64(time.Second))
+	}
+	bucket.tokens--
+	return true, 0
+}
+
+// rateLimitKey identifica al cliente por el usuario autenticado y, en las peticiones sin token, por la
+// IP; así los usuarios detrás de una misma IP no comparten el límite.
+func rateLimitKey(c *gin.Context) string {
+	if user := CurrentUser(c); user != "" {
+		return "user:" + user
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func respondTooManyRequests(c *gin.Context, retryAfter time.Duration) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	RespondError(c, http.StatusTooManyRequests, "Too many requests, try again later")
+}
+
+// RateLimit allows each client at most limit requests per window on the routes it wraps, refilling
+// them gradually, and answers 429 Too Many Requests, with Retry-After, past that. Buckets are kept in
+// memory, so with several instances the effective limit is per instance.
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	limiter := newRateLimiter(limit, window)
+	return func(c *gin.Context) {
+		if ok, retryAfter := limiter.take(rateLimitKey(c), time.Now()); !ok {
+			respondTooManyRequests(c, retryAfter)
+			return
+		}
+		c.Next()
+	}
+}
+
+// GlobalRateLimit applies cfg.Requests per cfg.Window to every request and, on the routes listed in
+// cfg.Routes, that stricter limit as well. It must run after Authenticate so that authenticated
+// clients are limited by user instead of by IP.
+func GlobalRateLimit(cfg config.RateLimitConfig) gin.HandlerFunc {
+	global := newRateLimiter(cfg.Requests, cfg.Window)
+	routes := make(map[string]*rateLimiter, len(cfg.Routes))
+	for route, limit := range cfg.Routes {
+		routes[route] = newRateLimiter(limit, cfg.Window)
+	}
+
+	return func(c *gin.Context) {
+		key := rateLimitKey(c)
+		now := time.Now()
+		if limiter, ok := routes[c.Request.Method+" "+c.FullPath()]; ok {
+			if ok, retryAfter := limiter.take(key, now); !ok {
+				respondTooManyRequests(c, retryAfter)
+				return
+			}
+		}
+		if ok, retryAfter := global.take(key, now); !ok {
+			respondTooManyRequests(c, retryAfter)
+			return
+		}
+		c.Next()
+	}
+}
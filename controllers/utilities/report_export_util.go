@@ -0,0 +1,323 @@
+package utilities
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ReportFormatJSON = "json"
+	ReportFormatCSV  = "csv"
+	ReportFormatXLSX = "xlsx"
+
+	xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+	// Cada cuántas filas se vacía el buffer hacia el cliente.
+	reportFlushEvery = 500
+)
+
+// ReportTable describes a report as a header plus a row producer. Rows calls write once per row,
+// so large result sets can be streamed straight from the database to the response.
+type ReportTable struct {
+	Name   string
+	Header []string
+	Rows   func(write func(row []interface{}) error) error
+}
+
+// ReportFormat resolves the output format requested by the client. The "format" query
+// parameter takes precedence over the Accept header; JSON is the default.
+func ReportFormat(c *gin.Context) (string, error) {
+	if format := strings.ToLower(c.Query("format")); format != "" {
+		switch format {
+		case ReportFormatJSON, ReportFormatCSV, ReportFormatXLSX:
+			return format, nil
+		default:
+			return "", errors.New("unsupported format '" + format + "'. Use json, csv or xlsx")
+		}
+	}
+
+	accept := c.GetHeader("Accept")
+	switch {
+	case strings.Contains(accept, xlsxContentType):
+		return ReportFormatXLSX, nil
+	case strings.Contains(accept, "text/csv"):
+		return ReportFormatCSV, nil
+	default:
+		return ReportFormatJSON, nil
+	}
+}
+
+// WriteReportTable streams the table to the response as CSV or XLSX. Once the first bytes
+// are written the status can no longer change, so errors are only returned to be logged.
+func WriteReportTable(c *gin.Context, format string, table ReportTable) error {
+	ClearWriteDeadline(c)
+	filename := table.Name + "-" + time.Now().Format("20060102-150405")
+
+	switch format {
+	case ReportFormatCSV:
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`.csv"`)
+		c.Status(http.StatusOK)
+		return writeReportCSV(c.Writer, table)
+	case ReportFormatXLSX:
+		c.Header("Content-Type", xlsxContentType)
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`.xlsx"`)
+		c.Status(http.StatusOK)
+		return writeReportXLSX(c.Writer, table)
+	default:
+		return errors.New("unsupported export format: " + format)
+	}
+}
+
+// ClearWriteDeadline lifts SERVER_WRITE_TIMEOUT for this response, for downloads and streams
+// that are written bit by bit and may take longer. The client disconnecting still ends them.
+func ClearWriteDeadline(c *gin.Context) {
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		log.Printf("could not lift the write deadline for %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+	}
+}
+
+// ExportColumn is a column the client can pick in an export; Value reads it from each record.
+type ExportColumn[T any] struct {
+	Name  string
+	Value func(T) interface{}
+}
+
+// ExportTable builds a table with the columns listed in the "columns" query parameter, comma
+// separated and in the given order; without it every column is exported. stream walks the records.
+func ExportTable[T any](c *gin.Context, name string, columns []ExportColumn[T], stream func(fn func(T) error) error) (ReportTable, error) {
+	selected := columns
+	if param := strings.TrimSpace(c.Query("columns")); param != "" {
+		byName := make(map[string]ExportColumn[T], len(columns))
+		names := make([]string, len(columns))
+		for i, column := range columns {
+			byName[column.Name] = column
+			names[i] = column.Name
+		}
+
+		selected = nil
+		for _, requested := range strings.Split(param, ",") {
+			column, ok := byName[strings.TrimSpace(requested)]
+			if !ok {
+				return ReportTable{}, errors.New("unknown column '" + strings.TrimSpace(requested) + "'. Valid columns: " + strings.Join(names, ", "))
+			}
+			selected = append(selected, column)
+		}
+	}
+
+	header := make([]string, len(selected))
+	for i, column := range selected {
+		header[i] = column.Name
+	}
+	return ReportTable{
+		Name:   name,
+		Header: header,
+		Rows: func(write func(row []interface{}) error) error {
+			row := make([]interface{}, len(selected))
+			return stream(func(record T) error {
+				for i, column := range selected {
+					row[i] = column.Value(record)
+				}
+				return write(row)
+			})
+		},
+	}, nil
+}
+
+func writeReportCSV(w gin.ResponseWriter, table ReportTable) error {
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.Write(table.Header); err != nil {
+		return err
+	}
+
+	count := 0
+	record := make([]string, 0, len(table.Header))
+	err := table.Rows(func(row []interface{}) error {
+		record = record[:0]
+		for _, value := range row {
+			record = append(record, formatReportValue(value))
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+		count++
+		if count%reportFlushEvery == 0 {
+			csvWriter.Flush()
+			w.Flush()
+		}
+		return csvWriter.Error()
+	})
+	if err != nil {
+		return err
+	}
+
+	csvWriter.Flush()
+	w.Flush()
+	return csvWriter.Error()
+}
+
+// writeReportXLSX escribe un libro mínimo de una sola hoja directamente sobre el zip de la
+// respuesta, usando celdas inlineStr para no tener que construir la tabla de strings compartidos.
+func writeReportXLSX(w gin.ResponseWriter, table ReportTable) error {
+	zipWriter := zip.NewWriter(w)
+
+	staticParts := []struct{ name, content string }{
+		{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="` + xlsxEscape(xlsxSheetName(table.Name)) + `" sheetId="1" r:id="rId1"/></sheets>` +
+			`</workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`},
+	}
+	for _, part := range staticParts {
+		partWriter, err := zipWriter.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(partWriter, part.content); err != nil {
+			return err
+		}
+	}
+
+	sheet, err := zipWriter.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(sheet, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`+
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return err
+	}
+
+	header := make([]interface{}, len(table.Header))
+	for i, column := range table.Header {
+		header[i] = column
+	}
+	if err := writeXLSXRow(sheet, header); err != nil {
+		return err
+	}
+
+	count := 0
+	err = table.Rows(func(row []interface{}) error {
+		if err := writeXLSXRow(sheet, row); err != nil {
+			return err
+		}
+		count++
+		if count%reportFlushEvery == 0 {
+			if err := zipWriter.Flush(); err != nil {
+				return err
+			}
+			w.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	if err := zipWriter.Close(); err != nil {
+		return err
+	}
+	w.Flush()
+	return nil
+}
+
+func writeXLSXRow(w io.Writer, row []interface{}) error {
+	var b strings.Builder
+	b.WriteString("<row>")
+	for _, value := range row {
+		if p, ok := value.(*float64); ok {
+			if p == nil {
+				value = nil
+			} else {
+				value = *p
+			}
+		}
+		if p, ok := value.(*time.Time); ok && p == nil {
+			value = nil
+		}
+		switch v := value.(type) {
+		case int, int32, int64, float32, float64:
+			b.WriteString(`<c t="n"><v>` + formatReportValue(v) + `</v></c>`)
+		case nil:
+			b.WriteString(`<c/>`)
+		default:
+			b.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">` + xlsxEscape(formatReportValue(v)) + `</t></is></c>`)
+		}
+	}
+	b.WriteString("</row>")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func formatReportValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case *float64:
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*v, 'f', -1, 64)
+	case *string:
+		if v == nil {
+			return ""
+		}
+		return *v
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func xlsxEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// Excel limita los nombres de hoja a 31 caracteres.
+func xlsxSheetName(name string) string {
+	if len(name) > 31 {
+		return name[:31]
+	}
+	return name
+}
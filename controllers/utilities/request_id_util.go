@@ -0,0 +1,55 @@
+package utilities
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const REQUEST_ID_HEADER = "X-Request-ID"
+
+// Largo máximo de un X-Request-ID recibido; coincide con la columna request_id de los logs
+const requestIDMaxLength = 64
+
+// RequestID assigns every request an ID, reusing the incoming X-Request-ID when it is a sane
+// value (e.g. one set by a proxy or the frontend). The ID is echoed in the response header and
+// stored in the request context, so user logs, audit entries and security events record it.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(REQUEST_ID_HEADER)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		c.Header(REQUEST_ID_HEADER, requestID)
+		c.Request = c.Request.WithContext(services.WithRequestID(c.Request.Context(), requestID))
+		c.Next()
+	}
+}
+
+// newRequestID genera un UUID versión 4.
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	id := hex.EncodeToString(b)
+	return id[0:8] + "-" + id[8:12] + "-" + id[12:16] + "-" + id[16:20] + "-" + id[20:]
+}
+
+// validRequestID solo acepta IDs cortos de caracteres seguros para no guardar basura en los logs.
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > requestIDMaxLength {
+		return false
+	}
+	for _, r := range requestID {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
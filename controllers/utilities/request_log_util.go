@@ -0,0 +1,129 @@
+package utilities
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// Bytes of each body captured for redaction
+	requestLogCaptureLimit = 4096
+	// Bytes of each body kept in the log of a failed request
+	requestLogBodyLimit = 150
+)
+
+// RequestLogger records one history log entry per request with method, route, status and
+// latency. For failed requests (status >= 400) the beginning of the request and response
+// bodies is added, with passwords and tokens redacted.
+func (l *LogUtil) RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestBody := &limitedBuffer{limit: requestLogCaptureLimit}
+		if c.Request.Body != nil {
+			c.Request.Body = readCloser{Reader: io.TeeReader(c.Request.Body, requestBody), Closer: c.Request.Body}
+		}
+		responseWriter := &bodyCaptureWriter{ResponseWriter: c.Writer, body: &limitedBuffer{limit: requestLogCaptureLimit}}
+		c.Writer = responseWriter
+
+		c.Next()
+
+		endpoint := c.FullPath()
+		if endpoint == "" {
+			endpoint = c.Request.URL.Path
+		}
+		status := c.Writer.Status()
+
+		message := fmt.Sprintf("%s %s -> %d (%s)", c.Request.Method, c.Request.URL.Path, status, time.Since(start).Round(time.Millisecond))
+		if status >= 400 {
+			if body := requestBody.String(); body != "" {
+				message += " request: " + body
+			}
+			if body := responseWriter.body.String(); body != "" {
+				message += " response: " + body
+			}
+		}
+		if len(message) > 500 {
+			message = message[:500]
+		}
+
+		userEmail := CurrentUser(c)
+		if userEmail == "" {
+			userEmail = "anonymous"
+		}
+
+		// La respuesta ya se envió: si el log falla solo queda reportarlo en stderr
+		if _, err := l.LogService.CreateUserLog(c.Request.Context(), userEmail, c.Request.Method+" "+endpoint, message); err != nil {
+			fmt.Fprintf(os.Stderr, "%s [%s] %s (request %s, log not stored: %v)\n",
+				time.Now().Format(time.RFC3339), userEmail, message, c.Writer.Header().Get(REQUEST_ID_HEADER), err)
+		}
+	}
+}
+
+// limitedBuffer guarda solo los primeros limit bytes y descarta el resto.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.buf.Len(); remaining > 0 {
+		if len(p) > remaining {
+			b.buf.Write(p[:remaining])
+			b.truncated = true
+		} else {
+			b.buf.Write(p)
+		}
+	} else if len(p) > 0 {
+		b.truncated = true
+	}
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	if b.buf.Len() == 0 {
+		return ""
+	}
+	body := strings.TrimSpace(string(services.RedactJSON(b.buf.Bytes())))
+	if b.truncated || len(body) > requestLogBodyLimit {
+		if len(body) > requestLogBodyLimit {
+			body = body[:requestLogBodyLimit]
+		}
+		body += "..."
+	}
+	return body
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+type bodyCaptureWriter struct {
+	gin.ResponseWriter
+	body *limitedBuffer
+}
+
+func (w *bodyCaptureWriter) Write(p []byte) (int, error) {
+	w.body.Write(p)
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *bodyCaptureWriter) WriteString(s string) (int, error) {
+	w.body.Write([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Unwrap deja que http.ResponseController llegue a la conexión a través de este writer.
+func (w *bodyCaptureWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,295 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type WebhookController struct {
+	Service *services.WebhookService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewWebhookController(service *services.WebhookService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *WebhookController {
+	return &WebhookController{Service: service, Auth: auth, Log: log}
+}
+
+// GetWebhookSubscriptions godoc
+// @Summary      List webhook subscriptions
+// @Description  Returns the registered webhook subscriptions. Secrets are never included.
+// @Tags         webhooks
+// @Produce      json
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[models.WebhookSubscription]  "Webhook subscriptions"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid pagination"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving webhook subscriptions"
+// @Security     ApiKeyAuth
+// @Router       /webhooks [get]
+func (wc *WebhookController) GetWebhookSubscriptions(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_WEBHOOKS
+	if !wc.Auth.CheckPermission(c, permissionId) {
+		_ = wc.Log.RegisterLog(c, "Access denied for GetWebhookSubscriptions")
+		return
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = wc.Log.RegisterLog(c, "Invalid pagination for GetWebhookSubscriptions: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	subscriptions, total, err := wc.Service.GetAllSubscriptions(c.Request.Context(), pagination)
+	if err != nil {
+		_ = wc.Log.RegisterLog(c, "Error retrieving webhook subscriptions: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving webhook subscriptions")
+		return
+	}
+
+	_ = wc.Log.RegisterLog(c, "Successfully retrieved webhook subscriptions")
+	c.JSON(http.StatusOK, dtos.NewPageDTO(subscriptions, pagination, total))
+}
+
+// GetWebhookSubscriptionByID godoc
+// @Summary      Get a webhook subscription
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path  int  true  "Subscription ID"
+// @Success      200  {object}  models.WebhookSubscription  "Webhook subscription"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid subscription ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Subscription not found"
+// @Security     ApiKeyAuth
+// @Router       /webhooks/{id} [get]
+func (wc *WebhookController) GetWebhookSubscriptionByID(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_WEBHOOKS
+	if !wc.Auth.CheckPermission(c, permissionId) {
+		_ = wc.Log.RegisterLog(c, "Access denied for GetWebhookSubscriptionByID")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = wc.Log.RegisterLog(c, "Invalid webhook subscription ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid subscription ID")
+		return
+	}
+
+	subscription, err := wc.Service.GetSubscriptionByID(c.Request.Context(), id)
+	if err != nil {
+		_ = wc.Log.RegisterLog(c, "Webhook subscription not found with ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusNotFound, "Subscription not found")
+		return
+	}
+
+	_ = wc.Log.RegisterLog(c, "Successfully retrieved webhook subscription with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, subscription)
+}
+
+// CreateWebhookSubscription godoc
+// @Summary      Create a webhook subscription
+// @Description  Registers a URL that receives signed POST requests for the given event types. If no secret is sent one is generated; it is only returned in this response.
+// @Description  Event types: invoice.created, purchase_order.created, purchase_order.state_changed, appointment.created, appointment.updated, appointment.deleted, item.stock_changed, item.low_stock.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        subscription  body  dtos.CreateWebhookSubscriptionDTO  true  "Subscription data"
+// @Success      201  {object}  dtos.CreatedWebhookSubscriptionDTO  "Created subscription, including its secret"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid subscription data"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error creating webhook subscription"
+// @Security     ApiKeyAuth
+// @Router       /webhooks [post]
+func (wc *WebhookController) CreateWebhookSubscription(c *gin.Context) {
+	permissionId := config.PERMISSION_CREATE_WEBHOOK
+	if !wc.Auth.CheckPermission(c, permissionId) {
+		_ = wc.Log.RegisterLog(c, "Access denied for CreateWebhookSubscription")
+		return
+	}
+
+	var dto dtos.CreateWebhookSubscriptionDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = wc.Log.RegisterLog(c, "Invalid JSON for CreateWebhookSubscription: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request body", err)
+		return
+	}
+
+	subscription, err := wc.Service.CreateSubscription(c.Request.Context(), dto)
+	if err != nil {
+		_ = wc.Log.RegisterLog(c, "Error creating webhook subscription: "+err.Error())
+		if errors.Is(err, services.ErrInvalidWebhook) {
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error creating webhook subscription")
+		return
+	}
+
+	_ = wc.Log.RegisterLog(c, "Successfully created webhook subscription with ID: "+strconv.Itoa(subscription.ID))
+	c.JSON(http.StatusCreated, dtos.CreatedWebhookSubscriptionDTO{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		Secret:     subscription.Secret,
+		EventTypes: subscription.EventTypes,
+		Active:     subscription.Active,
+		CreatedAt:  subscription.CreatedAt,
+	})
+}
+
+// UpdateWebhookSubscription godoc
+// @Summary      Update a webhook subscription
+// @Description  Replaces the URL, event types and active flag. The secret is only changed when a new one is sent.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id            path  int                                true  "Subscription ID"
+// @Param        subscription  body  dtos.UpdateWebhookSubscriptionDTO  true  "Subscription data"
+// @Success      200  {object}  models.WebhookSubscription  "Updated subscription"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid subscription data"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Subscription not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error updating webhook subscription"
+// @Security     ApiKeyAuth
+// @Router       /webhooks/{id} [put]
+func (wc *WebhookController) UpdateWebhookSubscription(c *gin.Context) {
+	permissionId := config.PERMISSION_UPDATE_WEBHOOK
+	if !wc.Auth.CheckPermission(c, permissionId) {
+		_ = wc.Log.RegisterLog(c, "Access denied for UpdateWebhookSubscription")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = wc.Log.RegisterLog(c, "Invalid webhook subscription ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid subscription ID")
+		return
+	}
+
+	var dto dtos.UpdateWebhookSubscriptionDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = wc.Log.RegisterLog(c, "Invalid JSON for UpdateWebhookSubscription: "+err.Error())
+		utilities.RespondValidationError(c, "Invalid request body", err)
+		return
+	}
+
+	subscription, err := wc.Service.UpdateSubscription(c.Request.Context(), id, dto)
+	if err != nil {
+		_ = wc.Log.RegisterLog(c, "Error updating webhook subscription "+c.Param("id")+": "+err.Error())
+		switch {
+		case errors.Is(err, services.ErrInvalidWebhook):
+			utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utilities.RespondError(c, http.StatusNotFound, "Subscription not found")
+		default:
+			utilities.RespondError(c, http.StatusInternalServerError, "Error updating webhook subscription")
+		}
+		return
+	}
+
+	_ = wc.Log.RegisterLog(c, "Successfully updated webhook subscription with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, subscription)
+}
+
+// DeleteWebhookSubscription godoc
+// @Summary      Delete a webhook subscription
+// @Description  Deletes the subscription together with its delivery log.
+// @Tags         webhooks
+// @Param        id  path  int  true  "Subscription ID"
+// @Success      204  "Subscription deleted"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid subscription ID"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Subscription not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error deleting webhook subscription"
+// @Security     ApiKeyAuth
+// @Router       /webhooks/{id} [delete]
+func (wc *WebhookController) DeleteWebhookSubscription(c *gin.Context) {
+	permissionId := config.PERMISSION_DELETE_WEBHOOK
+	if !wc.Auth.CheckPermission(c, permissionId) {
+		_ = wc.Log.RegisterLog(c, "Access denied for DeleteWebhookSubscription")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = wc.Log.RegisterLog(c, "Invalid webhook subscription ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid subscription ID")
+		return
+	}
+
+	if err := wc.Service.DeleteSubscription(c.Request.Context(), id); err != nil {
+		_ = wc.Log.RegisterLog(c, "Error deleting webhook subscription "+c.Param("id")+": "+err.Error())
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utilities.RespondError(c, http.StatusNotFound, "Subscription not found")
+			return
+		}
+		utilities.RespondError(c, http.StatusInternalServerError, "Error deleting webhook subscription")
+		return
+	}
+
+	_ = wc.Log.RegisterLog(c, "Successfully deleted webhook subscription with ID: "+c.Param("id"))
+	c.Status(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries godoc
+// @Summary      Get the delivery log of a webhook subscription
+// @Description  Returns every delivery of the subscription, newest first, with its status (pending, succeeded or failed), attempts and last error.
+// @Tags         webhooks
+// @Produce      json
+// @Param        id        path   int  true   "Subscription ID"
+// @Param        page      query  int  false  "Page number (default 1)"
+// @Param        pageSize  query  int  false  "Page size, up to 200 (default 50)"
+// @Success      200  {object}  dtos.PageDTO[dtos.GetWebhookDeliveryDTO]  "Deliveries"
+// @Failure      400  {object}  dtos.ErrorResponse  "Invalid subscription ID or pagination"
+// @Failure      403  {object}  dtos.ErrorResponse  "Permission denied"
+// @Failure      404  {object}  dtos.ErrorResponse  "Subscription not found"
+// @Failure      500  {object}  dtos.ErrorResponse  "Error retrieving webhook deliveries"
+// @Security     ApiKeyAuth
+// @Router       /webhooks/{id}/deliveries [get]
+func (wc *WebhookController) GetWebhookDeliveries(c *gin.Context) {
+	permissionId := config.PERMISSION_VIEW_WEBHOOK_DELIVERIES
+	if !wc.Auth.CheckPermission(c, permissionId) {
+		_ = wc.Log.RegisterLog(c, "Access denied for GetWebhookDeliveries")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = wc.Log.RegisterLog(c, "Invalid webhook subscription ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusBadRequest, "Invalid subscription ID")
+		return
+	}
+
+	pagination, err := utilities.ParsePagination(c)
+	if err != nil {
+		_ = wc.Log.RegisterLog(c, "Invalid pagination for GetWebhookDeliveries: "+err.Error())
+		utilities.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, err := wc.Service.GetSubscriptionByID(c.Request.Context(), id); err != nil {
+		_ = wc.Log.RegisterLog(c, "Webhook subscription not found with ID: "+c.Param("id"))
+		utilities.RespondError(c, http.StatusNotFound, "Subscription not found")
+		return
+	}
+
+	deliveries, total, err := wc.Service.GetDeliveries(c.Request.Context(), id, pagination)
+	if err != nil {
+		_ = wc.Log.RegisterLog(c, "Error retrieving webhook deliveries: "+err.Error())
+		utilities.RespondError(c, http.StatusInternalServerError, "Error retrieving webhook deliveries")
+		return
+	}
+
+	_ = wc.Log.RegisterLog(c, "Successfully retrieved deliveries of webhook subscription "+c.Param("id"))
+	c.JSON(http.StatusOK, dtos.NewPageDTO(deliveries, pagination, total))
+}
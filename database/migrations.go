@@ -0,0 +1,595 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"totesbackend/config"
+	"totesbackend/dtos"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+// Migration es un cambio de esquema con versión. Las migraciones se aplican en orden de versión y
+// cada una se registra en schema_migrations dentro de la misma transacción que la aplica.
+// Destructive marca las que borran o transforman datos (DROP, cambios de tipo, etc.).
+type Migration struct {
+	Version     int
+	Name        string
+	Destructive bool
+	Up          func(tx *gorm.DB) error
+}
+
+// Las migraciones nuevas se agregan al final con la siguiente versión; nunca se editan las ya publicadas.
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "initial_schema",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.Item{}, &models.ItemType{},
+				&models.AdditionalExpense{}, &models.Permission{}, &models.Role{},
+				&models.UserType{}, &models.IdentifierType{}, &models.UserStateType{}, &models.Employee{}, &models.HistoricalItemPrice{},
+				&models.Comment{}, models.User{}, models.UserLog{}, &models.Customer{}, &models.Appointment{}, models.OrderStateType{}, &models.PurchaseOrder{},
+				&models.DiscountType{}, &models.TaxType{}, &models.Invoice{}, &models.InvoiceItem{}, &models.PurchaseOrderItem{}, &models.ExternalSale{},
+				&models.DailyClose{}, &models.DailyClosePayment{}, &models.AuditEntry{}, &models.SecurityEvent{})
+		},
+	},
+	{
+		Version: 2,
+		Name:    "webhooks",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.WebhookSubscription{}, &models.WebhookDelivery{})
+		},
+	},
+	{
+		Version: 3,
+		Name:    "request_ids",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.UserLog{}, &models.AuditEntry{}, &models.SecurityEvent{})
+		},
+	},
+	{
+		Version: 4,
+		Name:    "scheduled_jobs",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.ScheduledJob{})
+		},
+	},
+	{
+		Version: 5,
+		Name:    "email_messages",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.EmailMessage{})
+		},
+	},
+	{
+		Version: 6,
+		Name:    "notification_preferences",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.NotificationPreference{}, &models.EmailMessage{})
+		},
+	},
+	{
+		Version: 7,
+		Name:    "notifications",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.Notification{})
+		},
+	},
+	{
+		Version: 8,
+		Name:    "email_template_versions",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.EmailTemplateVersion{})
+		},
+	},
+	{
+		Version: 9,
+		Name:    "invoice_payment_reminders",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.Invoice{}, &models.InvoiceReminder{})
+		},
+	},
+	{
+		Version: 10,
+		Name:    "notification_deliveries",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.NotificationDelivery{})
+		},
+	},
+	{
+		Version: 11,
+		Name:    "user_state_login_flags",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&models.UserStateType{}); err != nil {
+				return err
+			}
+			// hasta ahora el login solo aceptaba el estado "Active"
+			if err := tx.Model(&models.UserStateType{}).Where("name = ?", SEED_ACTIVE_USER_STATE).
+				Update("allows_login", true).Error; err != nil {
+				return err
+			}
+			return tx.Model(&models.UserStateType{}).Where("name IN ?", seedUserStates).
+				Update("built_in", true).Error
+		},
+	},
+	{
+		Version: 12,
+		Name:    "unaccent_search",
+		Up: func(tx *gorm.DB) error {
+			// unaccent es una extensión "trusted": basta con ser dueño de la base para crearla
+			return tx.Exec("CREATE EXTENSION IF NOT EXISTS unaccent").Error
+		},
+	},
+	{
+		Version: 13,
+		Name:    "optimistic_locking",
+		Up: func(tx *gorm.DB) error {
+			// las filas existentes quedan en la versión 1 por el default de la columna
+			return tx.AutoMigrate(&models.Item{}, &models.Customer{}, &models.Appointment{}, &models.Invoice{})
+		},
+	},
+	{
+		Version: 14,
+		Name:    "archive_tables",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.ArchivedInvoice{}, &models.ArchivedAppointment{})
+		},
+	},
+	{
+		Version: 15,
+		Name:    "data_exports",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.DataExport{})
+		},
+	},
+	{
+		Version: 16,
+		Name:    "accounting_syncs",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.AccountingSync{})
+		},
+	},
+	{
+		Version: 17,
+		Name:    "customer_geocoding",
+		Up: func(tx *gorm.DB) error {
+			// amplía address para las direcciones normalizadas; las existentes las ubica el trabajo customer_geocoding
+			return tx.AutoMigrate(&models.Customer{})
+		},
+	},
+	{
+		Version: 18,
+		Name:    "ecommerce_sync",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.EcommerceListing{}, &models.EcommerceOrder{})
+		},
+	},
+	{
+		Version: 19,
+		Name:    "refresh_tokens",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.RefreshToken{})
+		},
+	},
+	{
+		Version: 20,
+		Name:    "customer_soft_delete",
+		Up: func(tx *gorm.DB) error {
+			// el documento y el correo pasan a ser únicos solo entre los clientes no borrados; el nombre
+			// de la restricción depende de la versión de GORM que creó la tabla
+			for _, constraint := range []string{"uni_customers_customer_id", "customers_customer_id_key",
+				"uni_customers_email", "customers_email_key"} {
+				if err := tx.Exec("ALTER TABLE customers DROP CONSTRAINT IF EXISTS " + constraint).Error; err != nil {
+					return err
+				}
+			}
+			return tx.AutoMigrate(&models.Customer{})
+		},
+	},
+	{
+		Version: 21,
+		Name:    "appointment_reminders",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.AppointmentReminder{})
+		},
+	},
+	{
+		Version: 22,
+		Name:    "stock_movements",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&models.StockMovement{}); err != nil {
+				return err
+			}
+			// el stock actual de cada item entra al libro como saldo inicial
+			return tx.Exec(`INSERT INTO stock_movements (item_id, delta, stock, reason, created_at)
+				SELECT id, stock, stock, ?, NOW() FROM items WHERE stock <> 0`, config.STOCK_MOVEMENT_OPENING).Error
+		},
+	},
+	{
+		Version: 23,
+		Name:    "restock_orders",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.RestockOrder{}, &models.RestockOrderItem{})
+		},
+	},
+	{
+		Version: 24,
+		Name:    "suppliers",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.Supplier{}, &models.AdditionalExpense{}, &models.RestockOrder{})
+		},
+	},
+	{
+		Version: 25,
+		Name:    "item_reorder_level",
+		Up: func(tx *gorm.DB) error {
+			// los items existentes quedan con el umbral que se usaba para todos
+			if err := tx.Exec(fmt.Sprintf("ALTER TABLE items ADD COLUMN IF NOT EXISTS reorder_level integer NOT NULL DEFAULT %d",
+				config.LOW_STOCK_THRESHOLD)).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("ALTER TABLE items ALTER COLUMN reorder_level DROP DEFAULT").Error; err != nil {
+				return err
+			}
+			return tx.AutoMigrate(&models.LowStockAlert{})
+		},
+	},
+	{
+		Version: 26,
+		Name:    "credit_notes",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.Invoice{}, &models.CreditNote{}, &models.CreditNoteItem{})
+		},
+	},
+	{
+		Version: 27,
+		Name:    "customer_item_created_at",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.Customer{}, &models.Item{})
+		},
+	},
+	{
+		Version: 28,
+		Name:    "business_hours",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.BusinessHours{})
+		},
+	},
+	{
+		Version:     29,
+		Name:        "appointment_states",
+		Destructive: true,
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&models.AppointmentStateType{}, &models.AppointmentStateChange{}); err != nil {
+				return err
+			}
+			if err := seedAppointmentStateTypes(tx); err != nil {
+				return err
+			}
+			// las citas activas quedan pendientes y las inactivas canceladas; la columna booleana se borra
+			if tx.Migrator().HasColumn("appointments", "state") {
+				if err := tx.Exec("ALTER TABLE appointments ADD COLUMN IF NOT EXISTS state_id integer").Error; err != nil {
+					return err
+				}
+				if err := tx.Exec("UPDATE appointments SET state_id = CASE WHEN state THEN ? ELSE ? END",
+					config.APPOINTMENT_STATE_PENDING, config.APPOINTMENT_STATE_CANCELLED).Error; err != nil {
+					return err
+				}
+				if err := tx.Exec("ALTER TABLE appointments DROP COLUMN state").Error; err != nil {
+					return err
+				}
+			}
+			return tx.AutoMigrate(&models.Appointment{})
+		},
+	},
+	{
+		Version: 30,
+		Name:    "quotations",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.Quotation{}, &models.QuotationItem{})
+		},
+	},
+	{
+		Version: 31,
+		Name:    "currencies",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&models.Currency{}); err != nil {
+				return err
+			}
+			if err := tx.Exec("INSERT INTO currencies (code, name, symbol, exchange_rate, updated_at) VALUES (?, ?, ?, 1, now()) ON CONFLICT DO NOTHING",
+				config.BASE_CURRENCY, "Peso colombiano", "$").Error; err != nil {
+				return err
+			}
+			// los precios, facturas y cotizaciones existentes quedan en la moneda base
+			columns := []struct{ table, column, definition string }{
+				{"items", "currency", fmt.Sprintf("varchar(3) NOT NULL DEFAULT '%s'", config.BASE_CURRENCY)},
+				{"historical_item_prices", "currency", fmt.Sprintf("varchar(3) NOT NULL DEFAULT '%s'", config.BASE_CURRENCY)},
+				{"invoices", "currency", fmt.Sprintf("varchar(3) NOT NULL DEFAULT '%s'", config.BASE_CURRENCY)},
+				{"invoices", "exchange_rate", "decimal NOT NULL DEFAULT 1"},
+				{"quotations", "currency", fmt.Sprintf("varchar(3) NOT NULL DEFAULT '%s'", config.BASE_CURRENCY)},
+			}
+			for _, c := range columns {
+				if err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s", c.table, c.column, c.definition)).Error; err != nil {
+					return err
+				}
+				if err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT", c.table, c.column)).Error; err != nil {
+					return err
+				}
+			}
+			for _, table := range []string{"items", "invoices", "quotations"} {
+				if err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT fk_%s_currency FOREIGN KEY (currency) REFERENCES currencies(code)",
+					table, table)).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version: 32,
+		Name:    "invoice_payments",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&models.Payment{}); err != nil {
+				return err
+			}
+			columns := []struct{ column, definition string }{
+				{"amount_paid", "decimal NOT NULL DEFAULT 0"},
+				{"payment_status", fmt.Sprintf("varchar(10) NOT NULL DEFAULT '%s'", config.INVOICE_PAYMENT_UNPAID)},
+			}
+			for _, c := range columns {
+				if err := tx.Exec(fmt.Sprintf("ALTER TABLE invoices ADD COLUMN IF NOT EXISTS %s %s", c.column, c.definition)).Error; err != nil {
+					return err
+				}
+				if err := tx.Exec(fmt.Sprintf("ALTER TABLE invoices ALTER COLUMN %s DROP DEFAULT", c.column)).Error; err != nil {
+					return err
+				}
+			}
+			// las facturas de contado y las marcadas como pagadas quedan saldadas
+			if err := tx.Exec("UPDATE invoices SET amount_paid = total, payment_status = ? WHERE due_date IS NULL OR paid_at IS NOT NULL",
+				config.INVOICE_PAYMENT_PAID).Error; err != nil {
+				return err
+			}
+			return tx.Exec("CREATE INDEX IF NOT EXISTS idx_invoices_payment_status ON invoices (payment_status)").Error
+		},
+	},
+	{
+		Version: 33,
+		Name:    "gateway_payments",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.GatewayPayment{})
+		},
+	},
+	{
+		Version: 34,
+		Name:    "invoice_returns",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.InvoiceReturn{}, &models.InvoiceReturnItem{})
+		},
+	},
+	{
+		Version: 35,
+		Name:    "discount_type_validity",
+		Up: func(tx *gorm.DB) error {
+			// los descuentos existentes quedan activos y sin vigencia
+			return tx.AutoMigrate(&models.DiscountType{})
+		},
+	},
+	{
+		Version: 36,
+		Name:    "tax_type_rates",
+		Up: func(tx *gorm.DB) error {
+			// los impuestos existentes quedan activos y sin tarifas anteriores
+			return tx.AutoMigrate(&models.TaxType{}, &models.TaxTypeRate{})
+		},
+	},
+	{
+		Version: 37,
+		Name:    "price_lists",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.PriceList{}, &models.PriceListItem{}, &models.Customer{})
+		},
+	},
+	{
+		Version: 38,
+		Name:    "item_images",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.ItemImage{})
+		},
+	},
+	{
+		Version: 39,
+		Name:    "item_search",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&models.Item{}); err != nil {
+				return err
+			}
+			// unaccent no es IMMUTABLE porque su resultado depende del search_path; el envoltorio fija
+			// el esquema y el diccionario para poder indexarla. pg_trgm, como unaccent, es trusted
+			statements := []string{
+				"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+				"CREATE OR REPLACE FUNCTION immutable_unaccent(text) RETURNS text LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT " +
+					"AS $$ SELECT public.unaccent('public.unaccent'::regdictionary, $1) $$",
+				"CREATE INDEX IF NOT EXISTS idx_items_name_trgm ON items USING gin (immutable_unaccent(name) gin_trgm_ops)",
+				"CREATE INDEX IF NOT EXISTS idx_items_description_trgm ON items USING gin (immutable_unaccent(description) gin_trgm_ops)",
+				"CREATE INDEX IF NOT EXISTS idx_items_sku_trgm ON items USING gin (sku gin_trgm_ops)",
+				// los roles que buscaban items por ID conservan la búsqueda
+				"INSERT INTO role_permission (role_id, permission_id) SELECT role_id, 9004 FROM role_permission " +
+					"WHERE permission_id = 9003 ON CONFLICT DO NOTHING",
+			}
+			for _, statement := range statements {
+				if err := tx.Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version: 40,
+		Name:    "customer_search",
+		Up: func(tx *gorm.DB) error {
+			// GET /customers/search (14007) reemplaza las búsquedas por ID (14006) y por apellido (14008);
+			// los roles que tenían alguna la conservan
+			return tx.Exec("INSERT INTO role_permission (role_id, permission_id) SELECT DISTINCT role_id, 14007 FROM role_permission " +
+				"WHERE permission_id IN (14006, 14008) ON CONFLICT DO NOTHING").Error
+		},
+	},
+	{
+		Version: 41,
+		Name:    "text_messages",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.TextMessage{}, &models.Customer{}, &models.AppointmentReminder{})
+		},
+	},
+	{
+		Version: 42,
+		Name:    "appointment_employees",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&models.Appointment{}); err != nil {
+				return err
+			}
+			// quien ya veía todas las citas (13002) puede ver la agenda de cualquier empleado (13014)
+			if err := tx.Where(models.Permission{ID: config.PERMISSION_GET_EMPLOYEE_APPOINTMENTS}).
+				Attrs(models.Permission{Name: "Get any employee's appointments"}).
+				FirstOrCreate(&models.Permission{}).Error; err != nil {
+				return err
+			}
+			return tx.Exec("INSERT INTO role_permission (role_id, permission_id) SELECT DISTINCT role_id, 13014 FROM role_permission " +
+				"WHERE permission_id = 13002 ON CONFLICT DO NOTHING").Error
+		},
+	},
+	{
+		Version: 43,
+		Name:    "invoice_return_refunds",
+		Up: func(tx *gorm.DB) error {
+			// las devoluciones ya registradas quedan sin medio ni fecha de reembolso: no se sabe cómo se pagaron
+			return tx.AutoMigrate(&models.InvoiceReturn{})
+		},
+	},
+}
+
+// Evita que dos instancias apliquen la misma migración a la vez.
+const migrationLockKey = 7203411
+
+var ErrDestructiveMigrationPending = errors.New("destructive migrations are pending")
+
+// MigrateDB applies every pending migration. If one of them is destructive and allowDestructive is
+// false nothing is applied and ErrDestructiveMigrationPending is returned, so the server does not
+// start against a schema it cannot safely upgrade on its own.
+func MigrateDB(allowDestructive bool) error {
+	if err := db.AutoMigrate(&models.SchemaMigration{}); err != nil {
+		return err
+	}
+
+	pending, err := pendingMigrations()
+	if err != nil {
+		return err
+	}
+
+	if !allowDestructive {
+		var destructive []string
+		for _, migration := range pending {
+			if migration.Destructive {
+				destructive = append(destructive, fmt.Sprintf("%d_%s", migration.Version, migration.Name))
+			}
+		}
+		if len(destructive) > 0 {
+			return fmt.Errorf("%w: %s (run 'migrate up --allow-destructive' or set DB_ALLOW_DESTRUCTIVE_MIGRATIONS=true)",
+				ErrDestructiveMigrationPending, strings.Join(destructive, ", "))
+		}
+	}
+
+	for _, migration := range pending {
+		if err := applyMigration(migration); err != nil {
+			return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+	}
+	return nil
+}
+
+// GetMigrationStatus lists every known migration and whether it has been applied.
+func GetMigrationStatus(conn *gorm.DB) ([]dtos.MigrationStatusDTO, error) {
+	applied, err := appliedMigrations(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]dtos.MigrationStatusDTO, len(migrations))
+	for i, migration := range migrations {
+		status[i] = dtos.MigrationStatusDTO{
+			Version:     migration.Version,
+			Name:        migration.Name,
+			Destructive: migration.Destructive,
+		}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status[i].Applied = true
+			status[i].AppliedAt = &appliedAt
+		}
+	}
+	return status, nil
+}
+
+func pendingMigrations() ([]Migration, error) {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+func appliedMigrations(conn *gorm.DB) (map[int]models.SchemaMigration, error) {
+	applied := make(map[int]models.SchemaMigration)
+	if !conn.Migrator().HasTable(&models.SchemaMigration{}) {
+		return applied, nil
+	}
+
+	var records []models.SchemaMigration
+	if err := conn.Find(&records).Error; err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+func applyMigration(migration Migration) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockKey).Error; err != nil {
+			return err
+		}
+
+		// otra instancia pudo aplicarla mientras esperábamos el lock
+		var count int64
+		if err := tx.Model(&models.SchemaMigration{}).Where("version = ?", migration.Version).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+
+		if err := migration.Up(tx); err != nil {
+			return err
+		}
+
+		log.Printf("applied migration %d_%s", migration.Version, migration.Name)
+		return tx.Create(&models.SchemaMigration{
+			Version:     migration.Version,
+			Name:        migration.Name,
+			Destructive: migration.Destructive,
+			AppliedAt:   time.Now(),
+		}).Error
+	})
+}
@@ -2,9 +2,7 @@ package database
 
 import (
 	"errors"
-	"log"
-	"os"
-	"totesbackend/models"
+	"totesbackend/config"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -18,11 +16,16 @@ func GetDB() *gorm.DB {
 }
 
 // StartPostgres inicia la conexión con PostgreSQL
-func StartPostgres() error {
-	// Obtener la URI desde la variable de entorno
-	dsn := os.Getenv("POSTGRES_URI")
-	if dsn == "" {
-		return errors.New("you must set your 'POSTGRES_URI' environmental variable")
+func StartPostgres(cfg config.DatabaseConfig) error {
+	dsn := cfg.DSN
+	if cfg.Schema != "" {
+		if err := prepareSandboxSchema(dsn, cfg.Schema); err != nil {
+			return err
+		}
+		var err error
+		if dsn, err = sandboxDSN(dsn, cfg.Schema); err != nil {
+			return err
+		}
 	}
 
 	// Conectar con PostgreSQL usando GORM
@@ -43,7 +46,13 @@ func StartPostgres() error {
 		return errors.New("can't verify a connection")
 	}
 
-	return nil
+	pool := cfg.Pool
+	sqlDB.SetMaxOpenConns(pool.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(pool.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+
+	return startReplicas(cfg)
 }
 
 // ClosePostgres cierra la conexión con la base de datos
@@ -58,16 +67,6 @@ func ClosePostgres() {
 		panic(err)
 	}
 
-}
-
-func MigrateDB() {
+	closeReplicas()
 
-	err := db.AutoMigrate(&models.Item{}, &models.ItemType{},
-		&models.AdditionalExpense{}, &models.Permission{}, &models.Role{},
-		&models.UserType{}, &models.IdentifierType{}, &models.UserStateType{}, &models.Employee{}, &models.HistoricalItemPrice{},
-		&models.Comment{}, models.User{}, models.UserLog{}, &models.Customer{}, &models.Appointment{}, models.OrderStateType{}, &models.PurchaseOrder{},
-		&models.DiscountType{}, &models.TaxType{}, &models.Invoice{}, &models.InvoiceItem{}, &models.PurchaseOrderItem{}, &models.ExternalSale{})
-	if err != nil {
-		log.Fatal("Error en la migración de la base de datos:", err)
-	}
 }
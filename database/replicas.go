@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"totesbackend/config"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+var replicaDB *gorm.DB
+var replicaConns []*sql.DB
+
+// GetReplicaDB devuelve la conexión de solo lectura, o nil si no hay réplicas configuradas.
+// Los repositorios la usan para reportes, listados y búsquedas; las escrituras van siempre a la primaria.
+func GetReplicaDB() *gorm.DB {
+	return replicaDB
+}
+
+// startReplicas abre una conexión por réplica y las reparte en round-robin detrás de un único *gorm.DB.
+func startReplicas(cfg config.DatabaseConfig) error {
+	if len(cfg.ReplicaDSNs) == 0 {
+		return nil
+	}
+
+	pool := &replicaPool{}
+	for i, dsn := range cfg.ReplicaDSNs {
+		conn, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+		if err != nil {
+			return fmt.Errorf("failed to connect to PostgreSQL replica %d", i+1)
+		}
+		sqlDB, err := conn.DB()
+		if err != nil {
+			return err
+		}
+		if err := sqlDB.Ping(); err != nil {
+			return fmt.Errorf("can't verify the connection to PostgreSQL replica %d", i+1)
+		}
+		sqlDB.SetMaxOpenConns(cfg.Pool.MaxOpenConns)
+		sqlDB.SetMaxIdleConns(cfg.Pool.MaxIdleConns)
+		sqlDB.SetConnMaxLifetime(cfg.Pool.ConnMaxLifetime)
+		sqlDB.SetConnMaxIdleTime(cfg.Pool.ConnMaxIdleTime)
+		replicaConns = append(replicaConns, sqlDB)
+	}
+	pool.conns = replicaConns
+
+	var err error
+	replicaDB, err = gorm.Open(postgres.New(postgres.Config{Conn: pool}), &gorm.Config{})
+	return err
+}
+
+// ReplicaCount devuelve cuántas réplicas hay abiertas.
+func ReplicaCount() int {
+	return len(replicaConns)
+}
+
+// PingReplica comprueba la réplica i, contando desde 0 en el orden de POSTGRES_REPLICA_URIS.
+func PingReplica(ctx context.Context, i int) error {
+	return replicaConns[i].PingContext(ctx)
+}
+
+func closeReplicas() {
+	for _, conn := range replicaConns {
+		if err := conn.Close(); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// replicaPool implementa gorm.ConnPool repartiendo cada consulta entre las réplicas.
+type replicaPool struct {
+	conns []*sql.DB
+	next  atomic.Uint32
+}
+
+func (p *replicaPool) pick() *sql.DB {
+	return p.conns[int(p.next.Add(1)-1)%len(p.conns)]
+}
+
+func (p *replicaPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.pick().PrepareContext(ctx, query)
+}
+
+func (p *replicaPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, errors.New("replicas are read-only")
+}
+
+func (p *replicaPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return p.pick().QueryContext(ctx, query, args...)
+}
+
+func (p *replicaPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return p.pick().QueryRowContext(ctx, query, args...)
+}
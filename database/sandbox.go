@@ -0,0 +1,234 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+	"totesbackend/config"
+	"totesbackend/models"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Tablas que sobreviven a un reinicio del sandbox: el esquema sigue migrado y los trabajos
+// programados conservan su estado.
+var sandboxKeptTables = []string{"schema_migrations", "scheduled_jobs"}
+
+var demoCustomers = []models.Customer{
+	{CustomerName: "Laura", LastName: "Gómez", CustomerId: "DEMO-1001", Email: "laura.gomez@example.com", PhoneNumbers: "3001234567", Address: "Calle 10 # 5-20"},
+	{CustomerName: "Andrés", LastName: "Martínez", CustomerId: "DEMO-1002", Email: "andres.martinez@example.com", PhoneNumbers: "3109876543", Address: "Carrera 7 # 45-12"},
+	{CustomerName: "Camila", LastName: "Rodríguez", CustomerId: "DEMO-1003", Email: "camila.rodriguez@example.com", PhoneNumbers: "3204567890"},
+	{CustomerName: "Ferretería El Tornillo", LastName: "S.A.S.", CustomerId: "DEMO-900123456", Email: "compras@eltornillo.example.com", IsBusiness: true, Address: "Avenida 68 # 13-40"},
+	{CustomerName: "Distribuciones Andina", LastName: "Ltda.", CustomerId: "DEMO-900654321", Email: "pedidos@andina.example.com", IsBusiness: true},
+}
+
+var demoItems = []struct {
+	Name          string
+	Description   string
+	Type          string
+	Stock         int
+	SellingPrice  float64
+	PurchasePrice float64
+}{
+	{"Taladro percutor 650W", "Taladro con maletín y juego de brocas", "Product", 12, 289000, 195000},
+	{"Juego de destornilladores x12", "Puntas planas y de estrella", "Product", 40, 59900, 32000},
+	{"Pintura vinilo blanco 1 gal", "Pintura para interiores", "Product", 25, 74900, 48000},
+	{"Cinta métrica 8 m", "", "Product", 3, 21900, 11500},
+	{"Guantes de nitrilo (caja x100)", "", "Product", 60, 34900, 19000},
+	{"Instalación de lámparas", "Por punto, incluye mano de obra", "Service", 0, 45000, 0},
+	{"Mantenimiento de herramientas", "Limpieza, lubricación y revisión", "Service", 0, 80000, 0},
+}
+
+var demoTaxTypes = []models.TaxType{
+	{Name: "IVA 19%", Description: "Impuesto al valor agregado", IsPercentage: true, Value: 19},
+}
+
+var demoDiscountTypes = []models.DiscountType{
+	{Name: "Cliente frecuente", Description: "5% para clientes frecuentes", IsPercentage: true, Value: 5},
+	{Name: "Bono $10.000", IsPercentage: false, Value: 10000},
+}
+
+// prepareSandboxSchema crea el esquema del sandbox antes de abrir el pool que trabaja sobre él.
+// unaccent y pg_trgm se crean en public para que las migraciones 12 y 39 no las dejen dentro del
+// sandbox, donde el esquema real no las vería.
+func prepareSandboxSchema(dsn, schema string) error {
+	conn, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return errors.New("failed to connect to PostgreSQL")
+	}
+	sqlDB, err := conn.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	if err := conn.Exec(`CREATE SCHEMA IF NOT EXISTS "` + schema + `"`).Error; err != nil {
+		return err
+	}
+	if err := conn.Exec("CREATE EXTENSION IF NOT EXISTS unaccent WITH SCHEMA public").Error; err != nil {
+		return err
+	}
+	return conn.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm WITH SCHEMA public").Error
+}
+
+// sandboxDSN agrega el search_path del sandbox a la cadena de conexión, en formato URL o
+// clave=valor. public queda detrás para las funciones de las extensiones.
+func sandboxDSN(dsn, schema string) (string, error) {
+	searchPath := schema + ",public"
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		parsed, err := url.Parse(dsn)
+		if err != nil {
+			return "", errors.New("POSTGRES_URI is not a valid URL")
+		}
+		query := parsed.Query()
+		query.Set("search_path", searchPath)
+		parsed.RawQuery = query.Encode()
+		return parsed.String(), nil
+	}
+	return dsn + " search_path=" + searchPath, nil
+}
+
+// SeedSandbox carga los datos base y, si el sandbox está vacío, los datos de ejemplo. Se llama al
+// arrancar en modo demo.
+func SeedSandbox(adminEmail, adminPassword string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := seedBase(tx, adminEmail, adminPassword); err != nil {
+			return err
+		}
+		var customers int64
+		if err := tx.Model(&models.Customer{}).Count(&customers).Error; err != nil {
+			return err
+		}
+		if customers > 0 {
+			return nil
+		}
+		return seedDemoData(tx, time.Now())
+	})
+}
+
+// ResetSandbox vacía todas las tablas del sandbox y vuelve a cargar los datos base y los de
+// ejemplo, en una sola transacción. conn debe apuntar al sandbox: la función se niega a
+// trabajar sobre public.
+func ResetSandbox(conn *gorm.DB, adminEmail, adminPassword string) error {
+	return conn.Transaction(func(tx *gorm.DB) error {
+		var schema string
+		if err := tx.Raw("SELECT current_schema()").Scan(&schema).Error; err != nil {
+			return err
+		}
+		if schema == "" || schema == "public" {
+			return fmt.Errorf("refusing to reset schema %q", schema)
+		}
+
+		var tables []string
+		if err := tx.Raw("SELECT tablename FROM pg_tables WHERE schemaname = ? AND tablename NOT IN ?", schema, sandboxKeptTables).
+			Scan(&tables).Error; err != nil {
+			return err
+		}
+		if len(tables) > 0 {
+			quoted := make([]string, len(tables))
+			for i, table := range tables {
+				quoted[i] = `"` + schema + `"."` + table + `"`
+			}
+			if err := tx.Exec("TRUNCATE " + strings.Join(quoted, ", ") + " RESTART IDENTITY CASCADE").Error; err != nil {
+				return err
+			}
+		}
+
+		if err := seedBase(tx, adminEmail, adminPassword); err != nil {
+			return err
+		}
+		if err := seedDemoData(tx, time.Now()); err != nil {
+			return err
+		}
+		log.Printf("sandbox schema %s reset with %d tables emptied", schema, len(tables))
+		return nil
+	})
+}
+
+// seedDemoData carga clientes, productos, impuestos, descuentos y citas de ejemplo. Las citas
+// quedan en los próximos días para que la agenda de la demo no aparezca vacía.
+func seedDemoData(tx *gorm.DB, now time.Time) error {
+	var person, business models.IdentifierType
+	if err := tx.Where("name = ?", "Cédula de Ciudadanía").First(&person).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("name = ?", "NIT").First(&business).Error; err != nil {
+		return err
+	}
+	personID, businessID := person.ID, business.ID
+
+	customers := make([]models.Customer, len(demoCustomers))
+	for i, customer := range demoCustomers {
+		customer.CustomerState = true
+		customer.IdentifierTypeID = personID
+		if customer.IsBusiness {
+			customer.IdentifierTypeID = businessID
+		}
+		customers[i] = customer
+	}
+	if err := tx.Create(&customers).Error; err != nil {
+		return err
+	}
+
+	for _, demo := range demoItems {
+		var itemType models.ItemType
+		if err := tx.Where("name = ?", demo.Type).First(&itemType).Error; err != nil {
+			return err
+		}
+		item := models.Item{
+			Name:          demo.Name,
+			Description:   demo.Description,
+			Stock:         demo.Stock,
+			SellingPrice:  demo.SellingPrice,
+			PurchasePrice: demo.PurchasePrice,
+			Currency:      config.BASE_CURRENCY,
+			ItemState:     true,
+			ReorderLevel:  config.LOW_STOCK_THRESHOLD,
+			ItemTypeID:    itemType.ID,
+		}
+		if err := tx.Omit("ItemType").Create(&item).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.HistoricalItemPrice{ItemID: item.ID, Price: item.SellingPrice, Currency: item.Currency, AddedAt: now}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.StockMovement{ItemID: item.ID, Delta: item.Stock, Stock: item.Stock,
+			Reason: config.STOCK_MOVEMENT_ITEM_CREATED, CreatedAt: now}).Error; err != nil {
+			return err
+		}
+	}
+
+	taxTypes := append([]models.TaxType(nil), demoTaxTypes...)
+	if err := tx.Create(&taxTypes).Error; err != nil {
+		return err
+	}
+	discountTypes := append([]models.DiscountType(nil), demoDiscountTypes...)
+	if err := tx.Create(&discountTypes).Error; err != nil {
+		return err
+	}
+
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for i, customer := range customers[:3] {
+		appointment := models.Appointment{
+			DateTime:         day.AddDate(0, 0, i+1).Add(time.Duration(9+2*i) * time.Hour),
+			StateID:          config.APPOINTMENT_STATE_CONFIRMED,
+			CustomerID:       customer.ID,
+			CustomerName:     customer.CustomerName,
+			IsBusiness:       customer.IsBusiness,
+			Address:          customer.Address,
+			PhoneNumbers:     customer.PhoneNumbers,
+			CustomerState:    customer.CustomerState,
+			Email:            customer.Email,
+			LastName:         customer.LastName,
+			IdentifierTypeID: customer.IdentifierTypeID,
+		}
+		if err := tx.Create(&appointment).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"totesbackend/config"
+	"totesbackend/models"
+	"totesbackend/services/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	SEED_ADMIN_ROLE      = "Administrator"
+	SEED_ADMIN_USER_TYPE = "Administrator"
+	// Estado con el que se crea el administrador; es el único del seed que permite iniciar sesión
+	SEED_ACTIVE_USER_STATE = "Active"
+)
+
+// Los IDs de los estados de orden los usa la máquina de estados de services/orderstatemachine.
+var seedOrderStates = []models.OrderStateType{
+	{ID: 1, Description: "Issued"},
+	{ID: 2, Description: "In Transit"},
+	{ID: 3, Description: "Cancelled"},
+	{ID: 4, Description: "Approved"},
+}
+
+var seedAppointmentStates = []models.AppointmentStateType{
+	{ID: config.APPOINTMENT_STATE_PENDING, Name: "pending"},
+	{ID: config.APPOINTMENT_STATE_CONFIRMED, Name: "confirmed"},
+	{ID: config.APPOINTMENT_STATE_COMPLETED, Name: "completed"},
+	{ID: config.APPOINTMENT_STATE_CANCELLED, Name: "cancelled"},
+	{ID: config.APPOINTMENT_STATE_NO_SHOW, Name: "no_show"},
+}
+
+var seedUserStates = []string{SEED_ACTIVE_USER_STATE, "Inactive"}
+
+var seedItemTypes = []string{"Product", "Service"}
+
+var seedIdentifierTypes = []string{"Cédula de Ciudadanía", "Cédula de Extranjería", "NIT", "Pasaporte"}
+
+// Seed loads the base catalogs (permissions, the administrator role and user type, user states,
+// item types, identifier types, order states and appointment states) and, when adminEmail is set, an administrator
+// user. It is idempotent: existing rows are left untouched, including the admin's password.
+func Seed(adminEmail, adminPassword string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		return seedBase(tx, adminEmail, adminPassword)
+	})
+}
+
+func seedBase(tx *gorm.DB, adminEmail, adminPassword string) error {
+	permissions := make([]models.Permission, len(seedPermissions))
+	for i, seed := range seedPermissions {
+		permissions[i] = models.Permission{ID: uint(seed.ID)}
+		if err := tx.Where(models.Permission{ID: uint(seed.ID)}).
+			Attrs(models.Permission{Name: seed.Name}).
+			FirstOrCreate(&permissions[i]).Error; err != nil {
+			return err
+		}
+	}
+
+	var role models.Role
+	if err := tx.Where(models.Role{Name: SEED_ADMIN_ROLE}).
+		Attrs(models.Role{Description: "Full access to every module"}).
+		FirstOrCreate(&role).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&role).Association("Permissions").Append(permissions); err != nil {
+		return err
+	}
+
+	var userType models.UserType
+	if err := tx.Where(models.UserType{Name: SEED_ADMIN_USER_TYPE}).
+		Attrs(models.UserType{Description: "System administrator"}).
+		FirstOrCreate(&userType).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&userType).Association("Roles").Append(&role); err != nil {
+		return err
+	}
+
+	var activeState models.UserStateType
+	for _, name := range seedUserStates {
+		var state models.UserStateType
+		if err := tx.Where(models.UserStateType{Name: name}).
+			Attrs(models.UserStateType{AllowsLogin: name == SEED_ACTIVE_USER_STATE, BuiltIn: true}).
+			FirstOrCreate(&state).Error; err != nil {
+			return err
+		}
+		if name == SEED_ACTIVE_USER_STATE {
+			activeState = state
+		}
+	}
+
+	for _, name := range seedItemTypes {
+		if err := tx.Where(models.ItemType{Name: name}).FirstOrCreate(&models.ItemType{}).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, name := range seedIdentifierTypes {
+		if err := tx.Where(models.IdentifierType{Name: name}).FirstOrCreate(&models.IdentifierType{}).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, seed := range seedOrderStates {
+		state := models.OrderStateType{ID: seed.ID}
+		if err := tx.Where(models.OrderStateType{ID: seed.ID}).
+			Attrs(models.OrderStateType{Description: seed.Description}).
+			FirstOrCreate(&state).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := seedAppointmentStateTypes(tx); err != nil {
+		return err
+	}
+
+	// se insertaron IDs explícitos: la secuencia debe quedar por encima para los próximos registros
+	for _, table := range []string{"permissions", "order_state_types", "appointment_state_types"} {
+		if err := tx.Exec(fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', 'id'), (SELECT MAX(id) FROM %s))", table, table)).Error; err != nil {
+			return err
+		}
+	}
+
+	if adminEmail == "" {
+		log.Println("SEED_ADMIN_EMAIL is not set, skipping the administrator user")
+		return nil
+	}
+	return seedAdminUser(tx, adminEmail, adminPassword, int(userType.ID), activeState.ID)
+}
+
+// seedAppointmentStateTypes también lo usa la migración que pasa las citas a estos estados.
+func seedAppointmentStateTypes(tx *gorm.DB) error {
+	for _, seed := range seedAppointmentStates {
+		state := models.AppointmentStateType{ID: seed.ID}
+		if err := tx.Where(models.AppointmentStateType{ID: seed.ID}).
+			Attrs(models.AppointmentStateType{Name: seed.Name}).
+			FirstOrCreate(&state).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func seedAdminUser(tx *gorm.DB, email, password string, userTypeID, userStateID int) error {
+	var existing models.User
+	err := tx.Where("email = ?", email).First(&existing).Error
+	if err == nil {
+		log.Printf("administrator user %s already exists", email)
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if len(password) < 8 {
+		return errors.New("SEED_ADMIN_PASSWORD must be set (at least 8 characters) to create the administrator user")
+	}
+	hashed, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	admin := models.User{
+		Email:           email,
+		Password:        hashed,
+		UserTypeID:      userTypeID,
+		UserStateTypeID: userStateID,
+	}
+	if err := tx.Omit("UserType", "UserStateType").Create(&admin).Error; err != nil {
+		return err
+	}
+	log.Printf("created administrator user %s", email)
+	return nil
+}
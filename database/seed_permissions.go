@@ -0,0 +1,229 @@
+package database
+
+import "totesbackend/config"
+
+type seedPermission struct {
+	ID   int
+	Name string
+}
+
+// seedPermissions debe incluir cada constante de config/permissions.go; el rol administrador
+// recibe todos estos permisos al ejecutar el seed.
+var seedPermissions = []seedPermission{
+	{ID: config.PERMISSION_GET_PERMISSION_BY_ID, Name: "Get permission by ID"},
+	{ID: config.PERMISSION_GET_ALL_PERMISSIONS, Name: "Get all permissions"},
+	{ID: config.PERMISSION_SEARCH_PERMISSION_BY_ID, Name: "Search permission by ID"},
+	{ID: config.PERMISSION_SEARCH_PERMISSION_BY_NAME, Name: "Search permission by name"},
+	{ID: config.PERMISSION_GET_ROLE_BY_ID, Name: "Get role by ID"},
+	{ID: config.PERMISSION_GET_ALL_ROLES, Name: "Get all roles"},
+	{ID: config.PERMISSION_GET_ALL_PERMISSIONS_OF_ROLE, Name: "Get all permissions of role"},
+	{ID: config.PERMISSION_EXIST_ROLE, Name: "Exist role"},
+	{ID: config.PERMISSION_SEARCH_ROLE_BY_NAME, Name: "Search role by name"},
+	{ID: config.PERMISSION_SEARCH_ROLE_BY_ID, Name: "Search role by ID"},
+	{ID: config.PERMISSION_GET_USER_TYPE_BY_ID, Name: "Get user type by ID"},
+	{ID: config.PERMISSION_GET_ALL_USER_TYPES, Name: "Get all user types"},
+	{ID: config.PERMISSION_EXIST_USER_TYPE, Name: "Exist user type"},
+	{ID: config.PERMISSION_SEARCH_USER_TYPES_BY_ID, Name: "Search user types by ID"},
+	{ID: config.PERMISSION_SEARCH_USER_TYPES_BY_NAME, Name: "Search user types by name"},
+	{ID: config.PERMISSION_GET_USER_BY_ID, Name: "Get user by ID"},
+	{ID: config.PERMISSION_GET_ALL_USERS, Name: "Get all users"},
+	{ID: config.PERMISSION_SEARCH_USER_BY_ID, Name: "Search user by ID"},
+	{ID: config.PERMISSION_SEARCH_USERS_BY_EMAIL, Name: "Search users by email"},
+	{ID: config.PERMISSION_UPDATE_USER_STATE, Name: "Update user state"},
+	{ID: config.PERMISSION_UPDATE_USER, Name: "Update user"},
+	{ID: config.PERMISSION_CREATE_USER, Name: "Create user"},
+	{ID: config.PERMISSION_USER_HAS_PERMISSION, Name: "User has permission"},
+	{ID: config.PERMISSION_GET_USER_STATE_TYPE_BY_ID, Name: "Get user state type by ID"},
+	{ID: config.PERMISSION_GET_ALL_USER_STATE_TYPES, Name: "Get all user state types"},
+	{ID: config.PERMISSION_CREATE_USER_STATE_TYPE, Name: "Create user state type"},
+	{ID: config.PERMISSION_UPDATE_USER_STATE_TYPE, Name: "Update user state type"},
+	{ID: config.PERMISSION_DELETE_USER_STATE_TYPE, Name: "Delete user state type"},
+	{ID: config.PERMISSION_GET_ALL_LOGS_FROM_USER, Name: "Get all logs from user"},
+	{ID: config.PERMISSION_SEARCH_LOGS, Name: "Search logs"},
+	{ID: config.PERMISSION_SEARCH_SECURITY_EVENTS, Name: "Search security events"},
+	{ID: config.PERMISSION_VERIFY_SECURITY_EVENTS, Name: "Verify security events"},
+	{ID: config.PERMISSION_GET_EMPLOYEE_BY_ID, Name: "Get employee by ID"},
+	{ID: config.PERMISSION_GET_ALL_EMPLOYEES, Name: "Get all employees"},
+	{ID: config.PERMISSION_SEARCH_EMPLOYEES_BY_NAME, Name: "Search employees by name"},
+	{ID: config.PERMISSION_CREATE_EMPLOYEE, Name: "Create employee"},
+	{ID: config.PERMISSION_UPDATE_EMPLOYEE, Name: "Update employee"},
+	{ID: config.PERMISSION_SEARCH_EMPLOYEES_BY_ID, Name: "Search employees by ID"},
+	{ID: config.PERMISSION_GET_ITEM_TYPES_BY_ID, Name: "Get item types by ID"},
+	{ID: config.PERMISSION_GET_ITEM_TYPES, Name: "Get item types"},
+	{ID: config.PERMISSION_CREATE_ITEM_TYPE, Name: "Create item type"},
+	{ID: config.PERMISSION_UPDATE_ITEM_TYPE, Name: "Update item type"},
+	{ID: config.PERMISSION_DELETE_ITEM_TYPE, Name: "Delete item type"},
+	{ID: config.PERMISSION_GET_ITEM_BY_ID, Name: "Get item by ID"},
+	{ID: config.PERMISSION_GET_ALL_ITEMS, Name: "Get all items"},
+	{ID: config.PERMISSION_SEARCH_ITEMS, Name: "Search items"},
+	{ID: config.PERMISSION_UPDATE_ITEM_STATE, Name: "Update item state"},
+	{ID: config.PERMISSION_UPDATE_ITEM, Name: "Update item"},
+	{ID: config.PERMISSION_CREATE_ITEM, Name: "Create item"},
+	{ID: config.PERMISSION_CHECK_ITEM_STOCK, Name: "Check item stock"},
+	{ID: config.PERMISSION_UPLOAD_ITEM_IMAGE, Name: "Upload item image"},
+	{ID: config.PERMISSION_DELETE_ITEM_IMAGE, Name: "Delete item image"},
+	{ID: config.PERMISSION_GET_ADDITIONAL_EXPENSE_BY_ID, Name: "Get additional expense by ID"},
+	{ID: config.PERMISSION_GET_ALL_ADDITIONAL_EXPENSE, Name: "Get all additional expense"},
+	{ID: config.PERMISSION_CREATE_ADDITIONAL_EXPENSE, Name: "Create additional expense"},
+	{ID: config.PERMISSION_DELETE_ADDITIONAL_EXPENSE, Name: "Delete additional expense"},
+	{ID: config.PERMISSION_UPDATE_ADDITIONAL_EXPENSE, Name: "Update additional expense"},
+	{ID: config.PERMISSION_GET_HISTORICAL_ITEM_PRICE, Name: "Get historical item price"},
+	{ID: config.PERMISSION_GET_COMMENT_BY_ID, Name: "Get comment by ID"},
+	{ID: config.PERMISSION_GET_ALL_COMMENTS, Name: "Get all comments"},
+	{ID: config.PERMISSION_SEARCH_COMMENTS_BY_EMAIL, Name: "Search comments by email"},
+	{ID: config.PERMISSION_CREATE_COMMENT, Name: "Create comment"},
+	{ID: config.PERMISSION_UPDATE_COMMENT, Name: "Update comment"},
+	{ID: config.PERMISSION_SEARCH_COMMENTS_BY_NAME, Name: "Search comments by name"},
+	{ID: config.PERMISSION_SEARCH_COMMENTS_BY_ID, Name: "Search comments by ID"},
+	{ID: config.PERMISSION_GET_APPOINTMENT_BY_ID, Name: "Get appointment by ID"},
+	{ID: config.PERMISSION_GET_ALL_APPOINTMENTS, Name: "Get all appointments"},
+	{ID: config.PERMISSION_SEARCH_APPOINTMENT_BY_STATE, Name: "Search appointment by state"},
+	{ID: config.PERMISSION_GET_APPOINTMENT_BY_CUSTOMER_ID, Name: "Get appointment by customer ID"},
+	{ID: config.PERMISSION_CREATE_APPOINTMENT, Name: "Create appointment"},
+	{ID: config.PERMISSION_UPDATE_APPOINTMENT, Name: "Update appointment"},
+	{ID: config.PERMISSION_SEARCH_APPOINTMENTS_BY_ID, Name: "Search appointments by ID"},
+	{ID: config.PERMISSION_SEARCH_APPOINTMENTS_BY_NAME, Name: "Search appointments by name"},
+	{ID: config.PERMISSION_GET_APPOINTMENTS_BY_CUSTOMERID_AND_DATE, Name: "Get appointments by customer ID and date"},
+	{ID: config.PERMISSION_DELETE_APPOINTMENT, Name: "Delete appointment"},
+	{ID: config.PERMISSION_GET_APPOINTMENTS_BY_HOUR, Name: "Get appointments by hour"},
+	{ID: config.PERMISSION_LINK_APPOINTMENT_CUSTOMERS, Name: "Link appointments to customers"},
+	{ID: config.PERMISSION_GET_APPOINTMENT_REMINDERS, Name: "Get appointment reminders"},
+	{ID: config.PERMISSION_GET_EMPLOYEE_APPOINTMENTS, Name: "Get any employee's appointments"},
+	{ID: config.PERMISSION_GET_ALL_CUSTOMERS, Name: "Get all customers"},
+	{ID: config.PERMISSION_GET_CUSTOMER_BY_ID, Name: "Get customer by ID"},
+	{ID: config.PERMISSION_CREATE_CUSTOMER, Name: "Create customer"},
+	{ID: config.PERMISSION_UPDATE_CUSTOMER, Name: "Update customer"},
+	{ID: config.PERMISSION_GET_CUSTOMER_BY_EMAIL, Name: "Get customer by email"},
+	{ID: config.PERMISSION_SEARCH_CUSTOMERS, Name: "Search customers"},
+	{ID: config.PERMISSION_GET_CUSTOMER_BY_CUSTOMERID, Name: "Get customer by customer ID"},
+	{ID: config.PERMISSION_DELETE_CUSTOMER, Name: "Delete customer"},
+	{ID: config.PERMISSION_RESTORE_CUSTOMER, Name: "Restore customer"},
+	{ID: config.PERMISSION_MERGE_CUSTOMERS, Name: "Merge customers"},
+	{ID: config.PERMISSION_GET_ALL_IDENTIFIER_TYPES, Name: "Get all identifier types"},
+	{ID: config.PERMISSION_GET_IDENTIFIER_TYPE_BY_ID, Name: "Get identifier type by ID"},
+	{ID: config.PERMISSION_CREATE_IDENTIFIER_TYPE, Name: "Create identifier type"},
+	{ID: config.PERMISSION_UPDATE_IDENTIFIER_TYPE, Name: "Update identifier type"},
+	{ID: config.PERMISSION_DELETE_IDENTIFIER_TYPE, Name: "Delete identifier type"},
+	{ID: config.PERMISSION_GET_ORDER_STATE_TYPE_BY_ID, Name: "Get order state type by ID"},
+	{ID: config.PERMISSION_GET_ALL_ORDER_STATE_TYPES, Name: "Get all order state types"},
+	{ID: config.PERMISSION_GET_PURCHASE_ORDER_BY_ID, Name: "Get purchase order by ID"},
+	{ID: config.PERMISSION_GET_ALL_PURCHASE_ORDERS, Name: "Get all purchase orders"},
+	{ID: config.PERMISSION_SEARCH_PURCHASE_ORDERS_BY_ID, Name: "Search purchase orders by ID"},
+	{ID: config.PERMISSION_GET_PURCHASE_ORDERS_BY_CUSTOMER_ID, Name: "Get purchase orders by customer ID"},
+	{ID: config.PERMISSION_GET_PURCHASE_ORDERS_BY_SELLER_ID, Name: "Get purchase orders by seller ID"},
+	{ID: config.PERMISSION_UPDATE_PURCHASE_ORDER_STATE, Name: "Update purchase order state"},
+	{ID: config.PERMISSION_UPDATE_PURCHASE_ORDER, Name: "Update purchase order"},
+	{ID: config.PERMISSION_CREATE_PURCHASE_ORDER, Name: "Create purchase order"},
+	{ID: config.PERMISSION_GET_PURCHASE_ORDERS_BY_STATE_ID, Name: "Get purchase orders by state ID"},
+	{ID: config.PERMISSION_GET_DISCOUNT_TYPE_BY_ID, Name: "Get discount type by ID"},
+	{ID: config.PERMISSION_GET_ALL_DISCOUNT_TYPES, Name: "Get all discount types"},
+	{ID: config.PERMISSION_CREATE_DISCOUNT_TYPE, Name: "Create discount type"},
+	{ID: config.PERMISSION_IMPORT_DISCOUNT_TYPES, Name: "Import discount types"},
+	{ID: config.PERMISSION_UPDATE_DISCOUNT_TYPE, Name: "Update discount type"},
+	{ID: config.PERMISSION_DEACTIVATE_DISCOUNT_TYPE, Name: "Deactivate discount type"},
+	{ID: config.PERMISSION_GET_INVOICE_BY_ID, Name: "Get invoice by ID"},
+	{ID: config.PERMISSION_GET_ALL_INVOICES, Name: "Get all invoices"},
+	{ID: config.PERMISSION_SEARCH_INVOICE_BY_ID, Name: "Search invoice by ID"},
+	{ID: config.PERMISSION_SEARCH_INVOICE_BY_CUSTOMER_PERSONAL_ID, Name: "Search invoice by customer personal ID"},
+	{ID: config.PERMISSION_CREATE_INVOICE, Name: "Create invoice"},
+	{ID: config.PERMISSION_UPDATE_INVOICE_PAYMENT, Name: "Update invoice payment"},
+	{ID: config.PERMISSION_GET_INVOICE_REMINDERS, Name: "Get invoice payment reminders"},
+	{ID: config.PERMISSION_CALCULATE_SUBTOTAL, Name: "Calculate subtotal"},
+	{ID: config.PERMISSION_CALCULATE_TOTAL, Name: "Calculate total"},
+	{ID: config.PERMISSION_GET_TAX_TYPE_BY_ID, Name: "Get tax type by ID"},
+	{ID: config.PERMISSION_GET_ALL_TAX_TYPES, Name: "Get all tax types"},
+	{ID: config.PERMISSION_CREATE_TAX_TYPE, Name: "Create tax type"},
+	{ID: config.PERMISSION_IMPORT_TAX_TYPES, Name: "Import tax types"},
+	{ID: config.PERMISSION_UPDATE_TAX_TYPE, Name: "Update tax type"},
+	{ID: config.PERMISSION_DEACTIVATE_TAX_TYPE, Name: "Deactivate tax type"},
+	{ID: config.PERMISSION_GET_EXTERNAL_SALE_BY_ID, Name: "Get external sale by ID"},
+	{ID: config.PERMISSION_GET_ALL_EXTERNAL_SALES, Name: "Get all external sales"},
+	{ID: config.PERMISSION_CREATE_EXTERNAL_SALE, Name: "Create external sale"},
+	{ID: config.PERMISSION_VIEW_SALES_REPORT, Name: "View sales report"},
+	{ID: config.PERMISSION_VIEW_SALES_SUMMARY_REPORT, Name: "View sales summary report"},
+	{ID: config.PERMISSION_VIEW_MARGIN_REPORT, Name: "View margin report"},
+	{ID: config.PERMISSION_VIEW_DISCOUNT_USAGE_REPORT, Name: "View discount usage report"},
+	{ID: config.PERMISSION_VIEW_TOP_ITEMS_REPORT, Name: "View top items report"},
+	{ID: config.PERMISSION_VIEW_REVENUE_BY_CUSTOMER_REPORT, Name: "View revenue by customer report"},
+	{ID: config.PERMISSION_VIEW_DAILY_CLOSE, Name: "View daily close"},
+	{ID: config.PERMISSION_CLOSE_DAY, Name: "Close day"},
+	{ID: config.PERMISSION_VIEW_DASHBOARD, Name: "View dashboard"},
+	{ID: config.PERMISSION_VIEW_INVENTORY_TURNOVER_REPORT, Name: "View inventory turnover report"},
+	{ID: config.PERMISSION_VIEW_AUDIT_TRAIL, Name: "View audit trail"},
+	{ID: config.PERMISSION_GET_WEBHOOKS, Name: "Get webhooks"},
+	{ID: config.PERMISSION_CREATE_WEBHOOK, Name: "Create webhook"},
+	{ID: config.PERMISSION_UPDATE_WEBHOOK, Name: "Update webhook"},
+	{ID: config.PERMISSION_DELETE_WEBHOOK, Name: "Delete webhook"},
+	{ID: config.PERMISSION_VIEW_WEBHOOK_DELIVERIES, Name: "View webhook deliveries"},
+	{ID: config.PERMISSION_SUBSCRIBE_EVENTS, Name: "Subscribe events"},
+	{ID: config.PERMISSION_VIEW_MIGRATIONS, Name: "View migrations"},
+	{ID: config.PERMISSION_VIEW_SCHEDULED_JOBS, Name: "View scheduled jobs"},
+	{ID: config.PERMISSION_VIEW_CUSTOMER_NOTIFICATION_PREFERENCES, Name: "View customer notification preferences"},
+	{ID: config.PERMISSION_EDIT_CUSTOMER_NOTIFICATION_PREFERENCES, Name: "Edit customer notification preferences"},
+	{ID: config.PERMISSION_GET_EMAIL_TEMPLATES, Name: "Get email templates"},
+	{ID: config.PERMISSION_EDIT_EMAIL_TEMPLATE, Name: "Edit email template"},
+	{ID: config.PERMISSION_SEND_TEST_EMAIL, Name: "Send test email"},
+	{ID: config.PERMISSION_VIEW_NOTIFICATION_DELIVERIES, Name: "View notification deliveries"},
+	{ID: config.PERMISSION_RETRY_NOTIFICATION_DELIVERY, Name: "Retry notification delivery"},
+	{ID: config.PERMISSION_VIEW_ROUTES, Name: "View routes and their permissions"},
+	{ID: config.PERMISSION_VIEW_ARCHIVE, Name: "View archived invoices and appointments"},
+	{ID: config.PERMISSION_CREATE_DATA_EXPORT, Name: "Create data export"},
+	{ID: config.PERMISSION_VIEW_DATA_EXPORTS, Name: "View data exports"},
+	{ID: config.PERMISSION_RESET_SANDBOX, Name: "Reset sandbox data"},
+	{ID: config.PERMISSION_VIEW_ACCOUNTING_SYNCS, Name: "View accounting sync status"},
+	{ID: config.PERMISSION_RESYNC_ACCOUNTING_DOCUMENT, Name: "Resync accounting document"},
+	{ID: config.PERMISSION_VIEW_ECOMMERCE_ORDERS, Name: "View web orders"},
+	{ID: config.PERMISSION_RETRY_ECOMMERCE_ORDER, Name: "Retry web order"},
+	{ID: config.PERMISSION_VIEW_ECOMMERCE_RECONCILIATION, Name: "View online store reconciliation"},
+	{ID: config.PERMISSION_VIEW_STOCK_MOVEMENTS, Name: "View stock movements"},
+	{ID: config.PERMISSION_VIEW_RESTOCK_ORDERS, Name: "View restock orders"},
+	{ID: config.PERMISSION_CREATE_RESTOCK_ORDER, Name: "Create restock order"},
+	{ID: config.PERMISSION_UPDATE_RESTOCK_ORDER, Name: "Update restock order"},
+	{ID: config.PERMISSION_SEND_RESTOCK_ORDER, Name: "Send restock order"},
+	{ID: config.PERMISSION_RECEIVE_RESTOCK_ORDER, Name: "Receive restock order"},
+	{ID: config.PERMISSION_CANCEL_RESTOCK_ORDER, Name: "Cancel restock order"},
+	{ID: config.PERMISSION_GET_SUPPLIERS, Name: "Get and search suppliers"},
+	{ID: config.PERMISSION_CREATE_SUPPLIER, Name: "Create supplier"},
+	{ID: config.PERMISSION_UPDATE_SUPPLIER, Name: "Update supplier"},
+	{ID: config.PERMISSION_DELETE_SUPPLIER, Name: "Delete supplier"},
+	{ID: config.PERMISSION_GET_LOW_STOCK_ITEMS, Name: "Get low stock items"},
+	{ID: config.PERMISSION_RECEIVE_LOW_STOCK_ALERTS, Name: "Receive low stock alerts (inventory manager)"},
+	{ID: config.PERMISSION_CANCEL_INVOICE, Name: "Cancel invoice with a credit note"},
+	{ID: config.PERMISSION_GET_CREDIT_NOTES, Name: "Get credit notes"},
+	{ID: config.PERMISSION_EXPORT_CUSTOMERS, Name: "Export customers to CSV"},
+	{ID: config.PERMISSION_EXPORT_ITEMS, Name: "Export items to CSV"},
+	{ID: config.PERMISSION_EXPORT_INVOICES, Name: "Export invoices to CSV"},
+	{ID: config.PERMISSION_GET_AVAILABLE_APPOINTMENT_SLOTS, Name: "Get available appointment slots"},
+	{ID: config.PERMISSION_GET_BUSINESS_HOURS, Name: "Get business hours"},
+	{ID: config.PERMISSION_UPDATE_BUSINESS_HOURS, Name: "Update business hours"},
+	{ID: config.PERMISSION_CHANGE_APPOINTMENT_STATE, Name: "Change appointment state"},
+	{ID: config.PERMISSION_GET_APPOINTMENT_STATE_HISTORY, Name: "Get appointment state history"},
+	{ID: config.PERMISSION_GET_APPOINTMENT_STATE_TYPES, Name: "Get appointment states"},
+	{ID: config.PERMISSION_CREATE_ROLE, Name: "Create role"},
+	{ID: config.PERMISSION_UPDATE_ROLE, Name: "Update role"},
+	{ID: config.PERMISSION_DELETE_ROLE, Name: "Delete role"},
+	{ID: config.PERMISSION_UPDATE_ROLE_PERMISSIONS, Name: "Assign and remove role permissions"},
+	{ID: config.PERMISSION_CREATE_USER_TYPE, Name: "Create user type"},
+	{ID: config.PERMISSION_UPDATE_USER_TYPE, Name: "Update user type"},
+	{ID: config.PERMISSION_DELETE_USER_TYPE, Name: "Delete user type"},
+	{ID: config.PERMISSION_UPDATE_USER_TYPE_ROLES, Name: "Assign and remove user type roles"},
+	{ID: config.PERMISSION_VIEW_QUOTATIONS, Name: "View quotations"},
+	{ID: config.PERMISSION_CREATE_QUOTATION, Name: "Create quotation"},
+	{ID: config.PERMISSION_UPDATE_QUOTATION, Name: "Update quotation"},
+	{ID: config.PERMISSION_DELETE_QUOTATION, Name: "Delete quotation"},
+	{ID: config.PERMISSION_DECIDE_QUOTATION, Name: "Accept and reject quotations"},
+	{ID: config.PERMISSION_CONVERT_QUOTATION, Name: "Convert quotation into invoice"},
+	{ID: config.PERMISSION_VIEW_APPOINTMENT_REPORT, Name: "View appointment report"},
+	{ID: config.PERMISSION_VIEW_CURRENCIES, Name: "View currencies"},
+	{ID: config.PERMISSION_CREATE_CURRENCY, Name: "Create currency"},
+	{ID: config.PERMISSION_UPDATE_CURRENCY, Name: "Update currency and exchange rate"},
+	{ID: config.PERMISSION_RECORD_INVOICE_PAYMENT, Name: "Record invoice payment"},
+	{ID: config.PERMISSION_GET_INVOICE_PAYMENTS, Name: "Get invoice payments"},
+	{ID: config.PERMISSION_CREATE_INVOICE_RETURN, Name: "Return invoice items"},
+	{ID: config.PERMISSION_GET_INVOICE_RETURNS, Name: "Get invoice returns"},
+	{ID: config.PERMISSION_VIEW_PRICE_LISTS, Name: "View price lists"},
+	{ID: config.PERMISSION_CREATE_PRICE_LIST, Name: "Create price list"},
+	{ID: config.PERMISSION_UPDATE_PRICE_LIST, Name: "Update price list"},
+	{ID: config.PERMISSION_DELETE_PRICE_LIST, Name: "Delete price list"},
+	{ID: config.PERMISSION_ASSIGN_PRICE_LIST, Name: "Assign price list to customer"},
+}
@@ -0,0 +1,8 @@
+package dtos
+
+type AccountingSyncFilterDTO struct {
+	Status       string
+	DocumentType string
+	DocumentID   *int
+	PaginationDTO
+}
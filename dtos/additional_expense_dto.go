@@ -5,4 +5,5 @@ type UpdateAdditionalExpenseDTO struct {
 	ItemID      int     `json:"item_id"`
 	Expense     float64 `json:"expense"`
 	Description string  `json:"description,omitempty"`
+	SupplierID  *int    `json:"supplier_id,omitempty"`
 }
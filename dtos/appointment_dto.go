@@ -0,0 +1,53 @@
+package dtos
+
+import "time"
+
+// AppointmentCustomerLinkDTO resume el enlace de citas históricas con clientes por correo.
+type AppointmentCustomerLinkDTO struct {
+	Scanned          int `json:"scanned"`
+	Linked           int `json:"linked"`
+	CreatedCustomers int `json:"createdCustomers"`
+	// citas sin correo, que no se pueden asociar
+	Skipped int `json:"skipped"`
+}
+
+// AppointmentSlotDTO es un horario del día con los cupos que le quedan.
+type AppointmentSlotDTO struct {
+	DateTime  time.Time `json:"dateTime"`
+	Available int       `json:"available"`
+}
+
+// PublicAppointmentBookingDTO es la reserva que hace el cliente desde la web.
+type PublicAppointmentBookingDTO struct {
+	DateTime         time.Time `json:"dateTime" binding:"required"`
+	CustomerName     string    `json:"customerName" binding:"required,max=255"`
+	LastName         string    `json:"lastName" binding:"required,max=255"`
+	Email            string    `json:"email" binding:"required,email,max=255"`
+	PhoneNumbers     string    `json:"phoneNumbers,omitempty" binding:"max=100"`
+	Address          string    `json:"address,omitempty" binding:"max=100"`
+	IdentifierTypeID int       `json:"identifierTypeId" binding:"required"`
+	// token del widget de captcha; obligatorio si CAPTCHA_PROVIDER está configurado
+	CaptchaToken string `json:"captchaToken,omitempty" binding:"max=4096"`
+}
+
+// PublicAppointmentDTO es lo que recibe el cliente al reservar; cancelUrl es el mismo enlace del
+// correo de confirmación.
+type PublicAppointmentDTO struct {
+	ID        int       `json:"id"`
+	DateTime  time.Time `json:"dateTime"`
+	CancelURL string    `json:"cancelUrl,omitempty"`
+}
+
+// BusinessHoursDTO cambia el horario de atención de un día; los días que no se envían no cambian.
+type BusinessHoursDTO struct {
+	Weekday      *int `json:"weekday" binding:"required,min=0,max=6"`
+	Open         bool `json:"open"`
+	OpeningHour  int  `json:"openingHour" binding:"min=0,max=23"`
+	LastSlotHour int  `json:"lastSlotHour" binding:"min=0,max=23"`
+}
+
+// AppointmentStateDTO pide el cambio de estado de una cita.
+type AppointmentStateDTO struct {
+	StateID int    `json:"stateId" binding:"required"`
+	Reason  string `json:"reason,omitempty" binding:"max=300"`
+}
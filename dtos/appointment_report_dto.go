@@ -0,0 +1,33 @@
+package dtos
+
+import "time"
+
+// AppointmentPeriodDTO cuenta las citas de un periodo por estado. NoShowRate es la parte de las citas
+// ya atendidas o perdidas (completed + no_show) a las que el cliente no llegó.
+type AppointmentPeriodDTO struct {
+	Period     time.Time `json:"period"`
+	Total      int64     `json:"total"`
+	Pending    int64     `json:"pending"`
+	Confirmed  int64     `json:"confirmed"`
+	Completed  int64     `json:"completed"`
+	Cancelled  int64     `json:"cancelled"`
+	NoShow     int64     `json:"no_show"`
+	NoShowRate float64   `json:"no_show_rate"`
+}
+
+// AppointmentHourDTO son las citas no canceladas que empiezan a una hora del día en todo el rango.
+type AppointmentHourDTO struct {
+	Hour         int   `json:"hour"`
+	Appointments int64 `json:"appointments"`
+	NoShow       int64 `json:"no_show"`
+}
+
+type AppointmentReportDTO struct {
+	From    time.Time              `json:"from"`
+	To      time.Time              `json:"to"`
+	GroupBy string                 `json:"group_by"`
+	Periods []AppointmentPeriodDTO `json:"periods"`
+	Totals  AppointmentPeriodDTO   `json:"totals"`
+	// de la hora con más citas a la de menos
+	BusiestHours []AppointmentHourDTO `json:"busiest_hours"`
+}
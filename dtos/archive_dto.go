@@ -0,0 +1,28 @@
+package dtos
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type ArchiveFilterDTO struct {
+	CustomerID *int
+	From       *time.Time
+	To         *time.Time
+	PaginationDTO
+}
+
+// ArchivedDocumentDTO es una factura o cita archivada; Document tiene la misma forma que la
+// respuesta de GET /invoices/{id} o GET /appointments/{id} al momento de archivarse.
+type ArchivedDocumentDTO struct {
+	ID         int             `json:"id"`
+	CustomerID int             `json:"customer_id"`
+	DateTime   time.Time       `json:"date_time"`
+	ArchivedAt time.Time       `json:"archived_at"`
+	Document   json.RawMessage `json:"document" swaggertype:"object"`
+}
+
+type ArchiveRunDTO struct {
+	Invoices     int `json:"invoices"`
+	Appointments int `json:"appointments"`
+}
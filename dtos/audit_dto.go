@@ -0,0 +1,17 @@
+package dtos
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type GetAuditEntryDTO struct {
+	ID        int             `json:"id"`
+	Entity    string          `json:"entity"`
+	EntityID  string          `json:"entity_id"`
+	Action    string          `json:"action"`
+	UserEmail string          `json:"user_email"`
+	Before    json.RawMessage `json:"before" swaggertype:"object"`
+	After     json.RawMessage `json:"after" swaggertype:"object"`
+	DateTime  time.Time       `json:"date_time"`
+}
@@ -0,0 +1,26 @@
+package dtos
+
+// TokenPairDTO es la respuesta de POST /login y POST /auth/refresh. El token de acceso va en el
+// header "Authorization: Bearer ..."; el de refresco solo sirve para pedir un par nuevo.
+type TokenPairDTO struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	// segundos hasta que vence access_token
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	// segundos hasta que vence refresh_token
+	RefreshExpiresIn int `json:"refresh_expires_in"`
+}
+
+type RefreshTokenRequestDTO struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type PasswordResetRequestDTO struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// PasswordResetDTO llega como JSON o desde el formulario de GET /auth/password-reset/confirm.
+type PasswordResetDTO struct {
+	Password string `json:"password" form:"password" binding:"required"`
+}
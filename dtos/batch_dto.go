@@ -0,0 +1,31 @@
+package dtos
+
+// BatchOperationDTO es una operación de un lote. ID es obligatorio para update y delete; Data
+// para create y update.
+type BatchOperationDTO[T any] struct {
+	Op   string `json:"op" binding:"required,oneof=create update delete"`
+	ID   int    `json:"id,omitempty"`
+	Data *T     `json:"data,omitempty"`
+}
+
+type BatchRequestDTO[T any] struct {
+	Operations []BatchOperationDTO[T] `json:"operations" binding:"required,min=1,max=100,dive"`
+}
+
+// BatchResultDTO reports what happened to one operation. Before and After are kept for the
+// audit trail and are not serialized.
+type BatchResultDTO struct {
+	Index  int         `json:"index"`
+	Op     string      `json:"op"`
+	ID     int         `json:"id,omitempty"`
+	Status string      `json:"status"`
+	Error  string      `json:"error,omitempty"`
+	Data   interface{} `json:"data,omitempty"`
+	Before interface{} `json:"-"`
+	After  interface{} `json:"-"`
+}
+
+type BatchResponseDTO struct {
+	Committed bool             `json:"committed"`
+	Results   []BatchResultDTO `json:"results"`
+}
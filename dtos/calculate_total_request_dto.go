@@ -4,4 +4,8 @@ type CalculateTotalRequestDTO struct {
 	DiscountTypesIds []int            `json:"discountTypesIds"`
 	TaxTypesIds      []int            `json:"taxTypesIds"`
 	ItemsDTO         []BillingItemDTO `json:"itemsDTO"`
+	// moneda del total; sin ella se calcula en la moneda base
+	Currency string `json:"currency"`
+	// cliente cuya lista de precios se aplica; sin él se usan los precios de venta
+	CustomerID int `json:"customerId"`
 }
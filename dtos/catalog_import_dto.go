@@ -0,0 +1,12 @@
+package dtos
+
+type ImportCatalogEntryDTO struct {
+	Name         string  `json:"name" binding:"required"`
+	Description  string  `json:"description,omitempty"`
+	IsPercentage bool    `json:"is_percentage"`
+	Value        float64 `json:"value"`
+}
+
+type ImportCatalogResultDTO struct {
+	Imported int `json:"imported"`
+}
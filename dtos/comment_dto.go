@@ -9,6 +9,7 @@ type GetCommentDTO struct {
 	ResidenceState string `json:"residence_state,omitempty"`
 	ResidenceCity  string `json:"residence_city,omitempty"`
 	Comment        string `json:"comment,omitempty"`
+	Reviewed       bool   `json:"reviewed"`
 }
 
 type UpdateCommentDTO struct {
@@ -19,6 +20,7 @@ type UpdateCommentDTO struct {
 	ResidenceState string `json:"residence_state,omitempty"`
 	ResidenceCity  string `json:"residence_city,omitempty"`
 	Comment        string `json:"comment,omitempty"`
+	Reviewed       *bool  `json:"reviewed,omitempty"`
 }
 
 type CreateCommentDTO struct {
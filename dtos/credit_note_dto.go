@@ -0,0 +1,5 @@
+package dtos
+
+type CancelInvoiceDTO struct {
+	Reason string `json:"reason" binding:"required,max=300"`
+}
@@ -0,0 +1,10 @@
+package dtos
+
+// CurrencyDTO crea o edita una moneda; al editar el código viene en la ruta y se ignora aquí.
+// ExchangeRate es cuánto vale una unidad en la moneda base.
+type CurrencyDTO struct {
+	Code         string  `json:"code"`
+	Name         string  `json:"name" binding:"required,max=100"`
+	Symbol       string  `json:"symbol" binding:"max=10"`
+	ExchangeRate float64 `json:"exchange_rate" binding:"required,gt=0"`
+}
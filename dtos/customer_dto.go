@@ -1,16 +1,49 @@
 package dtos
 
+import (
+	"time"
+	"totesbackend/models"
+)
+
 type GetCustomerDTO struct {
-	ID               int    `json:"id"`
-	CustomerName     string `json:"customerName"`
-	CustomerId       string `json:"customerId"`
-	IsBusiness       bool   `json:"isBusiness"`
-	Address          string `json:"address,omitempty"`
-	PhoneNumbers     string `json:"phoneNumbers,omitempty"`
-	CustomerState    bool   `json:"customerState"`
-	Email            string `json:"email"`
-	LastName         string `json:"lastName"`
-	IdentifierTypeID int    `json:"identifierTypeId"`
+	ID               int      `json:"id"`
+	CustomerName     string   `json:"customerName"`
+	CustomerId       string   `json:"customerId"`
+	IsBusiness       bool     `json:"isBusiness"`
+	Address          string   `json:"address,omitempty"`
+	PhoneNumbers     string   `json:"phoneNumbers,omitempty"`
+	CustomerState    bool     `json:"customerState"`
+	Email            string   `json:"email"`
+	LastName         string   `json:"lastName"`
+	IdentifierTypeID int      `json:"identifierTypeId"`
+	Version          int      `json:"version"`
+	Latitude         *float64 `json:"latitude,omitempty"`
+	Longitude        *float64 `json:"longitude,omitempty"`
+	AddressStatus    string   `json:"addressStatus,omitempty"`
+	TextChannel      string   `json:"textChannel,omitempty"`
+	// solo con includeDeleted: cuándo se borró el cliente
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
+// CustomerSearchDTO son los filtros de GET /customers/search; los vacíos no filtran y los demás se
+// combinan entre sí.
+type CustomerSearchDTO struct {
+	// prefijo del ID interno
+	ID string
+	// prefijos del nombre y del apellido, sin distinguir mayúsculas ni tildes
+	Name     string
+	LastName string
+	// prefijo del correo, sin distinguir mayúsculas
+	Email string
+	// dígitos contenidos en alguno de los teléfonos, sin contar espacios ni signos
+	Phone            string
+	IdentifierTypeID *int
+	IsBusiness       *bool
+	State            *bool
+	IncludeDeleted   bool
+	SortBy           string
+	Order            string
+	PaginationDTO
 }
 
 type CreateCustomerDTO struct {
@@ -23,6 +56,8 @@ type CreateCustomerDTO struct {
 	Email            string `json:"email" binding:"required,email"`
 	LastName         string `json:"lastName" binding:"required"`
 	IdentifierTypeID int    `json:"identifierTypeId" binding:"required"`
+	// sms o whatsapp para recibir también por ese canal los avisos de citas; vacío para no recibirlos
+	TextChannel string `json:"textChannel,omitempty" binding:"omitempty,oneof=sms whatsapp"`
 }
 
 type UpdateCustomerDTO struct {
@@ -35,4 +70,58 @@ type UpdateCustomerDTO struct {
 	Email            string `json:"email"`
 	LastName         string `json:"lastName"`
 	IdentifierTypeID int    `json:"identifierTypeId"`
+	TextChannel      string `json:"textChannel,omitempty" binding:"omitempty,oneof=sms whatsapp"`
+	Version          *int   `json:"version" binding:"required"`
+}
+
+// CustomerDependenciesDTO cuenta los registros que hacen referencia a un cliente.
+type CustomerDependenciesDTO struct {
+	Invoices       int64 `json:"invoices"`
+	Appointments   int64 `json:"appointments"`
+	ExternalSales  int64 `json:"externalSales"`
+	PurchaseOrders int64 `json:"purchaseOrders"`
+}
+
+func (d CustomerDependenciesDTO) Total() int64 {
+	return d.Invoices + d.Appointments + d.ExternalSales + d.PurchaseOrders
+}
+
+// CustomerDuplicateDTO es un cliente existente que parece ser el mismo que se quiere crear.
+// Reasons dice por qué: "identifier", "email" o "name_and_phone".
+type CustomerDuplicateDTO struct {
+	Customer models.Customer `json:"customer"`
+	Reasons  []string        `json:"reasons"`
+}
+
+// CustomerDuplicatesDTO va en details del 409 de POST /customers. Con CanOverride el cliente se
+// puede crear igual repitiendo la petición con force=true; sin él choca con el documento o el
+// correo de otro cliente, que son únicos.
+type CustomerDuplicatesDTO struct {
+	Candidates  []CustomerDuplicateDTO `json:"candidates"`
+	CanOverride bool                   `json:"canOverride"`
+}
+
+type MergeCustomersDTO struct {
+	// PrimaryID es el cliente que se conserva; DuplicateID se desactiva
+	PrimaryID   int `json:"primaryId" binding:"required"`
+	DuplicateID int `json:"duplicateId" binding:"required"`
+}
+
+// CustomerReassignmentDTO cuenta los registros que pasaron del duplicado al cliente principal.
+type CustomerReassignmentDTO struct {
+	Appointments  int64 `json:"appointments"`
+	Invoices      int64 `json:"invoices"`
+	ExternalSales int64 `json:"externalSales"`
+}
+
+type CustomerMergeDTO struct {
+	Customer   models.Customer         `json:"customer"`
+	Duplicate  models.Customer         `json:"duplicate"`
+	Reassigned CustomerReassignmentDTO `json:"reassigned"`
+}
+
+type CustomerDeletionDTO struct {
+	// Action es "deleted" o "archived"
+	Action       string                  `json:"action"`
+	Dependencies CustomerDependenciesDTO `json:"dependencies"`
 }
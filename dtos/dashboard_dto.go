@@ -0,0 +1,17 @@
+package dtos
+
+type DashboardSalesDTO struct {
+	InvoiceCount int64   `json:"invoice_count"`
+	Total        float64 `json:"total"`
+}
+
+type DashboardDTO struct {
+	SalesToday        DashboardSalesDTO `json:"sales_today"`
+	SalesThisWeek     DashboardSalesDTO `json:"sales_this_week"`
+	SalesThisMonth    DashboardSalesDTO `json:"sales_this_month"`
+	AppointmentsToday int64             `json:"appointments_today"`
+	AppointmentsWeek  int64             `json:"appointments_this_week"`
+	AppointmentsMonth int64             `json:"appointments_this_month"`
+	LowStockItems     int64             `json:"low_stock_items"`
+	PendingComments   int64             `json:"pending_comments"`
+}
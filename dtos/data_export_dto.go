@@ -0,0 +1,27 @@
+package dtos
+
+import (
+	"time"
+	"totesbackend/models"
+)
+
+type DataExportDTO struct {
+	models.DataExport
+	// solo en las exportaciones terminadas que aún tienen archivo
+	DownloadURL       string     `json:"download_url,omitempty"`
+	DownloadExpiresAt *time.Time `json:"download_expires_at,omitempty"`
+}
+
+// DataExportManifestDTO es el manifest.json de cada zip: cuándo se generó y cuántas filas tiene
+// cada tabla.
+type DataExportManifestDTO struct {
+	GeneratedAt time.Time      `json:"generated_at"`
+	Tables      map[string]int `json:"tables"`
+}
+
+// CSVExportFilterDTO limita las exportaciones CSV de customers, items e invoices a un rango de fechas;
+// los extremos nil no filtran.
+type CSVExportFilterDTO struct {
+	From *time.Time
+	To   *time.Time
+}
@@ -0,0 +1,14 @@
+package dtos
+
+import "time"
+
+type DiscountTypeDTO struct {
+	Name         string     `json:"name" binding:"required,max=100"`
+	Description  string     `json:"description" binding:"max=300"`
+	IsPercentage bool       `json:"is_percentage"`
+	Value        float64    `json:"value" binding:"min=0"`
+	ValidFrom    *time.Time `json:"valid_from"`
+	ValidTo      *time.Time `json:"valid_to"`
+	// al actualizar, se conserva si se omite; los descuentos nuevos quedan activos
+	Active *bool `json:"active"`
+}
@@ -0,0 +1,49 @@
+package dtos
+
+import "time"
+
+type EcommerceOrderFilterDTO struct {
+	Status string
+	PaginationDTO
+}
+
+// EcommerceMismatchDTO es un ítem cuyo precio o existencias no coinciden con los de la tienda.
+// StoreStock es nil si la tienda no controla el inventario del producto.
+type EcommerceMismatchDTO struct {
+	ItemID     int      `json:"item_id"`
+	Name       string   `json:"name"`
+	Stock      int      `json:"stock"`
+	StoreStock *int     `json:"store_stock"`
+	Price      float64  `json:"price"`
+	StorePrice float64  `json:"store_price"`
+	Fields     []string `json:"fields"`
+}
+
+// EcommerceUnknownProductDTO es un producto de la tienda cuyo SKU no es el ID de ningún ítem.
+type EcommerceUnknownProductDTO struct {
+	SKU        string `json:"sku"`
+	ExternalID string `json:"external_id"`
+	Name       string `json:"name"`
+}
+
+type EcommerceUnlistedItemDTO struct {
+	ItemID int    `json:"item_id"`
+	Name   string `json:"name"`
+}
+
+// EcommerceReconciliationDTO compara el catálogo de la tienda con los ítems activos.
+type EcommerceReconciliationDTO struct {
+	Provider    string    `json:"provider"`
+	GeneratedAt time.Time `json:"generated_at"`
+	// productos de la tienda con SKU y cuántos de ellos coinciden en precio y existencias
+	StoreProducts int                          `json:"store_products"`
+	Matched       int                          `json:"matched"`
+	Mismatches    []EcommerceMismatchDTO       `json:"mismatches"`
+	UnknownSKUs   []EcommerceUnknownProductDTO `json:"unknown_skus"`
+	// ítems activos que no están publicados en la tienda
+	Unlisted []EcommerceUnlistedItemDTO `json:"unlisted"`
+	// ítems cuyo último envío a la tienda falló
+	FailedPushes int64 `json:"failed_pushes"`
+	// pedidos web que no se pudieron importar
+	FailedOrders int64 `json:"failed_orders"`
+}
@@ -0,0 +1,46 @@
+package dtos
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type EmailTemplateSummaryDTO struct {
+	Name string `json:"name"`
+	// ActiveVersion es 0 cuando se usa la plantilla incluida
+	ActiveVersion int        `json:"active_version"`
+	UpdatedAt     *time.Time `json:"updated_at"`
+}
+
+type EmailTemplateDTO struct {
+	Name          string   `json:"name"`
+	ActiveVersion int      `json:"active_version"`
+	Subject       string   `json:"subject"`
+	Body          string   `json:"body"`
+	Placeholders  []string `json:"placeholders"`
+}
+
+type CreateEmailTemplateVersionDTO struct {
+	Subject string `json:"subject" binding:"required,max=255"`
+	Body    string `json:"body" binding:"required"`
+}
+
+// PreviewEmailTemplateDTO renderiza la versión activa, o el borrador si se envían subject y body.
+// Data reemplaza campos de los datos de ejemplo.
+type PreviewEmailTemplateDTO struct {
+	Subject *string         `json:"subject"`
+	Body    *string         `json:"body"`
+	Data    json.RawMessage `json:"data" swaggertype:"object"`
+}
+
+type EmailTemplatePreviewDTO struct {
+	Subject string `json:"subject"`
+	HTML    string `json:"html"`
+}
+
+type SendTestEmailDTO struct {
+	To      string          `json:"to" binding:"required,email"`
+	Subject *string         `json:"subject"`
+	Body    *string         `json:"body"`
+	Data    json.RawMessage `json:"data" swaggertype:"object"`
+}
@@ -1,5 +1,15 @@
 package dtos
 
+// ErrorResponse es el cuerpo común de todas las respuestas de error.
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Code    string          `json:"code"`
+	Message string          `json:"message"`
+	Fields  []FieldErrorDTO `json:"fields,omitempty"`
+	// datos adicionales de algunos errores, p. ej. los candidatos de un cliente duplicado
+	Details any `json:"details,omitempty"`
+}
+
+type FieldErrorDTO struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
@@ -0,0 +1,18 @@
+package dtos
+
+import "time"
+
+// StreamEventDTO es lo que recibe cada cliente de /events en el campo data del mensaje SSE.
+type StreamEventDTO struct {
+	ID        int64       `json:"id"`
+	Type      string      `json:"type"`
+	Data      interface{} `json:"data"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type LowStockEventDTO struct {
+	ItemID    int    `json:"item_id"`
+	Name      string `json:"name"`
+	Stock     int    `json:"stock"`
+	Threshold int    `json:"threshold"`
+}
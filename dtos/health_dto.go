@@ -0,0 +1,32 @@
+package dtos
+
+type DBPoolStatsDTO struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMs     int64 `json:"wait_duration_ms"`
+	MaxIdleClosed      int64 `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64 `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64 `json:"max_lifetime_closed"`
+}
+
+type HealthDTO struct {
+	Message  string         `json:"message"`
+	Database DBPoolStatsDTO `json:"database"`
+}
+
+// DependencyStatusDTO es el estado de una dependencia en /health/ready: "up" o "down", con el motivo
+// cuando está caída.
+type DependencyStatusDTO struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+type ReadinessDTO struct {
+	Status       string                `json:"status"`
+	Dependencies []DependencyStatusDTO `json:"dependencies"`
+}
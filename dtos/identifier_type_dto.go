@@ -0,0 +1,5 @@
+package dtos
+
+type IdentifierTypeDTO struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
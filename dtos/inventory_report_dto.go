@@ -0,0 +1,23 @@
+package dtos
+
+import "time"
+
+type InventoryTurnoverRowDTO struct {
+	ItemID       int      `json:"item_id,omitempty"`
+	ItemName     string   `json:"item_name,omitempty"`
+	CategoryID   int      `json:"category_id"`
+	CategoryName string   `json:"category_name"`
+	Stock        int      `json:"stock"`
+	UnitsSold    int      `json:"units_sold"`
+	Turnover     float64  `json:"turnover"`
+	DaysOfStock  *float64 `json:"days_of_stock"`
+	DeadStock    bool     `json:"dead_stock"`
+}
+
+type InventoryTurnoverReportDTO struct {
+	From      time.Time                 `json:"from"`
+	To        time.Time                 `json:"to"`
+	GroupBy   string                    `json:"group_by"`
+	Rows      []InventoryTurnoverRowDTO `json:"rows"`
+	DeadStock int                       `json:"dead_stock_count"`
+}
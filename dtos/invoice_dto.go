@@ -12,9 +12,17 @@ type GetInvoiceDTO struct {
 	CustomerID     int              `json:"customer_id"`
 	Total          float64          `json:"total"`
 	Subtotal       float64          `json:"subtotal"`
+	Currency       string           `json:"currency"`
+	ExchangeRate   float64          `json:"exchange_rate"`
 	Items          []BillingItemDTO `json:"items"`
 	Discounts      []int            `json:"discounts"`
 	Taxes          []int            `json:"taxes"`
+	DueDate        *time.Time       `json:"due_date"`
+	PaidAt         *time.Time       `json:"paid_at"`
+	PaymentStatus  string           `json:"payment_status"`
+	AmountPaid     float64          `json:"amount_paid"`
+	Balance        float64          `json:"balance"`
+	Version        int              `json:"version"`
 }
 
 type SalesReportInvoiceDTO struct {
@@ -22,6 +30,7 @@ type SalesReportInvoiceDTO struct {
 	DateTime  time.Time             `json:"date_time"`
 	Total     float64               `json:"total"`
 	Subtotal  float64               `json:"subtotal"`
+	Currency  string                `json:"currency"`
 	Items     []BillingItemDTO      `json:"items"`
 	Discounts []models.DiscountType `json:"discounts"`
 	Taxes     []models.TaxType      `json:"taxes"`
@@ -33,4 +42,14 @@ type CreateInvoiceDTO struct {
 	Items          []BillingItemDTO `json:"items"`
 	Discounts      []int            `json:"discounts"`
 	Taxes          []int            `json:"taxes"`
+	// Currency es la moneda de la factura; sin ella se factura en la moneda base
+	Currency string `json:"currency"`
+	// DueDate marca la factura como a crédito; sin ella se considera pagada al emitirse
+	DueDate *time.Time `json:"due_date"`
+}
+
+// InvoicePaymentDTO marca una factura como pagada o la devuelve a pendiente.
+type InvoicePaymentDTO struct {
+	Paid    *bool `json:"paid" binding:"required"`
+	Version *int  `json:"version" binding:"required"`
 }
@@ -0,0 +1,25 @@
+package dtos
+
+import "time"
+
+type CreateInvoiceReturnDTO struct {
+	Reason string                 `json:"reason" binding:"max=300"`
+	Items  []InvoiceReturnItemDTO `json:"items" binding:"required,min=1,dive"`
+	// RefundMethod es cómo se pagó el reembolso, uno de los medios de pago
+	RefundMethod string `json:"refund_method" binding:"required"`
+	// RefundDate es cuándo se pagó el reembolso; sin ella es ahora
+	RefundDate *time.Time `json:"refund_date"`
+}
+
+type InvoiceReturnItemDTO struct {
+	ItemID int `json:"item_id" binding:"required"`
+	Amount int `json:"amount" binding:"required,gt=0"`
+}
+
+// ReturnExcessDTO es un item de la devolución que no se vendió en la factura o del que se piden más
+// unidades de las que quedan por devolver. Requested suma todas las líneas del mismo item.
+type ReturnExcessDTO struct {
+	ItemID     int `json:"item_id"`
+	Requested  int `json:"requested"`
+	Returnable int `json:"returnable"`
+}
@@ -1,28 +1,57 @@
 package dtos
 
 type GetItemDTO struct {
-	ID                 int     `json:"id"`
-	Name               string  `json:"name"`
-	Description        string  `json:"description,omitempty"`
-	Stock              int     `json:"stock"`
-	SellingPrice       float64 `json:"selling_price"`
-	PurchasePrice      float64 `json:"purchase_price"`
-	ItemState          bool    `json:"item_state"`
-	ItemTypeID         int     `json:"item_type_id"`
-	AdditionalExpenses []int   `json:"additional_expenses"`
+	ID                 int            `json:"id"`
+	Name               string         `json:"name"`
+	Description        string         `json:"description,omitempty"`
+	SKU                *string        `json:"sku,omitempty"`
+	Barcode            *string        `json:"barcode,omitempty"`
+	Stock              int            `json:"stock"`
+	SellingPrice       float64        `json:"selling_price"`
+	PurchasePrice      float64        `json:"purchase_price"`
+	Currency           string         `json:"currency"`
+	ItemState          bool           `json:"item_state"`
+	ReorderLevel       int            `json:"reorder_level"`
+	ItemTypeID         int            `json:"item_type_id"`
+	AdditionalExpenses []int          `json:"additional_expenses"`
+	Images             []ItemImageDTO `json:"images"`
+	Version            int            `json:"version"`
+}
+
+// ItemImageDTO es una imagen de un item; con ID se borra en DELETE /items/{id}/images/{imageId}.
+type ItemImageDTO struct {
+	ID  int    `json:"id"`
+	URL string `json:"url"`
 }
 
 type UpdateItemDTO struct {
-	Name          string  `json:"name"`
-	Description   string  `json:"description,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// sku y barcode son únicos; sin ellos un item existente conserva los suyos y "" los quita
+	SKU           *string `json:"sku,omitempty" binding:"omitempty,max=64"`
+	Barcode       *string `json:"barcode,omitempty" binding:"omitempty,max=64"`
 	Stock         int     `json:"stock"`
 	SellingPrice  float64 `json:"selling_price"`
 	PurchasePrice float64 `json:"purchase_price"`
 	ItemState     bool    `json:"item_state"`
 	ItemTypeID    int     `json:"item_type_id"`
+	// moneda de los precios; sin ella un item nuevo usa la moneda base y uno existente conserva la suya
+	Currency string `json:"currency,omitempty"`
+	// sin reorder_level un item nuevo usa config.LOW_STOCK_THRESHOLD y uno existente conserva el suyo
+	ReorderLevel *int `json:"reorder_level,omitempty" binding:"omitempty,min=0"`
+	// Version es obligatoria en PUT /items/{id}
+	Version *int `json:"version,omitempty"`
 }
 
 type BillingItemDTO struct {
 	ID    int `json:"id"`
 	Stock int `json:"stock"`
 }
+
+// StockShortageDTO es un item de la factura sin existencias suficientes. Requested suma todas las
+// líneas del mismo item.
+type StockShortageDTO struct {
+	ItemID    int `json:"item_id"`
+	Requested int `json:"requested"`
+	Available int `json:"available"`
+}
@@ -0,0 +1,5 @@
+package dtos
+
+type ItemTypeDTO struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
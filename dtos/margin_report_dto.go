@@ -0,0 +1,21 @@
+package dtos
+
+import "time"
+
+type MarginRowDTO struct {
+	Key       string  `json:"key"`
+	Label     string  `json:"label"`
+	UnitsSold int     `json:"units_sold"`
+	Revenue   float64 `json:"revenue"`
+	Cost      float64 `json:"cost"`
+	Margin    float64 `json:"margin"`
+	MarginPct float64 `json:"margin_pct"`
+}
+
+type MarginReportDTO struct {
+	From    time.Time      `json:"from"`
+	To      time.Time      `json:"to"`
+	GroupBy string         `json:"group_by"`
+	Rows    []MarginRowDTO `json:"rows"`
+	Totals  MarginRowDTO   `json:"totals"`
+}
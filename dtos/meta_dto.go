@@ -0,0 +1,8 @@
+package dtos
+
+// RouteDTO describe una ruta registrada. PermissionIDs queda vacío en las rutas públicas.
+type RouteDTO struct {
+	Method        string `json:"method"`
+	Path          string `json:"path"`
+	PermissionIDs []int  `json:"permission_ids"`
+}
@@ -0,0 +1,11 @@
+package dtos
+
+import "time"
+
+type MigrationStatusDTO struct {
+	Version     int        `json:"version"`
+	Name        string     `json:"name"`
+	Destructive bool       `json:"destructive"`
+	Applied     bool       `json:"applied"`
+	AppliedAt   *time.Time `json:"applied_at,omitempty"`
+}
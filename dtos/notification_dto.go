@@ -0,0 +1,35 @@
+package dtos
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type GetNotificationDTO struct {
+	ID        int             `json:"id"`
+	EventType string          `json:"event_type"`
+	Title     string          `json:"title"`
+	Body      string          `json:"body"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	Read      bool            `json:"read"`
+	ReadAt    *time.Time      `json:"read_at"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type UnreadNotificationCountDTO struct {
+	Unread int64 `json:"unread"`
+}
+
+type MarkedNotificationsDTO struct {
+	Marked int64 `json:"marked"`
+}
+
+type NotificationDeliveryFilterDTO struct {
+	Channel   string
+	Status    string
+	Recipient string
+	MessageID int
+	From      *time.Time
+	To        *time.Time
+	PaginationDTO
+}
@@ -0,0 +1,22 @@
+package dtos
+
+type NotificationPreferenceDTO struct {
+	EventType   string `json:"event_type"`
+	Description string `json:"description"`
+	Channel     string `json:"channel"`
+	Enabled     bool   `json:"enabled"`
+	// Mandatory: correos que no se pueden desactivar, como el de restablecer la contraseña
+	Mandatory bool `json:"mandatory"`
+	// IsDefault: el destinatario nunca cambió esta preferencia
+	IsDefault bool `json:"is_default"`
+}
+
+type UpdateNotificationPreferencesDTO struct {
+	Preferences []UpdateNotificationPreferenceDTO `json:"preferences" binding:"required,min=1,dive"`
+}
+
+type UpdateNotificationPreferenceDTO struct {
+	EventType string `json:"event_type" binding:"required"`
+	Channel   string `json:"channel" binding:"required"`
+	Enabled   *bool  `json:"enabled" binding:"required"`
+}
@@ -0,0 +1,79 @@
+package dtos
+
+import (
+	"net/url"
+	"strconv"
+)
+
+type PaginationDTO struct {
+	Page     int
+	PageSize int
+	// URL de la petición; a partir de ella se arman los links de PageDTO
+	URL *url.URL `json:"-" form:"-"`
+}
+
+func (p PaginationDTO) Offset() int {
+	return (p.Page - 1) * p.PageSize
+}
+
+// PageDTO es el sobre común de todas las respuestas paginadas.
+type PageDTO[T any] struct {
+	Data     []T          `json:"data"`
+	Page     int          `json:"page"`
+	PageSize int          `json:"page_size"`
+	Total    int64        `json:"total"`
+	Links    PageLinksDTO `json:"links"`
+}
+
+// PageLinksDTO tiene la misma consulta con otra página; prev y next faltan en los extremos.
+type PageLinksDTO struct {
+	Self  string `json:"self,omitempty"`
+	First string `json:"first,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last,omitempty"`
+}
+
+func NewPageDTO[T any](data []T, pagination PaginationDTO, total int64) *PageDTO[T] {
+	if data == nil {
+		data = []T{}
+	}
+	return &PageDTO[T]{
+		Data:     data,
+		Page:     pagination.Page,
+		PageSize: pagination.PageSize,
+		Total:    total,
+		Links:    pagination.links(total),
+	}
+}
+
+func (p PaginationDTO) links(total int64) PageLinksDTO {
+	if p.URL == nil || p.PageSize < 1 {
+		return PageLinksDTO{}
+	}
+
+	lastPage := int((total + int64(p.PageSize) - 1) / int64(p.PageSize))
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	links := PageLinksDTO{
+		Self:  p.pageURL(p.Page),
+		First: p.pageURL(1),
+		Last:  p.pageURL(lastPage),
+	}
+	if p.Page > 1 {
+		links.Prev = p.pageURL(min(p.Page-1, lastPage))
+	}
+	if p.Page < lastPage {
+		links.Next = p.pageURL(p.Page + 1)
+	}
+	return links
+}
+
+func (p PaginationDTO) pageURL(page int) string {
+	query := p.URL.Query()
+	query.Set("page", strconv.Itoa(page))
+	query.Set("pageSize", strconv.Itoa(p.PageSize))
+	return p.URL.Path + "?" + query.Encode()
+}
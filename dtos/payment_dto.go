@@ -0,0 +1,40 @@
+package dtos
+
+import (
+	"time"
+	"totesbackend/models"
+)
+
+type CreatePaymentDTO struct {
+	// Amount está en la moneda de la factura
+	Amount    float64 `json:"amount" binding:"required,gt=0"`
+	Method    string  `json:"method" binding:"required"`
+	Reference string  `json:"reference" binding:"max=100"`
+	// Date es cuándo se recibió el pago; sin ella es ahora
+	Date *time.Time `json:"date"`
+}
+
+// PaymentMethodTotalDTO es lo recibido con un medio de pago, en la moneda base.
+type PaymentMethodTotalDTO struct {
+	Method string  `json:"method"`
+	Total  float64 `json:"total"`
+}
+
+// InvoicePaymentsDTO son los pagos de una factura con lo abonado y el saldo pendiente.
+type InvoicePaymentsDTO struct {
+	InvoiceID     int              `json:"invoice_id"`
+	Currency      string           `json:"currency"`
+	Total         float64          `json:"total"`
+	AmountPaid    float64          `json:"amount_paid"`
+	Balance       float64          `json:"balance"`
+	PaymentStatus string           `json:"payment_status"`
+	Payments      []models.Payment `json:"payments"`
+}
+
+// PaymentRecordedDTO es el pago registrado con el estado de pago en que quedó la factura.
+type PaymentRecordedDTO struct {
+	Payment       models.Payment `json:"payment"`
+	AmountPaid    float64        `json:"amount_paid"`
+	Balance       float64        `json:"balance"`
+	PaymentStatus string         `json:"payment_status"`
+}
@@ -0,0 +1,18 @@
+package dtos
+
+type PriceListDTO struct {
+	Name        string             `json:"name" binding:"required,max=100"`
+	Description string             `json:"description" binding:"max=300"`
+	Items       []PriceListItemDTO `json:"items" binding:"dive"`
+}
+
+type PriceListItemDTO struct {
+	ItemID int `json:"item_id" binding:"required"`
+	// en la moneda del item
+	Price float64 `json:"price" binding:"min=0"`
+}
+
+type AssignPriceListDTO struct {
+	// null quita la lista y el cliente vuelve a los precios normales
+	PriceListID *int `json:"price_list_id"`
+}
@@ -0,0 +1,19 @@
+package dtos
+
+type PublicCatalogFilterDTO struct {
+	Search     string
+	CategoryID int
+	PaginationDTO
+}
+
+// PublicCatalogItemDTO es lo que ve la tienda de un item: sin costo ni cantidades en bodega.
+type PublicCatalogItemDTO struct {
+	ID          int     `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Price       float64 `json:"price"`
+	Currency    string  `json:"currency"`
+	CategoryID  int     `json:"category_id"`
+	Category    string  `json:"category"`
+	InStock     bool    `json:"in_stock"`
+}
@@ -17,7 +17,8 @@ type GetPurchaseOrderDTO struct {
 }
 
 type CreatePurchaseOrderDTO struct {
-	Items []BillingItemDTO `json:"items"`
+	Items      []BillingItemDTO `json:"items"`
+	CustomerID *int             `json:"customer_id,omitempty"`
 }
 
 type UpdatePurchaseOrderDTO struct {
@@ -0,0 +1,22 @@
+package dtos
+
+import "time"
+
+// QuotationDTO crea una cotización pendiente o reemplaza una que sigue pendiente. Los precios se
+// toman de los items al cotizar.
+type QuotationDTO struct {
+	EnterpriseData string           `json:"enterprise_data" binding:"required,max=300"`
+	CustomerID     int              `json:"customer_id" binding:"required"`
+	Items          []BillingItemDTO `json:"items" binding:"required,min=1"`
+	Discounts      []int            `json:"discounts"`
+	Taxes          []int            `json:"taxes"`
+	ExpiresAt      time.Time        `json:"expires_at" binding:"required"`
+	// sin currency se cotiza en la moneda base
+	Currency string `json:"currency"`
+}
+
+// ConvertQuotationDTO son los datos de la factura que no vienen de la cotización; due_date la
+// emite a crédito.
+type ConvertQuotationDTO struct {
+	DueDate *time.Time `json:"due_date"`
+}
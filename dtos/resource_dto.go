@@ -0,0 +1,15 @@
+package dtos
+
+// LinksDTO relaciona un nombre ("self", "customer", "items", ...) con la ruta del recurso en la
+// API, o con la lista de rutas cuando son varios.
+type LinksDTO map[string]any
+
+// ResourceDTO es el sobre común de las respuestas de detalle.
+type ResourceDTO[T any] struct {
+	Data  T        `json:"data"`
+	Links LinksDTO `json:"links"`
+}
+
+func NewResourceDTO[T any](data T, links LinksDTO) *ResourceDTO[T] {
+	return &ResourceDTO[T]{Data: data, Links: links}
+}
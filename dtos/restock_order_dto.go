@@ -0,0 +1,26 @@
+package dtos
+
+type RestockOrderItemDTO struct {
+	ItemID   int     `json:"item_id" binding:"required"`
+	Quantity int     `json:"quantity" binding:"required,min=1"`
+	UnitCost float64 `json:"unit_cost" binding:"min=0"`
+}
+
+// RestockOrderDTO crea un pedido en borrador o reemplaza uno que sigue en borrador.
+type RestockOrderDTO struct {
+	SupplierID *int `json:"supplier_id"`
+	// solo para proveedores sin registrar; con supplier_id se usa su nombre
+	SupplierName string                `json:"supplier_name" binding:"max=150"`
+	Notes        string                `json:"notes" binding:"max=500"`
+	Items        []RestockOrderItemDTO `json:"items" binding:"required,min=1,dive"`
+}
+
+type ReceivedItemDTO struct {
+	ItemID   int `json:"item_id" binding:"required"`
+	Quantity int `json:"quantity" binding:"required,min=1"`
+}
+
+// ReceiveRestockOrderDTO son las cantidades que llegaron; sin items se recibe todo lo pendiente.
+type ReceiveRestockOrderDTO struct {
+	Items []ReceivedItemDTO `json:"items" binding:"dive"`
+}
@@ -6,3 +6,20 @@ type RoleDTO struct {
 	Description string   `json:"description"`
 	Permissions []string `json:"permissions"`
 }
+
+type CreateRoleDTO struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=300"`
+	// IDs de los permisos del rol; puede quedar sin permisos
+	Permissions []uint `json:"permissions"`
+}
+
+type UpdateRoleDTO struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=300"`
+}
+
+// UpdateRolePermissionsDTO es el conjunto completo de permisos del rol; los que no vienen se quitan.
+type UpdateRolePermissionsDTO struct {
+	Permissions []uint `json:"permissions" binding:"required"`
+}
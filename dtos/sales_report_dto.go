@@ -0,0 +1,74 @@
+package dtos
+
+import "time"
+
+type SalesSummaryPeriodDTO struct {
+	Period       time.Time `json:"period"`
+	InvoiceCount int64     `json:"invoice_count"`
+	Subtotal     float64   `json:"subtotal"`
+	Tax          float64   `json:"tax"`
+	Discount     float64   `json:"discount"`
+	Total        float64   `json:"total"`
+}
+
+// VoidTotalsDTO son las facturas anuladas en un rango y lo que sumaban, en la moneda base.
+type VoidTotalsDTO struct {
+	VoidCount int64   `json:"void_count"`
+	VoidTotal float64 `json:"void_total"`
+}
+
+type SalesSummaryReportDTO struct {
+	From    time.Time               `json:"from"`
+	To      time.Time               `json:"to"`
+	GroupBy string                  `json:"group_by"`
+	Periods []SalesSummaryPeriodDTO `json:"periods"`
+	Totals  SalesSummaryPeriodDTO   `json:"totals"`
+}
+
+type DiscountUsageDTO struct {
+	DiscountTypeID   int     `json:"discount_type_id"`
+	DiscountTypeName string  `json:"discount_type_name"`
+	IsPercentage     bool    `json:"is_percentage"`
+	Value            float64 `json:"value"`
+	InvoiceCount     int64   `json:"invoice_count"`
+	Subtotal         float64 `json:"subtotal"`
+	Discounted       float64 `json:"discounted"`
+}
+
+type DiscountUsageReportDTO struct {
+	From            time.Time          `json:"from"`
+	To              time.Time          `json:"to"`
+	DiscountTypes   []DiscountUsageDTO `json:"discount_types"`
+	TotalDiscounted float64            `json:"total_discounted"`
+}
+
+type TopItemDTO struct {
+	ItemID       int     `json:"item_id"`
+	ItemName     string  `json:"item_name"`
+	UnitsSold    int64   `json:"units_sold"`
+	InvoiceCount int64   `json:"invoice_count"`
+	Revenue      float64 `json:"revenue"`
+}
+
+type TopItemsReportDTO struct {
+	From    time.Time    `json:"from"`
+	To      time.Time    `json:"to"`
+	OrderBy string       `json:"order_by"`
+	Items   []TopItemDTO `json:"items"`
+}
+
+type CustomerRevenueDTO struct {
+	CustomerID     int       `json:"customer_id"`
+	CustomerName   string    `json:"customer_name"`
+	DocumentNumber string    `json:"document_number"`
+	InvoiceCount   int64     `json:"invoice_count"`
+	Subtotal       float64   `json:"subtotal"`
+	Total          float64   `json:"total"`
+	LastInvoiceAt  time.Time `json:"last_invoice_at"`
+}
+
+type RevenueByCustomerReportDTO struct {
+	From      time.Time            `json:"from"`
+	To        time.Time            `json:"to"`
+	Customers []CustomerRevenueDTO `json:"customers"`
+}
@@ -0,0 +1,19 @@
+package dtos
+
+import (
+	"time"
+)
+
+type SecurityEventFilterDTO struct {
+	EventType string
+	User      string
+	From      *time.Time
+	To        *time.Time
+	PaginationDTO
+}
+
+type SecurityEventChainDTO struct {
+	Valid         bool `json:"valid"`
+	CheckedEvents int  `json:"checked_events"`
+	BrokenAtID    *int `json:"broken_at_id,omitempty"`
+}
@@ -0,0 +1,19 @@
+package dtos
+
+import "time"
+
+type StockMovementFilterDTO struct {
+	ItemID *int
+	Reason string
+	From   *time.Time
+	To     *time.Time
+	PaginationDTO
+}
+
+// StockBalanceDTO compara el stock guardado en el item con la suma de sus movimientos.
+type StockBalanceDTO struct {
+	ItemID      int    `json:"item_id"`
+	Name        string `json:"name"`
+	ItemStock   int    `json:"item_stock"`
+	LedgerStock int    `json:"ledger_stock"`
+}
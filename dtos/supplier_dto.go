@@ -0,0 +1,13 @@
+package dtos
+
+type SupplierDTO struct {
+	Name            string `json:"name" binding:"required,max=150"`
+	TaxID           string `json:"tax_id" binding:"required,max=50"`
+	ContactName     string `json:"contact_name" binding:"max=150"`
+	Email           string `json:"email" binding:"omitempty,email,max=254"`
+	PhoneNumbers    string `json:"phone_numbers" binding:"max=100"`
+	Address         string `json:"address" binding:"max=255"`
+	PaymentTermDays int    `json:"payment_term_days" binding:"min=0,max=365"`
+	// activo si se omite
+	SupplierState *bool `json:"supplier_state"`
+}
@@ -0,0 +1,10 @@
+package dtos
+
+type TaxTypeDTO struct {
+	Name         string  `json:"name" binding:"required,max=100"`
+	Description  string  `json:"description" binding:"max=300"`
+	IsPercentage bool    `json:"is_percentage"`
+	Value        float64 `json:"value" binding:"min=0"`
+	// al actualizar, se conserva si se omite; los impuestos nuevos quedan activos
+	Active *bool `json:"active"`
+}
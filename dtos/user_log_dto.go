@@ -0,0 +1,18 @@
+package dtos
+
+import (
+	"time"
+)
+
+type UserLogFilterDTO struct {
+	User     string
+	Endpoint string
+	Text     string
+	// coincidencia exacta con el X-Request-ID de la petición
+	RequestID string
+	From      *time.Time
+	To        *time.Time
+	SortBy    string
+	Order     string
+	PaginationDTO
+}
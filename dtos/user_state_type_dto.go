@@ -0,0 +1,6 @@
+package dtos
+
+type UserStateTypeDTO struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	AllowsLogin *bool  `json:"allows_login" binding:"required"`
+}
@@ -6,3 +6,20 @@ type UserTypeDTO struct {
 	Description string   `json:"description"`
 	Roles       []string `json:"roles"`
 }
+
+type CreateUserTypeDTO struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=300"`
+	// IDs de los roles del tipo de usuario; puede quedar sin roles
+	Roles []uint `json:"roles"`
+}
+
+type UpdateUserTypeDTO struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=300"`
+}
+
+// UpdateUserTypeRolesDTO es el conjunto completo de roles del tipo de usuario; los que no vienen se quitan.
+type UpdateUserTypeRolesDTO struct {
+	Roles []uint `json:"roles" binding:"required"`
+}
@@ -0,0 +1,53 @@
+package dtos
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type CreateWebhookSubscriptionDTO struct {
+	URL        string   `json:"url" binding:"required,url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types" binding:"required,min=1"`
+	Active     *bool    `json:"active"`
+}
+
+type UpdateWebhookSubscriptionDTO struct {
+	URL        string   `json:"url" binding:"required,url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types" binding:"required,min=1"`
+	Active     bool     `json:"active"`
+}
+
+// CreatedWebhookSubscriptionDTO es la única respuesta que incluye el secreto de firma.
+type CreatedWebhookSubscriptionDTO struct {
+	ID         int       `json:"id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"secret"`
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type GetWebhookDeliveryDTO struct {
+	ID             int             `json:"id"`
+	SubscriptionID int             `json:"subscription_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	LastStatusCode int             `json:"last_status_code"`
+	LastError      string          `json:"last_error"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// StockChangedEventDTO es el payload de item.stock_changed. Stock solo se incluye cuando se
+// conoce el valor resultante.
+type StockChangedEventDTO struct {
+	ItemID int    `json:"item_id"`
+	Change int    `json:"change"`
+	Stock  *int   `json:"stock,omitempty"`
+	Reason string `json:"reason"`
+}
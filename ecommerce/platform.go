@@ -0,0 +1,118 @@
+package ecommerce
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+	"totesbackend/config"
+)
+
+const (
+	ECOMMERCE_PROVIDER_SHOPIFY     = "shopify"
+	ECOMMERCE_PROVIDER_WOOCOMMERCE = "woocommerce"
+)
+
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+// Product es un producto de la tienda cuyo SKU es el ID de un ítem de este sistema.
+type Product struct {
+	SKU string
+	// ExternalID es el ID de la variante en Shopify o del producto en WooCommerce
+	ExternalID string
+	// InventoryID es el inventory item de Shopify; vacío en WooCommerce
+	InventoryID string
+	Name        string
+	Price       float64
+	// Stock es nil si la tienda no controla el inventario del producto
+	Stock *int
+}
+
+type OrderCustomer struct {
+	Email     string
+	FirstName string
+	LastName  string
+	Phone     string
+	Address   string
+}
+
+type OrderLine struct {
+	SKU       string
+	Quantity  int
+	UnitPrice float64
+}
+
+// Order es un pedido hecho en la tienda en línea.
+type Order struct {
+	ExternalID string
+	Number     string
+	Date       time.Time
+	Customer   OrderCustomer
+	Lines      []OrderLine
+	Total      float64
+}
+
+// Platform es el adaptador de una tienda en línea. Los productos se relacionan con los ítems por
+// SKU, que debe ser el ID del ítem.
+type Platform interface {
+	Provider() string
+	// ListProducts devuelve todos los productos de la tienda que tienen SKU.
+	ListProducts(ctx context.Context) ([]Product, error)
+	// UpdateProduct fija el precio y las existencias del producto.
+	UpdateProduct(ctx context.Context, product Product, price float64, stock int) error
+	// ParseOrderWebhook verifica la firma de un webhook de la tienda y devuelve el pedido creado.
+	// Devuelve nil, nil para los eventos que no son pedidos nuevos.
+	ParseOrderWebhook(header http.Header, body []byte) (*Order, error)
+}
+
+// NewPlatform crea el adaptador de la tienda configurada en ECOMMERCE_PROVIDER. Devuelve nil si la
+// sincronización está desactivada.
+func NewPlatform(cfg config.EcommerceConfig) (Platform, error) {
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case ECOMMERCE_PROVIDER_SHOPIFY:
+		return NewShopifyPlatform(cfg), nil
+	case ECOMMERCE_PROVIDER_WOOCOMMERCE:
+		return NewWooCommercePlatform(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown e-commerce provider %q", cfg.Provider)
+	}
+}
+
+// validSignature compara signature con el HMAC-SHA256 del cuerpo en base64, el esquema que usan
+// tanto Shopify como WooCommerce.
+func validSignature(secret string, body []byte, signature string) bool {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return signature != "" && hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// do envía la petición y devuelve la respuesta con el cuerpo leído; un estado fuera de 2xx es un
+// error.
+func do(client *http.Client, req *http.Request) (*http.Response, []byte, error) {
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 8<<20))
+	if err != nil {
+		return resp, nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if len(body) > 300 {
+			body = body[:300]
+		}
+		return resp, body, fmt.Errorf("store responded %s: %s", resp.Status, body)
+	}
+	return resp, body, nil
+}
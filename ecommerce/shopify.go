@@ -0,0 +1,208 @@
+package ecommerce
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"totesbackend/config"
+)
+
+const shopifyAPIVersion = "2024-01"
+
+var shopifyNextLink = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// ShopifyPlatform usa la Admin REST API de Shopify con el token de una app privada. Las
+// existencias se fijan en la bodega ECOMMERCE_LOCATION_ID.
+type ShopifyPlatform struct {
+	Config config.EcommerceConfig
+	Client *http.Client
+}
+
+func NewShopifyPlatform(cfg config.EcommerceConfig) *ShopifyPlatform {
+	return &ShopifyPlatform{Config: cfg, Client: &http.Client{Timeout: config.ECOMMERCE_REQUEST_TIMEOUT}}
+}
+
+func (p *ShopifyPlatform) Provider() string {
+	return ECOMMERCE_PROVIDER_SHOPIFY
+}
+
+func (p *ShopifyPlatform) ListProducts(ctx context.Context) ([]Product, error) {
+	var products []Product
+	next := p.url("/products.json?limit=250&fields=id,title,variants")
+	for next != "" {
+		resp, body, err := p.request(ctx, http.MethodGet, next, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page struct {
+			Products []struct {
+				Title    string `json:"title"`
+				Variants []struct {
+					ID                int64  `json:"id"`
+					SKU               string `json:"sku"`
+					Price             string `json:"price"`
+					InventoryItemID   int64  `json:"inventory_item_id"`
+					InventoryQuantity int    `json:"inventory_quantity"`
+					InventoryTracked  string `json:"inventory_management"`
+				} `json:"variants"`
+			} `json:"products"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, err
+		}
+		for _, product := range page.Products {
+			for _, variant := range product.Variants {
+				if strings.TrimSpace(variant.SKU) == "" {
+					continue
+				}
+				price, _ := strconv.ParseFloat(variant.Price, 64)
+				found := Product{
+					SKU:         strings.TrimSpace(variant.SKU),
+					ExternalID:  strconv.FormatInt(variant.ID, 10),
+					InventoryID: strconv.FormatInt(variant.InventoryItemID, 10),
+					Name:        product.Title,
+					Price:       price,
+				}
+				if variant.InventoryTracked == "shopify" {
+					stock := variant.InventoryQuantity
+					found.Stock = &stock
+				}
+				products = append(products, found)
+			}
+		}
+
+		// la paginación va en el encabezado Link
+		next = ""
+		if match := shopifyNextLink.FindStringSubmatch(resp.Header.Get("Link")); match != nil {
+			next = match[1]
+		}
+	}
+	return products, nil
+}
+
+func (p *ShopifyPlatform) UpdateProduct(ctx context.Context, product Product, price float64, stock int) error {
+	variant := map[string]interface{}{
+		"variant": map[string]interface{}{
+			"id":    product.ExternalID,
+			"price": strconv.FormatFloat(price, 'f', 2, 64),
+		},
+	}
+	if _, _, err := p.request(ctx, http.MethodPut, p.url("/variants/"+product.ExternalID+".json"), variant); err != nil {
+		return err
+	}
+
+	level := map[string]interface{}{
+		"location_id":       p.Config.LocationID,
+		"inventory_item_id": product.InventoryID,
+		"available":         stock,
+	}
+	_, _, err := p.request(ctx, http.MethodPost, p.url("/inventory_levels/set.json"), level)
+	return err
+}
+
+func (p *ShopifyPlatform) ParseOrderWebhook(header http.Header, body []byte) (*Order, error) {
+	if !validSignature(p.Config.WebhookSecret, body, header.Get("X-Shopify-Hmac-Sha256")) {
+		return nil, ErrInvalidWebhookSignature
+	}
+	if header.Get("X-Shopify-Topic") != "orders/create" {
+		return nil, nil
+	}
+
+	var payload struct {
+		ID         int64     `json:"id"`
+		Name       string    `json:"name"`
+		Email      string    `json:"email"`
+		CreatedAt  time.Time `json:"created_at"`
+		TotalPrice string    `json:"total_price"`
+		Customer   struct {
+			Email     string `json:"email"`
+			FirstName string `json:"first_name"`
+			LastName  string `json:"last_name"`
+			Phone     string `json:"phone"`
+		} `json:"customer"`
+		ShippingAddress struct {
+			Address1 string `json:"address1"`
+			City     string `json:"city"`
+			Phone    string `json:"phone"`
+		} `json:"shipping_address"`
+		LineItems []struct {
+			SKU      string `json:"sku"`
+			Quantity int    `json:"quantity"`
+			Price    string `json:"price"`
+		} `json:"line_items"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	order := &Order{
+		ExternalID: strconv.FormatInt(payload.ID, 10),
+		Number:     payload.Name,
+		Date:       payload.CreatedAt,
+		Customer: OrderCustomer{
+			Email:     firstNonEmpty(payload.Customer.Email, payload.Email),
+			FirstName: payload.Customer.FirstName,
+			LastName:  payload.Customer.LastName,
+			Phone:     firstNonEmpty(payload.Customer.Phone, payload.ShippingAddress.Phone),
+			Address:   joinAddress(payload.ShippingAddress.Address1, payload.ShippingAddress.City),
+		},
+	}
+	order.Total, _ = strconv.ParseFloat(payload.TotalPrice, 64)
+	for _, line := range payload.LineItems {
+		price, _ := strconv.ParseFloat(line.Price, 64)
+		order.Lines = append(order.Lines, OrderLine{SKU: strings.TrimSpace(line.SKU), Quantity: line.Quantity, UnitPrice: price})
+	}
+	return order, nil
+}
+
+func (p *ShopifyPlatform) url(path string) string {
+	return p.Config.StoreURL + "/admin/api/" + shopifyAPIVersion + path
+}
+
+func (p *ShopifyPlatform) request(ctx context.Context, method, url string, payload interface{}) (*http.Response, []byte, error) {
+	var reader *bytes.Reader
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return nil, nil, err
+		}
+		reader = bytes.NewReader(encoded)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("X-Shopify-Access-Token", p.Config.AccessToken)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return do(p.Client, req)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+func joinAddress(parts ...string) string {
+	var nonEmpty []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, ", ")
+}
@@ -0,0 +1,159 @@
+package ecommerce
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"totesbackend/config"
+)
+
+// WooCommercePlatform usa la REST API v3 de WooCommerce con una clave de consumidor. Solo se
+// sincronizan productos simples: las variaciones no se listan.
+type WooCommercePlatform struct {
+	Config config.EcommerceConfig
+	Client *http.Client
+}
+
+func NewWooCommercePlatform(cfg config.EcommerceConfig) *WooCommercePlatform {
+	return &WooCommercePlatform{Config: cfg, Client: &http.Client{Timeout: config.ECOMMERCE_REQUEST_TIMEOUT}}
+}
+
+func (p *WooCommercePlatform) Provider() string {
+	return ECOMMERCE_PROVIDER_WOOCOMMERCE
+}
+
+func (p *WooCommercePlatform) ListProducts(ctx context.Context) ([]Product, error) {
+	var products []Product
+	for page, totalPages := 1, 1; page <= totalPages; page++ {
+		resp, body, err := p.request(ctx, http.MethodGet, "/products?per_page=100&page="+strconv.Itoa(page), nil)
+		if err != nil {
+			return nil, err
+		}
+		if total, err := strconv.Atoi(resp.Header.Get("X-WP-TotalPages")); err == nil {
+			totalPages = total
+		}
+
+		var items []struct {
+			ID            int64  `json:"id"`
+			Name          string `json:"name"`
+			SKU           string `json:"sku"`
+			RegularPrice  string `json:"regular_price"`
+			ManageStock   bool   `json:"manage_stock"`
+			StockQuantity *int   `json:"stock_quantity"`
+		}
+		if err := json.Unmarshal(body, &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if strings.TrimSpace(item.SKU) == "" {
+				continue
+			}
+			price, _ := strconv.ParseFloat(item.RegularPrice, 64)
+			product := Product{
+				SKU:        strings.TrimSpace(item.SKU),
+				ExternalID: strconv.FormatInt(item.ID, 10),
+				Name:       item.Name,
+				Price:      price,
+			}
+			if item.ManageStock {
+				product.Stock = item.StockQuantity
+			}
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
+
+func (p *WooCommercePlatform) UpdateProduct(ctx context.Context, product Product, price float64, stock int) error {
+	update := map[string]interface{}{
+		"regular_price":  strconv.FormatFloat(price, 'f', 2, 64),
+		"manage_stock":   true,
+		"stock_quantity": stock,
+	}
+	_, _, err := p.request(ctx, http.MethodPut, "/products/"+product.ExternalID, update)
+	return err
+}
+
+func (p *WooCommercePlatform) ParseOrderWebhook(header http.Header, body []byte) (*Order, error) {
+	// al crear el webhook WooCommerce envía un ping sin firma ni tema
+	if header.Get("X-WC-Webhook-Topic") == "" && header.Get("X-WC-Webhook-Signature") == "" {
+		return nil, nil
+	}
+	if !validSignature(p.Config.WebhookSecret, body, header.Get("X-WC-Webhook-Signature")) {
+		return nil, ErrInvalidWebhookSignature
+	}
+	if header.Get("X-WC-Webhook-Topic") != "order.created" {
+		return nil, nil
+	}
+
+	var payload struct {
+		ID             int64  `json:"id"`
+		Number         string `json:"number"`
+		DateCreatedGMT string `json:"date_created_gmt"`
+		Total          string `json:"total"`
+		Billing        struct {
+			FirstName string `json:"first_name"`
+			LastName  string `json:"last_name"`
+			Email     string `json:"email"`
+			Phone     string `json:"phone"`
+			Address1  string `json:"address_1"`
+			City      string `json:"city"`
+		} `json:"billing"`
+		LineItems []struct {
+			SKU      string  `json:"sku"`
+			Quantity int     `json:"quantity"`
+			Price    float64 `json:"price"`
+		} `json:"line_items"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	order := &Order{
+		ExternalID: strconv.FormatInt(payload.ID, 10),
+		Number:     payload.Number,
+		Customer: OrderCustomer{
+			Email:     strings.TrimSpace(payload.Billing.Email),
+			FirstName: payload.Billing.FirstName,
+			LastName:  payload.Billing.LastName,
+			Phone:     payload.Billing.Phone,
+			Address:   joinAddress(payload.Billing.Address1, payload.Billing.City),
+		},
+	}
+	// las fechas _gmt vienen en UTC sin zona
+	if date, err := time.Parse("2006-01-02T15:04:05", payload.DateCreatedGMT); err == nil {
+		order.Date = date
+	}
+	order.Total, _ = strconv.ParseFloat(payload.Total, 64)
+	for _, line := range payload.LineItems {
+		order.Lines = append(order.Lines, OrderLine{SKU: strings.TrimSpace(line.SKU), Quantity: line.Quantity, UnitPrice: line.Price})
+	}
+	return order, nil
+}
+
+func (p *WooCommercePlatform) request(ctx context.Context, method, path string, payload interface{}) (*http.Response, []byte, error) {
+	var reader *bytes.Reader
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return nil, nil, err
+		}
+		reader = bytes.NewReader(encoded)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.Config.StoreURL+"/wp-json/wc/v3"+path, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.SetBasicAuth(p.Config.ConsumerKey, p.Config.ConsumerSecret)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return do(p.Client, req)
+}
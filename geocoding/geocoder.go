@@ -0,0 +1,78 @@
+package geocoding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"totesbackend/config"
+)
+
+const (
+	GEOCODING_PROVIDER_GOOGLE    = "google"
+	GEOCODING_PROVIDER_NOMINATIM = "nominatim"
+)
+
+// ErrAddressNotFound indica que el proveedor no encontró la dirección. Cualquier otro error es
+// transitorio y la búsqueda se puede repetir más tarde.
+var ErrAddressNotFound = errors.New("address not found")
+
+// Location es una dirección encontrada por el proveedor.
+type Location struct {
+	// Address es la dirección normalizada como la escribe el proveedor
+	Address   string
+	Latitude  float64
+	Longitude float64
+}
+
+type Geocoder interface {
+	Provider() string
+	Geocode(ctx context.Context, address string) (*Location, error)
+}
+
+// NewGeocoder crea el geocodificador del proveedor configurado en GEOCODING_PROVIDER. Devuelve nil
+// si la geocodificación está desactivada.
+func NewGeocoder(cfg config.GeocodingConfig) (Geocoder, error) {
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case GEOCODING_PROVIDER_GOOGLE:
+		return NewGoogleGeocoder(cfg), nil
+	case GEOCODING_PROVIDER_NOMINATIM:
+		return NewNominatimGeocoder(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown geocoding provider %q", cfg.Provider)
+	}
+}
+
+// getJSON hace un GET a endpoint con query y devuelve el cuerpo de la respuesta.
+func getJSON(ctx context.Context, client *http.Client, endpoint string, query url.Values, userAgent string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if len(body) > 300 {
+			body = body[:300]
+		}
+		return nil, fmt.Errorf("geocoding provider responded %s: %s", resp.Status, body)
+	}
+	return body, nil
+}
@@ -0,0 +1,79 @@
+package geocoding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"totesbackend/config"
+)
+
+// GoogleGeocoder usa la Geocoding API de Google Maps.
+type GoogleGeocoder struct {
+	Config config.GeocodingConfig
+	Client *http.Client
+}
+
+func NewGoogleGeocoder(cfg config.GeocodingConfig) *GoogleGeocoder {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://maps.googleapis.com"
+	}
+	return &GoogleGeocoder{Config: cfg, Client: &http.Client{Timeout: config.GEOCODING_REQUEST_TIMEOUT}}
+}
+
+func (g *GoogleGeocoder) Provider() string {
+	return GEOCODING_PROVIDER_GOOGLE
+}
+
+func (g *GoogleGeocoder) Geocode(ctx context.Context, address string) (*Location, error) {
+	query := url.Values{}
+	query.Set("address", address)
+	query.Set("key", g.Config.APIKey)
+	query.Set("language", "es")
+	if g.Config.Country != "" {
+		query.Set("components", "country:"+strings.ToUpper(g.Config.Country))
+	}
+
+	body, err := getJSON(ctx, g.Client, g.Config.BaseURL+"/maps/api/geocode/json", query, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Status       string `json:"status"`
+		ErrorMessage string `json:"error_message"`
+		Results      []struct {
+			FormattedAddress string `json:"formatted_address"`
+			Geometry         struct {
+				Location struct {
+					Lat float64 `json:"lat"`
+					Lng float64 `json:"lng"`
+				} `json:"location"`
+			} `json:"geometry"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+
+	switch response.Status {
+	case "OK":
+	case "ZERO_RESULTS":
+		return nil, ErrAddressNotFound
+	default:
+		// OVER_QUERY_LIMIT, REQUEST_DENIED, UNKNOWN_ERROR...
+		return nil, fmt.Errorf("google geocoding returned %s: %s", response.Status, response.ErrorMessage)
+	}
+	if len(response.Results) == 0 {
+		return nil, ErrAddressNotFound
+	}
+
+	result := response.Results[0]
+	return &Location{
+		Address:   result.FormattedAddress,
+		Latitude:  result.Geometry.Location.Lat,
+		Longitude: result.Geometry.Location.Lng,
+	}, nil
+}
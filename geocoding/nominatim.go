@@ -0,0 +1,94 @@
+package geocoding
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+	"totesbackend/config"
+)
+
+// NominatimGeocoder usa Nominatim (OpenStreetMap). La instancia pública no pide API key pero
+// admite una petición por segundo, así que las búsquedas se espacian.
+type NominatimGeocoder struct {
+	Config config.GeocodingConfig
+	Client *http.Client
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+func NewNominatimGeocoder(cfg config.GeocodingConfig) *NominatimGeocoder {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://nominatim.openstreetmap.org"
+	}
+	return &NominatimGeocoder{Config: cfg, Client: &http.Client{Timeout: config.GEOCODING_REQUEST_TIMEOUT}}
+}
+
+func (n *NominatimGeocoder) Provider() string {
+	return GEOCODING_PROVIDER_NOMINATIM
+}
+
+func (n *NominatimGeocoder) Geocode(ctx context.Context, address string) (*Location, error) {
+	if err := n.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("q", address)
+	query.Set("format", "jsonv2")
+	query.Set("limit", "1")
+	query.Set("accept-language", "es")
+	if n.Config.Country != "" {
+		query.Set("countrycodes", n.Config.Country)
+	}
+
+	// la política de uso exige identificar la aplicación
+	body, err := getJSON(ctx, n.Client, n.Config.BaseURL+"/search", query, "totesbackend (TotesBGA CRM)")
+	if err != nil {
+		return nil, err
+	}
+
+	var results []struct {
+		DisplayName string `json:"display_name"`
+		Lat         string `json:"lat"`
+		Lon         string `json:"lon"`
+	}
+	if err := json.Unmarshal(body, &results); err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrAddressNotFound
+	}
+
+	latitude, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return nil, err
+	}
+	longitude, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &Location{Address: results[0].DisplayName, Latitude: latitude, Longitude: longitude}, nil
+}
+
+// wait deja pasar al menos GEOCODING_NOMINATIM_INTERVAL entre dos peticiones.
+func (n *NominatimGeocoder) wait(ctx context.Context) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if delay := time.Until(n.last.Add(config.GEOCODING_NOMINATIM_INTERVAL)); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	n.last = time.Now()
+	return nil
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"log"
+	"os"
 	"totesbackend/app"
 	_ "totesbackend/docs"
 )
@@ -20,7 +22,22 @@ import (
 // @BasePath  /
 
 // @schemes http https
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name Authorization
+// @description Access token from POST /login, as "Bearer <token>"
 func main() {
+	// administrative commands, e.g. "migrate status"
+	if len(os.Args) > 1 {
+		if err := app.RunCommand(os.Args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	// Load environment variables and run the application
-	app.SetupAndRunApp()
+	if err := app.SetupAndRunApp(); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// AccountingSync es el estado del envío de un documento (factura o pago) al sistema contable. Hay
+// una fila por documento; ExternalID y ExternalNumber son el ID y el número que le asignó el
+// sistema contable.
+type AccountingSync struct {
+	ID             int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	DocumentType   string     `gorm:"size:20;not null;uniqueIndex:idx_accounting_sync_document" json:"document_type"`
+	DocumentID     int        `gorm:"not null;uniqueIndex:idx_accounting_sync_document" json:"document_id"`
+	Provider       string     `gorm:"size:20;not null" json:"provider"`
+	Status         string     `gorm:"size:20;not null;index" json:"status"`
+	Attempts       int        `gorm:"not null;default:0" json:"attempts"`
+	ExternalID     string     `gorm:"size:100" json:"external_id,omitempty"`
+	ExternalNumber string     `gorm:"size:100" json:"external_number,omitempty"`
+	LastError      string     `gorm:"size:500" json:"last_error,omitempty"`
+	NextAttemptAt  time.Time  `gorm:"not null;index" json:"next_attempt_at"`
+	SyncedAt       *time.Time `json:"synced_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	RequestID      string     `gorm:"size:64" json:"request_id,omitempty"`
+}
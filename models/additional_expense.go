@@ -6,4 +6,6 @@ type AdditionalExpense struct {
 	ItemID      int     `gorm:"size:50;not null;index" json:"item_id"`
 	Expense     float64 `gorm:"not null" json:"expense"`
 	Description string  `gorm:"size:200" json:"description,omitempty"`
+	// proveedor que cobra el gasto, si se conoce
+	SupplierID *int `gorm:"index" json:"supplier_id,omitempty"`
 }
@@ -2,17 +2,23 @@ package models
 
 import "time"
 
+// Appointment es una cita. StateID solo cambia con PATCH /appointments/{id}/state; las citas nuevas
+// quedan pendientes.
 type Appointment struct {
-	ID               int       `gorm:"primaryKey;autoIncrement" json:"id"`
-	DateTime         time.Time `gorm:"type:timestamp;not null" json:"dateTime"`
-	State            bool      `gorm:"not null" json:"state"`
-	CustomerID       int       `gorm:"not null;index" json:"customerId"`
-	CustomerName     string    `gorm:"size:255;not null" json:"customerName"`
-	IsBusiness       bool      `gorm:"not null" json:"isBusiness"`
-	Address          string    `gorm:"size:100" json:"address,omitempty"`
-	PhoneNumbers     string    `gorm:"size:100" json:"phoneNumbers,omitempty"`
-	CustomerState    bool      `gorm:"not null" json:"customerState"`
-	Email            string    `gorm:"size:255;not null" json:"email"`
-	LastName         string    `gorm:"size:255;not null" json:"lastName"`
-	IdentifierTypeID int       `gorm:"not null" json:"identifierTypeId"`
+	ID         int                   `gorm:"primaryKey;autoIncrement" json:"id"`
+	DateTime   time.Time             `gorm:"type:timestamp;not null" json:"dateTime"`
+	StateID    int                   `gorm:"not null;index" json:"stateId"`
+	State      *AppointmentStateType `gorm:"foreignKey:StateID;references:ID" json:"state,omitempty"`
+	CustomerID int                   `gorm:"not null;index" json:"customerId"`
+	// empleado que atiende la cita; sin él la cita ocupa uno de los cupos generales del horario
+	EmployeeID       *int   `gorm:"index" json:"employeeId,omitempty"`
+	CustomerName     string `gorm:"size:255;not null" json:"customerName"`
+	IsBusiness       bool   `gorm:"not null" json:"isBusiness"`
+	Address          string `gorm:"size:100" json:"address,omitempty"`
+	PhoneNumbers     string `gorm:"size:100" json:"phoneNumbers,omitempty"`
+	CustomerState    bool   `gorm:"not null" json:"customerState"`
+	Email            string `gorm:"size:255;not null" json:"email"`
+	LastName         string `gorm:"size:255;not null" json:"lastName"`
+	IdentifierTypeID int    `gorm:"not null" json:"identifierTypeId"`
+	Version          int    `gorm:"not null;default:1" json:"version"`
 }
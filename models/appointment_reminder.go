@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// AppointmentReminder registra cada recordatorio de una cita, se haya enviado o no. Hay uno por
+// etapa (HoursBefore) y fecha de la cita: si se reprograma, se recuerda de nuevo.
+type AppointmentReminder struct {
+	ID             int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	AppointmentID  int       `gorm:"not null;uniqueIndex:idx_appointment_reminder_stage" json:"appointment_id"`
+	HoursBefore    int       `gorm:"not null;uniqueIndex:idx_appointment_reminder_stage" json:"hours_before"`
+	DateTime       time.Time `gorm:"type:timestamp;not null;uniqueIndex:idx_appointment_reminder_stage" json:"date_time"`
+	Status         string    `gorm:"size:20;not null" json:"status"`
+	Email          string    `gorm:"size:254" json:"email"`
+	EmailMessageID *int      `json:"email_message_id"`
+	TextMessageID  *int      `json:"text_message_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
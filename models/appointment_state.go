@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// AppointmentStateType es un estado de cita. Los IDs vienen del seed y los usan las transiciones de
+// services.AppointmentService.
+type AppointmentStateType struct {
+	ID   int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name string `gorm:"size:50;not null;uniqueIndex" json:"name"`
+}
+
+// AppointmentStateChange es una entrada del historial de estados de una cita.
+type AppointmentStateChange struct {
+	ID            int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	AppointmentID int       `gorm:"not null;index" json:"appointmentId"`
+	FromStateID   int       `gorm:"not null" json:"fromStateId"`
+	ToStateID     int       `gorm:"not null" json:"toStateId"`
+	Reason        string    `gorm:"size:300" json:"reason,omitempty"`
+	ChangedBy     string    `gorm:"size:255" json:"changedBy,omitempty"`
+	ChangedAt     time.Time `gorm:"not null" json:"changedAt"`
+}
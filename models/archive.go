@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// ArchivedInvoice es una factura que el archivado sacó de invoices. Document guarda la factura
+// completa (ítems, descuentos e impuestos) tal como la devolvía la API al archivarse.
+type ArchivedInvoice struct {
+	ID         int       `gorm:"primaryKey;autoIncrement:false" json:"id"`
+	CustomerID int       `gorm:"not null;index" json:"customer_id"`
+	DateTime   time.Time `gorm:"not null;index" json:"date_time"`
+	Document   string    `gorm:"type:jsonb;not null" json:"-"`
+	ArchivedAt time.Time `gorm:"not null" json:"archived_at"`
+}
+
+// ArchivedAppointment es el equivalente de ArchivedInvoice para las citas.
+type ArchivedAppointment struct {
+	ID         int       `gorm:"primaryKey;autoIncrement:false" json:"id"`
+	CustomerID int       `gorm:"not null;index" json:"customer_id"`
+	DateTime   time.Time `gorm:"type:timestamp;not null;index" json:"date_time"`
+	Document   string    `gorm:"type:jsonb;not null" json:"-"`
+	ArchivedAt time.Time `gorm:"not null" json:"archived_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type AuditEntry struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Entity    string    `gorm:"size:50;not null;index:idx_audit_entity" json:"entity"`
+	EntityID  string    `gorm:"size:50;not null;index:idx_audit_entity" json:"entity_id"`
+	Action    string    `gorm:"size:20;not null" json:"action"`
+	UserEmail string    `gorm:"size:80" json:"user_email"`
+	Before    string    `gorm:"type:jsonb" json:"-"`
+	After     string    `gorm:"type:jsonb" json:"-"`
+	DateTime  time.Time `gorm:"not null" json:"date_time"`
+	RequestID string    `gorm:"size:64;index" json:"request_id,omitempty"`
+}
@@ -0,0 +1,11 @@
+package models
+
+// BusinessHours es el horario de atención de un día de la semana (0 domingo … 6 sábado): franjas
+// de una hora desde OpeningHour hasta la que empieza a LastSlotHour. Los días sin fila usan el
+// horario por defecto de config.
+type BusinessHours struct {
+	Weekday      int  `gorm:"primaryKey;autoIncrement:false" json:"weekday"`
+	Open         bool `gorm:"not null" json:"open"`
+	OpeningHour  int  `gorm:"not null" json:"openingHour"`
+	LastSlotHour int  `gorm:"not null" json:"lastSlotHour"`
+}
@@ -9,4 +9,5 @@ type Comment struct {
 	ResidenceState string `gorm:"size:50" json:"residenceState,omitempty"`
 	ResidenceCity  string `gorm:"size:50" json:"residenceCity,omitempty"`
 	Comment        string `gorm:"size:1000" json:"comment,omitempty"`
+	Reviewed       bool   `gorm:"not null;default:false" json:"reviewed"`
 }
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// CreditNote anula una factura completa: devuelve al stock lo facturado y guarda el motivo. Una
+// factura tiene a lo sumo una nota crédito.
+type CreditNote struct {
+	ID        int              `gorm:"primaryKey;autoIncrement" json:"id"`
+	InvoiceID int              `gorm:"not null;uniqueIndex" json:"invoice_id"`
+	Reason    string           `gorm:"size:300;not null" json:"reason"`
+	Items     []CreditNoteItem `gorm:"foreignKey:CreditNoteID" json:"items"`
+	Subtotal  float64          `gorm:"not null" json:"subtotal"`
+	Total     float64          `gorm:"not null" json:"total"`
+	CreatedBy string           `gorm:"size:80" json:"created_by,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// CreditNoteItem son las unidades de un item que volvieron al stock con la nota.
+type CreditNoteItem struct {
+	CreditNoteID int   `gorm:"primaryKey" json:"-"`
+	ItemID       int   `gorm:"primaryKey;autoIncrement:false" json:"item_id"`
+	Item         *Item `gorm:"foreignKey:ItemID;references:ID" json:"item,omitempty"`
+	Amount       int   `gorm:"not null" json:"amount"`
+}
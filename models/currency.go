@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Currency es una moneda en la que se fijan precios y se factura. ExchangeRate es cuánto vale una
+// unidad en la moneda base (config.BASE_CURRENCY); las conversiones usan la tasa guardada.
+type Currency struct {
+	Code         string    `gorm:"primaryKey;size:3" json:"code"`
+	Name         string    `gorm:"size:100;not null" json:"name"`
+	Symbol       string    `gorm:"size:10;not null" json:"symbol"`
+	ExchangeRate float64   `gorm:"not null" json:"exchange_rate"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
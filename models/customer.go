@@ -1,14 +1,36 @@
 package models
 
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
 type Customer struct {
 	ID               int    `gorm:"primaryKey;autoIncrement" json:"id"`
 	CustomerName     string `gorm:"size:255; null" json:"customerName"` // puede ser nulo
-	CustomerId       string `gorm:"size:100;not null;unique" json:"customerId"`
+	CustomerId       string `gorm:"size:100;not null;uniqueIndex:idx_customers_customer_id,where:deleted_at IS NULL" json:"customerId"`
 	IsBusiness       bool   `gorm:"not null" json:"isBusiness"`
-	Address          string `gorm:"size:100" json:"address,omitempty"`
+	Address          string `gorm:"size:255" json:"address,omitempty"`
 	PhoneNumbers     string `gorm:"size:100" json:"phoneNumbers,omitempty"`
 	CustomerState    bool   `gorm:"not null" json:"customerState"`
-	Email            string `gorm:"size:255;not null;unique" json:"email"`
+	Email            string `gorm:"size:255;not null;uniqueIndex:idx_customers_email,where:deleted_at IS NULL" json:"email"`
 	LastName         string `gorm:"size:255;not null" json:"lastName"`
 	IdentifierTypeID int    `gorm:"not null" json:"identifierTypeId"`
+	Version          int    `gorm:"not null;default:1" json:"version"`
+	// Ubicación de la dirección según el proveedor de geocodificación. AddressStatus es "verified",
+	// "not_found" o vacío mientras no se ha buscado
+	Latitude      *float64   `json:"latitude,omitempty"`
+	Longitude     *float64   `json:"longitude,omitempty"`
+	AddressStatus string     `gorm:"size:20;not null;default:''" json:"addressStatus,omitempty"`
+	GeocodedAt    *time.Time `json:"geocodedAt,omitempty"`
+	// Borrado lógico: las facturas, citas y órdenes que lo referencian se conservan y se puede
+	// restaurar. El documento y el correo solo son únicos entre los clientes no borrados
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty"`
+	// TextChannel es por dónde recibe avisos en su teléfono: "sms", "whatsapp" o vacío si no los quiere
+	TextChannel string `gorm:"size:10;not null;default:''" json:"textChannel,omitempty"`
+	// lista de precios especiales del cliente; sin ella compra a los precios normales
+	PriceListID *int `gorm:"index" json:"priceListId,omitempty"`
+	// solo se escribe al crear; los clientes anteriores a la migración 27 no la tienen
+	CreatedAt *time.Time `gorm:"<-:create;index" json:"createdAt,omitempty"`
 }
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+type DailyClose struct {
+	ID               int                 `gorm:"primaryKey;autoIncrement" json:"id"`
+	Date             time.Time           `gorm:"type:date;not null;uniqueIndex" json:"date"`
+	InvoiceCount     int64               `gorm:"not null" json:"invoice_count"`
+	Subtotal         float64             `gorm:"not null" json:"subtotal"`
+	Tax              float64             `gorm:"not null" json:"tax"`
+	Discount         float64             `gorm:"not null" json:"discount"`
+	Total            float64             `gorm:"not null" json:"total"`
+	VoidCount        int64               `gorm:"not null" json:"void_count"`
+	VoidTotal        float64             `gorm:"not null" json:"void_total"`
+	CashExpected     float64             `gorm:"not null" json:"cash_expected"`
+	PaymentsByMethod []DailyClosePayment `gorm:"foreignKey:DailyCloseID" json:"payments_by_method"`
+	ClosedBy         string              `gorm:"size:100" json:"closed_by,omitempty"`
+	ClosedAt         *time.Time          `json:"closed_at,omitempty"`
+	Frozen           bool                `gorm:"-" json:"frozen"`
+}
+
+type DailyClosePayment struct {
+	DailyCloseID int     `gorm:"primaryKey" json:"-"`
+	Method       string  `gorm:"primaryKey;size:50" json:"method"`
+	Amount       float64 `gorm:"not null" json:"amount"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// DataExport es un pedido de exportación completa de los datos. El worker de exportaciones lo
+// toma, genera el zip en el directorio de exportaciones y guarda su nombre en FileName.
+type DataExport struct {
+	ID          int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	Status      string     `gorm:"size:20;not null;index" json:"status"`
+	RequestedBy string     `gorm:"size:80" json:"requested_by"`
+	FileName    string     `gorm:"size:100" json:"file_name,omitempty"`
+	SizeBytes   int64      `json:"size_bytes"`
+	Error       string     `gorm:"size:500" json:"error,omitempty"`
+	CreatedAt   time.Time  `gorm:"not null" json:"created_at"`
+	StartedAt   *time.Time `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	RequestID   string     `gorm:"size:64" json:"request_id,omitempty"`
+}
@@ -1,9 +1,15 @@
 package models
 
+import "time"
+
 type DiscountType struct {
 	ID           int     `gorm:"primaryKey;autoIncrement;size:50" json:"id"`
 	Name         string  `gorm:"size:100;not null" json:"name"`
 	Description  string  `gorm:"size:300" json:"description,omitempty"`
 	IsPercentage bool    `gorm:"not null" json:"is_percentage"`
 	Value        float64 `gorm:"not null" json:"value"`
+	// inactivo o fuera de [ValidFrom, ValidTo] no se aplica a facturas ni cotizaciones nuevas
+	Active    bool       `gorm:"not null;default:true" json:"active"`
+	ValidFrom *time.Time `json:"valid_from,omitempty"`
+	ValidTo   *time.Time `json:"valid_to,omitempty"`
 }
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// EcommerceListing enlaza un ítem con su producto en la tienda en línea, cuyo SKU es el ID del
+// ítem. SyncedStock y SyncedPrice son los últimos valores que se enviaron (o que tenía la tienda
+// al encontrar el producto): el ítem se vuelve a enviar cuando dejan de coincidir con los suyos.
+type EcommerceListing struct {
+	ItemID        int        `gorm:"primaryKey;autoIncrement:false" json:"item_id"`
+	ExternalID    string     `gorm:"size:100;not null" json:"external_id"`
+	InventoryID   string     `gorm:"size:100" json:"inventory_id,omitempty"`
+	SyncedStock   *int       `json:"synced_stock"`
+	SyncedPrice   *float64   `json:"synced_price"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	LastError     string     `gorm:"size:500" json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `gorm:"not null;index" json:"next_attempt_at"`
+	SyncedAt      *time.Time `json:"synced_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// EcommerceOrder es un pedido recibido de la tienda en línea. Al importarlo se crea una orden de
+// compra en estado Issued; Payload guarda el pedido para reintentar los que fallaron.
+type EcommerceOrder struct {
+	ID              int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Provider        string    `gorm:"size:20;not null;uniqueIndex:idx_ecommerce_order_external" json:"provider"`
+	ExternalID      string    `gorm:"size:100;not null;uniqueIndex:idx_ecommerce_order_external" json:"external_id"`
+	Number          string    `gorm:"size:100" json:"number"`
+	Status          string    `gorm:"size:20;not null;index" json:"status"`
+	PurchaseOrderID *int      `json:"purchase_order_id"`
+	CustomerID      *int      `json:"customer_id"`
+	Total           float64   `gorm:"not null" json:"total"`
+	LastError       string    `gorm:"size:500" json:"last_error,omitempty"`
+	Payload         string    `gorm:"type:jsonb;not null" json:"-"`
+	OrderedAt       time.Time `json:"ordered_at"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	RequestID       string    `gorm:"size:64" json:"request_id,omitempty"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// EmailMessage es un correo en la cola de envío. Queda guardado después de enviarse, con el
+// número de intentos y la respuesta del proveedor, como registro de lo que se mandó.
+type EmailMessage struct {
+	ID               int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	To               string     `gorm:"size:254;not null;index" json:"to"`
+	Template         string     `gorm:"size:50;not null" json:"template"`
+	Subject          string     `gorm:"size:255;not null" json:"subject"`
+	HTML             string     `gorm:"type:text;not null" json:"-"`
+	UnsubscribeURL   string     `gorm:"size:500" json:"-"`
+	Status           string     `gorm:"size:20;not null;index:idx_email_message_due" json:"status"`
+	Attempts         int        `gorm:"not null;default:0" json:"attempts"`
+	LastError        string     `gorm:"size:500" json:"last_error"`
+	ProviderResponse string     `gorm:"size:255" json:"provider_response"`
+	NextAttemptAt    time.Time  `gorm:"not null;index:idx_email_message_due" json:"next_attempt_at"`
+	SentAt           *time.Time `json:"sent_at"`
+	RequestID        string     `gorm:"size:64" json:"request_id"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
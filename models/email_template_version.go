@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// EmailTemplateVersion es una versión editada de una plantilla de correo. A lo sumo una versión por
+// plantilla está activa; sin versión activa se usa la plantilla incluida en el binario.
+type EmailTemplateVersion struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name      string    `gorm:"size:50;not null;uniqueIndex:idx_email_template_version" json:"name"`
+	Version   int       `gorm:"not null;uniqueIndex:idx_email_template_version" json:"version"`
+	Subject   string    `gorm:"size:255;not null" json:"subject"`
+	Body      string    `gorm:"type:text;not null" json:"body"`
+	Active    bool      `gorm:"not null;default:false" json:"active"`
+	CreatedBy string    `gorm:"size:80" json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
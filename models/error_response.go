@@ -1,6 +0,0 @@
-package models
-
-// ErrorResponse representa una respuesta de error estándar
-type ErrorResponse struct {
-	Error string `json:"error"`
-}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// GatewayPayment es un pago aprobado notificado por la pasarela. Se guarda una vez por transacción,
+// así las notificaciones repetidas no registran el pago dos veces; PaymentID es el pago que se
+// registró en la factura, o LastError dice por qué no se pudo.
+type GatewayPayment struct {
+	ID               int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Provider         string    `gorm:"size:20;not null;uniqueIndex:idx_gateway_payment_external" json:"provider"`
+	ExternalID       string    `gorm:"size:100;not null;uniqueIndex:idx_gateway_payment_external" json:"external_id"`
+	InvoiceReference string    `gorm:"size:100" json:"invoice_reference"`
+	Amount           float64   `gorm:"not null" json:"amount"`
+	Currency         string    `gorm:"size:3;not null" json:"currency"`
+	Status           string    `gorm:"size:20;not null;index" json:"status"`
+	PaymentID        *int      `json:"payment_id"`
+	LastError        string    `gorm:"size:500" json:"last_error,omitempty"`
+	PaidAt           time.Time `json:"paid_at"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	RequestID        string    `gorm:"size:64" json:"request_id,omitempty"`
+}
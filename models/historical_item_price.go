@@ -3,8 +3,9 @@ package models
 import "time"
 
 type HistoricalItemPrice struct {
-	ID      int       `gorm:"primaryKey;autoIncrement" json:"id"`
-	ItemID  int       `gorm:"size:50;not null;index" json:"item_id"`
-	Price   float64   `gorm:"not null" json:"price"`
-	AddedAt time.Time `gorm:"not null" json:"modified_at,omitempty"`
+	ID       int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	ItemID   int       `gorm:"size:50;not null;index" json:"item_id"`
+	Price    float64   `gorm:"not null" json:"price"`
+	Currency string    `gorm:"size:3;not null" json:"currency"`
+	AddedAt  time.Time `gorm:"not null" json:"modified_at,omitempty"`
 }
@@ -13,6 +13,18 @@ type Invoice struct {
 	Discounts      []DiscountType `gorm:"many2many:invoice_discounts;" json:"discounts"`
 	Taxes          []TaxType      `gorm:"many2many:invoice_taxes;" json:"taxes"`
 	Total          float64        `gorm:"not null" json:"total"`
+	// los valores están en Currency; ExchangeRate es su tasa a la moneda base al emitirse
+	Currency     string  `gorm:"size:3;not null" json:"currency"`
+	ExchangeRate float64 `gorm:"not null" json:"exchange_rate"`
+	// DueDate solo la tienen las facturas a crédito; las demás se pagan al emitirse
+	DueDate *time.Time `gorm:"index" json:"due_date"`
+	PaidAt  *time.Time `json:"paid_at"`
+	// AmountPaid es lo abonado con pagos; el saldo pendiente es Total - AmountPaid
+	AmountPaid    float64 `gorm:"not null" json:"amount_paid"`
+	PaymentStatus string  `gorm:"size:10;not null;index" json:"payment_status"`
+	// CancelledAt la tienen las facturas anuladas con una nota crédito
+	CancelledAt *time.Time `gorm:"index" json:"cancelled_at"`
+	Version     int        `gorm:"not null;default:1" json:"version"`
 }
 
 type InvoiceItem struct {
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// InvoiceReminder registra cada recordatorio de pago de una factura, se haya enviado o no. Hay
+// uno por etapa (DaysFromDue) para no repetirlo.
+type InvoiceReminder struct {
+	ID             int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	InvoiceID      int       `gorm:"not null;uniqueIndex:idx_invoice_reminder_stage" json:"invoice_id"`
+	DaysFromDue    int       `gorm:"not null;uniqueIndex:idx_invoice_reminder_stage" json:"days_from_due"`
+	Status         string    `gorm:"size:20;not null" json:"status"`
+	Email          string    `gorm:"size:254" json:"email"`
+	EmailMessageID *int      `json:"email_message_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// InvoiceReturn es la devolución de parte de lo vendido en una factura: las unidades vuelven al
+// stock y Total es lo que se reembolsa, en la moneda de la factura. RefundMethod y RefundedAt dicen
+// cómo y cuándo se pagó el reembolso; las devoluciones anteriores a la migración 43 no los tienen.
+type InvoiceReturn struct {
+	ID           int                 `gorm:"primaryKey;autoIncrement" json:"id"`
+	InvoiceID    int                 `gorm:"not null;index" json:"invoice_id"`
+	Reason       string              `gorm:"size:300" json:"reason,omitempty"`
+	Items        []InvoiceReturnItem `gorm:"foreignKey:InvoiceReturnID" json:"items"`
+	Subtotal     float64             `gorm:"not null" json:"subtotal"`
+	Total        float64             `gorm:"not null" json:"total"`
+	RefundMethod string              `gorm:"size:20" json:"refund_method,omitempty"`
+	RefundedAt   *time.Time          `gorm:"index" json:"refunded_at,omitempty"`
+	CreatedBy    string              `gorm:"size:80" json:"created_by,omitempty"`
+	CreatedAt    time.Time           `json:"created_at"`
+}
+
+// InvoiceReturnItem son las unidades devueltas de un item y su precio en la factura.
+type InvoiceReturnItem struct {
+	InvoiceReturnID int     `gorm:"primaryKey" json:"-"`
+	ItemID          int     `gorm:"primaryKey;autoIncrement:false" json:"item_id"`
+	Item            *Item   `gorm:"foreignKey:ItemID;references:ID" json:"item,omitempty"`
+	Amount          int     `gorm:"not null" json:"amount"`
+	UnitPrice       float64 `gorm:"not null" json:"unit_price"`
+}
@@ -1,14 +1,24 @@
 package models
 
+import "time"
+
 type Item struct {
 	ID                 int                 `gorm:"primaryKey;autoIncrement;size:50" json:"id"`
 	Name               string              `gorm:"size:255;not null" json:"name"`
 	Description        string              `gorm:"size:300" json:"description,omitempty"`
+	SKU                *string             `gorm:"column:sku;size:64;uniqueIndex" json:"sku,omitempty"`
+	Barcode            *string             `gorm:"size:64;uniqueIndex" json:"barcode,omitempty"`
 	Stock              int                 `gorm:"not null" json:"stock"`
 	SellingPrice       float64             `gorm:"not null" json:"selling_price"`
 	PurchasePrice      float64             `gorm:"not null" json:"purchase_price"`
+	Currency           string              `gorm:"size:3;not null" json:"currency"`
 	ItemState          bool                `gorm:"not null" json:"item_state"`
+	ReorderLevel       int                 `gorm:"not null" json:"reorder_level"`
 	ItemTypeID         int                 `gorm:"size:50;not null" json:"-"`
 	ItemType           ItemType            `gorm:"foreignKey:ItemTypeID;references:ID" json:"item_type"`
 	AdditionalExpenses []AdditionalExpense `gorm:"foreignKey:ItemID" json:"additional_expenses"`
+	Images             []ItemImage         `gorm:"foreignKey:ItemID" json:"images"`
+	Version            int                 `gorm:"not null;default:1" json:"version"`
+	// solo se escribe al crear; los items anteriores a la migración 27 no la tienen
+	CreatedAt *time.Time `gorm:"<-:create;index" json:"created_at,omitempty"`
 }
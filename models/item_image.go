@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// ItemImage es una imagen de un item guardada en el almacenamiento configurado (STORAGE_PROVIDER)
+// con la llave Key; URL es la dirección pública con que se descarga.
+type ItemImage struct {
+	ID          int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	ItemID      int       `gorm:"not null;index" json:"item_id"`
+	Key         string    `gorm:"size:255;not null;uniqueIndex" json:"-"`
+	URL         string    `gorm:"size:500;not null" json:"url"`
+	ContentType string    `gorm:"size:50;not null" json:"content_type"`
+	Size        int       `gorm:"not null" json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+}
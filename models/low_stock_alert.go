@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// LowStockAlert marca un item del que ya se avisó que está bajo de stock, para no repetir el aviso
+// en cada corrida. Se borra cuando el stock vuelve a superar el nivel de reorden.
+type LowStockAlert struct {
+	ItemID       int       `gorm:"primaryKey;autoIncrement:false" json:"item_id"`
+	Stock        int       `gorm:"not null" json:"stock"`
+	ReorderLevel int       `gorm:"not null" json:"reorder_level"`
+	AlertedAt    time.Time `gorm:"not null" json:"alerted_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Notification es un aviso en la bandeja de un usuario del sistema.
+type Notification struct {
+	ID        int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    int        `gorm:"not null;index:idx_notification_user" json:"user_id"`
+	EventType string     `gorm:"size:50;not null" json:"event_type"`
+	Title     string     `gorm:"size:150;not null" json:"title"`
+	Body      string     `gorm:"size:500" json:"body"`
+	Data      string     `gorm:"type:jsonb" json:"-"`
+	ReadAt    *time.Time `gorm:"index:idx_notification_user" json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
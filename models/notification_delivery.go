@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// NotificationDelivery es un intento de entrega de una notificación por un canal externo. Un mismo
+// mensaje tiene un registro por cada intento, con la respuesta del proveedor o el error.
+type NotificationDelivery struct {
+	ID               int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Channel          string    `gorm:"size:20;not null;index:idx_notification_delivery_message" json:"channel"`
+	MessageID        int       `gorm:"not null;index:idx_notification_delivery_message" json:"message_id"`
+	Recipient        string    `gorm:"size:254;not null;index" json:"recipient"`
+	Template         string    `gorm:"size:50" json:"template"`
+	Attempt          int       `gorm:"not null" json:"attempt"`
+	Status           string    `gorm:"size:20;not null;index" json:"status"`
+	ProviderResponse string    `gorm:"size:255" json:"provider_response"`
+	Error            string    `gorm:"size:500" json:"error"`
+	RequestID        string    `gorm:"size:64" json:"request_id"`
+	CreatedAt        time.Time `gorm:"index" json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// NotificationPreference guarda solo lo que el destinatario cambió; lo que no tiene fila usa el
+// valor por defecto del evento.
+type NotificationPreference struct {
+	ID            int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	RecipientType string    `gorm:"size:20;not null;uniqueIndex:idx_notification_preference" json:"recipient_type"`
+	RecipientID   int       `gorm:"not null;uniqueIndex:idx_notification_preference" json:"recipient_id"`
+	EventType     string    `gorm:"size:50;not null;uniqueIndex:idx_notification_preference" json:"event_type"`
+	Channel       string    `gorm:"size:20;not null;uniqueIndex:idx_notification_preference" json:"channel"`
+	Enabled       bool      `gorm:"not null" json:"enabled"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
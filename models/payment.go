@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Payment es un abono a una factura, en la moneda de la factura.
+type Payment struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	InvoiceID int       `gorm:"not null;index" json:"invoice_id"`
+	Amount    float64   `gorm:"not null" json:"amount"`
+	Method    string    `gorm:"size:20;not null" json:"method"`
+	Reference string    `gorm:"size:100" json:"reference,omitempty"`
+	Date      time.Time `gorm:"not null" json:"date"`
+	CreatedBy string    `gorm:"size:80" json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
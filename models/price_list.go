@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// PriceList es una lista de precios especiales para los clientes que la tienen asignada. Los items
+// que no están en la lista se les venden a su precio normal.
+type PriceList struct {
+	ID          int             `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string          `gorm:"size:100;not null;uniqueIndex" json:"name"`
+	Description string          `gorm:"size:300" json:"description,omitempty"`
+	Items       []PriceListItem `gorm:"foreignKey:PriceListID" json:"items"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// PriceListItem reemplaza el precio de venta del item para la lista. Price está en la moneda del item.
+type PriceListItem struct {
+	PriceListID int     `gorm:"primaryKey" json:"-"`
+	ItemID      int     `gorm:"primaryKey" json:"item_id"`
+	Item        *Item   `gorm:"foreignKey:ItemID;references:ID" json:"item,omitempty"`
+	Price       float64 `gorm:"not null" json:"price"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Quotation es una cotización para un cliente. Lleva items, descuentos e impuestos como una factura y,
+// una vez aceptada, se convierte en una factura con los valores cotizados. Pasa por pending,
+// accepted, rejected y converted.
+type Quotation struct {
+	ID             int             `gorm:"primaryKey;autoIncrement" json:"id"`
+	EnterpriseData string          `gorm:"size:300;not null" json:"enterprise_data"`
+	DateTime       time.Time       `gorm:"not null" json:"date_time"`
+	CustomerID     int             `gorm:"not null;index" json:"customer_id"`
+	Customer       Customer        `gorm:"foreignKey:CustomerID;references:ID" json:"customer"`
+	Items          []QuotationItem `gorm:"foreignKey:QuotationID" json:"items"`
+	Subtotal       float64         `gorm:"not null" json:"subtotal"`
+	Discounts      []DiscountType  `gorm:"many2many:quotation_discounts;" json:"discounts"`
+	Taxes          []TaxType       `gorm:"many2many:quotation_taxes;" json:"taxes"`
+	Total          float64         `gorm:"not null" json:"total"`
+	// moneda de los valores y de la factura que resulte
+	Currency string `gorm:"size:3;not null" json:"currency"`
+	// después de ExpiresAt la cotización ya no se puede aceptar
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`
+	State     string    `gorm:"size:20;not null;index" json:"state"`
+	CreatedBy string    `gorm:"size:80" json:"created_by,omitempty"`
+	// InvoiceID es la factura creada al convertirla
+	InvoiceID   *int       `gorm:"index" json:"invoice_id,omitempty"`
+	ConvertedAt *time.Time `json:"converted_at,omitempty"`
+}
+
+type QuotationItem struct {
+	QuotationID int   `gorm:"primaryKey" json:"-"`
+	ItemID      int   `gorm:"primaryKey" json:"item_id"`
+	Item        *Item `gorm:"foreignKey:ItemID;references:ID" json:"item,omitempty"`
+	Amount      int   `gorm:"not null" json:"amount"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// RefreshToken es una sesión iniciada con POST /login. Solo se guarda el hash SHA-256 del token que
+// recibe el cliente; cada refresco revoca el token usado y emite uno nuevo.
+type RefreshToken struct {
+	ID        int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    int        `gorm:"not null;index" json:"user_id"`
+	TokenHash string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `gorm:"not null" json:"created_at"`
+	ClientIP  string     `gorm:"size:45" json:"client_ip,omitempty"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// RestockOrder es un pedido de inventario a un proveedor. A diferencia de PurchaseOrder, que es un
+// pedido de un cliente, recibirlo suma stock. Pasa por draft, sent, received y cancelled.
+type RestockOrder struct {
+	ID         int  `gorm:"primaryKey;autoIncrement" json:"id"`
+	SupplierID *int `gorm:"index" json:"supplier_id,omitempty"`
+	// nombre del proveedor al crear el pedido; los pedidos anteriores a los proveedores solo tienen este
+	SupplierName string             `gorm:"size:150;not null" json:"supplier_name"`
+	State        string             `gorm:"size:20;not null;index" json:"state"`
+	Notes        string             `gorm:"size:500" json:"notes,omitempty"`
+	Items        []RestockOrderItem `gorm:"foreignKey:RestockOrderID" json:"items"`
+	Total        float64            `gorm:"not null" json:"total"`
+	CreatedBy    string             `gorm:"size:80" json:"created_by,omitempty"`
+	CreatedAt    time.Time          `json:"created_at"`
+	SentAt       *time.Time         `json:"sent_at,omitempty"`
+	ReceivedAt   *time.Time         `json:"received_at,omitempty"`
+	CancelledAt  *time.Time         `json:"cancelled_at,omitempty"`
+}
+
+// RestockOrderItem es una línea del pedido. ReceivedQuantity crece con cada recepción parcial hasta
+// llegar a Quantity.
+type RestockOrderItem struct {
+	ID               int     `gorm:"primaryKey;autoIncrement" json:"id"`
+	RestockOrderID   int     `gorm:"not null;uniqueIndex:idx_restock_order_item" json:"-"`
+	ItemID           int     `gorm:"not null;uniqueIndex:idx_restock_order_item" json:"item_id"`
+	Item             *Item   `gorm:"foreignKey:ItemID;references:ID" json:"item,omitempty"`
+	Quantity         int     `gorm:"not null" json:"quantity"`
+	ReceivedQuantity int     `gorm:"not null;default:0" json:"received_quantity"`
+	UnitCost         float64 `gorm:"not null" json:"unit_cost"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// ScheduledJob guarda el estado compartido de un trabajo programado. NextRunAt también sirve de
+// lock entre instancias: solo la que logra adelantarlo ejecuta esa corrida.
+type ScheduledJob struct {
+	Name           string     `gorm:"primaryKey;size:100" json:"name"`
+	Schedule       string     `gorm:"size:100;not null" json:"schedule"`
+	NextRunAt      time.Time  `gorm:"not null" json:"next_run_at"`
+	LastStartedAt  *time.Time `json:"last_started_at"`
+	LastFinishedAt *time.Time `json:"last_finished_at"`
+	LastStatus     string     `gorm:"size:20" json:"last_status"`
+	LastResult     string     `gorm:"size:500" json:"last_result"`
+	LastDurationMs int64      `json:"last_duration_ms"`
+	LastRunBy      string     `gorm:"size:100" json:"last_run_by"`
+}
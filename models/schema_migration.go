@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+type SchemaMigration struct {
+	Version     int       `gorm:"primaryKey;autoIncrement:false" json:"version"`
+	Name        string    `gorm:"size:100;not null" json:"name"`
+	Destructive bool      `gorm:"not null;default:false" json:"destructive"`
+	AppliedAt   time.Time `gorm:"not null" json:"applied_at"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type SecurityEvent struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	EventType string    `gorm:"size:50;not null;index" json:"event_type"`
+	UserEmail string    `gorm:"size:80;index" json:"user_email"`
+	Detail    string    `gorm:"size:500" json:"detail"`
+	IPAddress string    `gorm:"size:64" json:"ip_address"`
+	DateTime  time.Time `gorm:"not null;index" json:"date_time"`
+	PrevHash  string    `gorm:"size:64;not null" json:"prev_hash"`
+	Hash      string    `gorm:"size:64;not null;uniqueIndex" json:"hash"`
+	// No forma parte del hash, así la cadena registrada antes de agregar la columna sigue verificando
+	RequestID string `gorm:"size:64;index" json:"request_id,omitempty"`
+}
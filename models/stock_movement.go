@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// StockMovement es un cambio del stock de un item. La suma de los movimientos de un item es su
+// stock; Item.Stock es ese saldo ya calculado, y ambos se escriben en la misma transacción.
+type StockMovement struct {
+	ID     int `gorm:"primaryKey;autoIncrement" json:"id"`
+	ItemID int `gorm:"not null;index" json:"item_id"`
+	Delta  int `gorm:"not null" json:"delta"`
+	// stock del item después del movimiento
+	Stock  int    `gorm:"not null" json:"stock"`
+	Reason string `gorm:"size:30;not null;index" json:"reason"`
+	// factura, venta externa u orden de compra según Reason; nil en los ajustes
+	ReferenceID *int      `json:"reference_id,omitempty"`
+	UserEmail   string    `gorm:"size:80" json:"user_email,omitempty"`
+	RequestID   string    `gorm:"size:64" json:"request_id,omitempty"`
+	CreatedAt   time.Time `gorm:"not null;index" json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Supplier es a quien se le compra inventario. PaymentTermDays son los días de crédito que da; 0 es
+// pago de contado.
+type Supplier struct {
+	ID              int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name            string    `gorm:"size:150;not null" json:"name"`
+	TaxID           string    `gorm:"size:50;not null;uniqueIndex" json:"tax_id"`
+	ContactName     string    `gorm:"size:150" json:"contact_name,omitempty"`
+	Email           string    `gorm:"size:254" json:"email,omitempty"`
+	PhoneNumbers    string    `gorm:"size:100" json:"phone_numbers,omitempty"`
+	Address         string    `gorm:"size:255" json:"address,omitempty"`
+	PaymentTermDays int       `gorm:"not null;default:0" json:"payment_term_days"`
+	SupplierState   bool      `gorm:"not null;default:true" json:"supplier_state"`
+	CreatedAt       time.Time `json:"created_at"`
+}
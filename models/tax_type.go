@@ -1,9 +1,23 @@
 package models
 
+import "time"
+
 type TaxType struct {
 	ID           int     `gorm:"primaryKey;autoIncrement;size:50" json:"id"`
 	Name         string  `gorm:"size:100;not null" json:"name"`
 	Description  string  `gorm:"size:300" json:"description,omitempty"`
 	IsPercentage bool    `gorm:"not null" json:"is_percentage"`
 	Value        float64 `gorm:"not null" json:"value"`
+	// inactivo no se aplica a facturas ni cotizaciones nuevas
+	Active bool `gorm:"not null;default:true" json:"active"`
+}
+
+// TaxTypeRate es una tarifa anterior del impuesto, vigente hasta ValidUntil. Las facturas emitidas
+// antes de ValidUntil se leen con ella y no con la tarifa actual.
+type TaxTypeRate struct {
+	ID           int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	TaxTypeID    int       `gorm:"not null;index" json:"tax_type_id"`
+	IsPercentage bool      `gorm:"not null" json:"is_percentage"`
+	Value        float64   `gorm:"not null" json:"value"`
+	ValidUntil   time.Time `gorm:"not null" json:"valid_until"`
 }
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// TextMessage es un SMS o mensaje de WhatsApp en la cola de envío. Como EmailMessage, queda guardado
+// después de enviarse como registro de lo que se mandó.
+type TextMessage struct {
+	ID               int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	Channel          string     `gorm:"size:20;not null" json:"channel"`
+	To               string     `gorm:"size:20;not null;index" json:"to"`
+	CustomerID       int        `gorm:"not null;index" json:"customer_id"`
+	Template         string     `gorm:"size:50;not null" json:"template"`
+	Body             string     `gorm:"type:text;not null" json:"-"`
+	Status           string     `gorm:"size:20;not null;index:idx_text_message_due" json:"status"`
+	Attempts         int        `gorm:"not null;default:0" json:"attempts"`
+	LastError        string     `gorm:"size:500" json:"last_error"`
+	ProviderResponse string     `gorm:"size:255" json:"provider_response"`
+	NextAttemptAt    time.Time  `gorm:"not null;index:idx_text_message_due" json:"next_attempt_at"`
+	SentAt           *time.Time `json:"sent_at"`
+	RequestID        string     `gorm:"size:64" json:"request_id"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
@@ -6,7 +6,9 @@ import (
 
 type UserLog struct {
 	ID        int       `gorm:"primaryKey;autoIncrement;size:50" json:"id"`
-	UserEmail string    `gorm:"size:80;not null" json:"email"`
+	UserEmail string    `gorm:"size:80;not null;index" json:"email"`
+	Endpoint  string    `gorm:"size:200;index" json:"endpoint,omitempty"`
 	Log       string    `gorm:"size:500;not null" json:"log"`
-	DateTime  time.Time `gorm:"not null" json:"date_time,omitempty"`
+	DateTime  time.Time `gorm:"not null;index" json:"date_time,omitempty"`
+	RequestID string    `gorm:"size:64;index" json:"request_id,omitempty"`
 }
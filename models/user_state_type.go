@@ -1,6 +1,10 @@
 package models
 
+// UserStateType es un estado de usuario. Solo los estados con AllowsLogin dejan iniciar sesión;
+// los BuiltIn (Active e Inactive) vienen del seed y no se pueden modificar ni borrar.
 type UserStateType struct {
-	ID   int    `gorm:"primaryKey;autoIncrement" json:"id"`
-	Name string `gorm:"not null;size:100" json:"name"`
+	ID          int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string `gorm:"not null;size:100" json:"name"`
+	AllowsLogin bool   `gorm:"not null;default:false" json:"allows_login"`
+	BuiltIn     bool   `gorm:"not null;default:false" json:"built_in"`
 }
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type WebhookSubscription struct {
+	ID         int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	URL        string    `gorm:"size:500;not null" json:"url"`
+	Secret     string    `gorm:"size:100;not null" json:"-"`
+	EventTypes []string  `gorm:"serializer:json;not null" json:"event_types"`
+	Active     bool      `gorm:"not null;default:true" json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	SubscriptionID int        `gorm:"not null;index" json:"subscription_id"`
+	EventType      string     `gorm:"size:50;not null" json:"event_type"`
+	Payload        string     `gorm:"type:jsonb;not null" json:"-"`
+	Status         string     `gorm:"size:20;not null;index:idx_webhook_delivery_due" json:"status"`
+	Attempts       int        `gorm:"not null;default:0" json:"attempts"`
+	LastStatusCode int        `json:"last_status_code"`
+	LastError      string     `gorm:"size:500" json:"last_error"`
+	NextAttemptAt  time.Time  `gorm:"not null;index:idx_webhook_delivery_due" json:"next_attempt_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
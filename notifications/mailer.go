@@ -0,0 +1,52 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"totesbackend/config"
+)
+
+const (
+	EMAIL_PROVIDER_SMTP     = "smtp"
+	EMAIL_PROVIDER_SENDGRID = "sendgrid"
+	EMAIL_PROVIDER_LOG      = "log"
+)
+
+// Message es un correo ya renderizado, listo para enviar.
+type Message struct {
+	To      string
+	Subject string
+	HTML    string
+	// UnsubscribeURL se envía como List-Unsubscribe; vacío en los correos obligatorios
+	UnsubscribeURL string
+}
+
+// Mailer entrega un correo a un proveedor. La respuesta es lo que el proveedor devolvió (ID del
+// mensaje, código de estado) y se guarda junto al intento para poder rastrearlo después.
+type Mailer interface {
+	Send(ctx context.Context, message Message) (response string, err error)
+}
+
+// NewMailer crea el mailer del proveedor configurado en EMAIL_PROVIDER.
+func NewMailer(cfg config.EmailConfig, smtpCfg config.SMTPConfig) (Mailer, error) {
+	switch cfg.Provider {
+	case EMAIL_PROVIDER_SMTP:
+		return &SMTPMailer{Config: smtpCfg, From: cfg.From}, nil
+	case EMAIL_PROVIDER_SENDGRID:
+		return NewSendGridMailer(cfg.SendGridAPIKey, cfg.From), nil
+	case EMAIL_PROVIDER_LOG:
+		return LogMailer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown email provider %q", cfg.Provider)
+	}
+}
+
+// LogMailer no envía nada: escribe el destinatario y el asunto en el log. Es el proveedor por
+// defecto en desarrollo.
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, message Message) (string, error) {
+	log.Printf("email to %s: %s (%d bytes, not sent: EMAIL_PROVIDER=log)", message.To, message.Subject, len(message.HTML))
+	return "logged", nil
+}
@@ -0,0 +1,75 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"time"
+)
+
+const sendGridEndpoint = "https://api.sendgrid.com/v3/mail/send"
+
+// SendGridMailer envía con la API v3 de SendGrid.
+type SendGridMailer struct {
+	APIKey string
+	From   string
+	Client *http.Client
+}
+
+func NewSendGridMailer(apiKey, from string) *SendGridMailer {
+	return &SendGridMailer{APIKey: apiKey, From: from, Client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+func (m *SendGridMailer) Send(ctx context.Context, message Message) (string, error) {
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return "", fmt.Errorf("invalid sender address: %w", err)
+	}
+
+	request := map[string]interface{}{
+		"personalizations": []map[string]interface{}{
+			{"to": []sendGridAddress{{Email: message.To}}},
+		},
+		"from":    sendGridAddress{Email: from.Address, Name: from.Name},
+		"subject": message.Subject,
+		"content": []map[string]string{{"type": "text/html", "value": message.HTML}},
+	}
+	if message.UnsubscribeURL != "" {
+		request["headers"] = map[string]string{
+			"List-Unsubscribe":      "<" + message.UnsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		}
+	}
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sendGridEndpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+m.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 300))
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		return resp.Status + " " + string(body), fmt.Errorf("sendgrid responded %s", resp.Status)
+	}
+	return resp.Status + " " + resp.Header.Get("X-Message-Id"), nil
+}
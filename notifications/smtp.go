@@ -0,0 +1,75 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+	"totesbackend/config"
+)
+
+// SMTPMailer envía por SMTP; net/smtp usa STARTTLS cuando el servidor lo ofrece.
+type SMTPMailer struct {
+	Config config.SMTPConfig
+	From   string
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, message Message) (string, error) {
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return "", fmt.Errorf("invalid sender address: %w", err)
+	}
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return "", fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	messageID := newMessageID(from.Address)
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "From: %s\r\n", from.String())
+	fmt.Fprintf(&body, "To: %s\r\n", to.String())
+	fmt.Fprintf(&body, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&body, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&body, "Message-ID: %s\r\n", messageID)
+	if message.UnsubscribeURL != "" {
+		fmt.Fprintf(&body, "List-Unsubscribe: <%s>\r\n", message.UnsubscribeURL)
+		body.WriteString("List-Unsubscribe-Post: List-Unsubscribe=One-Click\r\n")
+	}
+	body.WriteString("MIME-Version: 1.0\r\n")
+	body.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	body.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	body.WriteString(message.HTML)
+
+	var auth smtp.Auth
+	if m.Config.Username != "" {
+		auth = smtp.PlainAuth("", m.Config.Username, m.Config.Password, m.Config.Host)
+	}
+	address := net.JoinHostPort(m.Config.Host, strconv.Itoa(m.Config.Port))
+
+	// net/smtp no recibe un contexto; se respeta al menos la cancelación previa al envío
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if err := smtp.SendMail(address, auth, from.Address, []string{to.Address}, body.Bytes()); err != nil {
+		return "", err
+	}
+	return "accepted " + messageID, nil
+}
+
+func newMessageID(from string) string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		domain = from[at+1:]
+	}
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
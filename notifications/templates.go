@@ -0,0 +1,258 @@
+package notifications
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html"
+	"html/template"
+	"reflect"
+	"strings"
+	"time"
+	"totesbackend/config"
+)
+
+const (
+	TEMPLATE_APPOINTMENT_CONFIRMATION = "appointment_confirmation"
+	TEMPLATE_APPOINTMENT_REMINDER     = "appointment_reminder"
+	TEMPLATE_INVOICE                  = "invoice"
+	TEMPLATE_PAYMENT_REMINDER         = "payment_reminder"
+	TEMPLATE_PASSWORD_RESET           = "password_reset"
+	TEMPLATE_LOW_STOCK_ALERT          = "low_stock_alert"
+)
+
+// Datos que recibe cada plantilla.
+type AppointmentConfirmationData struct {
+	CustomerName string
+	DateTime     time.Time
+	Address      string
+	CancelURL    string
+}
+
+// AppointmentReminderData es un recordatorio de cita; HoursBefore es la etapa que lo envía.
+type AppointmentReminderData struct {
+	CustomerName string
+	DateTime     time.Time
+	Address      string
+	HoursBefore  int
+	CancelURL    string
+}
+
+type InvoiceData struct {
+	CustomerName string
+	InvoiceID    int
+	Date         time.Time
+	Items        []InvoiceLineData
+	Subtotal     float64
+	Total        float64
+	Currency     string
+}
+
+type InvoiceLineData struct {
+	Name   string
+	Amount int
+}
+
+// PaymentReminderData es un recordatorio de pago; DaysOverdue es 0 si la factura aún no vence.
+type PaymentReminderData struct {
+	CustomerName string
+	InvoiceID    int
+	Total        float64
+	Currency     string
+	DueDate      time.Time
+	DaysOverdue  int
+}
+
+type PasswordResetData struct {
+	ResetURL  string
+	ExpiresAt time.Time
+}
+
+// LowStockAlertData lista los items que llegaron a su nivel de reorden desde el último aviso.
+type LowStockAlertData struct {
+	Items []LowStockLineData
+}
+
+type LowStockLineData struct {
+	Name         string
+	Stock        int
+	ReorderLevel int
+}
+
+//go:embed templates/*.html
+var templateFiles embed.FS
+
+var templateFuncs = template.FuncMap{
+	"company": func() string { return config.ENTERPRISE_INVOICE_DATA },
+	"date":    func(t time.Time) string { return t.Format("02/01/2006") },
+	"clock":   func(t time.Time) string { return t.Format("15:04") },
+	"money":   func(v float64) string { return fmt.Sprintf("$%.2f", v) },
+}
+
+// Cada plantilla define "subject" y "body"; el cuerpo se envuelve en layout.html.
+var templates = map[string]*template.Template{}
+
+// sources guarda el texto de las plantillas incluidas, que es el punto de partida al editarlas.
+var sources = map[string]Source{}
+
+// Source es el texto editable de una plantilla: asunto y cuerpo con los campos de sus datos
+// como {{.CustomerName}}.
+type Source struct {
+	Subject string
+	Body    string
+}
+
+// sampleData son los datos de ejemplo usados para validar y previsualizar cada plantilla.
+var sampleData = map[string]func() interface{}{
+	TEMPLATE_APPOINTMENT_CONFIRMATION: func() interface{} {
+		return &AppointmentConfirmationData{
+			CustomerName: "Ana Pérez",
+			DateTime:     time.Date(2025, 3, 14, 10, 30, 0, 0, time.Local),
+			Address:      "Calle 45 #27-12",
+			CancelURL:    "https://example.com/appointments/cancel?token=ejemplo",
+		}
+	},
+	TEMPLATE_APPOINTMENT_REMINDER: func() interface{} {
+		return &AppointmentReminderData{
+			CustomerName: "Ana Pérez",
+			DateTime:     time.Date(2025, 3, 14, 10, 30, 0, 0, time.Local),
+			Address:      "Calle 45 #27-12",
+			HoursBefore:  24,
+			CancelURL:    "https://example.com/appointments/cancel?token=ejemplo",
+		}
+	},
+	TEMPLATE_INVOICE: func() interface{} {
+		return &InvoiceData{
+			CustomerName: "Ana Pérez",
+			InvoiceID:    1024,
+			Date:         time.Date(2025, 3, 14, 10, 30, 0, 0, time.Local),
+			Items:        []InvoiceLineData{{Name: "Cambio de aceite", Amount: 1}, {Name: "Filtro de aire", Amount: 2}},
+			Subtotal:     100000,
+			Total:        119000,
+			Currency:     "COP",
+		}
+	},
+	TEMPLATE_PAYMENT_REMINDER: func() interface{} {
+		return &PaymentReminderData{
+			CustomerName: "Ana Pérez",
+			InvoiceID:    1024,
+			Total:        119000,
+			Currency:     "COP",
+			DueDate:      time.Date(2025, 4, 13, 0, 0, 0, 0, time.Local),
+			DaysOverdue:  3,
+		}
+	},
+	TEMPLATE_PASSWORD_RESET: func() interface{} {
+		return &PasswordResetData{
+			ResetURL:  "https://example.com/reset?token=example",
+			ExpiresAt: time.Date(2025, 3, 14, 11, 30, 0, 0, time.Local),
+		}
+	},
+	TEMPLATE_LOW_STOCK_ALERT: func() interface{} {
+		return &LowStockAlertData{
+			Items: []LowStockLineData{{Name: "Filtro de aire", Stock: 2, ReorderLevel: 5}, {Name: "Aceite 20W-50", Stock: 0, ReorderLevel: 10}},
+		}
+	},
+}
+
+func init() {
+	for _, name := range TemplateNames() {
+		tmpl := template.Must(template.New(name).Funcs(templateFuncs).
+			ParseFS(templateFiles, "templates/layout.html", "templates/"+name+".html"))
+		templates[name] = tmpl
+		// el texto se toma antes de ejecutar: al ejecutar html/template agrega sus escapes al árbol
+		sources[name] = Source{
+			Subject: tmpl.Lookup("subject").Tree.Root.String(),
+			Body:    tmpl.Lookup("body").Tree.Root.String(),
+		}
+	}
+}
+
+// TemplateNames lista las plantillas disponibles.
+func TemplateNames() []string {
+	return []string{TEMPLATE_APPOINTMENT_CONFIRMATION, TEMPLATE_APPOINTMENT_REMINDER, TEMPLATE_INVOICE, TEMPLATE_PAYMENT_REMINDER, TEMPLATE_PASSWORD_RESET, TEMPLATE_LOW_STOCK_ALERT}
+}
+
+// DefaultSource devuelve el texto de la plantilla incluida en el binario.
+func DefaultSource(name string) (Source, bool) {
+	source, ok := sources[name]
+	return source, ok
+}
+
+// SampleData devuelve un puntero a datos de ejemplo de la plantilla, o nil si no existe.
+func SampleData(name string) interface{} {
+	sample, ok := sampleData[name]
+	if !ok {
+		return nil
+	}
+	return sample()
+}
+
+// Placeholders lista los campos que puede usar la plantilla, por ejemplo ".CustomerName".
+func Placeholders(name string) []string {
+	sample := SampleData(name)
+	if sample == nil {
+		return nil
+	}
+	dataType := reflect.TypeOf(sample).Elem()
+	placeholders := make([]string, dataType.NumField())
+	for i := range placeholders {
+		placeholders[i] = "." + dataType.Field(i).Name
+	}
+	return placeholders
+}
+
+// page es lo que recibe layout.html; cada plantilla solo ve Data.
+type page struct {
+	Data           interface{}
+	UnsubscribeURL string
+}
+
+// Render devuelve el asunto y el HTML de la plantilla indicada. Con unsubscribeURL el pie del
+// correo incluye el enlace para darse de baja.
+func Render(name string, data interface{}, unsubscribeURL string) (subject, body string, err error) {
+	tmpl, ok := templates[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown email template %q", name)
+	}
+	return execute(tmpl, data, unsubscribeURL)
+}
+
+// RenderSource es Render con un texto distinto al incluido, como el de una versión editada.
+func RenderSource(name string, source Source, data interface{}, unsubscribeURL string) (subject, body string, err error) {
+	tmpl, err := Parse(name, source)
+	if err != nil {
+		return "", "", err
+	}
+	return execute(tmpl, data, unsubscribeURL)
+}
+
+// Parse arma la plantilla con el layout común; falla si el texto no es una plantilla válida.
+func Parse(name string, source Source) (*template.Template, error) {
+	if _, ok := templates[name]; !ok {
+		return nil, fmt.Errorf("unknown email template %q", name)
+	}
+	tmpl, err := template.New(name).Funcs(templateFuncs).ParseFS(templateFiles, "templates/layout.html")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tmpl.New("subject").Parse(source.Subject); err != nil {
+		return nil, fmt.Errorf("subject: %w", err)
+	}
+	if _, err := tmpl.New("body").Parse(source.Body); err != nil {
+		return nil, fmt.Errorf("body: %w", err)
+	}
+	return tmpl, nil
+}
+
+func execute(tmpl *template.Template, data interface{}, unsubscribeURL string) (subject, body string, err error) {
+	var subjectBuf, bodyBuf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subjectBuf, "subject", data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.ExecuteTemplate(&bodyBuf, "layout", page{Data: data, UnsubscribeURL: unsubscribeURL}); err != nil {
+		return "", "", err
+	}
+	// el asunto va en un encabezado, no en HTML: se deshacen los escapes de html/template
+	return html.UnescapeString(strings.TrimSpace(subjectBuf.String())), bodyBuf.String(), nil
+}
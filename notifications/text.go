@@ -0,0 +1,82 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	texttemplate "text/template"
+	"totesbackend/config"
+)
+
+const (
+	TEXT_PROVIDER_TWILIO = "twilio"
+	TEXT_PROVIDER_LOG    = "log"
+)
+
+const (
+	TEXT_CHANNEL_SMS      = "sms"
+	TEXT_CHANNEL_WHATSAPP = "whatsapp"
+)
+
+// TextMessage es un SMS o un mensaje de WhatsApp ya renderizado. To va en formato E.164.
+type TextMessage struct {
+	Channel string
+	To      string
+	Body    string
+}
+
+// TextSender entrega un mensaje de texto a un proveedor; la respuesta se guarda junto al intento,
+// como con los correos.
+type TextSender interface {
+	Send(ctx context.Context, message TextMessage) (response string, err error)
+}
+
+// NewTextSender crea el proveedor configurado en TEXT_PROVIDER.
+func NewTextSender(cfg config.TextConfig) (TextSender, error) {
+	switch cfg.Provider {
+	case TEXT_PROVIDER_TWILIO:
+		return NewTwilioSender(cfg), nil
+	case TEXT_PROVIDER_LOG:
+		return LogTextSender{}, nil
+	default:
+		return nil, fmt.Errorf("unknown text provider %q", cfg.Provider)
+	}
+}
+
+// LogTextSender no envía nada: escribe el destinatario y el mensaje en el log.
+type LogTextSender struct{}
+
+func (LogTextSender) Send(ctx context.Context, message TextMessage) (string, error) {
+	log.Printf("%s to %s: %q (not sent: TEXT_PROVIDER=log)", message.Channel, message.To, message.Body)
+	return "logged", nil
+}
+
+// Los mensajes de texto usan los mismos datos que los correos del mismo nombre, en una versión corta
+// y sin HTML. No se editan desde /email-templates.
+var textTemplates = map[string]*texttemplate.Template{
+	TEMPLATE_APPOINTMENT_CONFIRMATION: parseText(TEMPLATE_APPOINTMENT_CONFIRMATION,
+		`{{company}}: hola {{.CustomerName}}, tu cita quedó agendada para el {{date .DateTime}} a las {{clock .DateTime}}`+
+			`{{with .Address}} en {{.}}{{end}}.{{with .CancelURL}} Para cancelarla: {{.}}{{end}}`),
+	TEMPLATE_APPOINTMENT_REMINDER: parseText(TEMPLATE_APPOINTMENT_REMINDER,
+		`{{company}}: hola {{.CustomerName}}, te recordamos tu cita del {{date .DateTime}} a las {{clock .DateTime}}`+
+			`{{with .Address}} en {{.}}{{end}}.{{with .CancelURL}} Si no puedes asistir, cancélala aquí: {{.}}{{end}}`),
+}
+
+func parseText(name, text string) *texttemplate.Template {
+	return texttemplate.Must(texttemplate.New(name).Funcs(texttemplate.FuncMap(templateFuncs)).Parse(text))
+}
+
+// RenderText devuelve el mensaje de texto de la plantilla indicada.
+func RenderText(name string, data interface{}) (string, error) {
+	tmpl, ok := textTemplates[name]
+	if !ok {
+		return "", fmt.Errorf("unknown text template %q", name)
+	}
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(body.String()), nil
+}
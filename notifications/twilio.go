@@ -0,0 +1,79 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"totesbackend/config"
+)
+
+const twilioEndpoint = "https://api.twilio.com/2010-04-01/Accounts/"
+
+// TwilioSender envía SMS y mensajes de WhatsApp con la API de mensajes de Twilio.
+type TwilioSender struct {
+	AccountSID   string
+	AuthToken    string
+	From         string
+	WhatsAppFrom string
+	Client       *http.Client
+}
+
+func NewTwilioSender(cfg config.TextConfig) *TwilioSender {
+	return &TwilioSender{
+		AccountSID:   cfg.TwilioAccountSID,
+		AuthToken:    cfg.TwilioAuthToken,
+		From:         cfg.From,
+		WhatsAppFrom: cfg.WhatsAppFrom,
+		Client:       &http.Client{Timeout: config.TEXT_REQUEST_TIMEOUT},
+	}
+}
+
+func (s *TwilioSender) Send(ctx context.Context, message TextMessage) (string, error) {
+	form := url.Values{"Body": {message.Body}}
+	switch message.Channel {
+	case TEXT_CHANNEL_SMS:
+		if s.From == "" {
+			return "", fmt.Errorf("TWILIO_FROM is not set")
+		}
+		form.Set("From", s.From)
+		form.Set("To", message.To)
+	case TEXT_CHANNEL_WHATSAPP:
+		if s.WhatsAppFrom == "" {
+			return "", fmt.Errorf("TWILIO_WHATSAPP_FROM is not set")
+		}
+		form.Set("From", "whatsapp:"+s.WhatsAppFrom)
+		form.Set("To", "whatsapp:"+message.To)
+	default:
+		return "", fmt.Errorf("unknown text channel %q", message.Channel)
+	}
+
+	endpoint := twilioEndpoint + url.PathEscape(s.AccountSID) + "/Messages.json"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(s.AccountSID, s.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var result struct {
+		SID     string `json:"sid"`
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	}
+	_ = json.Unmarshal(body, &result)
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return resp.Status + " " + result.Message, fmt.Errorf("twilio responded %s: %s", resp.Status, result.Message)
+	}
+	return resp.Status + " " + result.SID + " " + result.Status, nil
+}
@@ -0,0 +1,50 @@
+package payments
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"totesbackend/config"
+)
+
+const (
+	PAYMENT_GATEWAY_STRIPE = "stripe"
+	PAYMENT_GATEWAY_PAYU   = "payu"
+)
+
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+// Payment es un pago aprobado por la pasarela. InvoiceReference es la referencia con que se cobró:
+// el ID de la factura, seguido opcionalmente de "-" y un sufijo para que cada intento sea único.
+type Payment struct {
+	ExternalID       string
+	InvoiceReference string
+	Amount           float64
+	// Currency es el código ISO 4217 en mayúsculas
+	Currency string
+	Date     time.Time
+}
+
+// Gateway es el adaptador de una pasarela de pagos.
+type Gateway interface {
+	Provider() string
+	// ParseWebhook verifica la firma de una notificación de la pasarela y devuelve el pago aprobado.
+	// Devuelve nil, nil para los eventos que no son pagos aprobados.
+	ParseWebhook(header http.Header, body []byte) (*Payment, error)
+}
+
+// NewGateway crea el adaptador de la pasarela configurada en PAYMENT_GATEWAY. Devuelve nil si está
+// desactivada.
+func NewGateway(cfg config.PaymentGatewayConfig) (Gateway, error) {
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case PAYMENT_GATEWAY_STRIPE:
+		return NewStripeGateway(cfg), nil
+	case PAYMENT_GATEWAY_PAYU:
+		return NewPayUGateway(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown payment gateway %q", cfg.Provider)
+	}
+}
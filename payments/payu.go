@@ -0,0 +1,85 @@
+package payments
+
+import (
+	"crypto/md5"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"totesbackend/config"
+)
+
+// estado de una transacción aprobada en la confirmación de PayU
+const payuStateApproved = "4"
+
+// PayUGateway recibe la página de confirmación de PayU Latam: un POST con la transacción en un
+// formulario. reference_sale es la referencia de la factura.
+type PayUGateway struct {
+	Config config.PaymentGatewayConfig
+}
+
+func NewPayUGateway(cfg config.PaymentGatewayConfig) *PayUGateway {
+	return &PayUGateway{Config: cfg}
+}
+
+func (g *PayUGateway) Provider() string {
+	return PAYMENT_GATEWAY_PAYU
+}
+
+func (g *PayUGateway) ParseWebhook(header http.Header, body []byte) (*Payment, error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	if form.Get("merchant_id") != g.Config.MerchantID || !g.validSignature(form) {
+		return nil, ErrInvalidWebhookSignature
+	}
+	if form.Get("state_pol") != payuStateApproved {
+		return nil, nil
+	}
+
+	amount, err := strconv.ParseFloat(form.Get("value"), 64)
+	if err != nil {
+		return nil, err
+	}
+	// transaction_date viene en la hora local de la cuenta, sin zona
+	date, err := time.ParseInLocation("2006-01-02 15:04:05", form.Get("transaction_date"), time.Local)
+	if err != nil {
+		date = time.Now()
+	}
+	return &Payment{
+		ExternalID:       form.Get("transaction_id"),
+		InvoiceReference: form.Get("reference_sale"),
+		Amount:           amount,
+		Currency:         strings.ToUpper(form.Get("currency")),
+		Date:             date,
+	}, nil
+}
+
+// validSignature verifica sign, el MD5 de "ApiKey~merchant_id~reference_sale~new_value~currency~state_pol",
+// que es el esquema que define PayU para la confirmación.
+func (g *PayUGateway) validSignature(form url.Values) bool {
+	fields := []string{g.Config.APIKey, form.Get("merchant_id"), form.Get("reference_sale"), payuSignatureValue(form.Get("value")),
+		form.Get("currency"), form.Get("state_pol")}
+	sum := md5.Sum([]byte(strings.Join(fields, "~")))
+	expected := hex.EncodeToString(sum[:])
+	signature := strings.ToLower(form.Get("sign"))
+	return signature != "" && subtle.ConstantTimeCompare([]byte(expected), []byte(signature)) == 1
+}
+
+// payuSignatureValue da al valor el formato de la firma: con un decimal si el segundo es cero
+// (150.00 es 150.0) y con dos si no (150.26).
+func payuSignatureValue(value string) string {
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+	formatted := strconv.FormatFloat(amount, 'f', 2, 64)
+	if strings.HasSuffix(formatted, "0") {
+		return formatted[:len(formatted)-1]
+	}
+	return formatted
+}
@@ -0,0 +1,107 @@
+package payments
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"totesbackend/config"
+)
+
+// monedas que Stripe cobra sin decimales; en las demás los montos vienen en centavos
+var stripeZeroDecimalCurrencies = map[string]bool{
+	"BIF": true, "CLP": true, "DJF": true, "GNF": true, "JPY": true, "KMF": true, "KRW": true, "MGA": true,
+	"PYG": true, "RWF": true, "UGX": true, "VND": true, "VUV": true, "XAF": true, "XOF": true, "XPF": true,
+}
+
+// StripeGateway recibe los eventos payment_intent.succeeded. La factura va en el metadata
+// invoice_id del PaymentIntent.
+type StripeGateway struct {
+	Config config.PaymentGatewayConfig
+}
+
+func NewStripeGateway(cfg config.PaymentGatewayConfig) *StripeGateway {
+	return &StripeGateway{Config: cfg}
+}
+
+func (g *StripeGateway) Provider() string {
+	return PAYMENT_GATEWAY_STRIPE
+}
+
+func (g *StripeGateway) ParseWebhook(header http.Header, body []byte) (*Payment, error) {
+	if !g.validSignature(header.Get("Stripe-Signature"), body, time.Now()) {
+		return nil, ErrInvalidWebhookSignature
+	}
+
+	var event struct {
+		Type string `json:"type"`
+		Data struct {
+			Object struct {
+				ID             string            `json:"id"`
+				AmountReceived int64             `json:"amount_received"`
+				Currency       string            `json:"currency"`
+				Created        int64             `json:"created"`
+				Metadata       map[string]string `json:"metadata"`
+			} `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, err
+	}
+	if event.Type != "payment_intent.succeeded" {
+		return nil, nil
+	}
+
+	intent := event.Data.Object
+	currency := strings.ToUpper(intent.Currency)
+	amount := float64(intent.AmountReceived)
+	if !stripeZeroDecimalCurrencies[currency] {
+		amount /= 100
+	}
+	return &Payment{
+		ExternalID:       intent.ID,
+		InvoiceReference: intent.Metadata["invoice_id"],
+		Amount:           amount,
+		Currency:         currency,
+		Date:             time.Unix(intent.Created, 0),
+	}, nil
+}
+
+// validSignature verifica el encabezado Stripe-Signature ("t=<timestamp>,v1=<firma>,..."): la firma
+// es el HMAC-SHA256 en hexadecimal de "<timestamp>.<cuerpo>". Con la marca de tiempo se rechazan los
+// eventos repetidos después de config.PAYMENT_WEBHOOK_TOLERANCE.
+func (g *StripeGateway) validSignature(header string, body []byte, now time.Time) bool {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return false
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > config.PAYMENT_WEBHOOK_TOLERANCE || age < -config.PAYMENT_WEBHOOK_TOLERANCE {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(g.Config.WebhookSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(expected), []byte(signature)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AccountingSyncRepository struct {
+	DB *gorm.DB
+}
+
+func NewAccountingSyncRepository(db *gorm.DB) *AccountingSyncRepository {
+	return &AccountingSyncRepository{DB: db}
+}
+
+// CreateSync registra el documento para enviarlo. Devuelve false si el documento ya estaba
+// registrado, así un reintento de la operación que lo origina no lo envía dos veces.
+func (r *AccountingSyncRepository) CreateSync(ctx context.Context, sync *models.AccountingSync) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(sync)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ClaimDueSyncs toma hasta limit documentos vencidos y corre su next_attempt_at a leaseUntil, así
+// otra instancia no los envía mientras esta lo intenta.
+func (r *AccountingSyncRepository) ClaimDueSyncs(ctx context.Context, status string, now, leaseUntil time.Time, limit int) ([]models.AccountingSync, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var syncs []models.AccountingSync
+	err := r.DB.WithContext(ctx).Raw(`
+		UPDATE accounting_syncs SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM accounting_syncs
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, leaseUntil, status, now, limit).Scan(&syncs).Error
+	if err != nil {
+		return nil, err
+	}
+	return syncs, nil
+}
+
+func (r *AccountingSyncRepository) SaveSync(ctx context.Context, sync *models.AccountingSync) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Save(sync).Error
+}
+
+func (r *AccountingSyncRepository) GetSyncByID(ctx context.Context, id int) (*models.AccountingSync, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var sync models.AccountingSync
+	if err := r.DB.WithContext(ctx).First(&sync, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &sync, nil
+}
+
+func (r *AccountingSyncRepository) GetSyncByDocument(ctx context.Context, documentType string, documentID int) (*models.AccountingSync, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var sync models.AccountingSync
+	if err := r.DB.WithContext(ctx).First(&sync, "document_type = ? AND document_id = ?", documentType, documentID).Error; err != nil {
+		return nil, err
+	}
+	return &sync, nil
+}
+
+func (r *AccountingSyncRepository) GetSyncs(ctx context.Context, filter dtos.AccountingSyncFilterDTO) ([]models.AccountingSync, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	query := r.DB.WithContext(ctx).Model(&models.AccountingSync{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.DocumentType != "" {
+		query = query.Where("document_type = ?", filter.DocumentType)
+	}
+	if filter.DocumentID != nil {
+		query = query.Where("document_id = ?", *filter.DocumentID)
+	}
+	return paginate[models.AccountingSync](query.Order("id DESC"), filter.PaginationDTO)
+}
+
+// DeleteUnsentSync borra el registro del documento si todavía no llegó al sistema contable.
+// Devuelve false si ya se había enviado o no existe.
+func (r *AccountingSyncRepository) DeleteUnsentSync(ctx context.Context, documentType string, documentID int, sentStatus string) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).
+		Where("document_type = ? AND document_id = ? AND status <> ?", documentType, documentID, sentStatus).
+		Delete(&models.AccountingSync{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// RequeueSync pasa el documento de fromStatus a toStatus con los intentos en cero, para que se
+// envíe de nuevo en now. Devuelve false si no estaba en fromStatus y gorm.ErrRecordNotFound si no
+// existe.
+func (r *AccountingSyncRepository) RequeueSync(ctx context.Context, id int, fromStatus, toStatus string, now time.Time) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Model(&models.AccountingSync{}).
+		Where("id = ? AND status = ?", id, fromStatus).
+		Updates(map[string]interface{}{"status": toStatus, "attempts": 0, "next_attempt_at": now})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	var count int64
+	if err := r.DB.WithContext(ctx).Model(&models.AccountingSync{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count == 0 {
+		return false, gorm.ErrRecordNotFound
+	}
+	return false, nil
+}
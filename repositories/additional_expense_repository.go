@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,36 +16,49 @@ func NewAdditionalExpenseRepository(db *gorm.DB) *AdditionalExpenseRepository {
 	return &AdditionalExpenseRepository{DB: db}
 }
 
-func (r *AdditionalExpenseRepository) GetAllAdditionalExpenses() ([]models.AdditionalExpense, error) {
-	var additionalExpenses []models.AdditionalExpense
-	err := r.DB.Find(&additionalExpenses).Error
-	return additionalExpenses, err
+func (r *AdditionalExpenseRepository) GetAllAdditionalExpenses(ctx context.Context, pagination dtos.PaginationDTO) ([]models.AdditionalExpense, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return paginate[models.AdditionalExpense](r.DB.WithContext(ctx), pagination)
 }
 
-func (r *AdditionalExpenseRepository) GetAdditionalExpenseByID(id string) (*models.AdditionalExpense, error) {
+func (r *AdditionalExpenseRepository) GetAdditionalExpenseByID(ctx context.Context, id string) (*models.AdditionalExpense, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var additionalExpense models.AdditionalExpense
-	err := r.DB.First(&additionalExpense, id).Error
+	err := r.DB.WithContext(ctx).First(&additionalExpense, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &additionalExpense, nil
 }
 
-func (r *AdditionalExpenseRepository) CreateAdditionalExpense(expense *models.AdditionalExpense) (*models.AdditionalExpense, error) {
-	err := r.DB.Create(expense).Error
+func (r *AdditionalExpenseRepository) CreateAdditionalExpense(ctx context.Context, expense *models.AdditionalExpense) (*models.AdditionalExpense, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Create(expense).Error
 	if err != nil {
 		return nil, err
 	}
 	return expense, nil
 }
 
-func (r *AdditionalExpenseRepository) DeleteAdditionalExpense(id string) error {
-	result := r.DB.Delete(&models.AdditionalExpense{}, id)
+func (r *AdditionalExpenseRepository) DeleteAdditionalExpense(ctx context.Context, id string) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Delete(&models.AdditionalExpense{}, id)
 	return result.Error
 }
 
-func (r *AdditionalExpenseRepository) UpdateAdditionalExpense(expense *models.AdditionalExpense) (*models.AdditionalExpense, error) {
-	err := r.DB.Save(expense).Error
+func (r *AdditionalExpenseRepository) UpdateAdditionalExpense(ctx context.Context, expense *models.AdditionalExpense) (*models.AdditionalExpense, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Save(expense).Error
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/config"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type AppointmentReminderRepository struct {
+	DB *gorm.DB
+}
+
+func NewAppointmentReminderRepository(db *gorm.DB) *AppointmentReminderRepository {
+	return &AppointmentReminderRepository{DB: db}
+}
+
+// GetAppointmentsDueForReminder devuelve las citas pendientes o confirmadas de (from, to] que todavía no tienen el
+// recordatorio de la etapa hoursBefore para su fecha actual.
+func (r *AppointmentReminderRepository) GetAppointmentsDueForReminder(ctx context.Context, hoursBefore int, from, to time.Time) ([]models.Appointment, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var appointments []models.Appointment
+	err := r.DB.WithContext(ctx).
+		Where("state_id IN ? AND date_time > ? AND date_time <= ?",
+			[]int{config.APPOINTMENT_STATE_PENDING, config.APPOINTMENT_STATE_CONFIRMED}, from, to).
+		Where(`NOT EXISTS (SELECT 1 FROM appointment_reminders WHERE appointment_reminders.appointment_id = appointments.id
+			AND appointment_reminders.hours_before = ? AND appointment_reminders.date_time = appointments.date_time)`, hoursBefore).
+		Order("date_time, id").
+		Find(&appointments).Error
+	return appointments, err
+}
+
+func (r *AppointmentReminderRepository) CreateReminder(ctx context.Context, reminder *models.AppointmentReminder) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Create(reminder).Error
+}
+
+// GetRemindersByAppointment devuelve el historial de recordatorios de la cita, del más antiguo al
+// más reciente. Si la cita no existe devuelve gorm.ErrRecordNotFound.
+func (r *AppointmentReminderRepository) GetRemindersByAppointment(ctx context.Context, appointmentID int) ([]models.AppointmentReminder, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var count int64
+	if err := r.DB.WithContext(ctx).Model(&models.Appointment{}).Where("id = ?", appointmentID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var reminders []models.AppointmentReminder
+	err := r.DB.WithContext(ctx).Where("appointment_id = ?", appointmentID).Order("id").Find(&reminders).Error
+	return reminders, err
+}
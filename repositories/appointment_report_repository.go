@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/config"
+	"totesbackend/dtos"
+
+	"gorm.io/gorm"
+)
+
+type AppointmentReportRepository struct {
+	DB *gorm.DB
+	// opcional: reportes, listados y búsquedas se leen de aquí
+	Replica *gorm.DB
+}
+
+func NewAppointmentReportRepository(db *gorm.DB) *AppointmentReportRepository {
+	return &AppointmentReportRepository{DB: db}
+}
+
+// GetAppointmentCountsByPeriod cuenta las citas del rango por periodo (day, week o month) y estado.
+func (r *AppointmentReportRepository) GetAppointmentCountsByPeriod(ctx context.Context, startDate, endDate time.Time, groupBy string) ([]dtos.AppointmentPeriodDTO, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var periods []dtos.AppointmentPeriodDTO
+	err := reader(r.DB, r.Replica).WithContext(ctx).Table("appointments").
+		Select("date_trunc('"+groupBy+"', date_time) AS period, COUNT(*) AS total, "+
+			"COUNT(*) FILTER (WHERE state_id = ?) AS pending, COUNT(*) FILTER (WHERE state_id = ?) AS confirmed, "+
+			"COUNT(*) FILTER (WHERE state_id = ?) AS completed, COUNT(*) FILTER (WHERE state_id = ?) AS cancelled, "+
+			"COUNT(*) FILTER (WHERE state_id = ?) AS no_show",
+			config.APPOINTMENT_STATE_PENDING, config.APPOINTMENT_STATE_CONFIRMED, config.APPOINTMENT_STATE_COMPLETED,
+			config.APPOINTMENT_STATE_CANCELLED, config.APPOINTMENT_STATE_NO_SHOW).
+		Where("date_time BETWEEN ? AND ?", startDate, endDate).
+		Group("period").
+		Order("period").
+		Scan(&periods).Error
+	if err != nil {
+		return nil, err
+	}
+	return periods, nil
+}
+
+// GetAppointmentCountsByHour cuenta las citas no canceladas del rango por hora del día, de la hora
+// con más citas a la de menos.
+func (r *AppointmentReportRepository) GetAppointmentCountsByHour(ctx context.Context, startDate, endDate time.Time) ([]dtos.AppointmentHourDTO, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var hours []dtos.AppointmentHourDTO
+	err := reader(r.DB, r.Replica).WithContext(ctx).Table("appointments").
+		Select("EXTRACT(HOUR FROM date_time)::int AS hour, COUNT(*) AS appointments, "+
+			"COUNT(*) FILTER (WHERE state_id = ?) AS no_show", config.APPOINTMENT_STATE_NO_SHOW).
+		Where("date_time BETWEEN ? AND ? AND state_id <> ?", startDate, endDate, config.APPOINTMENT_STATE_CANCELLED).
+		Group("hour").
+		Order("appointments DESC, hour").
+		Scan(&hours).Error
+	if err != nil {
+		return nil, err
+	}
+	return hours, nil
+}
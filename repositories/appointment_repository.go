@@ -1,7 +1,10 @@
 package repositories
 
 import (
+	"context"
 	"time"
+	"totesbackend/config"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -15,93 +18,171 @@ func NewAppointmentRepository(db *gorm.DB) *AppointmentRepository {
 	return &AppointmentRepository{DB: db}
 }
 
-func (r *AppointmentRepository) GetAppointmentByID(id int) (*models.Appointment, error) {
+func (r *AppointmentRepository) WithTx(tx Tx) AppointmentRepositoryInterface {
+	return &AppointmentRepository{DB: tx.Conn()}
+}
+
+func (r *AppointmentRepository) GetAppointmentByID(ctx context.Context, id int) (*models.Appointment, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var appointment models.Appointment
-	err := r.DB.First(&appointment, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).Preload("State").First(&appointment, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &appointment, nil
 }
 
-func (r *AppointmentRepository) GetAllAppointments() ([]models.Appointment, error) {
-	var appointments []models.Appointment
-	err := r.DB.Find(&appointments).Error
-	if err != nil {
-		return nil, err
-	}
-	return appointments, nil
+func (r *AppointmentRepository) GetAllAppointments(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Appointment, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return paginate[models.Appointment](r.DB.WithContext(ctx).Preload("State"), pagination)
 }
 
-func (r *AppointmentRepository) SearchAppointmentsByState(state bool) ([]models.Appointment, error) {
-	var appointments []models.Appointment
-	err := r.DB.Where("state = ?", state).Find(&appointments).Error
-	if err != nil {
-		return nil, err
-	}
-	return appointments, nil
+func (r *AppointmentRepository) SearchAppointmentsByState(ctx context.Context, stateID int, pagination dtos.PaginationDTO) ([]models.Appointment, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Preload("State").Where("state_id = ?", stateID)
+	return paginate[models.Appointment](db, pagination)
 }
 
-func (r *AppointmentRepository) GetAppointmentsByCustomerID(customerID int) ([]models.Appointment, error) {
-	var appointments []models.Appointment
-	err := r.DB.Where("customer_id = ?", customerID).Find(&appointments).Error
-	if err != nil {
-		return nil, err
-	}
-	return appointments, nil
+func (r *AppointmentRepository) GetAppointmentsByCustomerID(ctx context.Context, customerID int, pagination dtos.PaginationDTO) ([]models.Appointment, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Preload("State").Where("customer_id = ?", customerID)
+	return paginate[models.Appointment](db, pagination)
 }
 
-func (r *AppointmentRepository) CreateAppointment(appointment *models.Appointment) (*models.Appointment, error) {
-	if err := r.DB.Create(appointment).Error; err != nil {
+func (r *AppointmentRepository) CreateAppointment(ctx context.Context, appointment *models.Appointment) (*models.Appointment, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	if err := r.DB.WithContext(ctx).Create(appointment).Error; err != nil {
 		return nil, err
 	}
 	return appointment, nil
 }
 
-func (r *AppointmentRepository) UpdateAppointment(appointment *models.Appointment) error {
-	if err := r.DB.Save(appointment).Error; err != nil {
-		return err
-	}
-	return nil
-}
+// GetUnlinkedAppointments devuelve, por id ascendente desde afterID, las citas cuyo customer_id no
+// corresponde a ningún cliente.
+func (r *AppointmentRepository) GetUnlinkedAppointments(ctx context.Context, afterID, limit int) ([]models.Appointment, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
 
-func (r *AppointmentRepository) SearchAppointmentsByID(query string) ([]models.Appointment, error) {
 	var appointments []models.Appointment
-	err := r.DB.Where("CAST(id AS TEXT) LIKE ?", query+"%").Find(&appointments).Error
-	if err != nil {
-		return nil, err
-	}
-	return appointments, nil
+	err := r.DB.WithContext(ctx).
+		Where("id > ? AND NOT EXISTS (SELECT 1 FROM customers WHERE customers.id = appointments.customer_id)", afterID).
+		Order("id").Limit(limit).Find(&appointments).Error
+	return appointments, err
 }
 
-func (r *AppointmentRepository) SearchAppointmentsByCustomerID(query string) ([]models.Appointment, error) {
-	var appointments []models.Appointment
-	err := r.DB.Where("CAST(customer_id AS TEXT) LIKE ?", query+"%").Find(&appointments).Error
-	if err != nil {
-		return nil, err
-	}
-	return appointments, nil
+func (r *AppointmentRepository) SetAppointmentCustomer(ctx context.Context, id, customerID int) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Model(&models.Appointment{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"customer_id": customerID, "version": nextVersion}).Error
+}
+
+// UpdateAppointment guarda la cita si sigue en appointment.Version; devuelve false si cambió antes.
+func (r *AppointmentRepository) UpdateAppointment(ctx context.Context, appointment *models.Appointment) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return saveVersioned(r.DB.WithContext(ctx), appointment, &appointment.Version)
+}
+
+func (r *AppointmentRepository) SearchAppointmentsByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Appointment, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Preload("State").Where("CAST(id AS TEXT) LIKE ?", query+"%")
+	return paginate[models.Appointment](db, pagination)
 }
 
-func (r *AppointmentRepository) GetAppointmentByCustomerIDAndDate(customerID int, dateTime time.Time) (*models.Appointment, error) {
+func (r *AppointmentRepository) SearchAppointmentsByCustomerID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Appointment, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Preload("State").Where("CAST(customer_id AS TEXT) LIKE ?", query+"%")
+	return paginate[models.Appointment](db, pagination)
+}
+
+func (r *AppointmentRepository) GetAppointmentByCustomerIDAndDate(ctx context.Context, customerID int, dateTime time.Time) (*models.Appointment, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var appointment models.Appointment
-	err := r.DB.Where("customer_id = ? AND date_time = ?", customerID, dateTime).First(&appointment).Error
+	err := r.DB.WithContext(ctx).Preload("State").Where("customer_id = ? AND date_time = ?", customerID, dateTime).First(&appointment).Error
 	if err != nil {
 		return nil, err
 	}
 	return &appointment, nil
 }
 
-func (r *AppointmentRepository) CountAppointmentsAtDateTime(dateTime time.Time) (int64, error) {
+// LockSlot bloquea el horario hasta el fin de la transacción, para revisar el cupo y guardar la cita
+// sin que otra reserva del mismo horario se cuele en medio. Solo tiene efecto dentro de WithTx.
+func (r *AppointmentRepository) LockSlot(ctx context.Context, dateTime time.Time) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Exec("SELECT pg_advisory_xact_lock(?, ?)", appointmentSlotLockKey, int32(dateTime.Unix()/60)).Error
+}
+
+// Primera clave de los locks de horario de LockSlot; la segunda es el minuto de la cita.
+const appointmentSlotLockKey = 7203412
+
+// CountAppointmentsAtDateTime cuenta las citas sin empleado que ocupan el horario, sin contar
+// excludeID (0 para no excluir ninguna); las canceladas lo liberan.
+func (r *AppointmentRepository) CountAppointmentsAtDateTime(ctx context.Context, dateTime time.Time, excludeID int) (int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var count int64
+	err := r.DB.WithContext(ctx).Model(&models.Appointment{}).
+		Where("date_time = ? AND employee_id IS NULL AND id <> ? AND state_id <> ?", dateTime, excludeID, config.APPOINTMENT_STATE_CANCELLED).
+		Count(&count).Error
+	return count, err
+}
+
+// CountEmployeeAppointmentsAtDateTime cuenta las citas del empleado en el horario sin contar
+// excludeID (0 para no excluir ninguna); las canceladas no cuentan.
+func (r *AppointmentRepository) CountEmployeeAppointmentsAtDateTime(ctx context.Context, employeeID int, dateTime time.Time, excludeID int) (int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var count int64
-	err := r.DB.Model(&models.Appointment{}).
-		Where("date_time = ?", dateTime).
+	err := r.DB.WithContext(ctx).Model(&models.Appointment{}).
+		Where("employee_id = ? AND date_time = ? AND id <> ? AND state_id <> ?", employeeID, dateTime, excludeID, config.APPOINTMENT_STATE_CANCELLED).
 		Count(&count).Error
 	return count, err
 }
 
-func (r *AppointmentRepository) DeleteAppointmentByID(id int) error {
-	result := r.DB.Delete(&models.Appointment{}, id)
+// GetAppointmentsByEmployeeID devuelve las citas del empleado en orden cronológico, entre from y to
+// si se envían.
+func (r *AppointmentRepository) GetAppointmentsByEmployeeID(ctx context.Context, employeeID int, from, to *time.Time, pagination dtos.PaginationDTO) ([]models.Appointment, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Preload("State").Where("employee_id = ?", employeeID)
+	if from != nil {
+		db = db.Where("date_time >= ?", *from)
+	}
+	if to != nil {
+		db = db.Where("date_time <= ?", *to)
+	}
+	return paginate[models.Appointment](db.Order("date_time"), pagination)
+}
+
+func (r *AppointmentRepository) DeleteAppointmentByID(ctx context.Context, id int) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Delete(&models.Appointment{}, id)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -111,25 +192,75 @@ func (r *AppointmentRepository) DeleteAppointmentByID(id int) error {
 	return nil
 }
 
-func (r *AppointmentRepository) CountAppointmentsByHourOnDate(date time.Time) ([]int, error) {
-	counts := make([]int, 9) // from 9:00 to 17:00
+// CountAppointmentsByHourOnDate cuenta las citas de date en cada hora desde openingHour hasta
+// lastSlotHour, en ese orden, sin las canceladas. Con unassigned solo cuenta las que no tienen empleado.
+func (r *AppointmentRepository) CountAppointmentsByHourOnDate(ctx context.Context, date time.Time, openingHour, lastSlotHour int, unassigned bool) ([]int, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	counts := make([]int, lastSlotHour-openingHour+1)
 
-	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 9, 0, 0, 0, date.Location())
-	endOfDay := time.Date(date.Year(), date.Month(), date.Day(), 17, 59, 59, 0, date.Location())
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), openingHour, 0, 0, 0, date.Location())
+	endOfDay := time.Date(date.Year(), date.Month(), date.Day(), lastSlotHour, 59, 59, 0, date.Location())
 
+	db := r.DB.WithContext(ctx).Where("date_time BETWEEN ? AND ? AND state_id <> ?", startOfDay, endOfDay, config.APPOINTMENT_STATE_CANCELLED)
+	if unassigned {
+		db = db.Where("employee_id IS NULL")
+	}
 	var appointments []models.Appointment
-	err := r.DB.Where("date_time BETWEEN ? AND ?", startOfDay, endOfDay).Find(&appointments).Error
+	err := db.Find(&appointments).Error
 	if err != nil {
 		return nil, err
 	}
 
 	for _, appointment := range appointments {
 		hour := appointment.DateTime.Hour()
-		if hour >= 9 && hour <= 17 {
-			index := hour - 9
-			counts[index]++
+		if hour >= openingHour && hour <= lastSlotHour {
+			counts[hour-openingHour]++
 		}
 	}
 
 	return counts, nil
 }
+
+func (r *AppointmentRepository) GetAppointmentStateTypes(ctx context.Context) ([]models.AppointmentStateType, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var states []models.AppointmentStateType
+	err := r.DB.WithContext(ctx).Order("id").Find(&states).Error
+	return states, err
+}
+
+// ChangeAppointmentState pasa la cita de change.FromStateID a change.ToStateID y guarda change en el
+// historial, en una transacción. Devuelve false si la cita ya no estaba en FromStateID.
+func (r *AppointmentRepository) ChangeAppointmentState(ctx context.Context, change *models.AppointmentStateChange) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	changed := false
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Appointment{}).
+			Where("id = ? AND state_id = ?", change.AppointmentID, change.FromStateID).
+			Updates(map[string]interface{}{"state_id": change.ToStateID, "version": nextVersion})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if err := tx.Create(change).Error; err != nil {
+			return err
+		}
+		changed = true
+		return nil
+	})
+	return changed, err
+}
+
+// GetAppointmentStateHistory devuelve los cambios de estado de la cita, del más antiguo al más reciente.
+func (r *AppointmentRepository) GetAppointmentStateHistory(ctx context.Context, appointmentID int) ([]models.AppointmentStateChange, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var changes []models.AppointmentStateChange
+	err := r.DB.WithContext(ctx).Where("appointment_id = ?", appointmentID).Order("id").Find(&changes).Error
+	return changes, err
+}
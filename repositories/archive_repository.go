@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ArchiveRepository struct {
+	DB *gorm.DB
+}
+
+func NewArchiveRepository(db *gorm.DB) *ArchiveRepository {
+	return &ArchiveRepository{DB: db}
+}
+
+// GetArchivableInvoices devuelve, desde afterID, facturas emitidas antes de before. Las facturas a
+// crédito sin pagar se quedan en invoices porque todavía reciben recordatorios y pagos, salvo que se
+// hayan anulado.
+func (r *ArchiveRepository) GetArchivableInvoices(ctx context.Context, before time.Time, afterID, limit int) ([]models.Invoice, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var invoices []models.Invoice
+	err := r.DB.WithContext(ctx).
+		Preload("Items").Preload("Discounts").Preload("Taxes").
+		Where("date_time < ? AND id > ?", before, afterID).
+		Where("due_date IS NULL OR paid_at IS NOT NULL OR cancelled_at IS NOT NULL").
+		Order("id").
+		Limit(limit).
+		Find(&invoices).Error
+	return invoices, err
+}
+
+func (r *ArchiveRepository) GetArchivableAppointments(ctx context.Context, before time.Time, afterID, limit int) ([]models.Appointment, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var appointments []models.Appointment
+	err := r.DB.WithContext(ctx).Preload("State").
+		Where("date_time < ? AND id > ?", before, afterID).
+		Order("id").
+		Limit(limit).
+		Find(&appointments).Error
+	return appointments, err
+}
+
+// ArchiveInvoice guarda record y borra la factura con sus ítems, descuentos, impuestos y
+// recordatorios en una transacción. Devuelve false sin cambiar nada si la factura ya no existe o
+// cambió de versión desde que se leyó.
+func (r *ArchiveRepository) ArchiveInvoice(ctx context.Context, record *models.ArchivedInvoice, version int) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	archived := false
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked []int
+		if err := tx.Model(&models.Invoice{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND version = ?", record.ID, version).Pluck("id", &locked).Error; err != nil {
+			return err
+		}
+		if len(locked) == 0 {
+			return nil
+		}
+
+		for _, table := range []string{"invoice_items", "invoice_discounts", "invoice_taxes", "invoice_reminders"} {
+			if err := tx.Exec("DELETE FROM "+table+" WHERE invoice_id = ?", record.ID).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Delete(&models.Invoice{}, record.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(record).Error; err != nil {
+			return err
+		}
+		archived = true
+		return nil
+	})
+	return archived, err
+}
+
+// ArchiveAppointment es el equivalente de ArchiveInvoice para las citas.
+func (r *ArchiveRepository) ArchiveAppointment(ctx context.Context, record *models.ArchivedAppointment, version int) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	archived := false
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND version = ?", record.ID, version).Delete(&models.Appointment{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.Exec("DELETE FROM appointment_reminders WHERE appointment_id = ?", record.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM appointment_state_changes WHERE appointment_id = ?", record.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(record).Error; err != nil {
+			return err
+		}
+		archived = true
+		return nil
+	})
+	return archived, err
+}
+
+func (r *ArchiveRepository) SearchArchivedInvoices(ctx context.Context, filter dtos.ArchiveFilterDTO) ([]models.ArchivedInvoice, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return paginate[models.ArchivedInvoice](archiveFilter(r.DB.WithContext(ctx), filter), filter.PaginationDTO)
+}
+
+func (r *ArchiveRepository) SearchArchivedAppointments(ctx context.Context, filter dtos.ArchiveFilterDTO) ([]models.ArchivedAppointment, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return paginate[models.ArchivedAppointment](archiveFilter(r.DB.WithContext(ctx), filter), filter.PaginationDTO)
+}
+
+func (r *ArchiveRepository) GetArchivedInvoiceByID(ctx context.Context, id int) (*models.ArchivedInvoice, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var record models.ArchivedInvoice
+	if err := r.DB.WithContext(ctx).First(&record, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *ArchiveRepository) GetArchivedAppointmentByID(ctx context.Context, id int) (*models.ArchivedAppointment, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var record models.ArchivedAppointment
+	if err := r.DB.WithContext(ctx).First(&record, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func archiveFilter(query *gorm.DB, filter dtos.ArchiveFilterDTO) *gorm.DB {
+	if filter.CustomerID != nil {
+		query = query.Where("customer_id = ?", *filter.CustomerID)
+	}
+	if filter.From != nil {
+		query = query.Where("date_time >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("date_time <= ?", *filter.To)
+	}
+	return query
+}
@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"context"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type AuditRepository struct {
+	DB *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{DB: db}
+}
+
+func (r *AuditRepository) CreateAuditEntry(ctx context.Context, entry *models.AuditEntry) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Create(entry).Error
+}
+
+func (r *AuditRepository) GetAuditEntries(ctx context.Context, entity, entityID string) ([]models.AuditEntry, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var entries []models.AuditEntry
+	err := r.DB.WithContext(ctx).Where("entity = ? AND entity_id = ?", entity, entityID).
+		Order("date_time DESC, id DESC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"gorm.io/gorm"
 )
 
@@ -12,9 +13,12 @@ func NewAuthorizationRepository(db *gorm.DB) *AuthorizationRepository {
 	return &AuthorizationRepository{DB: db}
 }
 
-func (r *AuthorizationRepository) UserHasPermission(email string, permissionID int) (bool, error) {
+func (r *AuthorizationRepository) UserHasPermission(ctx context.Context, email string, permissionID int) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var count int64
-	err := r.DB.Table("users").
+	err := r.DB.WithContext(ctx).Table("users").
 		Joins("JOIN user_types ON users.user_type_id = user_types.id").
 		Joins("JOIN user_type_has_role ON user_types.id = user_type_has_role.user_type_id").
 		Joins("JOIN roles ON user_type_has_role.role_id = roles.id").
@@ -29,3 +33,18 @@ func (r *AuthorizationRepository) UserHasPermission(email string, permissionID i
 
 	return count > 0, nil
 }
+
+// GetUserIDsWithPermission devuelve los usuarios que tienen el permiso por alguno de los roles de su tipo.
+func (r *AuthorizationRepository) GetUserIDsWithPermission(ctx context.Context, permissionID int) ([]int, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var ids []int
+	err := r.DB.WithContext(ctx).Table("users").
+		Joins("JOIN user_type_has_role ON users.user_type_id = user_type_has_role.user_type_id").
+		Joins("JOIN role_permission ON user_type_has_role.role_id = role_permission.role_id").
+		Where("role_permission.permission_id = ?", permissionID).
+		Distinct().
+		Pluck("users.id", &ids).Error
+	return ids, err
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BusinessHoursRepository struct {
+	DB *gorm.DB
+}
+
+func NewBusinessHoursRepository(db *gorm.DB) *BusinessHoursRepository {
+	return &BusinessHoursRepository{DB: db}
+}
+
+// GetBusinessHours devuelve los días configurados, domingo primero.
+func (r *BusinessHoursRepository) GetBusinessHours(ctx context.Context) ([]models.BusinessHours, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var hours []models.BusinessHours
+	err := r.DB.WithContext(ctx).Order("weekday").Find(&hours).Error
+	return hours, err
+}
+
+// GetBusinessHoursForWeekday devuelve nil si el día no está configurado.
+func (r *BusinessHoursRepository) GetBusinessHoursForWeekday(ctx context.Context, weekday int) (*models.BusinessHours, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var hours []models.BusinessHours
+	err := r.DB.WithContext(ctx).Where("weekday = ?", weekday).Limit(1).Find(&hours).Error
+	if err != nil || len(hours) == 0 {
+		return nil, err
+	}
+	return &hours[0], nil
+}
+
+// SaveBusinessHours crea o reemplaza el horario de cada día recibido.
+func (r *BusinessHoursRepository) SaveBusinessHours(ctx context.Context, hours []models.BusinessHours) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "weekday"}},
+		DoUpdates: clause.AssignmentColumns([]string{"open", "opening_hour", "last_slot_hour"}),
+	}).Create(&hours).Error
+}
@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,63 +16,67 @@ func NewCommentRepository(db *gorm.DB) *CommentRepository {
 	return &CommentRepository{DB: db}
 }
 
-func (r *CommentRepository) GetCommentByID(id int) (*models.Comment, error) {
+func (r *CommentRepository) GetCommentByID(ctx context.Context, id int) (*models.Comment, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var comment models.Comment
-	err := r.DB.First(&comment, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).First(&comment, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &comment, nil
 }
 
-func (r *CommentRepository) GetAllComments() ([]models.Comment, error) {
-	var comments []models.Comment
-	err := r.DB.Find(&comments).Error
-	if err != nil {
-		return nil, err
-	}
-	return comments, nil
+func (r *CommentRepository) GetAllComments(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Comment, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return paginate[models.Comment](r.DB.WithContext(ctx), pagination)
 }
 
-func (r *CommentRepository) SearchCommentsByEmail(email string) ([]models.Comment, error) {
-	var comments []models.Comment
-	err := r.DB.Where("LOWER(email) LIKE LOWER(?)", email+"%").Find(&comments).Error
-	if err != nil {
-		return nil, err
-	}
-	return comments, nil
+func (r *CommentRepository) SearchCommentsByEmail(ctx context.Context, email string, pagination dtos.PaginationDTO) ([]models.Comment, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Where("LOWER(email) LIKE LOWER(?)", email+"%")
+	return paginate[models.Comment](db, pagination)
 }
 
-func (r *CommentRepository) CreateComment(comment *models.Comment) (*models.Comment, error) {
-	if err := r.DB.Create(comment).Error; err != nil {
+func (r *CommentRepository) CreateComment(ctx context.Context, comment *models.Comment) (*models.Comment, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	if err := r.DB.WithContext(ctx).Create(comment).Error; err != nil {
 		return nil, err
 	}
 	return comment, nil
 }
 
-func (r *CommentRepository) UpdateComment(comment *models.Comment) error {
+func (r *CommentRepository) UpdateComment(ctx context.Context, comment *models.Comment) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var existingComment models.Comment
-	if err := r.DB.First(&existingComment, "id = ?", comment.ID).Error; err != nil {
+	if err := r.DB.WithContext(ctx).First(&existingComment, "id = ?", comment.ID).Error; err != nil {
 		return err
 	}
 
-	return r.DB.Save(comment).Error
+	return r.DB.WithContext(ctx).Save(comment).Error
 }
 
-func (r *CommentRepository) SearchCommentsByID(query string) ([]models.Comment, error) {
-	var comments []models.Comment
-	err := r.DB.Where("CAST(id AS TEXT) LIKE ?", query+"%").Find(&comments).Error
-	if err != nil {
-		return nil, err
-	}
-	return comments, nil
+func (r *CommentRepository) SearchCommentsByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Comment, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Where("CAST(id AS TEXT) LIKE ?", query+"%")
+	return paginate[models.Comment](db, pagination)
 }
 
-func (r *CommentRepository) SearchCommentsByName(name string) ([]models.Comment, error) {
-	var comments []models.Comment
-	err := r.DB.Where("LOWER(name) LIKE LOWER(?)", name+"%").Find(&comments).Error
-	if err != nil {
-		return nil, err
-	}
-	return comments, nil
+func (r *CommentRepository) SearchCommentsByName(ctx context.Context, name string, pagination dtos.PaginationDTO) ([]models.Comment, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Where(unaccentPrefix("name"), name+"%")
+	return paginate[models.Comment](db, pagination)
 }
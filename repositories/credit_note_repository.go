@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CreditNoteRepository struct {
+	DB *gorm.DB
+	// opcional: reportes, listados y búsquedas se leen de aquí
+	Replica *gorm.DB
+}
+
+func NewCreditNoteRepository(db *gorm.DB) *CreditNoteRepository {
+	return &CreditNoteRepository{DB: db}
+}
+
+func (r *CreditNoteRepository) GetCreditNoteByID(ctx context.Context, id int) (*models.CreditNote, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var note models.CreditNote
+	err := r.DB.WithContext(ctx).Preload("Items.Item").First(&note, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+// GetCreditNotes lista las notas crédito, las más recientes primero; invoiceID nil no filtra.
+func (r *CreditNoteRepository) GetCreditNotes(ctx context.Context, invoiceID *int, pagination dtos.PaginationDTO) ([]models.CreditNote, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := reader(r.DB, r.Replica).WithContext(ctx).Preload("Items").Order("id DESC")
+	if invoiceID != nil {
+		db = db.Where("invoice_id = ?", *invoiceID)
+	}
+	return paginate[models.CreditNote](db, pagination)
+}
+
+// CancelInvoice anula la factura con note en una transacción: copia en la nota las líneas y los
+// valores de la factura, devuelve al stock las unidades que no volvieron ya con una devolución, con
+// un movimiento por item a partir de movement, y marca la factura como anulada en at. Devuelve false
+// si la factura ya estaba anulada y gorm.ErrRecordNotFound si no existe.
+func (r *CreditNoteRepository) CancelInvoice(ctx context.Context, invoiceID int, note *models.CreditNote, movement models.StockMovement, at time.Time) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var cancelled bool
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var invoice models.Invoice
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&invoice, "id = ?", invoiceID).Error; err != nil {
+			return err
+		}
+		if invoice.CancelledAt != nil {
+			return nil
+		}
+
+		// una factura puede repetir un item en varias líneas; la nota lleva una por item
+		var sold []models.CreditNoteItem
+		if err := tx.Model(&models.InvoiceItem{}).Select("item_id, SUM(amount) AS amount").
+			Where("invoice_id = ?", invoiceID).Group("item_id").Order("item_id").
+			Scan(&sold).Error; err != nil {
+			return err
+		}
+		// las unidades que ya volvieron con una devolución no se devuelven otra vez
+		returned, err := returnedAmounts(tx, invoiceID)
+		if err != nil {
+			return err
+		}
+		for _, item := range sold {
+			item.Amount -= returned[item.ItemID]
+			if item.Amount > 0 {
+				note.Items = append(note.Items, item)
+			}
+		}
+		note.InvoiceID = invoiceID
+		note.Subtotal = invoice.Subtotal
+		note.Total = invoice.Total
+		note.CreatedAt = at
+		if err := tx.Omit("Items").Create(note).Error; err != nil {
+			return err
+		}
+		for i := range note.Items {
+			note.Items[i].CreditNoteID = note.ID
+		}
+		if len(note.Items) > 0 {
+			if err := tx.Omit("Item").Create(&note.Items).Error; err != nil {
+				return err
+			}
+		}
+
+		movement.ReferenceID = &note.ID
+		for _, item := range note.Items {
+			movement.ItemID = item.ItemID
+			movement.Delta = item.Amount
+			if err := applyStockMovement(tx, movement); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&models.Invoice{}).Where("id = ?", invoiceID).
+			UpdateColumns(map[string]interface{}{"cancelled_at": at, "version": nextVersion}).Error; err != nil {
+			return err
+		}
+		cancelled = true
+		return nil
+	})
+	return cancelled, err
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type CurrencyRepository struct {
+	DB *gorm.DB
+}
+
+func NewCurrencyRepository(db *gorm.DB) *CurrencyRepository {
+	return &CurrencyRepository{DB: db}
+}
+
+func (r *CurrencyRepository) GetCurrencies(ctx context.Context) ([]models.Currency, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var currencies []models.Currency
+	err := r.DB.WithContext(ctx).Order("code").Find(&currencies).Error
+	return currencies, err
+}
+
+func (r *CurrencyRepository) GetCurrencyByCode(ctx context.Context, code string) (*models.Currency, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var currency models.Currency
+	if err := r.DB.WithContext(ctx).First(&currency, "code = ?", code).Error; err != nil {
+		return nil, err
+	}
+	return &currency, nil
+}
+
+func (r *CurrencyRepository) CreateCurrency(ctx context.Context, currency *models.Currency) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Create(currency).Error
+}
+
+// UpdateCurrency guarda el nombre, el símbolo y la tasa de cambio; el código no cambia.
+func (r *CurrencyRepository) UpdateCurrency(ctx context.Context, currency *models.Currency) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Model(currency).Select("name", "symbol", "exchange_rate", "updated_at").Updates(currency).Error
+}
@@ -1,6 +1,9 @@
 package repositories
 
 import (
+	"context"
+	"database/sql"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -8,85 +11,289 @@ import (
 
 type CustomerRepository struct {
 	DB *gorm.DB
+	// opcional: reportes, listados y búsquedas se leen de aquí
+	Replica *gorm.DB
 }
 
 func NewCustomerRepository(db *gorm.DB) *CustomerRepository {
 	return &CustomerRepository{DB: db}
 }
 
-func (r *CustomerRepository) GetCustomerByID(id int) (*models.Customer, error) {
+func (r *CustomerRepository) WithTx(tx Tx) CustomerRepositoryInterface {
+	return &CustomerRepository{DB: tx.Conn()}
+}
+
+func (r *CustomerRepository) GetCustomerByID(ctx context.Context, id int) (*models.Customer, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var customer models.Customer
-	err := r.DB.First(&customer, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).First(&customer, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &customer, nil
 }
 
-func (r *CustomerRepository) GetCustomerByCustomerID(customerID string) (*models.Customer, error) {
+func (r *CustomerRepository) GetCustomerByCustomerID(ctx context.Context, customerID string) (*models.Customer, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var customer models.Customer
-	err := r.DB.First(&customer, "customer_id = ?", customerID).Error
+	err := r.DB.WithContext(ctx).First(&customer, "customer_id = ?", customerID).Error
 	if err != nil {
 		return nil, err
 	}
 	return &customer, nil
 }
 
-func (r *CustomerRepository) GetAllCustomers() ([]models.Customer, error) {
-	var customers []models.Customer
-	err := r.DB.Find(&customers).Error
-	if err != nil {
-		return nil, err
-	}
-	return customers, nil
+func (r *CustomerRepository) GetAllCustomers(ctx context.Context, includeDeleted bool, pagination dtos.PaginationDTO) ([]models.Customer, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return paginate[models.Customer](customerScope(reader(r.DB, r.Replica).WithContext(ctx), includeDeleted), pagination)
 }
 
-func (r *CustomerRepository) GetCustomerByEmail(email string) (*models.Customer, error) {
+func (r *CustomerRepository) GetCustomerByEmail(ctx context.Context, email string) (*models.Customer, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var customer models.Customer
-	err := r.DB.First(&customer, "email = ?", email).Error
+	err := r.DB.WithContext(ctx).First(&customer, "email = ?", email).Error
 	if err != nil {
 		return nil, err
 	}
 	return &customer, nil
 }
 
-func (r *CustomerRepository) CreateCustomer(customer *models.Customer) (*models.Customer, error) {
-	if err := r.DB.Create(customer).Error; err != nil {
+// FindCustomerByEmail compara sin distinguir mayúsculas; devuelve nil si no existe.
+func (r *CustomerRepository) FindCustomerByEmail(ctx context.Context, email string) (*models.Customer, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var customers []models.Customer
+	err := r.DB.WithContext(ctx).Where("LOWER(email) = LOWER(?)", email).Limit(1).Find(&customers).Error
+	if err != nil || len(customers) == 0 {
 		return nil, err
 	}
-	return customer, nil
+	return &customers[0], nil
 }
 
-func (r *CustomerRepository) UpdateCustomer(customer *models.Customer) error {
-	if err := r.DB.Save(customer).Error; err != nil {
-		return err
+func (r *CustomerRepository) CreateCustomer(ctx context.Context, customer *models.Customer) (*models.Customer, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	if err := r.DB.WithContext(ctx).Create(customer).Error; err != nil {
+		return nil, err
 	}
-	return nil
+	return customer, nil
 }
 
-func (r *CustomerRepository) SearchCustomersByID(id string) ([]models.Customer, error) {
-	var customers []models.Customer
-	err := r.DB.Where("CAST(id AS TEXT) LIKE ?", id+"%").Find(&customers).Error
+// UpdateCustomer guarda el cliente si sigue en customer.Version; devuelve false si cambió antes.
+func (r *CustomerRepository) UpdateCustomer(ctx context.Context, customer *models.Customer) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return saveVersioned(r.DB.WithContext(ctx), customer, &customer.Version)
+}
+
+// GetCustomerDependencies cuenta las facturas, citas, ventas externas y órdenes de compra del cliente.
+func (r *CustomerRepository) GetCustomerDependencies(ctx context.Context, id int) (*dtos.CustomerDependenciesDTO, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var dependencies dtos.CustomerDependenciesDTO
+	err := r.DB.WithContext(ctx).Raw(`
+		SELECT
+			(SELECT COUNT(*) FROM invoices WHERE customer_id = @id) AS invoices,
+			(SELECT COUNT(*) FROM appointments WHERE customer_id = @id) AS appointments,
+			(SELECT COUNT(*) FROM external_sales WHERE customer_id = @id) AS external_sales,
+			(SELECT COUNT(*) FROM purchase_orders WHERE customer_id = @id) AS purchase_orders`,
+		sql.Named("id", id)).Scan(&dependencies).Error
 	if err != nil {
 		return nil, err
 	}
-	return customers, nil
+	return &dependencies, nil
 }
 
-func (r *CustomerRepository) SearchCustomersByName(name string) ([]models.Customer, error) {
-	var customers []models.Customer
-	err := r.DB.Where("LOWER(customer_name) LIKE LOWER(?)", name+"%").Find(&customers).Error
+// ReassignCustomerRecords pasa las citas, facturas y ventas externas de fromID a toID. Las citas y
+// facturas cambian de versión.
+func (r *CustomerRepository) ReassignCustomerRecords(ctx context.Context, fromID, toID int) (*dtos.CustomerReassignmentDTO, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx)
+	var reassigned dtos.CustomerReassignmentDTO
+	result := db.Model(&models.Appointment{}).Where("customer_id = ?", fromID).
+		Updates(map[string]interface{}{"customer_id": toID, "version": nextVersion})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	reassigned.Appointments = result.RowsAffected
+
+	result = db.Model(&models.Invoice{}).Where("customer_id = ?", fromID).
+		Updates(map[string]interface{}{"customer_id": toID, "version": nextVersion})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	reassigned.Invoices = result.RowsAffected
+
+	result = db.Model(&models.ExternalSale{}).Where("customer_id = ?", fromID).Update("customer_id", toID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	reassigned.ExternalSales = result.RowsAffected
+	return &reassigned, nil
+}
+
+// DeleteCustomer marca el cliente como borrado (deleted_at). Sus facturas, citas, ventas externas,
+// órdenes de compra y preferencias de notificación se conservan para poder restaurarlo.
+func (r *CustomerRepository) DeleteCustomer(ctx context.Context, id int) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Delete(&models.Customer{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetDeletedCustomerByID devuelve el cliente solo si está borrado.
+func (r *CustomerRepository) GetDeletedCustomerByID(ctx context.Context, id int) (*models.Customer, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var customer models.Customer
+	err := r.DB.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").First(&customer, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
-	return customers, nil
+	return &customer, nil
+}
+
+// RestoreCustomer quita la marca de borrado; devuelve false si el cliente no estaba borrado.
+func (r *CustomerRepository) RestoreCustomer(ctx context.Context, id int) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Unscoped().Model(&models.Customer{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	return result.RowsAffected > 0, result.Error
 }
 
-func (r *CustomerRepository) SearchCustomersByLastName(lastname string) ([]models.Customer, error) {
+// withDeletedCustomers es el scope de los Preload("Customer"): las facturas, órdenes y ventas de un
+// cliente borrado lo siguen mostrando.
+func withDeletedCustomers(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+// StreamCustomers recorre en lotes los clientes no borrados creados dentro del rango de filter.
+func (r *CustomerRepository) StreamCustomers(ctx context.Context, filter dtos.CSVExportFilterDTO, fn func(models.Customer) error) error {
+	query := reader(r.DB, r.Replica).WithContext(ctx)
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at <= ?", *filter.To)
+	}
+
+	var batch []models.Customer
+	result := query.Order("id").FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+		for _, customer := range batch {
+			if err := fn(customer); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return result.Error
+}
+
+// customerScope incluye los clientes borrados en listados y búsquedas cuando se piden.
+func customerScope(db *gorm.DB, includeDeleted bool) *gorm.DB {
+	if includeDeleted {
+		return db.Unscoped()
+	}
+	return db
+}
+
+// FindCustomerDuplicateCandidates busca en la primaria los clientes con el mismo documento (sin
+// contar puntos, guiones ni espacios), el mismo correo o alguno de los teléfonos dados (sus últimos
+// dígitos). El servicio decide cuáles son duplicados probables.
+func (r *CustomerRepository) FindCustomerDuplicateCandidates(ctx context.Context, customerID, email string, phones []string) ([]models.Customer, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	conditions := r.DB.Where("regexp_replace(upper(customer_id), '[^0-9A-Z]', '', 'g') = ?", customerID).
+		Or("lower(email) = lower(?)", email)
+	for _, phone := range phones {
+		conditions = conditions.Or("regexp_replace(phone_numbers, '[^0-9]', '', 'g') LIKE ?", "%"+phone+"%")
+	}
+
 	var customers []models.Customer
-	err := r.DB.Where("LOWER(last_name) LIKE LOWER(?)", lastname+"%").Find(&customers).Error
-	if err != nil {
-		return nil, err
+	err := r.DB.WithContext(ctx).Where(conditions).Order("id").Limit(50).Find(&customers).Error
+	return customers, err
+}
+
+// GetCustomersToGeocode devuelve hasta limit clientes con dirección que todavía no se ha buscado.
+func (r *CustomerRepository) GetCustomersToGeocode(ctx context.Context, limit int) ([]models.Customer, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var customers []models.Customer
+	err := r.DB.WithContext(ctx).Where("address <> '' AND address_status = ''").Order("id").Limit(limit).Find(&customers).Error
+	return customers, err
+}
+
+// SetCustomerLocation guarda la dirección normalizada y la ubicación de customer sin cambiar su
+// versión. No aplica nada si la dirección ya no es previousAddress: alguien la editó mientras se
+// buscaba.
+func (r *CustomerRepository) SetCustomerLocation(ctx context.Context, customer *models.Customer, previousAddress string) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Model(&models.Customer{}).
+		Where("id = ? AND address = ?", customer.ID, previousAddress).
+		Select("address", "latitude", "longitude", "address_status", "geocoded_at").
+		Updates(customer)
+	return result.RowsAffected > 0, result.Error
+}
+
+// SearchCustomers aplica los filtros de search. search.SortBy debe venir ya validado como nombre de
+// columna.
+func (r *CustomerRepository) SearchCustomers(ctx context.Context, search dtos.CustomerSearchDTO) ([]models.Customer, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := customerScope(reader(r.DB, r.Replica).WithContext(ctx), search.IncludeDeleted)
+	if search.ID != "" {
+		db = db.Where("CAST(id AS TEXT) LIKE ?", search.ID+"%")
+	}
+	if search.Name != "" {
+		db = db.Where(unaccentPrefix("customer_name"), search.Name+"%")
+	}
+	if search.LastName != "" {
+		db = db.Where(unaccentPrefix("last_name"), search.LastName+"%")
+	}
+	if search.Email != "" {
+		db = db.Where("email ILIKE ?", search.Email+"%")
+	}
+	if search.Phone != "" {
+		db = db.Where("regexp_replace(phone_numbers, '[^0-9]', '', 'g') LIKE ?", "%"+search.Phone+"%")
+	}
+	if search.IdentifierTypeID != nil {
+		db = db.Where("identifier_type_id = ?", *search.IdentifierTypeID)
+	}
+	if search.IsBusiness != nil {
+		db = db.Where("is_business = ?", *search.IsBusiness)
+	}
+	if search.State != nil {
+		db = db.Where("customer_state = ?", *search.State)
 	}
-	return customers, nil
+	return paginate[models.Customer](db.Order(search.SortBy+" "+search.Order), search.PaginationDTO)
 }
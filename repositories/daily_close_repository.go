@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type DailyCloseRepository struct {
+	DB *gorm.DB
+}
+
+func NewDailyCloseRepository(db *gorm.DB) *DailyCloseRepository {
+	return &DailyCloseRepository{DB: db}
+}
+
+func (r *DailyCloseRepository) GetDailyCloseByDate(ctx context.Context, date time.Time) (*models.DailyClose, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var dailyClose models.DailyClose
+	err := r.DB.WithContext(ctx).Preload("PaymentsByMethod").
+		Where("date = ?", date.Format("2006-01-02")).
+		First(&dailyClose).Error
+	if err != nil {
+		return nil, err
+	}
+	return &dailyClose, nil
+}
+
+func (r *DailyCloseRepository) CreateDailyClose(ctx context.Context, dailyClose *models.DailyClose) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Create(dailyClose).Error
+}
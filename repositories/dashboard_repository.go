@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/config"
+	"totesbackend/dtos"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type DashboardRepository struct {
+	DB *gorm.DB
+	// opcional: reportes, listados y búsquedas se leen de aquí
+	Replica *gorm.DB
+}
+
+func NewDashboardRepository(db *gorm.DB) *DashboardRepository {
+	return &DashboardRepository{DB: db}
+}
+
+func (r *DashboardRepository) GetSalesBetween(ctx context.Context, start, end time.Time) (dtos.DashboardSalesDTO, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var sales dtos.DashboardSalesDTO
+	err := reader(r.DB, r.Replica).WithContext(ctx).Model(&models.Invoice{}).
+		Select("COUNT(*) AS invoice_count, COALESCE(SUM(total * exchange_rate), 0) AS total").
+		Where("date_time >= ? AND date_time < ? AND cancelled_at IS NULL", start, end).
+		Scan(&sales).Error
+	return sales, err
+}
+
+func (r *DashboardRepository) CountAppointmentsBetween(ctx context.Context, start, end time.Time) (int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var count int64
+	err := reader(r.DB, r.Replica).WithContext(ctx).Model(&models.Appointment{}).
+		Where("date_time >= ? AND date_time < ? AND state_id <> ?", start, end, config.APPOINTMENT_STATE_CANCELLED).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *DashboardRepository) CountLowStockItems(ctx context.Context) (int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var count int64
+	err := reader(r.DB, r.Replica).WithContext(ctx).Model(&models.Item{}).
+		Where("item_state = ? AND reorder_level > 0 AND stock <= reorder_level", true).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *DashboardRepository) CountPendingComments(ctx context.Context) (int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var count int64
+	err := reader(r.DB, r.Replica).WithContext(ctx).Model(&models.Comment{}).
+		Where("reviewed = ?", false).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type DataExportRepository struct {
+	DB *gorm.DB
+}
+
+func NewDataExportRepository(db *gorm.DB) *DataExportRepository {
+	return &DataExportRepository{DB: db}
+}
+
+func (r *DataExportRepository) CreateExport(ctx context.Context, export *models.DataExport) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Create(export).Error
+}
+
+// ClaimExport pasa la exportación más antigua en fromStatus a toStatus y la devuelve; nil si no hay
+// ninguna. SKIP LOCKED evita que dos instancias tomen la misma.
+func (r *DataExportRepository) ClaimExport(ctx context.Context, fromStatus, toStatus string, now time.Time) (*models.DataExport, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var exports []models.DataExport
+	err := r.DB.WithContext(ctx).Raw(`
+		UPDATE data_exports SET status = ?, started_at = ?
+		WHERE id = (
+			SELECT id FROM data_exports
+			WHERE status = ?
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, toStatus, now, fromStatus).Scan(&exports).Error
+	if err != nil || len(exports) == 0 {
+		return nil, err
+	}
+	return &exports[0], nil
+}
+
+func (r *DataExportRepository) SaveExport(ctx context.Context, export *models.DataExport) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Save(export).Error
+}
+
+func (r *DataExportRepository) GetExportByID(ctx context.Context, id int) (*models.DataExport, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var export models.DataExport
+	if err := r.DB.WithContext(ctx).First(&export, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *DataExportRepository) GetExports(ctx context.Context, pagination dtos.PaginationDTO) ([]models.DataExport, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var total int64
+	query := r.DB.WithContext(ctx).Model(&models.DataExport{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var exports []models.DataExport
+	err := query.Order("id DESC").Offset(pagination.Offset()).Limit(pagination.PageSize).Find(&exports).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return exports, total, nil
+}
+
+// GetExportFilesBefore devuelve las exportaciones terminadas antes de before que aún tienen archivo.
+func (r *DataExportRepository) GetExportFilesBefore(ctx context.Context, before time.Time) ([]models.DataExport, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var exports []models.DataExport
+	err := r.DB.WithContext(ctx).
+		Where("completed_at < ? AND file_name <> ''", before).
+		Find(&exports).Error
+	return exports, err
+}
+
+// StreamTable recorre todas las filas de table como mapas columna → valor. Como las exportaciones
+// leen tablas completas no se aplica el límite de tiempo de las consultas.
+func (r *DataExportRepository) StreamTable(ctx context.Context, table string, fn func(row map[string]interface{}) error) error {
+	if !r.DB.Migrator().HasTable(table) {
+		return errors.New("table " + table + " does not exist")
+	}
+
+	rows, err := r.DB.WithContext(ctx).Table(table).Order("1").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := make(map[string]interface{})
+		if err := r.DB.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
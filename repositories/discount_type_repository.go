@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,21 +16,60 @@ func NewDiscountTypeRepository(db *gorm.DB) *DiscountTypeRepository {
 	return &DiscountTypeRepository{DB: db}
 }
 
-func (r *DiscountTypeRepository) GetAllDiscountTypes() ([]models.DiscountType, error) {
-	var discountTypes []models.DiscountType
-	err := r.DB.Find(&discountTypes).Error
-	return discountTypes, err
+func (r *DiscountTypeRepository) GetAllDiscountTypes(ctx context.Context, pagination dtos.PaginationDTO) ([]models.DiscountType, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return paginate[models.DiscountType](r.DB.WithContext(ctx), pagination)
 }
 
-func (r *DiscountTypeRepository) GetDiscountTypeByID(id string) (*models.DiscountType, error) {
+func (r *DiscountTypeRepository) GetDiscountTypeByID(ctx context.Context, id string) (*models.DiscountType, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var discountType models.DiscountType
-	err := r.DB.First(&discountType, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).First(&discountType, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &discountType, nil
 }
 
-func (r *DiscountTypeRepository) CreateDiscountType(discount *models.DiscountType) error {
-	return r.DB.Create(discount).Error
+func (r *DiscountTypeRepository) CreateDiscountType(ctx context.Context, discount *models.DiscountType) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Create(discount).Error
+}
+
+func (r *DiscountTypeRepository) CreateDiscountTypes(ctx context.Context, discountTypes []models.DiscountType) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.Create(&discountTypes).Error
+	})
+}
+
+func (r *DiscountTypeRepository) UpdateDiscountType(ctx context.Context, discount *models.DiscountType) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Model(discount).
+		Select("name", "description", "is_percentage", "value", "active", "valid_from", "valid_to").Updates(discount).Error
+}
+
+// DeactivateDiscountType desactiva el descuento sin borrarlo; las facturas que ya lo aplicaron lo conservan.
+func (r *DiscountTypeRepository) DeactivateDiscountType(ctx context.Context, id string) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Model(&models.DiscountType{}).Where("id = ?", id).Update("active", false)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
@@ -0,0 +1,178 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EcommerceRepository struct {
+	DB *gorm.DB
+}
+
+func NewEcommerceRepository(db *gorm.DB) *EcommerceRepository {
+	return &EcommerceRepository{DB: db}
+}
+
+// UpsertListings registra los productos encontrados en la tienda. A los que ya existían solo se
+// les actualizan los IDs externos: sus valores sincronizados se conservan.
+func (r *EcommerceRepository) UpsertListings(ctx context.Context, listings []models.EcommerceListing) error {
+	if len(listings) == 0 {
+		return nil
+	}
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "item_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"external_id", "inventory_id", "updated_at"}),
+	}).CreateInBatches(listings, 500).Error
+}
+
+// DeleteListingsExcept borra los enlaces de los ítems que ya no están en la tienda.
+func (r *EcommerceRepository) DeleteListingsExcept(ctx context.Context, itemIDs []int) (int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	query := r.DB.WithContext(ctx)
+	if len(itemIDs) > 0 {
+		query = query.Where("item_id NOT IN ?", itemIDs)
+	} else {
+		query = query.Where("1 = 1")
+	}
+	result := query.Delete(&models.EcommerceListing{})
+	return result.RowsAffected, result.Error
+}
+
+// ClaimOutdatedListings toma hasta limit ítems publicados cuyo precio o existencias (0 si el ítem
+// está inactivo) ya no coinciden con lo último enviado, y corre su next_attempt_at a leaseUntil
+// para que otra instancia no los envíe a la vez.
+func (r *EcommerceRepository) ClaimOutdatedListings(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.EcommerceListing, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var listings []models.EcommerceListing
+	err := r.DB.WithContext(ctx).Raw(`
+		UPDATE ecommerce_listings SET next_attempt_at = ?
+		WHERE item_id IN (
+			SELECT l.item_id FROM ecommerce_listings l
+			JOIN items i ON i.id = l.item_id
+			WHERE l.next_attempt_at <= ?
+				AND (l.synced_stock IS DISTINCT FROM (CASE WHEN i.item_state THEN GREATEST(i.stock, 0) ELSE 0 END)
+					OR l.synced_price IS DISTINCT FROM i.selling_price)
+			ORDER BY l.next_attempt_at
+			LIMIT ?
+			FOR UPDATE OF l SKIP LOCKED
+		)
+		RETURNING *`, leaseUntil, now, limit).Scan(&listings).Error
+	if err != nil {
+		return nil, err
+	}
+	return listings, nil
+}
+
+// GetItems devuelve el ID, nombre, existencias, precio y estado de los ítems; con ids, solo los de
+// esos IDs.
+func (r *EcommerceRepository) GetItems(ctx context.Context, ids []int) ([]models.Item, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	query := r.DB.WithContext(ctx).Select("id", "name", "stock", "selling_price", "item_state")
+	if ids != nil {
+		query = query.Where("id IN ?", ids)
+	}
+	var items []models.Item
+	err := query.Order("id").Find(&items).Error
+	return items, err
+}
+
+func (r *EcommerceRepository) SaveListing(ctx context.Context, listing *models.EcommerceListing) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Save(listing).Error
+}
+
+func (r *EcommerceRepository) CountFailingListings(ctx context.Context) (int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var count int64
+	err := r.DB.WithContext(ctx).Model(&models.EcommerceListing{}).Where("last_error <> ''").Count(&count).Error
+	return count, err
+}
+
+// CreateOrder registra el pedido. Devuelve false si ya estaba registrado: la tienda reintenta los
+// webhooks y el mismo pedido puede llegar más de una vez.
+func (r *EcommerceRepository) CreateOrder(ctx context.Context, order *models.EcommerceOrder) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(order)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *EcommerceRepository) SaveOrder(ctx context.Context, order *models.EcommerceOrder) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Save(order).Error
+}
+
+func (r *EcommerceRepository) GetOrderByID(ctx context.Context, id int) (*models.EcommerceOrder, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var order models.EcommerceOrder
+	if err := r.DB.WithContext(ctx).First(&order, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *EcommerceRepository) GetOrderByExternalID(ctx context.Context, provider, externalID string) (*models.EcommerceOrder, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var order models.EcommerceOrder
+	if err := r.DB.WithContext(ctx).First(&order, "provider = ? AND external_id = ?", provider, externalID).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *EcommerceRepository) GetOrders(ctx context.Context, filter dtos.EcommerceOrderFilterDTO) ([]models.EcommerceOrder, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	query := r.DB.WithContext(ctx).Model(&models.EcommerceOrder{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	return paginate[models.EcommerceOrder](query.Order("id DESC"), filter.PaginationDTO)
+}
+
+// ChangeOrderStatus pasa el pedido de fromStatus a toStatus. Devuelve false si no estaba en
+// fromStatus, así dos reintentos simultáneos no lo importan dos veces.
+func (r *EcommerceRepository) ChangeOrderStatus(ctx context.Context, id int, fromStatus, toStatus string) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Model(&models.EcommerceOrder{}).
+		Where("id = ? AND status = ?", id, fromStatus).
+		Update("status", toStatus)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *EcommerceRepository) CountOrdersByStatus(ctx context.Context, status string) (int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var count int64
+	err := r.DB.WithContext(ctx).Model(&models.EcommerceOrder{}).Where("status = ?", status).Count(&count).Error
+	return count, err
+}
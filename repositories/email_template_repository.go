@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type EmailTemplateRepository struct {
+	DB *gorm.DB
+}
+
+func NewEmailTemplateRepository(db *gorm.DB) *EmailTemplateRepository {
+	return &EmailTemplateRepository{DB: db}
+}
+
+// GetActiveVersion devuelve nil sin error cuando la plantilla no tiene una versión activa.
+func (r *EmailTemplateRepository) GetActiveVersion(ctx context.Context, name string) (*models.EmailTemplateVersion, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var versions []models.EmailTemplateVersion
+	err := r.DB.WithContext(ctx).Where("name = ? AND active = ?", name, true).Limit(1).Find(&versions).Error
+	if err != nil || len(versions) == 0 {
+		return nil, err
+	}
+	return &versions[0], nil
+}
+
+func (r *EmailTemplateRepository) GetActiveVersions(ctx context.Context) ([]models.EmailTemplateVersion, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var versions []models.EmailTemplateVersion
+	err := r.DB.WithContext(ctx).Where("active = ?", true).Find(&versions).Error
+	return versions, err
+}
+
+func (r *EmailTemplateRepository) GetVersions(ctx context.Context, name string) ([]models.EmailTemplateVersion, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var versions []models.EmailTemplateVersion
+	err := r.DB.WithContext(ctx).Where("name = ?", name).Order("version DESC").Find(&versions).Error
+	return versions, err
+}
+
+func (r *EmailTemplateRepository) GetVersion(ctx context.Context, name string, version int) (*models.EmailTemplateVersion, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var templateVersion models.EmailTemplateVersion
+	if err := r.DB.WithContext(ctx).First(&templateVersion, "name = ? AND version = ?", name, version).Error; err != nil {
+		return nil, err
+	}
+	return &templateVersion, nil
+}
+
+// CreateVersion guarda la versión con el siguiente número y la deja como la única activa.
+func (r *EmailTemplateRepository) CreateVersion(ctx context.Context, templateVersion *models.EmailTemplateVersion) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var last int
+		if err := tx.Model(&models.EmailTemplateVersion{}).Where("name = ?", templateVersion.Name).
+			Select("COALESCE(MAX(version), 0)").Scan(&last).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.EmailTemplateVersion{}).Where("name = ? AND active = ?", templateVersion.Name, true).
+			Update("active", false).Error; err != nil {
+			return err
+		}
+		templateVersion.Version = last + 1
+		templateVersion.Active = true
+		return tx.Create(templateVersion).Error
+	})
+}
+
+// ActivateVersion deja activa solo la versión indicada; con version 0 ninguna queda activa y se
+// vuelve a la plantilla incluida.
+func (r *EmailTemplateRepository) ActivateVersion(ctx context.Context, name string, version int) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.EmailTemplateVersion{}).Where("name = ? AND active = ?", name, true).
+			Update("active", false).Error; err != nil {
+			return err
+		}
+		if version == 0 {
+			return nil
+		}
+		result := tx.Model(&models.EmailTemplateVersion{}).Where("name = ? AND version = ?", name, version).
+			Update("active", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
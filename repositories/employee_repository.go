@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,48 +16,49 @@ func NewEmployeeRepository(db *gorm.DB) *EmployeeRepository {
 	return &EmployeeRepository{DB: db}
 }
 
-func (r *EmployeeRepository) GetEmployeeByID(id string) (*models.Employee, error) {
+func (r *EmployeeRepository) GetEmployeeByID(ctx context.Context, id string) (*models.Employee, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var employee models.Employee
-	err := r.DB.Preload("User").Preload("IdentifierType").First(&employee, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).Preload("User").Preload("IdentifierType").First(&employee, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &employee, nil
 }
 
-func (r *EmployeeRepository) SearchEmployeesByID(query string) ([]models.Employee, error) {
-	var employees []models.Employee
-	err := r.DB.Preload("User").Preload("IdentifierType").
-		Where("CAST(id AS TEXT) LIKE ?", query+"%").
-		Find(&employees).Error
-	if err != nil {
-		return nil, err
-	}
-	return employees, nil
+func (r *EmployeeRepository) SearchEmployeesByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Employee, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Preload("User").Preload("IdentifierType").
+		Where("CAST(id AS TEXT) LIKE ?", query+"%")
+	return paginate[models.Employee](db, pagination)
 }
 
-func (r *EmployeeRepository) SearchEmployeesByName(names string) ([]models.Employee, error) {
-	var employees []models.Employee
-	err := r.DB.Preload("User").Preload("IdentifierType").
-		Where("LOWER(names) LIKE LOWER(?)", names+"%").
-		Find(&employees).Error
-	if err != nil {
-		return nil, err
-	}
-	return employees, nil
+func (r *EmployeeRepository) SearchEmployeesByName(ctx context.Context, names string, pagination dtos.PaginationDTO) ([]models.Employee, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Preload("User").Preload("IdentifierType").
+		Where(unaccentPrefix("names"), names+"%")
+	return paginate[models.Employee](db, pagination)
 }
 
-func (r *EmployeeRepository) GetAllEmployees() ([]models.Employee, error) {
-	var employees []models.Employee
-	err := r.DB.Preload("User").Preload("IdentifierType").Find(&employees).Error
-	if err != nil {
-		return nil, err
-	}
-	return employees, nil
+func (r *EmployeeRepository) GetAllEmployees(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Employee, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Preload("User").Preload("IdentifierType")
+	return paginate[models.Employee](db, pagination)
 }
 
-func (r *EmployeeRepository) UpdateEmployee(employee *models.Employee) error {
-	return r.DB.Model(&models.Employee{}).
+func (r *EmployeeRepository) UpdateEmployee(ctx context.Context, employee *models.Employee) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Model(&models.Employee{}).
 		Where("id = ?", employee.ID).
 		Updates(map[string]interface{}{
 			"names":              employee.Names,
@@ -68,8 +71,11 @@ func (r *EmployeeRepository) UpdateEmployee(employee *models.Employee) error {
 		}).Error
 }
 
-func (r *EmployeeRepository) CreateEmployee(employee *models.Employee) (*models.Employee, error) {
-	if err := r.DB.Create(employee).Error; err != nil {
+func (r *EmployeeRepository) CreateEmployee(ctx context.Context, employee *models.Employee) (*models.Employee, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	if err := r.DB.WithContext(ctx).Create(employee).Error; err != nil {
 		return nil, err
 	}
 	return employee, nil
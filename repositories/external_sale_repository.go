@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,13 +16,17 @@ func NewExternalSaleRepository(db *gorm.DB) *ExternalSaleRepository {
 	return &ExternalSaleRepository{DB: db}
 }
 
-func (r *ExternalSaleRepository) GetExternalSaleByID(id string) (*models.ExternalSale, error) {
+func (r *ExternalSaleRepository) GetExternalSaleByID(ctx context.Context, id string) (*models.ExternalSale, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var externalSale models.ExternalSale
-	err := r.DB.
+	err := r.DB.WithContext(ctx).
 		Preload("Item").
 		Preload("Item.ItemType").
 		Preload("Item.AdditionalExpenses").
-		Preload("Customer").
+		Preload("Item.Images", orderItemImages).
+		Preload("Customer", withDeletedCustomers).
 		First(&externalSale, "id = ?", id).Error
 
 	if err != nil {
@@ -30,26 +36,48 @@ func (r *ExternalSaleRepository) GetExternalSaleByID(id string) (*models.Externa
 	return &externalSale, nil
 }
 
-func (r *ExternalSaleRepository) GetAllExternalSales() ([]models.ExternalSale, error) {
-	var externalSales []models.ExternalSale
-	err := r.DB.
-		Preload("Item").
+func (r *ExternalSaleRepository) GetAllExternalSales(ctx context.Context, pagination dtos.PaginationDTO) ([]models.ExternalSale, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Preload("Item").
 		Preload("Item.ItemType").
 		Preload("Item.AdditionalExpenses").
-		Preload("Customer").
-		Find(&externalSales).Error
-
-	if err != nil {
-		return nil, err
-	}
-
-	return externalSales, nil
+		Preload("Item.Images", orderItemImages).
+		Preload("Customer", withDeletedCustomers)
+	return paginate[models.ExternalSale](db, pagination)
 }
 
-func (r *ExternalSaleRepository) CreateExternalSale(externalSale *models.ExternalSale) error {
+// CreateExternalSale registra la venta y descuenta su stock en la misma transacción, con el mismo
+// UPDATE condicionado que las facturas. Si el item no alcanza no se crea nada y se devuelve el faltante.
+func (r *ExternalSaleRepository) CreateExternalSale(ctx context.Context, externalSale *models.ExternalSale, movement models.StockMovement) ([]dtos.StockShortageDTO, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
 
-	if err := r.DB.Create(externalSale).Error; err != nil {
-		return err
-	}
-	return nil
+	var shortages []dtos.StockShortageDTO
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Item{}).
+			Where("id = ? AND stock >= ?", externalSale.ItemID, externalSale.Stock).
+			UpdateColumns(map[string]interface{}{"stock": gorm.Expr("stock - ?", externalSale.Stock), "version": nextVersion})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			var available int
+			if err := tx.Model(&models.Item{}).Select("stock").Where("id = ?", externalSale.ItemID).Scan(&available).Error; err != nil {
+				return err
+			}
+			shortages = append(shortages, dtos.StockShortageDTO{ItemID: externalSale.ItemID, Requested: externalSale.Stock, Available: available})
+			return nil
+		}
+
+		if err := tx.Create(externalSale).Error; err != nil {
+			return err
+		}
+		movement.ItemID = externalSale.ItemID
+		movement.Delta = -externalSale.Stock
+		movement.ReferenceID = &externalSale.ID
+		return recordStockMovement(tx, movement)
+	})
+	return shortages, err
 }
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GatewayPaymentRepository struct {
+	DB *gorm.DB
+}
+
+func NewGatewayPaymentRepository(db *gorm.DB) *GatewayPaymentRepository {
+	return &GatewayPaymentRepository{DB: db}
+}
+
+// CreateGatewayPayment registra el pago notificado. Devuelve false si ya estaba registrado: la
+// pasarela reintenta las notificaciones y la misma transacción puede llegar más de una vez.
+func (r *GatewayPaymentRepository) CreateGatewayPayment(ctx context.Context, payment *models.GatewayPayment) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(payment)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ClaimGatewayPayment pasa a processing el pago que quedó en uno de retryStatuses, o en processing
+// desde antes de staleBefore (la petición que lo procesaba no terminó), y lo devuelve; nil si otra
+// petición lo está procesando o ya se registró.
+func (r *GatewayPaymentRepository) ClaimGatewayPayment(ctx context.Context, provider, externalID, processing string, retryStatuses []string, staleBefore time.Time) (*models.GatewayPayment, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var payments []models.GatewayPayment
+	err := r.DB.WithContext(ctx).Raw(`
+		UPDATE gateway_payments SET status = ?, updated_at = ?
+		WHERE provider = ? AND external_id = ?
+			AND (status IN ? OR (status = ? AND updated_at < ?))
+		RETURNING *`, processing, time.Now(), provider, externalID, retryStatuses, processing, staleBefore).Scan(&payments).Error
+	if err != nil || len(payments) == 0 {
+		return nil, err
+	}
+	return &payments[0], nil
+}
+
+func (r *GatewayPaymentRepository) SaveGatewayPayment(ctx context.Context, payment *models.GatewayPayment) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Save(payment).Error
+}
+
+func (r *GatewayPaymentRepository) GetGatewayPaymentByExternalID(ctx context.Context, provider, externalID string) (*models.GatewayPayment, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var payment models.GatewayPayment
+	if err := r.DB.WithContext(ctx).First(&payment, "provider = ? AND external_id = ?", provider, externalID).Error; err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,12 +15,22 @@ func NewHistoricalItemPriceRepository(db *gorm.DB) *HistoricalItemPriceRepositor
 	return &HistoricalItemPriceRepository{DB: db}
 }
 
-func (r *HistoricalItemPriceRepository) CreateHistoricalItemPrice(price *models.HistoricalItemPrice) error {
-	return r.DB.Create(price).Error
+func (r *HistoricalItemPriceRepository) WithTx(tx Tx) HistoricalItemPriceRepositoryInterface {
+	return &HistoricalItemPriceRepository{DB: tx.Conn()}
 }
 
-func (r *HistoricalItemPriceRepository) GetHistoricalItemPrice(itemID string) ([]models.HistoricalItemPrice, error) {
+func (r *HistoricalItemPriceRepository) CreateHistoricalItemPrice(ctx context.Context, price *models.HistoricalItemPrice) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Create(price).Error
+}
+
+func (r *HistoricalItemPriceRepository) GetHistoricalItemPrice(ctx context.Context, itemID string) ([]models.HistoricalItemPrice, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var historicalPrices []models.HistoricalItemPrice
-	err := r.DB.Where("item_id = ?", itemID).Order("added_at DESC").Find(&historicalPrices).Error
+	err := r.DB.WithContext(ctx).Where("item_id = ?", itemID).Order("added_at DESC").Find(&historicalPrices).Error
 	return historicalPrices, err
 }
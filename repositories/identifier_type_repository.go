@@ -1,6 +1,9 @@
 package repositories
 
 import (
+	"context"
+	"database/sql"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,20 +17,77 @@ func NewIdentifierTypeRepository(db *gorm.DB) *IdentifierTypeRepository {
 	return &IdentifierTypeRepository{DB: db}
 }
 
-func (r *IdentifierTypeRepository) GetAllIdentifierTypes() ([]models.IdentifierType, error) {
-	var IdentifierTypes []models.IdentifierType
-	err := r.DB.Find(&IdentifierTypes).Error
-	if err != nil {
-		return nil, err
-	}
-	return IdentifierTypes, nil
+func (r *IdentifierTypeRepository) GetAllIdentifierTypes(ctx context.Context, pagination dtos.PaginationDTO) ([]models.IdentifierType, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return paginate[models.IdentifierType](r.DB.WithContext(ctx), pagination)
 }
 
-func (r *IdentifierTypeRepository) GetIdentifierTypeByID(id string) (*models.IdentifierType, error) {
+func (r *IdentifierTypeRepository) GetIdentifierTypeByID(ctx context.Context, id string) (*models.IdentifierType, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var IdentifierType models.IdentifierType
-	err := r.DB.First(&IdentifierType, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).First(&IdentifierType, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &IdentifierType, nil
 }
+
+// GetIdentifierTypeByName compara sin distinguir mayúsculas; devuelve nil si no existe.
+func (r *IdentifierTypeRepository) GetIdentifierTypeByName(ctx context.Context, name string) (*models.IdentifierType, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var identifierTypes []models.IdentifierType
+	err := r.DB.WithContext(ctx).Where("LOWER(name) = LOWER(?)", name).Limit(1).Find(&identifierTypes).Error
+	if err != nil || len(identifierTypes) == 0 {
+		return nil, err
+	}
+	return &identifierTypes[0], nil
+}
+
+func (r *IdentifierTypeRepository) CreateIdentifierType(ctx context.Context, identifierType *models.IdentifierType) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Create(identifierType).Error
+}
+
+func (r *IdentifierTypeRepository) UpdateIdentifierType(ctx context.Context, identifierType *models.IdentifierType) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Save(identifierType).Error
+}
+
+// CountIdentifierTypeUsage cuenta los clientes, empleados y citas que usan el tipo de identificación.
+func (r *IdentifierTypeRepository) CountIdentifierTypeUsage(ctx context.Context, id int) (int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var count int64
+	err := r.DB.WithContext(ctx).Raw(`
+		SELECT
+			(SELECT COUNT(*) FROM customers WHERE identifier_type_id = @id) +
+			(SELECT COUNT(*) FROM employees WHERE identifier_type_id = @id) +
+			(SELECT COUNT(*) FROM appointments WHERE identifier_type_id = @id)`,
+		sql.Named("id", id)).Scan(&count).Error
+	return count, err
+}
+
+func (r *IdentifierTypeRepository) DeleteIdentifierType(ctx context.Context, id int) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Delete(&models.IdentifierType{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
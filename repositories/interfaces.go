@@ -0,0 +1,584 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/models"
+)
+
+//go:generate go run ../tools/mockgen -source interfaces.go,transactor.go -out mocks/mocks.go
+
+// Los servicios dependen de estas interfaces y no de los structs concretos, así se pueden probar
+// con los mocks de repositories/mocks sin una base de datos. Al agregar un método público a un
+// repositorio hay que agregarlo aquí y regenerar los mocks con "go generate ./repositories".
+
+type AccountingSyncRepositoryInterface interface {
+	CreateSync(ctx context.Context, sync *models.AccountingSync) (bool, error)
+	ClaimDueSyncs(ctx context.Context, status string, now, leaseUntil time.Time, limit int) ([]models.AccountingSync, error)
+	SaveSync(ctx context.Context, sync *models.AccountingSync) error
+	GetSyncByID(ctx context.Context, id int) (*models.AccountingSync, error)
+	GetSyncByDocument(ctx context.Context, documentType string, documentID int) (*models.AccountingSync, error)
+	GetSyncs(ctx context.Context, filter dtos.AccountingSyncFilterDTO) ([]models.AccountingSync, int64, error)
+	DeleteUnsentSync(ctx context.Context, documentType string, documentID int, sentStatus string) (bool, error)
+	RequeueSync(ctx context.Context, id int, fromStatus, toStatus string, now time.Time) (bool, error)
+}
+
+type AdditionalExpenseRepositoryInterface interface {
+	GetAllAdditionalExpenses(ctx context.Context, pagination dtos.PaginationDTO) ([]models.AdditionalExpense, int64, error)
+	GetAdditionalExpenseByID(ctx context.Context, id string) (*models.AdditionalExpense, error)
+	CreateAdditionalExpense(ctx context.Context, expense *models.AdditionalExpense) (*models.AdditionalExpense, error)
+	DeleteAdditionalExpense(ctx context.Context, id string) error
+	UpdateAdditionalExpense(ctx context.Context, expense *models.AdditionalExpense) (*models.AdditionalExpense, error)
+}
+
+type AppointmentReminderRepositoryInterface interface {
+	GetAppointmentsDueForReminder(ctx context.Context, hoursBefore int, from, to time.Time) ([]models.Appointment, error)
+	CreateReminder(ctx context.Context, reminder *models.AppointmentReminder) error
+	GetRemindersByAppointment(ctx context.Context, appointmentID int) ([]models.AppointmentReminder, error)
+}
+
+type AppointmentReportRepositoryInterface interface {
+	GetAppointmentCountsByPeriod(ctx context.Context, startDate, endDate time.Time, groupBy string) ([]dtos.AppointmentPeriodDTO, error)
+	GetAppointmentCountsByHour(ctx context.Context, startDate, endDate time.Time) ([]dtos.AppointmentHourDTO, error)
+}
+
+type AppointmentRepositoryInterface interface {
+	WithTx(tx Tx) AppointmentRepositoryInterface
+	GetAppointmentByID(ctx context.Context, id int) (*models.Appointment, error)
+	GetAllAppointments(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Appointment, int64, error)
+	SearchAppointmentsByState(ctx context.Context, stateID int, pagination dtos.PaginationDTO) ([]models.Appointment, int64, error)
+	GetAppointmentsByCustomerID(ctx context.Context, customerID int, pagination dtos.PaginationDTO) ([]models.Appointment, int64, error)
+	CreateAppointment(ctx context.Context, appointment *models.Appointment) (*models.Appointment, error)
+	GetUnlinkedAppointments(ctx context.Context, afterID, limit int) ([]models.Appointment, error)
+	SetAppointmentCustomer(ctx context.Context, id, customerID int) error
+	UpdateAppointment(ctx context.Context, appointment *models.Appointment) (bool, error)
+	SearchAppointmentsByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Appointment, int64, error)
+	SearchAppointmentsByCustomerID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Appointment, int64, error)
+	GetAppointmentByCustomerIDAndDate(ctx context.Context, customerID int, dateTime time.Time) (*models.Appointment, error)
+	LockSlot(ctx context.Context, dateTime time.Time) error
+	CountAppointmentsAtDateTime(ctx context.Context, dateTime time.Time, excludeID int) (int64, error)
+	CountEmployeeAppointmentsAtDateTime(ctx context.Context, employeeID int, dateTime time.Time, excludeID int) (int64, error)
+	GetAppointmentsByEmployeeID(ctx context.Context, employeeID int, from, to *time.Time, pagination dtos.PaginationDTO) ([]models.Appointment, int64, error)
+	DeleteAppointmentByID(ctx context.Context, id int) error
+	CountAppointmentsByHourOnDate(ctx context.Context, date time.Time, openingHour, lastSlotHour int, unassigned bool) ([]int, error)
+	GetAppointmentStateTypes(ctx context.Context) ([]models.AppointmentStateType, error)
+	ChangeAppointmentState(ctx context.Context, change *models.AppointmentStateChange) (bool, error)
+	GetAppointmentStateHistory(ctx context.Context, appointmentID int) ([]models.AppointmentStateChange, error)
+}
+
+type ArchiveRepositoryInterface interface {
+	GetArchivableInvoices(ctx context.Context, before time.Time, afterID, limit int) ([]models.Invoice, error)
+	GetArchivableAppointments(ctx context.Context, before time.Time, afterID, limit int) ([]models.Appointment, error)
+	ArchiveInvoice(ctx context.Context, record *models.ArchivedInvoice, version int) (bool, error)
+	ArchiveAppointment(ctx context.Context, record *models.ArchivedAppointment, version int) (bool, error)
+	SearchArchivedInvoices(ctx context.Context, filter dtos.ArchiveFilterDTO) ([]models.ArchivedInvoice, int64, error)
+	SearchArchivedAppointments(ctx context.Context, filter dtos.ArchiveFilterDTO) ([]models.ArchivedAppointment, int64, error)
+	GetArchivedInvoiceByID(ctx context.Context, id int) (*models.ArchivedInvoice, error)
+	GetArchivedAppointmentByID(ctx context.Context, id int) (*models.ArchivedAppointment, error)
+}
+
+type AuditRepositoryInterface interface {
+	CreateAuditEntry(ctx context.Context, entry *models.AuditEntry) error
+	GetAuditEntries(ctx context.Context, entity, entityID string) ([]models.AuditEntry, error)
+}
+
+type AuthorizationRepositoryInterface interface {
+	UserHasPermission(ctx context.Context, email string, permissionID int) (bool, error)
+	GetUserIDsWithPermission(ctx context.Context, permissionID int) ([]int, error)
+}
+
+type BusinessHoursRepositoryInterface interface {
+	GetBusinessHours(ctx context.Context) ([]models.BusinessHours, error)
+	GetBusinessHoursForWeekday(ctx context.Context, weekday int) (*models.BusinessHours, error)
+	SaveBusinessHours(ctx context.Context, hours []models.BusinessHours) error
+}
+
+type CommentRepositoryInterface interface {
+	GetCommentByID(ctx context.Context, id int) (*models.Comment, error)
+	GetAllComments(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Comment, int64, error)
+	SearchCommentsByEmail(ctx context.Context, email string, pagination dtos.PaginationDTO) ([]models.Comment, int64, error)
+	CreateComment(ctx context.Context, comment *models.Comment) (*models.Comment, error)
+	UpdateComment(ctx context.Context, comment *models.Comment) error
+	SearchCommentsByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Comment, int64, error)
+	SearchCommentsByName(ctx context.Context, name string, pagination dtos.PaginationDTO) ([]models.Comment, int64, error)
+}
+
+type CreditNoteRepositoryInterface interface {
+	GetCreditNoteByID(ctx context.Context, id int) (*models.CreditNote, error)
+	GetCreditNotes(ctx context.Context, invoiceID *int, pagination dtos.PaginationDTO) ([]models.CreditNote, int64, error)
+	CancelInvoice(ctx context.Context, invoiceID int, note *models.CreditNote, movement models.StockMovement, at time.Time) (bool, error)
+}
+
+type CurrencyRepositoryInterface interface {
+	GetCurrencies(ctx context.Context) ([]models.Currency, error)
+	GetCurrencyByCode(ctx context.Context, code string) (*models.Currency, error)
+	CreateCurrency(ctx context.Context, currency *models.Currency) error
+	UpdateCurrency(ctx context.Context, currency *models.Currency) error
+}
+
+type CustomerRepositoryInterface interface {
+	WithTx(tx Tx) CustomerRepositoryInterface
+	GetCustomerByID(ctx context.Context, id int) (*models.Customer, error)
+	GetCustomerByCustomerID(ctx context.Context, customerID string) (*models.Customer, error)
+	GetAllCustomers(ctx context.Context, includeDeleted bool, pagination dtos.PaginationDTO) ([]models.Customer, int64, error)
+	GetCustomerByEmail(ctx context.Context, email string) (*models.Customer, error)
+	FindCustomerByEmail(ctx context.Context, email string) (*models.Customer, error)
+	CreateCustomer(ctx context.Context, customer *models.Customer) (*models.Customer, error)
+	UpdateCustomer(ctx context.Context, customer *models.Customer) (bool, error)
+	GetCustomerDependencies(ctx context.Context, id int) (*dtos.CustomerDependenciesDTO, error)
+	ReassignCustomerRecords(ctx context.Context, fromID, toID int) (*dtos.CustomerReassignmentDTO, error)
+	DeleteCustomer(ctx context.Context, id int) error
+	GetDeletedCustomerByID(ctx context.Context, id int) (*models.Customer, error)
+	RestoreCustomer(ctx context.Context, id int) (bool, error)
+	StreamCustomers(ctx context.Context, filter dtos.CSVExportFilterDTO, fn func(models.Customer) error) error
+	FindCustomerDuplicateCandidates(ctx context.Context, customerID, email string, phones []string) ([]models.Customer, error)
+	GetCustomersToGeocode(ctx context.Context, limit int) ([]models.Customer, error)
+	SetCustomerLocation(ctx context.Context, customer *models.Customer, previousAddress string) (bool, error)
+	SearchCustomers(ctx context.Context, search dtos.CustomerSearchDTO) ([]models.Customer, int64, error)
+}
+
+type DailyCloseRepositoryInterface interface {
+	GetDailyCloseByDate(ctx context.Context, date time.Time) (*models.DailyClose, error)
+	CreateDailyClose(ctx context.Context, dailyClose *models.DailyClose) error
+}
+
+type DashboardRepositoryInterface interface {
+	GetSalesBetween(ctx context.Context, start, end time.Time) (dtos.DashboardSalesDTO, error)
+	CountAppointmentsBetween(ctx context.Context, start, end time.Time) (int64, error)
+	CountLowStockItems(ctx context.Context) (int64, error)
+	CountPendingComments(ctx context.Context) (int64, error)
+}
+
+type DataExportRepositoryInterface interface {
+	CreateExport(ctx context.Context, export *models.DataExport) error
+	ClaimExport(ctx context.Context, fromStatus, toStatus string, now time.Time) (*models.DataExport, error)
+	SaveExport(ctx context.Context, export *models.DataExport) error
+	GetExportByID(ctx context.Context, id int) (*models.DataExport, error)
+	GetExports(ctx context.Context, pagination dtos.PaginationDTO) ([]models.DataExport, int64, error)
+	GetExportFilesBefore(ctx context.Context, before time.Time) ([]models.DataExport, error)
+	StreamTable(ctx context.Context, table string, fn func(row map[string]interface{}) error) error
+}
+
+type DiscountTypeRepositoryInterface interface {
+	GetAllDiscountTypes(ctx context.Context, pagination dtos.PaginationDTO) ([]models.DiscountType, int64, error)
+	GetDiscountTypeByID(ctx context.Context, id string) (*models.DiscountType, error)
+	CreateDiscountType(ctx context.Context, discount *models.DiscountType) error
+	CreateDiscountTypes(ctx context.Context, discountTypes []models.DiscountType) error
+	UpdateDiscountType(ctx context.Context, discount *models.DiscountType) error
+	DeactivateDiscountType(ctx context.Context, id string) error
+}
+
+type EcommerceRepositoryInterface interface {
+	UpsertListings(ctx context.Context, listings []models.EcommerceListing) error
+	DeleteListingsExcept(ctx context.Context, itemIDs []int) (int64, error)
+	ClaimOutdatedListings(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.EcommerceListing, error)
+	GetItems(ctx context.Context, ids []int) ([]models.Item, error)
+	SaveListing(ctx context.Context, listing *models.EcommerceListing) error
+	CountFailingListings(ctx context.Context) (int64, error)
+	CreateOrder(ctx context.Context, order *models.EcommerceOrder) (bool, error)
+	SaveOrder(ctx context.Context, order *models.EcommerceOrder) error
+	GetOrderByID(ctx context.Context, id int) (*models.EcommerceOrder, error)
+	GetOrderByExternalID(ctx context.Context, provider, externalID string) (*models.EcommerceOrder, error)
+	GetOrders(ctx context.Context, filter dtos.EcommerceOrderFilterDTO) ([]models.EcommerceOrder, int64, error)
+	ChangeOrderStatus(ctx context.Context, id int, fromStatus, toStatus string) (bool, error)
+	CountOrdersByStatus(ctx context.Context, status string) (int64, error)
+}
+
+type EmailMessageRepositoryInterface interface {
+	CreateMessage(ctx context.Context, message *models.EmailMessage) error
+	ClaimDueMessages(ctx context.Context, status string, now, leaseUntil time.Time, limit int) ([]models.EmailMessage, error)
+	SaveAttempt(ctx context.Context, message *models.EmailMessage, delivery *models.NotificationDelivery) error
+	RequeueMessage(ctx context.Context, id int, fromStatus, toStatus string, now time.Time) (bool, error)
+}
+
+type EmailTemplateRepositoryInterface interface {
+	GetActiveVersion(ctx context.Context, name string) (*models.EmailTemplateVersion, error)
+	GetActiveVersions(ctx context.Context) ([]models.EmailTemplateVersion, error)
+	GetVersions(ctx context.Context, name string) ([]models.EmailTemplateVersion, error)
+	GetVersion(ctx context.Context, name string, version int) (*models.EmailTemplateVersion, error)
+	CreateVersion(ctx context.Context, templateVersion *models.EmailTemplateVersion) error
+	ActivateVersion(ctx context.Context, name string, version int) error
+}
+
+type EmployeeRepositoryInterface interface {
+	GetEmployeeByID(ctx context.Context, id string) (*models.Employee, error)
+	SearchEmployeesByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Employee, int64, error)
+	SearchEmployeesByName(ctx context.Context, names string, pagination dtos.PaginationDTO) ([]models.Employee, int64, error)
+	GetAllEmployees(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Employee, int64, error)
+	UpdateEmployee(ctx context.Context, employee *models.Employee) error
+	CreateEmployee(ctx context.Context, employee *models.Employee) (*models.Employee, error)
+}
+
+type ExternalSaleRepositoryInterface interface {
+	GetExternalSaleByID(ctx context.Context, id string) (*models.ExternalSale, error)
+	GetAllExternalSales(ctx context.Context, pagination dtos.PaginationDTO) ([]models.ExternalSale, int64, error)
+	CreateExternalSale(ctx context.Context, externalSale *models.ExternalSale, movement models.StockMovement) ([]dtos.StockShortageDTO, error)
+}
+
+type GatewayPaymentRepositoryInterface interface {
+	CreateGatewayPayment(ctx context.Context, payment *models.GatewayPayment) (bool, error)
+	ClaimGatewayPayment(ctx context.Context, provider, externalID, processing string, retryStatuses []string, staleBefore time.Time) (*models.GatewayPayment, error)
+	SaveGatewayPayment(ctx context.Context, payment *models.GatewayPayment) error
+	GetGatewayPaymentByExternalID(ctx context.Context, provider, externalID string) (*models.GatewayPayment, error)
+}
+
+type HistoricalItemPriceRepositoryInterface interface {
+	WithTx(tx Tx) HistoricalItemPriceRepositoryInterface
+	CreateHistoricalItemPrice(ctx context.Context, price *models.HistoricalItemPrice) error
+	GetHistoricalItemPrice(ctx context.Context, itemID string) ([]models.HistoricalItemPrice, error)
+}
+
+type IdentifierTypeRepositoryInterface interface {
+	GetAllIdentifierTypes(ctx context.Context, pagination dtos.PaginationDTO) ([]models.IdentifierType, int64, error)
+	GetIdentifierTypeByID(ctx context.Context, id string) (*models.IdentifierType, error)
+	GetIdentifierTypeByName(ctx context.Context, name string) (*models.IdentifierType, error)
+	CreateIdentifierType(ctx context.Context, identifierType *models.IdentifierType) error
+	UpdateIdentifierType(ctx context.Context, identifierType *models.IdentifierType) error
+	CountIdentifierTypeUsage(ctx context.Context, id int) (int64, error)
+	DeleteIdentifierType(ctx context.Context, id int) error
+}
+
+type InventoryReportRepositoryInterface interface {
+	GetItemSalesBetween(ctx context.Context, startDate, endDate time.Time) ([]ItemSalesRow, error)
+}
+
+type InvoiceReminderRepositoryInterface interface {
+	GetInvoicesDueForReminder(ctx context.Context, daysFromDue int, from, to time.Time) ([]models.Invoice, error)
+	CreateReminder(ctx context.Context, reminder *models.InvoiceReminder) error
+	GetRemindersByInvoice(ctx context.Context, invoiceID int) ([]models.InvoiceReminder, error)
+}
+
+type InvoiceRepositoryInterface interface {
+	Primary() InvoiceRepositoryInterface
+	GetInvoiceByID(ctx context.Context, id string) (*models.Invoice, error)
+	GetAllInvoices(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Invoice, int64, error)
+	GetInvoicesByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.Invoice, error)
+	StreamInvoicesByDateRange(ctx context.Context, startDate, endDate time.Time, fn func(models.Invoice) error) error
+	StreamInvoices(ctx context.Context, filter dtos.CSVExportFilterDTO, fn func(models.Invoice) error) error
+	SearchInvoiceByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Invoice, int64, error)
+	SearchInvoiceByCustomerPersonalId(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Invoice, int64, error)
+	CreateInvoice(ctx context.Context, dto *dtos.CreateInvoiceDTO, subtotal float64, total float64, movement models.StockMovement) (*models.Invoice, []dtos.StockShortageDTO, error)
+	CreateInvoiceWithoutStockReduction(ctx context.Context, dto *dtos.CreateInvoiceDTO, subtotal float64, total float64) (*models.Invoice, error)
+	SetInvoicePaidAt(ctx context.Context, id, version int, paidAt *time.Time) (*models.Invoice, bool, error)
+	GetSalesSummaryByPeriod(ctx context.Context, startDate, endDate time.Time, groupBy string) ([]dtos.SalesSummaryPeriodDTO, error)
+	GetVoidTotals(ctx context.Context, startDate, endDate time.Time) (dtos.VoidTotalsDTO, error)
+	GetPaymentTotalsByMethod(ctx context.Context, startDate, endDate time.Time) ([]dtos.PaymentMethodTotalDTO, error)
+	GetInvoiceLineCosts(ctx context.Context, startDate, endDate time.Time) ([]InvoiceLineCost, error)
+	GetDiscountUsage(ctx context.Context, startDate, endDate time.Time) ([]dtos.DiscountUsageDTO, error)
+	GetTopItems(ctx context.Context, startDate, endDate time.Time, orderBy string, limit int) ([]dtos.TopItemDTO, error)
+	GetRevenueByCustomer(ctx context.Context, startDate, endDate time.Time, limit int) ([]dtos.CustomerRevenueDTO, error)
+	RecordPayment(ctx context.Context, payment *models.Payment) (*models.Invoice, bool, error)
+	GetInvoicePayments(ctx context.Context, invoiceID int) ([]models.Payment, error)
+}
+
+type InvoiceReturnRepositoryInterface interface {
+	GetInvoiceReturns(ctx context.Context, invoiceID int) ([]models.InvoiceReturn, error)
+	GetInvoiceReturnByID(ctx context.Context, id int) (*models.InvoiceReturn, error)
+	CreateReturn(ctx context.Context, invoiceID int, ret *models.InvoiceReturn, movement models.StockMovement,
+		at time.Time) ([]dtos.ReturnExcessDTO, bool, error)
+}
+
+type ItemImageRepositoryInterface interface {
+	GetItemImage(ctx context.Context, itemID int, imageID int) (*models.ItemImage, error)
+	CountItemImages(ctx context.Context, itemID int) (int64, error)
+	CreateItemImage(ctx context.Context, image *models.ItemImage) error
+	DeleteItemImage(ctx context.Context, image *models.ItemImage) error
+}
+
+type ItemRepositoryInterface interface {
+	WithTx(tx Tx) ItemRepositoryInterface
+	GetItemByID(ctx context.Context, id string) (*models.Item, error)
+	HasEnoughStock(ctx context.Context, id string, quantity int) (bool, error)
+	GetAllItems(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Item, int64, error)
+	SearchItems(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Item, int64, error)
+	GetItemBySKU(ctx context.Context, sku string) (*models.Item, error)
+	GetItemByBarcode(ctx context.Context, barcode string) (*models.Item, error)
+	GetLowStockItems(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Item, int64, error)
+	StreamItems(ctx context.Context, filter dtos.CSVExportFilterDTO, fn func(models.Item) error) error
+	GetCatalogItems(ctx context.Context, filter dtos.PublicCatalogFilterDTO) ([]models.Item, int64, error)
+	UpdateItem(ctx context.Context, item *models.Item, movement models.StockMovement) (bool, error)
+	CreateItem(ctx context.Context, item *models.Item, movement models.StockMovement) (*models.Item, error)
+}
+
+type ItemTypeRepositoryInterface interface {
+	GetAllItemTypes(ctx context.Context, pagination dtos.PaginationDTO) ([]models.ItemType, int64, error)
+	GetItemTypeByID(ctx context.Context, id string) (*models.ItemType, error)
+	GetItemTypeByName(ctx context.Context, name string) (*models.ItemType, error)
+	CreateItemType(ctx context.Context, itemType *models.ItemType) error
+	UpdateItemType(ctx context.Context, itemType *models.ItemType) error
+	CountItemsWithType(ctx context.Context, id int) (int64, error)
+	DeleteItemType(ctx context.Context, id int) error
+}
+
+type LowStockAlertRepositoryInterface interface {
+	ClearRecoveredAlerts(ctx context.Context) (int64, error)
+	GetItemsPendingAlert(ctx context.Context) ([]models.Item, error)
+	CreateAlerts(ctx context.Context, alerts []models.LowStockAlert) error
+}
+
+type NotificationDeliveryRepositoryInterface interface {
+	SearchDeliveries(ctx context.Context, filter dtos.NotificationDeliveryFilterDTO) ([]models.NotificationDelivery, int64, error)
+	GetDeliveryByID(ctx context.Context, id int) (*models.NotificationDelivery, error)
+}
+
+type NotificationPreferenceRepositoryInterface interface {
+	GetPreferences(ctx context.Context, recipientType string, recipientID int) ([]models.NotificationPreference, error)
+	GetPreference(ctx context.Context, recipientType string, recipientID int, eventType, channel string) (*models.NotificationPreference, error)
+	GetPreferencesForEvent(ctx context.Context, recipientType string, recipientIDs []int, eventType, channel string) ([]models.NotificationPreference, error)
+	SavePreferences(ctx context.Context, preferences []models.NotificationPreference) error
+}
+
+type NotificationRepositoryInterface interface {
+	CreateNotifications(ctx context.Context, notifications []models.Notification) error
+	GetNotificationsByUser(ctx context.Context, userID int, unreadOnly bool, pagination dtos.PaginationDTO) ([]models.Notification, int64, error)
+	CountUnread(ctx context.Context, userID int) (int64, error)
+	MarkAsRead(ctx context.Context, userID, id int, readAt time.Time) error
+	MarkAllAsRead(ctx context.Context, userID int, readAt time.Time) (int64, error)
+	DeleteReadBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type OrderStateTypeRepositoryInterface interface {
+	GetOrderStateTypeByID(ctx context.Context, id string) (*models.OrderStateType, error)
+	GetAllOrderStateTypes(ctx context.Context, pagination dtos.PaginationDTO) ([]models.OrderStateType, int64, error)
+}
+
+type PermissionRepositoryInterface interface {
+	GetPermissionByID(ctx context.Context, id uint) (*models.Permission, error)
+	SearchPermissionsByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Permission, int64, error)
+	SearchPermissionsByName(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Permission, int64, error)
+	GetAllPermissions(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Permission, int64, error)
+}
+
+type PriceListRepositoryInterface interface {
+	GetPriceLists(ctx context.Context, pagination dtos.PaginationDTO) ([]models.PriceList, int64, error)
+	GetPriceListByID(ctx context.Context, id int) (*models.PriceList, error)
+	GetPriceListByName(ctx context.Context, name string) (*models.PriceList, error)
+	CreatePriceList(ctx context.Context, priceList *models.PriceList) error
+	ReplacePriceList(ctx context.Context, priceList *models.PriceList) error
+	DeletePriceList(ctx context.Context, id int) error
+	SetCustomerPriceList(ctx context.Context, customerID int, priceListID *int) error
+	GetCustomerPrices(ctx context.Context, customerID int) (map[int]float64, error)
+}
+
+type PurchaseOrderRepositoryInterface interface {
+	GetPurchaseOrderByID(ctx context.Context, id string) (*models.PurchaseOrder, error)
+	GetPurchaseOrdersByStateID(ctx context.Context, stateID string, pagination dtos.PaginationDTO) ([]models.PurchaseOrder, int64, error)
+	GetPurchaseOrdersByCustomerID(ctx context.Context, customerID string, pagination dtos.PaginationDTO) ([]models.PurchaseOrder, int64, error)
+	GetPurchaseOrdersBySellerID(ctx context.Context, sellerID string, pagination dtos.PaginationDTO) ([]models.PurchaseOrder, int64, error)
+	GetAllPurchaseOrders(ctx context.Context, pagination dtos.PaginationDTO) ([]models.PurchaseOrder, int64, error)
+	SearchPurchaseOrdersByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.PurchaseOrder, int64, error)
+	UpdatePurchaseOrder(ctx context.Context, purchaseOrder *models.PurchaseOrder) error
+	CreatePurchaseOrder(ctx context.Context, dto *dtos.CreatePurchaseOrderDTO, subtotal float64, total float64) (*models.PurchaseOrder, error)
+	ChangePurchaseOrderState(ctx context.Context, id string, state string) (*models.PurchaseOrder, error)
+	ChangePurchaseOrderStateWithStock(ctx context.Context, id, fromStateID, toStateID int, subtract bool, movement models.StockMovement) (bool, []dtos.StockShortageDTO, error)
+}
+
+type QuotationRepositoryInterface interface {
+	GetQuotationByID(ctx context.Context, id int) (*models.Quotation, error)
+	GetQuotations(ctx context.Context, state string, customerID *int, pagination dtos.PaginationDTO) ([]models.Quotation, int64, error)
+	CreateQuotation(ctx context.Context, quotation *models.Quotation) error
+	ReplaceQuotation(ctx context.Context, quotation *models.Quotation, fromState string) (bool, error)
+	SetQuotationState(ctx context.Context, id int, fromStates []string, state string) (bool, error)
+	DeleteQuotation(ctx context.Context, id int, states []string) (bool, error)
+	ConvertQuotation(ctx context.Context, id int, fromState, toState string, dueDate *time.Time,
+		movement models.StockMovement) (*models.Invoice, []dtos.StockShortageDTO, error)
+}
+
+type RefreshTokenRepositoryInterface interface {
+	CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*models.RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, id int) (bool, error)
+	RevokeUserRefreshTokens(ctx context.Context, userID int) error
+	DeleteExpiredRefreshTokens(ctx context.Context, before time.Time) (int64, error)
+}
+
+type RestockOrderRepositoryInterface interface {
+	GetRestockOrderByID(ctx context.Context, id int) (*models.RestockOrder, error)
+	GetRestockOrders(ctx context.Context, state string, pagination dtos.PaginationDTO) ([]models.RestockOrder, int64, error)
+	CreateRestockOrder(ctx context.Context, order *models.RestockOrder) error
+	ReplaceRestockOrder(ctx context.Context, order *models.RestockOrder, fromState string) (bool, error)
+	SetRestockOrderState(ctx context.Context, id int, fromStates []string, state, timeColumn string, at time.Time) (bool, error)
+	ReceiveRestockOrderItems(ctx context.Context, id int, received map[int]int, movement models.StockMovement,
+		fromState, completeState string) (bool, error)
+}
+
+type RoleRepositoryInterface interface {
+	GetAllRoles(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Role, int64, error)
+	GetRoleByID(ctx context.Context, id uint) (*models.Role, error)
+	GetRolePermissions(ctx context.Context, roleID uint) ([]uint, error)
+	GetAllPermissionsOfRole(ctx context.Context, roleID uint, pagination dtos.PaginationDTO) ([]models.Permission, int64, error)
+	ExistRole(ctx context.Context, roleID uint) (bool, error)
+	SearchRolesByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Role, int64, error)
+	SearchRolesByName(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Role, int64, error)
+	GetRoleByName(ctx context.Context, name string) (*models.Role, error)
+	GetExistingPermissionIDs(ctx context.Context, permissionIDs []uint) ([]uint, error)
+	CreateRole(ctx context.Context, role *models.Role, permissionIDs []uint) error
+	UpdateRole(ctx context.Context, role *models.Role) error
+	SetRolePermissions(ctx context.Context, roleID uint, permissionIDs []uint) error
+	DeleteRole(ctx context.Context, roleID uint) (int64, error)
+}
+
+type ScheduledJobRepositoryInterface interface {
+	EnsureJob(ctx context.Context, name, schedule string, nextRunAt time.Time) error
+	ClaimJob(ctx context.Context, name string, now, nextRunAt time.Time, runBy string) (bool, error)
+	FinishJob(ctx context.Context, name, status, result string, finishedAt time.Time, duration time.Duration) error
+	GetAllJobs(ctx context.Context) ([]models.ScheduledJob, error)
+}
+
+type SecurityEventRepositoryInterface interface {
+	AppendSecurityEvent(ctx context.Context, event *models.SecurityEvent, buildHash func(prevHash string) string) error
+	SearchSecurityEvents(ctx context.Context, filter dtos.SecurityEventFilterDTO) ([]models.SecurityEvent, int64, error)
+	StreamSecurityEvents(ctx context.Context, fn func(models.SecurityEvent) error) error
+	DeleteSecurityEventsBefore(ctx context.Context, limit time.Time) (int64, error)
+}
+
+type StockMovementRepositoryInterface interface {
+	GetStockMovements(ctx context.Context, filter dtos.StockMovementFilterDTO) ([]models.StockMovement, int64, error)
+	GetStockBalance(ctx context.Context, itemID int) (*dtos.StockBalanceDTO, error)
+	GetStockDiscrepancies(ctx context.Context) ([]dtos.StockBalanceDTO, error)
+}
+
+type SupplierRepositoryInterface interface {
+	GetAllSuppliers(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Supplier, int64, error)
+	GetSupplierByID(ctx context.Context, id int) (*models.Supplier, error)
+	GetSupplierByTaxID(ctx context.Context, taxID string) (*models.Supplier, error)
+	SearchSuppliersByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Supplier, int64, error)
+	SearchSuppliersByName(ctx context.Context, name string, pagination dtos.PaginationDTO) ([]models.Supplier, int64, error)
+	CreateSupplier(ctx context.Context, supplier *models.Supplier) error
+	UpdateSupplier(ctx context.Context, supplier *models.Supplier) error
+	CountSupplierUsage(ctx context.Context, id int) (int64, error)
+	DeleteSupplier(ctx context.Context, id int) error
+}
+
+type TaxTypeRepositoryInterface interface {
+	GetAllTaxTypes(ctx context.Context, pagination dtos.PaginationDTO) ([]models.TaxType, int64, error)
+	GetTaxTypeByID(ctx context.Context, id string) (*models.TaxType, error)
+	CreateTaxType(ctx context.Context, taxType *models.TaxType) error
+	CreateTaxTypes(ctx context.Context, taxTypes []models.TaxType) error
+	UpdateTaxType(ctx context.Context, taxType *models.TaxType) error
+	DeactivateTaxType(ctx context.Context, id string) error
+	GetTaxTypeRates(ctx context.Context, id string) ([]models.TaxTypeRate, error)
+}
+
+type TextMessageRepositoryInterface interface {
+	CreateMessage(ctx context.Context, message *models.TextMessage) error
+	ClaimDueMessages(ctx context.Context, status string, now, leaseUntil time.Time, limit int) ([]models.TextMessage, error)
+	SaveAttempt(ctx context.Context, message *models.TextMessage, delivery *models.NotificationDelivery) error
+	RequeueMessage(ctx context.Context, id int, fromStatus, toStatus string, now time.Time) (bool, error)
+}
+
+type UserLogRepositoryInterface interface {
+	CreateUserLog(ctx context.Context, userLog *models.UserLog) (*models.UserLog, error)
+	SearchUserLogs(ctx context.Context, filter dtos.UserLogFilterDTO) ([]models.UserLog, int64, error)
+	CreateUserLogs(ctx context.Context, userLogs []models.UserLog) error
+	DeleteUserLogsBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type UserRepositoryInterface interface {
+	GetUserByID(ctx context.Context, id string) (*models.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	GetAllUsers(ctx context.Context, pagination dtos.PaginationDTO) ([]models.User, int64, error)
+	SearchUsersByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.User, int64, error)
+	SearchUsersByEmail(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.User, int64, error)
+	UpdateUserState(ctx context.Context, id string, state int) (*models.User, error)
+	UpdateUser(ctx context.Context, user *models.User) error
+	CreateUser(ctx context.Context, user *models.User) (*models.User, error)
+}
+
+type UserStateTypeRepositoryInterface interface {
+	GetUserStateTypeByID(ctx context.Context, id string) (*models.UserStateType, error)
+	GetAllUserStateTypes(ctx context.Context, pagination dtos.PaginationDTO) ([]models.UserStateType, int64, error)
+	GetUserStateTypeByName(ctx context.Context, name string) (*models.UserStateType, error)
+	CreateUserStateType(ctx context.Context, userStateType *models.UserStateType) error
+	UpdateUserStateType(ctx context.Context, userStateType *models.UserStateType) error
+	CountUsersWithState(ctx context.Context, id int) (int64, error)
+	DeleteUserStateType(ctx context.Context, id int) error
+}
+
+type UserTypeRepositoryInterface interface {
+	ObtainAllUserTypes(ctx context.Context, pagination dtos.PaginationDTO) ([]models.UserType, int64, error)
+	GetUserTypeByID(ctx context.Context, id uint) (*models.UserType, error)
+	Exists(ctx context.Context, userTypeID uint) (bool, error)
+	GetRolesForUserType(ctx context.Context, userTypeID uint) ([]uint, error)
+	SearchUserTypesByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.UserType, int64, error)
+	SearchUserTypesByName(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.UserType, int64, error)
+	GetUserTypeByName(ctx context.Context, name string) (*models.UserType, error)
+	GetExistingRoleIDs(ctx context.Context, roleIDs []uint) ([]uint, error)
+	CreateUserType(ctx context.Context, userType *models.UserType, roleIDs []uint) error
+	UpdateUserType(ctx context.Context, userType *models.UserType) error
+	SetUserTypeRoles(ctx context.Context, userTypeID uint, roleIDs []uint) error
+	CountUserTypeUsers(ctx context.Context, userTypeID uint) (int64, error)
+	DeleteUserType(ctx context.Context, userTypeID uint) error
+}
+
+type WebhookRepositoryInterface interface {
+	GetAllSubscriptions(ctx context.Context, pagination dtos.PaginationDTO) ([]models.WebhookSubscription, int64, error)
+	GetSubscriptionByID(ctx context.Context, id int) (*models.WebhookSubscription, error)
+	GetActiveSubscriptionsForEvent(ctx context.Context, eventType string) ([]models.WebhookSubscription, error)
+	CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error
+	UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, id int) error
+	CreateDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error
+	GetDeliveriesBySubscription(ctx context.Context, subscriptionID int, pagination dtos.PaginationDTO) ([]models.WebhookDelivery, int64, error)
+	GetDueDeliveries(ctx context.Context, status string, now time.Time, limit int) ([]models.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+}
+
+var (
+	_ AccountingSyncRepositoryInterface         = (*AccountingSyncRepository)(nil)
+	_ AdditionalExpenseRepositoryInterface      = (*AdditionalExpenseRepository)(nil)
+	_ AppointmentReminderRepositoryInterface    = (*AppointmentReminderRepository)(nil)
+	_ AppointmentReportRepositoryInterface      = (*AppointmentReportRepository)(nil)
+	_ AppointmentRepositoryInterface            = (*AppointmentRepository)(nil)
+	_ ArchiveRepositoryInterface                = (*ArchiveRepository)(nil)
+	_ AuditRepositoryInterface                  = (*AuditRepository)(nil)
+	_ AuthorizationRepositoryInterface          = (*AuthorizationRepository)(nil)
+	_ BusinessHoursRepositoryInterface          = (*BusinessHoursRepository)(nil)
+	_ CommentRepositoryInterface                = (*CommentRepository)(nil)
+	_ CreditNoteRepositoryInterface             = (*CreditNoteRepository)(nil)
+	_ CurrencyRepositoryInterface               = (*CurrencyRepository)(nil)
+	_ CustomerRepositoryInterface               = (*CustomerRepository)(nil)
+	_ DailyCloseRepositoryInterface             = (*DailyCloseRepository)(nil)
+	_ DashboardRepositoryInterface              = (*DashboardRepository)(nil)
+	_ DataExportRepositoryInterface             = (*DataExportRepository)(nil)
+	_ DiscountTypeRepositoryInterface           = (*DiscountTypeRepository)(nil)
+	_ EcommerceRepositoryInterface              = (*EcommerceRepository)(nil)
+	_ EmailMessageRepositoryInterface           = (*EmailMessageRepository)(nil)
+	_ EmailTemplateRepositoryInterface          = (*EmailTemplateRepository)(nil)
+	_ EmployeeRepositoryInterface               = (*EmployeeRepository)(nil)
+	_ ExternalSaleRepositoryInterface           = (*ExternalSaleRepository)(nil)
+	_ GatewayPaymentRepositoryInterface         = (*GatewayPaymentRepository)(nil)
+	_ HistoricalItemPriceRepositoryInterface    = (*HistoricalItemPriceRepository)(nil)
+	_ IdentifierTypeRepositoryInterface         = (*IdentifierTypeRepository)(nil)
+	_ InventoryReportRepositoryInterface        = (*InventoryReportRepository)(nil)
+	_ InvoiceReminderRepositoryInterface        = (*InvoiceReminderRepository)(nil)
+	_ InvoiceRepositoryInterface                = (*InvoiceRepository)(nil)
+	_ InvoiceReturnRepositoryInterface          = (*InvoiceReturnRepository)(nil)
+	_ ItemImageRepositoryInterface              = (*ItemImageRepository)(nil)
+	_ ItemRepositoryInterface                   = (*ItemRepository)(nil)
+	_ ItemTypeRepositoryInterface               = (*ItemTypeRepository)(nil)
+	_ LowStockAlertRepositoryInterface          = (*LowStockAlertRepository)(nil)
+	_ NotificationDeliveryRepositoryInterface   = (*NotificationDeliveryRepository)(nil)
+	_ NotificationPreferenceRepositoryInterface = (*NotificationPreferenceRepository)(nil)
+	_ NotificationRepositoryInterface           = (*NotificationRepository)(nil)
+	_ OrderStateTypeRepositoryInterface         = (*OrderStateTypeRepository)(nil)
+	_ PermissionRepositoryInterface             = (*PermissionRepository)(nil)
+	_ PriceListRepositoryInterface              = (*PriceListRepository)(nil)
+	_ PurchaseOrderRepositoryInterface          = (*PurchaseOrderRepository)(nil)
+	_ QuotationRepositoryInterface              = (*QuotationRepository)(nil)
+	_ RefreshTokenRepositoryInterface           = (*RefreshTokenRepository)(nil)
+	_ RestockOrderRepositoryInterface           = (*RestockOrderRepository)(nil)
+	_ RoleRepositoryInterface                   = (*RoleRepository)(nil)
+	_ ScheduledJobRepositoryInterface           = (*ScheduledJobRepository)(nil)
+	_ SecurityEventRepositoryInterface          = (*SecurityEventRepository)(nil)
+	_ StockMovementRepositoryInterface          = (*StockMovementRepository)(nil)
+	_ SupplierRepositoryInterface               = (*SupplierRepository)(nil)
+	_ TaxTypeRepositoryInterface                = (*TaxTypeRepository)(nil)
+	_ TextMessageRepositoryInterface            = (*TextMessageRepository)(nil)
+	_ UserLogRepositoryInterface                = (*UserLogRepository)(nil)
+	_ UserRepositoryInterface                   = (*UserRepository)(nil)
+	_ UserStateTypeRepositoryInterface          = (*UserStateTypeRepository)(nil)
+	_ UserTypeRepositoryInterface               = (*UserTypeRepository)(nil)
+	_ WebhookRepositoryInterface                = (*WebhookRepository)(nil)
+)
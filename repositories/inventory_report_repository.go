@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type InventoryReportRepository struct {
+	DB *gorm.DB
+	// opcional: reportes, listados y búsquedas se leen de aquí
+	Replica *gorm.DB
+}
+
+type ItemSalesRow struct {
+	ItemID       int
+	ItemName     string
+	CategoryID   int
+	CategoryName string
+	Stock        int
+	UnitsSold    int
+}
+
+func NewInventoryReportRepository(db *gorm.DB) *InventoryReportRepository {
+	return &InventoryReportRepository{DB: db}
+}
+
+// GetItemSalesBetween devuelve, por cada item, su stock actual y las unidades vendidas en el rango.
+func (r *InventoryReportRepository) GetItemSalesBetween(ctx context.Context, startDate, endDate time.Time) ([]ItemSalesRow, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var rows []ItemSalesRow
+	err := reader(r.DB, r.Replica).WithContext(ctx).Table("items").
+		Select("items.id AS item_id, items.name AS item_name, items.item_type_id AS category_id, "+
+			"COALESCE(item_types.name, '') AS category_name, items.stock AS stock, "+
+			"COALESCE(SUM(invoice_items.amount), 0) AS units_sold").
+		Joins("LEFT JOIN item_types ON item_types.id = items.item_type_id").
+		Joins("LEFT JOIN invoice_items ON invoice_items.item_id = items.id AND invoice_items.invoice_id IN "+
+			"(SELECT id FROM invoices WHERE date_time BETWEEN ? AND ? AND cancelled_at IS NULL)", startDate, endDate).
+		Group("items.id, items.name, items.item_type_id, item_types.name, items.stock").
+		Order("items.id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
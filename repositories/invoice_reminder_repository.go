@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type InvoiceReminderRepository struct {
+	DB *gorm.DB
+}
+
+func NewInvoiceReminderRepository(db *gorm.DB) *InvoiceReminderRepository {
+	return &InvoiceReminderRepository{DB: db}
+}
+
+// GetInvoicesDueForReminder devuelve, con su cliente, las facturas sin pagar ni anular que vencen en
+// (from, to] y todavía no tienen el recordatorio de la etapa daysFromDue.
+func (r *InvoiceReminderRepository) GetInvoicesDueForReminder(ctx context.Context, daysFromDue int, from, to time.Time) ([]models.Invoice, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var invoices []models.Invoice
+	err := r.DB.WithContext(ctx).Preload("Customer", withDeletedCustomers).
+		Where("paid_at IS NULL AND cancelled_at IS NULL AND due_date > ? AND due_date <= ?", from, to).
+		Where("NOT EXISTS (SELECT 1 FROM invoice_reminders WHERE invoice_reminders.invoice_id = invoices.id AND invoice_reminders.days_from_due = ?)", daysFromDue).
+		Order("due_date, id").
+		Find(&invoices).Error
+	return invoices, err
+}
+
+func (r *InvoiceReminderRepository) CreateReminder(ctx context.Context, reminder *models.InvoiceReminder) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Create(reminder).Error
+}
+
+// GetRemindersByInvoice devuelve el historial de recordatorios de la factura, del más antiguo al
+// más reciente. Si la factura no existe devuelve gorm.ErrRecordNotFound.
+func (r *InvoiceReminderRepository) GetRemindersByInvoice(ctx context.Context, invoiceID int) ([]models.InvoiceReminder, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var count int64
+	if err := r.DB.WithContext(ctx).Model(&models.Invoice{}).Where("id = ?", invoiceID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var reminders []models.InvoiceReminder
+	err := r.DB.WithContext(ctx).Where("invoice_id = ?", invoiceID).Order("id").Find(&reminders).Error
+	return reminders, err
+}
@@ -1,116 +1,253 @@
 package repositories
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"math"
+	"sort"
 	"time"
+	"totesbackend/config"
 	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type InvoiceRepository struct {
 	DB *gorm.DB
+	// opcional: reportes, listados y búsquedas se leen de aquí
+	Replica *gorm.DB
 }
 
 func NewInvoiceRepository(db *gorm.DB) *InvoiceRepository {
 	return &InvoiceRepository{DB: db}
 }
 
-func (r *InvoiceRepository) GetInvoiceByID(id string) (*models.Invoice, error) {
+// Primary devuelve el repositorio sin la réplica, para los reportes que se leen justo antes de
+// guardarse y no pueden perder lo escrito en los últimos segundos.
+func (r *InvoiceRepository) Primary() InvoiceRepositoryInterface {
+	return &InvoiceRepository{DB: r.DB}
+}
+
+func (r *InvoiceRepository) GetInvoiceByID(ctx context.Context, id string) (*models.Invoice, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var invoice models.Invoice
-	err := r.DB.Preload("Customer").
+	err := r.DB.WithContext(ctx).Preload("Customer", withDeletedCustomers).
 		Preload("Items.Item").
 		Preload("Discounts").
 		Preload("Taxes").
-		Find(&invoice).Error
+		First(&invoice, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("invoice not found: %w", err)
+	}
 	if err != nil {
-		return nil, errors.New("invoice not found")
+		return nil, err
+	}
+	invoices := []models.Invoice{invoice}
+	if err := issuedTaxRates(r.DB.WithContext(ctx), invoices); err != nil {
+		return nil, err
 	}
-	return &invoice, nil
+	return &invoices[0], nil
 }
 
-func (r *InvoiceRepository) GetAllInvoices() ([]models.Invoice, error) {
-	var invoices []models.Invoice
-	err := r.DB.Preload("Customer").
+func (r *InvoiceRepository) GetAllInvoices(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Invoice, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := reader(r.DB, r.Replica).WithContext(ctx).Preload("Customer", withDeletedCustomers).
 		Preload("Items.Item").
 		Preload("Discounts").
-		Preload("Taxes").
-		Find(&invoices).Error
+		Preload("Taxes")
+	invoices, total, err := paginate[models.Invoice](db, pagination)
+	if err == nil {
+		err = issuedTaxRates(reader(r.DB, r.Replica).WithContext(ctx), invoices)
+	}
 	if err != nil {
-		return nil, errors.New("error retrieving invoices")
+		return nil, 0, errors.New("error retrieving invoices")
 	}
-	return invoices, nil
+	return invoices, total, nil
 }
 
-func (r *InvoiceRepository) GetInvoicesByDateRange(startDate, endDate time.Time) ([]models.Invoice, error) {
+func (r *InvoiceRepository) GetInvoicesByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.Invoice, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var invoices []models.Invoice
-	err := r.DB.Preload("Customer").
+	err := reader(r.DB, r.Replica).WithContext(ctx).Preload("Customer", withDeletedCustomers).
 		Preload("Items.Item").
 		Preload("Discounts").
 		Preload("Taxes").
 		Where("date_time BETWEEN ? AND ?", startDate, endDate).
 		Find(&invoices).Error
+	if err == nil {
+		err = issuedTaxRates(reader(r.DB, r.Replica).WithContext(ctx), invoices)
+	}
 	if err != nil {
 		return nil, errors.New("error retrieving invoices by date range")
 	}
 	return invoices, nil
 }
 
-func (r *InvoiceRepository) SearchInvoiceByID(query string) ([]models.Invoice, error) {
-	var invoices []models.Invoice
-	err := r.DB.Preload("Customer").Preload("Items.Item").Preload("Discounts").Preload("Taxes").
-		Where("CAST(id AS TEXT) LIKE ?", query+"%").Find(&invoices).Error
+// StreamInvoicesByDateRange recorre las facturas del rango en lotes para no cargarlas todas en memoria.
+func (r *InvoiceRepository) StreamInvoicesByDateRange(ctx context.Context, startDate, endDate time.Time, fn func(models.Invoice) error) error {
+	var batch []models.Invoice
+	result := reader(r.DB, r.Replica).WithContext(ctx).Preload("Customer", withDeletedCustomers).
+		Preload("Items.Item").
+		Preload("Discounts").
+		Preload("Taxes").
+		Where("date_time BETWEEN ? AND ?", startDate, endDate).
+		Order("id").
+		FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+			if err := issuedTaxRates(reader(r.DB, r.Replica).WithContext(ctx), batch); err != nil {
+				return err
+			}
+			for _, invoice := range batch {
+				if err := fn(invoice); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	return result.Error
+}
 
-	if err != nil {
-		return nil, err
+// StreamInvoices recorre en lotes las facturas, anuladas incluidas, emitidas dentro del rango de filter.
+func (r *InvoiceRepository) StreamInvoices(ctx context.Context, filter dtos.CSVExportFilterDTO, fn func(models.Invoice) error) error {
+	query := reader(r.DB, r.Replica).WithContext(ctx).Preload("Customer", withDeletedCustomers).Preload("Items")
+	if filter.From != nil {
+		query = query.Where("date_time >= ?", *filter.From)
 	}
-	return invoices, nil
+	if filter.To != nil {
+		query = query.Where("date_time <= ?", *filter.To)
+	}
+
+	var batch []models.Invoice
+	result := query.Order("id").FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+		for _, invoice := range batch {
+			if err := fn(invoice); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return result.Error
 }
 
-func (r *InvoiceRepository) SearchInvoiceByCustomerPersonalId(query string) ([]models.Invoice, error) {
-	var invoices []models.Invoice
-	err := r.DB.Preload("Customer").
+func (r *InvoiceRepository) SearchInvoiceByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Invoice, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := reader(r.DB, r.Replica).WithContext(ctx).Preload("Customer", withDeletedCustomers).Preload("Items.Item").Preload("Discounts").Preload("Taxes").
+		Where("CAST(id AS TEXT) LIKE ?", query+"%")
+	return r.paginateWithTaxRates(ctx, db, pagination)
+}
+
+func (r *InvoiceRepository) SearchInvoiceByCustomerPersonalId(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Invoice, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	// ILIKE para búsqueda sin distinción de mayúsculas
+	db := reader(r.DB, r.Replica).WithContext(ctx).Preload("Customer", withDeletedCustomers).
 		Preload("Items.Item").
 		Preload("Discounts").
 		Preload("Taxes").
 		Joins("JOIN customers ON customers.id = invoices.customer_id").
-		Where("customers.customer_id ILIKE ?", query+"%"). // ILIKE para búsqueda sin distinción de mayúsculas
-		Find(&invoices).Error
+		Where("customers.customer_id ILIKE ?", query+"%")
+	return r.paginateWithTaxRates(ctx, db, pagination)
+}
 
+// CreateInvoice crea la factura y descuenta el stock en la misma transacción. Cada item se descuenta
+// con un UPDATE condicionado a que alcance (stock >= cantidad), que bloquea la fila hasta el commit,
+// así dos ventas simultáneas no pueden dejarlo negativo. Si algún item no alcanza no se crea nada y
+// se devuelven los faltantes. Cada descuento queda en el libro de stock a partir de movement.
+func (r *InvoiceRepository) CreateInvoice(ctx context.Context, dto *dtos.CreateInvoiceDTO, subtotal float64, total float64, movement models.StockMovement) (*models.Invoice, []dtos.StockShortageDTO, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var invoice *models.Invoice
+	var shortages []dtos.StockShortageDTO
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		invoice, shortages, err = createInvoice(tx, dto, subtotal, total, movement)
+		return err
+	})
+	if errors.Is(err, errStockShortage) {
+		return nil, shortages, nil
+	}
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return invoices, nil
+	return r.loadCreatedInvoice(ctx, invoice.ID)
 }
-func (r *InvoiceRepository) CreateInvoice(dto *dtos.CreateInvoiceDTO, subtotal float64, total float64) (*models.Invoice, error) {
+
+// createInvoice hace el trabajo de CreateInvoice dentro de tx. Si falta stock devuelve los
+// faltantes con errStockShortage para que la transacción se deshaga.
+func createInvoice(tx *gorm.DB, dto *dtos.CreateInvoiceDTO, subtotal float64, total float64, movement models.StockMovement) (*models.Invoice, []dtos.StockShortageDTO, error) {
+	// la factura guarda la tasa vigente de su moneda para que los reportes la pasen a la moneda base
+	var currency models.Currency
+	if err := tx.First(&currency, "code = ?", dto.Currency).Error; err != nil {
+		return nil, nil, err
+	}
 	invoice := &models.Invoice{
 		EnterpriseData: dto.EnterpriseData,
 		DateTime:       time.Now(),
+		DueDate:        dto.DueDate,
 		CustomerID:     dto.CustomerID,
 		Subtotal:       subtotal,
 		Total:          total,
+		Currency:       currency.Code,
+		ExchangeRate:   currency.ExchangeRate,
 	}
+	invoice.AmountPaid, invoice.PaymentStatus = openingPayment(dto.DueDate, total)
 
-	tx := r.DB.Begin()
-	if tx.Error != nil {
-		return nil, tx.Error
-	}
-
-	// Restar stock de los Items
+	// Restar stock de los Items, en orden de ID para que dos facturas no se bloqueen mutuamente
+	requested := make(map[int]int)
+	var itemIDs []int
 	for _, billingItem := range dto.Items {
-		if err := tx.Model(&models.Item{}).
-			Where("id = ?", billingItem.ID).
-			UpdateColumn("stock", gorm.Expr("stock - ?", billingItem.Stock)).Error; err != nil {
-			tx.Rollback()
-			return nil, err
+		if _, ok := requested[billingItem.ID]; !ok {
+			itemIDs = append(itemIDs, billingItem.ID)
+		}
+		requested[billingItem.ID] += billingItem.Stock
+	}
+	sort.Ints(itemIDs)
+
+	var shortages []dtos.StockShortageDTO
+	for _, itemID := range itemIDs {
+		result := tx.Model(&models.Item{}).
+			Where("id = ? AND stock >= ?", itemID, requested[itemID]).
+			UpdateColumns(map[string]interface{}{"stock": gorm.Expr("stock - ?", requested[itemID]), "version": nextVersion})
+		if result.Error != nil {
+			return nil, nil, result.Error
 		}
+		if result.RowsAffected == 0 {
+			var available int
+			if err := tx.Model(&models.Item{}).Select("stock").Where("id = ?", itemID).Scan(&available).Error; err != nil {
+				return nil, nil, err
+			}
+			shortages = append(shortages, dtos.StockShortageDTO{ItemID: itemID, Requested: requested[itemID], Available: available})
+		}
+	}
+	if len(shortages) > 0 {
+		return nil, shortages, errStockShortage
 	}
 
 	// Crear Invoice
 	if err := tx.Create(invoice).Error; err != nil {
-		tx.Rollback()
-		return nil, err
+		return nil, nil, err
+	}
+
+	// Registrar los movimientos de stock
+	movement.ReferenceID = &invoice.ID
+	for _, itemID := range itemIDs {
+		movement.ItemID = itemID
+		movement.Delta = -requested[itemID]
+		if err := recordStockMovement(tx, movement); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	// Registrar InvoiceItems
@@ -122,8 +259,7 @@ func (r *InvoiceRepository) CreateInvoice(dto *dtos.CreateInvoiceDTO, subtotal f
 		}
 
 		if err := tx.Create(invoiceItem).Error; err != nil {
-			tx.Rollback()
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
@@ -131,12 +267,10 @@ func (r *InvoiceRepository) CreateInvoice(dto *dtos.CreateInvoiceDTO, subtotal f
 	var discounts []models.DiscountType
 	if len(dto.Discounts) > 0 {
 		if err := tx.Where("id IN ?", dto.Discounts).Find(&discounts).Error; err != nil {
-			tx.Rollback()
-			return nil, err
+			return nil, nil, err
 		}
 		if err := tx.Model(invoice).Association("Discounts").Append(discounts); err != nil {
-			tx.Rollback()
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
@@ -144,43 +278,48 @@ func (r *InvoiceRepository) CreateInvoice(dto *dtos.CreateInvoiceDTO, subtotal f
 	var taxes []models.TaxType
 	if len(dto.Taxes) > 0 {
 		if err := tx.Where("id IN ?", dto.Taxes).Find(&taxes).Error; err != nil {
-			tx.Rollback()
-			return nil, err
+			return nil, nil, err
 		}
 		if err := tx.Model(invoice).Association("Taxes").Append(taxes); err != nil {
-			tx.Rollback()
-			return nil, err
+			return nil, nil, err
 		}
 	}
+	return invoice, nil, nil
+}
 
-	// Confirmar transacción
-	if err := tx.Commit().Error; err != nil {
-		return nil, err
-	}
-
-	// Cargar Items con Join
+// loadCreatedInvoice lee la factura recién creada con sus items, descuentos e impuestos.
+func (r *InvoiceRepository) loadCreatedInvoice(ctx context.Context, id int) (*models.Invoice, []dtos.StockShortageDTO, error) {
 	var fullInvoice models.Invoice
-	if err := r.DB.
+	if err := r.DB.WithContext(ctx).
+		Preload("Customer", withDeletedCustomers).
 		Preload("Discounts").
 		Preload("Taxes").
 		Preload("Items.Item"). // Carga los items y sus productos
-		First(&fullInvoice, invoice.ID).Error; err != nil {
-		return nil, err
+		First(&fullInvoice, id).Error; err != nil {
+		return nil, nil, err
 	}
 
-	return &fullInvoice, nil
+	return &fullInvoice, nil, nil
 }
 
-func (r *InvoiceRepository) CreateInvoiceWithoutStockReduction(dto *dtos.CreateInvoiceDTO, subtotal float64, total float64) (*models.Invoice, error) {
+func (r *InvoiceRepository) CreateInvoiceWithoutStockReduction(ctx context.Context, dto *dtos.CreateInvoiceDTO, subtotal float64, total float64) (*models.Invoice, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	invoice := &models.Invoice{
 		EnterpriseData: dto.EnterpriseData,
 		DateTime:       time.Now(),
+		DueDate:        dto.DueDate,
 		CustomerID:     dto.CustomerID,
 		Subtotal:       subtotal,
 		Total:          total,
+		// las órdenes de compra se calculan en la moneda base
+		Currency:     config.BASE_CURRENCY,
+		ExchangeRate: 1,
 	}
+	invoice.AmountPaid, invoice.PaymentStatus = openingPayment(dto.DueDate, total)
 
-	tx := r.DB.Begin()
+	tx := r.DB.WithContext(ctx).Begin()
 	if tx.Error != nil {
 		return nil, tx.Error
 	}
@@ -240,7 +379,7 @@ func (r *InvoiceRepository) CreateInvoiceWithoutStockReduction(dto *dtos.CreateI
 
 	// Cargar Items con Join
 	var fullInvoice models.Invoice
-	if err := r.DB.
+	if err := r.DB.WithContext(ctx).
 		Preload("Discounts").
 		Preload("Taxes").
 		Preload("Items.Item").
@@ -250,3 +389,449 @@ func (r *InvoiceRepository) CreateInvoiceWithoutStockReduction(dto *dtos.CreateI
 
 	return &fullInvoice, nil
 }
+
+// SetInvoicePaidAt marca la factura como pagada en paidAt, o como pendiente con nil, si sigue en
+// version. Pendiente no borra los pagos registrados: queda como abonada si los tiene. Devuelve false si la factura existe pero cambió antes.
+func (r *InvoiceRepository) SetInvoicePaidAt(ctx context.Context, id, version int, paidAt *time.Time) (*models.Invoice, bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	// pagada queda saldada; pendiente vuelve a lo abonado con pagos
+	updates := map[string]interface{}{"paid_at": paidAt, "version": nextVersion,
+		"amount_paid": gorm.Expr("total"), "payment_status": config.INVOICE_PAYMENT_PAID}
+	if paidAt == nil {
+		var amountPaid float64
+		if err := r.DB.WithContext(ctx).Model(&models.Payment{}).Where("invoice_id = ?", id).
+			Select("COALESCE(SUM(amount), 0)").Scan(&amountPaid).Error; err != nil {
+			return nil, false, err
+		}
+		updates["amount_paid"] = amountPaid
+		updates["payment_status"] = config.INVOICE_PAYMENT_UNPAID
+		if amountPaid > 0 {
+			updates["payment_status"] = config.INVOICE_PAYMENT_PARTIAL
+		}
+	}
+	result := r.DB.WithContext(ctx).Model(&models.Invoice{}).Where("id = ? AND version = ?", id, version).
+		Updates(updates)
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := r.DB.WithContext(ctx).Model(&models.Invoice{}).Where("id = ?", id).Count(&count).Error; err != nil {
+			return nil, false, err
+		}
+		if count == 0 {
+			return nil, false, gorm.ErrRecordNotFound
+		}
+		return nil, false, nil
+	}
+
+	var invoice models.Invoice
+	if err := r.DB.WithContext(ctx).
+		Preload("Discounts").
+		Preload("Taxes").
+		Preload("Items.Item").
+		First(&invoice, id).Error; err != nil {
+		return nil, false, err
+	}
+	invoices := []models.Invoice{invoice}
+	if err := issuedTaxRates(r.DB.WithContext(ctx), invoices); err != nil {
+		return nil, false, err
+	}
+	return &invoices[0], true, nil
+}
+
+// issuedTaxRateJoin agrega old_rate, la tarifa anterior de invoice_taxes.tax_type_id vigente al emitirse
+// invoices; es NULL si la tarifa no ha cambiado desde entonces y vale la de tax_types.
+const issuedTaxRateJoin = "LEFT JOIN LATERAL (SELECT is_percentage, value FROM tax_type_rates " +
+	"WHERE tax_type_id = invoice_taxes.tax_type_id AND valid_until > invoices.date_time ORDER BY valid_until LIMIT 1) old_rate ON true"
+
+// issuedTaxRates cambia en los impuestos de cada factura la tarifa actual por la que tenía al
+// emitirse, si cambió después.
+func issuedTaxRates(db *gorm.DB, invoices []models.Invoice) error {
+	var taxIDs []int
+	var since time.Time
+	for _, invoice := range invoices {
+		for _, tax := range invoice.Taxes {
+			taxIDs = append(taxIDs, tax.ID)
+		}
+		if len(invoice.Taxes) > 0 && (since.IsZero() || invoice.DateTime.Before(since)) {
+			since = invoice.DateTime
+		}
+	}
+	if len(taxIDs) == 0 {
+		return nil
+	}
+
+	var rates []models.TaxTypeRate
+	if err := db.Where("tax_type_id IN ? AND valid_until > ?", taxIDs, since).Order("valid_until").Find(&rates).Error; err != nil {
+		return err
+	}
+	for i := range invoices {
+		for j := range invoices[i].Taxes {
+			tax := &invoices[i].Taxes[j]
+			for _, rate := range rates {
+				if rate.TaxTypeID == tax.ID && rate.ValidUntil.After(invoices[i].DateTime) {
+					tax.IsPercentage, tax.Value = rate.IsPercentage, rate.Value
+					break
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (r *InvoiceRepository) paginateWithTaxRates(ctx context.Context, db *gorm.DB, pagination dtos.PaginationDTO) ([]models.Invoice, int64, error) {
+	invoices, total, err := paginate[models.Invoice](db, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := issuedTaxRates(reader(r.DB, r.Replica).WithContext(ctx), invoices); err != nil {
+		return nil, 0, err
+	}
+	return invoices, total, nil
+}
+
+type periodAmount struct {
+	Period time.Time
+	Amount float64
+}
+
+// GetSalesSummaryByPeriod agrupa las facturas del rango por periodo (day, week o month)
+// y calcula en la base de datos los conteos, subtotales, impuestos, descuentos y totales. Las
+// facturas anuladas no cuentan, aquí ni en los demás reportes de ventas, y los montos se pasan a la
+// moneda base con la tasa guardada en cada factura.
+func (r *InvoiceRepository) GetSalesSummaryByPeriod(ctx context.Context, startDate, endDate time.Time, groupBy string) ([]dtos.SalesSummaryPeriodDTO, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	periodExpr := "date_trunc('" + groupBy + "', invoices.date_time)"
+
+	var periods []dtos.SalesSummaryPeriodDTO
+	err := reader(r.DB, r.Replica).WithContext(ctx).Model(&models.Invoice{}).
+		Select(periodExpr+" AS period, COUNT(*) AS invoice_count, "+
+			"COALESCE(SUM(invoices.subtotal * invoices.exchange_rate), 0) AS subtotal, COALESCE(SUM(invoices.total * invoices.exchange_rate), 0) AS total").
+		Where("invoices.date_time BETWEEN ? AND ? AND invoices.cancelled_at IS NULL", startDate, endDate).
+		Group("period").
+		Order("period").
+		Scan(&periods).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var taxes []periodAmount
+	err = reader(r.DB, r.Replica).WithContext(ctx).Table("invoices").
+		Select(periodExpr+" AS period, "+
+			"COALESCE(SUM(CASE WHEN COALESCE(old_rate.is_percentage, tax_types.is_percentage) "+
+			"THEN invoices.subtotal * invoices.exchange_rate * COALESCE(old_rate.value, tax_types.value) / 100 "+
+			"ELSE COALESCE(old_rate.value, tax_types.value) END), 0) AS amount").
+		Joins("JOIN invoice_taxes ON invoice_taxes.invoice_id = invoices.id").
+		Joins("JOIN tax_types ON tax_types.id = invoice_taxes.tax_type_id").
+		Joins(issuedTaxRateJoin).
+		Where("invoices.date_time BETWEEN ? AND ? AND invoices.cancelled_at IS NULL", startDate, endDate).
+		Group("period").
+		Scan(&taxes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var discounts []periodAmount
+	err = reader(r.DB, r.Replica).WithContext(ctx).Table("invoices").
+		Select(periodExpr+" AS period, "+
+			"COALESCE(SUM(CASE WHEN discount_types.is_percentage THEN invoices.subtotal * invoices.exchange_rate * discount_types.value / 100 ELSE discount_types.value END), 0) AS amount").
+		Joins("JOIN invoice_discounts ON invoice_discounts.invoice_id = invoices.id").
+		Joins("JOIN discount_types ON discount_types.id = invoice_discounts.discount_type_id").
+		Where("invoices.date_time BETWEEN ? AND ? AND invoices.cancelled_at IS NULL", startDate, endDate).
+		Group("period").
+		Scan(&discounts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	indexByPeriod := make(map[int64]int, len(periods))
+	for i, period := range periods {
+		indexByPeriod[period.Period.Unix()] = i
+	}
+	for _, tax := range taxes {
+		if i, ok := indexByPeriod[tax.Period.Unix()]; ok {
+			periods[i].Tax = tax.Amount
+		}
+	}
+	for _, discount := range discounts {
+		if i, ok := indexByPeriod[discount.Period.Unix()]; ok {
+			periods[i].Discount = discount.Amount
+		}
+	}
+
+	return periods, nil
+}
+
+// GetVoidTotals cuenta las facturas anuladas con nota crédito dentro del rango, sin importar cuándo se
+// emitieron, y suma su total en la moneda base.
+func (r *InvoiceRepository) GetVoidTotals(ctx context.Context, startDate, endDate time.Time) (dtos.VoidTotalsDTO, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var totals dtos.VoidTotalsDTO
+	err := reader(r.DB, r.Replica).WithContext(ctx).Model(&models.Invoice{}).
+		Select("COUNT(*) AS void_count, COALESCE(SUM(invoices.total * invoices.exchange_rate), 0) AS void_total").
+		Where("invoices.cancelled_at BETWEEN ? AND ?", startDate, endDate).
+		Scan(&totals).Error
+	return totals, err
+}
+
+// GetPaymentTotalsByMethod suma lo recibido en el rango por medio de pago, en la moneda base: los
+// pagos registrados con fecha en el rango y las facturas de contado emitidas en él, que se pagan al
+// emitirse sin registrar un pago y se cuentan como efectivo, menos los reembolsos de devoluciones
+// pagados en el rango con ese medio. Las facturas anuladas no cuentan.
+func (r *InvoiceRepository) GetPaymentTotalsByMethod(ctx context.Context, startDate, endDate time.Time) ([]dtos.PaymentMethodTotalDTO, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var totals []dtos.PaymentMethodTotalDTO
+	err := reader(r.DB, r.Replica).WithContext(ctx).Raw(
+		"SELECT method, SUM(amount) AS total FROM ("+
+			"SELECT payments.method, payments.amount * invoices.exchange_rate AS amount FROM payments "+
+			"JOIN invoices ON invoices.id = payments.invoice_id "+
+			"WHERE payments.date BETWEEN ? AND ? AND invoices.cancelled_at IS NULL "+
+			"UNION ALL "+
+			"SELECT ?, invoices.total * invoices.exchange_rate FROM invoices "+
+			"WHERE invoices.date_time BETWEEN ? AND ? AND invoices.due_date IS NULL AND invoices.cancelled_at IS NULL "+
+			"UNION ALL "+
+			"SELECT invoice_returns.refund_method, -invoice_returns.total * invoices.exchange_rate FROM invoice_returns "+
+			"JOIN invoices ON invoices.id = invoice_returns.invoice_id "+
+			"WHERE invoice_returns.refunded_at BETWEEN ? AND ? AND invoices.cancelled_at IS NULL"+
+			") received GROUP BY method ORDER BY method",
+		startDate, endDate, config.PAYMENT_METHOD_CASH, startDate, endDate, startDate, endDate).
+		Scan(&totals).Error
+	return totals, err
+}
+
+// itemCurrencyRate es la tasa actual de la moneda de los precios de items.
+const itemCurrencyRate = "(SELECT currencies.exchange_rate FROM currencies WHERE currencies.code = items.currency)"
+
+// invoiceLinePrice es el precio de venta de items vigente en la fecha de invoices, pasado a la moneda
+// base con la tasa actual de su moneda; las facturas no guardan el precio de cada línea. Si el item
+// está en la lista de precios del cliente se toma el de la lista, que no tiene historial.
+const invoiceLinePrice = "COALESCE((SELECT price_list_items.price * " + itemCurrencyRate + " FROM customers " +
+	"JOIN price_list_items ON price_list_items.price_list_id = customers.price_list_id " +
+	"WHERE customers.id = invoices.customer_id AND price_list_items.item_id = items.id), " +
+	"(SELECT historical_item_prices.price * currencies.exchange_rate FROM historical_item_prices " +
+	"JOIN currencies ON currencies.code = historical_item_prices.currency " +
+	"WHERE historical_item_prices.item_id = items.id AND historical_item_prices.added_at <= invoices.date_time " +
+	"ORDER BY historical_item_prices.added_at DESC LIMIT 1), items.selling_price * " + itemCurrencyRate + ")"
+
+type InvoiceLineCost struct {
+	InvoiceID         int
+	DateTime          time.Time
+	ItemID            int
+	ItemName          string
+	CategoryID        int
+	CategoryName      string
+	Amount            int
+	UnitPrice         float64
+	UnitPurchasePrice float64
+	UnitExpenses      float64
+}
+
+// purchasedStockReasons son los movimientos con que entran al stock unidades compradas: el saldo
+// inicial, el stock al crear el item y las recepciones de pedidos a proveedores.
+var purchasedStockReasons = []string{config.STOCK_MOVEMENT_OPENING, config.STOCK_MOVEMENT_ITEM_CREATED, config.STOCK_MOVEMENT_RESTOCK_ORDER}
+
+// GetInvoiceLineCosts devuelve cada línea de factura del rango con el precio de venta vigente
+// en la fecha de la factura, el precio de compra y los gastos adicionales por unidad del item: el
+// total de sus gastos repartido entre todas las unidades compradas del item.
+func (r *InvoiceRepository) GetInvoiceLineCosts(ctx context.Context, startDate, endDate time.Time) ([]InvoiceLineCost, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var lines []InvoiceLineCost
+	err := reader(r.DB, r.Replica).WithContext(ctx).Table("invoice_items").
+		Select("invoices.id AS invoice_id, invoices.date_time AS date_time, items.id AS item_id, items.name AS item_name, "+
+			"items.item_type_id AS category_id, COALESCE(item_types.name, '') AS category_name, invoice_items.amount AS amount, "+
+			invoiceLinePrice+" AS unit_price, "+
+			"items.purchase_price * "+itemCurrencyRate+" AS unit_purchase_price, "+
+			"COALESCE((SELECT SUM(additional_expenses.expense) FROM additional_expenses "+
+			"WHERE additional_expenses.item_id = items.id), 0) / "+
+			"GREATEST((SELECT COALESCE(SUM(stock_movements.delta), 0) FROM stock_movements "+
+			"WHERE stock_movements.item_id = items.id AND stock_movements.delta > 0 AND stock_movements.reason IN ?), 1) AS unit_expenses",
+			purchasedStockReasons).
+		Joins("JOIN invoices ON invoices.id = invoice_items.invoice_id").
+		Joins("JOIN items ON items.id = invoice_items.item_id").
+		Joins("LEFT JOIN item_types ON item_types.id = items.item_type_id").
+		Where("invoices.date_time BETWEEN ? AND ? AND invoices.cancelled_at IS NULL", startDate, endDate).
+		Order("invoices.date_time, invoices.id, items.id").
+		Scan(&lines).Error
+	if err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// GetDiscountUsage devuelve, por cada tipo de descuento, cuántas facturas del rango lo aplicaron,
+// el subtotal de esas facturas y el monto descontado. Los descuentos sin uso aparecen con cero.
+func (r *InvoiceRepository) GetDiscountUsage(ctx context.Context, startDate, endDate time.Time) ([]dtos.DiscountUsageDTO, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var usage []dtos.DiscountUsageDTO
+	err := reader(r.DB, r.Replica).WithContext(ctx).Table("discount_types").
+		Select("discount_types.id AS discount_type_id, discount_types.name AS discount_type_name, "+
+			"discount_types.is_percentage AS is_percentage, discount_types.value AS value, "+
+			"COUNT(invoices.id) AS invoice_count, COALESCE(SUM(invoices.subtotal * invoices.exchange_rate), 0) AS subtotal, "+
+			"COALESCE(SUM(CASE WHEN discount_types.is_percentage THEN invoices.subtotal * invoices.exchange_rate * discount_types.value / 100 "+
+			"ELSE discount_types.value END) FILTER (WHERE invoices.id IS NOT NULL), 0) AS discounted").
+		Joins("LEFT JOIN invoice_discounts ON invoice_discounts.discount_type_id = discount_types.id").
+		Joins("LEFT JOIN invoices ON invoices.id = invoice_discounts.invoice_id AND invoices.date_time BETWEEN ? AND ? "+
+			"AND invoices.cancelled_at IS NULL", startDate, endDate).
+		Group("discount_types.id, discount_types.name, discount_types.is_percentage, discount_types.value").
+		Order("discounted DESC, discount_types.id").
+		Scan(&usage).Error
+	if err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// GetTopItems devuelve los limit items más vendidos en el rango, por unidades o por ingresos según
+// orderBy ("units" o "revenue"), con los ingresos calculados al precio de cada factura.
+func (r *InvoiceRepository) GetTopItems(ctx context.Context, startDate, endDate time.Time, orderBy string, limit int) ([]dtos.TopItemDTO, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	order := "units_sold DESC, revenue DESC, items.id"
+	if orderBy == "revenue" {
+		order = "revenue DESC, units_sold DESC, items.id"
+	}
+
+	var items []dtos.TopItemDTO
+	err := reader(r.DB, r.Replica).WithContext(ctx).Table("invoice_items").
+		Select("items.id AS item_id, items.name AS item_name, SUM(invoice_items.amount) AS units_sold, "+
+			"COUNT(DISTINCT invoices.id) AS invoice_count, COALESCE(SUM(invoice_items.amount * "+invoiceLinePrice+"), 0) AS revenue").
+		Joins("JOIN invoices ON invoices.id = invoice_items.invoice_id").
+		Joins("JOIN items ON items.id = invoice_items.item_id").
+		Where("invoices.date_time BETWEEN ? AND ? AND invoices.cancelled_at IS NULL", startDate, endDate).
+		Group("items.id, items.name").
+		Order(order).
+		Limit(limit).
+		Scan(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// GetRevenueByCustomer devuelve los limit clientes que más facturaron en el rango, con el número
+// de facturas, la suma de subtotales y totales y la fecha de la última. Incluye clientes borrados.
+func (r *InvoiceRepository) GetRevenueByCustomer(ctx context.Context, startDate, endDate time.Time, limit int) ([]dtos.CustomerRevenueDTO, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var customers []dtos.CustomerRevenueDTO
+	err := reader(r.DB, r.Replica).WithContext(ctx).Table("invoices").
+		Select("customers.id AS customer_id, TRIM(COALESCE(customers.customer_name, '') || ' ' || customers.last_name) AS customer_name, "+
+			"customers.customer_id AS document_number, COUNT(invoices.id) AS invoice_count, "+
+			"SUM(invoices.subtotal * invoices.exchange_rate) AS subtotal, SUM(invoices.total * invoices.exchange_rate) AS total, MAX(invoices.date_time) AS last_invoice_at").
+		Joins("JOIN customers ON customers.id = invoices.customer_id").
+		Where("invoices.date_time BETWEEN ? AND ? AND invoices.cancelled_at IS NULL", startDate, endDate).
+		Group("customers.id, customers.customer_name, customers.last_name, customers.customer_id").
+		Order("total DESC, customers.id").
+		Limit(limit).
+		Scan(&customers).Error
+	if err != nil {
+		return nil, err
+	}
+	return customers, nil
+}
+
+// RecordPayment registra payment contra su factura en una transacción y suma el abono a lo pagado;
+// si la factura queda saldada la marca como pagada en la fecha del pago. No registra nada, y devuelve
+// la factura con false, si está anulada o el abono supera el saldo; devuelve gorm.ErrRecordNotFound
+// si no existe.
+func (r *InvoiceRepository) RecordPayment(ctx context.Context, payment *models.Payment) (*models.Invoice, bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var invoice models.Invoice
+	recorded := false
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&invoice, "id = ?", payment.InvoiceID).Error; err != nil {
+			return err
+		}
+		amountPaid := roundAmount(invoice.AmountPaid + payment.Amount)
+		if invoice.CancelledAt != nil || amountPaid > roundAmount(invoice.Total) {
+			return nil
+		}
+
+		if err := tx.Create(payment).Error; err != nil {
+			return err
+		}
+		updates := map[string]interface{}{"amount_paid": amountPaid, "payment_status": paymentStatus(invoice.Total, amountPaid),
+			"version": nextVersion}
+		if updates["payment_status"] == config.INVOICE_PAYMENT_PAID && invoice.PaidAt == nil {
+			updates["paid_at"] = payment.Date
+		}
+		if err := tx.Model(&models.Invoice{}).Where("id = ?", invoice.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+		recorded = true
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if !recorded {
+		return &invoice, false, nil
+	}
+
+	var updated models.Invoice
+	if err := r.DB.WithContext(ctx).
+		Preload("Discounts").
+		Preload("Taxes").
+		Preload("Items.Item").
+		First(&updated, invoice.ID).Error; err != nil {
+		return nil, false, err
+	}
+	invoices := []models.Invoice{updated}
+	if err := issuedTaxRates(r.DB.WithContext(ctx), invoices); err != nil {
+		return nil, false, err
+	}
+	return &invoices[0], true, nil
+}
+
+// GetInvoicePayments lista los pagos de la factura en el orden en que se hicieron.
+func (r *InvoiceRepository) GetInvoicePayments(ctx context.Context, invoiceID int) ([]models.Payment, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var payments []models.Payment
+	err := r.DB.WithContext(ctx).Where("invoice_id = ?", invoiceID).Order("date, id").Find(&payments).Error
+	return payments, err
+}
+
+// openingPayment es lo pagado y el estado de pago de una factura nueva: las de contado se pagan al
+// emitirse y las de crédito empiezan sin abonos.
+func openingPayment(dueDate *time.Time, total float64) (float64, string) {
+	if dueDate == nil {
+		return total, config.INVOICE_PAYMENT_PAID
+	}
+	return 0, config.INVOICE_PAYMENT_UNPAID
+}
+
+func paymentStatus(total, amountPaid float64) string {
+	switch {
+	case amountPaid <= 0:
+		return config.INVOICE_PAYMENT_UNPAID
+	case amountPaid >= roundAmount(total):
+		return config.INVOICE_PAYMENT_PAID
+	default:
+		return config.INVOICE_PAYMENT_PARTIAL
+	}
+}
+
+// roundAmount redondea un valor a config.CURRENCY_DECIMALS para comparar abonos con el total.
+func roundAmount(amount float64) float64 {
+	scale := math.Pow10(config.CURRENCY_DECIMALS)
+	return math.Round(amount*scale) / scale
+}
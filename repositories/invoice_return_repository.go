@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InvoiceReturnRepository struct {
+	DB *gorm.DB
+}
+
+func NewInvoiceReturnRepository(db *gorm.DB) *InvoiceReturnRepository {
+	return &InvoiceReturnRepository{DB: db}
+}
+
+// GetInvoiceReturns lista las devoluciones de la factura en el orden en que se hicieron.
+func (r *InvoiceReturnRepository) GetInvoiceReturns(ctx context.Context, invoiceID int) ([]models.InvoiceReturn, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var returns []models.InvoiceReturn
+	err := r.DB.WithContext(ctx).Preload("Items").Where("invoice_id = ?", invoiceID).Order("id").Find(&returns).Error
+	return returns, err
+}
+
+func (r *InvoiceReturnRepository) GetInvoiceReturnByID(ctx context.Context, id int) (*models.InvoiceReturn, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var invoiceReturn models.InvoiceReturn
+	if err := r.DB.WithContext(ctx).Preload("Items.Item").First(&invoiceReturn, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &invoiceReturn, nil
+}
+
+type returnableLine struct {
+	ItemID    int
+	Sold      int
+	UnitPrice float64
+}
+
+// CreateReturn registra la devolución ret de la factura en una transacción. Cada item se valida
+// contra lo vendido en la factura menos lo ya devuelto; si alguno no alcanza no se hace nada y se
+// devuelven los excesos. Las líneas se valoran con el precio del item vigente en la fecha de la
+// factura, en su moneda, y el total aplica la misma proporción de descuentos e impuestos que la
+// factura. Las unidades vuelven al stock con un movimiento por item a partir de movement. Devuelve
+// false si la factura está anulada y gorm.ErrRecordNotFound si no existe.
+func (r *InvoiceReturnRepository) CreateReturn(ctx context.Context, invoiceID int, ret *models.InvoiceReturn, movement models.StockMovement,
+	at time.Time) ([]dtos.ReturnExcessDTO, bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var excesses []dtos.ReturnExcessDTO
+	cancelled := false
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var invoice models.Invoice
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&invoice, "id = ?", invoiceID).Error; err != nil {
+			return err
+		}
+		if invoice.CancelledAt != nil {
+			cancelled = true
+			return nil
+		}
+
+		var lines []returnableLine
+		if err := tx.Table("invoice_items").
+			Select("items.id AS item_id, SUM(invoice_items.amount) AS sold, "+invoiceLinePrice+" / invoices.exchange_rate AS unit_price").
+			Joins("JOIN invoices ON invoices.id = invoice_items.invoice_id").
+			Joins("JOIN items ON items.id = invoice_items.item_id").
+			Where("invoice_items.invoice_id = ?", invoiceID).
+			Group("items.id, invoices.id").
+			Scan(&lines).Error; err != nil {
+			return err
+		}
+		returned, err := returnedAmounts(tx, invoiceID)
+		if err != nil {
+			return err
+		}
+		returnable := make(map[int]returnableLine, len(lines))
+		for _, line := range lines {
+			line.Sold -= returned[line.ItemID]
+			returnable[line.ItemID] = line
+		}
+
+		// una devolución puede repetir un item en varias líneas; se guarda una por item
+		requested := make(map[int]int)
+		var itemIDs []int
+		for _, item := range ret.Items {
+			if _, ok := requested[item.ItemID]; !ok {
+				itemIDs = append(itemIDs, item.ItemID)
+			}
+			requested[item.ItemID] += item.Amount
+		}
+		ret.Items = nil
+		for _, itemID := range itemIDs {
+			line := returnable[itemID]
+			if requested[itemID] > line.Sold {
+				excesses = append(excesses, dtos.ReturnExcessDTO{ItemID: itemID, Requested: requested[itemID], Returnable: line.Sold})
+				continue
+			}
+			unitPrice := roundAmount(line.UnitPrice)
+			ret.Items = append(ret.Items, models.InvoiceReturnItem{ItemID: itemID, Amount: requested[itemID], UnitPrice: unitPrice})
+			ret.Subtotal += unitPrice * float64(requested[itemID])
+		}
+		if len(excesses) > 0 {
+			return nil
+		}
+
+		ret.InvoiceID = invoiceID
+		ret.Subtotal = roundAmount(ret.Subtotal)
+		ret.Total = ret.Subtotal
+		if invoice.Subtotal > 0 {
+			ret.Total = roundAmount(ret.Subtotal * invoice.Total / invoice.Subtotal)
+		}
+		ret.CreatedAt = at
+		items := ret.Items
+		if err := tx.Omit("Items").Create(ret).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].InvoiceReturnID = ret.ID
+		}
+		if err := tx.Omit("Item").Create(&items).Error; err != nil {
+			return err
+		}
+		ret.Items = items
+
+		movement.ReferenceID = &ret.ID
+		for _, item := range items {
+			movement.ItemID = item.ItemID
+			movement.Delta = item.Amount
+			if err := applyStockMovement(tx, movement); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return excesses, !cancelled, nil
+}
+
+// returnedAmounts suma, por item, las unidades ya devueltas de la factura.
+func returnedAmounts(tx *gorm.DB, invoiceID int) (map[int]int, error) {
+	var rows []struct {
+		ItemID int
+		Amount int
+	}
+	if err := tx.Table("invoice_return_items").
+		Select("invoice_return_items.item_id, SUM(invoice_return_items.amount) AS amount").
+		Joins("JOIN invoice_returns ON invoice_returns.id = invoice_return_items.invoice_return_id").
+		Where("invoice_returns.invoice_id = ?", invoiceID).
+		Group("invoice_return_items.item_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	returned := make(map[int]int, len(rows))
+	for _, row := range rows {
+		returned[row.ItemID] = row.Amount
+	}
+	return returned, nil
+}
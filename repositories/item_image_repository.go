@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type ItemImageRepository struct {
+	DB *gorm.DB
+}
+
+func NewItemImageRepository(db *gorm.DB) *ItemImageRepository {
+	return &ItemImageRepository{DB: db}
+}
+
+func (r *ItemImageRepository) GetItemImage(ctx context.Context, itemID int, imageID int) (*models.ItemImage, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var image models.ItemImage
+	if err := r.DB.WithContext(ctx).First(&image, "id = ? AND item_id = ?", imageID, itemID).Error; err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+func (r *ItemImageRepository) CountItemImages(ctx context.Context, itemID int) (int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var count int64
+	err := r.DB.WithContext(ctx).Model(&models.ItemImage{}).Where("item_id = ?", itemID).Count(&count).Error
+	return count, err
+}
+
+func (r *ItemImageRepository) CreateItemImage(ctx context.Context, image *models.ItemImage) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Create(image).Error
+}
+
+func (r *ItemImageRepository) DeleteItemImage(ctx context.Context, image *models.ItemImage) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Delete(image)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
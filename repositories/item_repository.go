@@ -1,125 +1,215 @@
 package repositories
 
 import (
+	"context"
+	"strings"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ItemRepository struct {
 	DB *gorm.DB
+	// opcional: reportes, listados y búsquedas se leen de aquí
+	Replica *gorm.DB
 }
 
 func NewItemRepository(db *gorm.DB) *ItemRepository {
 	return &ItemRepository{DB: db}
 }
 
-func (r *ItemRepository) GetItemByID(id string) (*models.Item, error) {
+func (r *ItemRepository) WithTx(tx Tx) ItemRepositoryInterface {
+	return &ItemRepository{DB: tx.Conn()}
+}
+
+func (r *ItemRepository) GetItemByID(ctx context.Context, id string) (*models.Item, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var item models.Item
-	err := r.DB.Preload("ItemType").Preload("AdditionalExpenses").First(&item, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).Preload("ItemType").Preload("AdditionalExpenses").Preload("Images", orderItemImages).First(&item, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &item, nil
 }
 
-func (r *ItemRepository) HasEnoughStock(id string, quantity int) (bool, error) {
+func (r *ItemRepository) HasEnoughStock(ctx context.Context, id string, quantity int) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var stock int
-	err := r.DB.Model(&models.Item{}).Select("stock").Where("id = ?", id).Scan(&stock).Error
+	err := r.DB.WithContext(ctx).Model(&models.Item{}).Select("stock").Where("id = ?", id).Scan(&stock).Error
 	if err != nil {
 		return false, err
 	}
 	return stock >= quantity, nil
 }
 
-func (r *ItemRepository) GetAllItems() ([]models.Item, error) {
-	var items []models.Item
-	err := r.DB.Preload("ItemType").Preload("AdditionalExpenses").Find(&items).Error
-	if err != nil {
-		return nil, err
-	}
-	return items, nil
+func (r *ItemRepository) GetAllItems(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Item, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := reader(r.DB, r.Replica).WithContext(ctx).Preload("ItemType").Preload("AdditionalExpenses").Preload("Images", orderItemImages)
+	return paginate[models.Item](db, pagination)
 }
 
-func (r *ItemRepository) SearchItemsByID(query string) ([]models.Item, error) {
-	var items []models.Item
-	err := r.DB.Preload("ItemType").Preload("AdditionalExpenses").
-		Where("CAST(id AS TEXT) LIKE ?", query+"%").Find(&items).Error
-	if err != nil {
-		return nil, err
+// SearchItems busca los items cuyo ID, SKU o código de barras es query, o en los que cada palabra de
+// query aparece en el nombre o la descripción (sin distinguir mayúsculas ni tildes) o empieza el SKU.
+// Primero van las coincidencias exactas de código, luego los nombres que empiezan por query, los que
+// la contienen y el resto, cada grupo por parecido del nombre. Los índices de trigramas de la
+// migración 39 cubren las condiciones ILIKE.
+func (r *ItemRepository) SearchItems(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Item, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	exact := r.DB.Where("(items.sku ILIKE ? OR items.barcode = ? OR CAST(items.id AS TEXT) = ?)", query, query, query)
+	words := r.DB
+	for _, word := range strings.Fields(query) {
+		words = words.Where("("+itemSearchName+" ILIKE immutable_unaccent(?) OR immutable_unaccent(items.description) ILIKE immutable_unaccent(?) OR items.sku ILIKE ?)",
+			"%"+word+"%", "%"+word+"%", word+"%")
+	}
+	rank := clause.Expr{
+		SQL: "CASE WHEN items.sku ILIKE ? OR items.barcode = ? OR CAST(items.id AS TEXT) = ? THEN 0 " +
+			"WHEN " + itemSearchName + " ILIKE immutable_unaccent(?) THEN 1 " +
+			"WHEN " + itemSearchName + " ILIKE immutable_unaccent(?) THEN 2 ELSE 3 END, " +
+			"similarity(" + itemSearchName + ", immutable_unaccent(?)) DESC",
+		Vars:               []interface{}{query, query, query, query + "%", "%" + query + "%", query},
+		WithoutParentheses: true,
 	}
-	return items, nil
+
+	db := reader(r.DB, r.Replica).WithContext(ctx).Preload("ItemType").Preload("AdditionalExpenses").Preload("Images", orderItemImages).
+		Where(exact.Or(words)).
+		Order(clause.OrderBy{Expression: rank})
+	return paginate[models.Item](db, pagination)
 }
 
-func (r *ItemRepository) SearchItemsByName(query string) ([]models.Item, error) {
-	var items []models.Item
-	err := r.DB.Preload("ItemType").Preload("AdditionalExpenses").
-		Where("LOWER(name) LIKE LOWER(?)", query+"%").
-		Find(&items).Error
-	if err != nil {
-		return nil, err
-	}
-	return items, nil
+// GetItemBySKU devuelve nil si ningún item tiene ese SKU.
+func (r *ItemRepository) GetItemBySKU(ctx context.Context, sku string) (*models.Item, error) {
+	return r.getItemByCode(ctx, "sku", sku)
 }
 
-func (r *ItemRepository) UpdateItemState(id string, state bool) (*models.Item, error) {
-	var item models.Item
-	if err := r.DB.Preload("ItemType").Preload("AdditionalExpenses").First(&item, "id = ?", id).Error; err != nil {
-		return nil, err
-	}
+// GetItemByBarcode devuelve nil si ningún item tiene ese código de barras.
+func (r *ItemRepository) GetItemByBarcode(ctx context.Context, barcode string) (*models.Item, error) {
+	return r.getItemByCode(ctx, "barcode", barcode)
+}
 
-	item.ItemState = state
+func (r *ItemRepository) getItemByCode(ctx context.Context, column string, code string) (*models.Item, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
 
-	if err := r.DB.Save(&item).Error; err != nil {
+	var items []models.Item
+	err := r.DB.WithContext(ctx).Where(column+" = ?", code).Limit(1).Find(&items).Error
+	if err != nil || len(items) == 0 {
 		return nil, err
 	}
-	return &item, nil
+	return &items[0], nil
 }
 
-func (r *ItemRepository) UpdateItem(item *models.Item) (bool, error) {
+// GetLowStockItems lista los items activos con stock en su nivel de reorden o menos, primero los que
+// más unidades les faltan. Los items con nivel 0 no cuentan.
+func (r *ItemRepository) GetLowStockItems(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Item, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
 
-	var existingItem models.Item
-	if err := r.DB.Preload("ItemType").Preload("AdditionalExpenses").First(&existingItem, "id = ?", item.ID).Error; err != nil {
-		return false, err
+	db := reader(r.DB, r.Replica).WithContext(ctx).Preload("ItemType").Preload("AdditionalExpenses").Preload("Images", orderItemImages).
+		Where("item_state = ? AND reorder_level > 0 AND stock <= reorder_level", true).
+		Order("stock - reorder_level")
+	return paginate[models.Item](db, pagination)
+}
+
+// StreamItems recorre en lotes los items creados dentro del rango de filter.
+func (r *ItemRepository) StreamItems(ctx context.Context, filter dtos.CSVExportFilterDTO, fn func(models.Item) error) error {
+	query := reader(r.DB, r.Replica).WithContext(ctx).Preload("ItemType")
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at <= ?", *filter.To)
 	}
 
-	priceChanged := existingItem.SellingPrice != item.SellingPrice
+	var batch []models.Item
+	result := query.Order("id").FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+		for _, item := range batch {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return result.Error
+}
 
-	existingItem.ItemTypeID = item.ItemTypeID
+// GetCatalogItems lista los items activos para el catálogo público. La búsqueda es por nombre y
+// descripción, sin distinguir mayúsculas ni tildes.
+func (r *ItemRepository) GetCatalogItems(ctx context.Context, filter dtos.PublicCatalogFilterDTO) ([]models.Item, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
 
-	if err := r.DB.Model(&existingItem).Updates(item).Error; err != nil {
-		return false, err
+	db := reader(r.DB, r.Replica).WithContext(ctx).Preload("ItemType").Where("item_state = ?", true)
+	if filter.Search != "" {
+		pattern := "%" + filter.Search + "%"
+		db = db.Where(r.DB.Where(unaccentPrefix("name"), pattern).Or(unaccentPrefix("description"), pattern))
 	}
-
-	if err := r.DB.Model(&existingItem).Select("ItemState").Updates(item).Error; err != nil {
-		return false, err
+	if filter.CategoryID != 0 {
+		db = db.Where("item_type_id = ?", filter.CategoryID)
 	}
-
-	return priceChanged, nil
+	return paginate[models.Item](db, filter.PaginationDTO)
 }
 
-func (r *ItemRepository) CreateItem(item *models.Item) (*models.Item, error) {
+// UpdateItem guarda el item si sigue en item.Version; devuelve false si cambió antes. Si el stock
+// cambia registra un movimiento con la diferencia, a partir de movement.
+func (r *ItemRepository) UpdateItem(ctx context.Context, item *models.Item, movement models.StockMovement) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var updated bool
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var previousStock int
+		if err := tx.Model(&models.Item{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("stock").Where("id = ?", item.ID).Scan(&previousStock).Error; err != nil {
+			return err
+		}
+
+		var err error
+		updated, err = saveVersioned(tx, item, &item.Version)
+		if err != nil || !updated || item.Stock == previousStock {
+			return err
+		}
+		movement.ItemID = item.ID
+		movement.Delta = item.Stock - previousStock
+		return recordStockMovement(tx, movement)
+	})
+	return updated, err
+}
 
-	if err := r.DB.Create(item).Error; err != nil {
+// CreateItem crea el item y, si empieza con stock, su primer movimiento a partir de movement.
+func (r *ItemRepository) CreateItem(ctx context.Context, item *models.Item, movement models.StockMovement) (*models.Item, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(item).Error; err != nil {
+			return err
+		}
+		if item.Stock == 0 {
+			return nil
+		}
+		movement.ItemID = item.ID
+		movement.Delta = item.Stock
+		return recordStockMovement(tx, movement)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return item, nil
 }
 
-func (r *ItemRepository) SubtractItemsFromInventory(itemID string, amount int) error {
-	if err := r.DB.Model(&models.Item{}).
-		Where("id = ?", itemID).
-		UpdateColumn("stock", gorm.Expr("stock - ?", amount)).Error; err != nil {
-		return err
-	}
-	return nil
-}
+// itemSearchName es el nombre del item tal como lo indexa la migración 39.
+const itemSearchName = "immutable_unaccent(items.name)"
 
-func (r *ItemRepository) ReturnItemsToInventory(itemID string, amount int) error {
-	if err := r.DB.Model(&models.Item{}).
-		Where("id = ?", itemID).
-		UpdateColumn("stock", gorm.Expr("stock + ?", amount)).Error; err != nil {
-		return err
-	}
-	return nil
+func orderItemImages(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
 }
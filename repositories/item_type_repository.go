@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,17 +16,71 @@ func NewItemTypeRepository(db *gorm.DB) *ItemTypeRepository {
 	return &ItemTypeRepository{DB: db}
 }
 
-func (r *ItemTypeRepository) GetAllItemTypes() ([]models.ItemType, error) {
-	var itemTypes []models.ItemType
-	err := r.DB.Find(&itemTypes).Error
-	return itemTypes, err
+func (r *ItemTypeRepository) GetAllItemTypes(ctx context.Context, pagination dtos.PaginationDTO) ([]models.ItemType, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return paginate[models.ItemType](r.DB.WithContext(ctx), pagination)
 }
 
-func (r *ItemTypeRepository) GetItemTypeByID(id string) (*models.ItemType, error) {
+func (r *ItemTypeRepository) GetItemTypeByID(ctx context.Context, id string) (*models.ItemType, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var itemType models.ItemType
-	err := r.DB.First(&itemType, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).First(&itemType, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &itemType, nil
 }
+
+// GetItemTypeByName compara sin distinguir mayúsculas; devuelve nil si no existe.
+func (r *ItemTypeRepository) GetItemTypeByName(ctx context.Context, name string) (*models.ItemType, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var itemTypes []models.ItemType
+	err := r.DB.WithContext(ctx).Where("LOWER(name) = LOWER(?)", name).Limit(1).Find(&itemTypes).Error
+	if err != nil || len(itemTypes) == 0 {
+		return nil, err
+	}
+	return &itemTypes[0], nil
+}
+
+func (r *ItemTypeRepository) CreateItemType(ctx context.Context, itemType *models.ItemType) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Create(itemType).Error
+}
+
+func (r *ItemTypeRepository) UpdateItemType(ctx context.Context, itemType *models.ItemType) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Save(itemType).Error
+}
+
+func (r *ItemTypeRepository) CountItemsWithType(ctx context.Context, id int) (int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var count int64
+	err := r.DB.WithContext(ctx).Model(&models.Item{}).Where("item_type_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+func (r *ItemTypeRepository) DeleteItemType(ctx context.Context, id int) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Delete(&models.ItemType{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LowStockAlertRepository struct {
+	DB *gorm.DB
+}
+
+func NewLowStockAlertRepository(db *gorm.DB) *LowStockAlertRepository {
+	return &LowStockAlertRepository{DB: db}
+}
+
+// ClearRecoveredAlerts borra los avisos de los items que ya no están bajos de stock, porque se
+// repusieron, se desactivaron o se les quitó el nivel de reorden. Si vuelven a bajar se avisa de nuevo.
+func (r *LowStockAlertRepository) ClearRecoveredAlerts(ctx context.Context) (int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).
+		Where("item_id IN (SELECT id FROM items WHERE item_state = ? OR reorder_level = 0 OR stock > reorder_level)", false).
+		Delete(&models.LowStockAlert{})
+	return result.RowsAffected, result.Error
+}
+
+// GetItemsPendingAlert devuelve los items bajos de stock de los que todavía no se avisó.
+func (r *LowStockAlertRepository) GetItemsPendingAlert(ctx context.Context) ([]models.Item, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var items []models.Item
+	err := r.DB.WithContext(ctx).
+		Where("item_state = ? AND reorder_level > 0 AND stock <= reorder_level", true).
+		Where("NOT EXISTS (SELECT 1 FROM low_stock_alerts WHERE low_stock_alerts.item_id = items.id)").
+		Order("stock - reorder_level, id").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *LowStockAlertRepository) CreateAlerts(ctx context.Context, alerts []models.LowStockAlert) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&alerts).Error
+}
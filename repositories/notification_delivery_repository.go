@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"totesbackend/dtos"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type NotificationDeliveryRepository struct {
+	DB *gorm.DB
+}
+
+func NewNotificationDeliveryRepository(db *gorm.DB) *NotificationDeliveryRepository {
+	return &NotificationDeliveryRepository{DB: db}
+}
+
+func (r *NotificationDeliveryRepository) SearchDeliveries(ctx context.Context, filter dtos.NotificationDeliveryFilterDTO) ([]models.NotificationDelivery, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	query := r.DB.WithContext(ctx).Model(&models.NotificationDelivery{})
+	if filter.Channel != "" {
+		query = query.Where("channel = ?", filter.Channel)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Recipient != "" {
+		query = query.Where("recipient ILIKE ?", "%"+filter.Recipient+"%")
+	}
+	if filter.MessageID != 0 {
+		query = query.Where("message_id = ?", filter.MessageID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at <= ?", *filter.To)
+	}
+	return paginate[models.NotificationDelivery](query.Order("id DESC"), filter.PaginationDTO)
+}
+
+func (r *NotificationDeliveryRepository) GetDeliveryByID(ctx context.Context, id int) (*models.NotificationDelivery, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var delivery models.NotificationDelivery
+	if err := r.DB.WithContext(ctx).First(&delivery, id).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
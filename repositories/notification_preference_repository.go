@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationPreferenceRepository struct {
+	DB *gorm.DB
+}
+
+func NewNotificationPreferenceRepository(db *gorm.DB) *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{DB: db}
+}
+
+func (r *NotificationPreferenceRepository) GetPreferences(ctx context.Context, recipientType string, recipientID int) ([]models.NotificationPreference, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var preferences []models.NotificationPreference
+	err := r.DB.WithContext(ctx).
+		Where("recipient_type = ? AND recipient_id = ?", recipientType, recipientID).
+		Find(&preferences).Error
+	return preferences, err
+}
+
+// GetPreference devuelve nil sin error cuando el destinatario no cambió esa preferencia.
+func (r *NotificationPreferenceRepository) GetPreference(ctx context.Context, recipientType string, recipientID int, eventType, channel string) (*models.NotificationPreference, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var preferences []models.NotificationPreference
+	err := r.DB.WithContext(ctx).
+		Where("recipient_type = ? AND recipient_id = ? AND event_type = ? AND channel = ?", recipientType, recipientID, eventType, channel).
+		Limit(1).
+		Find(&preferences).Error
+	if err != nil || len(preferences) == 0 {
+		return nil, err
+	}
+	return &preferences[0], nil
+}
+
+func (r *NotificationPreferenceRepository) GetPreferencesForEvent(ctx context.Context, recipientType string, recipientIDs []int, eventType, channel string) ([]models.NotificationPreference, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var preferences []models.NotificationPreference
+	err := r.DB.WithContext(ctx).
+		Where("recipient_type = ? AND recipient_id IN ? AND event_type = ? AND channel = ?", recipientType, recipientIDs, eventType, channel).
+		Find(&preferences).Error
+	return preferences, err
+}
+
+// SavePreferences inserta o actualiza cada preferencia según destinatario, evento y canal.
+func (r *NotificationPreferenceRepository) SavePreferences(ctx context.Context, preferences []models.NotificationPreference) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	if len(preferences) == 0 {
+		return nil
+	}
+	return r.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "recipient_type"}, {Name: "recipient_id"}, {Name: "event_type"}, {Name: "channel"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+	}).Create(&preferences).Error
+}
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type NotificationRepository struct {
+	DB *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{DB: db}
+}
+
+func (r *NotificationRepository) CreateNotifications(ctx context.Context, notifications []models.Notification) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.DB.WithContext(ctx).CreateInBatches(&notifications, 500).Error
+}
+
+// GetNotificationsByUser devuelve primero las más recientes.
+func (r *NotificationRepository) GetNotificationsByUser(ctx context.Context, userID int, unreadOnly bool, pagination dtos.PaginationDTO) ([]models.Notification, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	query := r.DB.WithContext(ctx).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	return paginate[models.Notification](query.Order("id DESC"), pagination)
+}
+
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID int) (int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var count int64
+	err := r.DB.WithContext(ctx).Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkAsRead devuelve gorm.ErrRecordNotFound si la notificación no existe o es de otro usuario.
+// Marcar una ya leída no cambia su fecha de lectura.
+func (r *NotificationRepository) MarkAsRead(ctx context.Context, userID, id int, readAt time.Time) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Model(&models.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", readAt))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *NotificationRepository) MarkAllAsRead(ctx context.Context, userID int, readAt time.Time) (int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", readAt)
+	return result.RowsAffected, result.Error
+}
+
+func (r *NotificationRepository) DeleteReadBefore(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Where("read_at < ?", before).Delete(&models.Notification{})
+	return result.RowsAffected, result.Error
+}
@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,20 +16,21 @@ func NewOrderStateTypeRepository(db *gorm.DB) *OrderStateTypeRepository {
 	return &OrderStateTypeRepository{DB: db}
 }
 
-func (r *OrderStateTypeRepository) GetOrderStateTypeByID(id string) (*models.OrderStateType, error) {
+func (r *OrderStateTypeRepository) GetOrderStateTypeByID(ctx context.Context, id string) (*models.OrderStateType, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var OrderStateType models.OrderStateType
-	err := r.DB.First(&OrderStateType, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).First(&OrderStateType, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &OrderStateType, nil
 }
 
-func (r *OrderStateTypeRepository) GetAllOrderStateTypes() ([]models.OrderStateType, error) {
-	var OrderStateTypes []models.OrderStateType
-	err := r.DB.Find(&OrderStateTypes).Error
-	if err != nil {
-		return nil, err
-	}
-	return OrderStateTypes, nil
+func (r *OrderStateTypeRepository) GetAllOrderStateTypes(ctx context.Context, pagination dtos.PaginationDTO) ([]models.OrderStateType, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return paginate[models.OrderStateType](r.DB.WithContext(ctx), pagination)
 }
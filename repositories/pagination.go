@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"totesbackend/dtos"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// paginate cuenta los registros que cumplen la consulta y carga solo la página pedida.
+// Se ordena por la clave primaria para que las páginas sean estables entre peticiones.
+func paginate[T any](query *gorm.DB, pagination dtos.PaginationDTO) ([]T, int64, error) {
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Model(new(T)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var records []T
+	err := query.
+		Order(clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: clause.PrimaryKey}}).
+		Offset(pagination.Offset()).
+		Limit(pagination.PageSize).
+		Find(&records).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return records, total, nil
+}
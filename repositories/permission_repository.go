@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,38 +16,37 @@ func NewPermissionRepository(db *gorm.DB) *PermissionRepository {
 	return &PermissionRepository{DB: db}
 }
 
-func (r *PermissionRepository) GetPermissionByID(id uint) (*models.Permission, error) {
+func (r *PermissionRepository) GetPermissionByID(ctx context.Context, id uint) (*models.Permission, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var permission models.Permission
-	err := r.DB.First(&permission, id).Error
+	err := r.DB.WithContext(ctx).First(&permission, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &permission, nil
 }
 
-func (r *PermissionRepository) SearchPermissionsByID(query string) ([]models.Permission, error) {
-	var permissions []models.Permission
-	err := r.DB.Where("CAST(id AS TEXT) LIKE ?", query+"%").Find(&permissions).Error
-	if err != nil {
-		return nil, err
-	}
-	return permissions, nil
+func (r *PermissionRepository) SearchPermissionsByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Permission, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Where("CAST(id AS TEXT) LIKE ?", query+"%")
+	return paginate[models.Permission](db, pagination)
 }
 
-func (r *PermissionRepository) SearchPermissionsByName(query string) ([]models.Permission, error) {
-	var permissions []models.Permission
-	err := r.DB.Where("LOWER(name) LIKE LOWER(?)", query+"%").Find(&permissions).Error
-	if err != nil {
-		return nil, err
-	}
-	return permissions, nil
+func (r *PermissionRepository) SearchPermissionsByName(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Permission, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Where("LOWER(name) LIKE LOWER(?)", query+"%")
+	return paginate[models.Permission](db, pagination)
 }
 
-func (r *PermissionRepository) GetAllPermissions() ([]models.Permission, error) {
-	var permissions []models.Permission
-	err := r.DB.Find(&permissions).Error
-	if err != nil {
-		return nil, err
-	}
-	return permissions, nil
+func (r *PermissionRepository) GetAllPermissions(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Permission, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return paginate[models.Permission](r.DB.WithContext(ctx), pagination)
 }
@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"context"
+	"totesbackend/dtos"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PriceListRepository struct {
+	DB *gorm.DB
+}
+
+func NewPriceListRepository(db *gorm.DB) *PriceListRepository {
+	return &PriceListRepository{DB: db}
+}
+
+func (r *PriceListRepository) GetPriceLists(ctx context.Context, pagination dtos.PaginationDTO) ([]models.PriceList, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return paginate[models.PriceList](r.DB.WithContext(ctx).Preload("Items", orderPriceListItems).Order("name"), pagination)
+}
+
+func (r *PriceListRepository) GetPriceListByID(ctx context.Context, id int) (*models.PriceList, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var priceList models.PriceList
+	err := r.DB.WithContext(ctx).Preload("Items", orderPriceListItems).Preload("Items.Item").First(&priceList, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &priceList, nil
+}
+
+// GetPriceListByName devuelve nil si ninguna lista tiene ese nombre.
+func (r *PriceListRepository) GetPriceListByName(ctx context.Context, name string) (*models.PriceList, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var priceLists []models.PriceList
+	err := r.DB.WithContext(ctx).Where("LOWER(name) = LOWER(?)", name).Limit(1).Find(&priceLists).Error
+	if err != nil || len(priceLists) == 0 {
+		return nil, err
+	}
+	return &priceLists[0], nil
+}
+
+// CreatePriceList crea la lista con sus precios en una transacción.
+func (r *PriceListRepository) CreatePriceList(ctx context.Context, priceList *models.PriceList) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(priceList).Error; err != nil {
+			return err
+		}
+		return savePriceListItems(tx, priceList)
+	})
+}
+
+// ReplacePriceList reemplaza el nombre, la descripción y todos los precios de la lista.
+func (r *PriceListRepository) ReplacePriceList(ctx context.Context, priceList *models.PriceList) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(priceList).Select("name", "description", "updated_at").Updates(priceList)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Where("price_list_id = ?", priceList.ID).Delete(&models.PriceListItem{}).Error; err != nil {
+			return err
+		}
+		return savePriceListItems(tx, priceList)
+	})
+}
+
+// DeletePriceList borra la lista; sus clientes vuelven a los precios normales.
+func (r *PriceListRepository) DeletePriceList(ctx context.Context, id int) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Customer{}).Unscoped().Where("price_list_id = ?", id).
+			Updates(map[string]interface{}{"price_list_id": nil, "version": nextVersion}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("price_list_id = ?", id).Delete(&models.PriceListItem{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.PriceList{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// SetCustomerPriceList asigna la lista al cliente; nil se la quita.
+func (r *PriceListRepository) SetCustomerPriceList(ctx context.Context, customerID int, priceListID *int) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Model(&models.Customer{}).Where("id = ?", customerID).
+		Updates(map[string]interface{}{"price_list_id": priceListID, "version": nextVersion})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetCustomerPrices devuelve los precios especiales de la lista del cliente por item; está vacío si
+// el cliente no tiene lista.
+func (r *PriceListRepository) GetCustomerPrices(ctx context.Context, customerID int) (map[int]float64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var items []models.PriceListItem
+	err := r.DB.WithContext(ctx).
+		Joins("JOIN customers ON customers.price_list_id = price_list_items.price_list_id").
+		Where("customers.id = ?", customerID).
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	prices := make(map[int]float64, len(items))
+	for _, item := range items {
+		prices[item.ItemID] = item.Price
+	}
+	return prices, nil
+}
+
+func savePriceListItems(tx *gorm.DB, priceList *models.PriceList) error {
+	if len(priceList.Items) == 0 {
+		return nil
+	}
+	for i := range priceList.Items {
+		priceList.Items[i].PriceListID = priceList.ID
+	}
+	return tx.Omit("Item").Create(&priceList.Items).Error
+}
+
+func orderPriceListItems(db *gorm.DB) *gorm.DB {
+	return db.Order("item_id")
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"errors"
 	"strconv"
 	"time"
@@ -8,21 +9,27 @@ import (
 	"totesbackend/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PurchaseOrderRepository struct {
 	DB *gorm.DB
+	// opcional: reportes, listados y búsquedas se leen de aquí
+	Replica *gorm.DB
 }
 
 func NewPurchaseOrderRepository(db *gorm.DB) *PurchaseOrderRepository {
 	return &PurchaseOrderRepository{DB: db}
 }
 
-func (r *PurchaseOrderRepository) GetPurchaseOrderByID(id string) (*models.PurchaseOrder, error) {
+func (r *PurchaseOrderRepository) GetPurchaseOrderByID(ctx context.Context, id string) (*models.PurchaseOrder, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var purchaseOrder models.PurchaseOrder
-	err := r.DB.Preload("Seller").
+	err := r.DB.WithContext(ctx).Preload("Seller").
 		Preload("Responsible").
-		Preload("Customer").
+		Preload("Customer", withDeletedCustomers).
 		Preload("OrderState").
 		Preload("Items.Item").
 		Preload("Discounts"). // Ahora sí debería funcionar
@@ -36,102 +43,94 @@ func (r *PurchaseOrderRepository) GetPurchaseOrderByID(id string) (*models.Purch
 	return &purchaseOrder, nil
 }
 
-func (r *PurchaseOrderRepository) GetPurchaseOrdersByStateID(stateID string) ([]models.PurchaseOrder, error) {
-	var purchaseOrders []models.PurchaseOrder
-	err := r.DB.Preload("Seller").
+func (r *PurchaseOrderRepository) GetPurchaseOrdersByStateID(ctx context.Context, stateID string, pagination dtos.PaginationDTO) ([]models.PurchaseOrder, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := reader(r.DB, r.Replica).WithContext(ctx).Preload("Seller").
 		Preload("Responsible").
-		Preload("Customer").
+		Preload("Customer", withDeletedCustomers).
 		Preload("OrderState").
 		Preload("Items.Item").
 		Preload("Discounts").
 		Preload("Taxes").
-		Where("order_state_id = ?", stateID).
-		Find(&purchaseOrders).Error
-
-	if err != nil {
-		return nil, err
-	}
-	return purchaseOrders, nil
+		Where("order_state_id = ?", stateID)
+	return paginate[models.PurchaseOrder](db, pagination)
 }
 
-func (r *PurchaseOrderRepository) GetPurchaseOrdersByCustomerID(customerID string) ([]models.PurchaseOrder, error) {
-	var purchaseOrders []models.PurchaseOrder
-	err := r.DB.Preload("Seller").
+func (r *PurchaseOrderRepository) GetPurchaseOrdersByCustomerID(ctx context.Context, customerID string, pagination dtos.PaginationDTO) ([]models.PurchaseOrder, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := reader(r.DB, r.Replica).WithContext(ctx).Preload("Seller").
 		Preload("Responsible").
-		Preload("Customer").
+		Preload("Customer", withDeletedCustomers).
 		Preload("OrderState").
 		Preload("Items.Item").
 		Preload("Discounts").
 		Preload("Taxes").
-		Where("CAST(customer_id AS TEXT) = ?", customerID).
-		Find(&purchaseOrders).Error
-
-	if err != nil {
-		return nil, err
-	}
-	return purchaseOrders, nil
+		Where("CAST(customer_id AS TEXT) = ?", customerID)
+	return paginate[models.PurchaseOrder](db, pagination)
 }
 
-func (r *PurchaseOrderRepository) GetPurchaseOrdersBySellerID(sellerID string) ([]models.PurchaseOrder, error) {
-	var purchaseOrders []models.PurchaseOrder
-	err := r.DB.Preload("Seller").
+func (r *PurchaseOrderRepository) GetPurchaseOrdersBySellerID(ctx context.Context, sellerID string, pagination dtos.PaginationDTO) ([]models.PurchaseOrder, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := reader(r.DB, r.Replica).WithContext(ctx).Preload("Seller").
 		Preload("Responsible").
-		Preload("Customer").
+		Preload("Customer", withDeletedCustomers).
 		Preload("OrderState").
 		Preload("Items.Item").
 		Preload("Discounts").
 		Preload("Taxes").
-		Where("CAST(seller_id AS TEXT) = ?", sellerID).
-		Find(&purchaseOrders).Error
-
-	if err != nil {
-		return nil, err
-	}
-	return purchaseOrders, nil
+		Where("CAST(seller_id AS TEXT) = ?", sellerID)
+	return paginate[models.PurchaseOrder](db, pagination)
 }
 
-func (r *PurchaseOrderRepository) GetAllPurchaseOrders() ([]models.PurchaseOrder, error) {
-	var purchaseOrders []models.PurchaseOrder
-	err := r.DB.Preload("Seller").
+func (r *PurchaseOrderRepository) GetAllPurchaseOrders(ctx context.Context, pagination dtos.PaginationDTO) ([]models.PurchaseOrder, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := reader(r.DB, r.Replica).WithContext(ctx).Preload("Seller").
 		Preload("Responsible").
-		Preload("Customer").
+		Preload("Customer", withDeletedCustomers).
 		Preload("OrderState").
 		Preload("Items.Item").
 		Preload("Discounts").
-		Preload("Taxes").
-		Find(&purchaseOrders).Error
-
+		Preload("Taxes")
+	purchaseOrders, total, err := paginate[models.PurchaseOrder](db, pagination)
 	if err != nil {
-		return nil, errors.New("error retrieving purchase orders")
+		return nil, 0, errors.New("error retrieving purchase orders")
 	}
-	return purchaseOrders, nil
+	return purchaseOrders, total, nil
 }
 
-func (r *PurchaseOrderRepository) SearchPurchaseOrdersByID(query string) ([]models.PurchaseOrder, error) {
-	var purchaseOrders []models.PurchaseOrder
-	err := r.DB.Preload("Seller").
+func (r *PurchaseOrderRepository) SearchPurchaseOrdersByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.PurchaseOrder, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := reader(r.DB, r.Replica).WithContext(ctx).Preload("Seller").
 		Preload("Responsible").
-		Preload("Customer").
+		Preload("Customer", withDeletedCustomers).
 		Preload("OrderState").
 		Preload("Items.Item").
 		Preload("Discounts").
 		Preload("Taxes").
-		Where("CAST(id AS TEXT) LIKE ?", query+"%").
-		Find(&purchaseOrders).Error
-
-	if err != nil {
-		return nil, err
-	}
-	return purchaseOrders, nil
+		Where("CAST(id AS TEXT) LIKE ?", query+"%")
+	return paginate[models.PurchaseOrder](db, pagination)
 }
 
-func (r *PurchaseOrderRepository) UpdatePurchaseOrder(purchaseOrder *models.PurchaseOrder) error {
+func (r *PurchaseOrderRepository) UpdatePurchaseOrder(ctx context.Context, purchaseOrder *models.PurchaseOrder) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var existingPurchaseOrder models.PurchaseOrder
 
 	// Preload completo de todas las relaciones relevantes
-	if err := r.DB.Preload("Seller").
+	if err := r.DB.WithContext(ctx).Preload("Seller").
 		Preload("Responsible").
-		Preload("Customer").
+		Preload("Customer", withDeletedCustomers).
 		Preload("OrderState").
 		Preload("Items.Item").
 		Preload("Discounts").
@@ -141,17 +140,20 @@ func (r *PurchaseOrderRepository) UpdatePurchaseOrder(purchaseOrder *models.Purc
 	}
 
 	// Actualización de la orden de compra
-	if err := r.DB.Model(&existingPurchaseOrder).Updates(purchaseOrder).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Model(&existingPurchaseOrder).Updates(purchaseOrder).Error; err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (r *PurchaseOrderRepository) CreatePurchaseOrder(dto *dtos.CreatePurchaseOrderDTO, subtotal float64, total float64) (*models.PurchaseOrder, error) {
+func (r *PurchaseOrderRepository) CreatePurchaseOrder(ctx context.Context, dto *dtos.CreatePurchaseOrderDTO, subtotal float64, total float64) (*models.PurchaseOrder, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	purchaseOrder := &models.PurchaseOrder{
 		SellerID:      nil,
-		CustomerID:    nil,
+		CustomerID:    dto.CustomerID,
 		ResponsibleID: nil,
 		DateTime:      time.Now(),
 		SubTotal:      subtotal,
@@ -159,7 +161,7 @@ func (r *PurchaseOrderRepository) CreatePurchaseOrder(dto *dtos.CreatePurchaseOr
 		OrderStateID:  1, // Estado inicial
 	}
 
-	tx := r.DB.Begin()
+	tx := r.DB.WithContext(ctx).Begin()
 	if tx.Error != nil {
 		return nil, tx.Error
 	}
@@ -191,18 +193,21 @@ func (r *PurchaseOrderRepository) CreatePurchaseOrder(dto *dtos.CreatePurchaseOr
 
 	// Cargar datos completos de la orden
 	var fullPurchaseOrder models.PurchaseOrder
-	if err := r.DB.Preload("Discounts").Preload("Taxes").Preload("Items.Item").First(&fullPurchaseOrder, purchaseOrder.ID).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Preload("Discounts").Preload("Taxes").Preload("Items.Item").First(&fullPurchaseOrder, purchaseOrder.ID).Error; err != nil {
 		return nil, err
 	}
 
 	return &fullPurchaseOrder, nil
 }
 
-func (r *PurchaseOrderRepository) ChangePurchaseOrderState(id string, state string) (*models.PurchaseOrder, error) {
+func (r *PurchaseOrderRepository) ChangePurchaseOrderState(ctx context.Context, id string, state string) (*models.PurchaseOrder, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var purchaseOrder models.PurchaseOrder
 
 	// Buscar solo por ID sin preloads inicialmente
-	if err := r.DB.First(&purchaseOrder, "id = ?", id).Error; err != nil {
+	if err := r.DB.WithContext(ctx).First(&purchaseOrder, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 
@@ -213,14 +218,14 @@ func (r *PurchaseOrderRepository) ChangePurchaseOrderState(id string, state stri
 	}
 
 	// Actualizar solo el campo 'order_state_id'
-	if err := r.DB.Model(&purchaseOrder).Update("order_state_id", stateInt).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Model(&purchaseOrder).Update("order_state_id", stateInt).Error; err != nil {
 		return nil, err
 	}
 
 	// Recargar la orden completa con sus relaciones
-	if err := r.DB.Preload("Seller").
+	if err := r.DB.WithContext(ctx).Preload("Seller").
 		Preload("Responsible").
-		Preload("Customer").
+		Preload("Customer", withDeletedCustomers).
 		Preload("OrderState").
 		Preload("Items.Item").
 		Preload("Discounts").
@@ -231,3 +236,73 @@ func (r *PurchaseOrderRepository) ChangePurchaseOrderState(id string, state stri
 
 	return &purchaseOrder, nil
 }
+
+// ChangePurchaseOrderStateWithStock pasa la orden de fromStateID a toStateID y mueve el stock de
+// sus items en la misma transacción, con la orden bloqueada: con subtract descuenta las unidades
+// solo si alcanzan (como CreateInvoice), si no las devuelve. Devuelve false si la orden ya no
+// estaba en fromStateID y los faltantes si algún item no alcanzó; en ambos casos no cambia nada.
+func (r *PurchaseOrderRepository) ChangePurchaseOrderStateWithStock(ctx context.Context, id, fromStateID, toStateID int, subtract bool, movement models.StockMovement) (bool, []dtos.StockShortageDTO, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var changed bool
+	var shortages []dtos.StockShortageDTO
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var purchaseOrder models.PurchaseOrder
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&purchaseOrder, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if purchaseOrder.OrderStateID != fromStateID {
+			return nil
+		}
+
+		// en orden de ID para que dos órdenes no se bloqueen mutuamente
+		var items []models.PurchaseOrderItem
+		if err := tx.Where("purchase_order_id = ?", id).Order("item_id").Find(&items).Error; err != nil {
+			return err
+		}
+		movement.ReferenceID = &purchaseOrder.ID
+		for _, item := range items {
+			movement.ItemID = item.ItemID
+			movement.Delta = item.Amount
+			if !subtract {
+				if err := applyStockMovement(tx, movement); err != nil {
+					return err
+				}
+				continue
+			}
+
+			movement.Delta = -item.Amount
+			result := tx.Model(&models.Item{}).
+				Where("id = ? AND stock >= ?", item.ItemID, item.Amount).
+				UpdateColumns(map[string]interface{}{"stock": gorm.Expr("stock - ?", item.Amount), "version": nextVersion})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				var available int
+				if err := tx.Model(&models.Item{}).Select("stock").Where("id = ?", item.ItemID).Scan(&available).Error; err != nil {
+					return err
+				}
+				shortages = append(shortages, dtos.StockShortageDTO{ItemID: item.ItemID, Requested: item.Amount, Available: available})
+				continue
+			}
+			if err := recordStockMovement(tx, movement); err != nil {
+				return err
+			}
+		}
+		if len(shortages) > 0 {
+			return errStockShortage
+		}
+
+		if err := tx.Model(&models.PurchaseOrder{}).Where("id = ?", id).Update("order_state_id", toStateID).Error; err != nil {
+			return err
+		}
+		changed = true
+		return nil
+	})
+	if errors.Is(err, errStockShortage) {
+		return false, shortages, nil
+	}
+	return changed, nil, err
+}
@@ -0,0 +1,12 @@
+package repositories
+
+import (
+	"context"
+	"totesbackend/config"
+)
+
+// queryContext limita la consulta a config.Get().Database.QueryTimeout. Como deriva del contexto de la
+// petición, la consulta también se cancela si el cliente se desconecta.
+func queryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, config.Get().Database.QueryTimeout)
+}
@@ -0,0 +1,219 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type QuotationRepository struct {
+	DB *gorm.DB
+	// opcional: reportes, listados y búsquedas se leen de aquí
+	Replica *gorm.DB
+}
+
+func NewQuotationRepository(db *gorm.DB) *QuotationRepository {
+	return &QuotationRepository{DB: db}
+}
+
+func (r *QuotationRepository) GetQuotationByID(ctx context.Context, id int) (*models.Quotation, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var quotation models.Quotation
+	err := r.DB.WithContext(ctx).Preload("Customer", withDeletedCustomers).
+		Preload("Items", orderQuotationItems).
+		Preload("Items.Item").
+		Preload("Discounts").
+		Preload("Taxes").
+		First(&quotation, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &quotation, nil
+}
+
+// GetQuotations lista las cotizaciones, las más recientes primero; state vacío y customerID nil no
+// filtran.
+func (r *QuotationRepository) GetQuotations(ctx context.Context, state string, customerID *int, pagination dtos.PaginationDTO) ([]models.Quotation, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := reader(r.DB, r.Replica).WithContext(ctx).Preload("Customer", withDeletedCustomers).
+		Preload("Items", orderQuotationItems).
+		Preload("Discounts").
+		Preload("Taxes").
+		Order("id DESC")
+	if state != "" {
+		db = db.Where("state = ?", state)
+	}
+	if customerID != nil {
+		db = db.Where("customer_id = ?", *customerID)
+	}
+	return paginate[models.Quotation](db, pagination)
+}
+
+// CreateQuotation crea la cotización con sus líneas, descuentos e impuestos en una transacción.
+func (r *QuotationRepository) CreateQuotation(ctx context.Context, quotation *models.Quotation) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(quotation).Error; err != nil {
+			return err
+		}
+		return saveQuotationLines(tx, quotation)
+	})
+}
+
+// ReplaceQuotation reemplaza los datos, las líneas, los descuentos y los impuestos de la cotización si
+// sigue en fromState; devuelve false si cambió de estado antes.
+func (r *QuotationRepository) ReplaceQuotation(ctx context.Context, quotation *models.Quotation, fromState string) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var updated bool
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Quotation{}).
+			Where("id = ? AND state = ?", quotation.ID, fromState).
+			Updates(map[string]interface{}{
+				"enterprise_data": quotation.EnterpriseData,
+				"customer_id":     quotation.CustomerID,
+				"subtotal":        quotation.Subtotal,
+				"total":           quotation.Total,
+				"expires_at":      quotation.ExpiresAt,
+				"currency":        quotation.Currency,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if err := tx.Where("quotation_id = ?", quotation.ID).Delete(&models.QuotationItem{}).Error; err != nil {
+			return err
+		}
+		if err := saveQuotationLines(tx, quotation); err != nil {
+			return err
+		}
+		updated = true
+		return nil
+	})
+	return updated, err
+}
+
+// SetQuotationState pasa la cotización a state si está en alguno de fromStates; devuelve false si no
+// estaba en ninguno.
+func (r *QuotationRepository) SetQuotationState(ctx context.Context, id int, fromStates []string, state string) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Model(&models.Quotation{}).
+		Where("id = ? AND state IN ?", id, fromStates).
+		Update("state", state)
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteQuotation borra la cotización con sus líneas si está en alguno de states; devuelve false si
+// no estaba en ninguno.
+func (r *QuotationRepository) DeleteQuotation(ctx context.Context, id int, states []string) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var deleted bool
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		quotation := models.Quotation{ID: id}
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND state IN ?", id, states).Limit(1).Find(&quotation)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if err := tx.Model(&quotation).Association("Discounts").Clear(); err != nil {
+			return err
+		}
+		if err := tx.Model(&quotation).Association("Taxes").Clear(); err != nil {
+			return err
+		}
+		if err := tx.Where("quotation_id = ?", id).Delete(&models.QuotationItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.Quotation{}, id).Error; err != nil {
+			return err
+		}
+		deleted = true
+		return nil
+	})
+	return deleted, err
+}
+
+// ConvertQuotation crea la factura de la cotización y la marca como convertida en una transacción.
+// La factura lleva los items, descuentos, impuestos y valores cotizados y descuenta el stock como
+// CreateInvoice; si algún item no alcanza no se hace nada y se devuelven los faltantes. Devuelve nil
+// sin faltantes si la cotización no estaba en fromState y gorm.ErrRecordNotFound si no existe.
+func (r *QuotationRepository) ConvertQuotation(ctx context.Context, id int, fromState, toState string, dueDate *time.Time,
+	movement models.StockMovement) (*models.Invoice, []dtos.StockShortageDTO, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var invoice *models.Invoice
+	var shortages []dtos.StockShortageDTO
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var quotation models.Quotation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&quotation, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if quotation.State != fromState {
+			return nil
+		}
+
+		dto := &dtos.CreateInvoiceDTO{EnterpriseData: quotation.EnterpriseData, CustomerID: quotation.CustomerID, Currency: quotation.Currency,
+			DueDate: dueDate}
+		var items []models.QuotationItem
+		if err := tx.Where("quotation_id = ?", id).Order("item_id").Find(&items).Error; err != nil {
+			return err
+		}
+		for _, item := range items {
+			dto.Items = append(dto.Items, dtos.BillingItemDTO{ID: item.ItemID, Stock: item.Amount})
+		}
+		if err := tx.Table("quotation_discounts").Where("quotation_id = ?", id).Pluck("discount_type_id", &dto.Discounts).Error; err != nil {
+			return err
+		}
+		if err := tx.Table("quotation_taxes").Where("quotation_id = ?", id).Pluck("tax_type_id", &dto.Taxes).Error; err != nil {
+			return err
+		}
+
+		var err error
+		invoice, shortages, err = createInvoice(tx, dto, quotation.Subtotal, quotation.Total, movement)
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.Quotation{}).Where("id = ?", id).
+			Updates(map[string]interface{}{"state": toState, "invoice_id": invoice.ID, "converted_at": movement.CreatedAt}).Error
+	})
+	if errors.Is(err, errStockShortage) {
+		return nil, shortages, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return invoice, nil, nil
+}
+
+// saveQuotationLines crea las líneas de la cotización y reemplaza sus descuentos e impuestos.
+func saveQuotationLines(tx *gorm.DB, quotation *models.Quotation) error {
+	for i := range quotation.Items {
+		quotation.Items[i].QuotationID = quotation.ID
+	}
+	if err := tx.Omit("Item").Create(&quotation.Items).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(quotation).Association("Discounts").Replace(quotation.Discounts); err != nil {
+		return err
+	}
+	return tx.Model(quotation).Association("Taxes").Replace(quotation.Taxes)
+}
+
+func orderQuotationItems(db *gorm.DB) *gorm.DB {
+	return db.Order("item_id")
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type RefreshTokenRepository struct {
+	DB *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{DB: db}
+}
+
+func (r *RefreshTokenRepository) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Create(token).Error
+}
+
+func (r *RefreshTokenRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var token models.RefreshToken
+	if err := r.DB.WithContext(ctx).First(&token, "token_hash = ?", hash).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RevokeRefreshToken revoca el token si aún no lo estaba. Devuelve false si ya estaba revocado,
+// así dos refrescos simultáneos con el mismo token no emiten dos sesiones.
+func (r *RefreshTokenRepository) RevokeRefreshToken(ctx context.Context, id int) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *RefreshTokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID int) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// DeleteExpiredRefreshTokens borra los tokens vencidos antes de before, revocados o no.
+func (r *RefreshTokenRepository) DeleteExpiredRefreshTokens(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Where("expires_at < ?", before).Delete(&models.RefreshToken{})
+	return result.RowsAffected, result.Error
+}
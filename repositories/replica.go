@@ -0,0 +1,13 @@
+package repositories
+
+import "gorm.io/gorm"
+
+// reader elige la conexión para las consultas pesadas de solo lectura (reportes, listados y
+// búsquedas): la réplica cuando hay una configurada, la primaria en caso contrario. Las lecturas
+// que preceden a una escritura y las que corren dentro de una transacción siguen usando r.DB.
+func reader(primary, replica *gorm.DB) *gorm.DB {
+	if replica != nil {
+		return replica
+	}
+	return primary
+}
@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RestockOrderRepository struct {
+	DB *gorm.DB
+	// opcional: reportes, listados y búsquedas se leen de aquí
+	Replica *gorm.DB
+}
+
+func NewRestockOrderRepository(db *gorm.DB) *RestockOrderRepository {
+	return &RestockOrderRepository{DB: db}
+}
+
+func (r *RestockOrderRepository) GetRestockOrderByID(ctx context.Context, id int) (*models.RestockOrder, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var order models.RestockOrder
+	err := r.DB.WithContext(ctx).Preload("Items", orderRestockItems).Preload("Items.Item").First(&order, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// GetRestockOrders lista los pedidos, los más recientes primero; state vacío no filtra.
+func (r *RestockOrderRepository) GetRestockOrders(ctx context.Context, state string, pagination dtos.PaginationDTO) ([]models.RestockOrder, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := reader(r.DB, r.Replica).WithContext(ctx).Preload("Items", orderRestockItems).Order("id DESC")
+	if state != "" {
+		db = db.Where("state = ?", state)
+	}
+	return paginate[models.RestockOrder](db, pagination)
+}
+
+func (r *RestockOrderRepository) CreateRestockOrder(ctx context.Context, order *models.RestockOrder) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Create(order).Error
+}
+
+// ReplaceRestockOrder reemplaza los datos y las líneas del pedido si sigue en fromState; devuelve
+// false si cambió de estado antes.
+func (r *RestockOrderRepository) ReplaceRestockOrder(ctx context.Context, order *models.RestockOrder, fromState string) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var updated bool
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.RestockOrder{}).
+			Where("id = ? AND state = ?", order.ID, fromState).
+			Updates(map[string]interface{}{"supplier_id": order.SupplierID, "supplier_name": order.SupplierName, "notes": order.Notes, "total": order.Total})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if err := tx.Where("restock_order_id = ?", order.ID).Delete(&models.RestockOrderItem{}).Error; err != nil {
+			return err
+		}
+		for i := range order.Items {
+			order.Items[i].ID = 0
+			order.Items[i].RestockOrderID = order.ID
+		}
+		if err := tx.Omit("Item").Create(&order.Items).Error; err != nil {
+			return err
+		}
+		updated = true
+		return nil
+	})
+	return updated, err
+}
+
+// SetRestockOrderState pasa el pedido a state si está en alguno de fromStates y guarda la fecha en
+// timeColumn; devuelve false si no estaba en ninguno.
+func (r *RestockOrderRepository) SetRestockOrderState(ctx context.Context, id int, fromStates []string, state, timeColumn string, at time.Time) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Model(&models.RestockOrder{}).
+		Where("id = ? AND state IN ?", id, fromStates).
+		Updates(map[string]interface{}{"state": state, timeColumn: at})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ReceiveRestockOrderItems suma las cantidades recibidas (item -> cantidad) a las líneas del pedido
+// y al stock, con un movimiento por item a partir de movement, en una transacción. El pedido debe
+// estar en fromState y ninguna línea puede pasar de lo pedido; si no, no aplica nada y devuelve
+// false. Cuando todas las líneas quedan completas el pedido pasa a completeState.
+func (r *RestockOrderRepository) ReceiveRestockOrderItems(ctx context.Context, id int, received map[int]int, movement models.StockMovement,
+	fromState, completeState string) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var applied bool
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var order models.RestockOrder
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND state = ?", id, fromState).Limit(1).Find(&order)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		var items []models.RestockOrderItem
+		if err := tx.Where("restock_order_id = ?", id).Order("item_id").Find(&items).Error; err != nil {
+			return err
+		}
+		complete := true
+		for i, item := range items {
+			quantity := received[item.ItemID]
+			if item.ReceivedQuantity+quantity > item.Quantity {
+				return nil
+			}
+			items[i].ReceivedQuantity += quantity
+			if items[i].ReceivedQuantity < item.Quantity {
+				complete = false
+			}
+		}
+
+		movement.ReferenceID = &id
+		for _, item := range items {
+			quantity := received[item.ItemID]
+			if quantity == 0 {
+				continue
+			}
+			if err := tx.Model(&models.RestockOrderItem{}).Where("id = ?", item.ID).
+				Update("received_quantity", item.ReceivedQuantity).Error; err != nil {
+				return err
+			}
+			movement.ItemID = item.ItemID
+			movement.Delta = quantity
+			if err := applyStockMovement(tx, movement); err != nil {
+				return err
+			}
+		}
+
+		if complete {
+			if err := tx.Model(&models.RestockOrder{}).Where("id = ?", id).
+				Updates(map[string]interface{}{"state": completeState, "received_at": movement.CreatedAt}).Error; err != nil {
+				return err
+			}
+		}
+		applied = true
+		return nil
+	})
+	return applied, err
+}
+
+func orderRestockItems(db *gorm.DB) *gorm.DB {
+	return db.Order("item_id")
+}
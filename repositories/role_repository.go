@@ -1,9 +1,12 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type RoleRepository struct {
@@ -14,27 +17,32 @@ func NewRoleRepository(db *gorm.DB) *RoleRepository {
 	return &RoleRepository{DB: db}
 }
 
-func (r *RoleRepository) GetAllRoles() ([]models.Role, error) {
-	var roles []models.Role
-	err := r.DB.Preload("Permissions").Find(&roles).Error
-	if err != nil {
-		return nil, err
-	}
-	return roles, nil
+func (r *RoleRepository) GetAllRoles(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Role, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Preload("Permissions")
+	return paginate[models.Role](db, pagination)
 }
 
-func (r *RoleRepository) GetRoleByID(id uint) (*models.Role, error) {
+func (r *RoleRepository) GetRoleByID(ctx context.Context, id uint) (*models.Role, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var role models.Role
-	err := r.DB.Preload("Permissions").First(&role, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).Preload("Permissions").First(&role, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &role, nil
 }
 
-func (r *RoleRepository) GetRolePermissions(roleID uint) ([]uint, error) {
+func (r *RoleRepository) GetRolePermissions(ctx context.Context, roleID uint) ([]uint, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var permissionIDs []uint
-	err := r.DB.Table("role_permission").Select("permission_id").
+	err := r.DB.WithContext(ctx).Table("role_permission").Select("permission_id").
 		Where("role_id = ?", roleID).
 		Pluck("permission_id", &permissionIDs).Error
 	if err != nil {
@@ -43,44 +51,147 @@ func (r *RoleRepository) GetRolePermissions(roleID uint) ([]uint, error) {
 	return permissionIDs, nil
 }
 
-func (r *RoleRepository) GetAllPermissionsOfRole(roleID uint) ([]models.Permission, error) {
-	var permissions []models.Permission
-	err := r.DB.Joins("JOIN role_permission rp ON permissions.id = rp.permission_id").
-		Where("rp.role_id = ?", roleID).
-		Find(&permissions).Error
-	if err != nil {
-		return nil, err
-	}
-	return permissions, nil
+func (r *RoleRepository) GetAllPermissionsOfRole(ctx context.Context, roleID uint, pagination dtos.PaginationDTO) ([]models.Permission, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Joins("JOIN role_permission rp ON permissions.id = rp.permission_id").
+		Where("rp.role_id = ?", roleID)
+	return paginate[models.Permission](db, pagination)
 }
 
-func (r *RoleRepository) ExistRole(roleID uint) (bool, error) {
+func (r *RoleRepository) ExistRole(ctx context.Context, roleID uint) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var count int64
-	err := r.DB.Table("roles").Where("id = ?", roleID).Count(&count).Error
+	err := r.DB.WithContext(ctx).Table("roles").Where("id = ?", roleID).Count(&count).Error
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
 
-func (r *RoleRepository) SearchRolesByID(query string) ([]models.Role, error) {
+func (r *RoleRepository) SearchRolesByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Role, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Where("CAST(id AS TEXT) LIKE ?", query+"%")
+	return paginate[models.Role](db, pagination)
+}
+
+func (r *RoleRepository) SearchRolesByName(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Role, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Where("LOWER(name) LIKE LOWER(?)", query+"%")
+	return paginate[models.Role](db, pagination)
+}
+
+// GetRoleByName devuelve nil si ningún rol tiene ese nombre (sin distinguir mayúsculas).
+func (r *RoleRepository) GetRoleByName(ctx context.Context, name string) (*models.Role, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var roles []models.Role
-	err := r.DB.
-		Where("CAST(id AS TEXT) LIKE ?", query+"%").
-		Find(&roles).Error
-	if err != nil {
+	err := r.DB.WithContext(ctx).Where("LOWER(name) = LOWER(?)", name).Limit(1).Find(&roles).Error
+	if err != nil || len(roles) == 0 {
 		return nil, err
 	}
-	return roles, nil
+	return &roles[0], nil
 }
 
-func (r *RoleRepository) SearchRolesByName(query string) ([]models.Role, error) {
-	var roles []models.Role
-	err := r.DB.
-		Where("LOWER(name) LIKE LOWER(?)", query+"%").
-		Find(&roles).Error
-	if err != nil {
-		return nil, err
+// GetExistingPermissionIDs devuelve cuáles de permissionIDs existen.
+func (r *RoleRepository) GetExistingPermissionIDs(ctx context.Context, permissionIDs []uint) ([]uint, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var ids []uint
+	err := r.DB.WithContext(ctx).Model(&models.Permission{}).Where("id IN ?", permissionIDs).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// CreateRole crea el rol con sus permisos en una transacción.
+func (r *RoleRepository) CreateRole(ctx context.Context, role *models.Role, permissionIDs []uint) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Permissions").Create(role).Error; err != nil {
+			return err
+		}
+		return replaceRolePermissions(tx, role.ID, permissionIDs)
+	})
+}
+
+// UpdateRole cambia el nombre y la descripción del rol; sus permisos no se tocan.
+func (r *RoleRepository) UpdateRole(ctx context.Context, role *models.Role) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Model(&models.Role{}).Where("id = ?", role.ID).
+		Select("name", "description").Updates(role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// SetRolePermissions reemplaza los permisos del rol por permissionIDs en una transacción.
+func (r *RoleRepository) SetRolePermissions(ctx context.Context, roleID uint, permissionIDs []uint) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// el bloqueo ordena los cambios concurrentes sobre el mismo rol
+		var role models.Role
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&role, "id = ?", roleID).Error; err != nil {
+			return err
+		}
+		return replaceRolePermissions(tx, roleID, permissionIDs)
+	})
+}
+
+// DeleteRole borra el rol y sus permisos si ningún tipo de usuario lo tiene. El rol queda bloqueado
+// mientras se cuentan, así que nadie puede asignarlo entre la revisión y el borrado. Si no lo borra
+// devuelve cuántos tipos de usuario lo tienen; si no existe, gorm.ErrRecordNotFound.
+func (r *RoleRepository) DeleteRole(ctx context.Context, roleID uint) (int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var usage int64
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var role models.Role
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&role, roleID).Error; err != nil {
+			return err
+		}
+		if err := tx.Table("user_type_has_role").Where("role_id = ?", roleID).Count(&usage).Error; err != nil {
+			return err
+		}
+		if usage > 0 {
+			return nil
+		}
+		if err := tx.Exec("DELETE FROM role_permission WHERE role_id = ?", roleID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Role{}, roleID).Error
+	})
+	return usage, err
+}
+
+func replaceRolePermissions(tx *gorm.DB, roleID uint, permissionIDs []uint) error {
+	if err := tx.Exec("DELETE FROM role_permission WHERE role_id = ?", roleID).Error; err != nil {
+		return err
+	}
+	if len(permissionIDs) == 0 {
+		return nil
+	}
+	rows := make([]map[string]interface{}, len(permissionIDs))
+	for i, permissionID := range permissionIDs {
+		rows[i] = map[string]interface{}{"role_id": roleID, "permission_id": permissionID}
 	}
-	return roles, nil
+	return tx.Table("role_permission").Create(&rows).Error
 }
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type ScheduledJobRepository struct {
+	DB *gorm.DB
+}
+
+func NewScheduledJobRepository(db *gorm.DB) *ScheduledJobRepository {
+	return &ScheduledJobRepository{DB: db}
+}
+
+// EnsureJob crea el registro del trabajo si no existe. Si cambió la expresión se guarda la nueva
+// y se recalcula la próxima corrida.
+func (r *ScheduledJobRepository) EnsureJob(ctx context.Context, name, schedule string, nextRunAt time.Time) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	job := models.ScheduledJob{Name: name}
+	if err := r.DB.WithContext(ctx).Where(models.ScheduledJob{Name: name}).
+		Attrs(models.ScheduledJob{Schedule: schedule, NextRunAt: nextRunAt}).
+		FirstOrCreate(&job).Error; err != nil {
+		return err
+	}
+	if job.Schedule == schedule {
+		return nil
+	}
+	return r.DB.WithContext(ctx).Model(&models.ScheduledJob{}).Where("name = ?", name).
+		Updates(map[string]interface{}{"schedule": schedule, "next_run_at": nextRunAt}).Error
+}
+
+// ClaimJob adelanta next_run_at a nextRunAt solo si la corrida ya venció. Devuelve true si esta
+// instancia se quedó con la corrida.
+func (r *ScheduledJobRepository) ClaimJob(ctx context.Context, name string, now, nextRunAt time.Time, runBy string) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Model(&models.ScheduledJob{}).
+		Where("name = ? AND next_run_at <= ?", name, now).
+		Updates(map[string]interface{}{
+			"next_run_at":     nextRunAt,
+			"last_started_at": now,
+			"last_status":     "running",
+			"last_run_by":     runBy,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *ScheduledJobRepository) FinishJob(ctx context.Context, name, status, result string, finishedAt time.Time, duration time.Duration) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Model(&models.ScheduledJob{}).Where("name = ?", name).
+		Updates(map[string]interface{}{
+			"last_status":      status,
+			"last_result":      result,
+			"last_finished_at": finishedAt,
+			"last_duration_ms": duration.Milliseconds(),
+		}).Error
+}
+
+func (r *ScheduledJobRepository) GetAllJobs(ctx context.Context) ([]models.ScheduledJob, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var jobs []models.ScheduledJob
+	err := r.DB.WithContext(ctx).Order("name").Find(&jobs).Error
+	return jobs, err
+}
@@ -0,0 +1,7 @@
+package repositories
+
+// unaccentPrefix es la condición de las búsquedas por nombre: prefijo sin distinguir mayúsculas ni
+// tildes, así "lopez" encuentra "López". Usa la extensión unaccent (migración 12).
+func unaccentPrefix(column string) string {
+	return "unaccent(" + column + ") ILIKE unaccent(?)"
+}
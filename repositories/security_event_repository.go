@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+// Llave del advisory lock que serializa las inserciones de la cadena de eventos
+const securityEventLockKey = 3685
+
+type SecurityEventRepository struct {
+	DB *gorm.DB
+}
+
+func NewSecurityEventRepository(db *gorm.DB) *SecurityEventRepository {
+	return &SecurityEventRepository{DB: db}
+}
+
+// AppendSecurityEvent enlaza el evento con el último de la cadena y lo guarda. buildHash recibe
+// el hash anterior y devuelve el del nuevo evento; todo ocurre bajo un lock para que dos
+// inserciones simultáneas no tomen el mismo hash anterior.
+func (r *SecurityEventRepository) AppendSecurityEvent(ctx context.Context, event *models.SecurityEvent, buildHash func(prevHash string) string) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", securityEventLockKey).Error; err != nil {
+			return err
+		}
+
+		var last models.SecurityEvent
+		err := tx.Order("id DESC").First(&last).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		event.PrevHash = last.Hash
+		event.Hash = buildHash(event.PrevHash)
+		return tx.Create(event).Error
+	})
+}
+
+func (r *SecurityEventRepository) SearchSecurityEvents(ctx context.Context, filter dtos.SecurityEventFilterDTO) ([]models.SecurityEvent, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	query := r.DB.WithContext(ctx).Model(&models.SecurityEvent{})
+	if filter.EventType != "" {
+		query = query.Where("event_type = ?", filter.EventType)
+	}
+	if filter.User != "" {
+		query = query.Where("user_email ILIKE ?", "%"+filter.User+"%")
+	}
+	if filter.From != nil {
+		query = query.Where("date_time >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("date_time <= ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var events []models.SecurityEvent
+	err := query.Order("id DESC").
+		Offset(filter.Offset()).
+		Limit(filter.PageSize).
+		Find(&events).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// StreamSecurityEvents recorre la cadena completa en orden de inserción.
+func (r *SecurityEventRepository) StreamSecurityEvents(ctx context.Context, fn func(models.SecurityEvent) error) error {
+	var batch []models.SecurityEvent
+	return r.DB.WithContext(ctx).Order("id").FindInBatches(&batch, 1000, func(tx *gorm.DB, _ int) error {
+		for _, event := range batch {
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+// DeleteSecurityEventsBefore borra los eventos anteriores a la fecha límite de retención.
+func (r *SecurityEventRepository) DeleteSecurityEventsBefore(ctx context.Context, limit time.Time) (int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Where("date_time < ?", limit).Delete(&models.SecurityEvent{})
+	return result.RowsAffected, result.Error
+}
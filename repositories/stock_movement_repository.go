@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"totesbackend/dtos"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type StockMovementRepository struct {
+	DB *gorm.DB
+	// opcional: reportes, listados y búsquedas se leen de aquí
+	Replica *gorm.DB
+}
+
+func NewStockMovementRepository(db *gorm.DB) *StockMovementRepository {
+	return &StockMovementRepository{DB: db}
+}
+
+func (r *StockMovementRepository) GetStockMovements(ctx context.Context, filter dtos.StockMovementFilterDTO) ([]models.StockMovement, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := reader(r.DB, r.Replica).WithContext(ctx).Order("created_at DESC, id DESC")
+	if filter.ItemID != nil {
+		db = db.Where("item_id = ?", *filter.ItemID)
+	}
+	if filter.Reason != "" {
+		db = db.Where("reason = ?", filter.Reason)
+	}
+	if filter.From != nil {
+		db = db.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		db = db.Where("created_at <= ?", *filter.To)
+	}
+	return paginate[models.StockMovement](db, filter.PaginationDTO)
+}
+
+// GetStockBalance devuelve el stock del item junto con la suma de sus movimientos.
+func (r *StockMovementRepository) GetStockBalance(ctx context.Context, itemID int) (*dtos.StockBalanceDTO, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var balance dtos.StockBalanceDTO
+	result := r.DB.WithContext(ctx).Raw(`
+		SELECT items.id AS item_id, items.name, items.stock AS item_stock,
+			(SELECT COALESCE(SUM(delta), 0) FROM stock_movements WHERE item_id = items.id) AS ledger_stock
+		FROM items WHERE items.id = ?`, itemID).Scan(&balance)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &balance, nil
+}
+
+// GetStockDiscrepancies lista los items cuyo stock no coincide con la suma de sus movimientos, por
+// ejemplo porque se editó la tabla items a mano.
+func (r *StockMovementRepository) GetStockDiscrepancies(ctx context.Context) ([]dtos.StockBalanceDTO, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var balances []dtos.StockBalanceDTO
+	err := reader(r.DB, r.Replica).WithContext(ctx).Raw(`
+		SELECT items.id AS item_id, items.name, items.stock AS item_stock, COALESCE(ledger.stock, 0) AS ledger_stock
+		FROM items
+		LEFT JOIN (SELECT item_id, SUM(delta) AS stock FROM stock_movements GROUP BY item_id) ledger ON ledger.item_id = items.id
+		WHERE items.stock <> COALESCE(ledger.stock, 0)
+		ORDER BY items.id`).Scan(&balances).Error
+	return balances, err
+}
+
+// errStockShortage deshace una transacción que descuenta stock cuando algún item no alcanza; quien
+// la abrió devuelve los faltantes sin error.
+var errStockShortage = errors.New("insufficient stock")
+
+// applyStockMovement suma movement.Delta al stock del item y registra el movimiento, dentro de tx.
+func applyStockMovement(tx *gorm.DB, movement models.StockMovement) error {
+	if err := tx.Model(&models.Item{}).
+		Where("id = ?", movement.ItemID).
+		UpdateColumns(map[string]interface{}{"stock": gorm.Expr("stock + ?", movement.Delta), "version": nextVersion}).Error; err != nil {
+		return err
+	}
+	return recordStockMovement(tx, movement)
+}
+
+// recordStockMovement registra movement dentro de tx, que ya debe haber aplicado el cambio al
+// item: el saldo del movimiento es el stock que el item tiene en ese momento.
+func recordStockMovement(tx *gorm.DB, movement models.StockMovement) error {
+	if err := tx.Model(&models.Item{}).Select("stock").Where("id = ?", movement.ItemID).Scan(&movement.Stock).Error; err != nil {
+		return err
+	}
+	movement.ID = 0
+	return tx.Create(&movement).Error
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"totesbackend/dtos"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type SupplierRepository struct {
+	DB *gorm.DB
+	// opcional: reportes, listados y búsquedas se leen de aquí
+	Replica *gorm.DB
+}
+
+func NewSupplierRepository(db *gorm.DB) *SupplierRepository {
+	return &SupplierRepository{DB: db}
+}
+
+func (r *SupplierRepository) GetAllSuppliers(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Supplier, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return paginate[models.Supplier](reader(r.DB, r.Replica).WithContext(ctx).Order("name"), pagination)
+}
+
+func (r *SupplierRepository) GetSupplierByID(ctx context.Context, id int) (*models.Supplier, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var supplier models.Supplier
+	if err := r.DB.WithContext(ctx).First(&supplier, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &supplier, nil
+}
+
+// GetSupplierByTaxID devuelve nil si ningún proveedor tiene ese NIT.
+func (r *SupplierRepository) GetSupplierByTaxID(ctx context.Context, taxID string) (*models.Supplier, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var suppliers []models.Supplier
+	err := r.DB.WithContext(ctx).Where("tax_id = ?", taxID).Limit(1).Find(&suppliers).Error
+	if err != nil || len(suppliers) == 0 {
+		return nil, err
+	}
+	return &suppliers[0], nil
+}
+
+// SearchSuppliersByID busca por el comienzo del ID interno o del NIT.
+func (r *SupplierRepository) SearchSuppliersByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Supplier, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := reader(r.DB, r.Replica).WithContext(ctx).
+		Where("CAST(id AS TEXT) LIKE ? OR tax_id ILIKE ?", query+"%", query+"%").Order("name")
+	return paginate[models.Supplier](db, pagination)
+}
+
+func (r *SupplierRepository) SearchSuppliersByName(ctx context.Context, name string, pagination dtos.PaginationDTO) ([]models.Supplier, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := reader(r.DB, r.Replica).WithContext(ctx).Where(unaccentPrefix("name"), name+"%").Order("name")
+	return paginate[models.Supplier](db, pagination)
+}
+
+func (r *SupplierRepository) CreateSupplier(ctx context.Context, supplier *models.Supplier) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Create(supplier).Error
+}
+
+func (r *SupplierRepository) UpdateSupplier(ctx context.Context, supplier *models.Supplier) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Save(supplier).Error
+}
+
+// CountSupplierUsage cuenta los gastos adicionales y pedidos de reposición del proveedor.
+func (r *SupplierRepository) CountSupplierUsage(ctx context.Context, id int) (int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var count int64
+	err := r.DB.WithContext(ctx).Raw(`
+		SELECT
+			(SELECT COUNT(*) FROM additional_expenses WHERE supplier_id = @id) +
+			(SELECT COUNT(*) FROM restock_orders WHERE supplier_id = @id)`,
+		sql.Named("id", id)).Scan(&count).Error
+	return count, err
+}
+
+func (r *SupplierRepository) DeleteSupplier(ctx context.Context, id int) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Delete(&models.Supplier{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
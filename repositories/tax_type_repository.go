@@ -1,9 +1,13 @@
 package repositories
 
 import (
+	"context"
+	"time"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TaxTypeRepository struct {
@@ -14,21 +18,88 @@ func NewTaxTypeRepository(db *gorm.DB) *TaxTypeRepository {
 	return &TaxTypeRepository{DB: db}
 }
 
-func (r *TaxTypeRepository) GetAllTaxTypes() ([]models.TaxType, error) {
-	var taxTypes []models.TaxType
-	err := r.DB.Find(&taxTypes).Error
-	return taxTypes, err
+func (r *TaxTypeRepository) GetAllTaxTypes(ctx context.Context, pagination dtos.PaginationDTO) ([]models.TaxType, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return paginate[models.TaxType](r.DB.WithContext(ctx), pagination)
 }
 
-func (r *TaxTypeRepository) GetTaxTypeByID(id string) (*models.TaxType, error) {
+func (r *TaxTypeRepository) GetTaxTypeByID(ctx context.Context, id string) (*models.TaxType, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var taxType models.TaxType
-	err := r.DB.First(&taxType, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).First(&taxType, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &taxType, nil
 }
 
-func (r *TaxTypeRepository) CreateTaxType(taxType *models.TaxType) error {
-	return r.DB.Create(taxType).Error
+func (r *TaxTypeRepository) CreateTaxType(ctx context.Context, taxType *models.TaxType) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Create(taxType).Error
+}
+
+func (r *TaxTypeRepository) CreateTaxTypes(ctx context.Context, taxTypes []models.TaxType) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.Create(&taxTypes).Error
+	})
+}
+
+// UpdateTaxType guarda el impuesto; si cambia la tarifa, la anterior queda en el historial vigente
+// hasta ahora, así las facturas ya emitidas conservan la tarifa con que se emitieron.
+func (r *TaxTypeRepository) UpdateTaxType(ctx context.Context, taxType *models.TaxType) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current models.TaxType
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, taxType.ID).Error; err != nil {
+			return err
+		}
+		if current.Value != taxType.Value || current.IsPercentage != taxType.IsPercentage {
+			rate := models.TaxTypeRate{
+				TaxTypeID:    current.ID,
+				IsPercentage: current.IsPercentage,
+				Value:        current.Value,
+				ValidUntil:   time.Now(),
+			}
+			if err := tx.Create(&rate).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(taxType).Select("name", "description", "is_percentage", "value", "active").Updates(taxType).Error
+	})
+}
+
+// DeactivateTaxType desactiva el impuesto sin borrarlo; las facturas que ya lo aplicaron lo conservan.
+func (r *TaxTypeRepository) DeactivateTaxType(ctx context.Context, id string) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Model(&models.TaxType{}).Where("id = ?", id).Update("active", false)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetTaxTypeRates lista las tarifas anteriores del impuesto, de la más reciente a la más antigua.
+func (r *TaxTypeRepository) GetTaxTypeRates(ctx context.Context, id string) ([]models.TaxTypeRate, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var rates []models.TaxTypeRate
+	err := r.DB.WithContext(ctx).Where("tax_type_id = ?", id).Order("valid_until DESC").Find(&rates).Error
+	return rates, err
 }
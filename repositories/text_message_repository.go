@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type TextMessageRepository struct {
+	DB *gorm.DB
+}
+
+func NewTextMessageRepository(db *gorm.DB) *TextMessageRepository {
+	return &TextMessageRepository{DB: db}
+}
+
+func (r *TextMessageRepository) CreateMessage(ctx context.Context, message *models.TextMessage) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Create(message).Error
+}
+
+// ClaimDueMessages toma hasta limit mensajes vencidos y corre su next_attempt_at a leaseUntil, así
+// otra instancia no los envía mientras esta lo intenta. SKIP LOCKED evita que dos instancias se
+// queden esperando por las mismas filas.
+func (r *TextMessageRepository) ClaimDueMessages(ctx context.Context, status string, now, leaseUntil time.Time, limit int) ([]models.TextMessage, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var messages []models.TextMessage
+	err := r.DB.WithContext(ctx).Raw(`
+		UPDATE text_messages SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM text_messages
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, leaseUntil, status, now, limit).Scan(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// SaveAttempt guarda el estado del mensaje tras un intento de envío junto con el registro del intento.
+func (r *TextMessageRepository) SaveAttempt(ctx context.Context, message *models.TextMessage, delivery *models.NotificationDelivery) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(message).Error; err != nil {
+			return err
+		}
+		return tx.Create(delivery).Error
+	})
+}
+
+// RequeueMessage pasa el mensaje de fromStatus a toStatus con los intentos en cero, para que se
+// envíe de nuevo en now. Devuelve false si no estaba en fromStatus y gorm.ErrRecordNotFound si no
+// existe.
+func (r *TextMessageRepository) RequeueMessage(ctx context.Context, id int, fromStatus, toStatus string, now time.Time) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Model(&models.TextMessage{}).
+		Where("id = ? AND status = ?", id, fromStatus).
+		Updates(map[string]interface{}{"status": toStatus, "attempts": 0, "next_attempt_at": now})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	var count int64
+	if err := r.DB.WithContext(ctx).Model(&models.TextMessage{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count == 0 {
+		return false, gorm.ErrRecordNotFound
+	}
+	return false, nil
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Transactor abre transacciones sin que los servicios dependan de *gorm.DB. Dentro de fn los
+// repositorios se vinculan a la transacción con WithTx. Si ctx se cancela la transacción se revierte.
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(tx Tx) error) error
+}
+
+// Tx es una transacción abierta. Los savepoints permiten deshacer un paso sin perder el resto.
+type Tx interface {
+	SavePoint(name string) error
+	RollbackTo(name string) error
+	Conn() *gorm.DB
+}
+
+type GormTransactor struct {
+	DB *gorm.DB
+}
+
+func NewGormTransactor(db *gorm.DB) *GormTransactor {
+	return &GormTransactor{DB: db}
+}
+
+func (t *GormTransactor) Transaction(ctx context.Context, fn func(tx Tx) error) error {
+	return t.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&gormTx{db: tx})
+	})
+}
+
+type gormTx struct {
+	db *gorm.DB
+}
+
+func (t *gormTx) SavePoint(name string) error {
+	return t.db.SavePoint(name).Error
+}
+
+func (t *gormTx) RollbackTo(name string) error {
+	return t.db.RollbackTo(name).Error
+}
+
+func (t *gormTx) Conn() *gorm.DB {
+	return t.db
+}
@@ -1,6 +1,9 @@
 package repositories
 
 import (
+	"context"
+	"time"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -8,15 +11,78 @@ import (
 
 type UserLogRepository struct {
 	DB *gorm.DB
+	// opcional: reportes, listados y búsquedas se leen de aquí
+	Replica *gorm.DB
 }
 
 func NewUserLogRepository(db *gorm.DB) *UserLogRepository {
 	return &UserLogRepository{DB: db}
 }
 
-func (r *UserLogRepository) CreateUserLog(userLog *models.UserLog) (*models.UserLog, error) {
-	if err := r.DB.Create(userLog).Error; err != nil {
+func (r *UserLogRepository) CreateUserLog(ctx context.Context, userLog *models.UserLog) (*models.UserLog, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	if err := r.DB.WithContext(ctx).Create(userLog).Error; err != nil {
 		return nil, err
 	}
 	return userLog, nil
 }
+
+// SearchUserLogs aplica los filtros opcionales y devuelve la página pedida y el total de registros.
+// filter.SortBy debe venir ya validado como nombre de columna.
+func (r *UserLogRepository) SearchUserLogs(ctx context.Context, filter dtos.UserLogFilterDTO) ([]models.UserLog, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	query := reader(r.DB, r.Replica).WithContext(ctx).Model(&models.UserLog{})
+	if filter.User != "" {
+		query = query.Where("user_email ILIKE ?", "%"+filter.User+"%")
+	}
+	if filter.Endpoint != "" {
+		query = query.Where("endpoint ILIKE ?", "%"+filter.Endpoint+"%")
+	}
+	if filter.Text != "" {
+		query = query.Where("log ILIKE ?", "%"+filter.Text+"%")
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.From != nil {
+		query = query.Where("date_time >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("date_time <= ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var logs []models.UserLog
+	err := query.Order(filter.SortBy + " " + filter.Order).
+		Offset(filter.Offset()).
+		Limit(filter.PageSize).
+		Find(&logs).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+func (r *UserLogRepository) CreateUserLogs(ctx context.Context, userLogs []models.UserLog) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).CreateInBatches(userLogs, len(userLogs)).Error
+}
+
+// DeleteUserLogsBefore borra los logs anteriores a la fecha límite de retención.
+func (r *UserLogRepository) DeleteUserLogsBefore(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Where("date_time < ?", before).Delete(&models.UserLog{})
+	return result.RowsAffected, result.Error
+}
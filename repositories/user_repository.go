@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,81 +16,93 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 	return &UserRepository{DB: db}
 }
 
-func (r *UserRepository) GetUserByID(id string) (*models.User, error) {
+func (r *UserRepository) GetUserByID(ctx context.Context, id string) (*models.User, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var user models.User
-	err := r.DB.Preload("UserStateType").Preload("UserType").First(&user, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).Preload("UserStateType").Preload("UserType").First(&user, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *UserRepository) GetUserByEmail(email string) (*models.User, error) {
+func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var user models.User
-	err := r.DB.Preload("UserStateType").Preload("UserType").First(&user, "email = ?", email).Error
+	err := r.DB.WithContext(ctx).Preload("UserStateType").Preload("UserType").First(&user, "email = ?", email).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *UserRepository) GetAllUsers() ([]models.User, error) {
-	var users []models.User
-	err := r.DB.Preload("UserStateType").Preload("UserType").Find(&users).Error
-	if err != nil {
-		return nil, err
-	}
-	return users, nil
+func (r *UserRepository) GetAllUsers(ctx context.Context, pagination dtos.PaginationDTO) ([]models.User, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Preload("UserStateType").Preload("UserType")
+	return paginate[models.User](db, pagination)
 }
 
-func (r *UserRepository) SearchUsersByID(query string) ([]models.User, error) {
-	var users []models.User
-	err := r.DB.Preload("UserStateType").Preload("UserType").
-		Where("CAST(id AS TEXT)  LIKE ?", query+"%").Find(&users).Error
-	if err != nil {
-		return nil, err
-	}
-	return users, nil
+func (r *UserRepository) SearchUsersByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.User, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Preload("UserStateType").Preload("UserType").
+		Where("CAST(id AS TEXT)  LIKE ?", query+"%")
+	return paginate[models.User](db, pagination)
 }
 
-func (r *UserRepository) SearchUsersByEmail(query string) ([]models.User, error) {
-	var users []models.User
-	err := r.DB.Preload("UserStateType").Preload("UserType").Where("LOWER(email) LIKE LOWER(?)", query+"%").Find(&users).Error
-	if err != nil {
-		return nil, err
-	}
-	return users, nil
+func (r *UserRepository) SearchUsersByEmail(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.User, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Preload("UserStateType").Preload("UserType").Where("LOWER(email) LIKE LOWER(?)", query+"%")
+	return paginate[models.User](db, pagination)
 }
 
-func (r *UserRepository) UpdateUserState(id string, state int) (*models.User, error) {
+func (r *UserRepository) UpdateUserState(ctx context.Context, id string, state int) (*models.User, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var user models.User
-	if err := r.DB.Preload("UserStateType").Preload("UserType").First(&user, "id = ?", id).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Preload("UserStateType").Preload("UserType").First(&user, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 
 	user.UserStateType.ID = state
 
-	if err := r.DB.Save(&user).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Save(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *UserRepository) UpdateUser(user *models.User) error {
+func (r *UserRepository) UpdateUser(ctx context.Context, user *models.User) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var existingUser models.User
-	if err := r.DB.Preload("UserStateType").Preload("UserType").First(&existingUser, "id = ?", user.ID).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Preload("UserStateType").Preload("UserType").First(&existingUser, "id = ?", user.ID).Error; err != nil {
 		return err
 	}
 	// Realizar la actualización
-	if err := r.DB.Model(&existingUser).Updates(user).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Model(&existingUser).Updates(user).Error; err != nil {
 		return err
 	}
 	return nil
 }
 
-func (r *UserRepository) CreateUser(user *models.User) (*models.User, error) {
+func (r *UserRepository) CreateUser(ctx context.Context, user *models.User) (*models.User, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	// Intentar crear el usuario en la base de datos
-	if err := r.DB.Preload("UserStateType").Preload("UserType").Create(user).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Preload("UserStateType").Preload("UserType").Create(user).Error; err != nil {
 		return nil, err
 	}
 	return user, nil
@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,20 +16,71 @@ func NewUserStateTypeRepository(db *gorm.DB) *UserStateTypeRepository {
 	return &UserStateTypeRepository{DB: db}
 }
 
-func (r *UserStateTypeRepository) GetUserStateTypeByID(id string) (*models.UserStateType, error) {
+func (r *UserStateTypeRepository) GetUserStateTypeByID(ctx context.Context, id string) (*models.UserStateType, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var UserStateType models.UserStateType
-	err := r.DB.First(&UserStateType, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).First(&UserStateType, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &UserStateType, nil
 }
 
-func (r *UserStateTypeRepository) GetAllUserStateTypes() ([]models.UserStateType, error) {
-	var UserStateTypes []models.UserStateType
-	err := r.DB.Find(&UserStateTypes).Error
-	if err != nil {
+func (r *UserStateTypeRepository) GetAllUserStateTypes(ctx context.Context, pagination dtos.PaginationDTO) ([]models.UserStateType, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return paginate[models.UserStateType](r.DB.WithContext(ctx), pagination)
+}
+
+// GetUserStateTypeByName compara sin distinguir mayúsculas; devuelve nil si no existe.
+func (r *UserStateTypeRepository) GetUserStateTypeByName(ctx context.Context, name string) (*models.UserStateType, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var userStateTypes []models.UserStateType
+	err := r.DB.WithContext(ctx).Where("LOWER(name) = LOWER(?)", name).Limit(1).Find(&userStateTypes).Error
+	if err != nil || len(userStateTypes) == 0 {
 		return nil, err
 	}
-	return UserStateTypes, nil
+	return &userStateTypes[0], nil
+}
+
+func (r *UserStateTypeRepository) CreateUserStateType(ctx context.Context, userStateType *models.UserStateType) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Create(userStateType).Error
+}
+
+func (r *UserStateTypeRepository) UpdateUserStateType(ctx context.Context, userStateType *models.UserStateType) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Save(userStateType).Error
+}
+
+func (r *UserStateTypeRepository) CountUsersWithState(ctx context.Context, id int) (int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var count int64
+	err := r.DB.WithContext(ctx).Model(&models.User{}).Where("user_state_type_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+func (r *UserStateTypeRepository) DeleteUserStateType(ctx context.Context, id int) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Delete(&models.UserStateType{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
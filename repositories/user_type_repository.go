@@ -1,9 +1,12 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserTypeRepository struct {
@@ -14,36 +17,44 @@ func NewUserTypeRepository(db *gorm.DB) *UserTypeRepository {
 	return &UserTypeRepository{DB: db}
 }
 
-func (r *UserTypeRepository) ObtainAllUserTypes() ([]models.UserType, error) {
-	var userTypes []models.UserType
-	err := r.DB.Preload("Roles").Find(&userTypes).Error
-	if err != nil {
-		return nil, err
-	}
-	return userTypes, nil
+func (r *UserTypeRepository) ObtainAllUserTypes(ctx context.Context, pagination dtos.PaginationDTO) ([]models.UserType, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Preload("Roles")
+	return paginate[models.UserType](db, pagination)
 }
 
-func (r *UserTypeRepository) GetUserTypeByID(id uint) (*models.UserType, error) {
+func (r *UserTypeRepository) GetUserTypeByID(ctx context.Context, id uint) (*models.UserType, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var userType models.UserType
-	err := r.DB.Preload("Roles").First(&userType, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).Preload("Roles").First(&userType, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &userType, nil
 }
 
-func (r *UserTypeRepository) Exists(userTypeID uint) (bool, error) {
+func (r *UserTypeRepository) Exists(ctx context.Context, userTypeID uint) (bool, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var count int64
-	err := r.DB.Table("user_types").Where("id = ?", userTypeID).Count(&count).Error
+	err := r.DB.WithContext(ctx).Table("user_types").Where("id = ?", userTypeID).Count(&count).Error
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
 
-func (r *UserTypeRepository) GetRolesForUserType(userTypeID uint) ([]uint, error) {
+func (r *UserTypeRepository) GetRolesForUserType(ctx context.Context, userTypeID uint) ([]uint, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var roleIDs []uint
-	err := r.DB.Table("user_type_has_role").Select("role_id").
+	err := r.DB.WithContext(ctx).Table("user_type_has_role").Select("role_id").
 		Where("user_type_id = ?", userTypeID).
 		Pluck("role_id", &roleIDs).Error
 	if err != nil {
@@ -52,24 +63,131 @@ func (r *UserTypeRepository) GetRolesForUserType(userTypeID uint) ([]uint, error
 	return roleIDs, nil
 }
 
-func (r *UserTypeRepository) SearchUserTypesByID(query string) ([]models.UserType, error) {
+func (r *UserTypeRepository) SearchUserTypesByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.UserType, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Preload("Roles").
+		Where("CAST(id AS TEXT) LIKE ?", query+"%")
+	return paginate[models.UserType](db, pagination)
+}
+
+func (r *UserTypeRepository) SearchUserTypesByName(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.UserType, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Preload("Roles").
+		Where("LOWER(name) LIKE LOWER(?)", query+"%")
+	return paginate[models.UserType](db, pagination)
+}
+
+// GetUserTypeByName devuelve nil si ningún tipo de usuario tiene ese nombre (sin distinguir mayúsculas).
+func (r *UserTypeRepository) GetUserTypeByName(ctx context.Context, name string) (*models.UserType, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
 	var userTypes []models.UserType
-	err := r.DB.Preload("Roles").
-		Where("CAST(id AS TEXT) LIKE ?", query+"%").
-		Find(&userTypes).Error
-	if err != nil {
+	err := r.DB.WithContext(ctx).Where("LOWER(name) = LOWER(?)", name).Limit(1).Find(&userTypes).Error
+	if err != nil || len(userTypes) == 0 {
 		return nil, err
 	}
-	return userTypes, nil
+	return &userTypes[0], nil
 }
 
-func (r *UserTypeRepository) SearchUserTypesByName(query string) ([]models.UserType, error) {
-	var userTypes []models.UserType
-	err := r.DB.Preload("Roles").
-		Where("LOWER(name) LIKE LOWER(?)", query+"%").
-		Find(&userTypes).Error
-	if err != nil {
-		return nil, err
+// GetExistingRoleIDs devuelve cuáles de roleIDs existen.
+func (r *UserTypeRepository) GetExistingRoleIDs(ctx context.Context, roleIDs []uint) ([]uint, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var ids []uint
+	err := r.DB.WithContext(ctx).Model(&models.Role{}).Where("id IN ?", roleIDs).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// CreateUserType crea el tipo de usuario con sus roles en una transacción.
+func (r *UserTypeRepository) CreateUserType(ctx context.Context, userType *models.UserType, roleIDs []uint) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Roles").Create(userType).Error; err != nil {
+			return err
+		}
+		return replaceUserTypeRoles(tx, userType.ID, roleIDs)
+	})
+}
+
+// UpdateUserType cambia el nombre y la descripción del tipo de usuario; sus roles no se tocan.
+func (r *UserTypeRepository) UpdateUserType(ctx context.Context, userType *models.UserType) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	result := r.DB.WithContext(ctx).Model(&models.UserType{}).Where("id = ?", userType.ID).
+		Select("name", "description").Updates(userType)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// SetUserTypeRoles reemplaza los roles del tipo de usuario por roleIDs en una transacción.
+func (r *UserTypeRepository) SetUserTypeRoles(ctx context.Context, userTypeID uint, roleIDs []uint) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// el bloqueo ordena los cambios concurrentes sobre el mismo tipo de usuario
+		var userType models.UserType
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&userType, "id = ?", userTypeID).Error; err != nil {
+			return err
+		}
+		return replaceUserTypeRoles(tx, userTypeID, roleIDs)
+	})
+}
+
+// CountUserTypeUsers cuenta los usuarios de ese tipo.
+func (r *UserTypeRepository) CountUserTypeUsers(ctx context.Context, userTypeID uint) (int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var count int64
+	err := r.DB.WithContext(ctx).Model(&models.User{}).Where("user_type_id = ?", userTypeID).Count(&count).Error
+	return count, err
+}
+
+// DeleteUserType borra el tipo de usuario y sus asignaciones de roles.
+func (r *UserTypeRepository) DeleteUserType(ctx context.Context, userTypeID uint) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM user_type_has_role WHERE user_type_id = ?", userTypeID).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.UserType{}, userTypeID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func replaceUserTypeRoles(tx *gorm.DB, userTypeID uint, roleIDs []uint) error {
+	if err := tx.Exec("DELETE FROM user_type_has_role WHERE user_type_id = ?", userTypeID).Error; err != nil {
+		return err
+	}
+	if len(roleIDs) == 0 {
+		return nil
+	}
+	rows := make([]map[string]interface{}, len(roleIDs))
+	for i, roleID := range roleIDs {
+		rows[i] = map[string]interface{}{"user_type_id": userTypeID, "role_id": roleID}
 	}
-	return userTypes, nil
+	return tx.Table("user_type_has_role").Create(&rows).Error
 }
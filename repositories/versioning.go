@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Bloqueo optimista: items, clientes, citas y facturas tienen una columna version que aumenta con
+// cada cambio. Una escritura solo se aplica si la fila sigue en la versión que el cliente leyó.
+
+// nextVersion se agrega a los UPDATE que cambian la fila sin pasar por saveVersioned.
+var nextVersion = gorm.Expr("version + 1")
+
+// saveVersioned guarda todas las columnas de record (sin sus asociaciones) si la fila sigue en la
+// versión *version y la incrementa. Devuelve false, sin error, si otro la modificó antes.
+func saveVersioned(db *gorm.DB, record interface{}, version *int) (bool, error) {
+	expected := *version
+	*version = expected + 1
+	result := db.Model(record).Where("version = ?", expected).Select("*").Omit(clause.Associations).Updates(record)
+	if result.Error != nil || result.RowsAffected == 0 {
+		*version = expected
+		return false, result.Error
+	}
+	return true, nil
+}
@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type WebhookRepository struct {
+	DB *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{DB: db}
+}
+
+func (r *WebhookRepository) GetAllSubscriptions(ctx context.Context, pagination dtos.PaginationDTO) ([]models.WebhookSubscription, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return paginate[models.WebhookSubscription](r.DB.WithContext(ctx), pagination)
+}
+
+func (r *WebhookRepository) GetSubscriptionByID(ctx context.Context, id int) (*models.WebhookSubscription, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var subscription models.WebhookSubscription
+	if err := r.DB.WithContext(ctx).First(&subscription, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// GetActiveSubscriptionsForEvent filtra en Go porque los tipos de evento se guardan serializados.
+func (r *WebhookRepository) GetActiveSubscriptionsForEvent(ctx context.Context, eventType string) ([]models.WebhookSubscription, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var subscriptions []models.WebhookSubscription
+	if err := r.DB.WithContext(ctx).Where("active = ?", true).Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+
+	var matching []models.WebhookSubscription
+	for _, subscription := range subscriptions {
+		for _, subscribed := range subscription.EventTypes {
+			if subscribed == eventType {
+				matching = append(matching, subscription)
+				break
+			}
+		}
+	}
+	return matching, nil
+}
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Create(subscription).Error
+}
+
+func (r *WebhookRepository) UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Save(subscription).Error
+}
+
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id int) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subscription_id = ?", id).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.WebhookSubscription{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *WebhookRepository) CreateDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.DB.WithContext(ctx).Create(&deliveries).Error
+}
+
+func (r *WebhookRepository) GetDeliveriesBySubscription(ctx context.Context, subscriptionID int, pagination dtos.PaginationDTO) ([]models.WebhookDelivery, int64, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	db := r.DB.WithContext(ctx).Where("subscription_id = ?", subscriptionID).Order("created_at DESC")
+	return paginate[models.WebhookDelivery](db, pagination)
+}
+
+func (r *WebhookRepository) GetDueDeliveries(ctx context.Context, status string, now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	var deliveries []models.WebhookDelivery
+	err := r.DB.WithContext(ctx).Where("status = ? AND next_attempt_at <= ?", status, now).
+		Order("next_attempt_at").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Save(delivery).Error
+}
@@ -1,83 +1,107 @@
 package routes
 
 import (
+	"totesbackend/config"
 	"totesbackend/controllers"
+	"totesbackend/controllers/utilities"
 
 	"github.com/gin-gonic/gin"
 )
 
 func RegisterItemTypeRoutes(router *gin.Engine, controller *controllers.ItemTypeController) {
-	router.GET("/item-types", controller.GetItemTypes)
-	router.GET("/item-types/:id", controller.GetItemTypeByID)
+	router.GET("/item-types", utilities.ETag(), controller.GetItemTypes)
+	router.GET("/item-types/:id", utilities.ETag(), controller.GetItemTypeByID)
+	router.POST("/item-types", controller.CreateItemType)
+	router.PUT("/item-types/:id", controller.UpdateItemType)
+	router.DELETE("/item-types/:id", controller.DeleteItemType)
 }
 
 func RegisterItemRoutes(router *gin.Engine, controller *controllers.ItemController) {
 	router.GET("/items/:id", controller.GetItemByID)
-	router.GET("/items", controller.GetAllItems)
-	router.GET("/items/searchById", controller.SearchItemsByID)
-	router.GET("/items/searchByName", controller.SearchItemsByName)
+	router.GET("/items", utilities.ETag(), controller.GetAllItems)
+	router.GET("/items/search", controller.SearchItems)
+	router.GET("/items/lowStock", controller.GetLowStockItems)
+	router.GET("/items/export", controller.ExportItems)
 	router.PATCH("/items/:id/state", controller.UpdateItemState)
 	router.PUT("/items/:id", controller.UpdateItem)
+	router.PATCH("/items/:id", controller.PatchItem)
 	router.POST("/items", controller.CreateItem)
+	router.POST("/items/batch", controller.BatchItems)
 	router.GET("/items/:id/stock", controller.CheckItemStock)
 }
 
 func RegisterPermissionRoutes(router *gin.Engine,
 	controller *controllers.PermissionController) {
 
-	router.GET("/permissions", controller.GetAllPermissions)
-	router.GET("/permissions/:id", controller.GetPermissionByID)
+	router.GET("/permissions", utilities.ETag(), controller.GetAllPermissions)
+	router.GET("/permissions/:id", utilities.ETag(), controller.GetPermissionByID)
 	router.GET("/permissions/searchByID", controller.SearchPermissionsByID)
 	router.GET("/permissions/searchByName", controller.SearchPermissionsByName)
 }
 
 func RegisterRoleRoutes(router *gin.Engine, controller *controllers.RoleController) {
-	router.GET("/roles/:id", controller.GetRoleByID)
-	router.GET("/roles/:id/permission", controller.GetAllPermissionsOfRole)
+	router.GET("/roles/:id", utilities.ETag(), controller.GetRoleByID)
+	router.GET("/roles/:id/permission", utilities.ETag(), controller.GetAllPermissionsOfRole)
 	router.GET("/roles/:id/exist", controller.ExistRole)
-	router.GET("/roles", controller.GetAllRoles)
+	router.GET("/roles", utilities.ETag(), controller.GetAllRoles)
 	router.GET("/roles/searchByID", controller.SearchRolesByID)
 	router.GET("/roles/searchByName", controller.SearchRolesByName)
+	router.POST("/roles", controller.CreateRole)
+	router.PUT("/roles/:id", controller.UpdateRole)
+	router.DELETE("/roles/:id", controller.DeleteRole)
+	router.PUT("/roles/:id/permissions", controller.UpdateRolePermissions)
 }
 
 func RegisterUserTypeRoutes(router *gin.Engine,
 	controller *controllers.UserTypeController) {
-	router.GET("/user-types", controller.GetAllUserTypes)
-	router.GET("/user-types/:id", controller.GetUserTypeByID)
+	router.GET("/user-types", utilities.ETag(), controller.GetAllUserTypes)
+	router.GET("/user-types/:id", utilities.ETag(), controller.GetUserTypeByID)
 	router.GET("/user-types/:id/exists", controller.ExistsUserType)
 	router.GET("/user-types/searchByID", controller.SearchUserTypesByID)
 	router.GET("/user-types/searchByName", controller.SearchUserTypesByName)
+	router.POST("/user-types", controller.CreateUserType)
+	router.PUT("/user-types/:id", controller.UpdateUserType)
+	router.DELETE("/user-types/:id", controller.DeleteUserType)
+	router.PUT("/user-types/:id/roles", controller.UpdateUserTypeRoles)
 }
 
 func RegisterUserStateTypeRoutes(router *gin.Engine,
 	controller *controllers.UserStateTypeController) {
-	router.GET("/user-state-types", controller.GetAllUserStateTypes)
-	router.GET("/user-state-types/:id", controller.GetUserStateTypeByID)
+	router.GET("/user-state-types", utilities.ETag(), controller.GetAllUserStateTypes)
+	router.GET("/user-state-types/:id", utilities.ETag(), controller.GetUserStateTypeByID)
+	router.POST("/user-state-types", controller.CreateUserStateType)
+	router.PUT("/user-state-types/:id", controller.UpdateUserStateType)
+	router.DELETE("/user-state-types/:id", controller.DeleteUserStateType)
 }
 
 func RegisterIdentifierTypeRoutes(router *gin.Engine, controller *controllers.IdentifierTypeController) {
-	router.GET("/identifier-types", controller.GetAllIdentifierTypes)
-	router.GET("/identifier-types/:id", controller.GetIdentifierTypeByID)
+	router.GET("/identifier-types", utilities.ETag(), controller.GetAllIdentifierTypes)
+	router.GET("/identifier-types/:id", utilities.ETag(), controller.GetIdentifierTypeByID)
+	router.POST("/identifier-types", controller.CreateIdentifierType)
+	router.PUT("/identifier-types/:id", controller.UpdateIdentifierType)
+	router.DELETE("/identifier-types/:id", controller.DeleteIdentifierType)
 }
 
 func RegisterUserRoutes(router *gin.Engine,
 	controller *controllers.UserController) {
-	router.GET("/users", controller.GetAllUsers)
+	router.GET("/users", utilities.ETag(), controller.GetAllUsers)
 	router.GET("/users/:id", controller.GetUserByID)
 	router.GET("/users/searchByID", controller.SearchUsersByID)
 	router.GET("/users/searchByEmail", controller.SearchUsersByEmail)
 	router.PATCH("/users/:id/state", controller.UpdateUserState)
 	router.PUT("/users/:id", controller.UpdateUser)
+	router.PATCH("/users/:id", controller.PatchUser)
 	router.POST("/users", controller.CreateUser)
 }
 
 func RegisterEmployeeRoutes(router *gin.Engine, controller *controllers.EmployeeController) {
 	router.GET("/employees/:id", controller.GetEmployeeByID)
-	router.GET("/employees", controller.GetAllEmployees)
+	router.GET("/employees", utilities.ETag(), controller.GetAllEmployees)
 	router.GET("/employees/searchByID", controller.SearchEmployeesByID)
 	router.GET("/employees/searchByName", controller.SearchEmployeesByName)
 	router.POST("/employees", controller.CreateEmployee)
 	router.PUT("/employees/:id", controller.UpdateEmployee)
+	router.PATCH("/employees/:id", controller.PatchEmployee)
 }
 
 func RegisterAdditionalExpenseRoutes(router *gin.Engine,
@@ -96,7 +120,7 @@ func RegisterHistoricalItemPriceRoutes(router *gin.Engine, controller *controlle
 func RegisterCommentRoutes(router *gin.Engine,
 	controller *controllers.CommentController) {
 	router.GET("/comments/:id", controller.GetCommentByID)
-	router.GET("/comments", controller.GetAllComments)
+	router.GET("/comments", utilities.ETag(), controller.GetAllComments)
 	router.GET("/comments/searchByID", controller.SearchCommentsByID)
 	router.GET("/comments/searchByName", controller.SearchCommentsByName)
 	router.GET("/comments/searchByEmail", controller.SearchCommentsByEmail)
@@ -110,7 +134,7 @@ func RegisterAuthorizationRoutes(router *gin.Engine, controller *controllers.Aut
 
 func RegisterAppointmentRoutes(router *gin.Engine, controller *controllers.AppointmentController) {
 	router.GET("/appointments/:id", controller.GetAppointmentByID)
-	router.GET("/appointments", controller.GetAllAppointments)
+	router.GET("/appointments", utilities.ETag(), controller.GetAllAppointments)
 	router.GET("/appointments/searchByID", controller.SearchAppointmentsByID)
 	router.GET("/appointments/searchByCustomerID", controller.SearchAppointmentsByCustomerID)
 	router.GET("/appointments/searchByState", controller.SearchAppointmentsByState)
@@ -120,29 +144,48 @@ func RegisterAppointmentRoutes(router *gin.Engine, controller *controllers.Appoi
 	router.GET("/appointments/byCustomerAndDate", controller.GetAppointmentByCustomerIDAndDate)
 	router.DELETE("/appointments/deleteAppointment/:id", controller.DeleteAppointmentByID)
 	router.GET("/appointments/hourly-count", controller.GetAppointmentsByHourRange)
+	router.GET("/appointments/availableSlots", controller.GetAvailableSlots)
+	router.GET("/appointments/cancel", controller.GetAppointmentCancellation)
+	router.POST("/appointments/cancel", controller.CancelAppointmentByToken)
+	router.POST("/appointments/link-customers", controller.LinkAppointmentCustomers)
+	router.GET("/appointments/:id/reminders", controller.GetAppointmentReminders)
+	router.PATCH("/appointments/:id/state", controller.ChangeAppointmentState)
+	router.GET("/appointments/:id/state-history", controller.GetAppointmentStateHistory)
+	router.GET("/employees/:id/appointments", controller.GetEmployeeAppointments)
+	router.GET("/appointment-state-types", utilities.ETag(), controller.GetAppointmentStateTypes)
+	// público: reservas desde la web, limitadas por IP
+	router.GET("/public/appointments/slots", utilities.RateLimit(config.PUBLIC_SLOTS_RATE_LIMIT, config.PUBLIC_APPOINTMENT_RATE_WINDOW),
+		controller.GetAvailableAppointmentSlots)
+	router.POST("/public/appointments", utilities.RateLimit(config.PUBLIC_BOOKING_RATE_LIMIT, config.PUBLIC_APPOINTMENT_RATE_WINDOW),
+		controller.BookAppointment)
 }
 
 func RegisterCustomerRoutes(router *gin.Engine, controller *controllers.CustomerController) {
 	router.GET("/customers/:id", controller.GetCustomerByID)
 	router.GET("/customers/customerID/:customerID", controller.GetCustomerByCustomerID)
-	router.GET("/customers", controller.GetAllCustomers)
+	router.GET("/customers", utilities.ETag(), controller.GetAllCustomers)
 	router.GET("/customers/email/:email", controller.GetCustomerByEmail)
-	router.GET("/customers/searchByID", controller.SearchCustomersByID)
-	router.GET("/customers/searchByName", controller.SearchCustomersByName)
-	router.GET("/customers/searchByLastName", controller.SearchCustomersByLastName)
+	router.GET("/customers/search", controller.SearchCustomers)
+	router.GET("/customers/export", controller.ExportCustomers)
 	router.POST("/customers", controller.CreateCustomer)
 	router.PUT("/customers/:id", controller.UpdateCustomer)
+	router.PATCH("/customers/:id", controller.PatchCustomer)
+	router.DELETE("/customers/:id", controller.DeleteCustomer)
+	router.POST("/customers/:id/restore", controller.RestoreCustomer)
+	router.GET("/customers/:id/dependencies", controller.GetCustomerDependencies)
+	router.POST("/customers/batch", controller.BatchCustomers)
+	router.POST("/customers/merge", controller.MergeCustomers)
 }
 
 func RegisterOrderStateTypeRoutes(router *gin.Engine, controller *controllers.OrderStateTypeController) {
-	router.GET("/order-state-types", controller.GetAllOrderStateTypes)
-	router.GET("/order-state-types/:id", controller.GetOrderStateTypeByID)
+	router.GET("/order-state-types", utilities.ETag(), controller.GetAllOrderStateTypes)
+	router.GET("/order-state-types/:id", utilities.ETag(), controller.GetOrderStateTypeByID)
 }
 
 func RegisterPurchaseOrderRoutes(router *gin.Engine, controller *controllers.PurchaseOrderController) {
 
 	router.GET("/purchase-orders/:id", controller.GetPurchaseOrderByID)
-	router.GET("/purchase-orders", controller.GetAllPurchaseOrders)
+	router.GET("/purchase-orders", utilities.ETag(), controller.GetAllPurchaseOrders)
 	router.GET("/purchase-orders/searchByID", controller.SearchPurchaseOrdersByID)
 	router.GET("/purchase-orders/customers/:customerID", controller.GetPurchaseOrdersByCustomerID)
 	router.GET("/purchase-orders/seller/:sellerID", controller.GetPurchaseOrdersBySellerID)
@@ -153,19 +196,34 @@ func RegisterPurchaseOrderRoutes(router *gin.Engine, controller *controllers.Pur
 }
 
 func RegisterDiscountTypeRoutes(router *gin.Engine, controller *controllers.DiscountTypeController) {
-	router.GET("/discount-types", controller.GetAllDiscountTypes)
-	router.GET("/discount-types/:id", controller.GetDiscountTypeByID)
+	router.GET("/discount-types", utilities.ETag(), controller.GetAllDiscountTypes)
+	router.GET("/discount-types/:id", utilities.ETag(), controller.GetDiscountTypeByID)
 	router.POST("/discount-types", controller.CreateDiscountType)
+	router.POST("/discount-types/import", controller.ImportDiscountTypes)
+	router.PUT("/discount-types/:id", controller.UpdateDiscountType)
+	router.PATCH("/discount-types/:id/deactivate", controller.DeactivateDiscountType)
 }
 
 func RegisterUserCredentialValidationRoutes(router *gin.Engine, controller *controllers.UserCredentialValidationController) {
+	// públicas: abren, renuevan y cierran sesiones y restablecen contraseñas
+	router.POST("/login", controller.ValidateUserCredentials)
 	router.POST("/user-credential-validation", controller.ValidateUserCredentials)
+	router.POST("/auth/refresh", controller.RefreshTokens)
+	router.POST("/auth/revoke", controller.RevokeToken)
+	router.POST("/auth/password-reset", utilities.RateLimit(config.PASSWORD_RESET_RATE_LIMIT, config.PASSWORD_RESET_RATE_WINDOW),
+		controller.RequestPasswordReset)
+	router.GET("/auth/password-reset/confirm", controller.GetPasswordReset)
+	router.POST("/auth/password-reset/confirm", controller.ResetPassword)
 }
 
 func RegisterTaxTypeRoutes(router *gin.Engine, controller *controllers.TaxTypeController) {
-	router.GET("/tax-types", controller.GetAllTaxTypes)
-	router.GET("/tax-types/:id", controller.GetTaxTypeByID)
+	router.GET("/tax-types", utilities.ETag(), controller.GetAllTaxTypes)
+	router.GET("/tax-types/:id", utilities.ETag(), controller.GetTaxTypeByID)
 	router.POST("/tax-types", controller.CreateTaxType)
+	router.POST("/tax-types/import", controller.ImportTaxTypes)
+	router.PUT("/tax-types/:id", controller.UpdateTaxType)
+	router.PATCH("/tax-types/:id/deactivate", controller.DeactivateTaxType)
+	router.GET("/tax-types/:id/rates", controller.GetTaxTypeRates)
 }
 
 func RegisterBillingRoutes(router *gin.Engine, controller *controllers.BillingController) {
@@ -175,10 +233,15 @@ func RegisterBillingRoutes(router *gin.Engine, controller *controllers.BillingCo
 
 func RegisterInvoice(router *gin.Engine, controller *controllers.InvoiceController) {
 	router.GET("/invoices/:id", controller.GetInvoiceByID)
-	router.GET("/invoices", controller.GetAllInvoices)
+	router.GET("/invoices", utilities.ETag(), controller.GetAllInvoices)
 	router.GET("/invoices/searchById", controller.SearchInvoiceByID)
 	router.GET("/invoices/searchByPersonalId", controller.SearchInvoiceByCustomerPersonalId)
+	router.GET("/invoices/export", controller.ExportInvoices)
 	router.POST("/invoices", controller.CreateInvoice)
+	router.PATCH("/invoices/:id/payment", controller.UpdateInvoicePayment)
+	router.GET("/invoices/:id/reminders", controller.GetInvoiceReminders)
+	router.POST("/invoices/:id/payments", controller.RecordInvoicePayment)
+	router.GET("/invoices/:id/payments", controller.GetInvoicePayments)
 }
 func RegisterExternalSaleRoutes(router *gin.Engine, controller *controllers.ExternalSaleController) {
 	router.GET("/external-sales/:id", controller.GetExternalSaleByID)
@@ -187,4 +250,216 @@ func RegisterExternalSaleRoutes(router *gin.Engine, controller *controllers.Exte
 }
 func RegisterSalesReportRoutes(router *gin.Engine, controller *controllers.SalesReportController) {
 	router.GET("/sales-report/invoices", controller.GetInvoicesBetweenDates)
+	router.GET("/reports/sales", controller.GetSalesSummary)
+	router.GET("/reports/margins", controller.GetMarginReport)
+	router.GET("/reports/discounts", controller.GetDiscountUsageReport)
+	router.GET("/reports/top-items", controller.GetTopItemsReport)
+	router.GET("/reports/revenue-by-customer", controller.GetRevenueByCustomerReport)
+}
+
+func RegisterDashboardRoutes(router *gin.Engine, controller *controllers.DashboardController) {
+	router.GET("/dashboard", controller.GetDashboard)
+}
+
+func RegisterInventoryReportRoutes(router *gin.Engine, controller *controllers.InventoryReportController) {
+	router.GET("/reports/inventory-turnover", controller.GetInventoryTurnover)
+}
+
+func RegisterAppointmentReportRoutes(router *gin.Engine, controller *controllers.AppointmentReportController) {
+	router.GET("/reports/appointments", controller.GetAppointmentReport)
+}
+
+func RegisterDailyCloseRoutes(router *gin.Engine, controller *controllers.DailyCloseController) {
+	router.GET("/reports/daily-close", controller.GetDailyClose)
+	router.POST("/reports/daily-close", controller.CloseDay)
+}
+
+func RegisterUserLogRoutes(router *gin.Engine, controller *controllers.UserLogController) {
+	router.GET("/logs", controller.GetUserLogs)
+}
+
+func RegisterAuditRoutes(router *gin.Engine, controller *controllers.AuditController) {
+	router.GET("/audit/:entity/:id", controller.GetAuditTrail)
+}
+
+func RegisterSecurityEventRoutes(router *gin.Engine, controller *controllers.SecurityEventController) {
+	router.GET("/security-events", controller.GetSecurityEvents)
+	router.GET("/security-events/verify", controller.VerifySecurityEvents)
+}
+
+func RegisterHealthRoutes(router *gin.Engine, controller *controllers.HealthController) {
+	router.GET("/health", controller.HealthCheck)
+	router.GET("/health/ready", controller.ReadinessCheck)
+}
+
+func RegisterWebhookRoutes(router *gin.Engine, controller *controllers.WebhookController) {
+	router.GET("/webhooks", controller.GetWebhookSubscriptions)
+	router.GET("/webhooks/:id", controller.GetWebhookSubscriptionByID)
+	router.POST("/webhooks", controller.CreateWebhookSubscription)
+	router.PUT("/webhooks/:id", controller.UpdateWebhookSubscription)
+	router.DELETE("/webhooks/:id", controller.DeleteWebhookSubscription)
+	router.GET("/webhooks/:id/deliveries", controller.GetWebhookDeliveries)
+}
+
+func RegisterEventStreamRoutes(router *gin.Engine, controller *controllers.EventStreamController) {
+	router.GET("/events", controller.StreamEvents)
+}
+
+func RegisterMigrationRoutes(router *gin.Engine, controller *controllers.MigrationController) {
+	router.GET("/migrations", controller.GetMigrationStatus)
+}
+
+func RegisterSchedulerRoutes(router *gin.Engine, controller *controllers.SchedulerController) {
+	router.GET("/scheduler/jobs", controller.GetScheduledJobs)
+}
+
+func RegisterNotificationPreferenceRoutes(router *gin.Engine, controller *controllers.NotificationPreferenceController) {
+	router.GET("/notification-preferences/me", controller.GetMyNotificationPreferences)
+	router.PUT("/notification-preferences/me", controller.UpdateMyNotificationPreferences)
+	router.GET("/notification-preferences/unsubscribe", controller.Unsubscribe)
+	router.POST("/notification-preferences/unsubscribe", controller.Unsubscribe)
+	router.GET("/customers/:id/notification-preferences", controller.GetCustomerNotificationPreferences)
+	router.PUT("/customers/:id/notification-preferences", controller.UpdateCustomerNotificationPreferences)
+}
+
+func RegisterNotificationRoutes(router *gin.Engine, controller *controllers.NotificationController) {
+	router.GET("/notifications", controller.GetNotifications)
+	router.GET("/notifications/unread-count", controller.GetUnreadNotificationCount)
+	router.PATCH("/notifications/:id/read", controller.MarkNotificationAsRead)
+	router.POST("/notifications/read-all", controller.MarkAllNotificationsAsRead)
+}
+
+func RegisterEmailTemplateRoutes(router *gin.Engine, controller *controllers.EmailTemplateController) {
+	router.GET("/email-templates", controller.GetEmailTemplates)
+	router.GET("/email-templates/:name", controller.GetEmailTemplate)
+	router.GET("/email-templates/:name/versions", controller.GetEmailTemplateVersions)
+	router.POST("/email-templates/:name/versions", controller.CreateEmailTemplateVersion)
+	router.POST("/email-templates/:name/versions/:version/activate", controller.ActivateEmailTemplateVersion)
+	router.POST("/email-templates/:name/preview", controller.PreviewEmailTemplate)
+	router.POST("/email-templates/:name/test", controller.SendTestEmail)
+}
+
+func RegisterArchiveRoutes(router *gin.Engine, controller *controllers.ArchiveController) {
+	router.GET("/archive/invoices", controller.GetArchivedInvoices)
+	router.GET("/archive/invoices/:id", controller.GetArchivedInvoiceByID)
+	router.GET("/archive/appointments", controller.GetArchivedAppointments)
+	router.GET("/archive/appointments/:id", controller.GetArchivedAppointmentByID)
+}
+
+func RegisterDataExportRoutes(router *gin.Engine, controller *controllers.DataExportController) {
+	router.POST("/exports", controller.CreateDataExport)
+	router.GET("/exports", controller.GetDataExports)
+	// público: el token firmado del enlace es la credencial
+	router.GET("/exports/download", controller.DownloadDataExport)
+	router.GET("/exports/:id", controller.GetDataExportByID)
+}
+
+func RegisterSandboxRoutes(router *gin.Engine, controller *controllers.SandboxController) {
+	router.POST("/sandbox/reset", controller.ResetSandbox)
+}
+
+func RegisterAccountingRoutes(router *gin.Engine, controller *controllers.AccountingController) {
+	router.GET("/accounting/syncs", controller.GetAccountingSyncs)
+	router.POST("/accounting/syncs/:id/resync", controller.ResyncAccountingDocument)
+}
+
+func RegisterPublicCatalogRoutes(router *gin.Engine, controller *controllers.PublicCatalogController) {
+	// público: catálogo de solo lectura para la tienda
+	router.GET("/public/catalog", controller.GetPublicCatalog)
+}
+
+func RegisterStockRoutes(router *gin.Engine, controller *controllers.StockController) {
+	router.GET("/stock-movements", controller.GetStockMovements)
+	router.GET("/stock-movements/balance/:itemId", controller.GetStockBalance)
+	router.GET("/stock-movements/discrepancies", controller.GetStockDiscrepancies)
+}
+
+func RegisterRestockOrderRoutes(router *gin.Engine, controller *controllers.RestockOrderController) {
+	router.GET("/restock-orders", controller.GetRestockOrders)
+	router.GET("/restock-orders/:id", controller.GetRestockOrderByID)
+	router.POST("/restock-orders", controller.CreateRestockOrder)
+	router.PUT("/restock-orders/:id", controller.UpdateRestockOrder)
+	router.POST("/restock-orders/:id/send", controller.SendRestockOrder)
+	router.POST("/restock-orders/:id/receive", controller.ReceiveRestockOrder)
+	router.POST("/restock-orders/:id/cancel", controller.CancelRestockOrder)
+}
+
+func RegisterSupplierRoutes(router *gin.Engine, controller *controllers.SupplierController) {
+	router.GET("/suppliers", controller.GetAllSuppliers)
+	router.GET("/suppliers/:id", controller.GetSupplierByID)
+	router.GET("/suppliers/searchById", controller.SearchSuppliersByID)
+	router.GET("/suppliers/searchByName", controller.SearchSuppliersByName)
+	router.POST("/suppliers", controller.CreateSupplier)
+	router.PUT("/suppliers/:id", controller.UpdateSupplier)
+	router.DELETE("/suppliers/:id", controller.DeleteSupplier)
+}
+
+func RegisterCreditNoteRoutes(router *gin.Engine, controller *controllers.CreditNoteController) {
+	router.POST("/invoices/:id/cancel", controller.CancelInvoice)
+	router.GET("/credit-notes", controller.GetCreditNotes)
+	router.GET("/credit-notes/:id", controller.GetCreditNoteByID)
+}
+
+func RegisterEcommerceRoutes(router *gin.Engine, controller *controllers.EcommerceController) {
+	// público: la firma del webhook es la credencial
+	router.POST("/ecommerce/webhooks/orders", controller.ReceiveOrderWebhook)
+	router.GET("/ecommerce/orders", controller.GetEcommerceOrders)
+	router.POST("/ecommerce/orders/:id/retry", controller.RetryEcommerceOrder)
+	router.GET("/ecommerce/reconciliation", controller.GetEcommerceReconciliation)
+}
+
+func RegisterInvoiceReturnRoutes(router *gin.Engine, controller *controllers.InvoiceReturnController) {
+	router.POST("/invoices/:id/returns", controller.CreateInvoiceReturn)
+	router.GET("/invoices/:id/returns", controller.GetInvoiceReturns)
+}
+
+func RegisterPaymentGatewayRoutes(router *gin.Engine, controller *controllers.PaymentGatewayController) {
+	// público: la firma de la pasarela es la credencial
+	router.POST("/webhooks/payments", controller.ReceivePaymentWebhook)
+}
+
+func RegisterMetaRoutes(router *gin.Engine, controller *controllers.MetaController) {
+	router.GET("/meta/routes", controller.GetRoutes)
+}
+
+func RegisterNotificationDeliveryRoutes(router *gin.Engine, controller *controllers.NotificationDeliveryController) {
+	router.GET("/notifications/deliveries", controller.GetNotificationDeliveries)
+	router.POST("/notifications/deliveries/:id/retry", controller.RetryNotificationDelivery)
+}
+
+func RegisterBusinessHoursRoutes(router *gin.Engine, controller *controllers.BusinessHoursController) {
+	router.GET("/business-hours", controller.GetBusinessHours)
+	router.PUT("/business-hours", controller.UpdateBusinessHours)
+}
+
+func RegisterQuotationRoutes(router *gin.Engine, controller *controllers.QuotationController) {
+	router.GET("/quotations", controller.GetQuotations)
+	router.GET("/quotations/:id", controller.GetQuotationByID)
+	router.POST("/quotations", controller.CreateQuotation)
+	router.PUT("/quotations/:id", controller.UpdateQuotation)
+	router.DELETE("/quotations/:id", controller.DeleteQuotation)
+	router.POST("/quotations/:id/accept", controller.AcceptQuotation)
+	router.POST("/quotations/:id/reject", controller.RejectQuotation)
+	router.POST("/quotations/:id/convert", controller.ConvertQuotation)
+}
+
+func RegisterCurrencyRoutes(router *gin.Engine, controller *controllers.CurrencyController) {
+	router.GET("/currencies", controller.GetCurrencies)
+	router.GET("/currencies/:code", controller.GetCurrencyByCode)
+	router.POST("/currencies", controller.CreateCurrency)
+	router.PUT("/currencies/:code", controller.UpdateCurrency)
+}
+
+func RegisterPriceListRoutes(router *gin.Engine, controller *controllers.PriceListController) {
+	router.GET("/price-lists", controller.GetPriceLists)
+	router.GET("/price-lists/:id", controller.GetPriceListByID)
+	router.POST("/price-lists", controller.CreatePriceList)
+	router.PUT("/price-lists/:id", controller.UpdatePriceList)
+	router.DELETE("/price-lists/:id", controller.DeletePriceList)
+	router.PUT("/customers/:id/price-list", controller.AssignCustomerPriceList)
+}
+
+func RegisterItemImageRoutes(router *gin.Engine, controller *controllers.ItemImageController) {
+	router.POST("/items/:id/images", controller.UploadItemImage)
+	router.DELETE("/items/:id/images/:imageId", controller.DeleteItemImage)
 }
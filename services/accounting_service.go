@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+	"totesbackend/accounting"
+	"totesbackend/config"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ACCOUNTING_DOCUMENT_INVOICE = "invoice"
+	ACCOUNTING_DOCUMENT_PAYMENT = "payment"
+
+	ACCOUNTING_STATUS_PENDING = "pending"
+	ACCOUNTING_STATUS_SYNCED  = "synced"
+	ACCOUNTING_STATUS_FAILED  = "failed"
+)
+
+var errAccountingInvoiceNotSynced = errors.New("waiting for the invoice to be synced first")
+
+// AccountingService envía las facturas y los pagos al sistema contable. Cada documento queda
+// registrado en accounting_syncs al crearse; un worker los envía con el Client configurado y
+// reintenta los fallidos con espera exponencial hasta config.ACCOUNTING_MAX_ATTEMPTS.
+type AccountingService struct {
+	Repo       repositories.AccountingSyncRepositoryInterface
+	Invoices   repositories.InvoiceRepositoryInterface
+	Currencies repositories.CurrencyRepositoryInterface
+	Client     accounting.Client
+	wake       chan struct{}
+	stop       chan struct{}
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+func NewAccountingService(repo repositories.AccountingSyncRepositoryInterface, invoices repositories.InvoiceRepositoryInterface, client accounting.Client) *AccountingService {
+	s := &AccountingService{
+		Repo:     repo,
+		Invoices: invoices,
+		Client:   client,
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// HandleEvents encola cada factura emitida.
+func (s *AccountingService) HandleEvents(bus *EventBus) {
+	Subscribe(bus, func(ctx context.Context, event InvoiceCreated) {
+		s.QueueInvoice(ctx, event.Invoice)
+	})
+}
+
+// QueueInvoice registra la factura para enviarla. Como los correos, nunca hace fallar la
+// operación que la origina: los errores solo se registran en el log.
+func (s *AccountingService) QueueInvoice(ctx context.Context, invoice *models.Invoice) {
+	if s == nil || invoice == nil {
+		return
+	}
+	s.queue(ctx, ACCOUNTING_DOCUMENT_INVOICE, invoice.ID)
+}
+
+// QueuePayment registra el pago de una factura a crédito. Las facturas de contado se envían ya
+// pagadas, así que su pago no se registra aparte.
+func (s *AccountingService) QueuePayment(ctx context.Context, invoice *models.Invoice) {
+	if s == nil || invoice == nil || invoice.DueDate == nil || invoice.PaidAt == nil {
+		return
+	}
+	s.queue(ctx, ACCOUNTING_DOCUMENT_PAYMENT, invoice.ID)
+}
+
+// CancelPayment retira el pago de una factura que se volvió a marcar como pendiente, si aún no se
+// envió. Un pago ya enviado queda en el sistema contable y hay que anularlo allí.
+func (s *AccountingService) CancelPayment(ctx context.Context, invoiceID int) {
+	if s == nil {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	deleted, err := s.Repo.DeleteUnsentSync(ctx, ACCOUNTING_DOCUMENT_PAYMENT, invoiceID, ACCOUNTING_STATUS_SYNCED)
+	if err != nil {
+		log.Printf("error cancelling the accounting payment of invoice %d: %v", invoiceID, err)
+		return
+	}
+	if !deleted {
+		if _, err := s.Repo.GetSyncByDocument(ctx, ACCOUNTING_DOCUMENT_PAYMENT, invoiceID); err == nil {
+			log.Printf("payment of invoice %d was already synced to %s; void it there", invoiceID, s.Client.Provider())
+		}
+	}
+}
+
+func (s *AccountingService) queue(ctx context.Context, documentType string, documentID int) {
+	// la operación que origina el documento ya se hizo: se registra aunque el cliente se desconecte
+	ctx = context.WithoutCancel(ctx)
+	record := &models.AccountingSync{
+		DocumentType:  documentType,
+		DocumentID:    documentID,
+		Provider:      s.Client.Provider(),
+		Status:        ACCOUNTING_STATUS_PENDING,
+		NextAttemptAt: time.Now(),
+		RequestID:     RequestIDFromContext(ctx),
+	}
+	if _, err := s.Repo.CreateSync(ctx, record); err != nil {
+		log.Printf("error queuing %s %d for accounting: %v", documentType, documentID, err)
+		return
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *AccountingService) GetSyncs(ctx context.Context, filter dtos.AccountingSyncFilterDTO) ([]models.AccountingSync, int64, error) {
+	return s.Repo.GetSyncs(ctx, filter)
+}
+
+// Resync vuelve a encolar un documento que agotó sus intentos. Devuelve false si no está fallido.
+func (s *AccountingService) Resync(ctx context.Context, id int) (*models.AccountingSync, bool, error) {
+	requeued, err := s.Repo.RequeueSync(ctx, id, ACCOUNTING_STATUS_FAILED, ACCOUNTING_STATUS_PENDING, time.Now())
+	if err != nil || !requeued {
+		return nil, requeued, err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	record, err := s.Repo.GetSyncByID(ctx, id)
+	return record, true, err
+}
+
+// Close detiene el envío; los documentos pendientes quedan guardados y se envían al reiniciar.
+func (s *AccountingService) Close() {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+}
+
+func (s *AccountingService) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(config.ACCOUNTING_POLL_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+		s.syncDue()
+	}
+}
+
+func (s *AccountingService) syncDue() {
+	// corre en segundo plano, fuera de cualquier petición
+	ctx := context.Background()
+	now := time.Now()
+	records, err := s.Repo.ClaimDueSyncs(ctx, ACCOUNTING_STATUS_PENDING, now, now.Add(config.ACCOUNTING_SYNC_LEASE), config.ACCOUNTING_BATCH_SIZE)
+	if err != nil {
+		log.Printf("error loading pending accounting documents: %v", err)
+		return
+	}
+
+	for i := range records {
+		record := &records[i]
+		s.attempt(ctx, record)
+		if err := s.Repo.SaveSync(ctx, record); err != nil {
+			log.Printf("error updating accounting sync %d: %v", record.ID, err)
+		}
+	}
+}
+
+// attempt envía el documento y actualiza su estado.
+func (s *AccountingService) attempt(ctx context.Context, record *models.AccountingSync) {
+	result, err := s.push(ctx, record)
+	now := time.Now()
+	if errors.Is(err, errAccountingInvoiceNotSynced) {
+		// no cuenta como intento: el pago sale en cuanto la factura se envíe
+		record.LastError = err.Error()
+		record.NextAttemptAt = now.Add(config.ACCOUNTING_RETRY_BASE_DELAY)
+		return
+	}
+
+	record.Attempts++
+	if err == nil {
+		record.Status = ACCOUNTING_STATUS_SYNCED
+		record.ExternalID = result.ExternalID
+		record.ExternalNumber = result.ExternalNumber
+		record.LastError = ""
+		record.SyncedAt = &now
+		return
+	}
+
+	record.LastError = err.Error()
+	if len(record.LastError) > 500 {
+		record.LastError = record.LastError[:500]
+	}
+	log.Printf("accounting sync of %s %d failed (attempt %d): %v", record.DocumentType, record.DocumentID, record.Attempts, err)
+	if record.Attempts >= config.ACCOUNTING_MAX_ATTEMPTS {
+		record.Status = ACCOUNTING_STATUS_FAILED
+		return
+	}
+	record.NextAttemptAt = now.Add(accountingRetryDelay(record.Attempts))
+}
+
+func (s *AccountingService) push(ctx context.Context, record *models.AccountingSync) (accounting.Result, error) {
+	invoice, err := s.Invoices.GetInvoiceByID(ctx, strconv.Itoa(record.DocumentID))
+	if err != nil {
+		return accounting.Result{}, err
+	}
+
+	switch record.DocumentType {
+	case ACCOUNTING_DOCUMENT_INVOICE:
+		rates, err := loadCurrencyRates(ctx, s.Currencies)
+		if err != nil {
+			return accounting.Result{}, err
+		}
+		document, err := accountingInvoice(invoice, rates)
+		if err != nil {
+			return accounting.Result{}, err
+		}
+		return s.Client.PushInvoice(ctx, document)
+	case ACCOUNTING_DOCUMENT_PAYMENT:
+		invoiceSync, err := s.Repo.GetSyncByDocument(ctx, ACCOUNTING_DOCUMENT_INVOICE, invoice.ID)
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && invoiceSync.Status != ACCOUNTING_STATUS_SYNCED) {
+			return accounting.Result{}, errAccountingInvoiceNotSynced
+		}
+		if err != nil {
+			return accounting.Result{}, err
+		}
+		if invoice.PaidAt == nil {
+			return accounting.Result{}, errors.New("the invoice is no longer marked as paid")
+		}
+		return s.Client.PushPayment(ctx, accounting.Payment{
+			InvoiceID:             invoice.ID,
+			InvoiceExternalID:     invoiceSync.ExternalID,
+			InvoiceExternalNumber: invoiceSync.ExternalNumber,
+			InvoiceDate:           invoice.DateTime,
+			Customer:              accountingCustomer(invoice.Customer),
+			Date:                  *invoice.PaidAt,
+			Amount:                invoice.Total,
+		})
+	default:
+		return accounting.Result{}, errors.New("unknown document type " + record.DocumentType)
+	}
+}
+
+func accountingInvoice(invoice *models.Invoice, rates currencyRates) (accounting.Invoice, error) {
+	// el precio es el actual del ítem, convertido a la moneda de la factura: el worker envía la
+	// factura enseguida, con el mismo precio con que se calculó
+	lines := make([]accounting.Line, len(invoice.Items))
+	for i, item := range invoice.Items {
+		price, err := rates.convert(item.Item.SellingPrice, item.Item.Currency, invoice.Currency)
+		if err != nil {
+			return accounting.Invoice{}, err
+		}
+		lines[i] = accounting.Line{
+			Code:        strconv.Itoa(item.ItemID),
+			Description: item.Item.Name,
+			Quantity:    item.Amount,
+			UnitPrice:   price,
+		}
+	}
+	return accounting.Invoice{
+		ID:           invoice.ID,
+		Date:         invoice.DateTime,
+		DueDate:      invoice.DueDate,
+		Customer:     accountingCustomer(invoice.Customer),
+		Lines:        lines,
+		Subtotal:     invoice.Subtotal,
+		Total:        invoice.Total,
+		Currency:     invoice.Currency,
+		ExchangeRate: invoice.ExchangeRate,
+	}, nil
+}
+
+func accountingCustomer(customer models.Customer) accounting.Customer {
+	return accounting.Customer{
+		Identification: customer.CustomerId,
+		Name:           customer.CustomerName,
+		LastName:       customer.LastName,
+		Email:          customer.Email,
+		IsBusiness:     customer.IsBusiness,
+	}
+}
+
+func accountingRetryDelay(attempts int) time.Duration {
+	delay := config.ACCOUNTING_RETRY_BASE_DELAY
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= config.ACCOUNTING_RETRY_MAX_DELAY {
+			return config.ACCOUNTING_RETRY_MAX_DELAY
+		}
+	}
+	return delay
+}
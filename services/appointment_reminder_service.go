@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+	"totesbackend/models"
+	"totesbackend/notifications"
+	"totesbackend/repositories"
+)
+
+const (
+	APPOINTMENT_REMINDER_STATUS_QUEUED    = "queued"
+	APPOINTMENT_REMINDER_STATUS_OPTED_OUT = "opted_out"
+	APPOINTMENT_REMINDER_STATUS_NO_EMAIL  = "no_email"
+)
+
+// AppointmentReminderService recuerda a los clientes sus citas unas horas antes. Cada etapa se
+// registra una sola vez por cita y fecha, incluso si el cliente desactivó los recordatorios, así el
+// historial muestra también los que no se enviaron.
+type AppointmentReminderService struct {
+	Repo  repositories.AppointmentReminderRepositoryInterface
+	Email *EmailService
+	// Texts envía el recordatorio también por SMS o WhatsApp a quien lo eligió
+	Texts *TextMessageService
+	// opcional: sin él los recordatorios no llevan el enlace para cancelar
+	Links *LinkSigner
+	// Hours son las etapas en horas antes de la cita, ordenadas (APPOINTMENT_REMINDER_HOURS)
+	Hours []int
+}
+
+func NewAppointmentReminderService(repo repositories.AppointmentReminderRepositoryInterface, email *EmailService, hours []int) *AppointmentReminderService {
+	return &AppointmentReminderService{Repo: repo, Email: email, Hours: hours}
+}
+
+// SendAppointmentReminders encola los recordatorios pendientes a la hora now y devuelve cuántos se
+// encolaron. Una cita recibe solo la etapa más cercana que le corresponde: la agendada con dos horas
+// de anticipación no recibe el recordatorio de 24 horas, solo el de una hora.
+func (s *AppointmentReminderService) SendAppointmentReminders(ctx context.Context, now time.Time) (int, error) {
+	queued := 0
+	for i, hours := range s.Hours {
+		after := 0
+		if i > 0 {
+			after = s.Hours[i-1]
+		}
+
+		appointments, err := s.Repo.GetAppointmentsDueForReminder(ctx, hours,
+			now.Add(time.Duration(after)*time.Hour), now.Add(time.Duration(hours)*time.Hour))
+		if err != nil {
+			return queued, err
+		}
+		for _, appointment := range appointments {
+			reminder, err := s.remind(ctx, appointment, hours)
+			if err != nil {
+				return queued, err
+			}
+			if reminder.Status == APPOINTMENT_REMINDER_STATUS_QUEUED {
+				queued++
+			}
+		}
+	}
+	return queued, nil
+}
+
+func (s *AppointmentReminderService) remind(ctx context.Context, appointment models.Appointment, hours int) (*models.AppointmentReminder, error) {
+	reminder := &models.AppointmentReminder{
+		AppointmentID: appointment.ID,
+		HoursBefore:   hours,
+		DateTime:      appointment.DateTime,
+		Email:         appointment.Email,
+		Status:        APPOINTMENT_REMINDER_STATUS_NO_EMAIL,
+	}
+
+	data := notifications.AppointmentReminderData{
+		CustomerName: strings.TrimSpace(appointment.CustomerName + " " + appointment.LastName),
+		DateTime:     appointment.DateTime,
+		Address:      appointment.Address,
+		HoursBefore:  hours,
+		CancelURL:    appointmentCancelURL(s.Links, &appointment),
+	}
+	if appointment.Email != "" {
+		recipient := EmailRecipient{Type: NOTIFICATION_RECIPIENT_CUSTOMER, ID: appointment.CustomerID, Email: appointment.Email}
+		// si no se pudo encolar no queda registro, así se reintenta en la próxima corrida
+		message, err := s.Email.Notify(ctx, recipient, NOTIFICATION_EVENT_APPOINTMENT_REMINDER, notifications.TEMPLATE_APPOINTMENT_REMINDER, data)
+		if err != nil {
+			return nil, err
+		}
+		reminder.Status = APPOINTMENT_REMINDER_STATUS_OPTED_OUT
+		if message != nil {
+			reminder.Status = APPOINTMENT_REMINDER_STATUS_QUEUED
+			reminder.EmailMessageID = &message.ID
+		}
+	}
+	// el mensaje de texto se suma al correo; Status refleja el correo salvo que solo se envíe el texto
+	text, err := s.Texts.Notify(ctx, appointment.CustomerID, NOTIFICATION_EVENT_APPOINTMENT_REMINDER, notifications.TEMPLATE_APPOINTMENT_REMINDER, data)
+	if err != nil {
+		return nil, err
+	}
+	if text != nil {
+		reminder.TextMessageID = &text.ID
+		reminder.Status = APPOINTMENT_REMINDER_STATUS_QUEUED
+	}
+
+	if err := s.Repo.CreateReminder(ctx, reminder); err != nil {
+		return nil, err
+	}
+	return reminder, nil
+}
+
+func (s *AppointmentReminderService) GetAppointmentReminders(ctx context.Context, appointmentID int) ([]models.AppointmentReminder, error) {
+	return s.Repo.GetRemindersByAppointment(ctx, appointmentID)
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/repositories"
+)
+
+type AppointmentReportService struct {
+	Repo repositories.AppointmentReportRepositoryInterface
+}
+
+func NewAppointmentReportService(repo repositories.AppointmentReportRepositoryInterface) *AppointmentReportService {
+	return &AppointmentReportService{Repo: repo}
+}
+
+// GetAppointmentReport resume las citas del rango por día, semana o mes, con la tasa de inasistencia,
+// y las horas del día con más citas para planear el personal de recepción.
+func (s *AppointmentReportService) GetAppointmentReport(ctx context.Context, from, to time.Time, groupBy string) (*dtos.AppointmentReportDTO, error) {
+	if groupBy != "day" && groupBy != "week" && groupBy != "month" {
+		return nil, fmt.Errorf("%w: groupBy must be day, week or month", ErrInvalidReportParams)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: 'to' date must be after 'from' date", ErrInvalidReportParams)
+	}
+
+	periods, err := s.Repo.GetAppointmentCountsByPeriod(ctx, from, to, groupBy)
+	if err != nil {
+		return nil, err
+	}
+	hours, err := s.Repo.GetAppointmentCountsByHour(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dtos.AppointmentReportDTO{
+		From:         from,
+		To:           to,
+		GroupBy:      groupBy,
+		Periods:      []dtos.AppointmentPeriodDTO{},
+		BusiestHours: []dtos.AppointmentHourDTO{},
+	}
+	for _, period := range periods {
+		fillNoShowRate(&period)
+		report.Periods = append(report.Periods, period)
+		report.Totals.Total += period.Total
+		report.Totals.Pending += period.Pending
+		report.Totals.Confirmed += period.Confirmed
+		report.Totals.Completed += period.Completed
+		report.Totals.Cancelled += period.Cancelled
+		report.Totals.NoShow += period.NoShow
+	}
+	fillNoShowRate(&report.Totals)
+	report.BusiestHours = append(report.BusiestHours, hours...)
+
+	return report, nil
+}
+
+func fillNoShowRate(period *dtos.AppointmentPeriodDTO) {
+	resolved := period.Completed + period.NoShow
+	if resolved > 0 {
+		period.NoShowRate = float64(period.NoShow) / float64(resolved)
+	}
+}
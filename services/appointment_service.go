@@ -1,72 +1,542 @@
 package services
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
+	"totesbackend/captcha"
+	"totesbackend/config"
+	"totesbackend/dtos"
 	"totesbackend/models"
+	"totesbackend/notifications"
 	"totesbackend/repositories"
+
+	"gorm.io/gorm"
 )
 
+const appointmentCancelLinkPurpose = "appointment.cancel"
+
+var ErrAppointmentSlotFull = errors.New("there are no appointments left at this date and time")
+var ErrAppointmentSlotUnavailable = errors.New("the requested time is not a bookable slot")
+var ErrInvalidAppointmentState = errors.New("invalid appointment state")
+var ErrCaptchaFailed = errors.New("captcha verification failed")
+var ErrInvalidAppointmentEmployee = errors.New("invalid appointment employee")
+var ErrEmployeeUnavailable = errors.New("the employee has no appointments left at this date and time")
+
+// appointmentStateTransitions son los estados a los que puede pasar una cita desde cada estado;
+// completed, cancelled y no_show son finales.
+var appointmentStateTransitions = map[int][]int{
+	config.APPOINTMENT_STATE_PENDING:   {config.APPOINTMENT_STATE_CONFIRMED, config.APPOINTMENT_STATE_CANCELLED},
+	config.APPOINTMENT_STATE_CONFIRMED: {config.APPOINTMENT_STATE_COMPLETED, config.APPOINTMENT_STATE_CANCELLED, config.APPOINTMENT_STATE_NO_SHOW},
+}
+
 type AppointmentService struct {
-	Repo *repositories.AppointmentRepository
+	Repo      repositories.AppointmentRepositoryInterface
+	Customers repositories.CustomerRepositoryInterface
+	Employees repositories.EmployeeRepositoryInterface
+	Tx        repositories.Transactor
+	// Bus recibe los eventos de las citas: creadas, editadas, con nuevo estado y borradas
+	Bus   *EventBus
+	Email *EmailService
+	// Texts envía la confirmación también por SMS o WhatsApp a quien lo eligió
+	Texts *TextMessageService
+	Links *LinkSigner
+	// Hours da el horario de atención de cada día; sin él se usa el de config
+	Hours *BusinessHoursService
+	// Captcha verifica el token de las reservas públicas; sin él no se pide captcha
+	Captcha captcha.Verifier
+	// ConfirmationEmails envía la confirmación al cliente al agendar (APPOINTMENT_CONFIRMATION_EMAIL)
+	ConfirmationEmails bool
 }
 
-func NewAppointmentService(repo *repositories.AppointmentRepository) *AppointmentService {
+func NewAppointmentService(repo repositories.AppointmentRepositoryInterface) *AppointmentService {
 	return &AppointmentService{Repo: repo}
 }
 
-func (s *AppointmentService) GetAppointmentByID(id int) (*models.Appointment, error) {
-	return s.Repo.GetAppointmentByID(id)
+func (s *AppointmentService) GetAppointmentByID(ctx context.Context, id int) (*models.Appointment, error) {
+	return s.Repo.GetAppointmentByID(ctx, id)
+}
+
+func (s *AppointmentService) GetAllAppointments(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Appointment, int64, error) {
+	return s.Repo.GetAllAppointments(ctx, pagination)
+}
+
+func (s *AppointmentService) SearchAppointmentsByState(ctx context.Context, stateID int, pagination dtos.PaginationDTO) ([]models.Appointment, int64, error) {
+	return s.Repo.SearchAppointmentsByState(ctx, stateID, pagination)
+}
+
+func (s *AppointmentService) GetAppointmentStateTypes(ctx context.Context) ([]models.AppointmentStateType, error) {
+	return s.Repo.GetAppointmentStateTypes(ctx)
+}
+
+// ChangeAppointmentState mueve la cita a stateID si la transición es válida y la registra en el
+// historial. Una cita solo se completa o se marca como no asistida desde su hora.
+func (s *AppointmentService) ChangeAppointmentState(ctx context.Context, id, stateID int, reason string) (*models.Appointment, error) {
+	appointment, err := s.Repo.GetAppointmentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	states, err := s.Repo.GetAppointmentStateTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int]string, len(states))
+	for _, state := range states {
+		names[state.ID] = state.Name
+	}
+	if _, ok := names[stateID]; !ok {
+		return nil, fmt.Errorf("%w: unknown state %d", ErrInvalidAppointmentState, stateID)
+	}
+
+	allowed := false
+	for _, next := range appointmentStateTransitions[appointment.StateID] {
+		allowed = allowed || next == stateID
+	}
+	if !allowed {
+		return nil, fmt.Errorf("%w: cannot go from %s to %s", ErrInvalidAppointmentState, names[appointment.StateID], names[stateID])
+	}
+	now := time.Now()
+	if (stateID == config.APPOINTMENT_STATE_COMPLETED || stateID == config.APPOINTMENT_STATE_NO_SHOW) && appointment.DateTime.After(now) {
+		return nil, fmt.Errorf("%w: the appointment has not started yet", ErrInvalidAppointmentState)
+	}
+
+	change := &models.AppointmentStateChange{
+		AppointmentID: id,
+		FromStateID:   appointment.StateID,
+		ToStateID:     stateID,
+		Reason:        strings.TrimSpace(reason),
+		ChangedBy:     UserFromContext(ctx),
+		ChangedAt:     now,
+	}
+	changed, err := s.Repo.ChangeAppointmentState(ctx, change)
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		return nil, fmt.Errorf("%w: the appointment changed meanwhile, reload it and try again", ErrInvalidAppointmentState)
+	}
+
+	updated, err := s.Repo.GetAppointmentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.Bus.Publish(ctx, AppointmentStateChanged{Appointment: updated})
+	return updated, nil
+}
+
+func (s *AppointmentService) GetAppointmentStateHistory(ctx context.Context, id int) ([]models.AppointmentStateChange, error) {
+	if _, err := s.Repo.GetAppointmentByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetAppointmentStateHistory(ctx, id)
+}
+
+func (s *AppointmentService) GetAppointmentsByCustomerID(ctx context.Context, customerID int, pagination dtos.PaginationDTO) ([]models.Appointment, int64, error) {
+	return s.Repo.GetAppointmentsByCustomerID(ctx, customerID, pagination)
+}
+
+func (s *AppointmentService) CreateAppointment(ctx context.Context, appointment models.Appointment) (*models.Appointment, error) {
+	return s.createAppointment(ctx, appointment, s.ConfirmationEmails)
+}
+
+func (s *AppointmentService) createAppointment(ctx context.Context, appointment models.Appointment, confirm bool) (*models.Appointment, error) {
+	appointment.StateID = config.APPOINTMENT_STATE_PENDING
+	appointment.State = nil
+
+	// se revisa antes de crear el cliente para no dejar clientes de reservas rechazadas; reserveSlot
+	// lo vuelve a revisar con el horario bloqueado
+	if err := s.checkCapacity(ctx, s.Repo, &appointment); err != nil {
+		return nil, err
+	}
+
+	if appointment.CustomerID == 0 && s.Customers != nil {
+		if _, err := s.linkCustomer(ctx, &appointment); err != nil {
+			return nil, err
+		}
+	}
+
+	var created *models.Appointment
+	err := s.reserveSlot(ctx, &appointment, func(repo repositories.AppointmentRepositoryInterface) error {
+		var err error
+		created, err = repo.CreateAppointment(ctx, &appointment)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.Bus.Publish(ctx, AppointmentBooked{Appointment: created, SendConfirmation: confirm})
+	return created, nil
+}
+
+// HandleEvents envía la confirmación de las citas nuevas que la piden.
+func (s *AppointmentService) HandleEvents(bus *EventBus) {
+	Subscribe(bus, func(ctx context.Context, event AppointmentBooked) {
+		if event.SendConfirmation {
+			s.sendConfirmation(ctx, event.Appointment)
+		}
+	})
+}
+
+// reserveSlot bloquea el horario de la cita, revisa el cupo y la guarda con save en una transacción,
+// así dos reservas simultáneas no pueden tomar el mismo cupo.
+func (s *AppointmentService) reserveSlot(ctx context.Context, appointment *models.Appointment, save func(repo repositories.AppointmentRepositoryInterface) error) error {
+	return s.Tx.Transaction(ctx, func(tx repositories.Tx) error {
+		repo := s.Repo.WithTx(tx)
+		if err := repo.LockSlot(ctx, appointment.DateTime); err != nil {
+			return err
+		}
+		if err := s.checkCapacity(ctx, repo, appointment); err != nil {
+			return err
+		}
+		return save(repo)
+	})
+}
+
+// checkCapacity revisa que quede cupo para la cita: en la agenda de su empleado si tiene uno, o en los
+// cupos generales del horario si no. La cita misma no cuenta, para poder revisarla al editarla.
+func (s *AppointmentService) checkCapacity(ctx context.Context, repo repositories.AppointmentRepositoryInterface, appointment *models.Appointment) error {
+	if appointment.EmployeeID == nil {
+		count, err := repo.CountAppointmentsAtDateTime(ctx, appointment.DateTime, appointment.ID)
+		if err != nil {
+			return err
+		}
+		if count >= config.APPOINTMENT_SLOT_CAPACITY {
+			return ErrAppointmentSlotFull
+		}
+		return nil
+	}
+
+	if _, err := s.GetEmployee(ctx, *appointment.EmployeeID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: employee %d does not exist", ErrInvalidAppointmentEmployee, *appointment.EmployeeID)
+		}
+		return err
+	}
+	count, err := repo.CountEmployeeAppointmentsAtDateTime(ctx, *appointment.EmployeeID, appointment.DateTime, appointment.ID)
+	if err != nil {
+		return err
+	}
+	if count >= config.APPOINTMENT_EMPLOYEE_SLOT_CAPACITY {
+		return ErrEmployeeUnavailable
+	}
+	return nil
+}
+
+func (s *AppointmentService) GetEmployee(ctx context.Context, id int) (*models.Employee, error) {
+	return s.Employees.GetEmployeeByID(ctx, strconv.Itoa(id))
+}
+
+// GetEmployeeAppointments devuelve la agenda del empleado entre from y to; los extremos nil no
+// limitan.
+func (s *AppointmentService) GetEmployeeAppointments(ctx context.Context, employeeID int, from, to *time.Time, pagination dtos.PaginationDTO) ([]models.Appointment, int64, error) {
+	return s.Repo.GetAppointmentsByEmployeeID(ctx, employeeID, from, to, pagination)
+}
+
+// GetAvailableSlots devuelve los horarios de date, dentro del horario de atención, que aún no han
+// pasado y tienen cupo. Solo cuentan los cupos generales: las citas con empleado no los ocupan.
+func (s *AppointmentService) GetAvailableSlots(ctx context.Context, date time.Time) ([]dtos.AppointmentSlotDTO, error) {
+	counts, hours, err := s.hourlyCounts(ctx, date, true)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	slots := []dtos.AppointmentSlotDTO{}
+	for i, count := range counts {
+		slot := time.Date(date.Year(), date.Month(), date.Day(), hours.OpeningHour+i, 0, 0, 0, date.Location())
+		if slot.After(now) && count < config.APPOINTMENT_SLOT_CAPACITY {
+			slots = append(slots, dtos.AppointmentSlotDTO{DateTime: slot, Available: config.APPOINTMENT_SLOT_CAPACITY - count})
+		}
+	}
+	return slots, nil
+}
+
+// BookAppointment agenda la cita que pide un cliente desde la web. Solo acepta horarios en punto
+// dentro del horario de atención y hasta config.PUBLIC_BOOKING_MAX_DAYS_AHEAD días; el cupo es el
+// mismo de CreateAppointment. La confirmación con el enlace para cancelar se envía siempre. Con
+// Captcha, el token de la reserva se verifica antes que todo lo demás.
+func (s *AppointmentService) BookAppointment(ctx context.Context, booking dtos.PublicAppointmentBookingDTO, clientIP string) (*dtos.PublicAppointmentDTO, error) {
+	if s.Captcha != nil {
+		if err := s.Captcha.Verify(ctx, booking.CaptchaToken, clientIP); err != nil {
+			if errors.Is(err, captcha.ErrCaptchaRejected) {
+				return nil, ErrCaptchaFailed
+			}
+			return nil, err
+		}
+	}
+
+	local := booking.DateTime.In(time.Local)
+	slot := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), 0, 0, 0, time.Local)
+	hours, err := s.Hours.HoursForDate(ctx, slot)
+	if err != nil {
+		return nil, err
+	}
+	if !slot.Equal(local) || !hours.Open || slot.Hour() < hours.OpeningHour || slot.Hour() > hours.LastSlotHour ||
+		!slot.After(time.Now()) || slot.After(time.Now().AddDate(0, 0, config.PUBLIC_BOOKING_MAX_DAYS_AHEAD)) {
+		return nil, ErrAppointmentSlotUnavailable
+	}
+
+	appointment := models.Appointment{
+		DateTime:         slot,
+		CustomerName:     strings.TrimSpace(booking.CustomerName),
+		LastName:         strings.TrimSpace(booking.LastName),
+		Email:            strings.TrimSpace(booking.Email),
+		PhoneNumbers:     strings.TrimSpace(booking.PhoneNumbers),
+		Address:          strings.TrimSpace(booking.Address),
+		CustomerState:    true,
+		IdentifierTypeID: booking.IdentifierTypeID,
+	}
+	created, err := s.createAppointment(ctx, appointment, true)
+	if err != nil {
+		return nil, err
+	}
+	return &dtos.PublicAppointmentDTO{ID: created.ID, DateTime: created.DateTime, CancelURL: s.cancelURL(created)}, nil
+}
+
+// LinkAppointmentCustomers asocia las citas que no apuntan a ningún cliente con el cliente de su
+// correo, creándolo si no existe.
+func (s *AppointmentService) LinkAppointmentCustomers(ctx context.Context) (*dtos.AppointmentCustomerLinkDTO, error) {
+	result := &dtos.AppointmentCustomerLinkDTO{}
+	afterID := 0
+	for {
+		appointments, err := s.Repo.GetUnlinkedAppointments(ctx, afterID, config.APPOINTMENT_LINK_BATCH_SIZE)
+		if err != nil {
+			return result, err
+		}
+		if len(appointments) == 0 {
+			return result, nil
+		}
+
+		for i := range appointments {
+			appointment := &appointments[i]
+			afterID = appointment.ID
+			result.Scanned++
+
+			if strings.TrimSpace(appointment.Email) == "" {
+				result.Skipped++
+				continue
+			}
+			created, err := s.linkCustomer(ctx, appointment)
+			if err != nil {
+				return result, err
+			}
+			if err := s.Repo.SetAppointmentCustomer(ctx, appointment.ID, appointment.CustomerID); err != nil {
+				return result, err
+			}
+			result.Linked++
+			if created {
+				result.CreatedCustomers++
+			}
+		}
+	}
+}
+
+// linkCustomer asigna a la cita el cliente con su correo (sin distinguir mayúsculas) o, si no hay
+// ninguno, crea uno con los datos de la cita. Devuelve true si creó el cliente.
+func (s *AppointmentService) linkCustomer(ctx context.Context, appointment *models.Appointment) (bool, error) {
+	email := strings.TrimSpace(appointment.Email)
+	if email == "" {
+		return false, nil
+	}
+
+	customer, err := s.Customers.FindCustomerByEmail(ctx, email)
+	if err != nil {
+		return false, err
+	}
+	created := false
+	if customer == nil {
+		newCustomer, err := newCustomerFromAppointment(appointment, email)
+		if err != nil {
+			return false, err
+		}
+		customer, err = s.Customers.CreateCustomer(ctx, newCustomer)
+		if err != nil {
+			// otra cita con el mismo correo pudo crear el cliente al mismo tiempo
+			existing, findErr := s.Customers.FindCustomerByEmail(ctx, email)
+			if findErr != nil || existing == nil {
+				return false, err
+			}
+			customer = existing
+		} else {
+			created = true
+		}
+	}
+
+	appointment.CustomerID = customer.ID
+	return created, nil
+}
+
+// newCustomerFromAppointment arma un cliente mínimo. La cita no trae el número de documento, así que
+// queda uno provisional (APPT-...) hasta que se complete el registro.
+func newCustomerFromAppointment(appointment *models.Appointment, email string) (*models.Customer, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	return &models.Customer{
+		CustomerName:     appointment.CustomerName,
+		LastName:         appointment.LastName,
+		CustomerId:       "APPT-" + hex.EncodeToString(buf),
+		IsBusiness:       appointment.IsBusiness,
+		Address:          appointment.Address,
+		PhoneNumbers:     appointment.PhoneNumbers,
+		CustomerState:    true,
+		Email:            email,
+		IdentifierTypeID: appointment.IdentifierTypeID,
+	}, nil
 }
 
-func (s *AppointmentService) GetAllAppointments() ([]models.Appointment, error) {
-	return s.Repo.GetAllAppointments()
+// sendConfirmation encola la confirmación por correo y por el canal de texto del cliente, con un
+// enlace para cancelar que vale hasta la hora de la cita.
+func (s *AppointmentService) sendConfirmation(ctx context.Context, appointment *models.Appointment) {
+	data := notifications.AppointmentConfirmationData{
+		CustomerName: strings.TrimSpace(appointment.CustomerName + " " + appointment.LastName),
+		DateTime:     appointment.DateTime,
+		Address:      appointment.Address,
+		CancelURL:    s.cancelURL(appointment),
+	}
+	recipient := EmailRecipient{Type: NOTIFICATION_RECIPIENT_CUSTOMER, ID: appointment.CustomerID, Email: appointment.Email}
+	_, _ = s.Email.Notify(ctx, recipient, NOTIFICATION_EVENT_APPOINTMENT_CONFIRMATION, notifications.TEMPLATE_APPOINTMENT_CONFIRMATION, data)
+	_, _ = s.Texts.Notify(ctx, appointment.CustomerID, NOTIFICATION_EVENT_APPOINTMENT_CONFIRMATION, notifications.TEMPLATE_APPOINTMENT_CONFIRMATION, data)
 }
 
-func (s *AppointmentService) SearchAppointmentsByState(state bool) ([]models.Appointment, error) {
-	return s.Repo.SearchAppointmentsByState(state)
+func (s *AppointmentService) cancelURL(appointment *models.Appointment) string {
+	return appointmentCancelURL(s.Links, appointment)
 }
 
-func (s *AppointmentService) GetAppointmentsByCustomerID(customerID int) ([]models.Appointment, error) {
-	return s.Repo.GetAppointmentsByCustomerID(customerID)
+// appointmentCancelURL es el enlace firmado para cancelar la cita; vacío si la cita ya pasó.
+func appointmentCancelURL(links *LinkSigner, appointment *models.Appointment) string {
+	if links == nil || !appointment.DateTime.After(time.Now()) {
+		return ""
+	}
+	return links.URL("/appointments/cancel", appointmentCancelLinkPurpose, appointment.DateTime,
+		strconv.Itoa(appointment.ID), strconv.FormatInt(appointment.DateTime.Unix(), 10))
+}
+
+// GetAppointmentByCancelToken devuelve la cita de un enlace de cancelación. El enlace deja de valer
+// si la cita se reprogramó o ya está en un estado final.
+func (s *AppointmentService) GetAppointmentByCancelToken(ctx context.Context, token string) (*models.Appointment, error) {
+	if s.Links == nil {
+		return nil, ErrInvalidLinkToken
+	}
+	fields, err := s.Links.Verify(appointmentCancelLinkPurpose, token)
+	if err != nil || len(fields) != 2 {
+		return nil, ErrInvalidLinkToken
+	}
+	id, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return nil, ErrInvalidLinkToken
+	}
+
+	appointment, err := s.Repo.GetAppointmentByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidLinkToken
+		}
+		return nil, err
+	}
+	if strconv.FormatInt(appointment.DateTime.Unix(), 10) != fields[1] || len(appointmentStateTransitions[appointment.StateID]) == 0 {
+		return nil, ErrInvalidLinkToken
+	}
+	return appointment, nil
 }
 
-func (s *AppointmentService) CreateAppointment(appointment models.Appointment) (*models.Appointment, error) {
-	count, err := s.Repo.CountAppointmentsAtDateTime(appointment.DateTime)
+// CancelAppointmentByToken cancela la cita del enlace de cancelación y libera su horario.
+func (s *AppointmentService) CancelAppointmentByToken(ctx context.Context, token string) (*models.Appointment, error) {
+	appointment, err := s.GetAppointmentByCancelToken(ctx, token)
 	if err != nil {
 		return nil, err
 	}
+	cancelled, err := s.ChangeAppointmentState(ctx, appointment.ID, config.APPOINTMENT_STATE_CANCELLED, "cancelled by the customer from the email link")
+	if errors.Is(err, ErrInvalidAppointmentState) {
+		return nil, ErrInvalidLinkToken
+	}
+	return cancelled, err
+}
 
-	if count >= 3 {
-		return nil, errors.New("no hay mas citas disponibles en este horario :v")
+// UpdateAppointment guarda la cita si sigue en appointment.Version; si no, devuelve ErrVersionConflict.
+// El estado se conserva. Si cambia el empleado o la hora, revisa que el empleado tenga cupo.
+func (s *AppointmentService) UpdateAppointment(ctx context.Context, appointment *models.Appointment) error {
+	current, err := s.Repo.GetAppointmentByID(ctx, appointment.ID)
+	if err != nil {
+		return err
 	}
+	// el estado solo cambia con ChangeAppointmentState
+	appointment.StateID = current.StateID
+	appointment.State = current.State
 
-	return s.Repo.CreateAppointment(&appointment)
+	var updated bool
+	save := func(repo repositories.AppointmentRepositoryInterface) error {
+		var err error
+		updated, err = repo.UpdateAppointment(ctx, appointment)
+		return err
+	}
+	// una cita cancelada no ocupa cupo, así que moverla no necesita revisarlo
+	moved := !sameEmployee(current.EmployeeID, appointment.EmployeeID) || !current.DateTime.Equal(appointment.DateTime)
+	if moved && current.StateID != config.APPOINTMENT_STATE_CANCELLED {
+		err = s.reserveSlot(ctx, appointment, save)
+	} else {
+		err = save(s.Repo)
+	}
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrVersionConflict
+	}
+
+	s.Bus.Publish(ctx, AppointmentUpdated{Appointment: appointment})
+	return nil
+}
+
+func sameEmployee(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
 }
 
-func (s *AppointmentService) UpdateAppointment(appointment *models.Appointment) error {
-	return s.Repo.UpdateAppointment(appointment)
+func (s *AppointmentService) SearchAppointmentsByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Appointment, int64, error) {
+	return s.Repo.SearchAppointmentsByID(ctx, query, pagination)
 }
 
-func (s *AppointmentService) SearchAppointmentsByID(query string) ([]models.Appointment, error) {
-	return s.Repo.SearchAppointmentsByID(query)
+func (s *AppointmentService) SearchAppointmentsByCustomerID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Appointment, int64, error) {
+	return s.Repo.SearchAppointmentsByCustomerID(ctx, query, pagination)
 }
 
-func (s *AppointmentService) SearchAppointmentsByCustomerID(query string) ([]models.Appointment, error) {
-	return s.Repo.SearchAppointmentsByCustomerID(query)
+func (s *AppointmentService) GetAppointmentByCustomerIDAndDate(ctx context.Context, customerID int, dateTime time.Time) (*models.Appointment, error) {
+	return s.Repo.GetAppointmentByCustomerIDAndDate(ctx, customerID, dateTime)
 }
 
-func (s *AppointmentService) GetAppointmentByCustomerIDAndDate(customerID int, dateTime time.Time) (*models.Appointment, error) {
-	return s.Repo.GetAppointmentByCustomerIDAndDate(customerID, dateTime)
+func (s *AppointmentService) DeleteAppointmentByID(ctx context.Context, id int) error {
+	if err := s.Repo.DeleteAppointmentByID(ctx, id); err != nil {
+		return err
+	}
+
+	s.Bus.Publish(ctx, AppointmentDeleted{ID: id})
+	return nil
 }
 
-func (s *AppointmentService) DeleteAppointmentByID(id int) error {
-	return s.Repo.DeleteAppointmentByID(id)
+// GetHourlyAppointmentCount cuenta las citas de cada franja del horario de atención de date, con y
+// sin empleado; los días cerrados no tienen franjas.
+func (s *AppointmentService) GetHourlyAppointmentCount(ctx context.Context, date time.Time) ([]int, models.BusinessHours, error) {
+	return s.hourlyCounts(ctx, date, false)
 }
 
-func (s *AppointmentService) GetHourlyAppointmentCount(date time.Time) ([]int, error) {
+func (s *AppointmentService) hourlyCounts(ctx context.Context, date time.Time, unassigned bool) ([]int, models.BusinessHours, error) {
 	if s.Repo == nil {
-		return nil, errors.New("appointment repository is not initialized")
+		return nil, models.BusinessHours{}, errors.New("appointment repository is not initialized")
+	}
+	hours, err := s.Hours.HoursForDate(ctx, date)
+	if err != nil || !hours.Open {
+		return []int{}, hours, err
 	}
-	return s.Repo.CountAppointmentsByHourOnDate(date)
+	counts, err := s.Repo.CountAppointmentsByHourOnDate(ctx, date, hours.OpeningHour, hours.LastSlotHour, unassigned)
+	return counts, hours, err
 }
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	"totesbackend/config"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+)
+
+// ArchiveService mueve a archived_invoices y archived_appointments los documentos más antiguos que
+// la configuración de archivado, para que invoices y appointments no crezcan sin límite. Lo
+// archivado se sigue consultando por /archive, sin los índices ni relaciones de las tablas activas.
+type ArchiveService struct {
+	Repo            repositories.ArchiveRepositoryInterface
+	InvoiceDays     int
+	AppointmentDays int
+}
+
+func NewArchiveService(repo repositories.ArchiveRepositoryInterface, cfg config.ArchiveConfig) *ArchiveService {
+	return &ArchiveService{Repo: repo, InvoiceDays: cfg.InvoiceDays, AppointmentDays: cfg.AppointmentDays}
+}
+
+// ArchiveOldDocuments archiva las facturas y citas anteriores a now menos la antigüedad configurada.
+// Los documentos que cambian mientras se archivan se dejan para la próxima corrida.
+func (s *ArchiveService) ArchiveOldDocuments(ctx context.Context, now time.Time) (dtos.ArchiveRunDTO, error) {
+	var result dtos.ArchiveRunDTO
+
+	invoiceCutoff := now.AddDate(0, 0, -s.InvoiceDays)
+	for afterID := 0; ; {
+		invoices, err := s.Repo.GetArchivableInvoices(ctx, invoiceCutoff, afterID, config.ARCHIVE_BATCH_SIZE)
+		if err != nil {
+			return result, err
+		}
+		for _, invoice := range invoices {
+			afterID = invoice.ID
+			record, err := archivedInvoice(invoice, now)
+			if err != nil {
+				return result, err
+			}
+			archived, err := s.Repo.ArchiveInvoice(ctx, record, invoice.Version)
+			if err != nil {
+				return result, err
+			}
+			if archived {
+				result.Invoices++
+			}
+		}
+		if len(invoices) < config.ARCHIVE_BATCH_SIZE {
+			break
+		}
+	}
+
+	appointmentCutoff := now.AddDate(0, 0, -s.AppointmentDays)
+	for afterID := 0; ; {
+		appointments, err := s.Repo.GetArchivableAppointments(ctx, appointmentCutoff, afterID, config.ARCHIVE_BATCH_SIZE)
+		if err != nil {
+			return result, err
+		}
+		for _, appointment := range appointments {
+			afterID = appointment.ID
+			document, err := json.Marshal(appointment)
+			if err != nil {
+				return result, err
+			}
+			record := &models.ArchivedAppointment{
+				ID:         appointment.ID,
+				CustomerID: appointment.CustomerID,
+				DateTime:   appointment.DateTime,
+				Document:   string(document),
+				ArchivedAt: now,
+			}
+			archived, err := s.Repo.ArchiveAppointment(ctx, record, appointment.Version)
+			if err != nil {
+				return result, err
+			}
+			if archived {
+				result.Appointments++
+			}
+		}
+		if len(appointments) < config.ARCHIVE_BATCH_SIZE {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+func (s *ArchiveService) SearchArchivedInvoices(ctx context.Context, filter dtos.ArchiveFilterDTO) (*dtos.PageDTO[dtos.ArchivedDocumentDTO], error) {
+	records, total, err := s.Repo.SearchArchivedInvoices(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	documents := make([]dtos.ArchivedDocumentDTO, len(records))
+	for i, record := range records {
+		documents[i] = archivedInvoiceDTO(record)
+	}
+	return dtos.NewPageDTO(documents, filter.PaginationDTO, total), nil
+}
+
+func (s *ArchiveService) SearchArchivedAppointments(ctx context.Context, filter dtos.ArchiveFilterDTO) (*dtos.PageDTO[dtos.ArchivedDocumentDTO], error) {
+	records, total, err := s.Repo.SearchArchivedAppointments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	documents := make([]dtos.ArchivedDocumentDTO, len(records))
+	for i, record := range records {
+		documents[i] = archivedAppointmentDTO(record)
+	}
+	return dtos.NewPageDTO(documents, filter.PaginationDTO, total), nil
+}
+
+func (s *ArchiveService) GetArchivedInvoice(ctx context.Context, id int) (*dtos.ArchivedDocumentDTO, error) {
+	record, err := s.Repo.GetArchivedInvoiceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	document := archivedInvoiceDTO(*record)
+	return &document, nil
+}
+
+func (s *ArchiveService) GetArchivedAppointment(ctx context.Context, id int) (*dtos.ArchivedDocumentDTO, error) {
+	record, err := s.Repo.GetArchivedAppointmentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	document := archivedAppointmentDTO(*record)
+	return &document, nil
+}
+
+// archivedInvoice guarda la factura con la forma de dtos.GetInvoiceDTO, la misma que devuelve la API.
+func archivedInvoice(invoice models.Invoice, now time.Time) (*models.ArchivedInvoice, error) {
+	items := make([]dtos.BillingItemDTO, len(invoice.Items))
+	for i, item := range invoice.Items {
+		items[i] = dtos.BillingItemDTO{ID: item.ItemID, Stock: item.Amount}
+	}
+	discounts := make([]int, len(invoice.Discounts))
+	for i, discount := range invoice.Discounts {
+		discounts[i] = discount.ID
+	}
+	taxes := make([]int, len(invoice.Taxes))
+	for i, tax := range invoice.Taxes {
+		taxes[i] = tax.ID
+	}
+
+	document, err := json.Marshal(dtos.GetInvoiceDTO{
+		ID:             invoice.ID,
+		EnterpriseData: invoice.EnterpriseData,
+		DateTime:       invoice.DateTime,
+		CustomerID:     invoice.CustomerID,
+		Total:          invoice.Total,
+		Currency:       invoice.Currency,
+		ExchangeRate:   invoice.ExchangeRate,
+		PaymentStatus:  invoice.PaymentStatus,
+		AmountPaid:     invoice.AmountPaid,
+		Balance:        InvoiceBalance(&invoice),
+		Subtotal:       invoice.Subtotal,
+		Items:          items,
+		Discounts:      discounts,
+		Taxes:          taxes,
+		DueDate:        invoice.DueDate,
+		PaidAt:         invoice.PaidAt,
+		Version:        invoice.Version,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &models.ArchivedInvoice{
+		ID:         invoice.ID,
+		CustomerID: invoice.CustomerID,
+		DateTime:   invoice.DateTime,
+		Document:   string(document),
+		ArchivedAt: now,
+	}, nil
+}
+
+func archivedInvoiceDTO(record models.ArchivedInvoice) dtos.ArchivedDocumentDTO {
+	return dtos.ArchivedDocumentDTO{
+		ID:         record.ID,
+		CustomerID: record.CustomerID,
+		DateTime:   record.DateTime,
+		ArchivedAt: record.ArchivedAt,
+		Document:   json.RawMessage(record.Document),
+	}
+}
+
+func archivedAppointmentDTO(record models.ArchivedAppointment) dtos.ArchivedDocumentDTO {
+	return dtos.ArchivedDocumentDTO{
+		ID:         record.ID,
+		CustomerID: record.CustomerID,
+		DateTime:   record.DateTime,
+		ArchivedAt: record.ArchivedAt,
+		Document:   json.RawMessage(record.Document),
+	}
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+	"totesbackend/models"
+	"totesbackend/repositories"
+)
+
+const (
+	AUDIT_ENTITY_CUSTOMER  = "customers"
+	AUDIT_ENTITY_ITEM      = "items"
+	AUDIT_ENTITY_INVOICE   = "invoices"
+	AUDIT_ENTITY_USER      = "users"
+	AUDIT_ENTITY_ROLE      = "roles"
+	AUDIT_ENTITY_USER_TYPE = "user-types"
+
+	AUDIT_ACTION_CREATE  = "create"
+	AUDIT_ACTION_UPDATE  = "update"
+	AUDIT_ACTION_DELETE  = "delete"
+	AUDIT_ACTION_RESTORE = "restore"
+)
+
+var ErrUnknownAuditEntity = errors.New("unknown audit entity")
+
+var auditedEntities = map[string]bool{
+	AUDIT_ENTITY_CUSTOMER:  true,
+	AUDIT_ENTITY_ITEM:      true,
+	AUDIT_ENTITY_INVOICE:   true,
+	AUDIT_ENTITY_USER:      true,
+	AUDIT_ENTITY_ROLE:      true,
+	AUDIT_ENTITY_USER_TYPE: true,
+}
+
+type AuditService struct {
+	Repo repositories.AuditRepositoryInterface
+}
+
+func NewAuditService(repo repositories.AuditRepositoryInterface) *AuditService {
+	return &AuditService{Repo: repo}
+}
+
+// RecordChange guarda una foto JSON del objeto antes y después del cambio. Para los borrados
+// after es nil y para las creaciones y restauraciones before. Los campos de contraseña se ocultan antes de guardar.
+func (s *AuditService) RecordChange(ctx context.Context, entity, entityID, action, userEmail string, before, after interface{}) error {
+	if !auditedEntities[entity] {
+		return ErrUnknownAuditEntity
+	}
+
+	beforeJSON, err := auditSnapshot(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := auditSnapshot(after)
+	if err != nil {
+		return err
+	}
+
+	return s.Repo.CreateAuditEntry(ctx, &models.AuditEntry{
+		Entity:    entity,
+		EntityID:  entityID,
+		Action:    action,
+		UserEmail: userEmail,
+		Before:    beforeJSON,
+		After:     afterJSON,
+		DateTime:  time.Now(),
+		RequestID: RequestIDFromContext(ctx),
+	})
+}
+
+// HandleEvents deja en la auditoría de cada factura quién la emitió.
+func (s *AuditService) HandleEvents(bus *EventBus) {
+	Subscribe(bus, func(ctx context.Context, event InvoiceCreated) {
+		err := s.RecordChange(ctx, AUDIT_ENTITY_INVOICE, strconv.Itoa(event.Invoice.ID), AUDIT_ACTION_CREATE,
+			UserFromContext(ctx), nil, event.Invoice)
+		if err != nil {
+			log.Printf("error recording audit entry for invoice %d: %v", event.Invoice.ID, err)
+		}
+	})
+}
+
+func (s *AuditService) GetAuditTrail(ctx context.Context, entity, entityID string) ([]models.AuditEntry, error) {
+	if !auditedEntities[entity] {
+		return nil, ErrUnknownAuditEntity
+	}
+	return s.Repo.GetAuditEntries(ctx, entity, entityID)
+}
+
+func auditSnapshot(value interface{}) (string, error) {
+	if value == nil {
+		return "null", nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(RedactJSON(raw)), nil
+}
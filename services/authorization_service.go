@@ -1,18 +1,19 @@
 package services
 
 import (
+	"context"
 	"totesbackend/repositories"
 )
 
 type AuthorizationService struct {
-	Repo     *repositories.AuthorizationRepository
-	UserRepo *repositories.UserRepository
+	Repo     repositories.AuthorizationRepositoryInterface
+	UserRepo repositories.UserRepositoryInterface
 }
 
-func NewAuthorizationService(repo *repositories.AuthorizationRepository, userRepo *repositories.UserRepository) *AuthorizationService {
+func NewAuthorizationService(repo repositories.AuthorizationRepositoryInterface, userRepo repositories.UserRepositoryInterface) *AuthorizationService {
 	return &AuthorizationService{Repo: repo, UserRepo: userRepo}
 }
 
-func (s *AuthorizationService) UserHasPermission(email string, permissionID int) (bool, error) {
-	return s.Repo.UserHasPermission(email, permissionID)
+func (s *AuthorizationService) UserHasPermission(ctx context.Context, email string, permissionID int) (bool, error) {
+	return s.Repo.UserHasPermission(ctx, email, permissionID)
 }
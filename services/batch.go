@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"totesbackend/dtos"
+	"totesbackend/repositories"
+)
+
+const (
+	BATCH_OP_CREATE = "create"
+	BATCH_OP_UPDATE = "update"
+	BATCH_OP_DELETE = "delete"
+)
+
+const (
+	BATCH_STATUS_CREATED     = "created"
+	BATCH_STATUS_UPDATED     = "updated"
+	BATCH_STATUS_DELETED     = "deleted"
+	BATCH_STATUS_FAILED      = "failed"
+	BATCH_STATUS_ROLLED_BACK = "rolled_back"
+)
+
+var ErrInvalidBatchOperation = errors.New("invalid batch operation")
+
+var errBatchRolledBack = errors.New("batch rolled back")
+
+// runBatch ejecuta todas las operaciones en una sola transacción. Cada operación corre dentro de
+// un savepoint para que una falla no impida evaluar las siguientes; si alguna falla se deshace el
+// lote completo y las operaciones correctas quedan como rolled_back.
+func runBatch[T any](ctx context.Context, transactor repositories.Transactor, operations []dtos.BatchOperationDTO[T],
+	apply func(tx repositories.Tx, op dtos.BatchOperationDTO[T], result *dtos.BatchResultDTO) error) (*dtos.BatchResponseDTO, error) {
+
+	response := &dtos.BatchResponseDTO{Results: make([]dtos.BatchResultDTO, len(operations))}
+	failed := false
+
+	err := transactor.Transaction(ctx, func(tx repositories.Tx) error {
+		for i, op := range operations {
+			result := &response.Results[i]
+			result.Index = i
+			result.Op = op.Op
+			result.ID = op.ID
+
+			if err := validateBatchOperation(op); err != nil {
+				result.Status = BATCH_STATUS_FAILED
+				result.Error = err.Error()
+				failed = true
+				continue
+			}
+
+			savepoint := "batch_op_" + strconv.Itoa(i)
+			if err := tx.SavePoint(savepoint); err != nil {
+				return err
+			}
+			if err := apply(tx, op, result); err != nil {
+				if rollbackErr := tx.RollbackTo(savepoint); rollbackErr != nil {
+					return rollbackErr
+				}
+				result.Status = BATCH_STATUS_FAILED
+				result.Error = err.Error()
+				result.Data = nil
+				failed = true
+			}
+		}
+
+		if failed {
+			return errBatchRolledBack
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBatchRolledBack) {
+		return nil, err
+	}
+
+	response.Committed = !failed
+	if failed {
+		for i := range response.Results {
+			result := &response.Results[i]
+			if result.Status != BATCH_STATUS_FAILED {
+				result.Status = BATCH_STATUS_ROLLED_BACK
+				result.Data = nil
+				// el ID de un alta deshecha no existe
+				if result.Op == BATCH_OP_CREATE {
+					result.ID = 0
+				}
+			}
+			result.Before = nil
+			result.After = nil
+		}
+	}
+	return response, nil
+}
+
+func validateBatchOperation[T any](op dtos.BatchOperationDTO[T]) error {
+	switch op.Op {
+	case BATCH_OP_CREATE:
+		if op.Data == nil {
+			return fmt.Errorf("%w: create requires data", ErrInvalidBatchOperation)
+		}
+	case BATCH_OP_UPDATE:
+		if op.ID <= 0 || op.Data == nil {
+			return fmt.Errorf("%w: update requires id and data", ErrInvalidBatchOperation)
+		}
+	case BATCH_OP_DELETE:
+		if op.ID <= 0 {
+			return fmt.Errorf("%w: delete requires id", ErrInvalidBatchOperation)
+		}
+	default:
+		return fmt.Errorf("%w: unknown op '%s'", ErrInvalidBatchOperation, op.Op)
+	}
+	return nil
+}
@@ -1,39 +1,81 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"strconv"
+	"time"
+	"totesbackend/config"
 	"totesbackend/dtos"
 	"totesbackend/repositories"
 )
 
 type BillingService struct {
-	Repo         *repositories.ItemRepository
-	DiscountRepo *repositories.DiscountTypeRepository
-	TaxRepo      *repositories.TaxTypeRepository
+	Repo         repositories.ItemRepositoryInterface
+	DiscountRepo repositories.DiscountTypeRepositoryInterface
+	TaxRepo      repositories.TaxTypeRepositoryInterface
+	CurrencyRepo repositories.CurrencyRepositoryInterface
+	PriceRepo    repositories.PriceListRepositoryInterface
 }
 
-func NewBillingService(repo *repositories.ItemRepository, discountRepo *repositories.DiscountTypeRepository, taxRepo *repositories.TaxTypeRepository) *BillingService {
-	return &BillingService{Repo: repo, DiscountRepo: discountRepo, TaxRepo: taxRepo}
+func NewBillingService(repo repositories.ItemRepositoryInterface, discountRepo repositories.DiscountTypeRepositoryInterface, taxRepo repositories.TaxTypeRepositoryInterface,
+	currencyRepo repositories.CurrencyRepositoryInterface, priceRepo repositories.PriceListRepositoryInterface) *BillingService {
+	return &BillingService{Repo: repo, DiscountRepo: discountRepo, TaxRepo: taxRepo, CurrencyRepo: currencyRepo, PriceRepo: priceRepo}
 }
 
-func (s *BillingService) CalculateSubtotal(itemsDTO []dtos.BillingItemDTO) (float64, error) {
+// CalculateSubtotal suma los items en currency (vacía es la moneda base): el precio de cada item es el
+// de la lista de precios de customerID, si la tiene y lo incluye, o su precio de venta, y se convierte
+// de la moneda del item con las tasas guardadas. customerID 0 no tiene lista.
+func (s *BillingService) CalculateSubtotal(ctx context.Context, customerID int, currency string, itemsDTO []dtos.BillingItemDTO) (float64, error) {
+	rates, err := loadCurrencyRates(ctx, s.CurrencyRepo)
+	if err != nil {
+		return 0, err
+	}
+	return s.calculateSubtotal(ctx, rates, customerID, currencyCode(currency), itemsDTO)
+}
+
+func (s *BillingService) calculateSubtotal(ctx context.Context, rates currencyRates, customerID int, currency string, itemsDTO []dtos.BillingItemDTO) (float64, error) {
+	if _, ok := rates[currency]; !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownCurrency, currency)
+	}
+	prices, err := s.customerPrices(ctx, customerID)
+	if err != nil {
+		return 0, err
+	}
+
 	var subtotal float64 = 0
 
 	for _, dto := range itemsDTO {
-		item, err := s.Repo.GetItemByID(strconv.Itoa(dto.ID))
+		item, err := s.Repo.GetItemByID(ctx, strconv.Itoa(dto.ID))
 		if err != nil {
 			return 0, errors.New("item not found with ID: " + strconv.Itoa(dto.ID))
 		}
-		subtotal += item.SellingPrice * float64(dto.Stock)
+		sellingPrice, ok := prices[item.ID]
+		if !ok {
+			sellingPrice = item.SellingPrice
+		}
+		price, err := rates.convert(sellingPrice, item.Currency, currency)
+		if err != nil {
+			return 0, err
+		}
+		subtotal += price * float64(dto.Stock)
 	}
 
 	return subtotal, nil
 }
 
-func (s *BillingService) CalculateTotal(discountTypesIds []string, taxTypesIds []string,
+// CalculateTotal aplica al subtotal en currency los descuentos y los impuestos. Los de valor fijo
+// están en la moneda base y se convierten. Un descuento inactivo o fuera de su vigencia devuelve
+// ErrDiscountNotApplicable y un impuesto inactivo ErrTaxTypeInactive.
+func (s *BillingService) CalculateTotal(ctx context.Context, customerID int, currency string, discountTypesIds []string, taxTypesIds []string,
 	itemsDTO []dtos.BillingItemDTO) (float64, error) {
-	subtotal, err := s.CalculateSubtotal(itemsDTO)
+	rates, err := loadCurrencyRates(ctx, s.CurrencyRepo)
+	if err != nil {
+		return 0, err
+	}
+	currency = currencyCode(currency)
+	subtotal, err := s.calculateSubtotal(ctx, rates, customerID, currency, itemsDTO)
 	if err != nil {
 		return 0, err
 	}
@@ -41,30 +83,52 @@ func (s *BillingService) CalculateTotal(discountTypesIds []string, taxTypesIds [
 	total := subtotal
 
 	for _, discountID := range discountTypesIds {
-		discount, err := s.DiscountRepo.GetDiscountTypeByID(discountID)
+		discount, err := s.DiscountRepo.GetDiscountTypeByID(ctx, discountID)
 		if err != nil {
 			return 0, errors.New("discount not found with ID: " + discountID)
 		}
+		if err := checkDiscountApplicable(discount, time.Now()); err != nil {
+			return 0, err
+		}
 
 		if discount.IsPercentage {
 			total -= (subtotal * (discount.Value / 100))
 		} else {
-			total -= discount.Value
+			value, err := rates.convert(discount.Value, config.BASE_CURRENCY, currency)
+			if err != nil {
+				return 0, err
+			}
+			total -= value
 		}
 	}
 
 	for _, taxID := range taxTypesIds {
-		tax, err := s.TaxRepo.GetTaxTypeByID(taxID)
+		tax, err := s.TaxRepo.GetTaxTypeByID(ctx, taxID)
 		if err != nil {
 			return 0, errors.New("tax not found with ID: " + taxID)
 		}
+		if !tax.Active {
+			return 0, fmt.Errorf("%w: tax %d", ErrTaxTypeInactive, tax.ID)
+		}
 
 		if tax.IsPercentage {
 			total += (subtotal * (tax.Value / 100))
 		} else {
-			total += tax.Value
+			value, err := rates.convert(tax.Value, config.BASE_CURRENCY, currency)
+			if err != nil {
+				return 0, err
+			}
+			total += value
 		}
 	}
 
 	return total, nil
 }
+
+// customerPrices son los precios especiales del cliente por item; sin cliente o sin lista está vacío.
+func (s *BillingService) customerPrices(ctx context.Context, customerID int) (map[int]float64, error) {
+	if customerID == 0 || s.PriceRepo == nil {
+		return nil, nil
+	}
+	return s.PriceRepo.GetCustomerPrices(ctx, customerID)
+}
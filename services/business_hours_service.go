@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"totesbackend/config"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+)
+
+var ErrInvalidBusinessHours = errors.New("invalid business hours")
+
+// BusinessHoursService resuelve el horario de atención de cada día para la agenda de citas.
+type BusinessHoursService struct {
+	Repo repositories.BusinessHoursRepositoryInterface
+}
+
+func NewBusinessHoursService(repo repositories.BusinessHoursRepositoryInterface) *BusinessHoursService {
+	return &BusinessHoursService{Repo: repo}
+}
+
+// GetBusinessHours devuelve los siete días, domingo primero, con el horario por defecto en los que
+// no se han configurado.
+func (s *BusinessHoursService) GetBusinessHours(ctx context.Context) ([]models.BusinessHours, error) {
+	configured, err := s.Repo.GetBusinessHours(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	week := make([]models.BusinessHours, 7)
+	for day := range week {
+		week[day] = defaultBusinessHours(day)
+	}
+	for _, hours := range configured {
+		if hours.Weekday >= 0 && hours.Weekday < len(week) {
+			week[hours.Weekday] = hours
+		}
+	}
+	return week, nil
+}
+
+// HoursForDate devuelve el horario del día de la semana de date. Sin servicio (nil) se usa el
+// horario por defecto.
+func (s *BusinessHoursService) HoursForDate(ctx context.Context, date time.Time) (models.BusinessHours, error) {
+	weekday := int(date.Weekday())
+	if s == nil {
+		return defaultBusinessHours(weekday), nil
+	}
+
+	hours, err := s.Repo.GetBusinessHoursForWeekday(ctx, weekday)
+	if err != nil {
+		return models.BusinessHours{}, err
+	}
+	if hours == nil {
+		return defaultBusinessHours(weekday), nil
+	}
+	return *hours, nil
+}
+
+func (s *BusinessHoursService) UpdateBusinessHours(ctx context.Context, days []dtos.BusinessHoursDTO) ([]models.BusinessHours, error) {
+	if len(days) == 0 {
+		return nil, fmt.Errorf("%w: at least one day is required", ErrInvalidBusinessHours)
+	}
+
+	seen := make(map[int]bool, len(days))
+	hours := make([]models.BusinessHours, 0, len(days))
+	for _, day := range days {
+		if seen[*day.Weekday] {
+			return nil, fmt.Errorf("%w: weekday %d is repeated", ErrInvalidBusinessHours, *day.Weekday)
+		}
+		seen[*day.Weekday] = true
+		if day.Open && day.LastSlotHour < day.OpeningHour {
+			return nil, fmt.Errorf("%w: the last slot of weekday %d starts before the opening hour", ErrInvalidBusinessHours, *day.Weekday)
+		}
+		hours = append(hours, models.BusinessHours{
+			Weekday:      *day.Weekday,
+			Open:         day.Open,
+			OpeningHour:  day.OpeningHour,
+			LastSlotHour: day.LastSlotHour,
+		})
+	}
+
+	if err := s.Repo.SaveBusinessHours(ctx, hours); err != nil {
+		return nil, err
+	}
+	return s.GetBusinessHours(ctx)
+}
+
+func defaultBusinessHours(weekday int) models.BusinessHours {
+	return models.BusinessHours{
+		Weekday:      weekday,
+		Open:         true,
+		OpeningHour:  config.APPOINTMENT_OPENING_HOUR,
+		LastSlotHour: config.APPOINTMENT_LAST_SLOT_HOUR,
+	}
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"totesbackend/dtos"
+)
+
+// ErrInvalidCatalogImport marks imports rejected because of their rows, as opposed to failures while saving them.
+var ErrInvalidCatalogImport = errors.New("invalid catalog import")
+
+// validateCatalogEntries checks the rows of a tax/discount bulk import before anything is persisted,
+// so a single invalid row rejects the whole file.
+func validateCatalogEntries(entries []dtos.ImportCatalogEntryDTO) error {
+	if len(entries) == 0 {
+		return fmt.Errorf("%w: no entries to import", ErrInvalidCatalogImport)
+	}
+
+	for i, entry := range entries {
+		row := strconv.Itoa(i + 1)
+		if strings.TrimSpace(entry.Name) == "" {
+			return fmt.Errorf("%w: entry %s: name is required", ErrInvalidCatalogImport, row)
+		}
+		if entry.Value < 0 {
+			return fmt.Errorf("%w: entry %s: value cannot be negative", ErrInvalidCatalogImport, row)
+		}
+		if entry.IsPercentage && entry.Value > 100 {
+			return fmt.Errorf("%w: entry %s: percentage cannot be greater than 100", ErrInvalidCatalogImport, row)
+		}
+	}
+
+	return nil
+}
@@ -1,42 +1,51 @@
 package services
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
 
 type CommentService struct {
-	Repo *repositories.CommentRepository
+	Repo   repositories.CommentRepositoryInterface
+	Events *EventStreamService
 }
 
-func NewCommentService(repo *repositories.CommentRepository) *CommentService {
+func NewCommentService(repo repositories.CommentRepositoryInterface) *CommentService {
 	return &CommentService{Repo: repo}
 }
 
-func (s *CommentService) GetCommentByID(id int) (*models.Comment, error) {
-	return s.Repo.GetCommentByID(id)
+func (s *CommentService) GetCommentByID(ctx context.Context, id int) (*models.Comment, error) {
+	return s.Repo.GetCommentByID(ctx, id)
 }
 
-func (s *CommentService) SearchCommentsByEmail(email string) ([]models.Comment, error) {
-	return s.Repo.SearchCommentsByEmail(email)
+func (s *CommentService) SearchCommentsByEmail(ctx context.Context, email string, pagination dtos.PaginationDTO) ([]models.Comment, int64, error) {
+	return s.Repo.SearchCommentsByEmail(ctx, email, pagination)
 }
 
-func (s *CommentService) GetAllComments() ([]models.Comment, error) {
-	return s.Repo.GetAllComments()
+func (s *CommentService) GetAllComments(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Comment, int64, error) {
+	return s.Repo.GetAllComments(ctx, pagination)
 }
 
-func (s *CommentService) UpdateComment(comment *models.Comment) error {
-	return s.Repo.UpdateComment(comment)
+func (s *CommentService) UpdateComment(ctx context.Context, comment *models.Comment) error {
+	return s.Repo.UpdateComment(ctx, comment)
 }
 
-func (s *CommentService) CreateComment(comment models.Comment) (*models.Comment, error) {
-	return s.Repo.CreateComment(&comment)
+func (s *CommentService) CreateComment(ctx context.Context, comment models.Comment) (*models.Comment, error) {
+	created, err := s.Repo.CreateComment(ctx, &comment)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Events.Publish(STREAM_EVENT_COMMENT_CREATED, created)
+	return created, nil
 }
 
-func (s *CommentService) SearchCommentsByID(query string) ([]models.Comment, error) {
-	return s.Repo.SearchCommentsByID(query)
+func (s *CommentService) SearchCommentsByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Comment, int64, error) {
+	return s.Repo.SearchCommentsByID(ctx, query, pagination)
 }
 
-func (s *CommentService) SearchCommentsByName(name string) ([]models.Comment, error) {
-	return s.Repo.SearchCommentsByName(name)
+func (s *CommentService) SearchCommentsByName(ctx context.Context, name string, pagination dtos.PaginationDTO) ([]models.Comment, int64, error) {
+	return s.Repo.SearchCommentsByName(ctx, name, pagination)
 }
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"totesbackend/config"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+)
+
+var (
+	ErrInvalidCreditNote       = errors.New("invalid credit note")
+	ErrInvoiceAlreadyCancelled = errors.New("invoice is already cancelled")
+)
+
+type CreditNoteService struct {
+	Repo repositories.CreditNoteRepositoryInterface
+	Bus  *EventBus
+}
+
+func NewCreditNoteService(repo repositories.CreditNoteRepositoryInterface) *CreditNoteService {
+	return &CreditNoteService{Repo: repo}
+}
+
+func (s *CreditNoteService) GetCreditNoteByID(ctx context.Context, id int) (*models.CreditNote, error) {
+	return s.Repo.GetCreditNoteByID(ctx, id)
+}
+
+func (s *CreditNoteService) GetCreditNotes(ctx context.Context, invoiceID *int, pagination dtos.PaginationDTO) ([]models.CreditNote, int64, error) {
+	return s.Repo.GetCreditNotes(ctx, invoiceID, pagination)
+}
+
+// CancelInvoice anula la factura con una nota crédito que devuelve al stock todo lo facturado. Una
+// factura solo se anula una vez: la segunda vez devuelve ErrInvoiceAlreadyCancelled.
+func (s *CreditNoteService) CancelInvoice(ctx context.Context, invoiceID int, reason string) (*models.CreditNote, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: the reason cannot be blank", ErrInvalidCreditNote)
+	}
+
+	note := &models.CreditNote{Reason: reason, CreatedBy: UserFromContext(ctx)}
+	cancelled, err := s.Repo.CancelInvoice(ctx, invoiceID, note, newStockMovement(ctx, config.STOCK_MOVEMENT_CREDIT_NOTE), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return nil, ErrInvoiceAlreadyCancelled
+	}
+
+	s.Bus.Publish(ctx, InvoiceCancelled{CreditNote: note})
+	for _, item := range note.Items {
+		s.Bus.Publish(ctx, StockChanged{dtos.StockChangedEventDTO{ItemID: item.ItemID, Change: item.Amount, Reason: config.STOCK_MOVEMENT_CREDIT_NOTE}})
+	}
+	return s.Repo.GetCreditNoteByID(ctx, note.ID)
+}
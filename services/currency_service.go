@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"totesbackend/config"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidCurrency = errors.New("invalid currency")
+	ErrUnknownCurrency = errors.New("unknown currency")
+	ErrCurrencyTaken   = errors.New("a currency with that code already exists")
+)
+
+type CurrencyService struct {
+	Repo repositories.CurrencyRepositoryInterface
+}
+
+func NewCurrencyService(repo repositories.CurrencyRepositoryInterface) *CurrencyService {
+	return &CurrencyService{Repo: repo}
+}
+
+func (s *CurrencyService) GetCurrencies(ctx context.Context) ([]models.Currency, error) {
+	return s.Repo.GetCurrencies(ctx)
+}
+
+func (s *CurrencyService) GetCurrencyByCode(ctx context.Context, code string) (*models.Currency, error) {
+	return s.Repo.GetCurrencyByCode(ctx, currencyCode(code))
+}
+
+// CreateCurrency agrega una moneda con su tasa de cambio a la moneda base.
+func (s *CurrencyService) CreateCurrency(ctx context.Context, dto dtos.CurrencyDTO) (*models.Currency, error) {
+	currency := &models.Currency{Code: currencyCode(dto.Code)}
+	if len(currency.Code) != 3 || strings.Trim(currency.Code, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return nil, fmt.Errorf("%w: the code must be three letters (ISO 4217), got %q", ErrInvalidCurrency, dto.Code)
+	}
+	if _, err := s.Repo.GetCurrencyByCode(ctx, currency.Code); err == nil {
+		return nil, ErrCurrencyTaken
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err := fillCurrency(currency, dto); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.CreateCurrency(ctx, currency); err != nil {
+		return nil, err
+	}
+	return currency, nil
+}
+
+// UpdateCurrency cambia el nombre, el símbolo y la tasa de una moneda. Las facturas ya emitidas
+// conservan la tasa con que se emitieron.
+func (s *CurrencyService) UpdateCurrency(ctx context.Context, code string, dto dtos.CurrencyDTO) (*models.Currency, error) {
+	currency, err := s.Repo.GetCurrencyByCode(ctx, currencyCode(code))
+	if err != nil {
+		return nil, err
+	}
+	if err := fillCurrency(currency, dto); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.UpdateCurrency(ctx, currency); err != nil {
+		return nil, err
+	}
+	return currency, nil
+}
+
+func fillCurrency(currency *models.Currency, dto dtos.CurrencyDTO) error {
+	currency.Name = strings.TrimSpace(dto.Name)
+	currency.Symbol = strings.TrimSpace(dto.Symbol)
+	currency.ExchangeRate = dto.ExchangeRate
+	currency.UpdatedAt = time.Now()
+	if currency.Name == "" {
+		return fmt.Errorf("%w: the name cannot be blank", ErrInvalidCurrency)
+	}
+	if currency.ExchangeRate <= 0 {
+		return fmt.Errorf("%w: the exchange rate must be greater than zero", ErrInvalidCurrency)
+	}
+	if currency.Code == config.BASE_CURRENCY && currency.ExchangeRate != 1 {
+		return fmt.Errorf("%w: the exchange rate of the base currency %s is always 1", ErrInvalidCurrency, config.BASE_CURRENCY)
+	}
+	return nil
+}
+
+// currencyCode normaliza un código de moneda; vacío es la moneda base.
+func currencyCode(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return config.BASE_CURRENCY
+	}
+	return code
+}
+
+// currencyRates son las tasas de cambio guardadas, por código.
+type currencyRates map[string]float64
+
+func loadCurrencyRates(ctx context.Context, repo repositories.CurrencyRepositoryInterface) (currencyRates, error) {
+	currencies, err := repo.GetCurrencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rates := make(currencyRates, len(currencies))
+	for _, currency := range currencies {
+		rates[currency.Code] = currency.ExchangeRate
+	}
+	return rates, nil
+}
+
+// convert pasa amount de la moneda from a la moneda to, redondeado a config.CURRENCY_DECIMALS.
+func (r currencyRates) convert(amount float64, from, to string) (float64, error) {
+	if from == to {
+		return amount, nil
+	}
+	fromRate, ok := r[from]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownCurrency, from)
+	}
+	toRate, ok := r[to]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownCurrency, to)
+	}
+	return roundCurrency(amount * fromRate / toRate), nil
+}
+
+// roundCurrency redondea un valor a config.CURRENCY_DECIMALS.
+func roundCurrency(amount float64) float64 {
+	scale := math.Pow10(config.CURRENCY_DECIMALS)
+	return math.Round(amount*scale) / scale
+}
@@ -1,50 +1,508 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+	"totesbackend/config"
+	"totesbackend/dtos"
+	"totesbackend/geocoding"
 	"totesbackend/models"
 	"totesbackend/repositories"
+	"totesbackend/services/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	CUSTOMER_DELETION_DELETED  = "deleted"
+	CUSTOMER_DELETION_ARCHIVED = "archived"
+	// CUSTOMER_DELETION_STRATEGY_ARCHIVE desactiva al cliente y conserva todo lo que lo referencia
+	CUSTOMER_DELETION_STRATEGY_ARCHIVE = "archive"
+)
+
+const (
+	CUSTOMER_DUPLICATE_IDENTIFIER     = "identifier"
+	CUSTOMER_DUPLICATE_EMAIL          = "email"
+	CUSTOMER_DUPLICATE_NAME_AND_PHONE = "name_and_phone"
+)
+
+const (
+	CUSTOMER_ADDRESS_VERIFIED  = "verified"
+	CUSTOMER_ADDRESS_NOT_FOUND = "not_found"
 )
 
+var (
+	ErrCustomerRestoreConflict       = errors.New("another customer already uses the same document or email")
+	ErrInvalidCustomerDeleteStrategy = errors.New("invalid deletion strategy")
+	ErrAddressNotFound               = errors.New("the address could not be found")
+	ErrInvalidCustomerSearch         = errors.New("invalid customer search")
+	ErrInvalidCustomerMerge          = errors.New("invalid customer merge")
+)
+
+// columnas por las que se puede ordenar GET /customers/search
+var customerSortColumns = map[string]string{
+	"id":        "id",
+	"name":      "customer_name",
+	"lastName":  "last_name",
+	"email":     "email",
+	"createdAt": "created_at",
+}
+
+// CustomerDeletion es el resultado de DeleteCustomer; After es nil si el cliente se borró.
+type CustomerDeletion struct {
+	Action       string
+	Dependencies dtos.CustomerDependenciesDTO
+	Before       models.Customer
+	After        *models.Customer
+}
+
+// CustomerMerge es el resultado de MergeCustomers; DuplicateBefore es el duplicado antes de
+// desactivarlo.
+type CustomerMerge struct {
+	Primary         models.Customer
+	DuplicateBefore models.Customer
+	Duplicate       models.Customer
+	Reassigned      dtos.CustomerReassignmentDTO
+}
+
+// CustomerGeocoding es el resultado de una corrida de GeocodePendingCustomers.
+type CustomerGeocoding struct {
+	Located  int
+	NotFound int
+}
+
 type CustomerService struct {
-	Repo *repositories.CustomerRepository
+	Repo repositories.CustomerRepositoryInterface
+	Tx   repositories.Transactor
+	// opcional: sin él las direcciones se guardan como llegan y sin ubicación
+	Geocoder geocoding.Geocoder
+	// con Geocoder, rechaza con ErrAddressNotFound las direcciones que el proveedor no encuentra
+	RejectUnknownAddresses bool
+}
+
+func NewCustomerService(repo repositories.CustomerRepositoryInterface, tx repositories.Transactor) *CustomerService {
+	return &CustomerService{Repo: repo, Tx: tx}
+}
+
+func (s *CustomerService) GetCustomerByID(ctx context.Context, id int) (*models.Customer, error) {
+	return s.Repo.GetCustomerByID(ctx, id)
+}
+
+func (s *CustomerService) GetCustomerByCustomerID(ctx context.Context, customerID string) (*models.Customer, error) {
+	return s.Repo.GetCustomerByCustomerID(ctx, customerID)
 }
 
-func NewCustomerService(repo *repositories.CustomerRepository) *CustomerService {
-	return &CustomerService{Repo: repo}
+func (s *CustomerService) GetAllCustomers(ctx context.Context, includeDeleted bool, pagination dtos.PaginationDTO) ([]models.Customer, int64, error) {
+	return s.Repo.GetAllCustomers(ctx, includeDeleted, pagination)
 }
 
-func (s *CustomerService) GetCustomerByID(id int) (*models.Customer, error) {
-	return s.Repo.GetCustomerByID(id)
+func (s *CustomerService) StreamCustomers(ctx context.Context, filter dtos.CSVExportFilterDTO, fn func(models.Customer) error) error {
+	return s.Repo.StreamCustomers(ctx, filter, fn)
 }
 
-func (s *CustomerService) GetCustomerByCustomerID(customerID string) (*models.Customer, error) {
-	return s.Repo.GetCustomerByCustomerID(customerID)
+func (s *CustomerService) GetCustomerByEmail(ctx context.Context, email string) (*models.Customer, error) {
+	return s.Repo.GetCustomerByEmail(ctx, email)
+}
+
+func (s *CustomerService) CreateCustomer(ctx context.Context, customer models.Customer) (*models.Customer, error) {
+	if err := s.locateCustomer(ctx, &customer, nil); err != nil {
+		return nil, err
+	}
+	return s.Repo.CreateCustomer(ctx, &customer)
+}
+
+// FindDuplicateCustomers devuelve los clientes que probablemente son el mismo que customer: mismo
+// documento sin contar puntos ni guiones, mismo correo, o nombre completo parecido con un
+// teléfono en común. Devuelve nil si no hay ninguno.
+func (s *CustomerService) FindDuplicateCustomers(ctx context.Context, customer models.Customer) (*dtos.CustomerDuplicatesDTO, error) {
+	phones := customerPhoneSuffixes(customer.PhoneNumbers)
+	candidates, err := s.Repo.FindCustomerDuplicateCandidates(ctx, normalizeCustomerID(customer.CustomerId), customer.Email, phones)
+	if err != nil {
+		return nil, err
+	}
+
+	name := utils.NormalizeName(customer.CustomerName + " " + customer.LastName)
+	result := &dtos.CustomerDuplicatesDTO{CanOverride: true}
+	for _, candidate := range candidates {
+		var reasons []string
+		if normalizeCustomerID(candidate.CustomerId) == normalizeCustomerID(customer.CustomerId) {
+			reasons = append(reasons, CUSTOMER_DUPLICATE_IDENTIFIER)
+		}
+		if strings.EqualFold(candidate.Email, customer.Email) {
+			reasons = append(reasons, CUSTOMER_DUPLICATE_EMAIL)
+		}
+		if sharesPhone(phones, customerPhoneSuffixes(candidate.PhoneNumbers)) &&
+			utils.Similarity(name, utils.NormalizeName(candidate.CustomerName+" "+candidate.LastName)) >= config.CUSTOMER_DUPLICATE_NAME_SIMILARITY {
+			reasons = append(reasons, CUSTOMER_DUPLICATE_NAME_AND_PHONE)
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+
+		// el documento y el correo son únicos: con uno idéntico el cliente no se puede crear
+		if candidate.CustomerId == customer.CustomerId || candidate.Email == customer.Email {
+			result.CanOverride = false
+		}
+		if len(result.Candidates) < config.CUSTOMER_DUPLICATE_MAX_CANDIDATES {
+			result.Candidates = append(result.Candidates, dtos.CustomerDuplicateDTO{Customer: candidate, Reasons: reasons})
+		}
+	}
+
+	if len(result.Candidates) == 0 {
+		return nil, nil
+	}
+	return result, nil
 }
 
-func (s *CustomerService) GetAllCustomers() ([]models.Customer, error) {
-	return s.Repo.GetAllCustomers()
+// normalizeCustomerID deja solo letras y dígitos del documento, en mayúsculas.
+func normalizeCustomerID(customerID string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' || r >= 'A' && r <= 'Z' {
+			return r
+		}
+		return -1
+	}, strings.ToUpper(customerID))
 }
 
-func (s *CustomerService) GetCustomerByEmail(email string) (*models.Customer, error) {
-	return s.Repo.GetCustomerByEmail(email)
+// customerPhoneSuffixes extrae los últimos dígitos de cada teléfono de phoneNumbers, que puede
+// traer varios separados por comas o barras.
+func customerPhoneSuffixes(phoneNumbers string) []string {
+	var suffixes []string
+	for _, phone := range strings.FieldsFunc(phoneNumbers, func(r rune) bool { return r == ',' || r == ';' || r == '/' }) {
+		digits := strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, phone)
+		if len(digits) >= config.CUSTOMER_DUPLICATE_PHONE_DIGITS {
+			suffixes = append(suffixes, digits[len(digits)-config.CUSTOMER_DUPLICATE_PHONE_DIGITS:])
+		}
+	}
+	return suffixes
 }
 
-func (s *CustomerService) CreateCustomer(customer models.Customer) (*models.Customer, error) {
-	return s.Repo.CreateCustomer(&customer)
+func sharesPhone(a, b []string) bool {
+	for _, phone := range a {
+		if slices.Contains(b, phone) {
+			return true
+		}
+	}
+	return false
 }
 
-func (s *CustomerService) UpdateCustomer(customer *models.Customer) error {
-	return s.Repo.UpdateCustomer(customer)
+// UpdateCustomer guarda el cliente si sigue en customer.Version; si no, devuelve ErrVersionConflict.
+// before es el cliente tal como estaba: si la dirección no cambió conserva su ubicación.
+func (s *CustomerService) UpdateCustomer(ctx context.Context, customer *models.Customer, before *models.Customer) error {
+	if before != nil {
+		// la lista de precios solo cambia con PriceListService.AssignPriceList
+		customer.PriceListID = before.PriceListID
+	}
+	if err := s.locateCustomer(ctx, customer, before); err != nil {
+		return err
+	}
+	updated, err := s.Repo.UpdateCustomer(ctx, customer)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrVersionConflict
+	}
+	return nil
 }
 
-func (s *CustomerService) SearchCustomersByID(id string) ([]models.Customer, error) {
-	return s.Repo.SearchCustomersByID(id)
+func (s *CustomerService) GetCustomerDependencies(ctx context.Context, id int) (*dtos.CustomerDependenciesDTO, error) {
+	if _, err := s.Repo.GetCustomerByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetCustomerDependencies(ctx, id)
 }
 
-func (s *CustomerService) SearchCustomersByName(name string) ([]models.Customer, error) {
-	return s.Repo.SearchCustomersByName(name)
+// DeleteCustomer borra el cliente de forma lógica: deja de aparecer en consultas y búsquedas, pero
+// sus facturas, citas, ventas externas y órdenes de compra se conservan y RestoreCustomer lo
+// recupera. Con la estrategia "archive" el cliente solo se desactiva.
+func (s *CustomerService) DeleteCustomer(ctx context.Context, id int, strategy string) (*CustomerDeletion, error) {
+	if strategy != "" && strategy != CUSTOMER_DELETION_STRATEGY_ARCHIVE {
+		return nil, fmt.Errorf("%w: the only strategy is %s", ErrInvalidCustomerDeleteStrategy, CUSTOMER_DELETION_STRATEGY_ARCHIVE)
+	}
+
+	before, err := s.Repo.GetCustomerByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	dependencies, err := s.Repo.GetCustomerDependencies(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	deletion := &CustomerDeletion{Dependencies: *dependencies, Before: *before}
+
+	if strategy != CUSTOMER_DELETION_STRATEGY_ARCHIVE {
+		if err := s.Repo.DeleteCustomer(ctx, id); err != nil {
+			return nil, err
+		}
+		deletion.Action = CUSTOMER_DELETION_DELETED
+		return deletion, nil
+	}
+
+	customer := *before
+	customer.CustomerState = false
+	if err := s.UpdateCustomer(ctx, &customer, before); err != nil {
+		return nil, err
+	}
+	deletion.Action = CUSTOMER_DELETION_ARCHIVED
+	deletion.After = &customer
+	return deletion, nil
+}
+
+// MergeCustomers pasa las citas, facturas y ventas externas de duplicateID a primaryID y desactiva el
+// duplicado, todo en una transacción. Ninguno de los dos puede estar borrado.
+func (s *CustomerService) MergeCustomers(ctx context.Context, primaryID, duplicateID int) (*CustomerMerge, error) {
+	if primaryID == duplicateID {
+		return nil, fmt.Errorf("%w: a customer cannot be merged into itself", ErrInvalidCustomerMerge)
+	}
+
+	var merge CustomerMerge
+	err := s.Tx.Transaction(ctx, func(tx repositories.Tx) error {
+		txService := &CustomerService{Repo: s.Repo.WithTx(tx)}
+
+		primary, err := txService.GetCustomerByID(ctx, primaryID)
+		if err != nil {
+			return err
+		}
+		before, err := txService.GetCustomerByID(ctx, duplicateID)
+		if err != nil {
+			return err
+		}
+		reassigned, err := txService.Repo.ReassignCustomerRecords(ctx, duplicateID, primaryID)
+		if err != nil {
+			return err
+		}
+
+		duplicate := *before
+		duplicate.CustomerState = false
+		if err := txService.UpdateCustomer(ctx, &duplicate, before); err != nil {
+			return err
+		}
+		merge = CustomerMerge{Primary: *primary, DuplicateBefore: *before, Duplicate: duplicate, Reassigned: *reassigned}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &merge, nil
+}
+
+// RestoreCustomer recupera un cliente borrado. Falla con ErrCustomerRestoreConflict si mientras
+// tanto se creó otro con el mismo documento o correo, que solo son únicos entre los no borrados.
+func (s *CustomerService) RestoreCustomer(ctx context.Context, id int) (*models.Customer, error) {
+	customer, err := s.Repo.GetDeletedCustomerByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.Repo.GetCustomerByCustomerID(ctx, customer.CustomerId); err == nil {
+		return nil, fmt.Errorf("%w: document %s", ErrCustomerRestoreConflict, customer.CustomerId)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if _, err := s.Repo.GetCustomerByEmail(ctx, customer.Email); err == nil {
+		return nil, fmt.Errorf("%w: email %s", ErrCustomerRestoreConflict, customer.Email)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	restored, err := s.Repo.RestoreCustomer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !restored {
+		return nil, gorm.ErrRecordNotFound
+	}
+	customer.DeletedAt = gorm.DeletedAt{}
+	return customer, nil
+}
+
+// locateCustomer busca la dirección de customer con el proveedor de geocodificación y la reemplaza
+// por la normalizada. Si no cambió respecto a before se conserva la ubicación que ya tenía. Una
+// falla del proveedor no impide guardar: la dirección queda sin buscar y la ubica el trabajo
+// customer_geocoding.
+func (s *CustomerService) locateCustomer(ctx context.Context, customer *models.Customer, before *models.Customer) error {
+	customer.Address = strings.TrimSpace(customer.Address)
+	if before != nil && customer.Address == before.Address {
+		customer.Latitude, customer.Longitude = before.Latitude, before.Longitude
+		customer.AddressStatus, customer.GeocodedAt = before.AddressStatus, before.GeocodedAt
+		return nil
+	}
+
+	customer.Latitude, customer.Longitude, customer.AddressStatus, customer.GeocodedAt = nil, nil, "", nil
+	if customer.Address == "" || s.Geocoder == nil {
+		return nil
+	}
+
+	err := s.geocode(ctx, customer)
+	if errors.Is(err, ErrAddressNotFound) {
+		if s.RejectUnknownAddresses {
+			return err
+		}
+		return nil
+	}
+	if err != nil {
+		log.Printf("error geocoding the address of customer %s, it will be retried: %v", customer.CustomerId, err)
+	}
+	return nil
+}
+
+// geocode deja en customer la dirección normalizada y su ubicación. Si el proveedor no la encuentra
+// la marca como not_found y devuelve ErrAddressNotFound.
+func (s *CustomerService) geocode(ctx context.Context, customer *models.Customer) error {
+	location, err := s.Geocoder.Geocode(ctx, customer.Address)
+	now := time.Now()
+	if errors.Is(err, geocoding.ErrAddressNotFound) {
+		customer.AddressStatus = CUSTOMER_ADDRESS_NOT_FOUND
+		customer.GeocodedAt = &now
+		return ErrAddressNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	// la columna address admite 255 caracteres; una dirección más larga se deja como se escribió
+	if location.Address != "" && len(location.Address) <= 255 {
+		customer.Address = location.Address
+	}
+	customer.Latitude, customer.Longitude = &location.Latitude, &location.Longitude
+	customer.AddressStatus = CUSTOMER_ADDRESS_VERIFIED
+	customer.GeocodedAt = &now
+	return nil
+}
+
+// GeocodePendingCustomers ubica hasta GEOCODING_BACKFILL_BATCH_SIZE clientes cuya dirección no se
+// ha buscado: los anteriores a la geocodificación, los que llegaron con el proveedor caído y los
+// creados por lotes o desde citas. Una falla del proveedor detiene la corrida; los que faltan se
+// intentan en la siguiente.
+func (s *CustomerService) GeocodePendingCustomers(ctx context.Context) (CustomerGeocoding, error) {
+	var result CustomerGeocoding
+	if s.Geocoder == nil {
+		return result, nil
+	}
+
+	customers, err := s.Repo.GetCustomersToGeocode(ctx, config.GEOCODING_BACKFILL_BATCH_SIZE)
+	if err != nil {
+		return result, err
+	}
+	for _, customer := range customers {
+		previousAddress := customer.Address
+		err := s.geocode(ctx, &customer)
+		if err != nil && !errors.Is(err, ErrAddressNotFound) {
+			return result, err
+		}
+		updated, saveErr := s.Repo.SetCustomerLocation(ctx, &customer, previousAddress)
+		if saveErr != nil {
+			return result, saveErr
+		}
+		if !updated {
+			continue
+		}
+		if err != nil {
+			result.NotFound++
+		} else {
+			result.Located++
+		}
+	}
+	return result, nil
+}
+
+// SearchCustomers valida el orden y el teléfono de search y devuelve los clientes que cumplen todos
+// los filtros.
+func (s *CustomerService) SearchCustomers(ctx context.Context, search dtos.CustomerSearchDTO) ([]models.Customer, int64, error) {
+	column, ok := customerSortColumns[search.SortBy]
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: sortBy must be id, name, lastName, email or createdAt", ErrInvalidCustomerSearch)
+	}
+	if search.Order != "asc" && search.Order != "desc" {
+		return nil, 0, fmt.Errorf("%w: order must be asc or desc", ErrInvalidCustomerSearch)
+	}
+	search.SortBy = column
+	if search.Phone != "" {
+		search.Phone = strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, search.Phone)
+		if search.Phone == "" {
+			return nil, 0, fmt.Errorf("%w: phone must contain digits", ErrInvalidCustomerSearch)
+		}
+	}
+	return s.Repo.SearchCustomers(ctx, search)
+}
+
+// BatchCustomers aplica un lote de altas, cambios y bajas de clientes en una transacción. Una baja
+// desactiva el cliente (customerState = false).
+func (s *CustomerService) BatchCustomers(ctx context.Context, operations []dtos.BatchOperationDTO[dtos.CreateCustomerDTO]) (*dtos.BatchResponseDTO, error) {
+	return runBatch(ctx, s.Tx, operations, func(tx repositories.Tx, op dtos.BatchOperationDTO[dtos.CreateCustomerDTO], result *dtos.BatchResultDTO) error {
+		txService := &CustomerService{Repo: s.Repo.WithTx(tx)}
+
+		switch op.Op {
+		case BATCH_OP_CREATE:
+			customer, err := txService.CreateCustomer(ctx, customerFromDTO(0, *op.Data))
+			if err != nil {
+				return err
+			}
+			result.ID = customer.ID
+			result.Status = BATCH_STATUS_CREATED
+			result.Data = customer
+
+		case BATCH_OP_UPDATE:
+			before, err := txService.GetCustomerByID(ctx, op.ID)
+			if err != nil {
+				return err
+			}
+			customer := customerFromDTO(op.ID, *op.Data)
+			customer.Version = before.Version
+			if err := txService.UpdateCustomer(ctx, &customer, before); err != nil {
+				return err
+			}
+			result.Status = BATCH_STATUS_UPDATED
+			result.Data = customer
+			result.Before = *before
+			result.After = customer
+
+		case BATCH_OP_DELETE:
+			before, err := txService.GetCustomerByID(ctx, op.ID)
+			if err != nil {
+				return err
+			}
+			customer := *before
+			customer.CustomerState = false
+			if err := txService.UpdateCustomer(ctx, &customer, before); err != nil {
+				return err
+			}
+			result.Status = BATCH_STATUS_DELETED
+			result.Before = *before
+			result.After = customer
+		}
+		return nil
+	})
 }
 
-func (s *CustomerService) SearchCustomersByLastName(lastname string) ([]models.Customer, error) {
-	return s.Repo.SearchCustomersByLastName(lastname)
+func customerFromDTO(id int, dto dtos.CreateCustomerDTO) models.Customer {
+	return models.Customer{
+		ID:               id,
+		CustomerName:     dto.CustomerName,
+		CustomerId:       dto.CustomerId,
+		IsBusiness:       dto.IsBusiness,
+		Address:          dto.Address,
+		PhoneNumbers:     dto.PhoneNumbers,
+		CustomerState:    dto.CustomerState,
+		Email:            dto.Email,
+		LastName:         dto.LastName,
+		IdentifierTypeID: dto.IdentifierTypeID,
+		TextChannel:      dto.TextChannel,
+	}
 }
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+	"totesbackend/config"
+	"totesbackend/models"
+	"totesbackend/repositories"
+
+	"gorm.io/gorm"
+)
+
+var ErrDayAlreadyClosed = errors.New("the day is already closed")
+
+type DailyCloseService struct {
+	Repo        repositories.DailyCloseRepositoryInterface
+	InvoiceRepo repositories.InvoiceRepositoryInterface
+}
+
+func NewDailyCloseService(repo repositories.DailyCloseRepositoryInterface, invoiceRepo repositories.InvoiceRepositoryInterface) *DailyCloseService {
+	return &DailyCloseService{Repo: repo, InvoiceRepo: invoiceRepo}
+}
+
+// GetDailyClose devuelve el cierre congelado del día si existe; si no, lo calcula en vivo.
+func (s *DailyCloseService) GetDailyClose(ctx context.Context, date time.Time) (*models.DailyClose, error) {
+	dailyClose, err := s.Repo.GetDailyCloseByDate(ctx, date)
+	if err == nil {
+		dailyClose.Frozen = true
+		return dailyClose, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return s.buildDailyClose(ctx, s.InvoiceRepo, date)
+}
+
+// CloseDay calcula el reporte Z del día y lo guarda. Una vez cerrado, el reporte no vuelve
+// a recalcularse aunque cambien las facturas, así que se lee de la base primaria y no de la réplica.
+func (s *DailyCloseService) CloseDay(ctx context.Context, date time.Time, closedBy string) (*models.DailyClose, error) {
+	if _, err := s.Repo.GetDailyCloseByDate(ctx, date); err == nil {
+		return nil, ErrDayAlreadyClosed
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	dailyClose, err := s.buildDailyClose(ctx, s.InvoiceRepo.Primary(), date)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	dailyClose.ClosedBy = closedBy
+	dailyClose.ClosedAt = &now
+	if err := s.Repo.CreateDailyClose(ctx, dailyClose); err != nil {
+		return nil, err
+	}
+	dailyClose.Frozen = true
+
+	return dailyClose, nil
+}
+
+// buildDailyClose arma el reporte a partir de las facturas y los pagos del día. Las ventas no
+// incluyen las facturas anuladas, que se cuentan aparte con las anulaciones hechas en el día. El
+// efectivo esperado es lo recibido en efectivo, sin importar cuándo se emitió la factura, menos los
+// reembolsos de devoluciones pagados en efectivo ese día.
+func (s *DailyCloseService) buildDailyClose(ctx context.Context, invoiceRepo repositories.InvoiceRepositoryInterface, date time.Time) (*models.DailyClose, error) {
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	end := start.Add(24*time.Hour - time.Nanosecond)
+
+	periods, err := invoiceRepo.GetSalesSummaryByPeriod(ctx, start, end, "day")
+	if err != nil {
+		return nil, err
+	}
+
+	dailyClose := &models.DailyClose{
+		Date:             start,
+		PaymentsByMethod: []models.DailyClosePayment{},
+	}
+	for _, period := range periods {
+		dailyClose.InvoiceCount += period.InvoiceCount
+		dailyClose.Subtotal += period.Subtotal
+		dailyClose.Tax += period.Tax
+		dailyClose.Discount += period.Discount
+		dailyClose.Total += period.Total
+	}
+
+	voids, err := invoiceRepo.GetVoidTotals(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	dailyClose.VoidCount = voids.VoidCount
+	dailyClose.VoidTotal = voids.VoidTotal
+
+	payments, err := invoiceRepo.GetPaymentTotalsByMethod(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	for _, payment := range payments {
+		dailyClose.PaymentsByMethod = append(dailyClose.PaymentsByMethod, models.DailyClosePayment{
+			Method: payment.Method,
+			Amount: payment.Total,
+		})
+		if payment.Method == config.PAYMENT_METHOD_CASH {
+			dailyClose.CashExpected += payment.Total
+		}
+	}
+
+	return dailyClose, nil
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"totesbackend/config"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+	"totesbackend/repositories/mocks"
+
+	"gorm.io/gorm"
+)
+
+// dayTotals es un repositorio de facturas con las ventas, anulaciones y pagos de un día.
+func dayTotals(total float64, payments []dtos.PaymentMethodTotalDTO) *mocks.InvoiceRepositoryMock {
+	return &mocks.InvoiceRepositoryMock{
+		GetSalesSummaryByPeriodFunc: func(ctx context.Context, startDate, endDate time.Time, groupBy string) ([]dtos.SalesSummaryPeriodDTO, error) {
+			return []dtos.SalesSummaryPeriodDTO{{InvoiceCount: 2, Subtotal: total, Total: total}}, nil
+		},
+		GetVoidTotalsFunc: func(ctx context.Context, startDate, endDate time.Time) (dtos.VoidTotalsDTO, error) {
+			return dtos.VoidTotalsDTO{VoidCount: 1, VoidTotal: 30}, nil
+		},
+		GetPaymentTotalsByMethodFunc: func(ctx context.Context, startDate, endDate time.Time) ([]dtos.PaymentMethodTotalDTO, error) {
+			return payments, nil
+		},
+	}
+}
+
+func TestCloseDayReadsFromPrimary(t *testing.T) {
+	primary := dayTotals(150, []dtos.PaymentMethodTotalDTO{
+		{Method: config.PAYMENT_METHOD_CASH, Total: 100},
+		{Method: "card", Total: 50},
+	})
+	// la réplica todavía no tiene el último pago en efectivo
+	replica := dayTotals(100, []dtos.PaymentMethodTotalDTO{{Method: config.PAYMENT_METHOD_CASH, Total: 50}})
+	replica.PrimaryFunc = func() repositories.InvoiceRepositoryInterface { return primary }
+
+	var saved *models.DailyClose
+	service := NewDailyCloseService(&mocks.DailyCloseRepositoryMock{
+		GetDailyCloseByDateFunc: func(ctx context.Context, date time.Time) (*models.DailyClose, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+		CreateDailyCloseFunc: func(ctx context.Context, dailyClose *models.DailyClose) error {
+			saved = dailyClose
+			return nil
+		},
+	}, replica)
+
+	dailyClose, err := service.CloseDay(context.Background(), time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC), "admin@totes.com")
+	if err != nil {
+		t.Fatalf("CloseDay: %v", err)
+	}
+	if saved != dailyClose || !dailyClose.Frozen || dailyClose.ClosedBy != "admin@totes.com" {
+		t.Fatalf("the close was not saved as frozen: %+v", dailyClose)
+	}
+	if !dailyClose.Date.Equal(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Date = %v, want the start of the day", dailyClose.Date)
+	}
+	if dailyClose.Total != 150 || dailyClose.InvoiceCount != 2 {
+		t.Errorf("Total = %v, InvoiceCount = %d, want the primary's 150 and 2", dailyClose.Total, dailyClose.InvoiceCount)
+	}
+	if dailyClose.VoidCount != 1 || dailyClose.VoidTotal != 30 {
+		t.Errorf("VoidCount = %d, VoidTotal = %v, want 1 and 30", dailyClose.VoidCount, dailyClose.VoidTotal)
+	}
+	if dailyClose.CashExpected != 100 {
+		t.Errorf("CashExpected = %v, want 100", dailyClose.CashExpected)
+	}
+	if len(dailyClose.PaymentsByMethod) != 2 {
+		t.Errorf("PaymentsByMethod = %+v, want cash and card", dailyClose.PaymentsByMethod)
+	}
+}
+
+func TestCloseDayAlreadyClosed(t *testing.T) {
+	service := NewDailyCloseService(&mocks.DailyCloseRepositoryMock{
+		GetDailyCloseByDateFunc: func(ctx context.Context, date time.Time) (*models.DailyClose, error) {
+			return &models.DailyClose{ID: 1, Date: date}, nil
+		},
+	}, &mocks.InvoiceRepositoryMock{})
+
+	if _, err := service.CloseDay(context.Background(), time.Now(), "admin@totes.com"); !errors.Is(err, ErrDayAlreadyClosed) {
+		t.Fatalf("CloseDay error = %v, want ErrDayAlreadyClosed", err)
+	}
+}
+
+func TestGetDailyCloseLiveReadsFromReplica(t *testing.T) {
+	replica := dayTotals(100, []dtos.PaymentMethodTotalDTO{{Method: config.PAYMENT_METHOD_CASH, Total: 40}})
+	service := NewDailyCloseService(&mocks.DailyCloseRepositoryMock{
+		GetDailyCloseByDateFunc: func(ctx context.Context, date time.Time) (*models.DailyClose, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+	}, replica)
+
+	dailyClose, err := service.GetDailyClose(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("GetDailyClose: %v", err)
+	}
+	if dailyClose.Frozen || dailyClose.Total != 100 || dailyClose.CashExpected != 40 {
+		t.Errorf("live close = %+v, want the replica's totals, not frozen", dailyClose)
+	}
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/repositories"
+)
+
+type DashboardService struct {
+	Repo repositories.DashboardRepositoryInterface
+}
+
+func NewDashboardService(repo repositories.DashboardRepositoryInterface) *DashboardService {
+	return &DashboardService{Repo: repo}
+}
+
+// GetDashboard reúne en una sola respuesta las cifras de la pantalla de inicio del administrador.
+// Las semanas empiezan el lunes.
+func (s *DashboardService) GetDashboard(ctx context.Context, now time.Time) (*dtos.DashboardDTO, error) {
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	daysSinceMonday := (int(startOfDay.Weekday()) + 6) % 7
+	startOfWeek := startOfDay.AddDate(0, 0, -daysSinceMonday)
+	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	endOfDay := startOfDay.AddDate(0, 0, 1)
+	endOfWeek := startOfWeek.AddDate(0, 0, 7)
+	endOfMonth := startOfMonth.AddDate(0, 1, 0)
+
+	var dashboard dtos.DashboardDTO
+	var err error
+
+	if dashboard.SalesToday, err = s.Repo.GetSalesBetween(ctx, startOfDay, endOfDay); err != nil {
+		return nil, err
+	}
+	if dashboard.SalesThisWeek, err = s.Repo.GetSalesBetween(ctx, startOfWeek, endOfWeek); err != nil {
+		return nil, err
+	}
+	if dashboard.SalesThisMonth, err = s.Repo.GetSalesBetween(ctx, startOfMonth, endOfMonth); err != nil {
+		return nil, err
+	}
+
+	if dashboard.AppointmentsToday, err = s.Repo.CountAppointmentsBetween(ctx, startOfDay, endOfDay); err != nil {
+		return nil, err
+	}
+	if dashboard.AppointmentsWeek, err = s.Repo.CountAppointmentsBetween(ctx, startOfWeek, endOfWeek); err != nil {
+		return nil, err
+	}
+	if dashboard.AppointmentsMonth, err = s.Repo.CountAppointmentsBetween(ctx, startOfMonth, endOfMonth); err != nil {
+		return nil, err
+	}
+
+	if dashboard.LowStockItems, err = s.Repo.CountLowStockItems(ctx); err != nil {
+		return nil, err
+	}
+	if dashboard.PendingComments, err = s.Repo.CountPendingComments(ctx); err != nil {
+		return nil, err
+	}
+
+	return &dashboard, nil
+}
+
+// CountLowStockItems cuenta los items activos con stock en su nivel de reorden o menos.
+func (s *DashboardService) CountLowStockItems(ctx context.Context) (int64, error) {
+	return s.Repo.CountLowStockItems(ctx)
+}
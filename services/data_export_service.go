@@ -0,0 +1,321 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+	"totesbackend/config"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+)
+
+const (
+	DATA_EXPORT_STATUS_QUEUED    = "queued"
+	DATA_EXPORT_STATUS_RUNNING   = "running"
+	DATA_EXPORT_STATUS_COMPLETED = "completed"
+	DATA_EXPORT_STATUS_FAILED    = "failed"
+	// el archivo ya se borró por antigüedad
+	DATA_EXPORT_STATUS_EXPIRED = "expired"
+
+	dataExportLinkPurpose = "data_export"
+)
+
+var ErrDataExportNotReady = errors.New("the export has no file to download")
+
+// DataExportService genera exportaciones completas de los datos del negocio: un zip con un JSON por
+// tabla de config.DATA_EXPORT_TABLES y un manifest.json con la cantidad de filas de cada una. Los
+// pedidos se encolan en data_exports y los genera un worker en segundo plano; el zip queda en Dir
+// y se descarga con un enlace firmado.
+type DataExportService struct {
+	Repo      repositories.DataExportRepositoryInterface
+	Signer    *LinkSigner
+	Dir       string
+	wake      chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewDataExportService(repo repositories.DataExportRepositoryInterface, signer *LinkSigner, cfg config.ExportConfig) *DataExportService {
+	return &DataExportService{
+		Repo:   repo,
+		Signer: signer,
+		Dir:    cfg.Dir,
+		wake:   make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Start arranca el worker que genera las exportaciones encoladas. El comando "export" no lo usa:
+// escribe el zip directamente con Write.
+func (s *DataExportService) Start() {
+	go s.run()
+}
+
+// Close detiene el worker y espera a que termine la exportación en curso.
+func (s *DataExportService) Close() {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+}
+
+func (s *DataExportService) RequestExport(ctx context.Context, requestedBy string) (*models.DataExport, error) {
+	export := &models.DataExport{
+		Status:      DATA_EXPORT_STATUS_QUEUED,
+		RequestedBy: requestedBy,
+		CreatedAt:   time.Now(),
+		RequestID:   RequestIDFromContext(ctx),
+	}
+	if err := s.Repo.CreateExport(ctx, export); err != nil {
+		return nil, err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return export, nil
+}
+
+func (s *DataExportService) GetExports(ctx context.Context, pagination dtos.PaginationDTO) (*dtos.PageDTO[dtos.DataExportDTO], error) {
+	exports, total, err := s.Repo.GetExports(ctx, pagination)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dtos.DataExportDTO, len(exports))
+	for i, export := range exports {
+		result[i] = s.ToDTO(export)
+	}
+	return dtos.NewPageDTO(result, pagination, total), nil
+}
+
+func (s *DataExportService) GetExport(ctx context.Context, id int) (*dtos.DataExportDTO, error) {
+	export, err := s.Repo.GetExportByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	result := s.ToDTO(*export)
+	return &result, nil
+}
+
+// ToDTO agrega el enlace de descarga a las exportaciones terminadas. Cada consulta firma un
+// enlace nuevo que vale config.DATA_EXPORT_LINK_TTL.
+func (s *DataExportService) ToDTO(export models.DataExport) dtos.DataExportDTO {
+	result := dtos.DataExportDTO{DataExport: export}
+	if export.Status == DATA_EXPORT_STATUS_COMPLETED && export.FileName != "" {
+		expiresAt := time.Now().Add(config.DATA_EXPORT_LINK_TTL)
+		result.DownloadURL = s.Signer.URL("/exports/download", dataExportLinkPurpose, expiresAt, strconv.Itoa(export.ID))
+		result.DownloadExpiresAt = &expiresAt
+	}
+	return result
+}
+
+// OpenDownload valida el token del enlace y devuelve la ruta del zip y el nombre con que se descarga.
+func (s *DataExportService) OpenDownload(ctx context.Context, token string) (string, string, error) {
+	fields, err := s.Signer.Verify(dataExportLinkPurpose, token)
+	if err != nil || len(fields) != 1 {
+		return "", "", ErrInvalidLinkToken
+	}
+	id, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return "", "", ErrInvalidLinkToken
+	}
+
+	export, err := s.Repo.GetExportByID(ctx, id)
+	if err != nil {
+		return "", "", err
+	}
+	if export.Status != DATA_EXPORT_STATUS_COMPLETED || export.FileName == "" {
+		return "", "", ErrDataExportNotReady
+	}
+	return filepath.Join(s.Dir, export.FileName), export.FileName, nil
+}
+
+// PurgeExpiredExports borra los archivos de las exportaciones terminadas hace más de retentionDays días.
+func (s *DataExportService) PurgeExpiredExports(ctx context.Context, retentionDays int) (int, error) {
+	exports, err := s.Repo.GetExportFilesBefore(ctx, time.Now().AddDate(0, 0, -retentionDays))
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for i := range exports {
+		export := &exports[i]
+		if err := os.Remove(filepath.Join(s.Dir, export.FileName)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return purged, err
+		}
+		export.FileName = ""
+		export.Status = DATA_EXPORT_STATUS_EXPIRED
+		if err := s.Repo.SaveExport(ctx, export); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// Write escribe la exportación completa como zip en w.
+func (s *DataExportService) Write(ctx context.Context, w io.Writer) error {
+	archive := zip.NewWriter(w)
+
+	manifest := dtos.DataExportManifestDTO{GeneratedAt: time.Now(), Tables: make(map[string]int, len(config.DATA_EXPORT_TABLES))}
+	for _, table := range config.DATA_EXPORT_TABLES {
+		count, err := s.writeTable(ctx, archive, table)
+		if err != nil {
+			return fmt.Errorf("exporting %s: %w", table, err)
+		}
+		manifest.Tables[table] = count
+	}
+
+	file, err := archive.Create("manifest.json")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// writeTable escribe la tabla como un arreglo JSON fila por fila, sin cargarla entera en memoria.
+func (s *DataExportService) writeTable(ctx context.Context, archive *zip.Writer, table string) (int, error) {
+	file, err := archive.Create(table + ".json")
+	if err != nil {
+		return 0, err
+	}
+	if _, err := io.WriteString(file, "["); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err = s.Repo.StreamTable(ctx, table, func(row map[string]interface{}) error {
+		for _, column := range config.DATA_EXPORT_EXCLUDED_COLUMNS[table] {
+			delete(row, column)
+		}
+		for column, value := range row {
+			// texto y jsonb pueden llegar como bytes; así no se exportan en base64
+			if bytes, ok := value.([]byte); ok {
+				row[column] = string(bytes)
+			}
+		}
+
+		data, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			if _, err := io.WriteString(file, ",\n"); err != nil {
+				return err
+			}
+		}
+		count++
+		_, err = file.Write(data)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = io.WriteString(file, "]\n")
+	return count, err
+}
+
+func (s *DataExportService) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(config.DATA_EXPORT_POLL_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+		s.generateQueued()
+	}
+}
+
+func (s *DataExportService) generateQueued() {
+	// corre en segundo plano, fuera de cualquier petición
+	ctx := context.Background()
+	for {
+		export, err := s.Repo.ClaimExport(ctx, DATA_EXPORT_STATUS_QUEUED, DATA_EXPORT_STATUS_RUNNING, time.Now())
+		if err != nil {
+			log.Printf("error loading queued data exports: %v", err)
+			return
+		}
+		if export == nil {
+			return
+		}
+
+		s.generate(ctx, export)
+		if err := s.Repo.SaveExport(ctx, export); err != nil {
+			log.Printf("error saving data export %d: %v", export.ID, err)
+		}
+	}
+}
+
+func (s *DataExportService) generate(ctx context.Context, export *models.DataExport) {
+	fileName := fmt.Sprintf("export-%d-%s.zip", export.ID, time.Now().Format("20060102-150405"))
+	size, err := s.writeFile(ctx, fileName)
+
+	now := time.Now()
+	export.CompletedAt = &now
+	if err != nil {
+		log.Printf("data export %d failed: %v", export.ID, err)
+		export.Status = DATA_EXPORT_STATUS_FAILED
+		export.Error = err.Error()
+		if len(export.Error) > 500 {
+			export.Error = export.Error[:500]
+		}
+		return
+	}
+	export.Status = DATA_EXPORT_STATUS_COMPLETED
+	export.FileName = fileName
+	export.SizeBytes = size
+}
+
+// writeFile escribe primero a un archivo temporal para que nunca se descargue un zip a medias.
+func (s *DataExportService) writeFile(ctx context.Context, fileName string) (int64, error) {
+	if err := os.MkdirAll(s.Dir, 0o700); err != nil {
+		return 0, err
+	}
+	file, err := os.CreateTemp(s.Dir, fileName+".*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(file.Name())
+
+	if err := s.Write(ctx, file); err != nil {
+		file.Close()
+		return 0, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return 0, err
+	}
+	if err := file.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(file.Name(), filepath.Join(s.Dir, fileName)); err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
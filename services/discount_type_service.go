@@ -1,26 +1,125 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
 
+var (
+	ErrInvalidDiscountType   = errors.New("invalid discount type")
+	ErrDiscountNotApplicable = errors.New("discount is not applicable")
+)
+
 type DiscountTypeService struct {
-	Repo *repositories.DiscountTypeRepository
+	Repo repositories.DiscountTypeRepositoryInterface
 }
 
-func NewDiscountTypeService(repo *repositories.DiscountTypeRepository) *DiscountTypeService {
+func NewDiscountTypeService(repo repositories.DiscountTypeRepositoryInterface) *DiscountTypeService {
 	return &DiscountTypeService{Repo: repo}
 }
 
-func (s *DiscountTypeService) GetAllDiscountTypes() ([]models.DiscountType, error) {
-	return s.Repo.GetAllDiscountTypes()
+func (s *DiscountTypeService) GetAllDiscountTypes(ctx context.Context, pagination dtos.PaginationDTO) ([]models.DiscountType, int64, error) {
+	return s.Repo.GetAllDiscountTypes(ctx, pagination)
+}
+
+func (s *DiscountTypeService) GetDiscountTypeByID(ctx context.Context, id string) (*models.DiscountType, error) {
+	return s.Repo.GetDiscountTypeByID(ctx, id)
+}
+
+func (s *DiscountTypeService) CreateDiscountType(ctx context.Context, dto dtos.DiscountTypeDTO) (*models.DiscountType, error) {
+	discount := &models.DiscountType{}
+	if err := fillDiscountType(discount, dto); err != nil {
+		return nil, err
+	}
+	discount.Active = true
+	if err := s.Repo.CreateDiscountType(ctx, discount); err != nil {
+		return nil, err
+	}
+	return discount, nil
+}
+
+// UpdateDiscountType cambia el descuento para las facturas y cotizaciones nuevas; las ya emitidas
+// guardan sus totales.
+func (s *DiscountTypeService) UpdateDiscountType(ctx context.Context, id string, dto dtos.DiscountTypeDTO) (*models.DiscountType, error) {
+	discount, err := s.Repo.GetDiscountTypeByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := fillDiscountType(discount, dto); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.UpdateDiscountType(ctx, discount); err != nil {
+		return nil, err
+	}
+	return discount, nil
+}
+
+func (s *DiscountTypeService) DeactivateDiscountType(ctx context.Context, id string) (*models.DiscountType, error) {
+	if err := s.Repo.DeactivateDiscountType(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetDiscountTypeByID(ctx, id)
+}
+
+func (s *DiscountTypeService) ImportDiscountTypes(ctx context.Context, entries []dtos.ImportCatalogEntryDTO) ([]models.DiscountType, error) {
+	if err := validateCatalogEntries(entries); err != nil {
+		return nil, err
+	}
+
+	discountTypes := make([]models.DiscountType, len(entries))
+	for i, entry := range entries {
+		discountTypes[i] = models.DiscountType{
+			Name:         strings.TrimSpace(entry.Name),
+			Description:  entry.Description,
+			IsPercentage: entry.IsPercentage,
+			Value:        entry.Value,
+			Active:       true,
+		}
+	}
+
+	if err := s.Repo.CreateDiscountTypes(ctx, discountTypes); err != nil {
+		return nil, err
+	}
+	return discountTypes, nil
 }
 
-func (s *DiscountTypeService) GetDiscountTypeByID(id string) (*models.DiscountType, error) {
-	return s.Repo.GetDiscountTypeByID(id)
+func fillDiscountType(discount *models.DiscountType, dto dtos.DiscountTypeDTO) error {
+	discount.Name = strings.TrimSpace(dto.Name)
+	discount.Description = strings.TrimSpace(dto.Description)
+	discount.IsPercentage = dto.IsPercentage
+	discount.Value = dto.Value
+	discount.ValidFrom = dto.ValidFrom
+	discount.ValidTo = dto.ValidTo
+	if dto.Active != nil {
+		discount.Active = *dto.Active
+	}
+	if discount.Name == "" {
+		return fmt.Errorf("%w: the name cannot be blank", ErrInvalidDiscountType)
+	}
+	if discount.IsPercentage && discount.Value > 100 {
+		return fmt.Errorf("%w: a percentage discount cannot exceed 100", ErrInvalidDiscountType)
+	}
+	if discount.ValidFrom != nil && discount.ValidTo != nil && discount.ValidTo.Before(*discount.ValidFrom) {
+		return fmt.Errorf("%w: valid_to cannot be before valid_from", ErrInvalidDiscountType)
+	}
+	return nil
 }
 
-func (s *DiscountTypeService) CreateDiscountType(discount *models.DiscountType) error {
-	return s.Repo.CreateDiscountType(discount)
+// checkDiscountApplicable verifica que el descuento esté activo y vigente en at.
+func checkDiscountApplicable(discount *models.DiscountType, at time.Time) error {
+	switch {
+	case !discount.Active:
+		return fmt.Errorf("%w: discount %d is inactive", ErrDiscountNotApplicable, discount.ID)
+	case discount.ValidFrom != nil && at.Before(*discount.ValidFrom):
+		return fmt.Errorf("%w: discount %d is valid from %s", ErrDiscountNotApplicable, discount.ID, discount.ValidFrom.Format(time.RFC3339))
+	case discount.ValidTo != nil && at.After(*discount.ValidTo):
+		return fmt.Errorf("%w: discount %d expired on %s", ErrDiscountNotApplicable, discount.ID, discount.ValidTo.Format(time.RFC3339))
+	}
+	return nil
 }
@@ -0,0 +1,478 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"totesbackend/config"
+	"totesbackend/dtos"
+	"totesbackend/ecommerce"
+	"totesbackend/models"
+	"totesbackend/repositories"
+)
+
+const (
+	ECOMMERCE_ORDER_RECEIVED = "received"
+	ECOMMERCE_ORDER_IMPORTED = "imported"
+	ECOMMERCE_ORDER_FAILED   = "failed"
+)
+
+var ErrEcommerceStoreUnavailable = errors.New("the online store could not be reached")
+
+// EcommerceService sincroniza el inventario con la tienda en línea. Un worker envía el precio y las
+// existencias de los ítems publicados cuando cambian, sin importar qué los cambió (facturas,
+// órdenes de compra, ediciones), y cada hora vuelve a leer el catálogo para enlazar los productos
+// nuevos. Los pedidos web llegan por webhook y se importan como órdenes de compra.
+type EcommerceService struct {
+	Repo      repositories.EcommerceRepositoryInterface
+	Customers repositories.CustomerRepositoryInterface
+	Orders    *PurchaseOrderService
+	Platform  ecommerce.Platform
+	// tipo de documento de los clientes que se crean desde un pedido
+	IdentifierTypeID int
+
+	lastRefresh time.Time
+	wake        chan struct{}
+	stop        chan struct{}
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+func NewEcommerceService(repo repositories.EcommerceRepositoryInterface, customers repositories.CustomerRepositoryInterface,
+	platform ecommerce.Platform, identifierTypeID int) *EcommerceService {
+	s := &EcommerceService{
+		Repo:             repo,
+		Customers:        customers,
+		Platform:         platform,
+		IdentifierTypeID: identifierTypeID,
+		wake:             make(chan struct{}, 1),
+		stop:             make(chan struct{}),
+		done:             make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// ReceiveOrderWebhook verifica el webhook de la tienda e importa el pedido que trae. Devuelve nil
+// sin error si el evento no es un pedido nuevo. Un pedido repetido devuelve el registro que ya
+// existía. Si el pedido no se puede importar queda como failed y no es un error: reintentar el
+// webhook no lo arreglaría.
+func (s *EcommerceService) ReceiveOrderWebhook(ctx context.Context, header http.Header, body []byte) (*models.EcommerceOrder, error) {
+	order, err := s.Platform.ParseOrderWebhook(header, body)
+	if err != nil || order == nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(order)
+	if err != nil {
+		return nil, err
+	}
+	orderedAt := order.Date
+	if orderedAt.IsZero() {
+		orderedAt = time.Now()
+	}
+	record := &models.EcommerceOrder{
+		Provider:   s.Platform.Provider(),
+		ExternalID: order.ExternalID,
+		Number:     order.Number,
+		Status:     ECOMMERCE_ORDER_RECEIVED,
+		Total:      order.Total,
+		Payload:    string(payload),
+		OrderedAt:  orderedAt,
+		RequestID:  RequestIDFromContext(ctx),
+	}
+	created, err := s.Repo.CreateOrder(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return s.Repo.GetOrderByExternalID(ctx, record.Provider, record.ExternalID)
+	}
+
+	s.importOrder(ctx, record, order)
+	return record, nil
+}
+
+// RetryOrder vuelve a importar un pedido que falló, por ejemplo después de crear el ítem que
+// faltaba o de reponer existencias. Devuelve false si el pedido no estaba en failed.
+func (s *EcommerceService) RetryOrder(ctx context.Context, id int) (*models.EcommerceOrder, bool, error) {
+	claimed, err := s.Repo.ChangeOrderStatus(ctx, id, ECOMMERCE_ORDER_FAILED, ECOMMERCE_ORDER_RECEIVED)
+	if err != nil {
+		return nil, false, err
+	}
+	record, err := s.Repo.GetOrderByID(ctx, id)
+	if err != nil || !claimed {
+		return record, false, err
+	}
+
+	var order ecommerce.Order
+	if err := json.Unmarshal([]byte(record.Payload), &order); err != nil {
+		return nil, false, err
+	}
+	s.importOrder(ctx, record, &order)
+	return record, true, nil
+}
+
+func (s *EcommerceService) GetOrders(ctx context.Context, filter dtos.EcommerceOrderFilterDTO) ([]models.EcommerceOrder, int64, error) {
+	return s.Repo.GetOrders(ctx, filter)
+}
+
+// importOrder crea la orden de compra del pedido y guarda el resultado en record.
+func (s *EcommerceService) importOrder(ctx context.Context, record *models.EcommerceOrder, order *ecommerce.Order) {
+	purchaseOrder, err := s.createPurchaseOrder(ctx, record, order)
+	if err != nil {
+		record.Status = ECOMMERCE_ORDER_FAILED
+		record.LastError = err.Error()
+		if len(record.LastError) > 500 {
+			record.LastError = record.LastError[:500]
+		}
+		log.Printf("web order %s from %s could not be imported: %v", record.ExternalID, record.Provider, err)
+	} else {
+		record.Status = ECOMMERCE_ORDER_IMPORTED
+		record.LastError = ""
+		record.PurchaseOrderID = &purchaseOrder.ID
+	}
+
+	// la orden de compra ya existe: el registro se guarda aunque la petición se haya cancelado
+	if err := s.Repo.SaveOrder(context.WithoutCancel(ctx), record); err != nil {
+		log.Printf("error saving web order %s from %s: %v", record.ExternalID, record.Provider, err)
+	}
+}
+
+func (s *EcommerceService) createPurchaseOrder(ctx context.Context, record *models.EcommerceOrder, order *ecommerce.Order) (*models.PurchaseOrder, error) {
+	quantities := make(map[int]int)
+	var itemIDs []int
+	for _, line := range order.Lines {
+		id, err := strconv.Atoi(line.SKU)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("product SKU %q is not an item ID", line.SKU)
+		}
+		if _, ok := quantities[id]; !ok {
+			itemIDs = append(itemIDs, id)
+		}
+		quantities[id] += line.Quantity
+	}
+	if len(itemIDs) == 0 {
+		return nil, errors.New("the order has no items")
+	}
+
+	items, err := s.Repo.GetItems(ctx, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) != len(itemIDs) {
+		found := make(map[int]bool, len(items))
+		for _, item := range items {
+			found[item.ID] = true
+		}
+		for _, id := range itemIDs {
+			if !found[id] {
+				return nil, fmt.Errorf("item %d does not exist", id)
+			}
+		}
+	}
+
+	dto := &dtos.CreatePurchaseOrderDTO{Items: make([]dtos.BillingItemDTO, len(itemIDs))}
+	for i, id := range itemIDs {
+		dto.Items[i] = dtos.BillingItemDTO{ID: id, Stock: quantities[id]}
+	}
+	customer, err := s.orderCustomer(ctx, order.Customer)
+	if err != nil {
+		return nil, err
+	}
+	if customer != nil {
+		dto.CustomerID = &customer.ID
+		record.CustomerID = &customer.ID
+	}
+
+	return s.Orders.CreatePurchaseOrder(ctx, dto)
+}
+
+// orderCustomer busca el cliente del pedido por correo y lo crea si no existe. Como en las citas,
+// el cliente nuevo recibe un documento provisional que se corrige al facturarle.
+func (s *EcommerceService) orderCustomer(ctx context.Context, buyer ecommerce.OrderCustomer) (*models.Customer, error) {
+	email := strings.TrimSpace(buyer.Email)
+	if email == "" {
+		return nil, nil
+	}
+	customer, err := s.Customers.FindCustomerByEmail(ctx, email)
+	if err != nil || customer != nil {
+		return customer, err
+	}
+
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(buyer.FirstName)
+	if name == "" {
+		name = strings.SplitN(email, "@", 2)[0]
+	}
+	customer, err = s.Customers.CreateCustomer(ctx, &models.Customer{
+		CustomerName:     name,
+		LastName:         strings.TrimSpace(buyer.LastName),
+		CustomerId:       "WEB-" + hex.EncodeToString(buf),
+		Address:          buyer.Address,
+		PhoneNumbers:     buyer.Phone,
+		CustomerState:    true,
+		Email:            email,
+		IdentifierTypeID: s.IdentifierTypeID,
+	})
+	if err != nil {
+		// otro pedido del mismo comprador pudo crear el cliente al mismo tiempo
+		existing, findErr := s.Customers.FindCustomerByEmail(ctx, email)
+		if findErr != nil || existing == nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+	return customer, nil
+}
+
+// Reconcile compara el catálogo de la tienda con los ítems: productos con precio o existencias
+// distintos, SKUs que no son ítems e ítems activos sin publicar.
+func (s *EcommerceService) Reconcile(ctx context.Context) (*dtos.EcommerceReconciliationDTO, error) {
+	products, err := s.Platform.ListProducts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEcommerceStoreUnavailable, err)
+	}
+	items, err := s.Repo.GetItems(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]models.Item, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+
+	report := &dtos.EcommerceReconciliationDTO{
+		Provider:      s.Platform.Provider(),
+		GeneratedAt:   time.Now(),
+		StoreProducts: len(products),
+		Mismatches:    []dtos.EcommerceMismatchDTO{},
+		UnknownSKUs:   []dtos.EcommerceUnknownProductDTO{},
+		Unlisted:      []dtos.EcommerceUnlistedItemDTO{},
+	}
+	listed := make(map[int]bool, len(products))
+	for _, product := range products {
+		id, err := strconv.Atoi(product.SKU)
+		item, ok := byID[id]
+		if err != nil || !ok {
+			report.UnknownSKUs = append(report.UnknownSKUs, dtos.EcommerceUnknownProductDTO{SKU: product.SKU, ExternalID: product.ExternalID, Name: product.Name})
+			continue
+		}
+		listed[id] = true
+
+		stock := ecommerceStock(item)
+		var fields []string
+		if product.Stock == nil || *product.Stock != stock {
+			fields = append(fields, "stock")
+		}
+		// la tienda guarda los precios con dos decimales
+		if math.Abs(product.Price-item.SellingPrice) >= 0.005 {
+			fields = append(fields, "price")
+		}
+		if len(fields) == 0 {
+			report.Matched++
+			continue
+		}
+		report.Mismatches = append(report.Mismatches, dtos.EcommerceMismatchDTO{
+			ItemID:     item.ID,
+			Name:       item.Name,
+			Stock:      stock,
+			StoreStock: product.Stock,
+			Price:      item.SellingPrice,
+			StorePrice: product.Price,
+			Fields:     fields,
+		})
+	}
+	for _, item := range items {
+		if item.ItemState && !listed[item.ID] {
+			report.Unlisted = append(report.Unlisted, dtos.EcommerceUnlistedItemDTO{ItemID: item.ID, Name: item.Name})
+		}
+	}
+
+	if report.FailedPushes, err = s.Repo.CountFailingListings(ctx); err != nil {
+		return nil, err
+	}
+	if report.FailedOrders, err = s.Repo.CountOrdersByStatus(ctx, ECOMMERCE_ORDER_FAILED); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Close detiene el worker; lo que falte por enviar se envía al reiniciar.
+func (s *EcommerceService) Close() {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+}
+
+func (s *EcommerceService) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(config.ECOMMERCE_SYNC_INTERVAL)
+	defer ticker.Stop()
+
+	// la primera ronda lee el catálogo enseguida
+	s.wake <- struct{}{}
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+
+		// corre en segundo plano, fuera de cualquier petición
+		ctx := context.Background()
+		if time.Since(s.lastRefresh) >= config.ECOMMERCE_CATALOG_REFRESH {
+			s.refreshCatalog(ctx)
+		}
+		s.pushOutdated(ctx)
+	}
+}
+
+// refreshCatalog enlaza los productos de la tienda con los ítems por SKU. Los productos nuevos
+// entran con el precio y las existencias que tienen en la tienda, así solo se envían si difieren.
+func (s *EcommerceService) refreshCatalog(ctx context.Context) {
+	products, err := s.Platform.ListProducts(ctx)
+	if err != nil {
+		log.Printf("error reading the %s catalog: %v", s.Platform.Provider(), err)
+		return
+	}
+	items, err := s.Repo.GetItems(ctx, nil)
+	if err != nil {
+		log.Printf("error loading items for the %s catalog: %v", s.Platform.Provider(), err)
+		return
+	}
+	known := make(map[int]bool, len(items))
+	for _, item := range items {
+		known[item.ID] = true
+	}
+
+	now := time.Now()
+	listings := make([]models.EcommerceListing, 0, len(products))
+	itemIDs := make([]int, 0, len(products))
+	linked := make(map[int]bool, len(products))
+	for _, product := range products {
+		id, err := strconv.Atoi(product.SKU)
+		// con el SKU repetido en la tienda se sincroniza el primer producto
+		if err != nil || !known[id] || linked[id] {
+			continue
+		}
+		linked[id] = true
+		price := product.Price
+		listings = append(listings, models.EcommerceListing{
+			ItemID:        id,
+			ExternalID:    product.ExternalID,
+			InventoryID:   product.InventoryID,
+			SyncedStock:   product.Stock,
+			SyncedPrice:   &price,
+			NextAttemptAt: now,
+		})
+		itemIDs = append(itemIDs, id)
+	}
+
+	if err := s.Repo.UpsertListings(ctx, listings); err != nil {
+		log.Printf("error saving the %s catalog: %v", s.Platform.Provider(), err)
+		return
+	}
+	if _, err := s.Repo.DeleteListingsExcept(ctx, itemIDs); err != nil {
+		log.Printf("error removing unlisted items from the %s catalog: %v", s.Platform.Provider(), err)
+		return
+	}
+	s.lastRefresh = now
+}
+
+// pushOutdated envía el precio y las existencias de los ítems que cambiaron desde el último envío.
+func (s *EcommerceService) pushOutdated(ctx context.Context) {
+	now := time.Now()
+	listings, err := s.Repo.ClaimOutdatedListings(ctx, now, now.Add(config.ECOMMERCE_PUSH_LEASE), config.ECOMMERCE_BATCH_SIZE)
+	if err != nil {
+		log.Printf("error loading items to push to %s: %v", s.Platform.Provider(), err)
+		return
+	}
+	if len(listings) == 0 {
+		return
+	}
+
+	itemIDs := make([]int, len(listings))
+	for i, listing := range listings {
+		itemIDs[i] = listing.ItemID
+	}
+	items, err := s.Repo.GetItems(ctx, itemIDs)
+	if err != nil {
+		log.Printf("error loading items to push to %s: %v", s.Platform.Provider(), err)
+		return
+	}
+	byID := make(map[int]models.Item, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+
+	for i := range listings {
+		listing := &listings[i]
+		item, ok := byID[listing.ItemID]
+		if !ok {
+			continue
+		}
+		s.push(ctx, listing, item)
+		if err := s.Repo.SaveListing(ctx, listing); err != nil {
+			log.Printf("error updating the %s listing of item %d: %v", s.Platform.Provider(), listing.ItemID, err)
+		}
+	}
+}
+
+func (s *EcommerceService) push(ctx context.Context, listing *models.EcommerceListing, item models.Item) {
+	stock, price := ecommerceStock(item), item.SellingPrice
+	product := ecommerce.Product{SKU: strconv.Itoa(item.ID), ExternalID: listing.ExternalID, InventoryID: listing.InventoryID}
+	err := s.Platform.UpdateProduct(ctx, product, price, stock)
+	now := time.Now()
+	if err == nil {
+		listing.SyncedStock, listing.SyncedPrice = &stock, &price
+		listing.Attempts = 0
+		listing.LastError = ""
+		listing.SyncedAt = &now
+		listing.NextAttemptAt = now
+		return
+	}
+
+	listing.Attempts++
+	listing.LastError = err.Error()
+	if len(listing.LastError) > 500 {
+		listing.LastError = listing.LastError[:500]
+	}
+	listing.NextAttemptAt = now.Add(ecommerceRetryDelay(listing.Attempts))
+	log.Printf("push of item %d to %s failed (attempt %d): %v", item.ID, s.Platform.Provider(), listing.Attempts, err)
+}
+
+// ecommerceStock son las existencias que se publican: un ítem inactivo no se vende en línea.
+func ecommerceStock(item models.Item) int {
+	if !item.ItemState || item.Stock < 0 {
+		return 0
+	}
+	return item.Stock
+}
+
+func ecommerceRetryDelay(attempts int) time.Duration {
+	delay := config.ECOMMERCE_RETRY_BASE_DELAY
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= config.ECOMMERCE_RETRY_MAX_DELAY {
+			return config.ECOMMERCE_RETRY_MAX_DELAY
+		}
+	}
+	return delay
+}
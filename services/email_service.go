@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/mail"
+	"sync"
+	"time"
+	"totesbackend/config"
+	"totesbackend/models"
+	"totesbackend/notifications"
+	"totesbackend/repositories"
+)
+
+const (
+	EMAIL_STATUS_PENDING = "pending"
+	EMAIL_STATUS_SENT    = "sent"
+	EMAIL_STATUS_FAILED  = "failed"
+)
+
+var ErrInvalidEmailRecipient = errors.New("invalid email recipient")
+
+// EmailService renderiza las plantillas de notifications y encola los correos en email_messages.
+// Un worker los envía con el Mailer configurado y reintenta los fallidos con espera exponencial
+// hasta config.EMAIL_MAX_ATTEMPTS.
+type EmailService struct {
+	Repo        repositories.EmailMessageRepositoryInterface
+	Mailer      notifications.Mailer
+	Preferences *NotificationPreferenceService
+	Templates   *EmailTemplateService
+	wake        chan struct{}
+	stop        chan struct{}
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+func NewEmailService(repo repositories.EmailMessageRepositoryInterface, mailer notifications.Mailer) *EmailService {
+	s := &EmailService{
+		Repo:   repo,
+		Mailer: mailer,
+		wake:   make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// EmailRecipient es el usuario o cliente al que va un correo, para consultar sus preferencias.
+type EmailRecipient struct {
+	Type  string
+	ID    int
+	Email string
+}
+
+// Notify encola el correo del evento si el destinatario no lo desactivó en sus preferencias; en
+// ese caso devuelve nil sin error. Los errores ya quedan registrados, así que quien no necesita el
+// resultado puede ignorarlo: un correo nunca debe hacer fallar la operación que lo origina.
+func (s *EmailService) Notify(ctx context.Context, recipient EmailRecipient, eventType, template string, data interface{}) (*models.EmailMessage, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	unsubscribeURL := ""
+	if s.Preferences != nil {
+		if !s.Preferences.Allows(ctx, recipient.Type, recipient.ID, eventType, NOTIFICATION_CHANNEL_EMAIL) {
+			return nil, nil
+		}
+		unsubscribeURL = s.Preferences.UnsubscribeURL(recipient.Type, recipient.ID, eventType, NOTIFICATION_CHANNEL_EMAIL)
+	}
+	message, err := s.enqueue(ctx, recipient.Email, template, data, unsubscribeURL)
+	if err != nil {
+		log.Printf("error queuing %s email to %s: %v", template, recipient.Email, err)
+		return nil, err
+	}
+	return message, nil
+}
+
+// SendTemplate encola un correo sin consultar preferencias; es para direcciones que no son de un
+// usuario ni de un cliente. El envío ocurre en segundo plano, así que un proveedor caído no hace
+// fallar la petición.
+func (s *EmailService) SendTemplate(ctx context.Context, to, template string, data interface{}) (*models.EmailMessage, error) {
+	return s.enqueue(ctx, to, template, data, "")
+}
+
+func (s *EmailService) enqueue(ctx context.Context, to, template string, data interface{}, unsubscribeURL string) (*models.EmailMessage, error) {
+	if _, err := mail.ParseAddress(to); err != nil {
+		return nil, ErrInvalidEmailRecipient
+	}
+
+	// la operación que origina el correo ya se hizo: se encola aunque el cliente se desconecte
+	ctx = context.WithoutCancel(ctx)
+	var subject, body string
+	var err error
+	if s.Templates != nil {
+		subject, body, err = s.Templates.Render(ctx, template, data, unsubscribeURL)
+	} else {
+		subject, body, err = notifications.Render(template, data, unsubscribeURL)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.queue(ctx, to, template, subject, body, unsubscribeURL)
+}
+
+// SendRendered encola un correo ya renderizado, como los de prueba de las plantillas.
+func (s *EmailService) SendRendered(ctx context.Context, to, template, subject, body string) (*models.EmailMessage, error) {
+	if _, err := mail.ParseAddress(to); err != nil {
+		return nil, ErrInvalidEmailRecipient
+	}
+	return s.queue(ctx, to, template, subject, body, "")
+}
+
+func (s *EmailService) queue(ctx context.Context, to, template, subject, body, unsubscribeURL string) (*models.EmailMessage, error) {
+	message := &models.EmailMessage{
+		To:             to,
+		Template:       template,
+		Subject:        subject,
+		HTML:           body,
+		UnsubscribeURL: unsubscribeURL,
+		Status:         EMAIL_STATUS_PENDING,
+		NextAttemptAt:  time.Now(),
+		RequestID:      RequestIDFromContext(ctx),
+	}
+	// la operación que origina el correo ya se hizo: se encola aunque el cliente se desconecte
+	if err := s.Repo.CreateMessage(context.WithoutCancel(ctx), message); err != nil {
+		return nil, err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return message, nil
+}
+
+// Close detiene el envío; los correos pendientes quedan guardados y se envían al reiniciar.
+func (s *EmailService) Close() {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+}
+
+func (s *EmailService) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(config.EMAIL_POLL_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+		s.sendDue()
+	}
+}
+
+func (s *EmailService) sendDue() {
+	// corre en segundo plano, fuera de cualquier petición
+	ctx := context.Background()
+	now := time.Now()
+	messages, err := s.Repo.ClaimDueMessages(ctx, EMAIL_STATUS_PENDING, now, now.Add(config.EMAIL_SEND_LEASE), config.EMAIL_BATCH_SIZE)
+	if err != nil {
+		log.Printf("error loading pending emails: %v", err)
+		return
+	}
+
+	for i := range messages {
+		message := &messages[i]
+		delivery := s.attempt(ctx, message)
+		if err := s.Repo.SaveAttempt(ctx, message, delivery); err != nil {
+			log.Printf("error updating email %d: %v", message.ID, err)
+		}
+	}
+}
+
+// attempt envía el correo, actualiza su estado y devuelve el registro del intento.
+func (s *EmailService) attempt(ctx context.Context, message *models.EmailMessage) *models.NotificationDelivery {
+	response, err := s.Mailer.Send(ctx, notifications.Message{
+		To:             message.To,
+		Subject:        message.Subject,
+		HTML:           message.HTML,
+		UnsubscribeURL: message.UnsubscribeURL,
+	})
+
+	now := time.Now()
+	message.Attempts++
+	if len(response) > 255 {
+		response = response[:255]
+	}
+	message.ProviderResponse = response
+	delivery := &models.NotificationDelivery{
+		Channel:          NOTIFICATION_CHANNEL_EMAIL,
+		MessageID:        message.ID,
+		Recipient:        message.To,
+		Template:         message.Template,
+		Attempt:          message.Attempts,
+		Status:           NOTIFICATION_DELIVERY_SENT,
+		ProviderResponse: response,
+		RequestID:        message.RequestID,
+	}
+	if err == nil {
+		message.Status = EMAIL_STATUS_SENT
+		message.LastError = ""
+		message.SentAt = &now
+		return delivery
+	}
+
+	message.LastError = err.Error()
+	if len(message.LastError) > 500 {
+		message.LastError = message.LastError[:500]
+	}
+	delivery.Status = NOTIFICATION_DELIVERY_FAILED
+	delivery.Error = message.LastError
+	log.Printf("email %d to %s failed (attempt %d): %v", message.ID, message.To, message.Attempts, err)
+	if message.Attempts >= config.EMAIL_MAX_ATTEMPTS {
+		message.Status = EMAIL_STATUS_FAILED
+		return delivery
+	}
+	message.NextAttemptAt = now.Add(emailRetryDelay(message.Attempts))
+	return delivery
+}
+
+// Requeue vuelve a encolar un correo que agotó sus intentos. Devuelve false si el correo no está
+// fallido.
+func (s *EmailService) Requeue(ctx context.Context, messageID int) (bool, error) {
+	requeued, err := s.Repo.RequeueMessage(ctx, messageID, EMAIL_STATUS_FAILED, EMAIL_STATUS_PENDING, time.Now())
+	if err != nil || !requeued {
+		return requeued, err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return true, nil
+}
+
+func emailRetryDelay(attempts int) time.Duration {
+	delay := config.EMAIL_RETRY_BASE_DELAY
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= config.EMAIL_RETRY_MAX_DELAY {
+			return config.EMAIL_RETRY_MAX_DELAY
+		}
+	}
+	return delay
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/notifications"
+	"totesbackend/repositories"
+)
+
+var (
+	ErrUnknownEmailTemplate = errors.New("unknown email template")
+	ErrInvalidEmailTemplate = errors.New("invalid email template")
+)
+
+// EmailTemplateService administra las versiones editadas de las plantillas de notifications. Cada
+// cambio crea una versión nueva; la activa reemplaza a la plantilla incluida en el binario.
+type EmailTemplateService struct {
+	Repo  repositories.EmailTemplateRepositoryInterface
+	Email *EmailService
+}
+
+func NewEmailTemplateService(repo repositories.EmailTemplateRepositoryInterface) *EmailTemplateService {
+	return &EmailTemplateService{Repo: repo}
+}
+
+func (s *EmailTemplateService) ListTemplates(ctx context.Context) ([]dtos.EmailTemplateSummaryDTO, error) {
+	active, err := s.Repo.GetActiveVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]models.EmailTemplateVersion, len(active))
+	for _, version := range active {
+		byName[version.Name] = version
+	}
+
+	names := notifications.TemplateNames()
+	result := make([]dtos.EmailTemplateSummaryDTO, len(names))
+	for i, name := range names {
+		result[i] = dtos.EmailTemplateSummaryDTO{Name: name}
+		if version, ok := byName[name]; ok {
+			createdAt := version.CreatedAt
+			result[i].ActiveVersion = version.Version
+			result[i].UpdatedAt = &createdAt
+		}
+	}
+	return result, nil
+}
+
+// GetTemplate devuelve el texto que se está usando: el de la versión activa o el incluido.
+func (s *EmailTemplateService) GetTemplate(ctx context.Context, name string) (*dtos.EmailTemplateDTO, error) {
+	source, version, err := s.currentSource(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &dtos.EmailTemplateDTO{
+		Name:          name,
+		ActiveVersion: version,
+		Subject:       source.Subject,
+		Body:          source.Body,
+		Placeholders:  notifications.Placeholders(name),
+	}, nil
+}
+
+func (s *EmailTemplateService) GetVersions(ctx context.Context, name string) ([]models.EmailTemplateVersion, error) {
+	if _, ok := notifications.DefaultSource(name); !ok {
+		return nil, ErrUnknownEmailTemplate
+	}
+	return s.Repo.GetVersions(ctx, name)
+}
+
+// CreateVersion valida el texto con los datos de ejemplo, lo guarda como versión nueva y lo activa.
+func (s *EmailTemplateService) CreateVersion(ctx context.Context, name string, dto dtos.CreateEmailTemplateVersionDTO, createdBy string) (*models.EmailTemplateVersion, error) {
+	source := notifications.Source{Subject: dto.Subject, Body: dto.Body}
+	if _, _, err := s.renderDraft(name, source, nil); err != nil {
+		return nil, err
+	}
+
+	version := &models.EmailTemplateVersion{
+		Name:      name,
+		Subject:   dto.Subject,
+		Body:      dto.Body,
+		CreatedBy: createdBy,
+	}
+	if err := s.Repo.CreateVersion(ctx, version); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// ActivateVersion vuelve a una versión anterior; con version 0 se vuelve a la plantilla incluida.
+func (s *EmailTemplateService) ActivateVersion(ctx context.Context, name string, version int) error {
+	if _, ok := notifications.DefaultSource(name); !ok {
+		return ErrUnknownEmailTemplate
+	}
+	return s.Repo.ActivateVersion(ctx, name, version)
+}
+
+func (s *EmailTemplateService) Preview(ctx context.Context, name string, dto dtos.PreviewEmailTemplateDTO) (*dtos.EmailTemplatePreviewDTO, error) {
+	source, err := s.draftSource(ctx, name, dto.Subject, dto.Body)
+	if err != nil {
+		return nil, err
+	}
+	subject, body, err := s.renderDraft(name, source, dto.Data)
+	if err != nil {
+		return nil, err
+	}
+	return &dtos.EmailTemplatePreviewDTO{Subject: subject, HTML: body}, nil
+}
+
+// SendTest envía el borrador (o la versión activa) con los datos de ejemplo a la dirección indicada.
+func (s *EmailTemplateService) SendTest(ctx context.Context, name string, dto dtos.SendTestEmailDTO) (*models.EmailMessage, error) {
+	source, err := s.draftSource(ctx, name, dto.Subject, dto.Body)
+	if err != nil {
+		return nil, err
+	}
+	subject, body, err := s.renderDraft(name, source, dto.Data)
+	if err != nil {
+		return nil, err
+	}
+	return s.Email.SendRendered(ctx, dto.To, name, "[Prueba] "+subject, body)
+}
+
+// Render es el que usa EmailService: la versión activa si hay una, si no la plantilla incluida.
+func (s *EmailTemplateService) Render(ctx context.Context, name string, data interface{}, unsubscribeURL string) (subject, body string, err error) {
+	version, err := s.Repo.GetActiveVersion(ctx, name)
+	if err != nil {
+		return "", "", err
+	}
+	if version != nil {
+		source := notifications.Source{Subject: version.Subject, Body: version.Body}
+		subject, body, err := notifications.RenderSource(name, source, data, unsubscribeURL)
+		if err == nil {
+			return subject, body, nil
+		}
+		// se validó al guardarla, pero un correo no se pierde por un error en una versión editada
+		log.Printf("error rendering version %d of email template %s, using the default: %v", version.Version, name, err)
+	}
+	return notifications.Render(name, data, unsubscribeURL)
+}
+
+func (s *EmailTemplateService) currentSource(ctx context.Context, name string) (notifications.Source, int, error) {
+	source, ok := notifications.DefaultSource(name)
+	if !ok {
+		return source, 0, ErrUnknownEmailTemplate
+	}
+	version, err := s.Repo.GetActiveVersion(ctx, name)
+	if err != nil {
+		return source, 0, err
+	}
+	if version == nil {
+		return source, 0, nil
+	}
+	return notifications.Source{Subject: version.Subject, Body: version.Body}, version.Version, nil
+}
+
+// draftSource completa el borrador con el texto actual en lo que no se haya enviado.
+func (s *EmailTemplateService) draftSource(ctx context.Context, name string, subject, body *string) (notifications.Source, error) {
+	source, _, err := s.currentSource(ctx, name)
+	if err != nil {
+		return source, err
+	}
+	if subject != nil {
+		source.Subject = *subject
+	}
+	if body != nil {
+		source.Body = *body
+	}
+	return source, nil
+}
+
+// renderDraft usa los datos de ejemplo de la plantilla, con los campos de data encima.
+func (s *EmailTemplateService) renderDraft(name string, source notifications.Source, data json.RawMessage) (string, string, error) {
+	sample := notifications.SampleData(name)
+	if sample == nil {
+		return "", "", ErrUnknownEmailTemplate
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, sample); err != nil {
+			return "", "", fmt.Errorf("%w: data: %v", ErrInvalidEmailTemplate, err)
+		}
+	}
+	subject, body, err := notifications.RenderSource(name, source, sample, "")
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %v", ErrInvalidEmailTemplate, err)
+	}
+	if len(subject) > 255 {
+		return "", "", fmt.Errorf("%w: subject is longer than 255 characters", ErrInvalidEmailTemplate)
+	}
+	return subject, body, nil
+}
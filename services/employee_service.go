@@ -1,38 +1,40 @@
 package services
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
 
 type EmployeeService struct {
-	Repo *repositories.EmployeeRepository
+	Repo repositories.EmployeeRepositoryInterface
 }
 
-func NewEmployeeService(repo *repositories.EmployeeRepository) *EmployeeService {
+func NewEmployeeService(repo repositories.EmployeeRepositoryInterface) *EmployeeService {
 	return &EmployeeService{Repo: repo}
 }
 
-func (s *EmployeeService) GetEmployeeByID(id string) (*models.Employee, error) {
-	return s.Repo.GetEmployeeByID(id)
+func (s *EmployeeService) GetEmployeeByID(ctx context.Context, id string) (*models.Employee, error) {
+	return s.Repo.GetEmployeeByID(ctx, id)
 }
 
-func (s *EmployeeService) SearchEmployeesByID(query string) ([]models.Employee, error) {
-	return s.Repo.SearchEmployeesByID(query)
+func (s *EmployeeService) SearchEmployeesByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Employee, int64, error) {
+	return s.Repo.SearchEmployeesByID(ctx, query, pagination)
 }
 
-func (s *EmployeeService) SearchEmployeesByName(names string) ([]models.Employee, error) {
-	return s.Repo.SearchEmployeesByName(names)
+func (s *EmployeeService) SearchEmployeesByName(ctx context.Context, names string, pagination dtos.PaginationDTO) ([]models.Employee, int64, error) {
+	return s.Repo.SearchEmployeesByName(ctx, names, pagination)
 }
 
-func (s *EmployeeService) GetAllEmployees() ([]models.Employee, error) {
-	return s.Repo.GetAllEmployees()
+func (s *EmployeeService) GetAllEmployees(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Employee, int64, error) {
+	return s.Repo.GetAllEmployees(ctx, pagination)
 }
 
-func (s *EmployeeService) UpdateEmployee(employee *models.Employee) error {
-	return s.Repo.UpdateEmployee(employee)
+func (s *EmployeeService) UpdateEmployee(ctx context.Context, employee *models.Employee) error {
+	return s.Repo.UpdateEmployee(ctx, employee)
 }
 
-func (s *EmployeeService) CreateEmployee(employee *models.Employee) (*models.Employee, error) {
-	return s.Repo.CreateEmployee(employee)
+func (s *EmployeeService) CreateEmployee(ctx context.Context, employee *models.Employee) (*models.Employee, error) {
+	return s.Repo.CreateEmployee(ctx, employee)
 }
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+	"sync"
+	"totesbackend/dtos"
+	"totesbackend/models"
+)
+
+const (
+	DOMAIN_EVENT_INVOICE_CREATED              = "invoice.created"
+	DOMAIN_EVENT_INVOICE_CANCELLED            = "invoice.cancelled"
+	DOMAIN_EVENT_INVOICE_RETURNED             = "invoice.returned"
+	DOMAIN_EVENT_STOCK_CHANGED                = "item.stock_changed"
+	DOMAIN_EVENT_LOW_STOCK                    = "item.low_stock"
+	DOMAIN_EVENT_PURCHASE_ORDER_CREATED       = "purchase_order.created"
+	DOMAIN_EVENT_PURCHASE_ORDER_STATE_CHANGED = "purchase_order.state_changed"
+	DOMAIN_EVENT_APPOINTMENT_BOOKED           = "appointment.booked"
+	DOMAIN_EVENT_APPOINTMENT_UPDATED          = "appointment.updated"
+	DOMAIN_EVENT_APPOINTMENT_STATE_CHANGED    = "appointment.state_changed"
+	DOMAIN_EVENT_APPOINTMENT_DELETED          = "appointment.deleted"
+)
+
+// DomainEvent es algo que ya pasó y quedó guardado. Los servicios lo publican en el EventBus y los
+// interesados (webhooks, notificaciones, auditoría, ...) se suscriben sin que el servicio los conozca.
+type DomainEvent interface {
+	EventName() string
+}
+
+// InvoiceCreated se publica al emitir una factura, directamente o desde una cotización. Invoice trae
+// su cliente y sus items.
+type InvoiceCreated struct {
+	Invoice *models.Invoice
+}
+
+func (InvoiceCreated) EventName() string { return DOMAIN_EVENT_INVOICE_CREATED }
+
+// InvoiceCancelled se publica al anular una factura con su nota crédito.
+type InvoiceCancelled struct {
+	CreditNote *models.CreditNote
+}
+
+func (InvoiceCancelled) EventName() string { return DOMAIN_EVENT_INVOICE_CANCELLED }
+
+// InvoiceReturned se publica al registrar una devolución parcial de una factura.
+type InvoiceReturned struct {
+	Return *models.InvoiceReturn
+}
+
+func (InvoiceReturned) EventName() string { return DOMAIN_EVENT_INVOICE_RETURNED }
+
+// StockChanged se publica por cada item cuyo stock cambió.
+type StockChanged struct {
+	dtos.StockChangedEventDTO
+}
+
+func (StockChanged) EventName() string { return DOMAIN_EVENT_STOCK_CHANGED }
+
+// LowStock se publica cuando el stock de un item baja hasta su nivel de reorden o menos.
+type LowStock struct {
+	dtos.LowStockEventDTO
+}
+
+func (LowStock) EventName() string { return DOMAIN_EVENT_LOW_STOCK }
+
+// PurchaseOrderCreated se publica al crear una orden de compra.
+type PurchaseOrderCreated struct {
+	PurchaseOrder *models.PurchaseOrder
+}
+
+func (PurchaseOrderCreated) EventName() string { return DOMAIN_EVENT_PURCHASE_ORDER_CREATED }
+
+// PurchaseOrderStateChanged se publica cuando una orden de compra pasa a otro estado.
+type PurchaseOrderStateChanged struct {
+	PurchaseOrder *models.PurchaseOrder
+}
+
+func (PurchaseOrderStateChanged) EventName() string { return DOMAIN_EVENT_PURCHASE_ORDER_STATE_CHANGED }
+
+// AppointmentBooked se publica al crear una cita. SendConfirmation indica si el cliente debe recibir
+// la confirmación con el enlace para cancelar.
+type AppointmentBooked struct {
+	Appointment      *models.Appointment
+	SendConfirmation bool
+}
+
+func (AppointmentBooked) EventName() string { return DOMAIN_EVENT_APPOINTMENT_BOOKED }
+
+// AppointmentUpdated se publica al editar los datos de una cita.
+type AppointmentUpdated struct {
+	Appointment *models.Appointment
+}
+
+func (AppointmentUpdated) EventName() string { return DOMAIN_EVENT_APPOINTMENT_UPDATED }
+
+// AppointmentStateChanged se publica cuando una cita pasa a otro estado.
+type AppointmentStateChanged struct {
+	Appointment *models.Appointment
+}
+
+func (AppointmentStateChanged) EventName() string { return DOMAIN_EVENT_APPOINTMENT_STATE_CHANGED }
+
+// AppointmentDeleted se publica al borrar una cita.
+type AppointmentDeleted struct {
+	ID int
+}
+
+func (AppointmentDeleted) EventName() string { return DOMAIN_EVENT_APPOINTMENT_DELETED }
+
+// EventBus reparte los eventos de dominio entre los suscriptores dentro del mismo proceso. Los
+// manejadores corren en orden y en la goroutine de quien publica, después de guardar la operación:
+// no pueden hacerla fallar, así que registran sus errores en el log y lo lento lo encolan.
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers map[string][]func(context.Context, DomainEvent)
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{handlers: make(map[string][]func(context.Context, DomainEvent))}
+}
+
+// Subscribe registra handler para los eventos del tipo E. Los servicios que escuchan eventos lo
+// llaman desde su HandleEvents.
+func Subscribe[E DomainEvent](bus *EventBus, handler func(ctx context.Context, event E)) {
+	var zero E
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.handlers[zero.EventName()] = append(bus.handlers[zero.EventName()], func(ctx context.Context, event DomainEvent) {
+		handler(ctx, event.(E))
+	})
+}
+
+// Publish entrega el evento a sus suscriptores. Un manejador que entra en pánico se registra en el
+// log y no impide que los demás reciban el evento.
+func (b *EventBus) Publish(ctx context.Context, event DomainEvent) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	handlers := b.handlers[event.EventName()]
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("panic handling %s event: %v\n%s", event.EventName(), r, debug.Stack())
+				}
+			}()
+			handler(ctx, event)
+		}()
+	}
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+	"totesbackend/config"
+	"totesbackend/dtos"
+	"totesbackend/repositories"
+)
+
+const (
+	STREAM_EVENT_APPOINTMENT_CREATED = "appointment.created"
+	STREAM_EVENT_LOW_STOCK           = "item.low_stock"
+	STREAM_EVENT_COMMENT_CREATED     = "comment.created"
+)
+
+// StreamEventPermissions indica el permiso que necesita un usuario para recibir cada tipo de evento.
+var StreamEventPermissions = map[string]int{
+	STREAM_EVENT_APPOINTMENT_CREATED: config.PERMISSION_GET_ALL_APPOINTMENTS,
+	STREAM_EVENT_LOW_STOCK:           config.PERMISSION_GET_ALL_ITEMS,
+	STREAM_EVENT_COMMENT_CREATED:     config.PERMISSION_GET_ALL_COMMENTS,
+}
+
+// EventSubscription es un cliente conectado a /events. Events se cierra al cancelar la suscripción
+// o al apagar el servidor.
+type EventSubscription struct {
+	Events chan dtos.StreamEventDTO
+	types  map[string]bool
+}
+
+// EventStreamService reparte los eventos en memoria entre los clientes conectados. Nunca bloquea a
+// quien publica: si el buffer de un cliente está lleno el evento se descarta para ese cliente.
+type EventStreamService struct {
+	mu          sync.Mutex
+	subscribers map[*EventSubscription]struct{}
+	nextID      int64
+	closed      bool
+	// Bus recibe LowStock de los items que PublishLowStock encuentra con stock bajo
+	Bus *EventBus
+}
+
+func NewEventStreamService() *EventStreamService {
+	return &EventStreamService{subscribers: make(map[*EventSubscription]struct{})}
+}
+
+func (s *EventStreamService) Subscribe(eventTypes []string) *EventSubscription {
+	return s.SubscribeBuffered(eventTypes, config.EVENT_STREAM_BUFFER)
+}
+
+// SubscribeBuffered es Subscribe con un buffer propio, para suscriptores internos que no deben
+// perder eventos en una ráfaga.
+func (s *EventStreamService) SubscribeBuffered(eventTypes []string, buffer int) *EventSubscription {
+	subscription := &EventSubscription{
+		Events: make(chan dtos.StreamEventDTO, buffer),
+		types:  make(map[string]bool, len(eventTypes)),
+	}
+	for _, eventType := range eventTypes {
+		subscription.types[eventType] = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		close(subscription.Events)
+		return subscription
+	}
+	s.subscribers[subscription] = struct{}{}
+	return subscription
+}
+
+func (s *EventStreamService) Unsubscribe(subscription *EventSubscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.subscribers[subscription]; ok {
+		delete(s.subscribers, subscription)
+		close(subscription.Events)
+	}
+}
+
+// HandleEvents reenvía a /events las citas nuevas y los items con stock bajo.
+func (s *EventStreamService) HandleEvents(bus *EventBus) {
+	Subscribe(bus, func(ctx context.Context, event AppointmentBooked) {
+		s.Publish(STREAM_EVENT_APPOINTMENT_CREATED, event.Appointment)
+	})
+	Subscribe(bus, func(ctx context.Context, event LowStock) {
+		s.Publish(STREAM_EVENT_LOW_STOCK, event.LowStockEventDTO)
+	})
+}
+
+func (s *EventStreamService) Publish(eventType string, data interface{}) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	event := dtos.StreamEventDTO{ID: s.nextID, Type: eventType, Data: data, CreatedAt: time.Now()}
+	for subscription := range s.subscribers {
+		if !subscription.types[eventType] {
+			continue
+		}
+		select {
+		case subscription.Events <- event:
+		default:
+			log.Printf("event stream client is not keeping up, dropping event %d (%s)", event.ID, eventType)
+		}
+	}
+}
+
+// Close termina todas las conexiones abiertas para que el apagado del servidor no las espere.
+func (s *EventStreamService) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for subscription := range s.subscribers {
+		delete(s.subscribers, subscription)
+		close(subscription.Events)
+	}
+}
+
+// StockSnapshot lee el stock actual de los items antes de una operación; PublishLowStock lo compara
+// después para avisar solo de los items que bajaron hasta su nivel de reorden o menos.
+func (s *EventStreamService) StockSnapshot(ctx context.Context, itemRepo repositories.ItemRepositoryInterface, itemIDs []int) map[int]int {
+	if s == nil {
+		return nil
+	}
+
+	snapshot := make(map[int]int, len(itemIDs))
+	for _, id := range itemIDs {
+		item, err := itemRepo.GetItemByID(ctx, strconv.Itoa(id))
+		if err != nil {
+			continue
+		}
+		snapshot[id] = item.Stock
+	}
+	return snapshot
+}
+
+func (s *EventStreamService) PublishLowStock(ctx context.Context, itemRepo repositories.ItemRepositoryInterface, before map[int]int) {
+	if s == nil {
+		return
+	}
+
+	for id, previousStock := range before {
+		item, err := itemRepo.GetItemByID(ctx, strconv.Itoa(id))
+		if err != nil {
+			continue
+		}
+		if item.Stock < previousStock && item.ReorderLevel > 0 && item.Stock <= item.ReorderLevel {
+			s.Bus.Publish(ctx, LowStock{dtos.LowStockEventDTO{
+				ItemID:    item.ID,
+				Name:      item.Name,
+				Stock:     item.Stock,
+				Threshold: item.ReorderLevel,
+			}})
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"totesbackend/config"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 
@@ -9,25 +12,27 @@ import (
 )
 
 type ExternalSaleService struct {
-	Repo         *repositories.ExternalSaleRepository
-	CustomerRepo *repositories.CustomerRepository
+	Repo         repositories.ExternalSaleRepositoryInterface
+	CustomerRepo repositories.CustomerRepositoryInterface
 }
 
-func NewExternalSaleService(repo *repositories.ExternalSaleRepository, customerRepo *repositories.CustomerRepository) *ExternalSaleService {
+func NewExternalSaleService(repo repositories.ExternalSaleRepositoryInterface, customerRepo repositories.CustomerRepositoryInterface) *ExternalSaleService {
 	return &ExternalSaleService{Repo: repo, CustomerRepo: customerRepo}
 }
 
-func (s *ExternalSaleService) GetExternalSaleByID(id string) (*models.ExternalSale, error) {
-	return s.Repo.GetExternalSaleByID(id)
+func (s *ExternalSaleService) GetExternalSaleByID(ctx context.Context, id string) (*models.ExternalSale, error) {
+	return s.Repo.GetExternalSaleByID(ctx, id)
 }
 
-func (s *ExternalSaleService) GetAllExternalSales() ([]models.ExternalSale, error) {
-	return s.Repo.GetAllExternalSales()
+func (s *ExternalSaleService) GetAllExternalSales(ctx context.Context, pagination dtos.PaginationDTO) ([]models.ExternalSale, int64, error) {
+	return s.Repo.GetAllExternalSales(ctx, pagination)
 }
 
-func (s *ExternalSaleService) CreateExternalSale(externalSale *models.ExternalSale) (*models.ExternalSale, error) {
+// CreateExternalSale registra la venta y descuenta su stock; si no alcanza devuelve un
+// *InsufficientStockError.
+func (s *ExternalSaleService) CreateExternalSale(ctx context.Context, externalSale *models.ExternalSale) (*models.ExternalSale, error) {
 
-	customer, err := s.CustomerRepo.GetCustomerByEmail(externalSale.Customer.Email)
+	customer, err := s.CustomerRepo.GetCustomerByEmail(ctx, externalSale.Customer.Email)
 	if err != nil {
 
 		if !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -35,7 +40,7 @@ func (s *ExternalSaleService) CreateExternalSale(externalSale *models.ExternalSa
 		}
 
 		customer = &externalSale.Customer
-		createdCustomer, err := s.CustomerRepo.CreateCustomer(customer)
+		createdCustomer, err := s.CustomerRepo.CreateCustomer(ctx, customer)
 		if err != nil {
 			return nil, err
 		}
@@ -44,9 +49,13 @@ func (s *ExternalSaleService) CreateExternalSale(externalSale *models.ExternalSa
 
 	externalSale.Customer = *customer
 
-	if err := s.Repo.CreateExternalSale(externalSale); err != nil {
+	shortages, err := s.Repo.CreateExternalSale(ctx, externalSale, newStockMovement(ctx, config.STOCK_MOVEMENT_EXTERNAL_SALE))
+	if err != nil {
 		return nil, err
 	}
+	if len(shortages) > 0 {
+		return nil, &InsufficientStockError{Shortages: shortages}
+	}
 
 	return externalSale, nil
 }
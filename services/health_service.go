@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"totesbackend/config"
+	"totesbackend/database"
+	"totesbackend/dtos"
+
+	"gorm.io/gorm"
+)
+
+// Estados de /health/ready y de cada dependencia que revisa.
+const (
+	HEALTH_STATUS_READY     = "ready"
+	HEALTH_STATUS_NOT_READY = "not_ready"
+	DEPENDENCY_STATUS_UP    = "up"
+	DEPENDENCY_STATUS_DOWN  = "down"
+)
+
+type HealthService struct {
+	DB *gorm.DB
+}
+
+func NewHealthService(db *gorm.DB) *HealthService {
+	return &HealthService{DB: db}
+}
+
+// GetDBPoolStats devuelve el estado del pool de conexiones; WaitCount creciendo mientras InUse
+// está en el máximo indica que el pool se está quedando corto.
+func (s *HealthService) GetDBPoolStats() (*dtos.DBPoolStatsDTO, error) {
+	sqlDB, err := s.DB.DB()
+	if err != nil {
+		return nil, err
+	}
+	stats := sqlDB.Stats()
+	return &dtos.DBPoolStatsDTO{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	}, nil
+}
+
+// CheckReadiness revisa la base de datos, que no queden migraciones pendientes y cada réplica, cada
+// una con HEALTH_CHECK_TIMEOUT. La instancia está lista solo si todas responden.
+func (s *HealthService) CheckReadiness(ctx context.Context) dtos.ReadinessDTO {
+	dependencies := []dtos.DependencyStatusDTO{
+		checkDependency(ctx, "database", s.pingDatabase),
+		checkDependency(ctx, "migrations", s.checkMigrations),
+	}
+	for i := 0; i < database.ReplicaCount(); i++ {
+		dependencies = append(dependencies, checkDependency(ctx, "replica_"+strconv.Itoa(i+1), func(ctx context.Context) error {
+			return database.PingReplica(ctx, i)
+		}))
+	}
+
+	readiness := dtos.ReadinessDTO{Status: HEALTH_STATUS_READY, Dependencies: dependencies}
+	for _, dependency := range dependencies {
+		if dependency.Status != DEPENDENCY_STATUS_UP {
+			readiness.Status = HEALTH_STATUS_NOT_READY
+		}
+	}
+	return readiness
+}
+
+func (s *HealthService) pingDatabase(ctx context.Context) error {
+	sqlDB, err := s.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// checkMigrations falla si el esquema está atrasado respecto a las migraciones de este binario,
+// como cuando la instancia arrancó antes de que otra terminara de migrar.
+func (s *HealthService) checkMigrations(ctx context.Context) error {
+	status, err := database.GetMigrationStatus(s.DB.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	var pending []string
+	for _, migration := range status {
+		if !migration.Applied {
+			pending = append(pending, fmt.Sprintf("%d_%s", migration.Version, migration.Name))
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("pending migrations: %s", strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+func checkDependency(ctx context.Context, name string, check func(ctx context.Context) error) dtos.DependencyStatusDTO {
+	ctx, cancel := context.WithTimeout(ctx, config.HEALTH_CHECK_TIMEOUT)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	status := dtos.DependencyStatusDTO{Name: name, Status: DEPENDENCY_STATUS_UP, LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		status.Status = DEPENDENCY_STATUS_DOWN
+		status.Error = err.Error()
+	}
+	return status
+}
@@ -1,19 +1,20 @@
 package services
 
 import (
+	"context"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
 
 type HistoricalItemPriceService struct {
-	Repo *repositories.HistoricalItemPriceRepository
+	Repo repositories.HistoricalItemPriceRepositoryInterface
 }
 
-func NewHistoricalItemPriceService(repo *repositories.HistoricalItemPriceRepository) *HistoricalItemPriceService {
+func NewHistoricalItemPriceService(repo repositories.HistoricalItemPriceRepositoryInterface) *HistoricalItemPriceService {
 	return &HistoricalItemPriceService{Repo: repo}
 }
 
 // Obtener historial de precios de un ítem específico
-func (s *HistoricalItemPriceService) GetHistoricalItemPrice(itemID string) ([]models.HistoricalItemPrice, error) {
-	return s.Repo.GetHistoricalItemPrice(itemID)
+func (s *HistoricalItemPriceService) GetHistoricalItemPrice(ctx context.Context, itemID string) ([]models.HistoricalItemPrice, error) {
+	return s.Repo.GetHistoricalItemPrice(ctx, itemID)
 }
@@ -1,22 +1,87 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
 
+var (
+	ErrInvalidIdentifierTypeName = errors.New("identifier type name cannot be blank")
+	ErrIdentifierTypeNameTaken   = errors.New("an identifier type with that name already exists")
+	ErrIdentifierTypeInUse       = errors.New("identifier type is in use")
+)
+
 type IdentifierTypeService struct {
-	Repo *repositories.IdentifierTypeRepository
+	Repo repositories.IdentifierTypeRepositoryInterface
 }
 
-func NewIdentifierTypeService(repo *repositories.IdentifierTypeRepository) *IdentifierTypeService {
+func NewIdentifierTypeService(repo repositories.IdentifierTypeRepositoryInterface) *IdentifierTypeService {
 	return &IdentifierTypeService{Repo: repo}
 }
 
-func (s *IdentifierTypeService) GetAllIdentifierTypes() ([]models.IdentifierType, error) {
-	return s.Repo.GetAllIdentifierTypes()
+func (s *IdentifierTypeService) GetAllIdentifierTypes(ctx context.Context, pagination dtos.PaginationDTO) ([]models.IdentifierType, int64, error) {
+	return s.Repo.GetAllIdentifierTypes(ctx, pagination)
+}
+
+func (s *IdentifierTypeService) GetIdentifierTypeByID(ctx context.Context, id string) (*models.IdentifierType, error) {
+	return s.Repo.GetIdentifierTypeByID(ctx, id)
+}
+
+func (s *IdentifierTypeService) CreateIdentifierType(ctx context.Context, dto dtos.IdentifierTypeDTO) (*models.IdentifierType, error) {
+	identifierType := &models.IdentifierType{Name: strings.TrimSpace(dto.Name)}
+	if err := s.checkNameAvailable(ctx, identifierType.Name, 0); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.CreateIdentifierType(ctx, identifierType); err != nil {
+		return nil, err
+	}
+	return identifierType, nil
+}
+
+func (s *IdentifierTypeService) UpdateIdentifierType(ctx context.Context, id int, dto dtos.IdentifierTypeDTO) (*models.IdentifierType, error) {
+	identifierType, err := s.Repo.GetIdentifierTypeByID(ctx, strconv.Itoa(id))
+	if err != nil {
+		return nil, err
+	}
+	identifierType.Name = strings.TrimSpace(dto.Name)
+	if err := s.checkNameAvailable(ctx, identifierType.Name, id); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.UpdateIdentifierType(ctx, identifierType); err != nil {
+		return nil, err
+	}
+	return identifierType, nil
+}
+
+// DeleteIdentifierType solo borra tipos que ningún cliente, empleado o cita usa.
+func (s *IdentifierTypeService) DeleteIdentifierType(ctx context.Context, id int) error {
+	usage, err := s.Repo.CountIdentifierTypeUsage(ctx, id)
+	if err != nil {
+		return err
+	}
+	if usage > 0 {
+		return fmt.Errorf("%w by %d customers, employees or appointments", ErrIdentifierTypeInUse, usage)
+	}
+	return s.Repo.DeleteIdentifierType(ctx, id)
 }
 
-func (s *IdentifierTypeService) GetIdentifierTypeByID(id string) (*models.IdentifierType, error) {
-	return s.Repo.GetIdentifierTypeByID(id)
+// checkNameAvailable evita nombres repetidos sin distinguir mayúsculas; exceptID es el tipo que se edita.
+func (s *IdentifierTypeService) checkNameAvailable(ctx context.Context, name string, exceptID int) error {
+	if name == "" {
+		return ErrInvalidIdentifierTypeName
+	}
+	existing, err := s.Repo.GetIdentifierTypeByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != exceptID {
+		return ErrIdentifierTypeNameTaken
+	}
+	return nil
 }
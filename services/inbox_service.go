@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"totesbackend/config"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+)
+
+// InboxService guarda en la bandeja de cada usuario los eventos de EventStreamService. Recibe
+// cada evento quien tiene el permiso de su listado (StreamEventPermissions) y no desactivó el
+// canal in_app en sus preferencias.
+type InboxService struct {
+	Repo         repositories.NotificationRepositoryInterface
+	AuthRepo     repositories.AuthorizationRepositoryInterface
+	Preferences  *NotificationPreferenceService
+	events       *EventStreamService
+	subscription *EventSubscription
+	done         chan struct{}
+}
+
+func NewInboxService(repo repositories.NotificationRepositoryInterface, authRepo repositories.AuthorizationRepositoryInterface,
+	preferences *NotificationPreferenceService, events *EventStreamService) *InboxService {
+	eventTypes := make([]string, 0, len(StreamEventPermissions))
+	for eventType := range StreamEventPermissions {
+		eventTypes = append(eventTypes, eventType)
+	}
+
+	s := &InboxService{
+		Repo:         repo,
+		AuthRepo:     authRepo,
+		Preferences:  preferences,
+		events:       events,
+		subscription: events.SubscribeBuffered(eventTypes, config.INBOX_EVENT_BUFFER),
+		done:         make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *InboxService) GetNotifications(ctx context.Context, userID int, unreadOnly bool, pagination dtos.PaginationDTO) ([]dtos.GetNotificationDTO, int64, error) {
+	notifications, total, err := s.Repo.GetNotificationsByUser(ctx, userID, unreadOnly, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]dtos.GetNotificationDTO, len(notifications))
+	for i, notification := range notifications {
+		result[i] = dtos.GetNotificationDTO{
+			ID:        notification.ID,
+			EventType: notification.EventType,
+			Title:     notification.Title,
+			Body:      notification.Body,
+			Read:      notification.ReadAt != nil,
+			ReadAt:    notification.ReadAt,
+			CreatedAt: notification.CreatedAt,
+		}
+		if notification.Data != "" {
+			result[i].Data = json.RawMessage(notification.Data)
+		}
+	}
+	return result, total, nil
+}
+
+func (s *InboxService) CountUnread(ctx context.Context, userID int) (int64, error) {
+	return s.Repo.CountUnread(ctx, userID)
+}
+
+func (s *InboxService) MarkAsRead(ctx context.Context, userID, id int) error {
+	return s.Repo.MarkAsRead(ctx, userID, id, time.Now())
+}
+
+func (s *InboxService) MarkAllAsRead(ctx context.Context, userID int) (int64, error) {
+	return s.Repo.MarkAllAsRead(ctx, userID, time.Now())
+}
+
+// PurgeReadNotifications borra las notificaciones leídas hace más de retentionDays días.
+func (s *InboxService) PurgeReadNotifications(ctx context.Context, retentionDays int) (int64, error) {
+	return s.Repo.DeleteReadBefore(ctx, time.Now().AddDate(0, 0, -retentionDays))
+}
+
+// Close deja de recibir eventos y espera a que se guarden los que ya llegaron.
+func (s *InboxService) Close() {
+	s.events.Unsubscribe(s.subscription)
+	<-s.done
+}
+
+func (s *InboxService) run() {
+	defer close(s.done)
+	for event := range s.subscription.Events {
+		if err := s.deliver(event); err != nil {
+			log.Printf("error saving %s notification: %v", event.Type, err)
+		}
+	}
+}
+
+func (s *InboxService) deliver(event dtos.StreamEventDTO) error {
+	// corre en segundo plano, fuera de cualquier petición
+	ctx := context.Background()
+	userIDs, err := s.AuthRepo.GetUserIDsWithPermission(ctx, StreamEventPermissions[event.Type])
+	if err != nil {
+		return err
+	}
+	if s.Preferences != nil {
+		if userIDs, err = s.Preferences.FilterRecipients(ctx, NOTIFICATION_RECIPIENT_USER, userIDs, event.Type, NOTIFICATION_CHANNEL_IN_APP); err != nil {
+			return err
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	title, body := describeEvent(event)
+	notifications := make([]models.Notification, len(userIDs))
+	for i, userID := range userIDs {
+		notifications[i] = models.Notification{
+			UserID:    userID,
+			EventType: event.Type,
+			Title:     title,
+			Body:      body,
+			Data:      string(data),
+			CreatedAt: event.CreatedAt,
+		}
+	}
+	return s.Repo.CreateNotifications(ctx, notifications)
+}
+
+// describeEvent arma el título y el texto que se muestran en la bandeja.
+func describeEvent(event dtos.StreamEventDTO) (title, body string) {
+	switch data := event.Data.(type) {
+	case *models.Appointment:
+		return "Nueva cita", fmt.Sprintf("%s %s agendó una cita para el %s", data.CustomerName, data.LastName,
+			data.DateTime.Format("02/01/2006 15:04"))
+	case dtos.LowStockEventDTO:
+		return "Stock bajo", fmt.Sprintf("%s tiene %d unidades (umbral %d)", data.Name, data.Stock, data.Threshold)
+	case *models.Comment:
+		body = fmt.Sprintf("%s %s: %s", data.Name, data.LastName, data.Comment)
+		if len(body) > 500 {
+			// se corta por bytes; ToValidUTF8 descarta el carácter que haya quedado a medias
+			body = strings.ToValidUTF8(body[:500], "")
+		}
+		return "Nuevo comentario", body
+	default:
+		return event.Type, ""
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/repositories"
+)
+
+type InventoryReportService struct {
+	Repo repositories.InventoryReportRepositoryInterface
+}
+
+func NewInventoryReportService(repo repositories.InventoryReportRepositoryInterface) *InventoryReportService {
+	return &InventoryReportService{Repo: repo}
+}
+
+// GetInventoryTurnover calcula la rotación (unidades vendidas / stock actual) y los días de stock
+// restantes al ritmo de venta del periodo. Los items con stock y sin ventas se marcan como stock muerto.
+func (s *InventoryReportService) GetInventoryTurnover(ctx context.Context, from, to time.Time, groupBy string) (*dtos.InventoryTurnoverReportDTO, error) {
+	if groupBy != "item" && groupBy != "category" {
+		return nil, fmt.Errorf("%w: groupBy must be item or category", ErrInvalidReportParams)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: 'to' date must be after 'from' date", ErrInvalidReportParams)
+	}
+
+	sales, err := s.Repo.GetItemSalesBetween(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []dtos.InventoryTurnoverRowDTO
+	if groupBy == "item" {
+		for _, sale := range sales {
+			rows = append(rows, dtos.InventoryTurnoverRowDTO{
+				ItemID:       sale.ItemID,
+				ItemName:     sale.ItemName,
+				CategoryID:   sale.CategoryID,
+				CategoryName: sale.CategoryName,
+				Stock:        sale.Stock,
+				UnitsSold:    sale.UnitsSold,
+			})
+		}
+	} else {
+		indexByCategory := make(map[int]int)
+		for _, sale := range sales {
+			i, ok := indexByCategory[sale.CategoryID]
+			if !ok {
+				rows = append(rows, dtos.InventoryTurnoverRowDTO{
+					CategoryID:   sale.CategoryID,
+					CategoryName: sale.CategoryName,
+				})
+				i = len(rows) - 1
+				indexByCategory[sale.CategoryID] = i
+			}
+			rows[i].Stock += sale.Stock
+			rows[i].UnitsSold += sale.UnitsSold
+		}
+		sort.Slice(rows, func(i, j int) bool { return rows[i].CategoryID < rows[j].CategoryID })
+	}
+
+	days := to.Sub(from).Hours() / 24
+	if days < 1 {
+		days = 1
+	}
+
+	report := &dtos.InventoryTurnoverReportDTO{From: from, To: to, GroupBy: groupBy}
+	for i := range rows {
+		row := &rows[i]
+		if row.Stock > 0 {
+			row.Turnover = float64(row.UnitsSold) / float64(row.Stock)
+		}
+		if row.UnitsSold > 0 {
+			daysOfStock := float64(row.Stock) / (float64(row.UnitsSold) / days)
+			row.DaysOfStock = &daysOfStock
+		}
+		row.DeadStock = row.Stock > 0 && row.UnitsSold == 0
+		if row.DeadStock {
+			report.DeadStock++
+		}
+	}
+	report.Rows = rows
+
+	return report, nil
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"totesbackend/config"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+)
+
+var (
+	ErrInvalidReturn     = errors.New("invalid return")
+	ErrReturnExceedsSold = errors.New("return exceeds the units sold")
+)
+
+// ReturnExceedsSoldError lista los items de la devolución que no se vendieron en la factura o de los
+// que se piden más unidades de las que quedan por devolver.
+type ReturnExceedsSoldError struct {
+	Excesses []dtos.ReturnExcessDTO
+}
+
+func (e *ReturnExceedsSoldError) Error() string {
+	lines := make([]string, len(e.Excesses))
+	for i, excess := range e.Excesses {
+		lines[i] = fmt.Sprintf("item %d (requested %d, returnable %d)", excess.ItemID, excess.Requested, excess.Returnable)
+	}
+	return "return exceeds the units sold for " + strings.Join(lines, ", ")
+}
+
+func (e *ReturnExceedsSoldError) Is(target error) bool {
+	return target == ErrReturnExceedsSold
+}
+
+type InvoiceReturnService struct {
+	Repo repositories.InvoiceReturnRepositoryInterface
+	Bus  *EventBus
+}
+
+func NewInvoiceReturnService(repo repositories.InvoiceReturnRepositoryInterface) *InvoiceReturnService {
+	return &InvoiceReturnService{Repo: repo}
+}
+
+func (s *InvoiceReturnService) GetInvoiceReturns(ctx context.Context, invoiceID int) ([]models.InvoiceReturn, error) {
+	return s.Repo.GetInvoiceReturns(ctx, invoiceID)
+}
+
+// CreateReturn devuelve al stock parte de lo vendido en la factura y genera el documento de
+// devolución con el valor a reembolsar y cómo y cuándo se pagó. Si algún item supera lo que queda
+// por devolver devuelve un *ReturnExceedsSoldError; en una factura anulada, ErrInvoiceCancelled.
+func (s *InvoiceReturnService) CreateReturn(ctx context.Context, invoiceID int, dto dtos.CreateInvoiceReturnDTO) (*models.InvoiceReturn, error) {
+	method := strings.ToLower(strings.TrimSpace(dto.RefundMethod))
+	if !slices.Contains(config.PAYMENT_METHODS, method) {
+		return nil, fmt.Errorf("%w: refund_method must be one of %s", ErrInvalidReturn, strings.Join(config.PAYMENT_METHODS, ", "))
+	}
+	now := time.Now()
+	refundedAt := now
+	if dto.RefundDate != nil {
+		if dto.RefundDate.After(now) {
+			return nil, fmt.Errorf("%w: the refund date cannot be in the future", ErrInvalidReturn)
+		}
+		refundedAt = *dto.RefundDate
+	}
+
+	invoiceReturn := &models.InvoiceReturn{
+		Reason:       strings.TrimSpace(dto.Reason),
+		RefundMethod: method,
+		RefundedAt:   &refundedAt,
+		CreatedBy:    UserFromContext(ctx),
+	}
+	for _, item := range dto.Items {
+		invoiceReturn.Items = append(invoiceReturn.Items, models.InvoiceReturnItem{ItemID: item.ItemID, Amount: item.Amount})
+	}
+
+	excesses, ok, err := s.Repo.CreateReturn(ctx, invoiceID, invoiceReturn, newStockMovement(ctx, config.STOCK_MOVEMENT_INVOICE_RETURN), now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvoiceCancelled
+	}
+	if len(excesses) > 0 {
+		return nil, &ReturnExceedsSoldError{Excesses: excesses}
+	}
+
+	s.Bus.Publish(ctx, InvoiceReturned{Return: invoiceReturn})
+	for _, item := range invoiceReturn.Items {
+		s.Bus.Publish(ctx, StockChanged{dtos.StockChangedEventDTO{ItemID: item.ItemID, Change: item.Amount,
+			Reason: config.STOCK_MOVEMENT_INVOICE_RETURN}})
+	}
+	return s.Repo.GetInvoiceReturnByID(ctx, invoiceReturn.ID)
+}
@@ -1,42 +1,75 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
+	"totesbackend/config"
 	"totesbackend/dtos"
 	"totesbackend/models"
+	"totesbackend/notifications"
 	"totesbackend/repositories"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInsufficientStock     = errors.New("insufficient stock")
+	ErrInvalidPayment        = errors.New("invalid payment")
+	ErrInvoiceCancelled      = errors.New("invoice is cancelled")
+	ErrPaymentExceedsBalance = errors.New("payment exceeds the outstanding balance")
 )
 
+// InsufficientStockError lista los items de la factura cuyo stock no alcanzó al descontarlo.
+type InsufficientStockError struct {
+	Shortages []dtos.StockShortageDTO
+}
+
+func (e *InsufficientStockError) Error() string {
+	lines := make([]string, len(e.Shortages))
+	for i, shortage := range e.Shortages {
+		lines[i] = fmt.Sprintf("item %d (requested %d, available %d)", shortage.ItemID, shortage.Requested, shortage.Available)
+	}
+	return "insufficient stock for " + strings.Join(lines, ", ")
+}
+
+func (e *InsufficientStockError) Is(target error) bool {
+	return target == ErrInsufficientStock
+}
+
 type InvoiceService struct {
-	InvoiceRepo    *repositories.InvoiceRepository
-	ItemRepo       *repositories.ItemRepository
+	InvoiceRepo    repositories.InvoiceRepositoryInterface
+	ItemRepo       repositories.ItemRepositoryInterface
 	BillingService *BillingService
+	// Bus recibe InvoiceCreated y StockChanged de cada factura emitida
+	Bus        *EventBus
+	Events     *EventStreamService
+	Accounting *AccountingService
+	Email      *EmailService
+	// ReceiptEmails envía la factura al cliente al emitirla (INVOICE_EMAIL)
+	ReceiptEmails bool
 }
 
-func NewInvoiceService(invoiceRepo *repositories.InvoiceRepository,
-	itemRepo *repositories.ItemRepository, billingService *BillingService) *InvoiceService {
+func NewInvoiceService(invoiceRepo repositories.InvoiceRepositoryInterface,
+	itemRepo repositories.ItemRepositoryInterface, billingService *BillingService) *InvoiceService {
 	return &InvoiceService{
 		InvoiceRepo:    invoiceRepo,
 		ItemRepo:       itemRepo,
 		BillingService: billingService,
 	}
 }
-func (s *InvoiceService) CreateInvoice(dto *dtos.CreateInvoiceDTO) (*models.Invoice, error) {
-	// Verificar si hay suficiente stock para cada item
-	for _, item := range dto.Items {
-		itemID := strconv.Itoa(item.ID)
-		hasStock, err := s.ItemRepo.HasEnoughStock(itemID, item.Stock)
-		if err != nil {
-			return nil, err
-		}
-		if !hasStock {
-			return nil, errors.New("stock insuficiente para el item con ID " + itemID)
-		}
-	}
+
+// CreateInvoice factura los items y descuenta su stock. El stock se verifica al descontarlo, dentro
+// de la transacción: si algún item no alcanza devuelve un *InsufficientStockError con cada faltante.
+func (s *InvoiceService) CreateInvoice(ctx context.Context, dto *dtos.CreateInvoiceDTO) (*models.Invoice, error) {
+	dto.Currency = currencyCode(dto.Currency)
 
 	// Calcular subtotal
-	subtotal, err := s.BillingService.CalculateSubtotal(dto.Items)
+	subtotal, err := s.BillingService.CalculateSubtotal(ctx, dto.CustomerID, dto.Currency, dto.Items)
 	if err != nil {
 		return nil, err
 	}
@@ -53,32 +86,178 @@ func (s *InvoiceService) CreateInvoice(dto *dtos.CreateInvoiceDTO) (*models.Invo
 	}
 
 	// Calcular total
-	total, err := s.BillingService.CalculateTotal(discountIDs, taxIDs, dto.Items)
+	total, err := s.BillingService.CalculateTotal(ctx, dto.CustomerID, dto.Currency, discountIDs, taxIDs, dto.Items)
 	if err != nil {
 		return nil, err
 	}
 
+	itemIDs := make([]int, len(dto.Items))
+	for i, item := range dto.Items {
+		itemIDs[i] = item.ID
+	}
+	stockBefore := s.Events.StockSnapshot(ctx, s.ItemRepo, itemIDs)
+
 	// Crear la factura con los valores calculados
-	invoice, err := s.InvoiceRepo.CreateInvoice(dto, subtotal, total)
+	invoice, shortages, err := s.InvoiceRepo.CreateInvoice(ctx, dto, subtotal, total, newStockMovement(ctx, config.STOCK_MOVEMENT_INVOICE))
 	if err != nil {
 		return nil, err
 	}
+	if len(shortages) > 0 {
+		return nil, &InsufficientStockError{Shortages: shortages}
+	}
+	s.publishInvoiceCreated(ctx, invoice, dto.Items, stockBefore)
+
+	return invoice, nil
+}
+
+// publishInvoiceCreated avisa de una factura recién creada: los items que quedaron con poco stock
+// respecto a stockBefore y, en el bus, la factura y el cambio de stock de cada item.
+func (s *InvoiceService) publishInvoiceCreated(ctx context.Context, invoice *models.Invoice, items []dtos.BillingItemDTO, stockBefore map[int]int) {
+	s.Events.PublishLowStock(ctx, s.ItemRepo, stockBefore)
+	s.Bus.Publish(ctx, InvoiceCreated{Invoice: invoice})
+	for _, item := range items {
+		s.Bus.Publish(ctx, StockChanged{dtos.StockChangedEventDTO{ItemID: item.ID, Change: -item.Stock, Reason: "invoice"}})
+	}
+}
+
+// HandleEvents envía el correo de cada factura emitida si ReceiptEmails está activo. Basta con
+// llamarlo en una de las instancias del servicio.
+func (s *InvoiceService) HandleEvents(bus *EventBus) {
+	if !s.ReceiptEmails {
+		return
+	}
+	Subscribe(bus, func(ctx context.Context, event InvoiceCreated) {
+		s.sendReceipt(ctx, event.Invoice)
+	})
+}
+
+// sendReceipt encola el correo con el detalle de la factura; invoice debe traer su cliente y sus
+// items.
+func (s *InvoiceService) sendReceipt(ctx context.Context, invoice *models.Invoice) {
+	customer := invoice.Customer
+	if customer.Email == "" {
+		return
+	}
+	data := notifications.InvoiceData{
+		CustomerName: strings.TrimSpace(customer.CustomerName + " " + customer.LastName),
+		InvoiceID:    invoice.ID,
+		Date:         invoice.DateTime,
+		Items:        make([]notifications.InvoiceLineData, len(invoice.Items)),
+		Subtotal:     invoice.Subtotal,
+		Total:        invoice.Total,
+		Currency:     invoice.Currency,
+	}
+	for i, line := range invoice.Items {
+		data.Items[i] = notifications.InvoiceLineData{Name: line.Item.Name, Amount: line.Amount}
+	}
+	recipient := EmailRecipient{Type: NOTIFICATION_RECIPIENT_CUSTOMER, ID: customer.ID, Email: customer.Email}
+	_, _ = s.Email.Notify(ctx, recipient, NOTIFICATION_EVENT_INVOICE_ISSUED, notifications.TEMPLATE_INVOICE, data)
+}
 
+// SetInvoicePaid registra el pago de una factura, o lo anula con paid en false. version es la que
+// leyó el cliente; si la factura cambió desde entonces devuelve ErrVersionConflict.
+func (s *InvoiceService) SetInvoicePaid(ctx context.Context, id, version int, paid bool) (*models.Invoice, error) {
+	var paidAt *time.Time
+	if paid {
+		now := time.Now()
+		paidAt = &now
+	}
+	invoice, updated, err := s.InvoiceRepo.SetInvoicePaidAt(ctx, id, version, paidAt)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrVersionConflict
+	}
+	if paid {
+		s.Accounting.QueuePayment(ctx, invoice)
+	} else {
+		s.Accounting.CancelPayment(ctx, invoice.ID)
+	}
 	return invoice, nil
 }
 
-func (s *InvoiceService) GetInvoiceByID(id string) (*models.Invoice, error) {
-	return s.InvoiceRepo.GetInvoiceByID(id)
+// RecordPayment registra un abono a la factura y actualiza su estado de pago. El abono no puede
+// pasar del saldo pendiente ni registrarse en una factura anulada.
+func (s *InvoiceService) RecordPayment(ctx context.Context, invoiceID int, dto dtos.CreatePaymentDTO) (*models.Payment, *models.Invoice, error) {
+	method := strings.ToLower(strings.TrimSpace(dto.Method))
+	if !slices.Contains(config.PAYMENT_METHODS, method) {
+		return nil, nil, fmt.Errorf("%w: method must be one of %s", ErrInvalidPayment, strings.Join(config.PAYMENT_METHODS, ", "))
+	}
+	now := time.Now()
+	payment := &models.Payment{
+		InvoiceID: invoiceID,
+		Amount:    dto.Amount,
+		Method:    method,
+		Reference: strings.TrimSpace(dto.Reference),
+		Date:      now,
+		CreatedBy: UserFromContext(ctx),
+	}
+	if dto.Date != nil {
+		if dto.Date.After(now) {
+			return nil, nil, fmt.Errorf("%w: the date cannot be in the future", ErrInvalidPayment)
+		}
+		payment.Date = *dto.Date
+	}
+
+	invoice, recorded, err := s.InvoiceRepo.RecordPayment(ctx, payment)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !recorded {
+		if invoice.CancelledAt != nil {
+			return nil, nil, ErrInvoiceCancelled
+		}
+		return nil, nil, fmt.Errorf("%w of %.2f %s", ErrPaymentExceedsBalance, InvoiceBalance(invoice), invoice.Currency)
+	}
+	if invoice.PaymentStatus == config.INVOICE_PAYMENT_PAID {
+		s.Accounting.QueuePayment(ctx, invoice)
+	}
+	return payment, invoice, nil
+}
+
+// InvoiceBalance es el saldo pendiente de la factura, en su moneda.
+func InvoiceBalance(invoice *models.Invoice) float64 {
+	return roundCurrency(invoice.Total - invoice.AmountPaid)
+}
+
+// GetInvoicePayments devuelve los pagos de la factura con su saldo pendiente.
+func (s *InvoiceService) GetInvoicePayments(ctx context.Context, invoiceID int) (*dtos.InvoicePaymentsDTO, error) {
+	invoice, err := s.InvoiceRepo.GetInvoiceByID(ctx, strconv.Itoa(invoiceID))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", gorm.ErrRecordNotFound, err)
+	}
+	payments, err := s.InvoiceRepo.GetInvoicePayments(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	return &dtos.InvoicePaymentsDTO{
+		InvoiceID:     invoice.ID,
+		Currency:      invoice.Currency,
+		Total:         invoice.Total,
+		AmountPaid:    invoice.AmountPaid,
+		Balance:       InvoiceBalance(invoice),
+		PaymentStatus: invoice.PaymentStatus,
+		Payments:      payments,
+	}, nil
+}
+
+func (s *InvoiceService) GetInvoiceByID(ctx context.Context, id string) (*models.Invoice, error) {
+	return s.InvoiceRepo.GetInvoiceByID(ctx, id)
+}
+
+func (s *InvoiceService) GetAllInvoices(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Invoice, int64, error) {
+	return s.InvoiceRepo.GetAllInvoices(ctx, pagination)
 }
 
-func (s *InvoiceService) GetAllInvoices() ([]models.Invoice, error) {
-	return s.InvoiceRepo.GetAllInvoices()
+func (s *InvoiceService) StreamInvoices(ctx context.Context, filter dtos.CSVExportFilterDTO, fn func(models.Invoice) error) error {
+	return s.InvoiceRepo.StreamInvoices(ctx, filter, fn)
 }
 
-func (s *InvoiceService) SearchInvoiceByID(query string) ([]models.Invoice, error) {
-	return s.InvoiceRepo.SearchInvoiceByID(query)
+func (s *InvoiceService) SearchInvoiceByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Invoice, int64, error) {
+	return s.InvoiceRepo.SearchInvoiceByID(ctx, query, pagination)
 }
 
-func (s *InvoiceService) SearchInvoiceByCustomerPersonalId(query string) ([]models.Invoice, error) {
-	return s.InvoiceRepo.SearchInvoiceByCustomerPersonalId(query)
+func (s *InvoiceService) SearchInvoiceByCustomerPersonalId(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Invoice, int64, error) {
+	return s.InvoiceRepo.SearchInvoiceByCustomerPersonalId(ctx, query, pagination)
 }
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"totesbackend/config"
+	"totesbackend/models"
+	"totesbackend/repositories"
+	"totesbackend/storage"
+)
+
+var (
+	ErrItemImageTooLarge    = errors.New("the image is too large")
+	ErrItemImageUnsupported = errors.New("unsupported image type")
+	ErrItemImageLimit       = errors.New("the item already has the maximum number of images")
+)
+
+type ItemImageService struct {
+	Repo     repositories.ItemImageRepositoryInterface
+	ItemRepo repositories.ItemRepositoryInterface
+	Storage  storage.Storage
+	// MaxBytes es ITEM_IMAGE_MAX_BYTES
+	MaxBytes int
+}
+
+func NewItemImageService(repo repositories.ItemImageRepositoryInterface, itemRepo repositories.ItemRepositoryInterface,
+	store storage.Storage, maxBytes int) *ItemImageService {
+	return &ItemImageService{Repo: repo, ItemRepo: itemRepo, Storage: store, MaxBytes: maxBytes}
+}
+
+// UploadItemImage guarda una imagen del item. El tipo se detecta del contenido y debe ser uno de
+// config.ITEM_IMAGE_CONTENT_TYPES.
+func (s *ItemImageService) UploadItemImage(ctx context.Context, itemID int, body []byte) (*models.ItemImage, error) {
+	if len(body) > s.MaxBytes {
+		return nil, fmt.Errorf("%w: the maximum is %d bytes", ErrItemImageTooLarge, s.MaxBytes)
+	}
+	contentType := http.DetectContentType(body)
+	extension, ok := config.ITEM_IMAGE_CONTENT_TYPES[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrItemImageUnsupported, contentType)
+	}
+	if _, err := s.ItemRepo.GetItemByID(ctx, strconv.Itoa(itemID)); err != nil {
+		return nil, err
+	}
+	count, err := s.Repo.CountItemImages(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= config.ITEM_IMAGE_MAX_PER_ITEM {
+		return nil, fmt.Errorf("%w (%d)", ErrItemImageLimit, config.ITEM_IMAGE_MAX_PER_ITEM)
+	}
+
+	// la llave es aleatoria para que subir otra imagen nunca reemplace una que siga en caché
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("items/%d/%s%s", itemID, hex.EncodeToString(buf), extension)
+	if err := s.Storage.Put(ctx, key, body, contentType); err != nil {
+		return nil, err
+	}
+
+	image := &models.ItemImage{
+		ItemID:      itemID,
+		Key:         key,
+		URL:         s.Storage.URL(key),
+		ContentType: contentType,
+		Size:        len(body),
+	}
+	if err := s.Repo.CreateItemImage(ctx, image); err != nil {
+		s.deleteFile(ctx, key)
+		return nil, err
+	}
+	return image, nil
+}
+
+// DeleteItemImage borra la imagen del item y su archivo.
+func (s *ItemImageService) DeleteItemImage(ctx context.Context, itemID int, imageID int) error {
+	image, err := s.Repo.GetItemImage(ctx, itemID, imageID)
+	if err != nil {
+		return err
+	}
+	if err := s.Repo.DeleteItemImage(ctx, image); err != nil {
+		return err
+	}
+	s.deleteFile(ctx, image.Key)
+	return nil
+}
+
+// deleteFile borra un archivo que ya no está registrado; si falla solo queda huérfano en el
+// almacenamiento, así que se registra y no se devuelve el error.
+func (s *ItemImageService) deleteFile(ctx context.Context, key string) {
+	if err := s.Storage.Delete(ctx, key); err != nil {
+		log.Printf("could not delete item image %s: %v", key, err)
+	}
+}
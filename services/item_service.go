@@ -1,82 +1,278 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"strconv"
+	"strings"
 	"time"
+	"totesbackend/config"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
+
+	"gorm.io/gorm"
 )
 
+var ErrItemCodeTaken = errors.New("another item already uses that code")
+
 type ItemService struct {
-	Repo *repositories.ItemRepository
+	Repo         repositories.ItemRepositoryInterface
+	PriceHistory repositories.HistoricalItemPriceRepositoryInterface
+	Currencies   repositories.CurrencyRepositoryInterface
+	Tx           repositories.Transactor
+	Bus          *EventBus
+	Events       *EventStreamService
 }
 
-func NewItemService(repo *repositories.ItemRepository) *ItemService {
-	return &ItemService{Repo: repo}
+func NewItemService(repo repositories.ItemRepositoryInterface, priceHistory repositories.HistoricalItemPriceRepositoryInterface,
+	currencies repositories.CurrencyRepositoryInterface, tx repositories.Transactor) *ItemService {
+	return &ItemService{Repo: repo, PriceHistory: priceHistory, Currencies: currencies, Tx: tx}
 }
 
-func (s *ItemService) GetItemByID(id string) (*models.Item, error) {
-	return s.Repo.GetItemByID(id)
+func (s *ItemService) GetItemByID(ctx context.Context, id string) (*models.Item, error) {
+	return s.Repo.GetItemByID(ctx, id)
 }
 
-func (s *ItemService) GetAllItems() ([]models.Item, error) {
-	return s.Repo.GetAllItems()
+func (s *ItemService) GetAllItems(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Item, int64, error) {
+	return s.Repo.GetAllItems(ctx, pagination)
 }
 
-func (s *ItemService) SearchItemsByID(query string) ([]models.Item, error) {
-	return s.Repo.SearchItemsByID(query)
+func (s *ItemService) StreamItems(ctx context.Context, filter dtos.CSVExportFilterDTO, fn func(models.Item) error) error {
+	return s.Repo.StreamItems(ctx, filter, fn)
 }
 
-func (s *ItemService) SearchItemsByName(query string) ([]models.Item, error) {
-	return s.Repo.SearchItemsByName(query)
+// SearchItems busca query en el ID, el nombre, la descripción, el SKU y el código de barras de los
+// items, los más parecidos primero.
+func (s *ItemService) SearchItems(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Item, int64, error) {
+	return s.Repo.SearchItems(ctx, strings.TrimSpace(query), pagination)
 }
 
-func (s *ItemService) UpdateItemState(id string, state bool) (*models.Item, error) {
-	return s.Repo.UpdateItemState(id, state)
+func (s *ItemService) GetLowStockItems(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Item, int64, error) {
+	return s.Repo.GetLowStockItems(ctx, pagination)
 }
 
-func (s *ItemService) HasEnoughStock(id string, quantity int) (bool, error) {
-	return s.Repo.HasEnoughStock(id, quantity)
+// UpdateItemState activa o desactiva el item si sigue en version; si no, devuelve ErrVersionConflict.
+func (s *ItemService) UpdateItemState(ctx context.Context, id string, version int, state bool) (*models.Item, error) {
+	item, err := s.Repo.GetItemByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	item.ItemState = state
+	item.Version = version
+	updated, err := s.Repo.UpdateItem(ctx, item, newStockMovement(ctx, config.STOCK_MOVEMENT_ADJUSTMENT))
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrVersionConflict
+	}
+	return item, nil
+}
+
+func (s *ItemService) HasEnoughStock(ctx context.Context, id string, quantity int) (bool, error) {
+	return s.Repo.HasEnoughStock(ctx, id, quantity)
 }
 
-func (s *ItemService) UpdateItem(item *models.Item) error {
-	hisRepo := repositories.NewHistoricalItemPriceRepository(s.Repo.DB)
+// UpdateItem guarda el item si sigue en item.Version; si no, devuelve ErrVersionConflict.
+func (s *ItemService) UpdateItem(ctx context.Context, item *models.Item) error {
+	current, err := s.Repo.GetItemByID(ctx, strconv.Itoa(item.ID))
+	if err != nil {
+		return err
+	}
+	if err := s.checkCurrency(ctx, item); err != nil {
+		return err
+	}
+	if err := s.checkCodes(ctx, item); err != nil {
+		return err
+	}
 
-	SellingPriceChanged, err := s.Repo.UpdateItem(item)
+	updated, err := s.Repo.UpdateItem(ctx, item, newStockMovement(ctx, config.STOCK_MOVEMENT_ADJUSTMENT))
 	if err != nil {
 		return err
 	}
+	if !updated {
+		return ErrVersionConflict
+	}
 
-	if !SellingPriceChanged {
+	if (s.Bus != nil || s.Events != nil) && current.Stock != item.Stock {
+		s.Bus.Publish(ctx, StockChanged{dtos.StockChangedEventDTO{ItemID: item.ID, Change: item.Stock - current.Stock, Stock: &item.Stock, Reason: "update"}})
+		s.Events.PublishLowStock(ctx, s.Repo, map[int]int{item.ID: current.Stock})
+	}
+
+	if current.SellingPrice == item.SellingPrice && current.Currency == item.Currency {
 		return nil
 	}
-	oldItem, _ := s.Repo.GetItemByID(strconv.Itoa(item.ID))
 	historicalPrice := models.HistoricalItemPrice{
-		ItemID:  item.ID,
-		Price:   oldItem.SellingPrice,
-		AddedAt: time.Now(),
+		ItemID:   item.ID,
+		Price:    item.SellingPrice,
+		Currency: item.Currency,
+		AddedAt:  time.Now(),
 	}
 
-	if err := hisRepo.CreateHistoricalItemPrice(&historicalPrice); err != nil {
+	if err := s.PriceHistory.CreateHistoricalItemPrice(ctx, &historicalPrice); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *ItemService) CreateItem(item *models.Item) (*models.Item, error) {
-	hisRepo := repositories.NewHistoricalItemPriceRepository(s.Repo.DB)
-	item, err := s.Repo.CreateItem(item)
+// CreateItem crea el item; sin moneda sus precios quedan en la moneda base.
+func (s *ItemService) CreateItem(ctx context.Context, item *models.Item) (*models.Item, error) {
+	if err := s.checkCurrency(ctx, item); err != nil {
+		return nil, err
+	}
+	if err := s.checkCodes(ctx, item); err != nil {
+		return nil, err
+	}
+	item, err := s.Repo.CreateItem(ctx, item, newStockMovement(ctx, config.STOCK_MOVEMENT_ITEM_CREATED))
 
 	if err != nil {
 		return item, err
 	}
 
 	historicalPrice := models.HistoricalItemPrice{
-		ItemID:  item.ID,
-		Price:   item.SellingPrice,
-		AddedAt: time.Now(),
+		ItemID:   item.ID,
+		Price:    item.SellingPrice,
+		Currency: item.Currency,
+		AddedAt:  time.Now(),
 	}
-	hisRepo.CreateHistoricalItemPrice(&historicalPrice)
+	s.PriceHistory.CreateHistoricalItemPrice(ctx, &historicalPrice)
 
 	return item, err
 }
+
+// BatchItems aplica un lote de altas, cambios y bajas de items en una transacción. Como en el
+// endpoint individual, una baja solo desactiva el item.
+func (s *ItemService) BatchItems(ctx context.Context, operations []dtos.BatchOperationDTO[dtos.UpdateItemDTO]) (*dtos.BatchResponseDTO, error) {
+	return runBatch(ctx, s.Tx, operations, func(tx repositories.Tx, op dtos.BatchOperationDTO[dtos.UpdateItemDTO], result *dtos.BatchResultDTO) error {
+		txService := &ItemService{Repo: s.Repo.WithTx(tx), PriceHistory: s.PriceHistory.WithTx(tx), Currencies: s.Currencies}
+
+		switch op.Op {
+		case BATCH_OP_CREATE:
+			reorderLevel := config.LOW_STOCK_THRESHOLD
+			if op.Data.ReorderLevel != nil {
+				reorderLevel = *op.Data.ReorderLevel
+			}
+			item, err := txService.CreateItem(ctx, &models.Item{
+				Name:          op.Data.Name,
+				Description:   op.Data.Description,
+				SKU:           op.Data.SKU,
+				Barcode:       op.Data.Barcode,
+				Stock:         op.Data.Stock,
+				SellingPrice:  op.Data.SellingPrice,
+				PurchasePrice: op.Data.PurchasePrice,
+				Currency:      op.Data.Currency,
+				ItemState:     op.Data.ItemState,
+				ReorderLevel:  reorderLevel,
+				ItemTypeID:    op.Data.ItemTypeID,
+			})
+			if err != nil {
+				return err
+			}
+			result.ID = item.ID
+			result.Status = BATCH_STATUS_CREATED
+			result.Data = item
+
+		case BATCH_OP_UPDATE:
+			item, err := txService.GetItemByID(ctx, strconv.Itoa(op.ID))
+			if err != nil {
+				return err
+			}
+			before := *item
+			item.Name = op.Data.Name
+			item.Description = op.Data.Description
+			if op.Data.SKU != nil {
+				item.SKU = op.Data.SKU
+			}
+			if op.Data.Barcode != nil {
+				item.Barcode = op.Data.Barcode
+			}
+			item.Stock = op.Data.Stock
+			item.SellingPrice = op.Data.SellingPrice
+			item.PurchasePrice = op.Data.PurchasePrice
+			if op.Data.Currency != "" {
+				item.Currency = op.Data.Currency
+			}
+			item.ItemState = op.Data.ItemState
+			item.ItemTypeID = op.Data.ItemTypeID
+			if op.Data.ReorderLevel != nil {
+				item.ReorderLevel = *op.Data.ReorderLevel
+			}
+			// en lotes la versión es opcional; sin ella se usa la actual
+			if op.Data.Version != nil {
+				item.Version = *op.Data.Version
+			}
+			if err := txService.UpdateItem(ctx, item); err != nil {
+				return err
+			}
+			result.Status = BATCH_STATUS_UPDATED
+			result.Data = item
+			result.Before = before
+			result.After = *item
+
+		case BATCH_OP_DELETE:
+			before, err := txService.GetItemByID(ctx, strconv.Itoa(op.ID))
+			if err != nil {
+				return err
+			}
+			item, err := txService.UpdateItemState(ctx, strconv.Itoa(op.ID), before.Version, false)
+			if err != nil {
+				return err
+			}
+			result.Status = BATCH_STATUS_DELETED
+			result.Before = *before
+			result.After = *item
+		}
+		return nil
+	})
+}
+
+// checkCurrency normaliza la moneda de los precios del item y verifica que exista.
+func (s *ItemService) checkCurrency(ctx context.Context, item *models.Item) error {
+	item.Currency = currencyCode(item.Currency)
+	if _, err := s.Currencies.GetCurrencyByCode(ctx, item.Currency); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: %s", ErrUnknownCurrency, item.Currency)
+		}
+		return err
+	}
+	return nil
+}
+
+// checkCodes normaliza el SKU y el código de barras del item, "" es no tenerlos, y verifica que
+// ningún otro item los use.
+func (s *ItemService) checkCodes(ctx context.Context, item *models.Item) error {
+	item.SKU = itemCode(item.SKU)
+	item.Barcode = itemCode(item.Barcode)
+	if item.SKU != nil {
+		other, err := s.Repo.GetItemBySKU(ctx, *item.SKU)
+		if err != nil {
+			return err
+		}
+		if other != nil && other.ID != item.ID {
+			return fmt.Errorf("%w: SKU %s belongs to item %d", ErrItemCodeTaken, *item.SKU, other.ID)
+		}
+	}
+	if item.Barcode != nil {
+		other, err := s.Repo.GetItemByBarcode(ctx, *item.Barcode)
+		if err != nil {
+			return err
+		}
+		if other != nil && other.ID != item.ID {
+			return fmt.Errorf("%w: barcode %s belongs to item %d", ErrItemCodeTaken, *item.Barcode, other.ID)
+		}
+	}
+	return nil
+}
+
+func itemCode(code *string) *string {
+	if code == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*code)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
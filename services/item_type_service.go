@@ -1,22 +1,87 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
 
+var (
+	ErrInvalidItemTypeName = errors.New("item type name cannot be blank")
+	ErrItemTypeNameTaken   = errors.New("an item type with that name already exists")
+	ErrItemTypeInUse       = errors.New("item type is in use")
+)
+
 type ItemTypeService struct {
-	Repo *repositories.ItemTypeRepository
+	Repo repositories.ItemTypeRepositoryInterface
 }
 
-func NewItemTypeService(repo *repositories.ItemTypeRepository) *ItemTypeService {
+func NewItemTypeService(repo repositories.ItemTypeRepositoryInterface) *ItemTypeService {
 	return &ItemTypeService{Repo: repo}
 }
 
-func (s *ItemTypeService) GetAllItemTypes() ([]models.ItemType, error) {
-	return s.Repo.GetAllItemTypes()
+func (s *ItemTypeService) GetAllItemTypes(ctx context.Context, pagination dtos.PaginationDTO) ([]models.ItemType, int64, error) {
+	return s.Repo.GetAllItemTypes(ctx, pagination)
+}
+
+func (s *ItemTypeService) GetItemTypeByID(ctx context.Context, id string) (*models.ItemType, error) {
+	return s.Repo.GetItemTypeByID(ctx, id)
+}
+
+func (s *ItemTypeService) CreateItemType(ctx context.Context, dto dtos.ItemTypeDTO) (*models.ItemType, error) {
+	itemType := &models.ItemType{Name: strings.TrimSpace(dto.Name)}
+	if err := s.checkNameAvailable(ctx, itemType.Name, 0); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.CreateItemType(ctx, itemType); err != nil {
+		return nil, err
+	}
+	return itemType, nil
+}
+
+func (s *ItemTypeService) UpdateItemType(ctx context.Context, id int, dto dtos.ItemTypeDTO) (*models.ItemType, error) {
+	itemType, err := s.Repo.GetItemTypeByID(ctx, strconv.Itoa(id))
+	if err != nil {
+		return nil, err
+	}
+	itemType.Name = strings.TrimSpace(dto.Name)
+	if err := s.checkNameAvailable(ctx, itemType.Name, id); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.UpdateItemType(ctx, itemType); err != nil {
+		return nil, err
+	}
+	return itemType, nil
+}
+
+// DeleteItemType solo borra tipos que ningún ítem usa, incluidos los ítems desactivados.
+func (s *ItemTypeService) DeleteItemType(ctx context.Context, id int) error {
+	usage, err := s.Repo.CountItemsWithType(ctx, id)
+	if err != nil {
+		return err
+	}
+	if usage > 0 {
+		return fmt.Errorf("%w by %d items", ErrItemTypeInUse, usage)
+	}
+	return s.Repo.DeleteItemType(ctx, id)
 }
 
-func (s *ItemTypeService) GetItemTypeByID(id string) (*models.ItemType, error) {
-	return s.Repo.GetItemTypeByID(id)
+// checkNameAvailable evita nombres repetidos sin distinguir mayúsculas; exceptID es el tipo que se edita.
+func (s *ItemTypeService) checkNameAvailable(ctx context.Context, name string, exceptID int) error {
+	if name == "" {
+		return ErrInvalidItemTypeName
+	}
+	existing, err := s.Repo.GetItemTypeByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != exceptID {
+		return ErrItemTypeNameTaken
+	}
+	return nil
 }
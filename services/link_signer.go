@@ -0,0 +1,77 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"totesbackend/config"
+)
+
+var ErrInvalidLinkToken = errors.New("invalid or expired link")
+
+// LinkSigner firma los enlaces públicos que van en los correos (darse de baja, cancelar una cita).
+// El token lleva los datos en claro y una firma HMAC que incluye el propósito, así un token de un
+// tipo de enlace no sirve para otro.
+type LinkSigner struct {
+	key       []byte
+	publicURL string
+}
+
+func NewLinkSigner(cfg config.NotificationConfig) *LinkSigner {
+	key := []byte(cfg.SigningKey)
+	if len(key) == 0 {
+		// solo ocurre con EMAIL_PROVIDER=log: los enlaces dejan de valer al reiniciar
+		key = make([]byte, 32)
+		_, _ = rand.Read(key)
+		log.Printf("NOTIFICATION_SIGNING_KEY is not set; links in emails will only be valid until the server restarts")
+	}
+	return &LinkSigner{key: key, publicURL: cfg.PublicURL}
+}
+
+// URL arma el enlace público a path con el token firmado. Con expiresAt cero el enlace no vence.
+func (s *LinkSigner) URL(path, purpose string, expiresAt time.Time, fields ...string) string {
+	var expires int64
+	if !expiresAt.IsZero() {
+		expires = expiresAt.Unix()
+	}
+	payload := strings.Join(append([]string{strconv.FormatInt(expires, 10)}, fields...), "|")
+	token := base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(s.sign(purpose, payload))
+	return s.publicURL + path + "?token=" + url.QueryEscape(token)
+}
+
+// Verify comprueba la firma y el vencimiento del token y devuelve sus campos.
+func (s *LinkSigner) Verify(purpose, token string) ([]string, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidLinkToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, ErrInvalidLinkToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, s.sign(purpose, string(payload))) {
+		return nil, ErrInvalidLinkToken
+	}
+
+	parts := strings.Split(string(payload), "|")
+	expires, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || (expires != 0 && time.Now().Unix() > expires) {
+		return nil, ErrInvalidLinkToken
+	}
+	return parts[1:], nil
+}
+
+func (s *LinkSigner) sign(purpose, payload string) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(purpose + "\n" + payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+	"totesbackend/models"
+)
+
+const (
+	LOG_SINK_STDOUT = "stdout"
+	LOG_SINK_SYSLOG = "syslog"
+	LOG_SINK_HTTP   = "http"
+)
+
+// LogSink recibe una copia de los logs que se guardan en la base de datos para enviarlos
+// a un sistema externo (syslog, ELK, Cloud Logging, ...).
+type LogSink interface {
+	WriteLogs(userLogs []models.UserLog) error
+	Close() error
+}
+
+// NewLogSink crea el sink indicado. Un kind vacío significa que no hay sink configurado.
+func NewLogSink(kind, network, address, token string) (LogSink, error) {
+	switch kind {
+	case "":
+		return nil, nil
+	case LOG_SINK_STDOUT:
+		return &stdoutLogSink{encoder: json.NewEncoder(os.Stdout)}, nil
+	case LOG_SINK_SYSLOG:
+		if address == "" {
+			return nil, errors.New("LOG_SINK_ADDRESS is required for the syslog sink")
+		}
+		if network == "" {
+			network = "udp"
+		}
+		hostname, _ := os.Hostname()
+		return &syslogLogSink{network: network, address: address, hostname: hostname}, nil
+	case LOG_SINK_HTTP:
+		if address == "" {
+			return nil, errors.New("LOG_SINK_ADDRESS is required for the http sink")
+		}
+		return &httpLogSink{url: address, token: token, client: &http.Client{Timeout: 5 * time.Second}}, nil
+	default:
+		return nil, errors.New("unknown log sink '" + kind + "'")
+	}
+}
+
+// stdoutLogSink escribe una línea JSON por log, el formato que esperan los agentes que
+// recolectan la salida de los contenedores.
+type stdoutLogSink struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+func (s *stdoutLogSink) WriteLogs(userLogs []models.UserLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, userLog := range userLogs {
+		if err := s.encoder.Encode(userLog); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *stdoutLogSink) Close() error {
+	return nil
+}
+
+// syslogLogSink envía mensajes RFC 5424 por UDP o TCP y reconecta si la conexión se cae.
+type syslogLogSink struct {
+	mu       sync.Mutex
+	network  string
+	address  string
+	hostname string
+	conn     net.Conn
+}
+
+func (s *syslogLogSink) WriteLogs(userLogs []models.UserLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, userLog := range userLogs {
+		// facility user (1), severity informational (6)
+		message := fmt.Sprintf("<14>1 %s %s totesbackend - - - [%s] %s: %s\n",
+			userLog.DateTime.Format(time.RFC3339), s.hostname, userLog.UserEmail, userLog.Endpoint, userLog.Log)
+		if err := s.write(message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *syslogLogSink) write(message string) error {
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			conn, err := net.DialTimeout(s.network, s.address, 5*time.Second)
+			if err != nil {
+				return err
+			}
+			s.conn = conn
+		}
+		if _, err := s.conn.Write([]byte(message)); err == nil {
+			return nil
+		}
+		s.conn.Close()
+		s.conn = nil
+	}
+	return errors.New("could not write to syslog at " + s.address)
+}
+
+func (s *syslogLogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// httpLogSink publica los logs como un arreglo JSON, p. ej. hacia un input http de Logstash.
+type httpLogSink struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func (s *httpLogSink) WriteLogs(userLogs []models.UserLog) error {
+	body, err := json.Marshal(userLogs)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("log sink responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *httpLogSink) Close() error {
+	return nil
+}
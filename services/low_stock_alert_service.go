@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"time"
+	"totesbackend/config"
+	"totesbackend/models"
+	"totesbackend/notifications"
+	"totesbackend/repositories"
+)
+
+// LowStockAlertService avisa por correo a los encargados de inventario (los usuarios con
+// PERMISSION_RECEIVE_LOW_STOCK_ALERTS) de los items que llegaron a su nivel de reorden. Se avisa una
+// sola vez por item hasta que su stock vuelva a superar el nivel.
+type LowStockAlertService struct {
+	Repo     repositories.LowStockAlertRepositoryInterface
+	AuthRepo repositories.AuthorizationRepositoryInterface
+	Users    repositories.UserRepositoryInterface
+	Email    *EmailService
+}
+
+func NewLowStockAlertService(repo repositories.LowStockAlertRepositoryInterface, authRepo repositories.AuthorizationRepositoryInterface,
+	users repositories.UserRepositoryInterface, email *EmailService) *LowStockAlertService {
+	return &LowStockAlertService{Repo: repo, AuthRepo: authRepo, Users: users, Email: email}
+}
+
+// SendLowStockAlerts encola un resumen con los items que bajaron desde la corrida anterior y
+// devuelve cuántos items entraron en el aviso y cuántos correos se encolaron.
+func (s *LowStockAlertService) SendLowStockAlerts(ctx context.Context, now time.Time) (int, int, error) {
+	if _, err := s.Repo.ClearRecoveredAlerts(ctx); err != nil {
+		return 0, 0, err
+	}
+	items, err := s.Repo.GetItemsPendingAlert(ctx)
+	if err != nil || len(items) == 0 {
+		return 0, 0, err
+	}
+
+	data := notifications.LowStockAlertData{Items: make([]notifications.LowStockLineData, len(items))}
+	alerts := make([]models.LowStockAlert, len(items))
+	for i, item := range items {
+		data.Items[i] = notifications.LowStockLineData{Name: item.Name, Stock: item.Stock, ReorderLevel: item.ReorderLevel}
+		alerts[i] = models.LowStockAlert{ItemID: item.ID, Stock: item.Stock, ReorderLevel: item.ReorderLevel, AlertedAt: now}
+	}
+
+	userIDs, err := s.AuthRepo.GetUserIDsWithPermission(ctx, config.PERMISSION_RECEIVE_LOW_STOCK_ALERTS)
+	if err != nil {
+		return 0, 0, err
+	}
+	queued := 0
+	for _, userID := range userIDs {
+		user, err := s.Users.GetUserByID(ctx, strconv.Itoa(userID))
+		if err != nil {
+			return 0, queued, err
+		}
+		recipient := EmailRecipient{Type: NOTIFICATION_RECIPIENT_USER, ID: user.ID, Email: user.Email}
+		// si un correo no se pudo encolar los items no se marcan y el aviso se repite en la próxima corrida
+		message, err := s.Email.Notify(ctx, recipient, NOTIFICATION_EVENT_LOW_STOCK_ALERT, notifications.TEMPLATE_LOW_STOCK_ALERT, data)
+		if err != nil {
+			return 0, queued, err
+		}
+		if message != nil {
+			queued++
+		}
+	}
+
+	// sin encargados los items también se marcan: el listado /items/lowStock sigue mostrándolos
+	if err := s.Repo.CreateAlerts(ctx, alerts); err != nil {
+		return 0, queued, err
+	}
+	return len(items), queued, nil
+}
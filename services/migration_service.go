@@ -0,0 +1,21 @@
+package services
+
+import (
+	"context"
+	"totesbackend/database"
+	"totesbackend/dtos"
+
+	"gorm.io/gorm"
+)
+
+type MigrationService struct {
+	DB *gorm.DB
+}
+
+func NewMigrationService(db *gorm.DB) *MigrationService {
+	return &MigrationService{DB: db}
+}
+
+func (s *MigrationService) GetMigrationStatus(ctx context.Context) ([]dtos.MigrationStatusDTO, error) {
+	return database.GetMigrationStatus(s.DB.WithContext(ctx))
+}
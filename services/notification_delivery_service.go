@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/notifications"
+	"totesbackend/repositories"
+)
+
+const (
+	NOTIFICATION_DELIVERY_SENT   = "sent"
+	NOTIFICATION_DELIVERY_FAILED = "failed"
+)
+
+var (
+	ErrInvalidDeliveryFilter    = errors.New("invalid delivery filter")
+	ErrNotificationNotRetryable = errors.New("only notifications that exhausted their attempts can be retried")
+)
+
+// NotificationDeliveryService consulta el registro de intentos de entrega y permite reintentar las
+// notificaciones que fallaron, para soporte.
+type NotificationDeliveryService struct {
+	Repo  repositories.NotificationDeliveryRepositoryInterface
+	Email *EmailService
+	Texts *TextMessageService
+}
+
+func NewNotificationDeliveryService(repo repositories.NotificationDeliveryRepositoryInterface, email *EmailService) *NotificationDeliveryService {
+	return &NotificationDeliveryService{Repo: repo, Email: email}
+}
+
+func (s *NotificationDeliveryService) SearchDeliveries(ctx context.Context, filter dtos.NotificationDeliveryFilterDTO) (*dtos.PageDTO[models.NotificationDelivery], error) {
+	if filter.Channel != "" && filter.Channel != NOTIFICATION_CHANNEL_EMAIL && filter.Channel != notifications.TEXT_CHANNEL_SMS &&
+		filter.Channel != notifications.TEXT_CHANNEL_WHATSAPP {
+		return nil, fmt.Errorf("%w: unknown channel '%s'", ErrInvalidDeliveryFilter, filter.Channel)
+	}
+	if filter.Status != "" && filter.Status != NOTIFICATION_DELIVERY_SENT && filter.Status != NOTIFICATION_DELIVERY_FAILED {
+		return nil, fmt.Errorf("%w: unknown status '%s'", ErrInvalidDeliveryFilter, filter.Status)
+	}
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, fmt.Errorf("%w: 'to' date must be after 'from' date", ErrInvalidDeliveryFilter)
+	}
+
+	deliveries, total, err := s.Repo.SearchDeliveries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return dtos.NewPageDTO(deliveries, filter.PaginationDTO, total), nil
+}
+
+// RetryDelivery vuelve a encolar el mensaje del intento indicado. Solo se aceptan mensajes que ya
+// agotaron sus intentos; los que aún tienen reintentos programados siguen su curso.
+func (s *NotificationDeliveryService) RetryDelivery(ctx context.Context, deliveryID int) (*models.NotificationDelivery, error) {
+	delivery, err := s.Repo.GetDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	var requeued bool
+	switch delivery.Channel {
+	case NOTIFICATION_CHANNEL_EMAIL:
+		requeued, err = s.Email.Requeue(ctx, delivery.MessageID)
+	case notifications.TEXT_CHANNEL_SMS, notifications.TEXT_CHANNEL_WHATSAPP:
+		requeued, err = s.Texts.Requeue(ctx, delivery.MessageID)
+	default:
+		return nil, ErrNotificationNotRetryable
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !requeued {
+		return nil, ErrNotificationNotRetryable
+	}
+	return delivery, nil
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+)
+
+const (
+	NOTIFICATION_RECIPIENT_USER     = "user"
+	NOTIFICATION_RECIPIENT_CUSTOMER = "customer"
+)
+
+const (
+	NOTIFICATION_CHANNEL_EMAIL = "email"
+	// SMS o WhatsApp, según Customer.TextChannel
+	NOTIFICATION_CHANNEL_SMS    = "sms"
+	NOTIFICATION_CHANNEL_IN_APP = "in_app"
+)
+
+const (
+	NOTIFICATION_EVENT_APPOINTMENT_CONFIRMATION = "appointment.confirmation"
+	NOTIFICATION_EVENT_APPOINTMENT_REMINDER     = "appointment.reminder"
+	NOTIFICATION_EVENT_INVOICE_ISSUED           = "invoice.issued"
+	NOTIFICATION_EVENT_PAYMENT_REMINDER         = "invoice.payment_reminder"
+	NOTIFICATION_EVENT_PASSWORD_RESET           = "password.reset"
+	NOTIFICATION_EVENT_LOW_STOCK_ALERT          = "item.low_stock_alert"
+)
+
+// notificationEvent describe a quién va un evento, por qué canales y si está activo por defecto.
+type notificationEvent struct {
+	description string
+	recipient   string
+	channels    map[string]bool
+	// los obligatorios se envían siempre y no llevan enlace para darse de baja
+	mandatory bool
+}
+
+// notificationEvents es el catálogo de lo que se puede notificar; los nuevos envíos se agregan aquí.
+var notificationEvents = map[string]notificationEvent{
+	NOTIFICATION_EVENT_APPOINTMENT_CONFIRMATION: {
+		description: "Appointment confirmation",
+		recipient:   NOTIFICATION_RECIPIENT_CUSTOMER,
+		channels:    map[string]bool{NOTIFICATION_CHANNEL_EMAIL: true, NOTIFICATION_CHANNEL_SMS: true},
+	},
+	NOTIFICATION_EVENT_APPOINTMENT_REMINDER: {
+		description: "Appointment reminder",
+		recipient:   NOTIFICATION_RECIPIENT_CUSTOMER,
+		channels:    map[string]bool{NOTIFICATION_CHANNEL_EMAIL: true, NOTIFICATION_CHANNEL_SMS: true},
+	},
+	NOTIFICATION_EVENT_INVOICE_ISSUED: {
+		description: "Invoice issued",
+		recipient:   NOTIFICATION_RECIPIENT_CUSTOMER,
+		channels:    map[string]bool{NOTIFICATION_CHANNEL_EMAIL: true},
+	},
+	NOTIFICATION_EVENT_PAYMENT_REMINDER: {
+		description: "Payment reminder",
+		recipient:   NOTIFICATION_RECIPIENT_CUSTOMER,
+		channels:    map[string]bool{NOTIFICATION_CHANNEL_EMAIL: true},
+	},
+	NOTIFICATION_EVENT_PASSWORD_RESET: {
+		description: "Password reset",
+		recipient:   NOTIFICATION_RECIPIENT_USER,
+		channels:    map[string]bool{NOTIFICATION_CHANNEL_EMAIL: true},
+		mandatory:   true,
+	},
+	NOTIFICATION_EVENT_LOW_STOCK_ALERT: {
+		description: "Low stock summary",
+		recipient:   NOTIFICATION_RECIPIENT_USER,
+		channels:    map[string]bool{NOTIFICATION_CHANNEL_EMAIL: true},
+	},
+	// los eventos de /events llegan a la bandeja de quien tiene el permiso del listado
+	STREAM_EVENT_APPOINTMENT_CREATED: {
+		description: "New appointment",
+		recipient:   NOTIFICATION_RECIPIENT_USER,
+		channels:    map[string]bool{NOTIFICATION_CHANNEL_IN_APP: true},
+	},
+	STREAM_EVENT_LOW_STOCK: {
+		description: "Item low on stock",
+		recipient:   NOTIFICATION_RECIPIENT_USER,
+		channels:    map[string]bool{NOTIFICATION_CHANNEL_IN_APP: true},
+	},
+	STREAM_EVENT_COMMENT_CREATED: {
+		description: "New comment",
+		recipient:   NOTIFICATION_RECIPIENT_USER,
+		channels:    map[string]bool{NOTIFICATION_CHANNEL_IN_APP: true},
+	},
+}
+
+const unsubscribeLinkPurpose = "unsubscribe"
+
+var (
+	ErrInvalidNotificationPreference = errors.New("invalid notification preference")
+	ErrInvalidUnsubscribeToken       = errors.New("invalid unsubscribe token")
+)
+
+// NotificationPreferenceService decide si un destinatario quiere recibir un evento por un canal.
+// Todo envío a usuarios o clientes debe pasar por Allows.
+type NotificationPreferenceService struct {
+	Repo  repositories.NotificationPreferenceRepositoryInterface
+	Links *LinkSigner
+}
+
+func NewNotificationPreferenceService(repo repositories.NotificationPreferenceRepositoryInterface, links *LinkSigner) *NotificationPreferenceService {
+	return &NotificationPreferenceService{Repo: repo, Links: links}
+}
+
+// GetPreferences lista todas las preferencias que aplican al tipo de destinatario, con las que
+// nunca se cambiaron en su valor por defecto.
+func (s *NotificationPreferenceService) GetPreferences(ctx context.Context, recipientType string, recipientID int) ([]dtos.NotificationPreferenceDTO, error) {
+	stored, err := s.Repo.GetPreferences(ctx, recipientType, recipientID)
+	if err != nil {
+		return nil, err
+	}
+	saved := make(map[string]bool, len(stored))
+	for _, preference := range stored {
+		saved[preference.EventType+"|"+preference.Channel] = preference.Enabled
+	}
+
+	var result []dtos.NotificationPreferenceDTO
+	for _, eventType := range slices.Sorted(maps.Keys(notificationEvents)) {
+		event := notificationEvents[eventType]
+		if event.recipient != recipientType {
+			continue
+		}
+		for _, channel := range slices.Sorted(maps.Keys(event.channels)) {
+			enabled, ok := saved[eventType+"|"+channel]
+			if !ok || event.mandatory {
+				enabled = event.channels[channel]
+			}
+			result = append(result, dtos.NotificationPreferenceDTO{
+				EventType:   eventType,
+				Description: event.description,
+				Channel:     channel,
+				Enabled:     enabled,
+				Mandatory:   event.mandatory,
+				IsDefault:   !ok,
+			})
+		}
+	}
+	return result, nil
+}
+
+func (s *NotificationPreferenceService) UpdatePreferences(ctx context.Context, recipientType string, recipientID int, dto dtos.UpdateNotificationPreferencesDTO) ([]dtos.NotificationPreferenceDTO, error) {
+	preferences := make([]models.NotificationPreference, 0, len(dto.Preferences))
+	for _, update := range dto.Preferences {
+		event, ok := notificationEvents[update.EventType]
+		if !ok || event.recipient != recipientType {
+			return nil, fmt.Errorf("%w: unknown event type '%s'", ErrInvalidNotificationPreference, update.EventType)
+		}
+		if _, ok := event.channels[update.Channel]; !ok {
+			return nil, fmt.Errorf("%w: event '%s' is not sent by %s", ErrInvalidNotificationPreference, update.EventType, update.Channel)
+		}
+		if event.mandatory && !*update.Enabled {
+			return nil, fmt.Errorf("%w: '%s' notifications cannot be disabled", ErrInvalidNotificationPreference, update.EventType)
+		}
+		preferences = append(preferences, models.NotificationPreference{
+			RecipientType: recipientType,
+			RecipientID:   recipientID,
+			EventType:     update.EventType,
+			Channel:       update.Channel,
+			Enabled:       *update.Enabled,
+		})
+	}
+
+	if err := s.Repo.SavePreferences(ctx, preferences); err != nil {
+		return nil, err
+	}
+	return s.GetPreferences(ctx, recipientType, recipientID)
+}
+
+// Allows indica si se debe enviar el evento. Si no se puede leer la preferencia no se envía:
+// es preferible perder un aviso a escribirle a quien se dio de baja.
+func (s *NotificationPreferenceService) Allows(ctx context.Context, recipientType string, recipientID int, eventType, channel string) bool {
+	event, ok := notificationEvents[eventType]
+	if !ok || event.recipient != recipientType {
+		return false
+	}
+	enabled, ok := event.channels[channel]
+	if !ok {
+		return false
+	}
+	if event.mandatory {
+		return true
+	}
+
+	preference, err := s.Repo.GetPreference(ctx, recipientType, recipientID, eventType, channel)
+	if err != nil {
+		log.Printf("error reading notification preference of %s %d for %s: %v", recipientType, recipientID, eventType, err)
+		return false
+	}
+	if preference != nil {
+		enabled = preference.Enabled
+	}
+	return enabled
+}
+
+// FilterRecipients es Allows para varios destinatarios a la vez: devuelve los que quieren recibir
+// el evento por ese canal.
+func (s *NotificationPreferenceService) FilterRecipients(ctx context.Context, recipientType string, recipientIDs []int, eventType, channel string) ([]int, error) {
+	event, ok := notificationEvents[eventType]
+	if !ok || event.recipient != recipientType {
+		return nil, nil
+	}
+	enabled, ok := event.channels[channel]
+	if !ok {
+		return nil, nil
+	}
+	if event.mandatory || len(recipientIDs) == 0 {
+		return recipientIDs, nil
+	}
+
+	stored, err := s.Repo.GetPreferencesForEvent(ctx, recipientType, recipientIDs, eventType, channel)
+	if err != nil {
+		return nil, err
+	}
+	changed := make(map[int]bool, len(stored))
+	for _, preference := range stored {
+		changed[preference.RecipientID] = preference.Enabled
+	}
+
+	var result []int
+	for _, id := range recipientIDs {
+		wants, ok := changed[id]
+		if !ok {
+			wants = enabled
+		}
+		if wants {
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
+
+// UnsubscribeURL arma el enlace firmado para dejar de recibir el evento por ese canal. Los eventos
+// obligatorios no tienen enlace.
+func (s *NotificationPreferenceService) UnsubscribeURL(recipientType string, recipientID int, eventType, channel string) string {
+	if event, ok := notificationEvents[eventType]; !ok || event.mandatory {
+		return ""
+	}
+	return s.Links.URL("/notification-preferences/unsubscribe", unsubscribeLinkPurpose, time.Time{},
+		recipientType, strconv.Itoa(recipientID), eventType, channel)
+}
+
+// Unsubscribe desactiva la preferencia indicada en un token generado por UnsubscribeURL.
+func (s *NotificationPreferenceService) Unsubscribe(ctx context.Context, token string) (*models.NotificationPreference, error) {
+	parts, err := s.Links.Verify(unsubscribeLinkPurpose, token)
+	if err != nil || len(parts) != 4 {
+		return nil, ErrInvalidUnsubscribeToken
+	}
+	recipientID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, ErrInvalidUnsubscribeToken
+	}
+	event, ok := notificationEvents[parts[2]]
+	if !ok || event.mandatory || event.recipient != parts[0] {
+		return nil, ErrInvalidUnsubscribeToken
+	}
+	if _, ok := event.channels[parts[3]]; !ok {
+		return nil, ErrInvalidUnsubscribeToken
+	}
+
+	preference := models.NotificationPreference{
+		RecipientType: parts[0],
+		RecipientID:   recipientID,
+		EventType:     parts[2],
+		Channel:       parts[3],
+		Enabled:       false,
+	}
+	if err := s.Repo.SavePreferences(ctx, []models.NotificationPreference{preference}); err != nil {
+		return nil, err
+	}
+	return &preference, nil
+}
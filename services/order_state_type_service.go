@@ -1,22 +1,24 @@
 package services
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
 
 type OrderStateTypeService struct {
-	Repo *repositories.OrderStateTypeRepository
+	Repo repositories.OrderStateTypeRepositoryInterface
 }
 
-func NewOrderStateTypeService(repo *repositories.OrderStateTypeRepository) *OrderStateTypeService {
+func NewOrderStateTypeService(repo repositories.OrderStateTypeRepositoryInterface) *OrderStateTypeService {
 	return &OrderStateTypeService{Repo: repo}
 }
 
-func (s *OrderStateTypeService) GetAllOrderStateTypes() ([]models.OrderStateType, error) {
-	return s.Repo.GetAllOrderStateTypes()
+func (s *OrderStateTypeService) GetAllOrderStateTypes(ctx context.Context, pagination dtos.PaginationDTO) ([]models.OrderStateType, int64, error) {
+	return s.Repo.GetAllOrderStateTypes(ctx, pagination)
 }
 
-func (s *OrderStateTypeService) GetOrderStateTypeByID(id string) (*models.OrderStateType, error) {
-	return s.Repo.GetOrderStateTypeByID(id)
+func (s *OrderStateTypeService) GetOrderStateTypeByID(ctx context.Context, id string) (*models.OrderStateType, error) {
+	return s.Repo.GetOrderStateTypeByID(ctx, id)
 }
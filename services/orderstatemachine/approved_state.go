@@ -50,7 +50,7 @@ func NewApprovedState(context *OrderStateMachine) *ApprovedState {
 	}
 
 	// Crear la factura usando el repositorio
-	invoice, err := invoiceRepo.CreateInvoiceWithoutStockReduction(dto, po.SubTotal, po.Total)
+	invoice, err := invoiceRepo.CreateInvoiceWithoutStockReduction(context.Ctx, dto, po.SubTotal, po.Total)
 	if err != nil {
 		invoice = nil
 	}
@@ -3,6 +3,7 @@ package orderstatemachine
 import (
 	"errors"
 	"strconv"
+	"totesbackend/config"
 	"totesbackend/models"
 )
 
@@ -41,19 +42,11 @@ func (s *InTransitState) GetDescription() string {
 }
 
 func (s *InTransitState) changeInTransitToCancelled(stateID string) error {
-	for _, item := range s.context.PurchaseOrder.Items {
-		itemIDStr := strconv.Itoa(item.ItemID)
-		if err := s.context.ItemRepo.ReturnItemsToInventory(itemIDStr, item.Amount); err != nil {
-			return errors.New("failed to return stock for item with ID: " + itemIDStr + " - " + err.Error())
-		}
+	movement := s.context.Movement
+	movement.Reason = config.STOCK_MOVEMENT_PURCHASE_ORDER_RETURN
+	if err := s.context.changeStateWithStock(stateID, false, movement); err != nil {
+		return err
 	}
-
-	orderIDStr := strconv.Itoa(s.context.PurchaseOrder.ID)
-	newPurchaseOrder, err := s.context.PurchaseOrderRepo.ChangePurchaseOrderState(orderIDStr, stateID)
-	if err != nil {
-		return errors.New("failed to change purchase order state with ID: " + orderIDStr + " - " + err.Error())
-	}
-	s.context.PurchaseOrder = newPurchaseOrder
 	s.context.CurrentState = NewCancelledState(s.context)
 	return nil
 }
@@ -62,7 +55,7 @@ func (s *InTransitState) changeInTransitToAccepted(stateID string) error {
 	// TODO: Call invoice repo here
 
 	orderIDStr := strconv.Itoa(s.context.PurchaseOrder.ID)
-	newPurchaseOrder, err := s.context.PurchaseOrderRepo.ChangePurchaseOrderState(orderIDStr, stateID)
+	newPurchaseOrder, err := s.context.PurchaseOrderRepo.ChangePurchaseOrderState(s.context.Ctx, orderIDStr, stateID)
 	if err != nil {
 		return errors.New("failed to accept purchase order with ID: " + orderIDStr + " - " + err.Error())
 	}
@@ -3,6 +3,7 @@ package orderstatemachine
 import (
 	"errors"
 	"strconv"
+	"totesbackend/config"
 	"totesbackend/models"
 )
 
@@ -42,32 +43,14 @@ func (s *IssuedState) GetDescription() string {
 	return s.state.Description
 }
 
+// changeIssuedToInTransit descuenta el stock de la orden; si algún item no alcanza la orden sigue
+// emitida y se devuelve un *StockShortageError.
 func (s *IssuedState) changeIssuedToInTransit(stateID string) error {
-
-	for _, item := range s.context.PurchaseOrder.Items {
-		itemIDStr := strconv.Itoa(item.ItemID)
-		hasStock, err := s.context.ItemRepo.HasEnoughStock(itemIDStr, item.Amount)
-		if err != nil {
-			return errors.New("error checking stock for item with ID: " + itemIDStr + " - " + err.Error())
-		}
-		if !hasStock {
-			return errors.New("insufficient stock for item with ID: " + itemIDStr)
-		}
-	}
-
-	for _, item := range s.context.PurchaseOrder.Items {
-		itemIDStr := strconv.Itoa(item.ItemID)
-		if err := s.context.ItemRepo.SubtractItemsFromInventory(itemIDStr, item.Amount); err != nil {
-			return errors.New("error subtracting stock for item with ID: " + itemIDStr + " - " + err.Error())
-		}
-	}
-
-	orderIDStr := strconv.Itoa(s.context.PurchaseOrder.ID)
-	newPurchaseOrder, err := s.context.PurchaseOrderRepo.ChangePurchaseOrderState(orderIDStr, stateID)
-	if err != nil {
-		return errors.New("error changing state of purchase order with ID: " + orderIDStr + " - " + err.Error())
+	movement := s.context.Movement
+	movement.Reason = config.STOCK_MOVEMENT_PURCHASE_ORDER
+	if err := s.context.changeStateWithStock(stateID, true, movement); err != nil {
+		return err
 	}
-	s.context.PurchaseOrder = newPurchaseOrder
 	s.context.CurrentState = NewInTransitState(s.context)
 
 	return nil
@@ -75,7 +58,7 @@ func (s *IssuedState) changeIssuedToInTransit(stateID string) error {
 
 func (s *IssuedState) changeIssuedToCancelled(stateID string) error {
 	orderIDStr := strconv.Itoa(s.context.PurchaseOrder.ID)
-	newPurchaseOrder, err := s.context.PurchaseOrderRepo.ChangePurchaseOrderState(orderIDStr, stateID)
+	newPurchaseOrder, err := s.context.PurchaseOrderRepo.ChangePurchaseOrderState(s.context.Ctx, orderIDStr, stateID)
 	if err != nil {
 		return errors.New("error cancelling purchase order with ID: " + orderIDStr + " - " + err.Error())
 	}
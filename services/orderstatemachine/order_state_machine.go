@@ -1,26 +1,48 @@
 package orderstatemachine
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 
 	"gorm.io/gorm"
 )
 
+// ErrOrderStateChanged indica que otra petición cambió el estado de la orden mientras se procesaba
+// esta transición.
+var ErrOrderStateChanged = errors.New("the purchase order state changed, reload it and try again")
+
+// StockShortageError lista los items de la orden cuyo stock no alcanzó al despacharla.
+type StockShortageError struct {
+	Shortages []dtos.StockShortageDTO
+}
+
+func (e *StockShortageError) Error() string {
+	return fmt.Sprintf("insufficient stock for %d items of the purchase order", len(e.Shortages))
+}
+
 type OrderStateMachine struct {
-	DB                *gorm.DB
+	DB *gorm.DB
+	// contexto de la petición que dispara la transición
+	Ctx               context.Context
 	CurrentState      OrderState
 	PurchaseOrder     *models.PurchaseOrder
-	ItemRepo          *repositories.ItemRepository
-	PurchaseOrderRepo *repositories.PurchaseOrderRepository
-	InvoiceRepo       *repositories.InvoiceRepository
+	ItemRepo          repositories.ItemRepositoryInterface
+	PurchaseOrderRepo repositories.PurchaseOrderRepositoryInterface
+	InvoiceRepo       repositories.InvoiceRepositoryInterface
+	// usuario y petición de los movimientos de stock; cada transición pone el motivo
+	Movement models.StockMovement
 }
 
 // NewStateMachine construye la máquina y setea el estado actual según el estado de la orden
-func NewStateMachine(po *models.PurchaseOrder,
-	itemRepo *repositories.ItemRepository, purchaseOrderRepo *repositories.PurchaseOrderRepository, invoiceRepo *repositories.InvoiceRepository) (*OrderStateMachine, error) {
+func NewStateMachine(ctx context.Context, po *models.PurchaseOrder,
+	itemRepo repositories.ItemRepositoryInterface, purchaseOrderRepo repositories.PurchaseOrderRepositoryInterface, invoiceRepo repositories.InvoiceRepositoryInterface) (*OrderStateMachine, error) {
 	sm := &OrderStateMachine{
+		Ctx:               ctx,
 		PurchaseOrder:     po,
 		ItemRepo:          itemRepo,
 		PurchaseOrderRepo: purchaseOrderRepo,
@@ -51,3 +73,30 @@ func (sm *OrderStateMachine) GetCurrentState() OrderState {
 func (sm *OrderStateMachine) ChangeState(stateID string) error {
 	return sm.CurrentState.ChangeState(stateID)
 }
+
+// changeStateWithStock mueve la orden del estado actual a stateID junto con su stock (ver
+// PurchaseOrderRepository.ChangePurchaseOrderStateWithStock) y la recarga.
+func (sm *OrderStateMachine) changeStateWithStock(stateID string, subtract bool, movement models.StockMovement) error {
+	target, err := strconv.Atoi(stateID)
+	if err != nil {
+		return errors.New("invalid state ID: " + stateID)
+	}
+	changed, shortages, err := sm.PurchaseOrderRepo.ChangePurchaseOrderStateWithStock(sm.Ctx, sm.PurchaseOrder.ID,
+		sm.CurrentState.GetId(), target, subtract, movement)
+	if err != nil {
+		return err
+	}
+	if len(shortages) > 0 {
+		return &StockShortageError{Shortages: shortages}
+	}
+	if !changed {
+		return ErrOrderStateChanged
+	}
+
+	purchaseOrder, err := sm.PurchaseOrderRepo.GetPurchaseOrderByID(sm.Ctx, strconv.Itoa(sm.PurchaseOrder.ID))
+	if err != nil {
+		return err
+	}
+	sm.PurchaseOrder = purchaseOrder
+	return nil
+}
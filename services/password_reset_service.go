@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	"totesbackend/config"
+	"totesbackend/models"
+	"totesbackend/notifications"
+	"totesbackend/repositories"
+	"totesbackend/services/utils"
+
+	"gorm.io/gorm"
+)
+
+const passwordResetLinkPurpose = "password.reset"
+
+var ErrInvalidPassword = errors.New("invalid password")
+
+// PasswordResetService envía por correo enlaces firmados para elegir una contraseña nueva. El enlace
+// lleva una huella de la contraseña actual, así deja de valer en cuanto se usa o la contraseña cambia
+// por otra vía.
+type PasswordResetService struct {
+	Users  repositories.UserRepositoryInterface
+	Tokens *TokenService
+	Email  *EmailService
+	Links  *LinkSigner
+}
+
+func NewPasswordResetService(users repositories.UserRepositoryInterface, tokens *TokenService, email *EmailService, links *LinkSigner) *PasswordResetService {
+	return &PasswordResetService{Users: users, Tokens: tokens, Email: email, Links: links}
+}
+
+// RequestPasswordReset encola el correo con el enlace si email es de un usuario que puede iniciar
+// sesión. Si no lo es no hace nada y tampoco devuelve error, para no revelar qué correos existen.
+func (s *PasswordResetService) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := s.Users.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if !user.UserStateType.AllowsLogin {
+		return nil
+	}
+
+	expiresAt := time.Now().Add(config.PASSWORD_RESET_LINK_TTL)
+	data := notifications.PasswordResetData{
+		ResetURL: s.Links.URL("/auth/password-reset/confirm", passwordResetLinkPurpose, expiresAt,
+			strconv.Itoa(user.ID), passwordFingerprint(user.Password)),
+		ExpiresAt: expiresAt,
+	}
+	recipient := EmailRecipient{Type: NOTIFICATION_RECIPIENT_USER, ID: user.ID, Email: user.Email}
+	_, err = s.Email.Notify(ctx, recipient, NOTIFICATION_EVENT_PASSWORD_RESET, notifications.TEMPLATE_PASSWORD_RESET, data)
+	return err
+}
+
+// GetUserByResetToken devuelve el usuario del enlace si sigue vigente.
+func (s *PasswordResetService) GetUserByResetToken(ctx context.Context, token string) (*models.User, error) {
+	fields, err := s.Links.Verify(passwordResetLinkPurpose, token)
+	if err != nil || len(fields) != 2 {
+		return nil, ErrInvalidLinkToken
+	}
+	user, err := s.Users.GetUserByID(ctx, fields[0])
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidLinkToken
+		}
+		return nil, err
+	}
+	if passwordFingerprint(user.Password) != fields[1] || !user.UserStateType.AllowsLogin {
+		return nil, ErrInvalidLinkToken
+	}
+	return user, nil
+}
+
+// ResetPassword cambia la contraseña del usuario del enlace y cierra todas sus sesiones.
+func (s *PasswordResetService) ResetPassword(ctx context.Context, token, password string) (*models.User, error) {
+	user, err := s.GetUserByResetToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if len(password) < config.PASSWORD_MIN_LENGTH {
+		return nil, fmt.Errorf("%w: it must have at least %d characters", ErrInvalidPassword, config.PASSWORD_MIN_LENGTH)
+	}
+
+	hashed, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	user.Password = hashed
+	if err := s.Users.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	if err := s.Tokens.RevokeUserTokens(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// passwordFingerprint resume el hash guardado de la contraseña sin exponerlo en el enlace.
+func passwordFingerprint(hash string) string {
+	sum := sha256.Sum256([]byte(hash))
+	return hex.EncodeToString(sum[:8])
+}
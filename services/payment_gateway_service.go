@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/payments"
+	"totesbackend/repositories"
+
+	"gorm.io/gorm"
+)
+
+const (
+	GATEWAY_PAYMENT_RECEIVED   = "received"
+	GATEWAY_PAYMENT_PROCESSING = "processing"
+	GATEWAY_PAYMENT_RECORDED   = "recorded"
+	GATEWAY_PAYMENT_FAILED     = "failed"
+)
+
+// gatewayPaymentLease es cuánto puede quedar un pago en processing antes de que una notificación
+// repetida lo vuelva a tomar.
+const gatewayPaymentLease = 5 * time.Minute
+
+var ErrInvalidGatewayPayment = errors.New("invalid payment notification")
+
+// errGatewayPaymentRejected marca los pagos que no se pueden registrar por su contenido: reintentar la
+// notificación no los arreglaría.
+var errGatewayPaymentRejected = errors.New("payment rejected")
+
+// PaymentGatewayService registra en las facturas los pagos aprobados que notifica la pasarela.
+type PaymentGatewayService struct {
+	Repo     repositories.GatewayPaymentRepositoryInterface
+	Invoices *InvoiceService
+	Gateway  payments.Gateway
+}
+
+func NewPaymentGatewayService(repo repositories.GatewayPaymentRepositoryInterface, invoices *InvoiceService, gateway payments.Gateway) *PaymentGatewayService {
+	return &PaymentGatewayService{Repo: repo, Invoices: invoices, Gateway: gateway}
+}
+
+// ReceiveWebhook verifica la notificación de la pasarela y registra el pago en su factura. Devuelve
+// nil sin error si el evento no es un pago aprobado. Una transacción ya registrada, o que otra
+// notificación está procesando, devuelve el registro que ya existía; una que quedó received o failed
+// se vuelve a procesar. Si el pago no se puede registrar por su contenido (referencia o monto
+// inválidos, factura desconocida, anulada o ya pagada, otra moneda) queda como failed y no es un
+// error; el pago se registra a mano con POST /invoices/{id}/payments. Los demás errores quedan como
+// received y se devuelven, para que la pasarela reintente la notificación.
+func (s *PaymentGatewayService) ReceiveWebhook(ctx context.Context, header http.Header, body []byte) (*models.GatewayPayment, error) {
+	payment, err := s.Gateway.ParseWebhook(header, body)
+	if err != nil || payment == nil {
+		return nil, err
+	}
+	if payment.ExternalID == "" {
+		return nil, fmt.Errorf("%w: the transaction has no ID", ErrInvalidGatewayPayment)
+	}
+
+	record := &models.GatewayPayment{
+		Provider:         s.Gateway.Provider(),
+		ExternalID:       payment.ExternalID,
+		InvoiceReference: payment.InvoiceReference,
+		Amount:           payment.Amount,
+		Currency:         payment.Currency,
+		Status:           GATEWAY_PAYMENT_PROCESSING,
+		PaidAt:           payment.Date,
+		RequestID:        RequestIDFromContext(ctx),
+	}
+	if record.PaidAt.IsZero() || record.PaidAt.After(time.Now()) {
+		record.PaidAt = time.Now()
+	}
+	created, err := s.Repo.CreateGatewayPayment(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		claimed, err := s.Repo.ClaimGatewayPayment(ctx, record.Provider, record.ExternalID, GATEWAY_PAYMENT_PROCESSING,
+			[]string{GATEWAY_PAYMENT_RECEIVED, GATEWAY_PAYMENT_FAILED}, time.Now().Add(-gatewayPaymentLease))
+		if err != nil {
+			return nil, err
+		}
+		if claimed == nil {
+			return s.Repo.GetGatewayPaymentByExternalID(ctx, record.Provider, record.ExternalID)
+		}
+		record = claimed
+		record.RequestID = RequestIDFromContext(ctx)
+	}
+
+	recorded, err := s.recordPayment(ctx, record)
+	switch {
+	case err == nil:
+		record.Status = GATEWAY_PAYMENT_RECORDED
+		record.PaymentID = &recorded.ID
+		record.LastError = ""
+	case errors.Is(err, errGatewayPaymentRejected):
+		record.Status = GATEWAY_PAYMENT_FAILED
+		record.LastError = truncateGatewayError(err)
+		log.Printf("payment %s from %s could not be recorded: %v", record.ExternalID, record.Provider, err)
+	default:
+		// la pasarela reintenta la notificación y el pago se vuelve a procesar
+		record.Status = GATEWAY_PAYMENT_RECEIVED
+		record.LastError = truncateGatewayError(err)
+		if saveErr := s.Repo.SaveGatewayPayment(context.WithoutCancel(ctx), record); saveErr != nil {
+			log.Printf("error saving payment %s from %s: %v", record.ExternalID, record.Provider, saveErr)
+		}
+		return nil, err
+	}
+
+	// el pago ya está en la factura: el registro se guarda aunque la petición se haya cancelado
+	if err := s.Repo.SaveGatewayPayment(context.WithoutCancel(ctx), record); err != nil {
+		log.Printf("error saving payment %s from %s: %v", record.ExternalID, record.Provider, err)
+	}
+	return record, nil
+}
+
+func truncateGatewayError(err error) string {
+	message := err.Error()
+	if len(message) > 500 {
+		message = message[:500]
+	}
+	return message
+}
+
+func (s *PaymentGatewayService) recordPayment(ctx context.Context, record *models.GatewayPayment) (*models.Payment, error) {
+	if record.Amount <= 0 {
+		return nil, fmt.Errorf("%w: the amount must be greater than zero", errGatewayPaymentRejected)
+	}
+	// la referencia es el ID de la factura, con un sufijo opcional para que cada intento sea único
+	reference, _, _ := strings.Cut(strings.TrimSpace(record.InvoiceReference), "-")
+	invoiceID, err := strconv.Atoi(reference)
+	if err != nil || invoiceID <= 0 {
+		return nil, fmt.Errorf("%w: reference %q is not an invoice ID", errGatewayPaymentRejected, record.InvoiceReference)
+	}
+	invoice, err := s.Invoices.GetInvoiceByID(ctx, strconv.Itoa(invoiceID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: invoice %d not found", errGatewayPaymentRejected, invoiceID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if record.Currency != invoice.Currency {
+		return nil, fmt.Errorf("%w: the payment is in %s but invoice %d is in %s", errGatewayPaymentRejected, record.Currency, invoiceID, invoice.Currency)
+	}
+
+	// si un intento anterior registró el pago pero no alcanzó a marcarlo, no se registra otra vez
+	paymentReference := record.Provider + " " + record.ExternalID
+	existing, err := s.Invoices.InvoiceRepo.GetInvoicePayments(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range existing {
+		if existing[i].Reference == paymentReference {
+			return &existing[i], nil
+		}
+	}
+
+	paidAt := record.PaidAt
+	payment, _, err := s.Invoices.RecordPayment(ctx, invoiceID, dtos.CreatePaymentDTO{
+		Amount:    record.Amount,
+		Method:    "online",
+		Reference: paymentReference,
+		Date:      &paidAt,
+	})
+	if errors.Is(err, ErrInvalidPayment) || errors.Is(err, ErrInvoiceCancelled) || errors.Is(err, ErrPaymentExceedsBalance) {
+		return nil, fmt.Errorf("%w: %v", errGatewayPaymentRejected, err)
+	}
+	return payment, err
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+	"totesbackend/config"
+	"totesbackend/models"
+	"totesbackend/notifications"
+	"totesbackend/repositories"
+)
+
+const (
+	INVOICE_REMINDER_STATUS_QUEUED    = "queued"
+	INVOICE_REMINDER_STATUS_OPTED_OUT = "opted_out"
+	INVOICE_REMINDER_STATUS_NO_EMAIL  = "no_email"
+)
+
+// PaymentReminderService recuerda a los clientes el pago de sus facturas a crédito antes y después
+// del vencimiento. Cada etapa se registra una sola vez por factura, incluso si el cliente desactivó
+// los recordatorios, así el historial muestra también los que no se enviaron.
+type PaymentReminderService struct {
+	Repo  repositories.InvoiceReminderRepositoryInterface
+	Email *EmailService
+	// Days son las etapas en días respecto del vencimiento, ordenadas (PAYMENT_REMINDER_DAYS)
+	Days []int
+}
+
+func NewPaymentReminderService(repo repositories.InvoiceReminderRepositoryInterface, email *EmailService, days []int) *PaymentReminderService {
+	return &PaymentReminderService{Repo: repo, Email: email, Days: days}
+}
+
+// SendPaymentReminders encola los recordatorios pendientes a la fecha now y devuelve cuántos se
+// encolaron. Una factura recibe solo la etapa más reciente que le corresponde: si el trabajo dejó
+// de correr unos días no se envían de golpe las etapas atrasadas, y una etapa con más de
+// PAYMENT_REMINDER_CATCH_UP_DAYS de retraso se omite.
+func (s *PaymentReminderService) SendPaymentReminders(ctx context.Context, now time.Time) (int, error) {
+	queued := 0
+	for i, days := range s.Days {
+		until := days + config.PAYMENT_REMINDER_CATCH_UP_DAYS
+		if i+1 < len(s.Days) && s.Days[i+1] < until {
+			until = s.Days[i+1]
+		}
+
+		invoices, err := s.Repo.GetInvoicesDueForReminder(ctx, days, now.AddDate(0, 0, -until), now.AddDate(0, 0, -days))
+		if err != nil {
+			return queued, err
+		}
+		for _, invoice := range invoices {
+			reminder, err := s.remind(ctx, invoice, days)
+			if err != nil {
+				return queued, err
+			}
+			if reminder.Status == INVOICE_REMINDER_STATUS_QUEUED {
+				queued++
+			}
+		}
+	}
+	return queued, nil
+}
+
+func (s *PaymentReminderService) remind(ctx context.Context, invoice models.Invoice, days int) (*models.InvoiceReminder, error) {
+	customer := invoice.Customer
+	reminder := &models.InvoiceReminder{
+		InvoiceID:   invoice.ID,
+		DaysFromDue: days,
+		Email:       customer.Email,
+		Status:      INVOICE_REMINDER_STATUS_NO_EMAIL,
+	}
+
+	if customer.Email != "" {
+		data := notifications.PaymentReminderData{
+			CustomerName: strings.TrimSpace(customer.CustomerName + " " + customer.LastName),
+			InvoiceID:    invoice.ID,
+			Total:        invoice.Total,
+			Currency:     invoice.Currency,
+			DueDate:      *invoice.DueDate,
+			DaysOverdue:  max(days, 0),
+		}
+		recipient := EmailRecipient{Type: NOTIFICATION_RECIPIENT_CUSTOMER, ID: invoice.CustomerID, Email: customer.Email}
+		// si no se pudo encolar no queda registro, así se reintenta en la próxima corrida
+		message, err := s.Email.Notify(ctx, recipient, NOTIFICATION_EVENT_PAYMENT_REMINDER, notifications.TEMPLATE_PAYMENT_REMINDER, data)
+		if err != nil {
+			return nil, err
+		}
+		reminder.Status = INVOICE_REMINDER_STATUS_OPTED_OUT
+		if message != nil {
+			reminder.Status = INVOICE_REMINDER_STATUS_QUEUED
+			reminder.EmailMessageID = &message.ID
+		}
+	}
+
+	if err := s.Repo.CreateReminder(ctx, reminder); err != nil {
+		return nil, err
+	}
+	return reminder, nil
+}
+
+func (s *PaymentReminderService) GetInvoiceReminders(ctx context.Context, invoiceID int) ([]models.InvoiceReminder, error) {
+	return s.Repo.GetRemindersByInvoice(ctx, invoiceID)
+}
@@ -1,30 +1,32 @@
 package services
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
 
 type PermissionService struct {
-	Repo *repositories.PermissionRepository
+	Repo repositories.PermissionRepositoryInterface
 }
 
-func NewPermissionService(repo *repositories.PermissionRepository) *PermissionService {
+func NewPermissionService(repo repositories.PermissionRepositoryInterface) *PermissionService {
 	return &PermissionService{Repo: repo}
 }
 
-func (s *PermissionService) GetPermissionByID(id uint) (*models.Permission, error) {
-	return s.Repo.GetPermissionByID(id)
+func (s *PermissionService) GetPermissionByID(ctx context.Context, id uint) (*models.Permission, error) {
+	return s.Repo.GetPermissionByID(ctx, id)
 }
 
-func (s *PermissionService) GetAllPermissions() ([]models.Permission, error) {
-	return s.Repo.GetAllPermissions()
+func (s *PermissionService) GetAllPermissions(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Permission, int64, error) {
+	return s.Repo.GetAllPermissions(ctx, pagination)
 }
 
-func (s *PermissionService) SearchPermissionsByID(query string) ([]models.Permission, error) {
-	return s.Repo.SearchPermissionsByID(query)
+func (s *PermissionService) SearchPermissionsByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Permission, int64, error) {
+	return s.Repo.SearchPermissionsByID(ctx, query, pagination)
 }
 
-func (s *PermissionService) SearchPermissionsByName(query string) ([]models.Permission, error) {
-	return s.Repo.SearchPermissionsByName(query)
+func (s *PermissionService) SearchPermissionsByName(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Permission, int64, error) {
+	return s.Repo.SearchPermissionsByName(ctx, query, pagination)
 }
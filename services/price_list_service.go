@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidPriceList  = errors.New("invalid price list")
+	ErrPriceListTaken    = errors.New("a price list with that name already exists")
+	ErrPriceListNotFound = errors.New("price list not found")
+)
+
+type PriceListService struct {
+	Repo         repositories.PriceListRepositoryInterface
+	ItemRepo     repositories.ItemRepositoryInterface
+	CustomerRepo repositories.CustomerRepositoryInterface
+}
+
+func NewPriceListService(repo repositories.PriceListRepositoryInterface, itemRepo repositories.ItemRepositoryInterface,
+	customerRepo repositories.CustomerRepositoryInterface) *PriceListService {
+	return &PriceListService{Repo: repo, ItemRepo: itemRepo, CustomerRepo: customerRepo}
+}
+
+func (s *PriceListService) GetPriceLists(ctx context.Context, pagination dtos.PaginationDTO) ([]models.PriceList, int64, error) {
+	return s.Repo.GetPriceLists(ctx, pagination)
+}
+
+func (s *PriceListService) GetPriceListByID(ctx context.Context, id int) (*models.PriceList, error) {
+	return s.Repo.GetPriceListByID(ctx, id)
+}
+
+func (s *PriceListService) CreatePriceList(ctx context.Context, dto dtos.PriceListDTO) (*models.PriceList, error) {
+	priceList := &models.PriceList{}
+	if err := s.fillPriceList(ctx, priceList, dto); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.CreatePriceList(ctx, priceList); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetPriceListByID(ctx, priceList.ID)
+}
+
+// UpdatePriceList reemplaza el nombre y todos los precios de la lista; los items que no vengan vuelven
+// al precio normal para sus clientes.
+func (s *PriceListService) UpdatePriceList(ctx context.Context, id int, dto dtos.PriceListDTO) (*models.PriceList, error) {
+	priceList, err := s.Repo.GetPriceListByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fillPriceList(ctx, priceList, dto); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.ReplacePriceList(ctx, priceList); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetPriceListByID(ctx, id)
+}
+
+func (s *PriceListService) DeletePriceList(ctx context.Context, id int) error {
+	return s.Repo.DeletePriceList(ctx, id)
+}
+
+// AssignPriceList asigna la lista priceListID al cliente; nil se la quita.
+func (s *PriceListService) AssignPriceList(ctx context.Context, customerID int, priceListID *int) (*models.Customer, error) {
+	if _, err := s.CustomerRepo.GetCustomerByID(ctx, customerID); err != nil {
+		return nil, err
+	}
+	if priceListID != nil {
+		if _, err := s.Repo.GetPriceListByID(ctx, *priceListID); errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %d", ErrPriceListNotFound, *priceListID)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	if err := s.Repo.SetCustomerPriceList(ctx, customerID, priceListID); err != nil {
+		return nil, err
+	}
+	return s.CustomerRepo.GetCustomerByID(ctx, customerID)
+}
+
+// fillPriceList copia dto a la lista; el nombre no puede repetirse y cada item debe existir y
+// aparecer una sola vez.
+func (s *PriceListService) fillPriceList(ctx context.Context, priceList *models.PriceList, dto dtos.PriceListDTO) error {
+	priceList.Name = strings.TrimSpace(dto.Name)
+	priceList.Description = strings.TrimSpace(dto.Description)
+	priceList.UpdatedAt = time.Now()
+	if priceList.Name == "" {
+		return fmt.Errorf("%w: the name cannot be blank", ErrInvalidPriceList)
+	}
+	existing, err := s.Repo.GetPriceListByName(ctx, priceList.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != priceList.ID {
+		return ErrPriceListTaken
+	}
+
+	priceList.Items = make([]models.PriceListItem, 0, len(dto.Items))
+	seen := make(map[int]bool, len(dto.Items))
+	for _, line := range dto.Items {
+		if seen[line.ItemID] {
+			return fmt.Errorf("%w: item %d appears more than once", ErrInvalidPriceList, line.ItemID)
+		}
+		seen[line.ItemID] = true
+		if _, err := s.ItemRepo.GetItemByID(ctx, strconv.Itoa(line.ItemID)); errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: item %d does not exist", ErrInvalidPriceList, line.ItemID)
+		} else if err != nil {
+			return err
+		}
+		priceList.Items = append(priceList.Items, models.PriceListItem{ItemID: line.ItemID, Price: line.Price})
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+	"totesbackend/config"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+)
+
+// PublicCatalogService sirve el catálogo de la tienda. Las páginas se guardan en memoria durante
+// config.PUBLIC_CATALOG_CACHE_TTL: el endpoint es público y no debe llegar a la base en cada visita.
+type PublicCatalogService struct {
+	Repo  repositories.ItemRepositoryInterface
+	mu    sync.Mutex
+	cache map[string]publicCatalogPage
+}
+
+type publicCatalogPage struct {
+	items     []dtos.PublicCatalogItemDTO
+	total     int64
+	expiresAt time.Time
+}
+
+func NewPublicCatalogService(repo repositories.ItemRepositoryInterface) *PublicCatalogService {
+	return &PublicCatalogService{Repo: repo, cache: make(map[string]publicCatalogPage)}
+}
+
+func (s *PublicCatalogService) GetCatalog(ctx context.Context, filter dtos.PublicCatalogFilterDTO) ([]dtos.PublicCatalogItemDTO, int64, error) {
+	key := fmt.Sprintf("%d|%d|%d|%s", filter.Page, filter.PageSize, filter.CategoryID, filter.Search)
+
+	s.mu.Lock()
+	page, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok && time.Now().Before(page.expiresAt) {
+		return page.items, page.total, nil
+	}
+
+	items, total, err := s.Repo.GetCatalogItems(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	catalog := make([]dtos.PublicCatalogItemDTO, len(items))
+	for i, item := range items {
+		catalog[i] = publicCatalogItem(item)
+	}
+
+	s.store(key, publicCatalogPage{items: catalog, total: total, expiresAt: time.Now().Add(config.PUBLIC_CATALOG_CACHE_TTL)})
+	return catalog, total, nil
+}
+
+func (s *PublicCatalogService) store(key string, page publicCatalogPage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.cache) >= config.PUBLIC_CATALOG_CACHE_MAX_ENTRIES {
+		now := time.Now()
+		for cached, entry := range s.cache {
+			if now.After(entry.expiresAt) {
+				delete(s.cache, cached)
+			}
+		}
+		// muchas búsquedas distintas en un minuto: se empieza de cero antes que crecer sin límite
+		if len(s.cache) >= config.PUBLIC_CATALOG_CACHE_MAX_ENTRIES {
+			s.cache = make(map[string]publicCatalogPage)
+		}
+	}
+	s.cache[key] = page
+}
+
+func publicCatalogItem(item models.Item) dtos.PublicCatalogItemDTO {
+	return dtos.PublicCatalogItemDTO{
+		ID:          item.ID,
+		Name:        item.Name,
+		Description: item.Description,
+		Price:       item.SellingPrice,
+		Currency:    item.Currency,
+		CategoryID:  item.ItemTypeID,
+		Category:    item.ItemType.Name,
+		InStock:     item.Stock > 0,
+	}
+}
@@ -1,23 +1,31 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"strconv"
+	"totesbackend/config"
 	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 	"totesbackend/services/orderstatemachine"
 )
 
+// ErrPurchaseOrderStateConflict indica que otra petición cambió el estado de la orden a la vez.
+var ErrPurchaseOrderStateConflict = orderstatemachine.ErrOrderStateChanged
+
 type PurchaseOrderService struct {
-	PurchaseOrderRepo *repositories.PurchaseOrderRepository
-	ItemRepo          *repositories.ItemRepository
-	InvoiceRepo       *repositories.InvoiceRepository
+	PurchaseOrderRepo repositories.PurchaseOrderRepositoryInterface
+	ItemRepo          repositories.ItemRepositoryInterface
+	InvoiceRepo       repositories.InvoiceRepositoryInterface
 	BillingService    *BillingService
+	Bus               *EventBus
+	Events            *EventStreamService
+	Accounting        *AccountingService
 }
 
-func NewPurchaseOrderService(purchaseOrderRepo *repositories.PurchaseOrderRepository,
-	itemRepo *repositories.ItemRepository, billingService *BillingService, invoiceRepo *repositories.InvoiceRepository) *PurchaseOrderService {
+func NewPurchaseOrderService(purchaseOrderRepo repositories.PurchaseOrderRepositoryInterface,
+	itemRepo repositories.ItemRepositoryInterface, billingService *BillingService, invoiceRepo repositories.InvoiceRepositoryInterface) *PurchaseOrderService {
 	return &PurchaseOrderService{
 		PurchaseOrderRepo: purchaseOrderRepo,
 		ItemRepo:          itemRepo,
@@ -26,11 +34,11 @@ func NewPurchaseOrderService(purchaseOrderRepo *repositories.PurchaseOrderReposi
 	}
 }
 
-func (s *PurchaseOrderService) CreatePurchaseOrder(dto *dtos.CreatePurchaseOrderDTO) (*models.PurchaseOrder, error) {
+func (s *PurchaseOrderService) CreatePurchaseOrder(ctx context.Context, dto *dtos.CreatePurchaseOrderDTO) (*models.PurchaseOrder, error) {
 	// Verificar stock de los items
 	for _, item := range dto.Items {
 		itemID := strconv.Itoa(item.ID)
-		hasStock, err := s.ItemRepo.HasEnoughStock(itemID, item.Stock)
+		hasStock, err := s.ItemRepo.HasEnoughStock(ctx, itemID, item.Stock)
 		if err != nil {
 			return nil, err
 		}
@@ -39,66 +47,88 @@ func (s *PurchaseOrderService) CreatePurchaseOrder(dto *dtos.CreatePurchaseOrder
 		}
 	}
 
-	// Calcular subtotal
-	subtotal, err := s.BillingService.CalculateSubtotal(dto.Items)
+	// Calcular subtotal, con la lista de precios del cliente si la tiene
+	customerID := 0
+	if dto.CustomerID != nil {
+		customerID = *dto.CustomerID
+	}
+	subtotal, err := s.BillingService.CalculateSubtotal(ctx, customerID, config.BASE_CURRENCY, dto.Items)
 	if err != nil {
 		return nil, err
 	}
 
 	// Crear la orden de compra
-	purchaseOrder, err := s.PurchaseOrderRepo.CreatePurchaseOrder(dto, subtotal, subtotal)
+	purchaseOrder, err := s.PurchaseOrderRepo.CreatePurchaseOrder(ctx, dto, subtotal, subtotal)
 	if err != nil {
 		return nil, err
 	}
 
+	s.Bus.Publish(ctx, PurchaseOrderCreated{PurchaseOrder: purchaseOrder})
+
 	return purchaseOrder, nil
 }
 
-func (s *PurchaseOrderService) GetPurchaseOrderByID(id string) (*models.PurchaseOrder, error) {
-	return s.PurchaseOrderRepo.GetPurchaseOrderByID(id)
+func (s *PurchaseOrderService) GetPurchaseOrderByID(ctx context.Context, id string) (*models.PurchaseOrder, error) {
+	return s.PurchaseOrderRepo.GetPurchaseOrderByID(ctx, id)
 }
 
-func (s *PurchaseOrderService) GetAllPurchaseOrders() ([]models.PurchaseOrder, error) {
-	return s.PurchaseOrderRepo.GetAllPurchaseOrders()
+func (s *PurchaseOrderService) GetAllPurchaseOrders(ctx context.Context, pagination dtos.PaginationDTO) ([]models.PurchaseOrder, int64, error) {
+	return s.PurchaseOrderRepo.GetAllPurchaseOrders(ctx, pagination)
 }
 
-func (s *PurchaseOrderService) SearchPurchaseOrdersByID(query string) ([]models.PurchaseOrder, error) {
-	return s.PurchaseOrderRepo.SearchPurchaseOrdersByID(query)
+func (s *PurchaseOrderService) SearchPurchaseOrdersByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.PurchaseOrder, int64, error) {
+	return s.PurchaseOrderRepo.SearchPurchaseOrdersByID(ctx, query, pagination)
 }
 
-func (s *PurchaseOrderService) GetPurchaseOrdersByCustomerID(customerID string) ([]models.PurchaseOrder, error) {
-	return s.PurchaseOrderRepo.GetPurchaseOrdersByCustomerID(customerID)
+func (s *PurchaseOrderService) GetPurchaseOrdersByCustomerID(ctx context.Context, customerID string, pagination dtos.PaginationDTO) ([]models.PurchaseOrder, int64, error) {
+	return s.PurchaseOrderRepo.GetPurchaseOrdersByCustomerID(ctx, customerID, pagination)
 }
 
-func (s *PurchaseOrderService) GetPurchaseOrdersBySellerID(sellerID string) ([]models.PurchaseOrder, error) {
-	return s.PurchaseOrderRepo.GetPurchaseOrdersBySellerID(sellerID)
+func (s *PurchaseOrderService) GetPurchaseOrdersBySellerID(ctx context.Context, sellerID string, pagination dtos.PaginationDTO) ([]models.PurchaseOrder, int64, error) {
+	return s.PurchaseOrderRepo.GetPurchaseOrdersBySellerID(ctx, sellerID, pagination)
 }
 
-func (s *PurchaseOrderService) ChangePurchaseOrderState(id string, targetStateID string) (*models.PurchaseOrder, *models.Invoice, error) {
-	po, err := s.PurchaseOrderRepo.GetPurchaseOrderByID(id)
+func (s *PurchaseOrderService) ChangePurchaseOrderState(ctx context.Context, id string, targetStateID string) (*models.PurchaseOrder, *models.Invoice, error) {
+	po, err := s.PurchaseOrderRepo.GetPurchaseOrderByID(ctx, id)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	stateMachine, err := orderstatemachine.NewStateMachine(po, s.ItemRepo, s.PurchaseOrderRepo, s.InvoiceRepo)
+	stateMachine, err := orderstatemachine.NewStateMachine(ctx, po, s.ItemRepo, s.PurchaseOrderRepo, s.InvoiceRepo)
 	if err != nil {
 		return nil, nil, err
 	}
+	stateMachine.Movement = newStockMovement(ctx, "")
+
+	// algunas transiciones descuentan stock
+	itemIDs := make([]int, len(po.Items))
+	for i, item := range po.Items {
+		itemIDs[i] = item.ItemID
+	}
+	stockBefore := s.Events.StockSnapshot(ctx, s.ItemRepo, itemIDs)
 
 	if err := stateMachine.ChangeState(targetStateID); err != nil {
+		var shortage *orderstatemachine.StockShortageError
+		if errors.As(err, &shortage) {
+			return nil, nil, &InsufficientStockError{Shortages: shortage.Shortages}
+		}
 		return nil, nil, err
 	}
+	s.Events.PublishLowStock(ctx, s.ItemRepo, stockBefore)
+	s.Bus.Publish(ctx, PurchaseOrderStateChanged{PurchaseOrder: stateMachine.PurchaseOrder})
 	if generator, ok := stateMachine.CurrentState.(orderstatemachine.InvoiceGenerator); ok {
-		return stateMachine.PurchaseOrder, generator.GetGeneratedInvoice(), nil
+		invoice := generator.GetGeneratedInvoice()
+		s.Accounting.QueueInvoice(ctx, invoice)
+		return stateMachine.PurchaseOrder, invoice, nil
 	}
 
 	return stateMachine.PurchaseOrder, nil, nil
 }
 
-func (s *PurchaseOrderService) UpdatePurchaseOrder(purchaseOrder *models.PurchaseOrder) error {
-	return s.PurchaseOrderRepo.UpdatePurchaseOrder(purchaseOrder)
+func (s *PurchaseOrderService) UpdatePurchaseOrder(ctx context.Context, purchaseOrder *models.PurchaseOrder) error {
+	return s.PurchaseOrderRepo.UpdatePurchaseOrder(ctx, purchaseOrder)
 }
 
-func (s *PurchaseOrderService) GetPurchaseOrdersByStateID(stateID string) ([]models.PurchaseOrder, error) {
-	return s.PurchaseOrderRepo.GetPurchaseOrdersByStateID(stateID)
+func (s *PurchaseOrderService) GetPurchaseOrdersByStateID(ctx context.Context, stateID string, pagination dtos.PaginationDTO) ([]models.PurchaseOrder, int64, error) {
+	return s.PurchaseOrderRepo.GetPurchaseOrdersByStateID(ctx, stateID, pagination)
 }
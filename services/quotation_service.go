@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"totesbackend/config"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+)
+
+// Estados de una cotización. Solo la pendiente se puede editar; el cliente la acepta o la rechaza y
+// la aceptada se convierte en factura una sola vez.
+const (
+	QUOTATION_STATE_PENDING   = "pending"
+	QUOTATION_STATE_ACCEPTED  = "accepted"
+	QUOTATION_STATE_REJECTED  = "rejected"
+	QUOTATION_STATE_CONVERTED = "converted"
+)
+
+var QuotationStates = []string{QUOTATION_STATE_PENDING, QUOTATION_STATE_ACCEPTED, QUOTATION_STATE_REJECTED, QUOTATION_STATE_CONVERTED}
+
+var (
+	ErrInvalidQuotation    = errors.New("invalid quotation")
+	ErrQuotationTransition = errors.New("quotation state does not allow this operation")
+)
+
+// QuotationService usa InvoiceService para calcular los valores, como una factura, y para avisar de
+// la factura creada al convertir.
+type QuotationService struct {
+	Repo     repositories.QuotationRepositoryInterface
+	Invoices *InvoiceService
+}
+
+func NewQuotationService(repo repositories.QuotationRepositoryInterface, invoices *InvoiceService) *QuotationService {
+	return &QuotationService{Repo: repo, Invoices: invoices}
+}
+
+func (s *QuotationService) GetQuotationByID(ctx context.Context, id int) (*models.Quotation, error) {
+	return s.Repo.GetQuotationByID(ctx, id)
+}
+
+func (s *QuotationService) GetQuotations(ctx context.Context, state string, customerID *int, pagination dtos.PaginationDTO) ([]models.Quotation, int64, error) {
+	return s.Repo.GetQuotations(ctx, state, customerID, pagination)
+}
+
+// CreateQuotation crea la cotización pendiente con los precios actuales de los items.
+func (s *QuotationService) CreateQuotation(ctx context.Context, dto dtos.QuotationDTO) (*models.Quotation, error) {
+	quotation := &models.Quotation{
+		DateTime:  time.Now(),
+		State:     QUOTATION_STATE_PENDING,
+		CreatedBy: UserFromContext(ctx),
+	}
+	if err := s.fillQuotation(ctx, quotation, dto); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.CreateQuotation(ctx, quotation); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetQuotationByID(ctx, quotation.ID)
+}
+
+// UpdateQuotation reemplaza una cotización pendiente y recalcula sus valores con los precios actuales.
+func (s *QuotationService) UpdateQuotation(ctx context.Context, id int, dto dtos.QuotationDTO) (*models.Quotation, error) {
+	quotation, err := s.Repo.GetQuotationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if quotation.State != QUOTATION_STATE_PENDING {
+		return nil, fmt.Errorf("%w: only pending quotations can be edited (quotation is %s)", ErrQuotationTransition, quotation.State)
+	}
+	if err := s.fillQuotation(ctx, quotation, dto); err != nil {
+		return nil, err
+	}
+	updated, err := s.Repo.ReplaceQuotation(ctx, quotation, QUOTATION_STATE_PENDING)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, fmt.Errorf("%w: the quotation was accepted or rejected meanwhile", ErrQuotationTransition)
+	}
+	return s.Repo.GetQuotationByID(ctx, id)
+}
+
+// DeleteQuotation borra una cotización que no se ha convertido en factura.
+func (s *QuotationService) DeleteQuotation(ctx context.Context, id int) error {
+	deleted, err := s.Repo.DeleteQuotation(ctx, id, []string{QUOTATION_STATE_PENDING, QUOTATION_STATE_ACCEPTED, QUOTATION_STATE_REJECTED})
+	if err != nil || deleted {
+		return err
+	}
+	if _, err := s.Repo.GetQuotationByID(ctx, id); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: converted quotations cannot be deleted", ErrQuotationTransition)
+}
+
+// AcceptQuotation registra que el cliente aceptó una cotización pendiente que no ha vencido.
+func (s *QuotationService) AcceptQuotation(ctx context.Context, id int) (*models.Quotation, error) {
+	quotation, err := s.Repo.GetQuotationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if quotation.State == QUOTATION_STATE_PENDING && !time.Now().Before(quotation.ExpiresAt) {
+		return nil, fmt.Errorf("%w: the quotation expired on %s", ErrQuotationTransition, quotation.ExpiresAt.Format(time.RFC3339))
+	}
+	return s.changeState(ctx, id, []string{QUOTATION_STATE_PENDING}, QUOTATION_STATE_ACCEPTED)
+}
+
+// RejectQuotation registra que el cliente rechazó la cotización, pendiente o ya aceptada.
+func (s *QuotationService) RejectQuotation(ctx context.Context, id int) (*models.Quotation, error) {
+	return s.changeState(ctx, id, []string{QUOTATION_STATE_PENDING, QUOTATION_STATE_ACCEPTED}, QUOTATION_STATE_REJECTED)
+}
+
+// ConvertQuotation crea la factura de una cotización aceptada con los valores cotizados, aunque los
+// precios hayan cambiado, y la marca como convertida. El stock se descuenta como en
+// InvoiceService.CreateInvoice: si algún item no alcanza devuelve un *InsufficientStockError.
+func (s *QuotationService) ConvertQuotation(ctx context.Context, id int, dto dtos.ConvertQuotationDTO) (*models.Invoice, error) {
+	quotation, err := s.Repo.GetQuotationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if quotation.State != QUOTATION_STATE_ACCEPTED {
+		return nil, fmt.Errorf("%w: only accepted quotations can be converted (quotation is %s)", ErrQuotationTransition, quotation.State)
+	}
+
+	items := make([]dtos.BillingItemDTO, len(quotation.Items))
+	itemIDs := make([]int, len(quotation.Items))
+	for i, item := range quotation.Items {
+		items[i] = dtos.BillingItemDTO{ID: item.ItemID, Stock: item.Amount}
+		itemIDs[i] = item.ItemID
+	}
+	stockBefore := s.Invoices.Events.StockSnapshot(ctx, s.Invoices.ItemRepo, itemIDs)
+
+	created, shortages, err := s.Repo.ConvertQuotation(ctx, id, QUOTATION_STATE_ACCEPTED, QUOTATION_STATE_CONVERTED, dto.DueDate,
+		newStockMovement(ctx, config.STOCK_MOVEMENT_INVOICE))
+	if err != nil {
+		return nil, err
+	}
+	if len(shortages) > 0 {
+		return nil, &InsufficientStockError{Shortages: shortages}
+	}
+	if created == nil {
+		return nil, fmt.Errorf("%w: the quotation changed meanwhile, reload it and try again", ErrQuotationTransition)
+	}
+
+	invoice, err := s.Invoices.InvoiceRepo.GetInvoiceByID(ctx, strconv.Itoa(created.ID))
+	if err != nil {
+		return nil, err
+	}
+	s.Invoices.publishInvoiceCreated(ctx, invoice, items, stockBefore)
+	return invoice, nil
+}
+
+func (s *QuotationService) changeState(ctx context.Context, id int, fromStates []string, state string) (*models.Quotation, error) {
+	changed, err := s.Repo.SetQuotationState(ctx, id, fromStates, state)
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		quotation, err := s.Repo.GetQuotationByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: cannot go from %s to %s", ErrQuotationTransition, quotation.State, state)
+	}
+	return s.Repo.GetQuotationByID(ctx, id)
+}
+
+// fillQuotation copia dto a la cotización y calcula el subtotal y el total como los de una factura.
+// Cada item, descuento e impuesto debe existir y aparecer una vez, y la fecha de vencimiento debe ser
+// futura.
+func (s *QuotationService) fillQuotation(ctx context.Context, quotation *models.Quotation, dto dtos.QuotationDTO) error {
+	if !dto.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("%w: expires_at must be in the future", ErrInvalidQuotation)
+	}
+	quotation.EnterpriseData = strings.TrimSpace(dto.EnterpriseData)
+	quotation.CustomerID = dto.CustomerID
+	quotation.ExpiresAt = dto.ExpiresAt
+	quotation.Currency = currencyCode(dto.Currency)
+
+	quotation.Items = make([]models.QuotationItem, 0, len(dto.Items))
+	seen := make(map[int]bool, len(dto.Items))
+	for _, line := range dto.Items {
+		if line.Stock < 1 {
+			return fmt.Errorf("%w: item %d must have a quantity of at least 1", ErrInvalidQuotation, line.ID)
+		}
+		if seen[line.ID] {
+			return fmt.Errorf("%w: item %d appears more than once", ErrInvalidQuotation, line.ID)
+		}
+		seen[line.ID] = true
+		quotation.Items = append(quotation.Items, models.QuotationItem{ItemID: line.ID, Amount: line.Stock})
+	}
+
+	billing := s.Invoices.BillingService
+	discountIDs, err := uniqueIDStrings(dto.Discounts, "discount")
+	if err != nil {
+		return err
+	}
+	taxIDs, err := uniqueIDStrings(dto.Taxes, "tax")
+	if err != nil {
+		return err
+	}
+	quotation.Subtotal, err = billing.CalculateSubtotal(ctx, quotation.CustomerID, quotation.Currency, dto.Items)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidQuotation, err)
+	}
+	quotation.Total, err = billing.CalculateTotal(ctx, quotation.CustomerID, quotation.Currency, discountIDs, taxIDs, dto.Items)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidQuotation, err)
+	}
+
+	quotation.Discounts = make([]models.DiscountType, 0, len(discountIDs))
+	for _, discountID := range discountIDs {
+		discount, err := billing.DiscountRepo.GetDiscountTypeByID(ctx, discountID)
+		if err != nil {
+			return err
+		}
+		quotation.Discounts = append(quotation.Discounts, *discount)
+	}
+	quotation.Taxes = make([]models.TaxType, 0, len(taxIDs))
+	for _, taxID := range taxIDs {
+		tax, err := billing.TaxRepo.GetTaxTypeByID(ctx, taxID)
+		if err != nil {
+			return err
+		}
+		quotation.Taxes = append(quotation.Taxes, *tax)
+	}
+	return nil
+}
+
+// uniqueIDStrings convierte los IDs a los strings que usa BillingService; un ID repetido es un error.
+func uniqueIDStrings(ids []int, kind string) ([]string, error) {
+	strs := make([]string, 0, len(ids))
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return nil, fmt.Errorf("%w: %s %d appears more than once", ErrInvalidQuotation, kind, id)
+		}
+		seen[id] = true
+		strs = append(strs, strconv.Itoa(id))
+	}
+	return strs, nil
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+const redactedValue = "[REDACTED]"
+
+// Fragmentos de nombres de campo cuyo valor nunca debe quedar en logs ni auditoría
+var sensitiveKeyParts = []string{"password", "token", "secret", "authorization", "api_key", "apikey"}
+
+var sensitiveFormValue = regexp.MustCompile(`(?i)((?:password|token|secret|authorization|api_?key)[^=&"]*)=([^&\s]*)`)
+
+// Para JSON incompleto (p. ej. un cuerpo truncado) que no se puede decodificar
+var sensitiveJSONValue = regexp.MustCompile(`(?i)("[^"]*(?:password|token|secret|authorization|api_?key)[^"]*"\s*:\s*)("(?:[^"\\]|\\.)*"?|[^,}\s]*)`)
+
+// RedactJSON oculta los valores de los campos sensibles de un documento JSON. Si el contenido
+// no es JSON válido se buscan los pares sensibles con expresiones regulares, tanto con forma
+// "clave": valor como clave=valor (formularios, query strings).
+func RedactJSON(raw []byte) []byte {
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		raw = sensitiveJSONValue.ReplaceAll(raw, []byte(`$1"`+redactedValue+`"`))
+		return sensitiveFormValue.ReplaceAll(raw, []byte("$1="+redactedValue))
+	}
+
+	redacted, err := json.Marshal(redactSecrets(decoded))
+	if err != nil {
+		return []byte(redactedValue)
+	}
+	return redacted
+}
+
+func isSensitiveKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(lower, part) {
+			return true
+		}
+	}
+	return false
+}
+
+func redactSecrets(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSensitiveKey(key) {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = redactSecrets(field)
+		}
+	case []interface{}:
+		for i, field := range v {
+			v[i] = redactSecrets(field)
+		}
+	}
+	return value
+}
@@ -0,0 +1,31 @@
+package services
+
+import "context"
+
+type requestIDKey struct{}
+
+// WithRequestID guarda el ID de la petición en el contexto para que los logs, la auditoría y los
+// eventos de seguridad creados durante ella lo registren.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext devuelve el ID de la petición, o "" si el contexto no viene de una.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+type userKey struct{}
+
+// WithUser guarda el correo del usuario autenticado para los registros que lo necesitan fuera de
+// los controladores, como los movimientos de stock.
+func WithUser(ctx context.Context, email string) context.Context {
+	return context.WithValue(ctx, userKey{}, email)
+}
+
+// UserFromContext devuelve el correo del usuario, o "" si la petición es anónima o no viene de una.
+func UserFromContext(ctx context.Context) string {
+	email, _ := ctx.Value(userKey{}).(string)
+	return email
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"totesbackend/config"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+
+	"gorm.io/gorm"
+)
+
+// Estados de un pedido de reposición. Solo el borrador se puede editar; un pedido enviado se
+// recibe en una o varias entregas y queda received cuando llegó todo.
+const (
+	RESTOCK_ORDER_STATE_DRAFT     = "draft"
+	RESTOCK_ORDER_STATE_SENT      = "sent"
+	RESTOCK_ORDER_STATE_RECEIVED  = "received"
+	RESTOCK_ORDER_STATE_CANCELLED = "cancelled"
+)
+
+var RestockOrderStates = []string{RESTOCK_ORDER_STATE_DRAFT, RESTOCK_ORDER_STATE_SENT, RESTOCK_ORDER_STATE_RECEIVED, RESTOCK_ORDER_STATE_CANCELLED}
+
+var (
+	ErrInvalidRestockOrder    = errors.New("invalid restock order")
+	ErrRestockOrderTransition = errors.New("restock order state does not allow this operation")
+)
+
+type RestockOrderService struct {
+	Repo      repositories.RestockOrderRepositoryInterface
+	ItemRepo  repositories.ItemRepositoryInterface
+	Suppliers *SupplierService
+	Bus       *EventBus
+}
+
+func NewRestockOrderService(repo repositories.RestockOrderRepositoryInterface, itemRepo repositories.ItemRepositoryInterface) *RestockOrderService {
+	return &RestockOrderService{Repo: repo, ItemRepo: itemRepo}
+}
+
+func (s *RestockOrderService) GetRestockOrderByID(ctx context.Context, id int) (*models.RestockOrder, error) {
+	return s.Repo.GetRestockOrderByID(ctx, id)
+}
+
+func (s *RestockOrderService) GetRestockOrders(ctx context.Context, state string, pagination dtos.PaginationDTO) ([]models.RestockOrder, int64, error) {
+	return s.Repo.GetRestockOrders(ctx, state, pagination)
+}
+
+// CreateRestockOrder crea el pedido en borrador.
+func (s *RestockOrderService) CreateRestockOrder(ctx context.Context, dto dtos.RestockOrderDTO) (*models.RestockOrder, error) {
+	order := &models.RestockOrder{
+		State:     RESTOCK_ORDER_STATE_DRAFT,
+		CreatedBy: UserFromContext(ctx),
+		CreatedAt: time.Now(),
+	}
+	if err := s.fillRestockOrder(ctx, order, dto); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.CreateRestockOrder(ctx, order); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetRestockOrderByID(ctx, order.ID)
+}
+
+// UpdateRestockOrder reemplaza el proveedor, las notas y las líneas de un pedido en borrador.
+func (s *RestockOrderService) UpdateRestockOrder(ctx context.Context, id int, dto dtos.RestockOrderDTO) (*models.RestockOrder, error) {
+	order, err := s.Repo.GetRestockOrderByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.State != RESTOCK_ORDER_STATE_DRAFT {
+		return nil, fmt.Errorf("%w: only draft orders can be edited (order is %s)", ErrRestockOrderTransition, order.State)
+	}
+	if err := s.fillRestockOrder(ctx, order, dto); err != nil {
+		return nil, err
+	}
+	updated, err := s.Repo.ReplaceRestockOrder(ctx, order, RESTOCK_ORDER_STATE_DRAFT)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, fmt.Errorf("%w: the order was sent or cancelled meanwhile", ErrRestockOrderTransition)
+	}
+	return s.Repo.GetRestockOrderByID(ctx, id)
+}
+
+// SendRestockOrder marca el borrador como enviado al proveedor; desde ahí ya no se edita.
+func (s *RestockOrderService) SendRestockOrder(ctx context.Context, id int) (*models.RestockOrder, error) {
+	return s.changeState(ctx, id, []string{RESTOCK_ORDER_STATE_DRAFT}, RESTOCK_ORDER_STATE_SENT, "sent_at")
+}
+
+// CancelRestockOrder cancela un pedido en borrador o enviado. Lo que ya se recibió queda en el stock.
+func (s *RestockOrderService) CancelRestockOrder(ctx context.Context, id int) (*models.RestockOrder, error) {
+	return s.changeState(ctx, id, []string{RESTOCK_ORDER_STATE_DRAFT, RESTOCK_ORDER_STATE_SENT}, RESTOCK_ORDER_STATE_CANCELLED, "cancelled_at")
+}
+
+// ReceiveRestockOrder suma al stock lo que llegó de un pedido enviado. Sin items recibe todo lo
+// pendiente; ninguna línea puede superar lo pedido. Cuando llega todo el pedido queda received.
+func (s *RestockOrderService) ReceiveRestockOrder(ctx context.Context, id int, dto dtos.ReceiveRestockOrderDTO) (*models.RestockOrder, error) {
+	order, err := s.Repo.GetRestockOrderByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.State != RESTOCK_ORDER_STATE_SENT {
+		return nil, fmt.Errorf("%w: only sent orders can be received (order is %s)", ErrRestockOrderTransition, order.State)
+	}
+
+	pending := make(map[int]int, len(order.Items))
+	for _, item := range order.Items {
+		pending[item.ItemID] = item.Quantity - item.ReceivedQuantity
+	}
+
+	received := make(map[int]int)
+	if len(dto.Items) == 0 {
+		for itemID, quantity := range pending {
+			if quantity > 0 {
+				received[itemID] = quantity
+			}
+		}
+	}
+	for _, item := range dto.Items {
+		remaining, ok := pending[item.ItemID]
+		if !ok {
+			return nil, fmt.Errorf("%w: item %d is not in the order", ErrInvalidRestockOrder, item.ItemID)
+		}
+		received[item.ItemID] += item.Quantity
+		if received[item.ItemID] > remaining {
+			return nil, fmt.Errorf("%w: item %d has only %d units pending", ErrInvalidRestockOrder, item.ItemID, remaining)
+		}
+	}
+	if len(received) == 0 {
+		return nil, fmt.Errorf("%w: nothing is pending", ErrInvalidRestockOrder)
+	}
+
+	applied, err := s.Repo.ReceiveRestockOrderItems(ctx, id, received, newStockMovement(ctx, config.STOCK_MOVEMENT_RESTOCK_ORDER),
+		RESTOCK_ORDER_STATE_SENT, RESTOCK_ORDER_STATE_RECEIVED)
+	if err != nil {
+		return nil, err
+	}
+	if !applied {
+		return nil, fmt.Errorf("%w: the order changed meanwhile, reload it and try again", ErrRestockOrderTransition)
+	}
+
+	for itemID, quantity := range received {
+		s.Bus.Publish(ctx, StockChanged{dtos.StockChangedEventDTO{ItemID: itemID, Change: quantity, Reason: config.STOCK_MOVEMENT_RESTOCK_ORDER}})
+	}
+	return s.Repo.GetRestockOrderByID(ctx, id)
+}
+
+func (s *RestockOrderService) changeState(ctx context.Context, id int, fromStates []string, state, timeColumn string) (*models.RestockOrder, error) {
+	changed, err := s.Repo.SetRestockOrderState(ctx, id, fromStates, state, timeColumn, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		order, err := s.Repo.GetRestockOrderByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: cannot go from %s to %s", ErrRestockOrderTransition, order.State, state)
+	}
+	return s.Repo.GetRestockOrderByID(ctx, id)
+}
+
+// fillRestockOrder copia dto al pedido y calcula el total; cada item debe existir y aparecer una vez.
+// Con supplier_id el nombre del proveedor se toma de él, que debe estar activo.
+func (s *RestockOrderService) fillRestockOrder(ctx context.Context, order *models.RestockOrder, dto dtos.RestockOrderDTO) error {
+	order.SupplierID = dto.SupplierID
+	order.SupplierName = strings.TrimSpace(dto.SupplierName)
+	supplier, err := s.Suppliers.CheckSupplier(ctx, dto.SupplierID)
+	if err != nil {
+		if errors.Is(err, ErrSupplierNotFound) {
+			return fmt.Errorf("%w: %s", ErrInvalidRestockOrder, err)
+		}
+		return err
+	}
+	if supplier != nil {
+		if !supplier.SupplierState {
+			return fmt.Errorf("%w: supplier %d is inactive", ErrInvalidRestockOrder, supplier.ID)
+		}
+		order.SupplierName = supplier.Name
+	}
+	if order.SupplierName == "" {
+		return fmt.Errorf("%w: supplier_id or supplier_name is required", ErrInvalidRestockOrder)
+	}
+	order.Notes = strings.TrimSpace(dto.Notes)
+	order.Items = make([]models.RestockOrderItem, 0, len(dto.Items))
+	order.Total = 0
+
+	seen := make(map[int]bool, len(dto.Items))
+	for _, line := range dto.Items {
+		if seen[line.ItemID] {
+			return fmt.Errorf("%w: item %d appears more than once", ErrInvalidRestockOrder, line.ItemID)
+		}
+		seen[line.ItemID] = true
+		if _, err := s.ItemRepo.GetItemByID(ctx, strconv.Itoa(line.ItemID)); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: item %d does not exist", ErrInvalidRestockOrder, line.ItemID)
+			}
+			return err
+		}
+		order.Items = append(order.Items, models.RestockOrderItem{ItemID: line.ItemID, Quantity: line.Quantity, UnitCost: line.UnitCost})
+		order.Total += float64(line.Quantity) * line.UnitCost
+	}
+	return nil
+}
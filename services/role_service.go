@@ -1,43 +1,166 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
 
+var (
+	ErrInvalidRole       = errors.New("invalid role")
+	ErrRoleNameTaken     = errors.New("a role with that name already exists")
+	ErrRoleInUse         = errors.New("role is in use")
+	ErrPermissionMissing = errors.New("permission not found")
+)
+
 type RoleService struct {
-	Repo *repositories.RoleRepository
+	Repo repositories.RoleRepositoryInterface
 }
 
-func NewRoleService(repo *repositories.RoleRepository) *RoleService {
+func NewRoleService(repo repositories.RoleRepositoryInterface) *RoleService {
 	return &RoleService{Repo: repo}
 }
 
-func (s *RoleService) GetAllRoles() ([]models.Role, error) {
-	return s.Repo.GetAllRoles()
+func (s *RoleService) GetAllRoles(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Role, int64, error) {
+	return s.Repo.GetAllRoles(ctx, pagination)
 }
 
-func (s *RoleService) GetRoleByID(id uint) (*models.Role, error) {
-	return s.Repo.GetRoleByID(id)
+func (s *RoleService) GetRoleByID(ctx context.Context, id uint) (*models.Role, error) {
+	return s.Repo.GetRoleByID(ctx, id)
 
 }
 
-func (s *RoleService) GetRolePermissions(id uint) ([]uint, error) {
-	return s.Repo.GetRolePermissions(id)
+func (s *RoleService) GetRolePermissions(ctx context.Context, id uint) ([]uint, error) {
+	return s.Repo.GetRolePermissions(ctx, id)
 }
 
-func (s *RoleService) GetAllPermissionsOfRole(id uint) ([]models.Permission, error) {
-	return s.Repo.GetAllPermissionsOfRole(id)
+func (s *RoleService) GetAllPermissionsOfRole(ctx context.Context, id uint, pagination dtos.PaginationDTO) ([]models.Permission, int64, error) {
+	return s.Repo.GetAllPermissionsOfRole(ctx, id, pagination)
 }
 
-func (s *RoleService) ExistRole(id uint) (bool, error) {
-	return s.Repo.ExistRole(id)
+func (s *RoleService) ExistRole(ctx context.Context, id uint) (bool, error) {
+	return s.Repo.ExistRole(ctx, id)
 }
 
-func (s *RoleService) SearchRolesByID(id string) ([]models.Role, error) {
-	return s.Repo.SearchRolesByID(id)
+func (s *RoleService) SearchRolesByID(ctx context.Context, id string, pagination dtos.PaginationDTO) ([]models.Role, int64, error) {
+	return s.Repo.SearchRolesByID(ctx, id, pagination)
 }
 
-func (s *RoleService) SearchRolesByName(name string) ([]models.Role, error) {
-	return s.Repo.SearchRolesByName(name)
+func (s *RoleService) SearchRolesByName(ctx context.Context, name string, pagination dtos.PaginationDTO) ([]models.Role, int64, error) {
+	return s.Repo.SearchRolesByName(ctx, name, pagination)
+}
+
+func (s *RoleService) CreateRole(ctx context.Context, dto dtos.CreateRoleDTO) (*models.Role, error) {
+	role := &models.Role{Name: strings.TrimSpace(dto.Name), Description: strings.TrimSpace(dto.Description)}
+	if err := s.checkRoleName(ctx, role); err != nil {
+		return nil, err
+	}
+	permissionIDs, err := s.checkPermissions(ctx, dto.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Repo.CreateRole(ctx, role, permissionIDs); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetRoleByID(ctx, role.ID)
+}
+
+func (s *RoleService) UpdateRole(ctx context.Context, id uint, dto dtos.UpdateRoleDTO) (*models.Role, error) {
+	role := &models.Role{ID: id, Name: strings.TrimSpace(dto.Name), Description: strings.TrimSpace(dto.Description)}
+	if err := s.checkRoleName(ctx, role); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.UpdateRole(ctx, role); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetRoleByID(ctx, id)
+}
+
+// UpdateRolePermissions deja al rol exactamente con permissionIDs; si alguno no existe no cambia nada.
+func (s *RoleService) UpdateRolePermissions(ctx context.Context, id uint, permissionIDs []uint) (*models.Role, error) {
+	permissionIDs, err := s.checkPermissions(ctx, permissionIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Repo.SetRolePermissions(ctx, id, permissionIDs); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetRoleByID(ctx, id)
+}
+
+// DeleteRole solo borra roles que ningún tipo de usuario tiene asignados.
+func (s *RoleService) DeleteRole(ctx context.Context, id uint) error {
+	usage, err := s.Repo.DeleteRole(ctx, id)
+	if err != nil {
+		return err
+	}
+	if usage > 0 {
+		return fmt.Errorf("%w by %d user types; remove it from them first", ErrRoleInUse, usage)
+	}
+	return nil
+}
+
+// checkRoleName exige un nombre que no tenga otro rol.
+func (s *RoleService) checkRoleName(ctx context.Context, role *models.Role) error {
+	if role.Name == "" {
+		return fmt.Errorf("%w: name cannot be blank", ErrInvalidRole)
+	}
+	existing, err := s.Repo.GetRoleByName(ctx, role.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != role.ID {
+		return ErrRoleNameTaken
+	}
+	return nil
+}
+
+// checkPermissions quita los IDs repetidos y verifica que todos los permisos existan.
+func (s *RoleService) checkPermissions(ctx context.Context, permissionIDs []uint) ([]uint, error) {
+	unique, missing, err := findMissingIDs(ctx, permissionIDs, s.Repo.GetExistingPermissionIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrPermissionMissing, strings.Join(missing, ", "))
+	}
+	return unique, nil
+}
+
+// findMissingIDs quita los repetidos de ids y los devuelve ordenados junto con los que existing no
+// encuentra.
+func findMissingIDs(ctx context.Context, ids []uint, existing func(context.Context, []uint) ([]uint, error)) ([]uint, []string, error) {
+	unique := make([]uint, 0, len(ids))
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) == 0 {
+		return unique, nil, nil
+	}
+	sort.Slice(unique, func(i, j int) bool { return unique[i] < unique[j] })
+
+	found, err := existing(ctx, unique)
+	if err != nil {
+		return nil, nil, err
+	}
+	exists := make(map[uint]bool, len(found))
+	for _, id := range found {
+		exists[id] = true
+	}
+	var missing []string
+	for _, id := range unique {
+		if !exists[id] {
+			missing = append(missing, fmt.Sprintf("%d", id))
+		}
+	}
+	return unique, missing, nil
 }
@@ -1,22 +1,218 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
 	"time"
+	"totesbackend/config"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
 
+var ErrInvalidReportParams = errors.New("invalid report parameters")
+
 type SalesReportService struct {
-	InvoiceRepo *repositories.InvoiceRepository
+	InvoiceRepo repositories.InvoiceRepositoryInterface
 }
 
-func NewSalesReportService(invoiceRepo *repositories.InvoiceRepository) *SalesReportService {
+func NewSalesReportService(invoiceRepo repositories.InvoiceRepositoryInterface) *SalesReportService {
 	return &SalesReportService{
 		InvoiceRepo: invoiceRepo,
 	}
 }
 
 // GetInvoicesBetweenDates devuelve las facturas registradas en un rango de fechas.
-func (s *SalesReportService) GetInvoicesBetweenDates(startDate, endDate time.Time) ([]models.Invoice, error) {
-	return s.InvoiceRepo.GetInvoicesByDateRange(startDate, endDate)
+func (s *SalesReportService) GetInvoicesBetweenDates(ctx context.Context, startDate, endDate time.Time) ([]models.Invoice, error) {
+	return s.InvoiceRepo.GetInvoicesByDateRange(ctx, startDate, endDate)
+}
+
+// StreamInvoicesBetweenDates recorre las facturas del rango sin cargarlas todas en memoria.
+func (s *SalesReportService) StreamInvoicesBetweenDates(ctx context.Context, startDate, endDate time.Time, fn func(models.Invoice) error) error {
+	return s.InvoiceRepo.StreamInvoicesByDateRange(ctx, startDate, endDate, fn)
+}
+
+// GetSalesSummary devuelve las cifras de ventas del rango agrupadas por día, semana o mes.
+func (s *SalesReportService) GetSalesSummary(ctx context.Context, from, to time.Time, groupBy string) (*dtos.SalesSummaryReportDTO, error) {
+	if groupBy != "day" && groupBy != "week" && groupBy != "month" {
+		return nil, fmt.Errorf("%w: groupBy must be day, week or month", ErrInvalidReportParams)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: 'to' date must be after 'from' date", ErrInvalidReportParams)
+	}
+
+	periods, err := s.InvoiceRepo.GetSalesSummaryByPeriod(ctx, from, to, groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dtos.SalesSummaryReportDTO{
+		From:    from,
+		To:      to,
+		GroupBy: groupBy,
+		Periods: periods,
+	}
+	for _, period := range periods {
+		report.Totals.InvoiceCount += period.InvoiceCount
+		report.Totals.Subtotal += period.Subtotal
+		report.Totals.Tax += period.Tax
+		report.Totals.Discount += period.Discount
+		report.Totals.Total += period.Total
+	}
+
+	return report, nil
+}
+
+// GetMarginReport calcula el margen bruto de las líneas de factura del rango agrupado por
+// item, categoría o periodo (day, week o month). El costo por unidad es el precio de compra
+// más la parte de los gastos adicionales del item que le toca a cada unidad comprada.
+func (s *SalesReportService) GetMarginReport(ctx context.Context, from, to time.Time, groupBy string) (*dtos.MarginReportDTO, error) {
+	switch groupBy {
+	case "item", "category", "day", "week", "month":
+	default:
+		return nil, fmt.Errorf("%w: groupBy must be item, category, day, week or month", ErrInvalidReportParams)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: 'to' date must be after 'from' date", ErrInvalidReportParams)
+	}
+
+	lines, err := s.InvoiceRepo.GetInvoiceLineCosts(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dtos.MarginReportDTO{
+		From:    from,
+		To:      to,
+		GroupBy: groupBy,
+		Rows:    []dtos.MarginRowDTO{},
+	}
+	indexByKey := make(map[string]int)
+	for _, line := range lines {
+		key, label := marginGroupKey(line, groupBy)
+		i, ok := indexByKey[key]
+		if !ok {
+			report.Rows = append(report.Rows, dtos.MarginRowDTO{Key: key, Label: label})
+			i = len(report.Rows) - 1
+			indexByKey[key] = i
+		}
+
+		revenue := line.UnitPrice * float64(line.Amount)
+		cost := (line.UnitPurchasePrice + line.UnitExpenses) * float64(line.Amount)
+
+		report.Rows[i].UnitsSold += line.Amount
+		report.Rows[i].Revenue += revenue
+		report.Rows[i].Cost += cost
+		report.Totals.UnitsSold += line.Amount
+		report.Totals.Revenue += revenue
+		report.Totals.Cost += cost
+	}
+
+	for i := range report.Rows {
+		fillMargin(&report.Rows[i])
+	}
+	report.Totals.Key = "total"
+	report.Totals.Label = "Total"
+	fillMargin(&report.Totals)
+
+	return report, nil
+}
+
+// GetDiscountUsageReport resume cuánto se descontó con cada tipo de descuento en el rango.
+func (s *SalesReportService) GetDiscountUsageReport(ctx context.Context, from, to time.Time) (*dtos.DiscountUsageReportDTO, error) {
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: 'to' date must be after 'from' date", ErrInvalidReportParams)
+	}
+
+	usage, err := s.InvoiceRepo.GetDiscountUsage(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dtos.DiscountUsageReportDTO{
+		From:          from,
+		To:            to,
+		DiscountTypes: usage,
+	}
+	for _, discount := range usage {
+		report.TotalDiscounted += discount.Discounted
+	}
+
+	return report, nil
+}
+
+// GetTopItemsReport devuelve los limit items más vendidos del rango, por unidades o por ingresos.
+func (s *SalesReportService) GetTopItemsReport(ctx context.Context, from, to time.Time, orderBy string, limit int) (*dtos.TopItemsReportDTO, error) {
+	if orderBy != "units" && orderBy != "revenue" {
+		return nil, fmt.Errorf("%w: orderBy must be units or revenue", ErrInvalidReportParams)
+	}
+	if err := checkRankingParams(from, to, limit); err != nil {
+		return nil, err
+	}
+
+	items, err := s.InvoiceRepo.GetTopItems(ctx, from, to, orderBy, limit)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []dtos.TopItemDTO{}
+	}
+
+	return &dtos.TopItemsReportDTO{From: from, To: to, OrderBy: orderBy, Items: items}, nil
+}
+
+// GetRevenueByCustomerReport devuelve los limit clientes que más facturaron en el rango.
+func (s *SalesReportService) GetRevenueByCustomerReport(ctx context.Context, from, to time.Time, limit int) (*dtos.RevenueByCustomerReportDTO, error) {
+	if err := checkRankingParams(from, to, limit); err != nil {
+		return nil, err
+	}
+
+	customers, err := s.InvoiceRepo.GetRevenueByCustomer(ctx, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	if customers == nil {
+		customers = []dtos.CustomerRevenueDTO{}
+	}
+
+	return &dtos.RevenueByCustomerReportDTO{From: from, To: to, Customers: customers}, nil
+}
+
+func checkRankingParams(from, to time.Time, limit int) error {
+	if to.Before(from) {
+		return fmt.Errorf("%w: 'to' date must be after 'from' date", ErrInvalidReportParams)
+	}
+	if limit < 1 || limit > config.REPORT_RANKING_MAX_LIMIT {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidReportParams, config.REPORT_RANKING_MAX_LIMIT)
+	}
+	return nil
+}
+
+func marginGroupKey(line repositories.InvoiceLineCost, groupBy string) (string, string) {
+	switch groupBy {
+	case "item":
+		return strconv.Itoa(line.ItemID), line.ItemName
+	case "category":
+		return strconv.Itoa(line.CategoryID), line.CategoryName
+	}
+
+	t := line.DateTime
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch groupBy {
+	case "week":
+		day = day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case "month":
+		day = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	}
+	period := day.Format("2006-01-02")
+	return period, period
+}
+
+func fillMargin(row *dtos.MarginRowDTO) {
+	row.Margin = row.Revenue - row.Cost
+	if row.Revenue != 0 {
+		row.MarginPct = row.Margin / row.Revenue * 100
+	}
 }
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"context"
+	"totesbackend/config"
+	"totesbackend/database"
+
+	"gorm.io/gorm"
+)
+
+// SandboxService reinicia los datos del modo demo. Solo se crea con SANDBOX_MODE activo, cuando la
+// conexión ya trabaja sobre el esquema del sandbox.
+type SandboxService struct {
+	DB            *gorm.DB
+	AdminEmail    string
+	AdminPassword string
+}
+
+func NewSandboxService(db *gorm.DB, cfg config.SandboxConfig) *SandboxService {
+	return &SandboxService{DB: db, AdminEmail: cfg.AdminEmail, AdminPassword: cfg.AdminPassword}
+}
+
+func (s *SandboxService) Reset(ctx context.Context) error {
+	return database.ResetSandbox(s.DB.WithContext(ctx), s.AdminEmail, s.AdminPassword)
+}
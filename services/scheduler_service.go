@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+	"totesbackend/config"
+	"totesbackend/models"
+	"totesbackend/repositories"
+	"totesbackend/services/utils"
+)
+
+const (
+	JOB_STATUS_SUCCEEDED = "succeeded"
+	JOB_STATUS_FAILED    = "failed"
+)
+
+// JobFunc es el trabajo de una corrida; el texto devuelto queda como resultado de la corrida.
+type JobFunc func(ctx context.Context) (string, error)
+
+type scheduledJob struct {
+	name     string
+	spec     string
+	schedule *utils.CronSchedule
+	run      JobFunc
+}
+
+// SchedulerService ejecuta trabajos periódicos con expresiones cron. Con varias instancias cada
+// corrida la ejecuta una sola: la que logra reclamarla en la tabla scheduled_jobs.
+type SchedulerService struct {
+	Repo     repositories.ScheduledJobRepositoryInterface
+	instance string
+	jobs     []*scheduledJob
+
+	mu      sync.Mutex
+	running map[string]bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+func NewSchedulerService(repo repositories.ScheduledJobRepositoryInterface) *SchedulerService {
+	hostname, _ := os.Hostname()
+	ctx, cancel := context.WithCancel(context.Background())
+	return &SchedulerService{
+		Repo:     repo,
+		instance: fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		running:  make(map[string]bool),
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+}
+
+// Register agrega un trabajo; debe llamarse antes de Start.
+func (s *SchedulerService) Register(name, spec string, run JobFunc) error {
+	schedule, err := utils.ParseCron(spec)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", name, err)
+	}
+	if schedule.Next(time.Now()).IsZero() {
+		return fmt.Errorf("job %s: schedule %q never runs", name, spec)
+	}
+	s.jobs = append(s.jobs, &scheduledJob{name: name, spec: spec, schedule: schedule, run: run})
+	return nil
+}
+
+func (s *SchedulerService) Start() error {
+	now := time.Now()
+	for _, job := range s.jobs {
+		if err := s.Repo.EnsureJob(s.ctx, job.name, job.spec, job.schedule.Next(now)); err != nil {
+			return fmt.Errorf("job %s: %w", job.name, err)
+		}
+	}
+	go s.loop()
+	return nil
+}
+
+// Close deja de programar corridas, cancela las que están en curso y espera a que terminen.
+func (s *SchedulerService) Close() {
+	s.cancel()
+	<-s.done
+	s.wg.Wait()
+}
+
+func (s *SchedulerService) GetJobs(ctx context.Context) ([]models.ScheduledJob, error) {
+	return s.Repo.GetAllJobs(ctx)
+}
+
+func (s *SchedulerService) loop() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(config.SCHEDULER_POLL_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		s.runDue()
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *SchedulerService) runDue() {
+	now := time.Now()
+	for _, job := range s.jobs {
+		s.mu.Lock()
+		busy := s.running[job.name]
+		s.mu.Unlock()
+		if busy {
+			continue
+		}
+
+		claimed, err := s.Repo.ClaimJob(s.ctx, job.name, now, job.schedule.Next(now), s.instance)
+		if err != nil {
+			log.Printf("error claiming scheduled job %s: %v", job.name, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		s.mu.Lock()
+		s.running[job.name] = true
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go s.execute(job)
+	}
+}
+
+func (s *SchedulerService) execute(job *scheduledJob) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, job.name)
+		s.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(s.ctx, config.SCHEDULER_JOB_TIMEOUT)
+	defer cancel()
+
+	start := time.Now()
+	result, err := runJob(ctx, job.run)
+	status := JOB_STATUS_SUCCEEDED
+	if err != nil {
+		status = JOB_STATUS_FAILED
+		result = err.Error()
+		log.Printf("scheduled job %s failed: %v", job.name, err)
+	}
+	if len(result) > 500 {
+		result = result[:500]
+	}
+
+	finished := time.Now()
+	// el resultado se guarda aunque el servidor se esté apagando
+	if err := s.Repo.FinishJob(context.Background(), job.name, status, result, finished, finished.Sub(start)); err != nil {
+		log.Printf("error saving result of scheduled job %s: %v", job.name, err)
+	}
+}
+
+// runJob evita que un panic en un trabajo tire abajo el servidor.
+func runJob(ctx context.Context, run JobFunc) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return run(ctx)
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+)
+
+const (
+	SECURITY_EVENT_LOGIN_SUCCESS     = "login_success"
+	SECURITY_EVENT_LOGIN_FAILURE     = "login_failure"
+	SECURITY_EVENT_PERMISSION_DENIED = "permission_denied"
+	SECURITY_EVENT_ROLE_CHANGE       = "role_change"
+	SECURITY_EVENT_PASSWORD_CHANGE   = "password_change"
+	// se usó un token de refresco ya revocado: se cierran todas las sesiones del usuario
+	SECURITY_EVENT_TOKEN_REUSE = "token_reuse"
+)
+
+var securityEventTypes = map[string]bool{
+	SECURITY_EVENT_LOGIN_SUCCESS:     true,
+	SECURITY_EVENT_LOGIN_FAILURE:     true,
+	SECURITY_EVENT_PERMISSION_DENIED: true,
+	SECURITY_EVENT_ROLE_CHANGE:       true,
+	SECURITY_EVENT_PASSWORD_CHANGE:   true,
+	SECURITY_EVENT_TOKEN_REUSE:       true,
+}
+
+type SecurityEventService struct {
+	Repo repositories.SecurityEventRepositoryInterface
+}
+
+func NewSecurityEventService(repo repositories.SecurityEventRepositoryInterface) *SecurityEventService {
+	return &SecurityEventService{Repo: repo}
+}
+
+// RecordSecurityEvent agrega el evento a la cadena. Cada evento guarda el hash del anterior,
+// así que modificar o borrar un evento intermedio rompe la verificación de la cadena.
+func (s *SecurityEventService) RecordSecurityEvent(ctx context.Context, eventType, userEmail, detail, ipAddress string) error {
+	if !securityEventTypes[eventType] {
+		return fmt.Errorf("unknown security event type '%s'", eventType)
+	}
+
+	event := &models.SecurityEvent{
+		EventType: eventType,
+		UserEmail: userEmail,
+		Detail:    detail,
+		IPAddress: ipAddress,
+		// Postgres guarda microsegundos; se trunca para que el hash coincida al releer
+		DateTime:  time.Now().UTC().Truncate(time.Microsecond),
+		RequestID: RequestIDFromContext(ctx),
+	}
+
+	return s.Repo.AppendSecurityEvent(ctx, event, func(prevHash string) string {
+		return securityEventHash(prevHash, event)
+	})
+}
+
+func (s *SecurityEventService) SearchSecurityEvents(ctx context.Context, filter dtos.SecurityEventFilterDTO) (*dtos.PageDTO[models.SecurityEvent], error) {
+	if filter.EventType != "" && !securityEventTypes[filter.EventType] {
+		return nil, fmt.Errorf("%w: unknown event type '%s'", ErrInvalidLogFilter, filter.EventType)
+	}
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, fmt.Errorf("%w: 'to' date must be after 'from' date", ErrInvalidLogFilter)
+	}
+
+	events, total, err := s.Repo.SearchSecurityEvents(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return dtos.NewPageDTO(events, filter.PaginationDTO, total), nil
+}
+
+// VerifySecurityEventChain recalcula los hashes de toda la cadena. El primer evento que queda
+// tras una purga por retención se toma como ancla.
+func (s *SecurityEventService) VerifySecurityEventChain(ctx context.Context) (*dtos.SecurityEventChainDTO, error) {
+	result := &dtos.SecurityEventChainDTO{Valid: true}
+	prevHash := ""
+	first := true
+
+	err := s.Repo.StreamSecurityEvents(ctx, func(event models.SecurityEvent) error {
+		if !result.Valid {
+			return nil
+		}
+		if first {
+			prevHash = event.PrevHash
+			first = false
+		}
+		result.CheckedEvents++
+		if event.PrevHash != prevHash || securityEventHash(event.PrevHash, &event) != event.Hash {
+			id := event.ID
+			result.Valid = false
+			result.BrokenAtID = &id
+			return nil
+		}
+		prevHash = event.Hash
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// PurgeExpiredSecurityEvents borra solo los eventos más antiguos que el periodo de retención.
+func (s *SecurityEventService) PurgeExpiredSecurityEvents(ctx context.Context, retentionDays int) (int64, error) {
+	return s.Repo.DeleteSecurityEventsBefore(ctx, time.Now().AddDate(0, 0, -retentionDays))
+}
+
+func securityEventHash(prevHash string, event *models.SecurityEvent) string {
+	payload := strings.Join([]string{
+		prevHash,
+		event.EventType,
+		event.UserEmail,
+		event.Detail,
+		event.IPAddress,
+		event.DateTime.UTC().Format(time.RFC3339Nano),
+	}, "|")
+	sum := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+)
+
+// StockService consulta el libro de movimientos de stock. Los movimientos los escriben los
+// repositorios de items, facturas y ventas externas en la misma transacción que cambia el stock.
+type StockService struct {
+	Repo repositories.StockMovementRepositoryInterface
+}
+
+func NewStockService(repo repositories.StockMovementRepositoryInterface) *StockService {
+	return &StockService{Repo: repo}
+}
+
+func (s *StockService) GetStockMovements(ctx context.Context, filter dtos.StockMovementFilterDTO) ([]models.StockMovement, int64, error) {
+	return s.Repo.GetStockMovements(ctx, filter)
+}
+
+// GetStockBalance calcula el stock del item a partir de sus movimientos.
+func (s *StockService) GetStockBalance(ctx context.Context, itemID int) (*dtos.StockBalanceDTO, error) {
+	return s.Repo.GetStockBalance(ctx, itemID)
+}
+
+func (s *StockService) GetStockDiscrepancies(ctx context.Context) ([]dtos.StockBalanceDTO, error) {
+	return s.Repo.GetStockDiscrepancies(ctx)
+}
+
+// newStockMovement arma la plantilla de un movimiento con el usuario y la petición de ctx; el
+// repositorio completa el item, la cantidad y el saldo.
+func newStockMovement(ctx context.Context, reason string) models.StockMovement {
+	return models.StockMovement{
+		Reason:    reason,
+		UserEmail: UserFromContext(ctx),
+		RequestID: RequestIDFromContext(ctx),
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidSupplier    = errors.New("invalid supplier")
+	ErrSupplierTaxIDTaken = errors.New("a supplier with that tax ID already exists")
+	ErrSupplierInUse      = errors.New("supplier is in use")
+	ErrSupplierNotFound   = errors.New("supplier not found")
+)
+
+type SupplierService struct {
+	Repo repositories.SupplierRepositoryInterface
+}
+
+func NewSupplierService(repo repositories.SupplierRepositoryInterface) *SupplierService {
+	return &SupplierService{Repo: repo}
+}
+
+func (s *SupplierService) GetAllSuppliers(ctx context.Context, pagination dtos.PaginationDTO) ([]models.Supplier, int64, error) {
+	return s.Repo.GetAllSuppliers(ctx, pagination)
+}
+
+func (s *SupplierService) GetSupplierByID(ctx context.Context, id int) (*models.Supplier, error) {
+	return s.Repo.GetSupplierByID(ctx, id)
+}
+
+func (s *SupplierService) SearchSuppliersByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.Supplier, int64, error) {
+	return s.Repo.SearchSuppliersByID(ctx, normalizeTaxID(query), pagination)
+}
+
+func (s *SupplierService) SearchSuppliersByName(ctx context.Context, name string, pagination dtos.PaginationDTO) ([]models.Supplier, int64, error) {
+	return s.Repo.SearchSuppliersByName(ctx, name, pagination)
+}
+
+func (s *SupplierService) CreateSupplier(ctx context.Context, dto dtos.SupplierDTO) (*models.Supplier, error) {
+	supplier := &models.Supplier{SupplierState: true}
+	if err := s.fillSupplier(ctx, supplier, dto); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.CreateSupplier(ctx, supplier); err != nil {
+		return nil, err
+	}
+	return supplier, nil
+}
+
+func (s *SupplierService) UpdateSupplier(ctx context.Context, id int, dto dtos.SupplierDTO) (*models.Supplier, error) {
+	supplier, err := s.Repo.GetSupplierByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fillSupplier(ctx, supplier, dto); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.UpdateSupplier(ctx, supplier); err != nil {
+		return nil, err
+	}
+	return supplier, nil
+}
+
+// DeleteSupplier solo borra proveedores sin gastos ni pedidos; los demás se desactivan.
+func (s *SupplierService) DeleteSupplier(ctx context.Context, id int) error {
+	usage, err := s.Repo.CountSupplierUsage(ctx, id)
+	if err != nil {
+		return err
+	}
+	if usage > 0 {
+		return fmt.Errorf("%w by %d additional expenses or restock orders; deactivate it instead", ErrSupplierInUse, usage)
+	}
+	return s.Repo.DeleteSupplier(ctx, id)
+}
+
+// CheckSupplier verifica que el proveedor exista; nil no referencia ninguno.
+func (s *SupplierService) CheckSupplier(ctx context.Context, id *int) (*models.Supplier, error) {
+	if id == nil {
+		return nil, nil
+	}
+	supplier, err := s.Repo.GetSupplierByID(ctx, *id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: %d", ErrSupplierNotFound, *id)
+	}
+	return supplier, err
+}
+
+// fillSupplier copia dto al proveedor; el NIT no puede repetirse con otro proveedor.
+func (s *SupplierService) fillSupplier(ctx context.Context, supplier *models.Supplier, dto dtos.SupplierDTO) error {
+	supplier.Name = strings.TrimSpace(dto.Name)
+	supplier.TaxID = normalizeTaxID(dto.TaxID)
+	if supplier.Name == "" || supplier.TaxID == "" {
+		return fmt.Errorf("%w: name and tax_id cannot be blank", ErrInvalidSupplier)
+	}
+	supplier.ContactName = strings.TrimSpace(dto.ContactName)
+	supplier.Email = strings.TrimSpace(dto.Email)
+	supplier.PhoneNumbers = strings.TrimSpace(dto.PhoneNumbers)
+	supplier.Address = strings.TrimSpace(dto.Address)
+	supplier.PaymentTermDays = dto.PaymentTermDays
+	if dto.SupplierState != nil {
+		supplier.SupplierState = *dto.SupplierState
+	}
+
+	existing, err := s.Repo.GetSupplierByTaxID(ctx, supplier.TaxID)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != supplier.ID {
+		return ErrSupplierTaxIDTaken
+	}
+	return nil
+}
+
+// normalizeTaxID quita los espacios y pasa las letras a mayúsculas.
+func normalizeTaxID(taxID string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(taxID), ""))
+}
@@ -1,26 +1,110 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
 
+var (
+	ErrInvalidTaxType  = errors.New("invalid tax type")
+	ErrTaxTypeInactive = errors.New("tax type is inactive")
+)
+
 type TaxTypeService struct {
-	Repo *repositories.TaxTypeRepository
+	Repo repositories.TaxTypeRepositoryInterface
 }
 
-func NewTaxTypeService(repo *repositories.TaxTypeRepository) *TaxTypeService {
+func NewTaxTypeService(repo repositories.TaxTypeRepositoryInterface) *TaxTypeService {
 	return &TaxTypeService{Repo: repo}
 }
 
-func (s *TaxTypeService) GetAllTaxTypes() ([]models.TaxType, error) {
-	return s.Repo.GetAllTaxTypes()
+func (s *TaxTypeService) GetAllTaxTypes(ctx context.Context, pagination dtos.PaginationDTO) ([]models.TaxType, int64, error) {
+	return s.Repo.GetAllTaxTypes(ctx, pagination)
+}
+
+func (s *TaxTypeService) GetTaxTypeByID(ctx context.Context, id string) (*models.TaxType, error) {
+	return s.Repo.GetTaxTypeByID(ctx, id)
+}
+
+func (s *TaxTypeService) CreateTaxType(ctx context.Context, dto dtos.TaxTypeDTO) (*models.TaxType, error) {
+	taxType := &models.TaxType{}
+	if err := fillTaxType(taxType, dto); err != nil {
+		return nil, err
+	}
+	taxType.Active = true
+	if err := s.Repo.CreateTaxType(ctx, taxType); err != nil {
+		return nil, err
+	}
+	return taxType, nil
+}
+
+// UpdateTaxType cambia el impuesto para las facturas y cotizaciones nuevas; si cambia la tarifa, la
+// anterior queda en el historial y las facturas ya emitidas la conservan.
+func (s *TaxTypeService) UpdateTaxType(ctx context.Context, id string, dto dtos.TaxTypeDTO) (*models.TaxType, error) {
+	taxType, err := s.Repo.GetTaxTypeByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := fillTaxType(taxType, dto); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.UpdateTaxType(ctx, taxType); err != nil {
+		return nil, err
+	}
+	return taxType, nil
+}
+
+func (s *TaxTypeService) DeactivateTaxType(ctx context.Context, id string) (*models.TaxType, error) {
+	if err := s.Repo.DeactivateTaxType(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetTaxTypeByID(ctx, id)
 }
 
-func (s *TaxTypeService) GetTaxTypeByID(id string) (*models.TaxType, error) {
-	return s.Repo.GetTaxTypeByID(id)
+func (s *TaxTypeService) GetTaxTypeRates(ctx context.Context, id string) ([]models.TaxTypeRate, error) {
+	if _, err := s.Repo.GetTaxTypeByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetTaxTypeRates(ctx, id)
+}
+
+func (s *TaxTypeService) ImportTaxTypes(ctx context.Context, entries []dtos.ImportCatalogEntryDTO) ([]models.TaxType, error) {
+	if err := validateCatalogEntries(entries); err != nil {
+		return nil, err
+	}
+
+	taxTypes := make([]models.TaxType, len(entries))
+	for i, entry := range entries {
+		taxTypes[i] = models.TaxType{
+			Name:         strings.TrimSpace(entry.Name),
+			Description:  entry.Description,
+			IsPercentage: entry.IsPercentage,
+			Value:        entry.Value,
+			Active:       true,
+		}
+	}
+
+	if err := s.Repo.CreateTaxTypes(ctx, taxTypes); err != nil {
+		return nil, err
+	}
+	return taxTypes, nil
 }
 
-func (s *TaxTypeService) CreateTaxType(taxType *models.TaxType) error {
-	return s.Repo.CreateTaxType(taxType)
+func fillTaxType(taxType *models.TaxType, dto dtos.TaxTypeDTO) error {
+	taxType.Name = strings.TrimSpace(dto.Name)
+	taxType.Description = strings.TrimSpace(dto.Description)
+	taxType.IsPercentage = dto.IsPercentage
+	taxType.Value = dto.Value
+	if dto.Active != nil {
+		taxType.Active = *dto.Active
+	}
+	if taxType.Name == "" {
+		return fmt.Errorf("%w: the name cannot be blank", ErrInvalidTaxType)
+	}
+	return nil
 }
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"totesbackend/config"
+	"totesbackend/models"
+	"totesbackend/notifications"
+	"totesbackend/repositories"
+)
+
+const (
+	TEXT_STATUS_PENDING = "pending"
+	TEXT_STATUS_SENT    = "sent"
+	TEXT_STATUS_FAILED  = "failed"
+)
+
+// TextMessageService envía avisos por SMS o WhatsApp a los clientes que eligieron un canal en su
+// ficha (Customer.TextChannel). Los mensajes se encolan en text_messages y un worker los envía y
+// reintenta igual que los correos. En las preferencias de notificación ambos canales son "sms".
+type TextMessageService struct {
+	Repo        repositories.TextMessageRepositoryInterface
+	Customers   repositories.CustomerRepositoryInterface
+	Sender      notifications.TextSender
+	Preferences *NotificationPreferenceService
+	// CountryCode se antepone a los teléfonos guardados sin indicativo (TEXT_DEFAULT_COUNTRY_CODE)
+	CountryCode string
+	wake        chan struct{}
+	stop        chan struct{}
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+func NewTextMessageService(repo repositories.TextMessageRepositoryInterface, customers repositories.CustomerRepositoryInterface,
+	sender notifications.TextSender, countryCode string) *TextMessageService {
+	s := &TextMessageService{
+		Repo:        repo,
+		Customers:   customers,
+		Sender:      sender,
+		CountryCode: countryCode,
+		wake:        make(chan struct{}, 1),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Notify encola el mensaje del evento para el cliente si eligió un canal, tiene un teléfono válido y
+// no desactivó el evento; si no, devuelve nil sin error. Como con los correos, quien no necesita el
+// resultado puede ignorar el error.
+func (s *TextMessageService) Notify(ctx context.Context, customerID int, eventType, template string, data interface{}) (*models.TextMessage, error) {
+	if s == nil || customerID == 0 {
+		return nil, nil
+	}
+
+	// la operación que origina el mensaje ya se hizo: se encola aunque el cliente se desconecte
+	ctx = context.WithoutCancel(ctx)
+	customer, err := s.Customers.GetCustomerByID(ctx, customerID)
+	if err != nil {
+		log.Printf("error loading customer %d for %s text message: %v", customerID, template, err)
+		return nil, err
+	}
+	if customer.TextChannel == "" {
+		return nil, nil
+	}
+	to := s.phoneNumber(customer.PhoneNumbers)
+	if to == "" {
+		return nil, nil
+	}
+	if s.Preferences != nil && !s.Preferences.Allows(ctx, NOTIFICATION_RECIPIENT_CUSTOMER, customer.ID, eventType, NOTIFICATION_CHANNEL_SMS) {
+		return nil, nil
+	}
+
+	body, err := notifications.RenderText(template, data)
+	if err != nil {
+		log.Printf("error rendering %s text message: %v", template, err)
+		return nil, err
+	}
+	message := &models.TextMessage{
+		Channel:       customer.TextChannel,
+		To:            to,
+		CustomerID:    customer.ID,
+		Template:      template,
+		Body:          body,
+		Status:        TEXT_STATUS_PENDING,
+		NextAttemptAt: time.Now(),
+		RequestID:     RequestIDFromContext(ctx),
+	}
+	if err := s.Repo.CreateMessage(ctx, message); err != nil {
+		log.Printf("error queuing %s text message to customer %d: %v", template, customer.ID, err)
+		return nil, err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return message, nil
+}
+
+// phoneNumber devuelve en formato E.164 el primero de los teléfonos del cliente (separados por comas,
+// punto y coma o barras) que parezca válido; vacío si ninguno lo es.
+func (s *TextMessageService) phoneNumber(phoneNumbers string) string {
+	for _, phone := range strings.FieldsFunc(phoneNumbers, func(r rune) bool { return r == ',' || r == ';' || r == '/' }) {
+		phone = strings.TrimSpace(phone)
+		international := strings.HasPrefix(phone, "+")
+		digits := strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, phone)
+		if !international {
+			digits = s.CountryCode + strings.TrimLeft(digits, "0")
+		}
+		if len(digits) >= 8 && len(digits) <= 15 && digits[0] != '0' {
+			return "+" + digits
+		}
+	}
+	return ""
+}
+
+// Close detiene el envío; los mensajes pendientes quedan guardados y se envían al reiniciar.
+func (s *TextMessageService) Close() {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+}
+
+func (s *TextMessageService) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(config.TEXT_POLL_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+		s.sendDue()
+	}
+}
+
+func (s *TextMessageService) sendDue() {
+	// corre en segundo plano, fuera de cualquier petición
+	ctx := context.Background()
+	now := time.Now()
+	messages, err := s.Repo.ClaimDueMessages(ctx, TEXT_STATUS_PENDING, now, now.Add(config.TEXT_SEND_LEASE), config.TEXT_BATCH_SIZE)
+	if err != nil {
+		log.Printf("error loading pending text messages: %v", err)
+		return
+	}
+
+	for i := range messages {
+		message := &messages[i]
+		delivery := s.attempt(ctx, message)
+		if err := s.Repo.SaveAttempt(ctx, message, delivery); err != nil {
+			log.Printf("error updating text message %d: %v", message.ID, err)
+		}
+	}
+}
+
+// attempt envía el mensaje, actualiza su estado y devuelve el registro del intento.
+func (s *TextMessageService) attempt(ctx context.Context, message *models.TextMessage) *models.NotificationDelivery {
+	response, err := s.Sender.Send(ctx, notifications.TextMessage{Channel: message.Channel, To: message.To, Body: message.Body})
+
+	now := time.Now()
+	message.Attempts++
+	if len(response) > 255 {
+		response = response[:255]
+	}
+	message.ProviderResponse = response
+	delivery := &models.NotificationDelivery{
+		Channel:          message.Channel,
+		MessageID:        message.ID,
+		Recipient:        message.To,
+		Template:         message.Template,
+		Attempt:          message.Attempts,
+		Status:           NOTIFICATION_DELIVERY_SENT,
+		ProviderResponse: response,
+		RequestID:        message.RequestID,
+	}
+	if err == nil {
+		message.Status = TEXT_STATUS_SENT
+		message.LastError = ""
+		message.SentAt = &now
+		return delivery
+	}
+
+	message.LastError = err.Error()
+	if len(message.LastError) > 500 {
+		message.LastError = message.LastError[:500]
+	}
+	delivery.Status = NOTIFICATION_DELIVERY_FAILED
+	delivery.Error = message.LastError
+	log.Printf("%s message %d to %s failed (attempt %d): %v", message.Channel, message.ID, message.To, message.Attempts, err)
+	if message.Attempts >= config.TEXT_MAX_ATTEMPTS {
+		message.Status = TEXT_STATUS_FAILED
+		return delivery
+	}
+	message.NextAttemptAt = now.Add(emailRetryDelay(message.Attempts))
+	return delivery
+}
+
+// Requeue vuelve a encolar un mensaje que agotó sus intentos. Devuelve false si no está fallido.
+func (s *TextMessageService) Requeue(ctx context.Context, messageID int) (bool, error) {
+	requeued, err := s.Repo.RequeueMessage(ctx, messageID, TEXT_STATUS_FAILED, TEXT_STATUS_PENDING, time.Now())
+	if err != nil || !requeued {
+		return requeued, err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return true, nil
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"totesbackend/config"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+
+	"gorm.io/gorm"
+)
+
+const tokenIssuer = "totesbackend"
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// jwtHeader es fijo: solo se emiten y aceptan tokens HS256.
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// AccessClaims son los datos del token de acceso. Subject es el ID del usuario.
+type AccessClaims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	Email     string `json:"email"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// TokenService emite las sesiones: un token de acceso JWT firmado con HS256, que se valida sin ir
+// a la base, y un token de refresco opaco que se guarda (su hash) y se puede revocar.
+type TokenService struct {
+	Repo       repositories.RefreshTokenRepositoryInterface
+	Users      repositories.UserRepositoryInterface
+	Security   *SecurityEventService
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+func NewTokenService(repo repositories.RefreshTokenRepositoryInterface, users repositories.UserRepositoryInterface, cfg config.AuthConfig) *TokenService {
+	return &TokenService{
+		Repo:       repo,
+		Users:      users,
+		secret:     []byte(cfg.JWTSecret),
+		accessTTL:  cfg.AccessTokenTTL,
+		refreshTTL: cfg.RefreshTokenTTL,
+	}
+}
+
+// IssueTokens abre una sesión para el usuario.
+func (s *TokenService) IssueTokens(ctx context.Context, user *models.User, clientIP string) (*dtos.TokenPairDTO, error) {
+	now := time.Now()
+	accessToken, err := s.signAccessToken(AccessClaims{
+		Issuer:    tokenIssuer,
+		Subject:   strconv.Itoa(user.ID),
+		Email:     user.Email,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.accessTTL).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	refreshToken := base64.RawURLEncoding.EncodeToString(buf)
+	if err := s.Repo.CreateRefreshToken(ctx, &models.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: refreshTokenHash(refreshToken),
+		ExpiresAt: now.Add(s.refreshTTL),
+		CreatedAt: now,
+		ClientIP:  clientIP,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &dtos.TokenPairDTO{
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(s.accessTTL.Seconds()),
+		RefreshToken:     refreshToken,
+		RefreshExpiresIn: int(s.refreshTTL.Seconds()),
+	}, nil
+}
+
+// ValidateAccessToken comprueba la firma, el emisor y el vencimiento del token de acceso.
+func (s *TokenService) ValidateAccessToken(token string) (*AccessClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return nil, ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, s.sign(parts[0]+"."+parts[1])) {
+		return nil, ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var claims AccessClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if claims.Issuer != tokenIssuer || claims.Email == "" || time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrInvalidToken
+	}
+	return &claims, nil
+}
+
+// Refresh cambia un token de refresco vigente por un par nuevo y revoca el usado. Si llega uno ya
+// revocado, alguien más lo tiene (o lo tuvo): se revocan todas las sesiones del usuario.
+func (s *TokenService) Refresh(ctx context.Context, refreshToken, clientIP string) (*dtos.TokenPairDTO, error) {
+	stored, err := s.Repo.GetRefreshTokenByHash(ctx, refreshTokenHash(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+	if !time.Now().Before(stored.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.Users.GetUserByID(ctx, strconv.Itoa(stored.UserID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	revoked, err := s.Repo.RevokeRefreshToken(ctx, stored.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		if err := s.Repo.RevokeUserRefreshTokens(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		if s.Security != nil {
+			if err := s.Security.RecordSecurityEvent(ctx, SECURITY_EVENT_TOKEN_REUSE, user.Email,
+				"revoked refresh token "+strconv.Itoa(stored.ID)+" reused; every session was closed", clientIP); err != nil {
+				log.Printf("error recording refresh token reuse for %s: %v", user.Email, err)
+			}
+		}
+		return nil, ErrInvalidToken
+	}
+
+	// un usuario desactivado no puede seguir renovando su sesión
+	if !user.UserStateType.AllowsLogin {
+		return nil, ErrInvalidToken
+	}
+	return s.IssueTokens(ctx, user, clientIP)
+}
+
+// Revoke cierra la sesión del token de refresco. Un token desconocido o ya revocado no es un error.
+func (s *TokenService) Revoke(ctx context.Context, refreshToken string) error {
+	stored, err := s.Repo.GetRefreshTokenByHash(ctx, refreshTokenHash(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	_, err = s.Repo.RevokeRefreshToken(ctx, stored.ID)
+	return err
+}
+
+// RevokeUserTokens cierra todas las sesiones del usuario. Los tokens de acceso ya emitidos siguen
+// valiendo hasta que vencen.
+func (s *TokenService) RevokeUserTokens(ctx context.Context, userID int) error {
+	return s.Repo.RevokeUserRefreshTokens(ctx, userID)
+}
+
+// PurgeExpiredTokens borra los tokens de refresco vencidos.
+func (s *TokenService) PurgeExpiredTokens(ctx context.Context) (int64, error) {
+	return s.Repo.DeleteExpiredRefreshTokens(ctx, time.Now())
+}
+
+func (s *TokenService) signAccessToken(claims AccessClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	unsigned := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(s.sign(unsigned)), nil
+}
+
+func (s *TokenService) sign(unsigned string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(unsigned))
+	return mac.Sum(nil)
+}
+
+func refreshTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
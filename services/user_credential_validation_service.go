@@ -1,32 +1,36 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"totesbackend/models"
 	"totesbackend/repositories"
 	"totesbackend/services/utils"
 )
 
 type UserCredentialValidationService struct {
-	UserRepo *repositories.UserRepository
+	UserRepo repositories.UserRepositoryInterface
 }
 
-func NewUserCredentialValidationService(userRepo *repositories.UserRepository) *UserCredentialValidationService {
+func NewUserCredentialValidationService(userRepo repositories.UserRepositoryInterface) *UserCredentialValidationService {
 	return &UserCredentialValidationService{UserRepo: userRepo}
 }
 
-func (s *UserCredentialValidationService) ValidateUserCredentials(email, password string) error {
-	user, err := s.UserRepo.GetUserByEmail(email)
+// ValidateUserCredentials devuelve el usuario si el correo y la contraseña coinciden y su estado
+// permite iniciar sesión.
+func (s *UserCredentialValidationService) ValidateUserCredentials(ctx context.Context, email, password string) (*models.User, error) {
+	user, err := s.UserRepo.GetUserByEmail(ctx, email)
 	if err != nil {
-		return errors.New("invalid email or password")
+		return nil, errors.New("invalid email or password")
 	}
 
-	if user.UserStateType.Name != "Active" {
-		return errors.New("user is not active")
+	if !user.UserStateType.AllowsLogin {
+		return nil, errors.New("user is not active")
 	}
 
 	if !utils.CheckPasswordHash(password, user.Password) {
-		return errors.New("invalid email or password")
+		return nil, errors.New("invalid email or password")
 	}
 
-	return nil
+	return user, nil
 }
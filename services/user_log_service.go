@@ -1,25 +1,87 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
 	"time"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
 
+var ErrInvalidLogFilter = errors.New("invalid log filter")
+
+var userLogSortColumns = map[string]string{
+	"id":        "id",
+	"date_time": "date_time",
+	"email":     "user_email",
+	"endpoint":  "endpoint",
+}
+
 type UserLogService struct {
-	Repo *repositories.UserLogRepository
+	Repo   repositories.UserLogRepositoryInterface
+	Writer *UserLogWriter
+	Sink   LogSink
 }
 
-func NewUserLogService(repo *repositories.UserLogRepository) *UserLogService {
+func NewUserLogService(repo repositories.UserLogRepositoryInterface) *UserLogService {
 	return &UserLogService{Repo: repo}
 }
 
-func (s *UserLogService) CreateUserLog(userEmail, logMessage string) (*models.UserLog, error) {
+func (s *UserLogService) CreateUserLog(ctx context.Context, userEmail, endpoint, logMessage string) (*models.UserLog, error) {
 	userLog := &models.UserLog{
 		UserEmail: userEmail,
+		Endpoint:  endpoint,
 		Log:       logMessage,
 		DateTime:  time.Now(),
+		RequestID: RequestIDFromContext(ctx),
+	}
+
+	// Con escritor asíncrono el log se encola y se guarda en el siguiente lote
+	if s.Writer != nil {
+		if err := s.Writer.Enqueue(*userLog); err != nil {
+			return nil, err
+		}
+		return userLog, nil
 	}
 
-	return s.Repo.CreateUserLog(userLog)
+	createdLog, err := s.Repo.CreateUserLog(ctx, userLog)
+	if err != nil {
+		return nil, err
+	}
+	if s.Sink != nil {
+		if err := s.Sink.WriteLogs([]models.UserLog{*createdLog}); err != nil {
+			log.Printf("error shipping user log: %v", err)
+		}
+	}
+	return createdLog, nil
+}
+
+// SearchUserLogs valida el filtro y devuelve una página de logs junto con el total de coincidencias.
+func (s *UserLogService) SearchUserLogs(ctx context.Context, filter dtos.UserLogFilterDTO) (*dtos.PageDTO[models.UserLog], error) {
+	column, ok := userLogSortColumns[filter.SortBy]
+	if !ok {
+		return nil, fmt.Errorf("%w: sortBy must be id, date_time, email or endpoint", ErrInvalidLogFilter)
+	}
+	if filter.Order != "asc" && filter.Order != "desc" {
+		return nil, fmt.Errorf("%w: order must be asc or desc", ErrInvalidLogFilter)
+	}
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, fmt.Errorf("%w: 'to' date must be after 'from' date", ErrInvalidLogFilter)
+	}
+	filter.SortBy = column
+
+	logs, total, err := s.Repo.SearchUserLogs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return dtos.NewPageDTO(logs, filter.PaginationDTO, total), nil
+}
+
+// PurgeExpiredUserLogs borra los logs más antiguos que el periodo de retención.
+func (s *UserLogService) PurgeExpiredUserLogs(ctx context.Context, retentionDays int) (int64, error) {
+	return s.Repo.DeleteUserLogsBefore(ctx, time.Now().AddDate(0, 0, -retentionDays))
 }
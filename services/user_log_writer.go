@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+	"totesbackend/config"
+	"totesbackend/models"
+	"totesbackend/repositories"
+)
+
+var ErrLogQueueFull = errors.New("log queue is full")
+var ErrLogWriterClosed = errors.New("log writer is closed")
+
+// UserLogWriter escribe los logs en segundo plano y por lotes para que los handlers no esperen
+// un INSERT por cada mensaje. La cola es acotada: si se llena, Enqueue devuelve ErrLogQueueFull.
+// Un lote que no se pudo guardar se reintenta y, si sigue fallando, se escribe en stderr.
+type UserLogWriter struct {
+	Repo          repositories.UserLogRepositoryInterface
+	Sink          LogSink
+	queue         chan models.UserLog
+	batchSize     int
+	flushInterval time.Duration
+	mu            sync.RWMutex
+	closed        bool
+	done          chan struct{}
+}
+
+func NewUserLogWriter(repo repositories.UserLogRepositoryInterface, queueSize, batchSize int, flushInterval time.Duration) *UserLogWriter {
+	w := &UserLogWriter{
+		Repo:          repo,
+		queue:         make(chan models.UserLog, queueSize),
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		done:          make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+func (w *UserLogWriter) Enqueue(userLog models.UserLog) error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return ErrLogWriterClosed
+	}
+
+	select {
+	case w.queue <- userLog:
+		return nil
+	default:
+		return ErrLogQueueFull
+	}
+}
+
+// Close deja de aceptar logs, espera a que se escriban los que quedan en la cola y cierra el Sink,
+// así el último lote alcanza a enviarse.
+func (w *UserLogWriter) Close() {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+
+	<-w.done
+	if w.Sink != nil {
+		if err := w.Sink.Close(); err != nil {
+			log.Printf("error closing the user log sink: %v", err)
+		}
+	}
+}
+
+func (w *UserLogWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]models.UserLog, 0, w.batchSize)
+	for {
+		select {
+		case userLog, ok := <-w.queue:
+			if !ok {
+				w.flush(batch)
+				return
+			}
+			batch = append(batch, userLog)
+			if len(batch) >= w.batchSize {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			w.flush(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+func (w *UserLogWriter) flush(batch []models.UserLog) {
+	if len(batch) == 0 {
+		return
+	}
+	if err := w.write(batch); err != nil {
+		log.Printf("error writing %d user logs after %d attempts: %v", len(batch), config.LOG_WRITE_ATTEMPTS, err)
+		for _, userLog := range batch {
+			log.Printf("%s [%s] %s: %s (request %s, log not stored)",
+				userLog.DateTime.Format(time.RFC3339), userLog.UserEmail, userLog.Endpoint, userLog.Log, userLog.RequestID)
+		}
+	}
+	if w.Sink != nil {
+		if err := w.Sink.WriteLogs(batch); err != nil {
+			log.Printf("error shipping %d user logs: %v", len(batch), err)
+		}
+	}
+}
+
+// write guarda el lote reintentando con espera creciente; mientras tanto los logs nuevos se
+// acumulan en la cola.
+func (w *UserLogWriter) write(batch []models.UserLog) error {
+	delay := config.LOG_WRITE_RETRY_DELAY
+	var err error
+	for attempt := 1; attempt <= config.LOG_WRITE_ATTEMPTS; attempt++ {
+		if err = w.Repo.CreateUserLogs(context.Background(), batch); err == nil {
+			return nil
+		}
+		if attempt < config.LOG_WRITE_ATTEMPTS {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+	return err
+}
@@ -1,49 +1,51 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 	"totesbackend/services/utils"
 )
 
 type UserService struct {
-	Repo *repositories.UserRepository
+	Repo repositories.UserRepositoryInterface
 }
 
-func NewUserService(repo *repositories.UserRepository) *UserService {
+func NewUserService(repo repositories.UserRepositoryInterface) *UserService {
 	return &UserService{Repo: repo}
 }
 
-func (s *UserService) GetUserByID(id string) (*models.User, error) {
-	return s.Repo.GetUserByID(id)
+func (s *UserService) GetUserByID(ctx context.Context, id string) (*models.User, error) {
+	return s.Repo.GetUserByID(ctx, id)
 }
 
-func (s *UserService) GetUserByEmail(email string) (*models.User, error) {
-	return s.Repo.GetUserByEmail(email)
+func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	return s.Repo.GetUserByEmail(ctx, email)
 }
 
-func (s *UserService) GetAllUsers() ([]models.User, error) {
-	return s.Repo.GetAllUsers()
+func (s *UserService) GetAllUsers(ctx context.Context, pagination dtos.PaginationDTO) ([]models.User, int64, error) {
+	return s.Repo.GetAllUsers(ctx, pagination)
 }
 
-func (s *UserService) SearchUsersByID(query string) ([]models.User, error) {
-	return s.Repo.SearchUsersByID(query)
+func (s *UserService) SearchUsersByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.User, int64, error) {
+	return s.Repo.SearchUsersByID(ctx, query, pagination)
 }
 
-func (s *UserService) SearchUsersByEmail(query string) ([]models.User, error) {
-	return s.Repo.SearchUsersByEmail(query)
+func (s *UserService) SearchUsersByEmail(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.User, int64, error) {
+	return s.Repo.SearchUsersByEmail(ctx, query, pagination)
 }
 
-func (s *UserService) UpdateUserState(id string, state int) (*models.User, error) {
-	return s.Repo.UpdateUserState(id, state)
+func (s *UserService) UpdateUserState(ctx context.Context, id string, state int) (*models.User, error) {
+	return s.Repo.UpdateUserState(ctx, id, state)
 }
 
-func (s *UserService) UpdateUser(user *models.User) error {
-	return s.Repo.UpdateUser(user)
+func (s *UserService) UpdateUser(ctx context.Context, user *models.User) error {
+	return s.Repo.UpdateUser(ctx, user)
 }
 
-func (s *UserService) CreateUser(user *models.User) (*models.User, error) {
+func (s *UserService) CreateUser(ctx context.Context, user *models.User) (*models.User, error) {
 	hashedPassword, err := utils.HashPassword(user.Password)
 	if err != nil {
 		return nil, fmt.Errorf("error hashing password: %w", err)
@@ -51,5 +53,5 @@ func (s *UserService) CreateUser(user *models.User) (*models.User, error) {
 
 	user.Password = hashedPassword
 
-	return s.Repo.CreateUser(user)
+	return s.Repo.CreateUser(ctx, user)
 }
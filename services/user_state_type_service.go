@@ -1,22 +1,101 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
 
+var (
+	ErrInvalidUserStateTypeName = errors.New("user state type name cannot be blank")
+	ErrUserStateTypeNameTaken   = errors.New("a user state type with that name already exists")
+	ErrUserStateTypeBuiltIn     = errors.New("built-in user state types cannot be modified or deleted")
+	ErrUserStateTypeInUse       = errors.New("user state type is in use")
+)
+
 type UserStateTypeService struct {
-	Repo *repositories.UserStateTypeRepository
+	Repo repositories.UserStateTypeRepositoryInterface
 }
 
-func NewUserStateTypeService(repo *repositories.UserStateTypeRepository) *UserStateTypeService {
+func NewUserStateTypeService(repo repositories.UserStateTypeRepositoryInterface) *UserStateTypeService {
 	return &UserStateTypeService{Repo: repo}
 }
 
-func (s *UserStateTypeService) GetAllUserStateTypes() ([]models.UserStateType, error) {
-	return s.Repo.GetAllUserStateTypes()
+func (s *UserStateTypeService) GetAllUserStateTypes(ctx context.Context, pagination dtos.PaginationDTO) ([]models.UserStateType, int64, error) {
+	return s.Repo.GetAllUserStateTypes(ctx, pagination)
+}
+
+func (s *UserStateTypeService) GetUserStateTypeByID(ctx context.Context, id string) (*models.UserStateType, error) {
+	return s.Repo.GetUserStateTypeByID(ctx, id)
+}
+
+func (s *UserStateTypeService) CreateUserStateType(ctx context.Context, dto dtos.UserStateTypeDTO) (*models.UserStateType, error) {
+	userStateType := &models.UserStateType{Name: strings.TrimSpace(dto.Name), AllowsLogin: *dto.AllowsLogin}
+	if err := s.checkNameAvailable(ctx, userStateType.Name, 0); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.CreateUserStateType(ctx, userStateType); err != nil {
+		return nil, err
+	}
+	return userStateType, nil
+}
+
+// UpdateUserStateType cambia el nombre y si permite iniciar sesión; el cambio aplica al próximo
+// login de los usuarios que ya están en ese estado.
+func (s *UserStateTypeService) UpdateUserStateType(ctx context.Context, id int, dto dtos.UserStateTypeDTO) (*models.UserStateType, error) {
+	userStateType, err := s.Repo.GetUserStateTypeByID(ctx, strconv.Itoa(id))
+	if err != nil {
+		return nil, err
+	}
+	if userStateType.BuiltIn {
+		return nil, ErrUserStateTypeBuiltIn
+	}
+	userStateType.Name = strings.TrimSpace(dto.Name)
+	userStateType.AllowsLogin = *dto.AllowsLogin
+	if err := s.checkNameAvailable(ctx, userStateType.Name, id); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.UpdateUserStateType(ctx, userStateType); err != nil {
+		return nil, err
+	}
+	return userStateType, nil
+}
+
+// DeleteUserStateType solo borra estados propios que ningún usuario tiene asignado.
+func (s *UserStateTypeService) DeleteUserStateType(ctx context.Context, id int) error {
+	userStateType, err := s.Repo.GetUserStateTypeByID(ctx, strconv.Itoa(id))
+	if err != nil {
+		return err
+	}
+	if userStateType.BuiltIn {
+		return ErrUserStateTypeBuiltIn
+	}
+	usage, err := s.Repo.CountUsersWithState(ctx, id)
+	if err != nil {
+		return err
+	}
+	if usage > 0 {
+		return fmt.Errorf("%w by %d users", ErrUserStateTypeInUse, usage)
+	}
+	return s.Repo.DeleteUserStateType(ctx, id)
 }
 
-func (s *UserStateTypeService) GetUserStateTypeByID(id string) (*models.UserStateType, error) {
-	return s.Repo.GetUserStateTypeByID(id)
+// checkNameAvailable evita nombres repetidos sin distinguir mayúsculas; exceptID es el estado que se edita.
+func (s *UserStateTypeService) checkNameAvailable(ctx context.Context, name string, exceptID int) error {
+	if name == "" {
+		return ErrInvalidUserStateTypeName
+	}
+	existing, err := s.Repo.GetUserStateTypeByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != exceptID {
+		return ErrUserStateTypeNameTaken
+	}
+	return nil
 }
@@ -1,38 +1,127 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
 
+var (
+	ErrInvalidUserType   = errors.New("invalid user type")
+	ErrUserTypeNameTaken = errors.New("a user type with that name already exists")
+	ErrUserTypeInUse     = errors.New("user type is in use")
+	ErrRoleMissing       = errors.New("role not found")
+)
+
 type UserTypeService struct {
-	Repo *repositories.UserTypeRepository
+	Repo repositories.UserTypeRepositoryInterface
 }
 
-func NewUserTypeService(repo *repositories.UserTypeRepository) *UserTypeService {
+func NewUserTypeService(repo repositories.UserTypeRepositoryInterface) *UserTypeService {
 	return &UserTypeService{Repo: repo}
 }
 
-func (s *UserTypeService) ObtainAllUserTypes() ([]models.UserType, error) {
-	return s.Repo.ObtainAllUserTypes()
+func (s *UserTypeService) ObtainAllUserTypes(ctx context.Context, pagination dtos.PaginationDTO) ([]models.UserType, int64, error) {
+	return s.Repo.ObtainAllUserTypes(ctx, pagination)
+}
+
+func (s *UserTypeService) GetUserTypeByID(ctx context.Context, id uint) (*models.UserType, error) {
+	return s.Repo.GetUserTypeByID(ctx, id)
+}
+
+func (s *UserTypeService) GetRolesForUserType(ctx context.Context, userTypeID uint) ([]uint, error) {
+	return s.Repo.GetRolesForUserType(ctx, userTypeID)
+}
+
+func (s *UserTypeService) Exists(ctx context.Context, userTypeID uint) (bool, error) {
+	return s.Repo.Exists(ctx, userTypeID)
+}
+
+func (s *UserTypeService) SearchUserTypesByID(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.UserType, int64, error) {
+	return s.Repo.SearchUserTypesByID(ctx, query, pagination)
+}
+
+func (s *UserTypeService) SearchUserTypesByName(ctx context.Context, query string, pagination dtos.PaginationDTO) ([]models.UserType, int64, error) {
+	return s.Repo.SearchUserTypesByName(ctx, query, pagination)
+}
+
+func (s *UserTypeService) CreateUserType(ctx context.Context, dto dtos.CreateUserTypeDTO) (*models.UserType, error) {
+	userType := &models.UserType{Name: strings.TrimSpace(dto.Name), Description: strings.TrimSpace(dto.Description)}
+	if err := s.checkUserTypeName(ctx, userType); err != nil {
+		return nil, err
+	}
+	roleIDs, err := s.checkRoles(ctx, dto.Roles)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Repo.CreateUserType(ctx, userType, roleIDs); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetUserTypeByID(ctx, userType.ID)
 }
 
-func (s *UserTypeService) GetUserTypeByID(id uint) (*models.UserType, error) {
-	return s.Repo.GetUserTypeByID(id)
+func (s *UserTypeService) UpdateUserType(ctx context.Context, id uint, dto dtos.UpdateUserTypeDTO) (*models.UserType, error) {
+	userType := &models.UserType{ID: id, Name: strings.TrimSpace(dto.Name), Description: strings.TrimSpace(dto.Description)}
+	if err := s.checkUserTypeName(ctx, userType); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.UpdateUserType(ctx, userType); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetUserTypeByID(ctx, id)
 }
 
-func (s *UserTypeService) GetRolesForUserType(userTypeID uint) ([]uint, error) {
-	return s.Repo.GetRolesForUserType(userTypeID)
+// UpdateUserTypeRoles deja al tipo de usuario exactamente con roleIDs; si alguno no existe no cambia nada.
+func (s *UserTypeService) UpdateUserTypeRoles(ctx context.Context, id uint, roleIDs []uint) (*models.UserType, error) {
+	roleIDs, err := s.checkRoles(ctx, roleIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Repo.SetUserTypeRoles(ctx, id, roleIDs); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetUserTypeByID(ctx, id)
 }
 
-func (s *UserTypeService) Exists(userTypeID uint) (bool, error) {
-	return s.Repo.Exists(userTypeID)
+// DeleteUserType solo borra tipos de usuario que ningún usuario tiene.
+func (s *UserTypeService) DeleteUserType(ctx context.Context, id uint) error {
+	usage, err := s.Repo.CountUserTypeUsers(ctx, id)
+	if err != nil {
+		return err
+	}
+	if usage > 0 {
+		return fmt.Errorf("%w by %d users; change their user type first", ErrUserTypeInUse, usage)
+	}
+	return s.Repo.DeleteUserType(ctx, id)
 }
 
-func (s *UserTypeService) SearchUserTypesByID(query string) ([]models.UserType, error) {
-	return s.Repo.SearchUserTypesByID(query)
+// checkUserTypeName exige un nombre que no tenga otro tipo de usuario.
+func (s *UserTypeService) checkUserTypeName(ctx context.Context, userType *models.UserType) error {
+	if userType.Name == "" {
+		return fmt.Errorf("%w: name cannot be blank", ErrInvalidUserType)
+	}
+	existing, err := s.Repo.GetUserTypeByName(ctx, userType.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != userType.ID {
+		return ErrUserTypeNameTaken
+	}
+	return nil
 }
 
-func (s *UserTypeService) SearchUserTypesByName(query string) ([]models.UserType, error) {
-	return s.Repo.SearchUserTypesByName(query)
+// checkRoles quita los IDs repetidos y verifica que todos los roles existan.
+func (s *UserTypeService) checkRoles(ctx context.Context, roleIDs []uint) ([]uint, error) {
+	unique, missing, err := findMissingIDs(ctx, roleIDs, s.Repo.GetExistingRoleIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrRoleMissing, strings.Join(missing, ", "))
+	}
+	return unique, nil
 }
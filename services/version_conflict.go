@@ -0,0 +1,7 @@
+package services
+
+import "errors"
+
+// ErrVersionConflict indica que el registro cambió desde que el cliente lo leyó: la versión que
+// envió ya no es la actual (bloqueo optimista).
+var ErrVersionConflict = errors.New("the record was modified by someone else; reload it and retry with its current version")
@@ -0,0 +1,406 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+	"totesbackend/config"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+)
+
+const (
+	WEBHOOK_EVENT_INVOICE_CREATED              = "invoice.created"
+	WEBHOOK_EVENT_INVOICE_CANCELLED            = "invoice.cancelled"
+	WEBHOOK_EVENT_INVOICE_RETURNED             = "invoice.returned"
+	WEBHOOK_EVENT_PURCHASE_ORDER_CREATED       = "purchase_order.created"
+	WEBHOOK_EVENT_PURCHASE_ORDER_STATE_CHANGED = "purchase_order.state_changed"
+	WEBHOOK_EVENT_APPOINTMENT_CREATED          = "appointment.created"
+	WEBHOOK_EVENT_APPOINTMENT_UPDATED          = "appointment.updated"
+	WEBHOOK_EVENT_APPOINTMENT_DELETED          = "appointment.deleted"
+	WEBHOOK_EVENT_APPOINTMENT_STATE_CHANGED    = "appointment.state_changed"
+	WEBHOOK_EVENT_STOCK_CHANGED                = "item.stock_changed"
+	WEBHOOK_EVENT_LOW_STOCK                    = "item.low_stock"
+)
+
+const (
+	WEBHOOK_DELIVERY_PENDING   = "pending"
+	WEBHOOK_DELIVERY_SUCCEEDED = "succeeded"
+	WEBHOOK_DELIVERY_FAILED    = "failed"
+)
+
+var webhookEventTypes = map[string]bool{
+	WEBHOOK_EVENT_INVOICE_CREATED:              true,
+	WEBHOOK_EVENT_INVOICE_CANCELLED:            true,
+	WEBHOOK_EVENT_INVOICE_RETURNED:             true,
+	WEBHOOK_EVENT_PURCHASE_ORDER_CREATED:       true,
+	WEBHOOK_EVENT_PURCHASE_ORDER_STATE_CHANGED: true,
+	WEBHOOK_EVENT_APPOINTMENT_CREATED:          true,
+	WEBHOOK_EVENT_APPOINTMENT_UPDATED:          true,
+	WEBHOOK_EVENT_APPOINTMENT_DELETED:          true,
+	WEBHOOK_EVENT_APPOINTMENT_STATE_CHANGED:    true,
+	WEBHOOK_EVENT_STOCK_CHANGED:                true,
+	WEBHOOK_EVENT_LOW_STOCK:                    true,
+}
+
+var ErrInvalidWebhook = errors.New("invalid webhook subscription")
+
+// WebhookService administra las suscripciones y entrega los eventos en segundo plano. Cada evento
+// se guarda como una entrega pendiente por suscripción; las que fallan se reintentan con espera
+// exponencial hasta config.WEBHOOK_MAX_ATTEMPTS.
+type WebhookService struct {
+	Repo      repositories.WebhookRepositoryInterface
+	Client    *http.Client
+	wake      chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewWebhookService(repo repositories.WebhookRepositoryInterface) *WebhookService {
+	s := &WebhookService{
+		Repo:   repo,
+		Client: &http.Client{Timeout: config.WEBHOOK_TIMEOUT},
+		wake:   make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *WebhookService) GetAllSubscriptions(ctx context.Context, pagination dtos.PaginationDTO) ([]models.WebhookSubscription, int64, error) {
+	return s.Repo.GetAllSubscriptions(ctx, pagination)
+}
+
+func (s *WebhookService) GetSubscriptionByID(ctx context.Context, id int) (*models.WebhookSubscription, error) {
+	return s.Repo.GetSubscriptionByID(ctx, id)
+}
+
+// CreateSubscription genera un secreto aleatorio cuando no se envía uno.
+func (s *WebhookService) CreateSubscription(ctx context.Context, dto dtos.CreateWebhookSubscriptionDTO) (*models.WebhookSubscription, error) {
+	if err := validateWebhook(dto.URL, dto.Secret, dto.EventTypes); err != nil {
+		return nil, err
+	}
+
+	secret := dto.Secret
+	if secret == "" {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		secret = hex.EncodeToString(buf)
+	}
+
+	subscription := &models.WebhookSubscription{
+		URL:        dto.URL,
+		Secret:     secret,
+		EventTypes: dto.EventTypes,
+		Active:     dto.Active == nil || *dto.Active,
+	}
+	if err := s.Repo.CreateSubscription(ctx, subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// UpdateSubscription conserva el secreto actual si no se envía uno nuevo.
+func (s *WebhookService) UpdateSubscription(ctx context.Context, id int, dto dtos.UpdateWebhookSubscriptionDTO) (*models.WebhookSubscription, error) {
+	if err := validateWebhook(dto.URL, dto.Secret, dto.EventTypes); err != nil {
+		return nil, err
+	}
+
+	subscription, err := s.Repo.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	subscription.URL = dto.URL
+	subscription.EventTypes = dto.EventTypes
+	subscription.Active = dto.Active
+	if dto.Secret != "" {
+		subscription.Secret = dto.Secret
+	}
+	if err := s.Repo.UpdateSubscription(ctx, subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id int) error {
+	return s.Repo.DeleteSubscription(ctx, id)
+}
+
+func (s *WebhookService) GetDeliveries(ctx context.Context, subscriptionID int, pagination dtos.PaginationDTO) ([]dtos.GetWebhookDeliveryDTO, int64, error) {
+	deliveries, total, err := s.Repo.GetDeliveriesBySubscription(ctx, subscriptionID, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]dtos.GetWebhookDeliveryDTO, len(deliveries))
+	for i, delivery := range deliveries {
+		result[i] = dtos.GetWebhookDeliveryDTO{
+			ID:             delivery.ID,
+			SubscriptionID: delivery.SubscriptionID,
+			EventType:      delivery.EventType,
+			Payload:        json.RawMessage(delivery.Payload),
+			Status:         delivery.Status,
+			Attempts:       delivery.Attempts,
+			LastStatusCode: delivery.LastStatusCode,
+			LastError:      delivery.LastError,
+			NextAttemptAt:  delivery.NextAttemptAt,
+			DeliveredAt:    delivery.DeliveredAt,
+			CreatedAt:      delivery.CreatedAt,
+		}
+	}
+	return result, total, nil
+}
+
+// Publish encola el evento para todas las suscripciones activas que lo escuchan. Los errores solo
+// se registran: un webhook nunca debe hacer fallar la operación que lo origina.
+func (s *WebhookService) Publish(ctx context.Context, eventType string, data interface{}) {
+	if s == nil {
+		return
+	}
+	// la operación ya se guardó: el evento se encola aunque el cliente se haya desconectado
+	if err := s.enqueue(context.WithoutCancel(ctx), eventType, data); err != nil {
+		log.Printf("error queuing webhook event %s: %v", eventType, err)
+	}
+}
+
+// HandleEvents envía como webhook los eventos de dominio que tienen uno.
+func (s *WebhookService) HandleEvents(bus *EventBus) {
+	Subscribe(bus, func(ctx context.Context, event InvoiceCreated) {
+		s.Publish(ctx, WEBHOOK_EVENT_INVOICE_CREATED, event.Invoice)
+	})
+	Subscribe(bus, func(ctx context.Context, event InvoiceCancelled) {
+		s.Publish(ctx, WEBHOOK_EVENT_INVOICE_CANCELLED, event.CreditNote)
+	})
+	Subscribe(bus, func(ctx context.Context, event InvoiceReturned) {
+		s.Publish(ctx, WEBHOOK_EVENT_INVOICE_RETURNED, event.Return)
+	})
+	Subscribe(bus, func(ctx context.Context, event StockChanged) {
+		s.Publish(ctx, WEBHOOK_EVENT_STOCK_CHANGED, event.StockChangedEventDTO)
+	})
+	Subscribe(bus, func(ctx context.Context, event LowStock) {
+		s.Publish(ctx, WEBHOOK_EVENT_LOW_STOCK, event.LowStockEventDTO)
+	})
+	Subscribe(bus, func(ctx context.Context, event PurchaseOrderCreated) {
+		s.Publish(ctx, WEBHOOK_EVENT_PURCHASE_ORDER_CREATED, event.PurchaseOrder)
+	})
+	Subscribe(bus, func(ctx context.Context, event PurchaseOrderStateChanged) {
+		s.Publish(ctx, WEBHOOK_EVENT_PURCHASE_ORDER_STATE_CHANGED, event.PurchaseOrder)
+	})
+	Subscribe(bus, func(ctx context.Context, event AppointmentBooked) {
+		s.Publish(ctx, WEBHOOK_EVENT_APPOINTMENT_CREATED, event.Appointment)
+	})
+	Subscribe(bus, func(ctx context.Context, event AppointmentUpdated) {
+		s.Publish(ctx, WEBHOOK_EVENT_APPOINTMENT_UPDATED, event.Appointment)
+	})
+	Subscribe(bus, func(ctx context.Context, event AppointmentStateChanged) {
+		s.Publish(ctx, WEBHOOK_EVENT_APPOINTMENT_STATE_CHANGED, event.Appointment)
+	})
+	Subscribe(bus, func(ctx context.Context, event AppointmentDeleted) {
+		s.Publish(ctx, WEBHOOK_EVENT_APPOINTMENT_DELETED, map[string]int{"id": event.ID})
+	})
+}
+
+// Close detiene el envío; las entregas pendientes quedan guardadas y se envían al reiniciar.
+func (s *WebhookService) Close() {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+}
+
+func (s *WebhookService) enqueue(ctx context.Context, eventType string, data interface{}) error {
+	subscriptions, err := s.Repo.GetActiveSubscriptionsForEvent(ctx, eventType)
+	if err != nil || len(subscriptions) == 0 {
+		return err
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	deliveries := make([]models.WebhookDelivery, len(subscriptions))
+	for i, subscription := range subscriptions {
+		deliveries[i] = models.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventType:      eventType,
+			Payload:        string(payload),
+			Status:         WEBHOOK_DELIVERY_PENDING,
+			NextAttemptAt:  now,
+		}
+	}
+	if err := s.Repo.CreateDeliveries(ctx, deliveries); err != nil {
+		return err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (s *WebhookService) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(config.WEBHOOK_POLL_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+		s.deliverDue()
+	}
+}
+
+func (s *WebhookService) deliverDue() {
+	// corre en segundo plano, fuera de cualquier petición
+	ctx := context.Background()
+	deliveries, err := s.Repo.GetDueDeliveries(ctx, WEBHOOK_DELIVERY_PENDING, time.Now(), config.WEBHOOK_BATCH_SIZE)
+	if err != nil {
+		log.Printf("error loading pending webhook deliveries: %v", err)
+		return
+	}
+
+	subscriptions := make(map[int]*models.WebhookSubscription)
+	for i := range deliveries {
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+
+		delivery := &deliveries[i]
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			subscription, err = s.Repo.GetSubscriptionByID(ctx, delivery.SubscriptionID)
+			if err != nil {
+				log.Printf("error loading webhook subscription %d: %v", delivery.SubscriptionID, err)
+				continue
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+
+		if !subscription.Active {
+			delivery.Status = WEBHOOK_DELIVERY_FAILED
+			delivery.LastError = "subscription is inactive"
+		} else {
+			s.attempt(delivery, subscription)
+		}
+		if err := s.Repo.UpdateDelivery(ctx, delivery); err != nil {
+			log.Printf("error updating webhook delivery %d: %v", delivery.ID, err)
+		}
+	}
+}
+
+func (s *WebhookService) attempt(delivery *models.WebhookDelivery, subscription *models.WebhookSubscription) {
+	body, err := json.Marshal(map[string]interface{}{
+		"id":         delivery.ID,
+		"event":      delivery.EventType,
+		"created_at": delivery.CreatedAt,
+		"data":       json.RawMessage(delivery.Payload),
+	})
+	if err == nil {
+		err = s.send(subscription, delivery, body)
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	if err == nil {
+		delivery.Status = WEBHOOK_DELIVERY_SUCCEEDED
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if len(delivery.LastError) > 500 {
+		delivery.LastError = delivery.LastError[:500]
+	}
+	if delivery.Attempts >= config.WEBHOOK_MAX_ATTEMPTS {
+		delivery.Status = WEBHOOK_DELIVERY_FAILED
+		return
+	}
+	delivery.NextAttemptAt = now.Add(webhookRetryDelay(delivery.Attempts))
+}
+
+// send firma el cuerpo con HMAC-SHA256 sobre "<timestamp>.<cuerpo>" usando el secreto de la suscripción.
+func (s *WebhookService) send(subscription *models.WebhookSubscription, delivery *models.WebhookDelivery, body []byte) error {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(subscription.Secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+
+	req, err := http.NewRequest(http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Delivery", strconv.Itoa(delivery.ID))
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	delivery.LastStatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("endpoint answered with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := config.WEBHOOK_RETRY_BASE_DELAY
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= config.WEBHOOK_RETRY_MAX_DELAY {
+			return config.WEBHOOK_RETRY_MAX_DELAY
+		}
+	}
+	return delay
+}
+
+func validateWebhook(rawURL, secret string, eventTypes []string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidWebhook)
+	}
+	if secret != "" && len(secret) < 16 {
+		return fmt.Errorf("%w: secret must be at least 16 characters", ErrInvalidWebhook)
+	}
+	for _, eventType := range eventTypes {
+		if !webhookEventTypes[eventType] {
+			return fmt.Errorf("%w: unknown event type '%s'", ErrInvalidWebhook, eventType)
+		}
+	}
+	return nil
+}
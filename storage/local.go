@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"totesbackend/config"
+)
+
+// LocalStorage guarda los archivos en STORAGE_DIR; el servidor los publica en
+// config.STORAGE_LOCAL_PATH. Con varias instancias el directorio debe ser compartido.
+type LocalStorage struct {
+	Config config.StorageConfig
+}
+
+func NewLocalStorage(cfg config.StorageConfig) *LocalStorage {
+	return &LocalStorage{Config: cfg}
+}
+
+func (l *LocalStorage) Provider() string {
+	return STORAGE_PROVIDER_LOCAL
+}
+
+func (l *LocalStorage) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	file := l.file(key)
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	// se escribe a un temporal y se renombra para que nunca se publique un archivo a medias
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+func (l *LocalStorage) Delete(ctx context.Context, key string) error {
+	if err := os.Remove(l.file(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (l *LocalStorage) URL(key string) string {
+	return l.Config.PublicURL + "/" + key
+}
+
+// file ubica key dentro de STORAGE_DIR; unirla a la raíz limpia los ".." para que una llave no pueda
+// salir del directorio.
+func (l *LocalStorage) file(key string) string {
+	return filepath.Join(l.Config.Dir, filepath.FromSlash(path.Join("/", key)))
+}
@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"totesbackend/config"
+)
+
+// S3Storage guarda los archivos en un bucket de un servicio compatible con S3. Las peticiones se
+// firman con AWS Signature V4 y los objetos se direccionan por ruta (endpoint/bucket/llave), que
+// admiten tanto AWS como MinIO y R2.
+type S3Storage struct {
+	Config config.StorageConfig
+	Client *http.Client
+}
+
+func NewS3Storage(cfg config.StorageConfig) *S3Storage {
+	return &S3Storage{Config: cfg, Client: &http.Client{Timeout: config.STORAGE_REQUEST_TIMEOUT}}
+}
+
+func (s *S3Storage) Provider() string {
+	return STORAGE_PROVIDER_S3
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return s.do(req, body)
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+	// S3 responde 204 también cuando el objeto no existe
+	return s.do(req, nil)
+}
+
+func (s *S3Storage) URL(key string) string {
+	if s.Config.PublicURL != "" {
+		return s.Config.PublicURL + "/" + escapeKey(key)
+	}
+	return s.objectURL(key)
+}
+
+func (s *S3Storage) objectURL(key string) string {
+	return s.Config.Endpoint + "/" + url.PathEscape(s.Config.Bucket) + "/" + escapeKey(key)
+}
+
+func (s *S3Storage) do(req *http.Request, body []byte) error {
+	s.sign(req, body, time.Now().UTC())
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 300))
+		return fmt.Errorf("storage responded %s: %s", resp.Status, detail)
+	}
+	return nil
+}
+
+// sign agrega a req los encabezados de AWS Signature V4 para el servicio s3.
+func (s *S3Storage) sign(req *http.Request, body []byte, now time.Time) {
+	payloadHash := sha256.Sum256(body)
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(req.Header.Get(name))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	scope := day + "/" + s.Config.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.Config.SecretAccessKey), day)
+	key = hmacSHA256(key, s.Config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.Config.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// escapeKey codifica cada segmento de la llave dejando las barras.
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
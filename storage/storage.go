@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"totesbackend/config"
+)
+
+const (
+	STORAGE_PROVIDER_LOCAL = "local"
+	STORAGE_PROVIDER_S3    = "s3"
+)
+
+// Storage guarda los archivos subidos bajo una llave, p. ej. "items/12/3f9a.png", y da la URL
+// pública con que se descargan.
+type Storage interface {
+	Provider() string
+	// Put guarda body con la llave key, reemplazando el archivo si ya existía
+	Put(ctx context.Context, key string, body []byte, contentType string) error
+	// Delete borra el archivo; borrar una llave que no existe no es un error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// NewStorage crea el almacenamiento configurado en STORAGE_PROVIDER.
+func NewStorage(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Provider {
+	case STORAGE_PROVIDER_LOCAL:
+		return NewLocalStorage(cfg), nil
+	case STORAGE_PROVIDER_S3:
+		return NewS3Storage(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown storage provider %q", cfg.Provider)
+	}
+}